@@ -75,6 +75,7 @@ type loginFlags struct {
 	clientSecret           stringPtr
 	clientCertificate      string
 	federatedTokenProvider string
+	federatedTokenAudience string
 	scopes                 []string
 	redirectPort           int
 	global                 *internal.GlobalCommandOptions
@@ -130,6 +131,7 @@ const (
 	cClientSecretFlagName                = "client-secret"
 	cClientCertificateFlagName           = "client-certificate"
 	cFederatedCredentialProviderFlagName = "federated-credential-provider"
+	cFederatedCredentialAudienceFlagName = "federated-credential-audience"
 )
 
 func (lf *loginFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
@@ -164,7 +166,13 @@ func (lf *loginFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandO
 		cFederatedCredentialProviderFlagName,
 		"",
 		"The provider to use to acquire a federated token to authenticate with. "+
-			"Supported values: github, azure-pipelines, oidc")
+			"Supported values: auto, github, azure-pipelines, google, oidc. "+
+			"Use auto to detect the provider from the CI environment.")
+	local.StringVar(
+		&lf.federatedTokenAudience,
+		cFederatedCredentialAudienceFlagName,
+		"",
+		"The audience of the federated token. Defaults to the token exchange audience of the current cloud.")
 	local.StringVar(
 		&lf.tenantID,
 		"tenant-id",
@@ -440,6 +448,21 @@ func runningOnCodespacesBrowser(ctx context.Context, commandRunner exec.CommandR
 }
 
 func (la *loginAction) login(ctx context.Context) error {
+	if la.flags.federatedTokenProvider == auth.FederatedTokenProviderAuto {
+		provider, err := auth.DetectFederatedTokenProvider()
+		if err != nil {
+			return err
+		}
+
+		log.Printf("detected federated credential provider: %s", provider)
+		la.flags.federatedTokenProvider = provider
+	}
+
+	if la.flags.federatedTokenAudience != "" && la.flags.federatedTokenProvider == azurePipelinesProvider {
+		return fmt.Errorf("%s is not supported by the %s federated credential provider",
+			cFederatedCredentialAudienceFlagName, azurePipelinesProvider)
+	}
+
 	if la.flags.federatedTokenProvider == azurePipelinesProvider {
 		if la.flags.clientID == "" {
 			log.Printf("setting client id from environment variable %s", azurePipelinesClientIDEnvVarName)
@@ -516,7 +539,7 @@ func (la *loginAction) login(ctx context.Context) error {
 			}
 		case la.flags.federatedTokenProvider == "github":
 			if _, err := la.authManager.LoginWithGitHubFederatedTokenProvider(
-				ctx, la.flags.tenantID, la.flags.clientID,
+				ctx, la.flags.tenantID, la.flags.clientID, la.flags.federatedTokenAudience,
 			); err != nil {
 				return fmt.Errorf("logging in: %w", err)
 			}
@@ -533,12 +556,20 @@ func (la *loginAction) login(ctx context.Context) error {
 			); err != nil {
 				return fmt.Errorf("logging in: %w", err)
 			}
+		case la.flags.federatedTokenProvider == "google":
+			if _, err := la.authManager.LoginWithGoogleFederatedTokenProvider(
+				ctx, la.flags.tenantID, la.flags.clientID, la.flags.federatedTokenAudience,
+			); err != nil {
+				return fmt.Errorf("logging in: %w", err)
+			}
 		case la.flags.federatedTokenProvider == "oidc": // generic oidc provider
 			if _, err := la.authManager.LoginWithOidcFederatedTokenProvider(
-				ctx, la.flags.tenantID, la.flags.clientID,
+				ctx, la.flags.tenantID, la.flags.clientID, la.flags.federatedTokenAudience,
 			); err != nil {
 				return fmt.Errorf("logging in: %w", err)
 			}
+		default:
+			return fmt.Errorf("unsupported federated credential provider: '%s'", la.flags.federatedTokenProvider)
 		}
 
 		return nil
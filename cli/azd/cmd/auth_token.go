@@ -5,9 +5,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -18,6 +20,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -26,7 +29,9 @@ import (
 type authTokenFlags struct {
 	tenantID string
 	scopes   []string
+	resource string
 	global   *internal.GlobalCommandOptions
+	internal.EnvFlag
 }
 
 func newAuthTokenFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *authTokenFlags {
@@ -38,8 +43,17 @@ func newAuthTokenFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions
 
 func newAuthTokenCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:    "token --output json",
-		Hidden: true,
+		Use:   "token",
+		Short: "Print an access token for Azure or another resource.",
+		Long: "Print an access token of the logged in account, so that hooks and extensions can call Azure APIs " +
+			"without the Azure CLI.\n\n" +
+			"The token is requested for Azure Resource Manager, unless --resource or --scope are set, in the tenant " +
+			"set with --tenant-id, or else the tenant of the environment: the tenant pinned with AZURE_AUTH_TENANT_ID " +
+			"or the tenant of its subscription. Use --output json to print the expiration of the token too.",
+		Example: `$ azd auth token
+$ azd auth token --resource graph --output json
+$ azd auth token --resource api://my-api --tenant-id <tenantID>`,
+		Args: cobra.NoArgs,
 	}
 }
 
@@ -47,6 +61,33 @@ func (f *authTokenFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComma
 	f.global = global
 	local.StringArrayVar(&f.scopes, "scope", nil, "The scope to use when requesting an access token")
 	local.StringVar(&f.tenantID, "tenant-id", "", "The tenant id to use when requesting an access token.")
+	local.StringVar(
+		&f.resource,
+		"resource",
+		"",
+		"The resource to request an access token for: graph, devcenter, management or the URI of an API.",
+	)
+	f.EnvFlag.Bind(local, global)
+}
+
+// tokenResourceAudiences are the audiences of the resources 'azd auth token --resource' accepts by name
+var tokenResourceAudiences = map[string]string{
+	"graph":     graphsdk.ServiceConfig.Audience,
+	"devcenter": "https://devcenter.azure.com",
+}
+
+// resourceScopes returns the scopes of an access token for the resource, a well known resource name or the URI of an API
+func resourceScopes(resource string, cloud *cloud.Cloud) []string {
+	if strings.EqualFold(resource, "management") {
+		return auth.LoginScopes(cloud)
+	}
+
+	audience := resource
+	if wellKnown, has := tokenResourceAudiences[strings.ToLower(resource)]; has {
+		audience = wellKnown
+	}
+
+	return []string{strings.TrimSuffix(audience, "/") + "/.default"}
 }
 
 type CredentialProviderFn func(context.Context, *auth.CredentialForCurrentUserOptions) (azcore.TokenCredential, error)
@@ -91,6 +132,11 @@ func getTenantIdFromAzdEnv(
 		return tenantId, nil
 	}
 
+	// The tenant pinned by the environment wins over the tenant of its subscription
+	if pinnedTenantId := azdEnv.Getenv(environment.AuthTenantIdEnvVarName); pinnedTenantId != "" {
+		return pinnedTenantId, nil
+	}
+
 	subIdAtAzdEnv := azdEnv.GetSubscriptionId()
 	if subIdAtAzdEnv == "" {
 		// azd env found, but missing or empty subscriptionID
@@ -128,6 +174,14 @@ func getTenantIdFromEnv(
 }
 
 func (a *authTokenAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.resource != "" && len(a.flags.scopes) > 0 {
+		return nil, errors.New("--resource and --scope can't be used together")
+	}
+
+	if a.flags.resource != "" {
+		a.flags.scopes = resourceScopes(a.flags.resource, a.cloud)
+	}
+
 	if len(a.flags.scopes) == 0 {
 		a.flags.scopes = auth.LoginScopes(a.cloud)
 	}
@@ -169,6 +223,12 @@ func (a *authTokenAction) Run(ctx context.Context) (*actions.ActionResult, error
 		return nil, fmt.Errorf("fetching token: %w", err)
 	}
 
+	// The raw token is printed by default, so that it can be captured by scripts, ex) TOKEN=$(azd auth token)
+	if a.formatter.Kind() == output.NoneFormat {
+		fmt.Fprintln(a.writer, token.Token)
+		return nil, nil
+	}
+
 	res := contracts.AuthTokenResult{
 		Token:     token.Token,
 		ExpiresOn: contracts.RFC3339Time(token.ExpiresOn),
@@ -313,6 +313,110 @@ func TestAuthTokenCustomScopes(t *testing.T) {
 	require.True(t, wasCalled, "GetToken was not called on the credential")
 }
 
+func TestAuthTokenResource(t *testing.T) {
+	tests := map[string][]string{
+		"graph":                          {"https://graph.microsoft.com/.default"},
+		"DevCenter":                      {"https://devcenter.azure.com/.default"},
+		"management":                     {managementScope},
+		"api://my-api/":                  {"api://my-api/.default"},
+		"https://myapi.contoso.com/data": {"https://myapi.contoso.com/data/.default"},
+	}
+
+	for resource, expectedScopes := range tests {
+		t.Run(resource, func(t *testing.T) {
+			token := authTokenFn(func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+				require.Equal(t, expectedScopes, options.Scopes)
+				return azcore.AccessToken{Token: "ABC123"}, nil
+			})
+
+			a := newAuthTokenAction(
+				credentialProviderForTokenFn(token),
+				&output.JsonFormatter{},
+				io.Discard,
+				&authTokenFlags{resource: resource},
+				func(ctx context.Context) (*environment.Environment, error) {
+					return nil, fmt.Errorf("not an azd env directory")
+				},
+				&mockSubscriptionTenantResolver{},
+				cloud.AzurePublic(),
+			)
+
+			_, err := a.Run(context.Background())
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("WithScope", func(t *testing.T) {
+		a := newAuthTokenAction(
+			credentialProviderForTokenFn(nil),
+			&output.JsonFormatter{},
+			io.Discard,
+			&authTokenFlags{resource: "graph", scopes: []string{"scopeA"}},
+			func(ctx context.Context) (*environment.Environment, error) {
+				return nil, fmt.Errorf("not an azd env directory")
+			},
+			&mockSubscriptionTenantResolver{},
+			cloud.AzurePublic(),
+		)
+
+		_, err := a.Run(context.Background())
+		require.ErrorContains(t, err, "can't be used together")
+	})
+}
+
+func TestAuthTokenAzdEnvPinnedTenant(t *testing.T) {
+	token := authTokenFn(func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+		return azcore.AccessToken{Token: "ABC123"}, nil
+	})
+
+	a := newAuthTokenAction(
+		func(ctx context.Context, options *auth.CredentialForCurrentUserOptions) (azcore.TokenCredential, error) {
+			require.Equal(t, "pinned-tenant", options.TenantID)
+			return credentialProviderForTokenFn(token)(ctx, options)
+		},
+		&output.JsonFormatter{},
+		io.Discard,
+		&authTokenFlags{},
+		func(ctx context.Context) (*environment.Environment, error) {
+			return environment.NewWithValues("env", map[string]string{
+				environment.SubscriptionIdEnvVarName: "sub-id",
+				environment.AuthTenantIdEnvVarName:   "pinned-tenant",
+			}), nil
+		},
+		&mockSubscriptionTenantResolver{
+			Err: errors.New("the tenant of the subscription should not be looked up"),
+		},
+		cloud.AzurePublic(),
+	)
+
+	_, err := a.Run(context.Background())
+	require.NoError(t, err)
+}
+
+func TestAuthTokenRawOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	token := authTokenFn(func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+		return azcore.AccessToken{Token: "ABC123"}, nil
+	})
+
+	a := newAuthTokenAction(
+		credentialProviderForTokenFn(token),
+		&output.NoneFormatter{},
+		buf,
+		&authTokenFlags{},
+		func(ctx context.Context) (*environment.Environment, error) {
+			return nil, fmt.Errorf("not an azd env directory")
+		},
+		&mockSubscriptionTenantResolver{},
+		cloud.AzurePublic(),
+	)
+
+	_, err := a.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ABC123\n", buf.String())
+}
+
 func TestAuthTokenFailure(t *testing.T) {
 	token := authTokenFn(func(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
 		return azcore.AccessToken{}, errors.New("could not fetch token")
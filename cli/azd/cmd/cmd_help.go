@@ -83,7 +83,8 @@ func getCmdHelpDefaultDescription(cmd *cobra.Command) string {
 func getCmdHelpDefaultUsage(cmd *cobra.Command) string {
 	return fmt.Sprintf("%s\n  %s\n\n",
 		output.WithBold("%s", output.WithUnderline("Usage")),
-		"{{if .Runnable}}{{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}{{.CommandPath}} [command]{{end}}",
+		"{{if .Runnable}}{{.UseLine}}{{end}}{{if and .Runnable .HasAvailableSubCommands}}\n  {{end}}"+
+			"{{if .HasAvailableSubCommands}}{{.CommandPath}} [command]{{end}}",
 	)
 }
 
@@ -262,7 +263,7 @@ func getCommandsDetails(cmd *cobra.Command) (result string) {
 
 // formatHelpNote provides the expected format in description notes using `•`.
 func formatHelpNote(note string) string {
-	return fmt.Sprintf("  • %s", note)
+	return fmt.Sprintf("  %s %s", output.Bullet(), note)
 }
 
 // getPreFooter automatically adds a message to any command containing sub-commands about how to get help for subcommands.
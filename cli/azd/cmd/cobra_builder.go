@@ -56,8 +56,9 @@ func (cb *CobraBuilder) BuildCommand(descriptor *actions.ActionDescriptor) (*cob
 		}
 	}
 
-	// Configure action resolver for leaf commands
-	if !cmd.HasSubCommands() {
+	// Configure action resolver for leaf commands, and for commands which run an action of their own besides their
+	// sub commands, ex) 'azd provision' and 'azd provision history'
+	if !cmd.HasSubCommands() || descriptor.Options.ActionResolver != nil {
 		if err := cb.configureActionResolver(cmd, descriptor); err != nil {
 			return nil, err
 		}
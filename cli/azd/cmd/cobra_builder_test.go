@@ -139,6 +139,47 @@ func Test_BuildAndRunActionWithNestedMiddleware(t *testing.T) {
 	require.True(t, middlewareBRan)
 }
 
+func Test_BuildAndRunActionWithSubCommands(t *testing.T) {
+	container := ioc.NewNestedContainer(nil)
+	setup(container)
+
+	root := actions.NewActionDescriptor("root", nil)
+	parent := root.Add("parent", &actions.ActionDescriptorOptions{
+		ActionResolver: newTestAction,
+		FlagsResolver:  newTestFlags,
+	})
+	childRan := false
+	parent.Add("child", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			RunE: func(cmd *cobra.Command, args []string) error {
+				childRan = true
+				return nil
+			},
+		},
+	})
+
+	builder := NewCobraBuilder(container)
+	cmd, err := builder.BuildCommand(root)
+
+	require.NotNil(t, cmd)
+	require.NoError(t, err)
+
+	actionRan := false
+	ctx := context.WithValue(context.Background(), actionName, &actionRan)
+
+	cmd.SetArgs([]string{"parent", "-r"})
+	err = cmd.ExecuteContext(ctx)
+
+	require.NoError(t, err)
+	require.True(t, actionRan)
+
+	cmd.SetArgs([]string{"parent", "child"})
+	err = cmd.ExecuteContext(ctx)
+
+	require.NoError(t, err)
+	require.True(t, childRan)
+}
+
 func Test_BuildAndRunActionWithNestedAndConditionalMiddleware(t *testing.T) {
 	container := ioc.NewNestedContainer(nil)
 	setup(container)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"maps"
+	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
@@ -18,12 +19,23 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/secretguard"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/spf13/cobra"
 )
 
+var configResetPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "CONFIG_RESET",
+	Description: "Whether to remove all the values of the azd configuration, without --force: true or false.",
+	Commands:    []string{"config reset"},
+})
+
 var userConfigPath string
 
 // Setup account command category
@@ -169,6 +181,21 @@ $ azd config set defaults.location eastus`,
 		ActionResolver: newConfigListAlphaAction,
 	})
 
+	group.Add("validate-project", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "validate-project [<path>]",
+			Short: "Validates the azure.yaml file of a project.",
+			Long: "Validates azure.yaml against its schema, checks that the paths and Dockerfiles it references exist " +
+				"and that the languages of its services are supported by their hosts. Fails when errors are found.",
+			Args: cobra.MaximumNArgs(1),
+			Example: `$ azd config validate-project
+$ azd config validate-project ./src/azure.yaml --output json`,
+		},
+		ActionResolver: newConfigValidateProjectAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
 	return group
 }
 
@@ -307,6 +334,36 @@ func (a *configSetAction) Run(ctx context.Context) (*actions.ActionResult, error
 	path := a.args[0]
 	value := a.args[1]
 
+	if path == output.ThemeConfigPath {
+		if _, err := output.ParseTheme(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if path == output.ProvisionViewConfigPath {
+		if _, err := output.ParseProvisionView(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if path == tools.MirrorConfigPath {
+		if err := tools.ValidateMirror(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if path == tools.BundleConfigPath {
+		if err := tools.ValidateBundle(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if path == secretguard.ModeConfigPath {
+		if _, err := secretguard.ParseMode(value); err != nil {
+			return nil, err
+		}
+	}
+
 	err = azdConfig.Set(path, value)
 	if err != nil {
 		return nil, fmt.Errorf("failed setting configuration value '%s' to '%s'. %w", path, value, err)
@@ -394,6 +451,7 @@ func (a *configResetAction) Run(ctx context.Context) (*actions.ActionResult, err
 		a.console.Message(ctx, output.WithWarningFormat(warningMessage))
 
 		confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Id:           configResetPromptId,
 			Message:      "Continue with reset?",
 			DefaultValue: false,
 		})
@@ -420,6 +478,111 @@ func (a *configResetAction) Run(ctx context.Context) (*actions.ActionResult, err
 	}, nil
 }
 
+// azd config validate-project [<path>]
+
+// ProjectValidationResult is the result of 'azd config validate-project'
+type ProjectValidationResult struct {
+	Path        string                      `json:"path"`
+	Valid       bool                        `json:"valid"`
+	Diagnostics []project.ProjectDiagnostic `json:"diagnostics"`
+}
+
+type configValidateProjectAction struct {
+	lazyAzdCtx *lazy.Lazy[*azdcontext.AzdContext]
+	console    input.Console
+	formatter  output.Formatter
+	writer     io.Writer
+	args       []string
+}
+
+func newConfigValidateProjectAction(
+	lazyAzdCtx *lazy.Lazy[*azdcontext.AzdContext],
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &configValidateProjectAction{
+		lazyAzdCtx: lazyAzdCtx,
+		console:    console,
+		formatter:  formatter,
+		writer:     writer,
+		args:       args,
+	}
+}
+
+// Executes the `azd config validate-project [<path>]` action
+func (a *configValidateProjectAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	var projectFilePath string
+	if len(a.args) == 1 {
+		projectFilePath = a.args[0]
+		if info, err := os.Stat(projectFilePath); err == nil && info.IsDir() {
+			projectFilePath = filepath.Join(projectFilePath, azdcontext.ProjectFileName)
+		}
+	} else {
+		azdCtx, err := a.lazyAzdCtx.GetValue()
+		if err != nil {
+			return nil, err
+		}
+
+		projectFilePath = azdCtx.ProjectPath()
+	}
+
+	diagnostics, err := project.ValidateProject(ctx, projectFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	errorCount := 0
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == project.DiagnosticError {
+			errorCount++
+		}
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		result := ProjectValidationResult{
+			Path:        projectFilePath,
+			Valid:       errorCount == 0,
+			Diagnostics: diagnostics,
+		}
+
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, fmt.Errorf("failing formatting validation result: %w", err)
+		}
+	} else {
+		for _, diagnostic := range diagnostics {
+			severity := output.WithErrorFormat(string(diagnostic.Severity))
+			if diagnostic.Severity == project.DiagnosticWarning {
+				severity = output.WithWarningFormat(string(diagnostic.Severity))
+			}
+
+			a.console.Message(ctx, fmt.Sprintf("%s:%d:%d: %s: %s %s",
+				projectFilePath,
+				diagnostic.Line,
+				diagnostic.Column,
+				severity,
+				diagnostic.Message,
+				output.WithGrayFormat("(%s)", diagnostic.Code),
+			))
+		}
+	}
+
+	if errorCount > 0 {
+		return nil, fmt.Errorf("%s is not valid, %d error(s) found", projectFilePath, errorCount)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("%s is valid.", projectFilePath),
+		},
+	}, nil
+}
+
 func getCmdConfigHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		"Manage the Azure Developer CLI user configuration.",
@@ -434,6 +597,43 @@ func getCmdConfigHelpDescription(*cobra.Command) string {
 				"The default values for azd prompts like subscription and location are stored with the key: %s.",
 				output.WithLinkFormat("defaults"),
 			)),
+			formatHelpNote(fmt.Sprintf(
+				"The HTTP(S) proxy and the additional trusted CA certificates used by azd are stored with the keys: "+
+					"%s and %s. Proxies with basic authentication take the credentials in the URL, "+
+					"NTLM and Kerberos authentication aren't supported.",
+				output.WithLinkFormat("network.proxy"),
+				output.WithLinkFormat("network.caBundle"),
+			)),
+			formatHelpNote(fmt.Sprintf(
+				"The regular expressions of the values masked in the output, in addition to the secrets known to azd, "+
+					"are stored with the key: %s.",
+				output.WithLinkFormat("output.redact.patterns"),
+			)),
+			formatHelpNote(fmt.Sprintf(
+				"The theme of the console output (dark, light, high-contrast or no-unicode) and whether spinners are "+
+					"animated are stored with the keys: %s and %s.",
+				output.WithLinkFormat(output.ThemeConfigPath),
+				output.WithLinkFormat(output.ReducedMotionConfigPath),
+			)),
+			formatHelpNote(fmt.Sprintf(
+				"Whether 'azd provision' shows its progress in a full-screen dashboard (tui) or as scrolling lines "+
+					"(default) is stored with the key: %s.",
+				output.WithLinkFormat(output.ProvisionViewConfigPath),
+			)),
+			formatHelpNote(fmt.Sprintf(
+				"The internal mirror URL and the offline bundle directory the tools azd downloads (bicep, gh and pack) "+
+					"are downloaded from instead of their release sites are stored with the keys: %s and %s. Each "+
+					"release is found under the same path as its original URL with the host, ex) "+
+					"downloads.bicep.azure.com/v0.36.1/bicep-linux-x64, next to a .sha256 file with its checksum, "+
+					"which detects corrupted copies but doesn't authenticate the release.",
+				output.WithLinkFormat(tools.MirrorConfigPath),
+				output.WithLinkFormat(tools.BundleConfigPath),
+			)),
+			formatHelpNote(fmt.Sprintf(
+				"What 'azd env set' does with credentials set in a .env file tracked by git (warn, block or off) "+
+					"is stored with the key: %s.",
+				output.WithLinkFormat(secretguard.ModeConfigPath),
+			)),
 		})
 }
 
@@ -445,6 +645,18 @@ func getCmdConfigHelpFooter(c *cobra.Command) string {
 		"Set the default Azure deployment location.": fmt.Sprintf("%s %s",
 			output.WithHighLightFormat("azd config set defaults.location"),
 			output.WithWarningFormat("<location>")),
+		"Set the HTTP(S) proxy used by azd.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd config set network.proxy"),
+			output.WithWarningFormat("<proxyUrl>")),
+		"Trust the CA certificates of a PEM file.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd config set network.caBundle"),
+			output.WithWarningFormat("<pemFilePath>")),
+		"Confirm the changes to the .env file of environments before they are saved.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd config set environment.confirmChanges"),
+			output.WithWarningFormat("true")),
+		"Use colors readable on terminals with a light background.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd config set ux.theme"),
+			output.WithWarningFormat("light")),
 	})
 }
 
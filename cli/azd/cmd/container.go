@@ -47,18 +47,22 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	infraTerraform "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/terraform"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/journal"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
 	"github.com/azure/azure-dev/cli/azd/pkg/kustomize"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/llm"
+	"github.com/azure/azure-dev/cli/azd/pkg/notify"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/secretguard"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/az"
@@ -66,6 +70,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
@@ -105,6 +110,26 @@ func resolveAction[T actions.Action](serviceLocator ioc.ServiceLocator, actionNa
 	return instance, nil
 }
 
+// configureNetwork applies the network settings of the user config (network.proxy and network.caBundle) to client. Since
+// client is http.DefaultClient, the settings are honored by the Azure SDK clients, which use it as their transport, and
+// by the tool and template downloads, which use http.DefaultClient directly.
+func configureNetwork(client *http.Client) {
+	var base *http.Transport
+	switch transport := client.Transport.(type) {
+	case nil:
+		base = http.DefaultTransport.(*http.Transport)
+	case *http.Transport:
+		base = transport
+	default:
+		// Already configured, or replaced by a test
+		return
+	}
+
+	client.Transport = httputil.NewConfiguredTransport(base, func() (config.Config, error) {
+		return config.NewUserConfigManager(config.NewFileConfigManager(config.NewManager())).Load()
+	})
+}
+
 // Registers common Azd dependencies
 func registerCommonDependencies(container *ioc.NestedContainer) {
 	// Core bootstrapping registrations
@@ -128,6 +153,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			writer = colorable.NewNonColorable(writer)
 		}
 
+		// Secrets, ex) the values of secure parameters or fetched from Key Vault, are masked in the console output
+		writer = output.NewRedactingWriter(writer)
+
 		isTerminal := cmd.OutOrStdout() == os.Stdout &&
 			cmd.InOrStdin() == os.Stdin && input.IsTerminal(os.Stdout.Fd(), os.Stdin.Fd())
 
@@ -151,6 +179,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	)
 
 	client := createHttpClient()
+	configureNetwork(client)
 	ioc.RegisterInstance[policy.Transporter](container, client)
 	ioc.RegisterInstance[auth.HttpClient](container, client)
 
@@ -167,7 +196,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			writer = colorable.NewNonColorable(writer)
 		}
 
-		return writer
+		// The formatted output of the commands, ex) --output json, masks the secrets too
+		return output.NewRedactingWriter(writer)
 	})
 
 	container.MustRegisterScoped(func(cmd *cobra.Command) internal.EnvFlag {
@@ -244,7 +274,10 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			return env, nil
 		},
 	)
-	container.MustRegisterScoped(func(lazyEnvManager *lazy.Lazy[environment.Manager]) environment.EnvironmentResolver {
+	container.MustRegisterScoped(func(
+		serviceLocator ioc.ServiceLocator,
+		lazyEnvManager *lazy.Lazy[environment.Manager],
+	) environment.EnvironmentResolver {
 		return func(ctx context.Context) (*environment.Environment, error) {
 			azdCtx, err := azdcontext.NewAzdContext()
 			if err != nil {
@@ -255,6 +288,12 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 				return nil, err
 			}
 
+			// The environment set with the env flag, ex) AZURE_ENV_NAME in hooks, overrides the default environment
+			var envFlag internal.EnvFlag
+			if err := serviceLocator.Resolve(&envFlag); err == nil && envFlag.EnvironmentName != "" {
+				defaultEnv = envFlag.EnvironmentName
+			}
+
 			// We need to lazy load the environment manager since it depends on azd context
 			envManager, err := lazyEnvManager.GetValue()
 			if err != nil {
@@ -383,6 +422,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		func(
 			ctx context.Context,
 			lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext],
+			serviceLocator ioc.ServiceLocator,
 		) *lazy.Lazy[*project.ProjectConfig] {
 			return lazy.NewLazy(func() (*project.ProjectConfig, error) {
 				azdCtx, err := lazyAzdContext.GetValue()
@@ -390,7 +430,22 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 					return nil, err
 				}
 
-				projectConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+				// The overrides of the environment in azure.yaml are merged when the environment is known. The
+				// environment flag isn't available outside of a command, the default environment is used then.
+				var envFlags internal.EnvFlag
+				if err := serviceLocator.Resolve(&envFlags); err != nil {
+					log.Printf("no environment flag, loading the project for the default environment: %v", err)
+				}
+
+				environmentName := envFlags.EnvironmentName
+				if environmentName == "" {
+					environmentName, err = azdCtx.GetDefaultEnvironmentName()
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				projectConfig, err := project.LoadForEnvironment(ctx, azdCtx.ProjectPath(), environmentName)
 				if err != nil {
 					return nil, err
 				}
@@ -531,6 +586,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(project.NewDotNetImporter)
 	container.MustRegisterScoped(project.NewImportManager)
 	container.MustRegisterScoped(project.NewServiceManager)
+	container.MustRegisterScoped(project.NewServiceBindings)
+	container.MustRegisterSingleton(project.NewServiceLogStreamer)
 
 	// Even though the service manager is scoped based on its use of environment we can still
 	// register its internal cache as a singleton to ensure operation caching is consistent across all instances
@@ -594,6 +651,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(account.NewSubscriptionsManager)
 	container.MustRegisterSingleton(account.NewSubscriptionCredentialProvider)
 	container.MustRegisterSingleton(azapi.NewManagedClustersService)
+	container.MustRegisterSingleton(azapi.NewCdnService)
+	container.MustRegisterSingleton(azapi.NewRealtimeService)
+	container.MustRegisterSingleton(azapi.NewCustomDomainService)
 	container.MustRegisterSingleton(entraid.NewEntraIdService)
 	container.MustRegisterSingleton(armmsi.NewArmMsiService)
 	container.MustRegisterSingleton(azapi.NewContainerRegistryService)
@@ -602,7 +662,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(keyvault.NewKeyVaultService)
 	container.MustRegisterSingleton(storage.NewFileShareService)
 	container.MustRegisterScoped(project.NewContainerHelper)
+	container.MustRegisterScoped(project.NewPackageArtifacts)
 	container.MustRegisterSingleton(azapi.NewSpringService)
+	container.MustRegisterSingleton(notify.NewNotifier)
 
 	container.MustRegisterSingleton(func(subManager *account.SubscriptionsManager) account.SubscriptionTenantResolver {
 		return subManager
@@ -616,10 +678,12 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(docker.NewCli)
 	container.MustRegisterSingleton(dotnet.NewCli)
 	container.MustRegisterSingleton(git.NewCli)
+	container.MustRegisterScoped(secretguard.NewGuard)
 	container.MustRegisterSingleton(github.NewGitHubCli)
 	container.MustRegisterSingleton(javac.NewCli)
 	container.MustRegisterSingleton(kubectl.NewCli)
 	container.MustRegisterSingleton(maven.NewCli)
+	container.MustRegisterSingleton(gradle.NewCli)
 	container.MustRegisterSingleton(kubelogin.NewCli)
 	container.MustRegisterSingleton(helm.NewCli)
 	container.MustRegisterSingleton(kustomize.NewCli)
@@ -650,6 +714,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	})
 
 	container.MustRegisterSingleton(azapi.NewResourceService)
+	container.MustRegisterScoped(journal.NewCleaner)
 
 	// Register Deployment Services
 	deploymentServiceTypes := map[azapi.DeploymentType]any{
@@ -671,6 +736,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(infra.NewAzureResourceManager)
 	container.MustRegisterScoped(provisioning.NewManager)
 	container.MustRegisterScoped(provisioning.NewPrincipalIdProvider)
+	container.MustRegisterScoped(provisioning.NewParameterSecrets)
 	container.MustRegisterScoped(prompt.NewDefaultPrompter)
 
 	// Other
@@ -687,6 +753,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.SpringAppTarget:          project.NewSpringAppTarget,
 		project.DotNetContainerAppTarget: project.NewDotNetContainerAppTarget,
 		project.AiEndpointTarget:         project.NewAiEndpointTarget,
+		project.AciTarget:                project.NewAciTarget,
+		project.StorageStaticSiteTarget:  project.NewStorageStaticSiteTarget,
+		project.LogicAppTarget:           project.NewLogicAppTarget,
 	}
 
 	for target, constructor := range serviceTargetMap {
@@ -715,6 +784,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	// Pipelines
 	container.MustRegisterScoped(pipeline.NewPipelineManager)
+	container.MustRegisterScoped(pipeline.NewAgentBootstrapper)
 	container.MustRegisterSingleton(func(flags *pipelineConfigFlags) *pipeline.PipelineManagerArgs {
 		return &flags.PipelineManagerArgs
 	})
@@ -818,6 +888,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterScoped(func(authManager *auth.Manager) prompt.AuthManager {
 		return authManager
 	})
+	container.MustRegisterScoped(func(authManager *auth.Manager) infraTerraform.AuthManager {
+		return authManager
+	})
 	container.MustRegisterSingleton(func(subscriptionManager *account.SubscriptionsManager) prompt.SubscriptionManager {
 		return subscriptionManager
 	})
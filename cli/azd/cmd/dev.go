@@ -0,0 +1,432 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The port of the first SWA emulator, the emulators of the next static web apps listen on the following ports
+const devEmulatorBasePort = 4280
+
+type devFlags struct {
+	debounce time.Duration
+	global   *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *devFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.DurationVar(
+		&f.debounce,
+		"debounce",
+		500*time.Millisecond,
+		"The time to wait for the changes of a service to settle before deploying it.",
+	)
+}
+
+func newDevFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *devFlags {
+	flags := &devFlags{}
+	flags.Bind(cmd.Flags(), global)
+	flags.EnvFlag.Bind(cmd.Flags(), global)
+	flags.global = global
+
+	return flags
+}
+
+func newDevCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev <service>",
+		Short: "Watch your services and deploy their changes as you edit them.",
+	}
+	cmd.Args = cobra.MaximumNArgs(1)
+	return cmd
+}
+
+type devAction struct {
+	flags          *devFlags
+	args           []string
+	console        input.Console
+	env            *environment.Environment
+	projectConfig  *project.ProjectConfig
+	projectManager project.ProjectManager
+	serviceManager project.ServiceManager
+	importManager  *project.ImportManager
+	swaCli         *swa.Cli
+}
+
+func newDevAction(
+	flags *devFlags,
+	args []string,
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	serviceManager project.ServiceManager,
+	importManager *project.ImportManager,
+	swaCli *swa.Cli,
+) actions.Action {
+	return &devAction{
+		flags:          flags,
+		args:           args,
+		console:        console,
+		env:            env,
+		projectConfig:  projectConfig,
+		projectManager: projectManager,
+		serviceManager: serviceManager,
+		importManager:  importManager,
+		swaCli:         swaCli,
+	}
+}
+
+func (da *devAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	targetServiceName := ""
+	if len(da.args) == 1 {
+		targetServiceName = da.args[0]
+	}
+
+	if da.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	// All the services are watched unless a service is specified
+	targetServiceName, err := getTargetServiceName(
+		ctx,
+		da.projectManager,
+		da.importManager,
+		da.projectConfig,
+		"watch",
+		targetServiceName,
+		targetServiceName == "",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := da.projectManager.EnsureServiceTargetTools(ctx, da.projectConfig, func(svc *project.ServiceConfig) bool {
+		return targetServiceName == "" || svc.Name == targetServiceName
+	}); err != nil {
+		return nil, err
+	}
+
+	stableServices, err := da.importManager.ServiceStable(ctx, da.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []*project.ServiceConfig{}
+	for _, svc := range stableServices {
+		if targetServiceName == "" || svc.Name == targetServiceName {
+			services = append(services, svc)
+		}
+	}
+
+	// Command title
+	da.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     "Starting development loop (azd dev)",
+		TitleNote: "Changes to the services are deployed as they are saved. Press Ctrl+C to stop",
+	})
+
+	status := newDevStatus(services)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var emulators sync.WaitGroup
+	defer emulators.Wait()
+
+	port := devEmulatorBasePort
+	for _, svc := range services {
+		if svc.Host != project.StaticWebAppTarget {
+			continue
+		}
+
+		// Static web apps are served locally by the SWA emulator rather than deployed
+		if err := da.build(ctx, svc); err != nil {
+			status.set(svc.Name, devServiceFailed, err.Error())
+			continue
+		}
+
+		emulators.Add(1)
+		go da.startEmulator(ctx, svc, port, status, &emulators)
+		port++
+	}
+
+	watcher, err := project.NewServiceWatcher(services, da.flags.debounce)
+	if err != nil {
+		return nil, err
+	}
+
+	da.console.MessageUxItem(ctx, status)
+
+	err = watcher.Watch(ctx, func(ctx context.Context, changed []*project.ServiceConfig) {
+		for _, svc := range changed {
+			status.set(svc.Name, devServiceUpdating, "")
+
+			if svc.Host == project.StaticWebAppTarget {
+				if err := da.build(ctx, svc); err != nil {
+					status.set(svc.Name, devServiceFailed, err.Error())
+					continue
+				}
+
+				// The emulator serves the new output of the build
+				status.set(svc.Name, devServiceEmulating, "")
+				continue
+			}
+
+			if err := da.deploy(ctx, svc); err != nil {
+				status.set(svc.Name, devServiceFailed, err.Error())
+				continue
+			}
+
+			status.set(svc.Name, devServiceUpdated, "")
+		}
+
+		da.console.MessageUxItem(ctx, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// build builds the service, which output is served by the SWA emulator for static web apps
+func (da *devAction) build(ctx context.Context, svc *project.ServiceConfig) error {
+	stepMessage := fmt.Sprintf("Building service %s", svc.Name)
+	da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	_, err := async.RunWithProgress(
+		func(buildProgress project.ServiceProgress) {
+			progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
+			da.console.ShowSpinner(ctx, progressMessage, input.Step)
+		},
+		func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceBuildResult, error) {
+			return da.serviceManager.Build(ctx, svc, nil, progress)
+		},
+	)
+
+	da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	return err
+}
+
+// deploy packages and deploys the service with the fast path of its host, ex) a new revision of a container app with
+// the new image, or a zip deployment of a function app
+func (da *devAction) deploy(ctx context.Context, svc *project.ServiceConfig) error {
+	stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
+	da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	packageResult, err := async.RunWithProgress(
+		func(packageProgress project.ServiceProgress) {
+			progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, packageProgress.Message)
+			da.console.ShowSpinner(ctx, progressMessage, input.Step)
+		},
+		func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
+			return da.serviceManager.Package(ctx, svc, nil, progress, nil)
+		},
+	)
+	if err != nil {
+		da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+		return err
+	}
+
+	_, err = async.RunWithProgress(
+		func(deployProgress project.ServiceProgress) {
+			progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, deployProgress.Message)
+			da.console.ShowSpinner(ctx, progressMessage, input.Step)
+		},
+		func(progress *async.Progress[project.ServiceProgress]) (*project.ServiceDeployResult, error) {
+			return da.serviceManager.Deploy(ctx, svc, packageResult, progress)
+		},
+	)
+
+	da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	return err
+}
+
+// startEmulator runs the SWA emulator of the static web app until the context is cancelled
+func (da *devAction) startEmulator(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	port int,
+	status *devStatus,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+
+	status.setUrl(svc.Name, fmt.Sprintf("http://localhost:%d", port))
+	status.set(svc.Name, devServiceEmulating, "")
+
+	err := da.swaCli.Start(ctx, svc.Path(), swa.StartOptions{
+		OutputRelativeFolderPath: svc.OutputPath,
+		Port:                     port,
+	}, log.Writer())
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err == nil {
+		err = errors.New("the emulator exited")
+	}
+
+	status.set(svc.Name, devServiceFailed, err.Error())
+	da.console.Message(ctx, output.WithErrorFormat("The emulator of service %s stopped: %v", svc.Name, err))
+}
+
+type devServiceState string
+
+const (
+	devServiceWatching  devServiceState = "Watching"
+	devServiceEmulating devServiceState = "Emulating"
+	devServiceUpdating  devServiceState = "Updating"
+	devServiceUpdated   devServiceState = "Updated"
+	devServiceFailed    devServiceState = "Failed"
+)
+
+// devServiceStatus is the watch status of a service of 'azd dev'
+type devServiceStatus struct {
+	Name   string          `json:"name"`
+	Host   string          `json:"host"`
+	State  devServiceState `json:"state"`
+	Detail string          `json:"detail,omitempty"`
+	// The url of the SWA emulator of a static web app
+	Url       string     `json:"url,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// devStatus is the watch status of the services of 'azd dev', displayed after each change
+type devStatus struct {
+	mu       sync.Mutex
+	services []*devServiceStatus
+}
+
+func newDevStatus(services []*project.ServiceConfig) *devStatus {
+	status := &devStatus{}
+	for _, svc := range services {
+		status.services = append(status.services, &devServiceStatus{
+			Name:  svc.Name,
+			Host:  string(svc.Host),
+			State: devServiceWatching,
+		})
+	}
+
+	return status
+}
+
+func (s *devStatus) set(name string, state devServiceState, detail string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, svc := range s.services {
+		if svc.Name != name {
+			continue
+		}
+
+		if state == devServiceUpdated {
+			now := time.Now()
+			svc.UpdatedAt = &now
+		}
+
+		svc.State = state
+		svc.Detail = detail
+	}
+}
+
+func (s *devStatus) setUrl(name string, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, svc := range s.services {
+		if svc.Name == name {
+			svc.Url = url
+		}
+	}
+}
+
+func (s *devStatus) ToString(currentIndentation string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nameWidth := 0
+	for _, svc := range s.services {
+		nameWidth = max(nameWidth, len(svc.Name)+len(svc.Host)+3)
+	}
+
+	builder := strings.Builder{}
+	for _, svc := range s.services {
+		state := string(svc.State)
+		switch svc.State {
+		case devServiceUpdated, devServiceEmulating:
+			state = output.WithSuccessFormat(state)
+		case devServiceFailed:
+			state = output.WithErrorFormat(state)
+		}
+
+		detail := svc.Detail
+		switch {
+		case svc.State == devServiceEmulating && svc.Url != "":
+			detail = output.WithLinkFormat(svc.Url)
+		case svc.State == devServiceUpdated && svc.UpdatedAt != nil:
+			detail = output.WithGrayFormat("at %s", svc.UpdatedAt.Format(time.TimeOnly))
+		}
+
+		name := fmt.Sprintf("%s (%s)", svc.Name, svc.Host)
+		builder.WriteString(fmt.Sprintf("%s%-*s  %s", currentIndentation, nameWidth, name, state))
+		if detail != "" {
+			builder.WriteString("  " + detail)
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+func (s *devStatus) MarshalJSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return json.Marshal(s.services)
+}
+
+func getCmdDevHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription("Watch the services of your project and deploy their changes as you edit them.",
+		[]string{
+			formatHelpNote("Only the services which files changed are rebuilt and deployed, once the changes settle."),
+			formatHelpNote("Container apps get a new revision with the new image, and function apps are zip deployed."),
+			formatHelpNote("Static web apps are served locally by the SWA emulator, and rebuilt when their files change."),
+			formatHelpNote(
+				fmt.Sprintf("When %s is set, only the specific service is watched.", output.WithHighLightFormat("<service>"))),
+		})
+}
+
+func getCmdDevHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Watch all the services of the current project.": output.WithHighLightFormat("azd dev"),
+		"Watch the service named 'api'.":                 output.WithHighLightFormat("azd dev api"),
+	})
+}
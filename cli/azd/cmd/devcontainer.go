@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+)
+
+func devcontainerActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("devcontainer", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "devcontainer",
+			Short: "Manage the dev container configuration of your project.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+	})
+
+	group.Add("sync", &actions.ActionDescriptorOptions{
+		Command:        newDevcontainerSyncCmd(),
+		ActionResolver: newDevcontainerSyncAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDevcontainerSyncHelpDescription,
+		},
+	})
+
+	return group
+}
+
+func newDevcontainerSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Generate or update the dev container configuration with the tools your project needs.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+func getCmdDevcontainerSyncHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		fmt.Sprintf(
+			"Generate %s, used by GitHub Codespaces and VS Code, or add the features your project needs to it.",
+			output.WithHighLightFormat(filepath.ToSlash(devcontainer.RelativePath)),
+		),
+		[]string{
+			formatHelpNote("The features of azd, the infrastructure provider, docker and the language runtimes of " +
+				"the services in azure.yaml are added. The existing features and settings are kept, but not the " +
+				"comments of the file."),
+		},
+	)
+}
+
+type devcontainerSyncAction struct {
+	projectConfig *project.ProjectConfig
+	console       input.Console
+}
+
+func newDevcontainerSyncAction(
+	projectConfig *project.ProjectConfig,
+	console input.Console,
+) actions.Action {
+	return &devcontainerSyncAction{
+		projectConfig: projectConfig,
+		console:       console,
+	}
+}
+
+func (a *devcontainerSyncAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Synchronizing the dev container configuration (azd devcontainer sync)",
+	})
+
+	result, err := devcontainer.Sync(a.projectConfig.Path, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: devcontainerSyncMessage(a.projectConfig.Path, result),
+		},
+	}, nil
+}
+
+// devcontainerSyncMessage describes the changes made to the dev container configuration of the project
+func devcontainerSyncMessage(projectDir string, result *devcontainer.SyncResult) string {
+	path := result.Path
+	if relPath, err := filepath.Rel(projectDir, result.Path); err == nil {
+		path = filepath.ToSlash(relPath)
+	}
+
+	switch {
+	case result.Created:
+		return fmt.Sprintf("Generated %s with the features: %s.",
+			output.WithHighLightFormat(path), strings.Join(result.Added, ", "))
+	case len(result.Added) > 0:
+		return fmt.Sprintf("Added to %s the features: %s.",
+			output.WithHighLightFormat(path), strings.Join(result.Added, ", "))
+	default:
+		return fmt.Sprintf("%s already has the features your project needs.", output.WithHighLightFormat(path))
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	osexec "os/exec"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	azdcloud "github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type doctorFlags struct {
+	global *internal.GlobalCommandOptions
+	*internal.EnvFlag
+}
+
+func (f *doctorFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag = &internal.EnvFlag{}
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newDoctorFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *doctorFlags {
+	flags := &doctorFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks the connectivity, authentication, tools and permissions required by the current project.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+// The actions checked on the subscription of the environment, and whether they are required to provision
+var doctorRequiredActions = []struct {
+	action   string
+	required bool
+}{
+	{"Microsoft.Resources/deployments/write", true},
+	{"Microsoft.Authorization/roleAssignments/write", false},
+}
+
+// Replaceable for testing
+var checkHostConnectivity = containerregistry.CheckConnectivity
+
+type doctorAction struct {
+	flags              *doctorFlags
+	console            input.Console
+	formatter          output.Formatter
+	writer             io.Writer
+	authManager        *auth.Manager
+	cloud              *azdcloud.Cloud
+	resourceService    *azapi.ResourceService
+	lazyProjectConfig  *lazy.Lazy[*project.ProjectConfig]
+	lazyEnv            *lazy.Lazy[*environment.Environment]
+	lazyServiceManager *lazy.Lazy[project.ServiceManager]
+}
+
+func newDoctorAction(
+	flags *doctorFlags,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	authManager *auth.Manager,
+	cloud *azdcloud.Cloud,
+	resourceService *azapi.ResourceService,
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	lazyServiceManager *lazy.Lazy[project.ServiceManager],
+) actions.Action {
+	return &doctorAction{
+		flags:              flags,
+		console:            console,
+		formatter:          formatter,
+		writer:             writer,
+		authManager:        authManager,
+		cloud:              cloud,
+		resourceService:    resourceService,
+		lazyProjectConfig:  lazyProjectConfig,
+		lazyEnv:            lazyEnv,
+		lazyServiceManager: lazyServiceManager,
+	}
+}
+
+func (a *doctorAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	result := contracts.DoctorResult{}
+	result.Checks = append(result.Checks, a.checkConnectivity(ctx)...)
+
+	authCheck := a.checkAuth(ctx)
+	result.Checks = append(result.Checks, authCheck)
+	result.Checks = append(result.Checks, a.checkTools(ctx)...)
+	result.Checks = append(result.Checks, a.checkPermissions(ctx, authCheck.Status == contracts.DoctorCheckPassed)...)
+
+	failed := 0
+	for _, check := range result.Checks {
+		if check.Status == contracts.DoctorCheckFailed {
+			failed++
+		}
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, check := range result.Checks {
+			a.console.Message(ctx, formatDoctorCheck(check))
+		}
+	}
+
+	if failed > 0 {
+		return nil, fmt.Errorf("%d of %d checks failed", failed, len(result.Checks))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "All checks passed.",
+		},
+	}, nil
+}
+
+// checkConnectivity checks the Microsoft Entra ID and Azure Resource Manager endpoints of the cloud can be reached.
+func (a *doctorAction) checkConnectivity(ctx context.Context) []contracts.DoctorCheck {
+	endpoints := []string{a.cloud.Configuration.ActiveDirectoryAuthorityHost}
+	if resourceManager, has := a.cloud.Configuration.Services[cloud.ResourceManager]; has {
+		endpoints = append(endpoints, resourceManager.Endpoint)
+	}
+
+	checks := []contracts.DoctorCheck{}
+	for _, endpoint := range endpoints {
+		host := endpoint
+		if parsed, err := url.Parse(endpoint); err == nil && parsed.Host != "" {
+			host = parsed.Host
+		}
+
+		name := fmt.Sprintf("Connectivity to %s", host)
+		result := checkHostConnectivity(ctx, host)
+		if result.Ok() {
+			checks = append(checks, doctorCheck(name, contracts.DoctorCheckPassed, strings.Join(result.Addresses, ", ")))
+			continue
+		}
+
+		err := result.DnsErr
+		if err == nil {
+			err = result.ConnectErr
+		}
+
+		checks = append(checks, doctorFailedCheck(name, &internal.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: fmt.Sprintf(
+				"Ensure this machine can resolve and reach %s on port 443, and configure HTTPS_PROXY if a proxy is "+
+					"required.", host),
+		}))
+	}
+
+	return checks
+}
+
+// checkAuth checks the current user is logged in and can get tokens for Azure.
+func (a *doctorAction) checkAuth(ctx context.Context) contracts.DoctorCheck {
+	const name = "Authentication"
+
+	credential, err := a.authManager.CredentialForCurrentUser(ctx, nil)
+	if err == nil {
+		_, err = credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: a.authManager.LoginScopes()})
+	}
+
+	if err != nil {
+		return doctorFailedCheck(name, err)
+	}
+
+	message := "Logged in to Azure"
+	if details, err := a.authManager.LogInDetails(ctx); err == nil {
+		message = fmt.Sprintf("Logged in to Azure as %s", details.Account)
+	}
+
+	return doctorCheck(name, contracts.DoctorCheckPassed, message)
+}
+
+// checkTools checks the tools required by the services of the project are installed with a supported version.
+func (a *doctorAction) checkTools(ctx context.Context) []contracts.DoctorCheck {
+	const name = "Tools"
+
+	projectConfig, err := a.lazyProjectConfig.GetValue()
+	if err != nil {
+		return []contracts.DoctorCheck{
+			doctorCheck(name, contracts.DoctorCheckSkipped, "No project found in the current directory"),
+		}
+	}
+
+	if _, err := a.lazyEnv.GetValue(); err != nil {
+		return []contracts.DoctorCheck{
+			doctorCheck(name, contracts.DoctorCheckSkipped, fmt.Sprintf("No environment selected: %s", err)),
+		}
+	}
+
+	serviceManager, err := a.lazyServiceManager.GetValue()
+	if err != nil {
+		return []contracts.DoctorCheck{doctorFailedCheck(name, err)}
+	}
+
+	serviceNames := make([]string, 0, len(projectConfig.Services))
+	for serviceName := range projectConfig.Services {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	slices.Sort(serviceNames)
+
+	requiredTools := []tools.ExternalTool{}
+	for _, serviceName := range serviceNames {
+		serviceTools, err := serviceManager.GetRequiredTools(ctx, projectConfig.Services[serviceName])
+		if err != nil {
+			return []contracts.DoctorCheck{
+				doctorFailedCheck(name, fmt.Errorf("getting tools of service '%s': %w", serviceName, err)),
+			}
+		}
+
+		requiredTools = append(requiredTools, serviceTools...)
+	}
+
+	requiredTools = tools.Unique(requiredTools)
+	if len(requiredTools) == 0 {
+		return []contracts.DoctorCheck{doctorCheck(name, contracts.DoctorCheckPassed, "No tools required")}
+	}
+
+	checks := []contracts.DoctorCheck{}
+	for _, tool := range requiredTools {
+		toolName := fmt.Sprintf("Tool %s", tool.Name())
+		err := tool.CheckInstalled(ctx)
+		switch {
+		case err == nil:
+			checks = append(checks, doctorCheck(toolName, contracts.DoctorCheckPassed, "Installed"))
+		case errors.Is(err, osexec.ErrNotFound):
+			checks = append(checks, doctorFailedCheck(toolName, &internal.ErrorWithSuggestion{
+				Err:        fmt.Errorf("%s is not installed: %w", tool.Name(), err),
+				Suggestion: fmt.Sprintf("Install %s, see %s", tool.Name(), tool.InstallUrl()),
+			}))
+		default:
+			checks = append(checks, doctorFailedCheck(toolName, err))
+		}
+	}
+
+	return checks
+}
+
+// checkPermissions checks the current user has the permissions to provision the environment on its subscription.
+func (a *doctorAction) checkPermissions(ctx context.Context, loggedIn bool) []contracts.DoctorCheck {
+	const name = "Permissions"
+
+	env, err := a.lazyEnv.GetValue()
+	if err != nil || env.GetSubscriptionId() == "" {
+		return []contracts.DoctorCheck{
+			doctorCheck(name, contracts.DoctorCheckSkipped, "No subscription is set for the environment"),
+		}
+	}
+
+	if !loggedIn {
+		return []contracts.DoctorCheck{doctorCheck(name, contracts.DoctorCheckSkipped, "Not logged in")}
+	}
+
+	permissions, err := a.resourceService.ListSubscriptionPermissions(ctx, env.GetSubscriptionId())
+	if err != nil {
+		return []contracts.DoctorCheck{doctorFailedCheck(name, err)}
+	}
+
+	checks := []contracts.DoctorCheck{}
+	for _, required := range doctorRequiredActions {
+		actionName := fmt.Sprintf("Permission %s", required.action)
+		if azapi.PermissionsAllow(permissions, required.action) {
+			checks = append(checks, doctorCheck(
+				actionName,
+				contracts.DoctorCheckPassed,
+				fmt.Sprintf("Allowed on subscription %s", env.GetSubscriptionId())))
+			continue
+		}
+
+		check := doctorCheck(
+			actionName,
+			contracts.DoctorCheckWarning,
+			fmt.Sprintf("Not allowed on subscription %s", env.GetSubscriptionId()))
+		if required.required {
+			check.Status = contracts.DoctorCheckFailed
+		}
+		check.Category = string(internal.ErrorCategoryAuth)
+		check.Code = fmt.Sprintf("%s.AuthorizationFailed", internal.ErrorCategoryAuth)
+		check.Suggestion = "Ask an owner of the subscription to assign you a role allowing this action, " +
+			"ex) Contributor, or Owner to create role assignments."
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+func doctorCheck(name string, status contracts.DoctorCheckStatus, message string) contracts.DoctorCheck {
+	return contracts.DoctorCheck{
+		Name:    name,
+		Status:  status,
+		Message: message,
+	}
+}
+
+// doctorFailedCheck returns a failed check for the error, with its category, code and a suggestion to resolve it.
+func doctorFailedCheck(name string, err error) contracts.DoctorCheck {
+	check := doctorCheck(name, contracts.DoctorCheckFailed, err.Error())
+
+	categorized := cmd.ClassifyError(err)
+	if categorized != nil {
+		check.Category = string(categorized.Category)
+		check.Code = categorized.Code
+	}
+
+	var suggestionErr *internal.ErrorWithSuggestion
+	if errors.As(err, &suggestionErr) {
+		check.Suggestion = suggestionErr.Suggestion
+	} else if categorized != nil {
+		check.Suggestion = categorized.Category.Remediation()
+	}
+
+	return check
+}
+
+func formatDoctorCheck(check contracts.DoctorCheck) string {
+	var status string
+	switch check.Status {
+	case contracts.DoctorCheckPassed:
+		status = output.WithSuccessFormat("(%s) Passed", output.CheckMark())
+	case contracts.DoctorCheckWarning:
+		status = output.WithWarningFormat("(!) Warning")
+	case contracts.DoctorCheckFailed:
+		status = output.WithErrorFormat("(x) Failed")
+	default:
+		status = output.WithGrayFormat("(-) Skipped")
+	}
+
+	message := fmt.Sprintf("%s: %s (%s)", status, check.Name, check.Message)
+	if check.Suggestion != "" {
+		message += fmt.Sprintf("\n  %s", check.Suggestion)
+	}
+
+	return message
+}
+
+func getCmdDoctorHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Checks the connectivity, authentication, tools and permissions required by the current project "+
+			"and environment, and suggests fixes for the checks that fail.",
+		[]string{
+			formatHelpNote("The tools are checked when run in a project with an environment."),
+			formatHelpNote("The permissions are checked on the subscription of the environment."),
+		})
+}
+
+func getCmdDoctorHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run the checks for the current project and environment.": output.WithHighLightFormat("azd doctor"),
+		"Run the checks for the environment named 'dev'.":         output.WithHighLightFormat("azd doctor -e dev"),
+	})
+}
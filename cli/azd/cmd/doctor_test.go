@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DoctorAction_checkConnectivity(t *testing.T) {
+	original := checkHostConnectivity
+	t.Cleanup(func() { checkHostConnectivity = original })
+
+	checkHostConnectivity = func(ctx context.Context, host string) *containerregistry.ConnectivityResult {
+		if host == "management.azure.com" {
+			return &containerregistry.ConnectivityResult{Host: host, DnsErr: &net.DNSError{Name: host, IsNotFound: true}}
+		}
+
+		return &containerregistry.ConnectivityResult{Host: host, Addresses: []string{"20.190.151.68"}}
+	}
+
+	action := &doctorAction{cloud: cloud.AzurePublic()}
+	checks := action.checkConnectivity(context.Background())
+	require.Len(t, checks, 2)
+
+	require.Equal(t, "Connectivity to login.microsoftonline.com", checks[0].Name)
+	require.Equal(t, contracts.DoctorCheckPassed, checks[0].Status)
+
+	require.Equal(t, "Connectivity to management.azure.com", checks[1].Name)
+	require.Equal(t, contracts.DoctorCheckFailed, checks[1].Status)
+	require.Equal(t, "network", checks[1].Category)
+	require.Equal(t, "network.dns", checks[1].Code)
+	require.Contains(t, checks[1].Suggestion, "reach management.azure.com on port 443")
+}
+
+func Test_doctorFailedCheck(t *testing.T) {
+	check := doctorFailedCheck("Authentication", fmt.Errorf("getting credential: %w", auth.ErrNoCurrentUser))
+	require.Equal(t, contracts.DoctorCheck{
+		Name:       "Authentication",
+		Status:     contracts.DoctorCheckFailed,
+		Message:    "getting credential: not logged in, run `azd auth login` to login",
+		Category:   "auth",
+		Code:       "auth.notLoggedIn",
+		Suggestion: internal.ErrorCategoryAuth.Remediation(),
+	}, check)
+
+	check = doctorFailedCheck("Tools", errors.New("unexpected"))
+	require.Empty(t, check.Category)
+	require.Empty(t, check.Suggestion)
+}
+
+func Test_formatDoctorCheck(t *testing.T) {
+	message := formatDoctorCheck(contracts.DoctorCheck{
+		Name:       "Tool Docker",
+		Status:     contracts.DoctorCheckFailed,
+		Message:    "Docker is not installed",
+		Suggestion: "Install Docker",
+	})
+	require.Contains(t, message, "Failed")
+	require.Contains(t, message, "Tool Docker (Docker is not installed)")
+	require.Contains(t, message, "\n  Install Docker")
+}
@@ -5,7 +5,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -22,10 +24,22 @@ import (
 	"github.com/spf13/pflag"
 )
 
+var downConfirmPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "DOWN_CONFIRM",
+	Description: "Whether to delete the resources listed in the plan, without --force: true or false.",
+	Commands:    []string{"down"},
+})
+
 type downFlags struct {
-	forceDelete bool
-	purgeDelete bool
-	global      *internal.GlobalCommandOptions
+	forceDelete  bool
+	purgeDelete  bool
+	services     []string
+	resources    []string
+	timeout      time.Duration
+	pollInterval time.Duration
+	user         string
+	unlock       bool
+	global       *internal.GlobalCommandOptions
 	internal.EnvFlag
 }
 
@@ -38,6 +52,39 @@ func (i *downFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		//nolint:lll
 		"Does not require confirmation before it permanently deletes resources that are soft-deleted by default (for example, key vaults).",
 	)
+	local.StringArrayVar(
+		&i.services,
+		"service",
+		nil,
+		"Deletes only the resources tagged with the specified service name. Can be used multiple times.",
+	)
+	local.StringArrayVar(
+		&i.resources,
+		"resource",
+		nil,
+		"Deletes only the resource with the specified name. Can be used multiple times.",
+	)
+	local.DurationVar(
+		&i.timeout,
+		"timeout",
+		0,
+		"(Dev Center only) The maximum time to wait for the environment to be deleted, for example 30m.")
+	local.DurationVar(
+		&i.pollInterval,
+		"poll-interval",
+		0,
+		"(Dev Center only) The time between the checks of the status of the environment while it is deleted.")
+	local.StringVar(
+		&i.user,
+		"user",
+		"",
+		"(Dev Center only) The object ID of the user owning the environment, for project admins deleting the "+
+			"environment of another user.")
+	local.BoolVar(
+		&i.unlock,
+		"unlock",
+		false,
+		"Deletes the resources of a locked or protected environment, after typing its name to confirm.")
 	i.EnvFlag.Bind(local, global)
 	i.global = global
 }
@@ -64,6 +111,8 @@ type downAction struct {
 	console             input.Console
 	projectConfig       *project.ProjectConfig
 	alphaFeatureManager *alpha.FeatureManager
+	resourceManager     project.ResourceManager
+	resourceService     *azapi.ResourceService
 }
 
 func newDownAction(
@@ -74,6 +123,8 @@ func newDownAction(
 	console input.Console,
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	resourceManager project.ResourceManager,
+	resourceService *azapi.ResourceService,
 ) actions.Action {
 	return &downAction{
 		flags:               flags,
@@ -83,10 +134,26 @@ func newDownAction(
 		projectConfig:       projectConfig,
 		importManager:       importManager,
 		alphaFeatureManager: alphaFeatureManager,
+		resourceManager:     resourceManager,
+		resourceService:     resourceService,
 	}
 }
 
 func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := ensureUnprotected(ctx, a.console, a.projectConfig, a.env, a.flags.unlock); err != nil {
+		return nil, err
+	}
+
+	existingResourceIds := project.ExistingResourceIds(a.projectConfig.Resources, a.env.Getenv)
+	filter := project.PartialDestroyFilter{
+		Services:  a.flags.services,
+		Resources: a.flags.resources,
+		Existing:  existingResourceIds,
+	}
+	if !filter.IsEmpty() {
+		return a.runPartial(ctx, filter)
+	}
+
 	// Command title
 	a.console.MessageUxItem(ctx, &ux.MessageTitle{
 		Title:     "Deleting all resources and deployed code on Azure (azd down)",
@@ -101,7 +168,12 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 	defer func() { _ = infra.Cleanup() }()
 
-	if err := a.provisionManager.Initialize(ctx, a.projectConfig.Path, infra.Options); err != nil {
+	infraOptions := infra.Options
+	infraOptions.Timeout = a.flags.timeout
+	infraOptions.PollInterval = a.flags.pollInterval
+	infraOptions.User = a.flags.user
+
+	if err := a.provisionManager.Initialize(ctx, a.projectConfig.Path, infraOptions); err != nil {
 		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
 	}
 
@@ -109,7 +181,8 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		a.console.WarnForFeature(ctx, azapi.FeatureDeploymentStacks)
 	}
 
-	destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete)
+	destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete).
+		WithExistingResourceIds(existingResourceIds)
 	if _, err := a.provisionManager.Destroy(ctx, destroyOptions); err != nil {
 		return nil, fmt.Errorf("deleting infrastructure: %w", err)
 	}
@@ -121,6 +194,133 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}, nil
 }
 
+// runPartial deletes only the resources selected by the filter, leaving the rest of the environment in place.
+func (a *downAction) runPartial(
+	ctx context.Context,
+	filter project.PartialDestroyFilter,
+) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     "Deleting selected resources on Azure (azd down)",
+		TitleNote: "Resources not selected by --service or --resource are not deleted.",
+	})
+
+	startTime := time.Now()
+	subscriptionId := a.env.GetSubscriptionId()
+
+	resourceGroups := map[string]struct{}{}
+	tagged := []string{}
+	for _, serviceName := range filter.Services {
+		svc, has := a.projectConfig.Services[serviceName]
+		if !has {
+			return nil, fmt.Errorf("service '%s' is not defined in azure.yaml", serviceName)
+		}
+
+		// Services with an explicit resourceName are matched by name instead of by tag.
+		resourceName, err := svc.ResourceName.Envsubst(a.env.Getenv)
+		if err != nil {
+			return nil, err
+		}
+		if resourceName != "" {
+			filter.Resources = append(filter.Resources, resourceName)
+		} else {
+			tagged = append(tagged, serviceName)
+		}
+
+		resourceGroupName, err := a.resourceManager.GetResourceGroupName(ctx, subscriptionId, svc.ResourceGroupTemplate())
+		if err != nil {
+			return nil, err
+		}
+		resourceGroups[resourceGroupName] = struct{}{}
+	}
+
+	// Services selected by name are excluded from the dependency analysis, same as tagged ones.
+	serviceNames := []string{}
+	for _, svc := range a.projectConfig.Services {
+		if slices.Contains(tagged, svc.Name) || !slices.Contains(filter.Services, svc.Name) {
+			serviceNames = append(serviceNames, svc.Name)
+		}
+	}
+	slices.Sort(serviceNames)
+	filter.Services = tagged
+
+	if len(filter.Resources) > 0 || len(resourceGroups) == 0 {
+		resourceGroupName, err := a.resourceManager.GetResourceGroupName(
+			ctx, subscriptionId, a.projectConfig.ResourceGroupName)
+		if err != nil {
+			return nil, err
+		}
+		resourceGroups[resourceGroupName] = struct{}{}
+	}
+
+	a.console.ShowSpinner(ctx, "Discovering resources to delete...", input.Step)
+	allResources := []*azapi.ResourceExtended{}
+	for resourceGroupName := range resourceGroups {
+		resources, err := a.resourceService.ListResourceGroupResources(ctx, subscriptionId, resourceGroupName, nil)
+		if err != nil {
+			a.console.StopSpinner(ctx, "", input.StepFailed)
+			return nil, fmt.Errorf("listing resources in resource group '%s': %w", resourceGroupName, err)
+		}
+		allResources = append(allResources, resources...)
+	}
+
+	plan, err := project.PlanPartialDestroy(filter, allResources, serviceNames)
+	a.console.StopSpinner(ctx, "", input.StepDone)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{"Resource(s) to be deleted:", ""}
+	for _, resource := range plan.Resources {
+		resourceTypeName := azapi.GetResourceTypeDisplayName(azapi.AzureResourceType(resource.Type))
+		if resourceTypeName == "" {
+			resourceTypeName = resource.Type
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s: %s", output.Bullet(), resourceTypeName, resource.Name))
+	}
+	a.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: append(lines, "")})
+
+	for _, warning := range plan.Warnings {
+		a.console.MessageUxItem(ctx, &ux.WarningMessage{Description: warning})
+	}
+
+	if !a.flags.forceDelete {
+		confirmDestroy, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Id: downConfirmPromptId,
+			Message: fmt.Sprintf(
+				"Total resources to %s: %d, are you sure you want to continue?",
+				output.WithErrorFormat("delete"),
+				len(plan.Resources),
+			),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("prompting for delete confirmation: %w", err)
+		}
+
+		if !confirmDestroy {
+			return nil, errors.New("user denied delete confirmation")
+		}
+	}
+
+	// Child resources are listed after their parent, delete them first.
+	for i := len(plan.Resources) - 1; i >= 0; i-- {
+		resource := plan.Resources[i]
+		stepMessage := fmt.Sprintf("Deleting %s", output.WithHighLightFormat(resource.Name))
+		a.console.ShowSpinner(ctx, stepMessage, input.Step)
+		if err := a.resourceService.DeleteResource(ctx, subscriptionId, resource.Id); err != nil {
+			a.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, fmt.Errorf("deleting resource '%s': %w", resource.Name, err)
+		}
+		a.console.StopSpinner(ctx, stepMessage, input.StepDone)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Selected resources were removed from Azure in %s.", ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
 func getCmdDownHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Delete Azure resources for an application. Running %s will not delete application"+
@@ -134,5 +334,7 @@ func getCmdDownHelpFooter(*cobra.Command) string {
 		"Forcibly delete all applications resources without confirmation.": output.WithHighLightFormat("azd down --force"),
 		"Permanently delete resources that are soft-deleted by default," +
 			" without confirmation.": output.WithHighLightFormat("azd down --purge"),
+		"Delete only the resources of a single service.": output.WithHighLightFormat("azd down --service <service>"),
+		"Delete a single resource by name.":              output.WithHighLightFormat("azd down --resource <name>"),
 	})
 }
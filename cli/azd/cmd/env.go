@@ -19,18 +19,23 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
 	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/secretguard"
+	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/joho/godotenv"
 	"github.com/sethvargo/go-retry"
 	"github.com/spf13/cobra"
@@ -81,11 +86,18 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 
 	group.Add("list", &actions.ActionDescriptorOptions{
 		Command:        newEnvListCmd(),
+		FlagsResolver:  newEnvListFlags,
 		ActionResolver: newEnvListAction,
 		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
 		DefaultFormat:  output.TableFormat,
 	})
 
+	group.Add("adopt", &actions.ActionDescriptorOptions{
+		Command:        newEnvAdoptCmd(),
+		FlagsResolver:  newEnvAdoptFlags,
+		ActionResolver: newEnvAdoptAction,
+	})
+
 	group.Add("refresh", &actions.ActionDescriptorOptions{
 		Command:        newEnvRefreshCmd(),
 		FlagsResolver:  newEnvRefreshFlags,
@@ -94,6 +106,18 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		DefaultFormat:  output.NoneFormat,
 	})
 
+	group.Add("delete", &actions.ActionDescriptorOptions{
+		Command:        newEnvDeleteCmd(),
+		FlagsResolver:  newEnvDeleteFlags,
+		ActionResolver: newEnvDeleteAction,
+	})
+
+	group.Add("export-preset", &actions.ActionDescriptorOptions{
+		Command:        newEnvExportPresetCmd(),
+		FlagsResolver:  newEnvExportPresetFlags,
+		ActionResolver: newEnvExportPresetAction,
+	})
+
 	group.Add("get-values", &actions.ActionDescriptorOptions{
 		Command:        newEnvGetValuesCmd(),
 		FlagsResolver:  newEnvGetValuesFlags,
@@ -108,6 +132,56 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvGetValueAction,
 	})
 
+	group.Add("wait", &actions.ActionDescriptorOptions{
+		Command:        newEnvWaitCmd(),
+		FlagsResolver:  newEnvWaitFlags,
+		ActionResolver: newEnvWaitAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("explain", &actions.ActionDescriptorOptions{
+		Command:        newEnvExplainCmd(),
+		FlagsResolver:  newEnvExplainFlags,
+		ActionResolver: newEnvExplainAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("foreach", &actions.ActionDescriptorOptions{
+		Command:        newEnvForeachCmd(),
+		FlagsResolver:  newEnvForeachFlags,
+		ActionResolver: newEnvForeachAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("exec", &actions.ActionDescriptorOptions{
+		Command:        newEnvExecCmd(),
+		FlagsResolver:  newEnvExecFlags,
+		ActionResolver: newEnvExecAction,
+	})
+
+	group.Add("extend", &actions.ActionDescriptorOptions{
+		Command:        newEnvExtendCmd(),
+		FlagsResolver:  newEnvExtendFlags,
+		ActionResolver: newEnvExtendAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("lock", &actions.ActionDescriptorOptions{
+		Command:        newEnvLockCmd(true),
+		FlagsResolver:  newEnvLockFlags,
+		ActionResolver: newEnvLockAction,
+	})
+
+	group.Add("unlock", &actions.ActionDescriptorOptions{
+		Command:        newEnvLockCmd(false),
+		FlagsResolver:  newEnvLockFlags,
+		ActionResolver: newEnvUnlockAction,
+	})
+
 	return group
 }
 
@@ -133,40 +207,50 @@ func newEnvSetCmd() *cobra.Command {
 
 type envSetFlags struct {
 	internal.EnvFlag
-	global *internal.GlobalCommandOptions
-	file   string
+	global  *internal.GlobalCommandOptions
+	file    string
+	preview bool
 }
 
 func (f *envSetFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	f.EnvFlag.Bind(local, global)
 	local.StringVar(&f.file, "file", "", "Path to .env formatted file to load environment values from.")
+	local.BoolVar(
+		&f.preview,
+		"preview",
+		false,
+		"Shows the changes to the environment, with secret values masked, and asks for confirmation before saving.",
+	)
 	f.global = global
 }
 
 type envSetAction struct {
-	console    input.Console
-	azdCtx     *azdcontext.AzdContext
-	env        *environment.Environment
-	envManager environment.Manager
-	flags      *envSetFlags
-	args       []string
+	console     input.Console
+	azdCtx      *azdcontext.AzdContext
+	env         *environment.Environment
+	envManager  environment.Manager
+	secretGuard *secretguard.Guard
+	flags       *envSetFlags
+	args        []string
 }
 
 func newEnvSetAction(
 	azdCtx *azdcontext.AzdContext,
 	env *environment.Environment,
 	envManager environment.Manager,
+	secretGuard *secretguard.Guard,
 	console input.Console,
 	flags *envSetFlags,
 	args []string,
 ) actions.Action {
 	return &envSetAction{
-		console:    console,
-		azdCtx:     azdCtx,
-		env:        env,
-		envManager: envManager,
-		flags:      flags,
-		args:       args,
+		console:     console,
+		azdCtx:      azdCtx,
+		env:         env,
+		envManager:  envManager,
+		secretGuard: secretGuard,
+		flags:       flags,
+		args:        args,
 	}
 }
 
@@ -215,21 +299,37 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("no environment values to set")
 	}
 
+	// Credentials are kept out of .env files tracked by git
+	if err := e.secretGuard.Protect(ctx, e.azdCtx, e.env, keyValues); err != nil {
+		return nil, err
+	}
+
 	// Apply the values
 	for key, value := range keyValues {
 		warnKeyCaseConflicts(ctx, e.console, dotEnv, key)
-		e.env.DotenvSet(key, value)
+		e.env.DotenvSetFrom(key, value, userValueOrigin())
 		// Update to check case conflicts in subsequent keys
 		dotEnv[key] = value
 	}
 
-	if err := e.envManager.Save(ctx, e.env); err != nil {
+	err := e.envManager.SaveWithOptions(ctx, e.env, &environment.SaveOptions{ConfirmChanges: e.flags.preview})
+	if err != nil {
 		return nil, fmt.Errorf("saving environment: %w", err)
 	}
 
 	return nil, nil
 }
 
+// userValueOrigin returns the origin of values set by the user, which are attributed to the running hook when azd is
+// invoked from a hook script.
+func userValueOrigin() environment.ValueOrigin {
+	if hookName := os.Getenv(environment.HookNameEnvVarName); hookName != "" {
+		return environment.ValueOrigin{Source: environment.ValueSourceHook, Detail: hookName}
+	}
+
+	return environment.ValueOrigin{Source: environment.ValueSourceUser}
+}
+
 // parseKeyValue parses a key=value string and returns the key and value parts
 func parseKeyValue(arg string) (string, string, error) {
 	parts := strings.SplitN(arg, "=", 2)
@@ -392,7 +492,7 @@ func (e *envSetSecretAction) Run(ctx context.Context) (*actions.ActionResult, er
 			}
 
 			envValue := keyvault.NewAzureKeyVaultSecret(kvSubId, kvAccount.Name, kvSecretName)
-			e.env.DotenvSet(secretName, envValue)
+			e.env.DotenvSetFrom(secretName, envValue, userValueOrigin())
 			if err := e.envManager.Save(ctx, e.env); err != nil {
 				return nil, fmt.Errorf("saving environment: %w", err)
 			}
@@ -574,7 +674,7 @@ func (e *envSetSecretAction) Run(ctx context.Context) (*actions.ActionResult, er
 
 	// akvs -> Azure Key Vault Secret (akvs://<subId>/<keyvault-name>/<secret-name>)
 	envValue := keyvault.NewAzureKeyVaultSecret(subId, kvAccount.Name, kvSecretName)
-	e.env.DotenvSet(secretName, envValue)
+	e.env.DotenvSetFrom(secretName, envValue, userValueOrigin())
 	if err := e.envManager.Save(ctx, e.env); err != nil {
 		return nil, fmt.Errorf("saving environment: %w", err)
 	}
@@ -744,11 +844,44 @@ func newEnvListCmd() *cobra.Command {
 	}
 }
 
+type envListFlags struct {
+	remote   string
+	allUsers bool
+	global   *internal.GlobalCommandOptions
+}
+
+func (f *envListFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.remote,
+		"remote",
+		"",
+		"Lists the environments of the remote backend instead, including the ones without a local environment. "+
+			"Supported values: devcenter.",
+	)
+	local.BoolVar(
+		&f.allUsers,
+		"all-users",
+		false,
+		"(Dev Center only) Lists the remote environments of all the users of the projects you are an admin of, "+
+			"instead of only yours.",
+	)
+	f.global = global
+}
+
+func newEnvListFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envListFlags {
+	flags := &envListFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
 type envListAction struct {
-	envManager environment.Manager
-	azdCtx     *azdcontext.AzdContext
-	formatter  output.Formatter
-	writer     io.Writer
+	envManager     environment.Manager
+	azdCtx         *azdcontext.AzdContext
+	formatter      output.Formatter
+	writer         io.Writer
+	flags          *envListFlags
+	serviceLocator ioc.ServiceLocator
 }
 
 func newEnvListAction(
@@ -756,16 +889,28 @@ func newEnvListAction(
 	azdCtx *azdcontext.AzdContext,
 	formatter output.Formatter,
 	writer io.Writer,
+	flags *envListFlags,
+	serviceLocator ioc.ServiceLocator,
 ) actions.Action {
 	return &envListAction{
-		envManager: envManager,
-		azdCtx:     azdCtx,
-		formatter:  formatter,
-		writer:     writer,
+		envManager:     envManager,
+		azdCtx:         azdCtx,
+		formatter:      formatter,
+		writer:         writer,
+		flags:          flags,
+		serviceLocator: serviceLocator,
 	}
 }
 
 func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if e.flags.remote != "" {
+		return e.runRemote(ctx)
+	}
+
+	if e.flags.allUsers {
+		return nil, errors.New("--all-users is only supported with --remote")
+	}
+
 	envs, err := e.envManager.List(ctx)
 
 	if err != nil {
@@ -805,6 +950,192 @@ func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	return nil, nil
 }
 
+// runRemote lists the environments of the remote backend selected with --remote.
+func (e *envListAction) runRemote(ctx context.Context) (*actions.ActionResult, error) {
+	remoteEnvironments, err := resolveRemoteEnvironments(e.serviceLocator, e.flags.remote)
+	if err != nil {
+		return nil, err
+	}
+
+	envs, err := remoteEnvironments.List(ctx, e.flags.allUsers)
+	if err != nil {
+		return nil, fmt.Errorf("listing remote environments: %w", err)
+	}
+
+	if e.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "NAME",
+				ValueTemplate: "{{.Name}}",
+			},
+			{
+				Heading:       "PROJECT",
+				ValueTemplate: "{{.Project}}",
+			},
+			{
+				Heading:       "TYPE",
+				ValueTemplate: "{{.EnvironmentType}}",
+			},
+			{
+				Heading:       "DEFINITION",
+				ValueTemplate: "{{.EnvironmentDefinition}}",
+			},
+			{
+				Heading:       "STATE",
+				ValueTemplate: "{{.ProvisioningState}}",
+			},
+			{
+				Heading:       "LOCAL",
+				ValueTemplate: "{{.LocalName}}",
+			},
+		}
+
+		// The environments of other users are listed with their owner
+		if e.flags.allUsers {
+			columns = slices.Insert(columns, 2, output.Column{
+				Heading:       "USER",
+				ValueTemplate: "{{.User}}",
+			})
+		}
+
+		err = e.formatter.Format(envs, e.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = e.formatter.Format(envs, e.writer, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if e.formatter.Kind() == output.TableFormat &&
+		slices.ContainsFunc(envs, func(env *devcenter.RemoteEnvironment) bool { return env.LocalName == "" }) {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				FollowUp: fmt.Sprintf(
+					"To create a local environment for a remote environment without one, run %s.",
+					output.WithHighLightFormat("azd env adopt <name>"),
+				),
+			},
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// resolveRemoteEnvironments resolves the environments of the remote backend. Only devcenter is supported.
+func resolveRemoteEnvironments(
+	serviceLocator ioc.ServiceLocator,
+	remote string,
+) (*devcenter.RemoteEnvironments, error) {
+	if remote != string(devcenter.RemoteKindDevCenter) {
+		return nil, fmt.Errorf(
+			"remote '%s' is not supported. Supported values: %s", remote, devcenter.RemoteKindDevCenter)
+	}
+
+	var remoteEnvironments *devcenter.RemoteEnvironments
+	if err := serviceLocator.Resolve(&remoteEnvironments); err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("the devcenter platform is not enabled: %w", err),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: Enable the devcenter platform with %s.",
+				output.WithHighLightFormat("azd config set platform.type devcenter"),
+			),
+		}
+	}
+
+	return remoteEnvironments, nil
+}
+
+func newEnvAdoptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "adopt <environment>",
+		Short: "Create a local environment from a remote environment.",
+		Long: "Create a local environment from a remote environment, which doesn't have a local environment yet. " +
+			"The configuration and the outputs of the remote environment are copied to the local environment.\n\n" +
+			"Only devcenter environments are supported, list them with 'azd env list --remote devcenter'.",
+		Args: cobra.ExactArgs(1),
+	}
+}
+
+type envAdoptFlags struct {
+	project string
+	global  *internal.GlobalCommandOptions
+}
+
+func (f *envAdoptFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.project,
+		"project",
+		"",
+		"The devcenter project of the environment, required when environments with the same name exist in several "+
+			"projects.",
+	)
+	f.global = global
+}
+
+func newEnvAdoptFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envAdoptFlags {
+	flags := &envAdoptFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type envAdoptAction struct {
+	azdCtx         *azdcontext.AzdContext
+	console        input.Console
+	flags          *envAdoptFlags
+	args           []string
+	serviceLocator ioc.ServiceLocator
+}
+
+func newEnvAdoptAction(
+	azdCtx *azdcontext.AzdContext,
+	console input.Console,
+	flags *envAdoptFlags,
+	args []string,
+	serviceLocator ioc.ServiceLocator,
+) actions.Action {
+	return &envAdoptAction{
+		azdCtx:         azdCtx,
+		console:        console,
+		flags:          flags,
+		args:           args,
+		serviceLocator: serviceLocator,
+	}
+}
+
+func (e *envAdoptAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	remoteEnvironments, err := resolveRemoteEnvironments(e.serviceLocator, string(devcenter.RemoteKindDevCenter))
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := remoteEnvironments.Adopt(ctx, e.flags.project, e.args[0])
+	if err != nil {
+		return nil, fmt.Errorf("adopting environment: %w", err)
+	}
+
+	// The adopted environment becomes the default when there's no default environment yet
+	defaultEnvironment, err := e.azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return nil, fmt.Errorf("get default environment: %w", err)
+	}
+
+	if defaultEnvironment == "" {
+		if err := e.azdCtx.SetProjectState(azdcontext.ProjectState{DefaultEnvironment: env.Name()}); err != nil {
+			return nil, fmt.Errorf("saving default environment: %w", err)
+		}
+		e.console.Message(ctx, fmt.Sprintf("Environment '%s' was set as default", env.Name()))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Created local environment '%s' from the remote environment.", env.Name()),
+		},
+	}, nil
+}
+
 type envNewFlags struct {
 	subscription string
 	location     string
@@ -1111,6 +1442,352 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 	}, nil
 }
 
+type envDeleteFlags struct {
+	force       bool
+	purgeAzure  bool
+	purgeDelete bool
+	unlock      bool
+	global      *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (ed *envDeleteFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&ed.force, "force", false, "Does not require confirmation before it deletes the environment.")
+	local.BoolVar(
+		&ed.purgeAzure,
+		"purge-azure",
+		false,
+		"Deletes the Azure resources provisioned for the environment before deleting the environment.",
+	)
+	local.BoolVar(
+		&ed.purgeDelete,
+		"purge",
+		false,
+		//nolint:lll
+		"Permanently deletes Azure resources that are soft-deleted by default (for example, key vaults). Requires --purge-azure.",
+	)
+	local.BoolVar(
+		&ed.unlock,
+		"unlock",
+		false,
+		"Deletes a locked or protected environment, after typing its name to confirm.",
+	)
+
+	ed.EnvFlag.Bind(local, global)
+	ed.global = global
+}
+
+func newEnvDeleteFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envDeleteFlags {
+	flags := &envDeleteFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <environment>",
+		Short: "Delete an environment and, optionally, its Azure resources.",
+		Long: "Delete the local and remote state of an environment.\n\n" +
+			"When --purge-azure is set, the Azure resources provisioned for the environment are deleted first. " +
+			"The environment is only deleted once the Azure resources are deleted.",
+		// Like `azd env refresh`, the environment can be passed either as an argument or with -e / --environment.
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				return nil
+			}
+
+			if flagValue, err := cmd.Flags().GetString(internal.EnvironmentNameFlagName); err == nil {
+				if flagValue != "" && args[0] != flagValue {
+					return errors.New(
+						"the --environment flag and an explicit environment name as an argument may not be used together")
+				}
+			}
+
+			return cmd.Flags().Set(internal.EnvironmentNameFlagName, args[0])
+		},
+		Annotations: map[string]string{},
+	}
+
+	cmd.Annotations["azdtest.use"] = "delete"
+	return cmd
+}
+
+type envDeleteAction struct {
+	provisionManager *provisioning.Manager
+	importManager    *project.ImportManager
+	projectConfig    *project.ProjectConfig
+	resourceService  *azapi.ResourceService
+	remoteConfig     *state.RemoteConfig
+	env              *environment.Environment
+	envManager       environment.Manager
+	flags            *envDeleteFlags
+	console          input.Console
+}
+
+func newEnvDeleteAction(
+	provisionManager *provisioning.Manager,
+	importManager *project.ImportManager,
+	projectConfig *project.ProjectConfig,
+	resourceService *azapi.ResourceService,
+	remoteConfig *state.RemoteConfig,
+	env *environment.Environment,
+	envManager environment.Manager,
+	flags *envDeleteFlags,
+	console input.Console,
+) actions.Action {
+	return &envDeleteAction{
+		provisionManager: provisionManager,
+		importManager:    importManager,
+		projectConfig:    projectConfig,
+		resourceService:  resourceService,
+		remoteConfig:     remoteConfig,
+		env:              env,
+		envManager:       envManager,
+		flags:            flags,
+		console:          console,
+	}
+}
+
+func (ed *envDeleteAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if ed.flags.purgeDelete && !ed.flags.purgeAzure {
+		return nil, errors.New("--purge can only be used with --purge-azure")
+	}
+
+	if err := ensureUnprotected(ctx, ed.console, ed.projectConfig, ed.env, ed.flags.unlock); err != nil {
+		return nil, err
+	}
+
+	ed.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: fmt.Sprintf("Deleting environment %s (azd env delete)", ed.env.Name()),
+	})
+
+	// The resource groups tagged with the environment are listed in the confirmation, and checked again once the Azure
+	// resources are deleted
+	var resourceGroups []*azapi.Resource
+	if ed.flags.purgeAzure {
+		var err error
+		resourceGroups, err = ed.environmentResourceGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !ed.flags.force {
+		confirmed, err := ed.confirm(ctx, resourceGroups)
+		if err != nil {
+			return nil, err
+		}
+
+		if !confirmed {
+			return nil, errors.New("environment delete cancelled")
+		}
+	}
+
+	if ed.flags.purgeAzure {
+		if err := ed.destroyInfrastructure(ctx, len(resourceGroups) > 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// Unlike other commands removing the local copy of an environment, the remote state is deleted too
+	err := ed.envManager.DeleteWithOptions(ctx, ed.env.Name(), &environment.DeleteOptions{IncludeRemote: true})
+	if err != nil {
+		return nil, fmt.Errorf("deleting environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment %s was deleted.", ed.env.Name()),
+		},
+	}, nil
+}
+
+// confirm lists what is deleted, the Azure resources when --purge-azure is set and the state of the environment, and
+// asks the user to confirm.
+func (ed *envDeleteAction) confirm(ctx context.Context, resourceGroups []*azapi.Resource) (bool, error) {
+	ed.console.Message(ctx, output.WithWarningFormat("WARNING: This will delete the following:\n"))
+
+	if ed.flags.purgeAzure {
+		ed.console.Message(ctx, "Azure resources:")
+		if len(resourceGroups) == 0 {
+			ed.console.Message(ctx, "  All resources provisioned for the environment by azd")
+		}
+		for _, group := range resourceGroups {
+			ed.console.Message(ctx, fmt.Sprintf("  Resource group %s", output.WithHighLightFormat(group.Name)))
+		}
+		ed.console.Message(ctx, "")
+	}
+
+	ed.console.Message(ctx, "Environment state:")
+	envPath := ed.envManager.EnvPath(ed.env)
+	ed.console.Message(ctx, fmt.Sprintf("  Local environment %s", output.WithHighLightFormat(envPath)))
+	if ed.remoteConfig != nil {
+		ed.console.Message(ctx, fmt.Sprintf(
+			"  Remote environment state (%s backend)", output.WithHighLightFormat(ed.remoteConfig.Backend)))
+	}
+	ed.console.Message(ctx, "")
+
+	return ed.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Are you sure you want to continue?",
+		DefaultValue: false,
+	})
+}
+
+// destroyInfrastructure deletes the Azure resources of the environment with its provision provider, then verifies no
+// resource group tagged with the environment remains, so that the state of the environment isn't lost while Azure
+// resources still exist.
+func (ed *envDeleteAction) destroyInfrastructure(ctx context.Context, verify bool) error {
+	infra, err := ed.importManager.ProjectInfrastructure(ctx, ed.projectConfig)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	if err := ed.provisionManager.Initialize(ctx, ed.projectConfig.Path, infra.Options); err != nil {
+		return fmt.Errorf("initializing provisioning manager: %w", err)
+	}
+
+	// The deletion was already confirmed, so the provider doesn't ask again
+	destroyOptions := provisioning.NewDestroyOptions(true, ed.flags.purgeDelete).
+		WithExistingResourceIds(project.ExistingResourceIds(ed.projectConfig.Resources, ed.env.Getenv))
+	if _, err := ed.provisionManager.Destroy(ctx, destroyOptions); err != nil {
+		return fmt.Errorf("deleting infrastructure: %w", err)
+	}
+
+	if !verify {
+		return nil
+	}
+
+	remaining, err := ed.environmentResourceGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("verifying infrastructure deletion: %w", err)
+	}
+
+	if len(remaining) > 0 {
+		names := make([]string, len(remaining))
+		for i, group := range remaining {
+			names[i] = group.Name
+		}
+
+		return fmt.Errorf(
+			"the environment was not deleted since resource groups %s still exist, run the command again once "+
+				"they are deleted",
+			strings.Join(names, ", "),
+		)
+	}
+
+	return nil
+}
+
+// environmentResourceGroups lists the resource groups tagged with the name of the environment.
+func (ed *envDeleteAction) environmentResourceGroups(ctx context.Context) ([]*azapi.Resource, error) {
+	subscriptionId := ed.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		return nil, nil
+	}
+
+	groups, err := ed.resourceService.ListResourceGroup(ctx, subscriptionId, &azapi.ListResourceGroupOptions{
+		TagFilter: &azapi.Filter{Key: azure.TagKeyAzdEnvName, Value: ed.env.Name()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing resource groups of environment '%s': %w", ed.env.Name(), err)
+	}
+
+	return groups, nil
+}
+
+type envExportPresetFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (ep *envExportPresetFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	ep.EnvFlag.Bind(local, global)
+	ep.global = global
+}
+
+func newEnvExportPresetFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envExportPresetFlags {
+	flags := &envExportPresetFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvExportPresetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-preset <name>",
+		Short: "Export the parameter values of the environment as a preset.",
+		Long: "Export the infrastructure parameter values of the environment as a named preset, stored in the " +
+			"presets folder of the infra folder of the project.\n\n" +
+			"Presets can be checked in to share standard configurations, and used with 'azd provision --preset <name>'.",
+		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			"azdtest.use": "export-preset small",
+		},
+	}
+}
+
+type envExportPresetAction struct {
+	env             *environment.Environment
+	projectConfig   *project.ProjectConfig
+	defaultProvider provisioning.DefaultProviderResolver
+	console         input.Console
+	args            []string
+}
+
+func newEnvExportPresetAction(
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	defaultProvider provisioning.DefaultProviderResolver,
+	console input.Console,
+	args []string,
+) actions.Action {
+	return &envExportPresetAction{
+		env:             env,
+		projectConfig:   projectConfig,
+		defaultProvider: defaultProvider,
+		console:         console,
+		args:            args,
+	}
+}
+
+func (ep *envExportPresetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := ep.args[0]
+
+	kind, err := provisioning.ResolveProviderKind(ep.projectConfig.Infra, ep.defaultProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	preset, err := provisioning.NewPresetFromEnvironment(ep.env, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	presetPath, err := provisioning.SavePreset(ep.projectConfig.Path, ep.projectConfig.Infra, name, preset)
+	if err != nil {
+		return nil, err
+	}
+
+	ep.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: "Parameter values are stored in plain text, review the preset before checking it in.",
+	})
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Preset %s exported to %s", name, output.WithHyperlink(presetPath, presetPath)),
+			FollowUp: fmt.Sprintf(
+				"Provision an environment with the preset by running %s",
+				output.WithHighLightFormat("azd provision --preset %s", name)),
+		},
+	}, nil
+}
+
 func newEnvGetValuesFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envGetValuesFlags {
 	flags := &envGetValuesFlags{}
 	flags.Bind(cmd.Flags(), global)
@@ -1284,6 +1961,123 @@ func (eg *envGetValueAction) Run(ctx context.Context) (*actions.ActionResult, er
 	return nil, nil
 }
 
+func newEnvExplainFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envExplainFlags {
+	flags := &envExplainFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvExplainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain <keyName>",
+		Short: "Show where an environment value came from and how it changed over time.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type envExplainFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envExplainFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+// envExplanation is the result of 'azd env explain'.
+type envExplanation struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Exists bool   `json:"exists"`
+	// Set when the value was changed without being recorded (ex: the .env file was edited)
+	ChangedOutsideAzd bool                          `json:"changedOutsideAzd"`
+	History           []environment.ProvenanceEntry `json:"history"`
+}
+
+type envExplainAction struct {
+	env       *environment.Environment
+	console   input.Console
+	formatter output.Formatter
+	writer    io.Writer
+	args      []string
+}
+
+func newEnvExplainAction(
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &envExplainAction{
+		env:       env,
+		console:   console,
+		formatter: formatter,
+		writer:    writer,
+		args:      args,
+	}
+}
+
+func (e *envExplainAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	key := e.args[0]
+	value, exists := e.env.Dotenv()[key]
+	history := e.env.Provenance(key)
+
+	if !exists && len(history) == 0 {
+		return nil, fmt.Errorf("key '%s' not found in the environment values", key)
+	}
+
+	explanation := envExplanation{
+		Key:     key,
+		Value:   value,
+		Exists:  exists,
+		History: history,
+	}
+	if explanation.History == nil {
+		explanation.History = []environment.ProvenanceEntry{}
+	}
+	if exists && len(history) > 0 {
+		explanation.ChangedOutsideAzd = !history[len(history)-1].Matches(value)
+	}
+
+	if e.formatter.Kind() == output.JsonFormat {
+		return nil, e.formatter.Format(explanation, e.writer, nil)
+	}
+
+	lines := []string{}
+	if exists {
+		lines = append(lines, fmt.Sprintf("%s=%s", output.WithHighLightFormat(key), value))
+	} else {
+		lines = append(lines, fmt.Sprintf("%s is no longer set in environment %s.",
+			output.WithHighLightFormat(key), e.env.Name()))
+	}
+
+	switch {
+	case len(history) == 0:
+		lines = append(lines, "Source: unknown. The value was set before azd recorded the source of values, "+
+			"or by a command which does not record it.")
+	case explanation.ChangedOutsideAzd:
+		lines = append(lines, "Source: changed outside of azd (ex: the .env file was edited).")
+	default:
+		latest := history[len(history)-1]
+		lines = append(lines, fmt.Sprintf("Source: %s, on %s",
+			latest.ValueOrigin, latest.Timestamp.Local().Format("2006-01-02 15:04:05")))
+	}
+
+	if len(history) > 0 {
+		lines = append(lines, "", "History (most recent first):")
+		for _, entry := range slices.Backward(history) {
+			lines = append(lines, fmt.Sprintf("  %s  %-20s %s",
+				entry.Timestamp.Local().Format("2006-01-02 15:04:05"), entry.ValueOrigin, entry.Fingerprint))
+		}
+	}
+
+	e.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: lines})
+	return nil, nil
+}
+
 func getCmdEnvHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		"Manage your application environments. With this command group, you can create a new environment or get, set,"+
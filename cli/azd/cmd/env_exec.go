@@ -0,0 +1,281 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type envExecFlags struct {
+	watch       bool
+	interval    time.Duration
+	dotenvFiles []string
+	global      *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *envExecFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.watch,
+		"watch",
+		false,
+		"Restarts the process when the environment values change, ex) after 'azd provision' runs in another terminal. "+
+			"The process doesn't read the console input.")
+	local.DurationVar(&f.interval, "interval", 2*time.Second, "The time between the checks of the environment values.")
+	local.StringArrayVar(
+		&f.dotenvFiles,
+		"dotenv-file",
+		nil,
+		"Also writes the environment values to the dotenv file, ex) .env.local, for the frameworks which read them from "+
+			"a file. The file contains the values of the secrets, don't commit it. Repeatable.")
+
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvExecFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envExecFlags {
+	flags := &envExecFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvExecCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "exec [flags] -- <command> [args...]",
+		Short: "Run a process with the environment values set as environment variables.",
+		Long: "Run a process with the environment values set as environment variables, ex) to run an app locally " +
+			"against the resources provisioned in Azure.\n\n" +
+			"The Key Vault references of the environment are resolved to the values of the secrets. With --dotenv-file, " +
+			"the values are also written to dotenv files before the process starts. With --watch, the process is " +
+			"restarted when the environment values change, ex) when 'azd provision' runs in another terminal.",
+		Example: `$ azd env exec -- npm run dev
+$ azd env exec --watch -- dotnet run --project src/api
+$ azd env exec --watch --dotenv-file .env.local -- python app.py`,
+		Args: cobra.MinimumNArgs(1),
+	}
+}
+
+// envExecStopGracePeriod is how long a watched process has to exit once interrupted, before its process tree is killed.
+const envExecStopGracePeriod = 10 * time.Second
+
+type envExecAction struct {
+	env            *environment.Environment
+	envManager     environment.Manager
+	commandRunner  exec.CommandRunner
+	console        input.Console
+	serviceLocator ioc.ServiceLocator
+	flags          *envExecFlags
+	args           []string
+}
+
+func newEnvExecAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	serviceLocator ioc.ServiceLocator,
+	flags *envExecFlags,
+	args []string,
+) actions.Action {
+	return &envExecAction{
+		env:            env,
+		envManager:     envManager,
+		commandRunner:  commandRunner,
+		console:        console,
+		serviceLocator: serviceLocator,
+		flags:          flags,
+		args:           args,
+	}
+}
+
+func (a *envExecAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.interval <= 0 {
+		return nil, errors.New("--interval must be positive")
+	}
+
+	if !a.flags.watch {
+		environ, err := a.prepare(ctx, a.env.Dotenv())
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, a.exec(ctx, environ, false)
+	}
+
+	return nil, a.watch(ctx)
+}
+
+// watch runs the process until the command is cancelled, restarting it each time the environment values change. The
+// process exiting doesn't stop the command, it's started again with the next change.
+func (a *envExecAction) watch(ctx context.Context) error {
+	values := a.env.Dotenv()
+	for {
+		environ, err := a.prepare(ctx, values)
+		if err != nil {
+			return err
+		}
+
+		processCtx, cancel := context.WithCancel(ctx)
+		exited := make(chan error, 1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			exited <- a.exec(processCtx, environ, true)
+		}()
+
+		values, err = a.waitForChanges(ctx, values, exited)
+		cancel()
+		<-done
+
+		if err != nil {
+			return err
+		}
+
+		// The command is cancelled, ex) with Ctrl+C
+		if values == nil {
+			return nil
+		}
+
+		a.console.Message(ctx, output.WithWarningFormat("\nThe environment values changed, restarting the process.\n"))
+	}
+}
+
+// waitForChanges reloads the environment at each interval and returns its values when they differ from the values the
+// process runs with, or nil when the command is cancelled.
+func (a *envExecAction) waitForChanges(
+	ctx context.Context,
+	current map[string]string,
+	exited <-chan error,
+) (map[string]string, error) {
+	ticker := time.NewTicker(a.flags.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case err := <-exited:
+			message := "\nThe process exited, it restarts when the environment values change.\n"
+			if err != nil {
+				message = fmt.Sprintf("\nThe process exited with an error: %s\nIt restarts when the environment values "+
+					"change.\n", err)
+			}
+			a.console.Message(ctx, output.WithGrayFormat(message))
+			// A nil channel is never selected, the process isn't waited for again
+			exited = nil
+		case <-ticker.C:
+			if err := a.envManager.Reload(ctx, a.env); err != nil {
+				return nil, fmt.Errorf("reloading environment: %w", err)
+			}
+
+			if values := a.env.Dotenv(); !maps.Equal(values, current) {
+				return values, nil
+			}
+		}
+	}
+}
+
+// prepare resolves the Key Vault references of the values and writes them to the dotenv files, returning the
+// environment variables of the process.
+func (a *envExecAction) prepare(ctx context.Context, values map[string]string) ([]string, error) {
+	values, err := a.resolveSecrets(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(a.flags.dotenvFiles) > 0 {
+		contents, err := environment.MarshalDotenv(values)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dotenvFile := range a.flags.dotenvFiles {
+			if err := os.MkdirAll(filepath.Dir(dotenvFile), osutil.PermissionDirectory); err != nil {
+				return nil, fmt.Errorf("creating directory of %s: %w", dotenvFile, err)
+			}
+
+			if err := os.WriteFile(dotenvFile, []byte(contents+"\n"), osutil.PermissionFileOwnerOnly); err != nil {
+				return nil, fmt.Errorf("writing %s: %w", dotenvFile, err)
+			}
+		}
+	}
+
+	return envExecEnviron(values), nil
+}
+
+// resolveSecrets replaces the Key Vault references, ex) akvs://<subscription>/<vault>/<secret>, with the values of the
+// secrets. The Key Vault service is only resolved when the environment has references.
+func (a *envExecAction) resolveSecrets(ctx context.Context, values map[string]string) (map[string]string, error) {
+	references := []string{}
+	for key, value := range values {
+		if keyvault.IsAzureKeyVaultSecret(value) {
+			references = append(references, key)
+		}
+	}
+
+	if len(references) == 0 {
+		return values, nil
+	}
+
+	resolved := maps.Clone(values)
+	err := a.serviceLocator.Invoke(func(keyvaultService keyvault.KeyVaultService) error {
+		for _, key := range references {
+			secret, err := keyvaultService.SecretFromAkvs(ctx, values[key])
+			if err != nil {
+				return fmt.Errorf("resolving the Key Vault secret of %s: %w", key, err)
+			}
+			resolved[key] = secret
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// exec runs the process attached to the console, with the environment variables added to the ones of azd. A watched
+// process runs in its own process group, which is interrupted then killed when the process is restarted or the command
+// is cancelled, so the children of the process, ex) the server started by 'npm run dev', are stopped too.
+func (a *envExecAction) exec(ctx context.Context, environ []string, watched bool) error {
+	runArgs := exec.NewRunArgs(a.args[0], a.args[1:]...).
+		WithEnv(environ).
+		WithInteractive(true)
+	if watched {
+		runArgs = runArgs.WithStopGracePeriod(envExecStopGracePeriod)
+	}
+
+	_, err := a.commandRunner.Run(ctx, runArgs)
+	return err
+}
+
+// envExecEnviron returns the values as KEY=VALUE environment variables
+func envExecEnviron(values map[string]string) []string {
+	environ := []string{}
+	for key, value := range values {
+		environ = append(environ, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return environ
+}
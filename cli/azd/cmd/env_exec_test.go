@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvExec(t *testing.T) {
+	t.Run("InjectsValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("dev", map[string]string{
+			"SERVICE_API_URI": "https://api.contoso.com",
+			"AZURE_ZONE":      "01",
+		})
+
+		runs := []exec.RunArgs{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runs = append(runs, args)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		dotenvFile := filepath.Join(t.TempDir(), "web", ".env.local")
+		action := newEnvExecAction(
+			env,
+			&mockenv.MockEnvManager{},
+			mockContext.CommandRunner,
+			mockContext.Console,
+			mockContext.Container,
+			&envExecFlags{interval: time.Second, dotenvFiles: []string{dotenvFile}, global: &internal.GlobalCommandOptions{}},
+			[]string{"npm", "run", "dev"},
+		)
+
+		_, err := action.Run(*mockContext.Context)
+		require.NoError(t, err)
+
+		require.Len(t, runs, 1)
+		require.Equal(t, "npm", runs[0].Cmd)
+		require.Equal(t, []string{"run", "dev"}, runs[0].Args)
+		require.True(t, runs[0].Interactive)
+		slices.Sort(runs[0].Env)
+		require.Equal(t, []string{"AZURE_ZONE=01", "SERVICE_API_URI=https://api.contoso.com"}, runs[0].Env)
+
+		values, err := godotenv.Read(dotenvFile)
+		require.NoError(t, err)
+		require.Equal(t, env.Dotenv(), values)
+	})
+
+	t.Run("WatchRestartsOnChange", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		ctx, cancel := context.WithCancel(*mockContext.Context)
+		defer cancel()
+
+		env := environment.NewWithValues("dev", map[string]string{"SERVICE_API_URI": "http://localhost"})
+
+		// The first reload is unchanged, the second one has the output of a new provisioning
+		reloads := 0
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Reload", mock.Anything, env).Run(func(args mock.Arguments) {
+			reloads++
+			if reloads == 2 {
+				env.DotenvSet("SERVICE_API_URI", "https://api.contoso.com")
+			}
+		}).Return(nil)
+
+		runs := [][]string{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runs = append(runs, args.Env)
+			// The restarted process runs until the command is cancelled
+			if len(runs) == 2 {
+				cancel()
+			}
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		action := newEnvExecAction(
+			env,
+			envManager,
+			mockContext.CommandRunner,
+			mockContext.Console,
+			mockContext.Container,
+			&envExecFlags{watch: true, interval: time.Millisecond, global: &internal.GlobalCommandOptions{}},
+			[]string{"python", "app.py"},
+		)
+
+		_, err := action.Run(ctx)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]string{
+			{"SERVICE_API_URI=http://localhost"},
+			{"SERVICE_API_URI=https://api.contoso.com"},
+		}, runs)
+		require.GreaterOrEqual(t, reloads, 2)
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type envExtendFlags struct {
+	hours  int
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *envExtendFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.IntVar(&f.hours, "hours", 8, "The number of hours to push the scheduled deletion of the environment out by.")
+
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvExtendFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envExtendFlags {
+	flags := &envExtendFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvExtendCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "extend",
+		Short: "Push out the scheduled deletion of the devcenter environment.",
+		Long: "Push out the scheduled deletion of the devcenter environment, which expires after the hours set by " +
+			"'platform.config.expirationHours' when it is provisioned.\n\n" +
+			"Only devcenter environments are supported, list their scheduled actions with 'azd show'.",
+		Example: `$ azd env extend --hours 4`,
+		Args:    cobra.NoArgs,
+	}
+}
+
+// envExtendResult is the result of 'azd env extend'.
+type envExtendResult struct {
+	Environment   string    `json:"environment"`
+	ScheduledTime time.Time `json:"scheduledTime"`
+}
+
+type envExtendAction struct {
+	env            *environment.Environment
+	console        input.Console
+	formatter      output.Formatter
+	writer         io.Writer
+	flags          *envExtendFlags
+	serviceLocator ioc.ServiceLocator
+}
+
+func newEnvExtendAction(
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envExtendFlags,
+	serviceLocator ioc.ServiceLocator,
+) actions.Action {
+	return &envExtendAction{
+		env:            env,
+		console:        console,
+		formatter:      formatter,
+		writer:         writer,
+		flags:          flags,
+		serviceLocator: serviceLocator,
+	}
+}
+
+func (a *envExtendAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.hours <= 0 {
+		return nil, fmt.Errorf("--hours must be positive, got %d", a.flags.hours)
+	}
+
+	var schedules *devcenter.Schedules
+	if err := a.serviceLocator.Resolve(&schedules); err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("the devcenter platform is not enabled: %w", err),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: Only devcenter environments are scheduled for deletion, enable the devcenter platform "+
+					"with %s.",
+				output.WithHighLightFormat("azd config set platform.type devcenter"),
+			),
+		}
+	}
+
+	spinnerMessage := fmt.Sprintf("Extending environment %s", output.WithHighLightFormat(a.env.Name()))
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	action, err := schedules.Extend(ctx, a.env.Name(), a.flags.hours)
+	if err != nil {
+		a.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+		return nil, err
+	}
+	a.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(envExtendResult{
+			Environment:   a.env.Name(),
+			ScheduledTime: action.ScheduledTime,
+		}, a.writer, nil)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Environment '%s' is now scheduled for deletion at %s.",
+				a.env.Name(),
+				action.ScheduledTime.UTC().Format(time.RFC1123),
+			),
+		},
+	}, nil
+}
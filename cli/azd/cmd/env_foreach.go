@@ -0,0 +1,353 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type envForeachFlags struct {
+	filter     string
+	configKeys []string
+	parallel   int
+	failFast   bool
+	global     *internal.GlobalCommandOptions
+}
+
+func (f *envForeachFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.filter, "filter", "*", "Only runs the command in the environments whose name matches the glob.")
+	local.StringArrayVar(
+		&f.configKeys,
+		"config-key",
+		nil,
+		"Only runs the command in the environments whose config has the key, or the key set to the value with "+
+			"<key>=<value>, ex) owner=alice. Repeatable.")
+	local.IntVar(&f.parallel, "parallel", 1, "The number of environments the command runs in at the same time.")
+	local.BoolVar(&f.failFast, "fail-fast", false, "Stops running the command in other environments after a failure.")
+
+	f.global = global
+}
+
+func newEnvForeachFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envForeachFlags {
+	flags := &envForeachFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvForeachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "foreach [flags] -- <command> [args...]",
+		Short: "Run an azd command in each environment matching a filter.",
+		Long: "Run an azd command in each environment matching a filter, ex) to provision or deploy the environments of " +
+			"all the developers of a team.\n\n" +
+			"The command runs with AZURE_ENV_NAME set to each environment, sequentially or in up to --parallel " +
+			"environments at the same time. When it runs in parallel, the output of the command in each environment is " +
+			"printed when it completes, and the command never prompts. The result in each environment is printed last, " +
+			"and with --output json, only the results are printed.",
+		Example: `$ azd env foreach --filter 'dev-*' -- provision
+$ azd env foreach --config-key team=payments --parallel 3 -- deploy api
+$ azd env foreach --filter 'dev-*' --output json -- hooks run seed`,
+		Args: cobra.MinimumNArgs(1),
+	}
+}
+
+// envForeachResult is the result of the command in one environment.
+type envForeachResult struct {
+	Environment     string  `json:"environment"`
+	Status          string  `json:"status"`
+	ExitCode        int     `json:"exitCode"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+const (
+	envForeachSucceeded = "succeeded"
+	envForeachFailed    = "failed"
+	// The command didn't run in the environment, since it ran with --fail-fast and failed in another environment
+	envForeachSkipped = "skipped"
+)
+
+type envForeachAction struct {
+	envManager    environment.Manager
+	azdCtx        *azdcontext.AzdContext
+	commandRunner exec.CommandRunner
+	console       input.Console
+	formatter     output.Formatter
+	writer        io.Writer
+	flags         *envForeachFlags
+	args          []string
+}
+
+func newEnvForeachAction(
+	envManager environment.Manager,
+	azdCtx *azdcontext.AzdContext,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envForeachFlags,
+	args []string,
+) actions.Action {
+	return &envForeachAction{
+		envManager:    envManager,
+		azdCtx:        azdCtx,
+		commandRunner: commandRunner,
+		console:       console,
+		formatter:     formatter,
+		writer:        writer,
+		flags:         flags,
+		args:          args,
+	}
+}
+
+func (a *envForeachAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.parallel < 1 {
+		return nil, errors.New("--parallel must be at least 1")
+	}
+
+	if _, err := path.Match(a.flags.filter, ""); err != nil {
+		return nil, fmt.Errorf("invalid filter '%s': %w", a.flags.filter, err)
+	}
+
+	for _, arg := range a.args {
+		if arg == "--" {
+			break
+		}
+
+		if arg == "-e" || arg == "--environment" || strings.HasPrefix(arg, "--environment=") {
+			return nil, &internal.ErrorWithSuggestion{
+				Err:        errors.New("the command may not set the environment, it's set to each environment"),
+				Suggestion: "Suggestion: select the environments with --filter or --config-key instead.",
+			}
+		}
+	}
+
+	envNames, err := a.environments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envNames) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        errors.New("no environment matches the filter"),
+			Suggestion: "Suggestion: run 'azd env list' to list the environments.",
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("finding the azd executable: %w", err)
+	}
+
+	command := strings.Join(a.args, " ")
+	if a.formatter.Kind() != output.JsonFormat {
+		a.console.Message(ctx, fmt.Sprintf("Running 'azd %s' in %d environment(s): %s\n",
+			command, len(envNames), strings.Join(envNames, ", ")))
+	}
+
+	results := make([]envForeachResult, len(envNames))
+	// Serializes the output of the environments completing at the same time
+	outputLock := sync.Mutex{}
+	failed := false
+
+	semaphore := make(chan struct{}, a.flags.parallel)
+	wg := sync.WaitGroup{}
+	for i, envName := range envNames {
+		semaphore <- struct{}{}
+
+		outputLock.Lock()
+		skip := a.flags.failFast && failed
+		outputLock.Unlock()
+
+		if skip {
+			<-semaphore
+			results[i] = envForeachResult{Environment: envName, Status: envForeachSkipped}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result, commandOutput := a.runIn(ctx, executable, envName)
+			results[i] = result
+
+			outputLock.Lock()
+			defer outputLock.Unlock()
+
+			if result.Status == envForeachFailed {
+				failed = true
+			}
+
+			if a.formatter.Kind() != output.JsonFormat {
+				if !a.streamsOutput() {
+					a.console.Message(ctx, output.WithBold("== %s ==", envName))
+					if commandOutput != "" {
+						a.console.Message(ctx, strings.TrimRight(commandOutput, "\n"))
+					}
+				}
+				a.console.Message(ctx, "")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(results, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, result := range results {
+			a.console.Message(ctx, envForeachResultLine(result))
+		}
+	}
+
+	failures := 0
+	for _, result := range results {
+		if result.Status == envForeachFailed {
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return nil, fmt.Errorf("'azd %s' failed in %d of %d environment(s)", command, failures, len(results))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("'azd %s' succeeded in %d environment(s).", command, len(results)),
+		},
+	}, nil
+}
+
+// environments returns the names of the environments matching the filters, sorted.
+func (a *envForeachAction) environments(ctx context.Context) ([]string, error) {
+	envs, err := a.envManager.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	envNames := []string{}
+	for _, env := range envs {
+		if matched, _ := path.Match(strings.ToLower(a.flags.filter), strings.ToLower(env.Name)); !matched {
+			continue
+		}
+
+		if len(a.flags.configKeys) > 0 {
+			loaded, err := a.envManager.Get(ctx, env.Name)
+			if err != nil {
+				return nil, fmt.Errorf("loading environment '%s': %w", env.Name, err)
+			}
+
+			if !matchesConfigKeys(loaded, a.flags.configKeys) {
+				continue
+			}
+		}
+
+		envNames = append(envNames, env.Name)
+	}
+
+	slices.Sort(envNames)
+	return envNames, nil
+}
+
+// matchesConfigKeys returns whether the config of the environment has all the keys, each formatted as <key> or
+// <key>=<value>.
+func matchesConfigKeys(env *environment.Environment, configKeys []string) bool {
+	for _, configKey := range configKeys {
+		key, expected, hasValue := strings.Cut(configKey, "=")
+		value, has := env.Config.Get(key)
+		if !has || (hasValue && fmt.Sprint(value) != expected) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// streamsOutput returns whether the command runs attached to the console, which is only the case when it runs in one
+// environment at a time and its output isn't replaced by the JSON results.
+func (a *envForeachAction) streamsOutput() bool {
+	return a.flags.parallel == 1 && a.formatter.Kind() != output.JsonFormat
+}
+
+// runIn runs the command in the environment with a new azd process, returning its result and its output, unless the
+// output is streamed to the console. The processes which aren't attached to the console never prompt.
+func (a *envForeachAction) runIn(
+	ctx context.Context,
+	executable string,
+	envName string,
+) (envForeachResult, string) {
+	// The flags are set before the arguments of the command, which may end with positional arguments after '--'. The
+	// environment is set with AZURE_ENV_NAME, since --environment isn't a flag of the root command.
+	args := a.args
+	if !a.streamsOutput() || a.flags.global.NoPrompt {
+		args = slices.Concat([]string{"--no-prompt"}, args)
+	}
+
+	commandOutput := &bytes.Buffer{}
+	runArgs := exec.NewRunArgs(executable, args...).
+		WithCwd(a.azdCtx.ProjectDirectory()).
+		WithEnv([]string{fmt.Sprintf("%s=%s", environment.EnvNameEnvVarName, envName)})
+	if a.streamsOutput() {
+		a.console.Message(ctx, output.WithBold("== %s ==", envName))
+		runArgs = runArgs.WithInteractive(true)
+	} else {
+		runArgs.StdOut = commandOutput
+		runArgs.Stderr = commandOutput
+	}
+
+	start := time.Now()
+	runResult, err := a.commandRunner.Run(ctx, runArgs)
+	result := envForeachResult{
+		Environment:     envName,
+		Status:          envForeachSucceeded,
+		ExitCode:        runResult.ExitCode,
+		DurationSeconds: time.Since(start).Round(time.Millisecond).Seconds(),
+	}
+
+	if err != nil {
+		result.Status = envForeachFailed
+		result.Error = err.Error()
+	}
+
+	return result, commandOutput.String()
+}
+
+// envForeachResultLine formats the result of the command in an environment, ex) (✓) Done: dev-alice (1m5s)
+func envForeachResultLine(result envForeachResult) string {
+	duration := time.Duration(result.DurationSeconds * float64(time.Second)).Round(time.Second)
+	switch result.Status {
+	case envForeachSucceeded:
+		return fmt.Sprintf("  (%s) Done: %s (%s)",
+			output.WithSuccessFormat(output.CheckMark()), result.Environment, duration)
+	case envForeachFailed:
+		return fmt.Sprintf("  (%s) Failed: %s (exit code %d)",
+			output.WithErrorFormat("x"), result.Environment, result.ExitCode)
+	default:
+		return fmt.Sprintf("  (%s) Skipped: %s", output.WithGrayFormat("-"), result.Environment)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvForeach(t *testing.T) {
+	alice := environment.New("dev-alice")
+	require.NoError(t, alice.Config.Set("team", "payments"))
+	bob := environment.New("dev-bob")
+	require.NoError(t, bob.Config.Set("team", "search"))
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("List", mock.Anything).Return([]*environment.Description{
+		{Name: "prod"},
+		{Name: "dev-bob"},
+		{Name: "dev-alice"},
+	}, nil)
+	envManager.On("Get", mock.Anything, "dev-alice").Return(alice, nil)
+	envManager.On("Get", mock.Anything, "dev-bob").Return(bob, nil)
+	envManager.On("Get", mock.Anything, "prod").Return(environment.New("prod"), nil)
+
+	run := func(
+		t *testing.T,
+		flags *envForeachFlags,
+		failIn string,
+	) ([]envForeachResult, [][]string, error) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		lock := sync.Mutex{}
+		commands := [][]string{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			lock.Lock()
+			commands = append(commands, slices.Concat(args.Args, args.Env))
+			lock.Unlock()
+
+			if slices.Contains(args.Env, "AZURE_ENV_NAME="+failIn) {
+				return exec.NewRunResult(1, "", ""), errors.New("exit code: 1")
+			}
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		flags.global = &internal.GlobalCommandOptions{}
+		writer := &bytes.Buffer{}
+		action := newEnvForeachAction(
+			envManager,
+			azdcontext.NewAzdContextWithDirectory(t.TempDir()),
+			mockContext.CommandRunner,
+			mockContext.Console,
+			&output.JsonFormatter{},
+			writer,
+			flags,
+			[]string{"provision"},
+		)
+
+		_, err := action.Run(*mockContext.Context)
+
+		results := []envForeachResult{}
+		if writer.Len() > 0 {
+			require.NoError(t, json.Unmarshal(writer.Bytes(), &results))
+		}
+
+		slices.SortFunc(commands, func(a, b []string) int {
+			return slices.Compare(a, b)
+		})
+		return results, commands, err
+	}
+
+	t.Run("FilterByName", func(t *testing.T) {
+		results, commands, err := run(t, &envForeachFlags{filter: "dev-*", parallel: 2}, "")
+		require.NoError(t, err)
+
+		require.Len(t, results, 2)
+		require.Equal(t, "dev-alice", results[0].Environment)
+		require.Equal(t, envForeachSucceeded, results[0].Status)
+		require.Equal(t, "dev-bob", results[1].Environment)
+		require.Equal(t, envForeachSucceeded, results[1].Status)
+
+		require.Equal(t, [][]string{
+			{"--no-prompt", "provision", "AZURE_ENV_NAME=dev-alice"},
+			{"--no-prompt", "provision", "AZURE_ENV_NAME=dev-bob"},
+		}, commands)
+	})
+
+	t.Run("FilterByConfigKey", func(t *testing.T) {
+		results, _, err := run(t, &envForeachFlags{filter: "*", configKeys: []string{"team=payments"}, parallel: 1}, "")
+		require.NoError(t, err)
+
+		require.Len(t, results, 1)
+		require.Equal(t, "dev-alice", results[0].Environment)
+	})
+
+	t.Run("AggregatesFailures", func(t *testing.T) {
+		results, commands, err := run(t, &envForeachFlags{filter: "dev-*", parallel: 1}, "dev-alice")
+		require.ErrorContains(t, err, "failed in 1 of 2 environment(s)")
+
+		require.Equal(t, envForeachFailed, results[0].Status)
+		require.Equal(t, 1, results[0].ExitCode)
+		require.Equal(t, envForeachSucceeded, results[1].Status)
+		require.Len(t, commands, 2)
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		results, commands, err := run(t, &envForeachFlags{filter: "dev-*", parallel: 1, failFast: true}, "dev-alice")
+		require.Error(t, err)
+
+		require.Equal(t, envForeachFailed, results[0].Status)
+		require.Equal(t, envForeachSkipped, results[1].Status)
+		require.Len(t, commands, 1)
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, _, err := run(t, &envForeachFlags{filter: "test-*", parallel: 1}, "")
+		require.ErrorContains(t, err, "no environment matches the filter")
+	})
+}
+
+func TestMatchesConfigKeys(t *testing.T) {
+	env := environment.New("dev")
+	require.NoError(t, env.Config.Set("team", "payments"))
+	require.NoError(t, env.Config.Set("infra.size", 3))
+
+	require.True(t, matchesConfigKeys(env, nil))
+	require.True(t, matchesConfigKeys(env, []string{"team"}))
+	require.True(t, matchesConfigKeys(env, []string{"team=payments", "infra.size=3"}))
+	require.False(t, matchesConfigKeys(env, []string{"team=search"}))
+	require.False(t, matchesConfigKeys(env, []string{"owner"}))
+}
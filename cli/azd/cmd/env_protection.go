@@ -0,0 +1,166 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var protectedEnvironmentPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "PROTECTED_ENVIRONMENT_NAME",
+	Description: "The name of the protected environment to delete, typed to confirm its deletion.",
+	Commands:    []string{"down", "env delete"},
+})
+
+// ensureUnprotected checks the environment can be deleted. A protected environment, locked or matching the protected
+// environments of the project, is only deleted when --unlock is passed and the name of the environment is typed.
+func ensureUnprotected(
+	ctx context.Context,
+	console input.Console,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	unlock bool,
+) error {
+	reason := project.EnvironmentProtection(projectConfig, env)
+	if reason == "" {
+		return nil
+	}
+
+	if !unlock {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("%s and can't be deleted", reason),
+			Suggestion: fmt.Sprintf(
+				"To delete the environment, run the command again with %s and type the name of the environment.",
+				output.WithHighLightFormat("--unlock")),
+		}
+	}
+
+	console.Message(ctx, output.WithWarningFormat("WARNING: The %s.", reason))
+	name, err := console.Prompt(ctx, input.ConsoleOptions{
+		Id:      protectedEnvironmentPromptId,
+		Message: "Type the name of the environment to confirm its deletion:",
+	})
+	if err != nil {
+		return err
+	}
+
+	if name != env.Name() {
+		return &internal.ErrorWithSuggestion{
+			Err: errors.New("the name typed doesn't match the name of the environment, the deletion was cancelled"),
+			Suggestion: fmt.Sprintf(
+				"When prompts are disabled, set %s to the name of the environment.",
+				output.WithHighLightFormat(protectedEnvironmentPromptId.EnvVarName())),
+		}
+	}
+
+	return nil
+}
+
+type envLockFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *envLockFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvLockFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envLockFlags {
+	flags := &envLockFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvLockCmd(locked bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock [<environment>]",
+		Short: "Lock an environment, so it can't be deleted by accident.",
+		Long: "Lock an environment. 'azd down' and 'azd env delete' only delete a locked environment when --unlock " +
+			"is passed and the name of the environment is typed to confirm.",
+	}
+	if !locked {
+		cmd.Use = "unlock [<environment>]"
+		cmd.Short = "Unlock an environment, so it can be deleted without confirming its name."
+		cmd.Long = "Unlock an environment. Environments matching the protected environments of the project stay " +
+			"protected."
+	}
+
+	// Like `azd env delete`, the environment can be passed either as an argument or with -e / --environment.
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			return nil
+		}
+
+		if flagValue, err := cmd.Flags().GetString(internal.EnvironmentNameFlagName); err == nil {
+			if flagValue != "" && args[0] != flagValue {
+				return errors.New(
+					"the --environment flag and an explicit environment name as an argument may not be used together")
+			}
+		}
+
+		return cmd.Flags().Set(internal.EnvironmentNameFlagName, args[0])
+	}
+
+	return cmd
+}
+
+type envLockAction struct {
+	env        *environment.Environment
+	envManager environment.Manager
+	locked     bool
+}
+
+func newEnvLockAction(env *environment.Environment, envManager environment.Manager) actions.Action {
+	return &envLockAction{
+		env:        env,
+		envManager: envManager,
+		locked:     true,
+	}
+}
+
+func newEnvUnlockAction(env *environment.Environment, envManager environment.Manager) actions.Action {
+	return &envLockAction{
+		env:        env,
+		envManager: envManager,
+		locked:     false,
+	}
+}
+
+func (a *envLockAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if err := a.env.SetLocked(a.locked); err != nil {
+		return nil, fmt.Errorf("updating the lock of the environment: %w", err)
+	}
+
+	if err := a.envManager.Save(ctx, a.env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	header := fmt.Sprintf("Environment %s was locked.", a.env.Name())
+	if !a.locked {
+		header = fmt.Sprintf("Environment %s was unlocked.", a.env.Name())
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: header,
+		},
+	}, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureUnprotected(t *testing.T) {
+	projectConfig := &project.ProjectConfig{
+		Protection: &project.ProtectionConfig{
+			Environments: []string{"prod*"},
+		},
+	}
+
+	t.Run("NotProtected", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		err := ensureUnprotected(context.Background(), console, projectConfig, environment.New("dev"), false)
+		require.NoError(t, err)
+	})
+
+	t.Run("RequiresUnlock", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		err := ensureUnprotected(context.Background(), console, projectConfig, environment.New("prod"), false)
+
+		var suggestionErr *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.Contains(t, suggestionErr.Suggestion, "--unlock")
+	})
+
+	t.Run("NameConfirmed", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		console.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return options.Id == protectedEnvironmentPromptId
+		}).Respond("prod")
+
+		err := ensureUnprotected(context.Background(), console, projectConfig, environment.New("prod"), true)
+		require.NoError(t, err)
+	})
+
+	t.Run("NameMismatch", func(t *testing.T) {
+		env := environment.New("dev")
+		require.NoError(t, env.SetLocked(true))
+
+		console := mockinput.NewMockConsole()
+		console.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return options.Id == protectedEnvironmentPromptId
+		}).Respond("prod")
+
+		err := ensureUnprotected(context.Background(), console, projectConfig, env, true)
+		require.ErrorContains(t, err, "deletion was cancelled")
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/wait"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type envWaitFlags struct {
+	keys     []string
+	dnsNames []string
+	urls     []string
+	timeout  time.Duration
+	interval time.Duration
+	global   *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func (f *envWaitFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringArrayVar(
+		&f.keys,
+		"for",
+		nil,
+		"Waits until the environment value is set, ex) an output of a provisioning running in another job. Repeatable.")
+	local.StringArrayVar(&f.dnsNames, "for-dns", nil, "Waits until the DNS name resolves. Repeatable.")
+	local.StringArrayVar(&f.urls, "for-url", nil, "Waits until a GET request to the URL returns 200 OK. Repeatable.")
+	local.DurationVar(&f.timeout, "timeout", 10*time.Minute, "The maximum time to wait for the conditions.")
+	local.DurationVar(&f.interval, "interval", 5*time.Second, "The time between the checks of the conditions.")
+
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvWaitFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envWaitFlags {
+	flags := &envWaitFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvWaitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "wait",
+		Short: "Wait until environment values are set, DNS names resolve or endpoints are up.",
+		Long: "Wait until environment values are set, DNS names resolve or endpoints return 200 OK.\n\n" +
+			"The command fails when the conditions aren't all met before the timeout. " +
+			"With --output json, the result of each condition is printed.",
+		Example: `$ azd env wait --for SERVICE_API_URI
+$ azd env wait --for-dns myapp.azurewebsites.net --timeout 15m
+$ azd env wait --for-url https://myapp.azurewebsites.net/health --output json`,
+		Args: cobra.NoArgs,
+	}
+}
+
+// envWaitResult is the result of 'azd env wait'.
+type envWaitResult struct {
+	Met            bool          `json:"met"`
+	ElapsedSeconds float64       `json:"elapsedSeconds"`
+	Conditions     []wait.Result `json:"conditions"`
+}
+
+type envWaitAction struct {
+	env        *environment.Environment
+	envManager environment.Manager
+	httpClient auth.HttpClient
+	console    input.Console
+	formatter  output.Formatter
+	writer     io.Writer
+	flags      *envWaitFlags
+}
+
+func newEnvWaitAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	httpClient auth.HttpClient,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envWaitFlags,
+) actions.Action {
+	return &envWaitAction{
+		env:        env,
+		envManager: envManager,
+		httpClient: httpClient,
+		console:    console,
+		formatter:  formatter,
+		writer:     writer,
+		flags:      flags,
+	}
+}
+
+func (a *envWaitAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	conditions := []wait.Condition{}
+	for _, key := range a.flags.keys {
+		conditions = append(conditions, wait.EnvValue(key, a.lookupEnv))
+	}
+	for _, dnsName := range a.flags.dnsNames {
+		conditions = append(conditions, wait.DNS(dnsName, nil))
+	}
+	for _, url := range a.flags.urls {
+		conditions = append(conditions, wait.HTTP(url, a.httpClient))
+	}
+
+	if len(conditions) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        errors.New("no condition to wait for"),
+			Suggestion: "Suggestion: specify the conditions with --for, --for-dns or --for-url.",
+		}
+	}
+
+	start := time.Now()
+	spinnerMessage := fmt.Sprintf("Waiting for %d condition(s)", len(conditions))
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	results, err := wait.For(ctx, conditions, wait.Options{
+		Timeout:  a.flags.timeout,
+		Interval: a.flags.interval,
+		OnMet: func(result wait.Result) {
+			a.console.StopSpinner(ctx, fmt.Sprintf("%s %s: %s", result.Kind, result.Target, result.Details), input.StepDone)
+			a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+		},
+	})
+	a.console.StopSpinner(ctx, "", input.Step)
+
+	if a.formatter.Kind() == output.JsonFormat {
+		result := envWaitResult{
+			Met:            err == nil,
+			ElapsedSeconds: time.Since(start).Round(time.Millisecond).Seconds(),
+			Conditions:     results,
+		}
+		if formatErr := a.formatter.Format(result, a.writer, nil); formatErr != nil {
+			return nil, formatErr
+		}
+	}
+
+	if errors.Is(err, wait.ErrTimeout) {
+		for _, result := range results {
+			if !result.Met {
+				a.console.Message(ctx, output.WithErrorFormat(
+					"  (x) %s %s: %s", result.Kind, result.Target, result.Details))
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("All the conditions were met in %s.", time.Since(start).Round(time.Second)),
+		},
+	}, nil
+}
+
+// lookupEnv reloads the environment, which may be updated by another azd process, and returns the value of the key
+func (a *envWaitAction) lookupEnv(ctx context.Context, key string) (string, bool, error) {
+	if err := a.envManager.Reload(ctx, a.env); err != nil {
+		return "", false, fmt.Errorf("reloading environment: %w", err)
+	}
+
+	// Only the values of the environment are considered, not the variables of the shell
+	value, has := a.env.Dotenv()[key]
+	return value, has, nil
+}
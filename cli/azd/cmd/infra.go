@@ -51,5 +51,7 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			DefaultFormat:  output.NoneFormat,
 		})
 
+	infraParamsActions(group)
+
 	return group
 }
@@ -25,6 +25,13 @@ import (
 	"github.com/spf13/pflag"
 )
 
+var infraGenerateOverwritePromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id: "INFRA_GENERATE_OVERWRITE",
+	Description: "What to do with the existing files of the generated infrastructure: " +
+		"'Overwrite with the generated versions' or 'Keep my existing files unchanged'.",
+	Commands: []string{"infra generate"},
+})
+
 type infraGenerateFlags struct {
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
@@ -190,6 +197,7 @@ func (a *infraGenerateAction) promptForDuplicates(
 		}
 
 		selection, err := a.console.Select(ctx, input.ConsoleOptions{
+			Id:      infraGenerateOverwritePromptId,
 			Message: "What would you like to do with these files?",
 			Options: []string{
 				"Overwrite with the generated versions",
@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// infraParamsActions registers the 'azd infra params' commands under the infra command.
+func infraParamsActions(infra *actions.ActionDescriptor) {
+	params := infra.Add("params", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Short: "Manage the infrastructure parameters of your environment.",
+		},
+	})
+
+	params.Add("export", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "export",
+			Short: "Export the parameters of the current environment as a parameters file of your IaC tool.",
+			Long: "Export the effective parameters of the current environment, after the substitution of the " +
+				"environment variables, as a .bicepparam file for Bicep or a .tfvars file for Terraform.\n\n" +
+				"The values of secure parameters are never written to the file, the file references environment " +
+				"variables instead.",
+			Args: cobra.NoArgs,
+		},
+		FlagsResolver:  newInfraParamsExportFlags,
+		ActionResolver: newInfraParamsExportAction,
+		OutputFormats:  []output.Format{output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		RequireLogin:   true,
+	})
+}
+
+type infraParamsExportFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+	file string
+}
+
+func newInfraParamsExportFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraParamsExportFlags {
+	flags := &infraParamsExportFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *infraParamsExportFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+	local.StringVar(
+		&f.file,
+		"file",
+		"",
+		"The path of the parameters file to write. When not set, the parameters are written to the standard output.")
+}
+
+type infraParamsExportAction struct {
+	flags               *infraParamsExportFlags
+	provisioningManager *provisioning.Manager
+	projectConfig       *project.ProjectConfig
+	importManager       *project.ImportManager
+	console             input.Console
+	writer              io.Writer
+}
+
+func newInfraParamsExportAction(
+	flags *infraParamsExportFlags,
+	provisioningManager *provisioning.Manager,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	console input.Console,
+	writer io.Writer,
+) actions.Action {
+	return &infraParamsExportAction{
+		flags:               flags,
+		provisioningManager: provisioningManager,
+		projectConfig:       projectConfig,
+		importManager:       importManager,
+		console:             console,
+		writer:              writer,
+	}
+}
+
+func (a *infraParamsExportAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infra, err := a.importManager.ProjectInfrastructure(ctx, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	if err := a.provisioningManager.Initialize(ctx, a.projectConfig.Path, infra.Options); err != nil {
+		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+	}
+
+	// Paths in the file are relative to the folder it's written to
+	dir := a.provisioningManager.InfraPath()
+	filePath := a.flags.file
+	if filePath != "" {
+		if filePath, err = filepath.Abs(filePath); err != nil {
+			return nil, err
+		}
+
+		dir = filepath.Dir(filePath)
+	}
+
+	file, err := a.provisioningManager.ExportParameters(ctx, dir)
+	if errors.Is(err, provisioning.ErrParametersExportNotSupported) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        err,
+			Suggestion: "Exporting parameters is available for Bicep and Terraform infrastructure.",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if filePath == "" {
+		_, err := a.writer.Write(file.Contents)
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating directory '%s': %w", dir, err)
+	}
+
+	if err := os.WriteFile(filePath, file.Contents, osutil.PermissionFile); err != nil {
+		return nil, fmt.Errorf("writing parameters file: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Parameters written to %s", output.WithHighLightFormat(filePath)),
+		},
+	}, nil
+}
@@ -7,17 +7,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
 	"github.com/azure/azure-dev/cli/azd/internal/repository"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -36,6 +41,19 @@ import (
 	"github.com/spf13/pflag"
 )
 
+var (
+	initMethodPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "INIT_METHOD",
+		Description: "How to initialize the app: 'Scan current directory' or 'Select a template'.",
+		Commands:    []string{"init"},
+	})
+	initUpPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "INIT_UP",
+		Description: "Whether to run 'azd up' after initializing the template, with --up: true or false.",
+		Commands:    []string{"init"},
+	})
+)
+
 func newInitFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *initFlags {
 	flags := &initFlags{}
 	flags.Bind(cmd.Flags(), global)
@@ -60,6 +78,8 @@ type initFlags struct {
 	fromCode       bool
 	minimal        bool
 	up             bool
+	dryRunDetect   bool
+	devcontainer   bool
 	internal.EnvFlag
 }
 
@@ -113,6 +133,19 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		false,
 		"Provision and deploy to Azure after initializing the project from a template.",
 	)
+	local.BoolVarP(
+		&i.dryRunDetect,
+		"dry-run-detect",
+		"",
+		false,
+		"Detects the services of your existing code, without initializing the project.",
+	)
+	local.BoolVar(
+		&i.devcontainer,
+		"devcontainer",
+		false,
+		"Generates or updates the dev container configuration with the tools your project needs.",
+	)
 	local.StringVarP(&i.location, "location", "l", "", "Azure location for the new environment")
 	i.EnvFlag.Bind(local, global)
 
@@ -131,6 +164,8 @@ type initAction struct {
 	featuresManager   *alpha.FeatureManager
 	extensionsManager *extensions.Manager
 	azd               workflow.AzdCommandRunner
+	formatter         output.Formatter
+	writer            io.Writer
 }
 
 func newInitAction(
@@ -145,6 +180,8 @@ func newInitAction(
 	featuresManager *alpha.FeatureManager,
 	extensionsManager *extensions.Manager,
 	azd workflow.AzdCommandRunner,
+	formatter output.Formatter,
+	writer io.Writer,
 ) actions.Action {
 	return &initAction{
 		lazyAzdCtx:        lazyAzdCtx,
@@ -158,6 +195,8 @@ func newInitAction(
 		featuresManager:   featuresManager,
 		extensionsManager: extensionsManager,
 		azd:               azd,
+		formatter:         formatter,
+		writer:            writer,
 	}
 }
 
@@ -176,6 +215,18 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 				"using branch argument (-b or --branch) requires a template argument (--template or -t) to be specified")
 	}
 
+	if i.flags.dryRunDetect {
+		if i.flags.templatePath != "" || len(i.flags.templateTags) > 0 || i.flags.minimal {
+			return nil, errors.New("--dry-run-detect can't be used with --template, --filter or --minimal")
+		}
+
+		return nil, i.dryRunDetect(ctx, wd)
+	}
+
+	if i.formatter.Kind() == output.JsonFormat {
+		return nil, errors.New("--output json is only supported with --dry-run-detect")
+	}
+
 	// ensure that git is available
 	if err := tools.EnsureInstalled(ctx, []tools.ExternalTool{i.gitCli}...); err != nil {
 		return nil, err
@@ -267,6 +318,7 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		if i.flags.up {
 			// Prompt to deploy to Azure
 			deploy, err := i.console.Confirm(ctx, input.ConsoleOptions{
+				Id:           initUpPromptId,
 				Message:      "Do you want to run " + output.WithHighLightFormat("azd up") + " now?",
 				DefaultValue: true,
 				Help: "Template files have been initialized in your local directory. " +
@@ -352,6 +404,23 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("initializing project extensions: %w", err)
 	}
 
+	if i.flags.devcontainer {
+		prjConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+		if err != nil {
+			return nil, fmt.Errorf("loading project: %w", err)
+		}
+
+		result, err := devcontainer.Sync(azdCtx.ProjectDirectory(), prjConfig)
+		if err != nil {
+			return nil, fmt.Errorf("generating dev container configuration: %w", err)
+		}
+
+		if followUp != "" {
+			followUp += "\n"
+		}
+		followUp += devcontainerSyncMessage(azdCtx.ProjectDirectory(), result)
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			Header:   header,
@@ -360,6 +429,96 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}, nil
 }
 
+// dryRunDetect detects the services of the existing code in the directory, the same way as 'azd init --from-code',
+// without initializing the project.
+func (i *initAction) dryRunDetect(ctx context.Context, wd string) error {
+	projects, err := repository.DetectApp(ctx, wd)
+	if err != nil {
+		return fmt.Errorf("detecting the services of the app: %w", err)
+	}
+
+	result := contracts.InitDetectResult{Projects: []contracts.InitDetectProject{}}
+	for _, prj := range projects {
+		result.Projects = append(result.Projects, initDetectProject(wd, prj))
+	}
+
+	if i.formatter.Kind() == output.JsonFormat {
+		return i.formatter.Format(result, i.writer, nil)
+	}
+
+	if len(result.Projects) == 0 {
+		i.console.Message(ctx, "No services were detected in the existing code.")
+		return nil
+	}
+
+	i.console.Message(ctx, "Detected services:\n")
+	for idx, prj := range result.Projects {
+		name := projects[idx].Language.Display()
+		frameworks := []string{}
+		for _, dependency := range projects[idx].Dependencies {
+			if display := dependency.Display(); display != "" {
+				frameworks = append(frameworks, display)
+			}
+		}
+		if len(frameworks) > 0 {
+			name += " (" + strings.Join(frameworks, ", ") + ")"
+		}
+
+		i.console.Message(ctx, fmt.Sprintf("  %s in %s", output.WithHighLightFormat(name), prj.Path))
+		i.console.Message(ctx, output.WithGrayFormat("    %s, confidence: %.1f", prj.DetectionRule, prj.Confidence))
+	}
+
+	return nil
+}
+
+// initDetectProject converts a detected project to its contract, with paths relative to the directory.
+func initDetectProject(wd string, prj appdetect.Project) contracts.InitDetectProject {
+	rel := func(path string) string {
+		if relPath, err := filepath.Rel(wd, path); err == nil {
+			return relPath
+		}
+
+		return path
+	}
+
+	result := contracts.InitDetectProject{
+		Language:      string(prj.Language),
+		Path:          rel(prj.Path),
+		DetectionRule: prj.DetectionRule,
+		Confidence:    float64(prj.Confidence),
+		StaticSite:    prj.StaticSite,
+	}
+
+	if prj.RootPath != "" {
+		result.RootPath = rel(prj.RootPath)
+	}
+
+	for _, dependency := range prj.Dependencies {
+		result.Dependencies = append(result.Dependencies, string(dependency))
+	}
+
+	for _, db := range prj.DatabaseDeps {
+		result.Databases = append(result.Databases, string(db))
+	}
+
+	if prj.Docker != nil {
+		result.Docker = &contracts.InitDetectDocker{Path: rel(prj.Docker.Path)}
+		for _, port := range prj.Docker.Ports {
+			result.Docker.Ports = append(result.Docker.Ports, fmt.Sprintf("%d/%s", port.Number, port.Protocol))
+		}
+	}
+
+	if prj.Compose != nil {
+		result.Compose = &contracts.InitDetectCompose{
+			Service: prj.Compose.Name,
+			Path:    rel(prj.Compose.Path),
+			Env:     prj.Compose.Env,
+		}
+	}
+
+	return result
+}
+
 type initType int
 
 const (
@@ -371,6 +530,7 @@ const (
 
 func promptInitType(console input.Console, ctx context.Context) (initType, error) {
 	selection, err := console.Select(ctx, input.ConsoleOptions{
+		Id:      initMethodPromptId,
 		Message: "How do you want to initialize your app?",
 		Options: []string{
 			"Scan current directory", // This now covers minimal project creation too
@@ -585,5 +745,8 @@ func getCmdInitHelpFooter(*cobra.Command) string {
 			output.WithHighLightFormat("--branch"),
 			output.WithWarningFormat("[Branch name]"),
 		),
+		"Detect the services of the existing code in your current local directory as JSON.": output.WithHighLightFormat(
+			"azd init --dry-run-detect --output json",
+		),
 	})
 }
@@ -8,38 +8,55 @@ import (
 	"fmt"
 	"log"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/grpcserver"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/fatih/color"
 )
 
 type ExtensionsMiddleware struct {
-	extensionManager *extensions.Manager
-	extensionRunner  *extensions.Runner
-	serviceLocator   ioc.ServiceLocator
-	console          input.Console
-	options          *Options
+	extensionManager    *extensions.Manager
+	extensionRunner     *extensions.Runner
+	serviceLocator      ioc.ServiceLocator
+	console             input.Console
+	options             *Options
+	globalOptions       *internal.GlobalCommandOptions
+	alphaFeatureManager *alpha.FeatureManager
+	lazyProjectConfig   *lazy.Lazy[*project.ProjectConfig]
 }
 
 func NewExtensionsMiddleware(
 	options *Options,
+	globalOptions *internal.GlobalCommandOptions,
 	serviceLocator ioc.ServiceLocator,
 	extensionsManager *extensions.Manager,
 	extensionRunner *extensions.Runner,
+	alphaFeatureManager *alpha.FeatureManager,
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
 	console input.Console,
 ) Middleware {
 	return &ExtensionsMiddleware{
-		options:          options,
-		serviceLocator:   serviceLocator,
-		extensionManager: extensionsManager,
-		extensionRunner:  extensionRunner,
-		console:          console,
+		options:             options,
+		globalOptions:       globalOptions,
+		serviceLocator:      serviceLocator,
+		extensionManager:    extensionsManager,
+		extensionRunner:     extensionRunner,
+		alphaFeatureManager: alphaFeatureManager,
+		lazyProjectConfig:   lazyProjectConfig,
+		console:             console,
 	}
 }
 
@@ -49,6 +66,11 @@ func (m *ExtensionsMiddleware) Run(ctx context.Context, next NextFn) (*actions.A
 		return next(ctx)
 	}
 
+	// Required extensions are installed first, so that the ones listening to lifecycle events are started below
+	if err := m.ensureRequiredExtensions(ctx); err != nil {
+		return nil, err
+	}
+
 	installedExtensions, err := m.extensionManager.ListInstalled()
 	if err != nil {
 		return nil, err
@@ -137,3 +159,168 @@ func (m *ExtensionsMiddleware) Run(ctx context.Context, next NextFn) (*actions.A
 
 	return next(ctx)
 }
+
+// extensionRequirement is an extension required by the project which is either missing or installed in a version that
+// doesn't satisfy the constraint of the project.
+type extensionRequirement struct {
+	Id         string
+	Constraint string
+	// The installed extension, nil when the extension is missing
+	Installed *extensions.Extension
+}
+
+// ensureRequiredExtensions installs or upgrades the extensions listed in the 'requiredVersions.extensions' section of
+// azure.yaml. Users are prompted before any change, unless prompts are disabled or azd runs on CI, and the install is
+// skipped altogether with '--no-auto-install'.
+func (m *ExtensionsMiddleware) ensureRequiredExtensions(ctx context.Context) error {
+	if !m.alphaFeatureManager.IsEnabled(extensions.FeatureExtensions) {
+		return nil
+	}
+
+	projectConfig, err := m.lazyProjectConfig.GetValue()
+	if err != nil || projectConfig == nil {
+		log.Println("azd project is not available, skipping required extensions.")
+		return nil
+	}
+
+	if projectConfig.RequiredVersions == nil || len(projectConfig.RequiredVersions.Extensions) == 0 {
+		return nil
+	}
+
+	installedExtensions, err := m.extensionManager.ListInstalled()
+	if err != nil {
+		return fmt.Errorf("listing installed extensions: %w", err)
+	}
+
+	requirements, err := unmetExtensionRequirements(installedExtensions, projectConfig.RequiredVersions.Extensions)
+	if err != nil {
+		return err
+	}
+
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		ids[i] = requirement.Id
+	}
+
+	if m.globalOptions.NoAutoInstall {
+		m.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: This project requires extensions that are missing or out of date: %s. "+
+				"Install them with 'azd extension install' or 'azd extension upgrade'.",
+			strings.Join(ids, ", "),
+		))
+		return nil
+	}
+
+	// Installs without prompting on CI, where the required extensions can't be installed ahead of time
+	if !m.globalOptions.NoPrompt && !resource.IsRunningOnCI() {
+		confirm, err := m.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"This project requires extensions that are missing or out of date (%s). Install them now?",
+				output.WithHighLightFormat(strings.Join(ids, ", ")),
+			),
+			DefaultValue: true,
+		})
+		if err != nil {
+			return fmt.Errorf("prompting to install required extensions: %w", err)
+		}
+
+		if !confirm {
+			m.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: Commands provided by the required extensions may not be available."))
+			return nil
+		}
+	}
+
+	m.console.Message(ctx, "\nInstalling required extensions...")
+
+	for _, requirement := range requirements {
+		filterOptions := &extensions.FilterOptions{
+			Version: requirement.Constraint,
+		}
+
+		if requirement.Installed == nil {
+			stepMessage := fmt.Sprintf("Installing %s extension", output.WithHighLightFormat(requirement.Id))
+			m.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+			extensionVersion, err := m.extensionManager.Install(ctx, requirement.Id, filterOptions)
+			if err != nil {
+				m.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+				return fmt.Errorf("installing extension %s: %w", requirement.Id, err)
+			}
+
+			stepMessage += output.WithGrayFormat(" (%s)", extensionVersion.Version)
+			m.console.StopSpinner(ctx, stepMessage, input.StepDone)
+			continue
+		}
+
+		stepMessage := fmt.Sprintf("Upgrading %s extension", output.WithHighLightFormat(requirement.Id))
+		m.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		extensionVersion, err := m.extensionManager.Upgrade(ctx, requirement.Id, filterOptions)
+		if err != nil {
+			m.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return fmt.Errorf("upgrading extension %s: %w", requirement.Id, err)
+		}
+
+		stepMessage += output.WithGrayFormat(" (%s -> %s)", requirement.Installed.Version, extensionVersion.Version)
+		m.console.StopSpinner(ctx, stepMessage, input.StepDone)
+	}
+
+	m.console.Message(ctx, "")
+
+	return nil
+}
+
+// unmetExtensionRequirements returns the required extensions, sorted by id, which are missing or installed in a version
+// that doesn't satisfy their constraint. A nil, empty or 'latest' constraint is satisfied by any installed version.
+func unmetExtensionRequirements(
+	installed map[string]*extensions.Extension,
+	required map[string]*string,
+) ([]*extensionRequirement, error) {
+	requirements := []*extensionRequirement{}
+
+	for id, versionConstraint := range required {
+		constraint := "latest"
+		if versionConstraint != nil && *versionConstraint != "" {
+			constraint = *versionConstraint
+		}
+
+		extension, has := installed[id]
+		if !has {
+			requirements = append(requirements, &extensionRequirement{Id: id, Constraint: constraint})
+			continue
+		}
+
+		if constraint == "latest" {
+			continue
+		}
+
+		semverConstraint, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint '%s' of required extension '%s': %w", constraint, id, err)
+		}
+
+		version, err := semver.NewVersion(extension.Version)
+		if err != nil {
+			log.Printf("failed parsing version '%s' of extension '%s': %v", extension.Version, id, err)
+		}
+
+		if err != nil || !semverConstraint.Check(version) {
+			requirements = append(requirements, &extensionRequirement{
+				Id:         id,
+				Constraint: constraint,
+				Installed:  extension,
+			})
+		}
+	}
+
+	slices.SortFunc(requirements, func(a, b *extensionRequirement) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return requirements, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_unmetExtensionRequirements(t *testing.T) {
+	constraint := func(value string) *string {
+		return &value
+	}
+
+	installed := map[string]*extensions.Extension{
+		"microsoft.azd.demo": {Id: "microsoft.azd.demo", Version: "1.2.0"},
+		"microsoft.azd.ai":   {Id: "microsoft.azd.ai", Version: "0.1.0"},
+	}
+
+	t.Run("Satisfied", func(t *testing.T) {
+		requirements, err := unmetExtensionRequirements(installed, map[string]*string{
+			"microsoft.azd.demo": constraint(">=1.0.0"),
+			"microsoft.azd.ai":   nil,
+		})
+		require.NoError(t, err)
+		require.Empty(t, requirements)
+	})
+
+	t.Run("LatestSatisfiedByAnyVersion", func(t *testing.T) {
+		requirements, err := unmetExtensionRequirements(installed, map[string]*string{
+			"microsoft.azd.ai": constraint("latest"),
+		})
+		require.NoError(t, err)
+		require.Empty(t, requirements)
+	})
+
+	t.Run("MissingAndOutdated", func(t *testing.T) {
+		requirements, err := unmetExtensionRequirements(installed, map[string]*string{
+			"microsoft.azd.demo":   constraint(">=1.0.0"),
+			"microsoft.azd.ai":     constraint("~0.2.0"),
+			"microsoft.azd.coding": nil,
+		})
+		require.NoError(t, err)
+		require.Len(t, requirements, 2)
+
+		require.Equal(t, "microsoft.azd.ai", requirements[0].Id)
+		require.Equal(t, "~0.2.0", requirements[0].Constraint)
+		require.Equal(t, installed["microsoft.azd.ai"], requirements[0].Installed)
+
+		require.Equal(t, "microsoft.azd.coding", requirements[1].Id)
+		require.Equal(t, "latest", requirements[1].Constraint)
+		require.Nil(t, requirements[1].Installed)
+	})
+
+	t.Run("InvalidConstraint", func(t *testing.T) {
+		_, err := unmetExtensionRequirements(installed, map[string]*string{
+			"microsoft.azd.demo": constraint("not-a-version"),
+		})
+		require.ErrorContains(t, err, "invalid version constraint 'not-a-version'")
+	})
+}
@@ -173,7 +173,7 @@ func (m *HooksMiddleware) registerServiceHooks(
 
 			if err := service.AddHandler(
 				ext.Event(hookName),
-				m.createServiceEventHandler(hookType, eventName, serviceHooksRunner),
+				m.createServiceEventHandler(env, hookType, eventName, serviceHooksRunner),
 			); err != nil {
 				return fmt.Errorf(
 					"failed registering event handler for service '%s' and event '%s', %w",
@@ -189,12 +189,15 @@ func (m *HooksMiddleware) registerServiceHooks(
 }
 
 // Creates an event handler for the specified service config and event name
+// The context of the service operation, like the package and the target resource of a deployment, is exposed to the hooks
 func (m *HooksMiddleware) createServiceEventHandler(
+	env *environment.Environment,
 	hookType ext.HookType,
 	hookName string,
 	hooksRunner *ext.HooksRunner,
 ) ext.EventHandlerFn[project.ServiceLifecycleEventArgs] {
 	return func(ctx context.Context, eventArgs project.ServiceLifecycleEventArgs) error {
-		return hooksRunner.RunHooks(ctx, hookType, nil, hookName)
+		hookContext := project.NewServiceHookContext(eventArgs, env)
+		return hooksRunner.RunHooksWithContext(ctx, hookType, nil, hookContext, hookName)
 	}
 }
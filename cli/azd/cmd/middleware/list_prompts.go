@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// Not every prompt is registered, the interactive wizards, ex) the ones of 'azd add' and of 'azd init' scanning the
+// directory, are only answered in the terminal.
+const partialCoverageNote = "The prompts which aren't listed can't be answered ahead of time yet, ex) the ones of " +
+	"'azd add'. The parameters of the infrastructure are answered in the parameters file, ex) main.parameters.json."
+
+// ListPromptsMiddleware lists the prompts of the command which can be answered with environment variables, instead of
+// running the command, when '--list-prompts' is set.
+type ListPromptsMiddleware struct {
+	options       *Options
+	globalOptions *internal.GlobalCommandOptions
+	console       input.Console
+}
+
+// Creates a new instance of the list prompts middleware
+func NewListPromptsMiddleware(
+	options *Options,
+	globalOptions *internal.GlobalCommandOptions,
+	console input.Console,
+) Middleware {
+	return &ListPromptsMiddleware{
+		options:       options,
+		globalOptions: globalOptions,
+		console:       console,
+	}
+}
+
+// Invokes the list prompts middleware. The action isn't run when the prompts are listed.
+func (m *ListPromptsMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if !m.globalOptions.ListPrompts || m.options.IsChildAction(ctx) {
+		return next(ctx)
+	}
+
+	command := strings.TrimPrefix(m.options.CommandPath, "azd ")
+	prompts := input.Prompts(command)
+	if len(prompts) == 0 {
+		m.console.Message(ctx, fmt.Sprintf("'%s' has no prompts which can be answered ahead of time.", m.options.CommandPath))
+		m.console.Message(ctx, output.WithGrayFormat(partialCoverageNote))
+		return nil, nil
+	}
+
+	m.console.Message(ctx, fmt.Sprintf(
+		"The prompts of '%s' can be answered by setting the following environment variables:\n", m.options.CommandPath))
+	for _, prompt := range prompts {
+		m.console.Message(ctx, fmt.Sprintf(
+			"  %s\n    %s", output.WithHighLightFormat(prompt.Id.EnvVarName()), prompt.Description))
+	}
+	m.console.Message(ctx, "\n"+output.WithGrayFormat(partialCoverageNote)+"\n")
+
+	return nil, nil
+}
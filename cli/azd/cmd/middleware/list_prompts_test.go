@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListPromptsMiddleware_Run(t *testing.T) {
+	input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "TEST_LIST_PROMPTS",
+		Description: "The value to use.",
+		Commands:    []string{"test list"},
+	})
+
+	t.Run("ListsPrompts", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewListPromptsMiddleware(
+			&Options{CommandPath: "azd test list"},
+			&internal.GlobalCommandOptions{ListPrompts: true},
+			mockContext.Console,
+		)
+
+		ran := false
+		result, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			ran = true
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.False(t, ran)
+
+		output := strings.Join(mockContext.Console.Output(), "\n")
+		require.Contains(t, output, "AZD_ANSWER_TEST_LIST_PROMPTS")
+		require.Contains(t, output, "The value to use.")
+		require.Contains(t, output, "The prompts which aren't listed can't be answered ahead of time yet")
+	})
+
+	t.Run("NotSet", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewListPromptsMiddleware(
+			&Options{CommandPath: "azd test list"},
+			&internal.GlobalCommandOptions{},
+			mockContext.Console,
+		)
+
+		result, err := middleware.Run(*mockContext.Context, next)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Empty(t, mockContext.Console.Output())
+	})
+}
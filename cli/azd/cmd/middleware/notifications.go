@@ -0,0 +1,232 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/notify"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// The section of the user configuration declaring the notifications of every project
+const notificationsConfigKey = "notifications"
+
+// The section of the user configuration recording whether the notifications of azure.yaml are allowed, by the
+// fingerprint of their endpoint
+const notificationConsentConfigKey = "notificationConsent"
+
+var notificationConsentPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id: "NOTIFICATION_CONSENT",
+	Description: "Whether to allow posting the results of azd commands to a notification declared in azure.yaml. " +
+		"The answer is remembered in the user configuration.",
+	Commands: []string{"provision", "deploy", "up", "down"},
+})
+
+// NotificationsMiddleware notifies the targets declared in the 'notifications' of azure.yaml and of the user
+// configuration when a lifecycle command, ex) 'azd deploy', completes or fails. The targets of azure.yaml are only
+// notified once the user allowed them, since the project may come from someone else.
+type NotificationsMiddleware struct {
+	options           *Options
+	globalOptions     *internal.GlobalCommandOptions
+	lazyEnv           *lazy.Lazy[*environment.Environment]
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]
+	userConfigManager config.UserConfigManager
+	notifier          *notify.Notifier
+	cloud             *cloud.Cloud
+	console           input.Console
+}
+
+// Creates a new instance of the notifications middleware
+func NewNotificationsMiddleware(
+	options *Options,
+	globalOptions *internal.GlobalCommandOptions,
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
+	userConfigManager config.UserConfigManager,
+	notifier *notify.Notifier,
+	cloud *cloud.Cloud,
+	console input.Console,
+) Middleware {
+	return &NotificationsMiddleware{
+		options:           options,
+		globalOptions:     globalOptions,
+		lazyEnv:           lazyEnv,
+		lazyProjectConfig: lazyProjectConfig,
+		userConfigManager: userConfigManager,
+		notifier:          notifier,
+		cloud:             cloud,
+		console:           console,
+	}
+}
+
+// Invokes the notifications middleware. The commands run by another command, ex) 'azd provision' run by 'azd up', are
+// notified with the command running them.
+func (m *NotificationsMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if m.options.IsChildAction(ctx) {
+		return next(ctx)
+	}
+
+	startedAt := time.Now()
+	result, err := next(ctx)
+
+	// The environment and the project are read once the command completed, since it may create them
+	env, envErr := m.lazyEnv.GetValue()
+	projectConfig, projectErr := m.lazyProjectConfig.GetValue()
+	if envErr != nil || projectErr != nil || env == nil || projectConfig == nil {
+		return result, err
+	}
+
+	operation := strings.TrimPrefix(m.options.CommandPath, "azd ")
+	targets := m.targets(ctx, projectConfig, env, operation)
+	if len(targets) == 0 {
+		return result, err
+	}
+
+	payload := notify.NewPayload(operation, projectConfig.Name, env.Name(), startedAt, err)
+	if link := m.portalLink(env); link != nil {
+		payload.Links = append(payload.Links, *link)
+	}
+
+	if notifyErr := m.notifier.Notify(ctx, targets, payload, env.Getenv); notifyErr != nil {
+		// A notification which can't be posted doesn't fail the command
+		log.Printf("failed posting notifications: %v", notifyErr)
+		m.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("Some notifications of 'azd %s' were not posted: %v", operation, notifyErr),
+		})
+	}
+
+	return result, err
+}
+
+// targets returns the notifications of the project allowed by the user, followed by the notifications of the user
+// configuration
+func (m *NotificationsMiddleware) targets(
+	ctx context.Context,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	operation string,
+) []*notify.Target {
+	userConfig, err := m.userConfigManager.Load()
+	if err != nil {
+		log.Printf("failed loading the notifications of the user configuration: %v", err)
+		return nil
+	}
+
+	targets := m.allowedTargets(ctx, userConfig, projectConfig, env, operation)
+
+	var userTargets []*notify.Target
+	if _, err := userConfig.GetSection(notificationsConfigKey, &userTargets); err != nil {
+		log.Printf("failed reading the notifications of the user configuration: %v", err)
+		return targets
+	}
+
+	return append(targets, userTargets...)
+}
+
+// allowedTargets returns the notifications of azure.yaml the user allowed. The first time a notification posts to an
+// endpoint, the user is asked whether to allow it, and the answer is saved in the user configuration. Without prompts,
+// the notifications which aren't allowed yet are skipped.
+func (m *NotificationsMiddleware) allowedTargets(
+	ctx context.Context,
+	userConfig config.Config,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	operation string,
+) []*notify.Target {
+	targets := []*notify.Target{}
+	changed := false
+
+	for _, target := range projectConfig.Notifications {
+		// Only the notifications of the command are prompted for, including the ones of its failures
+		payload := &notify.Payload{Operation: operation, Status: notify.StatusFailed}
+		if !target.Notifies(payload) {
+			continue
+		}
+
+		endpoint, err := target.Endpoint(env.Getenv)
+		if err != nil {
+			log.Printf("skipping notification of azure.yaml: %v", err)
+			continue
+		}
+
+		consentKey := fmt.Sprintf("%s.%s", notificationConsentConfigKey, endpoint.Fingerprint)
+		if allowed, has := userConfig.Get(consentKey); has {
+			if allowed == true {
+				targets = append(targets, target)
+			}
+			continue
+		}
+
+		if m.globalOptions.NoPrompt {
+			m.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: The %s notification of azure.yaml to %s wasn't posted, since it isn't allowed yet. Run "+
+					"'azd %s' without --no-prompt to allow it.", endpoint.Kind, endpoint.Host, operation))
+			continue
+		}
+
+		allowed, err := m.console.Confirm(ctx, input.ConsoleOptions{
+			Id: notificationConsentPromptId,
+			Message: fmt.Sprintf(
+				"Project '%s' posts the results of azd commands to the %s notification at %s. Allow it?",
+				projectConfig.Name, endpoint.Kind, endpoint.Host),
+			Help: "The notification includes the project, the environment, the outcome and the error of the command. " +
+				"The answer is saved in the user configuration.",
+			DefaultValue: false,
+		})
+		if err != nil {
+			log.Printf("failed prompting for notification consent: %v", err)
+			continue
+		}
+
+		if err := userConfig.Set(consentKey, allowed); err != nil {
+			log.Printf("failed recording notification consent: %v", err)
+			continue
+		}
+		changed = true
+
+		if allowed {
+			targets = append(targets, target)
+		}
+	}
+
+	if changed {
+		if err := m.userConfigManager.Save(userConfig); err != nil {
+			log.Printf("failed saving notification consent: %v", err)
+		}
+	}
+
+	return targets
+}
+
+// portalLink returns the link to the resource group of the environment in the Azure Portal, if any
+func (m *NotificationsMiddleware) portalLink(env *environment.Environment) *notify.Link {
+	subscriptionId := env.GetSubscriptionId()
+	resourceGroup := env.Getenv(environment.ResourceGroupEnvVarName)
+	if subscriptionId == "" || resourceGroup == "" {
+		return nil
+	}
+
+	return &notify.Link{
+		Title: "Open in Azure Portal",
+		Url: fmt.Sprintf(
+			"%s/#@/resource/subscriptions/%s/resourceGroups/%s/overview",
+			m.cloud.PortalUrlBase,
+			subscriptionId,
+			resourceGroup),
+	}
+}
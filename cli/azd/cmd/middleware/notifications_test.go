@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/notify"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NotificationsMiddleware_Run(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	userConfig := config.NewEmptyConfig()
+	require.NoError(t, userConfig.Set("notifications", []map[string]any{
+		{"kind": "slack", "url": "https://hooks.contoso.com/user", "failuresOnly": true},
+	}))
+	mockContext.ConfigManager.WithConfig(userConfig)
+
+	payloads := map[string]notify.Payload{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Host == "hooks.contoso.com"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		var payload notify.Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		payloads[request.URL.Path] = payload
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		environment.ResourceGroupEnvVarName:  "rg-dev",
+	})
+	projectConfig := &project.ProjectConfig{
+		Name: "todo",
+		Notifications: []*notify.Target{
+			{Url: "https://hooks.contoso.com/project"},
+		},
+	}
+
+	// The notification of azure.yaml is allowed the first time it's posted
+	confirmed := 0
+	mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+		return options.Message ==
+			"Project 'todo' posts the results of azd commands to the webhook notification at hooks.contoso.com. Allow it?"
+	}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+		confirmed++
+		return true, nil
+	})
+
+	middleware := NewNotificationsMiddleware(
+		&Options{CommandPath: "azd deploy"},
+		&internal.GlobalCommandOptions{},
+		lazy.From(env),
+		lazy.From(projectConfig),
+		config.NewUserConfigManager(mockContext.ConfigManager),
+		notify.NewNotifier(mockContext.HttpClient),
+		cloud.AzurePublic(),
+		mockContext.Console,
+	)
+
+	t.Run("Succeeded", func(t *testing.T) {
+		clear(payloads)
+		result, err := middleware.Run(*mockContext.Context, next)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		require.Len(t, payloads, 1)
+		require.Equal(t, 1, confirmed)
+		payload := payloads["/project"]
+		require.Equal(t, "azd.deploy.succeeded", payload.Event)
+		require.Equal(t, "todo", payload.Project)
+		require.Equal(t, "dev", payload.Environment)
+		require.Equal(t, []notify.Link{{
+			Title: "Open in Azure Portal",
+			Url:   "https://portal.azure.com/#@/resource/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-dev/overview",
+		}}, payload.Links)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		clear(payloads)
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, errors.New("deploying service web failed")
+		})
+		require.ErrorContains(t, err, "deploying service web failed")
+
+		// The failures are also posted to the channel of the user configuration
+		require.Len(t, payloads, 2)
+		require.Equal(t, notify.StatusFailed, payloads["/project"].Status)
+		require.Equal(t, "deploying service web failed", payloads["/project"].Error)
+		require.Contains(t, payloads, "/user")
+
+		// The consent was saved in the user configuration
+		require.Equal(t, 1, confirmed)
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		clear(payloads)
+		otherProject := &project.ProjectConfig{
+			Name: "other",
+			Notifications: []*notify.Target{
+				{Url: "https://hooks.contoso.com/other"},
+			},
+		}
+
+		noPromptMiddleware := NewNotificationsMiddleware(
+			&Options{CommandPath: "azd deploy"},
+			&internal.GlobalCommandOptions{NoPrompt: true},
+			lazy.From(env),
+			lazy.From(otherProject),
+			config.NewUserConfigManager(mockContext.ConfigManager),
+			notify.NewNotifier(mockContext.HttpClient),
+			cloud.AzurePublic(),
+			mockContext.Console,
+		)
+
+		_, err := noPromptMiddleware.Run(*mockContext.Context, next)
+		require.NoError(t, err)
+		require.Empty(t, payloads)
+		require.Contains(t, mockContext.Console.Output(), "WARNING: The webhook notification of azure.yaml to "+
+			"hooks.contoso.com wasn't posted, since it isn't allowed yet. Run 'azd deploy' without --no-prompt to allow it.")
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		clear(payloads)
+		_, err := middleware.Run(WithChildAction(*mockContext.Context), next)
+		require.NoError(t, err)
+		require.Empty(t, payloads)
+	})
+}
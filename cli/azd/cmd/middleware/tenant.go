@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+)
+
+// TenantMiddleware pins the tenant azd acquires tokens for to the tenant set with AZURE_AUTH_TENANT_ID in the
+// environment, ex) when the infrastructure of a CSP partner lives in the tenant of a customer.
+type TenantMiddleware struct {
+	lazyEnv *lazy.Lazy[*environment.Environment]
+}
+
+// Creates a new instance of the tenant middleware
+func NewTenantMiddleware(lazyEnv *lazy.Lazy[*environment.Environment]) Middleware {
+	return &TenantMiddleware{
+		lazyEnv: lazyEnv,
+	}
+}
+
+// Invokes the tenant middleware. Commands run outside of a project, or before the environment exists, use the tenant
+// resolved from the subscription.
+func (m *TenantMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	env, err := m.lazyEnv.GetValue()
+	if err != nil || env == nil {
+		return next(ctx)
+	}
+
+	tenantId := env.Getenv(environment.AuthTenantIdEnvVarName)
+	if tenantId == "" || account.TenantFromContext(ctx) == tenantId {
+		return next(ctx)
+	}
+
+	log.Printf("acquiring tokens for tenant '%s' pinned by environment '%s'", tenantId, env.Name())
+	return next(account.WithTenant(ctx, tenantId))
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TenantMiddleware_Run(t *testing.T) {
+	var tenantId string
+	next := func(ctx context.Context) (*actions.ActionResult, error) {
+		tenantId = account.TenantFromContext(ctx)
+		return &actions.ActionResult{}, nil
+	}
+
+	t.Run("Pinned", func(t *testing.T) {
+		env := environment.NewWithValues("dev", map[string]string{
+			environment.AuthTenantIdEnvVarName: "CUSTOMER_TENANT",
+		})
+		middleware := NewTenantMiddleware(lazy.From(env))
+
+		_, err := middleware.Run(context.Background(), next)
+		require.NoError(t, err)
+		require.Equal(t, "CUSTOMER_TENANT", tenantId)
+	})
+
+	t.Run("NotPinned", func(t *testing.T) {
+		t.Setenv(environment.AuthTenantIdEnvVarName, "")
+		middleware := NewTenantMiddleware(lazy.From(environment.NewWithValues("dev", nil)))
+
+		_, err := middleware.Run(context.Background(), next)
+		require.NoError(t, err)
+		require.Empty(t, tenantId)
+	})
+
+	t.Run("NoEnvironment", func(t *testing.T) {
+		middleware := NewTenantMiddleware(lazy.NewLazy(func() (*environment.Environment, error) {
+			return nil, errors.New("no project")
+		}))
+
+		_, err := middleware.Run(context.Background(), next)
+		require.NoError(t, err)
+		require.Empty(t, tenantId)
+	})
+}
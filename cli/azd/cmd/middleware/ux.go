@@ -5,10 +5,16 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
@@ -17,12 +23,14 @@ import (
 type UxMiddleware struct {
 	options *Options
 	console input.Console
+	writer  io.Writer
 }
 
-func NewUxMiddleware(options *Options, console input.Console) Middleware {
+func NewUxMiddleware(options *Options, console input.Console, writer io.Writer) Middleware {
 	return &UxMiddleware{
 		options: options,
 		console: console,
+		writer:  writer,
 	}
 }
 
@@ -38,17 +46,7 @@ func (m *UxMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionRes
 	m.console.StopSpinner(ctx, "", input.Step)
 
 	if err != nil {
-		var suggestionErr *internal.ErrorWithSuggestion
-		var errorWithTraceId *internal.ErrorWithTraceId
-		m.console.Message(ctx, output.WithErrorFormat("\nERROR: %s", err.Error()))
-
-		if errors.As(err, &errorWithTraceId) {
-			m.console.Message(ctx, output.WithErrorFormat("TraceID: %s", errorWithTraceId.TraceId))
-		}
-
-		if errors.As(err, &suggestionErr) {
-			m.console.Message(ctx, suggestionErr.Suggestion)
-		}
+		m.displayError(ctx, err)
 	}
 
 	if actionResult != nil && actionResult.Message != nil {
@@ -62,3 +60,54 @@ func (m *UxMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionRes
 
 	return actionResult, err
 }
+
+// displayError displays the error with its trace id and a suggestion to resolve it, which defaults to the remediation of
+// the category of the error. With JSON output, the error is written as a structured event including its category and
+// machine-readable code.
+func (m *UxMiddleware) displayError(ctx context.Context, err error) {
+	consoleError := contracts.ConsoleError{
+		Message: err.Error(),
+	}
+
+	var errorWithTraceId *internal.ErrorWithTraceId
+	if errors.As(err, &errorWithTraceId) {
+		consoleError.TraceId = errorWithTraceId.TraceId
+	}
+
+	categorized := cmd.ClassifyError(err)
+	if categorized != nil {
+		consoleError.Category = string(categorized.Category)
+		consoleError.Code = categorized.Code
+	}
+
+	var suggestionErr *internal.ErrorWithSuggestion
+	if errors.As(err, &suggestionErr) {
+		consoleError.Suggestion = suggestionErr.Suggestion
+	} else if categorized != nil {
+		consoleError.Suggestion = categorized.Category.Remediation()
+	}
+
+	if formatter := m.console.GetFormatter(); formatter != nil && formatter.Kind() == output.JsonFormat {
+		jsonError, marshalErr := json.Marshal(output.EventForError(consoleError))
+		if marshalErr != nil {
+			log.Printf("failed marshaling error: %v", marshalErr)
+		} else {
+			fmt.Fprintln(m.writer, string(jsonError))
+			return
+		}
+	}
+
+	m.console.Message(ctx, output.WithErrorFormat("\nERROR: %s", consoleError.Message))
+
+	if consoleError.TraceId != "" {
+		m.console.Message(ctx, output.WithErrorFormat("TraceID: %s", consoleError.TraceId))
+	}
+
+	if consoleError.Suggestion != "" {
+		if suggestionErr == nil {
+			m.console.Message(ctx, fmt.Sprintf("Suggestion: %s", consoleError.Suggestion))
+		} else {
+			m.console.Message(ctx, consoleError.Suggestion)
+		}
+	}
+}
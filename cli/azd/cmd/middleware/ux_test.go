@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonConsole is a mock console with JSON output enabled
+type jsonConsole struct {
+	*mockinput.MockConsole
+}
+
+func (c *jsonConsole) GetFormatter() output.Formatter {
+	return &output.JsonFormatter{}
+}
+
+func Test_UxMiddleware_Error(t *testing.T) {
+	notLoggedIn := func(ctx context.Context) (*actions.ActionResult, error) {
+		return nil, fmt.Errorf("getting credential: %w", auth.ErrNoCurrentUser)
+	}
+
+	t.Run("CategoryRemediation", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewUxMiddleware(&Options{}, mockContext.Console, &bytes.Buffer{})
+
+		_, err := middleware.Run(*mockContext.Context, notLoggedIn)
+		require.Error(t, err)
+
+		output := strings.Join(mockContext.Console.Output(), "\n")
+		require.Contains(t, output, "ERROR: getting credential: not logged in")
+		require.Contains(t, output, "Suggestion: "+internal.ErrorCategoryAuth.Remediation())
+	})
+
+	t.Run("ExplicitSuggestion", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewUxMiddleware(&Options{}, mockContext.Console, &bytes.Buffer{})
+
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err:        auth.ErrNoCurrentUser,
+				Suggestion: "Suggestion: log in with a service principal.",
+			}
+		})
+		require.Error(t, err)
+
+		output := strings.Join(mockContext.Console.Output(), "\n")
+		require.Contains(t, output, "Suggestion: log in with a service principal.")
+		require.NotContains(t, output, internal.ErrorCategoryAuth.Remediation())
+	})
+
+	t.Run("Json", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		console := &jsonConsole{MockConsole: mockContext.Console}
+		writer := &bytes.Buffer{}
+		middleware := NewUxMiddleware(&Options{}, console, writer)
+
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, &internal.ErrorWithTraceId{
+				TraceId: "TRACE_ID",
+				Err:     fmt.Errorf("getting credential: %w", auth.ErrNoCurrentUser),
+			}
+		})
+		require.Error(t, err)
+		require.Empty(t, mockContext.Console.Output())
+
+		var event struct {
+			Type contracts.EventDataType `json:"type"`
+			Data contracts.ConsoleError  `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(writer.Bytes(), &event))
+		require.Equal(t, contracts.ConsoleErrorEventDataType, event.Type)
+		require.Equal(t, contracts.ConsoleError{
+			Message:    "getting credential: not logged in, run `azd auth login` to login",
+			Category:   "auth",
+			Code:       "auth.notLoggedIn",
+			Suggestion: internal.ErrorCategoryAuth.Remediation(),
+			TraceId:    "TRACE_ID",
+		}, event.Data)
+	})
+
+	t.Run("Uncategorized", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		middleware := NewUxMiddleware(&Options{}, mockContext.Console, &bytes.Buffer{})
+
+		_, err := middleware.Run(*mockContext.Context, func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, errors.New("something bad happened")
+		})
+		require.Error(t, err)
+
+		output := strings.Join(mockContext.Console.Output(), "\n")
+		require.Contains(t, output, "ERROR: something bad happened")
+		require.NotContains(t, output, "Suggestion")
+	})
+}
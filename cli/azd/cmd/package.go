@@ -7,11 +7,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -25,7 +28,8 @@ type packageFlags struct {
 	all    bool
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
-	outputPath string
+	outputPath   string
+	outputFormat string
 }
 
 func newPackageFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *packageFlags {
@@ -52,7 +56,16 @@ func (pf *packageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 		&pf.outputPath,
 		"output-path",
 		"",
-		"File or folder path where the generated packages will be saved.",
+		"File or folder path where the generated packages will be saved. "+
+			"With '--output-format oci', the oci://<registry>/<repository>[:<tag>] the packages are published to.",
+	)
+	local.StringVar(
+		&pf.outputFormat,
+		"output-format",
+		"",
+		"The format of the generated packages. Use 'oci' to publish the packages to an OCI registry, "+
+			"ex) an Azure Container Registry, to be deployed with 'azd deploy --from-package oci://...'. "+
+			"When all the services are packaged, the infrastructure of the project is published too.",
 	)
 }
 
@@ -66,15 +79,17 @@ func newPackageCmd() *cobra.Command {
 }
 
 type packageAction struct {
-	flags          *packageFlags
-	args           []string
-	projectConfig  *project.ProjectConfig
-	projectManager project.ProjectManager
-	importManager  *project.ImportManager
-	serviceManager project.ServiceManager
-	console        input.Console
-	formatter      output.Formatter
-	writer         io.Writer
+	flags            *packageFlags
+	args             []string
+	projectConfig    *project.ProjectConfig
+	projectManager   project.ProjectManager
+	importManager    *project.ImportManager
+	serviceManager   project.ServiceManager
+	console          input.Console
+	formatter        output.Formatter
+	writer           io.Writer
+	env              *environment.Environment
+	packageArtifacts *project.PackageArtifacts
 }
 
 func newPackageAction(
@@ -87,23 +102,83 @@ func newPackageAction(
 	formatter output.Formatter,
 	writer io.Writer,
 	importManager *project.ImportManager,
+	env *environment.Environment,
+	packageArtifacts *project.PackageArtifacts,
 ) actions.Action {
 	return &packageAction{
-		flags:          flags,
-		args:           args,
-		projectConfig:  projectConfig,
-		projectManager: projectManager,
-		serviceManager: serviceManager,
-		console:        console,
-		formatter:      formatter,
-		writer:         writer,
-		importManager:  importManager,
+		flags:            flags,
+		args:             args,
+		projectConfig:    projectConfig,
+		projectManager:   projectManager,
+		serviceManager:   serviceManager,
+		console:          console,
+		formatter:        formatter,
+		writer:           writer,
+		importManager:    importManager,
+		env:              env,
+		packageArtifacts: packageArtifacts,
+	}
+}
+
+// The format of packages published to an OCI registry
+const packageOutputFormatOci = "oci"
+
+// artifactReference returns the reference packages are published to with '--output-format oci', which defaults to a
+// repository named after the project in the container registry of the environment. References without a tag get the
+// default tag, shared by all the packages published by the command.
+func (pa *packageAction) artifactReference() (*containerregistry.ArtifactReference, error) {
+	switch pa.flags.outputFormat {
+	case "":
+		return nil, nil
+	case packageOutputFormatOci:
+	default:
+		return nil, fmt.Errorf(
+			"invalid value '%s' for '--output-format', the supported value is '%s'",
+			pa.flags.outputFormat, packageOutputFormatOci)
+	}
+
+	if pa.flags.outputPath != "" {
+		if !strings.HasPrefix(pa.flags.outputPath, containerregistry.ArtifactScheme) {
+			return nil, fmt.Errorf(
+				"'--output-path' must be an %s<registry>/<repository>[:<tag>] reference with '--output-format %s'",
+				containerregistry.ArtifactScheme, packageOutputFormatOci)
+		}
+
+		reference, err := containerregistry.ParseArtifactReference(pa.flags.outputPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if reference.Reference == "" {
+			reference.Reference = pa.packageArtifacts.DefaultTag()
+		}
+
+		return reference, nil
+	}
+
+	registry := pa.env.Getenv(environment.ContainerRegistryEndpointEnvVarName)
+	if registry == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("no registry to publish the packages to, '%s' is not set",
+				environment.ContainerRegistryEndpointEnvVarName),
+			Suggestion: fmt.Sprintf(
+				"Set '--output-path %s<registry>/<repository>[:<tag>]', or provision a container registry first.",
+				containerregistry.ArtifactScheme),
+		}
 	}
+
+	return &containerregistry.ArtifactReference{
+		Registry:   registry,
+		Repository: strings.ToLower(pa.projectConfig.Name),
+		Reference:  pa.packageArtifacts.DefaultTag(),
+	}, nil
 }
 
 type PackageResult struct {
 	Timestamp time.Time                                `json:"timestamp"`
 	Services  map[string]*project.ServicePackageResult `json:"services"`
+	// The oci:// reference of the infrastructure as code published with the packages of all the services
+	Infra string `json:"infra,omitempty"`
 }
 
 func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error) {
@@ -114,12 +189,17 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	startTime := time.Now()
 
+	artifactReference, err := pa.artifactReference()
+	if err != nil {
+		return nil, err
+	}
+
 	targetServiceName := ""
 	if len(pa.args) == 1 {
 		targetServiceName = pa.args[0]
 	}
 
-	targetServiceName, err := getTargetServiceName(
+	targetServiceName, err = getTargetServiceName(
 		ctx,
 		pa.projectManager,
 		pa.importManager,
@@ -196,13 +276,23 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 
 		options := &project.PackageOptions{OutputPath: pa.flags.outputPath}
+		if artifactReference != nil {
+			options = nil
+		}
+
 		packageResult, err := async.RunWithProgress(
 			func(packageProgress project.ServiceProgress) {
 				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
 				pa.console.ShowSpinner(ctx, progressMessage, input.Step)
 			},
 			func(progress *async.Progress[project.ServiceProgress]) (*project.ServicePackageResult, error) {
-				return pa.serviceManager.Package(ctx, svc, nil, progress, options)
+				packageResult, err := pa.serviceManager.Package(ctx, svc, nil, progress, options)
+				if err != nil || artifactReference == nil {
+					return packageResult, err
+				}
+
+				progress.SetProgress(project.NewServiceProgress("Publishing package"))
+				return pa.packageArtifacts.Publish(ctx, svc, packageResult, artifactReference)
 			},
 		)
 		pa.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
@@ -219,10 +309,33 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 	}
 
+	// The infrastructure is published along with the packages of all the services, so that the whole application can
+	// be promoted to other environments
+	var infraReference string
+	if artifactReference != nil && targetServiceName == "" {
+		stepMessage := "Publishing infrastructure"
+		pa.console.Message(ctx, "")
+		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		infraReference, err = pa.packageArtifacts.PublishInfra(ctx, pa.projectConfig, artifactReference)
+		if err != nil {
+			pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, err
+		}
+
+		if infraReference == "" {
+			pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+		} else {
+			pa.console.StopSpinner(ctx, stepMessage, input.StepDone)
+			pa.console.Message(ctx, fmt.Sprintf("  - Infra: %s", output.WithLinkFormat(infraReference)))
+		}
+	}
+
 	if pa.formatter.Kind() == output.JsonFormat {
 		packageResult := PackageResult{
 			Timestamp: time.Now(),
 			Services:  packageResults,
+			Infra:     infraReference,
 		}
 
 		if fmtErr := pa.formatter.Format(packageResult, pa.writer, nil); fmtErr != nil {
@@ -259,6 +372,9 @@ func getCmdPackageHelpFooter(*cobra.Command) string {
 		"Packages all services to the specified output path.": output.WithHighLightFormat(
 			"azd package --output-path ./dist",
 		),
+		"Publishes the packages of all services to a container registry.": output.WithHighLightFormat(
+			"azd package --all --output-format oci --output-path oci://myregistry.azurecr.io/myapp:v1",
+		),
 		"Packages the service named 'api' to the specified output path.": output.WithHighLightFormat(
 			"azd package api --output-path ./dist/api.zip",
 		),
@@ -6,6 +6,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -71,6 +73,13 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 			"This value must be a Universally Unique Identifier (UUID). "+
 			"You can set this value globally by running "+
 			"azd config set pipeline.config.applicationServiceManagementReference <UUID>.")
+	local.BoolVar(
+		&pc.PrintVariables,
+		"print-variables",
+		false,
+		"Prints the variables and secrets the pipeline needs, without configuring the repository, the pipeline or its "+
+			"identity. The pipeline provider defaults to GitHub Actions when it can't be detected.",
+	)
 	pc.EnvFlag.Bind(local, global)
 	pc.global = global
 }
@@ -101,6 +110,26 @@ func pipelineActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		},
 	})
 
+	agentGroup := group.Add("agent", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "agent",
+			Short: "Manage the self-hosted agents running your deployment pipelines.",
+		},
+	})
+
+	agentGroup.Add("bootstrap", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "bootstrap",
+			Short: "Provision a self-hosted agent for the deployment pipeline of the project.",
+		},
+		FlagsResolver:  newPipelineAgentBootstrapFlags,
+		ActionResolver: newPipelineAgentBootstrapAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdPipelineAgentBootstrapHelpDescription,
+			Footer:      getCmdPipelineAgentBootstrapHelpFooter,
+		},
+	})
+
 	return group
 }
 
@@ -172,8 +201,12 @@ func (p *pipelineConfigAction) Run(ctx context.Context) (*actions.ActionResult,
 	pipelineProviderName := p.manager.CiProviderName()
 
 	// Command title
+	title := fmt.Sprintf("Configure your %s pipeline", pipelineProviderName)
+	if p.flags.PrintVariables {
+		title = fmt.Sprintf("Variables of your %s pipeline", pipelineProviderName)
+	}
 	p.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: fmt.Sprintf("Configure your %s pipeline", pipelineProviderName),
+		Title: title,
 	})
 
 	// Pull provider specific parameters
@@ -183,6 +216,10 @@ func (p *pipelineConfigAction) Run(ctx context.Context) (*actions.ActionResult,
 	}
 	p.manager.SetParameters(providerParameters)
 
+	if p.flags.PrintVariables {
+		return p.printVariables(ctx, infra)
+	}
+
 	pipelineResult, err := p.manager.Configure(ctx, p.projectConfig.Name, infra)
 	if err != nil {
 		return nil, err
@@ -200,6 +237,128 @@ func (p *pipelineConfigAction) Run(ctx context.Context) (*actions.ActionResult,
 	}, nil
 }
 
+// printVariables prints the variables and secrets of the pipeline, for the pipelines which are configured manually.
+func (p *pipelineConfigAction) printVariables(ctx context.Context, infra *project.Infra) (*actions.ActionResult, error) {
+	variables, err := p.manager.Variables(ctx, infra)
+	if err != nil {
+		return nil, err
+	}
+
+	p.console.Message(ctx, output.WithBold("Variables"))
+	for _, name := range slices.Sorted(maps.Keys(variables.Variables)) {
+		value := variables.Variables[name]
+		if value == "" {
+			value = output.WithGrayFormat("(not configured)")
+		}
+
+		p.console.Message(ctx, fmt.Sprintf("  %s=%s", name, value))
+	}
+
+	if len(variables.Secrets) > 0 {
+		p.console.Message(ctx, "")
+		p.console.Message(ctx, output.WithBold("Secrets"))
+		for _, name := range variables.Secrets {
+			p.console.Message(ctx, fmt.Sprintf("  %s", name))
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Set these variables and secrets on your %s pipeline, with the values of the secrets from the "+
+					"'%s' environment.", variables.Provider, p.env.Name()),
+			FollowUp: fmt.Sprintf("Run %s to create the identity of the pipeline and set them automatically.",
+				output.WithHighLightFormat("azd pipeline config")),
+		},
+	}, nil
+}
+
+type pipelineAgentBootstrapFlags struct {
+	pipeline.AgentBootstrapArgs
+	internal.EnvFlag
+}
+
+func (f *pipelineAgentBootstrapFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.Provider, "provider", "",
+		"The pipeline provider of the agent (github for Github Actions and azdo for Azure Pipelines).")
+	local.StringVar(
+		&f.Kind,
+		"kind",
+		"",
+		fmt.Sprintf("The kind of agent (%s for Azure Pipelines and %s for GitHub Actions).",
+			pipeline.AgentKindManagedPool, pipeline.AgentKindContainer),
+	)
+	local.StringVar(&f.Name, "name", "",
+		"The name of the agent pool, or the label of the runners. Defaults to azd-<environment name>.")
+	local.StringVar(&f.ResourceGroup, "resource-group", "",
+		"The resource group of the agent. Defaults to rg-<environment name>-agent.")
+	f.EnvFlag.Bind(local, global)
+}
+
+func newPipelineAgentBootstrapFlags(
+	cmd *cobra.Command,
+	global *internal.GlobalCommandOptions,
+) *pipelineAgentBootstrapFlags {
+	flags := &pipelineAgentBootstrapFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type pipelineAgentBootstrapAction struct {
+	flags         *pipelineAgentBootstrapFlags
+	bootstrapper  *pipeline.AgentBootstrapper
+	console       input.Console
+	projectConfig *project.ProjectConfig
+}
+
+func newPipelineAgentBootstrapAction(
+	flags *pipelineAgentBootstrapFlags,
+	bootstrapper *pipeline.AgentBootstrapper,
+	console input.Console,
+	projectConfig *project.ProjectConfig,
+) actions.Action {
+	return &pipelineAgentBootstrapAction{
+		flags:         flags,
+		bootstrapper:  bootstrapper,
+		console:       console,
+		projectConfig: projectConfig,
+	}
+}
+
+func (p *pipelineAgentBootstrapAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	p.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     "Provision a self-hosted agent for your pipeline (azd pipeline agent bootstrap)",
+		TitleNote: "Provisions the agent and saves it in azure.yaml for the pipelines generated by 'azd pipeline config'",
+	})
+
+	result, err := p.bootstrapper.Bootstrap(ctx, p.projectConfig, p.flags.AgentBootstrapArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	followUp := fmt.Sprintf("Run %s to generate and configure a pipeline running on the agent.",
+		output.WithHighLightFormat("azd pipeline config"))
+	if result.PipelineFile != "" {
+		target := fmt.Sprintf("runs-on: [self-hosted, %s]", result.Agent.Name)
+		if result.Agent.Kind == pipeline.AgentKindManagedPool {
+			target = fmt.Sprintf("pool: { name: %s }", result.Agent.Name)
+		}
+
+		followUp = fmt.Sprintf("Update %s with %s to run your existing pipeline on the agent.",
+			output.WithHighLightFormat(result.PipelineFile),
+			output.WithHighLightFormat(target))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your %s agent %s is ready in resource group %s.",
+				result.Agent.Kind, result.Agent.Name, result.ResourceGroup),
+			FollowUp: followUp,
+		},
+	}, nil
+}
+
 func getCmdPipelineHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf("Manage integrating your application with deployment pipelines. %s", output.WithWarningFormat("(Beta)")),
@@ -259,5 +418,38 @@ func getCmdPipelineConfigHelpFooter(c *cobra.Command) string {
 			output.WithWarningFormat("app-test"),
 			output.WithHighLightFormat("--provider azdo"),
 		),
+		"Print the variables and secrets of the deployment pipeline, to configure it manually.": output.WithHighLightFormat(
+			"azd pipeline config --print-variables",
+		),
+	})
+}
+
+func getCmdPipelineAgentBootstrapHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Provision a self-hosted agent for the deployment pipeline of the project, for organizations which can't use "+
+			"the hosted agents of the pipeline providers.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"Azure Pipelines run on a Managed DevOps Pool (%s), and GitHub Actions on ephemeral runners started by "+
+					"an Azure Container Apps job (%s).",
+				output.WithHighLightFormat(pipeline.AgentKindManagedPool),
+				output.WithHighLightFormat(pipeline.AgentKindContainer))),
+			formatHelpNote(fmt.Sprintf(
+				"The infrastructure of the agent is generated in %s, and deployed again with your changes by the next "+
+					"runs.", output.WithHighLightFormat(pipeline.AgentInfraDirectory))),
+			formatHelpNote(fmt.Sprintf(
+				"The agent is saved in azure.yaml, and used by the pipelines generated by %s.",
+				output.WithHighLightFormat("azd pipeline config"))),
+		})
+}
+
+func getCmdPipelineAgentBootstrapHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Provision a Managed DevOps Pool for Azure Pipelines.": output.WithHighLightFormat(
+			"azd pipeline agent bootstrap --provider azdo"),
+		"Provision container runners labeled 'build' for GitHub Actions.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd pipeline agent bootstrap --provider github --name"),
+			output.WithWarningFormat("build"),
+		),
 	})
 }
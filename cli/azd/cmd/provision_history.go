@@ -0,0 +1,279 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// provisionHistoryActions registers the 'azd provision history' commands under the provision command.
+func provisionHistoryActions(provision *actions.ActionDescriptor) {
+	history := provision.Add("history", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "history",
+			Short: "List the previous deployments of the current environment.",
+			Args:  cobra.NoArgs,
+		},
+		FlagsResolver:  newProvisionHistoryFlags,
+		ActionResolver: newProvisionHistoryListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		RequireLogin:   true,
+	})
+
+	history.Add("show", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "show <id>",
+			Short: "Show the details of a previous deployment, including its outputs.",
+			Args:  cobra.ExactArgs(1),
+		},
+		FlagsResolver:  newProvisionHistoryFlags,
+		ActionResolver: newProvisionHistoryShowAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		RequireLogin:   true,
+	})
+}
+
+type provisionHistoryFlags struct {
+	global *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func newProvisionHistoryFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *provisionHistoryFlags {
+	flags := &provisionHistoryFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *provisionHistoryFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+// provisionHistory loads the deployment history of the current environment from the configured provider.
+type provisionHistory struct {
+	provisionManager *provisioning.Manager
+	projectConfig    *project.ProjectConfig
+	importManager    *project.ImportManager
+	env              *environment.Environment
+	console          input.Console
+}
+
+func newProvisionHistory(
+	provisionManager *provisioning.Manager,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	env *environment.Environment,
+	console input.Console,
+) *provisionHistory {
+	return &provisionHistory{
+		provisionManager: provisionManager,
+		projectConfig:    projectConfig,
+		importManager:    importManager,
+		env:              env,
+		console:          console,
+	}
+}
+
+func (h *provisionHistory) load(ctx context.Context) ([]*provisioning.DeploymentHistoryEntry, error) {
+	infra, err := h.importManager.ProjectInfrastructure(ctx, h.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	if err := h.provisionManager.Initialize(ctx, h.projectConfig.Path, infra.Options); err != nil {
+		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+	}
+
+	spinnerMessage := "Retrieving deployment history"
+	h.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	history, err := h.provisionManager.History(ctx)
+	h.console.StopSpinner(ctx, "", input.Step)
+
+	if errors.Is(err, provisioning.ErrHistoryNotSupported) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: "Deployment history is available for Bicep, ARM and Dev Center environments. " +
+				"For Terraform, use the history of your state backend.",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+type provisionHistoryListAction struct {
+	history   *provisionHistory
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newProvisionHistoryListAction(
+	provisionManager *provisioning.Manager,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &provisionHistoryListAction{
+		history:   newProvisionHistory(provisionManager, projectConfig, importManager, env, console),
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *provisionHistoryListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	history, err := a.history.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		if len(history) == 0 {
+			a.history.console.Message(
+				ctx, fmt.Sprintf("No deployments found for environment %s.", a.history.env.Name()))
+			return nil, nil
+		}
+
+		columns := []output.Column{
+			{
+				Heading:       "TIMESTAMP",
+				ValueTemplate: `{{.Timestamp.Local.Format "2006-01-02 15:04:05"}}`,
+			},
+			{
+				Heading:       "STATUS",
+				ValueTemplate: "{{.Status}}",
+			},
+			{
+				Heading:       "DURATION",
+				ValueTemplate: "{{.Duration}}",
+			},
+			{
+				Heading:       "ID",
+				ValueTemplate: "{{.Id}}",
+			},
+			{
+				Heading:       "CORRELATION ID",
+				ValueTemplate: "{{.CorrelationId}}",
+			},
+			{
+				Heading:       "INITIATOR",
+				ValueTemplate: "{{.Initiator}}",
+			},
+			{
+				Heading:       "GIT SHA",
+				ValueTemplate: "{{.GitSha}}",
+			},
+		}
+
+		return nil, a.formatter.Format(history, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	}
+
+	return nil, a.formatter.Format(history, a.writer, nil)
+}
+
+type provisionHistoryShowAction struct {
+	history   *provisionHistory
+	args      []string
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newProvisionHistoryShowAction(
+	provisionManager *provisioning.Manager,
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &provisionHistoryShowAction{
+		history:   newProvisionHistory(provisionManager, projectConfig, importManager, env, console),
+		args:      args,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (a *provisionHistoryShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	history, err := a.history.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := a.args[0]
+	index := slices.IndexFunc(history, func(entry *provisioning.DeploymentHistoryEntry) bool {
+		return strings.EqualFold(entry.Id, id)
+	})
+	if index < 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("deployment '%s' was not found for environment '%s'", id, a.history.env.Name()),
+			Suggestion: fmt.Sprintf(
+				"Run %s to list the deployments of the environment.", output.WithHighLightFormat("azd provision history")),
+		}
+	}
+
+	entry := history[index]
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(entry, a.writer, nil)
+	}
+
+	lines := []string{
+		fmt.Sprintf("Deployment: %s", output.WithHighLightFormat(entry.Id)),
+		fmt.Sprintf("Timestamp: %s", entry.Timestamp.Local().Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("Status: %s", entry.Status),
+	}
+
+	optional := []struct{ label, value string }{
+		{"Duration", entry.Duration},
+		{"Correlation ID", entry.CorrelationId},
+		{"Initiator", entry.Initiator},
+		{"Git SHA", entry.GitSha},
+		{"Portal", entry.PortalUrl},
+	}
+	for _, field := range optional {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", field.label, field.value))
+		}
+	}
+
+	if len(entry.Outputs) > 0 {
+		lines = append(lines, "", "Outputs:")
+		for _, name := range slices.Sorted(maps.Keys(entry.Outputs)) {
+			lines = append(lines, fmt.Sprintf("  %s: %v", name, entry.Outputs[name].Value))
+		}
+	} else {
+		lines = append(lines, "", output.WithGrayFormat("No outputs were recorded for this deployment."))
+	}
+
+	a.history.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: lines})
+	return nil, nil
+}
@@ -103,6 +103,19 @@ func NewRootCmd(
 					"no-prompt",
 					false,
 					"Accepts the default value instead of prompting, or it fails if there is no default.")
+			rootCmd.PersistentFlags().
+				BoolVar(
+					&opts.NoAutoInstall,
+					"no-auto-install",
+					false,
+					"Skips installing or upgrading the extensions required by the project.")
+			rootCmd.PersistentFlags().
+				BoolVar(
+					&opts.ListPrompts,
+					"list-prompts",
+					false,
+					"Lists the prompts of the command which can be answered with environment variables, and exits. "+
+						"Not all prompts can be answered this way yet, ex) the ones of 'azd add'.")
 
 			// The telemetry system is responsible for reading these flags value and using it to configure the telemetry
 			// system, but we still need to add it to our flag set so that when we parse the command line with Cobra we
@@ -129,6 +142,8 @@ func NewRootCmd(
 	templatesActions(root)
 	authActions(root)
 	hooksActions(root)
+	devcontainerActions(root)
+	xActions(root)
 
 	root.Add("version", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
@@ -163,6 +178,21 @@ func NewRootCmd(
 		},
 	})
 
+	root.Add("doctor", &actions.ActionDescriptorOptions{
+		Command:        newDoctorCmd(),
+		FlagsResolver:  newDoctorFlags,
+		ActionResolver: newDoctorAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDoctorHelpDescription,
+			Footer:      getCmdDoctorHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupManage,
+		},
+	})
+
 	//deprecate:cmd hide login
 	login := newLoginCmd("")
 	login.Hidden = true
@@ -186,6 +216,8 @@ func NewRootCmd(
 		Command:        newInitCmd(),
 		FlagsResolver:  newInitFlags,
 		ActionResolver: newInitAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
 		HelpOptions: actions.ActionHelpOptions{
 			Description: getCmdInitHelpDescription,
 			Footer:      getCmdInitHelpFooter,
@@ -224,7 +256,7 @@ func NewRootCmd(
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
 		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
 
-	root.
+	provision := root.
 		Add("provision", &actions.ActionDescriptorOptions{
 			Command:        cmd.NewProvisionCmd(),
 			FlagsResolver:  cmd.NewProvisionFlags,
@@ -241,20 +273,41 @@ func NewRootCmd(
 			RequireLogin: true,
 		}).
 		UseMiddlewareWhen("hooks", middleware.NewHooksMiddleware, func(descriptor *actions.ActionDescriptor) bool {
-			if onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview"); onPreview {
-				log.Println("Skipping provision hooks due to preview flag.")
+			// Provision hooks don't apply to sub commands like 'provision history'
+			if descriptor.Name != "provision" {
+				return false
+			}
+			onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview")
+			onWhatChanged, _ := descriptor.Options.Command.Flags().GetBool("what-changed")
+			if onPreview || onWhatChanged {
+				log.Println("Skipping provision hooks due to preview or what-changed flag.")
 				return false
 			}
 			return true
 		}).
 		UseMiddlewareWhen("extensions", middleware.NewExtensionsMiddleware, func(descriptor *actions.ActionDescriptor) bool {
-			if onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview"); onPreview {
-				log.Println("Skipping provision hooks due to preview flag.")
+			if descriptor.Name != "provision" {
+				return false
+			}
+			onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview")
+			onWhatChanged, _ := descriptor.Options.Command.Flags().GetBool("what-changed")
+			if onPreview || onWhatChanged {
+				log.Println("Skipping provision hooks due to preview or what-changed flag.")
 				return false
 			}
 			return true
+		}).
+		UseMiddlewareWhen("notifications", middleware.NewNotificationsMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			if descriptor.Name != "provision" {
+				return false
+			}
+			onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview")
+			onWhatChanged, _ := descriptor.Options.Command.Flags().GetBool("what-changed")
+			return !onPreview && !onWhatChanged
 		})
 
+	provisionHistoryActions(provision)
+
 	root.
 		Add("package", &actions.ActionDescriptorOptions{
 			Command:        newPackageCmd(),
@@ -290,7 +343,8 @@ func NewRootCmd(
 			RequireLogin: true,
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
-		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
+		UseMiddleware("extensions", middleware.NewExtensionsMiddleware).
+		UseMiddleware("notifications", middleware.NewNotificationsMiddleware)
 
 	root.
 		Add("up", &actions.ActionDescriptorOptions{
@@ -308,7 +362,22 @@ func NewRootCmd(
 			RequireLogin: true,
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
-		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
+		UseMiddleware("extensions", middleware.NewExtensionsMiddleware).
+		UseMiddleware("notifications", middleware.NewNotificationsMiddleware)
+
+	root.Add("dev", &actions.ActionDescriptorOptions{
+		Command:        newDevCmd(),
+		FlagsResolver:  newDevFlags,
+		ActionResolver: newDevAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDevHelpDescription,
+			Footer:      getCmdDevHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+		RequireLogin: true,
+	})
 
 	root.Add("monitor", &actions.ActionDescriptorOptions{
 		Command:        newMonitorCmd(),
@@ -340,7 +409,8 @@ func NewRootCmd(
 			RequireLogin: true,
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware).
-		UseMiddleware("extensions", middleware.NewExtensionsMiddleware)
+		UseMiddleware("extensions", middleware.NewExtensionsMiddleware).
+		UseMiddleware("notifications", middleware.NewNotificationsMiddleware)
 	root.
 		Add("add", &actions.ActionDescriptorOptions{
 			Command:        add.NewAddCmd(),
@@ -361,9 +431,11 @@ func NewRootCmd(
 	root.
 		UseMiddleware("debug", middleware.NewDebugMiddleware).
 		UseMiddleware("ux", middleware.NewUxMiddleware).
+		UseMiddleware("listPrompts", middleware.NewListPromptsMiddleware).
 		UseMiddlewareWhen("telemetry", middleware.NewTelemetryMiddleware, func(descriptor *actions.ActionDescriptor) bool {
 			return !descriptor.Options.DisableTelemetry
 		}).
+		UseMiddleware("tenant", middleware.NewTenantMiddleware).
 		UseMiddlewareWhen("loginGuard", middleware.NewLoginGuardMiddleware, func(descriptor *actions.ActionDescriptor) bool {
 			// Check if the command or any of its parents require login
 			current := descriptor
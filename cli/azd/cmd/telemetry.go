@@ -4,26 +4,58 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const TelemetryCommandFlag = "telemetry"
 const TelemetryUploadCommandFlag = "upload"
+const TelemetryShowCommandFlag = "show"
 
 func telemetryActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	group := root.Add(TelemetryCommandFlag, &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
-			Short:  "Manage telemetry",
-			Long:   "Manage telemetry",
-			Hidden: true,
+			Short: "Manage telemetry.",
+			Long: "Manage the telemetry collected by azd.\n\n" +
+				"The level of the telemetry is set by the AZURE_DEV_COLLECT_TELEMETRY environment variable or the " +
+				output.WithHighLightFormat(telemetry.LevelConfigPath) + " user config, to one of: " +
+				strings.Join([]string{
+					string(telemetry.LevelOff),
+					string(telemetry.LevelErrorsOnly),
+					string(telemetry.LevelUsage),
+					string(telemetry.LevelFull),
+				}, ", ") + ". The fields removed from the telemetry are set by the " +
+				output.WithHighLightFormat(telemetry.ScrubConfigPath) + " user config, ex) [\"env.name\", \"error.*\"].",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupManage,
 		},
 	})
 
+	group.Add(TelemetryShowCommandFlag, &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Short: "Show the telemetry settings and the telemetry waiting to be uploaded.",
+			Example: `$ azd telemetry show
+$ azd telemetry show --pending`,
+		},
+		FlagsResolver:  newTelemetryShowFlags,
+		ActionResolver: newTelemetryShowAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
 	group.Add(TelemetryUploadCommandFlag, &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
 			Short:  "Upload telemetry",
@@ -56,3 +88,103 @@ func (a *uploadAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	return nil, telemetrySystem.RunBackgroundUpload(ctx, a.rootOptions.EnableDebugLogging)
 }
+
+type telemetryShowFlags struct {
+	pending bool
+	global  *internal.GlobalCommandOptions
+}
+
+func (f *telemetryShowFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.pending,
+		"pending",
+		false,
+		"Shows the exact telemetry events queued on this machine, as they will be uploaded.",
+	)
+	f.global = global
+}
+
+func newTelemetryShowFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *telemetryShowFlags {
+	flags := &telemetryShowFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+// telemetryShowResult is the result of 'azd telemetry show'
+type telemetryShowResult struct {
+	Level          telemetry.Level   `json:"level"`
+	ScrubbedFields []string          `json:"scrubbedFields"`
+	Pending        []json.RawMessage `json:"pending,omitempty"`
+}
+
+type telemetryShowAction struct {
+	flags     *telemetryShowFlags
+	formatter output.Formatter
+	writer    io.Writer
+	console   input.Console
+}
+
+func newTelemetryShowAction(
+	flags *telemetryShowFlags,
+	formatter output.Formatter,
+	writer io.Writer,
+	console input.Console,
+) actions.Action {
+	return &telemetryShowAction{
+		flags:     flags,
+		formatter: formatter,
+		writer:    writer,
+		console:   console,
+	}
+}
+
+func (a *telemetryShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	result := telemetryShowResult{
+		Level:          telemetry.CurrentLevel(),
+		ScrubbedFields: telemetry.ScrubbedFields(),
+	}
+
+	if a.flags.pending {
+		items, err := telemetry.PendingItems()
+		if err != nil {
+			return nil, fmt.Errorf("reading the pending telemetry: %w", err)
+		}
+
+		// Each item holds a batch of events, one per line
+		result.Pending = []json.RawMessage{}
+		for _, item := range items {
+			for _, line := range bytes.Split(item.Message(), []byte("\n")) {
+				if len(bytes.TrimSpace(line)) > 0 {
+					result.Pending = append(result.Pending, json.RawMessage(line))
+				}
+			}
+		}
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(result, a.writer, nil)
+	}
+
+	scrubbed := "none"
+	if len(result.ScrubbedFields) > 0 {
+		scrubbed = strings.Join(result.ScrubbedFields, ", ")
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("Level: %s", output.WithHighLightFormat(string(result.Level))))
+	a.console.Message(ctx, fmt.Sprintf("Scrubbed fields: %s", scrubbed))
+
+	if a.flags.pending {
+		a.console.Message(ctx, fmt.Sprintf("\nPending events: %d", len(result.Pending)))
+		for _, event := range result.Pending {
+			indented := bytes.Buffer{}
+			if err := json.Indent(&indented, event, "", "  "); err != nil {
+				return nil, fmt.Errorf("formatting the pending telemetry: %w", err)
+			}
+
+			a.console.Message(ctx, indented.String())
+		}
+	}
+
+	return nil, nil
+}
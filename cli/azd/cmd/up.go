@@ -7,6 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
@@ -14,22 +17,32 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/cmd"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/journal"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/status"
 	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
+var upCleanupPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "UP_CLEANUP",
+	Description: "What to do with the resources created by a failed run: 'Remove the resources' or 'Keep the resources'.",
+	Commands:    []string{"up"},
+})
+
 type upFlags struct {
 	cmd.ProvisionFlags
 	cmd.DeployFlags
-	global *internal.GlobalCommandOptions
+	statusEndpoint string
+	global         *internal.GlobalCommandOptions
 	internal.EnvFlag
 }
 
@@ -41,6 +54,13 @@ func (u *upFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptio
 	u.ProvisionFlags.SetCommon(&u.EnvFlag)
 	u.DeployFlags.BindNonCommon(local, global)
 	u.DeployFlags.SetCommon(&u.EnvFlag)
+
+	local.StringVar(
+		&u.statusEndpoint,
+		"status-endpoint",
+		"",
+		"Serves the live status of the operation as JSON (/status) and server-sent events (/events) on the local "+
+			"address, ex) :7070.")
 }
 
 func newUpFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *upFlags {
@@ -67,8 +87,13 @@ type upAction struct {
 	prompters           prompt.Prompter
 	importManager       *project.ImportManager
 	workflowRunner      *workflow.Runner
+	azdCtx              *azdcontext.AzdContext
+	journalCleaner      *journal.Cleaner
 }
 
+// errUpInterrupted is the cause of the cancellation of the up workflow when the terminal is interrupted
+var errUpInterrupted = errors.New("interrupted")
+
 var defaultUpWorkflow = &workflow.Workflow{
 	Name: "up",
 	Steps: []*workflow.Step{
@@ -88,6 +113,8 @@ func newUpAction(
 	prompters prompt.Prompter,
 	importManager *project.ImportManager,
 	workflowRunner *workflow.Runner,
+	azdCtx *azdcontext.AzdContext,
+	journalCleaner *journal.Cleaner,
 ) actions.Action {
 	return &upAction{
 		flags:               flags,
@@ -99,6 +126,8 @@ func newUpAction(
 		prompters:           prompters,
 		importManager:       importManager,
 		workflowRunner:      workflowRunner,
+		azdCtx:              azdCtx,
+		journalCleaner:      journalCleaner,
 	}
 }
 
@@ -136,10 +165,58 @@ func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		ctx = context.WithValue(ctx, envFlagCtxKey, u.flags.EnvFlag)
 	}
 
-	if err := u.workflowRunner.Run(ctx, upWorkflow); err != nil {
+	journalPath := journal.Path(u.azdCtx, u.env.Name())
+	if err := u.cleanOrphanedJournal(ctx, journalPath); err != nil {
+		return nil, err
+	}
+
+	upJournal, err := journal.Start(journalPath, "up")
+	if err != nil {
+		return nil, fmt.Errorf("starting the journal of the resources created: %w", err)
+	}
+
+	// The tracker records the run for its report, and serves its live status when requested
+	tracker := status.NewTracker("up")
+	if u.flags.statusEndpoint != "" {
+		server, err := status.Serve(u.flags.statusEndpoint, tracker)
+		if err != nil {
+			return nil, err
+		}
+		defer server.Close()
+
+		u.console.Message(ctx, fmt.Sprintf("Status of the operation: %s", output.WithLinkFormat(server.Url()+"/status")))
+	}
+	ctx = status.WithTracker(ctx, tracker)
+
+	// The first interrupt cancels the workflow, so the resources created so far can be removed
+	runCtx, cancel := context.WithCancelCause(journal.WithJournal(ctx, upJournal))
+	defer cancel(nil)
+	u.console.SetInterruptHandler(func() { cancel(errUpInterrupted) })
+	defer u.console.SetInterruptHandler(nil)
+
+	err = u.workflowRunner.Run(runCtx, upWorkflow)
+	tracker.Complete(err)
+	u.writeReport(ctx, tracker)
+	if err != nil {
+		if errors.Is(context.Cause(runCtx), errUpInterrupted) {
+			u.console.StopSpinner(ctx, "", input.Step)
+			u.console.Message(ctx, output.WithWarningFormat("\nazd up was interrupted."))
+			if cleanErr := u.promptCleanup(ctx, upJournal); cleanErr != nil {
+				return nil, cleanErr
+			}
+
+			return nil, fmt.Errorf("azd up was interrupted: %w", err)
+		}
+
+		// The resources of a failed workflow are kept, as they were before
+		_ = upJournal.Remove()
 		return nil, err
 	}
 
+	if err := upJournal.Remove(); err != nil {
+		log.Printf("failed removing the journal %s: %v", journalPath, err)
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			Header: fmt.Sprintf("Your up workflow to provision and deploy to Azure completed in %s.",
@@ -148,6 +225,76 @@ func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}, nil
 }
 
+// writeReport writes the report of the run to the directory of the environment, for 'azd show --last-run' and the
+// artifacts of CI pipelines.
+func (u *upAction) writeReport(ctx context.Context, tracker *status.Tracker) {
+	dir := u.azdCtx.EnvironmentRoot(u.env.Name())
+	if err := status.WriteReport(dir, status.NewReport(u.env.Name(), tracker.Snapshot())); err != nil {
+		log.Printf("failed writing the report of the run: %v", err)
+		return
+	}
+
+	u.console.Message(ctx, output.WithGrayFormat("Report of the run: %s",
+		filepath.Join(dir, status.ReportMarkdownFileName)))
+}
+
+// cleanOrphanedJournal offers to remove the resources recorded by a previous run of 'azd up' which was interrupted
+// before it could remove them.
+func (u *upAction) cleanOrphanedJournal(ctx context.Context, path string) error {
+	orphaned, err := journal.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		log.Printf("ignoring the journal of a previous run: %v", err)
+		return nil
+	}
+
+	u.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf(
+			"A previous run of 'azd %s' started at %s didn't complete.",
+			orphaned.Command,
+			orphaned.StartedAt.Local().Format(time.DateTime)),
+	})
+
+	return u.promptCleanup(ctx, orphaned)
+}
+
+// promptCleanup prompts to remove the resources recorded in the journal or keep them, then removes the journal. The
+// resources are kept by default, so they are only removed when the user explicitly chooses to, never with --no-prompt
+// or on CI.
+func (u *upAction) promptCleanup(ctx context.Context, upJournal *journal.Journal) error {
+	if len(upJournal.Entries) == 0 {
+		return upJournal.Remove()
+	}
+
+	u.console.Message(ctx, fmt.Sprintf("Resources created by the run:\n%s\n", upJournal.Describe()))
+	const removeChoice = "Remove the resources"
+	const keepChoice = "Keep the resources"
+	choices := []string{removeChoice, keepChoice}
+	choice, err := u.console.Select(ctx, input.ConsoleOptions{
+		Id:           upCleanupPromptId,
+		Message:      "Do you want to remove the resources created by the run?",
+		Options:      choices,
+		DefaultValue: keepChoice,
+	})
+	if err != nil {
+		return err
+	}
+
+	if choices[choice] == removeChoice {
+		spinnerMessage := "Removing the resources created by the run"
+		u.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+		err := u.journalCleaner.Clean(ctx, upJournal)
+		u.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			// The journal is kept, so removing the resources is offered again on the next run
+			return fmt.Errorf("removing the resources created by the run: %w", err)
+		}
+	}
+
+	return upJournal.Remove()
+}
+
 func getCmdUpHelpDescription(c *cobra.Command) string {
 	return generateCmdHelpDescription(
 		heredoc.Docf(
@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/journal"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpPromptCleanupKeepsResourcesByDefault(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	// Answers with the default value, like --no-prompt and CI runs
+	mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+		return options.Message == "Do you want to remove the resources created by the run?"
+	}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+		return slices.Index(options.Options, options.DefaultValue.(string)), nil
+	})
+
+	path := filepath.Join(t.TempDir(), journal.FileName)
+	upJournal, err := journal.Start(path, "up")
+	require.NoError(t, err)
+	require.NoError(t, upJournal.Add(journal.Entry{
+		Kind:            journal.EntryResourceGroups,
+		SubscriptionId:  "SUBSCRIPTION_ID",
+		EnvironmentName: "dev",
+	}))
+
+	// The cleaner isn't set, removing the resources would panic
+	action := &upAction{console: mockContext.Console}
+	require.NoError(t, action.promptCleanup(*mockContext.Context, upJournal))
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,235 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// xActions registers the experimental helper commands intended to be called from hooks and extensions.
+func xActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("x", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:    "x",
+			Short:  "Experimental helper commands for hooks and extensions.",
+			Hidden: true,
+		},
+	})
+
+	promptGroup := group.Add("prompt", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "prompt",
+			Short: "Prompt for Azure subscriptions, locations, resource groups and resources.",
+		},
+	})
+
+	prompts := []struct {
+		kind  xPromptKind
+		short string
+	}{
+		{xPromptSubscription, "Prompt for an Azure subscription."},
+		{xPromptLocation, "Prompt for an Azure location."},
+		{xPromptResourceGroup, "Prompt for an Azure resource group."},
+		{xPromptResource, "Prompt for an Azure resource of the specified type."},
+	}
+
+	for _, p := range prompts {
+		kind := p.kind
+		promptGroup.Add(string(kind), &actions.ActionDescriptorOptions{
+			Command: &cobra.Command{
+				Use:   string(kind),
+				Short: p.short,
+				Args:  cobra.NoArgs,
+			},
+			FlagsResolver: newXPromptFlags,
+			ActionResolver: func(
+				flags *xPromptFlags,
+				promptService prompt.PromptService,
+				resourceService prompt.ResourceService,
+				envResolver environment.EnvironmentResolver,
+				formatter output.Formatter,
+				writer io.Writer,
+			) actions.Action {
+				return &xPromptAction{
+					kind:            kind,
+					flags:           flags,
+					promptService:   promptService,
+					resourceService: resourceService,
+					envResolver:     envResolver,
+					formatter:       formatter,
+					writer:          writer,
+				}
+			},
+			OutputFormats: []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat: output.NoneFormat,
+			RequireLogin:  true,
+		})
+	}
+
+	return group
+}
+
+type xPromptKind string
+
+const (
+	xPromptSubscription  xPromptKind = "subscription"
+	xPromptLocation      xPromptKind = "location"
+	xPromptResourceGroup xPromptKind = "resource-group"
+	xPromptResource      xPromptKind = "resource"
+)
+
+type xPromptFlags struct {
+	subscription  string
+	location      string
+	resourceGroup string
+	resourceType  string
+	kinds         []string
+	message       string
+	global        *internal.GlobalCommandOptions
+	internal.EnvFlag
+}
+
+func newXPromptFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *xPromptFlags {
+	flags := &xPromptFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *xPromptFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.subscription,
+		"subscription",
+		"",
+		"The subscription to use. Defaults to the subscription of the current environment, otherwise prompts.",
+	)
+	local.StringVar(&f.location, "location", "", "The location used to filter the results.")
+	local.StringVar(
+		&f.resourceGroup,
+		"resource-group",
+		"",
+		"The resource group to search for resources. When not set, the whole subscription is searched.",
+	)
+	local.StringVar(&f.resourceType, "type", "", "The resource type to prompt for (ex: Microsoft.Storage/storageAccounts).")
+	local.StringArrayVar(&f.kinds, "kind", nil, "The resource kinds used to filter the results. Can be used multiple times.")
+	local.StringVar(&f.message, "message", "", "A custom message displayed in the prompt.")
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+type xPromptAction struct {
+	kind            xPromptKind
+	flags           *xPromptFlags
+	promptService   prompt.PromptService
+	resourceService prompt.ResourceService
+	envResolver     environment.EnvironmentResolver
+	formatter       output.Formatter
+	writer          io.Writer
+}
+
+func (a *xPromptAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.kind == xPromptResource && a.flags.resourceType == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("the --type flag is required"),
+			Suggestion: "Specify the resource type to prompt for, for example '--type Microsoft.Storage/storageAccounts'.",
+		}
+	}
+
+	selectOptions := &prompt.SelectOptions{}
+	if a.flags.message != "" {
+		selectOptions.Message = a.flags.message
+	}
+
+	if a.kind == xPromptSubscription {
+		subscription, err := a.promptService.PromptSubscription(ctx, selectOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, a.write(subscription, subscription.Id)
+	}
+
+	scope := prompt.AzureScope{
+		SubscriptionId: a.flags.subscription,
+		Location:       a.flags.location,
+		ResourceGroup:  a.flags.resourceGroup,
+	}
+
+	// Default to the subscription of the current azd environment when available
+	if scope.SubscriptionId == "" {
+		if env, err := a.envResolver(ctx); err == nil {
+			scope.SubscriptionId = env.GetSubscriptionId()
+		}
+	}
+
+	azureContext := prompt.NewAzureContext(
+		a.promptService,
+		scope,
+		prompt.NewAzureResourceList(a.resourceService, nil),
+	)
+
+	if err := azureContext.EnsureSubscription(ctx); err != nil {
+		return nil, err
+	}
+
+	switch a.kind {
+	case xPromptLocation:
+		location, err := a.promptService.PromptLocation(ctx, azureContext, selectOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, a.write(location, location.Name)
+	case xPromptResourceGroup:
+		resourceGroup, err := a.promptService.PromptResourceGroup(ctx, azureContext, &prompt.ResourceGroupOptions{
+			SelectorOptions: selectOptions,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, a.write(resourceGroup, resourceGroup.Id)
+	default:
+		resourceOptions := prompt.ResourceOptions{
+			ResourceType:    to.Ptr(azapi.AzureResourceType(a.flags.resourceType)),
+			Kinds:           a.flags.kinds,
+			SelectorOptions: selectOptions,
+		}
+
+		var resource *azapi.ResourceExtended
+		var err error
+		if scope.ResourceGroup != "" {
+			resource, err = a.promptService.PromptResourceGroupResource(ctx, azureContext, resourceOptions)
+		} else {
+			resource, err = a.promptService.PromptSubscriptionResource(ctx, azureContext, resourceOptions)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, a.write(resource, resource.Id)
+	}
+}
+
+// write formats the selected value as JSON, or writes the identifying value as plain text for easy use in scripts.
+func (a *xPromptAction) write(value any, text string) error {
+	if a.formatter.Kind() == output.JsonFormat {
+		return a.formatter.Format(value, a.writer, nil)
+	}
+
+	_, err := fmt.Fprintln(a.writer, text)
+	return err
+}
@@ -0,0 +1,198 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXPrompt(t *testing.T) {
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.SubscriptionIdEnvVarName: "ENV_SUBSCRIPTION_ID",
+	})
+	withEnv := func(ctx context.Context) (*environment.Environment, error) {
+		return env, nil
+	}
+	withoutEnv := func(ctx context.Context) (*environment.Environment, error) {
+		return nil, environment.ErrNotFound
+	}
+
+	location := &account.Location{Name: "eastus2", DisplayName: "East US 2"}
+
+	newAction := func(
+		kind xPromptKind,
+		flags *xPromptFlags,
+		promptService prompt.PromptService,
+		envResolver environment.EnvironmentResolver,
+		formatter output.Formatter,
+		writer *bytes.Buffer,
+	) *xPromptAction {
+		return &xPromptAction{
+			kind:          kind,
+			flags:         flags,
+			promptService: promptService,
+			envResolver:   envResolver,
+			formatter:     formatter,
+			writer:        writer,
+		}
+	}
+
+	t.Run("TypeRequired", func(t *testing.T) {
+		promptService := &mockXPromptService{}
+		action := newAction(
+			xPromptResource, &xPromptFlags{}, promptService, withEnv, &output.NoneFormatter{}, &bytes.Buffer{})
+
+		_, err := action.Run(context.Background())
+
+		var suggestion *internal.ErrorWithSuggestion
+		require.True(t, errors.As(err, &suggestion))
+		require.ErrorContains(t, err, "the --type flag is required")
+		promptService.AssertNotCalled(t, "PromptSubscriptionResource", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("SubscriptionFromEnvironment", func(t *testing.T) {
+		promptService := &mockXPromptService{}
+		promptService.On("PromptLocation", mock.Anything, mock.MatchedBy(func(azureContext *prompt.AzureContext) bool {
+			return azureContext.Scope.SubscriptionId == "ENV_SUBSCRIPTION_ID"
+		}), mock.Anything).Return(location, nil)
+
+		writer := &bytes.Buffer{}
+		action := newAction(xPromptLocation, &xPromptFlags{}, promptService, withEnv, &output.NoneFormatter{}, writer)
+
+		_, err := action.Run(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "eastus2\n", writer.String())
+		promptService.AssertNotCalled(t, "PromptSubscription", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SubscriptionFlagOverridesEnvironment", func(t *testing.T) {
+		promptService := &mockXPromptService{}
+		promptService.On("PromptLocation", mock.Anything, mock.MatchedBy(func(azureContext *prompt.AzureContext) bool {
+			return azureContext.Scope.SubscriptionId == "FLAG_SUBSCRIPTION_ID"
+		}), mock.Anything).Return(location, nil)
+
+		action := newAction(
+			xPromptLocation,
+			&xPromptFlags{subscription: "FLAG_SUBSCRIPTION_ID"},
+			promptService,
+			withEnv,
+			&output.NoneFormatter{},
+			&bytes.Buffer{},
+		)
+
+		_, err := action.Run(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("PromptsSubscriptionWithoutEnvironment", func(t *testing.T) {
+		promptService := &mockXPromptService{}
+		promptService.On("PromptSubscription", mock.Anything, mock.Anything).
+			Return(&account.Subscription{Id: "PROMPTED_SUBSCRIPTION_ID"}, nil)
+		promptService.On("PromptLocation", mock.Anything, mock.MatchedBy(func(azureContext *prompt.AzureContext) bool {
+			return azureContext.Scope.SubscriptionId == "PROMPTED_SUBSCRIPTION_ID"
+		}), mock.Anything).Return(location, nil)
+
+		action := newAction(
+			xPromptLocation, &xPromptFlags{}, promptService, withoutEnv, &output.NoneFormatter{}, &bytes.Buffer{})
+
+		_, err := action.Run(context.Background())
+		require.NoError(t, err)
+		promptService.AssertCalled(t, "PromptSubscription", mock.Anything, mock.Anything)
+	})
+
+	t.Run("JsonOutput", func(t *testing.T) {
+		resource := &azapi.ResourceExtended{
+			Resource: azapi.Resource{
+				Id:   "/subscriptions/ENV_SUBSCRIPTION_ID/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st",
+				Name: "st",
+				Type: "Microsoft.Storage/storageAccounts",
+			},
+		}
+
+		promptService := &mockXPromptService{}
+		promptService.On("PromptResourceGroupResource", mock.Anything, mock.Anything, mock.MatchedBy(
+			func(options prompt.ResourceOptions) bool {
+				return *options.ResourceType == "Microsoft.Storage/storageAccounts"
+			})).Return(resource, nil)
+
+		flags := &xPromptFlags{resourceType: "Microsoft.Storage/storageAccounts", resourceGroup: "rg"}
+
+		writer := &bytes.Buffer{}
+		action := newAction(xPromptResource, flags, promptService, withEnv, &output.JsonFormatter{}, writer)
+		_, err := action.Run(context.Background())
+		require.NoError(t, err)
+
+		var written azapi.ResourceExtended
+		require.NoError(t, json.Unmarshal(writer.Bytes(), &written))
+		require.Equal(t, *resource, written)
+
+		// Without --output json, only the id is written for scripts
+		writer.Reset()
+		action = newAction(xPromptResource, flags, promptService, withEnv, &output.NoneFormatter{}, writer)
+		_, err = action.Run(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, resource.Id+"\n", writer.String())
+	})
+}
+
+type mockXPromptService struct {
+	mock.Mock
+}
+
+func (m *mockXPromptService) PromptSubscription(
+	ctx context.Context,
+	selectorOptions *prompt.SelectOptions,
+) (*account.Subscription, error) {
+	args := m.Called(ctx, selectorOptions)
+	return args.Get(0).(*account.Subscription), args.Error(1)
+}
+
+func (m *mockXPromptService) PromptLocation(
+	ctx context.Context,
+	azureContext *prompt.AzureContext,
+	selectorOptions *prompt.SelectOptions,
+) (*account.Location, error) {
+	args := m.Called(ctx, azureContext, selectorOptions)
+	return args.Get(0).(*account.Location), args.Error(1)
+}
+
+func (m *mockXPromptService) PromptResourceGroup(
+	ctx context.Context,
+	azureContext *prompt.AzureContext,
+	options *prompt.ResourceGroupOptions,
+) (*azapi.ResourceGroup, error) {
+	args := m.Called(ctx, azureContext, options)
+	return args.Get(0).(*azapi.ResourceGroup), args.Error(1)
+}
+
+func (m *mockXPromptService) PromptSubscriptionResource(
+	ctx context.Context,
+	azureContext *prompt.AzureContext,
+	options prompt.ResourceOptions,
+) (*azapi.ResourceExtended, error) {
+	args := m.Called(ctx, azureContext, options)
+	return args.Get(0).(*azapi.ResourceExtended), args.Error(1)
+}
+
+func (m *mockXPromptService) PromptResourceGroupResource(
+	ctx context.Context,
+	azureContext *prompt.AzureContext,
+	options prompt.ResourceOptions,
+) (*azapi.ResourceExtended, error) {
+	args := m.Called(ctx, azureContext, options)
+	return args.Get(0).(*azapi.ResourceExtended), args.Error(1)
+}
@@ -11,6 +11,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
@@ -27,6 +29,8 @@ const (
 	JavaScript    Language = "js"
 	TypeScript    Language = "ts"
 	Python        Language = "python"
+	// A project only built by its Dockerfile, ex) a Go or Rust app
+	Dockerfile Language = "docker"
 )
 
 func (pt Language) Display() string {
@@ -43,6 +47,8 @@ func (pt Language) Display() string {
 		return "TypeScript"
 	case Python:
 		return "Python"
+	case Dockerfile:
+		return "Docker"
 	}
 
 	return ""
@@ -56,12 +62,17 @@ const (
 	JsJQuery  Dependency = "jquery"
 	JsVite    Dependency = "vite"
 	JsNext    Dependency = "next"
+	JsNuxt    Dependency = "nuxt"
+
+	JavaSpringBoot Dependency = "spring-boot"
 
 	PyFlask   Dependency = "flask"
 	PyDjango  Dependency = "django"
 	PyFastApi Dependency = "fastapi"
 )
 
+// WebUIFrameworks are the frameworks of static web UIs. Frameworks supporting server-side rendering, ex) Next.js, are
+// only web UI frameworks when the project is built as a static site, see Project.HasWebUIFramework.
 var WebUIFrameworks = map[Dependency]struct{}{
 	JsReact:   {},
 	JsAngular: {},
@@ -71,7 +82,7 @@ var WebUIFrameworks = map[Dependency]struct{}{
 
 func (f Dependency) Language() Language {
 	switch f {
-	case JsReact, JsAngular, JsJQuery, JsVite:
+	case JsReact, JsAngular, JsJQuery, JsVite, JsNuxt:
 		return JavaScript
 	}
 
@@ -90,6 +101,10 @@ func (f Dependency) Display() string {
 		return "Vite"
 	case JsNext:
 		return "Next.js"
+	case JsNuxt:
+		return "Nuxt"
+	case JavaSpringBoot:
+		return "Spring Boot"
 	}
 
 	return ""
@@ -152,15 +167,41 @@ type Project struct {
 	// A short description of the detection rule applied.
 	DetectionRule string
 
+	// How confident the detector is that the directory is a project of the language.
+	Confidence Confidence
+
+	// For web frameworks supporting server-side rendering, ex) Next.js, true when the project is built as a static site.
+	StaticSite bool
+
 	// If true, the project uses Docker for packaging. This is inferred through the presence of a Dockerfile.
 	Docker *Docker
+
+	// The service of a Docker Compose file the project is built by, if any.
+	Compose *ComposeService
 }
 
+// Confidence is how confident a detector is that a directory is a project, between 0 and 1.
+type Confidence float64
+
+const (
+	// The directory has a file hinting at a project, ex) a Dockerfile, which could be part of a larger project.
+	ConfidenceLow Confidence = 0.3
+	// The directory has the manifest of a language, ex) package.json.
+	ConfidenceMedium Confidence = 0.6
+	// The directory has the manifest of a buildable project, ex) a .csproj with its startup file.
+	ConfidenceHigh Confidence = 0.9
+)
+
 func (p *Project) HasWebUIFramework() bool {
 	for _, f := range p.Dependencies {
 		if f.IsWebUIFramework() {
 			return true
 		}
+
+		// Nuxt apps are served by node, unless generated as a static site
+		if f == JsNuxt && p.StaticSite {
+			return true
+		}
 	}
 
 	return false
@@ -176,14 +217,23 @@ type Docker struct {
 	Ports []Port
 }
 
-type projectDetector interface {
+// ProjectDetector detects the project of a language in a directory.
+type ProjectDetector interface {
 	Language() Language
+	// DetectProject returns the project in the directory, or nil when the directory isn't a project of the language.
+	// The confidence of the project is ConfidenceMedium when it isn't set.
 	DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error)
 }
 
-var allDetectors = []projectDetector{
-	// Order here determines precedence when two projects are in the same directory.
-	// This is unlikely to occur in practice, but reordering could help to break the tie in these cases.
+// RegisterDetector adds a detector to the registry of the detectors used by Detect and DetectDirectory. It has the
+// lowest precedence, and isn't safe to call concurrently with detections.
+func RegisterDetector(detector ProjectDetector) {
+	allDetectors = append(allDetectors, detector)
+}
+
+var allDetectors = []ProjectDetector{
+	// The project detected with the highest confidence is used when projects of several languages are in the same
+	// directory. Order here determines precedence when they have the same confidence.
 	&javaDetector{
 		mvnCli: maven.NewCli(exec.NewCommandRunner(nil)),
 	},
@@ -194,8 +244,10 @@ var allDetectors = []projectDetector{
 	&dotNetDetector{
 		dotnetCli: dotnet.NewCli(exec.NewCommandRunner(nil)),
 	},
+	&springBootGradleDetector{},
 	&pythonDetector{},
 	&javaScriptDetector{},
+	&dockerDetector{},
 }
 
 // Detect detects projects located under a directory.
@@ -217,6 +269,7 @@ func DetectDirectory(ctx context.Context, directory string, options ...DetectDir
 
 func detectUnder(ctx context.Context, root string, config detectConfig) ([]Project, error) {
 	projects := []Project{}
+	composeFiles := []*composeFile{}
 
 	walkFunc := func(path string, entries []fs.DirEntry) error {
 		relativePath, err := filepath.Rel(root, path)
@@ -237,14 +290,29 @@ func detectUnder(ctx context.Context, root string, config detectConfig) ([]Proje
 			}
 		}
 
+		compose, err := detectComposeInDirectory(path, entries)
+		if err != nil {
+			// A compose file which can't be read doesn't prevent the detection of the projects
+			log.Printf("ignoring compose file in %s: %v", path, err)
+		} else if compose != nil {
+			composeFiles = append(composeFiles, compose)
+		}
+
 		project, err := detectAny(ctx, config.detectors, path, entries)
 		if err != nil {
 			return err
 		}
 
 		if project != nil {
-			// Once a project is detected, we skip possible inner projects.
 			projects = append(projects, *project)
+
+			// A project detected with a low confidence, ex) a Dockerfile, may be part of a larger project. Inner
+			// projects are still detected, and replace it.
+			if project.Confidence <= ConfidenceLow {
+				return nil
+			}
+
+			// Once a project is detected, we skip possible inner projects.
 			return filepath.SkipDir
 		}
 
@@ -256,33 +324,72 @@ func detectUnder(ctx context.Context, root string, config detectConfig) ([]Proje
 		return nil, fmt.Errorf("scanning directories: %w", err)
 	}
 
+	detected := slices.Clone(projects)
+	projects = slices.DeleteFunc(projects, func(project Project) bool {
+		return project.Confidence <= ConfidenceLow && slices.ContainsFunc(detected, func(inner Project) bool {
+			return inner.Path != project.Path && isUnder(project.Path, inner.Path)
+		})
+	})
+
+	for _, compose := range composeFiles {
+		projects = compose.apply(root, projects)
+	}
+
 	return projects, nil
 }
 
-// Detects if a directory belongs to any projects.
-func detectAny(ctx context.Context, detectors []projectDetector, path string, entries []fs.DirEntry) (*Project, error) {
+// Detects if a directory belongs to any projects. When projects of several languages are detected, the one detected
+// with the highest confidence is returned.
+func detectAny(ctx context.Context, detectors []ProjectDetector, path string, entries []fs.DirEntry) (*Project, error) {
 	log.Printf("Detecting projects in directory: %s", path)
+
+	var detected *Project
 	for _, detector := range detectors {
 		project, err := detector.DetectProject(ctx, path, entries)
 		if err != nil {
 			return nil, fmt.Errorf("detecting %s project: %w", string(detector.Language()), err)
 		}
 
-		if project != nil {
-			log.Printf("Found project %s at %s", project.Language, path)
+		if project == nil {
+			continue
+		}
 
-			// docker is an optional property of a project, and thus is different than other detectors
-			docker, err := detectDockerInDirectory(path, entries)
-			if err != nil {
-				return nil, fmt.Errorf("detecting docker project: %w", err)
-			}
-			project.Docker = docker
+		if project.Confidence == 0 {
+			project.Confidence = ConfidenceMedium
+		}
+
+		if detected == nil || project.Confidence > detected.Confidence {
+			detected = project
+		}
+
+		if detected.Confidence >= ConfidenceHigh {
+			// The next detectors have a lower precedence, and can't be more confident
+			break
+		}
+	}
+
+	if detected == nil {
+		return nil, nil
+	}
+
+	log.Printf("Found project %s at %s with confidence %.1f", detected.Language, path, detected.Confidence)
 
-			return project, nil
+	// docker is an optional property of a project, and thus is different than other detectors
+	if detected.Docker == nil {
+		docker, err := detectDockerInDirectory(path, entries)
+		if err != nil {
+			return nil, fmt.Errorf("detecting docker project: %w", err)
 		}
+		detected.Docker = docker
 	}
 
-	return nil, nil
+	return detected, nil
+}
+
+// isUnder returns true when the path is the directory or one of its descendants.
+func isUnder(directory string, path string) bool {
+	rel, err := filepath.Rel(directory, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // walkDirFunc is the type of function that is called whenever a directory is visited by WalkDirectories.
@@ -38,17 +38,21 @@ func TestDetect(t *testing.T) {
 					Language:      DotNet,
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
+					Confidence:    ConfidenceHigh,
 				},
 				{
 					Language:      Java,
 					Path:          "java",
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
 				},
 				{
 					Language:      Java,
 					Path:          "java-multimodules/application",
 					RootPath:      filepath.Join(dir, "java-multimodules"),
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
+					Dependencies:  []Dependency{JavaSpringBoot},
 					DatabaseDeps: []DatabaseDep{
 						DbMySql,
 						DbPostgres,
@@ -59,34 +63,42 @@ func TestDetect(t *testing.T) {
 					Path:          "java-multimodules/library",
 					RootPath:      filepath.Join(dir, "java-multimodules"),
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
 				},
 				{
 					Language:      Java,
 					Path:          "java-multimodules/module1",
 					RootPath:      filepath.Join(dir, "java-multimodules"),
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
+					Dependencies:  []Dependency{JavaSpringBoot},
 				},
 				{
 					Language:      Java,
 					Path:          "java-multimodules/module2/submodule1",
 					RootPath:      filepath.Join(dir, "java-multimodules"), // point to the root, not direct parent
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
+					Dependencies:  []Dependency{JavaSpringBoot},
 				},
 				{
 					Language:      Java,
 					Path:          "java-multimodules/notmodule",
 					RootPath:      "",
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
 				},
 				{
 					Language:      JavaScript,
 					Path:          "javascript",
 					DetectionRule: "Inferred by presence of: package.json",
+					Confidence:    ConfidenceMedium,
 				},
 				{
 					Language:      JavaScript,
 					Path:          "javascript-full",
 					DetectionRule: "Inferred by presence of: package.json",
+					Confidence:    ConfidenceMedium,
 					Dependencies: []Dependency{
 						JsAngular,
 						JsJQuery,
@@ -105,11 +117,13 @@ func TestDetect(t *testing.T) {
 					Language:      Python,
 					Path:          "python",
 					DetectionRule: "Inferred by presence of: requirements.txt",
+					Confidence:    ConfidenceMedium,
 				},
 				{
 					Language:      Python,
 					Path:          "python-full",
 					DetectionRule: "Inferred by presence of: requirements.txt",
+					Confidence:    ConfidenceMedium,
 					Dependencies: []Dependency{
 						PyDjango,
 						PyFastApi,
@@ -126,6 +140,7 @@ func TestDetect(t *testing.T) {
 					Language:      TypeScript,
 					Path:          "typescript",
 					DetectionRule: "Inferred by presence of: package.json",
+					Confidence:    ConfidenceMedium,
 				},
 			},
 		},
@@ -142,6 +157,7 @@ func TestDetect(t *testing.T) {
 					Language:      DotNet,
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
+					Confidence:    ConfidenceHigh,
 				},
 			},
 		},
@@ -157,6 +173,7 @@ func TestDetect(t *testing.T) {
 					Language:      DotNet,
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
+					Confidence:    ConfidenceHigh,
 				},
 			},
 		},
@@ -175,16 +192,19 @@ func TestDetect(t *testing.T) {
 					Language:      DotNet,
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
+					Confidence:    ConfidenceHigh,
 				},
 				{
 					Language:      Java,
 					Path:          "java",
 					DetectionRule: "Inferred by presence of: pom.xml",
+					Confidence:    ConfidenceHigh,
 				},
 				{
 					Language:      Python,
 					Path:          "python",
 					DetectionRule: "Inferred by presence of: requirements.txt",
+					Confidence:    ConfidenceMedium,
 				},
 			},
 		},
@@ -221,6 +241,7 @@ func TestDetectDocker(t *testing.T) {
 		Language:      DotNet,
 		Path:          filepath.Join(dir, "dotnet"),
 		DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
+		Confidence:    ConfidenceHigh,
 		Docker: &Docker{
 			Path:  filepath.Join(dir, "dotnet", "Dockerfile"),
 			Ports: nil,
@@ -249,6 +270,7 @@ func TestDetectNested(t *testing.T) {
 		Language:      DotNet,
 		Path:          filepath.Join(src, "dotnet"),
 		DetectionRule: "Inferred by presence of: dotnettestapp.csproj, Program.cs",
+		Confidence:    ConfidenceHigh,
 	})
 }
 
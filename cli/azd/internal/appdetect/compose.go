@@ -0,0 +1,298 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/braydonk/yaml"
+)
+
+// composeFileNames are the names of the Docker Compose files, in their order of precedence.
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ComposeService is a service of a Docker Compose file, built from the directory of a project.
+type ComposeService struct {
+	// The name of the service.
+	Name string
+
+	// The path to the compose file.
+	Path string
+
+	// The container ports of the service, published or exposed.
+	Ports []Port
+
+	// The environment variables of the service.
+	Env map[string]string
+
+	// The build context and the Dockerfile of the service.
+	context    string
+	dockerfile string
+
+	// The databases of the services the service depends on, ex) a 'postgres' image.
+	databaseDeps []DatabaseDep
+}
+
+// composeFile is the content of a Docker Compose file used by the detection.
+type composeFile struct {
+	path     string
+	services []*ComposeService
+}
+
+// composeServiceSpec is the service of a Docker Compose file, where most properties support a short and a long syntax.
+type composeServiceSpec struct {
+	Image       string `yaml:"image"`
+	Build       any    `yaml:"build"`
+	Ports       []any  `yaml:"ports"`
+	Expose      []any  `yaml:"expose"`
+	Environment any    `yaml:"environment"`
+	DependsOn   any    `yaml:"depends_on"`
+}
+
+func detectComposeInDirectory(path string, entries []fs.DirEntry) (*composeFile, error) {
+	for _, name := range composeFileNames {
+		if slices.ContainsFunc(entries, func(entry fs.DirEntry) bool { return entry.Name() == name }) {
+			return readComposeFile(filepath.Join(path, name))
+		}
+	}
+
+	return nil, nil
+}
+
+func readComposeFile(composePath string) (*composeFile, error) {
+	contents, err := os.ReadFile(composePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec struct {
+		Services map[string]composeServiceSpec `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(contents, &spec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", composePath, err)
+	}
+
+	compose := &composeFile{path: composePath}
+	for _, name := range slices.Sorted(maps.Keys(spec.Services)) {
+		serviceSpec := spec.Services[name]
+		service := &ComposeService{
+			Name: name,
+			Path: composePath,
+			Env:  composeEnv(serviceSpec.Environment),
+		}
+
+		context, dockerfile := composeBuild(serviceSpec.Build)
+		if context != "" && !strings.Contains(context, "://") {
+			service.context = filepath.Join(filepath.Dir(composePath), filepath.FromSlash(context))
+			service.dockerfile = filepath.Join(service.context, filepath.FromSlash(dockerfile))
+		}
+
+		for _, port := range append(serviceSpec.Ports, serviceSpec.Expose...) {
+			parsed, err := parseComposePort(port)
+			if err != nil {
+				log.Printf("parsing the ports of service %s in %s: %v", name, composePath, err)
+				continue
+			}
+
+			if !slices.Contains(service.Ports, parsed) {
+				service.Ports = append(service.Ports, parsed)
+			}
+		}
+
+		databaseDeps := map[DatabaseDep]struct{}{}
+		for _, dependency := range composeDependsOn(serviceSpec.DependsOn) {
+			if db, isDb := imageDatabase(spec.Services[dependency].Image); isDb {
+				databaseDeps[db] = struct{}{}
+			}
+		}
+		service.databaseDeps = slices.Sorted(maps.Keys(databaseDeps))
+
+		compose.services = append(compose.services, service)
+	}
+
+	return compose, nil
+}
+
+// apply attaches the services of the compose file to the projects built from their build context. The services built
+// from a directory where no project was detected are added as Docker projects.
+func (c *composeFile) apply(root string, projects []Project) []Project {
+	for _, service := range c.services {
+		if service.context == "" || !isUnder(root, service.context) {
+			continue
+		}
+
+		idx := slices.IndexFunc(projects, func(project Project) bool { return project.Path == service.context })
+		if idx == -1 {
+			// The build context is part of a project, ex) a worker of a Python project built from its sub directory
+			if slices.ContainsFunc(projects, func(project Project) bool { return isUnder(project.Path, service.context) }) {
+				continue
+			}
+
+			docker, err := AnalyzeDocker(service.dockerfile)
+			if err != nil {
+				log.Printf("ignoring service %s of %s: %v", service.Name, c.path, err)
+				continue
+			}
+
+			projects = append(projects, Project{
+				Language:      Dockerfile,
+				Path:          service.context,
+				DetectionRule: fmt.Sprintf("Inferred by service '%s' of: %s", service.Name, filepath.Base(c.path)),
+				Confidence:    ConfidenceMedium,
+				Docker:        docker,
+			})
+			idx = len(projects) - 1
+		}
+
+		project := &projects[idx]
+		project.Compose = service
+
+		if project.Docker == nil {
+			if docker, err := AnalyzeDocker(service.dockerfile); err == nil {
+				project.Docker = docker
+			}
+		}
+
+		if project.Docker != nil {
+			for _, port := range service.Ports {
+				if !slices.Contains(project.Docker.Ports, port) {
+					project.Docker.Ports = append(project.Docker.Ports, port)
+				}
+			}
+		}
+
+		for _, db := range service.databaseDeps {
+			if !slices.Contains(project.DatabaseDeps, db) {
+				project.DatabaseDeps = append(project.DatabaseDeps, db)
+			}
+		}
+		slices.Sort(project.DatabaseDeps)
+	}
+
+	return projects
+}
+
+// composeBuild returns the build context and the Dockerfile of the short syntax, ex) build: ./web, or the long syntax,
+// ex) build: { context: ./web, dockerfile: web.Dockerfile }, of the build of a service.
+func composeBuild(build any) (context string, dockerfile string) {
+	dockerfile = "Dockerfile"
+	switch build := build.(type) {
+	case string:
+		context = build
+	case map[string]any:
+		context = "."
+		if value, has := build["context"].(string); has {
+			context = value
+		}
+		if value, has := build["dockerfile"].(string); has {
+			dockerfile = value
+		}
+	}
+
+	return context, dockerfile
+}
+
+// composeEnv returns the variables of the map syntax, ex) { PORT: 80 }, or the list syntax, ex) [ PORT=80 ], of the
+// environment of a service.
+func composeEnv(environment any) map[string]string {
+	env := map[string]string{}
+	switch environment := environment.(type) {
+	case map[string]any:
+		for name, value := range environment {
+			if value == nil {
+				env[name] = ""
+			} else {
+				env[name] = fmt.Sprint(value)
+			}
+		}
+	case []any:
+		for _, item := range environment {
+			name, value, _ := strings.Cut(fmt.Sprint(item), "=")
+			env[name] = value
+		}
+	}
+
+	if len(env) == 0 {
+		return nil
+	}
+
+	return env
+}
+
+// composeDependsOn returns the services of the list syntax, ex) [ db ], or the map syntax, ex) { db: { condition:
+// service_healthy } }, of the dependencies of a service.
+func composeDependsOn(dependsOn any) []string {
+	switch dependsOn := dependsOn.(type) {
+	case []any:
+		services := []string{}
+		for _, service := range dependsOn {
+			services = append(services, fmt.Sprint(service))
+		}
+		return services
+	case map[string]any:
+		return slices.Sorted(maps.Keys(dependsOn))
+	}
+
+	return nil
+}
+
+// parseComposePort returns the container port of the short syntax, ex) "8080:80/tcp", or the long syntax, ex) { target:
+// 80, published: 8080 }, of a port of a service.
+func parseComposePort(port any) (Port, error) {
+	if spec, isMap := port.(map[string]any); isMap {
+		number, err := strconv.Atoi(fmt.Sprint(spec["target"]))
+		if err != nil {
+			return Port{}, fmt.Errorf("parsing port target: %w", err)
+		}
+
+		protocol := "tcp"
+		if value, has := spec["protocol"].(string); has {
+			protocol = value
+		}
+
+		return Port{Number: number, Protocol: protocol}, nil
+	}
+
+	spec, protocol, hasProtocol := strings.Cut(fmt.Sprint(port), "/")
+	if !hasProtocol {
+		protocol = "tcp"
+	}
+
+	// The container port is the last part, after the host ip and the published port
+	target := spec[strings.LastIndex(spec, ":")+1:]
+	number, err := strconv.Atoi(target)
+	if err != nil {
+		return Port{}, fmt.Errorf("parsing port number: %w", err)
+	}
+
+	return Port{Number: number, Protocol: protocol}, nil
+}
+
+// imageDatabase returns the database of a container image, ex) postgres:16.
+func imageDatabase(image string) (DatabaseDep, bool) {
+	name, _, _ := strings.Cut(path.Base(image), ":")
+	switch {
+	case strings.HasPrefix(name, "postgres"):
+		return DbPostgres, true
+	case strings.HasPrefix(name, "mysql"), strings.HasPrefix(name, "mariadb"):
+		return DbMySql, true
+	case strings.HasPrefix(name, "mongo"):
+		return DbMongo, true
+	case strings.HasPrefix(name, "redis"):
+		return DbRedis, true
+	case strings.Contains(image, "mssql"):
+		return DbSqlServer, true
+	}
+
+	return "", false
+}
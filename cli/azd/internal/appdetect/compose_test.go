@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCompose(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"compose.yaml": `
+services:
+  web:
+    build: ./web
+    ports:
+      - "8080:3000"
+    environment:
+      API_URL: http://api:80
+  api:
+    build:
+      context: ./api
+      dockerfile: api.Dockerfile
+    expose:
+      - "80"
+    environment:
+      - DB_HOST=db
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: postgres:16
+`,
+		"web/package.json":   `{"dependencies": {"react": "^18.0.0"}}`,
+		"api/api.Dockerfile": "FROM alpine\nEXPOSE 80\n",
+	})
+
+	projects, err := Detect(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	composePath := filepath.Join(dir, "compose.yaml")
+	require.Equal(t, Project{
+		Language:      Dockerfile,
+		Path:          filepath.Join(dir, "api"),
+		DetectionRule: "Inferred by service 'api' of: compose.yaml",
+		Confidence:    ConfidenceMedium,
+		DatabaseDeps:  []DatabaseDep{DbPostgres},
+		Docker: &Docker{
+			Path:  filepath.Join(dir, "api", "api.Dockerfile"),
+			Ports: []Port{{Number: 80, Protocol: "tcp"}},
+		},
+		Compose: &ComposeService{
+			Name:         "api",
+			Path:         composePath,
+			Ports:        []Port{{Number: 80, Protocol: "tcp"}},
+			Env:          map[string]string{"DB_HOST": "db"},
+			context:      filepath.Join(dir, "api"),
+			dockerfile:   filepath.Join(dir, "api", "api.Dockerfile"),
+			databaseDeps: []DatabaseDep{DbPostgres},
+		},
+	}, projects[1])
+
+	web := projects[0]
+	require.Equal(t, JavaScript, web.Language)
+	require.Equal(t, filepath.Join(dir, "web"), web.Path)
+	require.Nil(t, web.Docker)
+	require.NotNil(t, web.Compose)
+	require.Equal(t, "web", web.Compose.Name)
+	require.Equal(t, []Port{{Number: 3000, Protocol: "tcp"}}, web.Compose.Ports)
+	require.Equal(t, map[string]string{"API_URL": "http://api:80"}, web.Compose.Env)
+}
+
+func TestDetectDockerfileFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"worker/Dockerfile":        "FROM alpine\nexpose 9000/udp\n",
+		"app/Containerfile":        "FROM python\n",
+		"app/src/requirements.txt": "flask\n",
+	})
+
+	projects, err := Detect(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	// the Dockerfile project is replaced by the project detected in its directory
+	require.Equal(t, Python, projects[0].Language)
+	require.Equal(t, filepath.Join(dir, "app", "src"), projects[0].Path)
+
+	require.Equal(t, Project{
+		Language:      Dockerfile,
+		Path:          filepath.Join(dir, "worker"),
+		DetectionRule: "Inferred by presence of: Dockerfile",
+		Confidence:    ConfidenceLow,
+		Docker: &Docker{
+			Path:  filepath.Join(dir, "worker", "Dockerfile"),
+			Ports: []Port{{Number: 9000, Protocol: "udp"}},
+		},
+	}, projects[1])
+}
+
+func TestParseComposePort(t *testing.T) {
+	tests := []struct {
+		port     any
+		expected Port
+		wantErr  bool
+	}{
+		{port: 80, expected: Port{Number: 80, Protocol: "tcp"}},
+		{port: "8080:80", expected: Port{Number: 80, Protocol: "tcp"}},
+		{port: "127.0.0.1:5353:53/udp", expected: Port{Number: 53, Protocol: "udp"}},
+		{port: map[string]any{"target": 3000, "published": 8080}, expected: Port{Number: 3000, Protocol: "tcp"}},
+		{port: "3000-3005", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		port, err := parseComposePort(tt.port)
+		if tt.wantErr {
+			require.Error(t, err)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, port)
+	}
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+	}
+}
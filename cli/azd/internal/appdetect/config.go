@@ -57,7 +57,7 @@ func setDetectors(c *languageConfig) {
 		}
 	}
 
-	c.detectors = []projectDetector{}
+	c.detectors = []ProjectDetector{}
 	for _, d := range allDetectors {
 		if languages[d.Language()] {
 			c.detectors = append(c.detectors, d)
@@ -99,7 +99,7 @@ type languageConfig struct {
 	ExcludeLanguages []Language
 
 	// Internal usage fields
-	detectors []projectDetector
+	detectors []ProjectDetector
 }
 
 type excludePatternsOptions struct {
@@ -5,6 +5,7 @@ package appdetect
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io/fs"
 	"log"
@@ -14,9 +15,34 @@ import (
 	"strings"
 )
 
+// dockerDetector detects the directories only built by a Dockerfile, ex) a Go app. It has a low confidence, so the
+// projects of the languages azd knows are preferred.
+type dockerDetector struct {
+}
+
+func (dd *dockerDetector) Language() Language {
+	return Dockerfile
+}
+
+func (dd *dockerDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	docker, err := detectDockerInDirectory(path, entries)
+	if err != nil || docker == nil {
+		return nil, err
+	}
+
+	return &Project{
+		Language:      Dockerfile,
+		Path:          path,
+		DetectionRule: "Inferred by presence of: " + filepath.Base(docker.Path),
+		Confidence:    ConfidenceLow,
+		Docker:        docker,
+	}, nil
+}
+
 func detectDockerInDirectory(path string, entries []fs.DirEntry) (*Docker, error) {
 	for _, entry := range entries {
-		if strings.ToLower(entry.Name()) == "dockerfile" {
+		switch strings.ToLower(entry.Name()) {
+		case "dockerfile", "containerfile":
 			dockerFilePath := filepath.Join(path, entry.Name())
 			return AnalyzeDocker(dockerFilePath)
 		}
@@ -37,7 +63,8 @@ func AnalyzeDocker(dockerFilePath string) (*Docker, error) {
 	var ports []Port
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "EXPOSE") {
+		// Instructions are case insensitive
+		if strings.HasPrefix(strings.ToUpper(line), "EXPOSE") {
 			parsedPorts, err := parsePortsInLine(line[len("EXPOSE"):])
 			if err != nil {
 				log.Printf("parsing Dockerfile at %s: %v", dockerFilePath, err)
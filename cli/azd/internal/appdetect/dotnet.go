@@ -60,6 +60,7 @@ func (dd *dotNetDetector) DetectProject(ctx context.Context, path string, entrie
 			Language:      DotNet,
 			Path:          path,
 			DetectionRule: "Inferred by presence of: " + fmt.Sprintf("%s, %s", projFileName, startUpFileName),
+			Confidence:    ConfidenceHigh,
 		}, nil
 	}
 
@@ -35,6 +35,7 @@ func (ad *dotNetAppHostDetector) DetectProject(ctx context.Context, path string,
 					Language:      DotNetAppHost,
 					Path:          projectPath,
 					DetectionRule: "Inferred by presence of: " + projectPath,
+					Confidence:    ConfidenceHigh,
 				}, nil
 			}
 		}
@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// springBootPluginRegex matches the Spring Boot plugin applied in a Gradle build script, in the Groovy or Kotlin syntax,
+// ex) id 'org.springframework.boot' version '3.4.5'
+var springBootPluginRegex = regexp.MustCompile(`(id\s*\(?\s*|apply\s+plugin\s*:\s*)["']org\.springframework\.boot["']`)
+
+// springBootGradleDetector detects the Spring Boot applications built with Gradle, from the Spring Boot plugin applied
+// in their build script.
+type springBootGradleDetector struct {
+	// The directories of the builds with a settings script, which can include the directories of their projects
+	rootProjects []string
+}
+
+func (sd *springBootGradleDetector) Language() Language {
+	return Java
+}
+
+func (sd *springBootGradleDetector) DetectProject(
+	ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	var buildFile string
+	for _, entry := range entries {
+		switch entry.Name() {
+		case "build.gradle", "build.gradle.kts":
+			buildFile = entry.Name()
+		case "settings.gradle", "settings.gradle.kts":
+			sd.rootProjects = append(sd.rootProjects, path)
+		}
+	}
+
+	if buildFile == "" {
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(filepath.Join(path, buildFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasSpringBootPlugin(contents) {
+		// The root project of a multi-project build, or a library
+		return nil, nil
+	}
+
+	project := &Project{
+		Language:      Java,
+		Path:          path,
+		DetectionRule: "Inferred by presence of: " + buildFile,
+		Confidence:    ConfidenceHigh,
+		Dependencies:  []Dependency{JavaSpringBoot},
+	}
+
+	for _, rootProject := range sd.rootProjects {
+		if rootProject != path && isUnder(rootProject, path) && len(rootProject) > len(project.RootPath) {
+			project.RootPath = rootProject
+		}
+	}
+
+	databaseDepMap := map[DatabaseDep]struct{}{}
+	for artifact, db := range map[string]DatabaseDep{
+		"org.postgresql:postgresql":                  DbPostgres,
+		"spring-cloud-azure-starter-jdbc-postgresql": DbPostgres,
+		"com.mysql:mysql-connector-j":                DbMySql,
+		"spring-cloud-azure-starter-jdbc-mysql":      DbMySql,
+		"spring-boot-starter-data-redis":             DbRedis,
+		"spring-boot-starter-data-mongodb":           DbMongo,
+	} {
+		if bytes.Contains(contents, []byte(artifact)) {
+			databaseDepMap[db] = struct{}{}
+		}
+	}
+
+	if len(databaseDepMap) > 0 {
+		project.DatabaseDeps = slices.SortedFunc(maps.Keys(databaseDepMap),
+			func(a, b DatabaseDep) int {
+				return strings.Compare(string(a), string(b))
+			})
+	}
+
+	return project, nil
+}
+
+// hasSpringBootPlugin returns true when the Spring Boot plugin is applied in the build script. The plugin declared
+// with 'apply false', ex) in the root project of a multi-project build, isn't applied.
+func hasSpringBootPlugin(buildScript []byte) bool {
+	for _, line := range strings.Split(string(buildScript), "\n") {
+		if springBootPluginRegex.MatchString(line) && !strings.Contains(line, "apply false") {
+			return true
+		}
+	}
+
+	return false
+}
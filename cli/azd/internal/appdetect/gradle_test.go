@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSpringBootGradle(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"settings.gradle.kts": `include("app", "lib")`,
+		"build.gradle.kts":    `plugins { id("org.springframework.boot") version "3.4.5" apply false }`,
+		"app/build.gradle.kts": `
+plugins {
+    id("org.springframework.boot")
+}
+dependencies {
+    runtimeOnly("org.postgresql:postgresql")
+    implementation("org.springframework.boot:spring-boot-starter-data-redis")
+}
+`,
+		"lib/build.gradle.kts": `plugins { id("java-library") }`,
+	})
+
+	projects, err := Detect(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, []Project{
+		{
+			Language:      Java,
+			Path:          filepath.Join(dir, "app"),
+			RootPath:      dir,
+			DetectionRule: "Inferred by presence of: build.gradle.kts",
+			Confidence:    ConfidenceHigh,
+			Dependencies:  []Dependency{JavaSpringBoot},
+			DatabaseDeps:  []DatabaseDep{DbPostgres, DbRedis},
+		},
+	}, projects)
+}
+
+func TestHasSpringBootPlugin(t *testing.T) {
+	require.True(t, hasSpringBootPlugin([]byte("plugins {\n  id 'org.springframework.boot' version '3.4.5'\n}")))
+	require.True(t, hasSpringBootPlugin([]byte("apply plugin: 'org.springframework.boot'")))
+	require.False(t, hasSpringBootPlugin([]byte("id 'org.springframework.boot' version '3.4.5' apply false")))
+	require.False(t, hasSpringBootPlugin([]byte("id 'io.spring.dependency-management'")))
+}
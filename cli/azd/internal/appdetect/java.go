@@ -66,6 +66,7 @@ func (jd *javaDetector) DetectProject(ctx context.Context, path string, entries
 				Language:      Java,
 				Path:          path,
 				DetectionRule: "Inferred by presence of: pom.xml",
+				Confidence:    ConfidenceHigh,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("detecting dependencies: %w", err)
@@ -163,6 +164,13 @@ func detectDependencies(mavenProject *mavenProject, project *Project) (*Project,
 		// todo: Add DbCosmos
 	}
 
+	// Spring Boot applications are packaged by the Spring Boot plugin, which libraries using Spring Boot don't have
+	if slices.ContainsFunc(mavenProject.Build.Plugins, func(p plugin) bool {
+		return p.GroupId == "org.springframework.boot" && p.ArtifactId == "spring-boot-maven-plugin"
+	}) {
+		project.Dependencies = append(project.Dependencies, JavaSpringBoot)
+	}
+
 	if len(databaseDepMap) > 0 {
 		project.DatabaseDeps = slices.SortedFunc(maps.Keys(databaseDepMap),
 			func(a, b DatabaseDep) int {
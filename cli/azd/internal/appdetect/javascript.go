@@ -10,6 +10,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 )
@@ -17,8 +18,15 @@ import (
 type PackagesJson struct {
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	Scripts         map[string]string `json:"scripts"`
 }
 
+// staticExportRegex matches the static export of the Next.js config, ex) output: 'export'
+var staticExportRegex = regexp.MustCompile(`output\s*:\s*["']export["']`)
+
+// ssrDisabledRegex matches the server-side rendering disabled in the Nuxt config, ex) ssr: false
+var ssrDisabledRegex = regexp.MustCompile(`ssr\s*:\s*false`)
+
 type javaScriptDetector struct {
 }
 
@@ -33,6 +41,7 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 				Language:      JavaScript,
 				Path:          path,
 				DetectionRule: "Inferred by presence of: " + entry.Name(),
+				Confidence:    ConfidenceMedium,
 			}
 
 			contents, err := os.ReadFile(filepath.Join(path, entry.Name()))
@@ -61,6 +70,8 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 					viteAdded = true
 				case "next":
 					project.Dependencies = append(project.Dependencies, JsNext)
+				case "nuxt":
+					project.Dependencies = append(project.Dependencies, JsNuxt)
 				default:
 					if strings.HasPrefix(dep, "@angular") && !angularAdded {
 						project.Dependencies = append(project.Dependencies, JsAngular)
@@ -102,6 +113,11 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 				return strings.Compare(string(a), string(b))
 			})
 
+			project.StaticSite, err = isStaticSite(path, entries, project.Dependencies, packagesJson.Scripts)
+			if err != nil {
+				return nil, err
+			}
+
 			tsFiles := 0
 			jsFiles := 0
 			err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
@@ -135,3 +151,52 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 
 	return nil, nil
 }
+
+// isStaticSite returns true when a project of a framework supporting server-side rendering, Next.js or Nuxt, is built
+// as a static site, from the scripts of its package.json or its framework config.
+func isStaticSite(
+	path string,
+	entries []fs.DirEntry,
+	dependencies []Dependency,
+	scripts map[string]string,
+) (bool, error) {
+	var staticScripts []string
+	var configPrefix string
+	var configRegex *regexp.Regexp
+
+	switch {
+	case slices.Contains(dependencies, JsNext):
+		staticScripts = []string{"next export"}
+		configPrefix = "next.config."
+		configRegex = staticExportRegex
+	case slices.Contains(dependencies, JsNuxt):
+		staticScripts = []string{"nuxt generate", "nuxi generate"}
+		configPrefix = "nuxt.config."
+		configRegex = ssrDisabledRegex
+	default:
+		return false, nil
+	}
+
+	for _, script := range scripts {
+		if slices.ContainsFunc(staticScripts, func(static string) bool { return strings.Contains(script, static) }) {
+			return true, nil
+		}
+	}
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), configPrefix) {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return false, err
+		}
+
+		if configRegex.Match(contents) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectStaticSite(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		dependency Dependency
+		staticSite bool
+	}{
+		{
+			name: "NextSsr",
+			files: map[string]string{
+				"package.json":   `{"dependencies": {"next": "^15.0.0"}, "scripts": {"build": "next build"}}`,
+				"next.config.js": `module.exports = { reactStrictMode: true }`,
+			},
+			dependency: JsNext,
+		},
+		{
+			name: "NextExportConfig",
+			files: map[string]string{
+				"package.json":    `{"dependencies": {"next": "^15.0.0"}}`,
+				"next.config.mjs": `export default { output: 'export' }`,
+			},
+			dependency: JsNext,
+			staticSite: true,
+		},
+		{
+			name: "NuxtSsr",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"nuxt": "^3.0.0"}, "scripts": {"build": "nuxt build"}}`,
+			},
+			dependency: JsNuxt,
+		},
+		{
+			name: "NuxtGenerate",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"nuxt": "^3.0.0"}, "scripts": {"generate": "nuxi generate"}}`,
+			},
+			dependency: JsNuxt,
+			staticSite: true,
+		},
+		{
+			name: "NuxtSsrDisabled",
+			files: map[string]string{
+				"package.json":   `{"dependencies": {"nuxt": "^3.0.0"}}`,
+				"nuxt.config.ts": `export default defineNuxtConfig({ ssr: false })`,
+			},
+			dependency: JsNuxt,
+			staticSite: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, tt.files)
+
+			projects, err := Detect(context.Background(), dir)
+			require.NoError(t, err)
+			require.Len(t, projects, 1)
+
+			require.Equal(t, filepath.Clean(dir), projects[0].Path)
+			require.Contains(t, projects[0].Dependencies, tt.dependency)
+			require.Equal(t, tt.staticSite, projects[0].StaticSite)
+			if tt.dependency == JsNuxt {
+				require.Equal(t, tt.staticSite, projects[0].HasWebUIFramework())
+			}
+		})
+	}
+}
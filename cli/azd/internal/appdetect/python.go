@@ -29,6 +29,7 @@ func (pd *pythonDetector) DetectProject(ctx context.Context, path string, entrie
 				Language:      Python,
 				Path:          path,
 				DetectionRule: "Inferred by presence of: " + entry.Name(),
+				Confidence:    ConfidenceMedium,
 			}
 
 			file, err := os.Open(filepath.Join(path, entry.Name()))
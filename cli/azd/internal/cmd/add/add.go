@@ -20,6 +20,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
@@ -284,6 +285,22 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		SuccessMessage: "azure.yaml updated.",
 	})
 
+	// Tell about the features the dev container configuration of the project misses for the new services. The
+	// configuration may have comments, which would be lost by rewriting it, so it's left to 'azd devcontainer sync'.
+	missingFeatures, err := devcontainer.Missing(prjConfig.Path, newCfg)
+	if err != nil {
+		log.Printf("checking the features of the dev container configuration: %v", err)
+	} else if len(missingFeatures) > 0 {
+		a.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"%s misses the features: %s. Run '%s' to add them.",
+				filepath.ToSlash(devcontainer.RelativePath),
+				strings.Join(missingFeatures, ", "),
+				output.WithHighLightFormat("azd devcontainer sync"),
+			),
+		})
+	}
+
 	// Use default project values for Infra when not specified in azure.yaml
 	if prjConfig.Infra.Module == "" {
 		prjConfig.Infra.Module = project.DefaultModule
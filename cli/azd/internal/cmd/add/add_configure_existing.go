@@ -8,6 +8,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/azure/azure-dev/cli/azd/internal/names"
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 )
@@ -57,5 +58,18 @@ func resourceType(azureResourceType string) project.ResourceType {
 		}
 	}
 
-	return project.ResourceType("")
+	// existing sub-resources are referenced by their parent, ex) the server of a database, when the parent is only
+	// used by one resource type
+	matched := project.ResourceType("")
+	for _, resourceType := range resourceTypes {
+		resourceMeta, ok := scaffold.ResourceMetaFromType(resourceType.AzureResourceType())
+		if ok && resourceMeta.ParentForEval == azureResourceType {
+			if matched != "" {
+				return project.ResourceType("")
+			}
+			matched = resourceType
+		}
+	}
+
+	return matched
 }
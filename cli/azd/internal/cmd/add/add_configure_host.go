@@ -348,6 +348,10 @@ func ServiceFromDetect(
 	svc.RelativePath = rel
 
 	language, supported := LanguageMap[prj.Language]
+	if prj.Language == appdetect.Dockerfile && prj.Docker != nil {
+		// Projects only built by their Dockerfile are only detected, they can't be added as a language
+		language, supported = project.ServiceLanguageDocker, true
+	}
 	if !supported {
 		return svc, fmt.Errorf("unsupported language: %s", prj.Language)
 	}
@@ -385,8 +389,19 @@ func ServiceFromDetect(
 		for _, dep := range prj.Dependencies {
 			switch dep {
 			case appdetect.JsNext:
-				// next.js works as SSR with default node configuration without static build output
+				// next.js works as SSR with default node configuration without static build output,
+				// and exports static sites to 'out'
 				svc.OutputPath = ""
+				if prj.StaticSite {
+					svc.OutputPath = "out"
+				}
+				break loop
+			case appdetect.JsNuxt:
+				// nuxt works as SSR with default node configuration, and generates static sites to '.output/public'
+				svc.OutputPath = ""
+				if prj.StaticSite {
+					svc.OutputPath = ".output/public"
+				}
 				break loop
 			case appdetect.JsVite:
 				svc.OutputPath = "dist"
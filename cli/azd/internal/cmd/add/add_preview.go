@@ -45,7 +45,17 @@ func Metadata(r *project.ResourceConfig) metaDisplay {
 				prefix = strings.ToUpper(r.Name)
 			}
 
-			variables := scaffold.EnvVars(prefix, res.Variables)
+			resVariables := res.Variables
+			if r.Existing {
+				// errors are surfaced when the infrastructure is generated
+				resVariables, _ = scaffold.ExistingVariables(resVariables)
+			}
+
+			variables := scaffold.EnvVars(prefix, resVariables)
+			if r.Type == project.ResourceTypeOpenAiModel && !r.Existing {
+				// each model deployment is exposed alongside the shared account endpoint
+				variables[scaffold.EnvVarName(prefix+"_"+environment.Key(r.Name), "deployment")] = r.Name
+			}
 			displayVariables := slices.Sorted(maps.Keys(variables))
 
 			display := metaDisplay{
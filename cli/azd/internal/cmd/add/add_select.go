@@ -194,13 +194,12 @@ func (a *AddAction) selectExistingResource(
 				continue
 			}
 
-			if menu.Namespace == "host" || // host resources are not yet supported
-				menu.Namespace == "db" { // db resources are not yet supported
+			// existing hosts are deployed to by setting the resourceName of a service instead
+			if menu.Namespace == "host" {
 				continue
 			}
 
 			selectMenu = append(selectMenu, menu)
-
 		}
 
 		slices.SortFunc(selectMenu, func(a, b Menu) int {
@@ -10,9 +10,13 @@ import (
 	"log"
 	"maps"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -55,106 +59,280 @@ func (a *AddAction) promptOpenAi(
 		return nil, err
 	}
 
-	var allModels []ModelList
-	for {
-		err = provisioning.EnsureSubscriptionAndLocation(
-			ctx, a.envManager, a.env, a.prompter, provisioning.EnsureSubscriptionAndLocationOptions{})
-		if err != nil {
-			return nil, err
-		}
-
-		console.ShowSpinner(
-			ctx,
-			fmt.Sprintf("Fetching available models in %s...", a.env.GetLocation()),
-			input.Step)
+	subId := a.env.GetSubscriptionId()
+	modelsByLocation, err := a.modelsByLocation(ctx, subId, "Retrieving available models...")
+	if err != nil {
+		return nil, err
+	}
 
-		supportedModels, err := a.supportedModelsInLocation(ctx, a.env.GetSubscriptionId(), a.env.GetLocation())
-		if err != nil {
-			return nil, err
+	catalog := openAiModelCatalog(modelsByLocation, func(model ModelList) bool {
+		switch aiOption {
+		case 0:
+			return model.Model.Name == "gpt-4o" || model.Model.Name == "gpt-4"
+		case 1:
+			return strings.HasPrefix(model.Model.Name, "text-embedding")
 		}
-		console.StopSpinner(ctx, "", input.Step)
+		return false
+	})
+	if len(catalog) == 0 {
+		return nil, errors.New("no Azure OpenAI models are available in this subscription")
+	}
 
-		for _, model := range supportedModels {
-			if model.Kind == "OpenAI" && slices.ContainsFunc(model.Model.Skus, func(sku ModelSku) bool {
-				return sku.Name == "Standard"
-			}) {
-				switch aiOption {
-				case 0:
-					if model.Model.Name == "gpt-4o" || model.Model.Name == "gpt-4" {
-						allModels = append(allModels, model)
-					}
-				case 1:
-					if strings.HasPrefix(model.Model.Name, "text-embedding") {
-						allModels = append(allModels, model)
-					}
-				}
-			}
+	modelName, versions, err := selectFromMap(ctx, console, "Which model do you want to use?", catalog, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		}
-		if len(allModels) > 0 {
+	var defaultVersion *string
+	for version, candidate := range versions {
+		if candidate.Model.IsDefaultVersion {
+			defaultVersion = &version
 			break
 		}
+	}
 
-		_, err = a.rm.FindResourceGroupForEnvironment(
-			ctx, a.env.GetSubscriptionId(), a.env.Name())
-		var notFoundError *azureutil.ResourceNotFoundError
-		if errors.As(err, &notFoundError) { // not yet provisioned, we're safe here
-			console.MessageUxItem(ctx, &ux.WarningMessage{
-				Description: fmt.Sprintf("No models found in %s", a.env.GetLocation()),
-			})
-			confirm, err := console.Confirm(ctx, input.ConsoleOptions{
-				Message: "Try a different location?",
-			})
-			if err != nil {
-				return nil, err
-			}
-			if confirm {
-				a.env.SetLocation("")
-				continue
-			}
-		} else if err != nil {
-			return nil, fmt.Errorf("finding resource group: %w", err)
-		}
+	modelVersion, candidate, err := selectFromMap(
+		ctx, console, "Which model version do you want to use?", versions, defaultVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	sku, err := selectFromSkus(ctx, console, "Select model SKU", candidate.Model.Skus)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil, fmt.Errorf("no models found in %s", a.env.GetLocation())
+	capacity, err := promptCapacity(ctx, console, sku.Capacity)
+	if err != nil {
+		return nil, err
 	}
 
-	slices.SortFunc(allModels, func(a ModelList, b ModelList) int {
-		return strings.Compare(b.Model.SystemData.CreatedAt, a.Model.SystemData.CreatedAt)
+	// Only keep the locations offering the selected SKU with enough remaining quota for the requested capacity
+	skuLocations := slices.DeleteFunc(slices.Clone(candidate.Locations), func(location string) bool {
+		return !slices.ContainsFunc(modelsByLocation[location], func(model ModelList) bool {
+			return model.Kind == "OpenAI" &&
+				model.Model.Name == modelName &&
+				model.Model.Version == modelVersion &&
+				slices.ContainsFunc(model.Model.Skus, func(s ModelSku) bool { return s.Name == sku.Name })
+		})
 	})
 
-	displayModels := make([]string, 0, len(allModels))
-	models := make([]Model, 0, len(allModels))
-	for _, model := range allModels {
-		models = append(models, model.Model)
-		displayModels = append(displayModels, fmt.Sprintf("%s\t%s", model.Model.Name, model.Model.Version))
-	}
+	console.ShowSpinner(ctx, "Checking quota in available locations...", input.Step)
+	locations := a.locationsWithQuota(ctx, subId, skuLocations, sku.UsageName, capacity)
+	console.StopSpinner(ctx, "", input.StepDone)
 
-	if console.IsSpinnerInteractive() {
-		displayModels, err = output.TabAlign(displayModels, 5)
-		if err != nil {
-			return nil, fmt.Errorf("writing models: %w", err)
+	if len(locations) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"no location has enough quota for %s %s (%s, capacity %d)", modelName, modelVersion, sku.Name, capacity),
+			Suggestion: "Select a lower capacity or request a quota increase at " +
+				output.WithHyperlink("https://aka.ms/oai/quotaincrease", "https://aka.ms/oai/quotaincrease"),
 		}
 	}
 
-	sel, err := console.Select(ctx, input.ConsoleOptions{
-		Message: "Select the model",
-		Options: displayModels,
-	})
-	if err != nil {
+	if err := a.ensureOpenAiLocation(ctx, console, locations); err != nil {
 		return nil, err
 	}
 
 	r.Props = project.AIModelProps{
 		Model: project.AIModelPropsModel{
-			Name:    models[sel].Name,
-			Version: models[sel].Version,
+			Name:    modelName,
+			Version: modelVersion,
+		},
+		Sku: project.AIModelPropsSku{
+			Name:     sku.Name,
+			Capacity: capacity,
 		},
 	}
 
 	return r, nil
 }
 
+// promptCapacity prompts for the capacity of a model deployment, in thousands of tokens per minute, within the bounds of
+// the SKU.
+func promptCapacity(ctx context.Context, console input.Console, skuCapacity ModelSkuCapacity) (int32, error) {
+	defaultCapacity := skuCapacity.Default
+	if defaultCapacity <= 0 {
+		defaultCapacity = 1
+	}
+
+	for {
+		value, err := console.Prompt(ctx, input.ConsoleOptions{
+			Message:      "Enter the deployment capacity (thousands of tokens per minute)",
+			DefaultValue: strconv.Itoa(int(defaultCapacity)),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		capacity, err := validateCapacity(value, skuCapacity)
+		if err != nil {
+			console.Message(ctx, output.WithErrorFormat(err.Error()))
+			continue
+		}
+
+		return capacity, nil
+	}
+}
+
+// validateCapacity parses the capacity and validates it against the minimum, maximum and step of the SKU.
+func validateCapacity(value string, skuCapacity ModelSkuCapacity) (int32, error) {
+	capacity, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+	if err != nil || capacity <= 0 {
+		return 0, fmt.Errorf("capacity must be a positive number")
+	}
+
+	if skuCapacity.Minimum > 0 && int32(capacity) < skuCapacity.Minimum {
+		return 0, fmt.Errorf("capacity must be at least %d", skuCapacity.Minimum)
+	}
+
+	if skuCapacity.Maximum > 0 && int32(capacity) > skuCapacity.Maximum {
+		return 0, fmt.Errorf("capacity must be at most %d", skuCapacity.Maximum)
+	}
+
+	if skuCapacity.Step > 1 && int32(capacity)%skuCapacity.Step != 0 {
+		return 0, fmt.Errorf("capacity must be a multiple of %d", skuCapacity.Step)
+	}
+
+	return int32(capacity), nil
+}
+
+// ensureOpenAiLocation ensures the location of the environment is one of the specified locations, prompting for a
+// location when the environment was not provisioned yet.
+func (a *AddAction) ensureOpenAiLocation(ctx context.Context, console input.Console, locations []string) error {
+	if location := a.env.GetLocation(); location != "" && !slices.Contains(locations, location) {
+		_, err := a.rm.FindResourceGroupForEnvironment(ctx, a.env.GetSubscriptionId(), a.env.Name())
+		var notFoundError *azureutil.ResourceNotFoundError
+		if err == nil {
+			return &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("the selected model is not available with enough quota in %s", location),
+				Suggestion: fmt.Sprintf(
+					"Select a lower capacity, or use a new environment in one of these locations: %s",
+					strings.Join(locations, ", ")),
+			}
+		} else if !errors.As(err, &notFoundError) {
+			return fmt.Errorf("finding resource group: %w", err)
+		}
+
+		// not yet provisioned, we're safe to change the location
+		console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf("The selected model is not available with enough quota in %s", location),
+		})
+		confirm, err := console.Confirm(ctx, input.ConsoleOptions{
+			Message:      "Select a different location?",
+			DefaultValue: true,
+		})
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return fmt.Errorf("the selected model is not available with enough quota in %s", location)
+		}
+
+		a.env.SetLocation("")
+	}
+
+	return provisioning.EnsureSubscriptionAndLocation(
+		ctx, a.envManager, a.env, a.prompter, provisioning.EnsureSubscriptionAndLocationOptions{
+			LocationFiler: func(loc account.Location) bool {
+				return slices.Contains(locations, loc.Name)
+			},
+		})
+}
+
+// openAiModelCatalog groups the OpenAI models offering a standard deployment SKU by model name and version.
+func openAiModelCatalog(
+	modelsByLocation map[string][]ModelList,
+	filter func(model ModelList) bool,
+) map[string]map[string]ModelCatalog {
+	catalog := map[string]map[string]ModelCatalog{}
+	for _, location := range slices.Sorted(maps.Keys(modelsByLocation)) {
+		for _, model := range modelsByLocation[location] {
+			if model.Kind != "OpenAI" || !filter(model) {
+				continue
+			}
+
+			model.Model.Skus = slices.DeleteFunc(slices.Clone(model.Model.Skus), func(sku ModelSku) bool {
+				return !strings.Contains(sku.Name, "Standard")
+			})
+			if len(model.Model.Skus) == 0 {
+				continue
+			}
+
+			versions, has := catalog[model.Model.Name]
+			if !has {
+				versions = map[string]ModelCatalog{}
+				catalog[model.Model.Name] = versions
+			}
+
+			entry, has := versions[model.Model.Version]
+			if !has {
+				entry = ModelCatalog{ModelList: model}
+			} else {
+				// SKUs vary per location, the catalog entry offers any of them
+				for _, sku := range model.Model.Skus {
+					if !slices.ContainsFunc(entry.Model.Skus, func(s ModelSku) bool { return s.Name == sku.Name }) {
+						entry.Model.Skus = append(entry.Model.Skus, sku)
+					}
+				}
+			}
+			entry.Locations = append(entry.Locations, location)
+			versions[model.Model.Version] = entry
+		}
+	}
+
+	return catalog
+}
+
+// locationsWithQuota returns the locations where the remaining quota for the usage is at least the specified capacity.
+func (a *AddAction) locationsWithQuota(
+	ctx context.Context,
+	subId string,
+	locations []string,
+	usageName string,
+	capacity int32,
+) []string {
+	var sharedResults sync.Map
+	var wg sync.WaitGroup
+
+	for _, location := range locations {
+		wg.Add(1)
+		go func(location string) {
+			defer wg.Done()
+			usages, err := a.azureClient.GetAiUsages(ctx, subId, location)
+			if err != nil {
+				// log the error and continue. Do not fail the entire operation when pulling location error
+				log.Println("error getting usage for location", location, ":", err, "skipping")
+				return
+			}
+			sharedResults.Store(location, usages)
+		}(location)
+	}
+	wg.Wait()
+
+	var results []string
+	sharedResults.Range(func(location, value any) bool {
+		if hasQuota(value.([]*armcognitiveservices.Usage), usageName, capacity) {
+			results = append(results, location.(string))
+		}
+		return true
+	})
+
+	slices.Sort(results)
+	return results
+}
+
+// hasQuota returns true when the remaining quota for the usage is at least the specified capacity.
+func hasQuota(usages []*armcognitiveservices.Usage, usageName string, capacity int32) bool {
+	return slices.ContainsFunc(usages, func(usage *armcognitiveservices.Usage) bool {
+		if usage.Name == nil || usage.Name.Value == nil || usage.Limit == nil || usage.CurrentValue == nil {
+			return false
+		}
+
+		return *usage.Name.Value == usageName && *usage.Limit-*usage.CurrentValue >= float64(capacity)
+	})
+}
+
 func (a *AddAction) supportedModelsInLocation(ctx context.Context, subId, location string) ([]ModelList, error) {
 	models, err := a.azureClient.GetAiModels(ctx, subId, location)
 	if err != nil {
@@ -349,54 +527,13 @@ func selectFromSkus(ctx context.Context, console input.Console, q string, s []Mo
 
 func (a *AddAction) aiDeploymentCatalog(
 	ctx context.Context, subId string, excludeModels []project.AiServicesModel) (map[string]ModelCatalogKind, error) {
-	allLocations, err := a.accountManager.GetLocations(ctx, subId)
+	modelsByLocation, err := a.modelsByLocation(ctx, subId, "Retrieving available models...")
 	if err != nil {
-		return nil, fmt.Errorf("getting locations: %w", err)
+		return nil, err
 	}
 
-	var sharedResults sync.Map
-	var wg sync.WaitGroup
-
-	a.console.ShowSpinner(ctx, "Retrieving available models...", input.Step)
-
-	for _, location := range allLocations {
-		wg.Add(1)
-		go func(location string) {
-			defer wg.Done()
-			results, err := a.supportedModelsInLocation(ctx, subId, location)
-			if err != nil {
-				// log the error and continue. Do not fail the entire operation when pulling location error
-				log.Println("error getting models in location", location, ":", err, "skipping")
-				return
-			}
-			var filterSkusWithZeroCapacity []ModelList
-			for _, model := range results {
-				if len(model.Model.Skus) == 0 {
-					continue
-				}
-				var skus []ModelSku
-				for _, sku := range model.Model.Skus {
-					if sku.Capacity.Default > 0 {
-						skus = append(skus, sku)
-					}
-				}
-				if len(skus) == 0 {
-					continue
-				}
-				model.Model.Skus = skus
-				filterSkusWithZeroCapacity = append(filterSkusWithZeroCapacity, model)
-			}
-			sharedResults.Store(location, filterSkusWithZeroCapacity)
-		}(location.Name)
-	}
-	wg.Wait()
-	a.console.StopSpinner(ctx, "", input.StepDone)
-
 	combinedResults := map[string]ModelCatalogKind{}
-	sharedResults.Range(func(key, value any) bool {
-		// cast should be safe as the call to sharedResults.Store() use a string key
-		locationNameKey := key.(string)
-		models := value.([]ModelList)
+	for locationNameKey, models := range modelsByLocation {
 		for _, model := range models {
 			if model.Kind == "OpenAI" {
 				// OpenAI kind is part of the `Add OpenAI` where clients connect directly to the service w/o an AIProject
@@ -464,9 +601,64 @@ func (a *AddAction) aiDeploymentCatalog(
 				combinedResults[nameKey] = modelKey
 			}
 		}
+	}
+	return combinedResults, nil
+}
+
+// modelsByLocation returns the models with a deployable SKU in every location of the subscription.
+func (a *AddAction) modelsByLocation(
+	ctx context.Context, subId string, spinnerMessage string) (map[string][]ModelList, error) {
+	allLocations, err := a.accountManager.GetLocations(ctx, subId)
+	if err != nil {
+		return nil, fmt.Errorf("getting locations: %w", err)
+	}
+
+	var sharedResults sync.Map
+	var wg sync.WaitGroup
+
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	for _, location := range allLocations {
+		wg.Add(1)
+		go func(location string) {
+			defer wg.Done()
+			results, err := a.supportedModelsInLocation(ctx, subId, location)
+			if err != nil {
+				// log the error and continue. Do not fail the entire operation when pulling location error
+				log.Println("error getting models in location", location, ":", err, "skipping")
+				return
+			}
+			var filterSkusWithZeroCapacity []ModelList
+			for _, model := range results {
+				if len(model.Model.Skus) == 0 {
+					continue
+				}
+				var skus []ModelSku
+				for _, sku := range model.Model.Skus {
+					if sku.Capacity.Default > 0 {
+						skus = append(skus, sku)
+					}
+				}
+				if len(skus) == 0 {
+					continue
+				}
+				model.Model.Skus = skus
+				filterSkusWithZeroCapacity = append(filterSkusWithZeroCapacity, model)
+			}
+			sharedResults.Store(location, filterSkusWithZeroCapacity)
+		}(location.Name)
+	}
+	wg.Wait()
+	a.console.StopSpinner(ctx, "", input.StepDone)
+
+	results := map[string][]ModelList{}
+	sharedResults.Range(func(key, value any) bool {
+		// cast should be safe as the call to sharedResults.Store() use a string key
+		results[key.(string)] = value.([]ModelList)
 		return true
 	})
-	return combinedResults, nil
+
+	return results, nil
 }
 
 type ModelCatalog struct {
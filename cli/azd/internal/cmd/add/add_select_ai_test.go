@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package add
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCapacity(t *testing.T) {
+	skuCapacity := ModelSkuCapacity{Minimum: 10, Maximum: 100, Step: 10, Default: 20}
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    int32
+		expectedErr string
+	}{
+		{name: "Valid", value: "30", expected: 30},
+		{name: "Trimmed", value: " 100 ", expected: 100},
+		{name: "NotANumber", value: "lots", expectedErr: "positive number"},
+		{name: "Zero", value: "0", expectedErr: "positive number"},
+		{name: "BelowMinimum", value: "5", expectedErr: "at least 10"},
+		{name: "AboveMaximum", value: "110", expectedErr: "at most 100"},
+		{name: "NotAStep", value: "25", expectedErr: "multiple of 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capacity, err := validateCapacity(tt.value, skuCapacity)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, capacity)
+		})
+	}
+}
+
+func TestOpenAiModelCatalog(t *testing.T) {
+	model := func(kind, name, version string, skus ...string) ModelList {
+		m := ModelList{Kind: kind, Model: Model{Name: name, Version: version}}
+		for _, sku := range skus {
+			m.Model.Skus = append(m.Model.Skus, ModelSku{Name: sku, UsageName: "OpenAI." + sku + "." + name})
+		}
+		return m
+	}
+
+	modelsByLocation := map[string][]ModelList{
+		"eastus": {
+			model("OpenAI", "gpt-4o", "2024-08-06", "Standard"),
+			model("OpenAI", "gpt-4o", "2024-11-20", "ProvisionedManaged"),
+			model("AIServices", "gpt-4o", "2024-08-06", "Standard"),
+		},
+		"swedencentral": {
+			model("OpenAI", "gpt-4o", "2024-08-06", "GlobalStandard"),
+			model("OpenAI", "text-embedding-3-large", "1", "Standard"),
+		},
+	}
+
+	catalog := openAiModelCatalog(modelsByLocation, func(model ModelList) bool {
+		return model.Model.Name == "gpt-4o"
+	})
+
+	require.Len(t, catalog, 1)
+	require.Len(t, catalog["gpt-4o"], 1)
+
+	entry := catalog["gpt-4o"]["2024-08-06"]
+	require.Equal(t, []string{"eastus", "swedencentral"}, entry.Locations)
+
+	skus := []string{}
+	for _, sku := range entry.Model.Skus {
+		skus = append(skus, sku.Name)
+	}
+	require.Equal(t, []string{"Standard", "GlobalStandard"}, skus)
+}
+
+func TestHasQuota(t *testing.T) {
+	usages := []*armcognitiveservices.Usage{
+		{
+			Name:         &armcognitiveservices.MetricName{Value: to.Ptr("OpenAI.Standard.gpt-4o")},
+			Limit:        to.Ptr(float64(100)),
+			CurrentValue: to.Ptr(float64(70)),
+		},
+		{
+			Name: &armcognitiveservices.MetricName{Value: to.Ptr("OpenAI.GlobalStandard.gpt-4o")},
+		},
+	}
+
+	require.True(t, hasQuota(usages, "OpenAI.Standard.gpt-4o", 30))
+	require.False(t, hasQuota(usages, "OpenAI.Standard.gpt-4o", 31))
+	require.False(t, hasQuota(usages, "OpenAI.GlobalStandard.gpt-4o", 1))
+	require.False(t, hasQuota(usages, "OpenAI.Standard.gpt-4", 1))
+}
@@ -329,3 +329,12 @@ func TestPathHasInfraModule(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceType(t *testing.T) {
+	require.Equal(t, project.ResourceTypeStorage, resourceType("Microsoft.Storage/storageAccounts"))
+	// existing databases are referenced by their server
+	require.Equal(t, project.ResourceTypeDbPostgres, resourceType("Microsoft.DBforPostgreSQL/flexibleServers"))
+	// accounts of several resource types are ambiguous
+	require.Equal(t, project.ResourceType(""), resourceType("Microsoft.DocumentDB/databaseAccounts"))
+	require.Equal(t, project.ResourceType(""), resourceType("Microsoft.Web/serverFarms"))
+}
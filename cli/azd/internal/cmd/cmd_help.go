@@ -13,7 +13,7 @@ import (
 
 // formatHelpNote provides the expected format in description notes using `•`.
 func formatHelpNote(note string) string {
-	return fmt.Sprintf("  • %s", note)
+	return fmt.Sprintf("  %s %s", output.Bullet(), note)
 }
 
 // generateCmdHelpDescription construct a help text block from a title and description notes.
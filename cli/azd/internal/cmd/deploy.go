@@ -8,8 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
@@ -17,23 +22,35 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/status"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type DeployFlags struct {
-	ServiceName string
-	All         bool
-	fromPackage string
-	global      *internal.GlobalCommandOptions
+	ServiceName    string
+	All            bool
+	fromPackage    string
+	image          string
+	revisionSuffix string
+	promote        bool
+	rollback       bool
+	noPurge        bool
+	settingsOnly   bool
+	global         *internal.GlobalCommandOptions
 	*internal.EnvFlag
 }
 
@@ -72,7 +89,44 @@ func (d *DeployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		"from-package",
 		"",
 		//nolint:lll
-		"Deploys the packaged service located at the provided path. Supports zipped file packages (file path) or container images (image tag).",
+		"Deploys the packaged service located at the provided path. Supports zipped file packages (file path), container images (image tag) or packages published with 'azd package --output-format oci' (oci://<registry>/<repository>:<tag>).",
+	)
+	local.StringVar(
+		&d.image,
+		"image",
+		"",
+		//nolint:lll
+		"Deploys the container image pushed earlier, by tag or digest (<registry>/<repository>@sha256:<digest>), without building it. Supports container app, AKS, ACI and container app service services.",
+	)
+	local.StringVar(
+		&d.revisionSuffix,
+		"revision-suffix",
+		"",
+		"Sets the suffix of the new revision of container app services.",
+	)
+	local.BoolVar(
+		&d.promote,
+		"promote",
+		false,
+		"Sends all the traffic of container app services to their latest revision, without deploying.",
+	)
+	local.BoolVar(
+		&d.rollback,
+		"rollback",
+		false,
+		"Sends all the traffic of container app services back to their previous revision, without deploying.",
+	)
+	local.BoolVar(
+		&d.noPurge,
+		"no-purge",
+		false,
+		"Skips purging the Azure Front Door or CDN endpoints of web services after deploying them.",
+	)
+	local.BoolVar(
+		&d.settingsOnly,
+		"settings-only",
+		false,
+		"Updates the app settings of app service and function services, without deploying their code.",
 	)
 }
 
@@ -122,6 +176,12 @@ type DeployAction struct {
 	commandRunner       exec.CommandRunner
 	alphaFeatureManager *alpha.FeatureManager
 	importManager       *project.ImportManager
+	containerAppService containerapps.ContainerAppService
+	resourceService     *azapi.ResourceService
+	cdnService          azapi.CdnService
+	customDomainService azapi.CustomDomainService
+	realtimeService     azapi.RealtimeService
+	packageArtifacts    *project.PackageArtifacts
 }
 
 func NewDeployAction(
@@ -142,6 +202,12 @@ func NewDeployAction(
 	writer io.Writer,
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	containerAppService containerapps.ContainerAppService,
+	resourceService *azapi.ResourceService,
+	cdnService azapi.CdnService,
+	customDomainService azapi.CustomDomainService,
+	realtimeService azapi.RealtimeService,
+	packageArtifacts *project.PackageArtifacts,
 ) actions.Action {
 	return &DeployAction{
 		flags:               flags,
@@ -161,6 +227,12 @@ func NewDeployAction(
 		commandRunner:       commandRunner,
 		alphaFeatureManager: alphaFeatureManager,
 		importManager:       importManager,
+		containerAppService: containerAppService,
+		resourceService:     resourceService,
+		cdnService:          cdnService,
+		customDomainService: customDomainService,
+		realtimeService:     realtimeService,
+		packageArtifacts:    packageArtifacts,
 	}
 }
 
@@ -206,6 +278,55 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
+	if da.flags.image != "" && (da.flags.All || targetServiceName == "") {
+		return nil, errors.New(
+			"'--image' cannot be specified when deploying all services. Specify a specific service by passing a <service>")
+	}
+
+	if da.flags.image != "" && da.flags.fromPackage != "" {
+		return nil, errors.New("'--image' and '--from-package' cannot be specified together")
+	}
+
+	if da.flags.promote && da.flags.rollback {
+		return nil, errors.New("'--promote' and '--rollback' cannot be specified together")
+	}
+
+	if (da.flags.promote || da.flags.rollback) &&
+		(da.flags.fromPackage != "" || da.flags.image != "" || da.flags.revisionSuffix != "") {
+		return nil, errors.New(
+			"'--from-package', '--image' and '--revision-suffix' cannot be specified with '--promote' or '--rollback'")
+	}
+
+	if da.flags.settingsOnly && (da.flags.fromPackage != "" || da.flags.image != "" || da.flags.revisionSuffix != "" ||
+		da.flags.promote || da.flags.rollback) {
+		return nil, errors.New(
+			//nolint:lll
+			"'--from-package', '--image', '--revision-suffix', '--promote' and '--rollback' cannot be specified with '--settings-only'")
+	}
+
+	var imagePackage *project.ImagePackageResult
+	if da.flags.image != "" {
+		svc, has := da.projectConfig.Services[targetServiceName]
+		if has && !project.SupportsImagePackage(svc) {
+			return nil, fmt.Errorf(
+				"'--image' isn't supported by the service '%s' (host: %s), which isn't deployed as a container image",
+				svc.Name, svc.Host)
+		}
+
+		imagePackage, err = project.NewImagePackageResult(da.flags.image)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if da.flags.promote || da.flags.rollback {
+		return da.shiftTraffic(ctx, targetServiceName)
+	}
+
+	if da.flags.settingsOnly {
+		return da.updateSettings(ctx, targetServiceName)
+	}
+
 	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
 		return nil, err
 	}
@@ -223,6 +344,16 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	startTime := time.Now()
 
+	// Packages published to a registry are downloaded to a temp directory, removed once the services are deployed
+	var packagesDir string
+	if strings.HasPrefix(da.flags.fromPackage, containerregistry.ArtifactScheme) {
+		packagesDir, err = os.MkdirTemp("", "azd-package")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(packagesDir)
+	}
+
 	deployResults := map[string]*project.ServiceDeployResult{}
 	stableServices, err := da.importManager.ServiceStable(ctx, da.projectConfig)
 	if err != nil {
@@ -241,6 +372,10 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			continue
 		}
 
+		if da.flags.revisionSuffix != "" && svc.Host == project.ContainerAppTarget {
+			svc.ContainerApp.RevisionSuffix = osutil.NewExpandableString(da.flags.revisionSuffix)
+		}
+
 		if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(svc.Host)); isAlphaFeature {
 			// alpha feature on/off detection for host is done during initialization.
 			// This is just for displaying the warning during deployment.
@@ -248,7 +383,20 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 
 		var packageResult *project.ServicePackageResult
-		if da.flags.fromPackage != "" {
+		if strings.HasPrefix(da.flags.fromPackage, containerregistry.ArtifactScheme) {
+			// --from-package references a package published with 'azd package --output-format oci'
+			packageResult, err = da.fetchPackage(ctx, svc, filepath.Join(packagesDir, svc.Name))
+			if err != nil {
+				da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+				return nil, err
+			}
+		} else if imagePackage != nil {
+			// --image set, deploy the image pushed earlier without building it
+			packageResult = &project.ServicePackageResult{
+				PackagePath: imagePackage.Image,
+				Details:     imagePackage,
+			}
+		} else if da.flags.fromPackage != "" {
 			// --from-package set, skip packaging
 			packageResult = &project.ServicePackageResult{
 				PackagePath: da.flags.fromPackage,
@@ -288,9 +436,28 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 
 		deployResults[svc.Name] = deployResult
+		status.FromContext(ctx).SetService(status.Service{
+			Name:        svc.Name,
+			Endpoints:   deployResult.Endpoints,
+			Image:       da.env.GetServiceProperty(svc.Name, "IMAGE_NAME"),
+			ImageDigest: da.env.GetServiceProperty(svc.Name, "IMAGE_DIGEST"),
+		})
 
 		// report deploy outputs
 		da.console.MessageUxItem(ctx, deployResult)
+
+		if !da.flags.noPurge && (svc.Host == project.AppServiceTarget || svc.Host == project.StaticWebAppTarget ||
+			svc.Host == project.StorageStaticSiteTarget) {
+			da.purgeCdnEndpoints(ctx, svc, deployResult)
+		}
+
+		if len(svc.Domains) > 0 {
+			da.bindCustomDomains(ctx, svc, deployResult)
+		}
+
+		if !svc.Realtime.Resource.Empty() {
+			da.configureRealtimeHandler(ctx, svc, deployResult)
+		}
 	}
 
 	aspireDashboardUrl := apphost.AspireDashboardUrl(ctx, da.env, da.alphaFeatureManager)
@@ -324,6 +491,246 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}, nil
 }
 
+// TrafficShiftResult is the traffic of container app services after 'azd deploy --promote' or 'azd deploy --rollback'
+type TrafficShiftResult struct {
+	Timestamp time.Time                                   `json:"timestamp"`
+	Services  map[string][]*containerapps.RevisionTraffic `json:"services"`
+}
+
+// shiftTraffic sends all the traffic of the container app services to their latest or previous revision.
+func (da *DeployAction) shiftTraffic(ctx context.Context, targetServiceName string) (*actions.ActionResult, error) {
+	target := containerapps.TrafficTargetLatest
+	title := "Promoting latest revisions (azd deploy --promote)"
+	if da.flags.rollback {
+		target = containerapps.TrafficTargetPrevious
+		title = "Rolling back to previous revisions (azd deploy --rollback)"
+	}
+
+	services, err := da.importManager.ServiceStable(ctx, da.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var containerAppServices []*project.ServiceConfig
+	for _, svc := range services {
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			continue
+		}
+
+		if svc.Host != project.ContainerAppTarget {
+			if targetServiceName != "" {
+				return nil, fmt.Errorf(
+					"service '%s' is hosted with '%s', only '%s' services have revisions",
+					svc.Name,
+					svc.Host,
+					project.ContainerAppTarget,
+				)
+			}
+
+			continue
+		}
+
+		containerAppServices = append(containerAppServices, svc)
+	}
+
+	if len(containerAppServices) == 0 {
+		return nil, fmt.Errorf("no '%s' services found to shift traffic for", project.ContainerAppTarget)
+	}
+
+	da.console.MessageUxItem(ctx, &ux.MessageTitle{Title: title})
+
+	results := map[string][]*containerapps.RevisionTraffic{}
+	for _, svc := range containerAppServices {
+		stepMessage := fmt.Sprintf("Shifting traffic of service %s", svc.Name)
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		traffic, err := da.shiftServiceTraffic(ctx, svc, target)
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+
+		results[svc.Name] = traffic
+
+		lines := make([]string, 0, len(traffic))
+		for _, revision := range traffic {
+			line := fmt.Sprintf("  - %s: %d%%", revision.RevisionName, revision.Weight)
+			if revision.Latest {
+				line += output.WithGrayFormat(" (latest)")
+			}
+			lines = append(lines, line)
+		}
+		da.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: lines})
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		result := TrafficShiftResult{
+			Timestamp: time.Now(),
+			Services:  results,
+		}
+
+		if fmtErr := da.formatter.Format(result, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("traffic result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	header := "Traffic was shifted to the latest revisions."
+	if da.flags.rollback {
+		header = "Traffic was shifted back to the previous revisions."
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: header,
+		},
+	}, nil
+}
+
+func (da *DeployAction) shiftServiceTraffic(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	target containerapps.TrafficTarget,
+) ([]*containerapps.RevisionTraffic, error) {
+	targetResource, err := da.resourceManager.GetTargetResource(ctx, da.env.GetSubscriptionId(), svc)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource for service '%s': %w", svc.Name, err)
+	}
+
+	return da.containerAppService.ShiftTraffic(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		target,
+		&containerapps.ContainerAppOptions{
+			ApiVersion: svc.ApiVersion,
+		},
+	)
+}
+
+// AppSettingsResult is the app settings changed by 'azd deploy --settings-only'
+type AppSettingsResult struct {
+	Timestamp time.Time                           `json:"timestamp"`
+	Services  map[string][]azapi.AppSettingChange `json:"services"`
+}
+
+// updateSettings applies the app settings of the app service and function services, without deploying their code.
+// Only the settings that changed are written, so the apps without changes aren't restarted.
+func (da *DeployAction) updateSettings(ctx context.Context, targetServiceName string) (*actions.ActionResult, error) {
+	services, err := da.importManager.ServiceStable(ctx, da.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var settingsServices []*project.ServiceConfig
+	for _, svc := range services {
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			continue
+		}
+
+		if svc.Host != project.AppServiceTarget && svc.Host != project.AzureFunctionTarget {
+			if targetServiceName != "" {
+				return nil, fmt.Errorf(
+					"service '%s' is hosted with '%s', only '%s' and '%s' services have app settings",
+					svc.Name,
+					svc.Host,
+					project.AppServiceTarget,
+					project.AzureFunctionTarget,
+				)
+			}
+
+			continue
+		}
+
+		if len(svc.AppService.AppSettings) == 0 {
+			continue
+		}
+
+		settingsServices = append(settingsServices, svc)
+	}
+
+	if len(settingsServices) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: errors.New("no services with app settings found"),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: declare the settings of '%s' and '%s' services in 'appService.appSettings' of %s",
+				project.AppServiceTarget,
+				project.AzureFunctionTarget,
+				azdcontext.ProjectFileName,
+			),
+		}
+	}
+
+	da.console.MessageUxItem(ctx, &ux.MessageTitle{Title: "Updating app settings (azd deploy --settings-only)"})
+
+	results := map[string][]azapi.AppSettingChange{}
+	for _, svc := range settingsServices {
+		changes, err := da.updateServiceSettings(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+
+		results[svc.Name] = changes
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		result := AppSettingsResult{
+			Timestamp: time.Now(),
+			Services:  results,
+		}
+
+		if fmtErr := da.formatter.Format(result, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("app settings result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Your app settings were updated.",
+		},
+	}, nil
+}
+
+// updateServiceSettings prints the changes to the app settings of the service before applying them.
+func (da *DeployAction) updateServiceSettings(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+) ([]azapi.AppSettingChange, error) {
+	stepMessage := fmt.Sprintf("Comparing app settings of service %s", svc.Name)
+	da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	targetResource, err := da.resourceManager.GetTargetResource(ctx, da.env.GetSubscriptionId(), svc)
+	if err != nil {
+		da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+		return nil, fmt.Errorf("getting target resource for service '%s': %w", svc.Name, err)
+	}
+
+	changes, err := project.AppSettingChanges(ctx, da.azCli, da.env, svc, targetResource)
+	da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	da.console.MessageUxItem(ctx, &ux.MultilineMessage{
+		Lines: []string{fmt.Sprintf("  - App settings: %s", project.FormatAppSettingChanges(changes))},
+	})
+
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	stepMessage = fmt.Sprintf("Updating app settings of service %s", svc.Name)
+	da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	changes, err = project.UpdateAppSettings(ctx, da.azCli, da.env, svc, targetResource)
+	da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
 func GetCmdDeployHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription("Deploy application to Azure.", []string{
 		formatHelpNote(
@@ -350,5 +757,286 @@ func GetCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Deploy the service named 'api' to Azure from a package published to a container registry.": output.WithHighLightFormat(
+			"azd deploy api --from-package oci://myregistry.azurecr.io/myapp:v1",
+		),
+		"Deploy the image of the service named 'web' pushed earlier, without building it.": output.WithHighLightFormat(
+			"azd deploy web --image myregistry.azurecr.io/web@sha256:<digest>",
+		),
+		"Send the traffic of the container app service named 'api' to its latest revision.": output.WithHighLightFormat(
+			"azd deploy api --promote",
+		),
+		"Send the traffic of the container app service named 'api' to its previous revision.": output.WithHighLightFormat(
+			"azd deploy api --rollback",
+		),
+		"Update the app settings of the service named 'api', without deploying its code.": output.WithHighLightFormat(
+			"azd deploy api --settings-only",
+		),
+	})
+}
+
+// fetchPackage gets the package of the service published at the oci:// reference of '--from-package'. References to
+// the repository of a project, as given to 'azd package --output-format oci', are resolved to the repository of the
+// service. The package is downloaded to the directory.
+func (da *DeployAction) fetchPackage(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	directory string,
+) (*project.ServicePackageResult, error) {
+	reference, err := containerregistry.ParseArtifactReference(da.flags.fromPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceRepository := strings.ToLower(svc.Name)
+	if !strings.HasSuffix(reference.Repository, "/"+serviceRepository) {
+		reference.Repository = fmt.Sprintf("%s/%s", reference.Repository, serviceRepository)
+	}
+
+	return da.packageArtifacts.Fetch(ctx, reference, directory)
+}
+
+// The default paths purged from the Front Door / CDN endpoints of services after a deployment
+var defaultCdnPurgePaths = []string{"/*"}
+
+// purgeCdnEndpoints purges the cached content of the Azure Front Door or CDN endpoints of a web service after it's
+// deployed, so that the new version is served right away. Failures are reported as warnings since the service itself
+// has been deployed.
+func (da *DeployAction) purgeCdnEndpoints(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	deployResult *project.ServiceDeployResult,
+) {
+	endpointIds, err := da.cdnEndpoints(ctx, svc, deployResult)
+	if err != nil {
+		log.Printf("failed finding Front Door / CDN endpoints of service '%s': %v", svc.Name, err)
+		return
+	}
+
+	purgePaths := svc.Cdn.PurgePaths
+	if len(purgePaths) == 0 {
+		purgePaths = defaultCdnPurgePaths
+	}
+
+	for _, endpointId := range endpointIds {
+		endpointName := endpointId
+		if resourceId, err := arm.ParseResourceID(endpointId); err == nil {
+			endpointName = resourceId.Name
+		}
+
+		stepMessage := fmt.Sprintf("Purging endpoint %s of service %s", endpointName, svc.Name)
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		if err := da.cdnService.PurgeContent(ctx, endpointId, purgePaths); err != nil {
+			da.console.StopSpinner(ctx, stepMessage, input.StepWarning)
+			da.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: %s. Run 'azd deploy %s' again or purge the endpoint from the Azure Portal.", err, svc.Name))
+			continue
+		}
+
+		da.console.StopSpinner(ctx, stepMessage, input.StepDone)
+	}
+}
+
+// cdnEndpoints gets the IDs of the Front Door or CDN endpoints of a service: the endpoint set in the
+// SERVICE_<NAME>_CDN_ENDPOINT_ID environment variable, or the endpoints tagged with the name of the service in the
+// resource group of the service.
+func (da *DeployAction) cdnEndpoints(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	deployResult *project.ServiceDeployResult,
+) ([]string, error) {
+	if endpointId := da.env.GetServiceProperty(svc.Name, "CDN_ENDPOINT_ID"); endpointId != "" {
+		return []string{endpointId}, nil
+	}
+
+	targetResourceId, err := arm.ParseResourceID(deployResult.TargetResourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", azure.TagKeyAzdServiceName, svc.Name)
+	resources, err := da.resourceService.ListResourceGroupResources(
+		ctx,
+		targetResourceId.SubscriptionID,
+		targetResourceId.ResourceGroupName,
+		&azapi.ListResourceGroupResourcesOptions{Filter: &filter},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointIds := []string{}
+	for _, resource := range resources {
+		if strings.EqualFold(resource.Type, string(azapi.AzureResourceTypeFrontDoorEndpoint)) ||
+			strings.EqualFold(resource.Type, string(azapi.AzureResourceTypeCDNEndpoint)) {
+			endpointIds = append(endpointIds, resource.Id)
+		}
+	}
+
+	return endpointIds, nil
+}
+
+// bindCustomDomains binds the custom domains of a web service after it's deployed, reporting the status of each domain.
+// The DNS records validating a domain are created when its Azure DNS zone is in the subscription of the service,
+// otherwise the records to create are listed. Failures are reported as warnings, since the service was deployed.
+func (da *DeployAction) bindCustomDomains(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	deployResult *project.ServiceDeployResult,
+) {
+	if svc.Host != project.AppServiceTarget && svc.Host != project.ContainerAppTarget &&
+		svc.Host != project.StaticWebAppTarget {
+		da.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: Custom domains aren't supported for service '%s' hosted on '%s'.", svc.Name, svc.Host))
+		return
+	}
+
+	targetResourceId, err := arm.ParseResourceID(deployResult.TargetResourceId)
+	if err != nil {
+		log.Printf("failed parsing the target resource of service '%s': %v", svc.Name, err)
+		return
+	}
+
+	for _, domain := range svc.Domains {
+		hostName, err := domain.Name.Envsubst(da.env.Getenv)
+		if err != nil || hostName == "" {
+			da.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: Skipping a custom domain of service '%s' which doesn't have a name.", svc.Name))
+			continue
+		}
+
+		stepMessage := fmt.Sprintf("Binding domain %s to service %s", hostName, svc.Name)
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		pendingRecords, err := da.bindCustomDomain(ctx, targetResourceId, hostName, domain.Certificate)
+		if err != nil {
+			da.console.StopSpinner(ctx, stepMessage, input.StepWarning)
+			da.console.Message(ctx, output.WithWarningFormat("WARNING: %s.", err))
+
+			if len(pendingRecords) > 0 {
+				da.console.Message(ctx, fmt.Sprintf(
+					"Create the following DNS records, then run %s again:",
+					output.WithHighLightFormat("azd deploy %s", svc.Name)))
+				for _, record := range pendingRecords {
+					da.console.Message(ctx, fmt.Sprintf("  %-6s %s -> %s", record.Type, record.Name, record.Value))
+				}
+				da.console.Message(ctx, "")
+			}
+			continue
+		}
+
+		da.console.StopSpinner(ctx, stepMessage, input.StepDone)
+	}
+}
+
+// bindCustomDomain validates and binds a custom domain to the target resource of a service. On failure, the DNS records
+// which must exist for the domain to be validated are returned.
+func (da *DeployAction) bindCustomDomain(
+	ctx context.Context,
+	targetResourceId *arm.ResourceID,
+	hostName string,
+	certificate project.DomainCertificateKind,
+) ([]azapi.DnsRecord, error) {
+	if certificate != "" && certificate != project.DomainCertificateManaged &&
+		certificate != project.DomainCertificateNone {
+		return nil, fmt.Errorf(
+			"invalid certificate '%s' for domain '%s', supported values are 'managed' and 'none'", certificate, hostName)
+	}
+
+	records, err := da.customDomainService.DomainRecords(ctx, targetResourceId.String(), hostName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The records of domains which zone isn't in the subscription may have been created by the user
+	zone, err := da.customDomainService.FindDnsZone(ctx, targetResourceId.SubscriptionID, hostName)
+	if err != nil {
+		log.Printf("failed finding the DNS zone of domain '%s': %v", hostName, err)
+	}
+
+	if zone != nil {
+		for _, record := range records {
+			if err := da.customDomainService.SetDnsRecord(ctx, zone, record); err != nil {
+				return records, err
+			}
+		}
+	}
+
+	managedCertificate := certificate != project.DomainCertificateNone
+	if err := da.customDomainService.BindDomain(
+		ctx, targetResourceId.String(), hostName, managedCertificate); err != nil {
+		if zone == nil {
+			return records, fmt.Errorf(
+				"domain '%s' couldn't be validated and its DNS zone isn't in the subscription: %w", hostName, err)
+		}
+
+		return records, err
+	}
+
+	return nil, nil
+}
+
+// The default url path of the event handler of a realtime backend
+const defaultRealtimeHandlerPath = "/eventhandler"
+
+// configureRealtimeHandler points the event handler of the Web PubSub hub, or the upstream of the SignalR Service, of a
+// realtime backend at the endpoint of the service after it's deployed, so it follows the changes of the url of the
+// service. Failures are reported as warnings, since the service was deployed.
+func (da *DeployAction) configureRealtimeHandler(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	deployResult *project.ServiceDeployResult,
+) {
+	stepMessage := fmt.Sprintf("Configuring the realtime event handler of service %s", svc.Name)
+	da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	if err := da.configureRealtimeHandlerOf(ctx, svc, deployResult); err != nil {
+		da.console.StopSpinner(ctx, stepMessage, input.StepWarning)
+		da.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: %s. Run 'azd deploy %s' again or set the event handler from the Azure Portal.", err, svc.Name))
+		return
+	}
+
+	da.console.StopSpinner(ctx, stepMessage, input.StepDone)
+}
+
+func (da *DeployAction) configureRealtimeHandlerOf(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	deployResult *project.ServiceDeployResult,
+) error {
+	if len(deployResult.Endpoints) == 0 {
+		return fmt.Errorf("service '%s' doesn't have any endpoint for the realtime event handler", svc.Name)
+	}
+
+	resource, err := svc.Realtime.Resource.Envsubst(da.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("expanding the realtime resource of service '%s': %w", svc.Name, err)
+	}
+
+	resourceId, err := infra.ResourceId(resource, da.env)
+	if err != nil {
+		return fmt.Errorf("finding the realtime resource '%s' of service '%s': %w", resource, svc.Name, err)
+	}
+
+	// Some endpoints are followed by a description, ex) http://10.0.0.1/ (Service, Type: LoadBalancer)
+	baseUrl, _, _ := strings.Cut(deployResult.Endpoints[0], " ")
+	if !strings.HasPrefix(baseUrl, "https://") {
+		return fmt.Errorf("the endpoint '%s' of service '%s' must use https to receive realtime events", baseUrl, svc.Name)
+	}
+
+	handlerPath := svc.Realtime.Path
+	if handlerPath == "" {
+		handlerPath = defaultRealtimeHandlerPath
+	}
+
+	_, err = da.realtimeService.ConfigureEventHandler(ctx, resourceId.String(), azapi.RealtimeEventHandler{
+		Hub:              svc.Realtime.Hub,
+		UrlTemplate:      strings.TrimSuffix(baseUrl, "/") + "/" + strings.TrimPrefix(handlerPath, "/"),
+		UserEventPattern: svc.Realtime.UserEvents,
+		SystemEvents:     svc.Realtime.SystemEvents,
+		IdentityResource: svc.Realtime.Audience,
 	})
+	return err
 }
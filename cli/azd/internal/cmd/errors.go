@@ -4,20 +4,28 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
+	osexec "os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 )
@@ -118,9 +126,128 @@ func MapError(err error, span tracing.Span) {
 		span.SetAttributes(errDetails...)
 	}
 
+	if categorized := ClassifyError(err); categorized != nil {
+		span.SetAttributes(fields.ErrCategory.String(string(categorized.Category)))
+	}
+
 	span.SetStatus(codes.Error, errCode)
 }
 
+// The codes of Azure errors of each category, returned by ARM deployments and services
+var categoryErrorCodes = map[string]internal.ErrorCategory{
+	"AuthorizationFailed":           internal.ErrorCategoryAuth,
+	"LinkedAuthorizationFailed":     internal.ErrorCategoryAuth,
+	"InvalidAuthenticationToken":    internal.ErrorCategoryAuth,
+	"ExpiredAuthenticationToken":    internal.ErrorCategoryAuth,
+	"QuotaExceeded":                 internal.ErrorCategoryQuota,
+	"InsufficientQuota":             internal.ErrorCategoryQuota,
+	"SubscriptionQuotaExceeded":     internal.ErrorCategoryQuota,
+	"SkuNotAvailable":               internal.ErrorCategoryQuota,
+	"RequestDisallowedByPolicy":     internal.ErrorCategoryPolicy,
+	"RequestDisallowedByAzure":      internal.ErrorCategoryPolicy,
+	"ResourceDeploymentFailureDeny": internal.ErrorCategoryPolicy,
+}
+
+// The tools which azd downloads and manages itself. The other commands failing, ex) a hook script or a build of the
+// user's project, fail because of the project rather than because of azd's tooling.
+var managedToolNames = map[string]bool{
+	"bicep": true,
+	"gh":    true,
+	"pack":  true,
+}
+
+// ClassifyError returns the category and the machine-readable code of the error, or nil when the error isn't of a known
+// category. Errors already categorized with internal.ErrorWithCategory are returned as is.
+func ClassifyError(err error) *internal.ErrorWithCategory {
+	if err == nil {
+		return nil
+	}
+
+	var categorized *internal.ErrorWithCategory
+	if errors.As(err, &categorized) {
+		return categorized
+	}
+
+	newError := func(category internal.ErrorCategory, code string) *internal.ErrorWithCategory {
+		return &internal.ErrorWithCategory{
+			Category: category,
+			Code:     fmt.Sprintf("%s.%s", category, code),
+			Err:      err,
+		}
+	}
+
+	var armDeployErr *azapi.AzureDeploymentError
+	var respErr *azcore.ResponseError
+	var authFailedErr *auth.AuthFailedError
+	var reLoginErr *auth.ReLoginRequiredError
+	var semverErr *tools.ErrSemver
+	var toolExecErr *exec.ExitError
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certificateErr *tls.CertificateVerificationError
+	var urlErr *url.Error
+
+	switch {
+	case errors.As(err, &armDeployErr):
+		if code, category := deploymentErrorCategory(armDeployErr.Details); category != "" {
+			return newError(category, code)
+		}
+	case errors.As(err, &respErr):
+		if category, has := categoryErrorCodes[respErr.ErrorCode]; has {
+			return newError(category, respErr.ErrorCode)
+		}
+
+		if respErr.StatusCode == http.StatusUnauthorized || respErr.StatusCode == http.StatusForbidden {
+			return newError(internal.ErrorCategoryAuth, fmt.Sprintf("http%d", respErr.StatusCode))
+		}
+	case errors.Is(err, auth.ErrNoCurrentUser):
+		return newError(internal.ErrorCategoryAuth, "notLoggedIn")
+	case errors.As(err, &reLoginErr):
+		return newError(internal.ErrorCategoryAuth, "reLoginRequired")
+	case errors.As(err, &authFailedErr):
+		return newError(internal.ErrorCategoryAuth, "failed")
+	case errors.As(err, &semverErr):
+		return newError(internal.ErrorCategoryTooling, fmt.Sprintf("%s.outdated", cmdAsName(semverErr.ToolName)))
+	case errors.Is(err, osexec.ErrNotFound):
+		return newError(internal.ErrorCategoryTooling, "notInstalled")
+	case errors.As(err, &toolExecErr):
+		if toolName := cmdAsName(toolExecErr.Cmd); managedToolNames[toolName] {
+			return newError(internal.ErrorCategoryTooling, fmt.Sprintf("%s.failed", toolName))
+		}
+	case errors.As(err, &dnsErr):
+		return newError(internal.ErrorCategoryNetwork, "dns")
+	case errors.As(err, &unknownAuthorityErr), errors.As(err, &certificateErr):
+		return newError(internal.ErrorCategoryNetwork, "tls")
+	case errors.As(err, &opErr):
+		return newError(internal.ErrorCategoryNetwork, "connection")
+	case errors.As(err, &urlErr) && urlErr.Timeout():
+		return newError(internal.ErrorCategoryNetwork, "timeout")
+	}
+
+	return nil
+}
+
+// deploymentErrorCategory returns the first code of a known category of the deployment error, searching the inner
+// errors first since they describe the root causes.
+func deploymentErrorCategory(line *azapi.DeploymentErrorLine) (string, internal.ErrorCategory) {
+	if line == nil {
+		return "", ""
+	}
+
+	for _, inner := range line.Inner {
+		if code, category := deploymentErrorCategory(inner); category != "" {
+			return code, category
+		}
+	}
+
+	if category, has := categoryErrorCodes[line.Code]; has {
+		return line.Code, category
+	}
+
+	return "", ""
+}
+
 type deploymentErrorCode struct {
 	Code  string `json:"error.code"`
 	Frame int    `json:"error.frame"`
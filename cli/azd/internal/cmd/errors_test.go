@@ -6,14 +6,20 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	osexec "os/exec"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mocktracing"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
@@ -146,6 +152,7 @@ func Test_MapError(t *testing.T) {
 				fields.ErrorKey(fields.ServiceErrorCode).String("50076,50078,50079"),
 				fields.ErrorKey(fields.ServiceStatusCode).String("invalid_grant"),
 				fields.ErrorKey(fields.ServiceCorrelationId).String("12345"),
+				fields.ErrCategory.String("auth"),
 			},
 		},
 	}
@@ -160,6 +167,104 @@ func Test_MapError(t *testing.T) {
 	}
 }
 
+func Test_ClassifyError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantCategory internal.ErrorCategory
+		wantCode     string
+	}{
+		{
+			name: "DeploymentQuota",
+			err: fmt.Errorf("deploying: %w", &azapi.AzureDeploymentError{
+				Details: &azapi.DeploymentErrorLine{
+					Code: "DeploymentFailed",
+					Inner: []*azapi.DeploymentErrorLine{
+						{Code: "Conflict"},
+						{Code: "InsufficientQuota"},
+					},
+				},
+			}),
+			wantCategory: internal.ErrorCategoryQuota,
+			wantCode:     "quota.InsufficientQuota",
+		},
+		{
+			name: "DeploymentPolicy",
+			err: &azapi.AzureDeploymentError{
+				Details: &azapi.DeploymentErrorLine{Code: "RequestDisallowedByPolicy"},
+			},
+			wantCategory: internal.ErrorCategoryPolicy,
+			wantCode:     "policy.RequestDisallowedByPolicy",
+		},
+		{
+			name:         "ResponseAuthorizationFailed",
+			err:          &azcore.ResponseError{ErrorCode: "AuthorizationFailed", StatusCode: http.StatusForbidden},
+			wantCategory: internal.ErrorCategoryAuth,
+			wantCode:     "auth.AuthorizationFailed",
+		},
+		{
+			name:         "ResponseUnauthorized",
+			err:          &azcore.ResponseError{StatusCode: http.StatusUnauthorized},
+			wantCategory: internal.ErrorCategoryAuth,
+			wantCode:     "auth.http401",
+		},
+		{
+			name:         "NotLoggedIn",
+			err:          fmt.Errorf("getting credential: %w", auth.ErrNoCurrentUser),
+			wantCategory: internal.ErrorCategoryAuth,
+			wantCode:     "auth.notLoggedIn",
+		},
+		{
+			name:         "ToolOutdated",
+			err:          &tools.ErrSemver{ToolName: "Docker"},
+			wantCategory: internal.ErrorCategoryTooling,
+			wantCode:     "tooling.docker.outdated",
+		},
+		{
+			name:         "ManagedToolFailed",
+			err:          fmt.Errorf("building bicep: %w", &exec.ExitError{Cmd: "/home/user/.azd/bin/bicep", ExitCode: 1}),
+			wantCategory: internal.ErrorCategoryTooling,
+			wantCode:     "tooling.bicep.failed",
+		},
+		{
+			name:         "ToolNotInstalled",
+			err:          fmt.Errorf("finding docker: %w", osexec.ErrNotFound),
+			wantCategory: internal.ErrorCategoryTooling,
+			wantCode:     "tooling.notInstalled",
+		},
+		{
+			name:         "Dns",
+			err:          &url.Error{Op: "Get", URL: "https://management.azure.com", Err: &net.DNSError{Name: "x"}},
+			wantCategory: internal.ErrorCategoryNetwork,
+			wantCode:     "network.dns",
+		},
+		{
+			name: "Categorized",
+			err: fmt.Errorf("wrapped: %w", &internal.ErrorWithCategory{
+				Category: internal.ErrorCategoryNetwork,
+				Code:     "network.custom",
+				Err:      errors.New("custom"),
+			}),
+			wantCategory: internal.ErrorCategoryNetwork,
+			wantCode:     "network.custom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categorized := ClassifyError(tt.err)
+			require.NotNil(t, categorized)
+			require.Equal(t, tt.wantCategory, categorized.Category)
+			require.Equal(t, tt.wantCode, categorized.Code)
+		})
+	}
+
+	require.Nil(t, ClassifyError(nil))
+	require.Nil(t, ClassifyError(errors.New("something bad happened!")))
+	// The commands of the user's project, ex) a hook script, are not azd's tooling
+	require.Nil(t, ClassifyError(&exec.ExitError{Cmd: "bash", ExitCode: 1}))
+	require.Nil(t, ClassifyError(&azcore.ResponseError{ErrorCode: "Conflict", StatusCode: http.StatusConflict}))
+}
+
 func Test_cmdAsName(t *testing.T) {
 	tests := []struct {
 		name string
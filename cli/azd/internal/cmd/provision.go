@@ -5,10 +5,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +29,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/wait"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.uber.org/multierr"
@@ -32,8 +37,19 @@ import (
 
 type ProvisionFlags struct {
 	noProgress            bool
+	whatChanged           bool
 	preview               bool
 	ignoreDeploymentState bool
+	preset                string
+	forceOutputs          bool
+	skipBudgetCheck       bool
+	skipQuotaCheck        bool
+	timeout               time.Duration
+	pollInterval          time.Duration
+	resume                bool
+	user                  string
+	waitForDns            bool
+	waitTimeout           time.Duration
 	global                *internal.GlobalCommandOptions
 	*internal.EnvFlag
 }
@@ -49,6 +65,13 @@ const (
 func (i *ProvisionFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	i.BindNonCommon(local, global)
 	i.bindCommon(local, global)
+	// Not bound with the flags of 'azd up', which always provisions
+	local.BoolVar(
+		&i.whatChanged,
+		"what-changed",
+		false,
+		"(Bicep only) Compares the template and parameters with the last provision of the environment, without calling "+
+			"Azure, to tell whether a provision is needed.")
 }
 
 func (i *ProvisionFlags) BindNonCommon(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
@@ -66,6 +89,58 @@ func (i *ProvisionFlags) bindCommon(local *pflag.FlagSet, global *internal.Globa
 		false,
 		"(Bicep only) Forces a fresh deployment based on current Bicep template files, "+
 			"ignoring any stored deployment state.")
+	local.StringVar(
+		&i.preset,
+		"preset",
+		"",
+		"(Bicep and Dev Center only) Sets the infrastructure parameter values of the environment from the named preset "+
+			"of the project, exported with 'azd env export-preset'.")
+	local.BoolVar(
+		&i.forceOutputs,
+		"force-outputs",
+		false,
+		"Overwrites the values set with 'azd env set' which conflict with provisioning outputs, without prompting.")
+	local.BoolVar(
+		&i.skipBudgetCheck,
+		"skip-budget-check",
+		false,
+		"Provisions even when the spend of the subscription budgets is above the thresholds set in azure.yaml.")
+	local.BoolVar(
+		&i.skipQuotaCheck,
+		"skip-quota-check",
+		false,
+		"Provisions even when the quotas set in azure.yaml aren't available in the location of the environment.")
+	local.DurationVar(
+		&i.timeout,
+		"timeout",
+		0,
+		"(Dev Center only) The maximum time to wait for the environment to be deployed, for example 90m.")
+	local.DurationVar(
+		&i.pollInterval,
+		"poll-interval",
+		0,
+		"(Dev Center only) The time between the checks of the status of the environment while it is deployed.")
+	local.BoolVar(
+		&i.resume,
+		"resume",
+		false,
+		"(Dev Center only) Waits for the deployment of the environment that is still running, instead of starting a new one.")
+	local.StringVar(
+		&i.user,
+		"user",
+		"",
+		"(Dev Center only) The object ID of the user owning the environment, for project admins provisioning the "+
+			"environment of another user.")
+	local.BoolVar(
+		&i.waitForDns,
+		"wait-for-dns",
+		false,
+		"Waits until the DNS names of the URL outputs of the deployment resolve, before completing.")
+	local.DurationVar(
+		&i.waitTimeout,
+		"wait-timeout",
+		10*time.Minute,
+		"The maximum time to wait for the DNS names with --wait-for-dns.")
 
 	i.EnvFlag = &internal.EnvFlag{}
 	i.EnvFlag.Bind(local, global)
@@ -112,6 +187,8 @@ type ProvisionAction struct {
 	subManager          *account.SubscriptionsManager
 	importManager       *project.ImportManager
 	alphaFeatureManager *alpha.FeatureManager
+	defaultProvider     provisioning.DefaultProviderResolver
+	azCli               *azapi.AzureClient
 	portalUrlBase       string
 }
 
@@ -129,6 +206,8 @@ func NewProvisionAction(
 	writer io.Writer,
 	subManager *account.SubscriptionsManager,
 	alphaFeatureManager *alpha.FeatureManager,
+	defaultProvider provisioning.DefaultProviderResolver,
+	azCli *azapi.AzureClient,
 	cloud *cloud.Cloud,
 ) actions.Action {
 	return &ProvisionAction{
@@ -145,10 +224,66 @@ func NewProvisionAction(
 		subManager:          subManager,
 		importManager:       importManager,
 		alphaFeatureManager: alphaFeatureManager,
+		defaultProvider:     defaultProvider,
+		azCli:               azCli,
 		portalUrlBase:       cloud.PortalUrlBase,
 	}
 }
 
+// whatChanged compares the template and parameters of the environment with its last provision, reporting whether a
+// provision is needed. The comparison is written as JSON with --output json, for CI pipelines to gate provisioning on it.
+func (p *ProvisionAction) whatChanged(ctx context.Context) (*actions.ActionResult, error) {
+	p.console.ShowSpinner(ctx, "Comparing with the last provision", input.Step)
+	diff, err := p.provisionManager.WhatChanged(ctx)
+	p.console.StopSpinner(ctx, "", input.Step)
+
+	if errors.Is(err, provisioning.ErrSnapshotNotSupported) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        err,
+			Suggestion: "Suggestion: run 'azd provision --preview' to preview the changes to your Azure resources.",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if p.formatter.Kind() == output.JsonFormat {
+		return nil, p.formatter.Format(diff, p.writer, nil)
+	}
+
+	if !diff.Changed {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "There are no changes to provision since the last provision.",
+			},
+		}, nil
+	}
+
+	lines := []string{}
+	if diff.NoSnapshot {
+		lines = append(lines, fmt.Sprintf("No provision of the environment '%s' was recorded.", p.env.Name()))
+	}
+	if diff.TemplateChanged {
+		lines = append(lines, "  The template changed.")
+	}
+	for _, name := range diff.AddedParameters {
+		lines = append(lines, fmt.Sprintf("  Parameter added: %s", output.WithHighLightFormat(name)))
+	}
+	for _, name := range diff.RemovedParameters {
+		lines = append(lines, fmt.Sprintf("  Parameter removed: %s", output.WithHighLightFormat(name)))
+	}
+	for _, name := range diff.ChangedParameters {
+		lines = append(lines, fmt.Sprintf("  Parameter changed: %s", output.WithHighLightFormat(name)))
+	}
+	p.console.Message(ctx, strings.Join(lines, "\n"))
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   "A provision is needed to apply the changes since the last provision.",
+			FollowUp: "Run 'azd provision' to apply them.",
+		},
+	}, nil
+}
+
 // SetFlags sets the flags for the provision action. Panics if `flags` is nil
 func (p *ProvisionAction) SetFlags(flags *ProvisionFlags) {
 	if flags == nil {
@@ -169,6 +304,9 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		)
 	}
 	previewMode := p.flags.preview
+	if previewMode && p.flags.whatChanged {
+		return nil, errors.New("the --preview and --what-changed flags may not be used together")
+	}
 
 	// Command title
 	defaultTitle := "Provisioning Azure resources (azd provision)"
@@ -176,6 +314,9 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	if previewMode {
 		defaultTitle = "Previewing Azure resource changes (azd provision --preview)"
 		defaultTitleNote = "This is a preview. No changes will be applied to your Azure resources."
+	} else if p.flags.whatChanged {
+		defaultTitle = "Comparing with the last provision (azd provision --what-changed)"
+		defaultTitleNote = "Only the template and parameters are compared. No changes will be applied to your Azure resources."
 	}
 
 	p.console.MessageUxItem(ctx, &ux.MessageTitle{
@@ -201,10 +342,28 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 	infraOptions := infra.Options
 	infraOptions.IgnoreDeploymentState = p.flags.ignoreDeploymentState
+	infraOptions.ForceOutputs = p.flags.forceOutputs
+	infraOptions.Timeout = p.flags.timeout
+	infraOptions.PollInterval = p.flags.pollInterval
+	infraOptions.Resume = p.flags.resume
+	infraOptions.User = p.flags.user
+
+	// The preset is applied before initializing the provider, so that its values aren't prompted for
+	if p.flags.preset != "" {
+		if err := p.applyPreset(ctx, infraOptions); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := p.provisionManager.Initialize(ctx, p.projectConfig.Path, infraOptions); err != nil {
 		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
 	}
 
+	// The comparison doesn't call Azure, so it's done before looking up the subscription
+	if p.flags.whatChanged {
+		return p.whatChanged(ctx)
+	}
+
 	// Get Subscription to Display in Command Title Note
 	// Subscription and Location are ONLY displayed when they are available (found from env), otherwise, this message
 	// is not displayed.
@@ -236,6 +395,18 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		log.Printf("failed getting subscriptions. Skip displaying sub and location: %v", subErr)
 	}
 
+	if !previewMode && !p.flags.skipBudgetCheck && infraOptions.Budget != nil {
+		if err := p.checkBudgets(ctx, *infraOptions.Budget); err != nil {
+			return nil, err
+		}
+	}
+
+	if !previewMode && !p.flags.skipQuotaCheck && len(infraOptions.Quota) > 0 {
+		if err := p.checkQuotas(ctx, infraOptions.Quota); err != nil {
+			return nil, err
+		}
+	}
+
 	var deployResult *provisioning.DeployResult
 	var deployPreviewResult *provisioning.DeployPreviewResult
 
@@ -363,6 +534,12 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		}
 	}
 
+	if p.flags.waitForDns {
+		if err := p.waitForDns(ctx, deployResult.Deployment.Outputs); err != nil {
+			return nil, err
+		}
+	}
+
 	if p.formatter.Kind() == output.JsonFormat {
 		stateResult, err := p.provisionManager.State(ctx, nil)
 		if err != nil {
@@ -398,6 +575,75 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	}, nil
 }
 
+// waitForDns waits until the host names of the URL outputs of the deployment resolve, since the DNS records of new
+// resources can take a few minutes to propagate.
+func (p *ProvisionAction) waitForDns(ctx context.Context, outputs map[string]provisioning.OutputParameter) error {
+	hostNames := outputHostNames(outputs)
+	if len(hostNames) == 0 {
+		p.console.Message(ctx, output.WithGrayFormat("No URL in the outputs of the deployment, skipping --wait-for-dns."))
+		return nil
+	}
+
+	conditions := make([]wait.Condition, len(hostNames))
+	for i, hostName := range hostNames {
+		conditions[i] = wait.DNS(hostName, nil)
+	}
+
+	spinnerMessage := "Waiting for DNS names to resolve"
+	p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	results, err := wait.For(ctx, conditions, wait.Options{
+		Timeout: p.flags.waitTimeout,
+		OnMet: func(result wait.Result) {
+			p.console.StopSpinner(ctx, fmt.Sprintf("%s %s", result.Target, result.Details), input.StepDone)
+			p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+		},
+	})
+	p.console.StopSpinner(ctx, "", input.Step)
+
+	if errors.Is(err, wait.ErrTimeout) {
+		pending := []string{}
+		for _, result := range results {
+			if !result.Met {
+				pending = append(pending, result.Target)
+			}
+		}
+
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("%w: %s still not resolving", err, strings.Join(pending, ", ")),
+			Suggestion: "Suggestion: the resources were provisioned. Increase the timeout with --wait-timeout, or " +
+				"wait for the DNS names with 'azd env wait --for-dns <name>'.",
+		}
+	}
+
+	return err
+}
+
+// outputHostNames returns the sorted and unique host names of the http(s) URL outputs
+func outputHostNames(outputs map[string]provisioning.OutputParameter) []string {
+	hostNames := []string{}
+	for _, param := range outputs {
+		value, ok := param.Value.(string)
+		if !ok {
+			continue
+		}
+
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			continue
+		}
+
+		// IP addresses don't need to be resolved
+		if net.ParseIP(u.Hostname()) != nil || slices.Contains(hostNames, u.Hostname()) {
+			continue
+		}
+
+		hostNames = append(hostNames, u.Hostname())
+	}
+
+	slices.Sort(hostNames)
+	return hostNames
+}
+
 // deployResultToUx creates the ux element to display from a provision preview
 func deployResultToUx(previewResult *provisioning.DeployPreviewResult) ux.UxItem {
 	var operations []*ux.Resource
@@ -424,3 +670,122 @@ func GetCmdProvisionHelpDescription(c *cobra.Command) string {
 		formatHelpNote("Azure subscription: The Azure subscription where your resources will be deployed."),
 	})
 }
+
+// applyPreset sets the parameter values of the environment from the preset selected with --preset.
+func (p *ProvisionAction) applyPreset(ctx context.Context, infraOptions provisioning.Options) error {
+	preset, err := provisioning.LoadPreset(p.projectConfig.Path, infraOptions, p.flags.preset)
+	if err != nil {
+		return err
+	}
+
+	kind, err := provisioning.ResolveProviderKind(infraOptions, p.defaultProvider)
+	if err != nil {
+		return err
+	}
+
+	if err := preset.Apply(p.env, kind); err != nil {
+		return fmt.Errorf("applying preset '%s': %w", p.flags.preset, err)
+	}
+
+	return p.envManager.Save(ctx, p.env)
+}
+
+// checkQuotas fails when the capacity of a quota needed by the infrastructure isn't available in the location of the
+// environment, before a deployment that would fail after creating part of the resources. The check is skipped when the
+// quotas can't be read, for example when the Microsoft.Quota provider isn't registered in the subscription.
+func (p *ProvisionAction) checkQuotas(ctx context.Context, requirements []provisioning.QuotaRequirement) error {
+	subscriptionId := p.env.GetSubscriptionId()
+	location := p.env.GetLocation()
+	if subscriptionId == "" || location == "" {
+		log.Printf("skipping quota check, the subscription or the location of the environment isn't set")
+		return nil
+	}
+
+	p.console.ShowSpinner(ctx, "Checking quotas", input.Step)
+	quotas := map[string][]azapi.QuotaUsage{}
+	for _, requirement := range requirements {
+		provider := strings.ToLower(requirement.ResourceProvider())
+		if _, has := quotas[provider]; has {
+			continue
+		}
+
+		usages, err := p.azCli.ListQuotaUsages(ctx, subscriptionId, location, requirement.ResourceProvider())
+		if err != nil {
+			p.console.StopSpinner(ctx, "Checking quotas", input.StepWarning)
+			log.Printf("failed listing quotas of subscription %s: %v", subscriptionId, err)
+			p.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: The quotas of the subscription couldn't be checked before provisioning."))
+			return nil
+		}
+
+		quotas[provider] = usages
+	}
+
+	statuses := provisioning.CheckQuotas(requirements, quotas)
+	if len(statuses) == 0 {
+		p.console.StopSpinner(ctx, "Checking quotas", input.StepDone)
+		return nil
+	}
+
+	p.console.StopSpinner(ctx, "Checking quotas", input.StepFailed)
+	for _, status := range statuses {
+		p.console.Message(ctx, output.WithErrorFormat("  %s", status.Error()))
+	}
+
+	return &internal.ErrorWithSuggestion{
+		Err: &internal.ErrorWithCategory{
+			Category: internal.ErrorCategoryQuota,
+			Code:     "quota.InsufficientQuota",
+			Err: fmt.Errorf(
+				"provisioning is blocked, %d quota(s) needed by the infrastructure aren't available in %s",
+				len(statuses), location),
+		},
+		Suggestion: fmt.Sprintf(
+			"Suggestion: Request a quota increase at %s, select another location with %s, or run again with %s "+
+				"to provision anyway.",
+			output.WithHyperlink(
+				fmt.Sprintf("%s/#view/Microsoft_Azure_Capacity/QuotaMenuBlade/~/myQuotas", p.portalUrlBase),
+				"Azure Quotas"),
+			output.WithHighLightFormat("azd env set AZURE_LOCATION <location>"),
+			output.WithHighLightFormat("--skip-quota-check"),
+		),
+	}
+}
+
+// checkBudgets warns about the budgets of the subscription which spend is above the warning threshold, and fails when
+// the spend of a budget is above the threshold blocking provisioning. The check is skipped when the budgets can't be
+// read, for example when the user doesn't have access to Cost Management.
+func (p *ProvisionAction) checkBudgets(ctx context.Context, options provisioning.BudgetOptions) error {
+	subscriptionId := p.env.GetSubscriptionId()
+	budgets, err := p.azCli.ListSubscriptionBudgets(ctx, subscriptionId)
+	if err != nil {
+		log.Printf("failed listing budgets of subscription %s: %v", subscriptionId, err)
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The budgets of the subscription couldn't be checked before provisioning."))
+		return nil
+	}
+
+	statuses := provisioning.CheckBudgets(budgets, options)
+	for _, status := range statuses {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: %.0f%% of the %s budget '%s' is spent (%.2f of %.2f %s).",
+			status.Percent,
+			strings.ToLower(status.Budget.TimeGrain),
+			status.Budget.Name,
+			status.Budget.CurrentSpend,
+			status.Budget.Amount,
+			status.Budget.Currency,
+		))
+	}
+
+	if len(statuses) > 0 && statuses[0].Blocking {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"provisioning is blocked, %.0f%% of the budget '%s' is spent", statuses[0].Percent, statuses[0].Budget.Name),
+			Suggestion: "Suggestion: Review the costs of the subscription before provisioning, or run again with " +
+				output.WithHighLightFormat("--skip-budget-check") + " to provision anyway.",
+		}
+	}
+
+	return nil
+}
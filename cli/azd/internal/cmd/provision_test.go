@@ -0,0 +1,26 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OutputHostNames(t *testing.T) {
+	outputs := map[string]provisioning.OutputParameter{
+		"SERVICE_WEB_URI":   {Type: provisioning.ParameterTypeString, Value: "https://web.azurewebsites.net"},
+		"SERVICE_API_URI":   {Type: provisioning.ParameterTypeString, Value: "https://api.example.com:8443/health"},
+		"API_ENDPOINT":      {Type: provisioning.ParameterTypeString, Value: "https://api.example.com"},
+		"PUBLIC_IP_URL":     {Type: provisioning.ParameterTypeString, Value: "http://20.1.2.3"},
+		"STORAGE_ACCOUNT":   {Type: provisioning.ParameterTypeString, Value: "mystorage"},
+		"REDIS_CONNECTION":  {Type: provisioning.ParameterTypeString, Value: "redis://cache.example.com:6380"},
+		"AZURE_REPLICAS":    {Type: provisioning.ParameterTypeNumber, Value: 3},
+		"AZURE_FEATURE_URL": {Type: provisioning.ParameterTypeString, Value: ""},
+	}
+
+	require.Equal(t, []string{"api.example.com", "web.azurewebsites.net"}, outputHostNames(outputs))
+}
@@ -27,15 +27,19 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/status"
+	"github.com/braydonk/yaml"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -43,6 +47,9 @@ import (
 type showFlags struct {
 	global      *internal.GlobalCommandOptions
 	showSecrets bool
+	lastRun     bool
+	// Whether to display the configuration of the project used in the environment
+	effectiveConfig bool
 	internal.EnvFlag
 }
 
@@ -54,6 +61,18 @@ func (s *showFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		false,
 		"Unmask secrets in output.",
 	)
+	local.BoolVar(
+		&s.lastRun,
+		"last-run",
+		false,
+		"Display the report of the last run of 'azd up' in the environment.",
+	)
+	local.BoolVar(
+		&s.effectiveConfig,
+		"effective-config",
+		false,
+		"Display the configuration of the project used in the environment, with its overrides merged over azure.yaml.",
+	)
 	s.global = global
 }
 
@@ -66,7 +85,7 @@ func NewShowFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *sh
 
 func NewShowCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "show [resource name or ID]",
+		Use:   "show [service name, resource name or ID]",
 		Short: "Display information about your project and its resources.",
 	}
 
@@ -91,6 +110,7 @@ type showAction struct {
 	featureManager       *alpha.FeatureManager
 	lazyServiceManager   *lazy.Lazy[project.ServiceManager]
 	lazyResourceManager  *lazy.Lazy[project.ResourceManager]
+	serviceLocator       ioc.ServiceLocator
 	portalUrlBase        string
 }
 
@@ -112,6 +132,7 @@ func NewShowAction(
 	args []string,
 	lazyServiceManager *lazy.Lazy[project.ServiceManager],
 	lazyResourceManager *lazy.Lazy[project.ResourceManager],
+	serviceLocator ioc.ServiceLocator,
 	cloud *cloud.Cloud,
 ) actions.Action {
 	return &showAction{
@@ -132,11 +153,20 @@ func NewShowAction(
 		flags:                flags,
 		lazyServiceManager:   lazyServiceManager,
 		lazyResourceManager:  lazyResourceManager,
+		serviceLocator:       serviceLocator,
 		portalUrlBase:        cloud.PortalUrlBase,
 	}
 }
 
 func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if s.flags.lastRun {
+		return nil, s.showLastRun(ctx)
+	}
+
+	if s.flags.effectiveConfig {
+		return nil, s.showEffectiveConfig(ctx)
+	}
+
 	s.console.ShowSpinner(ctx, "Gathering information about your app and its resources...", input.Step)
 	defer s.console.StopSpinner(ctx, "", input.Step)
 
@@ -193,6 +223,8 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 		log.Printf("could not load environment: %s, resource ids will not be available", err)
 	} else {
+		res.ScheduledActions = s.scheduledActions(ctx, env.Name())
+
 		if subId = env.GetSubscriptionId(); subId == "" {
 			log.Printf("provision has not been run, resource ids will not be available")
 		} else {
@@ -205,6 +237,10 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 			if len(s.args) > 0 {
 				name := s.args[0]
+				if serviceConfig, has := s.projectConfig.Services[name]; has {
+					return nil, s.showService(ctx, serviceConfig, env)
+				}
+
 				err := s.showResource(ctx, name, env)
 				if err != nil {
 					return nil, err
@@ -271,16 +307,124 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		index++
 	}
 
+	uxScheduledActions := make([]*ux.ShowScheduledAction, len(res.ScheduledActions))
+	for index, action := range res.ScheduledActions {
+		uxScheduledActions[index] = &ux.ShowScheduledAction{
+			ActionType:    action.ActionType,
+			ScheduledTime: action.ScheduledTime,
+		}
+	}
+
 	s.console.MessageUxItem(ctx, &ux.Show{
-		AppName:         s.projectConfig.Name,
-		Services:        uxServices,
-		Environments:    uxEnvironments,
-		AzurePortalLink: cmd.AzurePortalLink(s.portalUrlBase, subId, rgName),
+		AppName:          s.projectConfig.Name,
+		Services:         uxServices,
+		Environments:     uxEnvironments,
+		ScheduledActions: uxScheduledActions,
+		AzurePortalLink:  cmd.AzurePortalLink(s.portalUrlBase, subId, rgName),
 	})
 
 	return nil, nil
 }
 
+// scheduledActions returns the upcoming actions scheduled on the environment. Only devcenter environments have
+// scheduled actions, like their deletion when they expire.
+func (s *showAction) scheduledActions(ctx context.Context, envName string) []contracts.ShowScheduledAction {
+	var schedules *devcenter.Schedules
+	if err := s.serviceLocator.Resolve(&schedules); err != nil {
+		return nil
+	}
+
+	actions, err := schedules.List(ctx, envName)
+	if err != nil {
+		log.Printf("ignoring error listing the scheduled actions of environment %s: %v", envName, err)
+		return nil
+	}
+
+	scheduled := make([]contracts.ShowScheduledAction, len(actions))
+	for index, action := range actions {
+		scheduled[index] = contracts.ShowScheduledAction{
+			Name:          action.Name,
+			ActionType:    action.ActionType,
+			ScheduledTime: action.ScheduledTime,
+		}
+	}
+
+	return scheduled
+}
+
+// showLastRun displays the report written by the last run of 'azd up' in the environment
+func (s *showAction) showLastRun(ctx context.Context) error {
+	if len(s.args) > 0 {
+		return errors.New("--last-run can't be used with a service or resource name")
+	}
+
+	environmentName := s.flags.EnvironmentName
+	if environmentName == "" {
+		var err error
+		environmentName, err = s.azdCtx.GetDefaultEnvironmentName()
+		if err != nil {
+			return err
+		}
+	}
+
+	if environmentName == "" {
+		return environment.ErrDefaultEnvironmentNotFound
+	}
+
+	report, err := status.ReadReport(s.azdCtx.EnvironmentRoot(environmentName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("environment '%s' has no report of a run", environmentName),
+			Suggestion: fmt.Sprintf("Suggestion: run '%s' first, the report is written at the end of each run.",
+				output.WithHighLightFormat("azd up")),
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if s.formatter.Kind() == output.JsonFormat {
+		return s.formatter.Format(report, s.writer, nil)
+	}
+
+	s.console.Message(ctx, report.Markdown())
+	return nil
+}
+
+// showEffectiveConfig displays the configuration of the project used in the environment, see project.LoadEffectiveConfig.
+func (s *showAction) showEffectiveConfig(ctx context.Context) error {
+	if len(s.args) > 0 {
+		return errors.New("--effective-config can't be used with a service or resource name")
+	}
+
+	environmentName := s.flags.EnvironmentName
+	if environmentName == "" {
+		var err error
+		environmentName, err = s.azdCtx.GetDefaultEnvironmentName()
+		if err != nil {
+			return err
+		}
+	}
+
+	effective, err := project.LoadEffectiveConfig(ctx, s.azdCtx.ProjectPath(), environmentName)
+	if err != nil {
+		return err
+	}
+
+	if s.formatter.Kind() == output.JsonFormat {
+		return s.formatter.Format(effective, s.writer, nil)
+	}
+
+	content, err := yaml.Marshal(effective.Config)
+	if err != nil {
+		return fmt.Errorf("marshalling the effective config: %w", err)
+	}
+
+	s.console.Message(ctx, output.WithGrayFormat("# Merged from %s, by increasing precedence",
+		strings.Join(effective.Sources, ", ")))
+	s.console.Message(ctx, strings.TrimRight(string(content), "\n"))
+	return nil
+}
+
 func (s *showAction) showResource(ctx context.Context, name string, env *environment.Environment) error {
 	id, err := infra.ResourceId(name, env)
 	if err != nil {
@@ -489,26 +633,36 @@ func showContainerApp(
 
 func (s *showAction) serviceEndpoint(
 	ctx context.Context, subId string, serviceConfig *project.ServiceConfig, env *environment.Environment) string {
+	endpoints := s.serviceEndpoints(ctx, subId, serviceConfig, env)
+	if len(endpoints) == 0 {
+		return ""
+	}
+
+	return endpoints[0]
+}
+
+func (s *showAction) serviceEndpoints(
+	ctx context.Context, subId string, serviceConfig *project.ServiceConfig, env *environment.Environment) []string {
 	resourceManager, err := s.lazyResourceManager.GetValue()
 	if err != nil {
 		log.Printf("error: getting lazy target-resource. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 	targetResource, err := resourceManager.GetTargetResource(ctx, subId, serviceConfig)
 	if err != nil {
 		log.Printf("error: getting target-resource. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 
 	serviceManager, err := s.lazyServiceManager.GetValue()
 	if err != nil {
 		log.Printf("error: getting lazy service manager. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 	st, err := serviceManager.GetServiceTarget(ctx, serviceConfig)
 	if err != nil {
 		log.Printf("error: getting service target. Endpoints will be empty: %v", err)
-		return ""
+		return nil
 	}
 	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
@@ -520,11 +674,7 @@ func (s *showAction) serviceEndpoint(
 		endpoints = overriddenEndpoints
 	}
 
-	if len(endpoints) == 0 {
-		return ""
-	}
-
-	return endpoints[0]
+	return endpoints
 }
 
 func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowType {
@@ -540,7 +690,8 @@ func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowTy
 	case project.ServiceLanguageJava:
 		return contracts.ShowTypeJava
 	default:
-		panic(fmt.Sprintf("unknown language %s", language))
+		// Languages of build plugins don't have a show type
+		return contracts.ShowTypeNone
 	}
 }
 
@@ -33,6 +33,13 @@ func (s *showResource) showResourceGeneric(
 	id arm.ResourceID,
 	opts showResourceOptions) (*ux.ShowResource, error) {
 	resourceMeta, resourceId := getResourceMeta(id)
+	if opts.resourceSpec != nil && opts.resourceSpec.Existing {
+		// existing sub-resources are referenced by their parent, ex) the server of a database
+		if meta, ok := scaffold.ResourceMetaFromType(opts.resourceSpec.Type.AzureResourceType()); ok {
+			resourceMeta, resourceId = &meta, id
+		}
+	}
+
 	if resourceMeta == nil {
 		return nil, fmt.Errorf("resource type '%s' is not currently supported", id.ResourceType)
 	}
@@ -80,7 +87,15 @@ func (s *showResource) showResourceGeneric(
 		VaultSecret:  resolveSecret,
 	}
 
-	values, err := scaffold.Eval(resourceMeta.Variables, context)
+	variables := resourceMeta.Variables
+	if opts.resourceSpec != nil && opts.resourceSpec.Existing {
+		variables, err = scaffold.ExistingVariables(variables)
+		if err != nil {
+			return nil, fmt.Errorf("expanding variables: %w", err)
+		}
+	}
+
+	values, err := scaffold.Eval(variables, context)
 	if err != nil {
 		return nil, fmt.Errorf("expanding variables: %w", err)
 	}
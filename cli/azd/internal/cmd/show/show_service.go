@@ -0,0 +1,361 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package show
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// The number of recent deployments shown for a service
+const recentDeploymentsCount = 3
+
+// showService displays the details of a service: the resources it's deployed to, its endpoints, the managed identities
+// of its target resource, the deployed version and its most recent deployments.
+func (s *showAction) showService(
+	ctx context.Context,
+	serviceConfig *project.ServiceConfig,
+	env *environment.Environment,
+) error {
+	subId := env.GetSubscriptionId()
+
+	path, err := getFullPathToProjectForService(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	details := contracts.ShowServiceDetailsResult{
+		Name: serviceConfig.Name,
+		Project: contracts.ShowServiceProject{
+			Path: path,
+			Type: showTypeFromLanguage(serviceConfig.Language),
+		},
+		Host:              string(serviceConfig.Host),
+		Endpoints:         []string{},
+		Identities:        []contracts.ShowIdentity{},
+		RecentDeployments: []contracts.ShowDeployment{},
+	}
+
+	resourceManager, err := s.lazyResourceManager.GetValue()
+	if err != nil {
+		return err
+	}
+
+	targetResource, err := resourceManager.GetTargetResource(ctx, subId, serviceConfig)
+	if err != nil {
+		return fmt.Errorf(
+			"finding the resource of service '%s', ensure it has been provisioned: %w", serviceConfig.Name, err)
+	}
+
+	resources, err := resourceManager.GetServiceResources(
+		ctx, subId, targetResource.ResourceGroupName(), serviceConfig)
+	if err != nil {
+		return fmt.Errorf("finding the resources of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	resourceIds := make([]string, len(resources))
+	for idx, res := range resources {
+		resourceIds[idx] = res.Id
+	}
+	details.Target = &contracts.ShowTargetArm{
+		ResourceIds: resourceIds,
+	}
+
+	details.Endpoints = append(details.Endpoints, s.serviceEndpoints(ctx, subId, serviceConfig, env)...)
+
+	credential, err := s.creds.CredentialForSubscription(ctx, subId)
+	if err != nil {
+		return err
+	}
+
+	id := &arm.ResourceID{
+		SubscriptionID:    targetResource.SubscriptionId(),
+		ResourceGroupName: targetResource.ResourceGroupName(),
+		Name:              targetResource.ResourceName(),
+	}
+
+	switch {
+	case strings.EqualFold(targetResource.ResourceType(), string(azapi.AzureResourceTypeContainerApp)):
+		err = containerAppServiceDetails(ctx, credential, id, s.armClientOptions, &details)
+	case strings.EqualFold(targetResource.ResourceType(), string(azapi.AzureResourceTypeWebSite)):
+		err = appServiceDetails(ctx, credential, id, s.armClientOptions, &details)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.formatter.Kind() == output.JsonFormat {
+		return s.formatter.Format(details, s.writer, nil)
+	}
+
+	item := &ux.ShowServiceDetails{
+		Name:            details.Name,
+		Host:            details.Host,
+		ResourceIds:     resourceIds,
+		Endpoints:       details.Endpoints,
+		DeployedVersion: details.DeployedVersion,
+	}
+
+	for _, identity := range details.Identities {
+		description := identity.Type
+		if identity.ResourceId != "" {
+			description = fmt.Sprintf("%s %s", description, identity.ResourceId)
+		}
+		if identity.PrincipalId != "" {
+			description = fmt.Sprintf("%s (principal ID: %s)", description, identity.PrincipalId)
+		}
+		item.Identities = append(item.Identities, description)
+	}
+
+	for _, deployment := range details.RecentDeployments {
+		uxDeployment := &ux.ShowServiceDeployment{
+			Id:      deployment.Id,
+			Status:  deployment.Status,
+			Version: deployment.Version,
+		}
+		if deployment.Time != nil {
+			uxDeployment.Time = to.Ptr(deployment.Time.Local())
+		}
+		item.RecentDeployments = append(item.RecentDeployments, uxDeployment)
+	}
+
+	s.console.MessageUxItem(ctx, item)
+	return nil
+}
+
+// containerAppServiceDetails adds the identities, the image and the most recent revisions of a container app.
+func containerAppServiceDetails(
+	ctx context.Context,
+	cred azcore.TokenCredential,
+	id *arm.ResourceID,
+	clientOpts *arm.ClientOptions,
+	details *contracts.ShowServiceDetailsResult,
+) error {
+	client, err := armappcontainers.NewContainerAppsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return fmt.Errorf("creating container-apps client: %w", err)
+	}
+
+	app, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if app.Identity != nil {
+		userAssigned := map[string]contracts.ShowIdentity{}
+		for resourceId, identity := range app.Identity.UserAssignedIdentities {
+			userAssigned[resourceId] = userAssignedIdentity(resourceId, identity.PrincipalID, identity.ClientID)
+		}
+		details.Identities = identities(app.Identity.PrincipalID, userAssigned)
+	}
+
+	if app.Properties != nil {
+		details.DeployedVersion = containerImage(app.Properties.Template, id.Name)
+	}
+
+	revisionsClient, err := armappcontainers.NewContainerAppsRevisionsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return fmt.Errorf("creating container-apps revisions client: %w", err)
+	}
+
+	var revisions []*armappcontainers.Revision
+	pager := revisionsClient.NewListRevisionsPager(id.ResourceGroupName, id.Name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("listing revisions: %w", err)
+		}
+
+		for _, revision := range page.Value {
+			if revision.Name != nil && revision.Properties != nil && revision.Properties.CreatedTime != nil {
+				revisions = append(revisions, revision)
+			}
+		}
+	}
+
+	slices.SortFunc(revisions, func(a, b *armappcontainers.Revision) int {
+		return b.Properties.CreatedTime.Compare(*a.Properties.CreatedTime)
+	})
+
+	for _, revision := range revisions[:min(len(revisions), recentDeploymentsCount)] {
+		deployment := contracts.ShowDeployment{
+			Id:      *revision.Name,
+			Time:    revision.Properties.CreatedTime,
+			Version: containerImage(revision.Properties.Template, id.Name),
+		}
+
+		if revision.Properties.RunningState != nil {
+			deployment.Status = string(*revision.Properties.RunningState)
+		} else if revision.Properties.ProvisioningState != nil {
+			deployment.Status = string(*revision.Properties.ProvisioningState)
+		}
+
+		details.RecentDeployments = append(details.RecentDeployments, deployment)
+	}
+
+	return nil
+}
+
+// containerImage gets the image of the main container of a container app template.
+func containerImage(template *armappcontainers.Template, appName string) string {
+	if template == nil || len(template.Containers) == 0 {
+		return ""
+	}
+
+	container := template.Containers[0]
+	for _, c := range template.Containers {
+		if c.Name != nil && (strings.EqualFold(*c.Name, appName) || strings.EqualFold(*c.Name, "main")) {
+			container = c
+			break
+		}
+	}
+
+	if container.Image == nil {
+		return ""
+	}
+
+	return *container.Image
+}
+
+// appServiceDeploymentStatuses maps the status codes of App Service deployments to their names.
+var appServiceDeploymentStatuses = map[int32]string{
+	0: "Pending",
+	1: "Building",
+	2: "Deploying",
+	3: "Failed",
+	4: "Succeeded",
+}
+
+// appServiceDetails adds the identities, the deployed version and the most recent deployments of an App Service.
+func appServiceDetails(
+	ctx context.Context,
+	cred azcore.TokenCredential,
+	id *arm.ResourceID,
+	clientOpts *arm.ClientOptions,
+	details *contracts.ShowServiceDetailsResult,
+) error {
+	client, err := armappservice.NewWebAppsClient(id.SubscriptionID, cred, clientOpts)
+	if err != nil {
+		return fmt.Errorf("creating web-apps client: %w", err)
+	}
+
+	site, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return fmt.Errorf("getting web app: %w", err)
+	}
+
+	if site.Identity != nil {
+		userAssigned := map[string]contracts.ShowIdentity{}
+		for resourceId, identity := range site.Identity.UserAssignedIdentities {
+			userAssigned[resourceId] = userAssignedIdentity(resourceId, identity.PrincipalID, identity.ClientID)
+		}
+		details.Identities = identities(site.Identity.PrincipalID, userAssigned)
+	}
+
+	// Container based apps report their image, prefixed with DOCKER|
+	if site.Properties != nil && site.Properties.SiteConfig != nil && site.Properties.SiteConfig.LinuxFxVersion != nil {
+		if image, has := strings.CutPrefix(*site.Properties.SiteConfig.LinuxFxVersion, "DOCKER|"); has {
+			details.DeployedVersion = image
+		}
+	}
+
+	var deployments []*armappservice.Deployment
+	pager := client.NewListDeploymentsPager(id.ResourceGroupName, id.Name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("listing deployments: %w", err)
+		}
+
+		for _, deployment := range page.Value {
+			if deployment.Name != nil && deployment.Properties != nil && deploymentTime(deployment) != nil {
+				deployments = append(deployments, deployment)
+			}
+		}
+	}
+
+	slices.SortFunc(deployments, func(a, b *armappservice.Deployment) int {
+		return deploymentTime(b).Compare(*deploymentTime(a))
+	})
+
+	for _, deployment := range deployments[:min(len(deployments), recentDeploymentsCount)] {
+		showDeployment := contracts.ShowDeployment{
+			Id:   *deployment.Name,
+			Time: deploymentTime(deployment),
+		}
+
+		if deployment.Properties.Status != nil {
+			showDeployment.Status = appServiceDeploymentStatuses[*deployment.Properties.Status]
+		}
+
+		// Packages deployed to App Service are identified by their deployment, which is the active one
+		if deployment.Properties.Active != nil && *deployment.Properties.Active && details.DeployedVersion == "" {
+			details.DeployedVersion = *deployment.Name
+		}
+
+		details.RecentDeployments = append(details.RecentDeployments, showDeployment)
+	}
+
+	return nil
+}
+
+// deploymentTime gets when an App Service deployment ended, or when it started for deployments in progress.
+func deploymentTime(deployment *armappservice.Deployment) *time.Time {
+	if deployment.Properties.EndTime != nil {
+		return deployment.Properties.EndTime
+	}
+
+	return deployment.Properties.StartTime
+}
+
+func userAssignedIdentity(resourceId string, principalId *string, clientId *string) contracts.ShowIdentity {
+	identity := contracts.ShowIdentity{
+		Type:       "UserAssigned",
+		ResourceId: resourceId,
+	}
+
+	if principalId != nil {
+		identity.PrincipalId = *principalId
+	}
+
+	if clientId != nil {
+		identity.ClientId = *clientId
+	}
+
+	return identity
+}
+
+// identities lists the system assigned identity, when the resource has one, followed by the user assigned identities
+// sorted by resource ID.
+func identities(systemPrincipalId *string, userAssigned map[string]contracts.ShowIdentity) []contracts.ShowIdentity {
+	result := []contracts.ShowIdentity{}
+	if systemPrincipalId != nil {
+		result = append(result, contracts.ShowIdentity{
+			Type:        "SystemAssigned",
+			PrincipalId: *systemPrincipalId,
+		})
+	}
+
+	for _, resourceId := range slices.Sorted(maps.Keys(userAssigned)) {
+		result = append(result, userAssigned[resourceId])
+	}
+
+	return result
+}
@@ -34,3 +34,57 @@ func (et *ErrorWithTraceId) Error() string {
 func (et *ErrorWithTraceId) Unwrap() error {
 	return et.Err
 }
+
+// ErrorCategory groups errors by their cause, ex) an authentication failure or a missing tool, so that they can be
+// reported with a machine-readable code and a remediation.
+type ErrorCategory string
+
+const (
+	// The user isn't logged in, or isn't authorized to perform the operation
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// A quota or a SKU of the subscription or the location doesn't allow the operation
+	ErrorCategoryQuota ErrorCategory = "quota"
+	// An Azure Policy assignment denied the operation
+	ErrorCategoryPolicy ErrorCategory = "policy"
+	// A host could not be resolved or reached
+	ErrorCategoryNetwork ErrorCategory = "network"
+	// An external tool is missing or outdated, or a tool managed by azd failed
+	ErrorCategoryTooling ErrorCategory = "tooling"
+)
+
+// Remediation returns the default action to resolve errors of the category.
+func (c ErrorCategory) Remediation() string {
+	switch c {
+	case ErrorCategoryAuth:
+		return "Run 'azd auth login' to log in again, and ensure your account has the required role assignments " +
+			"on the subscription."
+	case ErrorCategoryQuota:
+		return "Request a quota increase for the subscription, or select another location or SKU."
+	case ErrorCategoryPolicy:
+		return "Review the Azure Policy assignments of the subscription with your administrator, " +
+			"or update the infrastructure to comply with them."
+	case ErrorCategoryNetwork:
+		return "Run 'azd doctor' to check the connectivity to the Azure endpoints used by azd."
+	case ErrorCategoryTooling:
+		return "Run 'azd doctor' to check the tools required by the project and their versions."
+	default:
+		return ""
+	}
+}
+
+// ErrorWithCategory is an error of a known category, with a machine-readable code, ex) quota.QuotaExceeded
+type ErrorWithCategory struct {
+	Category ErrorCategory
+	Code     string
+	Err      error
+}
+
+// Error returns the error message
+func (ec *ErrorWithCategory) Error() string {
+	return ec.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (ec *ErrorWithCategory) Unwrap() error {
+	return ec.Err
+}
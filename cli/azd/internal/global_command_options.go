@@ -18,6 +18,14 @@ type GlobalCommandOptions struct {
 	// if there is no default value the prompt returns an error.
 	NoPrompt bool
 
+	// NoAutoInstall disables installing the extensions required by the project, which are otherwise installed, or
+	// upgraded, before running the command. It's enabled with `--no-auto-install`, for any command.
+	NoAutoInstall bool
+
+	// ListPrompts lists the prompts of the command which can be answered with AZD_ANSWER_<ID> environment variables,
+	// instead of running the command. It's enabled with `--list-prompts`, for any command.
+	ListPrompts bool
+
 	// EnableTelemetry indicates if telemetry should be sent.
 	// The rootCmd will disable this based if the environment variable
 	// AZURE_DEV_COLLECT_TELEMETRY is set to 'no'.
@@ -37,7 +37,8 @@ func Test_EnvironmentService_NoEnvironment(t *testing.T) {
 	// Setup environment data store and manager.
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
 	localDataStore := environment.NewLocalFileDataStore(azdContext, fileConfigManager)
-	envManager, err := environment.NewManager(mockContext.Container, azdContext, mockContext.Console, localDataStore, nil)
+	envManager, err := environment.NewManager(
+		mockContext.Container, azdContext, mockContext.Console, localDataStore, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, envManager)
 
@@ -78,7 +79,8 @@ func Test_EnvironmentService_Flow(t *testing.T) {
 	// Configure environment data store and manager.
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
 	localDataStore := environment.NewLocalFileDataStore(azdContext, fileConfigManager)
-	envManager, err := environment.NewManager(mockContext.Container, azdContext, mockContext.Console, localDataStore, nil)
+	envManager, err := environment.NewManager(
+		mockContext.Container, azdContext, mockContext.Console, localDataStore, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, envManager)
 
@@ -58,7 +58,8 @@ func Test_ProjectService_Flow(t *testing.T) {
 	// Configure and initialize environment manager.
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
 	localDataStore := environment.NewLocalFileDataStore(azdContext, fileConfigManager)
-	envManager, err := environment.NewManager(mockContext.Container, azdContext, mockContext.Console, localDataStore, nil)
+	envManager, err := environment.NewManager(
+		mockContext.Container, azdContext, mockContext.Console, localDataStore, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, envManager)
 
@@ -104,7 +105,8 @@ func Test_ProjectService_AddService(t *testing.T) {
 	// Configure and initialize environment manager.
 	fileConfigManager := config.NewFileConfigManager(config.NewManager())
 	localDataStore := environment.NewLocalFileDataStore(azdContext, fileConfigManager)
-	envManager, err := environment.NewManager(mockContext.Container, azdContext, mockContext.Console, localDataStore, nil)
+	envManager, err := environment.NewManager(
+		mockContext.Container, azdContext, mockContext.Console, localDataStore, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, envManager)
 
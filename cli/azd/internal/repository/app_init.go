@@ -16,6 +16,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
 	"github.com/azure/azure-dev/cli/azd/internal/cmd/add"
+	"github.com/azure/azure-dev/cli/azd/internal/names"
 	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
@@ -42,31 +43,13 @@ func (i *Initializer) InitFromApp(
 	i.console.ShowSpinner(ctx, title, input.Step)
 	wd := azdCtx.ProjectDirectory()
 
-	projects := []appdetect.Project{}
 	start := time.Now()
-	sourceDir := filepath.Join(wd, "src")
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("detect"))
 
-	// Prioritize src directory if it exists
-	if ent, err := os.Stat(sourceDir); err == nil && ent.IsDir() {
-		prj, err := appdetect.Detect(ctx, sourceDir)
-		if err == nil && len(prj) > 0 {
-			projects = prj
-		}
-	}
-
-	if len(projects) == 0 {
-		prj, err := appdetect.Detect(ctx, wd, appdetect.WithExcludePatterns([]string{
-			"**/eng",
-			"**/tool",
-			"**/tools"},
-			false))
-		if err != nil {
-			i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
-			return err
-		}
-
-		projects = prj
+	projects, err := DetectApp(ctx, wd)
+	if err != nil {
+		i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+		return err
 	}
 
 	appHostManifests := make(map[string]*apphost.Manifest)
@@ -228,7 +211,7 @@ func (i *Initializer) InitFromApp(
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("modify"))
 
 	// Confirm selection of services and databases
-	err := detect.Confirm(ctx)
+	err = detect.Confirm(ctx)
 	if err != nil {
 		return err
 	}
@@ -275,6 +258,23 @@ func (i *Initializer) InitFromApp(
 	return nil
 }
 
+// DetectApp detects the projects of the app in the directory, prioritizing its src directory if it exists.
+func DetectApp(ctx context.Context, wd string) ([]appdetect.Project, error) {
+	sourceDir := filepath.Join(wd, "src")
+	if ent, err := os.Stat(sourceDir); err == nil && ent.IsDir() {
+		projects, err := appdetect.Detect(ctx, sourceDir)
+		if err == nil && len(projects) > 0 {
+			return projects, nil
+		}
+	}
+
+	return appdetect.Detect(ctx, wd, appdetect.WithExcludePatterns([]string{
+		"**/eng",
+		"**/tool",
+		"**/tools"},
+		false))
+}
+
 func (i *Initializer) genProjectFile(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
@@ -311,7 +311,13 @@ func (i *Initializer) prjConfigFromDetect(
 
 	svcMapping := map[string]string{}
 	for _, prj := range detect.Services {
-		svc, err := add.ServiceFromDetect(root, "", prj, project.ContainerAppTarget)
+		svcName := ""
+		if prj.Compose != nil {
+			// The services imported from a compose file keep their name
+			svcName = names.LabelName(prj.Compose.Name)
+		}
+
+		svc, err := add.ServiceFromDetect(root, svcName, prj, project.ContainerAppTarget)
 		if err != nil {
 			return config, err
 		}
@@ -363,6 +369,12 @@ func (i *Initializer) prjConfigFromDetect(
 		}
 		props.Port = port
 
+		if svc.Compose != nil {
+			for _, name := range slices.Sorted(maps.Keys(svc.Compose.Env)) {
+				props.Env = append(props.Env, project.ServiceEnvVar{Name: name, Value: svc.Compose.Env[name]})
+			}
+		}
+
 		for _, db := range svc.DatabaseDeps {
 			// filter out databases that were removed
 			if _, ok := detect.Databases[db]; !ok {
@@ -68,7 +68,8 @@ func (d *detectConfirm) Init(projects []appdetect.Project, root string) {
 	d.root = root
 
 	for _, project := range projects {
-		if _, supported := add.LanguageMap[project.Language]; supported {
+		_, supported := add.LanguageMap[project.Language]
+		if supported || project.Language == appdetect.Dockerfile {
 			d.Services = append(d.Services, project)
 		}
 
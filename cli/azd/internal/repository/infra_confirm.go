@@ -82,10 +82,8 @@ func (i *Initializer) infraSpecFromDetect(
 		}
 		serviceSpec.Port = port
 
-		for _, framework := range svc.Dependencies {
-			if framework.IsWebUIFramework() {
-				serviceSpec.Frontend = &scaffold.Frontend{}
-			}
+		if svc.HasWebUIFramework() {
+			serviceSpec.Frontend = &scaffold.Frontend{}
 		}
 
 		for _, db := range svc.DatabaseDeps {
@@ -94,6 +94,10 @@ func (i *Initializer) Initialize(
 		return err
 	}
 
+	if err := i.applyTemplatePrompts(ctx, stepMessage, staging); err != nil {
+		return fmt.Errorf("rendering template: %w", err)
+	}
+
 	skipStagingFiles, err := i.promptForDuplicates(ctx, staging, target)
 	if err != nil {
 		return err
@@ -0,0 +1,254 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/braydonk/yaml"
+)
+
+// The name of the file, at the root of a template, which declares the prompts used to render the template on init
+const templateConfigFileName = "template.yaml"
+
+// templateConfig declares the prompts of a template, which are asked on init and used to render the files of the
+// template, so that one template can generate many variants.
+//
+// Example:
+//
+//	prompts:
+//	  - name: database
+//	    message: Which database do you want to use?
+//	    type: select
+//	    choices: [postgres, cosmos]
+//	  - name: enableRedis
+//	    message: Do you want to add a Redis cache?
+//	    type: bool
+//	render:
+//	  - azure.yaml
+//	  - infra/**/*.bicep
+//	conditionalFiles:
+//	  - if: '{{ .Values.enableRedis }}'
+//	    paths:
+//	      - infra/app/redis.bicep
+type templateConfig struct {
+	// The prompts asked on init
+	Prompts []templatePrompt `yaml:"prompts"`
+	// Glob patterns of the files rendered as go templates, with the answers in .Values
+	Render []string `yaml:"render,omitempty"`
+	// Files only included when a condition is met
+	ConditionalFiles []templateConditionalFiles `yaml:"conditionalFiles,omitempty"`
+}
+
+type templatePromptType string
+
+const (
+	templatePromptTypeString templatePromptType = "string"
+	templatePromptTypeBool   templatePromptType = "bool"
+	templatePromptTypeSelect templatePromptType = "select"
+)
+
+// templatePrompt is a question asked on init, which answer is available to templates as .Values.<name>
+type templatePrompt struct {
+	Name    string             `yaml:"name"`
+	Message string             `yaml:"message"`
+	Help    string             `yaml:"help,omitempty"`
+	Type    templatePromptType `yaml:"type,omitempty"`
+	Default any                `yaml:"default,omitempty"`
+	// The choices of select prompts
+	Choices []string `yaml:"choices,omitempty"`
+}
+
+// templateConditionalFiles are files which are removed from the template unless the condition, a go template, renders
+// to 'true'
+type templateConditionalFiles struct {
+	If    string   `yaml:"if"`
+	Paths []string `yaml:"paths"`
+}
+
+// templateData is the data available to the files rendered on init
+type templateData struct {
+	Values map[string]any
+}
+
+// loadTemplateConfig loads the template.yaml file of a template, returning nil when the template doesn't have one.
+func loadTemplateConfig(templateDir string) (*templateConfig, error) {
+	contents, err := os.ReadFile(filepath.Join(templateDir, templateConfigFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var config templateConfig
+	if err := yaml.Unmarshal(contents, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", templateConfigFileName, err)
+	}
+
+	return &config, nil
+}
+
+// applyTemplatePrompts asks the prompts declared by the template.yaml file of the template fetched to templateDir, then
+// renders the files of the template with the answers and removes the conditional files which conditions aren't met.
+// The template.yaml file is removed, since it's only used on init.
+//
+// The spinner showing stepMessage is stopped while prompting.
+func (i *Initializer) applyTemplatePrompts(ctx context.Context, stepMessage string, templateDir string) error {
+	config, err := loadTemplateConfig(templateDir)
+	if err != nil || config == nil {
+		return err
+	}
+
+	values := map[string]any{}
+	if len(config.Prompts) > 0 {
+		i.console.StopSpinner(ctx, "", input.StepDone)
+		for _, prompt := range config.Prompts {
+			value, err := i.askTemplatePrompt(ctx, prompt)
+			if err != nil {
+				return err
+			}
+
+			values[prompt.Name] = value
+		}
+		i.console.ShowSpinner(ctx, stepMessage, input.Step)
+	}
+
+	data := templateData{Values: values}
+
+	for _, conditional := range config.ConditionalFiles {
+		include, err := renderTemplateString(conditional.If, data)
+		if err != nil {
+			return fmt.Errorf("evaluating condition '%s': %w", conditional.If, err)
+		}
+
+		if strings.TrimSpace(include) == "true" {
+			continue
+		}
+
+		for _, pattern := range conditional.Paths {
+			matches, err := doublestar.Glob(os.DirFS(templateDir), filepath.ToSlash(pattern))
+			if err != nil {
+				return fmt.Errorf("matching '%s': %w", pattern, err)
+			}
+
+			for _, match := range matches {
+				if err := os.RemoveAll(filepath.Join(templateDir, filepath.FromSlash(match))); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, pattern := range config.Render {
+		matches, err := doublestar.Glob(os.DirFS(templateDir), filepath.ToSlash(pattern), doublestar.WithFilesOnly())
+		if err != nil {
+			return fmt.Errorf("matching '%s': %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			if err := renderTemplateFile(filepath.Join(templateDir, filepath.FromSlash(match)), data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.Remove(filepath.Join(templateDir, templateConfigFileName))
+}
+
+func (i *Initializer) askTemplatePrompt(ctx context.Context, prompt templatePrompt) (any, error) {
+	options := input.ConsoleOptions{
+		Message:      prompt.Message,
+		Help:         prompt.Help,
+		DefaultValue: prompt.Default,
+	}
+
+	if options.Message == "" {
+		options.Message = fmt.Sprintf("Enter a value for %s", prompt.Name)
+	}
+
+	switch prompt.Type {
+	case templatePromptTypeString, "":
+		if prompt.Default != nil {
+			options.DefaultValue = fmt.Sprint(prompt.Default)
+		}
+
+		value, err := i.console.Prompt(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("prompting for %s: %w", prompt.Name, err)
+		}
+
+		return value, nil
+	case templatePromptTypeBool:
+		if _, isBool := prompt.Default.(bool); !isBool {
+			options.DefaultValue = false
+		}
+
+		value, err := i.console.Confirm(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("prompting for %s: %w", prompt.Name, err)
+		}
+
+		return value, nil
+	case templatePromptTypeSelect:
+		if len(prompt.Choices) == 0 {
+			return nil, fmt.Errorf("the '%s' select prompt of %s has no choices", prompt.Name, templateConfigFileName)
+		}
+
+		options.Options = prompt.Choices
+		if prompt.Default != nil {
+			options.DefaultValue = fmt.Sprint(prompt.Default)
+		}
+
+		selected, err := i.console.Select(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("prompting for %s: %w", prompt.Name, err)
+		}
+
+		return prompt.Choices[selected], nil
+	default:
+		return nil, fmt.Errorf(
+			"the '%s' prompt of %s has an unsupported type '%s'", prompt.Name, templateConfigFileName, prompt.Type)
+	}
+}
+
+func renderTemplateString(text string, data templateData) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	builder := strings.Builder{}
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+func renderTemplateFile(path string, data templateData) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := renderTemplateString(string(contents), data)
+	if err != nil {
+		return fmt.Errorf("rendering %s: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(rendered), info.Mode().Perm())
+}
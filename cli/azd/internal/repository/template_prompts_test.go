@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Initializer_applyTemplatePrompts(t *testing.T) {
+	templateConfig := heredoc.Doc(`
+		prompts:
+		  - name: projectName
+		    message: What is the name of your project?
+		  - name: database
+		    message: Which database do you want to use?
+		    type: select
+		    choices: [postgres, cosmos]
+		  - name: enableRedis
+		    message: Do you want to add a Redis cache?
+		    type: bool
+		render:
+		  - azure.yaml
+		  - infra/**/*.bicep
+		conditionalFiles:
+		  - if: '{{ .Values.enableRedis }}'
+		    paths:
+		      - infra/app/redis.bicep
+		  - if: '{{ eq .Values.database "cosmos" }}'
+		    paths:
+		      - infra/app/cosmos.bicep
+	`)
+
+	files := map[string]string{
+		"template.yaml":          templateConfig,
+		"azure.yaml":             "name: {{ .Values.projectName }}\n",
+		"infra/main.bicep":       "param database string = '{{ .Values.database }}'\n",
+		"infra/app/redis.bicep":  "// redis\n",
+		"infra/app/cosmos.bicep": "// cosmos\n",
+		"src/index.html":         "<p>{{ not rendered }}</p>\n",
+	}
+
+	templateDir := t.TempDir()
+	for path, contents := range files {
+		fullPath := filepath.Join(templateDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(fullPath, []byte(contents), osutil.PermissionFile))
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "name of your project")
+	}).Respond("todo")
+	mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "database")
+	}).Respond(0)
+	mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "Redis")
+	}).Respond(true)
+
+	i := &Initializer{console: mockContext.Console}
+	err := i.applyTemplatePrompts(*mockContext.Context, "Downloading template", templateDir)
+	require.NoError(t, err)
+
+	readFile := func(path string) string {
+		contents, err := os.ReadFile(filepath.Join(templateDir, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		return string(contents)
+	}
+
+	require.Equal(t, "name: todo\n", readFile("azure.yaml"))
+	require.Equal(t, "param database string = 'postgres'\n", readFile("infra/main.bicep"))
+	require.Equal(t, "<p>{{ not rendered }}</p>\n", readFile("src/index.html"))
+	require.FileExists(t, filepath.Join(templateDir, "infra", "app", "redis.bicep"))
+	require.NoFileExists(t, filepath.Join(templateDir, "infra", "app", "cosmos.bicep"))
+	require.NoFileExists(t, filepath.Join(templateDir, "template.yaml"))
+}
+
+func Test_Initializer_applyTemplatePrompts_NoConfig(t *testing.T) {
+	templateDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(templateDir, "azure.yaml"), []byte("name: {{ x }}"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	i := &Initializer{console: mockContext.Console}
+	err = i.applyTemplatePrompts(*mockContext.Context, "Downloading template", templateDir)
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(templateDir, "azure.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "name: {{ x }}", string(contents))
+}
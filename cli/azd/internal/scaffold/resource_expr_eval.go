@@ -109,6 +109,72 @@ func EmitBicep(values map[string]string, emitter Resolver) (map[string]string, e
 	return resolveVariables(values, emitter)
 }
 
+// ExistingVariables returns the variables which can be evaluated for an existing resource, which azd doesn't create.
+// Variables from the azure.yaml spec or from the vault of the environment are left out, with the variables composed from
+// them, ex) the url of a database including the password stored in the vault when the database was created.
+func ExistingVariables(values map[string]string) (map[string]string, error) {
+	dependencies := map[string][]string{}
+	excluded := map[string]bool{}
+	for key, value := range values {
+		expressions, err := Parse(&value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse expression '%s': %w", value, err)
+		}
+
+		for _, expr := range expressions {
+			names, existing := existingDependencies(expr)
+			if !existing {
+				excluded[key] = true
+			}
+			dependencies[key] = append(dependencies[key], names...)
+		}
+	}
+
+	// exclude the variables depending on excluded variables, until no more are found
+	for changed := true; changed; {
+		changed = false
+		for key, names := range dependencies {
+			if !excluded[key] && slices.ContainsFunc(names, func(name string) bool { return excluded[name] }) {
+				excluded[key] = true
+				changed = true
+			}
+		}
+	}
+
+	result := map[string]string{}
+	for key, value := range values {
+		if !excluded[key] {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// existingDependencies returns the variables the expression depends on, and whether the expression can be evaluated for
+// an existing resource.
+func existingDependencies(expr *Expression) ([]string, bool) {
+	switch expr.Kind {
+	case SpecExpr, VaultExpr:
+		return nil, false
+	case VarExpr:
+		return []string{expr.Data.(VarExprData).Name}, true
+	case FuncExpr:
+		names := []string{}
+		for _, arg := range expr.Data.(FuncExprData).Args {
+			argNames, existing := existingDependencies(arg)
+			if !existing {
+				return nil, false
+			}
+			names = append(names, argNames...)
+		}
+
+		return names, true
+	}
+
+	return nil, true
+}
+
 // Resolver is a function that resolves a variable expression.
 // It takes the expression value, and the current results map.
 // The function should evaluate expressions within value and call Replace.
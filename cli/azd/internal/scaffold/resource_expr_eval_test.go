@@ -158,3 +158,23 @@ location: eastus
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
+
+func TestExistingVariables(t *testing.T) {
+	variables, err := ExistingVariables(map[string]string{
+		"database": "${spec.name}",
+		"host":     "${.properties.fullyQualifiedDomainName}",
+		"username": "${.properties.administratorLogin}",
+		"port":     "5432",
+		"password": "${vault.postgres-password}",
+		"url":      "postgresql://${username}:${password}@${host}:${port}/${database}",
+		"endpoint": "${host}:${port}",
+		"name":     "${toLower spec.name}",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"host":     "${.properties.fullyQualifiedDomainName}",
+		"username": "${.properties.administratorLogin}",
+		"port":     "5432",
+		"endpoint": "${host}:${port}",
+	}, variables)
+}
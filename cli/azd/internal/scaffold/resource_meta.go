@@ -101,6 +101,15 @@ var Resources = []ResourceMeta{
 		Variables: map[string]string{
 			"endpoint": "${.properties.endpoint}",
 		},
+		RoleAssignments: RoleAssignments{
+			Write: []RoleAssignment{
+				{
+					Name:               "CognitiveServicesOpenAIUser",
+					RoleDefinitionName: "Cognitive Services OpenAI User",
+					RoleDefinitionId:   "5e0bd9bd-7b93-4f28-af87-19fc36ad61bd",
+				},
+			},
+		},
 	},
 	{
 		ResourceType:      "Microsoft.CognitiveServices/accounts/projects",
@@ -172,6 +181,7 @@ var Resources = []ResourceMeta{
 		ResourceType:      "Microsoft.DocumentDB/databaseAccounts/mongodbDatabases",
 		ApiVersion:        "2023-04-15",
 		StandardVarPrefix: "MONGODB",
+		ParentForEval:     "Microsoft.DocumentDB/databaseAccounts",
 		Variables: map[string]string{
 			"url": "${vault.mongodb-url}",
 		},
@@ -232,6 +232,34 @@ func TestExecInfra(t *testing.T) {
 				},
 			},
 		},
+		{
+			"API with OpenAI model",
+			InfraSpec{
+				AIModels: []AIModel{
+					{
+						Name: "chat",
+						Model: AIModelModel{
+							Name:    "gpt-4o",
+							Version: "2024-08-06",
+						},
+						Sku: AIModelSku{
+							Name:     "GlobalStandard",
+							Capacity: 30,
+						},
+					},
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						AIModels: []AIModelReference{
+							{Name: "chat"},
+						},
+						Host: "containerapp",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
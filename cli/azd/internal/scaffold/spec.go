@@ -80,6 +80,15 @@ type DatabaseRedis struct {
 type AIModel struct {
 	Name  string
 	Model AIModelModel
+	Sku   AIModelSku
+}
+
+// AIModelSku represents the deployment SKU of an AIModel.
+type AIModelSku struct {
+	// The name of the SKU.
+	Name string
+	// The capacity of the deployment, in thousands of tokens per minute.
+	Capacity int32
 }
 
 // AIModel represents a deployed, ready to use AI model.
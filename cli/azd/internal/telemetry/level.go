@@ -0,0 +1,192 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package telemetry
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+)
+
+// Level is how much telemetry is collected
+type Level string
+
+const (
+	// No telemetry is collected
+	LevelOff Level = "off"
+	// Only the events of the commands and operations which failed are collected
+	LevelErrorsOnly Level = "errors-only"
+	// The events of all the commands and operations are collected, without the details of their errors, requests and
+	// tools, ex) the inner error or the correlation id of a request
+	LevelUsage Level = "usage"
+	// All the telemetry is collected
+	LevelFull Level = "full"
+)
+
+// The path of the telemetry level in the user config, overridden by the AZURE_DEV_COLLECT_TELEMETRY environment variable
+const LevelConfigPath = "telemetry.level"
+
+// The path of the fields removed from the telemetry in the user config, ex) ["env.name", "error.*"]
+const ScrubConfigPath = "telemetry.scrub"
+
+// ParseLevel parses a telemetry level. For compatibility, 'yes' is the full level and 'no' turns telemetry off.
+func ParseLevel(value string) (Level, error) {
+	switch strings.ToLower(value) {
+	case "no", "off", "false", "0":
+		return LevelOff, nil
+	case "yes", "on", "true", "1", string(LevelFull):
+		return LevelFull, nil
+	case string(LevelErrorsOnly):
+		return LevelErrorsOnly, nil
+	case string(LevelUsage):
+		return LevelUsage, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid telemetry level '%s', supported levels are: %s, %s, %s, %s",
+			value, LevelOff, LevelErrorsOnly, LevelUsage, LevelFull)
+	}
+}
+
+var (
+	settingsMu      sync.RWMutex
+	configuredLevel = LevelFull
+	scrubbedFields  []string
+)
+
+// SetLevel sets the telemetry level of the user config. The AZURE_DEV_COLLECT_TELEMETRY environment variable still
+// takes precedence over it.
+func SetLevel(level Level) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	configuredLevel = level
+}
+
+// SetScrubbedFields sets the fields removed from the telemetry before it's queued for upload. A field is either the
+// name of an attribute, ex) env.name, or a pattern, ex) error.*
+func SetScrubbedFields(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid scrubbed field '%s': %w", pattern, err)
+		}
+	}
+
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	scrubbedFields = slices.Clone(patterns)
+	return nil
+}
+
+// ScrubbedFields returns the fields removed from the telemetry
+func ScrubbedFields() []string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return slices.Clone(scrubbedFields)
+}
+
+// CurrentLevel returns the telemetry level, from the AZURE_DEV_COLLECT_TELEMETRY environment variable when it's set, or
+// else from the user config.
+func CurrentLevel() Level {
+	if value, has := os.LookupEnv(collectTelemetryEnvVar); has && value != "" {
+		level, err := ParseLevel(value)
+		if err == nil {
+			return level
+		}
+
+		// Any value other than 'no' has always enabled telemetry
+		log.Printf("%v, using the '%s' level", err, LevelFull)
+		return LevelFull
+	}
+
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return configuredLevel
+}
+
+// The fields only collected at the full level
+var detailFields = []string{
+	string(fields.ErrInner),
+	string(fields.ErrFrame),
+	string(fields.ServiceHost),
+	string(fields.ServiceMethod),
+	string(fields.ServiceStatusCode),
+	string(fields.ServiceErrorCode),
+	string(fields.ServiceCorrelationId),
+	string(fields.ToolExitCode),
+	string(fields.CmdFlags),
+}
+
+// Filter selects the telemetry events collected at a level, and removes the scrubbed fields from them.
+type Filter struct {
+	// The level of the telemetry. Empty is the full level
+	Level Level
+	// The names or patterns of the fields removed, ex) error.*
+	Scrub []string
+}
+
+// CurrentFilter returns the filter of the current level and scrubbed fields
+func CurrentFilter() Filter {
+	return Filter{
+		Level: CurrentLevel(),
+		Scrub: ScrubbedFields(),
+	}
+}
+
+// Apply removes the fields of the envelope which aren't collected, and returns whether the envelope is collected at all.
+func (f Filter) Apply(envelope *contracts.Envelope) bool {
+	if f.Level == LevelOff {
+		return false
+	}
+
+	data, ok := envelope.Data.(*contracts.Data)
+	if !ok {
+		return true
+	}
+
+	requestData, ok := data.BaseData.(*contracts.RequestData)
+	if !ok {
+		return true
+	}
+
+	if f.Level == LevelErrorsOnly && requestData.Success {
+		return false
+	}
+
+	removed := func(name string) bool {
+		if f.Level == LevelUsage && slices.Contains(detailFields, name) {
+			return true
+		}
+
+		return slices.ContainsFunc(f.Scrub, func(pattern string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		})
+	}
+
+	for name := range requestData.Properties {
+		if removed(name) {
+			delete(requestData.Properties, name)
+		}
+	}
+
+	for name := range requestData.Measurements {
+		if removed(name) {
+			delete(requestData.Measurements, name)
+		}
+	}
+
+	for name := range envelope.Tags {
+		if removed(name) {
+			delete(envelope.Tags, name)
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/microsoft/ApplicationInsights-Go/appinsights/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"no":          LevelOff,
+		"off":         LevelOff,
+		"yes":         LevelFull,
+		"full":        LevelFull,
+		"errors-only": LevelErrorsOnly,
+		"Usage":       LevelUsage,
+	}
+
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			level, err := ParseLevel(value)
+			require.NoError(t, err)
+			require.Equal(t, expected, level)
+		})
+	}
+
+	_, err := ParseLevel("some")
+	require.Error(t, err)
+}
+
+func TestCurrentLevel(t *testing.T) {
+	t.Cleanup(func() { SetLevel(LevelFull) })
+	SetLevel(LevelUsage)
+
+	t.Run("FromConfig", func(t *testing.T) {
+		t.Setenv(collectTelemetryEnvVar, "")
+		require.Equal(t, LevelUsage, CurrentLevel())
+	})
+
+	t.Run("EnvVarTakesPrecedence", func(t *testing.T) {
+		t.Setenv(collectTelemetryEnvVar, "errors-only")
+		require.Equal(t, LevelErrorsOnly, CurrentLevel())
+	})
+
+	t.Run("OptOut", func(t *testing.T) {
+		t.Setenv(collectTelemetryEnvVar, "no")
+		require.Equal(t, LevelOff, CurrentLevel())
+		require.False(t, IsTelemetryEnabled())
+	})
+
+	t.Run("UnknownEnvVarValue", func(t *testing.T) {
+		t.Setenv(collectTelemetryEnvVar, "sure")
+		require.Equal(t, LevelFull, CurrentLevel())
+	})
+}
+
+func TestSetScrubbedFields(t *testing.T) {
+	t.Cleanup(func() { _ = SetScrubbedFields(nil) })
+
+	require.NoError(t, SetScrubbedFields([]string{"env.name", "error.*"}))
+	require.Equal(t, []string{"env.name", "error.*"}, ScrubbedFields())
+
+	require.Error(t, SetScrubbedFields([]string{"error.["}))
+	require.Equal(t, []string{"env.name", "error.*"}, ScrubbedFields())
+}
+
+func TestFilterApply(t *testing.T) {
+	newEnvelope := func(success bool) *contracts.Envelope {
+		requestData := contracts.NewRequestData()
+		requestData.Success = success
+		requestData.Properties = map[string]string{
+			"cmd.entry":   "cmd.up",
+			"cmd.flags":   `["debug"]`,
+			"env.name":    "dev",
+			"error.inner": "details",
+			"error.code":  "code",
+		}
+		requestData.Measurements = map[string]float64{
+			"tool.exitCode":      1,
+			"cmd.args.count":     0,
+			"perf.interact_time": 10,
+		}
+
+		data := contracts.NewData()
+		data.BaseData = requestData
+		envelope := contracts.NewEnvelope()
+		envelope.Data = data
+		envelope.Tags = map[string]string{contracts.UserAuthUserId: "user"}
+		return envelope
+	}
+
+	requestData := func(envelope *contracts.Envelope) *contracts.RequestData {
+		return envelope.Data.(*contracts.Data).BaseData.(*contracts.RequestData)
+	}
+
+	t.Run("Full", func(t *testing.T) {
+		envelope := newEnvelope(true)
+		require.True(t, Filter{}.Apply(envelope))
+		require.Len(t, requestData(envelope).Properties, 5)
+		require.Len(t, requestData(envelope).Measurements, 3)
+	})
+
+	t.Run("Off", func(t *testing.T) {
+		require.False(t, Filter{Level: LevelOff}.Apply(newEnvelope(false)))
+	})
+
+	t.Run("ErrorsOnly", func(t *testing.T) {
+		require.False(t, Filter{Level: LevelErrorsOnly}.Apply(newEnvelope(true)))
+
+		envelope := newEnvelope(false)
+		require.True(t, Filter{Level: LevelErrorsOnly}.Apply(envelope))
+		require.Len(t, requestData(envelope).Properties, 5)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		envelope := newEnvelope(false)
+		require.True(t, Filter{Level: LevelUsage}.Apply(envelope))
+		require.Equal(t, map[string]string{
+			"cmd.entry":  "cmd.up",
+			"env.name":   "dev",
+			"error.code": "code",
+		}, requestData(envelope).Properties)
+		require.Equal(t, map[string]float64{
+			"cmd.args.count":     0,
+			"perf.interact_time": 10,
+		}, requestData(envelope).Measurements)
+	})
+
+	t.Run("Scrub", func(t *testing.T) {
+		envelope := newEnvelope(true)
+		filter := Filter{Level: LevelFull, Scrub: []string{"env.name", "error.*", "perf.*", contracts.UserAuthUserId}}
+		require.True(t, filter.Apply(envelope))
+		require.Equal(t, map[string]string{
+			"cmd.entry": "cmd.up",
+			"cmd.flags": `["debug"]`,
+		}, requestData(envelope).Properties)
+		require.Equal(t, map[string]float64{
+			"tool.exitCode":  1,
+			"cmd.args.count": 0,
+		}, requestData(envelope).Measurements)
+		require.Empty(t, envelope.Tags)
+	})
+}
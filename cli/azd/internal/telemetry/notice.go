@@ -33,7 +33,7 @@ func FirstNotice() string {
 		//nolint:lll
 		return heredoc.Doc(`
 The Azure Developer CLI collects usage data and sends that usage data to Microsoft in order to help us improve your experience.
-You can opt-out of telemetry by setting the AZURE_DEV_COLLECT_TELEMETRY environment variable to 'no' in the shell you use.
+You can opt-out of telemetry by setting the AZURE_DEV_COLLECT_TELEMETRY environment variable to 'no' in the shell you use, or limit it to 'errors-only' or 'usage'.
 
 Read more about Azure Developer CLI telemetry: https://github.com/Azure/azure-dev#data-collection`)
 	}
@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -173,6 +174,44 @@ func (stg *StorageQueue) Peek() (*StoredItem, error) {
 	}, nil
 }
 
+// Items returns the items of the queue which are not obsolete, including the ones delayed for a retry, from the least
+// recently queued to the most recently queued.
+func (stg *StorageQueue) Items() ([]*StoredItem, error) {
+	items, err := stg.getAllItemsUnordered()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored files: %w", err)
+	}
+
+	slices.SortFunc(items, func(a, b itemEntry) int {
+		return a.fileModTime.Compare(b.fileModTime)
+	})
+
+	result := []*StoredItem{}
+	for _, item := range items {
+		if stg.clock.Since(item.readyTime) >= stg.itemFileMaxTimeKept {
+			continue
+		}
+
+		fileName := filepath.Join(stg.folder, item.name)
+		message, err := os.ReadFile(fileName)
+		if errors.Is(err, os.ErrNotExist) {
+			// The item was uploaded in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stored item: %w", err)
+		}
+
+		result = append(result, &StoredItem{
+			fileName:   fileName,
+			retryCount: item.retryCount,
+			message:    message,
+		})
+	}
+
+	return result, nil
+}
+
 // Removes the stored item from queue.
 // Does not return an error if the item is already removed.
 func (stg *StorageQueue) Remove(item *StoredItem) error {
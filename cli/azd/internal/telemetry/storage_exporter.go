@@ -18,15 +18,18 @@ type simpleQueue interface {
 // Exporter is an implementation of trace.SpanExporter that writes spans into a
 // storage queue in ApplicationInsights format.
 type Exporter struct {
-	queue simpleQueue
+	queue  simpleQueue
+	filter Filter
 
 	anyExported        *atomic.Bool
 	instrumentationKey string
 }
 
-func NewExporter(queue simpleQueue, instrumentationKey string) *Exporter {
+// NewExporter creates an exporter queuing the spans selected by the filter
+func NewExporter(queue simpleQueue, instrumentationKey string, filter Filter) *Exporter {
 	return &Exporter{
 		queue:              queue,
+		filter:             filter,
 		instrumentationKey: instrumentationKey,
 		anyExported:        atomic.NewBool(false),
 	}
@@ -42,6 +45,9 @@ func (e *Exporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan)
 		default:
 			envelope := appinsightsexporter.SpanToEnvelope(span)
 			envelope.IKey = e.instrumentationKey
+			if !e.filter.Apply(envelope) {
+				continue
+			}
 
 			items = append(items, *envelope)
 		}
@@ -22,7 +22,7 @@ import (
 
 func TestExportSpans(t *testing.T) {
 	queue := InMemoryQueue{[][]byte{}}
-	exporter := NewExporter(&queue, "iKey")
+	exporter := NewExporter(&queue, "iKey", Filter{})
 	assert.False(t, exporter.ExportedAny())
 
 	spans := []tracesdk.ReadOnlySpan{}
@@ -40,6 +40,29 @@ func TestExportSpans(t *testing.T) {
 	assert.Len(t, []contracts.Envelope(telemetryItemsQueued), len(spans))
 }
 
+func TestExportSpans_Filter(t *testing.T) {
+	queue := InMemoryQueue{[][]byte{}}
+	exporter := NewExporter(&queue, "iKey", Filter{Level: LevelErrorsOnly})
+
+	failed := GetSpanStub()
+	failed.Status = tracesdk.Status{Code: codes.Error, Description: "Failure"}
+
+	err := exporter.ExportSpans(context.Background(), []tracesdk.ReadOnlySpan{GetSpanStub().Snapshot()})
+	assert.NoError(t, err)
+	assert.Empty(t, queue.queue)
+	assert.False(t, exporter.ExportedAny())
+
+	err = exporter.ExportSpans(
+		context.Background(), []tracesdk.ReadOnlySpan{GetSpanStub().Snapshot(), failed.Snapshot()})
+	assert.NoError(t, err)
+	assert.Len(t, queue.queue, 1)
+	assert.True(t, exporter.ExportedAny())
+
+	var telemetryItemsQueued appinsightsexporter.TelemetryItems
+	telemetryItemsQueued.Deserialize(queue.queue[0])
+	assert.Len(t, []contracts.Envelope(telemetryItemsQueued), 1)
+}
+
 type InMemoryQueue struct {
 	queue [][]byte
 }
@@ -153,6 +153,28 @@ func enqueueAndAssert(storage *StorageQueue, message string, t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestItems(t *testing.T) {
+	dir := t.TempDir()
+	storage := setupStorageQueue(t, dir)
+
+	enqueueAndAssert(storage, "Message1", t)
+	time.Sleep(time.Millisecond * 10)
+	err := storage.EnqueueWithDelay([]byte("Message2"), time.Hour, 1)
+	require.NoError(t, err)
+
+	items, err := storage.Items()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "Message1", string(items[0].Message()))
+	require.Equal(t, "Message2", string(items[1].Message()))
+	require.Equal(t, 1, items[1].RetryCount())
+
+	// Listing the items doesn't remove them
+	item, err := storage.Peek()
+	require.NoError(t, err)
+	require.Equal(t, "Message1", string(item.Message()))
+}
+
 func TestPeekWhenNoItemsExist(t *testing.T) {
 	dir := t.TempDir()
 	storage := setupStorageQueue(t, dir)
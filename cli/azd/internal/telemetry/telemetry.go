@@ -67,7 +67,7 @@ func getTelemetryDirectory() (string, error) {
 
 func IsTelemetryEnabled() bool {
 	// If the user has opted out of telemetry directly, don't collect telemetry.
-	if os.Getenv(collectTelemetryEnvVar) == "no" {
+	if CurrentLevel() == LevelOff {
 		return false
 	}
 
@@ -125,7 +125,7 @@ func initialize() (*TelemetrySystem, error) {
 		return nil, fmt.Errorf("failed to parse appInsights connection string: %w", err)
 	}
 
-	exporter := NewExporter(storageQueue, config.InstrumentationKey)
+	exporter := NewExporter(storageQueue, config.InstrumentationKey, CurrentFilter())
 
 	options := []trace.TracerProviderOption{
 		trace.WithBatcher(exporter),
@@ -200,6 +200,22 @@ func initialize() (*TelemetrySystem, error) {
 	}, nil
 }
 
+// PendingItems returns the telemetry items queued on this machine which are not uploaded yet. The items are read even
+// when telemetry is disabled, since items may have been queued before.
+func PendingItems() ([]*StoredItem, error) {
+	telemetryDir, err := getTelemetryDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine storage directory: %w", err)
+	}
+
+	storageQueue, err := NewStorageQueue(telemetryDir, telemetryItemExtension, appInsightsMaxIngestionDelay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage queue: %w", err)
+	}
+
+	return storageQueue.Items()
+}
+
 // Flushes all ongoing telemetry and shuts down telemetry
 func (ts *TelemetrySystem) Shutdown(ctx context.Context) error {
 	shutdownErr := instance.tracerProvider.Shutdown(ctx)
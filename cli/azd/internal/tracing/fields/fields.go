@@ -186,6 +186,9 @@ const (
 
 	// The frame of the error.
 	ErrFrame = attribute.Key("error.frame")
+
+	// The category of the error, ex) auth or quota.
+	ErrCategory = attribute.Key("error.category")
 )
 
 // Service related fields.
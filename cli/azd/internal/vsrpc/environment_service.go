@@ -144,10 +144,11 @@ func (s *environmentService) DeleteEnvironmentAsync(
 	container.spinnerWriter.AddWriter(spinnerWriter)
 
 	var c struct {
-		provisionManager *provisioning.Manager  `container:"type"`
-		envManager       environment.Manager    `container:"type"`
-		importManager    *project.ImportManager `container:"type"`
-		projectConfig    *project.ProjectConfig `container:"type"`
+		provisionManager *provisioning.Manager    `container:"type"`
+		envManager       environment.Manager      `container:"type"`
+		env              *environment.Environment `container:"type"`
+		importManager    *project.ImportManager   `container:"type"`
+		projectConfig    *project.ProjectConfig   `container:"type"`
 	}
 	container.MustRegisterScoped(func() internal.EnvFlag {
 		return internal.EnvFlag{
@@ -173,7 +174,8 @@ func (s *environmentService) DeleteEnvironmentAsync(
 		}
 
 		// Enable force and purge options
-		destroyOptions := provisioning.NewDestroyOptions(true, true)
+		destroyOptions := provisioning.NewDestroyOptions(true, true).
+			WithExistingResourceIds(project.ExistingResourceIds(c.projectConfig.Resources, c.env.Getenv))
 		_, err = c.provisionManager.Destroy(ctx, destroyOptions)
 		if errors.Is(err, infra.ErrDeploymentsNotFound) || errors.Is(err, infra.ErrDeploymentResourcesNotFound) {
 			_ = observer.OnNext(ctx, newInfoProgressMessage("No Azure resources were found"))
@@ -205,5 +207,6 @@ func (s *environmentService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"RefreshEnvironmentAsync":    NewHandler(s.RefreshEnvironmentAsync),
 		"DeployAsync":                NewHandler(s.DeployAsync),
 		"DeployServiceAsync":         NewHandler(s.DeployServiceAsync),
+		"StreamServiceLogsAsync":     NewHandler(s.StreamServiceLogsAsync),
 	})
 }
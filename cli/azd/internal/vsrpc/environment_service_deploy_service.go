@@ -5,10 +5,15 @@ package vsrpc
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/cmd"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 )
 
@@ -105,7 +110,13 @@ func (s *environmentService) DeployServiceAsync(
 		return nil, err
 	}
 
-	if _, err := c.provisionAction.Run(ctx); err != nil {
+	// The deployment operations are relayed as they are found, the console only logs them once completed
+	provisionCtx := infra.WithDeploymentOperationObserver(ctx,
+		func(ctx context.Context, operation *armresources.DeploymentOperation) {
+			_ = observer.OnNext(ctx, newInfoProgressMessage(deploymentOperationMessage(operation)))
+		})
+
+	if _, err := c.provisionAction.Run(provisionCtx); err != nil {
 		return nil, err
 	}
 
@@ -123,3 +134,17 @@ func (s *environmentService) DeployServiceAsync(
 
 	return s.refreshEnvironmentAsync(ctx, container, name, observer)
 }
+
+// deploymentOperationMessage describes the state of a deployment operation, ex) "Succeeded: Key Vault kv-contoso".
+func deploymentOperationMessage(operation *armresources.DeploymentOperation) string {
+	target := operation.Properties.TargetResource
+	resourceType := convert.ToValueWithDefault(target.ResourceType, "")
+
+	resourceTypeName := azapi.GetResourceTypeDisplayName(azapi.AzureResourceType(resourceType))
+	if resourceTypeName == "" {
+		resourceTypeName = resourceType
+	}
+
+	return fmt.Sprintf("%s: %s %s",
+		*operation.Properties.ProvisioningState, resourceTypeName, convert.ToValueWithDefault(target.ResourceName, ""))
+}
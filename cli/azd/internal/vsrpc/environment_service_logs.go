@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package vsrpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// StreamServiceLogsAsync is the server implementation of:
+// ValueTask<bool> StreamServiceLogsAsync(RequestContext, string, string, IObserver<ProgressMessage>, CancellationToken)
+//
+// It relays the runtime logs of the service, or of all the services of the project when serviceName is empty, to the
+// observer as they are written, until the request is canceled. Each log line is sent as a Logging message. When all the
+// services are streamed, the lines are prefixed with the name of their service.
+func (s *environmentService) StreamServiceLogsAsync(
+	ctx context.Context, rc RequestContext, name, serviceName string, observer *Observer[ProgressMessage],
+) (bool, error) {
+	session, err := s.server.validateSession(rc.Session)
+	if err != nil {
+		return false, err
+	}
+
+	container, err := session.newContainer(rc)
+	if err != nil {
+		return false, err
+	}
+
+	container.MustRegisterScoped(func() internal.EnvFlag {
+		return internal.EnvFlag{
+			EnvironmentName: name,
+		}
+	})
+
+	var c struct {
+		projectConfig   *project.ProjectConfig      `container:"type"`
+		env             *environment.Environment    `container:"type"`
+		resourceManager project.ResourceManager     `container:"type"`
+		logStreamer     *project.ServiceLogStreamer `container:"type"`
+	}
+
+	if err := container.Fill(&c); err != nil {
+		return false, err
+	}
+
+	serviceNames := []string{serviceName}
+	if serviceName == "" {
+		serviceNames = slices.Sorted(maps.Keys(c.projectConfig.Services))
+	}
+
+	streams := map[string]io.ReadCloser{}
+	defer func() {
+		for _, stream := range streams {
+			_ = stream.Close()
+		}
+	}()
+
+	for _, name := range serviceNames {
+		stream, err := s.openServiceLogStream(ctx, c.projectConfig, c.env, c.resourceManager, c.logStreamer, name)
+		if err != nil && serviceName != "" {
+			return false, err
+		} else if err != nil {
+			// Other services may support streaming logs
+			message := fmt.Sprintf("Logs of %s are not available: %v", name, err)
+			_ = observer.OnNext(ctx, newImportantProgressMessage(message))
+			continue
+		}
+
+		streams[name] = stream
+	}
+
+	if len(streams) == 0 {
+		return false, fmt.Errorf("no service supports streaming logs")
+	}
+
+	// Notifications are sent one at a time, so the lines of different services are not interleaved
+	var notifyMu sync.Mutex
+	var wg sync.WaitGroup
+	for name, stream := range streams {
+		prefix := ""
+		if serviceName == "" {
+			prefix = fmt.Sprintf("[%s] ", name)
+		}
+
+		// Reading the stream is blocked until new logs are written, closing it unblocks the reader
+		stop := context.AfterFunc(ctx, func() { _ = stream.Close() })
+		defer stop()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			scanner := bufio.NewScanner(stream)
+			for scanner.Scan() {
+				notifyMu.Lock()
+				_ = observer.OnNext(ctx, newInfoProgressMessage(prefix+scanner.Text()))
+				notifyMu.Unlock()
+			}
+
+			if err := scanner.Err(); err != nil && ctx.Err() == nil {
+				log.Printf("failed reading logs of service %s: %v", name, err)
+			}
+		}()
+	}
+
+	_ = observer.OnNext(ctx, newImportantProgressMessage("Streaming service logs"))
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	return true, nil
+}
+
+// openServiceLogStream starts streaming the runtime logs of the service with the given name.
+func (s *environmentService) openServiceLogStream(
+	ctx context.Context,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	resourceManager project.ResourceManager,
+	logStreamer *project.ServiceLogStreamer,
+	serviceName string,
+) (io.ReadCloser, error) {
+	serviceConfig, has := projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service %s not found", serviceName)
+	}
+
+	targetResource, err := resourceManager.GetTargetResource(ctx, env.GetSubscriptionId(), serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource of service %s: %w", serviceName, err)
+	}
+
+	return logStreamer.Stream(ctx, serviceConfig, targetResource)
+}
@@ -17,6 +17,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -32,6 +33,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/oneauth"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/blang/semver/v4"
 	"github.com/mattn/go-colorable"
 	"github.com/spf13/pflag"
@@ -46,6 +48,8 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
 	if isDebugEnabled() {
+		// Secrets, ex) secure parameters in the traces of the requests, are masked in the debug logs
+		log.SetOutput(output.NewRedactingWriter(os.Stderr))
 		azcorelog.SetListener(func(event azcorelog.Event, msg string) {
 			log.Printf("%s: %s\n", event, msg)
 		})
@@ -53,6 +57,17 @@ func main() {
 		log.SetOutput(io.Discard)
 	}
 
+	// The user config is loaded once for the settings applied before the commands run
+	userConfig, err := config.NewUserConfigManager(config.NewFileConfigManager(config.NewManager())).Load()
+	if err != nil {
+		log.Printf("failed to load the user config: %v", err)
+	} else {
+		configureRedactionPatterns(userConfig)
+		configureTheme(userConfig)
+		configureTelemetry(userConfig)
+		configureTools(userConfig)
+	}
+
 	log.Printf("azd version: %s", internal.Version)
 
 	ts := telemetry.GetTelemetrySystem()
@@ -309,6 +324,92 @@ type updateCacheFile struct {
 	ExpiresOn string `json:"expiresOn"`
 }
 
+const redactPatternsConfigKey = "output.redact.patterns"
+
+// configureRedactionPatterns registers the regular expressions of the values to mask in the output, from the
+// output.redact.patterns key of the user config.
+func configureRedactionPatterns(userConfig config.Config) {
+	patterns, _ := userConfig.GetSlice(redactPatternsConfigKey)
+	for _, pattern := range patterns {
+		expr, err := regexp.Compile(fmt.Sprint(pattern))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, output.WithWarningFormat(
+				"WARNING: ignoring the invalid pattern '%v' of %s: %v", pattern, redactPatternsConfigKey, err))
+			continue
+		}
+
+		output.AddRedactionPattern(expr)
+	}
+}
+
+// configureTheme sets the theme of the console output, the reduced motion mode and the provisioning view from the
+// ux.theme, ux.reducedMotion and ux.provisionView keys of the user config.
+func configureTheme(userConfig config.Config) {
+	if name, has := userConfig.GetString(output.ThemeConfigPath); has && name != "" {
+		theme, err := output.ParseTheme(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, output.WithWarningFormat("WARNING: ignoring %s: %v", output.ThemeConfigPath, err))
+		} else {
+			output.SetTheme(theme)
+		}
+	}
+
+	if value, has := userConfig.Get(output.ReducedMotionConfigPath); has {
+		if reduced, err := strconv.ParseBool(fmt.Sprint(value)); err == nil {
+			output.SetReducedMotion(reduced)
+		} else {
+			log.Printf("ignoring the invalid %s value '%v'", output.ReducedMotionConfigPath, value)
+		}
+	}
+
+	if name, has := userConfig.GetString(output.ProvisionViewConfigPath); has && name != "" {
+		view, err := output.ParseProvisionView(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, output.WithWarningFormat(
+				"WARNING: ignoring %s: %v", output.ProvisionViewConfigPath, err))
+		} else {
+			output.SetProvisionView(view)
+		}
+	}
+}
+
+// configureTelemetry sets the telemetry level and the fields scrubbed from the telemetry from the telemetry.level and
+// telemetry.scrub keys of the user config.
+func configureTelemetry(userConfig config.Config) {
+	if value, has := userConfig.GetString(telemetry.LevelConfigPath); has && value != "" {
+		level, err := telemetry.ParseLevel(value)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, output.WithWarningFormat("WARNING: ignoring %s: %v", telemetry.LevelConfigPath, err))
+		} else {
+			telemetry.SetLevel(level)
+		}
+	}
+
+	patterns, _ := userConfig.GetSlice(telemetry.ScrubConfigPath)
+	scrubbed := []string{}
+	for _, pattern := range patterns {
+		scrubbed = append(scrubbed, fmt.Sprint(pattern))
+	}
+
+	if err := telemetry.SetScrubbedFields(scrubbed); err != nil {
+		// Nothing is collected rather than a field which was meant to be scrubbed
+		fmt.Fprintln(os.Stderr, output.WithWarningFormat(
+			"WARNING: telemetry is turned off since %s is invalid: %v", telemetry.ScrubConfigPath, err))
+		telemetry.SetLevel(telemetry.LevelOff)
+	}
+}
+
+// configureTools sets the internal mirror and the offline bundle the tools azd downloads are downloaded from, from the
+// tools.mirror and tools.bundle keys of the user config.
+func configureTools(userConfig config.Config) {
+	mirror, _ := userConfig.GetString(tools.MirrorConfigPath)
+	bundle, _ := userConfig.GetString(tools.BundleConfigPath)
+	if err := tools.SetDownloadSources(mirror, bundle); err != nil {
+		fmt.Fprintln(os.Stderr, output.WithWarningFormat(
+			"WARNING: ignoring %s and %s: %v", tools.MirrorConfigPath, tools.BundleConfigPath, err))
+	}
+}
+
 // isDebugEnabled checks to see if `--debug` was passed with a truthy
 // value.
 func isDebugEnabled() bool {
@@ -41,5 +41,19 @@ func (p *subscriptionCredentialProvider) CredentialForSubscription(
 		return nil, err
 	}
 
-	return p.credProvider.GetTokenCredential(ctx, tenantId)
+	credential, err := p.credProvider.GetTokenCredential(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tokens of the auxiliary tenants are requested with the tenant id set in the options
+	if len(AuxiliaryTenantsFromContext(ctx)) > 0 {
+		return &multiTenantCredential{
+			tenantId:     tenantId,
+			credential:   credential,
+			credProvider: p.credProvider,
+		}, nil
+	}
+
+	return credential, nil
 }
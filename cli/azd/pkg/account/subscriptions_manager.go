@@ -101,6 +101,9 @@ func (m *SubscriptionsManager) RefreshSubscriptions(ctx context.Context) error {
 
 // Resolve the tenant ID required by the current account to access the given subscription.
 //
+//   - If the context pins a tenant, ex) the tenant of a customer set with AZURE_AUTH_TENANT_ID in the environment, the
+//     pinned tenant ID is immediately returned.
+//
 //   - If the account is logged in with a service principal specified, the service principal's tenant ID
 //     is immediately returned (single-tenant mode).
 //
@@ -108,6 +111,10 @@ func (m *SubscriptionsManager) RefreshSubscriptions(ctx context.Context) error {
 //     See SubscriptionCache for details about caching. On cache miss, all tenants and subscriptions are queried from
 //     azure management services for the current account to build the mapping and populate the cache.
 func (m *SubscriptionsManager) LookupTenant(ctx context.Context, subscriptionId string) (tenantId string, err error) {
+	if pinnedTenantId := TenantFromContext(ctx); pinnedTenantId != "" {
+		return pinnedTenantId, nil
+	}
+
 	principalTenantId, err := m.principalInfo.GetLoggedInServicePrincipalTenantID(ctx)
 	if err != nil {
 		return "", err
@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+)
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context acquiring the tokens of every subscription for the tenant, ex) the tenant of a customer
+// pinned by the environment, instead of the tenant resolved from the subscription.
+func WithTenant(ctx context.Context, tenantId string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantId)
+}
+
+// TenantFromContext returns the tenant pinned by the context, empty when the tenant is resolved from the subscription.
+func TenantFromContext(ctx context.Context) string {
+	tenantId, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenantId
+}
+
+type auxiliaryTenantsContextKey struct{}
+
+// WithAuxiliaryTenants returns a context authenticating the ARM requests that support it to the auxiliary tenants, in
+// addition to the tenant of the subscription, ex) deployments referencing resources of another tenant.
+func WithAuxiliaryTenants(ctx context.Context, tenantIds []string) context.Context {
+	return context.WithValue(ctx, auxiliaryTenantsContextKey{}, tenantIds)
+}
+
+// AuxiliaryTenantsFromContext returns the auxiliary tenants of the context, if any.
+func AuxiliaryTenantsFromContext(ctx context.Context) []string {
+	tenantIds, _ := ctx.Value(auxiliaryTenantsContextKey{}).([]string)
+	return tenantIds
+}
+
+// multiTenantCredential acquires the tokens of the tenant of the subscription with its credential, and the tokens
+// requested for other tenants, ex) the auxiliary tenants of a cross-tenant request, with a credential of that tenant.
+type multiTenantCredential struct {
+	tenantId     string
+	credential   azcore.TokenCredential
+	credProvider auth.MultiTenantCredentialProvider
+}
+
+func (c *multiTenantCredential) GetToken(
+	ctx context.Context,
+	options policy.TokenRequestOptions,
+) (azcore.AccessToken, error) {
+	if options.TenantID == "" || strings.EqualFold(options.TenantID, c.tenantId) {
+		return c.credential.GetToken(ctx, options)
+	}
+
+	credential, err := c.credProvider.GetTokenCredential(ctx, options.TenantID)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("authenticating to auxiliary tenant '%s': %w", options.TenantID, err)
+	}
+
+	return credential.GetToken(ctx, options)
+}
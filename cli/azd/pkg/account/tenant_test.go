@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionsManager_LookupTenant_Pinned(t *testing.T) {
+	ctx := WithTenant(context.Background(), "CUSTOMER_TENANT")
+
+	// The pinned tenant is returned without resolving the subscriptions of the account
+	tenantId, err := (&SubscriptionsManager{}).LookupTenant(ctx, "SUBSCRIPTION_ID")
+	require.NoError(t, err)
+	require.Equal(t, "CUSTOMER_TENANT", tenantId)
+}
+
+func TestSubscriptionCredentialProvider_AuxiliaryTenants(t *testing.T) {
+	provider := NewSubscriptionCredentialProvider(
+		subscriptionTenantResolverFunc(func(ctx context.Context, subscriptionId string) (string, error) {
+			return "TENANT", nil
+		}),
+		multiTenantCredentialProviderFunc(func(ctx context.Context, tenantId string) (azcore.TokenCredential, error) {
+			if tenantId == "UNKNOWN_TENANT" {
+				return nil, errors.New("unknown tenant")
+			}
+
+			return &tenantCredential{tenantId: tenantId}, nil
+		}),
+	)
+
+	credential, err := provider.CredentialForSubscription(context.Background(), "SUBSCRIPTION_ID")
+	require.NoError(t, err)
+	require.Equal(t, &tenantCredential{tenantId: "TENANT"}, credential)
+
+	ctx := WithAuxiliaryTenants(context.Background(), []string{"PARTNER_TENANT"})
+	credential, err = provider.CredentialForSubscription(ctx, "SUBSCRIPTION_ID")
+	require.NoError(t, err)
+
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "TENANT", token.Token)
+
+	token, err = credential.GetToken(ctx, policy.TokenRequestOptions{TenantID: "PARTNER_TENANT"})
+	require.NoError(t, err)
+	require.Equal(t, "PARTNER_TENANT", token.Token)
+
+	_, err = credential.GetToken(ctx, policy.TokenRequestOptions{TenantID: "UNKNOWN_TENANT"})
+	require.ErrorContains(t, err, "authenticating to auxiliary tenant 'UNKNOWN_TENANT'")
+}
+
+// tenantCredential implements [azcore.TokenCredential] and returns its tenant as the token.
+type tenantCredential struct {
+	tenantId string
+}
+
+func (c *tenantCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: c.tenantId}, nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// ArmEnvOptions holds the optional inputs of [Manager.ArmEnvForCurrentUser].
+type ArmEnvOptions struct {
+	// The directory the federated tokens are written to, when they have to be passed as files.
+	TokenDir string
+	// The ids of the tenants, other than the tenant of the current user, the credential is used in.
+	AuxiliaryTenantIDs []string
+	// The tenant the credential is used in, when it isn't the tenant of the current user, ex) the tenant of a customer
+	// pinned by the environment.
+	TenantID string
+}
+
+// ArmEnvForCurrentUser returns the ARM_* environment variables which configure tools built on the Azure SDKs for Go, such
+// as the azurerm and azapi Terraform providers, to authenticate as the service principal or managed identity azd is
+// logged in as.
+//
+// No variables are returned when azd is logged in as a user, delegates auth to az or an external process, or when the
+// credential can't be passed through (for example, when the client certificate isn't in the PKCS#12 format). These tools
+// fall back to the az CLI in that case.
+func (m *Manager) ArmEnvForCurrentUser(ctx context.Context, options *ArmEnvOptions) ([]string, error) {
+	if options == nil {
+		options = &ArmEnvOptions{}
+	}
+
+	if m.UseExternalAuth() {
+		return nil, nil
+	}
+
+	userConfig, err := m.userConfigManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("fetching current user: %w", err)
+	}
+
+	if shouldUseLegacyAuth(userConfig) {
+		return nil, nil
+	}
+
+	authConfig, err := m.readAuthConfig()
+	if err != nil {
+		return nil, fmt.Errorf("reading auth config: %w", err)
+	}
+
+	currentUser, err := readUserProperties(authConfig)
+	if errors.Is(err, ErrNoCurrentUser) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var env []string
+	if currentUser.ManagedIdentity {
+		env = []string{"ARM_USE_MSI=true"}
+		if currentUser.ClientID != nil {
+			env = append(env, fmt.Sprintf("ARM_CLIENT_ID=%s", *currentUser.ClientID))
+		}
+
+		return append(env, m.armCloudEnv()...), nil
+	}
+
+	if currentUser.HomeAccountID != nil || currentUser.TenantID == nil || currentUser.ClientID == nil {
+		return nil, nil
+	}
+
+	ps, err := m.loadSecret(*currentUser.TenantID, *currentUser.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("loading secret: %w: %w", err, ErrNoCurrentUser)
+	}
+
+	switch {
+	case ps.ClientSecret != nil:
+		env = []string{fmt.Sprintf("ARM_CLIENT_SECRET=%s", *ps.ClientSecret)}
+	case ps.ClientCertificate != nil:
+		certData, err := base64.StdEncoding.DecodeString(*ps.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("decoding certificate: %w", err)
+		}
+
+		// The Terraform providers only accept certificates in the PKCS#12 format
+		if bytes.Contains(certData, []byte("-----BEGIN")) {
+			log.Printf("not passing the client certificate through since it is in the PEM format")
+			return nil, nil
+		}
+
+		env = []string{fmt.Sprintf("ARM_CLIENT_CERTIFICATE=%s", *ps.ClientCertificate)}
+	case ps.FederatedAuth != nil && ps.FederatedAuth.TokenProvider != nil:
+		env, err = m.armFederatedEnv(ctx, ps.FederatedAuth, options.TokenDir)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	tenantId := *currentUser.TenantID
+	if options.TenantID != "" {
+		tenantId = options.TenantID
+	}
+
+	env = append(env,
+		fmt.Sprintf("ARM_TENANT_ID=%s", tenantId),
+		fmt.Sprintf("ARM_CLIENT_ID=%s", *currentUser.ClientID),
+	)
+
+	if len(options.AuxiliaryTenantIDs) > 0 {
+		env = append(env, fmt.Sprintf("ARM_AUXILIARY_TENANT_IDS=%s", strings.Join(options.AuxiliaryTenantIDs, ";")))
+	}
+
+	return append(env, m.armCloudEnv()...), nil
+}
+
+// armFederatedEnv returns the variables which let the Terraform providers fetch the federated tokens themselves. Tokens
+// which they can't fetch are fetched by azd and written to a file in tokenDir.
+func (m *Manager) armFederatedEnv(ctx context.Context, auth *federatedAuth, tokenDir string) ([]string, error) {
+	env := []string{"ARM_USE_OIDC=true"}
+
+	switch *auth.TokenProvider {
+	case gitHubFederatedTokenProvider:
+		// The providers read ACTIONS_ID_TOKEN_REQUEST_TOKEN and ACTIONS_ID_TOKEN_REQUEST_URL from the environment
+		return env, nil
+	case azurePipelinesFederatedTokenProvider:
+		systemAccessToken := os.Getenv(azurePipelinesSystemAccessTokenEnvVarName)
+		if systemAccessToken == "" {
+			return nil, errNoSystemAccessTokenEnvVar
+		}
+
+		if auth.ServiceConnectionID == nil {
+			return nil, errors.New("service connection ID not found, please run `azd auth login` to authenticate")
+		}
+
+		return append(env,
+			fmt.Sprintf("ARM_OIDC_AZURE_SERVICE_CONNECTION_ID=%s", *auth.ServiceConnectionID),
+			fmt.Sprintf("ARM_OIDC_REQUEST_TOKEN=%s", systemAccessToken),
+		), nil
+	case oidcFederatedTokenProvider:
+		if idToken, has := os.LookupEnv("AZURE_OIDC_TOKEN"); has {
+			return append(env, fmt.Sprintf("ARM_OIDC_TOKEN=%s", idToken)), nil
+		}
+
+		return append(env,
+			fmt.Sprintf("ARM_OIDC_REQUEST_TOKEN=%s", os.Getenv("AZURE_OIDC_REQUEST_TOKEN")),
+			fmt.Sprintf("ARM_OIDC_REQUEST_URL=%s", os.Getenv("AZURE_OIDC_REQUEST_URL")),
+		), nil
+	case googleFederatedTokenProvider:
+		if tokenDir == "" {
+			return nil, errors.New("a token directory is required to pass Google identity tokens through")
+		}
+
+		client := NewGoogleIdentityTokenClient(azcore.ClientOptions{
+			Transport: m.httpClient,
+			Cloud:     m.cloud.Configuration,
+		})
+		token, err := client.TokenForAudience(ctx, m.federatedTokenAudience(auth.Audience))
+		if err != nil {
+			return nil, fmt.Errorf("fetching federated token: %w", err)
+		}
+
+		if err := os.MkdirAll(tokenDir, 0700); err != nil {
+			return nil, fmt.Errorf("creating token directory: %w", err)
+		}
+
+		tokenPath := filepath.Join(tokenDir, "oidc-token")
+		if err := os.WriteFile(tokenPath, []byte(token), 0600); err != nil {
+			return nil, fmt.Errorf("writing federated token: %w", err)
+		}
+
+		return append(env, fmt.Sprintf("ARM_OIDC_TOKEN_FILE_PATH=%s", tokenPath)), nil
+	default:
+		return nil, fmt.Errorf("unsupported federated token provider: '%s'", string(*auth.TokenProvider))
+	}
+}
+
+// armCloudEnv returns the variable which selects the sovereign cloud azd is configured for, if any.
+func (m *Manager) armCloudEnv() []string {
+	if m.cloud == nil {
+		return nil
+	}
+
+	switch m.cloud.Configuration.ActiveDirectoryAuthorityHost {
+	case azcloud.AzureChina.ActiveDirectoryAuthorityHost:
+		return []string{"ARM_ENVIRONMENT=china"}
+	case azcloud.AzureGovernment.ActiveDirectoryAuthorityHost:
+		return []string{"ARM_ENVIRONMENT=usgovernment"}
+	}
+
+	return nil
+}
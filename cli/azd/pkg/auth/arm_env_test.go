@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/stretchr/testify/require"
+)
+
+func newArmEnvTestManager(c *cloud.Cloud) *Manager {
+	return &Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache:   &memoryCache{cache: make(map[string][]byte)},
+		cloud:             c,
+	}
+}
+
+func TestArmEnvForCurrentUser(t *testing.T) {
+	t.Run("NotLoggedIn", func(t *testing.T) {
+		m := newArmEnvTestManager(cloud.AzurePublic())
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), nil)
+		require.NoError(t, err)
+		require.Empty(t, env)
+	})
+
+	t.Run("ClientSecret", func(t *testing.T) {
+		m := newArmEnvTestManager(cloud.AzureChina())
+		require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+			ClientSecret: to.Ptr("testClientSecret"),
+		}))
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), &ArmEnvOptions{
+			AuxiliaryTenantIDs: []string{"tenant-2", "tenant-3"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ARM_CLIENT_SECRET=testClientSecret",
+			"ARM_TENANT_ID=testTenantId",
+			"ARM_CLIENT_ID=testClientId",
+			"ARM_AUXILIARY_TENANT_IDS=tenant-2;tenant-3",
+			"ARM_ENVIRONMENT=china",
+		}, env)
+	})
+
+	t.Run("PinnedTenant", func(t *testing.T) {
+		m := newArmEnvTestManager(cloud.AzurePublic())
+		require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+			ClientSecret: to.Ptr("testClientSecret"),
+		}))
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), &ArmEnvOptions{TenantID: "customerTenantId"})
+		require.NoError(t, err)
+		require.Contains(t, env, "ARM_TENANT_ID=customerTenantId")
+	})
+
+	t.Run("PemCertificate", func(t *testing.T) {
+		m := newArmEnvTestManager(cloud.AzurePublic())
+		require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+			ClientCertificate: to.Ptr(base64.StdEncoding.EncodeToString(testClientCertificate)),
+		}))
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), nil)
+		require.NoError(t, err)
+		require.Empty(t, env)
+	})
+
+	t.Run("ManagedIdentity", func(t *testing.T) {
+		m := newArmEnvTestManager(cloud.AzurePublic())
+		require.NoError(t, m.saveLoginForManagedIdentity("testClientId"))
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ARM_USE_MSI=true", "ARM_CLIENT_ID=testClientId"}, env)
+	})
+
+	t.Run("AzurePipelines", func(t *testing.T) {
+		t.Setenv(azurePipelinesSystemAccessTokenEnvVarName, "system-token")
+
+		m := newArmEnvTestManager(cloud.AzurePublic())
+		require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+			FederatedAuth: &federatedAuth{
+				TokenProvider:       &azurePipelinesFederatedTokenProvider,
+				ServiceConnectionID: to.Ptr("connection"),
+			},
+		}))
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ARM_USE_OIDC=true",
+			"ARM_OIDC_AZURE_SERVICE_CONNECTION_ID=connection",
+			"ARM_OIDC_REQUEST_TOKEN=system-token",
+			"ARM_TENANT_ID=testTenantId",
+			"ARM_CLIENT_ID=testClientId",
+		}, env)
+	})
+
+	t.Run("Oidc", func(t *testing.T) {
+		t.Setenv("AZURE_OIDC_TOKEN", "id-token")
+
+		m := newArmEnvTestManager(cloud.AzurePublic())
+		require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+			FederatedAuth: &federatedAuth{
+				TokenProvider: &oidcFederatedTokenProvider,
+			},
+		}))
+
+		env, err := m.ArmEnvForCurrentUser(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ARM_USE_OIDC=true",
+			"ARM_OIDC_TOKEN=id-token",
+			"ARM_TENANT_ID=testTenantId",
+			"ARM_CLIENT_ID=testClientId",
+		}, env)
+	})
+}
@@ -7,8 +7,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -85,3 +88,64 @@ func NewFederatedTokenClient(idTokenUrl string, token string, options azcore.Cli
 		idTokenUrl: idTokenUrl,
 	}
 }
+
+// googleMetadataHostEnvVarName is the name of the environment variable which overrides the host of the Google Cloud
+// metadata server, matching the Google Cloud client libraries.
+const googleMetadataHostEnvVarName = "GCE_METADATA_HOST"
+
+// GoogleIdentityTokenClient is a client that can be used to fetch identity tokens of the service account of a Google
+// Cloud workload (e.g. a Cloud Build step) from the metadata server.
+type GoogleIdentityTokenClient struct {
+	metadataHost string
+
+	pipeline runtime.Pipeline
+}
+
+func NewGoogleIdentityTokenClient(options azcore.ClientOptions) *GoogleIdentityTokenClient {
+	metadataHost := os.Getenv(googleMetadataHostEnvVarName)
+	if metadataHost == "" {
+		metadataHost = "metadata.google.internal"
+	}
+
+	return &GoogleIdentityTokenClient{
+		metadataHost: metadataHost,
+		pipeline:     runtime.NewPipeline("google", "1.0.0", runtime.PipelineOptions{}, &options),
+	}
+}
+
+// TokenForAudience fetches an identity token for the audience from the metadata server.
+func (c *GoogleIdentityTokenClient) TokenForAudience(ctx context.Context, audience string) (string, error) {
+	identityUrl := fmt.Sprintf(
+		"http://%s/computeMetadata/v1/instance/service-accounts/default/identity?audience=%s&format=full",
+		c.metadataHost,
+		url.QueryEscape(audience),
+	)
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, identityUrl)
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	req.Raw().Header.Set("Metadata-Flavor", "Google")
+
+	res, err := c.pipeline.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sending request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return "", fmt.Errorf("expected 200 response, got: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading body: %w", err)
+	}
+
+	token := strings.TrimSpace(string(body))
+	if token == "" {
+		return "", errors.New("no token in response")
+	}
+
+	return token, nil
+}
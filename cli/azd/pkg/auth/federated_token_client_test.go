@@ -89,3 +89,31 @@ func TestTokenForAudienceFailure(t *testing.T) {
 	_, err := client.TokenForAudience(context.Background(), "api://AzureADTokenExchange")
 	require.Error(t, err)
 }
+
+func TestGoogleIdentityTokenForAudience(t *testing.T) {
+	t.Setenv("GCE_METADATA_HOST", "localhost:8080")
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var req http.Request
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		req = *request
+		return true
+	}).Respond(&http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString("abc\n")),
+	})
+
+	client := NewGoogleIdentityTokenClient(azcore.ClientOptions{
+		Transport: mockContext.HttpClient,
+	})
+
+	token, err := client.TokenForAudience(context.Background(), "api://AzureADTokenExchange")
+	require.NoError(t, err)
+
+	require.Equal(t, "abc", token)
+	require.Equal(t, "Google", req.Header.Get("Metadata-Flavor"))
+	require.Equal(t,
+		"http://localhost:8080/computeMetadata/v1/instance/service-accounts/default/identity"+
+			"?audience=api%3A%2F%2FAzureADTokenExchange&format=full",
+		req.URL.String())
+}
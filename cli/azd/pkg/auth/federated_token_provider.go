@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FederatedTokenProviderAuto is the name of the federated token provider which detects the provider to use from the CI
+// environment azd is running in.
+const FederatedTokenProviderAuto = "auto"
+
+// defaultFederatedTokenAudience is the audience of federated tokens used when the cloud doesn't configure one.
+const defaultFederatedTokenAudience = "api://AzureADTokenExchange"
+
+// federatedTokenExpiryBuffer is how long before it expires a cached federated token is fetched again.
+const federatedTokenExpiryBuffer = 1 * time.Minute
+
+// DetectFederatedTokenProvider detects the federated token provider from the CI environment azd is running in:
+//
+//   - oidc, when AZURE_OIDC_TOKEN or AZURE_OIDC_REQUEST_URL is set.
+//   - github, when running in GitHub Actions.
+//   - azure-pipelines, when running in Azure Pipelines.
+//   - google, when running in Google Cloud Build. Since Cloud Build doesn't expose its built-in substitutions to build
+//     steps, the step must map them with `env: ['BUILD_ID=$BUILD_ID', 'PROJECT_ID=$PROJECT_ID']`.
+func DetectFederatedTokenProvider() (string, error) {
+	_, hasOidcToken := os.LookupEnv("AZURE_OIDC_TOKEN")
+	_, hasOidcRequestUrl := os.LookupEnv("AZURE_OIDC_REQUEST_URL")
+
+	switch {
+	case hasOidcToken || hasOidcRequestUrl:
+		return string(oidcFederatedTokenProvider), nil
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return string(gitHubFederatedTokenProvider), nil
+	case strings.EqualFold(os.Getenv("TF_BUILD"), "true"):
+		return string(azurePipelinesFederatedTokenProvider), nil
+	case os.Getenv("BUILD_ID") != "" && os.Getenv("PROJECT_ID") != "":
+		return string(googleFederatedTokenProvider), nil
+	default:
+		return "", errors.New(
+			"could not detect a federated credential provider. Supported environments are GitHub Actions, " +
+				"Azure Pipelines and Google Cloud Build, or set AZURE_OIDC_TOKEN for other OIDC providers")
+	}
+}
+
+// federatedTokenSource fetches federated tokens from a federated token provider.
+type federatedTokenSource interface {
+	TokenForAudience(ctx context.Context, audience string) (string, error)
+}
+
+// cachedFederatedToken fetches federated tokens for an audience and caches them until shortly before they expire, so that
+// the provider isn't called each time a Microsoft Entra token is requested for a different scope.
+type cachedFederatedToken struct {
+	source   federatedTokenSource
+	audience string
+
+	mu        sync.Mutex
+	token     string
+	expiresOn time.Time
+}
+
+func newCachedFederatedToken(source federatedTokenSource, audience string) *cachedFederatedToken {
+	return &cachedFederatedToken{
+		source:   source,
+		audience: audience,
+	}
+}
+
+// Token returns the cached federated token, fetching a new one when there is no cached token or it is about to expire.
+func (c *cachedFederatedToken) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Add(federatedTokenExpiryBuffer).Before(c.expiresOn) {
+		return c.token, nil
+	}
+
+	token, err := c.source.TokenForAudience(ctx, c.audience)
+	if err != nil {
+		return "", err
+	}
+
+	// Tokens without a readable expiration aren't cached.
+	c.token = ""
+	if claims, err := GetClaimsFromAccessToken(token); err == nil && claims.ExpirationTime > 0 {
+		c.token = token
+		c.expiresOn = time.Unix(claims.ExpirationTime, 0)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFederatedTokenProvider(t *testing.T) {
+	ciEnvVars := []string{
+		"AZURE_OIDC_TOKEN", "AZURE_OIDC_REQUEST_URL", "GITHUB_ACTIONS", "TF_BUILD", "BUILD_ID", "PROJECT_ID",
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"GitHubActions", map[string]string{"GITHUB_ACTIONS": "true"}, "github"},
+		{"AzurePipelines", map[string]string{"TF_BUILD": "True"}, "azure-pipelines"},
+		{"GoogleCloudBuild", map[string]string{"BUILD_ID": "build", "PROJECT_ID": "project"}, "google"},
+		{"Oidc", map[string]string{"AZURE_OIDC_TOKEN": "token"}, "oidc"},
+		{"OidcPreferred", map[string]string{"AZURE_OIDC_REQUEST_URL": "url", "GITHUB_ACTIONS": "true"}, "oidc"},
+		{"Unknown", map[string]string{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ostest.Unsetenvs(t, ciEnvVars)
+			ostest.Setenvs(t, tt.env)
+
+			provider, err := DetectFederatedTokenProvider()
+			if tt.expected == "" {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, provider)
+		})
+	}
+}
+
+type mockFederatedTokenSource struct {
+	tokens    []string
+	audiences []string
+	err       error
+}
+
+func (m *mockFederatedTokenSource) TokenForAudience(ctx context.Context, audience string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+
+	m.audiences = append(m.audiences, audience)
+	token := m.tokens[0]
+	m.tokens = m.tokens[1:]
+	return token, nil
+}
+
+func fakeJwt(t *testing.T, expiresOn time.Time) string {
+	claims, err := json.Marshal(TokenClaims{ExpirationTime: expiresOn.Unix()})
+	require.NoError(t, err)
+
+	return fmt.Sprintf("header.%s.signature", base64.RawURLEncoding.EncodeToString(claims))
+}
+
+func TestCachedFederatedToken(t *testing.T) {
+	t.Run("CachedUntilExpiry", func(t *testing.T) {
+		token := fakeJwt(t, time.Now().Add(10*time.Minute))
+		source := &mockFederatedTokenSource{tokens: []string{token}}
+		cached := newCachedFederatedToken(source, "api://AzureADTokenExchange")
+
+		for i := 0; i < 3; i++ {
+			actual, err := cached.Token(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, token, actual)
+		}
+
+		require.Equal(t, []string{"api://AzureADTokenExchange"}, source.audiences)
+	})
+
+	t.Run("RefreshedWhenExpiring", func(t *testing.T) {
+		expiring := fakeJwt(t, time.Now().Add(30*time.Second))
+		fresh := fakeJwt(t, time.Now().Add(10*time.Minute))
+		source := &mockFederatedTokenSource{tokens: []string{expiring, fresh}}
+		cached := newCachedFederatedToken(source, "api://AzureADTokenExchange")
+
+		actual, err := cached.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expiring, actual)
+
+		actual, err = cached.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, fresh, actual)
+	})
+
+	t.Run("NotCachedWithoutExpiry", func(t *testing.T) {
+		source := &mockFederatedTokenSource{tokens: []string{"opaque-1", "opaque-2"}}
+		cached := newCachedFederatedToken(source, "api://AzureADTokenExchange")
+
+		actual, err := cached.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "opaque-1", actual)
+
+		actual, err = cached.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "opaque-2", actual)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		source := &mockFederatedTokenSource{err: errors.New("boom")}
+		cached := newCachedFederatedToken(source, "api://AzureADTokenExchange")
+
+		_, err := cached.Token(context.Background())
+		require.Error(t, err)
+	})
+}
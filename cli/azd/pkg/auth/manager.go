@@ -345,8 +345,7 @@ func (m *Manager) CredentialForCurrentUser(
 		} else if ps.ClientCertificate != nil {
 			return m.newCredentialFromClientCertificate(tenantID, *currentUser.ClientID, *ps.ClientCertificate)
 		} else if ps.FederatedAuth != nil && ps.FederatedAuth.TokenProvider != nil {
-			return m.newCredentialFromFederatedTokenProvider(
-				tenantID, *currentUser.ClientID, *ps.FederatedAuth.TokenProvider, ps.FederatedAuth.ServiceConnectionID)
+			return m.newCredentialFromFederatedTokenProvider(tenantID, *currentUser.ClientID, ps.FederatedAuth)
 		}
 	}
 
@@ -531,8 +530,7 @@ func (m *Manager) newCredentialFromClientCertificate(
 func (m *Manager) newCredentialFromFederatedTokenProvider(
 	tenantID string,
 	clientID string,
-	provider federatedTokenProvider,
-	serviceConnectionID *string,
+	auth *federatedAuth,
 ) (azcore.TokenCredential, error) {
 	clientOptions := azcore.ClientOptions{
 		Transport: m.httpClient,
@@ -541,38 +539,24 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 		Cloud: m.cloud.Configuration,
 	}
 
-	switch provider {
+	audience := m.federatedTokenAudience(auth.Audience)
+
+	switch *auth.TokenProvider {
 	case gitHubFederatedTokenProvider:
 		token, has := os.LookupEnv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
 		if !has {
-			return nil, errors.New("no ACTIONS_ID_TOKEN_REQUEST_TOKEN set in environment.")
+			return nil, errors.New("no ACTIONS_ID_TOKEN_REQUEST_TOKEN set in environment. " +
+				"Ensure the workflow has the 'id-token: write' permission")
 		}
 
 		tokenUrl, has := os.LookupEnv("ACTIONS_ID_TOKEN_REQUEST_URL")
 		if !has {
-			return nil, errors.New("no ACTIONS_ID_TOKEN_REQUEST_URL set in the environment")
+			return nil, errors.New("no ACTIONS_ID_TOKEN_REQUEST_URL set in the environment. " +
+				"Ensure the workflow has the 'id-token: write' permission")
 		}
 
 		federatedTokenClient := NewFederatedTokenClient(tokenUrl, token, clientOptions)
-		cred, err := azidentity.NewClientAssertionCredential(
-			tenantID,
-			clientID,
-			func(ctx context.Context) (string, error) {
-				federatedToken, err := federatedTokenClient.TokenForAudience(ctx, "api://AzureADTokenExchange")
-				if err != nil {
-					return "", fmt.Errorf("fetching federated token: %w", err)
-				}
-
-				return federatedToken, nil
-			},
-			&azidentity.ClientAssertionCredentialOptions{
-				ClientOptions: clientOptions,
-			})
-		if err != nil {
-			return nil, fmt.Errorf("creating credential: %w", err)
-		}
-
-		return cred, nil
+		return newFederatedAssertionCredential(tenantID, clientID, federatedTokenClient, audience, clientOptions)
 
 	case azurePipelinesFederatedTokenProvider:
 		systemAccessToken := os.Getenv(azurePipelinesSystemAccessTokenEnvVarName)
@@ -582,12 +566,12 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 
 		// Guard against the case where the service connection ID is not set because someone manually edited the json
 		// files managed by `azd auth login`.
-		if serviceConnectionID == nil {
+		if auth.ServiceConnectionID == nil {
 			return nil, errors.New("service connection ID not found, please run `azd auth login` to authenticate")
 		}
 
 		cred, err := azidentity.NewAzurePipelinesCredential(
-			tenantID, clientID, *serviceConnectionID, systemAccessToken, &azidentity.AzurePipelinesCredentialOptions{
+			tenantID, clientID, *auth.ServiceConnectionID, systemAccessToken, &azidentity.AzurePipelinesCredentialOptions{
 				ClientOptions: clientOptions,
 			},
 		)
@@ -632,29 +616,65 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 		}
 
 		federatedTokenClient := NewFederatedTokenClient(tokenUrl, token, clientOptions)
-		cred, err := azidentity.NewClientAssertionCredential(
-			tenantID,
-			clientID,
-			func(ctx context.Context) (string, error) {
-				federatedToken, err := federatedTokenClient.TokenForAudience(ctx, "api://AzureADTokenExchange")
-				if err != nil {
-					return "", fmt.Errorf("fetching federated token: %w", err)
-				}
+		return newFederatedAssertionCredential(tenantID, clientID, federatedTokenClient, audience, clientOptions)
+	case googleFederatedTokenProvider:
+		identityTokenClient := NewGoogleIdentityTokenClient(clientOptions)
+		return newFederatedAssertionCredential(tenantID, clientID, identityTokenClient, audience, clientOptions)
+	default:
+		return nil, fmt.Errorf("unsupported federated token provider: '%s'", string(*auth.TokenProvider))
+	}
+}
 
-				return federatedToken, nil
-			},
-			&azidentity.ClientAssertionCredentialOptions{
-				ClientOptions: clientOptions,
-			})
-		if err != nil {
-			return nil, fmt.Errorf("creating credential: %w", err)
-		}
+// federatedTokenAudience gets the audience of federated tokens, which is the audience persisted at login. Logins persisted
+// before the audience was recorded keep using the default audience, which the federated credentials of the existing
+// applications were registered with, whatever the cloud.
+func (m *Manager) federatedTokenAudience(audience *string) string {
+	if audience != nil && *audience != "" {
+		return *audience
+	}
 
-		return cred, nil
+	return defaultFederatedTokenAudience
+}
 
-	default:
-		return nil, fmt.Errorf("unsupported federated token provider: '%s'", string(provider))
+// cloudFederatedTokenAudience gets the audience of the federated tokens of new logins which don't set one, the audience
+// of the current cloud.
+func (m *Manager) cloudFederatedTokenAudience() string {
+	if m.cloud != nil && m.cloud.FederatedTokenAudience != "" {
+		return m.cloud.FederatedTokenAudience
 	}
+
+	return defaultFederatedTokenAudience
+}
+
+// newFederatedAssertionCredential creates a credential which authenticates with the federated tokens of the source,
+// which are cached until they expire.
+func newFederatedAssertionCredential(
+	tenantID string,
+	clientID string,
+	source federatedTokenSource,
+	audience string,
+	clientOptions azcore.ClientOptions,
+) (azcore.TokenCredential, error) {
+	federatedToken := newCachedFederatedToken(source, audience)
+	cred, err := azidentity.NewClientAssertionCredential(
+		tenantID,
+		clientID,
+		func(ctx context.Context) (string, error) {
+			token, err := federatedToken.Token(ctx)
+			if err != nil {
+				return "", fmt.Errorf("fetching federated token: %w", err)
+			}
+
+			return token, nil
+		},
+		&azidentity.ClientAssertionCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("creating credential: %w", err)
+	}
+
+	return cred, nil
 }
 
 func (m *Manager) newCredentialFromCloudShell() (azcore.TokenCredential, error) {
@@ -863,27 +883,12 @@ func (m *Manager) LoginWithServicePrincipalCertificate(
 	return cred, nil
 }
 
+// LoginWithGitHubFederatedTokenProvider logs in with the OIDC tokens of GitHub Actions. When audience is empty, the
+// audience of the current cloud is used.
 func (m *Manager) LoginWithGitHubFederatedTokenProvider(
-	ctx context.Context, tenantId, clientId string,
+	ctx context.Context, tenantId, clientId, audience string,
 ) (azcore.TokenCredential, error) {
-	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, gitHubFederatedTokenProvider, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := m.saveLoginForServicePrincipal(
-		tenantId,
-		clientId,
-		&persistedSecret{
-			FederatedAuth: &federatedAuth{
-				TokenProvider: &gitHubFederatedTokenProvider,
-			},
-		},
-	); err != nil {
-		return nil, err
-	}
-
-	return cred, nil
+	return m.loginWithFederatedTokenProvider(tenantId, clientId, gitHubFederatedTokenProvider, audience)
 }
 
 func (m *Manager) LoginWithAzurePipelinesFederatedTokenProvider(
@@ -921,10 +926,36 @@ func (m *Manager) LoginWithAzurePipelinesFederatedTokenProvider(
 	return cred, nil
 }
 
+// LoginWithOidcFederatedTokenProvider logs in with the OIDC tokens set in the AZURE_OIDC_* environment variables. When
+// audience is empty, the audience of the current cloud is used.
 func (m *Manager) LoginWithOidcFederatedTokenProvider(
-	ctx context.Context, tenantId, clientId string,
+	ctx context.Context, tenantId, clientId, audience string,
+) (azcore.TokenCredential, error) {
+	return m.loginWithFederatedTokenProvider(tenantId, clientId, oidcFederatedTokenProvider, audience)
+}
+
+// LoginWithGoogleFederatedTokenProvider logs in with the identity tokens of the service account of a Google Cloud
+// workload, such as a Cloud Build step. When audience is empty, the audience of the current cloud is used.
+func (m *Manager) LoginWithGoogleFederatedTokenProvider(
+	ctx context.Context, tenantId, clientId, audience string,
 ) (azcore.TokenCredential, error) {
-	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, oidcFederatedTokenProvider, nil)
+	return m.loginWithFederatedTokenProvider(tenantId, clientId, googleFederatedTokenProvider, audience)
+}
+
+func (m *Manager) loginWithFederatedTokenProvider(
+	tenantId, clientId string, provider federatedTokenProvider, audience string,
+) (azcore.TokenCredential, error) {
+	// The audience is persisted, so that the login keeps using it when the audience of the cloud changes
+	if audience == "" {
+		audience = m.cloudFederatedTokenAudience()
+	}
+
+	auth := &federatedAuth{
+		TokenProvider: &provider,
+		Audience:      &audience,
+	}
+
+	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -933,9 +964,7 @@ func (m *Manager) LoginWithOidcFederatedTokenProvider(
 		tenantId,
 		clientId,
 		&persistedSecret{
-			FederatedAuth: &federatedAuth{
-				TokenProvider: &oidcFederatedTokenProvider,
-			},
+			FederatedAuth: auth,
 		},
 	); err != nil {
 		return nil, err
@@ -1186,6 +1215,7 @@ var (
 	gitHubFederatedTokenProvider         federatedTokenProvider = "github"
 	azurePipelinesFederatedTokenProvider federatedTokenProvider = "azure-pipelines"
 	oidcFederatedTokenProvider           federatedTokenProvider = "oidc"
+	googleFederatedTokenProvider         federatedTokenProvider = "google"
 )
 
 // token provider for federated auth
@@ -1198,6 +1228,8 @@ type federatedAuth struct {
 	// The ID of the service connection to use for Azure Pipelines federated auth. This is only set when the TokenProvider
 	// is "azure-pipelines".
 	ServiceConnectionID *string `json:"serviceConnectionId,omitempty"`
+	// The audience of the federated tokens. Logins persisted without it use api://AzureADTokenExchange.
+	Audience *string `json:"audience,omitempty"`
 }
 
 // userProperties is the model type for the value we store in the user's config. It is logically a discriminated union of
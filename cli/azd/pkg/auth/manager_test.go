@@ -148,7 +148,7 @@ func TestServicePrincipalLoginFederatedTokenProvider(t *testing.T) {
 		cloud:             cloud.AzurePublic(),
 	}
 
-	cred, err := m.LoginWithGitHubFederatedTokenProvider(context.Background(), "testClientId", "testTenantId")
+	cred, err := m.LoginWithGitHubFederatedTokenProvider(context.Background(), "testClientId", "testTenantId", "")
 
 	require.NoError(t, err)
 	require.IsType(t, new(azidentity.ClientAssertionCredential), cred)
@@ -167,6 +167,72 @@ func TestServicePrincipalLoginFederatedTokenProvider(t *testing.T) {
 	require.True(t, errors.Is(err, ErrNoCurrentUser))
 }
 
+func TestFederatedTokenAudience(t *testing.T) {
+	credentialCache := &memoryCache{
+		cache: make(map[string][]byte),
+	}
+
+	t.Setenv("AZURE_OIDC_TOKEN", "fake-token")
+	mockContext := mocks.NewMockContext(context.Background())
+
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache:   credentialCache,
+		httpClient:        mockContext.HttpClient,
+		cloud:             cloud.AzureChina(),
+	}
+
+	require.Equal(t, "api://AzureADTokenExchangeChina", m.cloudFederatedTokenAudience())
+	require.Equal(t, "api://custom", m.federatedTokenAudience(to.Ptr("api://custom")))
+
+	// Logins persisted before the audience was recorded keep using the default audience in every cloud
+	require.Equal(t, "api://AzureADTokenExchange", m.federatedTokenAudience(nil))
+
+	// New logins record the audience of the cloud when they don't set one
+	_, err := m.LoginWithOidcFederatedTokenProvider(context.Background(), "testTenantId", "testClientId", "")
+	require.NoError(t, err)
+
+	ps, err := m.loadSecret("testTenantId", "testClientId")
+	require.NoError(t, err)
+	require.Equal(t, "api://AzureADTokenExchangeChina", *ps.FederatedAuth.Audience)
+
+	m.cloud = &cloud.Cloud{}
+	require.Equal(t, "api://AzureADTokenExchange", m.cloudFederatedTokenAudience())
+
+	_, err = m.LoginWithOidcFederatedTokenProvider(
+		context.Background(), "testTenantId", "testClientId", "api://custom")
+	require.NoError(t, err)
+
+	ps, err = m.loadSecret("testTenantId", "testClientId")
+	require.NoError(t, err)
+	require.Equal(t, oidcFederatedTokenProvider, *ps.FederatedAuth.TokenProvider)
+	require.Equal(t, "api://custom", *ps.FederatedAuth.Audience)
+}
+
+func TestFederatedTokenAudiencePersistedWithoutAudience(t *testing.T) {
+	t.Setenv("AZURE_OIDC_TOKEN", "fake-token")
+	mockContext := mocks.NewMockContext(context.Background())
+
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache:   &memoryCache{cache: make(map[string][]byte)},
+		httpClient:        mockContext.HttpClient,
+		cloud:             cloud.AzureGovernment(),
+	}
+
+	// A login persisted by an earlier version of azd, which didn't record the audience
+	require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+		FederatedAuth: &federatedAuth{TokenProvider: to.Ptr(oidcFederatedTokenProvider)},
+	}))
+
+	ps, err := m.loadSecret("testTenantId", "testClientId")
+	require.NoError(t, err)
+	require.Nil(t, ps.FederatedAuth.Audience)
+	require.Equal(t, "api://AzureADTokenExchange", m.federatedTokenAudience(ps.FederatedAuth.Audience))
+}
+
 func TestLegacyAzCliCredentialSupport(t *testing.T) {
 	mgr := newMemoryUserConfigManager()
 
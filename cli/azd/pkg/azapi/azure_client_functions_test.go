@@ -100,6 +100,7 @@ func Test_DeployFunctionAppUsingZipFile(t *testing.T) {
 			"FUNC_APP_NAME",
 			zipFile,
 			false,
+			nil,
 		)
 
 		require.NoError(t, err)
@@ -124,6 +125,7 @@ func Test_DeployFunctionAppUsingZipFile(t *testing.T) {
 			"FUNC_APP_NAME",
 			zipFile,
 			false,
+			nil,
 		)
 
 		require.Nil(t, res)
@@ -6,6 +6,7 @@ package azapi
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -25,6 +26,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 		azCli := newAzureClientFromMockContext(mockContext)
 
 		registerIsLinuxWebAppMocks(mockContext, &ran)
+		registerLinuxWebAppOneDeployNotSupportedMocks(mockContext)
 		registerLinuxWebAppZipDeployMocks(mockContext, &ran)
 		registerLinuxWebAppDeployRuntimeSuccessfulMocks(mockContext, &ran)
 
@@ -37,6 +39,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 			"LINUX_WEB_APP_NAME",
 			zipFile,
 			func(s string) {},
+			nil,
 		)
 
 		require.NoError(t, err)
@@ -50,6 +53,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 		azCli := newAzureClientFromMockContext(mockContext)
 
 		registerIsLinuxWebAppMocks(mockContext, &ran)
+		registerLinuxWebAppOneDeployNotSupportedMocks(mockContext)
 		registerLinuxWebAppZipDeployMocks(mockContext, &ran)
 		registerLinuxWebAppDeployRuntimeFailedMocks(mockContext, &ran)
 
@@ -62,6 +66,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 			"LINUX_WEB_APP_NAME",
 			zipFile,
 			func(s string) {},
+			nil,
 		)
 
 		require.Nil(t, res)
@@ -75,6 +80,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 		azCli := newAzureClientFromMockContext(mockContext)
 
 		registerIsLinuxWebAppMocks(mockContext, &ran)
+		registerLinuxWebAppOneDeployNotSupportedMocks(mockContext)
 		registerLinuxWebAppZipDeployMocks(mockContext, &ran)
 		registerLinuxWebAppDeploy500SuccessfulMocks(mockContext, &ran)
 
@@ -87,6 +93,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 			"LINUX_WEB_APP_NAME",
 			zipFile,
 			func(s string) {},
+			nil,
 		)
 
 		require.NoError(t, err)
@@ -94,6 +101,61 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 		require.NotNil(t, res)
 	})
 
+	t.Run("OneDeploy", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		registerIsLinuxWebAppMocks(mockContext, &ran)
+		published := registerLinuxWebAppOneDeployMocks(mockContext)
+
+		zipFile := bytes.NewReader([]byte("content"))
+
+		progress := []string{}
+		res, err := azCli.DeployAppServiceZip(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"LINUX_WEB_APP_NAME",
+			zipFile,
+			func(s string) { progress = append(progress, s) },
+			nil,
+		)
+
+		require.NoError(t, err)
+		require.True(t, ran)
+		require.True(t, *published)
+		require.Equal(t, "OK", *res)
+		require.Contains(t, progress, "Uploading package (7 B of 7 B)")
+	})
+
+	t.Run("OneDeployDisabled", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		registerIsLinuxWebAppMocks(mockContext, &ran)
+		published := registerLinuxWebAppOneDeployMocks(mockContext)
+		registerLinuxWebAppZipDeployMocks(mockContext, &ran)
+		registerLinuxWebAppDeployRuntimeSuccessfulMocks(mockContext, &ran)
+
+		zipFile := bytes.NewReader([]byte("content"))
+
+		res, err := azCli.DeployAppServiceZip(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"LINUX_WEB_APP_NAME",
+			zipFile,
+			func(s string) {},
+			&ZipDeployOptions{DisableOneDeploy: true},
+		)
+
+		require.NoError(t, err)
+		require.False(t, *published)
+		require.NotNil(t, res)
+	})
+
 	t.Run("Logic App Success", func(t *testing.T) {
 		ran := false
 		mockContext := mocks.NewMockContext(context.Background())
@@ -112,6 +174,7 @@ func Test_DeployTrackLinuxWebAppStatus(t *testing.T) {
 			"WINDOWS_LOGIC_APP_NAME",
 			zipFile,
 			func(s string) {},
+			nil,
 		)
 
 		require.NoError(t, err)
@@ -362,3 +425,48 @@ func registerLinuxWebAppZipDeployMocks(mockContext *mocks.MockContext, ran *bool
 		return response, nil
 	})
 }
+
+// registerLinuxWebAppOneDeployNotSupportedMocks mocks a host without OneDeploy, so that the package is deployed with
+// zipdeploy.
+func registerLinuxWebAppOneDeployNotSupportedMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "LINUX_WEB_APP_NAME_SCM_HOST" &&
+			strings.HasSuffix(request.URL.Path, "/api/publish")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+	})
+}
+
+// registerLinuxWebAppOneDeployMocks mocks a successful OneDeploy deployment, and returns whether the package was
+// published.
+func registerLinuxWebAppOneDeployMocks(mockContext *mocks.MockContext) *bool {
+	published := false
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Host == "LINUX_WEB_APP_NAME_SCM_HOST" &&
+			strings.Contains(request.URL.Path, "/api/publish")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if _, err := io.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+
+		published = true
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "DEPLOYMENT_ID")
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Host == "LINUX_WEB_APP_NAME_SCM_HOST" &&
+			strings.Contains(request.URL.Path, "/api/deployments/DEPLOYMENT_ID")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.PublishResponse{
+			Id:         "DEPLOYMENT_ID",
+			Status:     azsdk.PublishStatusSuccess,
+			StatusText: "OK",
+		})
+	})
+
+	return &published
+}
@@ -12,11 +12,14 @@ const (
 	AzureResourceTypeAutomationAccount         AzureResourceType = "Microsoft.Automation/automationAccounts"
 	AzureResourceTypeCacheForRedis             AzureResourceType = "Microsoft.Cache/redis"
 	AzureResourceTypeCDNProfile                AzureResourceType = "Microsoft.Cdn/profiles"
+	AzureResourceTypeCDNEndpoint               AzureResourceType = "Microsoft.Cdn/profiles/endpoints"
+	AzureResourceTypeFrontDoorEndpoint         AzureResourceType = "Microsoft.Cdn/profiles/afdEndpoints"
 	AzureResourceTypeCosmosDb                  AzureResourceType = "Microsoft.DocumentDB/databaseAccounts"
 	AzureResourceTypeEventHubsNamespace        AzureResourceType = "Microsoft.EventHub/namespaces"
 	AzureResourceTypeContainerApp              AzureResourceType = "Microsoft.App/containerApps"
 	AzureResourceTypeSpringApp                 AzureResourceType = "Microsoft.AppPlatform/Spring"
 	AzureResourceTypeContainerAppEnvironment   AzureResourceType = "Microsoft.App/managedEnvironments"
+	AzureResourceTypeContainerGroup            AzureResourceType = "Microsoft.ContainerInstance/containerGroups"
 	AzureResourceTypeDeployment                AzureResourceType = "Microsoft.Resources/deployments"
 	AzureResourceTypeKeyVault                  AzureResourceType = "Microsoft.KeyVault/vaults"
 	AzureResourceTypeManagedHSM                AzureResourceType = "Microsoft.KeyVault/managedHSMs"
@@ -30,6 +33,8 @@ const (
 	AzureResourceTypeStaticWebSite             AzureResourceType = "Microsoft.Web/staticSites"
 	AzureResourceTypeServiceBusNamespace       AzureResourceType = "Microsoft.ServiceBus/namespaces"
 	AzureResourceTypeServicePlan               AzureResourceType = "Microsoft.Web/serverfarms"
+	AzureResourceTypeSignalR                   AzureResourceType = "Microsoft.SignalRService/signalR"
+	AzureResourceTypeWebPubSub                 AzureResourceType = "Microsoft.SignalRService/webPubSub"
 	AzureResourceTypeSqlServer                 AzureResourceType = "Microsoft.Sql/servers"
 	AzureResourceTypeVirtualNetwork            AzureResourceType = "Microsoft.Network/virtualNetworks"
 	AzureResourceTypeWebSite                   AzureResourceType = "Microsoft.Web/sites"
@@ -82,6 +87,12 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Container App"
 	case AzureResourceTypeContainerAppEnvironment:
 		return "Container Apps Environment"
+	case AzureResourceTypeContainerGroup:
+		return "Container Instances"
+	case AzureResourceTypeSignalR:
+		return "SignalR"
+	case AzureResourceTypeWebPubSub:
+		return "Web PubSub"
 	case AzureResourceTypeServiceBusNamespace:
 		return "Service Bus Namespace"
 	case AzureResourceTypeEventHubsNamespace:
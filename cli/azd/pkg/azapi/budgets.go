@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The Consumption SDK isn't a dependency of azd, the REST API is called directly
+const consumptionApiVersion = "2023-05-01"
+
+// Budget is a Cost Management budget of a subscription, with the spend of its current period.
+type Budget struct {
+	Name string
+	// The category of the budget, either Cost or Usage
+	Category string
+	// The period the budget is reset, ex) Monthly
+	TimeGrain string
+	Amount    float64
+	// The actual spend of the current period, in Currency
+	CurrentSpend float64
+	Currency     string
+}
+
+type budgetResource struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Category     string  `json:"category"`
+		Amount       float64 `json:"amount"`
+		TimeGrain    string  `json:"timeGrain"`
+		CurrentSpend *struct {
+			Amount float64 `json:"amount"`
+			Unit   string  `json:"unit"`
+		} `json:"currentSpend"`
+	} `json:"properties"`
+}
+
+// ListSubscriptionBudgets lists the budgets defined at the scope of a subscription.
+func (cli *AzureClient) ListSubscriptionBudgets(ctx context.Context, subscriptionId string) ([]Budget, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-consumption", "v1.0.0", credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	nextLink := runtime.JoinPaths(
+		client.Endpoint(), azure.SubscriptionRID(subscriptionId), "providers/Microsoft.Consumption/budgets")
+	nextLink += "?api-version=" + consumptionApiVersion
+
+	budgets := []Budget{}
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("listing budgets of subscription '%s': %w", subscriptionId, err)
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, fmt.Errorf(
+				"listing budgets of subscription '%s': %w", subscriptionId, runtime.NewResponseError(res))
+		}
+
+		var page struct {
+			Value    []budgetResource `json:"value"`
+			NextLink string           `json:"nextLink"`
+		}
+		if err := runtime.UnmarshalAsJSON(res, &page); err != nil {
+			return nil, err
+		}
+
+		for _, resource := range page.Value {
+			budget := Budget{
+				Name:      resource.Name,
+				Category:  resource.Properties.Category,
+				TimeGrain: resource.Properties.TimeGrain,
+				Amount:    resource.Properties.Amount,
+			}
+			if spend := resource.Properties.CurrentSpend; spend != nil {
+				budget.CurrentSpend = spend.Amount
+				budget.Currency = spend.Unit
+			}
+
+			budgets = append(budgets, budget)
+		}
+
+		nextLink = page.NextLink
+	}
+
+	return budgets, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListSubscriptionBudgets(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasSuffix(request.URL.Path, "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Consumption/budgets")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{
+					{
+						"name": "sponsorship",
+						"properties": map[string]any{
+							"category":     "Cost",
+							"amount":       150,
+							"timeGrain":    "Monthly",
+							"currentSpend": map[string]any{"amount": 97.5, "unit": "USD"},
+						},
+					},
+					{
+						"name": "new",
+						"properties": map[string]any{
+							"category":  "Cost",
+							"amount":    10,
+							"timeGrain": "Annually",
+						},
+					},
+				},
+			})
+		})
+
+		budgets, err := azCli.ListSubscriptionBudgets(*mockContext.Context, "SUBSCRIPTION_ID")
+		require.NoError(t, err)
+		require.Equal(t, []Budget{
+			{
+				Name:         "sponsorship",
+				Category:     "Cost",
+				TimeGrain:    "Monthly",
+				Amount:       150,
+				CurrentSpend: 97.5,
+				Currency:     "USD",
+			},
+			{Name: "new", Category: "Cost", TimeGrain: "Annually", Amount: 10},
+		}, budgets)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/budgets")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		_, err := azCli.ListSubscriptionBudgets(*mockContext.Context, "SUBSCRIPTION_ID")
+		require.ErrorContains(t, err, "listing budgets of subscription 'SUBSCRIPTION_ID'")
+	})
+}
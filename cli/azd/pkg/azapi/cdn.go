@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// The API version of Microsoft.Cdn
+const cdnApiVersion = "2024-02-01"
+
+// CdnService provides actions on top of Azure Front Door and Azure CDN endpoints
+type CdnService interface {
+	// Purges the cached content at the specified paths of a Front Door (Microsoft.Cdn/profiles/afdEndpoints) or CDN
+	// (Microsoft.Cdn/profiles/endpoints) endpoint, waiting for the purge to complete
+	PurgeContent(ctx context.Context, endpointId string, contentPaths []string) error
+}
+
+type cdnService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the CdnService
+func NewCdnService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) CdnService {
+	return &cdnService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+func (cs *cdnService) PurgeContent(ctx context.Context, endpointId string, contentPaths []string) error {
+	resourceId, err := arm.ParseResourceID(endpointId)
+	if err != nil {
+		return fmt.Errorf("parsing endpoint id '%s': %w", endpointId, err)
+	}
+
+	if !strings.EqualFold(resourceId.ResourceType.String(), string(AzureResourceTypeFrontDoorEndpoint)) &&
+		!strings.EqualFold(resourceId.ResourceType.String(), string(AzureResourceTypeCDNEndpoint)) {
+		return fmt.Errorf("'%s' is not a Front Door or CDN endpoint", endpointId)
+	}
+
+	credential, err := cs.credentialProvider.CredentialForSubscription(ctx, resourceId.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	client, err := arm.NewClient("azd-cdn", "v1.0.0", credential, cs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodPost,
+		runtime.JoinPaths(client.Endpoint(), resourceId.String(), "purge"),
+	)
+	if err != nil {
+		return err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", cdnApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+
+	if err := runtime.MarshalAsJSON(req, map[string]any{"contentPaths": contentPaths}); err != nil {
+		return err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return fmt.Errorf("purging endpoint '%s': %w", resourceId.Name, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusAccepted) {
+		return fmt.Errorf("purging endpoint '%s': %w", resourceId.Name, runtime.NewResponseError(res))
+	}
+
+	poller, err := runtime.NewPoller[any](res, client.Pipeline(), nil)
+	if err != nil {
+		return fmt.Errorf("purging endpoint '%s': %w", resourceId.Name, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("purging endpoint '%s': %w", resourceId.Name, err)
+	}
+
+	return nil
+}
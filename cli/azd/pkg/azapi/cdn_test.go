@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PurgeContent(t *testing.T) {
+	frontDoorEndpointId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Cdn" +
+		"/profiles/PROFILE/afdEndpoints/ENDPOINT"
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		cdnService := NewCdnService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		var purgeBody map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost &&
+				strings.HasSuffix(request.URL.Path, "/profiles/PROFILE/afdEndpoints/ENDPOINT/purge")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(request.Body).Decode(&purgeBody); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+		})
+
+		err := cdnService.PurgeContent(*mockContext.Context, frontDoorEndpointId, []string{"/*", "/index.html"})
+		require.NoError(t, err)
+		require.Equal(t, []any{"/*", "/index.html"}, purgeBody["contentPaths"])
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		cdnService := NewCdnService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/purge")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		err := cdnService.PurgeContent(*mockContext.Context, frontDoorEndpointId, []string{"/*"})
+		require.Error(t, err)
+	})
+
+	t.Run("NotAnEndpoint", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		cdnService := NewCdnService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		err := cdnService.PurgeContent(
+			*mockContext.Context,
+			"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/WEB",
+			[]string{"/*"},
+		)
+		require.ErrorContains(t, err, "is not a Front Door or CDN endpoint")
+	})
+}
@@ -0,0 +1,298 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The Azure Container Instances SDK isn't a dependency of azd, the REST API is called directly
+const containerInstancesApiVersion = "2023-05-01"
+
+// ContainerGroupRestartPolicy is the restart policy of the containers of a container group.
+type ContainerGroupRestartPolicy string
+
+const (
+	ContainerGroupRestartAlways    ContainerGroupRestartPolicy = "Always"
+	ContainerGroupRestartOnFailure ContainerGroupRestartPolicy = "OnFailure"
+	ContainerGroupRestartNever     ContainerGroupRestartPolicy = "Never"
+)
+
+// The state of a container which process has exited.
+const ContainerStateTerminated = "Terminated"
+
+// ContainerGroup is an Azure Container Instances container group.
+type ContainerGroup struct {
+	Location   string                   `json:"location"`
+	Tags       map[string]string        `json:"tags,omitempty"`
+	Identity   *ContainerGroupIdentity  `json:"identity,omitempty"`
+	Properties ContainerGroupProperties `json:"properties"`
+}
+
+// ContainerGroupIdentity are the managed identities of a container group.
+type ContainerGroupIdentity struct {
+	// 'UserAssigned', the only type supported by azd
+	Type string `json:"type"`
+	// The resource ids of the user assigned identities, the values are returned by Azure
+	UserAssignedIdentities map[string]any `json:"userAssignedIdentities,omitempty"`
+}
+
+// ContainerGroupProperties are the properties of a container group.
+type ContainerGroupProperties struct {
+	OsType                   string                             `json:"osType"`
+	RestartPolicy            ContainerGroupRestartPolicy        `json:"restartPolicy,omitempty"`
+	Containers               []ContainerGroupContainer          `json:"containers"`
+	ImageRegistryCredentials []ContainerGroupRegistryCredential `json:"imageRegistryCredentials,omitempty"`
+	IpAddress                *ContainerGroupIpAddress           `json:"ipAddress,omitempty"`
+	ProvisioningState        string                             `json:"provisioningState,omitempty"`
+	InstanceView             *ContainerGroupInstanceView        `json:"instanceView,omitempty"`
+}
+
+// ContainerGroupContainer is a container of a container group.
+type ContainerGroupContainer struct {
+	Name       string                            `json:"name"`
+	Properties ContainerGroupContainerProperties `json:"properties"`
+}
+
+// ContainerGroupContainerProperties are the properties of a container of a container group.
+type ContainerGroupContainerProperties struct {
+	Image                string                         `json:"image"`
+	Command              []string                       `json:"command,omitempty"`
+	Ports                []ContainerGroupPort           `json:"ports,omitempty"`
+	EnvironmentVariables []ContainerEnvironmentVariable `json:"environmentVariables,omitempty"`
+	Resources            ContainerResources             `json:"resources"`
+	InstanceView         *ContainerInstanceView         `json:"instanceView,omitempty"`
+}
+
+// ContainerEnvironmentVariable is an environment variable of a container. Secrets are set in SecureValue, which is
+// never returned by Azure.
+type ContainerEnvironmentVariable struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	SecureValue string `json:"secureValue,omitempty"`
+}
+
+// ContainerResources are the resources requested by a container.
+type ContainerResources struct {
+	Requests ContainerResourceRequests `json:"requests"`
+}
+
+// ContainerResourceRequests are the CPU cores and memory in GB requested by a container.
+type ContainerResourceRequests struct {
+	Cpu        float64 `json:"cpu"`
+	MemoryInGB float64 `json:"memoryInGB"`
+}
+
+// ContainerGroupPort is a port opened by a container or by the IP address of a container group.
+type ContainerGroupPort struct {
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// ContainerGroupRegistryCredential are the credentials used to pull the images of a private registry, either a username
+// and password or the resource id of a managed identity of the container group.
+type ContainerGroupRegistryCredential struct {
+	Server   string `json:"server"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Identity string `json:"identity,omitempty"`
+}
+
+// ContainerGroupIpAddress is the IP address of a container group.
+type ContainerGroupIpAddress struct {
+	Type  string               `json:"type"`
+	Ports []ContainerGroupPort `json:"ports"`
+	Ip    string               `json:"ip,omitempty"`
+	Fqdn  string               `json:"fqdn,omitempty"`
+}
+
+// ContainerGroupInstanceView is the runtime state of a container group.
+type ContainerGroupInstanceView struct {
+	State string `json:"state,omitempty"`
+}
+
+// ContainerInstanceView is the runtime state of a container.
+type ContainerInstanceView struct {
+	RestartCount int             `json:"restartCount,omitempty"`
+	CurrentState *ContainerState `json:"currentState,omitempty"`
+}
+
+// ContainerState is the state of a container. ExitCode is set once the container is terminated.
+type ContainerState struct {
+	State        string `json:"state,omitempty"`
+	ExitCode     *int32 `json:"exitCode,omitempty"`
+	DetailStatus string `json:"detailStatus,omitempty"`
+}
+
+// GetContainerGroup gets a container group. When the container group doesn't exist, the error is an
+// *azcore.ResponseError with the http.StatusNotFound status code.
+func (cli *AzureClient) GetContainerGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	containerGroupName string,
+) (*ContainerGroup, error) {
+	client, err := cli.createContainerInstancesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newContainerInstancesRequest(
+		ctx,
+		http.MethodGet,
+		runtime.JoinPaths(
+			client.Endpoint(), azure.ContainerGroupRID(subscriptionId, resourceGroup, containerGroupName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("getting container group '%s': %w", containerGroupName, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, fmt.Errorf("getting container group '%s': %w", containerGroupName, runtime.NewResponseError(res))
+	}
+
+	var containerGroup ContainerGroup
+	if err := runtime.UnmarshalAsJSON(res, &containerGroup); err != nil {
+		return nil, err
+	}
+
+	return &containerGroup, nil
+}
+
+// CreateOrUpdateContainerGroup creates or updates a container group, waiting until it's provisioned. The containers
+// of the group are started with their new configuration.
+func (cli *AzureClient) CreateOrUpdateContainerGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	containerGroupName string,
+	containerGroup ContainerGroup,
+) (*ContainerGroup, error) {
+	client, err := cli.createContainerInstancesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newContainerInstancesRequest(
+		ctx,
+		http.MethodPut,
+		runtime.JoinPaths(
+			client.Endpoint(), azure.ContainerGroupRID(subscriptionId, resourceGroup, containerGroupName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runtime.MarshalAsJSON(req, containerGroup); err != nil {
+		return nil, err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("deploying container group '%s': %w", containerGroupName, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusCreated) {
+		return nil, fmt.Errorf("deploying container group '%s': %w", containerGroupName, runtime.NewResponseError(res))
+	}
+
+	poller, err := runtime.NewPoller[ContainerGroup](res, client.Pipeline(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("deploying container group '%s': %w", containerGroupName, err)
+	}
+
+	deployed, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("deploying container group '%s': %w", containerGroupName, err)
+	}
+
+	return &deployed, nil
+}
+
+// GetContainerLogs gets the logs written by a container of a container group since it started.
+func (cli *AzureClient) GetContainerLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	containerGroupName string,
+	containerName string,
+) (string, error) {
+	client, err := cli.createContainerInstancesClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := newContainerInstancesRequest(
+		ctx,
+		http.MethodGet,
+		runtime.JoinPaths(
+			client.Endpoint(),
+			azure.ContainerGroupRID(subscriptionId, resourceGroup, containerGroupName),
+			"containers",
+			containerName,
+			"logs",
+		),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("getting logs of container '%s': %w", containerName, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return "", fmt.Errorf("getting logs of container '%s': %w", containerName, runtime.NewResponseError(res))
+	}
+
+	var logs struct {
+		Content string `json:"content"`
+	}
+	if err := runtime.UnmarshalAsJSON(res, &logs); err != nil {
+		return "", err
+	}
+
+	return logs.Content, nil
+}
+
+func newContainerInstancesRequest(ctx context.Context, method string, url string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, url)
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", containerInstancesApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
+func (cli *AzureClient) createContainerInstancesClient(ctx context.Context, subscriptionId string) (*arm.Client, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-container-instances", "v1.0.0", credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	return client, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 )
@@ -42,6 +43,10 @@ type acrToken struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type acrAccessToken struct {
+	AccessToken string `json:"access_token"`
+}
+
 // ContainerRegistryService provides access to query and login to Azure Container Registries (ACR)
 type ContainerRegistryService interface {
 	// Logs into the specified container registry
@@ -50,6 +55,13 @@ type ContainerRegistryService interface {
 	Credentials(ctx context.Context, subscriptionId string, loginServer string) (*DockerCredentials, error)
 	// Gets a list of container registries for the specified subscription
 	GetContainerRegistries(ctx context.Context, subscriptionId string) ([]*armcontainerregistry.Registry, error)
+	// Finds the container registry with the specified login server (ex: myregistry.azurecr.io) in the subscription
+	FindContainerRegistry(
+		ctx context.Context, subscriptionId string, loginServer string) (*armcontainerregistry.Registry, error)
+	// Deletes the tag of an image of the specified container registry. Deleting a tag which doesn't exist succeeds.
+	DeleteImageTag(ctx context.Context, subscriptionId string, loginServer string, repository string, tag string) error
+	// Gets an access token for the registry REST API with the given scope, ex) repository:app/api:pull,push
+	AccessToken(ctx context.Context, subscriptionId string, loginServer string, scope string) (string, error)
 }
 
 type containerRegistryService struct {
@@ -99,6 +111,21 @@ func (crs *containerRegistryService) GetContainerRegistries(
 	return results, nil
 }
 
+// Finds the container registry with the specified login server (ex: myregistry.azurecr.io) in the subscription
+func (crs *containerRegistryService) FindContainerRegistry(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+) (*armcontainerregistry.Registry, error) {
+	registryName, _, _ := strings.Cut(loginServer, ".")
+	registry, _, err := crs.findContainerRegistryByName(ctx, subscriptionId, registryName)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
 func (crs *containerRegistryService) Login(ctx context.Context, subscriptionId string, loginServer string) error {
 	dockerCreds, err := crs.Credentials(ctx, subscriptionId, loginServer)
 	if err != nil {
@@ -147,6 +174,82 @@ func (crs *containerRegistryService) Credentials(
 	return dockerCreds, nil
 }
 
+// AccessToken gets an access token for the registry REST API with the given scope, exchanging the ACR refresh token of
+// the current user.
+func (crs *containerRegistryService) AccessToken(
+	ctx context.Context, subscriptionId string, loginServer string, scope string,
+) (string, error) {
+	refreshToken, err := crs.getAcrToken(ctx, subscriptionId, loginServer)
+	if err != nil {
+		return "", fmt.Errorf("getting token for registry '%s': %w", loginServer, err)
+	}
+
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	// Implementation based on docs @ https://azure.github.io/acr/AAD-OAuth.html
+	formData := url.Values{}
+	formData.Set("grant_type", "refresh_token")
+	formData.Set("service", loginServer)
+	formData.Set("scope", scope)
+	formData.Set("refresh_token", refreshToken.RefreshToken)
+
+	req, err := azruntime.NewRequest(ctx, http.MethodPost, fmt.Sprintf("https://%s/oauth2/token", loginServer))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	setHttpRequestBody(req, formData)
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !azruntime.HasStatusCode(response, http.StatusOK) {
+		return "", azruntime.NewResponseError(response)
+	}
+
+	accessToken, err := httputil.ReadRawResponse[acrAccessToken](response)
+	if err != nil {
+		return "", err
+	}
+
+	return accessToken.AccessToken, nil
+}
+
+// DeleteImageTag deletes the tag of an image of the container registry, using the registry REST API with an access
+// token scoped to deleting from the repository.
+func (crs *containerRegistryService) DeleteImageTag(
+	ctx context.Context, subscriptionId string, loginServer string, repository string, tag string,
+) error {
+	accessToken, err := crs.AccessToken(
+		ctx, subscriptionId, loginServer, fmt.Sprintf("repository:%s:delete", repository))
+	if err != nil {
+		return err
+	}
+
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	req, err := azruntime.NewRequest(
+		ctx, http.MethodDelete, fmt.Sprintf("https://%s/acr/v1/%s/_tags/%s", loginServer, repository, tag))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Raw().Header.Set("Authorization", "Bearer "+accessToken)
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return fmt.Errorf("deleting image %s/%s:%s: %w", loginServer, repository, tag, err)
+	}
+
+	if !azruntime.HasStatusCode(response, http.StatusAccepted, http.StatusOK, http.StatusNotFound) {
+		return fmt.Errorf(
+			"deleting image %s/%s:%s: %w", loginServer, repository, tag, azruntime.NewResponseError(response))
+	}
+
+	return nil
+}
+
 // getTokenCredentials
 func (crs *containerRegistryService) getTokenCredentials(
 	ctx context.Context,
@@ -158,11 +261,8 @@ func (crs *containerRegistryService) getTokenCredentials(
 		return nil, fmt.Errorf("failed getting ACR token: %w", err)
 	}
 
-	// Set it to 00000000-0000-0000-0000-000000000000 as per documented in
-	//nolint:lll
-	// https://learn.microsoft.com/azure/container-registry/container-registry-authentication?tabs=azure-cli#individual-login-with-microsoft-entra-id
 	return &DockerCredentials{
-		Username:    "00000000-0000-0000-0000-000000000000",
+		Username:    containerregistry.TokenUsername,
 		Password:    acrToken.RefreshToken,
 		LoginServer: loginServer,
 	}, nil
@@ -276,37 +376,14 @@ func (crs *containerRegistryService) getAcrToken(
 		return nil, fmt.Errorf("getting token for subscription '%s': %w", subscriptionId, err)
 	}
 
-	// Implementation based on docs @ https://azure.github.io/acr/AAD-OAuth.html
 	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
 
-	formData := url.Values{}
-	formData.Set("grant_type", "access_token")
-	formData.Set("service", loginServer)
-	formData.Set("access_token", token.Token)
-
-	tokenUrl := fmt.Sprintf("https://%s/oauth2/exchange", loginServer)
-	req, err := azruntime.NewRequest(ctx, http.MethodPost, tokenUrl)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	setHttpRequestBody(req, formData)
-
-	response, err := pipeline.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	if !azruntime.HasStatusCode(response, http.StatusOK) {
-		return nil, azruntime.NewResponseError(response)
-	}
-
-	acrTokenBody, err := httputil.ReadRawResponse[acrToken](response)
+	refreshToken, err := containerregistry.ExchangeRefreshToken(ctx, pipeline, loginServer, token.Token)
 	if err != nil {
 		return nil, err
 	}
 
-	return acrTokenBody, nil
+	return &acrToken{RefreshToken: refreshToken}, nil
 }
 
 func setHttpRequestBody(req *policy.Request, formData url.Values) {
@@ -0,0 +1,627 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// The API version of Microsoft.Network/dnsZones
+const dnsApiVersion = "2018-05-01"
+
+// The TTL, in seconds, of the DNS records created to validate custom domains
+const customDomainRecordTtl = 3600
+
+// How often and how long the issuance of App Service managed certificates is polled
+var (
+	managedCertificatePollInterval = 10 * time.Second
+	managedCertificateTimeout      = 15 * time.Minute
+)
+
+// DnsRecordType is the type of the DNS records used to validate custom domains
+type DnsRecordType string
+
+const (
+	DnsRecordTypeTxt   DnsRecordType = "TXT"
+	DnsRecordTypeCname DnsRecordType = "CNAME"
+)
+
+// DnsRecord is a DNS record which must exist for a custom domain to be validated and routed to a resource
+type DnsRecord struct {
+	Type DnsRecordType
+	// The fully qualified name of the record
+	Name string
+	// The value of TXT records or the target of CNAME records
+	Value string
+}
+
+// DnsZone is an Azure DNS zone
+type DnsZone struct {
+	Id   string
+	Name string
+}
+
+// CustomDomainService binds custom domains to App Service, Container Apps and Static Web Apps resources.
+type CustomDomainService interface {
+	// Finds the Azure DNS zone of the subscription hosting hostName, returning nil when the subscription doesn't have one
+	FindDnsZone(ctx context.Context, subscriptionId string, hostName string) (*DnsZone, error)
+	// Creates or updates a DNS record in a zone
+	SetDnsRecord(ctx context.Context, zone *DnsZone, record DnsRecord) error
+	// Gets the DNS records which validate the ownership of hostName and route it to the resource
+	DomainRecords(ctx context.Context, resourceId string, hostName string) ([]DnsRecord, error)
+	// Binds hostName to the resource, securing it with a free managed certificate when managedCertificate is set.
+	// The DNS records of the domain must exist.
+	BindDomain(ctx context.Context, resourceId string, hostName string, managedCertificate bool) error
+}
+
+type customDomainService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the CustomDomainService
+func NewCustomDomainService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) CustomDomainService {
+	return &customDomainService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+func (cds *customDomainService) FindDnsZone(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+) (*DnsZone, error) {
+	client, err := cds.createArmClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	nextLink := runtime.JoinPaths(
+		client.Endpoint(), "subscriptions", subscriptionId, "providers/Microsoft.Network/dnszones") +
+		"?api-version=" + dnsApiVersion
+
+	var zone *DnsZone
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("listing DNS zones: %w", err)
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, fmt.Errorf("listing DNS zones: %w", runtime.NewResponseError(res))
+		}
+
+		var page struct {
+			Value []struct {
+				Id   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := runtime.UnmarshalAsJSON(res, &page); err != nil {
+			return nil, err
+		}
+
+		// The zone with the longest name hosting the domain is the most specific one
+		for _, candidate := range page.Value {
+			if hostedInZone(hostName, candidate.Name) && (zone == nil || len(candidate.Name) > len(zone.Name)) {
+				zone = &DnsZone{Id: candidate.Id, Name: candidate.Name}
+			}
+		}
+
+		nextLink = page.NextLink
+	}
+
+	return zone, nil
+}
+
+func (cds *customDomainService) SetDnsRecord(ctx context.Context, zone *DnsZone, record DnsRecord) error {
+	zoneId, err := arm.ParseResourceID(zone.Id)
+	if err != nil {
+		return fmt.Errorf("parsing DNS zone id '%s': %w", zone.Id, err)
+	}
+
+	relativeName, err := relativeRecordName(record.Name, zone.Name)
+	if err != nil {
+		return err
+	}
+
+	properties := map[string]any{"TTL": customDomainRecordTtl}
+	switch record.Type {
+	case DnsRecordTypeTxt:
+		properties["TXTRecords"] = []map[string]any{{"value": []string{record.Value}}}
+	case DnsRecordTypeCname:
+		if relativeName == "@" {
+			return fmt.Errorf("a CNAME record can't be created at the apex of zone '%s', use a subdomain", zone.Name)
+		}
+		properties["CNAMERecord"] = map[string]any{"cname": record.Value}
+	default:
+		return fmt.Errorf("unsupported DNS record type '%s'", record.Type)
+	}
+
+	client, err := cds.createArmClient(ctx, zoneId.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodPut,
+		runtime.JoinPaths(client.Endpoint(), zoneId.String(), string(record.Type), relativeName),
+	)
+	if err != nil {
+		return err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", dnsApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+
+	if err := runtime.MarshalAsJSON(req, map[string]any{"properties": properties}); err != nil {
+		return err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return fmt.Errorf("setting %s record '%s': %w", record.Type, record.Name, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusCreated) {
+		return fmt.Errorf("setting %s record '%s': %w", record.Type, record.Name, runtime.NewResponseError(res))
+	}
+
+	return nil
+}
+
+func (cds *customDomainService) DomainRecords(
+	ctx context.Context,
+	resourceId string,
+	hostName string,
+) ([]DnsRecord, error) {
+	id, credential, err := cds.parseResourceId(ctx, resourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeWebSite)):
+		client, err := armappservice.NewWebAppsClient(id.SubscriptionID, credential, cds.armClientOptions)
+		if err != nil {
+			return nil, fmt.Errorf("creating WebApps client: %w", err)
+		}
+
+		site, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting web app: %w", err)
+		}
+
+		if site.Properties == nil || site.Properties.DefaultHostName == nil ||
+			site.Properties.CustomDomainVerificationID == nil {
+			return nil, fmt.Errorf("web app '%s' doesn't have a default host name", id.Name)
+		}
+
+		return verifiedDomainRecords(
+			hostName, *site.Properties.CustomDomainVerificationID, *site.Properties.DefaultHostName), nil
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeContainerApp)):
+		client, err := armappcontainers.NewContainerAppsClient(id.SubscriptionID, credential, cds.armClientOptions)
+		if err != nil {
+			return nil, fmt.Errorf("creating ContainerApps client: %w", err)
+		}
+
+		app, err := client.Get(ctx, id.ResourceGroupName, id.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting container app: %w", err)
+		}
+
+		if app.Properties == nil || app.Properties.Configuration == nil || app.Properties.Configuration.Ingress == nil ||
+			app.Properties.Configuration.Ingress.Fqdn == nil || app.Properties.CustomDomainVerificationID == nil {
+			return nil, fmt.Errorf("container app '%s' doesn't have an ingress", id.Name)
+		}
+
+		return verifiedDomainRecords(
+			hostName, *app.Properties.CustomDomainVerificationID, *app.Properties.Configuration.Ingress.Fqdn), nil
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeStaticWebSite)):
+		client, err := armappservice.NewStaticSitesClient(id.SubscriptionID, credential, cds.armClientOptions)
+		if err != nil {
+			return nil, fmt.Errorf("creating StaticSites client: %w", err)
+		}
+
+		site, err := client.GetStaticSite(ctx, id.ResourceGroupName, id.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting static web app: %w", err)
+		}
+
+		if site.Properties == nil || site.Properties.DefaultHostname == nil {
+			return nil, fmt.Errorf("static web app '%s' doesn't have a default host name", id.Name)
+		}
+
+		// Static Web Apps validate the domain with the CNAME record
+		return []DnsRecord{
+			{Type: DnsRecordTypeCname, Name: hostName, Value: *site.Properties.DefaultHostname},
+		}, nil
+	default:
+		return nil, fmt.Errorf("custom domains aren't supported for resources of type '%s'", id.ResourceType.String())
+	}
+}
+
+func (cds *customDomainService) BindDomain(
+	ctx context.Context,
+	resourceId string,
+	hostName string,
+	managedCertificate bool,
+) error {
+	id, credential, err := cds.parseResourceId(ctx, resourceId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeWebSite)):
+		return cds.bindWebAppDomain(ctx, id, credential, hostName, managedCertificate)
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeContainerApp)):
+		return cds.bindContainerAppDomain(ctx, id, credential, hostName, managedCertificate)
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeStaticWebSite)):
+		return cds.bindStaticWebAppDomain(ctx, id, credential, hostName)
+	default:
+		return fmt.Errorf("custom domains aren't supported for resources of type '%s'", id.ResourceType.String())
+	}
+}
+
+// bindWebAppDomain adds a host name binding to a web app, then creates an App Service managed certificate for the host
+// name and enables SNI SSL with it.
+func (cds *customDomainService) bindWebAppDomain(
+	ctx context.Context,
+	id *arm.ResourceID,
+	credential azcore.TokenCredential,
+	hostName string,
+	managedCertificate bool,
+) error {
+	webAppsClient, err := armappservice.NewWebAppsClient(id.SubscriptionID, credential, cds.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating WebApps client: %w", err)
+	}
+
+	binding := armappservice.HostNameBinding{
+		Properties: &armappservice.HostNameBindingProperties{
+			SiteName:                    to.Ptr(id.Name),
+			HostNameType:                to.Ptr(armappservice.HostNameTypeVerified),
+			CustomHostNameDNSRecordType: to.Ptr(armappservice.CustomHostNameDNSRecordTypeCName),
+			SSLState:                    to.Ptr(armappservice.SSLStateDisabled),
+		},
+	}
+
+	_, err = webAppsClient.CreateOrUpdateHostNameBinding(ctx, id.ResourceGroupName, id.Name, hostName, binding, nil)
+	if err != nil {
+		return fmt.Errorf("binding host name '%s': %w", hostName, err)
+	}
+
+	if !managedCertificate {
+		return nil
+	}
+
+	site, err := webAppsClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return fmt.Errorf("getting web app: %w", err)
+	}
+
+	certificatesClient, err := armappservice.NewCertificatesClient(id.SubscriptionID, credential, cds.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating Certificates client: %w", err)
+	}
+
+	certificateName := managedCertificateName(hostName)
+	certificate := armappservice.AppCertificate{
+		Location: site.Location,
+		Properties: &armappservice.AppCertificateProperties{
+			CanonicalName: to.Ptr(hostName),
+			ServerFarmID:  site.Properties.ServerFarmID,
+		},
+	}
+
+	_, err = certificatesClient.CreateOrUpdate(ctx, id.ResourceGroupName, certificateName, certificate, nil)
+	if err != nil {
+		return fmt.Errorf("creating managed certificate for '%s': %w", hostName, err)
+	}
+
+	// The certificate is issued asynchronously, its thumbprint is set once it's issued
+	thumbprint, err := pollUntil(ctx, func() (*string, error) {
+		certificate, err := certificatesClient.Get(ctx, id.ResourceGroupName, certificateName, nil)
+		var responseErr *azcore.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if certificate.Properties == nil {
+			return nil, nil
+		}
+
+		return certificate.Properties.Thumbprint, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for the managed certificate of '%s': %w", hostName, err)
+	}
+
+	binding.Properties.SSLState = to.Ptr(armappservice.SSLStateSniEnabled)
+	binding.Properties.Thumbprint = thumbprint
+
+	_, err = webAppsClient.CreateOrUpdateHostNameBinding(ctx, id.ResourceGroupName, id.Name, hostName, binding, nil)
+	if err != nil {
+		return fmt.Errorf("enabling SSL for host name '%s': %w", hostName, err)
+	}
+
+	return nil
+}
+
+// bindContainerAppDomain adds a custom domain to the ingress of a container app, then creates a managed certificate in
+// the environment of the container app and binds it to the domain.
+func (cds *customDomainService) bindContainerAppDomain(
+	ctx context.Context,
+	id *arm.ResourceID,
+	credential azcore.TokenCredential,
+	hostName string,
+	managedCertificate bool,
+) error {
+	appsClient, err := armappcontainers.NewContainerAppsClient(id.SubscriptionID, credential, cds.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating ContainerApps client: %w", err)
+	}
+
+	customDomain := &armappcontainers.CustomDomain{
+		Name:        to.Ptr(hostName),
+		BindingType: to.Ptr(armappcontainers.BindingTypeDisabled),
+	}
+	if err := cds.setContainerAppCustomDomain(ctx, appsClient, id, customDomain); err != nil {
+		return err
+	}
+
+	if !managedCertificate {
+		return nil
+	}
+
+	app, err := appsClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	environmentId := app.Properties.EnvironmentID
+	if environmentId == nil {
+		environmentId = app.Properties.ManagedEnvironmentID
+	}
+	if environmentId == nil {
+		return fmt.Errorf("container app '%s' doesn't have an environment", id.Name)
+	}
+
+	envId, err := arm.ParseResourceID(*environmentId)
+	if err != nil {
+		return fmt.Errorf("parsing container apps environment id '%s': %w", *environmentId, err)
+	}
+
+	certificatesClient, err := armappcontainers.NewManagedCertificatesClient(
+		envId.SubscriptionID, credential, cds.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating ManagedCertificates client: %w", err)
+	}
+
+	poller, err := certificatesClient.BeginCreateOrUpdate(
+		ctx,
+		envId.ResourceGroupName,
+		envId.Name,
+		managedCertificateName(hostName),
+		&armappcontainers.ManagedCertificatesClientBeginCreateOrUpdateOptions{
+			ManagedCertificateEnvelope: &armappcontainers.ManagedCertificate{
+				Location: app.Location,
+				Properties: &armappcontainers.ManagedCertificateProperties{
+					SubjectName:             to.Ptr(hostName),
+					DomainControlValidation: to.Ptr(armappcontainers.ManagedCertificateDomainControlValidationCNAME),
+				},
+			},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("creating managed certificate for '%s': %w", hostName, err)
+	}
+
+	certificate, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("creating managed certificate for '%s': %w", hostName, err)
+	}
+
+	customDomain.CertificateID = certificate.ID
+	customDomain.BindingType = to.Ptr(armappcontainers.BindingTypeSniEnabled)
+
+	return cds.setContainerAppCustomDomain(ctx, appsClient, id, customDomain)
+}
+
+// setContainerAppCustomDomain adds or replaces a custom domain of the ingress of a container app.
+func (cds *customDomainService) setContainerAppCustomDomain(
+	ctx context.Context,
+	appsClient *armappcontainers.ContainerAppsClient,
+	id *arm.ResourceID,
+	customDomain *armappcontainers.CustomDomain,
+) error {
+	app, err := appsClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if app.Properties == nil || app.Properties.Configuration == nil || app.Properties.Configuration.Ingress == nil {
+		return fmt.Errorf("container app '%s' doesn't have an ingress", id.Name)
+	}
+
+	ingress := app.Properties.Configuration.Ingress
+	ingress.CustomDomains = slices.DeleteFunc(ingress.CustomDomains, func(existing *armappcontainers.CustomDomain) bool {
+		return existing.Name != nil && strings.EqualFold(*existing.Name, *customDomain.Name)
+	})
+	ingress.CustomDomains = append(ingress.CustomDomains, customDomain)
+
+	poller, err := appsClient.BeginUpdate(ctx, id.ResourceGroupName, id.Name, app.ContainerApp, nil)
+	if err != nil {
+		return fmt.Errorf("updating custom domains of container app '%s': %w", id.Name, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("updating custom domains of container app '%s': %w", id.Name, err)
+	}
+
+	return nil
+}
+
+// bindStaticWebAppDomain adds a custom domain to a static web app, which validates the domain with its CNAME record and
+// secures it with a free certificate.
+func (cds *customDomainService) bindStaticWebAppDomain(
+	ctx context.Context,
+	id *arm.ResourceID,
+	credential azcore.TokenCredential,
+	hostName string,
+) error {
+	client, err := armappservice.NewStaticSitesClient(id.SubscriptionID, credential, cds.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating StaticSites client: %w", err)
+	}
+
+	poller, err := client.BeginCreateOrUpdateStaticSiteCustomDomain(
+		ctx,
+		id.ResourceGroupName,
+		id.Name,
+		hostName,
+		armappservice.StaticSiteCustomDomainRequestPropertiesARMResource{
+			Properties: &armappservice.StaticSiteCustomDomainRequestPropertiesARMResourceProperties{
+				ValidationMethod: to.Ptr("cname-delegation"),
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("adding custom domain '%s': %w", hostName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("validating custom domain '%s': %w", hostName, err)
+	}
+
+	return nil
+}
+
+func (cds *customDomainService) parseResourceId(
+	ctx context.Context,
+	resourceId string,
+) (*arm.ResourceID, azcore.TokenCredential, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing resource id '%s': %w", resourceId, err)
+	}
+
+	credential, err := cds.credentialProvider.CredentialForSubscription(ctx, id.SubscriptionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return id, credential, nil
+}
+
+func (cds *customDomainService) createArmClient(ctx context.Context, subscriptionId string) (*arm.Client, error) {
+	credential, err := cds.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-custom-domains", "v1.0.0", credential, cds.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	return client, nil
+}
+
+// verifiedDomainRecords are the records of App Service and Container Apps domains: a TXT record with the domain
+// verification ID of the resource, and a CNAME record to its default host name.
+func verifiedDomainRecords(hostName string, verificationId string, defaultHostName string) []DnsRecord {
+	return []DnsRecord{
+		{Type: DnsRecordTypeTxt, Name: "asuid." + hostName, Value: verificationId},
+		{Type: DnsRecordTypeCname, Name: hostName, Value: defaultHostName},
+	}
+}
+
+func hostedInZone(hostName string, zoneName string) bool {
+	hostName = strings.ToLower(strings.TrimSuffix(hostName, "."))
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+
+	return hostName == zoneName || strings.HasSuffix(hostName, "."+zoneName)
+}
+
+// relativeRecordName gets the name of a record relative to its zone, '@' for the apex of the zone.
+func relativeRecordName(recordName string, zoneName string) (string, error) {
+	if !hostedInZone(recordName, zoneName) {
+		return "", fmt.Errorf("'%s' isn't part of DNS zone '%s'", recordName, zoneName)
+	}
+
+	recordName = strings.ToLower(strings.TrimSuffix(recordName, "."))
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	if recordName == zoneName {
+		return "@", nil
+	}
+
+	return strings.TrimSuffix(recordName, "."+zoneName), nil
+}
+
+var invalidCertificateNameChars = regexp.MustCompile(`[^a-z0-9-]`)
+
+// managedCertificateName gets the name of the managed certificate of a host name.
+func managedCertificateName(hostName string) string {
+	name := "mc-" + invalidCertificateNameChars.ReplaceAllString(strings.ToLower(hostName), "-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+
+	return strings.TrimSuffix(name, "-")
+}
+
+// pollUntil calls get until it returns a value, an error, or the managed certificate timeout elapses.
+func pollUntil[T any](ctx context.Context, get func() (*T, error)) (*T, error) {
+	timeout := time.After(managedCertificateTimeout)
+	for {
+		value, err := get()
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			return value, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout:
+			return nil, fmt.Errorf("timed out after %s", managedCertificateTimeout)
+		case <-time.After(managedCertificatePollInterval):
+		}
+	}
+}
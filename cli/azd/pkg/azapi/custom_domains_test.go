@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindDnsZone(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	service := NewCustomDomainService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Network/dnszones")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"id": "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Network/dnszones/contoso.com",
+					"name": "contoso.com"},
+				{"id": "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Network/dnszones/apps.contoso.com",
+					"name": "apps.contoso.com"},
+				{"id": "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Network/dnszones/fabrikam.com",
+					"name": "fabrikam.com"},
+			},
+		})
+	})
+
+	zone, err := service.FindDnsZone(*mockContext.Context, "SUB", "www.apps.contoso.com")
+	require.NoError(t, err)
+	require.NotNil(t, zone)
+	require.Equal(t, "apps.contoso.com", zone.Name)
+
+	zone, err = service.FindDnsZone(*mockContext.Context, "SUB", "www.northwind.com")
+	require.NoError(t, err)
+	require.Nil(t, zone)
+}
+
+func Test_SetDnsRecord(t *testing.T) {
+	zone := &DnsZone{
+		Id:   "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Network/dnszones/contoso.com",
+		Name: "contoso.com",
+	}
+
+	t.Run("Txt", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		service := NewCustomDomainService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		var body map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut &&
+				strings.HasSuffix(request.URL.Path, "/dnszones/contoso.com/TXT/asuid.www")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		})
+
+		err := service.SetDnsRecord(*mockContext.Context, zone, DnsRecord{
+			Type:  DnsRecordTypeTxt,
+			Name:  "asuid.www.contoso.com",
+			Value: "VERIFICATION_ID",
+		})
+		require.NoError(t, err)
+
+		properties := body["properties"].(map[string]any)
+		require.Equal(t, []any{map[string]any{"value": []any{"VERIFICATION_ID"}}}, properties["TXTRecords"])
+	})
+
+	t.Run("CnameAtApex", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		service := NewCustomDomainService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		err := service.SetDnsRecord(*mockContext.Context, zone, DnsRecord{
+			Type:  DnsRecordTypeCname,
+			Name:  "contoso.com",
+			Value: "app.azurewebsites.net",
+		})
+		require.ErrorContains(t, err, "can't be created at the apex")
+	})
+
+	t.Run("OutsideOfZone", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		service := NewCustomDomainService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		err := service.SetDnsRecord(*mockContext.Context, zone, DnsRecord{
+			Type:  DnsRecordTypeCname,
+			Name:  "www.fabrikam.com",
+			Value: "app.azurewebsites.net",
+		})
+		require.ErrorContains(t, err, "isn't part of DNS zone")
+	})
+}
+
+func Test_DomainRecords_StaticWebApp(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	service := NewCustomDomainService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/staticSites/WEB")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"location": "eastus2",
+			"properties": map[string]any{
+				"defaultHostname": "web.azurestaticapps.net",
+			},
+		})
+	})
+
+	records, err := service.DomainRecords(
+		*mockContext.Context,
+		"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Web/staticSites/WEB",
+		"www.contoso.com",
+	)
+	require.NoError(t, err)
+	require.Equal(t, []DnsRecord{
+		{Type: DnsRecordTypeCname, Name: "www.contoso.com", Value: "web.azurestaticapps.net"},
+	}, records)
+}
+
+func Test_ManagedCertificateName(t *testing.T) {
+	require.Equal(t, "mc-www-contoso-com", managedCertificateName("www.contoso.com"))
+	require.LessOrEqual(t, len(managedCertificateName(strings.Repeat("a", 80)+".contoso.com")), 60)
+}
@@ -22,7 +22,15 @@ const (
 	DeploymentTypeStacks   DeploymentType = "deployments.stacks"
 )
 
-var ErrPreviewNotSupported = errors.New("preview not supported")
+var (
+	ErrPreviewNotSupported           = errors.New("preview not supported")
+	ErrDeploymentHistoryNotSupported = errors.New("deployment history not supported")
+)
+
+// KeepResourceGroupsOptionKey is the key of the delete options listing the names of the resource groups which aren't
+// deleted with the resources of a subscription deployment, ex) the resource groups of existing resources the deployment
+// assigned roles on. The value is a []string.
+const KeepResourceGroupsOptionKey = "keepResourceGroups"
 
 const emptySubscriptionArmTemplate = `{
 	"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
@@ -70,6 +78,15 @@ type ResourceDeployment struct {
 	// The status of the deployment
 	ProvisioningState DeploymentProvisioningState
 
+	// The duration of the deployment in ISO 8601 format (ex: PT1M30S)
+	Duration string
+
+	// The correlation ID of the deployment
+	CorrelationId string
+
+	// The principal which last modified the deployment, when reported by the service
+	ModifiedBy string
+
 	PortalUrl string
 
 	OutputsUrl string
@@ -220,6 +237,22 @@ type DeploymentService interface {
 	) error
 }
 
+// DeploymentHistory is implemented by the deployment services keeping each deployment in the deployment history of its
+// scope, which ARM limits to 800 deployments per resource group or subscription.
+type DeploymentHistory interface {
+	// DeleteSubscriptionDeploymentRecord removes the deployment from the deployment history of the subscription. The
+	// resources of the deployment aren't deleted.
+	DeleteSubscriptionDeploymentRecord(ctx context.Context, subscriptionId string, deploymentName string) error
+	// DeleteResourceGroupDeploymentRecord removes the deployment from the deployment history of the resource group. The
+	// resources of the deployment aren't deleted.
+	DeleteResourceGroupDeploymentRecord(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		deploymentName string,
+	) error
+}
+
 type DeleteResourceState string
 
 const (
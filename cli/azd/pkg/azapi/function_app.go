@@ -42,6 +42,7 @@ func (cli *AzureClient) DeployFunctionAppUsingZipFile(
 	appName string,
 	deployZipFile io.ReadSeeker,
 	remoteBuild bool,
+	options *ZipDeployOptions,
 ) (*string, error) {
 	app, err := cli.appService(ctx, subscriptionId, resourceGroup, appName)
 	if err != nil {
@@ -91,6 +92,13 @@ func (cli *AzureClient) DeployFunctionAppUsingZipFile(
 		return to.Ptr(response.StatusText), nil
 	}
 
+	if isLinuxFunctionApp(app) && (options == nil || !options.DisableOneDeploy) {
+		status, err := cli.oneDeploy(ctx, subscriptionId, hostName, deployZipFile, remoteBuild, nil)
+		if err != nil || status != nil {
+			return status, err
+		}
+	}
+
 	client, err := cli.createZipDeployClient(ctx, subscriptionId, hostName)
 	if err != nil {
 		return nil, err
@@ -103,3 +111,8 @@ func (cli *AzureClient) DeployFunctionAppUsingZipFile(
 
 	return to.Ptr(response.StatusText), nil
 }
+
+// isLinuxFunctionApp returns true when the function app runs on Linux, ex) its kind is functionapp,linux
+func isLinuxFunctionApp(response *armappservice.WebAppsClientGetResponse) bool {
+	return response.Kind != nil && strings.Contains(strings.ToLower(*response.Kind), "linux")
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+)
+
+type AzCliLogicAppProperties struct {
+	HostNames []string
+	// The kind of the site, ex) functionapp,workflowapp
+	Kind string
+}
+
+// IsWorkflowApp returns whether the site is a Logic App Standard, which kind includes 'workflowapp'.
+func (p *AzCliLogicAppProperties) IsWorkflowApp() bool {
+	return slices.Contains(strings.Split(strings.ToLower(p.Kind), ","), "workflowapp")
+}
+
+// LogicAppTriggerUrl is the callback URL of a request trigger of a workflow of a Logic App Standard.
+type LogicAppTriggerUrl struct {
+	Workflow string
+	Trigger  string
+	Url      string
+}
+
+func (cli *AzureClient) GetLogicAppProperties(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (*AzCliLogicAppProperties, error) {
+	webApp, err := cli.appService(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := &AzCliLogicAppProperties{
+		HostNames: []string{*webApp.Properties.DefaultHostName},
+	}
+	if webApp.Kind != nil {
+		properties.Kind = *webApp.Kind
+	}
+
+	return properties, nil
+}
+
+// ListLogicAppTriggerUrls returns the callback URLs of the triggers of the workflows of a Logic App Standard which are
+// called over HTTP, sorted by workflow and trigger. The other triggers, ex) recurrences, don't have a callback URL.
+func (cli *AzureClient) ListLogicAppTriggerUrls(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) ([]LogicAppTriggerUrl, error) {
+	webAppsClient, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	triggersClient, err := armappservice.NewWorkflowTriggersClient(subscriptionId, credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating workflow triggers client: %w", err)
+	}
+
+	workflows := []string{}
+	pager := webAppsClient.NewListWorkflowsPager(resourceGroup, appName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing workflows of logic app '%s': %w", appName, err)
+		}
+
+		for _, workflow := range page.Value {
+			if workflow.Name != nil {
+				// The name of a workflow is prefixed by the name of the app, ex) app/workflow
+				workflows = append(workflows, path.Base(*workflow.Name))
+			}
+		}
+	}
+	slices.Sort(workflows)
+
+	urls := []LogicAppTriggerUrl{}
+	for _, workflow := range workflows {
+		triggers := []string{}
+		pager := triggersClient.NewListPager(resourceGroup, appName, workflow, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("listing triggers of workflow '%s': %w", workflow, err)
+			}
+
+			for _, trigger := range page.Value {
+				if trigger.Name != nil {
+					triggers = append(triggers, path.Base(*trigger.Name))
+				}
+			}
+		}
+		slices.Sort(triggers)
+
+		for _, trigger := range triggers {
+			callbackUrl, err := triggersClient.ListCallbackURL(ctx, resourceGroup, appName, workflow, trigger, nil)
+			if err != nil {
+				log.Printf("getting callback url of trigger '%s' of workflow '%s': %v", trigger, workflow, err)
+				continue
+			}
+
+			if callbackUrl.Value != nil {
+				urls = append(urls, LogicAppTriggerUrl{Workflow: workflow, Trigger: trigger, Url: *callbackUrl.Value})
+			}
+		}
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListLogicAppTriggerUrls(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzureClientFromMockContext(mockContext)
+	const workflowsPath = "/providers/Microsoft.Web/sites/LOGIC_APP/hostruntime/runtime/webhooks/workflow/api/management" +
+		"/workflows/"
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/sites/LOGIC_APP/workflows")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WorkflowEnvelopeCollection{
+			Value: []*armappservice.WorkflowEnvelope{
+				{Name: to.Ptr("LOGIC_APP/shipping")},
+				{Name: to.Ptr("LOGIC_APP/orders")},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/triggers")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		trigger := "manual"
+		if strings.Contains(request.URL.Path, workflowsPath+"shipping/") {
+			trigger = "recurrence"
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WorkflowTriggerListResult{
+			Value: []*armappservice.WorkflowTrigger{{Name: to.Ptr(trigger)}},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/listCallbackUrl")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		// Only request triggers have a callback URL
+		if strings.Contains(request.URL.Path, "/triggers/recurrence/") {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusBadRequest)
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WorkflowTriggerCallbackURL{
+			Value: to.Ptr("https://LOGIC_APP.azurewebsites.net:443/api/orders/triggers/manual/invoke?sig=SIG"),
+		})
+	})
+
+	urls, err := azCli.ListLogicAppTriggerUrls(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "LOGIC_APP")
+	require.NoError(t, err)
+	require.Equal(t, []LogicAppTriggerUrl{
+		{
+			Workflow: "orders",
+			Trigger:  "manual",
+			Url:      "https://LOGIC_APP.azurewebsites.net:443/api/orders/triggers/manual/invoke?sig=SIG",
+		},
+	}, urls)
+}
+
+func Test_AzCliLogicAppProperties_IsWorkflowApp(t *testing.T) {
+	require.True(t, (&AzCliLogicAppProperties{Kind: "functionapp,workflowapp"}).IsWorkflowApp())
+	require.True(t, (&AzCliLogicAppProperties{Kind: "functionapp,linux,container,workflowApp"}).IsWorkflowApp())
+	require.False(t, (&AzCliLogicAppProperties{Kind: "functionapp"}).IsWorkflowApp())
+}
@@ -6,10 +6,15 @@ package azapi
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 )
 
 // ManagedClustersService provides actions on top of Azure Kubernetes Service (AKS) Managed Clusters
@@ -28,6 +33,54 @@ type ManagedClustersService interface {
 		resourceGroupName string,
 		resourceName string,
 	) (*armcontainerservice.CredentialResults, error)
+	// Creates or updates a Flux configuration which syncs manifests from a Git repository to the managed cluster.
+	// Requires the Flux extension to be installed on the cluster.
+	CreateOrUpdateFluxConfiguration(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		resourceName string,
+		config *FluxConfiguration,
+	) error
+	// Lists the member clusters of an Azure Kubernetes Fleet Manager
+	ListFleetMembers(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		fleetName string,
+	) ([]*FleetMember, error)
+}
+
+// The API version of Microsoft.KubernetesConfiguration/fluxConfigurations
+const fluxConfigurationsApiVersion = "2023-05-01"
+
+// FluxConfiguration is a Flux configuration that syncs the manifests at a path of a Git repository to a managed cluster
+type FluxConfiguration struct {
+	// The name of the Flux configuration
+	Name string
+	// The namespace the Flux configuration is created in. Defaults to the namespace chosen by Azure when empty.
+	Namespace string
+	// The URL of the Git repository
+	RepositoryUrl string
+	// The branch of the Git repository
+	Branch string
+	// The path of the manifests in the Git repository
+	Path string
+	// How often the repository is synced. Defaults to the Flux default when zero
+	SyncInterval time.Duration
+}
+
+// The API version of Microsoft.ContainerService/fleets
+const fleetsApiVersion = "2024-04-01"
+
+// FleetMember is a member cluster of an Azure Kubernetes Fleet Manager
+type FleetMember struct {
+	// The name of the member
+	Name string
+	// The resource id of the AKS cluster joined to the fleet
+	ClusterResourceId string
+	// The update group of the member, used to order the rollout of updates across the fleet
+	Group string
 }
 
 type managedClustersService struct {
@@ -86,6 +139,153 @@ func (cs *managedClustersService) GetUserCredentials(
 	return &credResult.CredentialResults, nil
 }
 
+// Creates or updates a Flux configuration on the managed cluster
+func (cs *managedClustersService) CreateOrUpdateFluxConfiguration(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceName string,
+	config *FluxConfiguration,
+) error {
+	credential, err := cs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	client, err := armresources.NewClient(subscriptionId, credential, cs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating resources client, %w", err)
+	}
+
+	resourceId := fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s"+
+			"/providers/Microsoft.KubernetesConfiguration/fluxConfigurations/%s",
+		subscriptionId,
+		resourceGroupName,
+		resourceName,
+		config.Name,
+	)
+
+	gitRepository := map[string]any{
+		"url": config.RepositoryUrl,
+		"repositoryRef": map[string]any{
+			"branch": config.Branch,
+		},
+	}
+	kustomization := map[string]any{
+		"path":  config.Path,
+		"prune": true,
+	}
+
+	// Flux uses its default interval when none is set
+	if config.SyncInterval > 0 {
+		syncIntervalInSeconds := int(config.SyncInterval.Seconds())
+		gitRepository["syncIntervalInSeconds"] = syncIntervalInSeconds
+		kustomization["syncIntervalInSeconds"] = syncIntervalInSeconds
+	}
+
+	properties := map[string]any{
+		"scope":          "cluster",
+		"sourceKind":     "GitRepository",
+		"gitRepository":  gitRepository,
+		"kustomizations": map[string]any{config.Name: kustomization},
+	}
+
+	if config.Namespace != "" {
+		properties["namespace"] = config.Namespace
+	}
+
+	poller, err := client.BeginCreateOrUpdateByID(
+		ctx,
+		resourceId,
+		fluxConfigurationsApiVersion,
+		armresources.GenericResource{
+			Properties: properties,
+		},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("creating flux configuration '%s', %w", config.Name, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("creating flux configuration '%s', %w", config.Name, err)
+	}
+
+	return nil
+}
+
+// Lists the member clusters of an Azure Kubernetes Fleet Manager
+func (cs *managedClustersService) ListFleetMembers(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	fleetName string,
+) ([]*FleetMember, error) {
+	credential, err := cs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Fleet SDK isn't a dependency of azd, the REST API is called directly
+	client, err := arm.NewClient("azd-fleets", "v1.0.0", credential, cs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client, %w", err)
+	}
+
+	nextLink := runtime.JoinPaths(
+		client.Endpoint(),
+		azure.ResourceGroupRID(subscriptionId, resourceGroupName),
+		"providers/Microsoft.ContainerService/fleets",
+		fleetName,
+		"members",
+	)
+	nextLink += "?api-version=" + fleetsApiVersion
+
+	members := []*FleetMember{}
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("listing members of fleet '%s', %w", fleetName, err)
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, fmt.Errorf("listing members of fleet '%s', %w", fleetName, runtime.NewResponseError(res))
+		}
+
+		var page struct {
+			Value []struct {
+				Name       string `json:"name"`
+				Properties struct {
+					ClusterResourceId string `json:"clusterResourceId"`
+					Group             string `json:"group"`
+				} `json:"properties"`
+			} `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := runtime.UnmarshalAsJSON(res, &page); err != nil {
+			return nil, err
+		}
+
+		for _, member := range page.Value {
+			members = append(members, &FleetMember{
+				Name:              member.Name,
+				ClusterResourceId: member.Properties.ClusterResourceId,
+				Group:             member.Properties.Group,
+			})
+		}
+
+		nextLink = page.NextLink
+	}
+
+	return members, nil
+}
+
 func (cs *managedClustersService) createManagedClusterClient(
 	ctx context.Context,
 	subscriptionId string,
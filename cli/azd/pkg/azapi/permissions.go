@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// The API version of the Microsoft.Authorization/permissions API
+const permissionsApiVersion = "2022-04-01"
+
+// ListSubscriptionPermissions lists the permissions of the current principal on the subscription, one for each of the
+// role assignments of the principal.
+func (rs *ResourceService) ListSubscriptionPermissions(
+	ctx context.Context,
+	subscriptionId string,
+) ([]*armauthorization.Permission, error) {
+	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-permissions", "v1.0.0", credential, rs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	permissions := []*armauthorization.Permission{}
+	nextLink := runtime.JoinPaths(
+		client.Endpoint(),
+		fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/permissions", subscriptionId),
+	) + "?api-version=" + permissionsApiVersion
+
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		response, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		result, err := httputil.ReadRawResponse[armauthorization.PermissionGetResult](response)
+		if err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, result.Value...)
+		nextLink = ""
+		if result.NextLink != nil {
+			nextLink = *result.NextLink
+		}
+	}
+
+	return permissions, nil
+}
+
+// ListPrincipalRoleAssignments lists the role assignments of the principal at, above or below the scope, ex) the
+// resource id of a container registry.
+func (rs *ResourceService) ListPrincipalRoleAssignments(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	principalId string,
+) ([]*armauthorization.RoleAssignment, error) {
+	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := armauthorization.NewRoleAssignmentsClient(subscriptionId, credential, rs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM Role Assignments client: %w", err)
+	}
+
+	roleAssignments := []*armauthorization.RoleAssignment{}
+	pager := client.NewListForScopePager(scope, &armauthorization.RoleAssignmentsClientListForScopeOptions{
+		Filter: to.Ptr(fmt.Sprintf("principalId eq '%s'", principalId)),
+	})
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing role assignments: %w", err)
+		}
+
+		roleAssignments = append(roleAssignments, page.Value...)
+	}
+
+	return roleAssignments, nil
+}
+
+// PermissionsAllow returns true when one of the permissions allows the action, ex)
+// Microsoft.Resources/deployments/write. Wildcards in the actions of the permissions are supported.
+func PermissionsAllow(permissions []*armauthorization.Permission, action string) bool {
+	for _, permission := range permissions {
+		if permission == nil {
+			continue
+		}
+
+		if matchesAnyAction(permission.Actions, action) && !matchesAnyAction(permission.NotActions, action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAnyAction(patterns []*string, action string) bool {
+	for _, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+
+		expression := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(*pattern), `\*`, ".*") + "$"
+		if matched, err := regexp.MatchString(expression, action); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
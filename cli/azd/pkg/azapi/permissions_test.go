@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListSubscriptionPermissions(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path, "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/permissions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if request.URL.Query().Get("page") == "2" {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{
+					{"actions": []string{"*/read"}},
+				},
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"actions": []string{"*"}, "notActions": []string{"Microsoft.Authorization/*/Write"}},
+			},
+			"nextLink": "https://management.azure.com/subscriptions/SUBSCRIPTION_ID/providers/" +
+				"Microsoft.Authorization/permissions?api-version=2022-04-01&page=2",
+		})
+	})
+
+	resourceService := NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	permissions, err := resourceService.ListSubscriptionPermissions(*mockContext.Context, "SUBSCRIPTION_ID")
+	require.NoError(t, err)
+	require.Len(t, permissions, 2)
+
+	require.True(t, PermissionsAllow(permissions, "Microsoft.Resources/deployments/write"))
+	require.True(t, PermissionsAllow(permissions, "Microsoft.Authorization/roleAssignments/read"))
+	require.False(t, PermissionsAllow(permissions, "Microsoft.Authorization/roleAssignments/write"))
+}
+
+func Test_PermissionsAllow(t *testing.T) {
+	permissions := []*armauthorization.Permission{
+		{Actions: to.SliceOfPtrs("Microsoft.Resources/*", "Microsoft.Web/sites/write")},
+	}
+
+	require.True(t, PermissionsAllow(permissions, "Microsoft.Resources/deployments/write"))
+	require.True(t, PermissionsAllow(permissions, "microsoft.web/sites/WRITE"))
+	require.False(t, PermissionsAllow(permissions, "Microsoft.Web/sites/delete"))
+	require.False(t, PermissionsAllow(nil, "Microsoft.Resources/deployments/write"))
+}
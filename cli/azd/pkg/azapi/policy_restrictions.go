@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The Policy Insights SDK isn't a dependency of azd, the REST API is called directly
+const policyInsightsApiVersion = "2022-03-01"
+
+// PolicyEvaluation is the result of the evaluation of a policy assigned to the scope of a resource, against the content
+// of the resource.
+type PolicyEvaluation struct {
+	PolicyDefinitionId          string
+	PolicySetDefinitionId       string
+	PolicyDefinitionReferenceId string
+	PolicyAssignmentId          string
+	// The result of the evaluation, ex) NonCompliant
+	EvaluationResult string
+	// The effect of the policy, ex) Deny
+	Effect string
+}
+
+type policyEvaluationResult struct {
+	PolicyInfo struct {
+		PolicyDefinitionId          string `json:"policyDefinitionId"`
+		PolicySetDefinitionId       string `json:"policySetDefinitionId"`
+		PolicyDefinitionReferenceId string `json:"policyDefinitionReferenceId"`
+		PolicyAssignmentId          string `json:"policyAssignmentId"`
+	} `json:"policyInfo"`
+	EvaluationResult string `json:"evaluationResult"`
+	EffectDetails    *struct {
+		PolicyEffect string `json:"policyEffect"`
+	} `json:"effectDetails"`
+}
+
+// CheckPolicyRestrictions evaluates the policies assigned to the resource group, or to the subscription when the
+// resource group name is empty, against the content of a resource before it is created or updated.
+func (cli *AzureClient) CheckPolicyRestrictions(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceContent map[string]any,
+	apiVersion string,
+) ([]PolicyEvaluation, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-policyinsights", "v1.0.0", credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	scope := azure.SubscriptionRID(subscriptionId)
+	if resourceGroupName != "" {
+		scope = azure.ResourceGroupRID(subscriptionId, resourceGroupName)
+	}
+
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodPost,
+		runtime.JoinPaths(client.Endpoint(), scope, "providers/Microsoft.PolicyInsights/checkPolicyRestrictions"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", policyInsightsApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+
+	resourceDetails := map[string]any{
+		"resourceContent": resourceContent,
+	}
+	if apiVersion != "" {
+		resourceDetails["apiVersion"] = apiVersion
+	}
+
+	if err := runtime.MarshalAsJSON(req, map[string]any{"resourceDetails": resourceDetails}); err != nil {
+		return nil, err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("checking policy restrictions: %w", err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, fmt.Errorf("checking policy restrictions: %w", runtime.NewResponseError(res))
+	}
+
+	var result struct {
+		ContentEvaluationResult struct {
+			PolicyEvaluations []policyEvaluationResult `json:"policyEvaluations"`
+		} `json:"contentEvaluationResult"`
+	}
+	if err := runtime.UnmarshalAsJSON(res, &result); err != nil {
+		return nil, err
+	}
+
+	evaluations := []PolicyEvaluation{}
+	for _, evaluation := range result.ContentEvaluationResult.PolicyEvaluations {
+		policyEvaluation := PolicyEvaluation{
+			PolicyDefinitionId:          evaluation.PolicyInfo.PolicyDefinitionId,
+			PolicySetDefinitionId:       evaluation.PolicyInfo.PolicySetDefinitionId,
+			PolicyDefinitionReferenceId: evaluation.PolicyInfo.PolicyDefinitionReferenceId,
+			PolicyAssignmentId:          evaluation.PolicyInfo.PolicyAssignmentId,
+			EvaluationResult:            evaluation.EvaluationResult,
+		}
+		if evaluation.EffectDetails != nil {
+			policyEvaluation.Effect = evaluation.EffectDetails.PolicyEffect
+		}
+
+		evaluations = append(evaluations, policyEvaluation)
+	}
+
+	return evaluations, nil
+}
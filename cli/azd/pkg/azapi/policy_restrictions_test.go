@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckPolicyRestrictions(t *testing.T) {
+	t.Run("ResourceGroup", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		var body map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(
+				request.URL.Path,
+				"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP"+
+					"/providers/Microsoft.PolicyInsights/checkPolicyRestrictions")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"fieldRestrictions": []any{},
+				"contentEvaluationResult": map[string]any{
+					"policyEvaluations": []map[string]any{
+						{
+							"policyInfo": map[string]any{
+								"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/allowed-locations",
+								"policyAssignmentId": "/subscriptions/SUBSCRIPTION_ID/providers/" +
+									"Microsoft.Authorization/policyAssignments/locations",
+							},
+							"evaluationResult": "NonCompliant",
+							"effectDetails":    map[string]any{"policyEffect": "Deny"},
+						},
+					},
+				},
+			})
+		})
+
+		evaluations, err := azCli.CheckPolicyRestrictions(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			map[string]any{"type": "Microsoft.Storage/storageAccounts", "location": "westus"},
+			"2023-01-01",
+		)
+		require.NoError(t, err)
+		require.Equal(t, []PolicyEvaluation{
+			{
+				PolicyDefinitionId: "/providers/Microsoft.Authorization/policyDefinitions/allowed-locations",
+				PolicyAssignmentId: "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/policyAssignments/locations",
+				EvaluationResult:   "NonCompliant",
+				Effect:             "Deny",
+			},
+		}, evaluations)
+
+		require.Equal(t, map[string]any{
+			"resourceDetails": map[string]any{
+				"resourceContent": map[string]any{"type": "Microsoft.Storage/storageAccounts", "location": "westus"},
+				"apiVersion":      "2023-01-01",
+			},
+		}, body)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(
+				request.URL.Path, "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.PolicyInsights/checkPolicyRestrictions")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		_, err := azCli.CheckPolicyRestrictions(*mockContext.Context, "SUBSCRIPTION_ID", "", map[string]any{}, "")
+		require.ErrorContains(t, err, "checking policy restrictions")
+	})
+}
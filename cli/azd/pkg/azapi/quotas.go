@@ -0,0 +1,155 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The Quota SDK isn't a dependency of azd, the REST API is called directly
+const quotaApiVersion = "2023-02-01"
+
+// QuotaUsage is the limit and the current usage of a quota of a resource provider in a location, ex) the vCPUs of a
+// VM family of Microsoft.Compute.
+type QuotaUsage struct {
+	// The name of the quota, ex) standardNCADSH100v5Family
+	Name string
+	// The display name of the quota, ex) Standard NCADS_H100_v5 Family vCPUs
+	DisplayName string
+	Limit       float64
+	Usage       float64
+	// The unit of the limit and the usage, ex) Count
+	Unit string
+}
+
+// Available returns the capacity left before the limit of the quota is reached
+func (q QuotaUsage) Available() float64 {
+	return q.Limit - q.Usage
+}
+
+type quotaName struct {
+	Value          string `json:"value"`
+	LocalizedValue string `json:"localizedValue"`
+}
+
+type quotaLimitResource struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Limit *struct {
+			Value float64 `json:"value"`
+		} `json:"limit"`
+		Name quotaName `json:"name"`
+		Unit string    `json:"unit"`
+	} `json:"properties"`
+}
+
+type quotaUsageResource struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Usages *struct {
+			Value float64 `json:"value"`
+		} `json:"usages"`
+	} `json:"properties"`
+}
+
+// ListQuotaUsages lists the quotas of the resource provider in the location, ex) Microsoft.Compute in eastus, with their
+// limits and current usages.
+func (cli *AzureClient) ListQuotaUsages(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+	resourceProvider string,
+) ([]QuotaUsage, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-quota", "v1.0.0", credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	scope := runtime.JoinPaths(
+		client.Endpoint(),
+		azure.SubscriptionRID(subscriptionId),
+		"providers", resourceProvider,
+		"locations", location,
+		"providers/Microsoft.Quota",
+	)
+
+	limits, err := listQuotaPages[quotaLimitResource](ctx, client, scope+"/quotas?api-version="+quotaApiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("listing quotas of %s in %s: %w", resourceProvider, location, err)
+	}
+
+	usages, err := listQuotaPages[quotaUsageResource](ctx, client, scope+"/usages?api-version="+quotaApiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("listing usages of %s in %s: %w", resourceProvider, location, err)
+	}
+
+	usageByName := map[string]float64{}
+	for _, usage := range usages {
+		if usage.Properties.Usages != nil {
+			usageByName[usage.Name] = usage.Properties.Usages.Value
+		}
+	}
+
+	quotas := []QuotaUsage{}
+	for _, limit := range limits {
+		// Quotas without a limit value, ex) shared limits, can't be checked
+		if limit.Properties.Limit == nil {
+			continue
+		}
+
+		quotas = append(quotas, QuotaUsage{
+			Name:        limit.Name,
+			DisplayName: limit.Properties.Name.LocalizedValue,
+			Limit:       limit.Properties.Limit.Value,
+			Usage:       usageByName[limit.Name],
+			Unit:        limit.Properties.Unit,
+		})
+	}
+
+	return quotas, nil
+}
+
+// listQuotaPages returns the resources of all the pages of a list of the Quota API
+func listQuotaPages[T any](ctx context.Context, client *arm.Client, nextLink string) ([]T, error) {
+	resources := []T{}
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, runtime.NewResponseError(res)
+		}
+
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := runtime.UnmarshalAsJSON(res, &page); err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, page.Value...)
+		nextLink = page.NextLink
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListQuotaUsages(t *testing.T) {
+	const scope = "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Compute/locations/eastus2/providers/Microsoft.Quota"
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, scope+"/quotas")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{
+					{
+						"name": "standardNCADSH100v5Family",
+						"properties": map[string]any{
+							"limit": map[string]any{"limitObjectType": "LimitValue", "value": 80},
+							"name": map[string]any{
+								"value":          "standardNCADSH100v5Family",
+								"localizedValue": "Standard NCADS_H100_v5 Family vCPUs",
+							},
+							"unit": "Count",
+						},
+					},
+					{
+						"name": "cores",
+						"properties": map[string]any{
+							"limit": map[string]any{"limitObjectType": "LimitValue", "value": 100},
+							"name":  map[string]any{"value": "cores", "localizedValue": "Total Regional vCPUs"},
+							"unit":  "Count",
+						},
+					},
+					{
+						// Without limit value, ignored
+						"name":       "sharedLimit",
+						"properties": map[string]any{"unit": "Count"},
+					},
+				},
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, scope+"/usages")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{
+					{
+						"name":       "standardNCADSH100v5Family",
+						"properties": map[string]any{"usages": map[string]any{"value": 40}},
+					},
+				},
+			})
+		})
+
+		quotas, err := azCli.ListQuotaUsages(*mockContext.Context, "SUBSCRIPTION_ID", "eastus2", "Microsoft.Compute")
+		require.NoError(t, err)
+		require.Equal(t, []QuotaUsage{
+			{
+				Name:        "standardNCADSH100v5Family",
+				DisplayName: "Standard NCADS_H100_v5 Family vCPUs",
+				Limit:       80,
+				Usage:       40,
+				Unit:        "Count",
+			},
+			{Name: "cores", DisplayName: "Total Regional vCPUs", Limit: 100, Unit: "Count"},
+		}, quotas)
+		require.Equal(t, float64(40), quotas[0].Available())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/quotas")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		_, err := azCli.ListQuotaUsages(*mockContext.Context, "SUBSCRIPTION_ID", "eastus2", "Microsoft.Compute")
+		require.ErrorContains(t, err, "listing quotas of Microsoft.Compute in eastus2")
+	})
+}
@@ -0,0 +1,356 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+)
+
+// The API version of Microsoft.SignalRService
+const signalRServiceApiVersion = "2024-03-01"
+
+// RealtimeEventHandler is the endpoint of a backend receiving the events of a Web PubSub hub, or the upstream of a
+// SignalR Service.
+type RealtimeEventHandler struct {
+	// The Web PubSub hub, or the pattern of the SignalR hubs, ex) chat. Defaults to all the hubs of SignalR
+	Hub string
+	// The url of the backend, ex) https://app.azurewebsites.net/eventhandler
+	UrlTemplate string
+	// The pattern of the user events sent to the backend, ex) message. Defaults to all the user events
+	UserEventPattern string
+	// The system events of Web PubSub sent to the backend, ex) connect, connected, disconnected
+	SystemEvents []string
+	// The audience of the token of the managed identity of the Web PubSub or SignalR resource, sent to the backend.
+	// Empty for the default audience
+	IdentityResource string
+}
+
+// RealtimeService configures the backends of Azure Web PubSub and Azure SignalR Service resources
+type RealtimeService interface {
+	// Points the event handler of a Web PubSub hub (Microsoft.SignalRService/webPubSub), or the upstream of a SignalR
+	// Service (Microsoft.SignalRService/signalR), at the url of the handler, authenticated with the managed identity of
+	// the resource. The handler with the same url host and path and the same hub and event patterns is replaced, and
+	// the others are kept. Returns whether the resource was updated.
+	ConfigureEventHandler(ctx context.Context, resourceId string, handler RealtimeEventHandler) (bool, error)
+}
+
+type realtimeService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	armClientOptions   *arm.ClientOptions
+}
+
+// Creates a new instance of the RealtimeService
+func NewRealtimeService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	armClientOptions *arm.ClientOptions,
+) RealtimeService {
+	return &realtimeService{
+		credentialProvider: credentialProvider,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+func (rs *realtimeService) ConfigureEventHandler(
+	ctx context.Context,
+	resourceId string,
+	handler RealtimeEventHandler,
+) (bool, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return false, fmt.Errorf("parsing resource id '%s': %w", resourceId, err)
+	}
+
+	if _, err := url.Parse(handler.UrlTemplate); err != nil {
+		return false, fmt.Errorf("parsing event handler url '%s': %w", handler.UrlTemplate, err)
+	}
+
+	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, id.SubscriptionID)
+	if err != nil {
+		return false, err
+	}
+
+	client, err := arm.NewClient("azd-realtime", "v1.0.0", credential, rs.armClientOptions)
+	if err != nil {
+		return false, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	switch {
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeWebPubSub)):
+		return rs.configureWebPubSubHub(ctx, client, id, handler)
+	case strings.EqualFold(id.ResourceType.String(), string(AzureResourceTypeSignalR)):
+		return rs.configureSignalRUpstream(ctx, client, id, handler)
+	default:
+		return false, fmt.Errorf("'%s' is not a Web PubSub or SignalR Service resource", resourceId)
+	}
+}
+
+// configureWebPubSubHub sets the event handler of a Web PubSub hub, creating the hub when it doesn't exist
+func (rs *realtimeService) configureWebPubSubHub(
+	ctx context.Context,
+	client *arm.Client,
+	id *arm.ResourceID,
+	handler RealtimeEventHandler,
+) (bool, error) {
+	if handler.Hub == "" {
+		return false, fmt.Errorf("the hub of the event handler of Web PubSub '%s' must be set", id.Name)
+	}
+
+	hubPath := runtime.JoinPaths(client.Endpoint(), id.String(), "hubs", url.PathEscape(handler.Hub))
+
+	hub := map[string]any{}
+	found, err := rs.send(ctx, client, http.MethodGet, hubPath, nil, &hub)
+	if err != nil {
+		return false, fmt.Errorf("getting hub '%s' of Web PubSub '%s': %w", handler.Hub, id.Name, err)
+	}
+
+	properties, _ := hub["properties"].(map[string]any)
+	if !found || properties == nil {
+		properties = map[string]any{}
+	}
+
+	systemEvents := handler.SystemEvents
+	if systemEvents == nil {
+		systemEvents = []string{}
+	}
+
+	eventHandler := map[string]any{
+		"urlTemplate":      handler.UrlTemplate,
+		"userEventPattern": defaultPattern(handler.UserEventPattern),
+		"systemEvents":     systemEvents,
+		"auth": map[string]any{
+			"type": "ManagedIdentity",
+			"managedIdentity": map[string]any{
+				"resource": handler.IdentityResource,
+			},
+		},
+	}
+
+	eventHandlers, changed := mergeRealtimeHandlers(properties["eventHandlers"], eventHandler)
+	if !changed {
+		return false, nil
+	}
+
+	properties["eventHandlers"] = eventHandlers
+	if _, err := rs.send(
+		ctx, client, http.MethodPut, hubPath, map[string]any{"properties": properties}, nil); err != nil {
+		return false, fmt.Errorf("updating hub '%s' of Web PubSub '%s': %w", handler.Hub, id.Name, err)
+	}
+
+	return true, nil
+}
+
+// configureSignalRUpstream sets the upstream template of a SignalR Service
+func (rs *realtimeService) configureSignalRUpstream(
+	ctx context.Context,
+	client *arm.Client,
+	id *arm.ResourceID,
+	handler RealtimeEventHandler,
+) (bool, error) {
+	resourcePath := runtime.JoinPaths(client.Endpoint(), id.String())
+
+	resource := map[string]any{}
+	found, err := rs.send(ctx, client, http.MethodGet, resourcePath, nil, &resource)
+	if err != nil {
+		return false, fmt.Errorf("getting SignalR Service '%s': %w", id.Name, err)
+	}
+
+	if !found {
+		return false, fmt.Errorf("SignalR Service '%s' was not found", id.Name)
+	}
+
+	properties, _ := resource["properties"].(map[string]any)
+	upstream, _ := properties["upstream"].(map[string]any)
+	if upstream == nil {
+		upstream = map[string]any{}
+	}
+
+	template := map[string]any{
+		"hubPattern":      defaultPattern(handler.Hub),
+		"eventPattern":    defaultPattern(handler.UserEventPattern),
+		"categoryPattern": "*",
+		"urlTemplate":     handler.UrlTemplate,
+		"auth": map[string]any{
+			"type": "ManagedIdentity",
+			"managedIdentity": map[string]any{
+				"resource": handler.IdentityResource,
+			},
+		},
+	}
+
+	templates, changed := mergeRealtimeHandlers(upstream["templates"], template)
+	if !changed {
+		return false, nil
+	}
+
+	upstream["templates"] = templates
+	patch := map[string]any{
+		"properties": map[string]any{
+			"upstream": upstream,
+		},
+	}
+
+	if _, err := rs.send(ctx, client, http.MethodPatch, resourcePath, patch, nil); err != nil {
+		return false, fmt.Errorf("updating the upstream of SignalR Service '%s': %w", id.Name, err)
+	}
+
+	return true, nil
+}
+
+// send sends a request to ARM, waiting for long running operations to complete. Returns false when the resource
+// wasn't found.
+func (rs *realtimeService) send(
+	ctx context.Context,
+	client *arm.Client,
+	method string,
+	path string,
+	body any,
+	result any,
+) (bool, error) {
+	req, err := runtime.NewRequest(ctx, method, path)
+	if err != nil {
+		return false, err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", signalRServiceApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return false, err
+		}
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	if method == http.MethodGet && res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
+		return false, runtime.NewResponseError(res)
+	}
+
+	if method == http.MethodGet {
+		return true, runtime.UnmarshalAsJSON(res, result)
+	}
+
+	poller, err := runtime.NewPoller[any](res, client.Pipeline(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// mergeRealtimeHandlers replaces the handlers with the url host and path and the patterns of the handler by the handler,
+// keeping the other handlers, ex) the handlers of other apps or events. Returns the handlers, and whether they changed.
+func mergeRealtimeHandlers(existing any, handler map[string]any) ([]any, bool) {
+	// Normalize the handler as it's returned by ARM, so it can be compared with the existing ones
+	raw, _ := json.Marshal(handler)
+	var normalized any
+	_ = json.Unmarshal(raw, &normalized)
+
+	handlerKey := realtimeHandlerKey(handler)
+
+	handlers := []any{}
+	found := false
+	changed := false
+	items, _ := existing.([]any)
+	for _, item := range items {
+		itemMap, _ := item.(map[string]any)
+		if realtimeHandlerKey(itemMap) != handlerKey {
+			handlers = append(handlers, item)
+			continue
+		}
+
+		if found || !realtimeHandlerEqual(itemMap, normalized) {
+			changed = true
+		}
+
+		if !found {
+			handlers = append(handlers, normalized)
+			found = true
+		}
+	}
+
+	if !found {
+		handlers = append(handlers, normalized)
+		changed = true
+	}
+
+	return handlers, changed
+}
+
+// realtimeHandlerEqual returns whether an existing handler has the values of the handler. Values ARM adds to the
+// existing handler, ex) empty patterns, are ignored, and missing values are the same as empty ones.
+func realtimeHandlerEqual(existing any, handler any) bool {
+	switch value := handler.(type) {
+	case map[string]any:
+		existingMap, _ := existing.(map[string]any)
+		for key, item := range value {
+			if !realtimeHandlerEqual(existingMap[key], item) {
+				return false
+			}
+		}
+
+		return true
+	case string:
+		existingString, _ := existing.(string)
+		return existingString == value
+	case []any:
+		existingSlice, _ := existing.([]any)
+		if len(existingSlice) == 0 && len(value) == 0 {
+			return true
+		}
+
+		return reflect.DeepEqual(existingSlice, value)
+	default:
+		return reflect.DeepEqual(existing, handler)
+	}
+}
+
+// realtimeHandlerKey identifies a handler by the host and the path of its url and its patterns, ex)
+// app.azurewebsites.net/eventhandler hub=* event=*. Missing patterns are the same as the default one.
+func realtimeHandlerKey(handler map[string]any) string {
+	value, _ := handler["urlTemplate"].(string)
+	target := value
+	if u, err := url.Parse(value); err == nil {
+		target = strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+	}
+
+	pattern := func(key string) string {
+		value, _ := handler[key].(string)
+		return defaultPattern(value)
+	}
+
+	return fmt.Sprintf("%s hub=%s event=%s userEvent=%s",
+		target, pattern("hubPattern"), pattern("eventPattern"), pattern("userEventPattern"))
+}
+
+func defaultPattern(pattern string) string {
+	if pattern == "" {
+		return "*"
+	}
+
+	return pattern
+}
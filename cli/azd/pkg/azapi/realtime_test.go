@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfigureEventHandler(t *testing.T) {
+	webPubSubId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers" +
+		"/Microsoft.SignalRService/webPubSub/PUBSUB"
+	signalRId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers" +
+		"/Microsoft.SignalRService/signalR/SIGNALR"
+
+	handler := RealtimeEventHandler{
+		Hub:          "chat",
+		UrlTemplate:  "https://app-new.azurewebsites.net/eventhandler",
+		SystemEvents: []string{"connected"},
+	}
+
+	t.Run("WebPubSubReplacesSameHandler", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		realtimeService := NewRealtimeService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/webPubSub/PUBSUB/hubs/chat")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"properties": map[string]any{
+					"anonymousConnectPolicy": "deny",
+					"eventHandlers": []any{
+						map[string]any{"urlTemplate": "https://APP-NEW.azurewebsites.net/eventhandler/"},
+						map[string]any{"urlTemplate": "https://other.contoso.com/eventhandler"},
+						map[string]any{
+							"urlTemplate":      "https://app-new.azurewebsites.net/eventhandler",
+							"userEventPattern": "message",
+						},
+					},
+				},
+			})
+		})
+
+		var putBody map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/webPubSub/PUBSUB/hubs/chat")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(request.Body).Decode(&putBody); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, putBody)
+		})
+
+		changed, err := realtimeService.ConfigureEventHandler(*mockContext.Context, webPubSubId, handler)
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		properties := putBody["properties"].(map[string]any)
+		require.Equal(t, "deny", properties["anonymousConnectPolicy"])
+		require.Equal(t, []any{
+			map[string]any{
+				"urlTemplate":      "https://app-new.azurewebsites.net/eventhandler",
+				"userEventPattern": "*",
+				"systemEvents":     []any{"connected"},
+				"auth": map[string]any{
+					"type":            "ManagedIdentity",
+					"managedIdentity": map[string]any{"resource": ""},
+				},
+			},
+			map[string]any{"urlTemplate": "https://other.contoso.com/eventhandler"},
+			map[string]any{
+				"urlTemplate":      "https://app-new.azurewebsites.net/eventhandler",
+				"userEventPattern": "message",
+			},
+		}, properties["eventHandlers"])
+	})
+
+	t.Run("WebPubSubUnchanged", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		realtimeService := NewRealtimeService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/hubs/chat")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"properties": map[string]any{
+					"eventHandlers": []any{
+						map[string]any{
+							"urlTemplate":      "https://app-new.azurewebsites.net/eventhandler",
+							"userEventPattern": "*",
+							"systemEvents":     []any{"connected"},
+							"auth": map[string]any{
+								"type":            "ManagedIdentity",
+								"managedIdentity": map[string]any{},
+							},
+						},
+					},
+				},
+			})
+		})
+
+		changed, err := realtimeService.ConfigureEventHandler(*mockContext.Context, webPubSubId, handler)
+		require.NoError(t, err)
+		require.False(t, changed)
+	})
+
+	t.Run("WebPubSubRequiresHub", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		realtimeService := NewRealtimeService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		_, err := realtimeService.ConfigureEventHandler(*mockContext.Context, webPubSubId, RealtimeEventHandler{
+			UrlTemplate: "https://app-new.azurewebsites.net/eventhandler",
+		})
+		require.ErrorContains(t, err, "the hub of the event handler")
+	})
+
+	t.Run("SignalRUpstream", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		realtimeService := NewRealtimeService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/signalR/SIGNALR")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"properties": map[string]any{},
+			})
+		})
+
+		var patchBody map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPatch && strings.HasSuffix(request.URL.Path, "/signalR/SIGNALR")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(request.Body).Decode(&patchBody); err != nil {
+				return nil, err
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, patchBody)
+		})
+
+		changed, err := realtimeService.ConfigureEventHandler(*mockContext.Context, signalRId, RealtimeEventHandler{
+			UrlTemplate:      "https://func.azurewebsites.net/api/{event}",
+			IdentityResource: "api://backend",
+		})
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		upstream := patchBody["properties"].(map[string]any)["upstream"].(map[string]any)
+		require.Equal(t, []any{
+			map[string]any{
+				"hubPattern":      "*",
+				"eventPattern":    "*",
+				"categoryPattern": "*",
+				"urlTemplate":     "https://func.azurewebsites.net/api/{event}",
+				"auth": map[string]any{
+					"type":            "ManagedIdentity",
+					"managedIdentity": map[string]any{"resource": "api://backend"},
+				},
+			},
+		}, upstream["templates"])
+	})
+
+	t.Run("NotARealtimeResource", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		realtimeService := NewRealtimeService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+
+		_, err := realtimeService.ConfigureEventHandler(
+			*mockContext.Context,
+			"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/WEB",
+			handler,
+		)
+		require.ErrorContains(t, err, "is not a Web PubSub or SignalR Service resource")
+	})
+}
@@ -8,11 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
@@ -34,7 +36,8 @@ type ResourceGroup struct {
 
 type ResourceExtended struct {
 	Resource
-	Kind string `json:"kind"`
+	Kind string             `json:"kind"`
+	Tags map[string]*string `json:"tags,omitempty"`
 }
 
 // Optional parameters for resource group listing.
@@ -154,6 +157,7 @@ func (rs *ResourceService) ListResourceGroupResources(
 					Location: *resource.Location,
 				},
 				Kind: convert.ToValueWithDefault(resource.Kind, ""),
+				Tags: resource.Tags,
 			})
 		}
 	}
@@ -244,6 +248,7 @@ func (rs *ResourceService) ListSubscriptionResources(
 					Location: *resource.Location,
 				},
 				Kind: convert.ToValueWithDefault(resource.Kind, ""),
+				Tags: resource.Tags,
 			})
 		}
 	}
@@ -251,6 +256,30 @@ func (rs *ResourceService) ListSubscriptionResources(
 	return resources, nil
 }
 
+// GetResourceGroup gets a resource group. When the resource group doesn't exist, the error is an *azcore.ResponseError
+// with the http.StatusNotFound status code.
+func (rs *ResourceService) GetResourceGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) (*ResourceGroup, error) {
+	client, err := rs.createResourceGroupClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Get(ctx, resourceGroupName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource group '%s': %w", resourceGroupName, err)
+	}
+
+	return &ResourceGroup{
+		Id:       *response.ID,
+		Name:     *response.Name,
+		Location: *response.Location,
+	}, nil
+}
+
 func (rs *ResourceService) CreateOrUpdateResourceGroup(
 	ctx context.Context,
 	subscriptionId string,
@@ -279,6 +308,36 @@ func (rs *ResourceService) CreateOrUpdateResourceGroup(
 	}, nil
 }
 
+// MergeResourceGroupTags adds the tags to the resource group, updating the values of the existing tags with the same
+// names and keeping the other ones.
+func (rs *ResourceService) MergeResourceGroupTags(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	tags map[string]*string,
+) error {
+	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	client, err := armresources.NewTagsClient(subscriptionId, credential, rs.armClientOptions)
+	if err != nil {
+		return fmt.Errorf("creating Tags client: %w", err)
+	}
+
+	scope := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionId, resourceGroupName)
+	_, err = client.UpdateAtScope(ctx, scope, armresources.TagsPatchResource{
+		Operation:  to.Ptr(armresources.TagsPatchOperationMerge),
+		Properties: &armresources.Tags{Tags: tags},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("updating tags of resource group '%s': %w", resourceGroupName, err)
+	}
+
+	return nil
+}
+
 func (rs *ResourceService) DeleteResourceGroup(ctx context.Context, subscriptionId string, resourceGroupName string) error {
 	client, err := rs.createResourceGroupClient(ctx, subscriptionId)
 	if err != nil {
@@ -303,6 +362,93 @@ func (rs *ResourceService) DeleteResourceGroup(ctx context.Context, subscription
 	return nil
 }
 
+// DeleteResource deletes the resource with the specified id.
+// The API version used for the request is resolved from the latest stable version registered for the resource type.
+func (rs *ResourceService) DeleteResource(ctx context.Context, subscriptionId string, resourceId string) error {
+	parsedId, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return fmt.Errorf("parsing resource id: %w", err)
+	}
+
+	apiVersion, err := rs.ResolveApiVersion(ctx, subscriptionId, parsedId.ResourceType)
+	if err != nil {
+		return err
+	}
+
+	client, err := rs.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDeleteByID(ctx, resourceId, apiVersion, nil)
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == 404 { // Resource is already deleted
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("beginning resource deletion: %w", err)
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("deleting resource: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveApiVersion finds the latest stable API version for the resource type, falling back to the latest preview
+// version when the resource provider does not publish a stable one.
+func (rs *ResourceService) ResolveApiVersion(
+	ctx context.Context,
+	subscriptionId string,
+	resourceType arm.ResourceType,
+) (string, error) {
+	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := armresources.NewProvidersClient(subscriptionId, credential, rs.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating Providers client: %w", err)
+	}
+
+	provider, err := client.Get(ctx, resourceType.Namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting resource provider '%s': %w", resourceType.Namespace, err)
+	}
+
+	typeName := strings.Join(resourceType.Types, "/")
+	for _, providerType := range provider.ResourceTypes {
+		if providerType.ResourceType == nil || !strings.EqualFold(*providerType.ResourceType, typeName) {
+			continue
+		}
+
+		var fallback string
+		for _, version := range providerType.APIVersions {
+			if version == nil {
+				continue
+			}
+
+			if !strings.Contains(*version, "preview") {
+				return *version, nil
+			}
+
+			if fallback == "" {
+				fallback = *version
+			}
+		}
+
+		if fallback != "" {
+			return fallback, nil
+		}
+	}
+
+	return "", fmt.Errorf("no API version found for resource type '%s'", resourceType.String())
+}
+
 func (rs *ResourceService) createResourcesClient(ctx context.Context, subscriptionId string) (*armresources.Client, error) {
 	credential, err := rs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
@@ -670,7 +670,7 @@ func (d *StackDeployments) createClient(ctx context.Context, subscriptionId stri
 		return nil, err
 	}
 
-	return armdeploymentstacks.NewClient(subscriptionId, credential, d.armClientOptions)
+	return armdeploymentstacks.NewClient(subscriptionId, credential, deploymentsClientOptions(ctx, d.armClientOptions))
 }
 
 // Converts from an ARM Extended Deployment to Azd Generic deployment
@@ -682,6 +682,11 @@ func (d *StackDeployments) convertFromStackDeployment(deployment *armdeployments
 
 	deploymentId := convert.ToValueWithDefault(deployment.Properties.DeploymentID, "")
 
+	modifiedBy := ""
+	if deployment.SystemData != nil {
+		modifiedBy = convert.ToValueWithDefault(deployment.SystemData.LastModifiedBy, "")
+	}
+
 	return &ResourceDeployment{
 		Id:                *deployment.ID,
 		Location:          convert.ToValueWithDefault(deployment.Location, ""),
@@ -695,6 +700,9 @@ func (d *StackDeployments) convertFromStackDeployment(deployment *armdeployments
 		Outputs:           deployment.Properties.Outputs,
 		Resources:         resources,
 		Dependencies:      []*armresources.Dependency{},
+		Duration:          convert.ToValueWithDefault(deployment.Properties.Duration, ""),
+		CorrelationId:     convert.ToValueWithDefault(deployment.Properties.CorrelationID, ""),
+		ModifiedBy:        modifiedBy,
 
 		PortalUrl: fmt.Sprintf("%s/%s/%s",
 			d.cloud.PortalUrlBase,
@@ -11,6 +11,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -194,7 +196,8 @@ func (ds *StandardDeployments) createDeploymentsClient(
 		return nil, err
 	}
 
-	client, err := armresources.NewDeploymentsClient(subscriptionId, credential, ds.armClientOptions)
+	client, err := armresources.NewDeploymentsClient(
+		subscriptionId, credential, deploymentsClientOptions(ctx, ds.armClientOptions))
 	if err != nil {
 		return nil, fmt.Errorf("creating deployments client: %w", err)
 	}
@@ -202,6 +205,23 @@ func (ds *StandardDeployments) createDeploymentsClient(
 	return client, nil
 }
 
+// deploymentsClientOptions returns the ARM client options authenticating the deployment requests to the auxiliary
+// tenants of the context, if any, so deployments can reference resources of other tenants.
+func deploymentsClientOptions(ctx context.Context, options *arm.ClientOptions) *arm.ClientOptions {
+	auxiliaryTenants := account.AuxiliaryTenantsFromContext(ctx)
+	if len(auxiliaryTenants) == 0 {
+		return options
+	}
+
+	withAuxiliaryTenants := arm.ClientOptions{}
+	if options != nil {
+		withAuxiliaryTenants = *options
+	}
+	withAuxiliaryTenants.AuxiliaryTenants = auxiliaryTenants
+
+	return &withAuxiliaryTenants
+}
+
 func (ds *StandardDeployments) DeployToSubscription(
 	ctx context.Context,
 	subscriptionId string,
@@ -443,6 +463,14 @@ func (ds *StandardDeployments) DeleteSubscriptionDeployment(
 		resourceGroups[resourceId.ResourceGroupName] = struct{}{}
 	}
 
+	if keep, ok := options[KeepResourceGroupsOptionKey].([]string); ok {
+		for resourceGroup := range resourceGroups {
+			if slices.ContainsFunc(keep, func(name string) bool { return strings.EqualFold(name, resourceGroup) }) {
+				delete(resourceGroups, resourceGroup)
+			}
+		}
+	}
+
 	for resourceGroup := range resourceGroups {
 		progress.SetProgress(DeleteDeploymentProgress{
 			Name:    resourceGroup,
@@ -535,6 +563,55 @@ func (ds *StandardDeployments) DeleteResourceGroupDeployment(
 	return nil
 }
 
+// DeleteSubscriptionDeploymentRecord removes the deployment from the deployment history of the subscription, keeping the
+// resources it deployed.
+func (ds *StandardDeployments) DeleteSubscriptionDeploymentRecord(
+	ctx context.Context,
+	subscriptionId string,
+	deploymentName string,
+) error {
+	deploymentClient, err := ds.createDeploymentsClient(ctx, subscriptionId)
+	if err != nil {
+		return fmt.Errorf("creating deployments client: %w", err)
+	}
+
+	poller, err := deploymentClient.BeginDeleteAtSubscriptionScope(ctx, deploymentName, nil)
+	if err != nil {
+		return fmt.Errorf("deleting deployment '%s' from subscription: %w", deploymentName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("deleting deployment '%s' from subscription: %w", deploymentName, err)
+	}
+
+	return nil
+}
+
+// DeleteResourceGroupDeploymentRecord removes the deployment from the deployment history of the resource group, keeping
+// the resources it deployed.
+func (ds *StandardDeployments) DeleteResourceGroupDeploymentRecord(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	deploymentName string,
+) error {
+	deploymentClient, err := ds.createDeploymentsClient(ctx, subscriptionId)
+	if err != nil {
+		return fmt.Errorf("creating deployments client: %w", err)
+	}
+
+	poller, err := deploymentClient.BeginDelete(ctx, resourceGroupName, deploymentName, nil)
+	if err != nil {
+		return fmt.Errorf("deleting deployment '%s' from resource group: %w", deploymentName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("deleting deployment '%s' from resource group: %w", deploymentName, err)
+	}
+
+	return nil
+}
+
 func (ds *StandardDeployments) WhatIfDeployToSubscription(
 	ctx context.Context,
 	subscriptionId string,
@@ -645,6 +722,8 @@ func (ds *StandardDeployments) convertFromArmDeployment(deployment *armresources
 		Outputs:           deployment.Properties.Outputs,
 		Resources:         deployment.Properties.OutputResources,
 		Dependencies:      deployment.Properties.Dependencies,
+		Duration:          convert.ToValueWithDefault(deployment.Properties.Duration, ""),
+		CorrelationId:     convert.ToValueWithDefault(deployment.Properties.CorrelationID, ""),
 
 		PortalUrl: fmt.Sprintf("%s/%s/%s",
 			ds.cloud.PortalUrlBase,
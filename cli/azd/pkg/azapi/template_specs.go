@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The Template Specs SDK isn't a dependency of azd, the REST API is called directly
+const templateSpecsApiVersion = "2022-02-01"
+
+// TemplateSpecVersion is a version of an Azure Template Spec.
+type TemplateSpecVersion struct {
+	Description  string
+	MainTemplate azure.RawArmTemplate
+	// Free-form metadata stored with the version, ex) the parameters of the deployment
+	Metadata map[string]any
+}
+
+// CreateOrUpdateTemplateSpecVersion publishes a version of a template spec, creating the template spec when it doesn't
+// exist yet, and returns the resource id of the version.
+func (cli *AzureClient) CreateOrUpdateTemplateSpecVersion(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	templateSpecName string,
+	versionName string,
+	location string,
+	version TemplateSpecVersion,
+) (string, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := arm.NewClient("azd-templatespecs", "v1.0.0", credential, cli.armClientOptions)
+	if err != nil {
+		return "", fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	templateSpecId := fmt.Sprintf(
+		"%s/providers/Microsoft.Resources/templateSpecs/%s",
+		azure.ResourceGroupRID(subscriptionId, resourceGroupName),
+		templateSpecName,
+	)
+	versionId := fmt.Sprintf("%s/versions/%s", templateSpecId, versionName)
+
+	err = putTemplateSpecResource(ctx, client, templateSpecId, map[string]any{
+		"location": location,
+		"properties": map[string]any{
+			"description": "Templates provisioned by the Azure Developer CLI",
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating template spec '%s': %w", templateSpecName, err)
+	}
+
+	properties := map[string]any{
+		"mainTemplate": version.MainTemplate,
+	}
+	if version.Description != "" {
+		properties["description"] = version.Description
+	}
+	if len(version.Metadata) > 0 {
+		properties["metadata"] = version.Metadata
+	}
+
+	err = putTemplateSpecResource(ctx, client, versionId, map[string]any{
+		"location":   location,
+		"properties": properties,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating version '%s' of template spec '%s': %w", versionName, templateSpecName, err)
+	}
+
+	return versionId, nil
+}
+
+// putTemplateSpecResource creates or updates the resource with the given id, at the api version of the template specs.
+func putTemplateSpecResource(ctx context.Context, client *arm.Client, resourceId string, body any) error {
+	req, err := runtime.NewRequest(ctx, http.MethodPut, runtime.JoinPaths(client.Endpoint(), resourceId))
+	if err != nil {
+		return err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", templateSpecsApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusCreated) {
+		return runtime.NewResponseError(res)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateOrUpdateTemplateSpecVersion(t *testing.T) {
+	const templateSpecPath = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP" +
+		"/providers/Microsoft.Resources/templateSpecs/main"
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		bodies := map[string]map[string]any{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.HasPrefix(request.URL.Path, templateSpecPath)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.Equal(t, templateSpecsApiVersion, request.URL.Query().Get("api-version"))
+
+			contents, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+
+			body := map[string]any{}
+			require.NoError(t, json.Unmarshal(contents, &body))
+			bodies[request.URL.Path] = body
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, body)
+		})
+
+		id, err := azCli.CreateOrUpdateTemplateSpecVersion(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP",
+			"main",
+			"dev-0123456789ab",
+			"eastus2",
+			TemplateSpecVersion{
+				Description:  "Provisioned environment 'dev'",
+				MainTemplate: []byte(`{"resources": []}`),
+				Metadata:     map[string]any{"environment": "dev"},
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, templateSpecPath+"/versions/dev-0123456789ab", id)
+
+		require.Equal(t, "eastus2", bodies[templateSpecPath]["location"])
+		require.Equal(t, map[string]any{
+			"location": "eastus2",
+			"properties": map[string]any{
+				"description":  "Provisioned environment 'dev'",
+				"mainTemplate": map[string]any{"resources": []any{}},
+				"metadata":     map[string]any{"environment": "dev"},
+			},
+		}, bodies[templateSpecPath+"/versions/dev-0123456789ab"])
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.HasPrefix(request.URL.Path, templateSpecPath)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		_, err := azCli.CreateOrUpdateTemplateSpecVersion(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "main", "dev", "eastus2", TemplateSpecVersion{})
+		require.ErrorContains(t, err, "creating template spec 'main'")
+	})
+}
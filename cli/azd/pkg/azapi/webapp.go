@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 
@@ -125,6 +126,12 @@ func resumeDeployment(err error, progressLog func(msg string)) bool {
 	return false
 }
 
+// ZipDeployOptions are the options of the deployment of a zip package to an App Service or a Function App
+type ZipDeployOptions struct {
+	// If true, the package is deployed with Kudu zipdeploy, even when the app supports OneDeploy.
+	DisableOneDeploy bool
+}
+
 func (cli *AzureClient) DeployAppServiceZip(
 	ctx context.Context,
 	subscriptionId string,
@@ -132,6 +139,7 @@ func (cli *AzureClient) DeployAppServiceZip(
 	appName string,
 	deployZipFile io.ReadSeeker,
 	progressLog func(string),
+	options *ZipDeployOptions,
 ) (*string, error) {
 	app, err := cli.appService(ctx, subscriptionId, resourceGroup, appName)
 	if err != nil {
@@ -143,6 +151,13 @@ func (cli *AzureClient) DeployAppServiceZip(
 		return nil, err
 	}
 
+	if isLinuxWebApp(app) && (options == nil || !options.DisableOneDeploy) {
+		status, err := cli.oneDeploy(ctx, subscriptionId, hostName, deployZipFile, false, progressLog)
+		if err != nil || status != nil {
+			return status, err
+		}
+	}
+
 	client, err := cli.createZipDeployClient(ctx, subscriptionId, hostName)
 	if err != nil {
 		return nil, err
@@ -170,6 +185,51 @@ func (cli *AzureClient) DeployAppServiceZip(
 	return to.Ptr(response.StatusText), nil
 }
 
+// oneDeploy deploys the package with OneDeploy, reporting the progress of the upload to progressLog when set. A nil
+// status is returned, and the package is rewound, when the host doesn't support it and the package must be deployed
+// with zipdeploy instead.
+func (cli *AzureClient) oneDeploy(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+	deployZipFile io.ReadSeeker,
+	remoteBuild bool,
+	progressLog func(string),
+) (*string, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azsdk.NewOneDeployClient(hostName, credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating OneDeploy client: %w", err)
+	}
+
+	response, err := client.Deploy(ctx, deployZipFile, &azsdk.OneDeployOptions{
+		RemoteBuild: remoteBuild,
+		Progress:    progressLog,
+	})
+	if errors.Is(err, azsdk.ErrOneDeployNotSupported) {
+		log.Printf("OneDeploy is not supported by %s, falling back to zipdeploy", hostName)
+		if progressLog != nil {
+			progressLog("OneDeploy is not supported by the app. Resuming deployment with zipdeploy.")
+		}
+
+		if _, err := deployZipFile.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("seeking package: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("deploying package with OneDeploy: %w", err)
+	}
+
+	return to.Ptr(response.StatusText), nil
+}
+
 func (cli *AzureClient) createWebAppsClient(
 	ctx context.Context,
 	subscriptionId string,
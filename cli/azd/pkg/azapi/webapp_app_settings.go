@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+)
+
+// AppSettingChangeKind is how an app setting changes
+type AppSettingChangeKind string
+
+const (
+	AppSettingAdded   AppSettingChangeKind = "added"
+	AppSettingUpdated AppSettingChangeKind = "updated"
+)
+
+// AppSettingChange is a change of an app setting of an App Service or a Function App. The value isn't kept, since app
+// settings often hold secrets.
+type AppSettingChange struct {
+	Name string               `json:"name"`
+	Kind AppSettingChangeKind `json:"kind"`
+}
+
+// DiffAppSettings returns the changes, sorted by name, applying the desired settings to the current ones. Current
+// settings that aren't desired are kept, so they are never part of the changes.
+func DiffAppSettings(current map[string]*string, desired map[string]string) []AppSettingChange {
+	changes := []AppSettingChange{}
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		existing, has := current[name]
+		switch {
+		case !has || existing == nil:
+			changes = append(changes, AppSettingChange{Name: name, Kind: AppSettingAdded})
+		case *existing != desired[name]:
+			changes = append(changes, AppSettingChange{Name: name, Kind: AppSettingUpdated})
+		}
+	}
+
+	return changes
+}
+
+// AppServiceAppSettingChanges returns the changes UpdateAppServiceAppSettings would make to the app settings of an App
+// Service or a Function App, without applying them.
+func (cli *AzureClient) AppServiceAppSettingChanges(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	settings map[string]string,
+) ([]AppSettingChange, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := client.ListApplicationSettings(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing app settings of app service '%s': %w", appName, err)
+	}
+
+	return DiffAppSettings(current.Properties, settings), nil
+}
+
+// UpdateAppServiceAppSettings adds or updates the given app settings of an App Service or a Function App, keeping the
+// other settings, and returns the changes. The settings are only written when they change, since writing them restarts
+// the app.
+func (cli *AzureClient) UpdateAppServiceAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	settings map[string]string,
+) ([]AppSettingChange, error) {
+	return cli.UpdateAppServiceAppSettingsWithDefaults(ctx, subscriptionId, resourceGroup, appName, settings, nil)
+}
+
+// UpdateAppServiceAppSettingsWithDefaults is UpdateAppServiceAppSettings, with default settings which are only added
+// when neither the settings nor the app have them, so the values set by the infrastructure of the app are kept.
+func (cli *AzureClient) UpdateAppServiceAppSettingsWithDefaults(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	settings map[string]string,
+	defaults map[string]string,
+) ([]AppSettingChange, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := client.ListApplicationSettings(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing app settings of app service '%s': %w", appName, err)
+	}
+
+	properties := current.Properties
+	if properties == nil {
+		properties = map[string]*string{}
+	}
+
+	settings = maps.Clone(settings)
+	for name, value := range defaults {
+		_, desired := settings[name]
+		existing, has := properties[name]
+		if !desired && (!has || existing == nil) {
+			settings[name] = value
+		}
+	}
+
+	changes := DiffAppSettings(properties, settings)
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	for _, change := range changes {
+		properties[change.Name] = to.Ptr(settings[change.Name])
+	}
+
+	_, err = client.UpdateApplicationSettings(ctx, resourceGroup, appName, armappservice.StringDictionary{
+		Properties: properties,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("updating app settings of app service '%s': %w", appName, err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAppSettings(t *testing.T) {
+	current := map[string]*string{
+		"API_URL":   to.Ptr("https://api.contoso.com"),
+		"LOG_LEVEL": to.Ptr("info"),
+		"EXISTING":  to.Ptr("value"),
+	}
+
+	changes := DiffAppSettings(current, map[string]string{
+		"LOG_LEVEL": "debug",
+		"API_URL":   "https://api.contoso.com",
+		"NEW":       "value",
+	})
+	require.Equal(t, []AppSettingChange{
+		{Name: "LOG_LEVEL", Kind: AppSettingUpdated},
+		{Name: "NEW", Kind: AppSettingAdded},
+	}, changes)
+
+	require.Empty(t, DiffAppSettings(current, map[string]string{"API_URL": "https://api.contoso.com"}))
+}
+
+func TestAppServiceAppSettingChanges(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/config/appsettings/list")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.StringDictionary{
+			Properties: map[string]*string{"LOG_LEVEL": to.Ptr("info")},
+		})
+	})
+
+	azCli := newAzureClientFromMockContext(mockContext)
+	changes, err := azCli.AppServiceAppSettingChanges(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"APP_NAME",
+		map[string]string{"LOG_LEVEL": "debug", "NEW": "value"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []AppSettingChange{
+		{Name: "LOG_LEVEL", Kind: AppSettingUpdated},
+		{Name: "NEW", Kind: AppSettingAdded},
+	}, changes)
+}
+
+func TestUpdateAppServiceAppSettingsWithDefaults(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/config/appsettings/list")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.StringDictionary{
+			Properties: map[string]*string{"FUNCTIONS_EXTENSION_VERSION": to.Ptr("~3")},
+		})
+	})
+
+	var updated armappservice.StringDictionary
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/config/appsettings")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := mocks.ReadHttpBody(request.Body, &updated); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, updated)
+	})
+
+	azCli := newAzureClientFromMockContext(mockContext)
+	changes, err := azCli.UpdateAppServiceAppSettingsWithDefaults(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"APP_NAME",
+		map[string]string{"APP_KIND": "custom"},
+		map[string]string{"APP_KIND": "workflowApp", "FUNCTIONS_EXTENSION_VERSION": "~4", "NEW": "value"},
+	)
+	require.NoError(t, err)
+
+	// The defaults don't override the settings, nor the settings of the app
+	require.Equal(t, []AppSettingChange{
+		{Name: "APP_KIND", Kind: AppSettingAdded},
+		{Name: "NEW", Kind: AppSettingAdded},
+	}, changes)
+	require.Equal(t, "custom", *updated.Properties["APP_KIND"])
+	require.Equal(t, "~3", *updated.Properties["FUNCTIONS_EXTENSION_VERSION"])
+	require.Equal(t, "value", *updated.Properties["NEW"])
+}
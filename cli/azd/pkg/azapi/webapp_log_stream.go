@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// StreamAppServiceLogs streams the application and web server logs of an App Service from the log stream of its Kudu
+// site, following new logs until ctx is canceled. The caller must close the returned reader.
+func (cli *AzureClient) StreamAppServiceLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (io.ReadCloser, error) {
+	app, err := cli.appService(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	hostName, err := appServiceRepositoryHost(app, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &arm.ClientOptions{}
+	if cli.armClientOptions != nil {
+		optionsCopy := *cli.armClientOptions
+		options = &optionsCopy
+	}
+
+	// The Kudu site is not an Azure Resource Manager endpoint, there is no Resource Provider to register
+	options.DisableRPRegistration = true
+
+	client, err := arm.NewClient("azd-log-stream", "v1.0.0", credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, fmt.Sprintf("https://%s/api/logstream", hostName))
+	if err != nil {
+		return nil, err
+	}
+
+	// The log stream never ends, the body is read as the logs are written
+	runtime.SkipBodyDownload(req)
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("streaming logs of app service '%s': %w", appName, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		defer res.Body.Close()
+		return nil, fmt.Errorf("streaming logs of app service '%s': %w", appName, runtime.NewResponseError(res))
+	}
+
+	return res.Body, nil
+}
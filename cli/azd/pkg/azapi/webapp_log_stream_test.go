@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StreamAppServiceLogs(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		registerIsLinuxWebAppMocks(mockContext, &ran)
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				request.URL.Host == "LINUX_WEB_APP_NAME_SCM_HOST" &&
+				request.URL.Path == "/api/logstream"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Request:    request,
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("line 1\nline 2\n")),
+			}, nil
+		})
+
+		stream, err := azCli.StreamAppServiceLogs(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP_ID", "LINUX_WEB_APP_NAME")
+		require.NoError(t, err)
+		defer stream.Close()
+
+		logs, err := io.ReadAll(stream)
+		require.NoError(t, err)
+		require.True(t, ran)
+		require.Equal(t, "line 1\nline 2\n", string(logs))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzureClientFromMockContext(mockContext)
+
+		registerIsLinuxWebAppMocks(mockContext, &ran)
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && request.URL.Path == "/api/logstream"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		stream, err := azCli.StreamAppServiceLogs(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP_ID", "LINUX_WEB_APP_NAME")
+		require.Error(t, err)
+		require.Nil(t, stream)
+		require.True(t, ran)
+	})
+}
@@ -0,0 +1,205 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// The site containers API isn't available in the armappservice version used by azd
+const siteContainersApiVersion = "2024-04-01"
+
+// SiteContainerAuthType is how App Service authenticates to the registry of a site container image.
+type SiteContainerAuthType string
+
+const (
+	SiteContainerAuthAnonymous       SiteContainerAuthType = "Anonymous"
+	SiteContainerAuthUserCredentials SiteContainerAuthType = "UserCredentials"
+	SiteContainerAuthSystemIdentity  SiteContainerAuthType = "SystemIdentity"
+	SiteContainerAuthUserAssigned    SiteContainerAuthType = "UserAssigned"
+)
+
+// SiteContainer is a container of a Linux App Service using sidecars. The main container receives the traffic of the
+// app, the sidecars run next to it.
+type SiteContainer struct {
+	Name                        string                `json:"-"`
+	Image                       string                `json:"image"`
+	TargetPort                  string                `json:"targetPort,omitempty"`
+	IsMain                      bool                  `json:"isMain"`
+	AuthType                    SiteContainerAuthType `json:"authType,omitempty"`
+	UserName                    string                `json:"userName,omitempty"`
+	UserManagedIdentityClientId string                `json:"userManagedIdentityClientId,omitempty"`
+	// The environment variables of the container. Each value is the name of the app setting holding the value.
+	EnvironmentVariables []SiteContainerEnvironmentVariable `json:"environmentVariables,omitempty"`
+}
+
+// SiteContainerEnvironmentVariable is an environment variable of a site container, which value is read from the app
+// setting named Value.
+type SiteContainerEnvironmentVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type siteContainerResource struct {
+	Name       string        `json:"name,omitempty"`
+	Properties SiteContainer `json:"properties"`
+}
+
+// GetAppServiceSiteContainers gets the containers of a Linux App Service using sidecars.
+func (cli *AzureClient) GetAppServiceSiteContainers(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) ([]SiteContainer, error) {
+	client, err := cli.createSiteContainersClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	nextLink := runtime.JoinPaths(
+		client.Endpoint(), azure.WebsiteRID(subscriptionId, resourceGroup, appName), "sitecontainers")
+	nextLink += "?api-version=" + siteContainersApiVersion
+
+	containers := []SiteContainer{}
+	for nextLink != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("getting containers of app service '%s': %w", appName, err)
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, fmt.Errorf("getting containers of app service '%s': %w", appName, runtime.NewResponseError(res))
+		}
+
+		var page struct {
+			Value    []siteContainerResource `json:"value"`
+			NextLink string                  `json:"nextLink"`
+		}
+		if err := runtime.UnmarshalAsJSON(res, &page); err != nil {
+			return nil, err
+		}
+
+		for _, resource := range page.Value {
+			container := resource.Properties
+			container.Name = resource.Name
+			containers = append(containers, container)
+		}
+
+		nextLink = page.NextLink
+	}
+
+	return containers, nil
+}
+
+// UpdateAppServiceSiteContainer creates or updates a container of a Linux App Service using sidecars.
+func (cli *AzureClient) UpdateAppServiceSiteContainer(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	container SiteContainer,
+) error {
+	client, err := cli.createSiteContainersClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodPut,
+		runtime.JoinPaths(
+			client.Endpoint(),
+			azure.WebsiteRID(subscriptionId, resourceGroup, appName),
+			"sitecontainers",
+			container.Name,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("api-version", siteContainersApiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+
+	if err := runtime.MarshalAsJSON(req, siteContainerResource{Properties: container}); err != nil {
+		return err
+	}
+
+	res, err := client.Pipeline().Do(req)
+	if err != nil {
+		return fmt.Errorf("updating container '%s': %w", container.Name, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK, http.StatusCreated) {
+		return fmt.Errorf("updating container '%s': %w", container.Name, runtime.NewResponseError(res))
+	}
+
+	return nil
+}
+
+// RestartAppService restarts an App Service, applying the changes made to its containers.
+func (cli *AzureClient) RestartAppService(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Restart(ctx, resourceGroup, appName, nil); err != nil {
+		return fmt.Errorf("restarting app service '%s': %w", appName, err)
+	}
+
+	return nil
+}
+
+// AppServiceContainerLogsUrl returns the URL of the Kudu endpoint listing the logs of the containers of an App Service.
+func (cli *AzureClient) AppServiceContainerLogsUrl(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (string, error) {
+	app, err := cli.appService(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return "", err
+	}
+
+	hostName, err := appServiceRepositoryHost(app, appName)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("https://%s/api/logs/docker", hostName), nil
+}
+
+func (cli *AzureClient) createSiteContainersClient(ctx context.Context, subscriptionId string) (*arm.Client, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azd-site-containers", "v1.0.0", credential, cli.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ARM client: %w", err)
+	}
+
+	return client, nil
+}
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/appconfig"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/cosmosdb"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	infraBicep "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	infraExisting "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/existing"
 	infraTerraform "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/terraform"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
@@ -80,6 +82,7 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	provisionProviderMap := map[provisioning.ProviderKind]any{
 		provisioning.Bicep:     infraBicep.NewBicepProvider,
 		provisioning.Terraform: infraTerraform.NewTerraformProvider,
+		provisioning.Existing:  infraExisting.NewExistingProvider,
 	}
 
 	for provider, constructor := range provisionProviderMap {
@@ -95,7 +98,8 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 
 	// Remote Environment State Providers
 	remoteStateProviderMap := map[environment.RemoteKind]any{
-		environment.RemoteKindAzureBlobStorage: environment.NewStorageBlobDataStore,
+		environment.RemoteKindAzureBlobStorage:      environment.NewStorageBlobDataStore,
+		environment.RemoteKindAzureAppConfiguration: environment.NewAppConfigDataStore,
 	}
 
 	for remoteKind, constructor := range remoteStateProviderMap {
@@ -131,6 +135,36 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		return storageAccountConfig, nil
 	})
 
+	container.MustRegisterSingleton(func(
+		remoteStateConfig *state.RemoteConfig,
+		projectConfig *project.ProjectConfig,
+	) (*appconfig.AccountConfig, error) {
+		if remoteStateConfig == nil {
+			return nil, nil
+		}
+
+		var appConfigAccountConfig *appconfig.AccountConfig
+		jsonBytes, err := json.Marshal(remoteStateConfig.Config)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling remote state config: %w", err)
+		}
+
+		if err := json.Unmarshal(jsonBytes, &appConfigAccountConfig); err != nil {
+			return nil, fmt.Errorf("unmarshalling remote state config: %w", err)
+		}
+
+		// If a key prefix has not been explicitly configured
+		// Default to use the project name so multiple projects can share a single store
+		if appConfigAccountConfig.KeyPrefix == "" {
+			appConfigAccountConfig.KeyPrefix = projectConfig.Name + "/"
+		}
+
+		return appConfigAccountConfig, nil
+	})
+
+	// App Configuration components
+	container.MustRegisterSingleton(appconfig.NewKeyValueClient)
+
 	// Storage components
 	container.MustRegisterSingleton(storage.NewBlobClient)
 	container.MustRegisterSingleton(storage.NewBlobSdkClient)
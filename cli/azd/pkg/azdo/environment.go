@@ -0,0 +1,361 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azdo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/google/uuid"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/identity"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/pipelinepermissions"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/pipelineschecks"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/taskagent"
+)
+
+const (
+	environmentResourceType   = "environment"
+	variableGroupResourceType = "variablegroup"
+)
+
+// approvalCheckTypeId is the id of the type of the approval checks
+var approvalCheckTypeId = uuid.MustParse("8C6F20A7-A545-4486-9777-F762FAFE0D4D")
+
+// VariableGroupName returns the name of the variable group holding the variables and secrets of an azd environment.
+func VariableGroupName(envName string) string {
+	return "azd-" + envName
+}
+
+// EnsureEnvironment gets or creates the Azure DevOps environment with the given name and authorizes all the pipelines
+// of the project to use it.
+func EnsureEnvironment(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	name string,
+) (*taskagent.EnvironmentInstance, error) {
+	client, err := taskagent.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	environments, err := client.GetEnvironments(ctx, taskagent.GetEnvironmentsArgs{
+		Project: &projectId,
+		Name:    &name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting environment %s: %w", name, err)
+	}
+
+	var environment *taskagent.EnvironmentInstance
+	for _, env := range environments.Value {
+		if env.Name != nil && *env.Name == name {
+			environment = &env
+			break
+		}
+	}
+
+	if environment == nil {
+		environment, err = client.AddEnvironment(ctx, taskagent.AddEnvironmentArgs{
+			Project: &projectId,
+			EnvironmentCreateParameter: &taskagent.EnvironmentCreateParameter{
+				Name:        &name,
+				Description: to.Ptr("Created by Azure Developer CLI"),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating environment %s: %w", name, err)
+		}
+	}
+
+	err = authorizeResourceToAllPipelines(
+		ctx, connection, projectId, environmentResourceType, strconv.Itoa(*environment.Id))
+	if err != nil {
+		return nil, fmt.Errorf("authorizing environment %s: %w", name, err)
+	}
+
+	return environment, nil
+}
+
+// EnsureApprovalCheck makes sure that the runs of the pipelines deploying to the environment are approved by one of the
+// approvers. Approvers are users or groups, identified by their name or email.
+func EnsureApprovalCheck(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	environment *taskagent.EnvironmentInstance,
+	approvers []string,
+) error {
+	identities, err := resolveIdentities(ctx, connection, approvers)
+	if err != nil {
+		return err
+	}
+
+	checksClient, err := pipelineschecks.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	environmentId := strconv.Itoa(*environment.Id)
+	resourceType := environmentResourceType
+	checks, err := checksClient.GetCheckConfigurationsOnResource(ctx, pipelineschecks.GetCheckConfigurationsOnResourceArgs{
+		Project:      &projectId,
+		ResourceType: &resourceType,
+		ResourceId:   &environmentId,
+	})
+	if err != nil {
+		return fmt.Errorf("getting checks of environment %s: %w", *environment.Name, err)
+	}
+
+	var existingCheckId *int
+	for _, check := range *checks {
+		if check.Type != nil && check.Type.Id != nil && *check.Type.Id == approvalCheckTypeId {
+			existingCheckId = check.Id
+			break
+		}
+	}
+
+	checkApprovers := make([]approvalCheckApprover, 0, len(identities))
+	for _, identity := range identities {
+		checkApprovers = append(checkApprovers, approvalCheckApprover{Id: identity.Id.String()})
+	}
+
+	check := approvalCheckConfiguration{
+		Type: pipelineschecks.CheckType{
+			Id:   &approvalCheckTypeId,
+			Name: to.Ptr("Approval"),
+		},
+		Resource: pipelineschecks.Resource{
+			Id:   &environmentId,
+			Type: &resourceType,
+		},
+		Settings: approvalCheckSettings{
+			Approvers:                 checkApprovers,
+			MinRequiredApprovers:      1,
+			Instructions:              "Approve the deployment of the azd environment " + *environment.Name,
+			RequesterCannotBeApprover: false,
+		},
+		// The approval expires after 30 days
+		Timeout: 43200,
+	}
+
+	client, err := newChecksClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	if err := client.saveCheckConfiguration(ctx, projectId, existingCheckId, check); err != nil {
+		return fmt.Errorf("configuring approvals of environment %s: %w", *environment.Name, err)
+	}
+
+	return nil
+}
+
+// EnsureVariableGroup creates or updates the variable group with the given name, setting exactly the given variables
+// and secrets, and authorizes all the pipelines of the project to use it.
+func EnsureVariableGroup(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	name string,
+	variables map[string]string,
+	secrets map[string]string,
+) (*taskagent.VariableGroup, error) {
+	client, err := taskagent.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	groupVariables := map[string]any{}
+	for key, value := range variables {
+		groupVariables[key] = taskagent.VariableValue{
+			Value:    to.Ptr(value),
+			IsSecret: to.Ptr(false),
+		}
+	}
+	for key, value := range secrets {
+		groupVariables[key] = taskagent.VariableValue{
+			Value:    to.Ptr(value),
+			IsSecret: to.Ptr(true),
+		}
+	}
+
+	projectUuid, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, fmt.Errorf("parsing project id %s: %w", projectId, err)
+	}
+
+	parameters := &taskagent.VariableGroupParameters{
+		Name:        &name,
+		Description: to.Ptr("Created by Azure Developer CLI"),
+		Type:        to.Ptr("Vsts"),
+		Variables:   &groupVariables,
+		VariableGroupProjectReferences: &[]taskagent.VariableGroupProjectReference{
+			{
+				Name: &name,
+				ProjectReference: &taskagent.ProjectReference{
+					Id: &projectUuid,
+				},
+			},
+		},
+	}
+
+	groups, err := client.GetVariableGroups(ctx, taskagent.GetVariableGroupsArgs{
+		Project:   &projectId,
+		GroupName: &name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting variable group %s: %w", name, err)
+	}
+
+	var group *taskagent.VariableGroup
+	if groups != nil && len(*groups) > 0 {
+		group, err = client.UpdateVariableGroup(ctx, taskagent.UpdateVariableGroupArgs{
+			GroupId:                 (*groups)[0].Id,
+			VariableGroupParameters: parameters,
+		})
+	} else {
+		group, err = client.AddVariableGroup(ctx, taskagent.AddVariableGroupArgs{
+			VariableGroupParameters: parameters,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("saving variable group %s: %w", name, err)
+	}
+
+	err = authorizeResourceToAllPipelines(ctx, connection, projectId, variableGroupResourceType, strconv.Itoa(*group.Id))
+	if err != nil {
+		return nil, fmt.Errorf("authorizing variable group %s: %w", name, err)
+	}
+
+	return group, nil
+}
+
+// authorize a resource of the project, like an environment or a variable group, to be used in all pipelines
+func authorizeResourceToAllPipelines(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	resourceType string,
+	resourceId string,
+) error {
+	client, err := pipelinepermissions.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.UpdatePipelinePermisionsForResource(ctx, pipelinepermissions.UpdatePipelinePermisionsForResourceArgs{
+		Project:      &projectId,
+		ResourceType: &resourceType,
+		ResourceId:   &resourceId,
+		ResourceAuthorization: &pipelinepermissions.ResourcePipelinePermissions{
+			AllPipelines: &pipelinepermissions.Permission{
+				Authorized: to.Ptr(true),
+			},
+		},
+	})
+
+	return err
+}
+
+// resolveIdentities finds the identity of each approver, which can be the name or the email of a user or a group.
+func resolveIdentities(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	approvers []string,
+) ([]identity.Identity, error) {
+	client, err := identity.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]identity.Identity, 0, len(approvers))
+	for _, approver := range approvers {
+		found, err := client.ReadIdentities(ctx, identity.ReadIdentitiesArgs{
+			SearchFilter: to.Ptr("General"),
+			FilterValue:  &approver,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("finding approver %s: %w", approver, err)
+		}
+
+		if found == nil || len(*found) == 0 || (*found)[0].Id == nil {
+			return nil, fmt.Errorf("approver %s was not found in the organization", approver)
+		}
+
+		identities = append(identities, (*found)[0])
+	}
+
+	return identities, nil
+}
+
+// approvalCheckConfiguration is the configuration of an approval check. The check settings are not part of the
+// pipelineschecks.CheckConfiguration model.
+type approvalCheckConfiguration struct {
+	Type     pipelineschecks.CheckType `json:"type"`
+	Resource pipelineschecks.Resource  `json:"resource"`
+	Settings approvalCheckSettings     `json:"settings"`
+	Timeout  int                       `json:"timeout"`
+}
+
+type approvalCheckSettings struct {
+	Approvers                 []approvalCheckApprover `json:"approvers"`
+	MinRequiredApprovers      int                     `json:"minRequiredApprovers"`
+	Instructions              string                  `json:"instructions"`
+	RequesterCannotBeApprover bool                    `json:"requesterCannotBeApprover"`
+}
+
+type approvalCheckApprover struct {
+	Id string `json:"id"`
+}
+
+func newChecksClient(ctx context.Context, connection *azuredevops.Connection) (*checksClient, error) {
+	client, err := connection.GetClientByResourceAreaId(ctx, pipelineschecks.ResourceAreaId)
+	if err != nil {
+		return nil, err
+	}
+	return &checksClient{
+		Client: *client,
+	}, nil
+}
+
+type checksClient struct {
+	Client azuredevops.Client
+}
+
+// local implementation to add or update a check configuration with its settings, which are dropped by the model of the
+// pipelineschecks client.
+func (client *checksClient) saveCheckConfiguration(
+	ctx context.Context, projectId string, checkId *int, check approvalCheckConfiguration) error {
+	routeValues := map[string]string{
+		"project": projectId,
+	}
+
+	method := http.MethodPost
+	if checkId != nil {
+		method = http.MethodPatch
+		routeValues["id"] = strconv.Itoa(*checkId)
+	}
+
+	body, err := json.Marshal(check)
+	if err != nil {
+		return err
+	}
+
+	locationId, err := uuid.Parse("86c8381e-5aee-4cde-8ae4-25c0c7f5eaea")
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Client.Send(
+		ctx, method, locationId, "7.1-preview.1", routeValues, nil, bytes.NewReader(body),
+		"application/json", "application/json", nil)
+	return err
+}
@@ -17,6 +17,19 @@ import (
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/operations"
 )
 
+var (
+	projectNamePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "AZDO_PROJECT_NAME",
+		Description: "The name of the Azure DevOps project to create.",
+		Commands:    []string{"pipeline config"},
+	})
+	existingProjectPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "AZDO_EXISTING_PROJECT",
+		Description: "The name of the existing Azure DevOps project to use.",
+		Commands:    []string{"pipeline config"},
+	})
+)
+
 // returns a process template (basic, agile etc) used in the new project creation flow
 func getProcessTemplateId(ctx context.Context, client core.Client) (string, error) {
 	processArgs := core.GetProcessesArgs{}
@@ -116,6 +129,7 @@ func GetProjectFromNew(
 
 	for {
 		name, err := console.Prompt(ctx, input.ConsoleOptions{
+			Id:           projectNamePromptId,
 			Message:      "Enter the name for your new Azure DevOps Project OR Hit enter to use this name:",
 			DefaultValue: currentFolderName,
 		})
@@ -206,6 +220,7 @@ func GetProjectFromExisting(
 	}
 
 	projectIdx, err := console.Select(ctx, input.ConsoleOptions{
+		Id:      existingProjectPromptId,
 		Message: "Choose an existing Azure DevOps Project",
 		Options: options,
 	})
@@ -12,6 +12,12 @@ import (
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
 )
 
+var existingRepositoryPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "AZDO_EXISTING_REPOSITORY",
+	Description: "The name of the existing Azure DevOps repository to use.",
+	Commands:    []string{"pipeline config"},
+})
+
 // create a new repository in the current project
 func CreateRepository(
 	ctx context.Context,
@@ -111,6 +117,7 @@ func GetGitRepositoriesInProject(
 	}
 
 	repoIdx, err := console.Select(ctx, input.ConsoleOptions{
+		Id:      existingRepositoryPromptId,
 		Message: "Choose an existing Azure DevOps Repository",
 		Options: options,
 	})
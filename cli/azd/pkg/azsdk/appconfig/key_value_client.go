@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+)
+
+const (
+	apiVersion = "2023-11-01"
+	// The content type used to identify key-values which are Key Vault references
+	KeyVaultReferenceContentType = "application/vnd.microsoft.appconfig.keyvaultref+json;charset=utf-8"
+)
+
+// AccountConfig contains the configuration for connecting to an App Configuration store
+type AccountConfig struct {
+	// The name of the App Configuration store. Used to build the endpoint when Endpoint is not set.
+	Name string
+	// The full endpoint of the store, for example https://<name>.azconfig.io
+	Endpoint string
+	// The prefix prepended to every key managed by azd
+	KeyPrefix string
+}
+
+// KeyValue represents a single key-value within an App Configuration store.
+type KeyValue struct {
+	Key         string            `json:"key"`
+	Label       string            `json:"label,omitempty"`
+	Value       string            `json:"value"`
+	ContentType string            `json:"content_type,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+type KeyValueClient interface {
+	// List returns the key-values matching the key and label filters. Filters support the trailing '*' wildcard.
+	List(ctx context.Context, keyFilter string, labelFilter string) ([]*KeyValue, error)
+
+	// Set creates or updates the specified key-value.
+	Set(ctx context.Context, keyValue *KeyValue) error
+
+	// Delete deletes the key-value with the specified key and label.
+	Delete(ctx context.Context, key string, label string) error
+}
+
+type listResponse struct {
+	Items    []*KeyValue `json:"items"`
+	NextLink string      `json:"@nextLink"`
+}
+
+type keyValueClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// NewKeyValueClient creates a new KeyValueClient for the configured App Configuration store.
+func NewKeyValueClient(
+	credentialProvider auth.MultiTenantCredentialProvider,
+	accountConfig *AccountConfig,
+	coreClientOptions *azcore.ClientOptions,
+) (KeyValueClient, error) {
+	endpoint := strings.TrimSuffix(accountConfig.Endpoint, "/")
+	if endpoint == "" {
+		if accountConfig.Name == "" {
+			return nil, fmt.Errorf("either 'name' or 'endpoint' must be configured for the App Configuration store")
+		}
+
+		endpoint = fmt.Sprintf("https://%s.azconfig.io", accountConfig.Name)
+	}
+
+	// Use home tenant ID
+	credential, err := credentialProvider.GetTokenCredential(context.Background(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{endpoint + "/.default"}, nil)
+	pipeline := runtime.NewPipeline(
+		"appconfig",
+		"1.0.0",
+		runtime.PipelineOptions{PerRetry: []policy.Policy{authPolicy}},
+		coreClientOptions,
+	)
+
+	return &keyValueClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// List returns the key-values matching the key and label filters, following all result pages.
+func (c *keyValueClient) List(ctx context.Context, keyFilter string, labelFilter string) ([]*KeyValue, error) {
+	query := url.Values{}
+	query.Set("key", keyFilter)
+	query.Set("label", labelFilter)
+	query.Set("api-version", apiVersion)
+	nextUrl := fmt.Sprintf("%s/kv?%s", c.endpoint, query.Encode())
+
+	keyValues := []*KeyValue{}
+	for nextUrl != "" {
+		request, err := runtime.NewRequest(ctx, http.MethodGet, nextUrl)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		response, err := c.pipeline.Do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		var page listResponse
+		if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+			return nil, fmt.Errorf("unmarshalling key-values: %w", err)
+		}
+
+		keyValues = append(keyValues, page.Items...)
+
+		nextUrl = ""
+		if page.NextLink != "" {
+			nextUrl = c.endpoint + page.NextLink
+		}
+	}
+
+	return keyValues, nil
+}
+
+// Set creates or updates the specified key-value.
+func (c *keyValueClient) Set(ctx context.Context, keyValue *KeyValue) error {
+	request, err := runtime.NewRequest(ctx, http.MethodPut, c.keyUrl(keyValue.Key, keyValue.Label))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	body := map[string]any{
+		"value":        keyValue.Value,
+		"content_type": keyValue.ContentType,
+		"tags":         keyValue.Tags,
+	}
+	if err := runtime.MarshalAsJSON(request, body); err != nil {
+		return fmt.Errorf("marshalling key-value: %w", err)
+	}
+	request.Raw().Header.Set("Content-Type", "application/vnd.microsoft.appconfig.kv+json")
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// Delete deletes the key-value with the specified key and label.
+func (c *keyValueClient) Delete(ctx context.Context, key string, label string) error {
+	request, err := runtime.NewRequest(ctx, http.MethodDelete, c.keyUrl(key, label))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *keyValueClient) keyUrl(key string, label string) string {
+	query := url.Values{}
+	query.Set("label", label)
+	query.Set("api-version", apiVersion)
+
+	return fmt.Sprintf("%s/kv/%s?%s", c.endpoint, url.PathEscape(key), query.Encode())
+}
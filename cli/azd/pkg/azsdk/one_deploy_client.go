@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+// The upload progress is reported each time this many bytes more of the package are sent
+const oneDeployProgressInterval = 4 * 1024 * 1024
+
+// ErrOneDeployNotSupported is returned when the host doesn't support OneDeploy, the package must be deployed with
+// zipdeploy then.
+var ErrOneDeployNotSupported = errors.New("the host doesn't support OneDeploy")
+
+// OneDeployClient deploys application packages with the OneDeploy API of the application host, usually located at
+// *.scm.azurewebsites.net. The package is streamed in the body of POST /api/publish?type=zip, then the deployment is
+// tracked like with FuncAppHostClient.Publish.
+type OneDeployClient struct {
+	hostName string
+	pipeline runtime.Pipeline
+}
+
+// OneDeployOptions are the options of a OneDeploy deployment
+type OneDeployOptions struct {
+	// If true, the remote host runs Oryx remote build steps after publishing, see PublishOptions.
+	RemoteBuild bool
+	// Called with the progress of the upload of the package, if set.
+	Progress func(string)
+}
+
+// NewOneDeployClient creates a new OneDeployClient
+func NewOneDeployClient(
+	hostName string,
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*OneDeployClient, error) {
+	oneDeployOptions := &arm.ClientOptions{}
+	if options != nil {
+		optionsCopy := *options
+		oneDeployOptions = &optionsCopy
+	}
+
+	oneDeployOptions.DisableRPRegistration = true
+
+	pipeline, err := armruntime.NewPipeline(
+		"one-deploy", "1.0.0", credential, runtime.PipelineOptions{}, oneDeployOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	return &OneDeployClient{
+		hostName: hostName,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Deploy uploads the package and publishes it, waiting for the deployment to complete. ErrOneDeployNotSupported is
+// returned when the host doesn't support OneDeploy.
+func (c *OneDeployClient) Deploy(
+	ctx context.Context,
+	zipFile io.ReadSeeker,
+	options *OneDeployOptions,
+) (PublishResponse, error) {
+	if options == nil {
+		options = &OneDeployOptions{}
+	}
+
+	size, err := zipFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return PublishResponse{}, fmt.Errorf("seeking package: %w", err)
+	}
+
+	if _, err := zipFile.Seek(0, io.SeekStart); err != nil {
+		return PublishResponse{}, fmt.Errorf("seeking package: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://%s/api/publish", c.hostName)
+	request, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
+	if err != nil {
+		return PublishResponse{}, fmt.Errorf("creating deploy request: %w", err)
+	}
+
+	rawRequest := request.Raw()
+	rawRequest.Header.Set("Accept", "application/json")
+
+	query := rawRequest.URL.Query()
+	query.Set("type", "zip")
+	query.Set("async", "true")
+	if options.RemoteBuild {
+		query.Set("RemoteBuild", "true")
+	}
+	rawRequest.URL.RawQuery = query.Encode()
+
+	body := zipFile
+	if options.Progress != nil {
+		body = &uploadProgressReader{ReadSeeker: zipFile, size: size, progress: options.Progress}
+	}
+
+	// The body is streamed from the package, and rewound when the request is retried
+	if err := request.SetBody(streaming.NopCloser(body), "application/zip"); err != nil {
+		return PublishResponse{}, fmt.Errorf("setting request body: %w", err)
+	}
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return PublishResponse{}, err
+	}
+
+	defer response.Body.Close()
+
+	switch {
+	case runtime.HasStatusCode(response, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented):
+		return PublishResponse{}, ErrOneDeployNotSupported
+	case !runtime.HasStatusCode(response, http.StatusAccepted):
+		return PublishResponse{}, runtime.NewResponseError(response)
+	}
+
+	payload, err := runtime.Payload(response)
+	if err != nil {
+		return PublishResponse{}, err
+	}
+
+	// the response body is the deployment id and nothing else.
+	var deploymentId string
+	if err := json.Unmarshal(payload, &deploymentId); err != nil {
+		return PublishResponse{}, err
+	}
+
+	if deploymentId == "" {
+		return PublishResponse{}, fmt.Errorf("missing deployment id")
+	}
+
+	host := &FuncAppHostClient{hostName: c.hostName, pipeline: c.pipeline}
+	return host.waitForDeployment(ctx, fmt.Sprintf("https://%s/api/deployments/%s", c.hostName, deploymentId))
+}
+
+// uploadProgressReader reports the progress of the upload of the package as it's read by the request.
+type uploadProgressReader struct {
+	io.ReadSeeker
+	size     int64
+	read     int64
+	reported int64
+	progress func(string)
+}
+
+func (r *uploadProgressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	r.read += int64(n)
+
+	if r.read-r.reported >= oneDeployProgressInterval || (r.read == r.size && r.reported < r.size) {
+		r.reported = r.read
+		r.progress(fmt.Sprintf("Uploading package (%s of %s)", formatUploadSize(r.read), formatUploadSize(r.size)))
+	}
+
+	return n, err
+}
+
+// Seek rewinds the progress with the package when the request is retried
+func (r *uploadProgressReader) Seek(offset int64, whence int) (int64, error) {
+	position, err := r.ReadSeeker.Seek(offset, whence)
+	if err == nil {
+		r.read = position
+		r.reported = position
+	}
+
+	return position, err
+}
+
+// formatUploadSize formats a size in bytes, ex) 12.3 MB
+func formatUploadSize(size int64) string {
+	const kb = 1024
+	const mb = 1024 * kb
+	switch {
+	case size < kb:
+		return fmt.Sprintf("%d B", size)
+	case size < mb:
+		return fmt.Sprintf("%.1f KB", float64(size)/kb)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/mb)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azsdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneDeploy(t *testing.T) {
+	zipContent := []byte("0123456789")
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		published := registerOneDeployMocks(mockContext, http.StatusAccepted)
+
+		client, err := NewOneDeployClient("HOSTNAME", &mocks.MockCredentials{}, mockContext.ArmClientOptions)
+		require.NoError(t, err)
+
+		progress := []string{}
+		response, err := client.Deploy(*mockContext.Context, bytes.NewReader(zipContent), &OneDeployOptions{
+			RemoteBuild: true,
+			Progress:    func(message string) { progress = append(progress, message) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, PublishStatusSuccess, response.Status)
+
+		require.Equal(t, string(zipContent), published.body)
+		require.Equal(t, "application/zip", published.contentType)
+		require.Equal(t, "zip", published.query.Get("type"))
+		require.Equal(t, "true", published.query.Get("async"))
+		require.Equal(t, "true", published.query.Get("RemoteBuild"))
+		require.Equal(t, []string{"Uploading package (10 B of 10 B)"}, progress)
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerOneDeployMocks(mockContext, http.StatusNotFound)
+
+		client, err := NewOneDeployClient("HOSTNAME", &mocks.MockCredentials{}, mockContext.ArmClientOptions)
+		require.NoError(t, err)
+
+		_, err = client.Deploy(*mockContext.Context, bytes.NewReader(zipContent), nil)
+		require.ErrorIs(t, err, ErrOneDeployNotSupported)
+	})
+}
+
+func TestUploadProgressReader(t *testing.T) {
+	content := bytes.Repeat([]byte("a"), oneDeployProgressInterval*2+1024*1024)
+
+	progress := []string{}
+	reader := &uploadProgressReader{
+		ReadSeeker: bytes.NewReader(content),
+		size:       int64(len(content)),
+		progress:   func(message string) { progress = append(progress, message) },
+	}
+
+	_, err := io.Copy(io.Discard, reader)
+	require.NoError(t, err)
+
+	// Reading the package again, like when the request is retried, reports the progress again
+	_, err = reader.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	_, err = io.Copy(io.Discard, reader)
+	require.NoError(t, err)
+
+	expected := []string{
+		"Uploading package (4.0 MB of 9.0 MB)",
+		"Uploading package (8.0 MB of 9.0 MB)",
+		"Uploading package (9.0 MB of 9.0 MB)",
+	}
+	require.Equal(t, append(expected, expected...), progress)
+}
+
+// oneDeployRequests records the publish request of a OneDeploy deployment
+type oneDeployRequests struct {
+	body        string
+	contentType string
+	query       url.Values
+}
+
+// registerOneDeployMocks mocks the host, which answers the publish request with the status code.
+func registerOneDeployMocks(mockContext *mocks.MockContext, publishStatusCode int) *oneDeployRequests {
+	requests := &oneDeployRequests{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/api/publish")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if publishStatusCode != http.StatusAccepted {
+			return mocks.CreateEmptyHttpResponse(request, publishStatusCode)
+		}
+
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		requests.body = string(body)
+		requests.contentType = request.Header.Get("Content-Type")
+		requests.query = request.URL.Query()
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "DEPLOYMENT_ID")
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/api/deployments/DEPLOYMENT_ID")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, PublishResponse{
+			Id:     "DEPLOYMENT_ID",
+			Status: PublishStatusSuccess,
+		})
+	})
+
+	return requests
+}
@@ -91,6 +91,22 @@ func KubernetesServiceRID(subscriptionId, resourceGroupName, clusterName string)
 	)
 }
 
+func ContainerGroupRID(subscriptionId, resourceGroupName, containerGroupName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.ContainerInstance/containerGroups/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		containerGroupName,
+	)
+}
+
+func StorageAccountRID(subscriptionId, resourceGroupName, accountName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.Storage/storageAccounts/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		accountName,
+	)
+}
+
 func StaticWebAppRID(subscriptionId, resourceGroupName, staticSiteName string) string {
 	returnValue := fmt.Sprintf(
 		"%s/providers/Microsoft.Web/staticSites/%s",
@@ -16,4 +16,16 @@ const (
 	// TagKeyAzdServiceName is the name of the key in the tags map of a resource
 	// used to store the azd service a resource is associated with.
 	TagKeyAzdServiceName = "azd-service-name"
+	// TagKeyAzdInitiator is the name of the key in the tags map of a deployment
+	// used to store the principal that started the deployment.
+	TagKeyAzdInitiator = "azd-initiator"
+	// TagKeyAzdGitSha is the name of the key in the tags map of a deployment
+	// used to store the source control commit that was deployed.
+	TagKeyAzdGitSha = "azd-git-sha"
+	// TagKeyAzdTemplate is the name of the key in the tags map of a resource
+	// used to store the template the resource was deployed from.
+	TagKeyAzdTemplate = "azd-template"
+	// TagKeyAzdRepoUrl is the name of the key in the tags map of a resource
+	// used to store the source control repository the resource was deployed from.
+	TagKeyAzdRepoUrl = "azd-repo-url"
 )
@@ -15,6 +15,12 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 )
 
+var locationPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "LOCATION",
+	Description: "The name of the Azure location to use, ex) eastus.",
+	Commands:    []string{"provision", "up", "deploy", "env new", "init"},
+})
+
 // PromptLocation asks the user to select a location from a list of supported azure locations for a given subscription.
 // shouldDisplay, when non-nil, filters the location being displayed.
 func PromptLocationWithFilter(
@@ -80,6 +86,7 @@ func PromptLocationWithFilter(
 	}
 
 	selectedIndex, err := console.Select(ctx, input.ConsoleOptions{
+		Id:           locationPromptId,
 		Message:      message,
 		Help:         help,
 		Options:      locationOptions,
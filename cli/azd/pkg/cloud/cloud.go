@@ -36,6 +36,10 @@ type Cloud struct {
 	ContainerRegistryEndpointSuffix string
 
 	KeyVaultEndpointSuffix string
+
+	// The audience of the federated tokens which are exchanged for Microsoft Entra tokens in the cloud (e.g.
+	// api://AzureADTokenExchange for Azure public cloud).
+	FederatedTokenAudience string
 }
 
 type Config struct {
@@ -72,6 +76,7 @@ func AzurePublic() *Cloud {
 		StorageEndpointSuffix:           "core.windows.net",
 		ContainerRegistryEndpointSuffix: "azurecr.io",
 		KeyVaultEndpointSuffix:          "vault.azure.net",
+		FederatedTokenAudience:          "api://AzureADTokenExchange",
 	}
 }
 
@@ -82,6 +87,7 @@ func AzureGovernment() *Cloud {
 		StorageEndpointSuffix:           "core.usgovcloudapi.net",
 		ContainerRegistryEndpointSuffix: "azurecr.us",
 		KeyVaultEndpointSuffix:          "vault.usgovcloudapi.net",
+		FederatedTokenAudience:          "api://AzureADTokenExchangeUSGov",
 	}
 }
 
@@ -92,6 +98,7 @@ func AzureChina() *Cloud {
 		StorageEndpointSuffix:           "core.chinacloudapi.cn",
 		ContainerRegistryEndpointSuffix: "azurecr.cn",
 		KeyVaultEndpointSuffix:          "vault.azure.cn",
+		FederatedTokenAudience:          "api://AzureADTokenExchangeChina",
 	}
 }
 
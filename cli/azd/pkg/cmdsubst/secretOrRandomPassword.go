@@ -12,6 +12,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/password"
 )
 
@@ -42,6 +43,7 @@ func (e *SecretOrRandomPasswordCommandExecutor) Run(
 	generatePassword := func() (bool, string, error) {
 		substitute, err := password.Generate(
 			password.GenerateConfig{MinLower: to.Ptr[uint](5), MinUpper: to.Ptr[uint](5), MinNumeric: to.Ptr[uint](5)})
+		output.AddSecret(substitute)
 		return err == nil, substitute, err
 	}
 
@@ -78,5 +80,6 @@ func (e *SecretOrRandomPasswordCommandExecutor) Run(
 		return generatePassword() // Do not use empty password secret even if the secret exists
 	}
 
+	output.AddSecret(secret.Value)
 	return true, secret.Value, nil
 }
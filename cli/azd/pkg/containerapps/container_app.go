@@ -8,9 +8,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -57,7 +60,8 @@ type ContainerAppService interface {
 		containerAppYaml []byte,
 		options *ContainerAppOptions,
 	) error
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app. Returns the changes of the secrets of the
+	// environment variables of the options.
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
@@ -65,7 +69,24 @@ type ContainerAppService interface {
 		appName string,
 		imageName string,
 		options *ContainerAppOptions,
-	) error
+	) ([]SecretChange, error)
+	// Sends all the traffic of the specified container app to its latest or previous revision
+	ShiftTraffic(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		target TrafficTarget,
+		options *ContainerAppOptions,
+	) ([]*RevisionTraffic, error)
+	// Streams the console logs of the latest ready revision of the specified container app, following new logs until
+	// ctx is canceled. The caller must close the returned reader.
+	StreamLogs(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+	) (io.ReadCloser, error)
 }
 
 // NewContainerAppService creates a new ContainerAppService
@@ -92,6 +113,60 @@ type containerAppService struct {
 
 type ContainerAppOptions struct {
 	ApiVersion string
+	// The suffix of the new revision. Defaults to a timestamp based suffix.
+	RevisionSuffix string
+	// When set activates multiple revision mode on the container app
+	MultipleRevisions bool
+	// The percentage of traffic sent to the new revision in multiple revision mode. The remaining traffic is sent to the
+	// previous revision. Defaults to 100.
+	LatestRevisionWeight *int32
+	// Environment variables set on the first container of the new revision, replacing the variables with the same name
+	Env map[string]string
+	// Environment variables set on the first container of the new revision from secrets of the container app. The name
+	// of the secret of a variable is its name in lower case, with '-' in place of '_'.
+	SecretEnv map[string]string
+	// Environment variables set on the first container of the new revision from secrets of the container app referencing
+	// Key Vault secrets. The secrets are named like the ones of SecretEnv.
+	KeyVaultSecretEnv map[string]KeyVaultSecretReference
+}
+
+// KeyVaultSecretReference is a Key Vault secret read by a container app with one of its managed identities
+type KeyVaultSecretReference struct {
+	// The URL of the secret, ex) https://vault.vault.azure.net/secrets/db-password
+	Url string
+	// The resource id of the user assigned identity reading the secret, or 'system' for the system assigned identity
+	Identity string
+}
+
+// SecretChangeKind is how a secret of a container app changes
+type SecretChangeKind string
+
+const (
+	SecretAdded   SecretChangeKind = "added"
+	SecretUpdated SecretChangeKind = "updated"
+)
+
+// SecretChange is a change of a secret of a container app. The value isn't kept.
+type SecretChange struct {
+	Name string           `json:"name"`
+	Kind SecretChangeKind `json:"kind"`
+}
+
+// TrafficTarget is the revision traffic is shifted to
+type TrafficTarget string
+
+const (
+	// The latest revision of the container app
+	TrafficTargetLatest TrafficTarget = "latest"
+	// The revision created before the latest revision of the container app
+	TrafficTargetPrevious TrafficTarget = "previous"
+)
+
+// RevisionTraffic is the percentage of the traffic of a container app sent to one of its revisions
+type RevisionTraffic struct {
+	RevisionName string `json:"revisionName"`
+	Weight       int32  `json:"weight"`
+	Latest       bool   `json:"latest"`
 }
 
 type ContainerAppIngressConfiguration struct {
@@ -276,94 +351,248 @@ func (cas *containerAppService) AddRevision(
 	appName string,
 	imageName string,
 	options *ContainerAppOptions,
-) error {
+) ([]SecretChange, error) {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
 	if err != nil {
-		return fmt.Errorf("getting container app: %w", err)
+		return nil, fmt.Errorf("getting container app: %w", err)
 	}
 
 	// Get the latest revision name
 	currentRevisionName, has := containerApp.GetString(pathLatestRevisionName)
 	if !has {
-		return fmt.Errorf("getting latest revision name: %w", err)
+		return nil, fmt.Errorf("getting latest revision name: %w", err)
 	}
 
 	apiVersionPolicy := createApiVersionPolicy(options)
 	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId, apiVersionPolicy)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var revisionResponse *http.Response
 	ctx = policy.WithCaptureResponse(ctx, &revisionResponse)
 
 	if _, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, currentRevisionName, nil); err != nil {
-		return fmt.Errorf("getting revision '%s': %w", currentRevisionName, err)
+		return nil, fmt.Errorf("getting revision '%s': %w", currentRevisionName, err)
 	}
 
 	var revisionMap map[string]any
 	if err := convert.FromHttpResponse(revisionResponse, &revisionMap); err != nil {
-		return err
+		return nil, err
 	}
 
 	revision := config.NewConfig(revisionMap)
 
+	revisionSuffix := fmt.Sprintf("azd-%d", cas.clock.Now().Unix())
+	if options != nil && options.RevisionSuffix != "" {
+		revisionSuffix = options.RevisionSuffix
+	}
+
 	// Update the revision with the new image name and suffix
-	if err := revision.Set(pathTemplateRevisionSuffix, fmt.Sprintf("azd-%d", cas.clock.Now().Unix())); err != nil {
-		return fmt.Errorf("setting revision suffix: %w", err)
+	if err := revision.Set(pathTemplateRevisionSuffix, revisionSuffix); err != nil {
+		return nil, fmt.Errorf("setting revision suffix: %w", err)
 	}
 
 	var containers []map[string]any
 	if ok, err := revision.GetSection(pathTemplateContainers, &containers); !ok || err != nil {
-		return fmt.Errorf("getting containers: %w", err)
+		return nil, fmt.Errorf("getting containers: %w", err)
 	}
 
 	containers[0]["image"] = imageName
+	if options != nil {
+		setContainerEnv(containers[0], options.Env, options.SecretEnv, options.KeyVaultSecretEnv)
+	}
+
 	if err := revision.Set(pathTemplateContainers, containers); err != nil {
-		return fmt.Errorf("setting containers: %w", err)
+		return nil, fmt.Errorf("setting containers: %w", err)
 	}
 
 	// Update the container app with the new revision
 	revisionTemplate, ok := revision.GetMap(pathTemplate)
 	if !ok {
-		return fmt.Errorf("getting revision template: %w", err)
+		return nil, fmt.Errorf("getting revision template: %w", err)
 	}
 
 	if err := containerApp.Set(pathTemplate, revisionTemplate); err != nil {
-		return fmt.Errorf("setting template: %w", err)
+		return nil, fmt.Errorf("setting template: %w", err)
+	}
+
+	if options != nil && options.MultipleRevisions {
+		err := containerApp.Set(pathConfigurationActiveRevisionsMode, string(armappcontainers.ActiveRevisionsModeMultiple))
+		if err != nil {
+			return nil, fmt.Errorf("setting active revisions mode: %w", err)
+		}
 	}
 
 	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
-		return fmt.Errorf("syncing secrets: %w", err)
+		return nil, fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	var secretChanges []SecretChange
+	if options != nil && (len(options.SecretEnv) > 0 || len(options.KeyVaultSecretEnv) > 0) {
+		secretChanges, err = setSecrets(containerApp, options.SecretEnv, options.KeyVaultSecretEnv)
+		if err != nil {
+			return nil, fmt.Errorf("setting secrets: %w", err)
+		}
 	}
 
 	// Update the container app
 	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp, options)
 	if err != nil {
-		return fmt.Errorf("updating container app revision: %w", err)
+		return nil, fmt.Errorf("updating container app revision: %w", err)
 	}
 
 	revisionMode, ok := containerApp.GetString(pathConfigurationActiveRevisionsMode)
 	if !ok {
-		return fmt.Errorf("getting active revisions mode: %w", err)
+		return nil, fmt.Errorf("getting active revisions mode: %w", err)
 	}
 
 	// If the container app is in multiple revision mode, update the traffic to point to the new revision
 	if revisionMode == string(armappcontainers.ActiveRevisionsModeMultiple) {
-		revisionSuffix, ok := revision.GetString(pathTemplateRevisionSuffix)
-		if !ok {
-			return fmt.Errorf("getting revision suffix: %w", err)
-		}
 		newRevisionName := fmt.Sprintf("%s--%s", appName, revisionSuffix)
 
-		err = cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, newRevisionName, options)
+		latestWeight := int32(100)
+		if options != nil && options.LatestRevisionWeight != nil {
+			latestWeight = *options.LatestRevisionWeight
+		}
+
+		if latestWeight < 0 || latestWeight > 100 {
+			return nil, fmt.Errorf("latest revision weight must be between 0 and 100, got %d", latestWeight)
+		}
+
+		trafficWeights := []*armappcontainers.TrafficWeight{
+			{
+				RevisionName: &newRevisionName,
+				Weight:       &latestWeight,
+			},
+		}
+
+		// The remaining traffic stays on the revision that was the latest before this deployment
+		if latestWeight < 100 {
+			trafficWeights = append(trafficWeights, &armappcontainers.TrafficWeight{
+				RevisionName: &currentRevisionName,
+				Weight:       to.Ptr(100 - latestWeight),
+			})
+		}
+
+		err = cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, trafficWeights, options)
 		if err != nil {
-			return fmt.Errorf("setting traffic weights: %w", err)
+			return nil, fmt.Errorf("setting traffic weights: %w", err)
 		}
 	}
 
-	return nil
+	return secretChanges, nil
+}
+
+// Sends all the traffic of the specified container app to its latest or previous revision
+func (cas *containerAppService) ShiftTraffic(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	target TrafficTarget,
+	options *ContainerAppOptions,
+) ([]*RevisionTraffic, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName, options)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	revisionMode, _ := containerApp.GetString(pathConfigurationActiveRevisionsMode)
+	if revisionMode != string(armappcontainers.ActiveRevisionsModeMultiple) {
+		return nil, fmt.Errorf(
+			"container app '%s' is not in multiple revision mode, traffic can't be shifted between revisions", appName)
+	}
+
+	latestRevisionName, has := containerApp.GetString(pathLatestRevisionName)
+	if !has {
+		return nil, fmt.Errorf("getting latest revision name for container app '%s'", appName)
+	}
+
+	targetRevisionName := latestRevisionName
+	switch target {
+	case TrafficTargetLatest:
+	case TrafficTargetPrevious:
+		targetRevisionName, err = cas.previousRevisionName(
+			ctx, subscriptionId, resourceGroupName, appName, latestRevisionName, options)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported traffic target '%s'", target)
+	}
+
+	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return nil, fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	trafficWeights := []*armappcontainers.TrafficWeight{
+		{
+			RevisionName: &targetRevisionName,
+			Weight:       to.Ptr[int32](100),
+		},
+	}
+
+	err = cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, trafficWeights, options)
+	if err != nil {
+		return nil, fmt.Errorf("setting traffic weights: %w", err)
+	}
+
+	return []*RevisionTraffic{
+		{
+			RevisionName: targetRevisionName,
+			Weight:       100,
+			Latest:       targetRevisionName == latestRevisionName,
+		},
+	}, nil
+}
+
+// previousRevisionName gets the most recently created active revision of the container app other than its latest
+// revision.
+func (cas *containerAppService) previousRevisionName(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	latestRevisionName string,
+	options *ContainerAppOptions,
+) (string, error) {
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId, createApiVersionPolicy(options))
+	if err != nil {
+		return "", err
+	}
+
+	var previous *armappcontainers.Revision
+	pager := revisionsClient.NewListRevisionsPager(resourceGroupName, appName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("listing revisions: %w", err)
+		}
+
+		for _, revision := range page.Value {
+			if revision.Name == nil || *revision.Name == latestRevisionName ||
+				revision.Properties == nil || revision.Properties.CreatedTime == nil {
+				continue
+			}
+
+			if revision.Properties.Active == nil || !*revision.Properties.Active {
+				continue
+			}
+
+			if previous == nil || revision.Properties.CreatedTime.After(*previous.Properties.CreatedTime) {
+				previous = revision
+			}
+		}
+	}
+
+	if previous == nil {
+		return "", fmt.Errorf("container app '%s' doesn't have an active previous revision to roll back to", appName)
+	}
+
+	return *previous.Name, nil
 }
 
 func (cas *containerAppService) syncSecrets(
@@ -406,22 +635,135 @@ func (cas *containerAppService) syncSecrets(
 	return containerApp, nil
 }
 
+// secretName returns the name of the container app secret of an environment variable
+func secretName(envName string) string {
+	return strings.ReplaceAll(strings.ToLower(envName), "_", "-")
+}
+
+// setContainerEnv sets the environment variables of the container, replacing the variables with the same name.
+func setContainerEnv(
+	container map[string]any,
+	env map[string]string,
+	secretEnv map[string]string,
+	keyVaultSecretEnv map[string]KeyVaultSecretReference,
+) {
+	if len(env) == 0 && len(secretEnv) == 0 && len(keyVaultSecretEnv) == 0 {
+		return
+	}
+
+	values := map[string]map[string]any{}
+	for name, value := range env {
+		values[name] = map[string]any{"name": name, "value": value}
+	}
+	for name := range secretEnv {
+		values[name] = map[string]any{"name": name, "secretRef": secretName(name)}
+	}
+	for name := range keyVaultSecretEnv {
+		values[name] = map[string]any{"name": name, "secretRef": secretName(name)}
+	}
+
+	containerEnv := []any{}
+	if existing, ok := container["env"].([]any); ok {
+		for _, item := range existing {
+			if envVar, ok := item.(map[string]any); ok {
+				if name, ok := envVar["name"].(string); ok && values[name] != nil {
+					continue
+				}
+			}
+
+			containerEnv = append(containerEnv, item)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		containerEnv = append(containerEnv, values[name])
+	}
+
+	container["env"] = containerEnv
+}
+
+// setSecrets sets the secrets of the environment variables on the container app, replacing the secrets with the same
+// name, and returns the secrets added or updated. The existing secrets must have been synced with their values.
+func setSecrets(
+	containerApp config.Config,
+	secretEnv map[string]string,
+	keyVaultSecretEnv map[string]KeyVaultSecretReference,
+) ([]SecretChange, error) {
+	values := map[string]map[string]any{}
+	for envName, value := range secretEnv {
+		name := secretName(envName)
+		values[name] = map[string]any{"name": name, "value": value}
+	}
+	for envName, reference := range keyVaultSecretEnv {
+		name := secretName(envName)
+		values[name] = map[string]any{"name": name, "keyVaultUrl": reference.Url, "identity": reference.Identity}
+	}
+
+	existingSecrets := map[string]map[string]any{}
+	secrets := []any{}
+	if existing, ok := containerApp.GetSlice(pathConfigurationSecrets); ok {
+		for _, item := range existing {
+			if secret, ok := item.(map[string]any); ok {
+				name := fmt.Sprint(secret["name"])
+				if _, replaced := values[name]; replaced {
+					existingSecrets[name] = secret
+					continue
+				}
+			}
+
+			secrets = append(secrets, item)
+		}
+	}
+
+	changes := []SecretChange{}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		secrets = append(secrets, values[name])
+
+		existing, has := existingSecrets[name]
+		switch {
+		case !has:
+			changes = append(changes, SecretChange{Name: name, Kind: SecretAdded})
+		case !secretEqual(existing, values[name]):
+			changes = append(changes, SecretChange{Name: name, Kind: SecretUpdated})
+		}
+	}
+
+	if err := containerApp.Set(pathConfigurationSecrets, secrets); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// secretEqual returns whether an existing secret has the value, or references the Key Vault secret, of a desired secret.
+// Only the properties of the desired secret are compared, since the value of a Key Vault reference is returned too.
+func secretEqual(existing map[string]any, desired map[string]any) bool {
+	for key, value := range desired {
+		existingValue, _ := existing[key].(string)
+		desiredValue, _ := value.(string)
+
+		// Identities are resource ids, which casing isn't preserved by ARM
+		if key == "identity" && strings.EqualFold(existingValue, desiredValue) {
+			continue
+		}
+
+		if existingValue != desiredValue {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (cas *containerAppService) setTrafficWeights(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	appName string,
 	containerApp config.Config,
-	revisionName string,
+	trafficWeights []*armappcontainers.TrafficWeight,
 	options *ContainerAppOptions,
 ) error {
-	trafficWeights := []*armappcontainers.TrafficWeight{
-		{
-			RevisionName: &revisionName,
-			Weight:       to.Ptr[int32](100),
-		},
-	}
-
 	trafficWeightsJson, err := convert.ToJsonArray(trafficWeights)
 	if err != nil {
 		return fmt.Errorf("converting traffic weights to JSON: %w", err)
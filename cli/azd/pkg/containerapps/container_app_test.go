@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
@@ -141,7 +142,7 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 		mockContext.ArmClientOptions,
 		mockContext.AlphaFeaturesManager,
 	)
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil)
+	_, err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil)
 	require.NoError(t, err)
 
 	// Verify lastest revision is read
@@ -164,6 +165,131 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	require.Equal(t, "azd-0", *updatedContainerApp.Properties.Template.RevisionSuffix)
 }
 
+func Test_ContainerApp_AddRevision_Env(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	originalRevisionName := "ORIGINAL_REVISION_NAME"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &originalRevisionName,
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+				Secrets: []*armappcontainers.Secret{
+					{
+						Name: to.Ptr("secret"),
+					},
+				},
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ORIGINAL_IMAGE_NAME"),
+					},
+				},
+			},
+		},
+	}
+
+	revision := &armappcontainers.Revision{
+		Properties: &armappcontainers.RevisionProperties{
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ORIGINAL_IMAGE_NAME"),
+						Env: []*armappcontainers.EnvironmentVar{
+							{Name: to.Ptr("PORT"), Value: to.Ptr("8080")},
+							{Name: to.Ptr("STORAGE_BLOB_ENDPOINT"), Value: to.Ptr("https://old.blob.core.windows.net/")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	secrets := &armappcontainers.SecretsCollection{
+		Value: []*armappcontainers.ContainerAppSecret{
+			{
+				Name:  to.Ptr("secret"),
+				Value: to.Ptr("value"),
+			},
+			{
+				Name:  to.Ptr("api-key"),
+				Value: to.Ptr("key"),
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppRevisionGet(
+		mockContext, subscriptionId, resourceGroup, appName, originalRevisionName, revision)
+	_ = mockazsdk.MockContainerAppSecretsList(mockContext, subscriptionId, resourceGroup, appName, secrets)
+	updateContainerAppRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext, subscriptionId, resourceGroup, appName, containerApp)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	secretChanges, err := cas.AddRevision(
+		*mockContext.Context, subscriptionId, resourceGroup, appName, "UPDATED_IMAGE_NAME", &ContainerAppOptions{
+			Env: map[string]string{
+				"STORAGE_BLOB_ENDPOINT": "https://new.blob.core.windows.net/",
+			},
+			SecretEnv: map[string]string{
+				"API_KEY":        "key",
+				"REDIS_PASSWORD": "password",
+			},
+			KeyVaultSecretEnv: map[string]KeyVaultSecretReference{
+				"DB_PASSWORD": {Url: "https://vault.vault.azure.net/secrets/db-password", Identity: "system"},
+			},
+		})
+	require.NoError(t, err)
+
+	// The secret with the same value isn't changed
+	require.Equal(t, []SecretChange{
+		{Name: "db-password", Kind: SecretAdded},
+		{Name: "redis-password", Kind: SecretAdded},
+	}, secretChanges)
+
+	var updatedContainerApp *armappcontainers.ContainerApp
+	err = json.NewDecoder(updateContainerAppRequest.Body).Decode(&updatedContainerApp)
+	require.NoError(t, err)
+
+	// The variables of the options replace the variables with the same name
+	env := updatedContainerApp.Properties.Template.Containers[0].Env
+	require.Len(t, env, 5)
+	require.Equal(t, "PORT", *env[0].Name)
+	require.Equal(t, "8080", *env[0].Value)
+	require.Equal(t, "API_KEY", *env[1].Name)
+	require.Equal(t, "api-key", *env[1].SecretRef)
+	require.Equal(t, "DB_PASSWORD", *env[2].Name)
+	require.Equal(t, "db-password", *env[2].SecretRef)
+	require.Equal(t, "REDIS_PASSWORD", *env[3].Name)
+	require.Equal(t, "redis-password", *env[3].SecretRef)
+	require.Nil(t, env[3].Value)
+	require.Equal(t, "STORAGE_BLOB_ENDPOINT", *env[4].Name)
+	require.Equal(t, "https://new.blob.core.windows.net/", *env[4].Value)
+
+	appSecrets := updatedContainerApp.Properties.Configuration.Secrets
+	require.Len(t, appSecrets, 4)
+	require.Equal(t, "secret", *appSecrets[0].Name)
+	require.Equal(t, "api-key", *appSecrets[1].Name)
+	require.Equal(t, "db-password", *appSecrets[2].Name)
+	require.Equal(t, "https://vault.vault.azure.net/secrets/db-password", *appSecrets[2].KeyVaultURL)
+	require.Equal(t, "system", *appSecrets[2].Identity)
+	require.Nil(t, appSecrets[2].Value)
+	require.Equal(t, "redis-password", *appSecrets[3].Name)
+	require.Equal(t, "password", *appSecrets[3].Value)
+}
+
 func Test_ContainerApp_DeployYaml(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 
@@ -252,3 +378,229 @@ properties:
 	require.Equal(t, expected.Properties.Configuration, actual.Properties.Configuration)
 	require.Equal(t, expected.Properties.Template, actual.Properties.Template)
 }
+
+func Test_ContainerApp_AddRevision_MultipleRevisions(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	originalRevisionName := "APP_NAME--v1"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Location: &location,
+		Name:     &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &originalRevisionName,
+			Configuration: &armappcontainers.Configuration{
+				ActiveRevisionsMode: to.Ptr(armappcontainers.ActiveRevisionsModeSingle),
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ORIGINAL_IMAGE_NAME"),
+					},
+				},
+			},
+		},
+	}
+
+	revision := &armappcontainers.Revision{
+		Properties: &armappcontainers.RevisionProperties{
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Image: to.Ptr("ORIGINAL_IMAGE_NAME"),
+					},
+				},
+			},
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	_ = mockazsdk.MockContainerAppRevisionGet(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		originalRevisionName,
+		revision,
+	)
+	updateContainerAppRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		containerApp,
+	)
+
+	cas := NewContainerAppService(
+		mockContext.SubscriptionCredentialProvider,
+		clock.NewMock(),
+		mockContext.ArmClientOptions,
+		mockContext.AlphaFeaturesManager,
+	)
+	_, err := cas.AddRevision(
+		*mockContext.Context,
+		subscriptionId,
+		resourceGroup,
+		appName,
+		"UPDATED_IMAGE_NAME",
+		&ContainerAppOptions{
+			RevisionSuffix:       "v2",
+			MultipleRevisions:    true,
+			LatestRevisionWeight: to.Ptr[int32](20),
+		},
+	)
+	require.NoError(t, err)
+
+	// The last update sets the traffic weights
+	var updatedContainerApp *armappcontainers.ContainerApp
+	err = mocks.ReadHttpBody(updateContainerAppRequest.Body, &updatedContainerApp)
+	require.NoError(t, err)
+
+	require.Equal(t, "v2", *updatedContainerApp.Properties.Template.RevisionSuffix)
+	require.Equal(
+		t,
+		armappcontainers.ActiveRevisionsModeMultiple,
+		*updatedContainerApp.Properties.Configuration.ActiveRevisionsMode,
+	)
+	require.Equal(t, []*armappcontainers.TrafficWeight{
+		{
+			RevisionName: to.Ptr("APP_NAME--v2"),
+			Weight:       to.Ptr[int32](20),
+		},
+		{
+			RevisionName: to.Ptr(originalRevisionName),
+			Weight:       to.Ptr[int32](80),
+		},
+	}, updatedContainerApp.Properties.Configuration.Ingress.Traffic)
+}
+
+func Test_ContainerApp_ShiftTraffic(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	location := "eastus2"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	latestRevisionName := "APP_NAME--v3"
+
+	revisions := []*armappcontainers.Revision{
+		{
+			Name: to.Ptr("APP_NAME--v1"),
+			Properties: &armappcontainers.RevisionProperties{
+				Active:      to.Ptr(true),
+				CreatedTime: to.Ptr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			Name: to.Ptr(latestRevisionName),
+			Properties: &armappcontainers.RevisionProperties{
+				Active:      to.Ptr(true),
+				CreatedTime: to.Ptr(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			Name: to.Ptr("APP_NAME--v2"),
+			Properties: &armappcontainers.RevisionProperties{
+				Active:      to.Ptr(true),
+				CreatedTime: to.Ptr(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			Name: to.Ptr("APP_NAME--inactive"),
+			Properties: &armappcontainers.RevisionProperties{
+				Active:      to.Ptr(false),
+				CreatedTime: to.Ptr(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)),
+			},
+		},
+	}
+
+	newContainerApp := func(mode armappcontainers.ActiveRevisionsMode) *armappcontainers.ContainerApp {
+		return &armappcontainers.ContainerApp{
+			Location: &location,
+			Name:     &appName,
+			Properties: &armappcontainers.ContainerAppProperties{
+				LatestRevisionName: &latestRevisionName,
+				Configuration: &armappcontainers.Configuration{
+					ActiveRevisionsMode: to.Ptr(mode),
+					Ingress: &armappcontainers.Ingress{
+						Traffic: []*armappcontainers.TrafficWeight{
+							{RevisionName: to.Ptr(latestRevisionName), Weight: to.Ptr[int32](20)},
+							{RevisionName: to.Ptr("APP_NAME--v2"), Weight: to.Ptr[int32](80)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		target           TrafficTarget
+		expectedRevision string
+	}{
+		{name: "Promote", target: TrafficTargetLatest, expectedRevision: latestRevisionName},
+		{name: "Rollback", target: TrafficTargetPrevious, expectedRevision: "APP_NAME--v2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			containerApp := newContainerApp(armappcontainers.ActiveRevisionsModeMultiple)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+			_ = mockazsdk.MockContainerAppRevisionsList(mockContext, subscriptionId, resourceGroup, appName, revisions)
+			updateContainerAppRequest := mockazsdk.MockContainerAppUpdate(
+				mockContext,
+				subscriptionId,
+				resourceGroup,
+				appName,
+				containerApp,
+			)
+
+			cas := NewContainerAppService(
+				mockContext.SubscriptionCredentialProvider,
+				clock.NewMock(),
+				mockContext.ArmClientOptions,
+				mockContext.AlphaFeaturesManager,
+			)
+			traffic, err := cas.ShiftTraffic(
+				*mockContext.Context, subscriptionId, resourceGroup, appName, test.target, nil)
+			require.NoError(t, err)
+			require.Equal(t, []*RevisionTraffic{
+				{
+					RevisionName: test.expectedRevision,
+					Weight:       100,
+					Latest:       test.expectedRevision == latestRevisionName,
+				},
+			}, traffic)
+
+			var updatedContainerApp *armappcontainers.ContainerApp
+			err = mocks.ReadHttpBody(updateContainerAppRequest.Body, &updatedContainerApp)
+			require.NoError(t, err)
+			require.Equal(t, []*armappcontainers.TrafficWeight{
+				{
+					RevisionName: to.Ptr(test.expectedRevision),
+					Weight:       to.Ptr[int32](100),
+				},
+			}, updatedContainerApp.Properties.Configuration.Ingress.Traffic)
+		})
+	}
+
+	t.Run("SingleRevisionMode", func(t *testing.T) {
+		containerApp := newContainerApp(armappcontainers.ActiveRevisionsModeSingle)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		_ = mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+
+		cas := NewContainerAppService(
+			mockContext.SubscriptionCredentialProvider,
+			clock.NewMock(),
+			mockContext.ArmClientOptions,
+			mockContext.AlphaFeaturesManager,
+		)
+		_, err := cas.ShiftTraffic(
+			*mockContext.Context, subscriptionId, resourceGroup, appName, TrafficTargetPrevious, nil)
+		require.ErrorContains(t, err, "is not in multiple revision mode")
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerapps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+)
+
+// The number of past log lines sent before following new logs
+const logStreamTailLines = "100"
+
+// Streams the console logs of the latest ready revision of the specified container app, following new logs until
+// ctx is canceled. The logs of the first container of the first replica of the revision are streamed.
+func (cas *containerAppService) StreamLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (io.ReadCloser, error) {
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	containerApp, err := appClient.Get(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	properties := containerApp.Properties
+	if properties == nil || properties.EventStreamEndpoint == nil || properties.LatestReadyRevisionName == nil {
+		return nil, fmt.Errorf("container app '%s' does not have a running revision", appName)
+	}
+
+	if properties.Template == nil || len(properties.Template.Containers) == 0 ||
+		properties.Template.Containers[0].Name == nil {
+		return nil, fmt.Errorf("container app '%s' does not have any container", appName)
+	}
+
+	revisionName := *properties.LatestReadyRevisionName
+	containerName := *properties.Template.Containers[0].Name
+
+	credential, err := cas.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	replicasClient, err := armappcontainers.NewContainerAppsRevisionReplicasClient(
+		subscriptionId, credential, cas.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating ContainerApps replicas client: %w", err)
+	}
+
+	replicas, err := replicasClient.ListReplicas(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing replicas of revision '%s': %w", revisionName, err)
+	}
+
+	if len(replicas.Value) == 0 || replicas.Value[0].Name == nil {
+		return nil, fmt.Errorf("revision '%s' of container app '%s' does not have any replica", revisionName, appName)
+	}
+
+	// The log stream is served by the endpoint of the environment, with a token of the container app
+	token, err := appClient.GetAuthToken(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting auth token of container app: %w", err)
+	}
+
+	if token.Properties == nil || token.Properties.Token == nil {
+		return nil, fmt.Errorf("getting auth token of container app: empty token")
+	}
+
+	eventStreamEndpoint := *properties.EventStreamEndpoint
+	baseIndex := strings.Index(eventStreamEndpoint, "/subscriptions/")
+	if baseIndex < 0 {
+		return nil, fmt.Errorf("invalid event stream endpoint '%s'", eventStreamEndpoint)
+	}
+
+	logStreamUrl := runtime.JoinPaths(
+		eventStreamEndpoint[:baseIndex],
+		"subscriptions", subscriptionId,
+		"resourceGroups", resourceGroupName,
+		"containerApps", appName,
+		"revisions", revisionName,
+		"replicas", *replicas.Value[0].Name,
+		"containers", containerName,
+		"logstream",
+	)
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, logStreamUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.Raw().URL.Query()
+	query.Set("follow", "true")
+	query.Set("output", "text")
+	query.Set("tailLines", logStreamTailLines)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header.Set("Authorization", "Bearer "+*token.Properties.Token)
+
+	// The log stream never ends, the body is read as the logs are written
+	runtime.SkipBodyDownload(req)
+
+	pipeline := runtime.NewPipeline(
+		"azd-log-stream", "1.0.0", runtime.PipelineOptions{}, &cas.armClientOptions.ClientOptions)
+	res, err := pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("streaming logs of container app '%s': %w", appName, err)
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		defer res.Body.Close()
+		return nil, fmt.Errorf("streaming logs of container app '%s': %w", appName, runtime.NewResponseError(res))
+	}
+
+	return res.Body, nil
+}
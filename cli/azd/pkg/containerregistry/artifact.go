@@ -0,0 +1,390 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// ArtifactScheme prefixes the references of artifacts stored in an OCI registry,
+// ex) oci://myregistry.azurecr.io/todo/api:v1
+const ArtifactScheme = "oci://"
+
+const (
+	// The media type of OCI image manifests
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// The media type of the empty config of artifacts which aren't container images
+	EmptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+
+	// The standard annotations of OCI artifacts,
+	// https://github.com/opencontainers/image-spec/blob/main/annotations.md
+	AnnotationTitle    = "org.opencontainers.image.title"
+	AnnotationVersion  = "org.opencontainers.image.version"
+	AnnotationCreated  = "org.opencontainers.image.created"
+	AnnotationRevision = "org.opencontainers.image.revision"
+)
+
+// The manifest media types accepted when reading manifests, which includes the ones of container images
+var acceptedManifestMediaTypes = []string{
+	ManifestMediaType,
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// The empty config, '{}', of artifacts which aren't container images
+var emptyConfig = []byte("{}")
+
+// ArtifactReference references an artifact of a repository of an OCI registry, by tag or digest.
+type ArtifactReference struct {
+	// The login server of the registry, ex) myregistry.azurecr.io
+	Registry   string
+	Repository string
+	// The tag, or the digest, of the artifact. Empty when not specified.
+	Reference string
+}
+
+// ParseArtifactReference parses a reference like oci://myregistry.azurecr.io/todo/api:v1, where the oci:// scheme
+// is optional.
+func ParseArtifactReference(value string) (*ArtifactReference, error) {
+	registry, path, _ := strings.Cut(strings.TrimPrefix(value, ArtifactScheme), "/")
+	if registry == "" || path == "" {
+		return nil, fmt.Errorf(
+			"invalid artifact reference '%s', expected %s<registry>/<repository>[:<tag>]", value, ArtifactScheme)
+	}
+
+	repository := path
+	reference := ""
+	if i := strings.Index(path, "@"); i >= 0 {
+		repository, reference = path[:i], path[i+1:]
+	} else if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		repository, reference = path[:i], path[i+1:]
+	}
+
+	if repository == "" || strings.ToLower(repository) != repository {
+		return nil, fmt.Errorf("invalid repository '%s' of artifact reference '%s', it must be lower case", repository, value)
+	}
+
+	return &ArtifactReference{
+		Registry:   registry,
+		Repository: repository,
+		Reference:  reference,
+	}, nil
+}
+
+// Image returns the reference without the oci:// scheme, ex) myregistry.azurecr.io/todo/api:v1
+func (r *ArtifactReference) Image() string {
+	image := fmt.Sprintf("%s/%s", r.Registry, r.Repository)
+	switch {
+	case r.Reference == "":
+		return image
+	case strings.Contains(r.Reference, ":"):
+		return fmt.Sprintf("%s@%s", image, r.Reference)
+	default:
+		return fmt.Sprintf("%s:%s", image, r.Reference)
+	}
+}
+
+// String returns the reference with the oci:// scheme
+func (r *ArtifactReference) String() string {
+	return ArtifactScheme + r.Image()
+}
+
+// ArtifactDescriptor describes the content of an artifact, like its config or a layer.
+type ArtifactDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ArtifactManifest is the manifest of an artifact, https://github.com/opencontainers/image-spec/blob/main/manifest.md
+type ArtifactManifest struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType"`
+	ArtifactType  string               `json:"artifactType,omitempty"`
+	Config        ArtifactDescriptor   `json:"config"`
+	Layers        []ArtifactDescriptor `json:"layers"`
+	Annotations   map[string]string    `json:"annotations,omitempty"`
+}
+
+// ArtifactFile is a file pushed as the single layer of an artifact.
+type ArtifactFile struct {
+	Path      string
+	MediaType string
+	// The type of the artifact, ex) application/vnd.azure.azd.package.v1
+	ArtifactType string
+	// The annotations of the manifest of the artifact
+	Annotations map[string]string
+}
+
+// ArtifactClient pushes and pulls artifacts with the distribution API of an OCI registry,
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md
+type ArtifactClient struct {
+	pipeline    runtime.Pipeline
+	registry    string
+	accessToken string
+}
+
+// NewArtifactClient creates a client of the registry with the given login server, ex) myregistry.azurecr.io,
+// authenticating with an access token scoped to the repositories used.
+func NewArtifactClient(pipeline runtime.Pipeline, registry string, accessToken string) *ArtifactClient {
+	return &ArtifactClient{
+		pipeline:    pipeline,
+		registry:    registry,
+		accessToken: accessToken,
+	}
+}
+
+// PushFile pushes the file as an artifact with the given tag, and returns the digest of its manifest.
+func (c *ArtifactClient) PushFile(
+	ctx context.Context,
+	repository string,
+	tag string,
+	file ArtifactFile,
+) (string, error) {
+	fileDigest, size, err := fileDigest(file.Path)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.Open(file.Path)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	if err := c.uploadBlob(ctx, repository, fileDigest, content); err != nil {
+		return "", fmt.Errorf("uploading '%s': %w", file.Path, err)
+	}
+
+	config := ArtifactDescriptor{
+		MediaType: EmptyConfigMediaType,
+		Digest:    digest(emptyConfig),
+		Size:      int64(len(emptyConfig)),
+	}
+	if err := c.uploadBlob(
+		ctx, repository, config.Digest, streaming.NopCloser(bytes.NewReader(emptyConfig))); err != nil {
+		return "", fmt.Errorf("uploading artifact config: %w", err)
+	}
+
+	manifest, err := json.Marshal(ArtifactManifest{
+		SchemaVersion: 2,
+		MediaType:     ManifestMediaType,
+		ArtifactType:  file.ArtifactType,
+		Config:        config,
+		Layers: []ArtifactDescriptor{
+			{
+				MediaType: file.MediaType,
+				Digest:    fileDigest,
+				Size:      size,
+				Annotations: map[string]string{
+					AnnotationTitle: fileName(file.Path),
+				},
+			},
+		},
+		Annotations: file.Annotations,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPut, fmt.Sprintf("/v2/%s/manifests/%s", repository, tag))
+	if err != nil {
+		return "", err
+	}
+
+	if err := req.SetBody(streaming.NopCloser(bytes.NewReader(manifest)), ManifestMediaType); err != nil {
+		return "", err
+	}
+
+	if _, err := c.do(req, http.StatusCreated); err != nil {
+		return "", fmt.Errorf("pushing manifest of %s%s/%s:%s: %w", ArtifactScheme, c.registry, repository, tag, err)
+	}
+
+	return digest(manifest), nil
+}
+
+// Manifest gets the manifest of the artifact with the given tag or digest.
+func (c *ArtifactClient) Manifest(ctx context.Context, repository string, reference string) (*ArtifactManifest, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/manifests/%s", repository, reference))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Raw().Header.Set("Accept", strings.Join(acceptedManifestMediaTypes, ", "))
+
+	response, err := c.do(req, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("getting manifest of %s/%s:%s: %w", c.registry, repository, reference, err)
+	}
+
+	return httputil.ReadRawResponse[ArtifactManifest](response)
+}
+
+// PullBlob downloads the blob, ex) a layer of an artifact, to the target file.
+func (c *ArtifactClient) PullBlob(
+	ctx context.Context,
+	repository string,
+	blob ArtifactDescriptor,
+	target string,
+) error {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/blobs/%s", repository, blob.Digest))
+	if err != nil {
+		return err
+	}
+
+	runtime.SkipBodyDownload(req)
+
+	response, err := c.do(req, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("downloading blob %s: %w", blob.Digest, err)
+	}
+	defer response.Body.Close()
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), response.Body); err != nil {
+		return fmt.Errorf("downloading blob %s: %w", blob.Digest, err)
+	}
+
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); actual != blob.Digest {
+		return fmt.Errorf("digest %s of the downloaded blob doesn't match the expected %s", actual, blob.Digest)
+	}
+
+	return nil
+}
+
+// uploadBlob uploads the content with a monolithic upload, unless the repository already has a blob with its digest.
+func (c *ArtifactClient) uploadBlob(
+	ctx context.Context,
+	repository string,
+	contentDigest string,
+	content io.ReadSeekCloser,
+) error {
+	req, err := c.newRequest(ctx, http.MethodHead, fmt.Sprintf("/v2/%s/blobs/%s", repository, contentDigest))
+	if err != nil {
+		return err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return err
+	}
+
+	response.Body.Close()
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	req, err = c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/v2/%s/blobs/uploads/", repository))
+	if err != nil {
+		return err
+	}
+
+	response, err = c.do(req, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+
+	base, err := url.Parse(fmt.Sprintf("https://%s/", c.registry))
+	if err != nil {
+		return err
+	}
+
+	location, err := base.Parse(response.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("parsing upload location: %w", err)
+	}
+
+	query := location.Query()
+	query.Set("digest", contentDigest)
+	location.RawQuery = query.Encode()
+
+	req, err = runtime.NewRequest(ctx, http.MethodPut, location.String())
+	if err != nil {
+		return err
+	}
+
+	req.Raw().Header.Set("Authorization", "Bearer "+c.accessToken)
+	if err := req.SetBody(content, "application/octet-stream"); err != nil {
+		return err
+	}
+
+	_, err = c.do(req, http.StatusCreated)
+	return err
+}
+
+func (c *ArtifactClient) newRequest(ctx context.Context, method string, path string) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, fmt.Sprintf("https://%s%s", c.registry, path))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Raw().Header.Set("Authorization", "Bearer "+c.accessToken)
+	return req, nil
+}
+
+func (c *ArtifactClient) do(req *policy.Request, statusCodes ...int) (*http.Response, error) {
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(response, statusCodes...) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	return response, nil
+}
+
+// fileDigest returns the sha256 digest and the size of the file
+func fileDigest(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("hashing '%s': %w", path, err)
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func digest(content []byte) string {
+	hash := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+func fileName(path string) string {
+	return path[strings.LastIndexAny(path, `/\`)+1:]
+}
+
+// ErrNotArtifactFile is returned when an artifact doesn't have the single layer of a file.
+var ErrNotArtifactFile = errors.New("the artifact doesn't contain a single file")
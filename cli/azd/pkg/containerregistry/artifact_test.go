@@ -0,0 +1,174 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseArtifactReference(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ArtifactReference
+	}{
+		{"oci://contoso.azurecr.io/todo", ArtifactReference{"contoso.azurecr.io", "todo", ""}},
+		{"oci://contoso.azurecr.io/todo/api:v1", ArtifactReference{"contoso.azurecr.io", "todo/api", "v1"}},
+		{"localhost:5000/todo:v1", ArtifactReference{"localhost:5000", "todo", "v1"}},
+		{
+			"oci://contoso.azurecr.io/todo@sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			ArtifactReference{
+				"contoso.azurecr.io",
+				"todo",
+				"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			reference, err := ParseArtifactReference(test.value)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, *reference)
+			require.Equal(t, strings.TrimPrefix(test.value, ArtifactScheme), reference.Image())
+		})
+	}
+
+	for _, value := range []string{"oci://contoso.azurecr.io", "oci:///todo", "oci://contoso.azurecr.io/Todo:v1"} {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseArtifactReference(value)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_ArtifactClient_PushFile(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "contoso.azurecr.io"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, "Bearer ACCESS_TOKEN", request.Header.Get("Authorization"))
+
+		path := request.URL.Path
+		switch {
+		case request.Method == http.MethodHead:
+			if _, has := blobs[path[strings.LastIndex(path, "/")+1:]]; has {
+				return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+			}
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		case request.Method == http.MethodPost && path == "/v2/todo/api/blobs/uploads/":
+			response, err := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+			if err != nil {
+				return nil, err
+			}
+			response.Header.Set("Location", "/v2/todo/api/blobs/uploads/UPLOAD?state=STATE")
+			return response, nil
+		case request.Method == http.MethodPut && path == "/v2/todo/api/blobs/uploads/UPLOAD":
+			require.Equal(t, "STATE", request.URL.Query().Get("state"))
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			blobs[request.URL.Query().Get("digest")] = body
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		case request.Method == http.MethodPut && strings.HasPrefix(path, "/v2/todo/api/manifests/"):
+			require.Equal(t, ManifestMediaType, request.Header.Get("Content-Type"))
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			manifests[strings.TrimPrefix(path, "/v2/todo/api/manifests/")] = body
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		case request.Method == http.MethodGet && strings.HasPrefix(path, "/v2/todo/api/manifests/"):
+			manifest, has := manifests[strings.TrimPrefix(path, "/v2/todo/api/manifests/")]
+			if !has {
+				return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, json.RawMessage(manifest))
+		case request.Method == http.MethodGet && strings.HasPrefix(path, "/v2/todo/api/blobs/"):
+			response, err := mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+			if err != nil {
+				return nil, err
+			}
+			response.Body = io.NopCloser(strings.NewReader(
+				string(blobs[strings.TrimPrefix(path, "/v2/todo/api/blobs/")])))
+			return response, nil
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusBadRequest)
+	})
+
+	pipeline := runtime.NewPipeline(
+		"test", "1.0.0", runtime.PipelineOptions{}, &azcore.ClientOptions{Transport: mockContext.HttpClient})
+	client := NewArtifactClient(pipeline, "contoso.azurecr.io", "ACCESS_TOKEN")
+
+	packagePath := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("PACKAGE"), 0600))
+
+	manifestDigest, err := client.PushFile(*mockContext.Context, "todo/api", "v1", ArtifactFile{
+		Path:         packagePath,
+		MediaType:    "application/zip",
+		ArtifactType: "application/vnd.test.v1",
+		Annotations:  map[string]string{AnnotationVersion: "v1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, digest(manifests["v1"]), manifestDigest)
+
+	// The config and the layer are uploaded
+	require.Len(t, blobs, 2)
+	require.Equal(t, []byte("{}"), blobs["sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"])
+
+	manifest, err := client.Manifest(*mockContext.Context, "todo/api", "v1")
+	require.NoError(t, err)
+	require.Equal(t, "application/vnd.test.v1", manifest.ArtifactType)
+	require.Equal(t, EmptyConfigMediaType, manifest.Config.MediaType)
+	require.Equal(t, "v1", manifest.Annotations[AnnotationVersion])
+	require.Len(t, manifest.Layers, 1)
+	require.Equal(t, "api.zip", manifest.Layers[0].Annotations[AnnotationTitle])
+	require.Equal(t, int64(len("PACKAGE")), manifest.Layers[0].Size)
+
+	target := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, client.PullBlob(*mockContext.Context, "todo/api", manifest.Layers[0], target))
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	require.Equal(t, "PACKAGE", string(content))
+
+	t.Run("ExistingBlobs", func(t *testing.T) {
+		// Pushing the same file again only pushes the manifest
+		_, err := client.PushFile(*mockContext.Context, "todo/api", "v2", ArtifactFile{
+			Path:      packagePath,
+			MediaType: "application/zip",
+		})
+		require.NoError(t, err)
+		require.Len(t, blobs, 2)
+		require.Contains(t, manifests, "v2")
+	})
+
+	t.Run("DigestMismatch", func(t *testing.T) {
+		layer := manifest.Layers[0]
+		layer.Digest = "sha256:" + strings.Repeat("0", 64)
+		mu.Lock()
+		blobs[layer.Digest] = []byte("TAMPERED")
+		mu.Unlock()
+
+		err := client.PullBlob(*mockContext.Context, "todo/api", layer, filepath.Join(t.TempDir(), "api.zip"))
+		require.ErrorContains(t, err, "doesn't match the expected")
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+const connectivityTimeout = 5 * time.Second
+
+// Replaceable for testing
+var (
+	lookupHost  = net.DefaultResolver.LookupHost
+	dialContext = (&net.Dialer{Timeout: connectivityTimeout}).DialContext
+)
+
+// ConnectivityResult is the result of checking the network connectivity to a registry endpoint.
+type ConnectivityResult struct {
+	// The host name which was checked
+	Host string
+	// The addresses the host name resolved to
+	Addresses []string
+	// The DNS resolution error, when the host name could not be resolved
+	DnsErr error
+	// The connection error, when a TCP connection could not be established on port 443
+	ConnectErr error
+}
+
+// Ok returns true when the host name was resolved and a connection was established.
+func (r *ConnectivityResult) Ok() bool {
+	return r.DnsErr == nil && r.ConnectErr == nil
+}
+
+// HasPublicAddress returns true when any of the resolved addresses is a public IP address. A registry which only allows
+// private endpoint access is expected to resolve to a private IP address through its 'privatelink' DNS zone.
+func (r *ConnectivityResult) HasPublicAddress() bool {
+	for _, address := range r.Addresses {
+		ip, err := netip.ParseAddr(address)
+		if err == nil && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *ConnectivityResult) String() string {
+	switch {
+	case r.DnsErr != nil:
+		return fmt.Sprintf("%s: DNS resolution failed (%v)", r.Host, r.DnsErr)
+	case r.ConnectErr != nil:
+		return fmt.Sprintf("%s (%v): connection failed (%v)", r.Host, r.Addresses, r.ConnectErr)
+	default:
+		return fmt.Sprintf("%s (%v): reachable", r.Host, r.Addresses)
+	}
+}
+
+// CheckConnectivity resolves the specified host and attempts a TCP connection to it on port 443, which is used by both the
+// registry login server and its data endpoints.
+func CheckConnectivity(ctx context.Context, host string) *ConnectivityResult {
+	result := &ConnectivityResult{Host: host}
+
+	ctx, cancel := context.WithTimeout(ctx, connectivityTimeout)
+	defer cancel()
+
+	hostName, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostName = host
+		port = "443"
+	}
+
+	addresses, err := lookupHost(ctx, hostName)
+	if err != nil {
+		result.DnsErr = err
+		return result
+	}
+	result.Addresses = addresses
+
+	conn, err := dialContext(ctx, "tcp", net.JoinHostPort(hostName, port))
+	if err != nil {
+		result.ConnectErr = err
+		return result
+	}
+	_ = conn.Close()
+
+	return result
+}
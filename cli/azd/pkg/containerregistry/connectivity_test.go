@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckConnectivity(t *testing.T) {
+	originalLookup, originalDial := lookupHost, dialContext
+	t.Cleanup(func() {
+		lookupHost, dialContext = originalLookup, originalDial
+	})
+
+	t.Run("Reachable", func(t *testing.T) {
+		var dialed string
+		lookupHost = func(ctx context.Context, host string) ([]string, error) {
+			return []string{"10.0.0.4"}, nil
+		}
+		dialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = address
+			client, server := net.Pipe()
+			_ = server.Close()
+			return client, nil
+		}
+
+		result := CheckConnectivity(context.Background(), "contoso.azurecr.io")
+		require.True(t, result.Ok())
+		require.False(t, result.HasPublicAddress())
+		require.Equal(t, "contoso.azurecr.io:443", dialed)
+	})
+
+	t.Run("CustomPort", func(t *testing.T) {
+		var dialed string
+		lookupHost = func(ctx context.Context, host string) ([]string, error) {
+			require.Equal(t, "contoso-edge.local", host)
+			return []string{"192.168.1.10"}, nil
+		}
+		dialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = address
+			client, server := net.Pipe()
+			_ = server.Close()
+			return client, nil
+		}
+
+		result := CheckConnectivity(context.Background(), "contoso-edge.local:8080")
+		require.True(t, result.Ok())
+		require.Equal(t, "contoso-edge.local:8080", dialed)
+	})
+
+	t.Run("DnsFailure", func(t *testing.T) {
+		lookupHost = func(ctx context.Context, host string) ([]string, error) {
+			return nil, errors.New("no such host")
+		}
+
+		result := CheckConnectivity(context.Background(), "contoso.azurecr.io")
+		require.False(t, result.Ok())
+		require.Error(t, result.DnsErr)
+		require.Contains(t, result.String(), "DNS resolution failed")
+	})
+
+	t.Run("PublicAddressUnreachable", func(t *testing.T) {
+		lookupHost = func(ctx context.Context, host string) ([]string, error) {
+			return []string{"20.1.2.3"}, nil
+		}
+		dialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, errors.New("i/o timeout")
+		}
+
+		result := CheckConnectivity(context.Background(), "contoso.azurecr.io")
+		require.False(t, result.Ok())
+		require.True(t, result.HasPublicAddress())
+		require.Contains(t, result.String(), "connection failed")
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/otiai10/copy"
+)
+
+// EphemeralDockerConfig is a docker client configuration directory holding the credentials of a single registry. The
+// docker CLI uses it in place of ~/.docker when DOCKER_CONFIG points to it, so that the credentials are never stored
+// in the user configuration or a credential helper, and are removed with Close.
+//
+// The current context, the contexts and the buildx builders of the user configuration are carried over, so docker
+// keeps talking to the daemon and the builder of the user, ex) Colima, Rancher Desktop or a remote context.
+type EphemeralDockerConfig struct {
+	// The directory holding the config.json file
+	Dir string
+}
+
+type dockerConfigFile struct {
+	Auths               map[string]dockerAuth `json:"auths"`
+	CurrentContext      string                `json:"currentContext,omitempty"`
+	CliPluginsExtraDirs []string              `json:"cliPluginsExtraDirs,omitempty"`
+}
+
+type dockerAuth struct {
+	Auth string `json:"auth"`
+}
+
+// userDockerConfigPaths are the paths of the user configuration directory carried over to the ephemeral configuration:
+// the endpoints and TLS material of the contexts, and the builder instances of buildx with the selected one.
+var userDockerConfigPaths = []string{
+	"contexts",
+	filepath.Join("buildx", "current"),
+	filepath.Join("buildx", "instances"),
+}
+
+// NewEphemeralDockerConfig creates a docker client configuration directory authenticating with the registry at
+// loginServer with the given user name and password or token.
+func NewEphemeralDockerConfig(loginServer string, username string, password string) (*EphemeralDockerConfig, error) {
+	userDir, err := userDockerConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return newEphemeralDockerConfig(userDir, loginServer, username, password)
+}
+
+func newEphemeralDockerConfig(
+	userDir string,
+	loginServer string,
+	username string,
+	password string,
+) (*EphemeralDockerConfig, error) {
+	dir, err := os.MkdirTemp("", "azd-docker-config")
+	if err != nil {
+		return nil, fmt.Errorf("creating docker config directory: %w", err)
+	}
+
+	config := dockerConfigFile{
+		Auths: map[string]dockerAuth{
+			loginServer: {Auth: base64.StdEncoding.EncodeToString([]byte(username + ":" + password))},
+		},
+	}
+
+	if err := carryOverUserDockerConfig(userDir, dir, &config); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	content, err := json.Marshal(config)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("marshalling docker config: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), content, osutil.PermissionFileOwnerOnly); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("writing docker config: %w", err)
+	}
+
+	return &EphemeralDockerConfig{Dir: dir}, nil
+}
+
+// carryOverUserDockerConfig copies the context and builder settings of the user configuration in userDir to the
+// ephemeral configuration in dir. The credentials and credential helpers of the user aren't copied.
+func carryOverUserDockerConfig(userDir string, dir string, config *dockerConfigFile) error {
+	content, err := os.ReadFile(filepath.Join(userDir, "config.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading docker config: %w", err)
+	}
+
+	if len(content) > 0 {
+		var userConfig struct {
+			CurrentContext      string   `json:"currentContext"`
+			CliPluginsExtraDirs []string `json:"cliPluginsExtraDirs"`
+		}
+		if err := json.Unmarshal(content, &userConfig); err != nil {
+			return fmt.Errorf("parsing docker config: %w", err)
+		}
+
+		config.CurrentContext = userConfig.CurrentContext
+		config.CliPluginsExtraDirs = userConfig.CliPluginsExtraDirs
+	}
+
+	// The plugins installed in the user configuration, ex) buildx installed by Docker Desktop, are looked up in the
+	// cli-plugins directory of the configuration
+	config.CliPluginsExtraDirs = append(config.CliPluginsExtraDirs, filepath.Join(userDir, "cli-plugins"))
+
+	for _, path := range userDockerConfigPaths {
+		if _, err := os.Stat(filepath.Join(userDir, path)); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err := copy.Copy(filepath.Join(userDir, path), filepath.Join(dir, path)); err != nil {
+			return fmt.Errorf("copying docker config '%s': %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// userDockerConfigDir returns the docker client configuration directory of the user, DOCKER_CONFIG or ~/.docker.
+func userDockerConfigDir() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".docker"), nil
+}
+
+// Env returns the environment variable pointing the docker CLI to the configuration.
+func (c *EphemeralDockerConfig) Env() string {
+	return "DOCKER_CONFIG=" + c.Dir
+}
+
+// Close removes the configuration directory along with the credentials it holds.
+func (c *EphemeralDockerConfig) Close() error {
+	return os.RemoveAll(c.Dir)
+}
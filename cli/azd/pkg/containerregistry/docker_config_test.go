@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EphemeralDockerConfig(t *testing.T) {
+	config, err := NewEphemeralDockerConfig("contoso.azurecr.io", TokenUsername, "REFRESH")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(config.Dir, "config.json"))
+	require.NoError(t, err)
+
+	var parsed dockerConfigFile
+	require.NoError(t, json.Unmarshal(content, &parsed))
+
+	auth, err := base64.StdEncoding.DecodeString(parsed.Auths["contoso.azurecr.io"].Auth)
+	require.NoError(t, err)
+	require.Equal(t, TokenUsername+":REFRESH", string(auth))
+
+	require.NoError(t, config.Close())
+	require.NoDirExists(t, config.Dir)
+}
+
+func Test_EphemeralDockerConfig_KeepsContext(t *testing.T) {
+	userDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, "config.json"), []byte(`{
+		"auths": {"docker.io": {"auth": "dXNlcjpwYXNz"}},
+		"credsStore": "desktop",
+		"currentContext": "colima"
+	}`), osutil.PermissionFile))
+
+	contextMeta := filepath.Join("contexts", "meta", "0123", "meta.json")
+	builder := filepath.Join("buildx", "instances", "multiplatform")
+	for _, path := range []string{contextMeta, builder, filepath.Join("buildx", "current")} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(userDir, path)), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(filepath.Join(userDir, path), []byte("{}"), osutil.PermissionFile))
+	}
+
+	config, err := newEphemeralDockerConfig(userDir, "contoso.azurecr.io", TokenUsername, "REFRESH")
+	require.NoError(t, err)
+	defer config.Close()
+
+	content, err := os.ReadFile(filepath.Join(config.Dir, "config.json"))
+	require.NoError(t, err)
+
+	// The credentials of the user aren't carried over
+	parsed := map[string]any{}
+	require.NoError(t, json.Unmarshal(content, &parsed))
+	require.Equal(t, "colima", parsed["currentContext"])
+	require.Equal(t, []any{filepath.Join(userDir, "cli-plugins")}, parsed["cliPluginsExtraDirs"])
+	require.NotContains(t, parsed, "credsStore")
+	require.Len(t, parsed["auths"], 1)
+
+	require.FileExists(t, filepath.Join(config.Dir, contextMeta))
+	require.FileExists(t, filepath.Join(config.Dir, builder))
+	require.FileExists(t, filepath.Join(config.Dir, "buildx", "current"))
+}
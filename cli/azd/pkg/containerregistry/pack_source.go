@@ -11,12 +11,19 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/google/uuid"
 	"github.com/moby/patternmatcher"
 	"github.com/moby/patternmatcher/ignorefile"
+	"github.com/otiai10/copy"
 )
 
+// ContextFilter returns whether a file of a build context is part of the context, from the path of the file in the
+// context and whether the file is excluded by the .dockerignore file.
+type ContextFilter func(contextPath string, ignored bool) bool
+
 // PackRemoteBuildSource creates a tarball of the specified context directory into a temporary file and returns the path to
 // it. It ensures that the dockerfile is present in the tarball and returns the relative path of it in the archive. If the
 // dockerfile is located outside of the context, it is added to the root of the archive with a unique prefix.
@@ -27,10 +34,105 @@ import (
 //
 // A `.dockerignore` file may be used to control what is included in the build context. If a file with the same path as
 // `dockerfile` exists but with an additional `.dockerignore` suffix, it is used as the ignore file. Otherwise, if a
-// `.dockerignore` file exists in the root of the context, it is used.
+// `.dockerignore` file exists in the root of the context, it is used. When filter is set, it decides which files are
+// packed instead, from whether they're excluded by the ignore file.
 //
 // Any folders named `.git` is excluded from the produced archive.
-func PackRemoteBuildSource(ctx context.Context, root string, dockerfile string) (string, string, error) {
+func PackRemoteBuildSource(
+	ctx context.Context,
+	root string,
+	dockerfile string,
+	filter ContextFilter,
+) (string, string, error) {
+	contextArchive, err := os.CreateTemp("", "azd-docker-context*.tar.gz")
+	if err != nil {
+		return "", "", err
+	}
+	defer contextArchive.Close()
+
+	gw := gzip.NewWriter(contextArchive)
+	defer gw.Close()
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	dockerfileArchivePath, err := walkBuildSource(root, dockerfile, filter, func(path string, archivePath string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, info.Name())
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = archivePath
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+
+	return contextArchive.Name(), dockerfileArchivePath, err
+}
+
+// CopyBuildSource copies the files of the context directory, selected like PackRemoteBuildSource does, into a temporary
+// directory and returns the path to it and the path of the dockerfile in the copy, so docker builds the copy instead of
+// the context. The ignore files aren't copied, since the files they exclude are already left out.
+//
+// On error, if the directory had been created, the path is returned. The caller should ensure it is removed.
+func CopyBuildSource(
+	ctx context.Context,
+	root string,
+	dockerfile string,
+	filter ContextFilter,
+) (string, string, error) {
+	dir, err := os.MkdirTemp("", "azd-docker-context")
+	if err != nil {
+		return "", "", err
+	}
+
+	ignoreFiles := []string{".dockerignore"}
+	if relativeDockerfile, err := filepath.Rel(root, dockerfile); err == nil {
+		ignoreFiles = append(ignoreFiles, filepath.ToSlash(relativeDockerfile)+".dockerignore")
+	}
+
+	dockerfileCopyPath, err := walkBuildSource(root, dockerfile, filter, func(path string, archivePath string) error {
+		if slices.Contains(ignoreFiles, archivePath) {
+			return nil
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(archivePath))
+		if err := os.MkdirAll(filepath.Dir(target), osutil.PermissionDirectory); err != nil {
+			return err
+		}
+
+		return copy.Copy(path, target)
+	})
+	if err != nil {
+		return dir, "", err
+	}
+
+	return dir, filepath.Join(dir, filepath.FromSlash(dockerfileCopyPath)), nil
+}
+
+// walkBuildSource calls fn with each file of the context directory which is part of the build context, and its path in
+// the context. When the dockerfile isn't in the context, fn is called with it at the root of the context with a unique
+// prefix. Returns the path of the dockerfile in the context.
+func walkBuildSource(
+	root string,
+	dockerfile string,
+	filter ContextFilter,
+	fn func(path string, archivePath string) error,
+) (string, error) {
 	var ignores []string
 
 	// Like docker, we allow the use of a .dockerignore file to control what is included in the build context.
@@ -42,29 +144,18 @@ func PackRemoteBuildSource(ctx context.Context, root string, dockerfile string)
 			defer f.Close()
 			i, err := ignorefile.ReadAll(f)
 			if err != nil {
-				return "", "", err
+				return "", err
 			}
 			ignores = i
 			break
 		} else if !errors.Is(err, os.ErrNotExist) {
-			return "", "", err
+			return "", err
 		}
 	}
 
-	contextArchive, err := os.CreateTemp("", "azd-docker-context*.tar.gz")
-	if err != nil {
-		return "", "", err
-	}
-	defer contextArchive.Close()
-
-	gw := gzip.NewWriter(contextArchive)
-	defer gw.Close()
-	tw := tar.NewWriter(gw)
-	defer tw.Close()
-
 	var dockerfileArchivePath string
 
-	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -84,37 +175,12 @@ func PackRemoteBuildSource(ctx context.Context, root string, dockerfile string)
 			return err
 		}
 
-		if !ignore {
-			info, err := d.Info()
-			if err != nil {
-				return err
-			}
-
-			hdr, err := tar.FileInfoHeader(info, info.Name())
-			if err != nil {
-				return err
-			}
-
-			hdr.Name = archivePath
-			if err := tw.WriteHeader(hdr); err != nil {
-				return err
-			}
-
-			err = func() error {
-				f, err := os.Open(path)
-				if err != nil {
-					return err
-				}
-				defer f.Close()
-
-				_, err = io.Copy(tw, f)
-				if err != nil {
-					return err
-				}
+		if filter != nil {
+			ignore = !filter(archivePath, ignore)
+		}
 
-				return nil
-			}()
-			if err != nil {
+		if !ignore {
+			if err := fn(path, archivePath); err != nil {
 				return err
 			}
 
@@ -127,40 +193,18 @@ func PackRemoteBuildSource(ctx context.Context, root string, dockerfile string)
 	})
 
 	if err != nil {
-		return contextArchive.Name(), dockerfileArchivePath, err
+		return dockerfileArchivePath, err
 	}
 
 	// If we didn't see the dockerfile in the context, add it to the archive at the root with a unique name.
 	if dockerfileArchivePath == "" {
-		f, err := os.Open(dockerfile)
-		if err != nil {
-			return contextArchive.Name(), dockerfileArchivePath, err
-		}
-		defer f.Close()
-		info, err := f.Stat()
-		if err != nil {
-			return contextArchive.Name(), dockerfileArchivePath, err
-		}
-
-		hdr, err := tar.FileInfoHeader(info, info.Name())
-		if err != nil {
-			return contextArchive.Name(), dockerfileArchivePath, err
-		}
-
 		uniqueName := uuid.NewString() + "_" + filepath.Base(dockerfile)
-
-		hdr.Name = uniqueName
-		if err := tw.WriteHeader(hdr); err != nil {
-			return contextArchive.Name(), dockerfileArchivePath, err
-		}
-
-		_, err = io.Copy(tw, f)
-		if err != nil {
-			return contextArchive.Name(), dockerfileArchivePath, err
+		if err := fn(dockerfile, uniqueName); err != nil {
+			return dockerfileArchivePath, err
 		}
 
 		dockerfileArchivePath = uniqueName
 	}
 
-	return contextArchive.Name(), dockerfileArchivePath, err
+	return dockerfileArchivePath, nil
 }
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newBuildContext(t *testing.T) string {
+	root := t.TempDir()
+	for file, contents := range map[string]string{
+		"Dockerfile":         "FROM scratch",
+		".dockerignore":      "tests/\n**/*.md",
+		"app.py":             "print('hi')",
+		"README.md":          "# app",
+		"tests/test_app.py":  "assert True",
+		".env":               "SECRET=1",
+		"static/styles.css":  "body {}",
+		".git/HEAD":          "ref: refs/heads/main",
+		"static/logo.md":     "logo",
+		"static/nested/a.js": "a",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+	}
+
+	return root
+}
+
+// filter excludes .env files and ships the markdown files of the static folder
+func filter(contextPath string, ignored bool) bool {
+	if strings.HasPrefix(contextPath, "static/") {
+		return true
+	}
+
+	return !ignored && contextPath != ".env"
+}
+
+func Test_PackRemoteBuildSource(t *testing.T) {
+	root := newBuildContext(t)
+
+	t.Run("DockerIgnore", func(t *testing.T) {
+		archivePath, dockerfilePath, err := PackRemoteBuildSource(
+			context.Background(), root, filepath.Join(root, "Dockerfile"), nil)
+		t.Cleanup(func() { _ = os.Remove(archivePath) })
+		require.NoError(t, err)
+		require.Equal(t, "Dockerfile", dockerfilePath)
+
+		require.Equal(t, []string{
+			".dockerignore", ".env", "Dockerfile", "app.py", "static/nested/a.js", "static/styles.css",
+		}, archiveFiles(t, archivePath))
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		archivePath, _, err := PackRemoteBuildSource(
+			context.Background(), root, filepath.Join(root, "Dockerfile"), filter)
+		t.Cleanup(func() { _ = os.Remove(archivePath) })
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			".dockerignore", "Dockerfile", "app.py", "static/logo.md", "static/nested/a.js", "static/styles.css",
+		}, archiveFiles(t, archivePath))
+	})
+}
+
+func Test_CopyBuildSource(t *testing.T) {
+	root := newBuildContext(t)
+
+	dir, dockerfilePath, err := CopyBuildSource(context.Background(), root, filepath.Join(root, "Dockerfile"), filter)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "Dockerfile"), dockerfilePath)
+
+	files := []string{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, filepath.ToSlash(path[len(dir)+1:]))
+		}
+
+		return err
+	})
+	require.NoError(t, err)
+	slices.Sort(files)
+
+	// The .dockerignore file isn't copied, so docker doesn't exclude the files shipped by the filter again
+	require.Equal(t, []string{
+		"Dockerfile", "app.py", "static/logo.md", "static/nested/a.js", "static/styles.css",
+	}, files)
+
+	t.Run("DockerfileOutsideContext", func(t *testing.T) {
+		dockerfile := filepath.Join(t.TempDir(), "api.Dockerfile")
+		require.NoError(t, os.WriteFile(dockerfile, []byte("FROM scratch"), osutil.PermissionFile))
+
+		dir, dockerfilePath, err := CopyBuildSource(context.Background(), root, dockerfile, filter)
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+		require.NoError(t, err)
+		require.Equal(t, dir, filepath.Dir(dockerfilePath))
+		require.True(t, strings.HasSuffix(dockerfilePath, "_api.Dockerfile"))
+		require.FileExists(t, dockerfilePath)
+	})
+}
+
+func archiveFiles(t *testing.T, archivePath string) []string {
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := []string{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		files = append(files, hdr.Name)
+	}
+	slices.Sort(files)
+
+	return files
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// TokenUsername is the user name a registry expects along with an ACR refresh token as password.
+//
+// https://learn.microsoft.com/azure/container-registry/container-registry-authentication?tabs=azure-cli#individual-login-with-microsoft-entra-id
+//
+//nolint:lll
+const TokenUsername = "00000000-0000-0000-0000-000000000000"
+
+type refreshTokenResponse struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ExchangeRefreshToken exchanges a Microsoft Entra access token for an ACR refresh token of the registry with the given
+// login server (ex: myregistry.azurecr.io), without involving the az CLI or a docker credential helper.
+func ExchangeRefreshToken(
+	ctx context.Context,
+	pipeline runtime.Pipeline,
+	loginServer string,
+	accessToken string,
+) (string, error) {
+	// Implementation based on docs @ https://azure.github.io/acr/AAD-OAuth.html
+	formData := url.Values{}
+	formData.Set("grant_type", "access_token")
+	formData.Set("service", loginServer)
+	formData.Set("access_token", accessToken)
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, fmt.Sprintf("https://%s/oauth2/exchange", loginServer))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	raw := req.Raw()
+	raw.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	raw.Body = io.NopCloser(strings.NewReader(formData.Encode()))
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return "", runtime.NewResponseError(response)
+	}
+
+	body, err := httputil.ReadRawResponse[refreshTokenResponse](response)
+	if err != nil {
+		return "", err
+	}
+
+	return body.RefreshToken, nil
+}
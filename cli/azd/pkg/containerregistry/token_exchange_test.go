@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerregistry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExchangeRefreshToken(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	var form url.Values
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Host == "contoso.azurecr.io"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		form, err = url.ParseQuery(string(body))
+		require.NoError(t, err)
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]string{"refresh_token": "REFRESH"})
+	})
+
+	pipeline := runtime.NewPipeline(
+		"test", "1.0.0", runtime.PipelineOptions{}, &azcore.ClientOptions{Transport: mockContext.HttpClient})
+
+	refreshToken, err := ExchangeRefreshToken(*mockContext.Context, pipeline, "contoso.azurecr.io", "ACCESS_TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, "REFRESH", refreshToken)
+	require.Equal(t, "access_token", form.Get("grant_type"))
+	require.Equal(t, "contoso.azurecr.io", form.Get("service"))
+	require.Equal(t, "ACCESS_TOKEN", form.Get("access_token"))
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return true
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusUnauthorized)
+		})
+
+		pipeline := runtime.NewPipeline(
+			"test", "1.0.0", runtime.PipelineOptions{}, &azcore.ClientOptions{Transport: mockContext.HttpClient})
+
+		_, err := ExchangeRefreshToken(*mockContext.Context, pipeline, "contoso.azurecr.io", "ACCESS_TOKEN")
+		var responseErr *azcore.ResponseError
+		require.ErrorAs(t, err, &responseErr)
+		require.Equal(t, http.StatusUnauthorized, responseErr.StatusCode)
+	})
+}
@@ -0,0 +1,15 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// ConsoleError is the error of a failed command, written when JSON output is enabled.
+type ConsoleError struct {
+	Message string `json:"message"`
+	// The category of the error, ex) auth or quota, when known
+	Category string `json:"category,omitempty"`
+	// The machine-readable code of the error, ex) quota.InsufficientQuota, when known
+	Code       string `json:"code,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	TraceId    string `json:"traceId,omitempty"`
+}
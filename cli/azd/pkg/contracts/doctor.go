@@ -0,0 +1,31 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// DoctorCheckStatus is the status of a check run by 'azd doctor'
+type DoctorCheckStatus string
+
+const (
+	DoctorCheckPassed  DoctorCheckStatus = "passed"
+	DoctorCheckWarning DoctorCheckStatus = "warning"
+	DoctorCheckFailed  DoctorCheckStatus = "failed"
+	DoctorCheckSkipped DoctorCheckStatus = "skipped"
+)
+
+// DoctorCheck is the result of a check run by 'azd doctor'
+type DoctorCheck struct {
+	Name    string            `json:"name"`
+	Status  DoctorCheckStatus `json:"status"`
+	Message string            `json:"message"`
+	// The category of the error of a failed check, ex) auth or network, when known
+	Category string `json:"category,omitempty"`
+	// The machine-readable code of the error of a failed check, when known
+	Code       string `json:"code,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// DoctorResult is the result of 'azd doctor'
+type DoctorResult struct {
+	Checks []DoctorCheck `json:"checks"`
+}
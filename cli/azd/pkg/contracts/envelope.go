@@ -9,6 +9,7 @@ type EventDataType string
 
 const (
 	ConsoleMessageEventDataType EventDataType = "consoleMessage"
+	ConsoleErrorEventDataType   EventDataType = "consoleError"
 )
 
 type EventEnvelope struct {
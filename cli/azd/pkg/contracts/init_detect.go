@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// InitDetectResult is the contract for the output of `azd init --dry-run-detect`
+type InitDetectResult struct {
+	// The projects detected in the app, which would be the services of the project
+	Projects []InitDetectProject `json:"projects"`
+}
+
+// InitDetectProject is the contract for a project detected by `azd init --dry-run-detect`
+type InitDetectProject struct {
+	// The language of the project, ex) python, or docker for a project only built by its Dockerfile
+	Language string `json:"language"`
+	// The path to the project directory, relative to the current directory
+	Path string `json:"path"`
+	// The root directory of the build, for languages supporting multiple projects, ex) a Maven multi-module project
+	RootPath string `json:"rootPath,omitempty"`
+	// How the project was detected, ex) Inferred by presence of: package.json
+	DetectionRule string `json:"detectionRule"`
+	// How confident the detection is, between 0 and 1
+	Confidence float64 `json:"confidence"`
+	// The frameworks and libraries detected, ex) react
+	Dependencies []string `json:"dependencies,omitempty"`
+	// The databases used by the project, ex) postgres
+	Databases []string `json:"databases,omitempty"`
+	// True when a web framework supporting server-side rendering is built as a static site
+	StaticSite bool `json:"staticSite,omitempty"`
+	// The Dockerfile of the project
+	Docker *InitDetectDocker `json:"docker,omitempty"`
+	// The Docker Compose service the project is imported from
+	Compose *InitDetectCompose `json:"compose,omitempty"`
+}
+
+// InitDetectDocker is the contract for the Dockerfile of a detected project
+type InitDetectDocker struct {
+	// The path to the Dockerfile, relative to the current directory
+	Path string `json:"path"`
+	// The ports exposed, ex) 8080/tcp
+	Ports []string `json:"ports,omitempty"`
+}
+
+// InitDetectCompose is the contract for the Docker Compose service of a detected project
+type InitDetectCompose struct {
+	// The name of the service
+	Service string `json:"service"`
+	// The path to the compose file, relative to the current directory
+	Path string `json:"path"`
+	// The environment variables of the service
+	Env map[string]string `json:"env,omitempty"`
+}
@@ -2,6 +2,8 @@
 // Licensed under the MIT License.
 package contracts
 
+import "time"
+
 // ShowType are the values for the language property of a ShowServiceProject
 type ShowType string
 
@@ -17,6 +19,16 @@ const (
 type ShowResult struct {
 	Name     string                 `json:"name"`
 	Services map[string]ShowService `json:"services"`
+	// The upcoming actions scheduled on the environment, like the deletion of an expiring devcenter environment.
+	ScheduledActions []ShowScheduledAction `json:"scheduledActions,omitempty"`
+}
+
+// ShowScheduledAction is the contract for an action scheduled on the environment, returned by `azd show`
+type ShowScheduledAction struct {
+	Name string `json:"name"`
+	// The kind of action, ex) Delete
+	ActionType    string    `json:"actionType"`
+	ScheduledTime time.Time `json:"scheduledTime"`
 }
 
 // ShowService is the contract for a service returned by `azd show`
@@ -43,3 +55,42 @@ type ShowServiceProject struct {
 type ShowTargetArm struct {
 	ResourceIds []string `json:"resourceIds"`
 }
+
+// ShowServiceDetailsResult is the contract for the output of `azd show <service>`
+type ShowServiceDetailsResult struct {
+	Name string `json:"name"`
+	// Project contains information about the project that backs this service.
+	Project ShowServiceProject `json:"project"`
+	// The kind of Azure resource the service is deployed to (e.g. containerapp).
+	Host string `json:"host"`
+	// Target contains information about the resources that the service is deployed to.
+	Target *ShowTargetArm `json:"target,omitempty"`
+	// The public endpoints of the service.
+	Endpoints []string `json:"endpoints"`
+	// The managed identities assigned to the target resource.
+	Identities []ShowIdentity `json:"identities"`
+	// The container image or package version currently deployed, when it can be determined.
+	DeployedVersion string `json:"deployedVersion,omitempty"`
+	// The most recent deployments of the service, newest first.
+	RecentDeployments []ShowDeployment `json:"recentDeployments"`
+}
+
+// ShowIdentity is the contract for a managed identity assigned to the target resource of a service.
+type ShowIdentity struct {
+	// Either SystemAssigned or UserAssigned.
+	Type string `json:"type"`
+	// The resource ID of a user assigned identity.
+	ResourceId  string `json:"resourceId,omitempty"`
+	PrincipalId string `json:"principalId,omitempty"`
+	ClientId    string `json:"clientId,omitempty"`
+}
+
+// ShowDeployment is the contract for a deployment of a service, such as a container app revision or an App Service
+// deployment.
+type ShowDeployment struct {
+	Id     string     `json:"id"`
+	Status string     `json:"status,omitempty"`
+	Time   *time.Time `json:"time,omitempty"`
+	// The container image or package version deployed.
+	Version string `json:"version,omitempty"`
+}
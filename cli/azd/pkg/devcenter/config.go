@@ -5,6 +5,8 @@ package devcenter
 
 import (
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
 )
@@ -17,6 +19,11 @@ const (
 	DevCenterEnvTypeEnvName       = "AZURE_DEVCENTER_ENVIRONMENT_TYPE"
 	DevCenterEnvDefinitionEnvName = "AZURE_DEVCENTER_ENVIRONMENT_DEFINITION"
 	DevCenterEnvUser              = "AZURE_DEVCENTER_ENVIRONMENT_USER"
+	DevCenterTimeoutEnvName       = "AZURE_DEVCENTER_TIMEOUT"
+	DevCenterPollIntervalEnvName  = "AZURE_DEVCENTER_POLL_INTERVAL"
+	DevCenterExpirationEnvName    = "AZURE_DEVCENTER_EXPIRATION_HOURS"
+	// Prefix of the environment variables which set the values of environment definition parameters
+	DevCenterParameterEnvNamePrefix = "AZURE_DEVCENTER_PARAM_"
 
 	// Environment configuration paths
 	DevCenterNamePath          = ConfigPath + ".name"
@@ -25,10 +32,27 @@ const (
 	DevCenterEnvTypePath       = ConfigPath + ".environmentType"
 	DevCenterEnvDefinitionPath = ConfigPath + ".environmentDefinition"
 	DevCenterUserPath          = ConfigPath + ".user"
+	DevCenterTimeoutPath       = ConfigPath + ".timeout"
+	DevCenterPollIntervalPath  = ConfigPath + ".pollInterval"
+	DevCenterExpirationPath    = ConfigPath + ".expirationHours"
 
 	PlatformKindDevCenter platform.PlatformKind = "devcenter"
 )
 
+// ParameterEnvName gets the name of the environment variable which sets the value of an environment definition parameter,
+// ex) AZURE_DEVCENTER_PARAM_REPOURL for the 'repoUrl' parameter.
+func ParameterEnvName(parameterId string) string {
+	name := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return '_'
+		}
+
+		return unicode.ToUpper(r)
+	}, parameterId)
+
+	return DevCenterParameterEnvNamePrefix + name
+}
+
 // Config provides the Azure DevCenter configuration used for devcenter enabled projects
 type Config struct {
 	Name                  string `json:"name,omitempty"                  yaml:"name,omitempty"`
@@ -37,6 +61,16 @@ type Config struct {
 	EnvironmentType       string `json:"environmentType,omitempty"       yaml:"environmentType,omitempty"`
 	EnvironmentDefinition string `json:"environmentDefinition,omitempty" yaml:"environmentDefinition,omitempty"`
 	User                  string `json:"user,omitempty"                  yaml:"user,omitempty"`
+	// Maps the names of deployment environment outputs to the azd environment variables they are stored as.
+	// Outputs mapped to an empty value are dropped, and mapping "*" to an empty value drops all unmapped outputs.
+	OutputMappings map[string]string `json:"outputMappings,omitempty" yaml:"outputMappings,omitempty"`
+	// The maximum time to wait for the environment to be deployed or deleted, as a duration, ex) 90m.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// The time between the checks of the status of the environment while it is deployed or deleted, as a duration, ex) 10s.
+	PollInterval string `json:"pollInterval,omitempty" yaml:"pollInterval,omitempty"`
+	// The number of hours after which ADE deletes the environment, set when it is provisioned. Zero when it doesn't
+	// expire.
+	ExpirationHours int `json:"expirationHours,omitempty" yaml:"expirationHours,omitempty"`
 }
 
 // EnsureValid ensures the devcenter configuration is valid to continue with provisioning
@@ -53,5 +87,9 @@ func (c *Config) EnsureValid() error {
 		return fmt.Errorf("devcenter environment definition is required")
 	}
 
+	if c.ExpirationHours < 0 {
+		return fmt.Errorf("devcenter expiration hours must be positive")
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@ package devcenter
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 )
 
 // Merges supplemental configuration into the base config only if the key/value doesn't already exist in the base config
@@ -29,6 +30,11 @@ func MergeConfigs(configs ...*Config) *Config {
 		Project:               destConfig.Project,
 		EnvironmentType:       destConfig.EnvironmentType,
 		EnvironmentDefinition: destConfig.EnvironmentDefinition,
+		User:                  destConfig.User,
+		OutputMappings:        maps.Clone(destConfig.OutputMappings),
+		Timeout:               destConfig.Timeout,
+		PollInterval:          destConfig.PollInterval,
+		ExpirationHours:       destConfig.ExpirationHours,
 	}
 
 	for _, config := range configs[1:] {
@@ -59,6 +65,30 @@ func MergeConfigs(configs ...*Config) *Config {
 		if config.User != "" && mergedConfig.User == "" {
 			mergedConfig.User = config.User
 		}
+
+		if config.Timeout != "" && mergedConfig.Timeout == "" {
+			mergedConfig.Timeout = config.Timeout
+		}
+
+		if config.PollInterval != "" && mergedConfig.PollInterval == "" {
+			mergedConfig.PollInterval = config.PollInterval
+		}
+
+		if config.ExpirationHours != 0 && mergedConfig.ExpirationHours == 0 {
+			mergedConfig.ExpirationHours = config.ExpirationHours
+		}
+
+		for output, envVar := range config.OutputMappings {
+			if _, has := mergedConfig.OutputMappings[output]; has {
+				continue
+			}
+
+			if mergedConfig.OutputMappings == nil {
+				mergedConfig.OutputMappings = map[string]string{}
+			}
+
+			mergedConfig.OutputMappings[output] = envVar
+		}
 	}
 
 	return mergedConfig
@@ -7,6 +7,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
@@ -104,6 +105,48 @@ func Test_MergeConfigs(t *testing.T) {
 		require.Equal(t, "CATALOG", mergedConfig.Catalog)
 		require.Equal(t, "ENVIRONMENT_TYPE", mergedConfig.EnvironmentType)
 	})
+
+	t.Run("MergeOutputMappings", func(t *testing.T) {
+		baseConfig := &Config{
+			OutputMappings: map[string]string{
+				"webUri": "SERVICE_WEB_ENDPOINT_URL",
+			},
+		}
+
+		overrideConfig := &Config{
+			OutputMappings: map[string]string{
+				"webUri":     "OVERRIDE",
+				"internalId": "",
+			},
+		}
+
+		mergedConfig := MergeConfigs(baseConfig, overrideConfig)
+
+		require.Equal(t, map[string]string{
+			"webUri":     "SERVICE_WEB_ENDPOINT_URL",
+			"internalId": "",
+		}, mergedConfig.OutputMappings)
+		// The base config is not modified
+		require.Len(t, baseConfig.OutputMappings, 1)
+	})
+
+	t.Run("MergeOperationSettings", func(t *testing.T) {
+		baseConfig := &Config{
+			Timeout: "90m",
+		}
+
+		overrideConfig := &Config{
+			Timeout:         "OVERRIDE",
+			PollInterval:    "10s",
+			ExpirationHours: 8,
+		}
+
+		mergedConfig := MergeConfigs(baseConfig, overrideConfig)
+
+		require.Equal(t, "90m", mergedConfig.Timeout)
+		require.Equal(t, "10s", mergedConfig.PollInterval)
+		require.Equal(t, 8, mergedConfig.ExpirationHours)
+	})
 }
 
 type mockDevCenterManager struct {
@@ -159,6 +202,38 @@ func (m *mockDevCenterManager) Outputs(
 	return nil, args.Error(1)
 }
 
+func (m *mockDevCenterManager) CompletedDeployments(
+	ctx context.Context,
+	config *Config,
+	env *devcentersdk.Environment,
+	hint string,
+) ([]*azapi.ResourceDeployment, error) {
+	args := m.Called(ctx, config, env, hint)
+
+	deployments, ok := args.Get(0).([]*azapi.ResourceDeployment)
+	if ok {
+		return deployments, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *mockDevCenterManager) DeploymentOutputs(
+	ctx context.Context,
+	config *Config,
+	env *devcentersdk.Environment,
+	deployment *azapi.ResourceDeployment,
+) (map[string]provisioning.OutputParameter, error) {
+	args := m.Called(ctx, config, env, deployment)
+
+	outputs, ok := args.Get(0).(map[string]provisioning.OutputParameter)
+	if ok {
+		return outputs, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 var mockDevCenterList []*devcentersdk.DevCenter = []*devcentersdk.DevCenter{
 	{
 		//nolint:lll
@@ -296,3 +371,21 @@ var mockEnvDefinitions []*devcentersdk.EnvironmentDefinition = []*devcentersdk.E
 		},
 	},
 }
+
+type mockSubscriptionResolver struct {
+	mock.Mock
+}
+
+func (m *mockSubscriptionResolver) GetSubscription(
+	ctx context.Context,
+	subscriptionId string,
+) (*account.Subscription, error) {
+	args := m.Called(ctx, subscriptionId)
+
+	subscription, ok := args.Get(0).(*account.Subscription)
+	if ok {
+		return subscription, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
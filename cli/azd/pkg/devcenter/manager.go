@@ -5,11 +5,14 @@ package devcenter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
 	"regexp"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
@@ -66,6 +69,21 @@ type Manager interface {
 		config *Config,
 		env *devcentersdk.Environment,
 	) (map[string]provisioning.OutputParameter, error)
+	// CompletedDeployments gets the successful ARM deployments holding the outputs of the specified devcenter
+	// environment, optionally matching the hint
+	CompletedDeployments(
+		ctx context.Context,
+		config *Config,
+		env *devcentersdk.Environment,
+		hint string,
+	) ([]*azapi.ResourceDeployment, error)
+	// DeploymentOutputs gets the outputs for the specified devcenter environment from one of its ARM deployments
+	DeploymentOutputs(
+		ctx context.Context,
+		config *Config,
+		env *devcentersdk.Environment,
+		deployment *azapi.ResourceDeployment,
+	) (map[string]provisioning.OutputParameter, error)
 }
 
 // Manager provides a common set of methods for interactive with a devcenter and its environments
@@ -252,16 +270,7 @@ func (m *manager) LatestArmDeployment(
 	})
 
 	latestDeploymentIndex := slices.IndexFunc(deployments, func(d *azapi.ResourceDeployment) bool {
-		tagDevCenterName, devCenterOk := d.Tags[DeploymentTagDevCenterName]
-		tagProjectName, projectOk := d.Tags[DeploymentTagDevCenterProject]
-		tagEnvTypeName, envTypeOk := d.Tags[DeploymentTagEnvironmentType]
-		tagEnvName, envOk := d.Tags[DeploymentTagEnvironmentName]
-
-		// ARM runner deployments contain the deployment tags for the specific environment
-		isArmDeployment := devCenterOk && strings.EqualFold(*tagDevCenterName, config.Name) &&
-			projectOk && strings.EqualFold(*tagProjectName, config.Project) &&
-			envTypeOk && strings.EqualFold(*tagEnvTypeName, env.EnvironmentType) &&
-			envOk && strings.EqualFold(*tagEnvName, env.Name)
+		isArmDeployment := isEnvironmentDeployment(d, config, env)
 
 		// Support for untagged Bicep ADE deployments
 		// If the deployment is not tagged but starts with the current date and is running
@@ -286,6 +295,24 @@ func (m *manager) LatestArmDeployment(
 	return deployments[latestDeploymentIndex], nil
 }
 
+// isEnvironmentDeployment returns true when the deployment is tagged by ADE as a deployment of the environment, which
+// ARM runner deployments are.
+func isEnvironmentDeployment(
+	deployment *azapi.ResourceDeployment,
+	config *Config,
+	env *devcentersdk.Environment,
+) bool {
+	tagDevCenterName, devCenterOk := deployment.Tags[DeploymentTagDevCenterName]
+	tagProjectName, projectOk := deployment.Tags[DeploymentTagDevCenterProject]
+	tagEnvTypeName, envTypeOk := deployment.Tags[DeploymentTagEnvironmentType]
+	tagEnvName, envOk := deployment.Tags[DeploymentTagEnvironmentName]
+
+	return devCenterOk && strings.EqualFold(*tagDevCenterName, config.Name) &&
+		projectOk && strings.EqualFold(*tagProjectName, config.Project) &&
+		envTypeOk && strings.EqualFold(*tagEnvTypeName, env.EnvironmentType) &&
+		envOk && strings.EqualFold(*tagEnvName, env.Name)
+}
+
 // Outputs gets the outputs for the latest deployment of the specified environment
 func (m *manager) Outputs(
 	ctx context.Context,
@@ -297,13 +324,31 @@ func (m *manager) Outputs(
 		return nil, fmt.Errorf("failed parsing resource group id: %w", err)
 	}
 
-	outputsResponse, err := m.client.DevCenterByName(config.Name).
+	envClient := m.client.DevCenterByName(config.Name).
 		ProjectByName(config.Project).
 		EnvironmentsByUser(env.User).
-		EnvironmentByName(env.Name).
-		Outputs().
-		Get(ctx)
+		EnvironmentByName(env.Name)
+
+	// The Terraform runner doesn't deploy ARM templates, its outputs are returned in the result of the deployment
+	if m.runnerType(ctx, config, env) == devcentersdk.EnvironmentDefinitionRunnerTerraform {
+		operations, err := envClient.Operations().Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environment operations: %w", err)
+		}
 
+		if operation := latestDeployResult(operations.Value); operation != nil {
+			outputs, err := createTerraformOutputParameters(operation.Result.Outputs)
+			if err != nil {
+				return nil, fmt.Errorf("failed resolving terraform output parameters: %w", err)
+			}
+
+			return environmentOutputs(config, resourceGroupId, outputs), nil
+		}
+
+		log.Printf("no deployment result found for terraform environment '%s', getting its outputs", env.Name)
+	}
+
+	outputsResponse, err := envClient.Outputs().Get(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed getting outputs: %w", err)
 	}
@@ -313,13 +358,172 @@ func (m *manager) Outputs(
 		return nil, fmt.Errorf("failed resolving output parameters: %w", err)
 	}
 
+	return environmentOutputs(config, resourceGroupId, outputs), nil
+}
+
+// runnerType returns the runner of the environment definition of the environment. Environments which definition can't be
+// found, ex) removed from the catalog, are assumed to be deployed by the ARM runner.
+func (m *manager) runnerType(
+	ctx context.Context,
+	config *Config,
+	env *devcentersdk.Environment,
+) devcentersdk.EnvironmentDefinitionRunner {
+	if env.CatalogName == "" || env.EnvironmentDefinitionName == "" {
+		return devcentersdk.EnvironmentDefinitionRunnerArm
+	}
+
+	envDefinition, err := m.client.DevCenterByName(config.Name).
+		ProjectByName(config.Project).
+		CatalogByName(env.CatalogName).
+		EnvironmentDefinitionByName(env.EnvironmentDefinitionName).
+		Get(ctx)
+	if err != nil {
+		log.Printf(
+			"failed getting environment definition '%s' of environment '%s': %v",
+			env.EnvironmentDefinitionName,
+			env.Name,
+			err,
+		)
+		return devcentersdk.EnvironmentDefinitionRunnerArm
+	}
+
+	return envDefinition.RunnerType()
+}
+
+// latestDeployResult returns the most recent succeeded deployment of the operations that has a result, nil when none
+func latestDeployResult(operations []*devcentersdk.EnvironmentOperation) *devcentersdk.EnvironmentOperation {
+	var latest *devcentersdk.EnvironmentOperation
+	for _, operation := range operations {
+		if operation.Kind != devcentersdk.EnvironmentOperationKindDeploy ||
+			!strings.EqualFold(operation.Status, string(devcentersdk.ProvisioningStateSucceeded)) ||
+			operation.Result == nil {
+			continue
+		}
+
+		if latest == nil || operationTime(operation).After(operationTime(latest)) {
+			latest = operation
+		}
+	}
+
+	return latest
+}
+
+func operationTime(operation *devcentersdk.EnvironmentOperation) time.Time {
+	switch {
+	case operation.EndTime != nil:
+		return *operation.EndTime
+	case operation.StartTime != nil:
+		return *operation.StartTime
+	default:
+		return time.Time{}
+	}
+}
+
+// CompletedDeployments gets the successful ARM deployments of the resource group of the specified environment that
+// hold its outputs, most recent first.
+// Without hint, the latest deployment tagged by ADE for the environment is returned, or when there's none, the latest
+// untagged deployment of the ADE Bicep runner. With a hint, all the deployments whose name contains the hint are
+// returned.
+func (m *manager) CompletedDeployments(
+	ctx context.Context,
+	config *Config,
+	env *devcentersdk.Environment,
+	hint string,
+) ([]*azapi.ResourceDeployment, error) {
+	resourceGroupId, err := devcentersdk.NewResourceGroupId(env.ResourceGroupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing resource group id: %w", err)
+	}
+
+	scope := m.deploymentManager.ResourceGroupScope(resourceGroupId.SubscriptionId, resourceGroupId.Name)
+	deployments, err := scope.ListDeployments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing deployments: %w", err)
+	}
+
+	slices.SortFunc(deployments, func(x, y *azapi.ResourceDeployment) int {
+		return y.Timestamp.Compare(x.Timestamp)
+	})
+
+	matchingDeployments := []*azapi.ResourceDeployment{}
+	var bicepDeployment *azapi.ResourceDeployment
+
+	for _, deployment := range deployments {
+		if deployment.ProvisioningState != azapi.DeploymentProvisioningStateSucceeded {
+			continue
+		}
+
+		if hint != "" {
+			if strings.Contains(deployment.Name, hint) {
+				matchingDeployments = append(matchingDeployments, deployment)
+			}
+
+			continue
+		}
+
+		if isEnvironmentDeployment(deployment, config, env) {
+			return []*azapi.ResourceDeployment{deployment}, nil
+		}
+
+		if bicepDeployment == nil && bicepDeploymentNameRegex.MatchString(deployment.Name) {
+			bicepDeployment = deployment
+		}
+	}
+
+	if bicepDeployment != nil {
+		matchingDeployments = append(matchingDeployments, bicepDeployment)
+	}
+
+	if len(matchingDeployments) == 0 {
+		return nil, fmt.Errorf("environment '%s': %w", env.Name, infra.ErrDeploymentsNotFound)
+	}
+
+	return matchingDeployments, nil
+}
+
+// DeploymentOutputs gets the outputs of the specified environment from one of its ARM deployments
+func (m *manager) DeploymentOutputs(
+	ctx context.Context,
+	config *Config,
+	env *devcentersdk.Environment,
+	deployment *azapi.ResourceDeployment,
+) (map[string]provisioning.OutputParameter, error) {
+	resourceGroupId, err := devcentersdk.NewResourceGroupId(env.ResourceGroupId)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing resource group id: %w", err)
+	}
+
+	outputs, err := createDeploymentOutputParameters(azapi.CreateDeploymentOutput(deployment.Outputs))
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving output parameters of deployment '%s': %w", deployment.Name, err)
+	}
+
+	return environmentOutputs(config, resourceGroupId, outputs), nil
+}
+
+// environmentOutputs applies the output mappings to the outputs of an environment, and sets the subscription and
+// resource group of the environment.
+func environmentOutputs(
+	config *Config,
+	resourceGroupId *devcentersdk.ResourceGroupId,
+	outputs map[string]provisioning.OutputParameter,
+) map[string]provisioning.OutputParameter {
+	outputs = applyOutputMappings(outputs, config.OutputMappings)
+
 	// Set up AZURE_SUBSCRIPTION_ID and AZURE_RESOURCE_GROUP environment variables
 	// These are required for azd deploy to work as expected
-	if _, exists := outputs[environment.SubscriptionIdEnvVarName]; !exists {
-		outputs[environment.SubscriptionIdEnvVarName] = provisioning.OutputParameter{
-			Type:  provisioning.ParameterTypeString,
-			Value: resourceGroupId.SubscriptionId,
-		}
+	// ADE always deploys the environment in the deployment target subscription of its environment type, which is the
+	// subscription of its resource group, so hooks and service deployments always target this subscription.
+	if output, exists := outputs[environment.SubscriptionIdEnvVarName]; exists &&
+		!strings.EqualFold(fmt.Sprint(output.Value), resourceGroupId.SubscriptionId) {
+		log.Printf(
+			"ignoring output %s '%v', the environment is deployed in subscription '%s'",
+			environment.SubscriptionIdEnvVarName, output.Value, resourceGroupId.SubscriptionId)
+	}
+
+	outputs[environment.SubscriptionIdEnvVarName] = provisioning.OutputParameter{
+		Type:  provisioning.ParameterTypeString,
+		Value: resourceGroupId.SubscriptionId,
 	}
 
 	if _, exists := outputs[environment.ResourceGroupEnvVarName]; !exists {
@@ -329,7 +533,52 @@ func (m *manager) Outputs(
 		}
 	}
 
-	return outputs, nil
+	return outputs
+}
+
+// applyOutputMappings renames and filters the outputs based on the configured output mappings.
+// Output names are matched case-insensitively since the outputs have already been normalized to upper case.
+func applyOutputMappings(
+	outputs map[string]provisioning.OutputParameter,
+	outputMappings map[string]string,
+) map[string]provisioning.OutputParameter {
+	if len(outputMappings) == 0 {
+		return outputs
+	}
+
+	mappings := map[string]string{}
+	for output, envVar := range outputMappings {
+		mappings[strings.ToUpper(output)] = envVar
+	}
+
+	dropUnmapped := false
+	if envVar, has := mappings["*"]; has && envVar == "" {
+		dropUnmapped = true
+	}
+
+	mappedOutputs := map[string]provisioning.OutputParameter{}
+	for key, output := range outputs {
+		envVar, has := mappings[key]
+		if !has {
+			if !dropUnmapped {
+				// Explicitly mapped outputs take precedence over outputs with the same name
+				if _, exists := mappedOutputs[key]; !exists {
+					mappedOutputs[key] = output
+				}
+			}
+
+			continue
+		}
+
+		if envVar == "" {
+			log.Printf("dropping devcenter output '%s' based on output mappings", key)
+			continue
+		}
+
+		mappedOutputs[envVar] = output
+	}
+
+	return mappedOutputs
 }
 
 func mapDevCenterTypeToParamType(devCenterType devcentersdk.OutputParameterType) (provisioning.ParameterType, error) {
@@ -376,3 +625,93 @@ func createOutputParameters(
 
 	return outputParams, nil
 }
+
+// Creates a normalized view of the outputs of a Terraform deployment, with upper case names like the outputs of ADE.
+func createTerraformOutputParameters(
+	terraformOutputs map[string]devcentersdk.TerraformOutput,
+) (map[string]provisioning.OutputParameter, error) {
+	outputParams := map[string]provisioning.OutputParameter{}
+
+	for key, terraformOutput := range terraformOutputs {
+		paramType, err := mapTerraformTypeToParamType(terraformOutput.Type)
+		if err != nil {
+			return nil, fmt.Errorf("output '%s': %w", key, err)
+		}
+
+		outputParams[strings.ToUpper(key)] = provisioning.OutputParameter{
+			Type:  paramType,
+			Value: terraformOutput.Value,
+		}
+	}
+
+	return outputParams, nil
+}
+
+// mapTerraformTypeToParamType maps the type constraint of a Terraform output, a primitive type like "string" or a
+// collection type like ["list", "string"], to the type of the output parameter.
+func mapTerraformTypeToParamType(terraformType json.RawMessage) (provisioning.ParameterType, error) {
+	var typeName string
+	if err := json.Unmarshal(terraformType, &typeName); err != nil {
+		var collectionType []json.RawMessage
+		if err := json.Unmarshal(terraformType, &collectionType); err != nil || len(collectionType) == 0 {
+			return "", fmt.Errorf("unexpected output parameter type: '%s'", string(terraformType))
+		}
+
+		if err := json.Unmarshal(collectionType[0], &typeName); err != nil {
+			return "", fmt.Errorf("unexpected output parameter type: '%s'", string(terraformType))
+		}
+	}
+
+	switch typeName {
+	case "string":
+		return provisioning.ParameterTypeString, nil
+	case "bool":
+		return provisioning.ParameterTypeBoolean, nil
+	case "number":
+		return provisioning.ParameterTypeNumber, nil
+	case "list", "tuple", "set":
+		return provisioning.ParameterTypeArray, nil
+	case "map", "object":
+		return provisioning.ParameterTypeObject, nil
+	default:
+		return "", fmt.Errorf("unexpected output parameter type: '%s'", string(terraformType))
+	}
+}
+
+// Creates a normalized view of the outputs of an ARM deployment, with upper case names like the outputs of ADE.
+func createDeploymentOutputParameters(
+	deploymentOutputs map[string]azapi.AzCliDeploymentOutput,
+) (map[string]provisioning.OutputParameter, error) {
+	outputParams := map[string]provisioning.OutputParameter{}
+
+	for key, deploymentOutput := range deploymentOutputs {
+		paramType, err := mapArmTypeToParamType(deploymentOutput.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		outputParams[strings.ToUpper(key)] = provisioning.OutputParameter{
+			Type:  paramType,
+			Value: deploymentOutput.Value,
+		}
+	}
+
+	return outputParams, nil
+}
+
+func mapArmTypeToParamType(armType string) (provisioning.ParameterType, error) {
+	switch strings.ToLower(armType) {
+	case "string", "securestring":
+		return provisioning.ParameterTypeString, nil
+	case "bool":
+		return provisioning.ParameterTypeBoolean, nil
+	case "int":
+		return provisioning.ParameterTypeNumber, nil
+	case "object", "secureobject":
+		return provisioning.ParameterTypeObject, nil
+	case "array":
+		return provisioning.ParameterTypeArray, nil
+	default:
+		return "", fmt.Errorf("unexpected output parameter type: '%s'", armType)
+	}
+}
@@ -4,10 +4,21 @@
 package devcenter
 
 import (
+	"context"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk/fake"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,3 +78,295 @@ func Test_Map_Outputs(t *testing.T) {
 		Value: map[string]interface{}{"key1": "value1", "key2": "value2"},
 	}, outputs["TEST_OBJECT"])
 }
+
+func Test_Apply_Output_Mappings(t *testing.T) {
+	outputs := map[string]provisioning.OutputParameter{
+		"WEBURI": {
+			Type:  provisioning.ParameterTypeString,
+			Value: "https://web.example.com",
+		},
+		"INTERNALID": {
+			Type:  provisioning.ParameterTypeString,
+			Value: "1234",
+		},
+		"AZURE_LOCATION": {
+			Type:  provisioning.ParameterTypeString,
+			Value: "eastus2",
+		},
+	}
+
+	t.Run("NoMappings", func(t *testing.T) {
+		require.Equal(t, outputs, applyOutputMappings(outputs, nil))
+	})
+
+	t.Run("RenameAndFilter", func(t *testing.T) {
+		mapped := applyOutputMappings(outputs, map[string]string{
+			"webUri":     "SERVICE_WEB_ENDPOINT_URL",
+			"internalId": "",
+		})
+
+		require.Equal(t, map[string]provisioning.OutputParameter{
+			"SERVICE_WEB_ENDPOINT_URL": outputs["WEBURI"],
+			"AZURE_LOCATION":           outputs["AZURE_LOCATION"],
+		}, mapped)
+	})
+
+	t.Run("DropUnmapped", func(t *testing.T) {
+		mapped := applyOutputMappings(outputs, map[string]string{
+			"webUri": "SERVICE_WEB_ENDPOINT_URL",
+			"*":      "",
+		})
+
+		require.Equal(t, map[string]provisioning.OutputParameter{
+			"SERVICE_WEB_ENDPOINT_URL": outputs["WEBURI"],
+		}, mapped)
+	})
+
+	t.Run("MappedOutputTakesPrecedence", func(t *testing.T) {
+		mapped := applyOutputMappings(outputs, map[string]string{
+			"webUri": "AZURE_LOCATION",
+		})
+
+		require.Equal(t, map[string]provisioning.OutputParameter{
+			"AZURE_LOCATION": outputs["WEBURI"],
+			"INTERNALID":     outputs["INTERNALID"],
+		}, mapped)
+	})
+}
+
+func Test_Manager_CompletedDeployments(t *testing.T) {
+	config := &Config{Name: "DEV_CENTER_01", Project: "Project1"}
+	env := &devcentersdk.Environment{
+		Name:            "test",
+		EnvironmentType: "Dev",
+		ResourceGroupId: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP_NAME",
+	}
+	adeTags := map[string]*string{
+		DeploymentTagDevCenterName:    to.Ptr("DEV_CENTER_01"),
+		DeploymentTagDevCenterProject: to.Ptr("Project1"),
+		DeploymentTagEnvironmentType:  to.Ptr("Dev"),
+		DeploymentTagEnvironmentName:  to.Ptr("test"),
+	}
+	now := time.Now().UTC()
+
+	newDeployment := func(
+		name string, age time.Duration, state armresources.ProvisioningState, tags map[string]*string,
+	) *armresources.DeploymentExtended {
+		return &armresources.DeploymentExtended{
+			ID: to.Ptr(
+				"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP_NAME/providers/" +
+					"Microsoft.Resources/deployments/" + name),
+			Name: to.Ptr(name),
+			Type: to.Ptr("Microsoft.Resources/deployments"),
+			Tags: tags,
+			Properties: &armresources.DeploymentPropertiesExtended{
+				ProvisioningState: to.Ptr(state),
+				Timestamp:         to.Ptr(now.Add(-age)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		deployments   []*armresources.DeploymentExtended
+		hint          string
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name: "LatestTaggedDeployment",
+			deployments: []*armresources.DeploymentExtended{
+				newDeployment("tagged-old", 2*time.Hour, armresources.ProvisioningStateSucceeded, adeTags),
+				newDeployment("tagged-new", time.Hour, armresources.ProvisioningStateSucceeded, adeTags),
+				newDeployment("tagged-failed", 0, armresources.ProvisioningStateFailed, adeTags),
+				newDeployment("2024-01-01-1", 0, armresources.ProvisioningStateSucceeded, nil),
+			},
+			expectedNames: []string{"tagged-new"},
+		},
+		{
+			name: "UntaggedBicepDeployment",
+			deployments: []*armresources.DeploymentExtended{
+				newDeployment("2024-01-01-1", 2*time.Hour, armresources.ProvisioningStateSucceeded, nil),
+				newDeployment("2024-01-02-1", time.Hour, armresources.ProvisioningStateSucceeded, nil),
+				newDeployment("nested-module", 0, armresources.ProvisioningStateSucceeded, nil),
+			},
+			expectedNames: []string{"2024-01-02-1"},
+		},
+		{
+			name: "Hint",
+			deployments: []*armresources.DeploymentExtended{
+				newDeployment("webapp-1", 2*time.Hour, armresources.ProvisioningStateSucceeded, nil),
+				newDeployment("webapp-2", time.Hour, armresources.ProvisioningStateSucceeded, nil),
+				newDeployment("tagged", 0, armresources.ProvisioningStateSucceeded, adeTags),
+			},
+			hint:          "webapp",
+			expectedNames: []string{"webapp-2", "webapp-1"},
+		},
+		{
+			name: "NotFound",
+			deployments: []*armresources.DeploymentExtended{
+				newDeployment("nested-module", 0, armresources.ProvisioningStateSucceeded, nil),
+			},
+			expectedErr: infra.ErrDeploymentsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodGet &&
+					strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Resources/deployments/")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.DeploymentListResult{
+					Value: tt.deployments,
+				})
+			})
+
+			resourceService := azapi.NewResourceService(
+				mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+			deploymentService := azapi.NewStandardDeployments(
+				mockContext.SubscriptionCredentialProvider,
+				mockContext.ArmClientOptions,
+				resourceService,
+				cloud.AzurePublic(),
+				mockContext.Clock,
+			)
+			deploymentManager := infra.NewDeploymentManager(
+				deploymentService, infra.NewAzureResourceManager(resourceService, deploymentService), mockContext.Console)
+
+			manager := NewManager(nil, deploymentManager)
+			deployments, err := manager.CompletedDeployments(*mockContext.Context, config, env, tt.hint)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, deployment := range deployments {
+				names = append(names, deployment.Name)
+			}
+			require.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func Test_Map_DeploymentOutputs(t *testing.T) {
+	outputs, err := createDeploymentOutputParameters(map[string]azapi.AzCliDeploymentOutput{
+		"webUri":  {Type: "String", Value: "https://contoso.com"},
+		"enabled": {Type: "Bool", Value: true},
+		"count":   {Type: "Int", Value: float64(2)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]provisioning.OutputParameter{
+		"WEBURI":  {Type: provisioning.ParameterTypeString, Value: "https://contoso.com"},
+		"ENABLED": {Type: provisioning.ParameterTypeBoolean, Value: true},
+		"COUNT":   {Type: provisioning.ParameterTypeNumber, Value: float64(2)},
+	}, outputs)
+
+	_, err = createDeploymentOutputParameters(map[string]azapi.AzCliDeploymentOutput{
+		"unknown": {Type: "Unknown", Value: 1},
+	})
+	require.Error(t, err)
+}
+
+func Test_Map_TerraformOutputs(t *testing.T) {
+	outputs, err := createTerraformOutputParameters(map[string]devcentersdk.TerraformOutput{
+		"web_uri":  {Type: []byte(`"string"`), Value: "https://contoso.com"},
+		"enabled":  {Type: []byte(`"bool"`), Value: true},
+		"count":    {Type: []byte(`"number"`), Value: float64(2)},
+		"hosts":    {Type: []byte(`["list","string"]`), Value: []any{"web", "api"}},
+		"settings": {Type: []byte(`["object",{"tier":"string"}]`), Value: map[string]any{"tier": "basic"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]provisioning.OutputParameter{
+		"WEB_URI":  {Type: provisioning.ParameterTypeString, Value: "https://contoso.com"},
+		"ENABLED":  {Type: provisioning.ParameterTypeBoolean, Value: true},
+		"COUNT":    {Type: provisioning.ParameterTypeNumber, Value: float64(2)},
+		"HOSTS":    {Type: provisioning.ParameterTypeArray, Value: []any{"web", "api"}},
+		"SETTINGS": {Type: provisioning.ParameterTypeObject, Value: map[string]any{"tier": "basic"}},
+	}, outputs)
+
+	_, err = createTerraformOutputParameters(map[string]devcentersdk.TerraformOutput{
+		"unknown": {Type: []byte(`"dynamic"`), Value: 1},
+	})
+	require.Error(t, err)
+}
+
+func Test_Manager_Outputs(t *testing.T) {
+	config := &Config{Name: "DEV_CENTER_01", Project: "Project1"}
+
+	// deploy deploys the environment with the definition on a fake dev center, and returns its outputs
+	deploy := func(t *testing.T, definition *devcentersdk.EnvironmentDefinition) map[string]provisioning.OutputParameter {
+		server := fake.NewServer(t, "DEV_CENTER_01")
+		project := server.AddProject("Project1")
+		project.AddEnvironmentType("Dev", "/subscriptions/"+fake.SubscriptionId)
+		project.AddEnvironmentDefinition(definition)
+		project.SetOutputs("env1", map[string]devcentersdk.OutputParameter{
+			"WEBSITE_URL": {Type: devcentersdk.OutputParameterTypeString, Value: "https://arm.contoso.com"},
+		})
+		project.SetTerraformOutputs("env1", map[string]devcentersdk.TerraformOutput{
+			"website_url": {Type: []byte(`"string"`), Value: "https://terraform.contoso.com"},
+			"hosts":       {Type: []byte(`["tuple",["string"]]`), Value: []any{"web"}},
+		})
+
+		client, err := server.Client()
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		envClient := client.DevCenterByName(config.Name).ProjectByName(config.Project).
+			EnvironmentsByUser(fake.UserId).EnvironmentByName("env1")
+		poller, err := envClient.BeginPut(ctx, devcentersdk.EnvironmentSpec{
+			CatalogName:               definition.CatalogName,
+			EnvironmentDefinitionName: definition.Name,
+			EnvironmentType:           "Dev",
+		})
+		require.NoError(t, err)
+
+		for !poller.Done() {
+			_, err := poller.Poll(ctx)
+			require.NoError(t, err)
+		}
+
+		env, err := envClient.Get(ctx)
+		require.NoError(t, err)
+
+		outputs, err := NewManager(client, nil).Outputs(ctx, config, env)
+		require.NoError(t, err)
+
+		return outputs
+	}
+
+	t.Run("Arm", func(t *testing.T) {
+		outputs := deploy(t, &devcentersdk.EnvironmentDefinition{
+			CatalogName:  "catalog",
+			Name:         "WebApp",
+			TemplatePath: "azuredeploy.json",
+		})
+
+		require.Equal(t, "https://arm.contoso.com", outputs["WEBSITE_URL"].Value)
+		require.NotContains(t, outputs, "HOSTS")
+		require.Equal(t, fake.SubscriptionId, outputs["AZURE_SUBSCRIPTION_ID"].Value)
+	})
+
+	t.Run("Terraform", func(t *testing.T) {
+		outputs := deploy(t, &devcentersdk.EnvironmentDefinition{
+			CatalogName:  "catalog",
+			Name:         "WebApp",
+			TemplatePath: "main.tf",
+			Runner:       devcentersdk.EnvironmentDefinitionRunnerTerraform,
+		})
+
+		require.Equal(t, provisioning.OutputParameter{
+			Type:  provisioning.ParameterTypeString,
+			Value: "https://terraform.contoso.com",
+		}, outputs["WEBSITE_URL"])
+		require.Equal(t, provisioning.OutputParameter{
+			Type:  provisioning.ParameterTypeArray,
+			Value: []any{"web"},
+		}, outputs["HOSTS"])
+		require.Equal(t, fake.SubscriptionId, outputs["AZURE_SUBSCRIPTION_ID"].Value)
+	})
+}
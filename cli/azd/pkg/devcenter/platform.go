@@ -5,11 +5,14 @@ package devcenter
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -70,6 +73,17 @@ func (p *Platform) ConfigureContainer(container *ioc.NestedContainer) error {
 			EnvironmentType:       os.Getenv(DevCenterEnvTypeEnvName),
 			EnvironmentDefinition: os.Getenv(DevCenterEnvDefinitionEnvName),
 			User:                  os.Getenv(DevCenterEnvUser),
+			Timeout:               os.Getenv(DevCenterTimeoutEnvName),
+			PollInterval:          os.Getenv(DevCenterPollIntervalEnvName),
+		}
+
+		if value := os.Getenv(DevCenterExpirationEnvName); value != "" {
+			hours, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s '%s', expected a number of hours", DevCenterExpirationEnvName, value)
+			}
+
+			envVarConfig.ExpirationHours = hours
 		}
 
 		azdCtx, _ := lazyAzdCtx.GetValue()
@@ -165,7 +179,12 @@ func (p *Platform) ConfigureContainer(container *ioc.NestedContainer) error {
 	container.MustRegisterNamedTransient(string(SourceKindDevCenter), NewTemplateSource)
 
 	container.MustRegisterSingleton(NewManager)
+	container.MustRegisterSingleton(NewRemoteEnvironments)
+	container.MustRegisterSingleton(NewSchedules)
 	container.MustRegisterSingleton(NewPrompter)
+	container.MustRegisterSingleton(func(subscriptionsManager *account.SubscriptionsManager) SubscriptionResolver {
+		return subscriptionsManager
+	})
 
 	// Other devcenter components
 	container.MustRegisterSingleton(func(
@@ -13,6 +13,25 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+var (
+	projectPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "DEVCENTER_PROJECT",
+		Description: "The name of the dev center project to use.",
+		Commands:    []string{"provision", "up", "init"},
+	})
+	environmentTypePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "DEVCENTER_ENVIRONMENT_TYPE",
+		Description: "The name of the dev center environment type to use.",
+		Commands:    []string{"provision", "up"},
+	})
+	environmentDefinitionPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "DEVCENTER_ENVIRONMENT_DEFINITION",
+		Description: "The name of the dev center environment definition to use.",
+		Commands:    []string{"provision", "up", "init"},
+	})
 )
 
 // Prompter provides a common set of methods for prompting the user for devcenter configuration values
@@ -47,7 +66,7 @@ func (p *Prompter) PromptForConfig(ctx context.Context, config *Config) error {
 	}
 
 	if config.EnvironmentDefinition == "" {
-		envDefinition, err := p.PromptEnvironmentDefinition(ctx, config.Name, config.Project)
+		envDefinition, err := p.promptEnvironmentDefinition(ctx, config.Name, config.Project, config.Catalog)
 		if err != nil {
 			return err
 		}
@@ -106,6 +125,7 @@ func (p *Prompter) PromptProject(ctx context.Context, devCenterName string) (*de
 	}
 
 	selected, err := p.console.Select(ctx, input.ConsoleOptions{
+		Id:      projectPromptId,
 		Message: "Select a project:",
 		Options: projectNames,
 	})
@@ -153,6 +173,7 @@ func (p *Prompter) PromptEnvironmentType(
 	}
 
 	selected, err := p.console.Select(ctx, input.ConsoleOptions{
+		Id:      environmentTypePromptId,
 		Message: "Select an environment type:",
 		Options: envTypeNames,
 	})
@@ -169,17 +190,53 @@ func (p *Prompter) PromptEnvironmentDefinition(
 	ctx context.Context,
 	devCenterName, projectName string,
 ) (*devcentersdk.EnvironmentDefinition, error) {
-	envDefinitionsResponse, err := p.devCenterClient.
+	return p.promptEnvironmentDefinition(ctx, devCenterName, projectName, "")
+}
+
+// The number of environment definitions requested per page, so the progress of large projects is reported while loading
+const environmentDefinitionsPageSize = 100
+
+// promptEnvironmentDefinition prompts the user to select an environment definition of the project, only the ones of the
+// catalog when set so the server filters them.
+func (p *Prompter) promptEnvironmentDefinition(
+	ctx context.Context,
+	devCenterName, projectName, catalogName string,
+) (*devcentersdk.EnvironmentDefinition, error) {
+	projectClient := p.devCenterClient.
 		DevCenterByName(devCenterName).
-		ProjectByName(projectName).
-		EnvironmentDefinitions().
-		Get(ctx)
+		ProjectByName(projectName)
 
-	if err != nil {
-		return nil, err
+	var listBuilder *devcentersdk.EnvironmentDefinitionListRequestBuilder
+	if catalogName != "" {
+		listBuilder = projectClient.CatalogByName(catalogName).EnvironmentDefinitions()
+	} else {
+		listBuilder = projectClient.EnvironmentDefinitions()
+	}
+
+	pager := listBuilder.Top(environmentDefinitionsPageSize).NewListPager()
+	environmentDefinitions := []*devcentersdk.EnvironmentDefinition{}
+	loading := false
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			if loading {
+				p.console.StopSpinner(ctx, "", input.Step)
+			}
+			return nil, err
+		}
+
+		environmentDefinitions = append(environmentDefinitions, page.Value...)
+		if pager.More() {
+			loading = true
+			p.console.ShowSpinner(
+				ctx, fmt.Sprintf("Loading environment definitions (%d)", len(environmentDefinitions)), input.Step)
+		}
+	}
+
+	if loading {
+		p.console.StopSpinner(ctx, "", input.Step)
 	}
 
-	environmentDefinitions := envDefinitionsResponse.Value
 	slices.SortFunc(environmentDefinitions, func(x, y *devcentersdk.EnvironmentDefinition) int {
 		return strings.Compare(x.Name, y.Name)
 	})
@@ -208,6 +265,7 @@ func (p *Prompter) PromptEnvironmentDefinition(
 	}
 
 	selected, err := p.console.Select(ctx, input.ConsoleOptions{
+		Id:      environmentDefinitionPromptId,
 		Message: "Select an environment definition:",
 		Options: envDefinitionNames,
 	})
@@ -229,10 +287,24 @@ func (p *Prompter) PromptParameters(
 	paramValues := map[string]any{}
 
 	for _, param := range envDef.Parameters {
+		// Values set with environment variables, ex) in CI, take precedence over the values stored in the environment
+		// configuration, the same way as for the environment variable mappings of bicep parameters
+		if envValue := env.Getenv(ParameterEnvName(param.Id)); envValue != "" {
+			value, err := parseParameterValue(param, envValue)
+			if err != nil {
+				return nil, err
+			}
+
+			addSecretParameter(param, value)
+			paramValues[param.Id] = value
+			continue
+		}
+
 		paramPath := fmt.Sprintf("%s.%s", ProvisionParametersConfigPath, param.Id)
 		paramValue, exists := env.Config.Get(paramPath)
 
 		if exists {
+			addSecretParameter(param, paramValue)
 			paramValues[param.Id] = paramValue
 			continue
 		}
@@ -266,6 +338,7 @@ func (p *Prompter) PromptParameters(
 			Options:      param.Allowed,
 			Message:      fmt.Sprintf("Enter a value for %s", param.Name),
 			Help:         param.Description,
+			IsPassword:   param.Secret(),
 		}
 
 		switch param.Type {
@@ -275,7 +348,7 @@ func (p *Prompter) PromptParameters(
 				return nil, fmt.Errorf("failed to prompt for %s: %w", param.Name, err)
 			}
 			paramValue = confirmValue
-		case devcentersdk.ParameterTypeString:
+		case devcentersdk.ParameterTypeString, devcentersdk.ParameterTypeSecureString:
 			if len(param.Allowed) > 0 {
 				selectedIndex, err := p.console.Select(ctx, promptOptions)
 				if err != nil {
@@ -307,8 +380,36 @@ func (p *Prompter) PromptParameters(
 			return nil, fmt.Errorf("failed to prompt for %s, unsupported parameter type: %s", param.Name, param.Type)
 		}
 
+		addSecretParameter(param, paramValue)
 		paramValues[param.Id] = paramValue
 	}
 
 	return paramValues, nil
 }
+
+// addSecretParameter registers the value of a secret parameter, so it's masked in the output of azd.
+func addSecretParameter(param devcentersdk.Parameter, value any) {
+	if secret, isString := value.(string); isString && param.Secret() {
+		output.AddSecret(secret)
+	}
+}
+
+// parseParameterValue converts the string value of an environment definition parameter to the type of the parameter
+func parseParameterValue(param devcentersdk.Parameter, value string) (any, error) {
+	switch param.Type {
+	case devcentersdk.ParameterTypeBool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to bool: %w", param.Name, err)
+		}
+		return boolValue, nil
+	case devcentersdk.ParameterTypeInt:
+		numValue, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s to int: %w", param.Name, err)
+		}
+		return numValue, nil
+	default:
+		return value, nil
+	}
+}
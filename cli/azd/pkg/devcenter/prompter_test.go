@@ -5,6 +5,8 @@ package devcenter
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockdevcentersdk"
 	"github.com/stretchr/testify/require"
@@ -184,6 +187,53 @@ func Test_Prompt_EnvironmentDefinitions(t *testing.T) {
 		require.ErrorContains(t, err, "no environment definitions found")
 		require.Nil(t, selectedEnvironmentType)
 	})
+
+	t.Run("Paged", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		selectedDevCenter := mockDevCenterList[0]
+		selectedProject := mockProjects[1]
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+
+		requests := []*http.Request{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				request.URL.Path == fmt.Sprintf("/projects/%s/environmentDefinitions", selectedProject.Name)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			requests = append(requests, request)
+			response := devcentersdk.EnvironmentDefinitionListResponse{Value: mockEnvDefinitions[2:]}
+			if request.URL.Query().Get("$skipToken") == "" {
+				nextLink := *request.URL
+				nextLink.RawQuery = "$skipToken=page2"
+				response = devcentersdk.EnvironmentDefinitionListResponse{
+					Value:    mockEnvDefinitions[:2],
+					NextLink: nextLink.String(),
+				}
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+
+		mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "Select an environment definition")
+		}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+			require.Len(t, options.Options, len(mockEnvDefinitions))
+			return 2, nil
+		})
+
+		prompter := newPrompterForTest(t, mockContext, &mockDevCenterManager{})
+		selectedEnvironmentDefinition, err := prompter.PromptEnvironmentDefinition(
+			*mockContext.Context,
+			selectedDevCenter.Name,
+			selectedProject.Name,
+		)
+		require.NoError(t, err)
+		require.Equal(t, mockEnvDefinitions[2], selectedEnvironmentDefinition)
+
+		require.Len(t, requests, 2)
+		require.Equal(t, "100", requests[0].URL.Query().Get("$top"))
+		require.Equal(t, "page2", requests[1].URL.Query().Get("$skipToken"))
+	})
 }
 
 func Test_Prompt_Config(t *testing.T) {
@@ -413,6 +463,75 @@ func Test_Prompt_Parameters(t *testing.T) {
 		require.Equal(t, "value1", values["param1"])
 		require.Equal(t, "value2", values["param2"])
 	})
+
+	t.Run("WithEnvVarValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prompter := newPrompterForTest(t, mockContext, nil)
+
+		env := environment.NewWithValues("Test", map[string]string{
+			"AZURE_DEVCENTER_PARAM_PARAM1":  "envValue1",
+			"AZURE_DEVCENTER_PARAM_PARAM_2": "true",
+			"AZURE_DEVCENTER_PARAM_PARAM3":  "123",
+		})
+		envDefinition := &devcentersdk.EnvironmentDefinition{
+			Parameters: []devcentersdk.Parameter{
+				{
+					Id:   "param1",
+					Name: "Param 1",
+					Type: devcentersdk.ParameterTypeString,
+				},
+				{
+					Id:   "param-2",
+					Name: "Param 2",
+					Type: devcentersdk.ParameterTypeBool,
+				},
+				{
+					Id:   "param3",
+					Name: "Param 3",
+					Type: devcentersdk.ParameterTypeInt,
+				},
+			},
+		}
+
+		// Environment variables take precedence over values stored in the environment configuration
+		_ = env.Config.Set("provision.parameters.param1", "value1")
+
+		values, err := prompter.PromptParameters(*mockContext.Context, env, envDefinition)
+		require.NoError(t, err)
+		require.Equal(t, "envValue1", values["param1"])
+		require.Equal(t, true, values["param-2"])
+		require.Equal(t, 123, values["param3"])
+	})
+
+	t.Run("WithSecretValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prompter := newPrompterForTest(t, mockContext, nil)
+
+		mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "Admin Password")
+		}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+			require.True(t, options.IsPassword)
+			return "P@ssw0rd-devcenter", nil
+		})
+
+		env := environment.New("Test")
+		envDefinition := &devcentersdk.EnvironmentDefinition{
+			Parameters: []devcentersdk.Parameter{
+				{
+					Id:   "adminPassword",
+					Name: "Admin Password",
+					Type: devcentersdk.ParameterTypeString,
+				},
+			},
+		}
+
+		values, err := prompter.PromptParameters(*mockContext.Context, env, envDefinition)
+		require.NoError(t, err)
+		require.Equal(t, "P@ssw0rd-devcenter", values["adminPassword"])
+
+		// The value of the secret parameter is masked in the output
+		require.Equal(t, "password: "+output.RedactedMask, output.Redact("password: P@ssw0rd-devcenter"))
+	})
 }
 
 func newPrompterForTest(t *testing.T, mockContext *mocks.MockContext, manager Manager) *Prompter {
@@ -5,24 +5,51 @@ package devcenter
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"maps"
+	"net/url"
 	"os"
+	"reflect"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/journal"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 )
 
+var deploymentPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "DEVCENTER_DEPLOYMENT",
+	Description: "The name of the dev center deployment to use when several match the environment.",
+	Commands:    []string{"provision", "up", "env refresh", "show"},
+})
+
+var userPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "DEVCENTER_USER",
+	Description: "Whether to provision or delete the dev center environment of the user set with --user.",
+	Commands:    []string{"provision", "up", "down"},
+})
+
 const (
 	ProvisionParametersConfigPath string                    = "provision.parameters"
 	ProvisionKindDevCenter        provisioning.ProviderKind = "devcenter"
@@ -32,19 +59,35 @@ const (
 	DeploymentTagDevCenterProject = "AdeProjectName"
 	DeploymentTagEnvironmentType  = "AdeEnvironmentTypeName"
 	DeploymentTagEnvironmentName  = "AdeEnvironmentName"
+
+	// The maximum time to wait for the deployment or deletion of an environment, unless configured
+	defaultOperationTimeout = 2 * time.Hour
+	// The time between the checks of the status of an environment, unless configured
+	defaultEnvironmentPollInterval = 5 * time.Second
 )
 
+// SubscriptionResolver resolves the details, like the friendly name, of a subscription
+type SubscriptionResolver interface {
+	GetSubscription(ctx context.Context, subscriptionId string) (*account.Subscription, error)
+}
+
 // ProvisionProvider is a devcenter provider for provisioning ADE environments
 type ProvisionProvider struct {
-	console           input.Console
-	env               *environment.Environment
-	envManager        environment.Manager
-	config            *Config
-	devCenterClient   devcentersdk.DevCenterClient
-	deploymentManager *infra.DeploymentManager
-	manager           Manager
-	prompter          *Prompter
-	options           provisioning.Options
+	console              input.Console
+	env                  *environment.Environment
+	envManager           environment.Manager
+	config               *Config
+	devCenterClient      devcentersdk.DevCenterClient
+	deploymentManager    *infra.DeploymentManager
+	manager              Manager
+	prompter             *Prompter
+	subscriptionResolver SubscriptionResolver
+	resourceService      *azapi.ResourceService
+	parameterSecrets     *provisioning.ParameterSecrets
+	gitCli               *git.Cli
+	lazyProjectConfig    *lazy.Lazy[*project.ProjectConfig]
+	projectPath          string
+	options              provisioning.Options
 }
 
 // NewProvisionProvider creates a new devcenter provider
@@ -57,16 +100,26 @@ func NewProvisionProvider(
 	deploymentManager *infra.DeploymentManager,
 	manager Manager,
 	prompter *Prompter,
+	subscriptionResolver SubscriptionResolver,
+	resourceService *azapi.ResourceService,
+	parameterSecrets *provisioning.ParameterSecrets,
+	gitCli *git.Cli,
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
 ) provisioning.Provider {
 	return &ProvisionProvider{
-		console:           console,
-		env:               env,
-		envManager:        envManager,
-		config:            config,
-		devCenterClient:   devCenterClient,
-		deploymentManager: deploymentManager,
-		manager:           manager,
-		prompter:          prompter,
+		console:              console,
+		env:                  env,
+		envManager:           envManager,
+		config:               config,
+		devCenterClient:      devCenterClient,
+		deploymentManager:    deploymentManager,
+		manager:              manager,
+		prompter:             prompter,
+		subscriptionResolver: subscriptionResolver,
+		resourceService:      resourceService,
+		parameterSecrets:     parameterSecrets,
+		gitCli:               gitCli,
+		lazyProjectConfig:    lazyProjectConfig,
 	}
 }
 
@@ -77,12 +130,76 @@ func (p *ProvisionProvider) Name() string {
 
 // Initialize initializes the provider
 func (p *ProvisionProvider) Initialize(ctx context.Context, projectPath string, options provisioning.Options) error {
+	p.projectPath = projectPath
 	p.options = options
 
-	return p.EnsureEnv(ctx)
+	if err := p.EnsureEnv(ctx); err != nil {
+		return err
+	}
+
+	// The user set with --user only applies to this command, it isn't saved in the environment configuration
+	if p.impersonating() {
+		p.config.User = options.User
+	}
+
+	return nil
 }
 
-// State returns the state of the environment from the most recent ARM deployment
+// impersonating returns true when operating on the environment of another user, set with --user
+func (p *ProvisionProvider) impersonating() bool {
+	return p.options.User != "" && !strings.EqualFold(p.options.User, "me")
+}
+
+// ensureUserAccess checks that the signed-in user is allowed to operate on the environment of the user set with --user,
+// like the admins of the project, and confirms the operation. action is the operation, ex) delete
+func (p *ProvisionProvider) ensureUserAccess(ctx context.Context, action string, confirm bool) error {
+	if !p.impersonating() {
+		return nil
+	}
+
+	permissions := p.devCenterClient.
+		DevCenterByName(p.config.Name).
+		ProjectByName(p.config.Project).
+		Permissions()
+
+	hasAccess := permissions.HasAdminWriteAccess
+	if action == "delete" {
+		hasAccess = permissions.HasAdminDeleteAccess
+	}
+
+	if !hasAccess(ctx) {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"you don't have permission to %s the environments of other users in project '%s'", action, p.config.Project),
+			Suggestion: "Suggestion: ask for the DevCenter Project Admin role on the project, " +
+				"or remove --user to use your own environment.",
+		}
+	}
+
+	if !confirm {
+		return nil
+	}
+
+	confirmed, err := p.console.Confirm(ctx, input.ConsoleOptions{
+		Id: userPromptId,
+		Message: fmt.Sprintf(
+			"Environment '%s' is owned by user %s. Are you sure you want to %s it?", p.env.Name(), p.options.User, action),
+		DefaultValue: false,
+	})
+	if err != nil {
+		return fmt.Errorf("prompting for confirmation: %w", err)
+	}
+
+	if !confirmed {
+		return fmt.Errorf("%s of the environment of user %s cancelled", action, p.options.User)
+	}
+
+	return nil
+}
+
+// State returns the state of the environment from its most recent successful ARM deployment, or the outputs reported
+// by ADE when the environment has no matching deployment, like environments deployed by a non ARM runner. The hint of
+// the options selects the deployment by name, prompting when multiple deployments match.
 func (p *ProvisionProvider) State(
 	ctx context.Context,
 	options *provisioning.StateOptions,
@@ -103,14 +220,70 @@ func (p *ProvisionProvider) State(
 		return nil, fmt.Errorf("failed getting environment: %w", err)
 	}
 
-	outputs, err := p.manager.Outputs(ctx, p.config, environment)
+	var hint string
+	if options != nil {
+		hint = options.Hint()
+	}
+
+	deployments, err := p.manager.CompletedDeployments(ctx, p.config, environment, hint)
+	if errors.Is(err, infra.ErrDeploymentsNotFound) && hint == "" {
+		log.Printf("no deployment found for environment '%s', using the environment outputs", envName)
+
+		outputs, err := p.manager.Outputs(ctx, p.config, environment)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environment outputs: %w", err)
+		}
+
+		return &provisioning.StateResult{
+			State: &provisioning.State{
+				Outputs: outputs,
+			},
+		}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed getting environment deployments: %w", err)
+	}
+
+	deployment := deployments[0]
+	if len(deployments) > 1 {
+		p.console.Message(ctx, output.WithWarningFormat("WARNING: Multiple matching deployments were found\n"))
+
+		deploymentOptions := make([]string, len(deployments))
+		for i, d := range deployments {
+			deploymentOptions[i] = fmt.Sprintf(
+				"%d. %s (%s)", i+1, d.Name, d.Timestamp.Local().Format("1/2/2006, 3:04 PM"))
+		}
+
+		selected, err := p.console.Select(ctx, input.ConsoleOptions{
+			Id:      deploymentPromptId,
+			Message: "Select a deployment to continue:",
+			Options: deploymentOptions,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		deployment = deployments[selected]
+		p.console.Message(ctx, "")
+	}
+
+	log.Printf("refreshing environment '%s' from deployment '%s'", envName, deployment.Name)
+
+	outputs, err := p.manager.DeploymentOutputs(ctx, p.config, environment, deployment)
 	if err != nil {
-		return nil, fmt.Errorf("failed getting environment outputs: %w", err)
+		return nil, fmt.Errorf("failed getting deployment outputs: %w", err)
+	}
+
+	resources := make([]provisioning.Resource, 0, len(deployment.Resources))
+	for _, resource := range deployment.Resources {
+		if resource.ID != nil {
+			resources = append(resources, provisioning.Resource{Id: *resource.ID})
+		}
 	}
 
 	return &provisioning.StateResult{
 		State: &provisioning.State{
-			Outputs: outputs,
+			Outputs:   outputs,
+			Resources: resources,
 		},
 	}, nil
 }
@@ -121,6 +294,10 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 		return nil, fmt.Errorf("invalid devcenter configuration, %w", err)
 	}
 
+	if err := p.ensureUserAccess(ctx, "provision", true); err != nil {
+		return nil, err
+	}
+
 	if hasInfraTemplates(p.options.Path) {
 		//nolint:lll
 		warningMsg := fmt.Sprintf(
@@ -145,15 +322,26 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 		return nil, fmt.Errorf("failed getting environment definition: %w", err)
 	}
 
+	log.Printf("deploying environment definition '%s' with the %s runner", envDef.Name, envDef.RunnerType())
+
 	paramValues, err := p.prompter.PromptParameters(ctx, p.env, envDef)
 	if err != nil {
 		return nil, fmt.Errorf("failed prompting for parameters: %w", err)
 	}
 
-	for key, value := range paramValues {
-		path := fmt.Sprintf("%s.%s", ProvisionParametersConfigPath, key)
-		if err := p.env.Config.Set(path, value); err != nil {
-			return nil, fmt.Errorf("failed setting config value %s: %w", path, err)
+	for _, param := range envDef.Parameters {
+		value, has := paramValues[param.Id]
+		if !has {
+			continue
+		}
+
+		if err := p.setParameterConfig(ctx, param, value); err != nil {
+			return nil, err
+		}
+
+		// The values of secret parameters are stored in Key Vault, and referenced from the config
+		if paramValues[param.Id], err = p.parameterSecrets.Resolve(ctx, value); err != nil {
+			return nil, err
 		}
 	}
 
@@ -183,10 +371,67 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 		EnvironmentType:           p.config.EnvironmentType,
 		EnvironmentDefinitionName: p.config.EnvironmentDefinition,
 		Parameters:                paramValues,
+		Tags:                      p.environmentTags(ctx),
+		ExpirationDate:            expirationDate(p.config, existingEnv, time.Now()),
+	}
+
+	settings, err := p.operationSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the deployment started by a previous run that timed out or was interrupted, instead of starting a new one
+	if p.options.Resume && existingEnv != nil && !provisioningCompleted(existingEnv.ProvisioningState) &&
+		existingEnv.ProvisioningState != devcentersdk.ProvisioningStateDeleting {
+		p.displayDeploymentTarget(ctx)
+
+		spinnerMessage = fmt.Sprintf("Resuming deployment of devcenter environment %s", output.WithHighLightFormat(envName))
+		p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+		return p.waitForDeployment(ctx, envName, envDef, envSpec, settings, spinnerMessage,
+			func(ctx context.Context) error {
+				return p.waitForEnvironment(ctx, envName, settings.pollInterval)
+			})
+	}
+
+	if p.options.Resume {
+		log.Printf("no running deployment of devcenter environment '%s' to resume", envName)
+	}
+
+	// Skip the Put when the existing environment was already deployed with the same spec.
+	// ADE redeploys the environment on every Put, which can take several minutes even without changes.
+	if existingEnv != nil && !p.options.IgnoreDeploymentState && environmentSpecMatches(existingEnv, envSpec) {
+		// The resource group is still tagged with the commit of the project
+		p.tagResourceGroup(ctx, existingEnv, envSpec.Tags)
+
+		outputs, err := p.manager.Outputs(ctx, p.config, existingEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environment outputs: %w", err)
+		}
+
+		return &provisioning.DeployResult{
+			Deployment: &provisioning.Deployment{
+				Parameters: createInputParameters(envDef, paramValues),
+				Outputs:    outputs,
+			},
+			SkippedReason: provisioning.DeploymentStateSkipped,
+		}, nil
 	}
 
+	p.displayDeploymentTarget(ctx)
 	p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
 
+	// Only an environment created by this deployment is removed when the command is interrupted
+	if existingEnv == nil {
+		journal.Record(ctx, journal.Entry{
+			Kind:      journal.EntryDevCenterEnvironment,
+			DevCenter: p.config.Name,
+			Project:   p.config.Project,
+			User:      p.config.User,
+			Name:      envName,
+		})
+	}
+
 	poller, err := p.devCenterClient.
 		DevCenterByName(p.config.Name).
 		ProjectByName(p.config.Project).
@@ -201,17 +446,89 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 
 	p.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
 
-	pollingContext, cancel := context.WithCancel(ctx)
-	defer cancel()
-
 	spinnerMessage = "Deploying dev center environment"
 	p.console.ShowSpinner(ctx, spinnerMessage, input.Step)
 
-	go p.pollForEnvironment(pollingContext, envName)
+	return p.waitForDeployment(ctx, envName, envDef, envSpec, settings, spinnerMessage,
+		func(ctx context.Context) error {
+			_, err := poller.PollUntilDone(ctx, settings.pollUntilDoneOptions())
+			return err
+		})
+}
+
+// setParameterConfig persists the value of a parameter in the environment config. The values of secret parameters are
+// stored in the Key Vault of the environment, configured or created with infra.parametersKeyVault, and only a reference
+// to the secret is persisted, or in the local user vault when no Key Vault is available.
+func (p *ProvisionProvider) setParameterConfig(ctx context.Context, param devcentersdk.Parameter, value any) error {
+	path := fmt.Sprintf("%s.%s", ProvisionParametersConfigPath, param.Id)
+
+	secret, isString := value.(string)
+	if !param.Secret() || !isString || keyvault.IsAzureKeyVaultSecret(secret) {
+		if err := p.env.Config.Set(path, value); err != nil {
+			return fmt.Errorf("failed setting config value %s: %w", path, err)
+		}
+
+		return nil
+	}
+
+	reference, err := p.parameterSecrets.Store(ctx, p.env, param.Id, secret, p.options.ParametersKeyVault)
+	if errors.Is(err, provisioning.ErrParametersKeyVaultUnavailable) {
+		log.Printf("storing secret parameter '%s' in the local user vault: %v", param.Id, err)
+		if err := p.env.Config.SetSecret(path, secret); err != nil {
+			return fmt.Errorf("failed setting config secret %s: %w", path, err)
+		}
+
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := p.env.Config.Set(path, reference); err != nil {
+		return fmt.Errorf("failed setting config value %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// waitForDeployment waits for the deployment of the environment to finish, reporting its progress, and returns the
+// outputs of the environment. The spinner with the given message is stopped when the wait is over.
+func (p *ProvisionProvider) waitForDeployment(
+	ctx context.Context,
+	envName string,
+	envDef *devcentersdk.EnvironmentDefinition,
+	envSpec devcentersdk.EnvironmentSpec,
+	settings operationSettings,
+	spinnerMessage string,
+	wait func(ctx context.Context) error,
+) (*provisioning.DeployResult, error) {
+	pollingContext, cancel := context.WithCancel(ctx)
+	pollingDone := make(chan struct{})
+	go func() {
+		defer close(pollingDone)
+		p.pollForEnvironment(pollingContext, envName, settings.pollInterval)
+	}()
+
+	timeoutContext, cancelTimeout := context.WithTimeout(ctx, settings.timeout)
+	defer cancelTimeout()
+
+	err := wait(timeoutContext)
+
+	// The progress display restores the screen when the polling stops, before anything else is written to the console
+	cancel()
+	<-pollingDone
 
-	_, err = poller.PollUntilDone(ctx, nil)
 	if err != nil {
 		p.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+
+		if operationTimedOut(ctx, err) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"devcenter environment '%s' is still being deployed after %s", envName, settings.timeout),
+				Suggestion: "The deployment continues in Azure Deployment Environments. Run 'azd provision --resume' " +
+					"to wait for it to finish, or increase the timeout with '--timeout'.",
+			}
+		}
+
 		return nil, fmt.Errorf("failed creating environment: %w", err)
 	}
 
@@ -229,6 +546,8 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 
 	p.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
 
+	p.tagResourceGroup(ctx, environment, envSpec.Tags)
+
 	outputs, err := p.manager.Outputs(ctx, p.config, environment)
 	if err != nil {
 		return nil, fmt.Errorf("failed getting environment outputs: %w", err)
@@ -236,7 +555,7 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 
 	result := &provisioning.DeployResult{
 		Deployment: &provisioning.Deployment{
-			Parameters: createInputParameters(envDef, paramValues),
+			Parameters: createInputParameters(envDef, envSpec.Parameters),
 			Outputs:    outputs,
 		},
 	}
@@ -244,11 +563,140 @@ func (p *ProvisionProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 	return result, nil
 }
 
+// displayDeploymentTarget displays the subscription where ADE deploys the environments of the configured environment
+// type. The deployment continues when the subscription can't be resolved, ADE validates the environment type.
+func (p *ProvisionProvider) displayDeploymentTarget(ctx context.Context) {
+	subscription, err := p.deploymentTarget(ctx)
+	if err != nil {
+		log.Printf("failed resolving the deployment target of environment type '%s': %v", p.config.EnvironmentType, err)
+		return
+	}
+
+	target := subscription.Id
+	if subscription.Name != "" {
+		target = fmt.Sprintf("%s (%s)", subscription.Name, subscription.Id)
+	}
+
+	p.console.Message(ctx, fmt.Sprintf("Environment Type: %s", output.WithHighLightFormat(p.config.EnvironmentType)))
+	p.console.Message(ctx, fmt.Sprintf("Target Subscription: %s\n", output.WithHighLightFormat(target)))
+}
+
+// deploymentTarget returns the subscription targeted by the configured environment type. The friendly name of the
+// subscription is empty when it can't be resolved.
+func (p *ProvisionProvider) deploymentTarget(ctx context.Context) (*account.Subscription, error) {
+	envTypes, err := p.devCenterClient.
+		DevCenterByName(p.config.Name).
+		ProjectByName(p.config.Project).
+		EnvironmentTypes().
+		Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment types: %w", err)
+	}
+
+	index := slices.IndexFunc(envTypes.Value, func(envType *devcentersdk.EnvironmentType) bool {
+		return strings.EqualFold(envType.Name, p.config.EnvironmentType)
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("environment type '%s' not found in project '%s'", p.config.EnvironmentType, p.config.Project)
+	}
+
+	subscriptionId, err := deploymentTargetSubscriptionId(envTypes.Value[index].DeploymentTargetId)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := p.subscriptionResolver.GetSubscription(ctx, subscriptionId)
+	if err != nil {
+		log.Printf("failed resolving the name of subscription '%s': %v", subscriptionId, err)
+		return &account.Subscription{Id: subscriptionId}, nil
+	}
+
+	return subscription, nil
+}
+
+// deploymentTargetSubscriptionId returns the subscription id of the deployment target of an environment type, in the
+// form /subscriptions/{subscriptionId}.
+func deploymentTargetSubscriptionId(deploymentTargetId string) (string, error) {
+	parts := strings.Split(strings.Trim(deploymentTargetId, "/"), "/")
+	if len(parts) < 2 || !strings.EqualFold(parts[0], "subscriptions") || parts[1] == "" {
+		return "", fmt.Errorf("invalid deployment target '%s'", deploymentTargetId)
+	}
+
+	return parts[1], nil
+}
+
 // Preview previews the deployment of the environment from the configured environment definition
 func (p *ProvisionProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
 	return nil, fmt.Errorf("preview is not supported for devcenter")
 }
 
+// History returns the deployment operations of the ADE environment, most recent first.
+// Outputs are only reported for the most recent successful deployment since ADE does not keep historical outputs.
+func (p *ProvisionProvider) History(ctx context.Context) ([]*provisioning.DeploymentHistoryEntry, error) {
+	if err := p.config.EnsureValid(); err != nil {
+		return nil, fmt.Errorf("invalid devcenter configuration, %w", err)
+	}
+
+	envClient := p.devCenterClient.
+		DevCenterByName(p.config.Name).
+		ProjectByName(p.config.Project).
+		EnvironmentsByUser(p.config.User).
+		EnvironmentByName(p.env.Name())
+
+	operations, err := envClient.Operations().Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment operations: %w", err)
+	}
+
+	history := []*provisioning.DeploymentHistoryEntry{}
+	for _, operation := range operations.Value {
+		if operation.Kind != devcentersdk.EnvironmentOperationKindDeploy {
+			continue
+		}
+
+		entry := &provisioning.DeploymentHistoryEntry{
+			Id:        operation.OperationId,
+			Status:    operation.Status,
+			Initiator: operation.CreatedByObjectId,
+		}
+
+		if operation.StartTime != nil {
+			entry.Timestamp = *operation.StartTime
+
+			if operation.EndTime != nil {
+				entry.Duration = operation.EndTime.Sub(*operation.StartTime).Round(time.Second).String()
+			}
+		}
+
+		history = append(history, entry)
+	}
+
+	slices.SortFunc(history, func(x, y *provisioning.DeploymentHistoryEntry) int {
+		return y.Timestamp.Compare(x.Timestamp)
+	})
+
+	for _, entry := range history {
+		if entry.Status != string(devcentersdk.ProvisioningStateSucceeded) {
+			continue
+		}
+
+		environment, err := envClient.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environment: %w", err)
+		}
+
+		outputs, err := p.manager.Outputs(ctx, p.config, environment)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environment outputs: %w", err)
+		}
+
+		entry.Outputs = outputs
+		break
+	}
+
+	return history, nil
+}
+
 // Destroy destroys the environment by deleting the ADE environment
 func (p *ProvisionProvider) Destroy(
 	ctx context.Context,
@@ -258,6 +706,16 @@ func (p *ProvisionProvider) Destroy(
 		return nil, fmt.Errorf("invalid devcenter configuration, %w", err)
 	}
 
+	settings, err := p.operationSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// The confirmation of the deletion below shows the user, a separate confirmation isn't needed
+	if err := p.ensureUserAccess(ctx, "delete", false); err != nil {
+		return nil, err
+	}
+
 	envName := p.env.Name()
 	spinnerMessage := fmt.Sprintf("Deleting devcenter environment %s", output.WithHighLightFormat(envName))
 
@@ -273,6 +731,9 @@ func (p *ProvisionProvider) Destroy(
 		p.console.Message(ctx,
 			fmt.Sprintf("Environment Definition: %s", output.WithHighLightFormat(p.config.EnvironmentDefinition)),
 		)
+		if p.impersonating() {
+			p.console.Message(ctx, fmt.Sprintf("User: %s", output.WithHighLightFormat(p.config.User)))
+		}
 		p.console.Message(ctx, fmt.Sprintf("Environment: %s\n", output.WithHighLightFormat(envName)))
 
 		confirm, err := p.console.Confirm(ctx, input.ConsoleOptions{
@@ -327,9 +788,21 @@ func (p *ProvisionProvider) Destroy(
 		return nil, fmt.Errorf("failed deleting environment: %w", err)
 	}
 
-	_, err = poller.PollUntilDone(ctx, nil)
+	timeoutContext, cancelTimeout := context.WithTimeout(ctx, settings.timeout)
+	defer cancelTimeout()
+
+	_, err = poller.PollUntilDone(timeoutContext, settings.pollUntilDoneOptions())
 	if err != nil {
 		p.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+
+		if operationTimedOut(ctx, err) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("devcenter environment '%s' is still being deleted after %s", envName, settings.timeout),
+				Suggestion: "The deletion continues in Azure Deployment Environments. Check the status of the " +
+					"environment in the developer portal, or increase the timeout with '--timeout'.",
+			}
+		}
+
 		return nil, fmt.Errorf("failed deleting environment: %w", err)
 	}
 
@@ -409,8 +882,105 @@ func (p *ProvisionProvider) EnsureEnv(ctx context.Context) error {
 	return nil
 }
 
+// operationSettings configures how long azd waits for the deployment or deletion of an environment, and how often it checks
+// its status.
+type operationSettings struct {
+	timeout time.Duration
+	// Zero when not configured, the default interval of each poll is used then.
+	pollInterval time.Duration
+}
+
+func (s operationSettings) pollUntilDoneOptions() *runtime.PollUntilDoneOptions {
+	if s.pollInterval == 0 {
+		return nil
+	}
+
+	return &runtime.PollUntilDoneOptions{Frequency: s.pollInterval}
+}
+
+// operationSettings gets the timeout and poll interval from the flags, or else from the devcenter configuration.
+func (p *ProvisionProvider) operationSettings() (operationSettings, error) {
+	timeout, err := configuredDuration(p.options.Timeout, p.config.Timeout, "timeout")
+	if err != nil {
+		return operationSettings{}, err
+	}
+
+	if timeout == 0 {
+		timeout = defaultOperationTimeout
+	}
+
+	pollInterval, err := configuredDuration(p.options.PollInterval, p.config.PollInterval, "pollInterval")
+	if err != nil {
+		return operationSettings{}, err
+	}
+
+	return operationSettings{timeout: timeout, pollInterval: pollInterval}, nil
+}
+
+func configuredDuration(flagValue time.Duration, configValue string, name string) (time.Duration, error) {
+	if flagValue > 0 {
+		return flagValue, nil
+	}
+
+	if configValue == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(configValue)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid devcenter %s '%s', expected a duration like 30m", name, configValue)
+	}
+
+	return duration, nil
+}
+
+// operationTimedOut returns true when the wait for an operation failed because its timeout expired, rather than because
+// the command was interrupted.
+func operationTimedOut(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// provisioningCompleted returns true when the environment isn't being deployed or deleted anymore
+func provisioningCompleted(state devcentersdk.ProvisioningState) bool {
+	return state == devcentersdk.ProvisioningStateSucceeded ||
+		state == devcentersdk.ProvisioningStateFailed ||
+		state == devcentersdk.ProvisioningStateCanceled
+}
+
+// waitForEnvironment polls the environment until its deployment completes
+func (p *ProvisionProvider) waitForEnvironment(ctx context.Context, envName string, pollInterval time.Duration) error {
+	if pollInterval == 0 {
+		pollInterval = defaultEnvironmentPollInterval
+	}
+
+	for {
+		environment, err := p.devCenterClient.
+			DevCenterByName(p.config.Name).
+			ProjectByName(p.config.Project).
+			EnvironmentsByUser(p.config.User).
+			EnvironmentByName(envName).
+			Get(ctx)
+		if err != nil {
+			return fmt.Errorf("failed getting environment: %w", err)
+		}
+
+		switch environment.ProvisioningState {
+		case devcentersdk.ProvisioningStateSucceeded:
+			return nil
+		case devcentersdk.ProvisioningStateFailed, devcentersdk.ProvisioningStateCanceled:
+			return fmt.Errorf("deployment of the environment %s", strings.ToLower(string(environment.ProvisioningState)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
 // Polls for the ADE environment and ARM deployment to be created
-func (p *ProvisionProvider) pollForEnvironment(ctx context.Context, envName string) {
+func (p *ProvisionProvider) pollForEnvironment(ctx context.Context, envName string, pollInterval time.Duration) {
 	// Disable reporting progress if needed
 	if use, err := strconv.ParseBool(os.Getenv("AZD_DEBUG_PROVISION_PROGRESS_DISABLE")); err == nil && use {
 		log.Println("Disabling progress reporting since AZD_DEBUG_PROVISION_PROGRESS_DISABLE was set")
@@ -418,7 +988,10 @@ func (p *ProvisionProvider) pollForEnvironment(ctx context.Context, envName stri
 	}
 
 	initialDelay := 3 * time.Second
-	regularDelay := 5 * time.Second
+	regularDelay := defaultEnvironmentPollInterval
+	if pollInterval > 0 {
+		regularDelay = pollInterval
+	}
 	timer := time.NewTimer(initialDelay)
 	pollStartTime := time.Now()
 
@@ -463,8 +1036,9 @@ func (p *ProvisionProvider) pollForEnvironment(ctx context.Context, envName stri
 
 			timer.Stop()
 
-			// Finally polling for provisioning progress
-			go p.pollForProgress(ctx, deployment)
+			// Finally polling for provisioning progress, until the deployment is over
+			p.pollForProgress(ctx, deployment)
+			return
 		}
 	}
 }
@@ -480,15 +1054,14 @@ func (p *ProvisionProvider) pollForProgress(ctx context.Context, deployment infr
 	// Report incremental progress
 	progressDisplay := p.deploymentManager.ProgressDisplay(deployment)
 
-	initialDelay := 3 * time.Second
-	regularDelay := 10 * time.Second
-	timer := time.NewTimer(initialDelay)
+	timer := time.NewTimer(infra.ProgressInitialPollDelay)
 	queryStartTime := time.Now()
 
 	for {
 		select {
 		case <-ctx.Done():
 			timer.Stop()
+			progressDisplay.Close(ctx)
 			return
 		case <-timer.C:
 			if err := progressDisplay.ReportProgress(ctx, &queryStartTime); err != nil {
@@ -496,7 +1069,7 @@ func (p *ProvisionProvider) pollForProgress(ctx context.Context, deployment infr
 				log.Printf("error while reporting progress: %v", err)
 			}
 
-			timer.Reset(regularDelay)
+			timer.Reset(progressDisplay.NextPollDelay())
 		}
 	}
 }
@@ -518,6 +1091,154 @@ func createInputParameters(
 	return inputParams
 }
 
+// environmentTags returns the tags tracing the devcenter environment back to the azd environment, and to the template and
+// source control commit it's deployed from. The tags which can't be resolved, ex) outside of a git repository, are
+// left out.
+func (p *ProvisionProvider) environmentTags(ctx context.Context) map[string]string {
+	tags := map[string]string{
+		azure.TagKeyAzdEnvName: p.env.Name(),
+	}
+
+	if projectConfig, err := p.lazyProjectConfig.GetValue(); err == nil &&
+		projectConfig.Metadata != nil && projectConfig.Metadata.Template != "" {
+		tags[azure.TagKeyAzdTemplate] = projectConfig.Metadata.Template
+	}
+
+	if p.projectPath == "" {
+		return tags
+	}
+
+	if repoUrl, err := p.gitCli.GetRemoteUrl(ctx, p.projectPath, "origin"); err == nil {
+		tags[azure.TagKeyAzdRepoUrl] = redactRepoUrl(repoUrl)
+	} else {
+		log.Printf("failed getting the remote url of the project for the environment tags: %v", err)
+	}
+
+	gitSha := provisioning.SourceVersion()
+	if gitSha == "" {
+		if headCommit, err := p.gitCli.GetHeadCommit(ctx, p.projectPath); err == nil {
+			gitSha = headCommit
+		} else {
+			log.Printf("failed getting the head commit of the project for the environment tags: %v", err)
+		}
+	}
+
+	if gitSha != "" {
+		tags[azure.TagKeyAzdGitSha] = gitSha
+	}
+
+	return tags
+}
+
+// tagResourceGroup adds the tags to the resource group of the environment. ADE manages the resource group, so failures,
+// ex) when the environment type doesn't grant the permission to update tags, are only logged.
+func (p *ProvisionProvider) tagResourceGroup(
+	ctx context.Context,
+	environment *devcentersdk.Environment,
+	tags map[string]string,
+) {
+	if environment.ResourceGroupId == "" || len(tags) == 0 {
+		return
+	}
+
+	resourceGroupId, err := arm.ParseResourceID(environment.ResourceGroupId)
+	if err != nil {
+		log.Printf("failed parsing resource group id '%s': %v", environment.ResourceGroupId, err)
+		return
+	}
+
+	rgTags := map[string]*string{}
+	for key, value := range tags {
+		rgTags[key] = to.Ptr(value)
+	}
+
+	if err := p.resourceService.MergeResourceGroupTags(
+		ctx, resourceGroupId.SubscriptionID, resourceGroupId.ResourceGroupName, rgTags); err != nil {
+		log.Printf("failed tagging the resource group of devcenter environment '%s': %v", environment.Name, err)
+	}
+}
+
+// redactRepoUrl removes the credentials, ex) a token, from the url of a repository
+func redactRepoUrl(repoUrl string) string {
+	parsed, err := url.Parse(repoUrl)
+	if err != nil || parsed.User == nil {
+		return repoUrl
+	}
+
+	parsed.User = nil
+	return parsed.String()
+}
+
+// environmentSpecMatches returns true when the existing environment was successfully deployed with the same
+// catalog, environment definition, environment type, parameter values and tags as the desired spec. The git sha tag
+// isn't compared, as the commit of the project changes without any change to the environment. The spec doesn't match
+// when it extends the expiration of the environment, or sets one on an environment that doesn't expire.
+func environmentSpecMatches(existing *devcentersdk.Environment, spec devcentersdk.EnvironmentSpec) bool {
+	if existing.ProvisioningState != devcentersdk.ProvisioningStateSucceeded ||
+		!strings.EqualFold(existing.CatalogName, spec.CatalogName) ||
+		!strings.EqualFold(existing.EnvironmentDefinitionName, spec.EnvironmentDefinitionName) ||
+		!strings.EqualFold(existing.EnvironmentType, spec.EnvironmentType) {
+		return false
+	}
+
+	if spec.ExpirationDate != nil &&
+		(existing.ExpirationDate == nil || spec.ExpirationDate.After(*existing.ExpirationDate)) {
+		return false
+	}
+
+	if !tagsMatch(existing.Tags, spec.Tags) {
+		return false
+	}
+
+	// Parameter values are normalized through JSON since the existing values are deserialized from the data plane
+	// response (ex: numbers as float64) while the desired values come from prompts or environment config.
+	existingParams, err := normalizeParameters(existing.Parameters)
+	if err != nil {
+		return false
+	}
+
+	desiredParams, err := normalizeParameters(spec.Parameters)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(existingParams, desiredParams)
+}
+
+// tagsMatch returns true when the tags are the same, ignoring the git sha tag.
+func tagsMatch(existing map[string]string, desired map[string]string) bool {
+	withoutGitSha := func(tags map[string]string) map[string]string {
+		filtered := map[string]string{}
+		for key, value := range tags {
+			if key != azure.TagKeyAzdGitSha {
+				filtered[key] = value
+			}
+		}
+
+		return filtered
+	}
+
+	return maps.Equal(withoutGitSha(existing), withoutGitSha(desired))
+}
+
+func normalizeParameters(params map[string]any) (map[string]any, error) {
+	normalized := map[string]any{}
+	if len(params) == 0 {
+		return normalized, nil
+	}
+
+	jsonBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(jsonBytes, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
 // hasInfraTemplates returns true if the specified path contains any infrastructure templates
 func hasInfraTemplates(path string) bool {
 	if _, err := os.Stat(path); err != nil && errors.Is(err, os.ErrNotExist) {
@@ -532,7 +1253,58 @@ func hasInfraTemplates(path string) bool {
 	return len(entries) > 0
 }
 
+// Parameters gets the parameters of the environment definition with their current values, so they can be configured in CI.
+// Each parameter is mapped to its AZURE_DEVCENTER_PARAM_<ID> environment variable.
 func (p *ProvisionProvider) Parameters(ctx context.Context) ([]provisioning.Parameter, error) {
-	// not supported (no-op)
-	return nil, nil
+	if err := p.config.EnsureValid(); err != nil {
+		return nil, fmt.Errorf("invalid devcenter configuration, %w", err)
+	}
+
+	envDef, err := p.devCenterClient.
+		DevCenterByName(p.config.Name).
+		ProjectByName(p.config.Project).
+		CatalogByName(p.config.Catalog).
+		EnvironmentDefinitionByName(p.config.EnvironmentDefinition).
+		Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment definition: %w", err)
+	}
+
+	parameters := []provisioning.Parameter{}
+	for _, param := range envDef.Parameters {
+		// The environment name is always set from the azd environment
+		if param.Id == "environmentName" || param.ReadOnly {
+			continue
+		}
+
+		envVarName := ParameterEnvName(param.Id)
+		parameter := provisioning.Parameter{
+			Name:          param.Id,
+			Secret:        param.Secret(),
+			Value:         param.Default,
+			EnvVarMapping: []string{envVarName},
+		}
+
+		if envValue := p.env.Getenv(envVarName); envValue != "" {
+			value, err := parseParameterValue(param, envValue)
+			if err != nil {
+				return nil, err
+			}
+
+			parameter.Value = value
+			parameter.UsingEnvVarMapping = true
+		} else if value, has := p.env.Config.Get(fmt.Sprintf("%s.%s", ProvisionParametersConfigPath, param.Id)); has {
+			value, err := p.parameterSecrets.Resolve(ctx, value)
+			if err != nil {
+				return nil, err
+			}
+
+			parameter.Value = value
+			parameter.LocalPrompt = true
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters, nil
 }
@@ -5,20 +5,33 @@ package devcenter
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockdevcentersdk"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
@@ -109,6 +122,7 @@ func Test_ProvisionProvider_Deploy(t *testing.T) {
 			Return(outputParams, nil)
 
 		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListEnvironmentTypes(mockContext, config.Project, mockEnvironmentTypes)
 		mockdevcentersdk.MockGetEnvironmentDefinition(
 			mockContext,
 			config.Project,
@@ -139,6 +153,93 @@ func Test_ProvisionProvider_Deploy(t *testing.T) {
 		require.Len(t, result.Deployment.Parameters, len(mockEnvDefinitions[0].Parameters))
 	})
 
+	t.Run("Tags", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		t.Setenv("GITHUB_SHA", "")
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}
+		env := environment.New("test")
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("Outputs", *mockContext.Context, mock.Anything, mock.Anything).
+			Return(map[string]provisioning.OutputParameter{}, nil)
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListEnvironmentTypes(mockContext, config.Project, mockEnvironmentTypes)
+		mockdevcentersdk.MockGetEnvironmentDefinition(
+			mockContext,
+			config.Project,
+			config.Catalog,
+			config.EnvironmentDefinition,
+			mockEnvDefinitions[0],
+		)
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, config.User, env.Name(), mockEnvironments[0])
+		mockdevcentersdk.MockPutEnvironment(
+			mockContext,
+			config.Project,
+			config.User,
+			env.Name(),
+			&devcentersdk.OperationStatus{Id: "id", Name: env.Name(), Status: "Succeeded"},
+		)
+
+		var envSpec devcentersdk.EnvironmentSpec
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/environments/test")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&envSpec))
+			response, err := mocks.CreateHttpResponseWithBody(request, http.StatusCreated, &devcentersdk.OperationStatus{
+				Id: "id", Name: env.Name(), Status: "Succeeded",
+			})
+			response.Header.Set(
+				"Location", fmt.Sprintf("https://%s/projects/%s/operationstatuses/put", request.Host, config.Project))
+			return response, err
+		})
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+		provider.(*ProvisionProvider).projectPath = t.TempDir()
+
+		var rgTags armresources.TagsPatchResource
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPatch &&
+				strings.HasSuffix(request.URL.Path, "/resourceGroups/RESOURCE_GROUP_NAME/providers/Microsoft.Resources/tags/default")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&rgTags))
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.TagsResource{})
+		})
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "remote get-url origin")
+		}).Respond(exec.NewRunResult(0, "https://token@github.com/contoso/todo.git\n", ""))
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "rev-parse HEAD")
+		}).Respond(exec.NewRunResult(0, "0123abcd\n", ""))
+
+		_, err := provider.Deploy(*mockContext.Context)
+		require.NoError(t, err)
+
+		expected := map[string]string{
+			azure.TagKeyAzdEnvName:  "test",
+			azure.TagKeyAzdTemplate: "todo-nodejs-mongo@0.0.1-beta",
+			azure.TagKeyAzdRepoUrl:  "https://github.com/contoso/todo.git",
+			azure.TagKeyAzdGitSha:   "0123abcd",
+		}
+		require.Equal(t, expected, envSpec.Tags)
+
+		require.NotNil(t, rgTags.Operation)
+		require.Equal(t, armresources.TagsPatchOperationMerge, *rgTags.Operation)
+		require.Len(t, rgTags.Properties.Tags, len(expected))
+		for key, value := range expected {
+			require.Equal(t, value, *rgTags.Properties.Tags[key])
+		}
+	})
+
 	t.Run("SuccessWithPrompts", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		selectedEnvironmentTypeIndex := 2
@@ -209,12 +310,86 @@ func Test_ProvisionProvider_Deploy(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, result)
 		require.Equal(t, mockEnvironmentTypes[selectedEnvironmentTypeIndex].Name, config.EnvironmentType)
+		require.Contains(t, mockContext.Console.Output(), "Target Subscription: Contoso Dev (SUBSCRIPTION_02)\n")
 		require.Equal(t, result.Deployment.Outputs, outputParams)
 		require.Len(t, result.Deployment.Parameters, len(mockEnvDefinitions[3].Parameters))
 		require.Equal(t, "value", result.Deployment.Parameters["param01"].Value)
 		require.Equal(t, "value", result.Deployment.Parameters["param02"].Value)
 	})
 
+	t.Run("SkipUnchangedEnvironment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}
+		env := environment.New("test")
+
+		existingEnv := *mockEnvironments[0]
+		existingEnv.Parameters = map[string]any{
+			"repoUrl": "https://github.com/Azure-Samples/todo-nodejs-mongo",
+		}
+		// Deployed from another commit of the project
+		existingEnv.Tags = map[string]string{
+			azure.TagKeyAzdEnvName:  "test",
+			azure.TagKeyAzdTemplate: "todo-nodejs-mongo@0.0.1-beta",
+			azure.TagKeyAzdGitSha:   "0123abcd",
+		}
+		existingEnv.ResourceGroupId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP_NAME"
+
+		outputParams := map[string]provisioning.OutputParameter{
+			"PARAM_01": {Type: provisioning.ParameterTypeString, Value: "value1"},
+		}
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("Outputs",
+				*mockContext.Context,
+				mock.AnythingOfType("*devcenter.Config"),
+				mock.AnythingOfType("*devcentersdk.Environment")).
+			Return(outputParams, nil)
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockGetEnvironmentDefinition(
+			mockContext,
+			config.Project,
+			config.Catalog,
+			config.EnvironmentDefinition,
+			mockEnvDefinitions[0],
+		)
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, config.User, env.Name(), &existingEnv)
+
+		putCalled := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			putCalled = true
+			return mocks.CreateEmptyHttpResponse(request, http.StatusInternalServerError)
+		})
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+
+		rgTagged := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPatch &&
+				strings.HasSuffix(request.URL.Path, "/resourceGroups/RESOURCE_GROUP_NAME/providers/Microsoft.Resources/tags/default")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			rgTagged = true
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.TagsResource{})
+		})
+
+		result, err := provider.Deploy(*mockContext.Context)
+		require.NoError(t, err)
+		require.False(t, putCalled)
+		require.True(t, rgTagged)
+		require.Equal(t, provisioning.DeploymentStateSkipped, result.SkippedReason)
+		require.Equal(t, outputParams, result.Deployment.Outputs)
+	})
+
 	t.Run("FailedCreatingEnvironment", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		config := &Config{
@@ -230,6 +405,7 @@ func Test_ProvisionProvider_Deploy(t *testing.T) {
 		provider := newProvisionProviderForTest(t, mockContext, config, env, nil)
 
 		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListEnvironmentTypes(mockContext, config.Project, mockEnvironmentTypes)
 		mockdevcentersdk.MockGetEnvironmentDefinition(
 			mockContext,
 			config.Project,
@@ -256,6 +432,185 @@ func Test_ProvisionProvider_Deploy(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, result)
 	})
+
+	t.Run("ResumeRunningDeployment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+			PollInterval:          "10ms",
+		}
+		env := environment.New("test")
+
+		outputParams := map[string]provisioning.OutputParameter{
+			"PARAM_01": {Type: provisioning.ParameterTypeString, Value: "value1"},
+		}
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("Outputs",
+				*mockContext.Context,
+				mock.AnythingOfType("*devcenter.Config"),
+				mock.AnythingOfType("*devcentersdk.Environment")).
+			Return(outputParams, nil)
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListEnvironmentTypes(mockContext, config.Project, mockEnvironmentTypes)
+		mockdevcentersdk.MockGetEnvironmentDefinition(
+			mockContext,
+			config.Project,
+			config.Catalog,
+			config.EnvironmentDefinition,
+			mockEnvDefinitions[0],
+		)
+
+		// The environment is still being deployed on the first two checks
+		getCount := 0
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				request.URL.Path == fmt.Sprintf("/projects/%s/users/%s/environments/%s",
+					config.Project, config.User, env.Name())
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			getCount++
+			environment := *mockEnvironments[0]
+			if getCount <= 2 {
+				environment.ProvisioningState = devcentersdk.ProvisioningStateCreating
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, environment)
+		})
+
+		putCalled := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			putCalled = true
+			return mocks.CreateEmptyHttpResponse(request, http.StatusInternalServerError)
+		})
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+		provider.(*ProvisionProvider).options.Resume = true
+
+		result, err := provider.Deploy(*mockContext.Context)
+		require.NoError(t, err)
+		require.False(t, putCalled)
+		require.GreaterOrEqual(t, getCount, 3)
+		require.Equal(t, outputParams, result.Deployment.Outputs)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}
+		env := environment.New("test")
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListEnvironmentTypes(mockContext, config.Project, mockEnvironmentTypes)
+		mockdevcentersdk.MockGetEnvironmentDefinition(
+			mockContext,
+			config.Project,
+			config.Catalog,
+			config.EnvironmentDefinition,
+			mockEnvDefinitions[0],
+		)
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, config.User, env.Name(), nil)
+
+		// The operation never completes
+		operationStatus := &devcentersdk.OperationStatus{
+			Id:        "id",
+			Name:      mockEnvironments[0].Name,
+			Status:    "Running",
+			StartTime: time.Now(),
+		}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			response, err := mocks.CreateHttpResponseWithBody(request, http.StatusCreated, operationStatus)
+			response.Header.Set(
+				"Location",
+				fmt.Sprintf("https://%s/projects/%s/operationstatuses/put", request.Host, config.Project),
+			)
+
+			return response, err
+		})
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/operationstatuses/put")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, operationStatus)
+		})
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, nil)
+		provider.(*ProvisionProvider).options.Timeout = 100 * time.Millisecond
+		provider.(*ProvisionProvider).options.PollInterval = 10 * time.Millisecond
+
+		result, err := provider.Deploy(*mockContext.Context)
+		require.Nil(t, result)
+
+		var suggestionErr *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.ErrorContains(t, err, "devcenter environment 'test' is still being deployed after 100ms")
+		require.Contains(t, suggestionErr.Suggestion, "azd provision --resume")
+	})
+}
+
+func Test_ProvisionProvider_operationSettings(t *testing.T) {
+	tests := []struct {
+		name                 string
+		options              provisioning.Options
+		config               Config
+		expectedTimeout      time.Duration
+		expectedPollInterval time.Duration
+		expectedErr          string
+	}{
+		{
+			name:            "Defaults",
+			expectedTimeout: defaultOperationTimeout,
+		},
+		{
+			name:                 "Config",
+			config:               Config{Timeout: "90m", PollInterval: "10s"},
+			expectedTimeout:      90 * time.Minute,
+			expectedPollInterval: 10 * time.Second,
+		},
+		{
+			name:                 "FlagsOverrideConfig",
+			options:              provisioning.Options{Timeout: time.Hour, PollInterval: time.Minute},
+			config:               Config{Timeout: "90m", PollInterval: "10s"},
+			expectedTimeout:      time.Hour,
+			expectedPollInterval: time.Minute,
+		},
+		{
+			name:        "InvalidConfig",
+			config:      Config{Timeout: "soon"},
+			expectedErr: "invalid devcenter timeout 'soon'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &ProvisionProvider{config: &tt.config, options: tt.options}
+
+			settings, err := provider.operationSettings()
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedTimeout, settings.timeout)
+			require.Equal(t, tt.expectedPollInterval, settings.pollInterval)
+		})
+	}
 }
 
 func Test_ProvisionProvider_State(t *testing.T) {
@@ -279,6 +634,9 @@ func Test_ProvisionProvider_State(t *testing.T) {
 		}
 
 		manager := &mockDevCenterManager{}
+		manager.
+			On("CompletedDeployments", *mockContext.Context, mock.Anything, mock.Anything, "").
+			Return(nil, infra.ErrDeploymentsNotFound)
 		manager.
 			On("Outputs",
 				*mockContext.Context,
@@ -296,6 +654,115 @@ func Test_ProvisionProvider_State(t *testing.T) {
 		require.Len(t, result.State.Outputs, len(outputParams))
 	})
 
+	t.Run("LatestDeployment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}
+		env := environment.New("test")
+
+		deployment := &azapi.ResourceDeployment{
+			Name: "ade-deployment",
+			Resources: []*armresources.ResourceReference{
+				{ID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/RG/providers/Microsoft.Web/sites/web")},
+			},
+		}
+		outputParams := map[string]provisioning.OutputParameter{
+			"PARAM_01": {Type: provisioning.ParameterTypeString, Value: "value1"},
+		}
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("CompletedDeployments", *mockContext.Context, mock.Anything, mock.Anything, "").
+			Return([]*azapi.ResourceDeployment{deployment}, nil)
+		manager.
+			On("DeploymentOutputs", *mockContext.Context, mock.Anything, mock.Anything, deployment).
+			Return(outputParams, nil)
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, config.User, env.Name(), mockEnvironments[0])
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+		result, err := provider.State(*mockContext.Context, &provisioning.StateOptions{})
+		require.NoError(t, err)
+		require.Equal(t, outputParams, result.State.Outputs)
+		require.Len(t, result.State.Resources, 1)
+		manager.AssertNotCalled(t, "Outputs")
+	})
+
+	t.Run("MultipleDeploymentsMatchingHint", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}
+		env := environment.New("test")
+
+		deployments := []*azapi.ResourceDeployment{
+			{Name: "webapp-2", Timestamp: time.Now()},
+			{Name: "webapp-1", Timestamp: time.Now().Add(-time.Hour)},
+		}
+		outputParams := map[string]provisioning.OutputParameter{
+			"PARAM_01": {Type: provisioning.ParameterTypeString, Value: "value1"},
+		}
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("CompletedDeployments", *mockContext.Context, mock.Anything, mock.Anything, "webapp").
+			Return(deployments, nil)
+		manager.
+			On("DeploymentOutputs", *mockContext.Context, mock.Anything, mock.Anything, deployments[1]).
+			Return(outputParams, nil)
+
+		mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+			return options.Message == "Select a deployment to continue:"
+		}).Respond(1)
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, config.User, env.Name(), mockEnvironments[0])
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+		result, err := provider.State(*mockContext.Context, provisioning.NewStateOptions("webapp"))
+		require.NoError(t, err)
+		require.Equal(t, outputParams, result.State.Outputs)
+	})
+
+	t.Run("NoDeploymentMatchingHint", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		config := &Config{
+			Name:                  "DEV_CENTER_01",
+			Catalog:               "SampleCatalog",
+			Project:               "Project1",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}
+		env := environment.New("test")
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("CompletedDeployments", *mockContext.Context, mock.Anything, mock.Anything, "other").
+			Return(nil, infra.ErrDeploymentsNotFound)
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, config.User, env.Name(), mockEnvironments[0])
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+		result, err := provider.State(*mockContext.Context, provisioning.NewStateOptions("other"))
+		require.ErrorIs(t, err, infra.ErrDeploymentsNotFound)
+		require.Nil(t, result)
+		manager.AssertNotCalled(t, "Outputs")
+	})
+
 	t.Run("EnvironmentNotFound", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		config := &Config{
@@ -330,6 +797,9 @@ func Test_ProvisionProvider_State(t *testing.T) {
 		env := environment.New("test")
 
 		manager := &mockDevCenterManager{}
+		manager.
+			On("CompletedDeployments", *mockContext.Context, mock.Anything, mock.Anything, "").
+			Return(nil, infra.ErrDeploymentsNotFound)
 		manager.
 			On("Outputs",
 				*mockContext.Context,
@@ -426,6 +896,91 @@ func Test_ProvisionProvider_Destroy(t *testing.T) {
 	})
 }
 
+func Test_ProvisionProvider_OtherUser(t *testing.T) {
+	config := &Config{
+		Name:                  "DEV_CENTER_01",
+		Catalog:               "SampleCatalog",
+		Project:               "Project1",
+		EnvironmentType:       "Dev",
+		EnvironmentDefinition: "WebApp",
+		User:                  "USER_ID",
+	}
+
+	t.Run("Destroy", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.New("test")
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListPermissions(mockContext, config.Project, []string{
+			"Microsoft.DevCenter/projects/users/environments/adminDelete/action",
+		})
+		mockdevcentersdk.MockGetEnvironment(mockContext, config.Project, "USER_ID", env.Name(), mockEnvironments[0])
+		// Only the requests on the environment of the user are mocked
+		mockdevcentersdk.MockDeleteEnvironment(
+			mockContext,
+			config.Project,
+			"USER_ID",
+			env.Name(),
+			&devcentersdk.OperationStatus{
+				Id:        "id",
+				Name:      mockEnvironments[0].Name,
+				Status:    "Succeeded",
+				StartTime: time.Now(),
+				EndTime:   time.Now(),
+			},
+		)
+
+		manager := &mockDevCenterManager{}
+		manager.
+			On("Outputs",
+				*mockContext.Context,
+				mock.AnythingOfType("*devcenter.Config"),
+				mock.AnythingOfType("*devcentersdk.Environment")).
+			Return(map[string]provisioning.OutputParameter{}, nil)
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, manager)
+		provider.(*ProvisionProvider).options.User = "USER_ID"
+
+		_, err := provider.Destroy(*mockContext.Context, provisioning.NewDestroyOptions(true, true))
+		require.NoError(t, err)
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.New("test")
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListPermissions(mockContext, config.Project, []string{
+			"Microsoft.DevCenter/projects/users/environments/userDelete/action",
+		})
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, nil)
+		provider.(*ProvisionProvider).options.User = "USER_ID"
+
+		_, err := provider.Destroy(*mockContext.Context, provisioning.NewDestroyOptions(true, true))
+		require.ErrorContains(t, err, "you don't have permission to delete the environments of other users")
+	})
+
+	t.Run("DeployNotConfirmed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.New("test")
+
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListPermissions(mockContext, config.Project, []string{
+			"Microsoft.DevCenter/projects/users/environments/adminWrite/action",
+		})
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "is owned by user USER_ID")
+		}).Respond(false)
+
+		provider := newProvisionProviderForTest(t, mockContext, config, env, nil)
+		provider.(*ProvisionProvider).options.User = "USER_ID"
+
+		_, err := provider.Deploy(*mockContext.Context)
+		require.ErrorContains(t, err, "provision of the environment of user USER_ID cancelled")
+	})
+}
+
 func Test_ProvisionProvider_Preview(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	config := &Config{
@@ -446,6 +1001,66 @@ func Test_ProvisionProvider_Preview(t *testing.T) {
 	require.Nil(t, result)
 }
 
+func Test_ProvisionProvider_Parameters(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	config := &Config{
+		Name:                  "DEV_CENTER_01",
+		Catalog:               "SampleCatalog",
+		Project:               "Project1",
+		EnvironmentType:       "Dev",
+		EnvironmentDefinition: "WebApp",
+		User:                  "me",
+	}
+	env := environment.NewWithValues("test", map[string]string{
+		"AZURE_DEVCENTER_PARAM_PARAM02": "value2",
+	})
+	_ = env.Config.Set("provision.parameters.param01", "value1")
+
+	envDefinition := *mockEnvDefinitions[3]
+	envDefinition.Parameters = append(slices.Clone(envDefinition.Parameters), devcentersdk.Parameter{
+		Id:   "sqlAdmin",
+		Name: "SQL Admin",
+		Type: devcentersdk.ParameterTypeSecureString,
+	})
+
+	mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+	mockdevcentersdk.MockGetEnvironmentDefinition(
+		mockContext,
+		config.Project,
+		config.Catalog,
+		config.EnvironmentDefinition,
+		&envDefinition,
+	)
+
+	provider := newProvisionProviderForTest(t, mockContext, config, env, nil)
+	parameters, err := provider.Parameters(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, []provisioning.Parameter{
+		{
+			Name:          "repoUrl",
+			Value:         "https://github.com/Azure-Samples/todo-nodejs-mongo-swa-func",
+			EnvVarMapping: []string{"AZURE_DEVCENTER_PARAM_REPOURL"},
+		},
+		{
+			Name:          "param01",
+			Value:         "value1",
+			EnvVarMapping: []string{"AZURE_DEVCENTER_PARAM_PARAM01"},
+			LocalPrompt:   true,
+		},
+		{
+			Name:               "param02",
+			Value:              "value2",
+			EnvVarMapping:      []string{"AZURE_DEVCENTER_PARAM_PARAM02"},
+			UsingEnvVarMapping: true,
+		},
+		{
+			Name:          "sqlAdmin",
+			Secret:        true,
+			EnvVarMapping: []string{"AZURE_DEVCENTER_PARAM_SQLADMIN"},
+		},
+	}, parameters)
+}
+
 func newProvisionProviderForTest(
 	t *testing.T,
 	mockContext *mocks.MockContext,
@@ -467,6 +1082,18 @@ func newProvisionProviderForTest(
 
 	resourceService := azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
 
+	// The resource group of the environment is tagged after its deployment
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPatch && strings.HasSuffix(request.URL.Path, "/tags/default")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.TagsResource{})
+	})
+
+	// The project isn't a git repository, unless mocked otherwise
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "git"
+	}).Respond(exec.NewRunResult(128, "", "fatal: not a git repository (or any of the parent directories): .git"))
+
 	deploymentService := azapi.NewStandardDeployments(
 		mockContext.SubscriptionCredentialProvider,
 		mockContext.ArmClientOptions,
@@ -491,6 +1118,14 @@ func newProvisionProviderForTest(
 
 	prompter := NewPrompter(mockContext.Console, manager, devCenterClient)
 
+	subscriptionResolver := &mockSubscriptionResolver{}
+	subscriptionResolver.
+		On("GetSubscription", mock.Anything, "SUBSCRIPTION_01").
+		Return(&account.Subscription{Id: "SUBSCRIPTION_01", Name: "Contoso Prod"}, nil)
+	subscriptionResolver.
+		On("GetSubscription", mock.Anything, "SUBSCRIPTION_02").
+		Return(&account.Subscription{Id: "SUBSCRIPTION_02", Name: "Contoso Dev"}, nil)
+
 	return NewProvisionProvider(
 		mockContext.Console,
 		env,
@@ -500,5 +1135,84 @@ func newProvisionProviderForTest(
 		deploymentManager,
 		manager,
 		prompter,
+		subscriptionResolver,
+		resourceService,
+		provisioning.NewParameterSecrets(nil, resourceService, nil, nil, nil),
+		git.NewCli(mockContext.CommandRunner),
+		lazy.From(&project.ProjectConfig{
+			Metadata: &project.ProjectMetadata{Template: "todo-nodejs-mongo@0.0.1-beta"},
+		}),
 	)
 }
+
+func Test_deploymentTargetSubscriptionId(t *testing.T) {
+	subscriptionId, err := deploymentTargetSubscriptionId("/subscriptions/SUBSCRIPTION_01/")
+	require.NoError(t, err)
+	require.Equal(t, "SUBSCRIPTION_01", subscriptionId)
+
+	_, err = deploymentTargetSubscriptionId("")
+	require.Error(t, err)
+
+	_, err = deploymentTargetSubscriptionId("/resourceGroups/RESOURCE_GROUP")
+	require.Error(t, err)
+}
+
+func Test_environmentSpecMatches(t *testing.T) {
+	expiration := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	existing := &devcentersdk.Environment{
+		ProvisioningState:         devcentersdk.ProvisioningStateSucceeded,
+		CatalogName:               "SampleCatalog",
+		EnvironmentDefinitionName: "WebApp",
+		EnvironmentType:           "Dev",
+		Parameters:                map[string]any{"count": float64(1)},
+	}
+	spec := devcentersdk.EnvironmentSpec{
+		CatalogName:               "SampleCatalog",
+		EnvironmentDefinitionName: "WebApp",
+		EnvironmentType:           "Dev",
+		Parameters:                map[string]any{"count": 1},
+	}
+
+	require.True(t, environmentSpecMatches(existing, spec))
+
+	t.Run("ExpirationSetOnEnvironmentWithout", func(t *testing.T) {
+		spec := spec
+		spec.ExpirationDate = &expiration
+		require.False(t, environmentSpecMatches(existing, spec))
+	})
+
+	t.Run("ExpirationExtended", func(t *testing.T) {
+		existing := *existing
+		existing.ExpirationDate = &expiration
+
+		spec := spec
+		spec.ExpirationDate = to.Ptr(expiration.Add(time.Hour))
+		require.False(t, environmentSpecMatches(&existing, spec))
+
+		spec.ExpirationDate = &expiration
+		require.True(t, environmentSpecMatches(&existing, spec))
+	})
+
+	t.Run("TagsChanged", func(t *testing.T) {
+		existing := *existing
+		existing.Tags = map[string]string{
+			azure.TagKeyAzdEnvName: "dev",
+			azure.TagKeyAzdGitSha:  "0123abcd",
+		}
+
+		// Only the commit changed
+		spec := spec
+		spec.Tags = map[string]string{
+			azure.TagKeyAzdEnvName: "dev",
+			azure.TagKeyAzdGitSha:  "4567cdef",
+		}
+		require.True(t, environmentSpecMatches(&existing, spec))
+
+		spec.Tags = map[string]string{
+			azure.TagKeyAzdEnvName:  "dev",
+			azure.TagKeyAzdTemplate: "todo-nodejs-mongo@0.0.1-beta",
+			azure.TagKeyAzdGitSha:   "4567cdef",
+		}
+		require.False(t, environmentSpecMatches(&existing, spec))
+	})
+}
@@ -0,0 +1,278 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcenter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// RemoteEnvironment is a devcenter environment of any project of the dev center.
+type RemoteEnvironment struct {
+	Name                  string `json:"name"`
+	Project               string `json:"project"`
+	EnvironmentType       string `json:"environmentType"`
+	Catalog               string `json:"catalog"`
+	EnvironmentDefinition string `json:"environmentDefinition"`
+	User                  string `json:"user"`
+	ProvisioningState     string `json:"provisioningState"`
+	// The name of the local azd environment linked to the devcenter environment, empty when there is none
+	LocalName string `json:"localName,omitempty"`
+}
+
+// RemoteEnvironments lists the devcenter environments of all the projects of the dev center, and creates local azd
+// environments for the ones which aren't linked to a local environment yet.
+type RemoteEnvironments struct {
+	config  *Config
+	client  devcentersdk.DevCenterClient
+	manager Manager
+	local   environment.LocalDataStore
+}
+
+// NewRemoteEnvironments creates a new RemoteEnvironments
+func NewRemoteEnvironments(
+	config *Config,
+	client devcentersdk.DevCenterClient,
+	manager Manager,
+	local environment.LocalDataStore,
+) *RemoteEnvironments {
+	return &RemoteEnvironments{
+		config:  config,
+		client:  client,
+		manager: manager,
+		local:   local,
+	}
+}
+
+// List returns the devcenter environments of the signed-in user in all the projects of the dev center, or the
+// environments of all the users of the projects the signed-in user is an admin of when allUsers is true. A devcenter
+// environment is linked to the local environment of the same project named by its azd-env-name tag, or with the same
+// name when it isn't tagged.
+func (r *RemoteEnvironments) List(ctx context.Context, allUsers bool) ([]*RemoteEnvironment, error) {
+	var environments []*devcentersdk.Environment
+	var err error
+	if allUsers {
+		environments, err = r.allUsersEnvironments(ctx)
+	} else {
+		environments, err = r.environments(ctx, "me")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	localProjects, err := r.localProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteEnvs := []*RemoteEnvironment{}
+	for _, env := range environments {
+		remoteEnv := &RemoteEnvironment{
+			Name:                  env.Name,
+			Project:               env.ProjectName,
+			EnvironmentType:       env.EnvironmentType,
+			Catalog:               env.CatalogName,
+			EnvironmentDefinition: env.EnvironmentDefinitionName,
+			User:                  env.User,
+			ProvisioningState:     string(env.ProvisioningState),
+		}
+
+		// Environments deployed by azd are tagged with the name of their azd environment
+		envName := env.Name
+		if tagName := env.Tags[azure.TagKeyAzdEnvName]; tagName != "" {
+			envName = tagName
+		}
+
+		for localName, project := range localProjects {
+			if strings.EqualFold(localName, envName) && strings.EqualFold(project, env.ProjectName) {
+				remoteEnv.LocalName = localName
+			}
+		}
+
+		remoteEnvs = append(remoteEnvs, remoteEnv)
+	}
+
+	return remoteEnvs, nil
+}
+
+// Adopt creates a local azd environment for the devcenter environment with the given name, from its configuration and
+// outputs. The project is required when environments with the same name exist in several projects.
+func (r *RemoteEnvironments) Adopt(ctx context.Context, projectName string, name string) (*environment.Environment, error) {
+	environments, err := r.environments(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []*devcentersdk.Environment{}
+	for _, env := range environments {
+		if strings.EqualFold(env.Name, name) && (projectName == "" || strings.EqualFold(env.ProjectName, projectName)) {
+			matches = append(matches, env)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("devcenter environment '%s' %w", name, environment.ErrNotFound)
+	}
+
+	if len(matches) > 1 {
+		return nil, fmt.Errorf(
+			"devcenter environment '%s' exists in multiple projects, specify the project of the environment", name)
+	}
+
+	remoteEnv := matches[0]
+	if _, err := r.local.Get(ctx, remoteEnv.Name); err == nil {
+		return nil, fmt.Errorf("local environment '%s' already exists", remoteEnv.Name)
+	} else if !errors.Is(err, environment.ErrNotFound) {
+		return nil, err
+	}
+
+	envConfig := *r.config
+	envConfig.Project = remoteEnv.ProjectName
+	envConfig.Catalog = remoteEnv.CatalogName
+	envConfig.EnvironmentType = remoteEnv.EnvironmentType
+	envConfig.EnvironmentDefinition = remoteEnv.EnvironmentDefinitionName
+	envConfig.User = remoteEnv.User
+
+	env := environment.New(remoteEnv.Name)
+	configValues := map[string]string{
+		DevCenterNamePath:          envConfig.Name,
+		DevCenterProjectPath:       envConfig.Project,
+		DevCenterCatalogPath:       envConfig.Catalog,
+		DevCenterEnvTypePath:       envConfig.EnvironmentType,
+		DevCenterEnvDefinitionPath: envConfig.EnvironmentDefinition,
+		DevCenterUserPath:          envConfig.User,
+	}
+	for path, value := range configValues {
+		if err := env.Config.Set(path, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range remoteEnv.Parameters {
+		path := fmt.Sprintf("%s.%s", ProvisionParametersConfigPath, key)
+		if err := env.Config.Set(path, value); err != nil {
+			return nil, fmt.Errorf("failed setting config value %s: %w", path, err)
+		}
+	}
+
+	outputs, err := r.manager.Outputs(ctx, &envConfig, remoteEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment outputs: %w", err)
+	}
+
+	for key, outputParam := range outputs {
+		env.DotenvSet(key, fmt.Sprintf("%v", outputParam.Value))
+	}
+
+	if err := r.local.Save(ctx, env, &environment.SaveOptions{IsNew: true}); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// environments returns the devcenter environments of the user in all the projects of the dev center, all the
+// environments the signed-in user has access to when userId is empty
+func (r *RemoteEnvironments) environments(ctx context.Context, userId string) ([]*devcentersdk.Environment, error) {
+	if err := r.ensureDevCenter(); err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.DevCenterByName(r.config.Name).EnvironmentsByUser(userId).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devcenter environment list: %w", err)
+	}
+
+	return response.Value, nil
+}
+
+// allUsersEnvironments returns the environments of all the users of the projects the signed-in user is an admin of,
+// and only the environments of the signed-in user in the other projects
+func (r *RemoteEnvironments) allUsersEnvironments(ctx context.Context) ([]*devcentersdk.Environment, error) {
+	if err := r.ensureDevCenter(); err != nil {
+		return nil, err
+	}
+
+	devCenter := r.client.DevCenterByName(r.config.Name)
+	projects, err := devCenter.Projects().Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devcenter projects: %w", err)
+	}
+
+	environments := []*devcentersdk.Environment{}
+	adminProjects := 0
+	for _, project := range projects.Value {
+		projectClient := devCenter.ProjectByName(project.Name)
+
+		envList := projectClient.EnvironmentsByMe()
+		if projectClient.Permissions().HasAdminReadAccess(ctx) {
+			envList = projectClient.Environments()
+			adminProjects++
+		} else {
+			log.Printf("not an admin of devcenter project '%s', listing only the environments of the user", project.Name)
+		}
+
+		response, err := envList.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environments of project '%s': %w", project.Name, err)
+		}
+
+		environments = append(environments, response.Value...)
+	}
+
+	if adminProjects == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"you don't have permission to list the environments of other users in the projects of dev center '%s'",
+				r.config.Name),
+			Suggestion: "Suggestion: ask for the DevCenter Project Admin role on the projects, " +
+				"or remove --all-users to list your own environments.",
+		}
+	}
+
+	return environments, nil
+}
+
+func (r *RemoteEnvironments) ensureDevCenter() error {
+	if r.config.Name == "" {
+		return errors.New(
+			"the dev center isn't configured, set it with 'azd config set platform.config.name <dev center name>'")
+	}
+
+	return nil
+}
+
+// localProjects returns the devcenter project of each local environment
+func (r *RemoteEnvironments) localProjects(ctx context.Context) (map[string]string, error) {
+	localEnvs, err := r.local.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing local environments: %w", err)
+	}
+
+	projects := map[string]string{}
+	for _, localEnv := range localEnvs {
+		env, err := r.local.Get(ctx, localEnv.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Environments without their own project use the project of the devcenter configuration
+		project, has := env.Config.GetString(DevCenterProjectPath)
+		if !has {
+			project = r.config.Project
+		}
+
+		projects[localEnv.Name] = project
+	}
+
+	return projects, nil
+}
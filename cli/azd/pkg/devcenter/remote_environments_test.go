@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcenter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockdevcentersdk"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RemoteEnvironments_List(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+	mockdevcentersdk.MockListEnvironmentsByUser(mockContext, "Project1", "me", mockEnvironments)
+
+	remoteEnvironments, local := newRemoteEnvironmentsForTest(t, mockContext, &Config{Name: "DEV_CENTER_01"}, nil)
+
+	// Linked to the first devcenter environment
+	linked := environment.New(mockEnvironments[0].Name)
+	require.NoError(t, linked.Config.Set(DevCenterProjectPath, "Project1"))
+	require.NoError(t, local.Save(*mockContext.Context, linked, nil))
+
+	// Same name as the second devcenter environment, in another project
+	other := environment.New(mockEnvironments[1].Name)
+	require.NoError(t, other.Config.Set(DevCenterProjectPath, "Project2"))
+	require.NoError(t, local.Save(*mockContext.Context, other, nil))
+
+	envs, err := remoteEnvironments.List(*mockContext.Context, false)
+	require.NoError(t, err)
+	require.Len(t, envs, len(mockEnvironments))
+
+	require.Equal(t, &RemoteEnvironment{
+		Name:                  mockEnvironments[0].Name,
+		Project:               "Project1",
+		EnvironmentType:       "Dev",
+		Catalog:               "SampleCatalog",
+		EnvironmentDefinition: "WebApp",
+		User:                  "me",
+		ProvisioningState:     "Succeeded",
+		LocalName:             mockEnvironments[0].Name,
+	}, envs[0])
+	require.Empty(t, envs[1].LocalName)
+	require.Empty(t, envs[2].LocalName)
+}
+
+func Test_RemoteEnvironments_List_Tagged(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+
+	tagged := *mockEnvironments[0]
+	tagged.Tags = map[string]string{azure.TagKeyAzdEnvName: "contoso-dev"}
+	mockdevcentersdk.MockListEnvironmentsByUser(mockContext, "Project1", "me", []*devcentersdk.Environment{&tagged})
+
+	remoteEnvironments, local := newRemoteEnvironmentsForTest(t, mockContext, &Config{Name: "DEV_CENTER_01"}, nil)
+
+	linked := environment.New("contoso-dev")
+	require.NoError(t, linked.Config.Set(DevCenterProjectPath, "Project1"))
+	require.NoError(t, local.Save(*mockContext.Context, linked, nil))
+
+	envs, err := remoteEnvironments.List(*mockContext.Context, false)
+	require.NoError(t, err)
+	require.Len(t, envs, 1)
+	require.Equal(t, "contoso-dev", envs[0].LocalName)
+}
+
+func Test_RemoteEnvironments_List_AllUsers(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListPermissions(mockContext, "Project1", []string{
+			"Microsoft.DevCenter/projects/users/environments/adminRead/action",
+		})
+		mockdevcentersdk.MockListEnvironmentsByProject(mockContext, "Project1", mockEnvironments)
+
+		remoteEnvironments, _ := newRemoteEnvironmentsForTest(t, mockContext, &Config{Name: "DEV_CENTER_01"}, nil)
+
+		envs, err := remoteEnvironments.List(*mockContext.Context, true)
+		require.NoError(t, err)
+		require.Len(t, envs, len(mockEnvironments))
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+		mockdevcentersdk.MockListPermissions(mockContext, "Project1", []string{
+			"Microsoft.DevCenter/projects/users/environments/userRead/action",
+		})
+		mockdevcentersdk.MockListEnvironmentsByUser(mockContext, "Project1", "me", mockEnvironments)
+
+		remoteEnvironments, _ := newRemoteEnvironmentsForTest(t, mockContext, &Config{Name: "DEV_CENTER_01"}, nil)
+
+		_, err := remoteEnvironments.List(*mockContext.Context, true)
+		require.ErrorContains(t, err, "you don't have permission to list the environments of other users")
+	})
+}
+
+func Test_RemoteEnvironments_Adopt(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockdevcentersdk.MockDevCenterGraphQuery(mockContext, mockDevCenterList)
+	mockdevcentersdk.MockListEnvironmentsByProject(mockContext, "Project1", mockEnvironments)
+
+	manager := &mockDevCenterManager{}
+	manager.
+		On("Outputs",
+			*mockContext.Context,
+			mock.MatchedBy(func(config *Config) bool { return config.Project == "Project1" }),
+			mock.AnythingOfType("*devcentersdk.Environment")).
+		Return(map[string]provisioning.OutputParameter{
+			"WEBSITE_URL": {Type: provisioning.ParameterTypeString, Value: "https://contoso.com"},
+		}, nil)
+
+	remoteEnvironments, local := newRemoteEnvironmentsForTest(t, mockContext, &Config{Name: "DEV_CENTER_01"}, manager)
+
+	t.Run("Success", func(t *testing.T) {
+		env, err := remoteEnvironments.Adopt(*mockContext.Context, "", mockEnvironments[0].Name)
+		require.NoError(t, err)
+
+		saved, err := local.Get(*mockContext.Context, mockEnvironments[0].Name)
+		require.NoError(t, err)
+		require.Equal(t, env.Name(), saved.Name())
+		require.Equal(t, "https://contoso.com", saved.Getenv("WEBSITE_URL"))
+
+		devCenterNode, has := saved.Config.Get(ConfigPath)
+		require.True(t, has)
+		envConfig, err := ParseConfig(devCenterNode)
+		require.NoError(t, err)
+		require.Equal(t, &Config{
+			Name:                  "DEV_CENTER_01",
+			Project:               "Project1",
+			Catalog:               "SampleCatalog",
+			EnvironmentType:       "Dev",
+			EnvironmentDefinition: "WebApp",
+			User:                  "me",
+		}, envConfig)
+
+		param, has := saved.Config.Get(ProvisionParametersConfigPath + ".stringParam")
+		require.True(t, has)
+		require.Equal(t, "value", param)
+	})
+
+	t.Run("AlreadyAdopted", func(t *testing.T) {
+		_, err := remoteEnvironments.Adopt(*mockContext.Context, "", mockEnvironments[0].Name)
+		require.ErrorContains(t, err, "already exists")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := remoteEnvironments.Adopt(*mockContext.Context, "Project2", mockEnvironments[1].Name)
+		require.ErrorIs(t, err, environment.ErrNotFound)
+	})
+}
+
+func newRemoteEnvironmentsForTest(
+	t *testing.T,
+	mockContext *mocks.MockContext,
+	devCenterConfig *Config,
+	manager Manager,
+) (*RemoteEnvironments, environment.LocalDataStore) {
+	resourceGraphClient, err := armresourcegraph.NewClient(mockContext.Credentials, mockContext.ArmClientOptions)
+	require.NoError(t, err)
+
+	devCenterClient, err := devcentersdk.NewDevCenterClient(
+		mockContext.Credentials,
+		mockContext.CoreClientOptions,
+		resourceGraphClient,
+		cloud.AzurePublic(),
+	)
+	require.NoError(t, err)
+
+	if manager == nil {
+		manager = &mockDevCenterManager{}
+	}
+
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	dataStore := environment.NewLocalFileDataStore(azdContext, fileConfigManager)
+
+	return NewRemoteEnvironments(devCenterConfig, devCenterClient, manager, dataStore), dataStore
+}
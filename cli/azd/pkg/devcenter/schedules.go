@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcenter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+)
+
+// ScheduledAction is an action ADE scheduled on a devcenter environment, ex) its deletion when it expires
+type ScheduledAction struct {
+	Name          string    `json:"name"`
+	ActionType    string    `json:"actionType"`
+	ScheduledTime time.Time `json:"scheduledTime"`
+}
+
+// Schedules lists and delays the actions scheduled on the devcenter environments of the project, like the deletion of
+// the environments which expire, see Config.ExpirationHours.
+type Schedules struct {
+	config *Config
+	client devcentersdk.DevCenterClient
+}
+
+// NewSchedules creates a new Schedules
+func NewSchedules(config *Config, client devcentersdk.DevCenterClient) *Schedules {
+	return &Schedules{
+		config: config,
+		client: client,
+	}
+}
+
+// List returns the upcoming actions scheduled on the environment, by scheduled time.
+func (s *Schedules) List(ctx context.Context, envName string) ([]*ScheduledAction, error) {
+	actions, err := s.environment(envName).Actions().Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting scheduled actions of environment '%s': %w", envName, err)
+	}
+
+	scheduled := []*ScheduledAction{}
+	for _, action := range actions.Value {
+		if action.Next == nil {
+			continue
+		}
+
+		scheduled = append(scheduled, &ScheduledAction{
+			Name:          action.Name,
+			ActionType:    string(action.ActionType),
+			ScheduledTime: action.Next.ScheduledTime,
+		})
+	}
+
+	slices.SortFunc(scheduled, func(a, b *ScheduledAction) int {
+		return a.ScheduledTime.Compare(b.ScheduledTime)
+	})
+
+	return scheduled, nil
+}
+
+// Extend delays the scheduled deletion of the environment by the number of hours, and returns the delayed action.
+func (s *Schedules) Extend(ctx context.Context, envName string, hours int) (*ScheduledAction, error) {
+	if hours <= 0 {
+		return nil, fmt.Errorf("the number of hours must be positive, got %d", hours)
+	}
+
+	scheduled, err := s.List(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(scheduled, func(action *ScheduledAction) bool {
+		return action.ActionType == string(devcentersdk.EnvironmentActionTypeDelete)
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("environment '%s' has no scheduled deletion", envName)
+	}
+
+	deletion := scheduled[index]
+	until := deletion.ScheduledTime.Add(time.Duration(hours) * time.Hour)
+
+	action, err := s.environment(envName).Actions().Delay(ctx, deletion.Name, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed delaying the deletion of environment '%s': %w", envName, err)
+	}
+
+	delayed := &ScheduledAction{
+		Name:          action.Name,
+		ActionType:    string(action.ActionType),
+		ScheduledTime: until,
+	}
+	if action.Next != nil {
+		delayed.ScheduledTime = action.Next.ScheduledTime
+	}
+
+	return delayed, nil
+}
+
+func (s *Schedules) environment(envName string) *devcentersdk.EnvironmentItemRequestBuilder {
+	return s.client.
+		DevCenterByName(s.config.Name).
+		ProjectByName(s.config.Project).
+		EnvironmentsByUser(s.config.User).
+		EnvironmentByName(envName)
+}
+
+// expirationDate returns the time the environment expires at when deployed at the time, nil when it doesn't expire.
+// The expiration of an existing environment is kept when it was extended beyond the configured one.
+func expirationDate(config *Config, existing *devcentersdk.Environment, now time.Time) *time.Time {
+	var existingDate *time.Time
+	if existing != nil {
+		existingDate = existing.ExpirationDate
+	}
+
+	if config.ExpirationHours == 0 {
+		return existingDate
+	}
+
+	expiration := now.UTC().Add(time.Duration(config.ExpirationHours) * time.Hour).Truncate(time.Minute)
+	if existingDate != nil && existingDate.After(expiration) {
+		return existingDate
+	}
+
+	return &expiration
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcenter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Schedules(t *testing.T) {
+	expiration := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newSchedules := func(t *testing.T) *Schedules {
+		server := fake.NewServer(t, "DEV_CENTER_01")
+		project := server.AddProject("Project1")
+		project.AddEnvironment(&devcentersdk.Environment{Name: "expiring", ExpirationDate: &expiration})
+		project.AddEnvironment(&devcentersdk.Environment{Name: "permanent"})
+
+		client, err := server.Client()
+		require.NoError(t, err)
+
+		return NewSchedules(&Config{Name: "DEV_CENTER_01", Project: "Project1"}, client)
+	}
+
+	t.Run("List", func(t *testing.T) {
+		schedules := newSchedules(t)
+
+		actions, err := schedules.List(context.Background(), "expiring")
+		require.NoError(t, err)
+		require.Equal(t, []*ScheduledAction{
+			{Name: "delete", ActionType: "Delete", ScheduledTime: expiration},
+		}, actions)
+
+		actions, err = schedules.List(context.Background(), "permanent")
+		require.NoError(t, err)
+		require.Empty(t, actions)
+	})
+
+	t.Run("Extend", func(t *testing.T) {
+		schedules := newSchedules(t)
+
+		action, err := schedules.Extend(context.Background(), "expiring", 8)
+		require.NoError(t, err)
+		require.Equal(t, expiration.Add(8*time.Hour), action.ScheduledTime)
+
+		actions, err := schedules.List(context.Background(), "expiring")
+		require.NoError(t, err)
+		require.Equal(t, expiration.Add(8*time.Hour), actions[0].ScheduledTime)
+	})
+
+	t.Run("ExtendWithoutDeletion", func(t *testing.T) {
+		schedules := newSchedules(t)
+
+		_, err := schedules.Extend(context.Background(), "permanent", 8)
+		require.ErrorContains(t, err, "has no scheduled deletion")
+
+		_, err = schedules.Extend(context.Background(), "expiring", 0)
+		require.ErrorContains(t, err, "must be positive")
+	})
+}
+
+func Test_expirationDate(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 30, 0, time.UTC)
+	extended := now.Add(48 * time.Hour)
+
+	require.Nil(t, expirationDate(&Config{}, nil, now))
+	require.Equal(t, &extended, expirationDate(&Config{}, &devcentersdk.Environment{ExpirationDate: &extended}, now))
+
+	expected := time.Date(2030, 1, 2, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, &expected, expirationDate(&Config{ExpirationHours: 24}, nil, now))
+
+	// An expiration extended beyond the configured one is kept
+	require.Equal(t, &extended, expirationDate(
+		&Config{ExpirationHours: 24}, &devcentersdk.Environment{ExpirationDate: &extended}, now))
+}
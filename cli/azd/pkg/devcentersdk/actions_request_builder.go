@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcentersdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+type ActionListRequestBuilder struct {
+	*EntityItemRequestBuilder[ActionListRequestBuilder]
+	projectName string
+	userId      string
+}
+
+func NewActionListRequestBuilder(
+	c *devCenterClient,
+	devCenter *DevCenter,
+	projectName string,
+	userId string,
+	environmentName string,
+) *ActionListRequestBuilder {
+	builder := &ActionListRequestBuilder{}
+	builder.EntityItemRequestBuilder = newEntityItemRequestBuilder(builder, c, devCenter, environmentName)
+	builder.projectName = projectName
+	builder.userId = userId
+
+	return builder
+}
+
+// user returns the id of the user owning the environment, the signed-in user when not set
+func (c *ActionListRequestBuilder) user() string {
+	if c.userId == "" {
+		return "me"
+	}
+
+	return c.userId
+}
+
+// Get lists the actions scheduled on the environment (ex. its deletion when it expires), following all result pages.
+func (c *ActionListRequestBuilder) Get(ctx context.Context) (*EnvironmentActionListResponse, error) {
+	requestUrl := fmt.Sprintf("projects/%s/users/%s/environments/%s/actions", c.projectName, c.user(), c.id)
+	req, err := c.createRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+
+	result := &EnvironmentActionListResponse{
+		Value: []*EnvironmentAction{},
+	}
+
+	for {
+		res, err := c.client.pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, runtime.NewResponseError(res)
+		}
+
+		page, err := httputil.ReadRawResponse[EnvironmentActionListResponse](res)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Value = append(result.Value, page.Value...)
+
+		if page.NextLink == "" {
+			return result, nil
+		}
+
+		req, err = runtime.NewRequest(ctx, http.MethodGet, page.NextLink)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating request: %w", err)
+		}
+	}
+}
+
+// Delay delays the next occurrence of the scheduled action until the time, ex) to postpone the deletion of the
+// environment.
+func (c *ActionListRequestBuilder) Delay(
+	ctx context.Context,
+	actionName string,
+	until time.Time,
+) (*EnvironmentAction, error) {
+	requestUrl := fmt.Sprintf(
+		"projects/%s/users/%s/environments/%s/actions/%s:delay", c.projectName, c.user(), c.id, actionName)
+	req, err := c.createRequest(ctx, http.MethodPost, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+
+	raw := req.Raw()
+	query := raw.URL.Query()
+	query.Set("until", until.UTC().Format(time.RFC3339))
+	raw.URL.RawQuery = query.Encode()
+
+	res, err := c.client.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return nil, runtime.NewResponseError(res)
+	}
+
+	return httputil.ReadRawResponse[EnvironmentAction](res)
+}
@@ -26,22 +26,22 @@ func NewCatalogListRequestBuilder(c *devCenterClient, devCenter *DevCenter, proj
 	return builder
 }
 
-func (c *CatalogListRequestBuilder) Get(ctx context.Context) (*CatalogListResponse, error) {
-	req, err := c.createRequest(ctx, http.MethodGet, fmt.Sprintf("projects/%s/catalogs", c.projectName))
-	if err != nil {
-		return nil, fmt.Errorf("failed creating request: %w", err)
-	}
+// NewListPager returns a pager over the pages of the catalogs of the project
+func (c *CatalogListRequestBuilder) NewListPager() *runtime.Pager[CatalogListResponse] {
+	return newListPager[CatalogListRequestBuilder, CatalogListResponse](
+		c.EntityListRequestBuilder, fmt.Sprintf("projects/%s/catalogs", c.projectName))
+}
 
-	res, err := c.client.pipeline.Do(req)
+// Get lists the catalogs of the project, following all result pages.
+func (c *CatalogListRequestBuilder) Get(ctx context.Context) (*CatalogListResponse, error) {
+	catalogs, err := allPages(ctx, c.NewListPager(), func(page CatalogListResponse) []*Catalog {
+		return page.Value
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if !runtime.HasStatusCode(res, http.StatusOK) {
-		return nil, runtime.NewResponseError(res)
-	}
-
-	return httputil.ReadRawResponse[CatalogListResponse](res)
+	return &CatalogListResponse{Value: catalogs}, nil
 }
 
 type CatalogItemRequestBuilder struct {
@@ -52,3 +52,14 @@ func (c *DevCenterItemRequestBuilder) ProjectByName(projectName string) *Project
 
 	return builder
 }
+
+func (c *DevCenterItemRequestBuilder) Environments() *DevCenterEnvironmentListRequestBuilder {
+	return NewDevCenterEnvironmentListRequestBuilder(c.client, c.devCenter)
+}
+
+func (c *DevCenterItemRequestBuilder) EnvironmentsByUser(userId string) *DevCenterEnvironmentListRequestBuilder {
+	builder := NewDevCenterEnvironmentListRequestBuilder(c.client, c.devCenter)
+	builder.userId = userId
+
+	return builder
+}
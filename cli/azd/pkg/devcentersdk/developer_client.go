@@ -91,25 +91,38 @@ func (c *devCenterClient) projectList(ctx context.Context) ([]*Project, error) {
 			AllowPartialScopes: to.Ptr(true),
 		},
 	}
-	res, err := c.resourceGraphClient.Resources(ctx, queryRequest, nil)
-	if err != nil {
-		return nil, err
-	}
 
-	list, ok := res.QueryResponse.Data.([]interface{})
-	if !ok {
-		return nil, errors.New("error converting data to list")
-	}
+	// Resource Graph returns at most 1000 results per page, follow the skip token for tenants with more projects
+	var resources []*GenericResource
+	for {
+		res, err := c.resourceGraphClient.Resources(ctx, queryRequest, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	jsonBytes, err := json.Marshal(list)
-	if err != nil {
-		return nil, fmt.Errorf("failed marshalling list: %w", err)
-	}
+		list, ok := res.QueryResponse.Data.([]interface{})
+		if !ok {
+			return nil, errors.New("error converting data to list")
+		}
 
-	var resources []*GenericResource
-	err = json.Unmarshal(jsonBytes, &resources)
-	if err != nil {
-		return nil, fmt.Errorf("failed unmarshalling list: %w", err)
+		jsonBytes, err := json.Marshal(list)
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling list: %w", err)
+		}
+
+		var page []*GenericResource
+		err = json.Unmarshal(jsonBytes, &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed unmarshalling list: %w", err)
+		}
+
+		resources = append(resources, page...)
+
+		if res.SkipToken == nil || *res.SkipToken == "" {
+			break
+		}
+
+		queryRequest.Options.SkipToken = res.SkipToken
 	}
 
 	projects := []*Project{}
@@ -6,9 +6,11 @@ package devcentersdk
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
 
 type entityListRequestInfo struct {
@@ -70,14 +72,75 @@ func (b *EntityListRequestBuilder[T]) createRequest(
 	return req, err
 }
 
+// Filter sets the $filter expression evaluated by the server, ex) contains(name, 'web')
 func (b *EntityListRequestBuilder[T]) Filter(filterExpression string) *T {
 	b.requestInfo.filter = &filterExpression
 
 	return b.builder
 }
 
+// Top sets the maximum number of results of each page returned by the server
 func (b *EntityListRequestBuilder[T]) Top(count int) *T {
 	b.requestInfo.top = &count
 
 	return b.builder
 }
+
+// listPage is a page of the results of a list operation, ex) CatalogListResponse
+type listPage interface {
+	nextPageLink() string
+}
+
+// newListPager creates a pager over the pages of the list operation at the path. The first page is requested with the
+// $filter and $top of the builder, the next ones with the continuation link returned by the server.
+func newListPager[T any, P listPage](b *EntityListRequestBuilder[T], path string) *runtime.Pager[P] {
+	return runtime.NewPager(runtime.PagingHandler[P]{
+		More: func(page P) bool {
+			return page.nextPageLink() != ""
+		},
+		Fetcher: func(ctx context.Context, page *P) (P, error) {
+			var empty P
+			var req *policy.Request
+			var err error
+			if page == nil {
+				req, err = b.createRequest(ctx, http.MethodGet, path)
+			} else {
+				req, err = runtime.NewRequest(ctx, http.MethodGet, (*page).nextPageLink())
+			}
+			if err != nil {
+				return empty, fmt.Errorf("failed creating request: %w", err)
+			}
+
+			res, err := b.client.pipeline.Do(req)
+			if err != nil {
+				return empty, err
+			}
+
+			if !runtime.HasStatusCode(res, http.StatusOK) {
+				return empty, runtime.NewResponseError(res)
+			}
+
+			result, err := httputil.ReadRawResponse[P](res)
+			if err != nil {
+				return empty, err
+			}
+
+			return *result, nil
+		},
+	})
+}
+
+// allPages returns the values of all the pages of the pager
+func allPages[P listPage, V any](ctx context.Context, pager *runtime.Pager[P], values func(page P) []V) ([]V, error) {
+	all := []V{}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, values(page)...)
+	}
+
+	return all, nil
+}
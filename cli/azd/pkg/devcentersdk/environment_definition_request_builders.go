@@ -33,7 +33,8 @@ func NewEnvironmentDefinitionListRequestBuilder(
 	return builder
 }
 
-func (c *EnvironmentDefinitionListRequestBuilder) Get(ctx context.Context) (*EnvironmentDefinitionListResponse, error) {
+// NewListPager returns a pager over the pages of the environment definitions of the project, or of the catalog
+func (c *EnvironmentDefinitionListRequestBuilder) NewListPager() *runtime.Pager[EnvironmentDefinitionListResponse] {
 	var requestPath string
 	if c.catalogName != "" {
 		requestPath = fmt.Sprintf("projects/%s/catalogs/%s/environmentDefinitions", c.projectName, c.catalogName)
@@ -41,21 +42,24 @@ func (c *EnvironmentDefinitionListRequestBuilder) Get(ctx context.Context) (*Env
 		requestPath = fmt.Sprintf("projects/%s/environmentDefinitions", c.projectName)
 	}
 
-	req, err := c.createRequest(ctx, http.MethodGet, requestPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed creating request: %w", err)
-	}
+	return newListPager[EnvironmentDefinitionListRequestBuilder, EnvironmentDefinitionListResponse](
+		c.EntityListRequestBuilder, requestPath)
+}
 
-	res, err := c.client.pipeline.Do(req)
+// Get lists the environment definitions of the project, or of the catalog, following all result pages.
+func (c *EnvironmentDefinitionListRequestBuilder) Get(ctx context.Context) (*EnvironmentDefinitionListResponse, error) {
+	definitions, err := allPages(
+		ctx,
+		c.NewListPager(),
+		func(page EnvironmentDefinitionListResponse) []*EnvironmentDefinition {
+			return page.Value
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	if !runtime.HasStatusCode(res, http.StatusOK) {
-		return nil, runtime.NewResponseError(res)
-	}
-
-	return httputil.ReadRawResponse[EnvironmentDefinitionListResponse](res)
+	return &EnvironmentDefinitionListResponse{Value: definitions}, nil
 }
 
 type EnvironmentDefinitionItemRequestBuilder struct {
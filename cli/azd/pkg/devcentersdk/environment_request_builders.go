@@ -40,7 +40,9 @@ func (c *EnvironmentListRequestBuilder) EnvironmentByName(name string) *Environm
 	return NewEnvironmentItemRequestBuilder(c.client, c.devCenter, c.projectName, c.userId, name)
 }
 
-func (c *EnvironmentListRequestBuilder) Get(ctx context.Context) (*EnvironmentListResponse, error) {
+// NewListPager returns a pager over the pages of the environments of the project, only the environments of the user
+// when set.
+func (c *EnvironmentListRequestBuilder) NewListPager() *runtime.Pager[EnvironmentListResponse] {
 	var requestUrl string
 
 	if c.userId != "" {
@@ -49,21 +51,65 @@ func (c *EnvironmentListRequestBuilder) Get(ctx context.Context) (*EnvironmentLi
 		requestUrl = fmt.Sprintf("projects/%s/environments", c.projectName)
 	}
 
-	req, err := c.createRequest(ctx, http.MethodGet, requestUrl)
+	return newListPager[EnvironmentListRequestBuilder, EnvironmentListResponse](c.EntityListRequestBuilder, requestUrl)
+}
+
+// Get lists the environments of the project, following all result pages.
+func (c *EnvironmentListRequestBuilder) Get(ctx context.Context) (*EnvironmentListResponse, error) {
+	environments, err := allPages(ctx, c.NewListPager(), func(page EnvironmentListResponse) []*Environment {
+		return page.Value
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed creating request: %w", err)
+		return nil, err
 	}
 
-	res, err := c.client.pipeline.Do(req)
+	for _, environment := range environments {
+		environment.ProjectName = c.projectName
+	}
+
+	return &EnvironmentListResponse{Value: environments}, nil
+}
+
+// Environments of all the projects of a dev center
+type DevCenterEnvironmentListRequestBuilder struct {
+	*EntityListRequestBuilder[DevCenterEnvironmentListRequestBuilder]
+	userId string
+}
+
+func NewDevCenterEnvironmentListRequestBuilder(
+	c *devCenterClient,
+	devCenter *DevCenter,
+) *DevCenterEnvironmentListRequestBuilder {
+	builder := &DevCenterEnvironmentListRequestBuilder{}
+	builder.EntityListRequestBuilder = newEntityListRequestBuilder(builder, c, devCenter)
+
+	return builder
+}
+
+// Gets the environments of all the projects of the dev center that the current logged in user has access to, only the
+// environments of the user when set.
+func (c *DevCenterEnvironmentListRequestBuilder) Get(ctx context.Context) (*EnvironmentListResponse, error) {
+	projects, err := c.client.projectListByDevCenter(ctx, c.devCenter)
 	if err != nil {
 		return nil, err
 	}
 
-	if !runtime.HasStatusCode(res, http.StatusOK) {
-		return nil, runtime.NewResponseError(res)
+	environments := []*Environment{}
+	for _, project := range projects {
+		builder := NewEnvironmentListRequestBuilder(c.client, c.devCenter, project.Name)
+		builder.userId = c.userId
+
+		projectEnvironments, err := builder.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting environments of project '%s': %w", project.Name, err)
+		}
+
+		environments = append(environments, projectEnvironments.Value...)
 	}
 
-	return httputil.ReadRawResponse[EnvironmentListResponse](res)
+	return &EnvironmentListResponse{
+		Value: environments,
+	}, nil
 }
 
 type EnvironmentItemRequestBuilder struct {
@@ -87,6 +133,15 @@ func NewEnvironmentItemRequestBuilder(
 	return builder
 }
 
+// user returns the id of the user owning the environment, the signed-in user when not set
+func (c *EnvironmentItemRequestBuilder) user() string {
+	if c.userId == "" {
+		return "me"
+	}
+
+	return c.userId
+}
+
 func (c *EnvironmentItemRequestBuilder) Get(ctx context.Context) (*Environment, error) {
 	requestUrl := fmt.Sprintf("projects/%s/users/%s/environments/%s", c.projectName, c.userId, c.id)
 	req, err := c.createRequest(ctx, http.MethodGet, requestUrl)
@@ -110,7 +165,7 @@ func (c *EnvironmentItemRequestBuilder) BeginPut(
 	ctx context.Context,
 	spec EnvironmentSpec,
 ) (*runtime.Poller[*OperationStatus], error) {
-	requestUrl := fmt.Sprintf("projects/%s/users/me/environments/%s", c.projectName, c.id)
+	requestUrl := fmt.Sprintf("projects/%s/users/%s/environments/%s", c.projectName, c.user(), c.id)
 	req, err := c.createRequest(ctx, http.MethodPut, requestUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating request: %w", err)
@@ -160,7 +215,7 @@ func (c *EnvironmentItemRequestBuilder) BeginDelete(
 	req, err := c.createRequest(
 		ctx,
 		http.MethodDelete,
-		fmt.Sprintf("projects/%s/users/me/environments/%s", c.projectName, c.id),
+		fmt.Sprintf("projects/%s/users/%s/environments/%s", c.projectName, c.user(), c.id),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating request: %w", err)
@@ -199,3 +254,11 @@ func (c *EnvironmentItemRequestBuilder) Delete(ctx context.Context) error {
 func (c *EnvironmentItemRequestBuilder) Outputs() *OutputsRequestBuilder {
 	return NewOutputsRequestBuilder(c.client, c.devCenter, c.projectName, c.userId, c.id)
 }
+
+func (c *EnvironmentItemRequestBuilder) Operations() *OperationListRequestBuilder {
+	return NewOperationListRequestBuilder(c.client, c.devCenter, c.projectName, c.userId, c.id)
+}
+
+func (c *EnvironmentItemRequestBuilder) Actions() *ActionListRequestBuilder {
+	return NewActionListRequestBuilder(c.client, c.devCenter, c.projectName, c.userId, c.id)
+}
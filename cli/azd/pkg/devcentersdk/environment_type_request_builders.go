@@ -30,26 +30,22 @@ func NewEnvironmentTypeListRequestBuilder(
 	return builder
 }
 
-func (c *EnvironmentTypeListRequestBuilder) Get(ctx context.Context) (*EnvironmentTypeListResponse, error) {
-	req, err := c.createRequest(
-		ctx,
-		http.MethodGet,
-		fmt.Sprintf("projects/%s/environmentTypes", c.projectName),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed creating request: %w", err)
-	}
+// NewListPager returns a pager over the pages of the environment types of the project
+func (c *EnvironmentTypeListRequestBuilder) NewListPager() *runtime.Pager[EnvironmentTypeListResponse] {
+	return newListPager[EnvironmentTypeListRequestBuilder, EnvironmentTypeListResponse](
+		c.EntityListRequestBuilder, fmt.Sprintf("projects/%s/environmentTypes", c.projectName))
+}
 
-	res, err := c.client.pipeline.Do(req)
+// Get lists the environment types of the project, following all result pages.
+func (c *EnvironmentTypeListRequestBuilder) Get(ctx context.Context) (*EnvironmentTypeListResponse, error) {
+	envTypes, err := allPages(ctx, c.NewListPager(), func(page EnvironmentTypeListResponse) []*EnvironmentType {
+		return page.Value
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if !runtime.HasStatusCode(res, http.StatusOK) {
-		return nil, runtime.NewResponseError(res)
-	}
-
-	return httputil.ReadRawResponse[EnvironmentTypeListResponse](res)
+	return &EnvironmentTypeListResponse{Value: envTypes}, nil
 }
 
 type EnvironmentTypeItemRequestBuilder struct {
@@ -0,0 +1,602 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+)
+
+// deleteActionName is the name of the action deleting an environment when it expires
+const deleteActionName = "delete"
+
+// operation is a long-running deployment or deletion of an environment
+type operation struct {
+	id          string
+	project     *Project
+	environment *devcentersdk.Environment
+	record      *devcentersdk.EnvironmentOperation
+	polls       int
+	failure     string
+}
+
+// errorResponse is the body of the error responses, in the format of the Azure REST APIs
+type errorResponse struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// environmentResponse is an environment, with the error of its last operation when it failed
+type environmentResponse struct {
+	*devcentersdk.Environment
+	Error *errorDetails `json:"error,omitempty"`
+}
+
+// operationStatus is the status of a long-running operation, polled by the client
+type operationStatus struct {
+	devcentersdk.OperationStatus
+	Error *errorDetails `json:"error,omitempty"`
+}
+
+// ServeHTTP serves the requests of the DevCenter data-plane, Resource Graph and permissions APIs
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "the request isn't authenticated")
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	if strings.EqualFold(path, "providers/Microsoft.ResourceGraph/resources") && r.Method == http.MethodPost {
+		s.serveResourceGraph(w)
+		return
+	}
+
+	if strings.HasSuffix(path, "/providers/Microsoft.Authorization/permissions") && r.Method == http.MethodGet {
+		s.servePermissions(w, path)
+		return
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || segments[0] != "projects" {
+		writeNotFound(w, path)
+		return
+	}
+
+	project := s.project(segments[1])
+	if project == nil {
+		writeError(w, http.StatusNotFound, "ProjectNotFound", fmt.Sprintf("project '%s' not found", segments[1]))
+		return
+	}
+
+	route := segments[2:]
+	switch {
+	case len(route) == 0 && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, map[string]string{"name": project.name})
+	case matchRoute(route, "catalogs") && r.Method == http.MethodGet:
+		writeList(w, r, project.catalogs, func(value []*devcentersdk.Catalog, nextLink string) any {
+			return devcentersdk.CatalogListResponse{Value: value, NextLink: nextLink}
+		})
+	case matchRoute(route, "catalogs", "*") && r.Method == http.MethodGet:
+		index := slices.IndexFunc(project.catalogs, func(catalog *devcentersdk.Catalog) bool {
+			return catalog.Name == route[1]
+		})
+		if index < 0 {
+			writeNotFound(w, path)
+			return
+		}
+
+		writeJson(w, http.StatusOK, project.catalogs[index])
+	case matchRoute(route, "environmentDefinitions") && r.Method == http.MethodGet:
+		s.serveEnvironmentDefinitions(w, r, project, "")
+	case matchRoute(route, "catalogs", "*", "environmentDefinitions") && r.Method == http.MethodGet:
+		s.serveEnvironmentDefinitions(w, r, project, route[1])
+	case matchRoute(route, "catalogs", "*", "environmentDefinitions", "*") && r.Method == http.MethodGet:
+		definition := project.definition(route[1], route[3])
+		if definition == nil {
+			writeNotFound(w, path)
+			return
+		}
+
+		writeJson(w, http.StatusOK, definition)
+	case matchRoute(route, "environmentTypes") && r.Method == http.MethodGet:
+		writeList(w, r, project.environmentTypes, func(value []*devcentersdk.EnvironmentType, nextLink string) any {
+			return devcentersdk.EnvironmentTypeListResponse{Value: value, NextLink: nextLink}
+		})
+	case matchRoute(route, "environmentTypes", "*") && r.Method == http.MethodGet:
+		index := slices.IndexFunc(project.environmentTypes, func(environmentType *devcentersdk.EnvironmentType) bool {
+			return environmentType.Name == route[1]
+		})
+		if index < 0 {
+			writeNotFound(w, path)
+			return
+		}
+
+		writeJson(w, http.StatusOK, project.environmentTypes[index])
+	case matchRoute(route, "environments") && r.Method == http.MethodGet:
+		s.serveEnvironments(w, r, project, "")
+	case matchRoute(route, "users", "*", "environments") && r.Method == http.MethodGet:
+		s.serveEnvironments(w, r, project, userId(route[1]))
+	case matchRoute(route, "users", "*", "environments", "*"):
+		s.serveEnvironment(w, r, project, userId(route[1]), route[3])
+	case matchRoute(route, "users", "*", "environments", "*", "outputs") && r.Method == http.MethodGet:
+		environment := project.environment(userId(route[1]), route[3])
+		if environment == nil {
+			writeNotFound(w, path)
+			return
+		}
+
+		writeJson(w, http.StatusOK, devcentersdk.OutputListResponse{
+			Outputs: project.outputs[environment.Name],
+		})
+	case matchRoute(route, "users", "*", "environments", "*", "operations") && r.Method == http.MethodGet:
+		environment := project.environment(userId(route[1]), route[3])
+		if environment == nil {
+			writeNotFound(w, path)
+			return
+		}
+
+		writeJson(w, http.StatusOK, devcentersdk.EnvironmentOperationListResponse{
+			Value: project.operations[environment.Name],
+		})
+	case matchRoute(route, "users", "*", "environments", "*", "actions") && r.Method == http.MethodGet:
+		environment := project.environment(userId(route[1]), route[3])
+		if environment == nil {
+			writeNotFound(w, path)
+			return
+		}
+
+		writeJson(w, http.StatusOK, devcentersdk.EnvironmentActionListResponse{
+			Value: environmentActions(environment),
+		})
+	case matchRoute(route, "users", "*", "environments", "*", "actions", "*") && r.Method == http.MethodPost:
+		s.serveDelayAction(w, r, project.environment(userId(route[1]), route[3]), path, route[5])
+	case matchRoute(route, "operationstatuses", "*") && r.Method == http.MethodGet:
+		s.serveOperationStatus(w, path, route[1])
+	default:
+		writeNotFound(w, path)
+	}
+}
+
+// serveResourceGraph returns the projects of the dev center as the result of the Resource Graph query of the client
+func (s *Server) serveResourceGraph(w http.ResponseWriter) {
+	resources := []*devcentersdk.GenericResource{}
+	for _, project := range s.projects {
+		resources = append(resources, &devcentersdk.GenericResource{
+			Id: fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.DevCenter/projects/%s",
+				SubscriptionId,
+				ResourceGroup,
+				project.name,
+			),
+			Location: "eastus2",
+			Name:     project.name,
+			Type:     "microsoft.devcenter/projects",
+			TenantId: "TENANT_ID",
+			Properties: map[string]any{
+				"devCenterUri":      s.devCenter.ServiceUri,
+				"devCenterId":       s.devCenter.Id,
+				"provisioningState": "Succeeded",
+			},
+		})
+	}
+
+	count := int64(len(resources))
+	writeJson(w, http.StatusOK, armresourcegraph.QueryResponse{
+		Count:        &count,
+		TotalRecords: &count,
+		Data:         resources,
+	})
+}
+
+// servePermissions returns the data actions granted on the project of the path
+func (s *Server) servePermissions(w http.ResponseWriter, path string) {
+	segments := strings.Split(path, "/")
+	index := slices.Index(segments, "projects")
+	if index < 0 || index+1 >= len(segments) || s.project(segments[index+1]) == nil {
+		writeNotFound(w, path)
+		return
+	}
+
+	project := s.project(segments[index+1])
+	writeJson(w, http.StatusOK, map[string]any{
+		"value": []map[string]any{
+			{
+				"actions":        []string{},
+				"notActions":     []string{},
+				"dataActions":    project.dataActions,
+				"notDataActions": []string{},
+			},
+		},
+	})
+}
+
+func (s *Server) serveEnvironmentDefinitions(w http.ResponseWriter, r *http.Request, project *Project, catalog string) {
+	definitions := slices.DeleteFunc(
+		slices.Clone(project.definitions),
+		func(definition *devcentersdk.EnvironmentDefinition) bool {
+			return catalog != "" && definition.CatalogName != catalog
+		},
+	)
+
+	writeList(w, r, definitions, func(value []*devcentersdk.EnvironmentDefinition, nextLink string) any {
+		return devcentersdk.EnvironmentDefinitionListResponse{Value: value, NextLink: nextLink}
+	})
+}
+
+// serveEnvironments returns the environments of the project, only the ones of the user when set
+func (s *Server) serveEnvironments(w http.ResponseWriter, r *http.Request, project *Project, user string) {
+	environments := slices.DeleteFunc(
+		slices.Clone(project.environments),
+		func(environment *devcentersdk.Environment) bool {
+			return user != "" && environment.User != user
+		},
+	)
+
+	writeList(w, r, environments, func(value []*devcentersdk.Environment, nextLink string) any {
+		return devcentersdk.EnvironmentListResponse{Value: value, NextLink: nextLink}
+	})
+}
+
+// serveEnvironment gets, deploys or deletes an environment, starting a long-running operation for PUT and DELETE
+func (s *Server) serveEnvironment(w http.ResponseWriter, r *http.Request, project *Project, user string, name string) {
+	environment := project.environment(user, name)
+
+	switch r.Method {
+	case http.MethodGet:
+		if environment == nil {
+			writeError(w, http.StatusNotFound, "EnvironmentNotFound", fmt.Sprintf("environment '%s' not found", name))
+			return
+		}
+
+		writeJson(w, http.StatusOK, project.environmentResponse(environment))
+	case http.MethodPut:
+		var spec devcentersdk.EnvironmentSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+			return
+		}
+
+		if err := project.validate(spec); err != nil {
+			writeError(w, http.StatusBadRequest, "ValidationError", err.Error())
+			return
+		}
+
+		if environment == nil {
+			environment = &devcentersdk.Environment{
+				Name:            name,
+				User:            user,
+				ResourceGroupId: project.resourceGroupId(name),
+			}
+			project.environments = append(project.environments, environment)
+		} else if environment.ProvisioningState == devcentersdk.ProvisioningStateCreating ||
+			environment.ProvisioningState == devcentersdk.ProvisioningStateDeleting {
+			writeError(w, http.StatusConflict, "Conflict", fmt.Sprintf("an operation is running on '%s'", name))
+			return
+		}
+
+		environment.EnvironmentType = spec.EnvironmentType
+		environment.CatalogName = spec.CatalogName
+		environment.EnvironmentDefinitionName = spec.EnvironmentDefinitionName
+		environment.Parameters = spec.Parameters
+		environment.Tags = spec.Tags
+		environment.ExpirationDate = spec.ExpirationDate
+		environment.ProvisioningState = devcentersdk.ProvisioningStateCreating
+
+		operation := s.startOperation(project, environment, devcentersdk.EnvironmentOperationKindDeploy)
+		w.Header().Set("Operation-Location", s.operationUrl(project, operation))
+		writeJson(w, http.StatusCreated, project.environmentResponse(environment))
+	case http.MethodDelete:
+		if environment == nil {
+			writeError(w, http.StatusNotFound, "EnvironmentNotFound", fmt.Sprintf("environment '%s' not found", name))
+			return
+		}
+
+		environment.ProvisioningState = devcentersdk.ProvisioningStateDeleting
+
+		operation := s.startOperation(project, environment, devcentersdk.EnvironmentOperationKindDelete)
+		w.Header().Set("Operation-Location", s.operationUrl(project, operation))
+		writeJson(w, http.StatusAccepted, operationStatusOf(operation))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+// serveDelayAction delays the deletion of an expiring environment until the time of the request, the only action
+// scheduled by the server.
+func (s *Server) serveDelayAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	environment *devcentersdk.Environment,
+	path string,
+	action string,
+) {
+	name, found := strings.CutSuffix(action, ":delay")
+	if environment == nil || !found || name != deleteActionName || environment.ExpirationDate == nil {
+		writeNotFound(w, path)
+		return
+	}
+
+	until, err := time.Parse(time.RFC3339, r.URL.Query().Get("until"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", fmt.Sprintf("invalid 'until': %v", err))
+		return
+	}
+
+	if !until.After(*environment.ExpirationDate) {
+		writeError(w, http.StatusBadRequest, "ValidationError", "'until' must be after the scheduled time of the action")
+		return
+	}
+
+	until = until.UTC()
+	environment.ExpirationDate = &until
+	writeJson(w, http.StatusOK, environmentActions(environment)[0])
+}
+
+// serveOperationStatus returns the status of the operation, which completes when polled the number of polls of the
+// server.
+func (s *Server) serveOperationStatus(w http.ResponseWriter, path string, id string) {
+	operation, has := s.operations[id]
+	if !has {
+		writeNotFound(w, path)
+		return
+	}
+
+	if operation.record.Status == "Running" {
+		operation.polls--
+		if operation.polls <= 0 {
+			s.completeOperation(operation)
+		}
+	}
+
+	if operation.record.Status == "Running" {
+		// Polls again right away, instead of waiting for the polling frequency of the client
+		w.Header().Set("Retry-After-ms", "1")
+	}
+
+	writeJson(w, http.StatusOK, operationStatusOf(operation))
+}
+
+func (s *Server) startOperation(
+	project *Project,
+	environment *devcentersdk.Environment,
+	kind devcentersdk.EnvironmentOperationKind,
+) *operation {
+	s.count++
+	startTime := time.Now().UTC()
+
+	operation := &operation{
+		id:          strconv.Itoa(s.count),
+		project:     project,
+		environment: environment,
+		polls:       s.polls,
+		failure:     project.failures[environment.Name],
+		record: &devcentersdk.EnvironmentOperation{
+			OperationId:           strconv.Itoa(s.count),
+			Kind:                  kind,
+			Status:                "Running",
+			CreatedByObjectId:     UserId,
+			StartTime:             &startTime,
+			EnvironmentParameters: environment.Parameters,
+		},
+	}
+	delete(project.failures, environment.Name)
+	delete(project.errors, environment)
+
+	s.operations[operation.id] = operation
+	project.operations[environment.Name] = append(project.operations[environment.Name], operation.record)
+
+	return operation
+}
+
+func (s *Server) completeOperation(operation *operation) {
+	endTime := time.Now().UTC()
+	operation.record.EndTime = &endTime
+
+	environment := operation.environment
+	switch {
+	case operation.failure != "":
+		operation.record.Status = "Failed"
+		environment.ProvisioningState = devcentersdk.ProvisioningStateFailed
+		operation.project.errors[environment] = operation.failure
+	case operation.record.Kind == devcentersdk.EnvironmentOperationKindDelete:
+		operation.record.Status = "Succeeded"
+		project := operation.project
+		if index := project.environmentIndex(environment.User, environment.Name); index >= 0 {
+			project.environments = slices.Delete(project.environments, index, index+1)
+		}
+	default:
+		operation.record.Status = "Succeeded"
+		environment.ProvisioningState = devcentersdk.ProvisioningStateSucceeded
+
+		project := operation.project
+		definition := project.definition(environment.CatalogName, environment.EnvironmentDefinitionName)
+		if definition != nil && definition.RunnerType() == devcentersdk.EnvironmentDefinitionRunnerTerraform {
+			operation.record.Result = &devcentersdk.EnvironmentOperationResult{
+				Outputs: project.terraform[environment.Name],
+			}
+		}
+	}
+}
+
+func (s *Server) operationUrl(project *Project, operation *operation) string {
+	return fmt.Sprintf("%s/projects/%s/operationstatuses/%s", s.httpServer.URL, project.name, operation.id)
+}
+
+func operationStatusOf(operation *operation) operationStatus {
+	status := operationStatus{
+		OperationStatus: devcentersdk.OperationStatus{
+			Id:        fmt.Sprintf("/projects/%s/operationstatuses/%s", operation.project.name, operation.id),
+			Name:      operation.id,
+			Status:    operation.record.Status,
+			StartTime: *operation.record.StartTime,
+		},
+	}
+
+	if operation.record.EndTime != nil {
+		status.EndTime = *operation.record.EndTime
+	}
+
+	if operation.record.Status == "Failed" {
+		status.Error = &errorDetails{
+			Code:    "EnvironmentOperationFailed",
+			Message: operation.failure,
+		}
+	}
+
+	return status
+}
+
+// environmentActions returns the actions scheduled on the environment, its deletion when it expires
+func environmentActions(environment *devcentersdk.Environment) []*devcentersdk.EnvironmentAction {
+	if environment.ExpirationDate == nil {
+		return []*devcentersdk.EnvironmentAction{}
+	}
+
+	return []*devcentersdk.EnvironmentAction{
+		{
+			Name:       deleteActionName,
+			ActionType: devcentersdk.EnvironmentActionTypeDelete,
+			Next: &devcentersdk.EnvironmentNextAction{
+				ScheduledTime: *environment.ExpirationDate,
+			},
+		},
+	}
+}
+
+// environmentResponse returns the environment with the error of its last operation when it failed
+func (p *Project) environmentResponse(environment *devcentersdk.Environment) environmentResponse {
+	response := environmentResponse{Environment: environment}
+	if message, has := p.errors[environment]; has {
+		response.Error = &errorDetails{
+			Code:    "EnvironmentOperationFailed",
+			Message: message,
+		}
+	}
+
+	return response
+}
+
+// definition returns the environment definition of the catalog, nil when not found
+func (p *Project) definition(catalog string, name string) *devcentersdk.EnvironmentDefinition {
+	index := slices.IndexFunc(p.definitions, func(definition *devcentersdk.EnvironmentDefinition) bool {
+		return definition.CatalogName == catalog && definition.Name == name
+	})
+	if index < 0 {
+		return nil
+	}
+
+	return p.definitions[index]
+}
+
+// validate checks the environment definition and type of the spec exist, and the required parameters are set
+func (p *Project) validate(spec devcentersdk.EnvironmentSpec) error {
+	definition := p.definition(spec.CatalogName, spec.EnvironmentDefinitionName)
+	if definition == nil {
+		return fmt.Errorf(
+			"environment definition '%s' not found in catalog '%s'", spec.EnvironmentDefinitionName, spec.CatalogName)
+	}
+
+	if !slices.ContainsFunc(p.environmentTypes, func(environmentType *devcentersdk.EnvironmentType) bool {
+		return environmentType.Name == spec.EnvironmentType
+	}) {
+		return fmt.Errorf("environment type '%s' not found", spec.EnvironmentType)
+	}
+
+	for _, parameter := range definition.Parameters {
+		if _, has := spec.Parameters[parameter.Id]; parameter.Required && !has {
+			return fmt.Errorf("parameter '%s' is required", parameter.Id)
+		}
+	}
+
+	return nil
+}
+
+// userId resolves the user of the path of a request, ex) me
+func userId(user string) string {
+	if user == "me" {
+		return UserId
+	}
+
+	return user
+}
+
+// matchRoute returns true when the segments of a path match the pattern, where * matches any segment
+func matchRoute(segments []string, pattern ...string) bool {
+	if len(segments) != len(pattern) {
+		return false
+	}
+
+	for i, segment := range pattern {
+		if segment != "*" && !strings.EqualFold(segment, segments[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeList writes a page of the items, which size is set by $top. The next page is returned with $skip.
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T, page func(value []T, nextLink string) any) {
+	query := r.URL.Query()
+	skip, _ := strconv.Atoi(query.Get("$skip"))
+	skip = min(max(skip, 0), len(items))
+
+	end := len(items)
+	if top, err := strconv.Atoi(query.Get("$top")); err == nil && top > 0 {
+		end = min(skip+top, len(items))
+	}
+
+	nextLink := ""
+	if end < len(items) {
+		query.Set("$skip", strconv.Itoa(end))
+		nextLink = fmt.Sprintf("https://%s%s?%s", r.Host, r.URL.Path, query.Encode())
+	}
+
+	value := items[skip:end]
+	if value == nil {
+		value = []T{}
+	}
+
+	writeJson(w, http.StatusOK, page(value, nextLink))
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	writeJson(w, statusCode, errorResponse{
+		Error: errorDetails{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, path string) {
+	writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("'%s' not found", path))
+}
+
+// clone returns a shallow copy of the value
+func clone[T any](value *T) *T {
+	copied := *value
+	return &copied
+}
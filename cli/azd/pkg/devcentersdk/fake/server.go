@@ -0,0 +1,365 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package fake provides an in-memory DevCenter data-plane server, to test the code calling DevCenter through the
+// devcentersdk client (azd, extensions and templates) without a dev center.
+//
+//	server := fake.NewServer(t, "contoso")
+//	project := server.AddProject("Project1")
+//	project.AddEnvironmentType("Dev", "/subscriptions/SUBSCRIPTION_ID")
+//	project.AddEnvironmentDefinition(&devcentersdk.EnvironmentDefinition{CatalogName: "catalog", Name: "WebApp"})
+//
+//	client, err := server.Client()
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+)
+
+const (
+	// UserId is the id of the signed-in user, the owner of the environments of the "me" user
+	UserId = "00000000-0000-0000-0000-000000000001"
+	// SubscriptionId is the subscription of the dev center and its projects
+	SubscriptionId = "00000000-0000-0000-0000-000000000000"
+	// ResourceGroup is the resource group of the dev center and its projects
+	ResourceGroup = "rg-devcenter"
+)
+
+// DataActions are the data actions granted on the projects by default, all the user and admin environment actions.
+var DataActions = []string{
+	"Microsoft.DevCenter/projects/users/environments/userWrite/action",
+	"Microsoft.DevCenter/projects/users/environments/userDelete/action",
+	"Microsoft.DevCenter/projects/users/environments/adminRead/action",
+	"Microsoft.DevCenter/projects/users/environments/adminWrite/action",
+	"Microsoft.DevCenter/projects/users/environments/adminDelete/action",
+}
+
+// Server is a dev center served over TLS from memory. Besides the DevCenter data-plane API, it serves the Resource
+// Graph query used to find the projects and the permissions of the projects.
+//
+// Long-running operations (PUT and DELETE of environments) complete after their status is polled a fixed number of
+// times, see SetPolls, and fail when set with FailNextOperation, so tests are deterministic.
+type Server struct {
+	mu         sync.Mutex
+	httpServer *httptest.Server
+	devCenter  *devcentersdk.DevCenter
+	projects   []*Project
+	operations map[string]*operation
+	count      int
+	polls      int
+}
+
+// NewServer starts a server for the dev center with the name, which is closed at the end of the test.
+func NewServer(t testing.TB, devCenterName string) *Server {
+	server := &Server{
+		operations: map[string]*operation{},
+		polls:      1,
+	}
+
+	server.httpServer = httptest.NewTLSServer(server)
+	t.Cleanup(server.httpServer.Close)
+
+	server.devCenter = &devcentersdk.DevCenter{
+		Id: fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.DevCenter/devcenters/%s",
+			SubscriptionId,
+			ResourceGroup,
+			devCenterName,
+		),
+		SubscriptionId: SubscriptionId,
+		ResourceGroup:  ResourceGroup,
+		Name:           devCenterName,
+		ServiceUri:     server.httpServer.URL,
+	}
+
+	return server
+}
+
+// URL returns the endpoint of the server, which is both the URI of the dev center and the ARM endpoint
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// DevCenter returns the dev center served
+func (s *Server) DevCenter() *devcentersdk.DevCenter {
+	devCenter := *s.devCenter
+	return &devCenter
+}
+
+// SetPolls sets the number of times the status of a long-running operation is polled before the operation completes,
+// 1 by default.
+func (s *Server) SetPolls(polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.polls = max(polls, 1)
+}
+
+// AddProject adds a project to the dev center
+func (s *Server) AddProject(name string) *Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project := &Project{
+		server:      s,
+		name:        name,
+		operations:  map[string][]*devcentersdk.EnvironmentOperation{},
+		outputs:     map[string]map[string]devcentersdk.OutputParameter{},
+		terraform:   map[string]map[string]devcentersdk.TerraformOutput{},
+		failures:    map[string]string{},
+		errors:      map[*devcentersdk.Environment]string{},
+		dataActions: slices.Clone(DataActions),
+	}
+	s.projects = append(s.projects, project)
+
+	return project
+}
+
+// project returns the project with the name, nil when not found. The lock of the server must be held.
+func (s *Server) project(name string) *Project {
+	index := slices.IndexFunc(s.projects, func(project *Project) bool {
+		return project.name == name
+	})
+	if index < 0 {
+		return nil
+	}
+
+	return s.projects[index]
+}
+
+// Credential returns a credential which tokens are accepted by the server
+func (s *Server) Credential() azcore.TokenCredential {
+	return credential{}
+}
+
+// Cloud returns a cloud which ARM endpoint is the server
+func (s *Server) Cloud() *cloud.Cloud {
+	return &cloud.Cloud{
+		Configuration: azcloud.Configuration{
+			ActiveDirectoryAuthorityHost: s.httpServer.URL,
+			Services: map[azcloud.ServiceName]azcloud.ServiceConfiguration{
+				azcloud.ResourceManager: {
+					Audience: "https://management.core.windows.net",
+					Endpoint: s.httpServer.URL,
+				},
+			},
+		},
+	}
+}
+
+// ClientOptions returns the options of the data-plane clients, which send the requests to the server
+func (s *Server) ClientOptions() *azcore.ClientOptions {
+	return &azcore.ClientOptions{
+		Cloud:     s.Cloud().Configuration,
+		Transport: s.httpServer.Client(),
+		Retry: policy.RetryOptions{
+			MaxRetries: -1,
+		},
+	}
+}
+
+// ArmClientOptions returns the options of the ARM clients, which send the requests to the server
+func (s *Server) ArmClientOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions:         *s.ClientOptions(),
+		DisableRPRegistration: true,
+	}
+}
+
+// Client returns a DevCenter client for the server
+func (s *Server) Client() (devcentersdk.DevCenterClient, error) {
+	resourceGraphClient, err := armresourcegraph.NewClient(s.Credential(), s.ArmClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return devcentersdk.NewDevCenterClient(s.Credential(), s.ClientOptions(), resourceGraphClient, s.Cloud())
+}
+
+// Project is a project of the dev center of a Server
+type Project struct {
+	server           *Server
+	name             string
+	catalogs         []*devcentersdk.Catalog
+	definitions      []*devcentersdk.EnvironmentDefinition
+	environmentTypes []*devcentersdk.EnvironmentType
+	environments     []*devcentersdk.Environment
+	operations       map[string][]*devcentersdk.EnvironmentOperation
+	outputs          map[string]map[string]devcentersdk.OutputParameter
+	terraform        map[string]map[string]devcentersdk.TerraformOutput
+	failures         map[string]string
+	errors           map[*devcentersdk.Environment]string
+	dataActions      []string
+}
+
+// Name returns the name of the project
+func (p *Project) Name() string {
+	return p.name
+}
+
+// AddCatalog adds an empty catalog to the project
+func (p *Project) AddCatalog(name string) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	p.addCatalog(name)
+}
+
+func (p *Project) addCatalog(name string) {
+	if !slices.ContainsFunc(p.catalogs, func(catalog *devcentersdk.Catalog) bool {
+		return catalog.Name == name
+	}) {
+		p.catalogs = append(p.catalogs, &devcentersdk.Catalog{Name: name})
+	}
+}
+
+// AddEnvironmentDefinition adds an environment definition to its catalog, which is added to the project when missing
+func (p *Project) AddEnvironmentDefinition(definition *devcentersdk.EnvironmentDefinition) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	definition = clone(definition)
+	if definition.Id == "" {
+		definition.Id = fmt.Sprintf(
+			"/projects/%s/catalogs/%s/environmentDefinitions/%s", p.name, definition.CatalogName, definition.Name)
+	}
+
+	p.addCatalog(definition.CatalogName)
+	p.definitions = append(p.definitions, definition)
+}
+
+// AddEnvironmentType adds an environment type deploying the environments to the target, ex) /subscriptions/<id>
+func (p *Project) AddEnvironmentType(name string, deploymentTargetId string) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	p.environmentTypes = append(p.environmentTypes, &devcentersdk.EnvironmentType{
+		Name:               name,
+		DeploymentTargetId: deploymentTargetId,
+		Status:             "Enabled",
+	})
+}
+
+// AddEnvironment adds an existing environment to the project, owned by UserId and succeeded unless set
+func (p *Project) AddEnvironment(environment *devcentersdk.Environment) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	environment = clone(environment)
+	if environment.User == "" {
+		environment.User = UserId
+	}
+
+	if environment.ProvisioningState == "" {
+		environment.ProvisioningState = devcentersdk.ProvisioningStateSucceeded
+	}
+
+	if environment.ResourceGroupId == "" {
+		environment.ResourceGroupId = p.resourceGroupId(environment.Name)
+	}
+
+	p.environments = append(p.environments, environment)
+}
+
+// Environment returns a copy of the environment of the user, nil when not found
+func (p *Project) Environment(userId string, name string) *devcentersdk.Environment {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	if environment := p.environment(userId, name); environment != nil {
+		return clone(environment)
+	}
+
+	return nil
+}
+
+// Operations returns the operations performed on the environments with the name
+func (p *Project) Operations(environmentName string) []*devcentersdk.EnvironmentOperation {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	operations := []*devcentersdk.EnvironmentOperation{}
+	for _, operation := range p.operations[environmentName] {
+		operations = append(operations, clone(operation))
+	}
+
+	return operations
+}
+
+// SetOutputs sets the outputs of the environments with the name
+func (p *Project) SetOutputs(environmentName string, outputs map[string]devcentersdk.OutputParameter) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	p.outputs[environmentName] = outputs
+}
+
+// SetTerraformOutputs sets the outputs returned in the result of the deployments of the environments with the name,
+// when their environment definition is deployed by the Terraform runner.
+func (p *Project) SetTerraformOutputs(environmentName string, outputs map[string]devcentersdk.TerraformOutput) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	p.terraform[environmentName] = outputs
+}
+
+// FailNextOperation makes the next deployment or deletion of the environments with the name fail with the message
+func (p *Project) FailNextOperation(environmentName string, message string) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	p.failures[environmentName] = message
+}
+
+// SetDataActions sets the data actions granted on the project, DataActions by default
+func (p *Project) SetDataActions(dataActions ...string) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	p.dataActions = dataActions
+}
+
+// environment returns the environment of the user, nil when not found. The lock of the server must be held.
+func (p *Project) environment(userId string, name string) *devcentersdk.Environment {
+	index := p.environmentIndex(userId, name)
+	if index < 0 {
+		return nil
+	}
+
+	return p.environments[index]
+}
+
+func (p *Project) environmentIndex(userId string, name string) int {
+	return slices.IndexFunc(p.environments, func(environment *devcentersdk.Environment) bool {
+		return environment.User == userId && strings.EqualFold(environment.Name, name)
+	})
+}
+
+func (p *Project) resourceGroupId(environmentName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s-%s", SubscriptionId, p.name, environmentName)
+}
+
+// credential is a token credential returning a token valid for an hour
+type credential struct{}
+
+func (credential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{
+		Token:     "fake-token",
+		ExpiresOn: time.Now().Add(time.Hour),
+	}, nil
+}
@@ -0,0 +1,214 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*Server, *Project, *devcentersdk.ProjectItemRequestBuilder) {
+	server := NewServer(t, "contoso")
+	project := server.AddProject("Project1")
+	project.AddEnvironmentType("Dev", "/subscriptions/SUBSCRIPTION_ID")
+	project.AddEnvironmentDefinition(&devcentersdk.EnvironmentDefinition{
+		CatalogName: "catalog",
+		Name:        "WebApp",
+		Parameters: []devcentersdk.Parameter{
+			{Id: "environmentName", Type: devcentersdk.ParameterTypeString, Required: true},
+		},
+	})
+	project.AddEnvironmentDefinition(&devcentersdk.EnvironmentDefinition{CatalogName: "catalog", Name: "Function"})
+	project.AddEnvironmentDefinition(&devcentersdk.EnvironmentDefinition{CatalogName: "other", Name: "Database"})
+
+	client, err := server.Client()
+	require.NoError(t, err)
+
+	return server, project, client.DevCenterByName("contoso").ProjectByName("Project1")
+}
+
+func Test_Server_Catalogs(t *testing.T) {
+	_, _, projectClient := newTestServer(t)
+	ctx := context.Background()
+
+	project, err := projectClient.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Project1", project.Name)
+
+	catalogs, err := projectClient.Catalogs().Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*devcentersdk.Catalog{{Name: "catalog"}, {Name: "other"}}, catalogs.Value)
+
+	environmentTypes, err := projectClient.EnvironmentTypes().Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, environmentTypes.Value, 1)
+	require.Equal(t, "Dev", environmentTypes.Value[0].Name)
+
+	definition, err := projectClient.CatalogByName("catalog").EnvironmentDefinitionByName("WebApp").Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "/projects/Project1/catalogs/catalog/environmentDefinitions/WebApp", definition.Id)
+
+	catalogDefinitions, err := projectClient.CatalogByName("catalog").EnvironmentDefinitions().Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogDefinitions.Value, 2)
+
+	t.Run("Paging", func(t *testing.T) {
+		pager := projectClient.EnvironmentDefinitions().Top(2).NewListPager()
+
+		pages := 0
+		names := []string{}
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			require.NoError(t, err)
+
+			pages++
+			for _, definition := range page.Value {
+				names = append(names, definition.Name)
+			}
+		}
+
+		require.Equal(t, 2, pages)
+		require.Equal(t, []string{"WebApp", "Function", "Database"}, names)
+	})
+
+	t.Run("Permissions", func(t *testing.T) {
+		require.True(t, projectClient.Permissions().HasWriteAccess(ctx))
+
+		_, project, projectClient := newTestServer(t)
+		project.SetDataActions()
+		require.False(t, projectClient.Permissions().HasWriteAccess(ctx))
+	})
+}
+
+func Test_Server_Environments(t *testing.T) {
+	spec := devcentersdk.EnvironmentSpec{
+		CatalogName:               "catalog",
+		EnvironmentDefinitionName: "WebApp",
+		EnvironmentType:           "Dev",
+		Parameters:                map[string]any{"environmentName": "env1"},
+	}
+
+	t.Run("PutAndDelete", func(t *testing.T) {
+		server, project, projectClient := newTestServer(t)
+		server.SetPolls(3)
+		project.SetOutputs("env1", map[string]devcentersdk.OutputParameter{
+			"WEBSITE_URL": {Type: devcentersdk.OutputParameterTypeString, Value: "https://env1.contoso.com"},
+		})
+		ctx := context.Background()
+
+		envClient := projectClient.EnvironmentsByMe().EnvironmentByName("env1")
+		poller, err := envClient.BeginPut(ctx, spec)
+		require.NoError(t, err)
+		require.Equal(t, devcentersdk.ProvisioningStateCreating, project.Environment(UserId, "env1").ProvisioningState)
+
+		polls := 0
+		for !poller.Done() {
+			_, err := poller.Poll(ctx)
+			require.NoError(t, err)
+			polls++
+		}
+		require.Equal(t, 3, polls)
+
+		environment, err := projectClient.EnvironmentsByUser(UserId).EnvironmentByName("env1").Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, devcentersdk.ProvisioningStateSucceeded, environment.ProvisioningState)
+		require.Equal(t, UserId, environment.User)
+		require.Equal(t, map[string]any{"environmentName": "env1"}, environment.Parameters)
+
+		outputs, err := projectClient.EnvironmentsByUser(UserId).EnvironmentByName("env1").Outputs().Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "https://env1.contoso.com", outputs.Outputs["WEBSITE_URL"].Value)
+
+		require.NoError(t, envClient.Delete(ctx))
+		require.Nil(t, project.Environment(UserId, "env1"))
+
+		operations := project.Operations("env1")
+		require.Len(t, operations, 2)
+		require.Equal(t, devcentersdk.EnvironmentOperationKindDeploy, operations[0].Kind)
+		require.Equal(t, devcentersdk.EnvironmentOperationKindDelete, operations[1].Kind)
+		require.Equal(t, "Succeeded", operations[1].Status)
+	})
+
+	t.Run("FailNextOperation", func(t *testing.T) {
+		_, project, projectClient := newTestServer(t)
+		project.FailNextOperation("env1", "deployment quota exceeded")
+		ctx := context.Background()
+
+		envClient := projectClient.EnvironmentsByMe().EnvironmentByName("env1")
+		err := envClient.Put(ctx, spec)
+		require.ErrorContains(t, err, "deployment quota exceeded")
+		require.Equal(t, devcentersdk.ProvisioningStateFailed, project.Environment(UserId, "env1").ProvisioningState)
+
+		// Only the next operation fails
+		require.NoError(t, envClient.Put(ctx, spec))
+		require.Equal(t, devcentersdk.ProvisioningStateSucceeded, project.Environment(UserId, "env1").ProvisioningState)
+	})
+
+	t.Run("InvalidSpec", func(t *testing.T) {
+		_, project, projectClient := newTestServer(t)
+		ctx := context.Background()
+
+		envClient := projectClient.EnvironmentsByMe().EnvironmentByName("env1")
+		err := envClient.Put(ctx, devcentersdk.EnvironmentSpec{
+			CatalogName:               "catalog",
+			EnvironmentDefinitionName: "WebApp",
+			EnvironmentType:           "Dev",
+		})
+		require.ErrorContains(t, err, "parameter 'environmentName' is required")
+		require.Nil(t, project.Environment(UserId, "env1"))
+
+		err = envClient.Delete(ctx)
+		require.ErrorContains(t, err, "EnvironmentNotFound")
+	})
+
+	t.Run("ScheduledDeletion", func(t *testing.T) {
+		_, _, projectClient := newTestServer(t)
+		ctx := context.Background()
+		expiration := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		expiringSpec := spec
+		expiringSpec.ExpirationDate = &expiration
+
+		envClient := projectClient.EnvironmentsByMe().EnvironmentByName("env1")
+		require.NoError(t, envClient.Put(ctx, expiringSpec))
+
+		actions, err := envClient.Actions().Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, actions.Value, 1)
+		require.Equal(t, devcentersdk.EnvironmentActionTypeDelete, actions.Value[0].ActionType)
+		require.Equal(t, expiration, actions.Value[0].Next.ScheduledTime)
+
+		action, err := envClient.Actions().Delay(ctx, actions.Value[0].Name, expiration.Add(4*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, expiration.Add(4*time.Hour), action.Next.ScheduledTime)
+
+		environment, err := envClient.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expiration.Add(4*time.Hour), environment.ExpirationDate.UTC())
+
+		_, err = envClient.Actions().Delay(ctx, actions.Value[0].Name, expiration)
+		require.ErrorContains(t, err, "must be after")
+	})
+
+	t.Run("ListByUser", func(t *testing.T) {
+		_, project, projectClient := newTestServer(t)
+		project.AddEnvironment(&devcentersdk.Environment{Name: "mine", EnvironmentType: "Dev"})
+		project.AddEnvironment(&devcentersdk.Environment{Name: "theirs", EnvironmentType: "Dev", User: "OTHER_USER"})
+		ctx := context.Background()
+
+		all, err := projectClient.Environments().Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, all.Value, 2)
+
+		mine, err := projectClient.EnvironmentsByMe().Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, mine.Value, 1)
+		require.Equal(t, "mine", mine.Value[0].Name)
+		require.Equal(t, "Project1", mine.Value[0].ProjectName)
+	})
+}
@@ -4,8 +4,12 @@
 package devcentersdk
 
 import (
+	"encoding/json"
 	"errors"
+	"path"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -122,7 +126,12 @@ type Catalog struct {
 }
 
 type CatalogListResponse struct {
-	Value []*Catalog `json:"value"`
+	Value    []*Catalog `json:"value"`
+	NextLink string     `json:"nextLink,omitempty"`
+}
+
+func (r CatalogListResponse) nextPageLink() string {
+	return r.NextLink
 }
 
 type EnvironmentType struct {
@@ -132,7 +141,12 @@ type EnvironmentType struct {
 }
 
 type EnvironmentTypeListResponse struct {
-	Value []*EnvironmentType `json:"value"`
+	Value    []*EnvironmentType `json:"value"`
+	NextLink string             `json:"nextLink,omitempty"`
+}
+
+func (r EnvironmentTypeListResponse) nextPageLink() string {
+	return r.NextLink
 }
 
 type EnvironmentDefinition struct {
@@ -142,10 +156,53 @@ type EnvironmentDefinition struct {
 	Description  string      `json:"description"`
 	TemplatePath string      `json:"templatePath"`
 	Parameters   []Parameter `json:"parameters"`
+	// The runner deploying the definition, ARM, Bicep, Terraform or the image of a custom runner. Catalogs synced
+	// before runners were surfaced don't return it.
+	Runner EnvironmentDefinitionRunner `json:"runner,omitempty"`
+}
+
+type EnvironmentDefinitionRunner string
+
+const (
+	EnvironmentDefinitionRunnerArm       EnvironmentDefinitionRunner = "ARM"
+	EnvironmentDefinitionRunnerBicep     EnvironmentDefinitionRunner = "Bicep"
+	EnvironmentDefinitionRunnerTerraform EnvironmentDefinitionRunner = "Terraform"
+)
+
+// RunnerType returns the runner deploying the definition, inferred from its template when the runner isn't returned
+func (d *EnvironmentDefinition) RunnerType() EnvironmentDefinitionRunner {
+	if d.Runner != "" {
+		for _, runner := range []EnvironmentDefinitionRunner{
+			EnvironmentDefinitionRunnerArm,
+			EnvironmentDefinitionRunnerBicep,
+			EnvironmentDefinitionRunnerTerraform,
+		} {
+			if strings.EqualFold(string(d.Runner), string(runner)) {
+				return runner
+			}
+		}
+
+		// Custom runners are returned as is
+		return d.Runner
+	}
+
+	switch strings.ToLower(path.Ext(d.TemplatePath)) {
+	case ".tf":
+		return EnvironmentDefinitionRunnerTerraform
+	case ".bicep":
+		return EnvironmentDefinitionRunnerBicep
+	default:
+		return EnvironmentDefinitionRunnerArm
+	}
 }
 
 type EnvironmentDefinitionListResponse struct {
-	Value []*EnvironmentDefinition `json:"value"`
+	Value    []*EnvironmentDefinition `json:"value"`
+	NextLink string                   `json:"nextLink,omitempty"`
+}
+
+func (r EnvironmentDefinitionListResponse) nextPageLink() string {
+	return r.NextLink
 }
 
 type ParameterType string
@@ -154,6 +211,8 @@ const (
 	ParameterTypeString ParameterType = "string"
 	ParameterTypeInt    ParameterType = "int"
 	ParameterTypeBool   ParameterType = "bool"
+	// The type of secure string parameters, which values are never returned by Azure
+	ParameterTypeSecureString ParameterType = "secureString"
 )
 
 type Parameter struct {
@@ -167,12 +226,31 @@ type Parameter struct {
 	Default     any           `json:"default"`
 }
 
+// secretParameterNames are the words in the ids of the parameters which values are secrets, since most environment
+// definitions declare secrets as plain strings.
+var secretParameterNames = []string{"password", "secret", "token", "apikey", "accesskey", "connectionstring"}
+
+// Secret returns true when the value of the parameter is a secret, either declared with a secure type or named like
+// one, ex) adminPassword
+func (p Parameter) Secret() bool {
+	if p.Type == ParameterTypeSecureString {
+		return true
+	}
+
+	id := strings.ToLower(p.Id)
+	return slices.ContainsFunc(secretParameterNames, func(name string) bool {
+		return strings.Contains(id, name)
+	})
+}
+
 type ProvisioningState string
 
 const (
 	ProvisioningStateSucceeded ProvisioningState = "Succeeded"
 	ProvisioningStateCreating  ProvisioningState = "Creating"
 	ProvisioningStateDeleting  ProvisioningState = "Deleting"
+	ProvisioningStateFailed    ProvisioningState = "Failed"
+	ProvisioningStateCanceled  ProvisioningState = "Canceled"
 )
 
 type Environment struct {
@@ -184,17 +262,30 @@ type Environment struct {
 	CatalogName               string            `json:"catalogName"`
 	EnvironmentDefinitionName string            `json:"environmentDefinitionName"`
 	Parameters                map[string]any    `json:"parameters"`
+	Tags                      map[string]string `json:"tags,omitempty"`
+	// The time the environment is deleted at by ADE, nil when it doesn't expire
+	ExpirationDate *time.Time `json:"expirationDate,omitempty"`
+	// The project of the environment, which isn't returned by the API but set when listing environments
+	ProjectName string `json:"-"`
 }
 
 type EnvironmentListResponse struct {
-	Value []*Environment `json:"value"`
+	Value    []*Environment `json:"value"`
+	NextLink string         `json:"nextLink,omitempty"`
+}
+
+func (r EnvironmentListResponse) nextPageLink() string {
+	return r.NextLink
 }
 
 type EnvironmentSpec struct {
-	CatalogName               string         `json:"catalogName"`
-	EnvironmentDefinitionName string         `json:"environmentDefinitionName"`
-	EnvironmentType           string         `json:"environmentType"`
-	Parameters                map[string]any `json:"parameters"`
+	CatalogName               string            `json:"catalogName"`
+	EnvironmentDefinitionName string            `json:"environmentDefinitionName"`
+	EnvironmentType           string            `json:"environmentType"`
+	Parameters                map[string]any    `json:"parameters"`
+	Tags                      map[string]string `json:"tags,omitempty"`
+	// The time the environment is deleted at by ADE, nil when it doesn't expire
+	ExpirationDate *time.Time `json:"expirationDate,omitempty"`
 }
 
 type EnvironmentPutResponse struct {
@@ -209,6 +300,71 @@ type OperationStatus struct {
 	EndTime   time.Time `json:"endTime"`
 }
 
+type EnvironmentOperationKind string
+
+const (
+	EnvironmentOperationKindDeploy EnvironmentOperationKind = "Deploy"
+	EnvironmentOperationKindDelete EnvironmentOperationKind = "Delete"
+)
+
+// EnvironmentOperation is an operation (deployment or deletion) performed on an environment
+type EnvironmentOperation struct {
+	OperationId           string                   `json:"operationId"`
+	Kind                  EnvironmentOperationKind `json:"kind"`
+	Status                string                   `json:"status"`
+	CreatedByObjectId     string                   `json:"createdByObjectId"`
+	StartTime             *time.Time               `json:"startTime"`
+	EndTime               *time.Time               `json:"endTime"`
+	EnvironmentParameters map[string]any           `json:"environmentParameters"`
+	// The result of the operation, which holds the outputs of definitions deployed by the Terraform runner
+	Result *EnvironmentOperationResult `json:"result,omitempty"`
+}
+
+// EnvironmentOperationResult is the result of an operation, ex) the outputs of a Terraform deployment
+type EnvironmentOperationResult struct {
+	Outputs map[string]TerraformOutput `json:"outputs,omitempty"`
+}
+
+// TerraformOutput is an output of a Terraform deployment, in the format of 'terraform output -json'
+type TerraformOutput struct {
+	// The Terraform type constraint of the output, ex) "string" or ["list", "string"]
+	Type      json.RawMessage `json:"type"`
+	Value     any             `json:"value"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+type EnvironmentOperationListResponse struct {
+	Value    []*EnvironmentOperation `json:"value"`
+	NextLink string                  `json:"nextLink"`
+}
+
+type EnvironmentActionType string
+
+const (
+	EnvironmentActionTypeDelete EnvironmentActionType = "Delete"
+)
+
+// EnvironmentAction is an action scheduled on an environment, ex) its deletion when it expires
+type EnvironmentAction struct {
+	Name           string                 `json:"name"`
+	ActionType     EnvironmentActionType  `json:"actionType"`
+	Reason         string                 `json:"reason,omitempty"`
+	LastModifiedBy string                 `json:"lastModifiedBy,omitempty"`
+	LastModifiedAt *time.Time             `json:"lastModifiedAt,omitempty"`
+	Next           *EnvironmentNextAction `json:"next,omitempty"`
+	SuspendedUntil *time.Time             `json:"suspendedUntil,omitempty"`
+}
+
+// EnvironmentNextAction is the next occurrence of a scheduled action
+type EnvironmentNextAction struct {
+	ScheduledTime time.Time `json:"scheduledTime"`
+}
+
+type EnvironmentActionListResponse struct {
+	Value    []*EnvironmentAction `json:"value"`
+	NextLink string               `json:"nextLink,omitempty"`
+}
+
 type OutputListResponse struct {
 	Outputs map[string]OutputParameter `json:"outputs"`
 }
@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcentersdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvironmentDefinition_RunnerType(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition EnvironmentDefinition
+		expected   EnvironmentDefinitionRunner
+	}{
+		{"Arm", EnvironmentDefinition{TemplatePath: "WebApp/azuredeploy.json"}, EnvironmentDefinitionRunnerArm},
+		{"Bicep", EnvironmentDefinition{TemplatePath: "WebApp/main.bicep"}, EnvironmentDefinitionRunnerBicep},
+		{"Terraform", EnvironmentDefinition{TemplatePath: "WebApp/main.tf"}, EnvironmentDefinitionRunnerTerraform},
+		{"RunnerCasing", EnvironmentDefinition{TemplatePath: "main.json", Runner: "terraform"},
+			EnvironmentDefinitionRunnerTerraform},
+		{"CustomRunner", EnvironmentDefinition{TemplatePath: "Pulumi.yaml", Runner: "contoso.azurecr.io/pulumi:latest"},
+			"contoso.azurecr.io/pulumi:latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.definition.RunnerType())
+		})
+	}
+}
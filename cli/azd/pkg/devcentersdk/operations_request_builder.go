@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcentersdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+type OperationListRequestBuilder struct {
+	*EntityItemRequestBuilder[OperationListRequestBuilder]
+	projectName string
+	userId      string
+}
+
+func NewOperationListRequestBuilder(
+	c *devCenterClient,
+	devCenter *DevCenter,
+	projectName string,
+	userId string,
+	environmentName string,
+) *OperationListRequestBuilder {
+	builder := &OperationListRequestBuilder{}
+	builder.EntityItemRequestBuilder = newEntityItemRequestBuilder(builder, c, devCenter, environmentName)
+	builder.projectName = projectName
+	builder.userId = userId
+
+	return builder
+}
+
+// Get lists the operations (deployments, deletions) performed on the environment, following all result pages.
+func (c *OperationListRequestBuilder) Get(ctx context.Context) (*EnvironmentOperationListResponse, error) {
+	requestUrl := fmt.Sprintf("projects/%s/users/%s/environments/%s/operations", c.projectName, c.userId, c.id)
+	req, err := c.createRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+
+	result := &EnvironmentOperationListResponse{
+		Value: []*EnvironmentOperation{},
+	}
+
+	for {
+		res, err := c.client.pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !runtime.HasStatusCode(res, http.StatusOK) {
+			return nil, runtime.NewResponseError(res)
+		}
+
+		page, err := httputil.ReadRawResponse[EnvironmentOperationListResponse](res)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Value = append(result.Value, page.Value...)
+
+		if page.NextLink == "" {
+			return result, nil
+		}
+
+		req, err = runtime.NewRequest(ctx, http.MethodGet, page.NextLink)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating request: %w", err)
+		}
+	}
+}
@@ -89,6 +89,22 @@ func (c *PermissionListRequestBuilder) HasDeleteAccess(ctx context.Context) bool
 	return c.hasPermission(ctx, "Microsoft.DevCenter/projects/users/environments/userDelete/action")
 }
 
+// HasAdminReadAccess returns true when the user can read the environments of all the users of the project,
+// ex) with the DevCenter Project Admin role
+func (c *PermissionListRequestBuilder) HasAdminReadAccess(ctx context.Context) bool {
+	return c.hasPermission(ctx, "Microsoft.DevCenter/projects/users/environments/adminRead/action")
+}
+
+// HasAdminWriteAccess returns true when the user can create and update the environments of other users of the project
+func (c *PermissionListRequestBuilder) HasAdminWriteAccess(ctx context.Context) bool {
+	return c.hasPermission(ctx, "Microsoft.DevCenter/projects/users/environments/adminWrite/action")
+}
+
+// HasAdminDeleteAccess returns true when the user can delete the environments of other users of the project
+func (c *PermissionListRequestBuilder) HasAdminDeleteAccess(ctx context.Context) bool {
+	return c.hasPermission(ctx, "Microsoft.DevCenter/projects/users/environments/adminDelete/action")
+}
+
 func (c *PermissionListRequestBuilder) hasPermission(ctx context.Context, permission string) bool {
 	permissions, err := c.Get(ctx)
 	if err != nil {
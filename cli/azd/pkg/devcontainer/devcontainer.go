@@ -0,0 +1,402 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package devcontainer generates the dev container configuration (.devcontainer/devcontainer.json) of a project, used by
+// GitHub Codespaces and VS Code, with the tools the project needs.
+package devcontainer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// RelativePath is the path of the dev container configuration, relative to the project directory
+var RelativePath = filepath.Join(".devcontainer", "devcontainer.json")
+
+// DefaultImage is the image of the dev containers created by azd
+const DefaultImage = "mcr.microsoft.com/devcontainers/base:ubuntu"
+
+const (
+	featureAzd            = "ghcr.io/azure/azure-dev/azd:latest"
+	featureAzureCli       = "ghcr.io/devcontainers/features/azure-cli:1"
+	featureTerraform      = "ghcr.io/devcontainers/features/terraform:1"
+	featureDockerInDocker = "ghcr.io/devcontainers/features/docker-in-docker:2"
+	featureKubectl        = "ghcr.io/devcontainers/features/kubectl-helm-minikube:1"
+	featureDotNet         = "ghcr.io/devcontainers/features/dotnet:2"
+	featureNode           = "ghcr.io/devcontainers/features/node:1"
+	featurePython         = "ghcr.io/devcontainers/features/python:1"
+	featureJava           = "ghcr.io/devcontainers/features/java:1"
+)
+
+// Features returns the dev container features, by id, providing the tools needed by the project: azd, the tools of its
+// infrastructure provider, docker for the services deployed as containers and the runtimes of the languages of its
+// services.
+func Features(prjConfig *project.ProjectConfig) map[string]map[string]any {
+	features := map[string]map[string]any{
+		featureAzd: {},
+	}
+
+	switch prjConfig.Infra.Provider {
+	case provisioning.Terraform:
+		features[featureAzureCli] = map[string]any{}
+		features[featureTerraform] = map[string]any{}
+	case provisioning.NotSpecified, provisioning.Bicep:
+		features[featureAzureCli] = map[string]any{"installBicep": true}
+	}
+
+	for _, serviceConfig := range prjConfig.Services {
+		if serviceConfig.Language == project.ServiceLanguageDocker ||
+			(serviceConfig.Host.RequiresContainer() && serviceConfig.Image.Empty() && !serviceConfig.Docker.RemoteBuild) {
+			features[featureDockerInDocker] = map[string]any{}
+		}
+
+		if serviceConfig.Host == project.AksTarget {
+			features[featureKubectl] = map[string]any{"minikube": "none"}
+		}
+
+		// The SWA CLI emulating static web apps runs on node
+		if serviceConfig.Host == project.StaticWebAppTarget {
+			features[featureNode] = map[string]any{}
+		}
+
+		switch serviceConfig.Language {
+		case project.ServiceLanguageDotNet, project.ServiceLanguageCsharp, project.ServiceLanguageFsharp:
+			features[featureDotNet] = map[string]any{}
+		case project.ServiceLanguageJavaScript, project.ServiceLanguageTypeScript:
+			features[featureNode] = map[string]any{}
+		case project.ServiceLanguagePython:
+			features[featurePython] = map[string]any{}
+		case project.ServiceLanguageJava:
+			features[featureJava] = map[string]any{"installMaven": true}
+		}
+	}
+
+	return features
+}
+
+// SyncResult is the result of the synchronization of the dev container configuration of a project
+type SyncResult struct {
+	// The path of the dev container configuration
+	Path string
+	// True when the dev container configuration didn't exist and was created
+	Created bool
+	// The ids of the features added to the dev container configuration, sorted
+	Added []string
+}
+
+// Sync creates the dev container configuration of the project in its directory, or adds the features missing from an
+// existing one. The features and settings of an existing configuration are kept, a feature is considered present when
+// it's set with any version, ex) ghcr.io/devcontainers/features/node:2 for ghcr.io/devcontainers/features/node:1.
+//
+// Comments of an existing configuration aren't preserved when features are added.
+func Sync(projectDir string, prjConfig *project.ProjectConfig) (*SyncResult, error) {
+	result := &SyncResult{
+		Path: filepath.Join(projectDir, RelativePath),
+	}
+
+	contents, err := os.ReadFile(result.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		contents, err = newConfig(prjConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Created = true
+	} else if err != nil {
+		return nil, fmt.Errorf("reading dev container configuration: %w", err)
+	}
+
+	config, existing, err := parseConfig(result.Path, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	features := Features(prjConfig)
+	for _, id := range slices.Sorted(maps.Keys(features)) {
+		if hasFeature(existing, id) {
+			continue
+		}
+
+		options, err := json.Marshal(features[id])
+		if err != nil {
+			return nil, err
+		}
+
+		existing[id] = options
+		result.Added = append(result.Added, id)
+	}
+
+	if !result.Created && len(result.Added) == 0 {
+		return result, nil
+	}
+
+	rawFeatures, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	config.set("features", rawFeatures)
+
+	contents, err = config.MarshalIndent()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(result.Path), osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating dev container directory: %w", err)
+	}
+
+	if err := os.WriteFile(result.Path, contents, osutil.PermissionFile); err != nil {
+		return nil, fmt.Errorf("writing dev container configuration: %w", err)
+	}
+
+	return result, nil
+}
+
+// Missing returns the ids of the features the project needs which are missing from its existing dev container
+// configuration, sorted, without changing it. It returns nothing when the project has no dev container configuration.
+func Missing(projectDir string, prjConfig *project.ProjectConfig) ([]string, error) {
+	path := filepath.Join(projectDir, RelativePath)
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading dev container configuration: %w", err)
+	}
+
+	_, existing, err := parseConfig(path, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, id := range slices.Sorted(maps.Keys(Features(prjConfig))) {
+		if !hasFeature(existing, id) {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing, nil
+}
+
+// parseConfig parses a dev container configuration and its features by id
+func parseConfig(path string, contents []byte) (object, map[string]json.RawMessage, error) {
+	config := object{}
+	if err := json.Unmarshal(standardizeJson(contents), &config); err != nil {
+		return object{}, nil, fmt.Errorf("parsing dev container configuration '%s': %w", path, err)
+	}
+
+	features := map[string]json.RawMessage{}
+	if raw, has := config.get("features"); has {
+		if err := json.Unmarshal(raw, &features); err != nil {
+			return object{}, nil, fmt.Errorf("parsing the features of the dev container configuration '%s': %w", path, err)
+		}
+	}
+
+	return config, features, nil
+}
+
+// newConfig returns the dev container configuration created for the project, without features
+func newConfig(prjConfig *project.ProjectConfig) ([]byte, error) {
+	extensions := []string{"ms-azuretools.azure-dev"}
+	if prjConfig.Infra.Provider == provisioning.NotSpecified || prjConfig.Infra.Provider == provisioning.Bicep {
+		extensions = append(extensions, "ms-azuretools.vscode-bicep")
+	}
+
+	config := object{}
+	for _, member := range []struct {
+		key   string
+		value any
+	}{
+		{"name", prjConfig.Name},
+		{"image", DefaultImage},
+		{"features", map[string]any{}},
+		{"customizations", map[string]any{
+			"vscode": map[string]any{
+				"extensions": extensions,
+			},
+		}},
+	} {
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+
+		config.set(member.key, value)
+	}
+
+	return json.Marshal(config)
+}
+
+// hasFeature returns true when the features have the feature with the id, with any version
+func hasFeature(features map[string]json.RawMessage, id string) bool {
+	for existingId := range features {
+		if strings.EqualFold(featureName(existingId), featureName(id)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// featureName returns the id of the feature without its version, ex) ghcr.io/devcontainers/features/node
+func featureName(id string) string {
+	// The version follows the last colon, which isn't part of the registry host when the id has a path
+	if index := strings.LastIndex(id, ":"); index > strings.LastIndex(id, "/") {
+		return id[:index]
+	}
+
+	return id
+}
+
+// object is a JSON object which members keep their order, so existing configurations are updated in place
+type object []member
+
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+func (o *object) get(key string) (json.RawMessage, bool) {
+	for _, m := range *o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (o *object) set(key string, value json.RawMessage) {
+	for i, m := range *o {
+		if m.key == key {
+			(*o)[i].value = value
+			return
+		}
+	}
+
+	*o = append(*o, member{key: key, value: value})
+}
+
+func (o *object) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if token != json.Delim('{') {
+		return errors.New("expected a JSON object")
+	}
+
+	*o = object{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		o.set(token.(string), value)
+	}
+
+	return nil
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// MarshalIndent returns the object indented with 2 spaces, like the dev container configurations of the templates
+func (o object) MarshalIndent() ([]byte, error) {
+	compact, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, compact, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// standardizeJson converts the JSON with comments (JSONC) of dev container configurations to standard JSON, removing the
+// comments and the trailing commas.
+func standardizeJson(data []byte) []byte {
+	var buf bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			buf.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				buf.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			buf.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			buf.WriteByte('\n')
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return buf.Bytes()
+			}
+			i += end + 3
+		case c == ']' || c == '}':
+			// Remove the trailing comma before the end of the array or object
+			trimmed := bytes.TrimRight(buf.Bytes(), " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				buf.Truncate(len(trimmed) - 1)
+			}
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcontainer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Features(t *testing.T) {
+	t.Run("Bicep", func(t *testing.T) {
+		features := Features(&project.ProjectConfig{
+			Services: map[string]*project.ServiceConfig{
+				"api": {Host: project.ContainerAppTarget, Language: project.ServiceLanguagePython},
+				"web": {Host: project.StaticWebAppTarget, Language: project.ServiceLanguageTypeScript},
+			},
+		})
+
+		require.Equal(t, map[string]map[string]any{
+			featureAzd:            {},
+			featureAzureCli:       {"installBicep": true},
+			featureDockerInDocker: {},
+			featurePython:         {},
+			featureNode:           {},
+		}, features)
+	})
+
+	t.Run("Terraform", func(t *testing.T) {
+		features := Features(&project.ProjectConfig{
+			Infra: provisioning.Options{Provider: provisioning.Terraform},
+			Services: map[string]*project.ServiceConfig{
+				"api": {Host: project.AksTarget, Language: project.ServiceLanguageDotNet},
+				// Container images built remotely don't need docker
+				"worker": {
+					Host:     project.ContainerAppTarget,
+					Language: project.ServiceLanguageJava,
+					Docker:   project.DockerProjectOptions{RemoteBuild: true},
+				},
+			},
+		})
+
+		require.Equal(t, map[string]map[string]any{
+			featureAzd:            {},
+			featureAzureCli:       {},
+			featureTerraform:      {},
+			featureDockerInDocker: {},
+			featureKubectl:        {"minikube": "none"},
+			featureDotNet:         {},
+			featureJava:           {"installMaven": true},
+		}, features)
+	})
+
+	t.Run("PrebuiltImage", func(t *testing.T) {
+		features := Features(&project.ProjectConfig{
+			Infra: provisioning.Options{Provider: provisioning.Pulumi},
+			Services: map[string]*project.ServiceConfig{
+				"api": {Host: project.ContainerAppTarget, Image: osutil.NewExpandableString("nginx:latest")},
+			},
+		})
+
+		require.Equal(t, map[string]map[string]any{featureAzd: {}}, features)
+	})
+}
+
+func Test_Sync(t *testing.T) {
+	prjConfig := &project.ProjectConfig{
+		Name: "todo",
+		Services: map[string]*project.ServiceConfig{
+			"api": {Host: project.AppServiceTarget, Language: project.ServiceLanguagePython},
+		},
+	}
+
+	readConfig := func(t *testing.T, path string) map[string]any {
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		config := map[string]any{}
+		require.NoError(t, json.Unmarshal(contents, &config))
+		return config
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		dir := t.TempDir()
+
+		result, err := Sync(dir, prjConfig)
+		require.NoError(t, err)
+		require.True(t, result.Created)
+		require.Equal(t, filepath.Join(dir, ".devcontainer", "devcontainer.json"), result.Path)
+		require.Equal(t, []string{featureAzd, featureAzureCli, featurePython}, result.Added)
+
+		config := readConfig(t, result.Path)
+		require.Equal(t, "todo", config["name"])
+		require.Equal(t, DefaultImage, config["image"])
+		require.Equal(t, map[string]any{
+			featureAzd:      map[string]any{},
+			featureAzureCli: map[string]any{"installBicep": true},
+			featurePython:   map[string]any{},
+		}, config["features"])
+
+		// A second sync has nothing to add
+		result, err = Sync(dir, prjConfig)
+		require.NoError(t, err)
+		require.False(t, result.Created)
+		require.Empty(t, result.Added)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, RelativePath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(`{
+	// The image of the template
+	"name": "custom",
+	"image": "mcr.microsoft.com/devcontainers/python:3.12", /* pinned */
+	"features": {
+		"ghcr.io/devcontainers/features/python:3": { "version": "3.12" },
+		"ghcr.io/azure/azure-dev/azd:0": {},
+	},
+	"postCreateCommand": "echo // not a comment",
+}`), osutil.PermissionFile))
+
+		result, err := Sync(dir, prjConfig)
+		require.NoError(t, err)
+		require.False(t, result.Created)
+		require.Equal(t, []string{featureAzureCli}, result.Added)
+
+		config := readConfig(t, path)
+		require.Equal(t, "custom", config["name"])
+		require.Equal(t, "echo // not a comment", config["postCreateCommand"])
+		require.Equal(t, map[string]any{
+			"ghcr.io/devcontainers/features/python:3": map[string]any{"version": "3.12"},
+			"ghcr.io/azure/azure-dev/azd:0":           map[string]any{},
+			featureAzureCli:                           map[string]any{"installBicep": true},
+		}, config["features"])
+
+		// The members of the configuration keep their order
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var order object
+		require.NoError(t, json.Unmarshal(contents, &order))
+		keys := []string{}
+		for _, m := range order {
+			keys = append(keys, m.key)
+		}
+		require.Equal(t, []string{"name", "image", "features", "postCreateCommand"}, keys)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, RelativePath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(`["not", "an", "object"]`), osutil.PermissionFile))
+
+		_, err := Sync(dir, prjConfig)
+		require.ErrorContains(t, err, "parsing dev container configuration")
+	})
+}
+
+func Test_Missing(t *testing.T) {
+	prjConfig := &project.ProjectConfig{
+		Name: "todo",
+		Services: map[string]*project.ServiceConfig{
+			"api": {Host: project.AppServiceTarget, Language: project.ServiceLanguagePython},
+		},
+	}
+
+	t.Run("NoConfig", func(t *testing.T) {
+		missing, err := Missing(t.TempDir(), prjConfig)
+		require.NoError(t, err)
+		require.Empty(t, missing)
+	})
+
+	t.Run("ConfigUnchanged", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, RelativePath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		original := []byte(`{
+	// The image of the template
+	"image": "mcr.microsoft.com/devcontainers/python:3.12",
+	"features": {
+		"ghcr.io/devcontainers/features/python:3": {},
+	},
+}`)
+		require.NoError(t, os.WriteFile(path, original, osutil.PermissionFile))
+
+		missing, err := Missing(dir, prjConfig)
+		require.NoError(t, err)
+		require.Equal(t, []string{featureAzd, featureAzureCli}, missing)
+
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, original, contents)
+	})
+}
+
+func Test_featureName(t *testing.T) {
+	require.Equal(t, "ghcr.io/devcontainers/features/node", featureName("ghcr.io/devcontainers/features/node:1"))
+	require.Equal(t, "ghcr.io/azure/azure-dev/azd", featureName("ghcr.io/azure/azure-dev/azd:latest"))
+	require.Equal(t, "localhost:5000/features/node", featureName("localhost:5000/features/node"))
+	require.Equal(t, "./local-feature", featureName("./local-feature"))
+}
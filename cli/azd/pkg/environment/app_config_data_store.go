@@ -0,0 +1,276 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/appconfig"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+)
+
+const (
+	// appConfigConfigKey is the key (relative to the key prefix) holding the environment config.json contents
+	appConfigConfigKey = "config.json"
+	// appConfigDotEnvKeyPrefix is the key prefix (relative to the key prefix) of every .env value
+	appConfigDotEnvKeyPrefix = "env/"
+	// appConfigSubscriptionTag is the tag storing the subscription of a Key Vault reference
+	appConfigSubscriptionTag = "azd-subscription-id"
+)
+
+var ErrAppConfigAccessDenied = errors.New("access denied connecting to Azure App Configuration store.")
+
+// AppConfigDataStore stores environments in an Azure App Configuration store.
+//
+// Every environment is stored under its own label. The environment config is stored as a JSON key-value and every
+// .env value is stored as an individual key-value, so changes to each value are tracked by the store revision history.
+// Values referencing Key Vault secrets (akvs://) are stored as App Configuration Key Vault references.
+type AppConfigDataStore struct {
+	configManager  config.Manager
+	keyValueClient appconfig.KeyValueClient
+	accountConfig  *appconfig.AccountConfig
+	cloud          *cloud.Cloud
+}
+
+func NewAppConfigDataStore(
+	configManager config.Manager,
+	keyValueClient appconfig.KeyValueClient,
+	accountConfig *appconfig.AccountConfig,
+	cloud *cloud.Cloud,
+) RemoteDataStore {
+	return &AppConfigDataStore{
+		configManager:  configManager,
+		keyValueClient: keyValueClient,
+		accountConfig:  accountConfig,
+		cloud:          cloud,
+	}
+}
+
+// EnvPath returns the key prefix of the .env values for the given environment
+func (acd *AppConfigDataStore) EnvPath(env *Environment) string {
+	return fmt.Sprintf("%s%s (label: %s)", acd.accountConfig.KeyPrefix, appConfigDotEnvKeyPrefix, env.name)
+}
+
+// ConfigPath returns the key of the config.json value for the given environment
+func (acd *AppConfigDataStore) ConfigPath(env *Environment) string {
+	return fmt.Sprintf("%s%s (label: %s)", acd.accountConfig.KeyPrefix, appConfigConfigKey, env.name)
+}
+
+func (acd *AppConfigDataStore) List(ctx context.Context) ([]*contracts.EnvListEnvironment, error) {
+	keyValues, err := acd.keyValueClient.List(ctx, acd.accountConfig.KeyPrefix+appConfigConfigKey, "*")
+	if err != nil {
+		return nil, fmt.Errorf("listing key-values: %w", describeAppConfigError(err))
+	}
+
+	envs := []*contracts.EnvListEnvironment{}
+	for _, keyValue := range keyValues {
+		if keyValue.Label == "" {
+			continue
+		}
+
+		env := &Environment{name: keyValue.Label}
+		envs = append(envs, &contracts.EnvListEnvironment{
+			Name:       keyValue.Label,
+			DotEnvPath: acd.EnvPath(env),
+			ConfigPath: acd.ConfigPath(env),
+		})
+	}
+
+	slices.SortFunc(envs, func(a, b *contracts.EnvListEnvironment) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return envs, nil
+}
+
+func (acd *AppConfigDataStore) Get(ctx context.Context, name string) (*Environment, error) {
+	env := &Environment{
+		name: name,
+	}
+
+	if err := acd.Reload(ctx, env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (acd *AppConfigDataStore) Reload(ctx context.Context, env *Environment) error {
+	keyValues, err := acd.keyValueClient.List(ctx, acd.accountConfig.KeyPrefix+"*", env.name)
+	if err != nil {
+		return describeAppConfigError(err)
+	}
+
+	dotEnvPrefix := acd.accountConfig.KeyPrefix + appConfigDotEnvKeyPrefix
+	configKey := acd.accountConfig.KeyPrefix + appConfigConfigKey
+
+	var configValue *appconfig.KeyValue
+	dotenv := map[string]string{}
+	for _, keyValue := range keyValues {
+		switch {
+		case keyValue.Key == configKey:
+			configValue = keyValue
+		case strings.HasPrefix(keyValue.Key, dotEnvPrefix):
+			value, err := acd.fromKeyValue(keyValue)
+			if err != nil {
+				return err
+			}
+
+			dotenv[strings.TrimPrefix(keyValue.Key, dotEnvPrefix)] = value
+		}
+	}
+
+	if configValue == nil {
+		return fmt.Errorf("'%s': %w", env.name, ErrNotFound)
+	}
+
+	env.dotenv = dotenv
+	env.deletedKeys = make(map[string]struct{})
+
+	cfg, err := acd.configManager.Load(bytes.NewBufferString(configValue.Value))
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	env.Config = cfg
+
+	if env.Name() != "" {
+		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
+	}
+
+	return nil
+}
+
+func (acd *AppConfigDataStore) Save(ctx context.Context, env *Environment, options *SaveOptions) error {
+	cfgWriter := new(bytes.Buffer)
+	if err := acd.configManager.Save(env.Config, cfgWriter); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	if err := acd.keyValueClient.Set(ctx, &appconfig.KeyValue{
+		Key:         acd.accountConfig.KeyPrefix + appConfigConfigKey,
+		Label:       env.name,
+		Value:       cfgWriter.String(),
+		ContentType: "application/json",
+	}); err != nil {
+		return fmt.Errorf("saving config: %w", describeAppConfigError(err))
+	}
+
+	dotEnvPrefix := acd.accountConfig.KeyPrefix + appConfigDotEnvKeyPrefix
+	for key, value := range env.dotenv {
+		keyValue, err := acd.toKeyValue(dotEnvPrefix+key, env.name, value)
+		if err != nil {
+			return err
+		}
+
+		if err := acd.keyValueClient.Set(ctx, keyValue); err != nil {
+			return fmt.Errorf("saving '%s': %w", key, describeAppConfigError(err))
+		}
+	}
+
+	for key := range env.deletedKeys {
+		if err := acd.keyValueClient.Delete(ctx, dotEnvPrefix+key, env.name); err != nil {
+			return fmt.Errorf("deleting '%s': %w", key, describeAppConfigError(err))
+		}
+	}
+	env.deletedKeys = make(map[string]struct{})
+
+	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
+	return nil
+}
+
+func (acd *AppConfigDataStore) Delete(ctx context.Context, name string) error {
+	keyValues, err := acd.keyValueClient.List(ctx, acd.accountConfig.KeyPrefix+"*", name)
+	if err != nil {
+		return describeAppConfigError(err)
+	}
+
+	if len(keyValues) == 0 {
+		return fmt.Errorf("'%s': %w", name, ErrNotFound)
+	}
+
+	for _, keyValue := range keyValues {
+		if err := acd.keyValueClient.Delete(ctx, keyValue.Key, keyValue.Label); err != nil {
+			return fmt.Errorf("deleting '%s': %w", keyValue.Key, describeAppConfigError(err))
+		}
+	}
+
+	return nil
+}
+
+type keyVaultReference struct {
+	Uri string `json:"uri"`
+}
+
+// toKeyValue converts a .env value into a key-value, mapping akvs:// secret references to Key Vault references.
+func (acd *AppConfigDataStore) toKeyValue(key string, label string, value string) (*appconfig.KeyValue, error) {
+	if !keyvault.IsAzureKeyVaultSecret(value) {
+		return &appconfig.KeyValue{Key: key, Label: label, Value: value}, nil
+	}
+
+	secret, err := keyvault.ParseAzureKeyVaultSecret(value)
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := json.Marshal(keyVaultReference{
+		Uri: fmt.Sprintf(
+			"https://%s.%s/secrets/%s", secret.VaultName, acd.cloud.KeyVaultEndpointSuffix, secret.SecretName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &appconfig.KeyValue{
+		Key:         key,
+		Label:       label,
+		Value:       string(reference),
+		ContentType: appconfig.KeyVaultReferenceContentType,
+		Tags:        map[string]string{appConfigSubscriptionTag: secret.SubscriptionId},
+	}, nil
+}
+
+// fromKeyValue converts a key-value into a .env value, mapping Key Vault references back to akvs:// references.
+func (acd *AppConfigDataStore) fromKeyValue(keyValue *appconfig.KeyValue) (string, error) {
+	if keyValue.ContentType != appconfig.KeyVaultReferenceContentType {
+		return keyValue.Value, nil
+	}
+
+	var reference keyVaultReference
+	if err := json.Unmarshal([]byte(keyValue.Value), &reference); err != nil {
+		return "", fmt.Errorf("parsing Key Vault reference '%s': %w", keyValue.Key, err)
+	}
+
+	// https://<vault-name>.<suffix>/secrets/<secret-name>[/<version>]
+	host, path, _ := strings.Cut(strings.TrimPrefix(reference.Uri, "https://"), "/")
+	vaultName, _, _ := strings.Cut(host, ".")
+	pathParts := strings.Split(path, "/")
+	if vaultName == "" || len(pathParts) < 2 || pathParts[0] != "secrets" {
+		return "", fmt.Errorf("invalid Key Vault reference '%s': %s", keyValue.Key, reference.Uri)
+	}
+
+	return keyvault.NewAzureKeyVaultSecret(keyValue.Tags[appConfigSubscriptionTag], vaultName, pathParts[1]), nil
+}
+
+func describeAppConfigError(err error) error {
+	var responseErr *azcore.ResponseError
+
+	if errors.As(err, &responseErr) && responseErr.StatusCode == 403 {
+		errorMsg := "Ensure your Azure account has `App Configuration Data Owner` role on the App Configuration store."
+		return fmt.Errorf("%w %s %w", ErrAppConfigAccessDenied, errorMsg, err)
+	}
+
+	return err
+}
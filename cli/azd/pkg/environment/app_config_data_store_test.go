@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/appconfig"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AppConfigDataStore_SaveAndGet(t *testing.T) {
+	client := &fakeKeyValueClient{}
+	dataStore := NewAppConfigDataStore(
+		config.NewManager(), client, &appconfig.AccountConfig{KeyPrefix: "app/"}, cloud.AzurePublic())
+
+	env := New("env1")
+	env.DotenvSet("key1", "value1")
+	env.DotenvSet("SECRET", "akvs://sub-id/my-vault/my-secret")
+	require.NoError(t, env.Config.Set("infra.parameters.location", "eastus2"))
+	require.NoError(t, dataStore.Save(context.Background(), env, nil))
+
+	secret := client.find("app/env/SECRET", "env1")
+	require.NotNil(t, secret)
+	require.Equal(t, appconfig.KeyVaultReferenceContentType, secret.ContentType)
+	require.Equal(t, `{"uri":"https://my-vault.vault.azure.net/secrets/my-secret"}`, secret.Value)
+
+	loaded, err := dataStore.Get(context.Background(), "env1")
+	require.NoError(t, err)
+	require.Equal(t, "value1", loaded.Getenv("key1"))
+	require.Equal(t, "akvs://sub-id/my-vault/my-secret", loaded.Getenv("SECRET"))
+	location, has := loaded.Config.Get("infra.parameters.location")
+	require.True(t, has)
+	require.Equal(t, "eastus2", location)
+
+	loaded.DotenvDelete("key1")
+	require.NoError(t, dataStore.Save(context.Background(), loaded, nil))
+	require.Nil(t, client.find("app/env/key1", "env1"))
+
+	envs, err := dataStore.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, envs, 1)
+	require.Equal(t, "env1", envs[0].Name)
+
+	require.NoError(t, dataStore.Delete(context.Background(), "env1"))
+	_, err = dataStore.Get(context.Background(), "env1")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+type fakeKeyValueClient struct {
+	items []*appconfig.KeyValue
+}
+
+func (c *fakeKeyValueClient) find(key string, label string) *appconfig.KeyValue {
+	for _, item := range c.items {
+		if item.Key == key && item.Label == label {
+			return item
+		}
+	}
+
+	return nil
+}
+
+func (c *fakeKeyValueClient) List(ctx context.Context, keyFilter string, labelFilter string) ([]*appconfig.KeyValue, error) {
+	matches := func(filter string, value string) bool {
+		if prefix, wildcard := strings.CutSuffix(filter, "*"); wildcard {
+			return strings.HasPrefix(value, prefix)
+		}
+
+		return filter == value
+	}
+
+	result := []*appconfig.KeyValue{}
+	for _, item := range c.items {
+		if matches(keyFilter, item.Key) && matches(labelFilter, item.Label) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *fakeKeyValueClient) Set(ctx context.Context, keyValue *appconfig.KeyValue) error {
+	_ = c.Delete(ctx, keyValue.Key, keyValue.Label)
+	c.items = append(c.items, keyValue)
+	return nil
+}
+
+func (c *fakeKeyValueClient) Delete(ctx context.Context, key string, label string) error {
+	for i, item := range c.items {
+		if item.Key == key && item.Label == label {
+			c.items = append(c.items[:i], c.items[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
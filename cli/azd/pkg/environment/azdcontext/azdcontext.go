@@ -90,8 +90,13 @@ func (c *AzdContext) SetProjectState(state ProjectState) error {
 		return err
 	}
 
-	// make sure to ignore the environment directory
-	path = filepath.Join(c.EnvironmentDirectory(), ".gitignore")
+	return c.WriteEnvironmentGitIgnore()
+}
+
+// WriteEnvironmentGitIgnore writes the .gitignore file which ignores the environment directory, since the environments
+// hold values like credentials which aren't intended to be committed.
+func (c *AzdContext) WriteEnvironmentGitIgnore() error {
+	path := filepath.Join(c.EnvironmentDirectory(), ".gitignore")
 	return os.WriteFile(path, []byte("# .azure is not intended to be committed\n*"), osutil.PermissionFile)
 }
 
@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// ConfirmChangesConfigKey is the user config key enabling the confirmation of the changes to the .env file of an
+// environment before they are saved.
+const ConfirmChangesConfigKey = "environment.confirmChanges"
+
+// ErrChangesDeclined is returned when the user declines to save the changes to an environment.
+var ErrChangesDeclined = errors.New("the changes to the environment were not saved")
+
+// ChangeKind is the kind of change made to a value of the .env file of an environment.
+type ChangeKind string
+
+const (
+	ChangeKindAdded   ChangeKind = "added"
+	ChangeKindChanged ChangeKind = "changed"
+	ChangeKindRemoved ChangeKind = "removed"
+)
+
+// Change is a change made to a value of the .env file of an environment.
+type Change struct {
+	Key      string
+	Kind     ChangeKind
+	OldValue string
+	NewValue string
+}
+
+// maskedValue replaces the values of secrets when changes are displayed.
+const maskedValue = "*******"
+
+// secretKeyParts are the parts of the keys of values considered secrets when changes are displayed.
+var secretKeyParts = []string{"SECRET", "PASSWORD", "TOKEN", "CONNECTION_STRING", "ACCESS_KEY", "API_KEY"}
+
+// secretKeyWord is the last word of the keys of values considered secrets, ex) STORAGE_KEY. It's only matched as the last
+// word, as it's also part of the keys of values which aren't secrets, ex) AZURE_KEY_VAULT_NAME.
+const secretKeyWord = "KEY"
+
+// Changes returns the changes that saving env makes to the values of persisted, the values of the .env file of the
+// environment in its data store, sorted by key.
+func Changes(persisted map[string]string, env *Environment) []Change {
+	changes := []Change{}
+	for key, value := range env.dotenv {
+		oldValue, has := persisted[key]
+		if !has {
+			changes = append(changes, Change{Key: key, Kind: ChangeKindAdded, NewValue: value})
+		} else if oldValue != value {
+			changes = append(changes, Change{Key: key, Kind: ChangeKindChanged, OldValue: oldValue, NewValue: value})
+		}
+	}
+
+	for key := range env.deletedKeys {
+		if oldValue, has := persisted[key]; has {
+			changes = append(changes, Change{Key: key, Kind: ChangeKindRemoved, OldValue: oldValue})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return changes
+}
+
+// String returns the change as displayed to the user, masking the values of secrets.
+func (c Change) String() string {
+	oldValue, newValue := c.OldValue, c.NewValue
+	if isSecretKey(c.Key) {
+		oldValue, newValue = maskedValue, maskedValue
+	}
+
+	switch c.Kind {
+	case ChangeKindAdded:
+		return output.WithSuccessFormat("+ %s=%s", c.Key, newValue)
+	case ChangeKindRemoved:
+		return output.WithErrorFormat("- %s=%s", c.Key, oldValue)
+	default:
+		return output.WithWarningFormat("~ %s=%s -> %s", c.Key, oldValue, newValue)
+	}
+}
+
+// isSecretKey returns true when the value of key is likely a secret.
+func isSecretKey(key string) bool {
+	upperKey := strings.ToUpper(key)
+	if upperKey == secretKeyWord || strings.HasSuffix(upperKey, "_"+secretKeyWord) {
+		return true
+	}
+
+	return slices.ContainsFunc(secretKeyParts, func(part string) bool {
+		return strings.Contains(upperKey, part)
+	})
+}
+
+// confirmChangesEnabled returns true when the user config asks to confirm the changes to environments before saving.
+func confirmChangesEnabled(userConfig config.Config) bool {
+	value, has := userConfig.Get(ConfirmChangesConfigKey)
+	if !has {
+		return false
+	}
+
+	switch value := value.(type) {
+	case bool:
+		return value
+	case string:
+		enabled, err := strconv.ParseBool(value)
+		return err == nil && enabled || strings.EqualFold(value, "on")
+	default:
+		return false
+	}
+}
+
+// formatChanges returns the changes as displayed to the user before they are saved.
+func formatChanges(envName string, changes []Change) string {
+	lines := make([]string, 0, len(changes)+1)
+	lines = append(lines, fmt.Sprintf("Changes to environment %s:", output.WithHighLightFormat(envName)))
+	for _, change := range changes {
+		lines = append(lines, "  "+change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Changes(t *testing.T) {
+	env := NewWithValues("dev", map[string]string{
+		"AZURE_LOCATION":  "westus3",
+		"API_URL":         "https://api.contoso.com",
+		"DB_PASSWORD":     "new-password",
+		"AZURE_ENV_NAME":  "dev",
+		"UNCHANGED_VALUE": "value",
+	})
+	env.DotenvDelete("OLD_VALUE")
+	env.DotenvDelete("NEVER_SAVED")
+
+	changes := Changes(map[string]string{
+		"AZURE_LOCATION":  "eastus2",
+		"DB_PASSWORD":     "old-password",
+		"AZURE_ENV_NAME":  "dev",
+		"UNCHANGED_VALUE": "value",
+		"OLD_VALUE":       "old",
+	}, env)
+
+	require.Equal(t, []Change{
+		{Key: "API_URL", Kind: ChangeKindAdded, NewValue: "https://api.contoso.com"},
+		{Key: "AZURE_LOCATION", Kind: ChangeKindChanged, OldValue: "eastus2", NewValue: "westus3"},
+		{Key: "DB_PASSWORD", Kind: ChangeKindChanged, OldValue: "old-password", NewValue: "new-password"},
+		{Key: "OLD_VALUE", Kind: ChangeKindRemoved, OldValue: "old"},
+	}, changes)
+
+	require.Contains(t, changes[1].String(), "AZURE_LOCATION=eastus2 -> westus3")
+	require.Contains(t, changes[2].String(), "DB_PASSWORD=******* -> *******")
+	require.NotContains(t, changes[2].String(), "password")
+}
+
+func Test_isSecretKey(t *testing.T) {
+	for _, key := range []string{"DB_PASSWORD", "STORAGE_KEY", "OPENAI_API_KEY", "AZURE_OPENAI_KEY", "ACCESS_KEY_ID"} {
+		require.True(t, isSecretKey(key), key)
+	}
+
+	for _, key := range []string{"AZURE_LOCATION", "AZURE_KEY_VAULT_NAME", "AZURE_KEY_VAULT_ENDPOINT", "MONKEY_NAME"} {
+		require.False(t, isSecretKey(key), key)
+	}
+}
+
+func Test_EnvManager_ConfirmChanges(t *testing.T) {
+	setup := func(t *testing.T, userConfig map[string]any) (*mocks.MockContext, Manager, *Environment) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.ConfigManager.WithConfig(config.NewConfig(userConfig))
+
+		azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+		localDataStore := NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
+		envManager := &manager{
+			azdContext:        azdCtx,
+			console:           mockContext.Console,
+			local:             localDataStore,
+			userConfigManager: config.NewUserConfigManager(mockContext.ConfigManager),
+		}
+
+		env := New("dev")
+		env.DotenvSet("API_KEY", "secret")
+		require.NoError(t, envManager.SaveWithOptions(*mockContext.Context, env, &SaveOptions{IsNew: true}))
+
+		env.DotenvSet("API_KEY", "new-secret")
+		return mockContext, envManager, env
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		mockContext, envManager, env := setup(t, nil)
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).SetError(context.Canceled)
+
+		require.NoError(t, envManager.Save(*mockContext.Context, env))
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		mockContext, envManager, env := setup(t, map[string]any{
+			"environment": map[string]any{"confirmChanges": "true"},
+		})
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return options.Message == "Save these changes?"
+		}).Respond(false)
+
+		err := envManager.Save(*mockContext.Context, env)
+		require.ErrorIs(t, err, ErrChangesDeclined)
+
+		persisted, err := envManager.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		require.Equal(t, "secret", persisted.Getenv("API_KEY"))
+
+		output := strings.Join(mockContext.Console.Output(), "\n")
+		require.Contains(t, output, "API_KEY=******* -> *******")
+		require.NotContains(t, output, "new-secret")
+	})
+
+	t.Run("ConfirmedWithOption", func(t *testing.T) {
+		mockContext, envManager, env := setup(t, nil)
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return options.Message == "Save these changes?"
+		}).Respond(true)
+
+		err := envManager.SaveWithOptions(*mockContext.Context, env, &SaveOptions{ConfirmChanges: true})
+		require.NoError(t, err)
+
+		persisted, err := envManager.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		require.Equal(t, "new-secret", persisted.Getenv("API_KEY"))
+	})
+}
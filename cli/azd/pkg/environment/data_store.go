@@ -14,17 +14,28 @@ import (
 type RemoteKind string
 
 const (
-	RemoteKindAzureBlobStorage RemoteKind = "AzureBlobStorage"
+	RemoteKindAzureBlobStorage      RemoteKind = "AzureBlobStorage"
+	RemoteKindAzureAppConfiguration RemoteKind = "AzureAppConfiguration"
 )
 
 var ValidRemoteKinds = []string{
 	string(RemoteKindAzureBlobStorage),
+	string(RemoteKindAzureAppConfiguration),
 }
 
 // SaveOptions provide additional metadata for the save operation
 type SaveOptions struct {
 	// Whether or not the environment is new
 	IsNew bool
+	// Whether the changes to the .env file are displayed and confirmed before saving, regardless of the
+	// environment.confirmChanges user config
+	ConfirmChanges bool
+}
+
+type DeleteOptions struct {
+	// Whether the environment is also deleted from the remote state, when configured. Only 'azd env delete' deletes the
+	// remote state, other callers only remove the local copy of the environment.
+	IncludeRemote bool
 }
 
 type DataStore interface {
@@ -36,6 +36,10 @@ const PrincipalTypeEnvVarName = "AZURE_PRINCIPAL_TYPE"
 // TenantIdEnvVarName is the tenant that owns the subscription
 const TenantIdEnvVarName = "AZURE_TENANT_ID"
 
+// AuthTenantIdEnvVarName is the name of the key used to pin the tenant azd acquires tokens for when it runs with the
+// environment, ex) the tenant of a customer, instead of the tenant resolved from the subscription.
+const AuthTenantIdEnvVarName = "AZURE_AUTH_TENANT_ID"
+
 // ContainerRegistryEndpointEnvVarName is the name of they key used to store the endpoint of the container registry to push
 // to.
 const ContainerRegistryEndpointEnvVarName = "AZURE_CONTAINER_REGISTRY_ENDPOINT"
@@ -298,10 +302,15 @@ func fixupUnquotedDotenv(values map[string]string, dotenv string) string {
 // Instead of calling `godotenv.Write` directly, we need to save the file ourselves, so we can fixup any numeric values
 // that were incorrectly unquoted.
 func marshallDotEnv(env *Environment) (string, error) {
-	marshalled, err := godotenv.Marshal(env.dotenv)
+	return MarshalDotenv(env.dotenv)
+}
+
+// MarshalDotenv marshals the values to the contents of a dotenv file, keeping the leading zeros of numeric like values.
+func MarshalDotenv(values map[string]string) (string, error) {
+	marshalled, err := godotenv.Marshal(values)
 	if err != nil {
 		return "", fmt.Errorf("marshalling .env: %w", err)
 	}
 
-	return fixupUnquotedDotenv(env.dotenv, marshalled), nil
+	return fixupUnquotedDotenv(values, marshalled), nil
 }
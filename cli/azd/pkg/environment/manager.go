@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
@@ -18,6 +19,19 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 )
 
+var (
+	environmentPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "ENVIRONMENT",
+		Description: "The name of the environment to use, or 'Create' to create a new one.",
+		Commands:    []string{"provision", "up", "deploy", "down", "env refresh"},
+	})
+	environmentNamePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "ENVIRONMENT_NAME",
+		Description: "The name of the environment to create.",
+		Commands:    []string{"provision", "up", "deploy", "env new", "init"},
+	})
+)
+
 // Description is a metadata description of an environment returned for the `azd env list` command
 type Description struct {
 	// The name of the environment
@@ -78,16 +92,19 @@ type Manager interface {
 
 	// Delete deletes the environment from local storage.
 	Delete(ctx context.Context, name string) error
+	// DeleteWithOptions deletes the environment from local storage and, when set by the options, from remote storage.
+	DeleteWithOptions(ctx context.Context, name string, options *DeleteOptions) error
 
 	EnvPath(env *Environment) string
 	ConfigPath(env *Environment) string
 }
 
 type manager struct {
-	local      DataStore
-	remote     DataStore
-	azdContext *azdcontext.AzdContext
-	console    input.Console
+	local             DataStore
+	remote            DataStore
+	azdContext        *azdcontext.AzdContext
+	console           input.Console
+	userConfigManager config.UserConfigManager
 }
 
 // NewManager creates a new Manager instance
@@ -97,6 +114,7 @@ func NewManager(
 	console input.Console,
 	local LocalDataStore,
 	remoteConfig *state.RemoteConfig,
+	userConfigManager config.UserConfigManager,
 ) (Manager, error) {
 	var remote RemoteDataStore
 
@@ -119,10 +137,11 @@ func NewManager(
 	}
 
 	return &manager{
-		azdContext: azdContext,
-		local:      local,
-		remote:     remote,
-		console:    console,
+		azdContext:        azdContext,
+		local:             local,
+		remote:            remote,
+		console:           console,
+		userConfigManager: userConfigManager,
 	}, nil
 }
 
@@ -148,13 +167,15 @@ func (m *manager) Create(ctx context.Context, spec Spec) (*Environment, error) {
 	}
 
 	env := New(spec.Name)
+	initOrigin := ValueOrigin{Source: ValueSourceInit}
+	env.DotenvSetFrom(EnvNameEnvVarName, spec.Name, initOrigin)
 
 	if spec.Subscription != "" {
-		env.SetSubscriptionId(spec.Subscription)
+		env.DotenvSetFrom(SubscriptionIdEnvVarName, spec.Subscription, initOrigin)
 	}
 
 	if spec.Location != "" {
-		env.SetLocation(spec.Location)
+		env.DotenvSetFrom(LocationEnvVarName, spec.Location, initOrigin)
 	}
 
 	if err := m.SaveWithOptions(ctx, env, &SaveOptions{IsNew: true}); err != nil {
@@ -288,6 +309,7 @@ func (m *manager) loadOrInitEnvironment(ctx context.Context, environmentName str
 			}
 
 			selection, err = m.console.Select(ctx, input.ConsoleOptions{
+				Id:      environmentPromptId,
 				Message: "Select an environment to use:",
 				Options: choices,
 			})
@@ -434,6 +456,12 @@ func (m *manager) SaveWithOptions(ctx context.Context, env *Environment, options
 		options = &SaveOptions{}
 	}
 
+	if !options.IsNew {
+		if err := m.confirmChanges(ctx, env, options); err != nil {
+			return err
+		}
+	}
+
 	if err := m.local.Save(ctx, env, options); err != nil {
 		return fmt.Errorf("saving local environment, %w", err)
 	}
@@ -449,19 +477,88 @@ func (m *manager) SaveWithOptions(ctx context.Context, env *Environment, options
 	return nil
 }
 
+// confirmChanges displays the changes that saving env makes to its .env file and asks the user to confirm them, when
+// enabled by the options or by the environment.confirmChanges user config. Secret values are masked.
+func (m *manager) confirmChanges(ctx context.Context, env *Environment, options *SaveOptions) error {
+	if !options.ConfirmChanges {
+		if m.userConfigManager == nil {
+			return nil
+		}
+
+		userConfig, err := m.userConfigManager.Load()
+		if err != nil {
+			return fmt.Errorf("loading user config: %w", err)
+		}
+
+		if !confirmChangesEnabled(userConfig) {
+			return nil
+		}
+	}
+
+	persisted, err := m.local.Get(ctx, env.Name())
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("loading environment '%s': %w", env.Name(), err)
+	}
+
+	changes := Changes(persisted.dotenv, env)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	m.console.Message(ctx, formatChanges(env.Name(), changes))
+	confirm, err := m.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Save these changes?",
+		DefaultValue: true,
+	})
+	if err != nil {
+		return fmt.Errorf("confirming changes: %w", err)
+	}
+
+	if !confirm {
+		return ErrChangesDeclined
+	}
+
+	return nil
+}
+
 // Reload reloads the environment from the persistent data store
 func (m *manager) Reload(ctx context.Context, env *Environment) error {
 	return m.local.Reload(ctx, env)
 }
 
+// Delete deletes the environment from local storage
 func (m *manager) Delete(ctx context.Context, name string) error {
+	return m.DeleteWithOptions(ctx, name, nil)
+}
+
+// DeleteWithOptions deletes the environment from local storage and, when set by the options, from remote storage
+func (m *manager) DeleteWithOptions(ctx context.Context, name string, options *DeleteOptions) error {
 	if name == "" {
 		return ErrNameNotSpecified
 	}
 
-	err := m.local.Delete(ctx, name)
-	if err != nil {
-		return err
+	if options == nil {
+		options = &DeleteOptions{}
+	}
+
+	localErr := m.local.Delete(ctx, name)
+	if localErr != nil && !errors.Is(localErr, ErrNotFound) {
+		return localErr
+	}
+
+	remoteErr := error(ErrNotFound)
+	if m.remote != nil && options.IncludeRemote {
+		remoteErr = m.remote.Delete(ctx, name)
+		if remoteErr != nil && !errors.Is(remoteErr, ErrNotFound) {
+			return fmt.Errorf("deleting remote environment: %w", remoteErr)
+		}
+	}
+
+	// The environment only needs to exist in one of the stores
+	if localErr != nil && remoteErr != nil {
+		return localErr
 	}
 
 	defaultEnvName, err := m.azdContext.GetDefaultEnvironmentName()
@@ -493,6 +590,7 @@ func (m *manager) ensureValidEnvironmentName(ctx context.Context, spec *Spec) er
 
 	for !IsValidEnvironmentName(spec.Name) {
 		userInput, err := m.console.Prompt(ctx, input.ConsoleOptions{
+			Id:      environmentNamePromptId,
 			Message: "Enter a unique environment name:",
 			Help: heredoc.Doc(`
 			A unique string that can be used to differentiate copies of your application in Azure.
@@ -510,6 +608,11 @@ func (m *manager) ensureValidEnvironmentName(ctx context.Context, spec *Spec) er
 		spec.Name = userInput
 
 		if !IsValidEnvironmentName(spec.Name) {
+			if environmentNamePromptId.HasAnswer() {
+				return fmt.Errorf(
+					"%s value '%s' is not a valid environment name", environmentNamePromptId.EnvVarName(), spec.Name)
+			}
+
 			m.console.Message(ctx, invalidEnvironmentNameMsg(spec.Name))
 		}
 	}
@@ -324,6 +324,64 @@ func Test_EnvManager_Save(t *testing.T) {
 	})
 }
 
+func Test_EnvManager_Delete(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+
+	t.Run("LocalAndRemote", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("Delete", *mockContext.Context, "env1").Return(nil)
+		remoteDataStore.On("Delete", *mockContext.Context, "env1").Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.DeleteWithOptions(*mockContext.Context, "env1", &DeleteOptions{IncludeRemote: true})
+		require.NoError(t, err)
+
+		localDataStore.AssertCalled(t, "Delete", *mockContext.Context, "env1")
+		remoteDataStore.AssertCalled(t, "Delete", *mockContext.Context, "env1")
+	})
+
+	t.Run("LocalByDefault", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("Delete", *mockContext.Context, "env1").Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.Delete(*mockContext.Context, "env1")
+		require.NoError(t, err)
+
+		localDataStore.AssertCalled(t, "Delete", *mockContext.Context, "env1")
+		remoteDataStore.AssertNotCalled(t, "Delete", *mockContext.Context, "env1")
+	})
+
+	t.Run("OnlyRemote", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("Delete", *mockContext.Context, "env1").Return(ErrNotFound)
+		remoteDataStore.On("Delete", *mockContext.Context, "env1").Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.DeleteWithOptions(*mockContext.Context, "env1", &DeleteOptions{IncludeRemote: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("Delete", *mockContext.Context, "env1").Return(ErrNotFound)
+		remoteDataStore.On("Delete", *mockContext.Context, "env1").Return(ErrNotFound)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.DeleteWithOptions(*mockContext.Context, "env1", &DeleteOptions{IncludeRemote: true})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
 func Test_EnvManager_CreateFromContainer(t *testing.T) {
 	t.Run("WithRemoteConfig", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
@@ -381,6 +439,9 @@ func registerContainerComponents(t *testing.T, mockContext *mocks.MockContext) {
 	})
 	mockContext.Container.MustRegisterSingleton(storage.NewBlobSdkClient)
 	mockContext.Container.MustRegisterSingleton(config.NewManager)
+	mockContext.Container.MustRegisterSingleton(func() config.UserConfigManager {
+		return config.NewUserConfigManager(mockContext.ConfigManager)
+	})
 	mockContext.Container.MustRegisterSingleton(storage.NewBlobClient)
 
 	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
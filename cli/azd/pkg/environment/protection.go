@@ -0,0 +1,28 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+// The path of the lock of an environment in its config. A locked environment can't be deleted by 'azd down' or
+// 'azd env delete' without --unlock and typing the name of the environment.
+const LockedConfigPath = "protection.locked"
+
+// IsLocked returns whether the environment is locked against deletion.
+func (e *Environment) IsLocked() bool {
+	value, has := e.Config.Get(LockedConfigPath)
+	if !has {
+		return false
+	}
+
+	locked, ok := value.(bool)
+	return ok && locked
+}
+
+// SetLocked locks or unlocks the environment. The environment must be saved for the change to be persisted.
+func (e *Environment) SetLocked(locked bool) error {
+	if !locked {
+		return e.Config.Unset(LockedConfigPath)
+	}
+
+	return e.Config.Set(LockedConfigPath, true)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ValueSource describes what set the value of an environment variable.
+type ValueSource string
+
+const (
+	// ValueSourceInit is used for the defaults set when the environment is created (ex: name, subscription, location).
+	ValueSourceInit ValueSource = "init"
+	// ValueSourceProvision is used for the outputs of a provisioning operation.
+	ValueSourceProvision ValueSource = "provision"
+	// ValueSourceUser is used for values set by the user with 'azd env set'.
+	ValueSourceUser ValueSource = "user"
+	// ValueSourceHook is used for values set with 'azd env set' while running a hook.
+	ValueSourceHook ValueSource = "hook"
+)
+
+// HookNameEnvVarName is set to the name of the running hook in the environment of hook scripts, which allows values set
+// by hooks to be attributed to them.
+const HookNameEnvVarName = "AZD_HOOK_NAME"
+
+// provenanceConfigPath is the environment config section where the provenance of values is recorded.
+const provenanceConfigPath = "provenance"
+
+// provenanceMaxEntries is the number of changes recorded for each value.
+const provenanceMaxEntries = 10
+
+// ValueOrigin describes where the value of an environment variable came from.
+type ValueOrigin struct {
+	Source ValueSource `json:"source"`
+	// Additional details about the source when known (ex: the name of the hook or deployment)
+	Detail string `json:"detail,omitempty"`
+}
+
+func (o ValueOrigin) String() string {
+	if o.Detail == "" {
+		return string(o.Source)
+	}
+
+	return fmt.Sprintf("%s (%s)", o.Source, o.Detail)
+}
+
+// ProvenanceEntry records a change of the value of an environment variable. The value itself isn't recorded, since it
+// may be a secret, only its fingerprint which tells whether the value changed since.
+type ProvenanceEntry struct {
+	ValueOrigin
+	Fingerprint string    `json:"fingerprint"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Matches returns true when the entry recorded the value.
+func (e ProvenanceEntry) Matches(value string) bool {
+	return e.Fingerprint == ValueFingerprint(value)
+}
+
+// ValueFingerprint returns the fingerprint of a value recorded in its provenance, a truncated SHA-256 hash of the value.
+func ValueFingerprint(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(hash[:8])
+}
+
+// DotenvSetFrom sets the value of [key] to [value] like [DotenvSet] and records the origin of the value in the environment
+// config when the value changed. [Save] should be called to ensure this change is persisted.
+func (e *Environment) DotenvSetFrom(key string, value string, origin ValueOrigin) {
+	current, has := e.dotenv[key]
+	e.DotenvSet(key, value)
+
+	// Keys are used as a config path, which can't contain path separators
+	if strings.Contains(key, ".") {
+		return
+	}
+
+	history := e.Provenance(key)
+	if has && current == value && len(history) > 0 && history[len(history)-1].Matches(value) {
+		return
+	}
+
+	history = append(history, ProvenanceEntry{
+		ValueOrigin: origin,
+		Fingerprint: ValueFingerprint(value),
+		Timestamp:   time.Now().UTC(),
+	})
+	if len(history) > provenanceMaxEntries {
+		history = history[len(history)-provenanceMaxEntries:]
+	}
+
+	if err := e.Config.Set(provenancePath(key), history); err != nil {
+		log.Printf("failed recording provenance of '%s': %v", key, err)
+	}
+}
+
+// Provenance returns the recorded changes of the value of [key], oldest first. Values which were never set with
+// [DotenvSetFrom] have no recorded provenance.
+func (e *Environment) Provenance(key string) []ProvenanceEntry {
+	if e.Config == nil || strings.Contains(key, ".") {
+		return nil
+	}
+
+	var history []ProvenanceEntry
+	if _, err := e.Config.GetSection(provenancePath(key), &history); err != nil {
+		log.Printf("failed reading provenance of '%s': %v", key, err)
+		return nil
+	}
+
+	return history
+}
+
+func provenancePath(key string) string {
+	return provenanceConfigPath + "." + key
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DotenvSetFrom(t *testing.T) {
+	t.Run("RecordsChanges", func(t *testing.T) {
+		env := New("test")
+		env.DotenvSetFrom("AZURE_STORAGE_ACCOUNT", "st1", ValueOrigin{Source: ValueSourceProvision})
+		env.DotenvSetFrom("AZURE_STORAGE_ACCOUNT", "st1", ValueOrigin{Source: ValueSourceProvision})
+		env.DotenvSetFrom("AZURE_STORAGE_ACCOUNT", "st2", ValueOrigin{Source: ValueSourceHook, Detail: "postprovision"})
+
+		require.Equal(t, "st2", env.Getenv("AZURE_STORAGE_ACCOUNT"))
+
+		history := env.Provenance("AZURE_STORAGE_ACCOUNT")
+		require.Len(t, history, 2)
+		require.Equal(t, ValueSourceProvision, history[0].Source)
+		require.True(t, history[0].Matches("st1"))
+		require.Equal(t, "hook (postprovision)", history[1].ValueOrigin.String())
+		require.True(t, history[1].Matches("st2"))
+		require.False(t, history[1].Timestamp.IsZero())
+	})
+
+	t.Run("LimitsHistory", func(t *testing.T) {
+		env := New("test")
+		for i := range provenanceMaxEntries + 5 {
+			env.DotenvSetFrom("KEY", fmt.Sprintf("value%d", i), ValueOrigin{Source: ValueSourceUser})
+		}
+
+		history := env.Provenance("KEY")
+		require.Len(t, history, provenanceMaxEntries)
+		require.True(t, history[0].Matches("value5"))
+	})
+
+	t.Run("SurvivesConfigRoundTrip", func(t *testing.T) {
+		env := New("test")
+		env.DotenvSetFrom("KEY", "value", ValueOrigin{Source: ValueSourceInit})
+
+		// Simulates reloading the config from its persisted JSON representation
+		raw, err := json.Marshal(env.Config.Raw())
+		require.NoError(t, err)
+		var persisted map[string]any
+		require.NoError(t, json.Unmarshal(raw, &persisted))
+
+		reloaded := New("test")
+		reloaded.Config = config.NewConfig(persisted)
+
+		history := reloaded.Provenance("KEY")
+		require.Len(t, history, 1)
+		require.Equal(t, ValueSourceInit, history[0].Source)
+	})
+
+	t.Run("DoesNotRecordValues", func(t *testing.T) {
+		env := New("test")
+		env.DotenvSetFrom("API_KEY", "s3cr3t-value", ValueOrigin{Source: ValueSourceUser})
+
+		raw, err := json.Marshal(env.Config.Raw())
+		require.NoError(t, err)
+		require.NotContains(t, string(raw), "s3cr3t-value")
+		require.Contains(t, string(raw), ValueFingerprint("s3cr3t-value"))
+	})
+
+	t.Run("NoProvenance", func(t *testing.T) {
+		env := New("test")
+		env.DotenvSet("KEY", "value")
+
+		require.Empty(t, env.Provenance("KEY"))
+	})
+}
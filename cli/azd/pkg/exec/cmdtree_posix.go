@@ -7,6 +7,8 @@
 package exec
 
 import (
+	"errors"
+	"os"
 	"os/exec"
 	"syscall"
 )
@@ -25,7 +27,7 @@ func (o *CmdTree) Start() error {
 	// Non interactive commands like `gh auth status` can be spawn
 	// wit a new process group (not as a child process) as it won't
 	// require stdin to interact with the user
-	if !o.Interactive {
+	if !o.Interactive || o.ProcessGroup {
 		o.Cmd.SysProcAttr = &syscall.SysProcAttr{
 			Setpgid: true,
 		}
@@ -34,6 +36,16 @@ func (o *CmdTree) Start() error {
 	return o.Cmd.Start()
 }
 
+// Interrupt sends SIGINT to the process group, like Ctrl+C does.
+func (o *CmdTree) Interrupt() error {
+	err := syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGINT)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+
+	return err
+}
+
 func (o *CmdTree) Kill() {
 	_ = syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGKILL)
 }
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build !windows
+// +build !windows
+
+package exec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopGracePeriodStopsProcessGroup(t *testing.T) {
+	runner := NewCommandRunner(nil)
+	pidFile := filepath.Join(t.TempDir(), "pid")
+
+	tests := map[string]struct {
+		script      string
+		gracePeriod time.Duration
+	}{
+		// The process exits on SIGINT before the grace period is over
+		"Interrupted": {
+			script:      "echo $$ > " + pidFile + "; exec sleep 30",
+			gracePeriod: 20 * time.Second,
+		},
+		// The shell and its background child ignore SIGINT, they're killed once the grace period is over
+		"Killed": {
+			script:      "sleep 30 & echo $! > " + pidFile + "; trap '' INT; wait",
+			gracePeriod: 500 * time.Millisecond,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				for {
+					if _, err := os.Stat(pidFile); err == nil {
+						break
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+				cancel()
+			}()
+
+			start := time.Now()
+			_, err := runner.Run(ctx, NewRunArgs("/bin/sh", "-c", test.script).
+				WithInteractive(true).
+				WithStopGracePeriod(test.gracePeriod))
+			require.Error(t, err)
+			require.Less(t, time.Since(start), 10*time.Second)
+
+			content, err := os.ReadFile(pidFile)
+			require.NoError(t, err)
+			require.NoError(t, os.Remove(pidFile))
+			pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+			require.NoError(t, err)
+
+			// The process, or the child process of the shell, is stopped
+			require.Eventually(t, func() bool {
+				return syscall.Kill(pid, 0) != nil
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
@@ -80,6 +80,13 @@ func (o *CmdTree) Start() error {
 	return nil
 }
 
+// Interrupt sends CTRL_BREAK_EVENT to the process group, the equivalent of Ctrl+C for processes which don't share the
+// console group of azd.
+func (o *CmdTree) Interrupt() error {
+	//nolint:gosec // G115: integer overflow conversion int -> uint32
+	return windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(o.Process.Pid))
+}
+
 func (o *CmdTree) Kill() {
 	err := windows.TerminateJobObject(o.jobObject, 0)
 	if err != nil {
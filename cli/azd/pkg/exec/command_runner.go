@@ -15,11 +15,14 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // Settings to modify the way CmdTree is executed
 type CmdTreeOptions struct {
 	Interactive bool
+	// Runs the command in its own process group even when it's interactive
+	ProcessGroup bool
 }
 
 // CommandRunner exposes the contract for executing console/shell commands for the specified runArgs
@@ -101,6 +104,12 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		return RunResult{}, err
 	}
 
+	if args.StopGracePeriod > 0 {
+		cmd.ProcessGroup = true
+		// The process group is interrupted instead of killing the root process only, and killed after the grace period
+		cmd.Cancel = cmd.Interrupt
+	}
+
 	cmd.Dir = args.Cwd
 
 	var stdin io.Reader
@@ -116,6 +125,9 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 
 	if args.Interactive {
 		cmd.Stdin = r.stdin
+		if args.StopGracePeriod > 0 {
+			cmd.Stdin = stdin
+		}
 		cmd.Stdout = r.stdout
 		cmd.Stderr = r.stderr
 	} else {
@@ -150,15 +162,25 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		return RunResult{}, err
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
+	exited := make(chan struct{})
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+			if args.StopGracePeriod > 0 {
+				select {
+				case <-exited:
+				case <-time.After(args.StopGracePeriod):
+				}
+			}
+		case <-exited:
+		}
+
+		// Kills the lingering child processes
 		cmd.Kill()
 	}()
 
 	err = cmd.Wait()
+	close(exited)
 
 	var result RunResult
 
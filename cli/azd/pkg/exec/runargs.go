@@ -5,6 +5,7 @@ package exec
 
 import (
 	"io"
+	"time"
 )
 
 // RunArgs exposes the command, arguments and other options when running console/shell commands
@@ -35,6 +36,11 @@ type RunArgs struct {
 
 	// When set will call the command with the specified StdOut
 	StdOut io.Writer
+
+	// When set, the command runs in its own process group, or job object on Windows, even when interactive. When the
+	// context is cancelled, the process group is interrupted like with Ctrl+C, then killed once the grace period is
+	// over. Interactive commands aren't attached to the console input, since they don't run in its foreground group.
+	StopGracePeriod time.Duration
 }
 
 // NewRunArgs creates a new instance with the specified cmd and args
@@ -80,6 +86,13 @@ func (b RunArgs) WithInteractive(interactive bool) RunArgs {
 	return b
 }
 
+// Updates the grace period between interrupting the process group of the command and killing it when the context is
+// cancelled
+func (b RunArgs) WithStopGracePeriod(gracePeriod time.Duration) RunArgs {
+	b.StopGracePeriod = gracePeriod
+	return b
+}
+
 // Updates whether or not this will be run in a shell
 func (b RunArgs) WithShell(useShell bool) RunArgs {
 	b.UseShell = useShell
@@ -0,0 +1,244 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ext
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// lookupEnvFn returns the value of an environment variable and whether it was set.
+type lookupEnvFn func(key string) (string, bool)
+
+// evalCondition evaluates a hook `runIf` expression against environment values.
+//
+// The supported syntax is:
+//   - `NAME` is the value of the environment variable NAME, which is true when it is set to a value other than
+//     an empty string, `false` or `0`
+//   - `"text"` or `'text'` is a string literal
+//   - `a == b` and `a != b` compare values as strings
+//   - `!a`, `a && b`, `a || b` and parentheses combine conditions
+func evalCondition(expression string, lookupEnv lookupEnvFn) (bool, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return false, err
+	}
+
+	if len(tokens) == 0 {
+		return false, fmt.Errorf("condition is empty")
+	}
+
+	p := &conditionParser{tokens: tokens, lookupEnv: lookupEnv}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("unexpected '%s' in condition", p.tokens[p.pos].value)
+	}
+
+	return result, nil
+}
+
+type conditionTokenKind int
+
+const (
+	conditionTokenOperator conditionTokenKind = iota
+	conditionTokenIdentifier
+	conditionTokenString
+)
+
+type conditionToken struct {
+	kind  conditionTokenKind
+	value string
+}
+
+var conditionOperators = []string{"==", "!=", "&&", "||", "!", "(", ")"}
+
+func tokenizeCondition(expression string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		if r == '"' || r == '\'' {
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string in condition")
+			}
+
+			tokens = append(tokens, conditionToken{kind: conditionTokenString, value: string(runes[i+1 : end])})
+			i = end + 1
+			continue
+		}
+
+		if isConditionIdentifierRune(r) {
+			end := i
+			for end < len(runes) && isConditionIdentifierRune(runes[end]) {
+				end++
+			}
+
+			tokens = append(tokens, conditionToken{kind: conditionTokenIdentifier, value: string(runes[i:end])})
+			i = end
+			continue
+		}
+
+		matched := false
+		for _, op := range conditionOperators {
+			if strings.HasPrefix(string(runes[i:]), op) {
+				tokens = append(tokens, conditionToken{kind: conditionTokenOperator, value: op})
+				i += len(op)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("unexpected character '%c' in condition", r)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isConditionIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// conditionParser is a recursive descent parser which evaluates the condition while parsing it.
+type conditionParser struct {
+	tokens    []conditionToken
+	pos       int
+	lookupEnv lookupEnvFn
+}
+
+func (p *conditionParser) peekOperator(op string) bool {
+	return p.pos < len(p.tokens) &&
+		p.tokens[p.pos].kind == conditionTokenOperator &&
+		p.tokens[p.pos].value == op
+}
+
+func (p *conditionParser) parseOr() (bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+
+	for p.peekOperator("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+
+		left = left || right
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return false, err
+	}
+
+	for p.peekOperator("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+
+		left = left && right
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (bool, error) {
+	if p.peekOperator("!") {
+		p.pos++
+		value, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+
+		return !value, nil
+	}
+
+	if p.peekOperator("(") {
+		p.pos++
+		value, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+
+		if !p.peekOperator(")") {
+			return false, fmt.Errorf("missing ')' in condition")
+		}
+
+		p.pos++
+		return value, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return false, err
+	}
+
+	for _, op := range []string{"==", "!="} {
+		if p.peekOperator(op) {
+			p.pos++
+			right, err := p.parseOperand()
+			if err != nil {
+				return false, err
+			}
+
+			return (left == right) == (op == "=="), nil
+		}
+	}
+
+	return isTruthy(left), nil
+}
+
+func (p *conditionParser) parseOperand() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("unexpected end of condition")
+	}
+
+	token := p.tokens[p.pos]
+	switch token.kind {
+	case conditionTokenString:
+		p.pos++
+		return token.value, nil
+	case conditionTokenIdentifier:
+		p.pos++
+		value, _ := p.lookupEnv(token.value)
+		return value, nil
+	default:
+		return "", fmt.Errorf("unexpected '%s' in condition", token.value)
+	}
+}
+
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "0":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EvalCondition(t *testing.T) {
+	values := map[string]string{
+		"SEED_DATA": "true",
+		"DISABLED":  "false",
+		"ZERO":      "0",
+		"STAGE":     "dev",
+		"LOCATION":  "eastus2",
+	}
+
+	lookupEnv := func(key string) (string, bool) {
+		value, has := values[key]
+		return value, has
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"SEED_DATA", true},
+		{"DISABLED", false},
+		{"ZERO", false},
+		{"MISSING", false},
+		{"!MISSING", true},
+		{`STAGE == "dev"`, true},
+		{`STAGE == 'prod'`, false},
+		{`STAGE != "prod"`, true},
+		{`"dev" == STAGE`, true},
+		{`MISSING == ""`, true},
+		{`SEED_DATA && STAGE == "dev"`, true},
+		{`DISABLED || LOCATION == "eastus2"`, true},
+		{`DISABLED || SEED_DATA && MISSING`, false},
+		{`(DISABLED || SEED_DATA) && !MISSING`, true},
+		{`!(STAGE == "dev")`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			result, err := evalCondition(test.expression, lookupEnv)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func Test_EvalCondition_Invalid(t *testing.T) {
+	lookupEnv := func(key string) (string, bool) { return "", false }
+
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{"", "condition is empty"},
+		{`STAGE == "dev`, "unterminated string"},
+		{"STAGE ==", "unexpected end of condition"},
+		{"(STAGE", "missing ')'"},
+		{"STAGE dev", "unexpected 'dev'"},
+		{"STAGE = dev", "unexpected character '='"},
+		{"&& STAGE", "unexpected '&&'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := evalCondition(test.expression, lookupEnv)
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
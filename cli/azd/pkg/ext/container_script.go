@@ -0,0 +1,247 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+const (
+	// The directory the project or service is mounted at in hook containers
+	containerWorkspaceDir = "/workspace"
+	// The directory the hook script is mounted at in hook containers
+	containerScriptDir = "/azd-hook"
+)
+
+// The exit code reported by 'docker run' when docker itself fails, instead of the script. Exit codes 126 and 127 are
+// not distinguished since shells report them as well.
+// https://docs.docker.com/engine/containers/run/#exit-status
+const dockerRunErrorExitCode = 125
+
+// newContainerScript creates a script running hooks inside a container of the configured image, with docker
+func newContainerScript(
+	commandRunner exec.CommandRunner,
+	cwd string,
+	envVars []string,
+	shell ShellType,
+	config *HookContainerConfig,
+) tools.Script {
+	return &containerScript{
+		commandRunner: commandRunner,
+		cwd:           cwd,
+		envVars:       envVars,
+		shell:         shell,
+		config:        config,
+	}
+}
+
+type containerScript struct {
+	commandRunner exec.CommandRunner
+	cwd           string
+	envVars       []string
+	shell         ShellType
+	config        *HookContainerConfig
+}
+
+// Execute runs the script in a new container, which is removed once the script completes. The exit code of the
+// script is the exit code of the result.
+func (cs *containerScript) Execute(
+	ctx context.Context,
+	scriptPath string,
+	options tools.ExecOptions,
+) (exec.RunResult, error) {
+	if err := cs.ensureImage(ctx, options.StdOut); err != nil {
+		return exec.RunResult{}, err
+	}
+
+	name, err := containerName()
+	if err != nil {
+		return exec.RunResult{}, err
+	}
+
+	args, err := cs.runArgs(name, scriptPath)
+	if err != nil {
+		return exec.RunResult{}, err
+	}
+
+	interactive := options.Interactive != nil && *options.Interactive
+	if interactive {
+		args = slices.Insert(args, 1, "--interactive", "--tty")
+	}
+
+	runArgs := exec.NewRunArgs("docker", args...).
+		WithCwd(cs.cwd).
+		WithEnv(cs.containerEnv()).
+		WithInteractive(interactive)
+
+	if options.StdOut != nil {
+		runArgs = runArgs.WithStdOut(options.StdOut)
+	}
+
+	res, err := cs.commandRunner.Run(ctx, runArgs)
+	if ctx.Err() != nil {
+		// Stopping the docker client, ex: on timeout, doesn't stop the container
+		cs.removeContainer(name)
+	}
+
+	if err != nil && res.ExitCode == dockerRunErrorExitCode {
+		return res, fmt.Errorf("running hook container of image '%s': %w", cs.config.Image, err)
+	}
+
+	return res, err
+}
+
+// ensureImage pulls the image of the container when it's not available locally, writing the progress of the pull to
+// stdOut.
+func (cs *containerScript) ensureImage(ctx context.Context, stdOut io.Writer) error {
+	inspectArgs := exec.NewRunArgs("docker", "image", "inspect", "--format", "{{.Id}}", cs.config.Image)
+	if _, err := cs.commandRunner.Run(ctx, inspectArgs); err == nil {
+		return nil
+	}
+
+	log.Printf("pulling hook container image '%s'", cs.config.Image)
+	if stdOut != nil {
+		fmt.Fprintf(stdOut, "Pulling image %s\n", cs.config.Image)
+	}
+
+	pullArgs := exec.NewRunArgs("docker", "pull", cs.config.Image)
+	if stdOut != nil {
+		pullArgs = pullArgs.WithStdOut(stdOut)
+	}
+
+	if _, err := cs.commandRunner.Run(ctx, pullArgs); err != nil {
+		return fmt.Errorf("pulling hook container image '%s': %w", cs.config.Image, err)
+	}
+
+	return nil
+}
+
+// runArgs returns the arguments of 'docker run' for the script
+func (cs *containerScript) runArgs(name string, scriptPath string) ([]string, error) {
+	hostScriptPath := scriptPath
+	if !filepath.IsAbs(hostScriptPath) {
+		hostScriptPath = filepath.Join(cs.cwd, hostScriptPath)
+	}
+
+	containerScriptPath := path.Join(containerScriptDir, filepath.Base(hostScriptPath))
+
+	args := []string{
+		"run",
+		"--rm",
+		"--name", name,
+		"--workdir", containerWorkspaceDir,
+		"--volume", fmt.Sprintf("%s:%s", cs.cwd, containerWorkspaceDir),
+		"--volume", fmt.Sprintf("%s:%s:ro", hostScriptPath, containerScriptPath),
+	}
+
+	for _, volume := range cs.config.Volumes {
+		resolved, err := cs.resolveVolume(volume)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, "--volume", resolved)
+	}
+
+	for _, key := range cs.envKeys() {
+		// Only the name is passed, docker reads the value from its environment so that secrets aren't part of the
+		// command line
+		args = append(args, "--env", key)
+	}
+
+	args = append(args, cs.config.Image)
+
+	switch ShellType(strings.Split(string(cs.shell), " ")[0]) {
+	case ShellTypePowershell:
+		args = append(args, "pwsh", "-NoProfile", "-File", containerScriptPath)
+	default:
+		args = append(args, "sh", containerScriptPath)
+	}
+
+	return args, nil
+}
+
+// resolveVolume resolves the source of a volume relative to the working directory, or the home directory when it
+// starts with '~'. Named volumes are left as is.
+func (cs *containerScript) resolveVolume(volume string) (string, error) {
+	// Keeps the drive of Windows paths, ex: C:\data:/data
+	drive := filepath.VolumeName(volume)
+	source, target, found := strings.Cut(volume[len(drive):], ":")
+	source = drive + source
+
+	if !found || source == "" || target == "" {
+		return "", fmt.Errorf("container volume '%s' is invalid, use the '<source>:<target>[:<options>]' format", volume)
+	}
+
+	switch {
+	case source == "~" || strings.HasPrefix(source, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolving container volume '%s': %w", volume, err)
+		}
+
+		source = filepath.Join(home, strings.TrimPrefix(source, "~"))
+	case strings.HasPrefix(source, "."):
+		source = filepath.Join(cs.cwd, source)
+	}
+
+	return source + ":" + target, nil
+}
+
+// envKeys returns the names of the environment variables set in the container
+func (cs *containerScript) envKeys() []string {
+	keys := []string{}
+	for _, envVar := range cs.containerEnv() {
+		key, _, _ := strings.Cut(envVar, "=")
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// containerEnv returns the environment variables of the hook followed by the ones of the container configuration, which
+// take precedence.
+func (cs *containerScript) containerEnv() []string {
+	env := slices.Clone(cs.envVars)
+	for key, value := range cs.config.Env {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return env
+}
+
+// removeContainer removes the container, which keeps running when the docker client is stopped
+func (cs *containerScript) removeContainer(name string) {
+	// The context of the hook is done at this point
+	removeArgs := exec.NewRunArgs("docker", "rm", "--force", name)
+	if _, err := cs.commandRunner.Run(context.Background(), removeArgs); err != nil {
+		log.Printf("failed removing hook container '%s': %v", name, err)
+	}
+}
+
+// containerName returns a unique name for the container of a hook
+func containerName() (string, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating container name: %w", err)
+	}
+
+	return "azd-hook-" + hex.EncodeToString(suffix), nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Hooks_Container(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.NewWithValues("test", map[string]string{
+		"DATABASE_HOST": "db.contoso.com",
+	})
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Reload", mock.Anything, env).Return(nil)
+
+	newRunner := func(mockContext *mocks.MockContext, hookConfig *HookConfig) *HooksRunner {
+		hooksMap := map[string][]*HookConfig{"predeploy": {hookConfig}}
+		ensureScriptsExist(t, hooksMap)
+
+		return NewHooksRunner(
+			NewHooksManager(cwd),
+			mockContext.CommandRunner,
+			envManager,
+			mockContext.Console,
+			cwd,
+			hooksMap,
+			env,
+			mockContext.Container,
+		)
+	}
+
+	mockImage := func(mockContext *mocks.MockContext, exists bool) *bool {
+		pulled := false
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker image inspect")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			if exists {
+				return exec.NewRunResult(0, "sha256:0123", ""), nil
+			}
+
+			return exec.NewRunResult(1, "", "No such image"), errors.New("exit code: 1")
+		})
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker pull")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			pulled = true
+			require.Equal(t, []string{"pull", "postgres:16"}, args.Args)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		return &pulled
+	}
+
+	t.Run("RunsInContainer", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		pulled := mockImage(mockContext, true)
+
+		var runArgs exec.RunArgs
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker run")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		runner := newRunner(mockContext, &HookConfig{
+			Run: "scripts/predeploy.sh",
+			Container: &HookContainerConfig{
+				Image:   "postgres:16",
+				Volumes: []string{"./data:/data:ro", "cache:/root/.cache"},
+				Env:     map[string]string{"PGPASSWORD": "secret"},
+			},
+		})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "deploy")
+		require.NoError(t, err)
+		require.False(t, *pulled)
+
+		scriptPath := filepath.Join(cwd, "scripts", "predeploy.sh")
+		require.Equal(t, "docker", runArgs.Cmd)
+		require.Contains(t, runArgs.Args, "--rm")
+		require.Contains(t, runArgs.Args, cwd+":/workspace")
+		require.Contains(t, runArgs.Args, scriptPath+":/azd-hook/predeploy.sh:ro")
+		require.Contains(t, runArgs.Args, filepath.Join(cwd, "data")+":/data:ro")
+		require.Contains(t, runArgs.Args, "cache:/root/.cache")
+		require.Equal(t, []string{"postgres:16", "sh", "/azd-hook/predeploy.sh"}, runArgs.Args[len(runArgs.Args)-3:])
+
+		// Values are passed through the environment of docker, not the command line
+		require.Contains(t, runArgs.Args, "DATABASE_HOST")
+		require.Contains(t, runArgs.Args, "PGPASSWORD")
+		require.NotContains(t, strings.Join(runArgs.Args, " "), "secret")
+		require.Contains(t, runArgs.Env, "DATABASE_HOST=db.contoso.com")
+		require.Contains(t, runArgs.Env, "PGPASSWORD=secret")
+	})
+
+	t.Run("PullsMissingImage", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		pulled := mockImage(mockContext, false)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker run")
+		}).Respond(exec.NewRunResult(0, "", ""))
+
+		runner := newRunner(mockContext, &HookConfig{
+			Run:       "scripts/predeploy.sh",
+			Container: &HookContainerConfig{Image: "postgres:16"},
+		})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "deploy")
+		require.NoError(t, err)
+		require.True(t, *pulled)
+	})
+
+	t.Run("PropagatesExitCode", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockImage(mockContext, true)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker run")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(3, "", ""), errors.New("exit code: 3")
+		})
+
+		runner := newRunner(mockContext, &HookConfig{
+			Run:       "scripts/predeploy.sh",
+			Container: &HookContainerConfig{Image: "postgres:16"},
+		})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "deploy")
+		require.ErrorContains(t, err, "'predeploy' hook failed with exit code: '3'")
+	})
+
+	t.Run("DockerFailure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockImage(mockContext, true)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker run")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(dockerRunErrorExitCode, "", ""), errors.New("invalid mount")
+		})
+
+		runner := newRunner(mockContext, &HookConfig{
+			Run:       "scripts/predeploy.sh",
+			Container: &HookContainerConfig{Image: "postgres:16"},
+		})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "deploy")
+		require.ErrorContains(t, err, "running hook container of image 'postgres:16'")
+	})
+
+	t.Run("ImageRequired", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		runner := newRunner(mockContext, &HookConfig{
+			Run:       "scripts/predeploy.sh",
+			Container: &HookContainerConfig{},
+		})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "deploy")
+		require.ErrorIs(t, err, ErrContainerImageRequired)
+	})
+}
+
+func Test_containerScript_resolveVolume(t *testing.T) {
+	cwd := t.TempDir()
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	script := &containerScript{cwd: cwd}
+
+	tests := []struct {
+		volume   string
+		expected string
+	}{
+		{volume: "./data:/data", expected: filepath.Join(cwd, "data") + ":/data"},
+		{volume: "../shared:/shared:ro", expected: filepath.Join(cwd, "..", "shared") + ":/shared:ro"},
+		{volume: "~/.kube:/root/.kube", expected: filepath.Join(home, ".kube") + ":/root/.kube"},
+		{volume: "cache:/root/.cache", expected: "cache:/root/.cache"},
+		{volume: "/var/run/docker.sock:/var/run/docker.sock", expected: "/var/run/docker.sock:/var/run/docker.sock"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.volume, func(t *testing.T) {
+			resolved, err := script.resolveVolume(test.volume)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, resolved)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := script.resolveVolume("/data")
+		require.Error(t, err)
+	})
+}
@@ -5,10 +5,13 @@ package ext
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -21,6 +24,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/powershell"
 )
 
+// The delay between retries of a failed hook. Overridden in tests.
+var hookRetryDelay = 5 * time.Second
+
 // Hooks enable support to invoke integration scripts before & after commands
 // Scripts can be invoked at the project or service level or
 type HooksRunner struct {
@@ -93,6 +99,18 @@ func (h *HooksRunner) RunHooks(
 	hookType HookType,
 	options *tools.ExecOptions,
 	commands ...string,
+) error {
+	return h.RunHooksWithContext(ctx, hookType, options, nil, commands...)
+}
+
+// RunHooksWithContext invokes any registered script hooks for the specified hook type and command, exposing the context of
+// the operation to the scripts. The context is optional.
+func (h *HooksRunner) RunHooksWithContext(
+	ctx context.Context,
+	hookType HookType,
+	options *tools.ExecOptions,
+	hookContext *HookContext,
+	commands ...string,
 ) error {
 	hooks, err := h.hooksManager.GetByParams(h.hooks, hookType, commands...)
 	if err != nil {
@@ -104,7 +122,7 @@ func (h *HooksRunner) RunHooks(
 			return fmt.Errorf("reloading environment before running hook: %w", err)
 		}
 
-		err := h.execHook(ctx, hookConfig, options)
+		err := h.execHook(ctx, hookConfig, options, hookContext)
 		if err != nil {
 			return err
 		}
@@ -124,6 +142,10 @@ func (h *HooksRunner) GetScript(hookConfig *HookConfig, envVars []string) (tools
 		return nil, err
 	}
 
+	if hookConfig.Container != nil {
+		return newContainerScript(h.commandRunner, h.cwd, envVars, hookConfig.Shell, hookConfig.Container), nil
+	}
+
 	switch ShellType(strings.Split(string(hookConfig.Shell), " ")[0]) {
 	case ShellTypeBash:
 		return bash.NewBashScript(h.commandRunner, h.cwd, envVars), nil
@@ -137,12 +159,35 @@ func (h *HooksRunner) GetScript(hookConfig *HookConfig, envVars []string) (tools
 	}
 }
 
-func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, options *tools.ExecOptions) error {
+func (h *HooksRunner) execHook(
+	ctx context.Context,
+	hookConfig *HookConfig,
+	options *tools.ExecOptions,
+	hookContext *HookContext,
+) error {
 	if options == nil {
 		options = &tools.ExecOptions{}
 	}
 
+	if hookConfig.RunIf != "" {
+		run, err := evalCondition(hookConfig.RunIf, h.env.LookupEnv)
+		if err != nil {
+			return fmt.Errorf("'%s' hook runIf condition '%s' is invalid: %w", hookConfig.Name, hookConfig.RunIf, err)
+		}
+
+		if !run {
+			log.Printf("skipping '%s' hook since runIf condition '%s' is false", hookConfig.Name, hookConfig.RunIf)
+			h.console.Message(
+				ctx,
+				output.WithGrayFormat("Skipping '%s' hook since '%s' is false.", hookConfig.Name, hookConfig.RunIf),
+			)
+			return nil
+		}
+	}
+
 	hookEnv := environment.NewWithValues("temp", h.env.Dotenv())
+	// Allows values set with 'azd env set' from the hook to be attributed to it
+	hookEnv.DotenvSet(environment.HookNameEnvVarName, hookConfig.Name)
 	if len(hookConfig.Secrets) > 0 {
 		err := h.serviceLocator.Invoke(func(keyvaultService keyvault.KeyVaultService) error {
 			for key, value := range hookConfig.Secrets {
@@ -166,6 +211,23 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, opti
 		}
 	}
 
+	if hookContext != nil {
+		for key, value := range hookContext.Env {
+			hookEnv.DotenvSet(key, value)
+		}
+
+		// The file isn't mounted in the containers of container hooks, which only get the environment variables
+		if hookContext.Data != nil && hookConfig.Container == nil {
+			contextPath, err := writeHookContext(hookContext.Data)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(contextPath)
+
+			hookEnv.DotenvSet(HookContextEnvVarName, contextPath)
+		}
+	}
+
 	script, err := h.GetScript(hookConfig, hookEnv.Environ())
 	if err != nil {
 		return err
@@ -192,17 +254,31 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, opti
 	}
 	options.UserPwsh = string(hookConfig.Shell)
 
-	log.Printf("Executing script '%s'\n", hookConfig.path)
-	res, err := script.Execute(ctx, hookConfig.path, *options)
-	if err != nil {
-		execErr := fmt.Errorf(
-			"'%s' hook failed with exit code: '%d', Path: '%s'. : %w",
-			hookConfig.Name,
-			res.ExitCode,
-			hookConfig.path,
-			err,
-		)
+	var execErr error
+	for attempt := 0; attempt <= hookConfig.Retries; attempt++ {
+		if attempt > 0 {
+			h.console.Message(
+				ctx,
+				output.WithWarningFormat(
+					"Retrying '%s' hook (attempt %d of %d)...", hookConfig.Name, attempt+1, hookConfig.Retries+1),
+			)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(hookRetryDelay):
+			}
+		}
+
+		execErr = h.execScript(ctx, hookConfig, script, *options)
+		if execErr == nil {
+			break
+		}
+
+		log.Println(execErr.Error())
+	}
 
+	if execErr != nil {
 		// If an error occurred log the failure but continue
 		if hookConfig.ContinueOnError {
 			h.console.Message(ctx, output.WithBold("%s", output.WithWarningFormat("WARNING: %s", execErr.Error())))
@@ -210,7 +286,6 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, opti
 				ctx,
 				output.WithWarningFormat("Execution will continue since ContinueOnError has been set to true."),
 			)
-			log.Println(execErr.Error())
 		} else {
 			return execErr
 		}
@@ -224,3 +299,61 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, opti
 
 	return nil
 }
+
+// writeHookContext writes the context of a hook to a temporary JSON file, and returns the path of the file
+func writeHookContext(data any) (string, error) {
+	contextJson, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshalling hook context: %w", err)
+	}
+
+	file, err := os.CreateTemp("", "azd-hook-context-*.json")
+	if err != nil {
+		return "", fmt.Errorf("creating hook context file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(contextJson); err != nil {
+		return "", fmt.Errorf("writing hook context file: %w", err)
+	}
+
+	return file.Name(), nil
+}
+
+// execScript runs a single attempt of the hook script, bounded by the configured timeout.
+func (h *HooksRunner) execScript(
+	ctx context.Context,
+	hookConfig *HookConfig,
+	script tools.Script,
+	options tools.ExecOptions,
+) error {
+	if hookConfig.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hookConfig.timeout)
+		defer cancel()
+	}
+
+	log.Printf("Executing script '%s'\n", hookConfig.path)
+	res, err := script.Execute(ctx, hookConfig.path, options)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf(
+			"'%s' hook timed out after %s, Path: '%s'. : %w",
+			hookConfig.Name,
+			hookConfig.timeout,
+			hookConfig.path,
+			err,
+		)
+	}
+
+	return fmt.Errorf(
+		"'%s' hook failed with exit code: '%d', Path: '%s'. : %w",
+		hookConfig.Name,
+		res.ExitCode,
+		hookConfig.path,
+		err,
+	)
+}
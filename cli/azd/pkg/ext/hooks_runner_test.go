@@ -5,10 +5,12 @@ package ext
 
 import (
 	"context"
+	"errors"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -75,7 +77,7 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPreHook = true
 			require.Equal(t, "scripts/precommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, append(env.Environ(), "AZD_HOOK_NAME=precommand"), args.Env)
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
@@ -110,7 +112,7 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/postcommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, append(env.Environ(), "AZD_HOOK_NAME=postcommand"), args.Env)
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
@@ -145,7 +147,7 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/preinteractive.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, append(env.Environ(), "AZD_HOOK_NAME=preinteractive"), args.Env)
 			require.Equal(t, true, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
@@ -258,6 +260,51 @@ func Test_Hooks_Execute(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("HookContext", func(t *testing.T) {
+		ranPostHook := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "postcommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ranPostHook = true
+			require.Contains(t, args.Env, "AZD_SERVICE_NAME=api")
+
+			var contextPath string
+			for _, envVar := range args.Env {
+				if value, has := strings.CutPrefix(envVar, HookContextEnvVarName+"="); has {
+					contextPath = value
+				}
+			}
+
+			contents, err := os.ReadFile(contextPath)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"service": "api"}`, string(contents))
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(
+			hooksManager,
+			mockContext.CommandRunner,
+			envManager,
+			mockContext.Console,
+			cwd,
+			hooksMap,
+			env,
+			mockContext.Container,
+		)
+		hookContext := &HookContext{
+			Env:  map[string]string{"AZD_SERVICE_NAME": "api"},
+			Data: map[string]string{"service": "api"},
+		}
+		err := runner.RunHooksWithContext(*mockContext.Context, HookTypePost, nil, hookContext, "command")
+
+		require.True(t, ranPostHook)
+		require.NoError(t, err)
+	})
 }
 
 func Test_Hooks_GetScript(t *testing.T) {
@@ -474,6 +521,16 @@ func Test_GetScript_Validation(t *testing.T) {
 			expectedError: ErrUnsupportedScriptType,
 			createFile:    true,
 		},
+		{
+			name: "Negative Retries",
+			config: &HookConfig{
+				Name:    "test6",
+				Shell:   ShellTypeBash,
+				Run:     "echo 'Hello'",
+				Retries: -1,
+			},
+			expectedError: ErrInvalidRetries,
+		},
 		{
 			name: "Valid External Script",
 			config: &HookConfig{
@@ -514,3 +571,162 @@ func Test_GetScript_Validation(t *testing.T) {
 		})
 	}
 }
+
+func Test_Hooks_Policies(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	originalRetryDelay := hookRetryDelay
+	hookRetryDelay = time.Millisecond
+	t.Cleanup(func() { hookRetryDelay = originalRetryDelay })
+
+	env := environment.NewWithValues(
+		"test",
+		map[string]string{
+			"SEED_DATA": "true",
+			"STAGE":     "dev",
+		},
+	)
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Reload", mock.Anything, env).Return(nil)
+
+	newRunner := func(mockContext *mocks.MockContext, hookConfig *HookConfig) *HooksRunner {
+		hooksMap := map[string][]*HookConfig{"precommand": {hookConfig}}
+		ensureScriptsExist(t, hooksMap)
+
+		return NewHooksRunner(
+			NewHooksManager(cwd),
+			mockContext.CommandRunner,
+			envManager,
+			mockContext.Console,
+			cwd,
+			hooksMap,
+			env,
+			mockContext.Container,
+		)
+	}
+
+	t.Run("RetriesUntilSuccess", func(t *testing.T) {
+		attempts := 0
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			attempts++
+			if attempts < 3 {
+				return exec.NewRunResult(1, "", ""), errors.New("seeding failed")
+			}
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		runner := newRunner(mockContext, &HookConfig{Shell: ShellTypeBash, Run: "scripts/precommand.sh", Retries: 2})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("FailsAfterRetries", func(t *testing.T) {
+		attempts := 0
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			attempts++
+			return exec.NewRunResult(1, "", ""), errors.New("seeding failed")
+		})
+
+		runner := newRunner(mockContext, &HookConfig{Shell: ShellTypeBash, Run: "scripts/precommand.sh", Retries: 1})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+		require.ErrorContains(t, err, "'precommand' hook failed with exit code: '1'")
+		require.Equal(t, 2, attempts)
+	})
+
+	t.Run("ContinueOnErrorAfterRetries", func(t *testing.T) {
+		attempts := 0
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			attempts++
+			return exec.NewRunResult(1, "", ""), errors.New("seeding failed")
+		})
+
+		runner := newRunner(mockContext, &HookConfig{
+			Shell:           ShellTypeBash,
+			Run:             "scripts/precommand.sh",
+			Retries:         1,
+			ContinueOnError: true,
+		})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			time.Sleep(50 * time.Millisecond)
+			return exec.NewRunResult(-1, "", ""), errors.New("signal: killed")
+		})
+
+		runner := newRunner(mockContext, &HookConfig{Shell: ShellTypeBash, Run: "scripts/precommand.sh", Timeout: "1ms"})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+		require.ErrorContains(t, err, "'precommand' hook timed out after 1ms")
+	})
+
+	t.Run("InvalidTimeout", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		runner := newRunner(mockContext, &HookConfig{Shell: ShellTypeBash, Run: "scripts/precommand.sh", Timeout: "soon"})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+		require.ErrorContains(t, err, "timeout 'soon' is not a valid duration")
+	})
+
+	runIfTests := []struct {
+		name   string
+		runIf  string
+		expect bool
+	}{
+		{name: "RunIfTrue", runIf: `SEED_DATA && STAGE == "dev"`, expect: true},
+		{name: "RunIfFalse", runIf: `STAGE != 'dev' || MISSING`, expect: false},
+	}
+
+	for _, test := range runIfTests {
+		t.Run(test.name, func(t *testing.T) {
+			ran := false
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "precommand.sh")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ran = true
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			runner := newRunner(mockContext, &HookConfig{
+				Shell: ShellTypeBash,
+				Run:   "scripts/precommand.sh",
+				RunIf: test.runIf,
+			})
+			err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+			require.NoError(t, err)
+			require.Equal(t, test.expect, ran)
+		})
+	}
+
+	t.Run("InvalidRunIf", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		runner := newRunner(mockContext, &HookConfig{Shell: ShellTypeBash, Run: "scripts/precommand.sh", RunIf: "STAGE =="})
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
+
+		require.ErrorContains(t, err, "runIf condition 'STAGE ==' is invalid")
+	})
+}
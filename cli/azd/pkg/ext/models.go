@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 )
@@ -39,13 +40,28 @@ var (
 	ErrScriptTypeUnknown error = errors.New(
 		"unable to determine script type. Ensure 'Shell' parameter is set in configuration options",
 	)
-	ErrRunRequired           error = errors.New("run is always required")
-	ErrUnsupportedScriptType error = errors.New("script type is not valid. Only '.sh' and '.ps1' are supported")
+	ErrRunRequired            error = errors.New("run is always required")
+	ErrUnsupportedScriptType  error = errors.New("script type is not valid. Only '.sh' and '.ps1' are supported")
+	ErrInvalidRetries         error = errors.New("retries must not be a negative number")
+	ErrContainerImageRequired error = errors.New("container image is required when running the hook in a container")
 )
 
 // Generic action function that may return an error
 type InvokeFn func() error
 
+// HookContextEnvVarName is set to the path of a JSON file with the context of the operation a hook runs for, in the
+// environment of hook scripts running on the host.
+const HookContextEnvVarName = "AZD_HOOK_CONTEXT"
+
+// HookContext is structured information about the operation a hook runs for, ex) the package and the target resource of
+// a service deployment. It saves hooks from re-deriving it, to sign images or register releases for example.
+type HookContext struct {
+	// Environment variables set in the environment of the hook script
+	Env map[string]string
+	// The value serialized in the JSON file referenced by AZD_HOOK_CONTEXT
+	Data any
+}
+
 // Azd hook configuration
 type HookConfig struct {
 	// The location of the script hook (file path or inline)
@@ -58,6 +74,8 @@ type HookConfig struct {
 	cwd string
 	// When location is `inline` a script must be defined inline
 	script string
+	// The parsed value of Timeout
+	timeout time.Duration
 
 	// Internal name of the hook running for a given command
 	Name string `yaml:",omitempty"`
@@ -67,6 +85,12 @@ type HookConfig struct {
 	Run string `yaml:"run,omitempty"`
 	// When set to true will not halt command execution even when a script error occurs.
 	ContinueOnError bool `yaml:"continueOnError,omitempty"`
+	// The maximum duration of each execution of the script (ex: 30s, 5m). By default the script doesn't time out.
+	Timeout string `yaml:"timeout,omitempty"`
+	// The number of times the script is retried after a failure.
+	Retries int `yaml:"retries,omitempty"`
+	// A condition on environment values which must be true for the script to run (ex: SEED_DATA == "true").
+	RunIf string `yaml:"runIf,omitempty"`
 	// When set to true will bind the stdin, stdout & stderr to the running console
 	Interactive bool `yaml:"interactive,omitempty"`
 	// When running on windows use this override config
@@ -76,6 +100,20 @@ type HookConfig struct {
 	// Environment variables in this list are added to the hook script and if the value is a akvs:// reference
 	// it will be resolved to the secret value
 	Secrets map[string]string `yaml:"secrets,omitempty"`
+	// When set the script runs inside a container of the configured image instead of on the host
+	Container *HookContainerConfig `yaml:"container,omitempty"`
+}
+
+// HookContainerConfig configures the container a hook script runs in. The project or service directory is mounted as
+// the working directory of the container.
+type HookContainerConfig struct {
+	// The image of the container (ex: postgres:16), pulled when it's not available locally
+	Image string `yaml:"image"`
+	// Additional volumes in the docker '<source>:<target>[:<options>]' format. Relative source paths are relative to the
+	// project or service directory.
+	Volumes []string `yaml:"volumes,omitempty"`
+	// Environment variables set in the container in addition to the azd environment values
+	Env map[string]string `yaml:"env,omitempty"`
 }
 
 // Validates and normalizes the hook configuration
@@ -88,6 +126,30 @@ func (hc *HookConfig) validate() error {
 		return ErrRunRequired
 	}
 
+	if hc.Timeout != "" {
+		timeout, err := time.ParseDuration(hc.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("timeout '%s' is not a valid duration. Use a value like '30s' or '5m'", hc.Timeout)
+		}
+
+		hc.timeout = timeout
+	}
+
+	if hc.Retries < 0 {
+		return ErrInvalidRetries
+	}
+
+	if hc.Container != nil && hc.Container.Image == "" {
+		return ErrContainerImageRequired
+	}
+
+	if hc.RunIf != "" {
+		// Validate the syntax of the condition, it's evaluated against the environment when the hook runs
+		if _, err := evalCondition(hc.RunIf, func(string) (string, bool) { return "", false }); err != nil {
+			return fmt.Errorf("runIf condition '%s' is invalid: %w", hc.RunIf, err)
+		}
+	}
+
 	relativeCheckPath := strings.ReplaceAll(hc.Run, "/", string(os.PathSeparator))
 	fullCheckPath := relativeCheckPath
 	if hc.cwd != "" {
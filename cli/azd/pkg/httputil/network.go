@@ -0,0 +1,210 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package httputil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// The user config paths of the network settings, set with `azd config set`
+const (
+	// The URL of the HTTP(S) proxy used by azd, which takes precedence over the HTTPS_PROXY and HTTP_PROXY environment
+	// variables. Credentials for proxies using basic authentication can be set in the URL, proxies requiring NTLM or
+	// Kerberos authentication aren't supported.
+	ProxyConfigPath = "network.proxy"
+	// The path to a PEM file of CA certificates trusted by azd, in addition to the system ones.
+	CaBundleConfigPath = "network.caBundle"
+)
+
+// NetworkConfig is the network configuration of the HTTP clients of azd.
+type NetworkConfig struct {
+	// The proxy URL, when empty the proxy is read from the environment
+	Proxy string
+	// The path to a PEM file of additional trusted CA certificates
+	CaBundle string
+}
+
+// NewNetworkConfig reads the network configuration from the user config.
+func NewNetworkConfig(cfg config.Config) NetworkConfig {
+	proxy, _ := cfg.GetString(ProxyConfigPath)
+	caBundle, _ := cfg.GetString(CaBundleConfigPath)
+
+	return NetworkConfig{
+		Proxy:    strings.TrimSpace(proxy),
+		CaBundle: strings.TrimSpace(caBundle),
+	}
+}
+
+// ConfigureTransport applies the network configuration to transport.
+func ConfigureTransport(transport *http.Transport, networkConfig NetworkConfig) error {
+	if networkConfig.Proxy != "" {
+		proxyUrl, err := parseProxyUrl(networkConfig.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid %s '%s': %w", ProxyConfigPath, networkConfig.Proxy, err)
+		}
+
+		noProxy := os.Getenv("NO_PROXY")
+		if noProxy == "" {
+			noProxy = os.Getenv("no_proxy")
+		}
+
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if bypassProxy(req.URL.Hostname(), noProxy) {
+				return nil, nil
+			}
+
+			return proxyUrl, nil
+		}
+	}
+
+	if networkConfig.CaBundle != "" {
+		pemBytes, err := os.ReadFile(networkConfig.CaBundle)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", CaBundleConfigPath, err)
+		}
+
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+
+		if !rootCAs.AppendCertsFromPEM(pemBytes) {
+			return fmt.Errorf(
+				"%s '%s' doesn't contain any PEM encoded certificate", CaBundleConfigPath, networkConfig.CaBundle)
+		}
+
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	return nil
+}
+
+func parseProxyUrl(proxy string) (*url.URL, error) {
+	// Like HTTPS_PROXY, proxies without a scheme are HTTP proxies
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	switch proxyUrl.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf(
+			"unsupported proxy scheme '%s', supported schemes are http, https and socks5", proxyUrl.Scheme)
+	}
+
+	if proxyUrl.Host == "" {
+		return nil, fmt.Errorf("missing proxy host")
+	}
+
+	return proxyUrl, nil
+}
+
+// bypassProxy reports whether host matches the comma separated list of hosts, domains and IP addresses of NO_PROXY.
+func bypassProxy(host string, noProxy string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return true
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if entry == "*" {
+			return true
+		}
+
+		// Ports in NO_PROXY entries are ignored
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip := net.ParseIP(host); ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConfiguredTransport is an http.RoundTripper which applies the network configuration of the user config the first time
+// it's used, so that reading the config is deferred until azd sends a request, and errors in the config are reported
+// by the requests.
+type ConfiguredTransport struct {
+	base       *http.Transport
+	loadConfig func() (config.Config, error)
+
+	once      sync.Once
+	transport *http.Transport
+	err       error
+}
+
+// NewConfiguredTransport creates a transport which applies the network configuration loaded by loadConfig to a clone of
+// base.
+func NewConfiguredTransport(base *http.Transport, loadConfig func() (config.Config, error)) *ConfiguredTransport {
+	return &ConfiguredTransport{
+		base:       base,
+		loadConfig: loadConfig,
+	}
+}
+
+func (t *ConfiguredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		cfg, err := t.loadConfig()
+		if err != nil {
+			t.err = fmt.Errorf("loading network configuration: %w", err)
+			return
+		}
+
+		transport := t.base.Clone()
+		if err := ConfigureTransport(transport, NewNetworkConfig(cfg)); err != nil {
+			t.err = err
+			return
+		}
+
+		t.transport = transport
+	})
+
+	if t.err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, t.err
+	}
+
+	return t.transport.RoundTrip(req)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package httputil
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureTransport(t *testing.T) {
+	t.Run("Proxy", func(t *testing.T) {
+		t.Setenv("NO_PROXY", ".internal.contoso.com,10.0.0.0/8")
+
+		transport := &http.Transport{}
+		err := ConfigureTransport(transport, NetworkConfig{Proxy: "proxy.contoso.com:8080"})
+		require.NoError(t, err)
+
+		tests := map[string]string{
+			"https://management.azure.com":      "http://proxy.contoso.com:8080",
+			"https://api.internal.contoso.com/": "",
+			"http://10.1.2.3/":                  "",
+			"http://localhost:8080/":            "",
+		}
+
+		for target, expected := range tests {
+			req, err := http.NewRequest(http.MethodGet, target, nil)
+			require.NoError(t, err)
+
+			proxyUrl, err := transport.Proxy(req)
+			require.NoError(t, err)
+			if expected == "" {
+				require.Nil(t, proxyUrl, target)
+			} else {
+				require.Equal(t, expected, proxyUrl.String(), target)
+			}
+		}
+	})
+
+	t.Run("InvalidProxy", func(t *testing.T) {
+		err := ConfigureTransport(&http.Transport{}, NetworkConfig{Proxy: "ftp://proxy.contoso.com"})
+		require.ErrorContains(t, err, "unsupported proxy scheme")
+	})
+
+	t.Run("InvalidCaBundle", func(t *testing.T) {
+		caBundle := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caBundle, []byte("not a certificate"), 0600))
+
+		err := ConfigureTransport(&http.Transport{}, NetworkConfig{CaBundle: caBundle})
+		require.ErrorContains(t, err, "doesn't contain any PEM encoded certificate")
+	})
+}
+
+func TestConfiguredTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caBundle, pemBytes, 0600))
+
+	t.Run("UntrustedWithoutCaBundle", func(t *testing.T) {
+		client := &http.Client{
+			Transport: NewConfiguredTransport(&http.Transport{}, func() (config.Config, error) {
+				return config.NewEmptyConfig(), nil
+			}),
+		}
+
+		_, err := client.Get(server.URL)
+		require.Error(t, err)
+	})
+
+	t.Run("TrustedWithCaBundle", func(t *testing.T) {
+		client := &http.Client{
+			Transport: NewConfiguredTransport(&http.Transport{}, func() (config.Config, error) {
+				cfg := config.NewEmptyConfig()
+				if err := cfg.Set(CaBundleConfigPath, caBundle); err != nil {
+					return nil, err
+				}
+
+				return cfg, nil
+			}),
+		}
+
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
@@ -42,6 +42,13 @@ func (dm *DeploymentManager) GenerateDeploymentName(baseName string) string {
 	return dm.deploymentService.GenerateDeploymentName(baseName)
 }
 
+// HasDeploymentHistory returns whether each deployment is kept in the deployment history of its scope, which ARM limits
+// to 800 deployments. Deployment stacks are updated in place instead.
+func (dm *DeploymentManager) HasDeploymentHistory() bool {
+	_, has := dm.deploymentService.(azapi.DeploymentHistory)
+	return has
+}
+
 func (dm *DeploymentManager) CalculateTemplateHash(
 	ctx context.Context,
 	subscriptionId string,
@@ -12,6 +12,7 @@ import (
 	"log"
 	"maps"
 	"math"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -20,9 +21,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cognitiveservices/armcognitiveservices"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -35,6 +39,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/journal"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
@@ -42,9 +47,29 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/blang/semver/v4"
 	"github.com/drone/envsubst"
 )
 
+var (
+	deploymentPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "DEPLOYMENT",
+		Description: "The name of the deployment to use when several match the environment.",
+		Commands:    []string{"provision", "up", "env refresh", "show", "down"},
+	})
+	destroyPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "DESTROY_CONFIRM",
+		Description: "Whether to delete the resources of the environment: true or false.",
+		Commands:    []string{"down"},
+	})
+	purgePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "PURGE_CONFIRM",
+		Description: "Whether to permanently delete the soft deleted resources: true or false.",
+		Commands:    []string{"down"},
+	})
+)
+
 const (
 	defaultModule = "main"
 	defaultPath   = "infra"
@@ -74,9 +99,11 @@ type BicepProvider struct {
 	// compileBicepResult is cached to avoid recompiling the same bicep file multiple times in the same azd run.
 	compileBicepMemoryCache *compileBicepResult
 	keyvaultService         keyvault.KeyVaultService
+	parameterSecrets        *provisioning.ParameterSecrets
 	portalUrlBase           string
 	subscriptionManager     *account.SubscriptionsManager
 	azureClient             *azapi.AzureClient
+	gitCli                  *git.Cli
 }
 
 // Name gets the name of the infra provider
@@ -106,12 +133,75 @@ func (p *BicepProvider) Initialize(ctx context.Context, projectPath string, opti
 	}
 	p.ignoreDeploymentState = options.IgnoreDeploymentState
 
+	if err := p.ensureBicepVersion(ctx); err != nil {
+		return err
+	}
+
+	if p.options.DeploymentName != nil {
+		if err := p.options.DeploymentName.Validate(); err != nil {
+			return &internal.ErrorWithSuggestion{
+				Err:        err,
+				Suggestion: "Suggestion: fix 'infra.deploymentName' in azure.yaml.",
+			}
+		}
+	}
+
 	p.console.ShowSpinner(ctx, "Initialize bicep provider", input.Step)
 	err := p.EnsureEnv(ctx)
 	p.console.StopSpinner(ctx, "", input.Step)
 	return err
 }
 
+// ensureBicepVersion switches to the version of bicep pinned by the project, and warns when the templates require a
+// newer one.
+func (p *BicepProvider) ensureBicepVersion(ctx context.Context) error {
+	if p.options.BicepVersion == "" {
+		return nil
+	}
+
+	pinned, err := semver.Parse(strings.TrimPrefix(p.options.BicepVersion, "v"))
+	if err != nil {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("invalid bicep version '%s' in azure.yaml: %w", p.options.BicepVersion, err),
+			Suggestion: "Suggestion: set 'infra.bicepVersion' to a released version of the Bicep CLI, " +
+				"ex) 0.30.3, or remove it to use the version managed by azd.",
+		}
+	}
+
+	pinnedCli, err := p.bicepCli.WithVersion(ctx, pinned)
+	if err != nil {
+		return err
+	}
+	p.bicepCli = pinnedCli
+
+	infraRoot := p.options.Path
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(p.projectPath, infraRoot)
+	}
+
+	required, err := bicep.FindRequiredVersion(infraRoot)
+	if err != nil {
+		log.Printf("failed finding the bicep version required by the templates: %v", err)
+		return nil
+	}
+
+	if required != nil && pinned.LT(required.Version) {
+		relPath, err := filepath.Rel(p.projectPath, required.File)
+		if err != nil {
+			relPath = required.File
+		}
+
+		p.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"The pinned Bicep CLI version %s is older than the version %s required by %s. "+
+					"Update 'infra.bicepVersion' in azure.yaml.",
+				pinned, required.Version, relPath),
+		})
+	}
+
+	return nil
+}
+
 var ErrEnsureEnvPreReqBicepCompileFailed = errors.New("")
 
 // EnsureEnv ensures that the environment is in a provision-ready state with required values set, prompting the user if
@@ -155,21 +245,12 @@ func (p *BicepProvider) EnsureEnv(ctx context.Context) error {
 	}
 
 	if scope == azure.DeploymentScopeResourceGroup {
-		if p.env.Getenv(environment.ResourceGroupEnvVarName) == "" {
-			// Prompt Resource Group supports creating a new resource group
-			// And prompts for a location as part of creating a new resource group
-			rgName, err := p.prompters.PromptResourceGroup(ctx, prompt.PromptResourceOptions{})
-			if err != nil {
-				return err
-			}
-
-			p.env.DotenvSet(environment.ResourceGroupEnvVarName, rgName)
-			if err := p.envManager.Save(ctx, p.env); err != nil {
-				return fmt.Errorf("saving resource group name: %w", err)
-			}
-		}
+		return provisioning.EnsureResourceGroup(
+			ctx, p.envManager, p.env, p.prompters, p.resourceService, p.options.ResourceGroup)
 	}
 
+	provisioning.WarnIgnoredResourceGroup(
+		ctx, p.console, p.options.ResourceGroup, fmt.Sprintf("the template is deployed to the %s", scope))
 	return nil
 }
 
@@ -284,6 +365,7 @@ func (p *BicepProvider) State(ctx context.Context, options *provisioning.StateOp
 		p.console.Message(ctx, output.WithWarningFormat("WARNING: Multiple matching deployments were found\n"))
 
 		promptConfig := input.ConsoleOptions{
+			Id:      deploymentPromptId,
 			Message: "Select a deployment to continue:",
 			Options: deploymentOptions,
 		}
@@ -393,7 +475,7 @@ func (p *BicepProvider) plan(ctx context.Context) (*deploymentDetails, error) {
 		return nil, err
 	}
 
-	target, err := p.deploymentFromScopeType(deploymentScope)
+	target, err := p.deploymentFromScopeType(deploymentScope, p.deploymentName(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -404,9 +486,10 @@ func (p *BicepProvider) plan(ctx context.Context) (*deploymentDetails, error) {
 	}, nil
 }
 
-func (p *BicepProvider) deploymentFromScopeType(deploymentScopeType azure.DeploymentScope) (infra.Deployment, error) {
-	deploymentName := p.deploymentManager.GenerateDeploymentName(p.env.Name())
-
+func (p *BicepProvider) deploymentFromScopeType(
+	deploymentScopeType azure.DeploymentScope,
+	deploymentName string,
+) (infra.Deployment, error) {
 	if deploymentScopeType == azure.DeploymentScopeSubscription {
 		scope := p.deploymentManager.SubscriptionScope(p.env.GetSubscriptionId(), p.env.GetLocation())
 		return infra.NewSubscriptionDeployment(
@@ -543,8 +626,19 @@ func logDS(msg string, v ...any) {
 	log.Printf("%s : %s", "deployment-state: ", fmt.Sprintf(msg, v...))
 }
 
+// withAuxiliaryTenants returns a context authenticating the deployments to the auxiliary tenants of the project, so the
+// templates can reference resources of other tenants, ex) a shared registry in the tenant of a partner.
+func (p *BicepProvider) withAuxiliaryTenants(ctx context.Context) context.Context {
+	if len(p.options.AuxiliaryTenants) == 0 {
+		return ctx
+	}
+
+	return account.WithAuxiliaryTenants(ctx, p.options.AuxiliaryTenants)
+}
+
 // Provisioning the infrastructure within the specified template
 func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult, error) {
+	ctx = p.withAuxiliaryTenants(ctx)
 	if p.ignoreDeploymentState {
 		logDS("Azure Deployment State is disabled by --no-state arg.")
 	}
@@ -559,6 +653,13 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		return nil, err
 	}
 
+	// the snapshot is only used to compare the next provisions with this one, failing to create it doesn't fail the
+	// deployment
+	snapshot, err := p.newSnapshot(bicepDeploymentData.CompiledBicep)
+	if err != nil {
+		log.Printf("creating provisioning snapshot: %v", err)
+	}
+
 	// parameters hash is required for doing deployment state validation check but also to set the hash
 	// after a successful deployment.
 	currentParamsHash, parametersHashErr := parametersHash(
@@ -579,6 +680,7 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 			return &provisioning.DeployResult{
 				Deployment:    deployment,
 				SkippedReason: provisioning.DeploymentStateSkipped,
+				Snapshot:      snapshot,
 			}, nil
 		}
 		logDS("%s", err.Error())
@@ -590,12 +692,29 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 	if parametersHashErr == nil {
 		deploymentTags[azure.TagKeyAzdDeploymentStateParamHashName] = to.Ptr(currentParamsHash)
 	}
+	// Tags used to describe the deployment in 'azd provision history'
+	if principalId, err := p.curPrincipal.CurrentPrincipalId(ctx); err == nil {
+		deploymentTags[azure.TagKeyAzdInitiator] = to.Ptr(principalId)
+	}
+	if gitSha := provisioning.SourceVersion(); gitSha != "" {
+		deploymentTags[azure.TagKeyAzdGitSha] = to.Ptr(gitSha)
+	}
 
 	optionsMap, err := convert.ToMap(p.options)
 	if err != nil {
 		return nil, err
 	}
 
+	if p.options.PolicyCheck {
+		if err := p.checkPolicies(ctx, bicepDeploymentData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.pruneDeploymentHistory(ctx, bicepDeploymentData.Target); err != nil {
+		return nil, err
+	}
+
 	err = p.validatePreflight(
 		ctx,
 		bicepDeploymentData.Target,
@@ -609,8 +728,15 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 	}
 
 	cancelProgress := make(chan bool)
-	defer func() { cancelProgress <- true }()
+	progressDone := make(chan struct{})
+	defer func() {
+		cancelProgress <- true
+		// Wait for the progress display to be closed before writing the results of the deployment
+		<-progressDone
+	}()
 	go func() {
+		defer close(progressDone)
+
 		// Disable reporting progress if needed
 		if use, err := strconv.ParseBool(os.Getenv("AZD_DEBUG_PROVISION_PROGRESS_DISABLE")); err == nil && use {
 			log.Println("Disabling progress reporting since AZD_DEBUG_PROVISION_PROGRESS_DISABLE was set")
@@ -620,16 +746,14 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 
 		// Report incremental progress
 		progressDisplay := p.deploymentManager.ProgressDisplay(bicepDeploymentData.Target)
-		// Make initial delay shorter to be more responsive in displaying initial progress
-		initialDelay := 3 * time.Second
-		regularDelay := 10 * time.Second
-		timer := time.NewTimer(initialDelay)
+		timer := time.NewTimer(infra.ProgressInitialPollDelay)
 		queryStartTime := time.Now()
 
 		for {
 			select {
 			case <-cancelProgress:
 				timer.Stop()
+				progressDisplay.Close(ctx)
 				return
 			case <-timer.C:
 				if err := progressDisplay.ReportProgress(ctx, &queryStartTime); err != nil {
@@ -637,11 +761,13 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 					log.Printf("error while reporting progress: %v", err)
 				}
 
-				timer.Reset(regularDelay)
+				timer.Reset(progressDisplay.NextPollDelay())
 			}
 		}
 	}()
 
+	p.recordResourceGroups(ctx, bicepDeploymentData.Target)
+
 	// Start the deployment
 	p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
 
@@ -662,13 +788,186 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*provisioning.DeployResult,
 		azapi.CreateDeploymentOutput(deployResult.Outputs),
 	)
 
+	if p.options.TemplateSpec != nil {
+		p.publishTemplateSpec(ctx, *p.options.TemplateSpec, bicepDeploymentData.CompiledBicep)
+	}
+
 	return &provisioning.DeployResult{
 		Deployment: deployment,
+		Snapshot:   snapshot,
 	}, nil
 }
 
+// publishTemplateSpec publishes the compiled template and the parameters of a successful provision as a version of a
+// template spec. The resources are already provisioned at this point, so failing to publish is reported as a warning.
+func (p *BicepProvider) publishTemplateSpec(
+	ctx context.Context,
+	options provisioning.TemplateSpecOptions,
+	compiled *compileBicepResult,
+) {
+	resourceGroup := options.ResourceGroup
+	if resourceGroup == "" {
+		resourceGroup = p.env.Getenv(environment.ResourceGroupEnvVarName)
+	}
+
+	name := options.Name
+	if name == "" {
+		name = p.options.Module
+	}
+
+	location := p.env.GetLocation()
+	if resourceGroup == "" || location == "" {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The template spec wasn't published, set 'infra.templateSpec.resourceGroup' in azure.yaml and "+
+				"the location of the environment."))
+		return
+	}
+
+	gitSha := provisioning.SourceVersion()
+	versionName := provisioning.TemplateSpecVersionName(p.env.Name(), gitSha, time.Now())
+
+	metadata := map[string]any{
+		"environment": p.env.Name(),
+		"parameters":  templateSpecParameters(compiled.Template.Parameters, compiled.Parameters),
+	}
+	if gitSha != "" {
+		metadata["gitSha"] = gitSha
+	}
+
+	p.console.ShowSpinner(ctx, "Publishing template spec", input.Step)
+	versionId, err := p.azapi.CreateOrUpdateTemplateSpecVersion(
+		ctx,
+		p.env.GetSubscriptionId(),
+		resourceGroup,
+		name,
+		versionName,
+		location,
+		azapi.TemplateSpecVersion{
+			Description:  fmt.Sprintf("Provisioned environment '%s'", p.env.Name()),
+			MainTemplate: compiled.RawArmTemplate,
+			Metadata:     metadata,
+		},
+	)
+	p.console.StopSpinner(ctx, "", input.Step)
+	if err != nil {
+		log.Printf("publishing template spec: %v", err)
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The template spec '%s' couldn't be published: %v", name, err))
+		return
+	}
+
+	p.console.MessageUxItem(ctx, &ux.DoneMessage{
+		Message: fmt.Sprintf("Published template spec version %s", output.WithHighLightFormat(versionId)),
+	})
+}
+
+// templateSpecParameters returns the parameters of the deployment stored with a template spec version. The values of
+// the secure parameters aren't stored, and the Key Vault references are kept as they are.
+func templateSpecParameters(
+	definitions azure.ArmTemplateParameterDefinitions, parameters azure.ArmParameters) azure.ArmParameters {
+	result := azure.ArmParameters{}
+	for name, parameter := range parameters {
+		if definition, has := definitions[name]; has && definition.Secure() && parameter.KeyVaultReference == nil {
+			continue
+		}
+
+		result[name] = parameter
+	}
+
+	return result
+}
+
+// checkPolicies evaluates the resources created or updated by the deployment, from its what-if result, against the
+// Azure Policies assigned to their scope, and fails when a resource would be denied. Resources which can't be evaluated
+// are reported as a warning, since the deployment itself reports the denials anyway.
+func (p *BicepProvider) checkPolicies(ctx context.Context, deploymentData *deploymentDetails) error {
+	p.console.ShowSpinner(ctx, "Checking Azure Policies", input.Step)
+	whatIf, err := deploymentData.Target.DeployPreview(
+		ctx,
+		deploymentData.CompiledBicep.RawArmTemplate,
+		deploymentData.CompiledBicep.Parameters,
+	)
+	if err == nil && whatIf.Error != nil {
+		err = fmt.Errorf("what-if error code: %s", convert.ToValueWithDefault(whatIf.Error.Code, ""))
+	}
+	if err != nil {
+		p.console.StopSpinner(ctx, "", input.Step)
+		log.Printf("running what-if to check policies: %v", err)
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The resources couldn't be checked against Azure Policy before provisioning."))
+		return nil
+	}
+
+	violations := []provisioning.PolicyViolation{}
+	unchecked := 0
+	for _, change := range whatIf.Properties.Changes {
+		if change.ChangeType == nil || change.ResourceID == nil {
+			continue
+		}
+
+		switch *change.ChangeType {
+		case armresources.ChangeTypeCreate, armresources.ChangeTypeModify, armresources.ChangeTypeDeploy:
+		default:
+			continue
+		}
+
+		content, isMap := change.After.(map[string]any)
+		resourceId, err := arm.ParseResourceID(*change.ResourceID)
+		if !isMap || err != nil || resourceId.SubscriptionID == "" {
+			continue
+		}
+
+		evaluations, err := p.checkPolicyRestrictions(ctx, resourceId, content)
+		if err != nil {
+			log.Printf("checking policy restrictions of '%s': %v", *change.ResourceID, err)
+			unchecked++
+			continue
+		}
+
+		for _, policy := range provisioning.DenyingPolicies(evaluations) {
+			violations = append(violations, provisioning.PolicyViolation{
+				ResourceId:   *change.ResourceID,
+				ResourceType: resourceId.ResourceType.String(),
+				ResourceName: resourceId.Name,
+				Policy:       policy,
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		p.console.StopSpinner(ctx, "", input.StepFailed)
+		return &provisioning.PolicyViolationsError{Violations: violations}
+	}
+
+	p.console.StopSpinner(ctx, "", input.StepDone)
+	if unchecked > 0 {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: %d resource(s) couldn't be checked against Azure Policy before provisioning.", unchecked))
+	}
+
+	return nil
+}
+
+// checkPolicyRestrictions evaluates the policies assigned to the scope of the resource against its content. The
+// policies of the subscription are evaluated when the resource group doesn't exist yet.
+func (p *BicepProvider) checkPolicyRestrictions(
+	ctx context.Context, resourceId *arm.ResourceID, content map[string]any) ([]azapi.PolicyEvaluation, error) {
+	apiVersion, _ := content["apiVersion"].(string)
+	evaluations, err := p.azapi.CheckPolicyRestrictions(
+		ctx, resourceId.SubscriptionID, resourceId.ResourceGroupName, content, apiVersion)
+
+	var responseErr *azcore.ResponseError
+	if resourceId.ResourceGroupName != "" && errors.As(err, &responseErr) &&
+		responseErr.StatusCode == http.StatusNotFound {
+		return p.azapi.CheckPolicyRestrictions(ctx, resourceId.SubscriptionID, "", content, apiVersion)
+	}
+
+	return evaluations, err
+}
+
 // Preview runs deploy using the what-if argument
 func (p *BicepProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
+	ctx = p.withAuxiliaryTenants(ctx)
 	bicepDeploymentData, err := p.plan(ctx)
 	if err != nil {
 		return nil, err
@@ -801,12 +1100,30 @@ func (p *BicepProvider) Destroy(
 		return nil, fmt.Errorf("getting resources to delete: %w", err)
 	}
 
+	keepResourceGroups := existingResourceGroups(scope.SubscriptionId(), options.ExistingResourceIds())
+	if rgScope, ok := scope.(*infra.ResourceGroupScope); ok {
+		if slices.ContainsFunc(keepResourceGroups, func(name string) bool {
+			return strings.EqualFold(name, rgScope.ResourceGroupName())
+		}) {
+			return nil, errExistingResourceGroup(rgScope.ResourceGroupName())
+		}
+	} else if len(keepResourceGroups) > 0 {
+		resourcesToDelete, err = withoutResourceGroups(resourcesToDelete, keepResourceGroups)
+		if err != nil {
+			return nil, fmt.Errorf("mapping resources to resource groups: %w", err)
+		}
+	}
+
 	groupedResources, err := azapi.GroupByResourceGroup(resourcesToDelete)
 	if err != nil {
 		return nil, fmt.Errorf("mapping resources to resource groups: %w", err)
 	}
 
 	if len(groupedResources) == 0 {
+		if len(keepResourceGroups) > 0 {
+			return nil, errExistingResourceGroup(strings.Join(keepResourceGroups, ", "))
+		}
+
 		return nil, fmt.Errorf("%w, '%s'", infra.ErrDeploymentResourcesNotFound, deploymentToDelete.Name())
 	}
 
@@ -842,6 +1159,7 @@ func (p *BicepProvider) Destroy(
 		deploymentToDelete,
 		groupedResources,
 		len(resourcesToDelete),
+		keepResourceGroups,
 	); err != nil {
 		return nil, fmt.Errorf("deleting resource groups: %w", err)
 	}
@@ -953,6 +1271,97 @@ func cognitiveAccountsByKind(
 	return result
 }
 
+// History returns the ARM deployments of the current environment, most recent first.
+func (p *BicepProvider) History(ctx context.Context) ([]*provisioning.DeploymentHistoryEntry, error) {
+	var scope infra.Scope
+	templateOutputs := azure.ArmTemplateOutputs{}
+
+	modulePath := p.modulePath()
+	if _, err := os.Stat(modulePath); err == nil {
+		compileResult, err := p.compileBicep(ctx, modulePath)
+		if err != nil {
+			return nil, fmt.Errorf("compiling bicep template: %w", err)
+		}
+
+		scope, err = p.scopeForTemplate(compileResult.Template)
+		if err != nil {
+			return nil, fmt.Errorf("computing deployment scope: %w", err)
+		}
+
+		templateOutputs = compileResult.Template.Outputs
+	} else {
+		scope, err = p.inferScopeFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("computing deployment scope: %w", err)
+		}
+	}
+
+	deployments, err := scope.ListDeployments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+
+	slices.SortFunc(deployments, func(x, y *azapi.ResourceDeployment) int {
+		return y.Timestamp.Compare(x.Timestamp)
+	})
+
+	envName := p.env.Name()
+	history := []*provisioning.DeploymentHistoryEntry{}
+	for _, deployment := range deployments {
+		// Match on the azd env name tag, or the deployment name used by older versions of azd
+		if v, has := deployment.Tags[azure.TagKeyAzdEnvName]; !(has && *v == envName) && deployment.Name != envName {
+			continue
+		}
+
+		history = append(history, p.historyEntry(deployment, templateOutputs))
+	}
+
+	return history, nil
+}
+
+// historyEntry converts an ARM deployment into a deployment history entry.
+func (p *BicepProvider) historyEntry(
+	deployment *azapi.ResourceDeployment,
+	templateOutputs azure.ArmTemplateOutputs,
+) *provisioning.DeploymentHistoryEntry {
+	tags := map[string]string{}
+	for key, value := range deployment.Tags {
+		if value != nil {
+			tags[key] = *value
+		}
+	}
+
+	initiator := deployment.ModifiedBy
+	if v, has := tags[azure.TagKeyAzdInitiator]; has {
+		initiator = v
+	}
+
+	duration := ""
+	if deployment.Duration != "" {
+		if d, err := provisioning.ParseIsoDuration(deployment.Duration); err == nil {
+			duration = d.String()
+		} else {
+			log.Printf("ignoring deployment duration: %v", err)
+		}
+	}
+
+	return &provisioning.DeploymentHistoryEntry{
+		Id:            deployment.Name,
+		Timestamp:     deployment.Timestamp,
+		Status:        string(deployment.ProvisioningState),
+		Duration:      duration,
+		CorrelationId: deployment.CorrelationId,
+		Initiator:     initiator,
+		GitSha:        tags[azure.TagKeyAzdGitSha],
+		Tags:          tags,
+		Outputs: p.createOutputParameters(
+			templateOutputs,
+			azapi.CreateDeploymentOutput(deployment.Outputs),
+		),
+		PortalUrl: deployment.PortalUrl,
+	}
+}
+
 func getDeploymentOptions(deployments []*azapi.ResourceDeployment) []string {
 	promptValues := []string{}
 	for index, deployment := range deployments {
@@ -1046,12 +1455,19 @@ func (p *BicepProvider) destroyDeploymentWithConfirmation(
 	deployment infra.Deployment,
 	groupedResources map[string][]*azapi.Resource,
 	resourceCount int,
+	keepResourceGroups []string,
 ) error {
+	for _, resourceGroup := range keepResourceGroups {
+		p.console.Message(ctx, output.WithGrayFormat(
+			"Resource group %s holds existing resources of the project and won't be deleted.", resourceGroup))
+	}
+
 	if !options.Force() {
 		p.console.MessageUxItem(ctx, &ux.MultilineMessage{
 			Lines: p.generateResourcesToDelete(groupedResources)},
 		)
 		confirmDestroy, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Id: destroyPromptId,
 			Message: fmt.Sprintf(
 				"Total resources to %s: %d, are you sure you want to continue?",
 				output.WithErrorFormat("delete"),
@@ -1086,6 +1502,13 @@ func (p *BicepProvider) destroyDeploymentWithConfirmation(
 			return err
 		}
 
+		if len(keepResourceGroups) > 0 {
+			if optionsMap == nil {
+				optionsMap = map[string]any{}
+			}
+			optionsMap[azapi.KeepResourceGroupsOptionKey] = keepResourceGroups
+		}
+
 		return deployment.Delete(ctx, optionsMap, progress)
 	})
 
@@ -1139,6 +1562,7 @@ func (p *BicepProvider) purgeItems(
 				"the argument %s to skip this confirmation.\n", output.WithHighLightFormat("--purge")))
 
 		purgeItems, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Id: purgePromptId,
 			Message: fmt.Sprintf(
 				"Would you like to %s these resources instead, allowing their names to be reused?",
 				output.WithErrorFormat("permanently delete"),
@@ -1759,6 +2183,14 @@ func (p *BicepProvider) compileBicep(
 		}
 	}
 
+	// resolve the user-defined types of object properties, which allows prompting for each property
+	for paramKey, param := range template.Parameters {
+		if len(param.Properties) > 0 {
+			param.Properties = resolvePropertyTypes(param.Properties, template.Definitions, 0)
+			template.Parameters[paramKey] = param
+		}
+	}
+
 	// outputs resolves just the type. Value and Metadata should persist
 	for outputKey, output := range template.Outputs {
 		paramRef := output.Ref
@@ -1811,6 +2243,61 @@ func combineMetadata(base map[string]json.RawMessage, override map[string]json.R
 	return finalMetadata
 }
 
+// maxPropertyTypeDepth limits the resolution of nested user-defined types, which can be recursive.
+const maxPropertyTypeDepth = 5
+
+// resolvePropertyTypes returns a copy of the properties of an object type where the properties referencing a user-defined
+// type are replaced by the definition of the type. References which can't be resolved are kept as is.
+func resolvePropertyTypes(
+	properties azure.ArmTemplateParameterDefinitions,
+	definitions azure.ArmTemplateParameterDefinitions,
+	depth int,
+) azure.ArmTemplateParameterDefinitions {
+	if depth >= maxPropertyTypeDepth {
+		return properties
+	}
+
+	resolved := make(azure.ArmTemplateParameterDefinitions, len(properties))
+	for name, property := range properties {
+		if property.Ref != "" {
+			definitionKeyName, err := definitionName(property.Ref)
+			if err != nil {
+				log.Printf("failed resolving type of property '%s': %v", name, err)
+				resolved[name] = property
+				continue
+			}
+
+			definition, has := definitions[definitionKeyName]
+			if !has {
+				log.Printf("did not find definition for property type: %s", definitionKeyName)
+				resolved[name] = property
+				continue
+			}
+
+			property = azure.ArmTemplateParameterDefinition{
+				Type:                 definition.Type,
+				AllowedValues:        definition.AllowedValues,
+				MinValue:             definition.MinValue,
+				MaxValue:             definition.MaxValue,
+				MinLength:            definition.MinLength,
+				MaxLength:            definition.MaxLength,
+				Properties:           definition.Properties,
+				AdditionalProperties: definition.AdditionalProperties,
+				Metadata:             combineMetadata(definition.Metadata, property.Metadata),
+				Nullable:             property.Nullable,
+			}
+		}
+
+		if len(property.Properties) > 0 {
+			property.Properties = resolvePropertyTypes(property.Properties, definitions, depth+1)
+		}
+
+		resolved[name] = property
+	}
+
+	return resolved
+}
+
 func definitionName(typeDefinitionRef string) (string, error) {
 	// We typically expect `#/definitions/<name>` or `/definitions/<name>`, but loosely, we simply take
 	// `<name>` as the value of the last separated element.
@@ -1859,6 +2346,40 @@ func (p *BicepProvider) validatePreflight(
 	return target.ValidatePreflight(ctx, armTemplate, armParameters, tags, options)
 }
 
+// recordResourceGroups records the resource groups created by a subscription deployment to the journal of the context,
+// along with the resource groups of the environment which already exist, so only the new ones are removed when the
+// command is interrupted.
+func (p *BicepProvider) recordResourceGroups(ctx context.Context, target infra.Deployment) {
+	if !journal.IsRecording(ctx) {
+		return
+	}
+
+	if _, isSubscription := target.(*infra.SubscriptionDeployment); !isSubscription {
+		return
+	}
+
+	envName := p.env.Name()
+	resourceGroups, err := p.resourceService.ListResourceGroup(ctx, target.SubscriptionId(), &azapi.ListResourceGroupOptions{
+		TagFilter: &azapi.Filter{Key: azure.TagKeyAzdEnvName, Value: envName},
+	})
+	if err != nil {
+		log.Printf("listing resource groups of environment '%s' to record them: %v", envName, err)
+		return
+	}
+
+	existing := make([]string, 0, len(resourceGroups))
+	for _, resourceGroup := range resourceGroups {
+		existing = append(existing, resourceGroup.Name)
+	}
+
+	journal.Record(ctx, journal.Entry{
+		Kind:                   journal.EntryResourceGroups,
+		SubscriptionId:         target.SubscriptionId(),
+		EnvironmentName:        envName,
+		ExistingResourceGroups: existing,
+	})
+}
+
 // Deploys the specified Bicep module and parameters with the selected provisioning scope (subscription vs resource group)
 func (p *BicepProvider) deployModule(
 	ctx context.Context,
@@ -2025,35 +2546,13 @@ func (p *BicepProvider) ensureParameters(
 		parameterType := p.mapBicepTypeToInterfaceType(param.Type)
 		azdMetadata, hasMetadata := param.AzdMetadata()
 
-		// If a value is explicitly configured via a parameters file, use it.
-		if v, has := parameters[key]; has {
-			// Directly pass through Key Vault references without prompting.
-			if v.KeyVaultReference != nil {
-				configuredParameters[key] = azure.ArmParameter{
-					KeyVaultReference: v.KeyVaultReference,
-				}
-				continue
-			}
-
-			paramValue := armParameterFileValue(parameterType, v.Value, param.DefaultValue)
-			if paramValue != nil {
-
-				if stringValue, isString := paramValue.(string); isString && param.Secure() {
-					// For secure parameters using a string value, azd checks if the string is an Azure Key Vault Secret
-					// and if yes, it fetches the secret value from the Key Vault.
-					if keyvault.IsAzureKeyVaultSecret(stringValue) {
-						paramValue, err = p.keyvaultService.SecretFromAkvs(ctx, stringValue)
-						if err != nil {
-							return nil, err
-						}
-					}
-				}
-
-				configuredParameters[key] = azure.ArmParameter{
-					Value: paramValue,
-				}
-				continue
-			}
+		configured, has, err := p.configuredParameter(ctx, key, param, parameters)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			configuredParameters[key] = configured
+			continue
 		}
 
 		// If this parameter has a default, then there is no need for us to configure it.
@@ -2066,23 +2565,6 @@ func (p *BicepProvider) ensureParameters(
 			continue
 		}
 
-		// This required parameter was not in parameters file - see if we stored a value in config from an earlier
-		// prompt and if so use it.
-		configKey := fmt.Sprintf("infra.parameters.%s", key)
-
-		if v, has := p.env.Config.Get(configKey); has {
-			if isValueAssignableToParameterType(parameterType, v) {
-				configuredParameters[key] = azure.ArmParameter{
-					Value: v,
-				}
-				continue
-			} else {
-				// The saved value is no longer valid (perhaps the user edited their template to change the type of a)
-				// parameter and then re-ran `azd provision`. Forget the saved value (if we can) and prompt for a new one.
-				_ = p.env.Config.Unset("infra.parameters.%s")
-			}
-		}
-
 		// If the parameter is tagged with {type: "generate"}, skip prompting.
 		// We generate it once, then save to config for next attempts.`.
 		if hasMetadata && parameterType == provisioning.ParameterTypeString && azdMetadata.Type != nil &&
@@ -2096,7 +2578,9 @@ func (p *BicepProvider) ensureParameters(
 			configuredParameters[key] = azure.ArmParameter{
 				Value: genValue,
 			}
-			mustSetParamAsConfig(key, genValue, p.env.Config, param.Secure())
+			if err := p.setParamAsConfig(ctx, key, genValue, param.Secure()); err != nil {
+				return nil, err
+			}
 			configModified = true
 			continue
 		}
@@ -2129,7 +2613,9 @@ func (p *BicepProvider) ensureParameters(
 			for _, prompt := range parameterPrompts {
 				key := prompt.key
 				value := values[prompt.key]
-				mustSetParamAsConfig(key, value, p.env.Config, prompt.param.Secure())
+				if err := p.setParamAsConfig(ctx, key, value, prompt.param.Secure()); err != nil {
+					return nil, err
+				}
 				configModified = true
 				configuredParameters[key] = azure.ArmParameter{
 					Value: value,
@@ -2148,7 +2634,9 @@ func (p *BicepProvider) ensureParameters(
 				if key != "location" {
 					// location param is special.
 					// It is not persisted in config, it is set in the .env directly
-					mustSetParamAsConfig(key, value, p.env.Config, prompt.param.Secure())
+					if err := p.setParamAsConfig(ctx, key, value, prompt.param.Secure()); err != nil {
+						return nil, err
+					}
 				}
 				configModified = true
 				configuredParameters[key] = azure.ArmParameter{
@@ -2166,8 +2654,104 @@ func (p *BicepProvider) ensureParameters(
 	return configuredParameters, nil
 }
 
+// configuredParameter returns the value of a parameter configured in the parameters file or, for required parameters,
+// stored in the config of the environment by an earlier prompt. It never prompts and returns false when the parameter
+// isn't configured.
+func (p *BicepProvider) configuredParameter(
+	ctx context.Context,
+	key string,
+	param azure.ArmTemplateParameterDefinition,
+	parameters azure.ArmParameters,
+) (azure.ArmParameter, bool, error) {
+	parameterType := p.mapBicepTypeToInterfaceType(param.Type)
+
+	// If a value is explicitly configured via a parameters file, use it.
+	if v, has := parameters[key]; has {
+		// Directly pass through Key Vault references without prompting.
+		if v.KeyVaultReference != nil {
+			return azure.ArmParameter{KeyVaultReference: v.KeyVaultReference}, true, nil
+		}
+
+		paramValue := armParameterFileValue(parameterType, v.Value, param.DefaultValue)
+		if paramValue != nil {
+
+			if stringValue, isString := paramValue.(string); isString && param.Secure() {
+				// For secure parameters using a string value, azd checks if the string is an Azure Key Vault Secret
+				// and if yes, it fetches the secret value from the Key Vault.
+				if keyvault.IsAzureKeyVaultSecret(stringValue) {
+					var err error
+					paramValue, err = p.keyvaultService.SecretFromAkvs(ctx, stringValue)
+					if err != nil {
+						return azure.ArmParameter{}, false, err
+					}
+				} else {
+					output.AddSecret(stringValue)
+				}
+			}
+
+			return azure.ArmParameter{Value: paramValue}, true, nil
+		}
+	}
+
+	// If this parameter has a default, then there is no need for us to configure it.
+	if param.DefaultValue != nil {
+		return azure.ArmParameter{}, false, nil
+	}
+
+	if param.Nullable != nil && *param.Nullable {
+		return azure.ArmParameter{}, false, nil
+	}
+
+	// This required parameter was not in parameters file - see if we stored a value in config from an earlier
+	// prompt and if so use it.
+	configKey := fmt.Sprintf("infra.parameters.%s", key)
+
+	if v, has := p.env.Config.Get(configKey); has {
+		// The values of secure parameters are stored in Key Vault, and referenced from the config
+		v, err := p.parameterSecrets.Resolve(ctx, v)
+		if err != nil {
+			return azure.ArmParameter{}, false, err
+		}
+
+		if isValueAssignableToParameterType(parameterType, v) {
+			if secret, isString := v.(string); isString && param.Secure() {
+				output.AddSecret(secret)
+			}
+			return azure.ArmParameter{Value: v}, true, nil
+		}
+
+		// The saved value is no longer valid (perhaps the user edited their template to change the type of a)
+		// parameter and then re-ran `azd provision`. Forget the saved value (if we can) and prompt for a new one.
+		_ = p.env.Config.Unset("infra.parameters.%s")
+	}
+
+	return azure.ArmParameter{}, false, nil
+}
+
 var configInfraParametersKey = "infra.parameters."
 
+// setParamAsConfig persists the value of a parameter in the environment config. The values of secure parameters are
+// stored in the Key Vault of the environment, configured or created with infra.parametersKeyVault, and only a reference
+// to the secret is persisted, or in the local user vault when no Key Vault is available.
+func (p *BicepProvider) setParamAsConfig(ctx context.Context, key string, value any, isSecured bool) error {
+	if secret, isString := value.(string); isString && isSecured {
+		reference, err := p.parameterSecrets.Store(ctx, p.env, key, secret, p.options.ParametersKeyVault)
+		if err == nil {
+			mustSetParamAsConfig(key, reference, p.env.Config, false)
+			return nil
+		}
+
+		if !errors.Is(err, provisioning.ErrParametersKeyVaultUnavailable) {
+			return err
+		}
+
+		log.Printf("storing secure parameter '%s' in the local user vault: %v", key, err)
+	}
+
+	mustSetParamAsConfig(key, value, p.env.Config, isSecured)
+	return nil
+}
+
 // mustSetParamAsConfig sets the specified key-value pair in the given config.Config object.
 // If the isSecured flag is set to true, the value is set as a secret using config.SetSecret,
 // otherwise it is set using config.Set.
@@ -2186,6 +2770,7 @@ func mustSetParamAsConfig(key string, value any, config config.Config, isSecured
 	if !castOk {
 		log.Panic("tried to set a non-string as secret. This is not supported.")
 	}
+	output.AddSecret(secretString)
 	if err := config.SetSecret(configKey, secretString); err != nil {
 		log.Panicf("failed setting a secret in config: %v", err)
 	}
@@ -2280,9 +2865,11 @@ func NewBicepProvider(
 	prompters prompt.Prompter,
 	curPrincipal provisioning.CurrentPrincipalIdProvider,
 	keyvaultService keyvault.KeyVaultService,
+	parameterSecrets *provisioning.ParameterSecrets,
 	cloud *cloud.Cloud,
 	subscriptionManager *account.SubscriptionsManager,
 	azureClient *azapi.AzureClient,
+	gitCli *git.Cli,
 ) provisioning.Provider {
 	return &BicepProvider{
 		envManager:          envManager,
@@ -2295,9 +2882,11 @@ func NewBicepProvider(
 		prompters:           prompters,
 		curPrincipal:        curPrincipal,
 		keyvaultService:     keyvaultService,
+		parameterSecrets:    parameterSecrets,
 		portalUrlBase:       cloud.PortalUrlBase,
 		subscriptionManager: subscriptionManager,
 		azureClient:         azureClient,
+		gitCli:              gitCli,
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appconfiguration/armappconfiguration"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
@@ -34,6 +35,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
@@ -224,6 +226,44 @@ func TestBicepDestroy(t *testing.T) {
 		require.Contains(t, consoleOutput[0], "Deleting your resources can take some time")
 		require.Contains(t, consoleOutput[1], "")
 	})
+
+	t.Run("ExistingResourceInOtherResourceGroup", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareStateMocks(mockContext)
+		prepareDestroyMocks(mockContext)
+
+		infraProvider := createBicepProvider(t, mockContext)
+
+		destroyOptions := provisioning.NewDestroyOptions(true, true).WithExistingResourceIds([]string{
+			"/subscriptions/SUBSCRIPTION_ID/resourceGroups/SHARED/providers/Microsoft.KeyVault/vaults/shared",
+		})
+		destroyResult, err := infraProvider.Destroy(*mockContext.Context, destroyOptions)
+		require.NoError(t, err)
+		require.NotNil(t, destroyResult)
+
+		consoleOutput := mockContext.Console.Output()
+		require.Contains(t, consoleOutput[0], "Resource group SHARED holds existing resources of the project")
+	})
+
+	t.Run("ExistingResourceInResourceGroup", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		prepareStateMocks(mockContext)
+		prepareDestroyMocks(mockContext)
+
+		infraProvider := createBicepProvider(t, mockContext)
+
+		destroyOptions := provisioning.NewDestroyOptions(true, true).WithExistingResourceIds([]string{
+			"/subscriptions/SUBSCRIPTION_ID/resourceGroups/resource_group/providers/Microsoft.Web/sites/app-123",
+		})
+		_, err := infraProvider.Destroy(*mockContext.Context, destroyOptions)
+
+		var suggestionErr *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.ErrorContains(t, err, "resource group 'resource_group' holds existing resources of the project")
+		require.Empty(t, mockContext.Console.Output())
+	})
 }
 
 func TestPlanForResourceGroup(t *testing.T) {
@@ -408,6 +448,16 @@ func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepPro
 		},
 	}
 
+	keyVaultService := keyvault.NewKeyVaultService(
+		mockaccount.SubscriptionCredentialProviderFunc(
+			func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+				return mockContext.Credentials, nil
+			}),
+		mockContext.ArmClientOptions,
+		mockContext.CoreClientOptions,
+		cloud.AzurePublic(),
+	)
+
 	provider := NewBicepProvider(
 		azCli,
 		bicepCli,
@@ -418,18 +468,12 @@ func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepPro
 		mockContext.Console,
 		prompt.NewDefaultPrompter(env, mockContext.Console, accountManager, resourceService, cloud.AzurePublic()),
 		&mockCurrentPrincipal{},
-		keyvault.NewKeyVaultService(
-			mockaccount.SubscriptionCredentialProviderFunc(
-				func(_ context.Context, _ string) (azcore.TokenCredential, error) {
-					return mockContext.Credentials, nil
-				}),
-			mockContext.ArmClientOptions,
-			mockContext.CoreClientOptions,
-			cloud.AzurePublic(),
-		),
+		keyVaultService,
+		provisioning.NewParameterSecrets(keyVaultService, resourceService, nil, nil, nil),
 		cloud.AzurePublic(),
 		nil,
 		nil,
+		git.NewCli(mockContext.CommandRunner),
 	)
 
 	err = provider.Initialize(*mockContext.Context, projectDir, options)
@@ -944,6 +988,10 @@ func (m *mockedScope) SubscriptionId() string {
 	return "sub-id"
 }
 
+func (m *mockedScope) DeleteDeploymentRecord(ctx context.Context, deploymentName string) error {
+	return nil
+}
+
 func (m *mockedScope) Deployment(deploymentName string) infra.Deployment {
 	return &infra.SubscriptionDeployment{}
 }
@@ -1021,9 +1069,11 @@ func TestUserDefinedTypes(t *testing.T) {
 			mockContext.CoreClientOptions,
 			cloud.AzurePublic(),
 		),
+		nil,
 		cloud.AzurePublic(),
 		nil,
 		nil,
+		git.NewCli(mockContext.CommandRunner),
 	)
 	bicepProvider, gooCast := provider.(*BicepProvider)
 	require.True(t, gooCast)
@@ -1462,3 +1512,73 @@ func TestDefaultLocationToSelectFn(t *testing.T) {
 		require.Nil(t, result)
 	})
 }
+
+func TestResolvePropertyTypes(t *testing.T) {
+	definitions := azure.ArmTemplateParameterDefinitions{
+		"skuType": {
+			Type:          "string",
+			AllowedValues: to.Ptr([]any{"Basic", "Standard"}),
+			Metadata: map[string]json.RawMessage{
+				"description": []byte(`"The SKU"`),
+			},
+		},
+		"networkType": {
+			Type: "object",
+			Properties: azure.ArmTemplateParameterDefinitions{
+				"sku": {Ref: "#/definitions/skuType"},
+			},
+		},
+	}
+
+	properties := azure.ArmTemplateParameterDefinitions{
+		"name":    {Type: "string"},
+		"network": {Ref: "#/definitions/networkType", Nullable: to.Ptr(true)},
+		"missing": {Ref: "#/definitions/missingType"},
+	}
+
+	resolved := resolvePropertyTypes(properties, definitions, 0)
+
+	require.Equal(t, "string", resolved["name"].Type)
+	require.Equal(t, "#/definitions/missingType", resolved["missing"].Ref)
+
+	network := resolved["network"]
+	require.Equal(t, "object", network.Type)
+	require.Equal(t, to.Ptr(true), network.Nullable)
+
+	sku := network.Properties["sku"]
+	require.Equal(t, "string", sku.Type)
+	require.Equal(t, []any{"Basic", "Standard"}, *sku.AllowedValues)
+	description, has := sku.Description()
+	require.True(t, has)
+	require.Equal(t, "The SKU", description)
+
+	// the definitions are not modified
+	require.Equal(t, "#/definitions/skuType", definitions["networkType"].Properties["sku"].Ref)
+}
+
+func TestTemplateSpecParameters(t *testing.T) {
+	definitions := azure.ArmTemplateParameterDefinitions{
+		"location":      {Type: "string"},
+		"adminPassword": {Type: "securestring"},
+		"apiKey":        {Type: "securestring"},
+	}
+	reference := &azure.KeyVaultParameterReference{SecretName: "api-key"}
+
+	parameters := templateSpecParameters(definitions, azure.ArmParameters{
+		"location":      {Value: "eastus2"},
+		"adminPassword": {Value: "P@ssw0rd!"},
+		"apiKey":        {KeyVaultReference: reference},
+	})
+
+	require.Equal(t, azure.ArmParameters{
+		"location": {Value: "eastus2"},
+		"apiKey":   {KeyVaultReference: reference},
+	}, parameters)
+}
+
+func TestBicepProviderInvalidBicepVersion(t *testing.T) {
+	provider := &BicepProvider{options: provisioning.Options{BicepVersion: "latest"}}
+
+	err := provider.ensureBicepVersion(context.Background())
+	require.ErrorContains(t, err, "invalid bicep version 'latest' in azure.yaml")
+}
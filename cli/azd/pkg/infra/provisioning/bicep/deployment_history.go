@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+var pruneDeploymentsPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "PRUNE_DEPLOYMENTS_CONFIRM",
+	Description: "Whether to delete old deployments from a deployment history near the Azure limit: true or false.",
+	Commands:    []string{"provision", "up"},
+})
+
+// deploymentName returns the name of the next deployment of the environment, following the naming options of azure.yaml
+// when the deployments are kept in a deployment history.
+func (p *BicepProvider) deploymentName(ctx context.Context) string {
+	options := p.options.DeploymentName
+	if options == nil || !p.deploymentManager.HasDeploymentHistory() {
+		return p.deploymentManager.GenerateDeploymentName(p.env.Name())
+	}
+
+	gitSha := ""
+	if options.GitSha {
+		gitSha = provisioning.SourceVersion()
+		if gitSha == "" && p.gitCli != nil {
+			sha, err := p.gitCli.GetHeadCommit(ctx, p.projectPath)
+			if err != nil {
+				log.Printf("getting the git commit for the deployment name: %v", err)
+			}
+			gitSha = sha
+		}
+	}
+
+	return options.DeploymentName(p.env.Name(), gitSha, time.Now())
+}
+
+// pruneDeploymentHistory removes the oldest deployments of azd from the deployment history of the scope when it's near
+// the ARM limit of 800 deployments, after confirmation, so the deployment doesn't fail once the limit is reached.
+func (p *BicepProvider) pruneDeploymentHistory(ctx context.Context, scope infra.Scope) error {
+	if !p.deploymentManager.HasDeploymentHistory() {
+		return nil
+	}
+
+	deployments, err := scope.ListDeployments(ctx)
+	if err != nil {
+		log.Printf("listing deployments to check the deployment history: %v", err)
+		return nil
+	}
+
+	full := len(deployments) >= provisioning.DeploymentHistoryLimit
+	fullErr := &internal.ErrorWithSuggestion{
+		Err: fmt.Errorf(
+			"the deployment history of the %s has reached the limit of %d deployments",
+			scopeDescription(scope), provisioning.DeploymentHistoryLimit),
+		Suggestion: "Suggestion: delete old deployments from the deployment history in the Azure Portal, or set " +
+			"'infra.deploymentName.timestamp' to false in azure.yaml so each deployment replaces the previous one.",
+	}
+
+	prune := provisioning.DeploymentsToPrune(deployments)
+	if len(prune) == 0 {
+		if full {
+			return fullErr
+		}
+
+		return nil
+	}
+
+	p.console.StopSpinner(ctx, "", input.Step)
+	confirm, err := p.console.Confirm(ctx, input.ConsoleOptions{
+		Id: pruneDeploymentsPromptId,
+		Message: fmt.Sprintf(
+			"The deployment history of the %s has %d of the %d deployments allowed by Azure. "+
+				"Delete the %d oldest deployments of azd from the history? The deployed resources are kept.",
+			scopeDescription(scope), len(deployments), provisioning.DeploymentHistoryLimit, len(prune)),
+		DefaultValue: false,
+	})
+	if err != nil {
+		return fmt.Errorf("prompting to prune the deployment history: %w", err)
+	}
+
+	if !confirm {
+		if full {
+			return fullErr
+		}
+
+		return nil
+	}
+
+	p.console.ShowSpinner(ctx, "Pruning the deployment history", input.Step)
+	for _, deployment := range prune {
+		if err := scope.DeleteDeploymentRecord(ctx, deployment.Name); err != nil {
+			p.console.StopSpinner(ctx, "", input.StepFailed)
+			return fmt.Errorf("pruning the deployment history: %w", err)
+		}
+	}
+	p.console.StopSpinner(ctx, "", input.StepDone)
+
+	p.console.Message(ctx, output.WithGrayFormat(
+		"  Deleted %d deployments from the deployment history of the %s.", len(prune), scopeDescription(scope)))
+	return nil
+}
+
+// scopeDescription returns the description of the scope displayed to the user, ex) resource group rg-dev
+func scopeDescription(scope infra.Scope) string {
+	if rgScope, ok := scope.(interface{ ResourceGroupName() string }); ok {
+		return fmt.Sprintf("resource group %s", rgScope.ResourceGroupName())
+	}
+
+	return fmt.Sprintf("subscription %s", scope.SubscriptionId())
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// historyScope is a resource group which deployment history has the given number of deployments of azd
+type historyScope struct {
+	count   int
+	deleted []string
+}
+
+func (s *historyScope) SubscriptionId() string {
+	return "SUBSCRIPTION_ID"
+}
+
+func (s *historyScope) ResourceGroupName() string {
+	return "rg-dev"
+}
+
+func (s *historyScope) ListDeployments(ctx context.Context) ([]*azapi.ResourceDeployment, error) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployments := []*azapi.ResourceDeployment{}
+	for i := range s.count {
+		deployments = append(deployments, &azapi.ResourceDeployment{
+			Name:              fmt.Sprintf("dev-%d", i),
+			Tags:              map[string]*string{azure.TagKeyAzdEnvName: to.Ptr("dev")},
+			ProvisioningState: azapi.DeploymentProvisioningStateSucceeded,
+			Timestamp:         start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	return deployments, nil
+}
+
+func (s *historyScope) DeleteDeploymentRecord(ctx context.Context, deploymentName string) error {
+	s.deleted = append(s.deleted, deploymentName)
+	return nil
+}
+
+func (s *historyScope) Deployment(deploymentName string) infra.Deployment {
+	return &infra.ResourceGroupDeployment{}
+}
+
+func TestPruneDeploymentHistory(t *testing.T) {
+	whenConfirmed := func(mockContext *mocks.MockContext, confirmed bool) {
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "deployment history of the resource group rg-dev")
+		}).Respond(confirmed)
+	}
+
+	t.Run("NotNearLimit", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		provider := createBicepProvider(t, mockContext)
+
+		scope := &historyScope{count: 100}
+		require.NoError(t, provider.pruneDeploymentHistory(*mockContext.Context, scope))
+		require.Empty(t, scope.deleted)
+	})
+
+	t.Run("Confirmed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		whenConfirmed(mockContext, true)
+		provider := createBicepProvider(t, mockContext)
+
+		scope := &historyScope{count: provisioning.DeploymentHistoryLimit}
+		require.NoError(t, provider.pruneDeploymentHistory(*mockContext.Context, scope))
+		require.Len(t, scope.deleted, 50)
+		require.Equal(t, "dev-0", scope.deleted[0])
+	})
+
+	t.Run("DeniedAtLimit", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		whenConfirmed(mockContext, false)
+		provider := createBicepProvider(t, mockContext)
+
+		scope := &historyScope{count: provisioning.DeploymentHistoryLimit}
+		err := provider.pruneDeploymentHistory(*mockContext.Context, scope)
+		var suggestionErr *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.ErrorContains(t, err, "has reached the limit of 800 deployments")
+		require.Empty(t, scope.deleted)
+	})
+
+	t.Run("DeniedNearLimit", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareBicepMocks(mockContext)
+		whenConfirmed(mockContext, false)
+		provider := createBicepProvider(t, mockContext)
+
+		scope := &historyScope{count: provisioning.DeploymentHistoryLimit - 5}
+		require.NoError(t, provider.pruneDeploymentHistory(*mockContext.Context, scope))
+		require.Empty(t, scope.deleted)
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/internal"
+)
+
+// existingResourceGroups returns the names of the resource groups of the subscription holding existing resources of the
+// project. The deployment only references these resources, or assigns roles on them, so their resource groups are never
+// deleted with the resources of the deployment.
+func existingResourceGroups(subscriptionId string, existingResourceIds []string) []string {
+	resourceGroups := []string{}
+	for _, id := range existingResourceIds {
+		resourceId, err := arm.ParseResourceID(id)
+		if err != nil {
+			log.Printf("ignoring existing resource '%s': %v", id, err)
+			continue
+		}
+
+		if !strings.EqualFold(resourceId.SubscriptionID, subscriptionId) || resourceId.ResourceGroupName == "" {
+			continue
+		}
+
+		if !slices.ContainsFunc(resourceGroups, func(name string) bool {
+			return strings.EqualFold(name, resourceId.ResourceGroupName)
+		}) {
+			resourceGroups = append(resourceGroups, resourceId.ResourceGroupName)
+		}
+	}
+
+	slices.Sort(resourceGroups)
+	return resourceGroups
+}
+
+// withoutResourceGroups returns the resources of the deployment which aren't in one of the given resource groups.
+func withoutResourceGroups(
+	resources []*armresources.ResourceReference,
+	resourceGroups []string,
+) ([]*armresources.ResourceReference, error) {
+	filtered := []*armresources.ResourceReference{}
+	for _, resource := range resources {
+		resourceId, err := arm.ParseResourceID(*resource.ID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing resource ID: %w", err)
+		}
+
+		if !slices.ContainsFunc(resourceGroups, func(name string) bool {
+			return strings.EqualFold(name, resourceId.ResourceGroupName)
+		}) {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	return filtered, nil
+}
+
+// errExistingResourceGroup is returned when the resource group a deployment targets holds existing resources of the
+// project, since deleting the resources of the deployment deletes the whole resource group.
+func errExistingResourceGroup(resourceGroupName string) error {
+	return &internal.ErrorWithSuggestion{
+		Err: fmt.Errorf(
+			"resource group '%s' holds existing resources of the project, which would be deleted with it",
+			resourceGroupName,
+		),
+		Suggestion: "Suggestion: delete the resources of the environment from the Azure portal, or move the existing " +
+			"resources out of the resource group before running 'azd down'.",
+	}
+}
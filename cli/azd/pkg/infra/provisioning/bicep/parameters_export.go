@@ -0,0 +1,173 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// ExportParameters renders the effective parameters of the environment as a .bicepparam file using the template of the
+// project. Secure parameters are read from environment variables with readEnvironmentVariable(), rather than inlined.
+func (p *BicepProvider) ExportParameters(ctx context.Context, dir string) (*provisioning.ParametersFile, error) {
+	parameters, err := p.Parameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infraRoot := p.options.Path
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(p.projectPath, infraRoot)
+	}
+
+	templatePath := filepath.Join(infraRoot, p.options.Module+".bicep")
+	usingPath, err := filepath.Rel(dir, templatePath)
+	if err != nil {
+		usingPath = templatePath
+	}
+
+	slices.SortFunc(parameters, func(a, b provisioning.Parameter) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf(
+		"// Generated by 'azd infra params export' for environment '%s'\n", p.env.Name()))
+	builder.WriteString(fmt.Sprintf("using %s\n", bicepString(filepath.ToSlash(usingPath))))
+
+	for _, parameter := range parameters {
+		builder.WriteString("\n")
+		if parameter.Secret {
+			envVar := secureParameterEnvVar(parameter)
+			builder.WriteString(fmt.Sprintf(
+				"// Secure parameter, set the %s environment variable to its value\n", envVar))
+			builder.WriteString(fmt.Sprintf(
+				"param %s = readEnvironmentVariable(%s)\n", parameter.Name, bicepString(envVar)))
+			continue
+		}
+
+		value, err := bicepValue(parameter.Value, "")
+		if err != nil {
+			return nil, fmt.Errorf("rendering parameter '%s': %w", parameter.Name, err)
+		}
+
+		builder.WriteString(fmt.Sprintf("param %s = %s\n", parameter.Name, value))
+	}
+
+	return &provisioning.ParametersFile{
+		Name:     fmt.Sprintf("%s.%s%s", p.options.Module, p.env.Name(), bicepparamFileExtension),
+		Contents: []byte(builder.String()),
+	}, nil
+}
+
+// secureParameterEnvVar returns the environment variable a secure parameter is read from: the variable it's mapped to
+// in the parameters file, or else AZD_PARAM_<NAME>.
+func secureParameterEnvVar(parameter provisioning.Parameter) string {
+	if len(parameter.EnvVarMapping) == 1 {
+		return parameter.EnvVarMapping[0]
+	}
+
+	return "AZD_PARAM_" + environment.Key(parameter.Name)
+}
+
+var bicepIdentifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// bicepValue renders a value as a Bicep literal, ex) 'eastus', ['a', 'b'] or { name: 'value' }
+func bicepValue(value any, indent string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return bicepString(v), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case int, int32, int64:
+		return fmt.Sprint(v), nil
+	case float64:
+		number := strconv.FormatFloat(v, 'f', -1, 64)
+		if v != math.Trunc(v) {
+			// Bicep doesn't have decimal literals
+			return fmt.Sprintf("json(%s)", bicepString(number)), nil
+		}
+
+		return number, nil
+	case []any:
+		if len(v) == 0 {
+			return "[]", nil
+		}
+
+		builder := strings.Builder{}
+		builder.WriteString("[\n")
+		for _, item := range v {
+			rendered, err := bicepValue(item, indent+"  ")
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(fmt.Sprintf("%s  %s\n", indent, rendered))
+		}
+		builder.WriteString(indent + "]")
+		return builder.String(), nil
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}", nil
+		}
+
+		builder := strings.Builder{}
+		builder.WriteString("{\n")
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			rendered, err := bicepValue(v[key], indent+"  ")
+			if err != nil {
+				return "", err
+			}
+
+			name := key
+			if !bicepIdentifierRegex.MatchString(key) {
+				name = bicepString(key)
+			}
+
+			builder.WriteString(fmt.Sprintf("%s  %s: %s\n", indent, name, rendered))
+		}
+		builder.WriteString(indent + "}")
+		return builder.String(), nil
+	default:
+		// Other values, ex) typed slices or maps, are rendered from their JSON representation
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+
+		var generic any
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return "", err
+		}
+
+		return bicepValue(generic, indent)
+	}
+}
+
+// bicepString renders a string as a Bicep string literal, escaping the characters which have a meaning in Bicep strings
+func bicepString(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+		"${", `\${`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+
+	return "'" + replacer.Replace(value) + "'"
+}
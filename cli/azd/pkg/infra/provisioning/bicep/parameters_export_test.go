@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBicepValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"Null", nil, "null"},
+		{"String", "eastus", "'eastus'"},
+		{"EscapedString", "it's ${name}\n", `'it\'s \${name}\n'`},
+		{"Bool", true, "true"},
+		{"Int", 3, "3"},
+		{"IntegralFloat", float64(42), "42"},
+		{"DecimalFloat", 1.5, "json('1.5')"},
+		{"EmptyArray", []any{}, "[]"},
+		{"Array", []any{"a", float64(1)}, "[\n  'a'\n  1\n]"},
+		{"EmptyObject", map[string]any{}, "{}"},
+		{
+			"Object",
+			map[string]any{"name": "value", "my-key": []any{true}},
+			"{\n  'my-key': [\n    true\n  ]\n  name: 'value'\n}",
+		},
+		{"TypedSlice", []string{"a"}, "[\n  'a'\n]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := bicepValue(tt.value, "")
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestSecureParameterEnvVar(t *testing.T) {
+	require.Equal(t, "DB_PASSWORD", secureParameterEnvVar(provisioning.Parameter{
+		Name:          "dbPassword",
+		EnvVarMapping: []string{"DB_PASSWORD"},
+	}))
+
+	require.Equal(t, "AZD_PARAM_DB_PASSWORD", secureParameterEnvVar(provisioning.Parameter{
+		Name: "db-password",
+	}))
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
@@ -241,6 +242,10 @@ func (p *BicepProvider) promptForParameter(
 	azdMetadata, _ := param.AzdMetadata()
 	paramType := p.mapBicepTypeToInterfaceType(param.Type)
 
+	if paramType == provisioning.ParameterTypeString && param.Nullable != nil && *param.Nullable {
+		msg = fmt.Sprintf("Enter a value for the '%s' infrastructure %s (optional):", key, securedParam)
+	}
+
 	var value any
 
 	if paramType == provisioning.ParameterTypeString &&
@@ -439,6 +444,15 @@ func (p *BicepProvider) promptForParameter(
 			}
 			value = userValue
 		case provisioning.ParameterTypeObject:
+			if hasKnownProperties(param) {
+				userValue, err := p.promptForObjectProperties(ctx, key, param)
+				if err != nil {
+					return nil, err
+				}
+				value = userValue
+				break
+			}
+
 			userValue, err := promptWithValidation(ctx, p.console, input.ConsoleOptions{
 				Message: msg,
 				Help:    help,
@@ -455,6 +469,61 @@ func (p *BicepProvider) promptForParameter(
 	return value, nil
 }
 
+// hasKnownProperties returns true when the shape of an object parameter is fully known (ex: a user-defined type), which
+// allows prompting for each of its properties instead of its JSON representation.
+func hasKnownProperties(param azure.ArmTemplateParameterDefinition) bool {
+	if len(param.Properties) == 0 || param.Secure() {
+		return false
+	}
+
+	if param.AdditionalProperties != nil && param.AdditionalProperties.HasAdditionalProperties() {
+		return false
+	}
+
+	for _, property := range param.Properties {
+		// unresolved user-defined types or unsupported types
+		if !slices.Contains(supportedPropertyTypes, strings.ToLower(property.Type)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var supportedPropertyTypes = []string{"string", "securestring", "bool", "int", "object", "array"}
+
+// promptForObjectProperties prompts for the value of each property of an object parameter. Properties which are nullable
+// are omitted from the object when no value is provided.
+func (p *BicepProvider) promptForObjectProperties(
+	ctx context.Context,
+	key string,
+	param azure.ArmTemplateParameterDefinition,
+) (map[string]any, error) {
+	description := fmt.Sprintf("Parameter %s is an object. Enter a value for each of its properties.",
+		output.WithUnderline("%s", key))
+	if help, has := param.Description(); has {
+		description = fmt.Sprintf("%s\n%s", description, output.WithGrayFormat(help))
+	}
+	p.console.Message(ctx, description)
+
+	value := map[string]any{}
+	for _, name := range slices.Sorted(maps.Keys(param.Properties)) {
+		property := param.Properties[name]
+		propertyValue, err := p.promptForParameter(ctx, fmt.Sprintf("%s.%s", key, name), property, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if property.Nullable != nil && *property.Nullable && propertyValue == "" {
+			continue
+		}
+
+		value[name] = propertyValue
+	}
+
+	return value, nil
+}
+
 // promptWithValidation prompts for a value using the console and then validates that it satisfies all the validation
 // functions. If it does, it is converted from a string to a value using the converter and returned. If any validation
 // fails, the prompt is retried after printing the error (prefixed with "Error: ") to the console. If there are is an
@@ -494,3 +494,82 @@ func TestPromptForParameterNumberDefaultStringTypeError(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestPromptForParameterObjectProperties(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+
+	p := createBicepProvider(t, mockContext)
+
+	mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "'testParam.name' infrastructure parameter:")
+	}).Respond("contoso")
+	mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "'testParam.tag' infrastructure parameter (optional):")
+	}).Respond("")
+	mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "'testParam.network.port' infrastructure parameter")
+	}).Respond("8080")
+	mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "'testParam.sku' infrastructure parameter")
+	}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+		require.Equal(t, []string{"Basic", "Standard"}, options.Options)
+		require.Equal(t, "The SKU of the service", options.Help)
+		return 1, nil
+	})
+
+	value, err := p.promptForParameter(*mockContext.Context, "testParam", azure.ArmTemplateParameterDefinition{
+		Type: "object",
+		Properties: azure.ArmTemplateParameterDefinitions{
+			"name": {Type: "string"},
+			"tag":  {Type: "string", Nullable: to.Ptr(true)},
+			"sku": {
+				Type:          "string",
+				AllowedValues: to.Ptr([]any{"Basic", "Standard"}),
+				Metadata: map[string]json.RawMessage{
+					"description": []byte(`"The SKU of the service"`),
+				},
+			},
+			"network": {
+				Type: "object",
+				Properties: azure.ArmTemplateParameterDefinitions{
+					"port": {Type: "int"},
+				},
+			},
+		},
+	}, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"name": "contoso",
+		"sku":  "Standard",
+		"network": map[string]any{
+			"port": 8080,
+		},
+	}, value)
+}
+
+func TestPromptForParameterObjectWithAdditionalProperties(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+
+	p := createBicepProvider(t, mockContext)
+
+	var additionalProperties azure.ArmTemplateParameterAdditionalPropertiesValue
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "string"}`), &additionalProperties))
+
+	mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+		return strings.Contains(options.Message, "'testParam' infrastructure parameter")
+	}).Respond(`{"name": "contoso", "extra": "value"}`)
+
+	value, err := p.promptForParameter(*mockContext.Context, "testParam", azure.ArmTemplateParameterDefinition{
+		Type: "object",
+		Properties: azure.ArmTemplateParameterDefinitions{
+			"name": {Type: "string"},
+		},
+		AdditionalProperties: &additionalProperties,
+	}, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "contoso", "extra": "value"}, value)
+}
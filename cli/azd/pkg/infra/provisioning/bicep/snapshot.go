@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// Snapshot creates the snapshot of the compiled template and the parameters a provision would deploy now. The template
+// is compiled locally and the parameters are read from the parameters file and the environment. Unlike a provision, it
+// never prompts for a parameter nor generates its value: a required parameter which isn't configured yet is recorded
+// without a value, so it's reported as changed.
+func (p *BicepProvider) Snapshot(ctx context.Context) (*provisioning.Snapshot, error) {
+	modulePath := p.modulePath()
+	compileResult, err := p.compileBicep(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBicepFile(modulePath) {
+		parametersResult, err := p.loadParameters(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("resolving bicep parameters file: %w", err)
+		}
+
+		configuredParameters := azure.ArmParameters{}
+		for key, param := range compileResult.Template.Parameters {
+			configured, has, err := p.configuredParameter(ctx, key, param, parametersResult.parameters)
+			if err != nil {
+				return nil, err
+			}
+			if has {
+				configuredParameters[key] = configured
+			}
+		}
+		compileResult.Parameters = configuredParameters
+	}
+
+	return p.newSnapshot(compileResult)
+}
+
+// newSnapshot creates the snapshot of a compiled template and its parameters. Parameters which aren't set use their
+// default value, like the parameters hash of the deployment state.
+func (p *BicepProvider) newSnapshot(compiled *compileBicepResult) (*provisioning.Snapshot, error) {
+	key, err := provisioning.SnapshotKey(p.env)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make(map[string]provisioning.SnapshotParameter, len(compiled.Template.Parameters))
+	for name, definition := range compiled.Template.Parameters {
+		value := definition.DefaultValue
+		if parameter, has := compiled.Parameters[name]; has {
+			value = parameter.Value
+			if parameter.KeyVaultReference != nil {
+				value = parameter.KeyVaultReference
+			}
+		}
+
+		parameters[name] = provisioning.SnapshotParameter{Value: value, Secure: definition.Secure()}
+	}
+
+	return provisioning.NewSnapshot(provisioning.Bicep, compiled.RawArmTemplate, parameters, key)
+}
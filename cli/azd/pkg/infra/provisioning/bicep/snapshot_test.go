@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBicepSnapshot(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+	infraProvider := createBicepProvider(t, mockContext)
+
+	snapshot, err := infraProvider.Snapshot(*mockContext.Context)
+	require.NoError(t, err)
+
+	require.Equal(t, provisioning.Bicep, snapshot.Provider)
+	require.NotEmpty(t, snapshot.TemplateHash)
+	require.Equal(t, `"`+infraProvider.env.GetLocation()+`"`, snapshot.Parameters["location"])
+	require.Equal(t, `"`+infraProvider.env.Name()+`"`, snapshot.Parameters["environmentName"])
+	// secure parameters are only recorded by their hash
+	require.True(t, strings.HasPrefix(snapshot.Parameters["kvSecret"], "hmac-sha256:"))
+
+	// the same template and parameters have the same snapshot
+	again, err := infraProvider.Snapshot(*mockContext.Context)
+	require.NoError(t, err)
+	require.False(t, again.Diff(snapshot).Changed)
+
+	t.Run("RequiredParameterNotConfigured", func(t *testing.T) {
+		armTemplate := azure.ArmTemplate{
+			Schema:         "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+			ContentVersion: "1.0.0.0",
+			Parameters: azure.ArmTemplateParameterDefinitions{
+				"environmentName": {Type: "string"},
+				"location":        {Type: "string"},
+				"adminName":       {Type: "string"},
+			},
+		}
+		bicepBytes, err := json.Marshal(armTemplate)
+		require.NoError(t, err)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(args.Cmd, "bicep") && args.Args[0] == "build"
+		}).Respond(exec.RunResult{Stdout: string(bicepBytes)})
+
+		// the mock console fails any prompt, a provision would prompt for the value
+		infraProvider.compileBicepMemoryCache = nil
+		snapshot, err := infraProvider.Snapshot(*mockContext.Context)
+		require.NoError(t, err)
+		require.Equal(t, "null", snapshot.Parameters["adminName"])
+
+		_, has := infraProvider.env.Config.Get("infra.parameters.adminName")
+		require.False(t, has)
+	})
+}
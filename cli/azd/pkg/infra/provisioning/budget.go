@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+)
+
+const (
+	defaultBudgetWarnAt  = 80
+	defaultBudgetBlockAt = 100
+)
+
+// BudgetOptions enables checking the spend of the Cost Management budgets of the subscription before provisioning.
+// The thresholds are percentages of the amount of a budget.
+type BudgetOptions struct {
+	// The percentage of a budget spent above which a warning is displayed. (Default: 80)
+	WarnAt float64 `yaml:"warnAt,omitempty"`
+	// The percentage of a budget spent above which provisioning is blocked. (Default: 100)
+	BlockAt float64 `yaml:"blockAt,omitempty"`
+}
+
+// BudgetStatus is a budget which spend is above the warning threshold.
+type BudgetStatus struct {
+	Budget azapi.Budget
+	// The percentage of the budget spent in its current period
+	Percent float64
+	// Whether the spend is above the threshold blocking provisioning
+	Blocking bool
+}
+
+// CheckBudgets returns the cost budgets which spend is above the warning threshold, the most spent first.
+func CheckBudgets(budgets []azapi.Budget, options BudgetOptions) []BudgetStatus {
+	warnAt := options.WarnAt
+	if warnAt == 0 {
+		warnAt = defaultBudgetWarnAt
+	}
+
+	blockAt := options.BlockAt
+	if blockAt == 0 {
+		blockAt = defaultBudgetBlockAt
+	}
+
+	statuses := []BudgetStatus{}
+	for _, budget := range budgets {
+		// Usage budgets aren't amounts of money
+		if budget.Category != "Cost" || budget.Amount <= 0 {
+			continue
+		}
+
+		percent := budget.CurrentSpend / budget.Amount * 100
+		if percent < warnAt && percent < blockAt {
+			continue
+		}
+
+		statuses = append(statuses, BudgetStatus{
+			Budget:   budget,
+			Percent:  percent,
+			Blocking: percent >= blockAt,
+		})
+	}
+
+	slices.SortFunc(statuses, func(a, b BudgetStatus) int {
+		if a.Percent > b.Percent {
+			return -1
+		} else if a.Percent < b.Percent {
+			return 1
+		}
+
+		return 0
+	})
+
+	return statuses
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckBudgets(t *testing.T) {
+	budgets := []azapi.Budget{
+		{Name: "under", Category: "Cost", Amount: 100, CurrentSpend: 50},
+		{Name: "warn", Category: "Cost", Amount: 200, CurrentSpend: 170},
+		{Name: "over", Category: "Cost", Amount: 100, CurrentSpend: 120},
+		{Name: "usage", Category: "Usage", Amount: 10, CurrentSpend: 20},
+		{Name: "empty", Category: "Cost", Amount: 0, CurrentSpend: 20},
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		statuses := CheckBudgets(budgets, BudgetOptions{})
+		require.Len(t, statuses, 2)
+		require.Equal(t, "over", statuses[0].Budget.Name)
+		require.Equal(t, float64(120), statuses[0].Percent)
+		require.True(t, statuses[0].Blocking)
+		require.Equal(t, "warn", statuses[1].Budget.Name)
+		require.Equal(t, float64(85), statuses[1].Percent)
+		require.False(t, statuses[1].Blocking)
+	})
+
+	t.Run("Thresholds", func(t *testing.T) {
+		statuses := CheckBudgets(budgets, BudgetOptions{WarnAt: 40, BlockAt: 150})
+		require.Len(t, statuses, 3)
+		for _, status := range statuses {
+			require.False(t, status.Blocking)
+		}
+		require.Equal(t, "under", statuses[2].Budget.Name)
+	})
+
+	t.Run("BlockBelowWarn", func(t *testing.T) {
+		statuses := CheckBudgets(budgets, BudgetOptions{WarnAt: 90, BlockAt: 50})
+		require.Len(t, statuses, 3)
+		for _, status := range statuses {
+			require.True(t, status.Blocking)
+		}
+	})
+}
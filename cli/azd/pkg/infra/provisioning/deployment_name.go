@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// deploymentNameInvalidChars matches the characters not allowed in the name of a deployment
+var deploymentNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._()-]`)
+
+const (
+	// deploymentNameMaxLength is the max length of the name of a deployment in ARM
+	deploymentNameMaxLength = 64
+
+	// DeploymentHistoryLimit is the max number of deployments ARM keeps in the deployment history of a resource group or
+	// a subscription. Deploying to a scope which history is full fails.
+	DeploymentHistoryLimit = 800
+	// deploymentHistoryPruneAt is the number of deployments in the history from which old deployments are pruned
+	deploymentHistoryPruneAt = DeploymentHistoryLimit - 10
+	// deploymentHistoryPruneTo is the number of deployments left in the history after pruning, so pruning doesn't happen
+	// again on the next provision
+	deploymentHistoryPruneTo = DeploymentHistoryLimit - 50
+)
+
+// DeploymentNameTruncation is how the names of deployments longer than the max length are shortened
+type DeploymentNameTruncation string
+
+const (
+	// DeploymentNameTruncateStart removes characters from the start of the name
+	DeploymentNameTruncateStart DeploymentNameTruncation = "start"
+	// DeploymentNameTruncatePrefix removes characters from the end of the prefix, keeping the timestamp and git commit sha
+	DeploymentNameTruncatePrefix DeploymentNameTruncation = "prefix"
+)
+
+// DeploymentNameOptions configures the names of the deployments created when provisioning, ex) dev-1683303710-0123456789ab.
+type DeploymentNameOptions struct {
+	// The start of the names of the deployments. (Default: the name of the environment)
+	Prefix string `yaml:"prefix,omitempty"`
+	// Whether the unix time of the deployment is appended to the name. Without timestamp nor git commit sha, each
+	// deployment replaces the previous one in the deployment history. (Default: true)
+	Timestamp *bool `yaml:"timestamp,omitempty"`
+	// Whether the sha of the git commit being deployed is appended to the name.
+	GitSha bool `yaml:"gitSha,omitempty"`
+	// The max length of the names, up to the ARM limit of 64 characters. (Default: 64)
+	MaxLength int `yaml:"maxLength,omitempty"`
+	// How names longer than the max length are shortened: start or prefix. (Default: start)
+	Truncate DeploymentNameTruncation `yaml:"truncate,omitempty"`
+}
+
+// Validate returns an error when the options can't generate valid deployment names.
+func (o *DeploymentNameOptions) Validate() error {
+	if o.MaxLength < 0 || o.MaxLength > deploymentNameMaxLength {
+		return fmt.Errorf(
+			"invalid infra.deploymentName.maxLength %d, must be between 1 and %d", o.MaxLength, deploymentNameMaxLength)
+	}
+
+	switch o.Truncate {
+	case "", DeploymentNameTruncateStart, DeploymentNameTruncatePrefix:
+	default:
+		return fmt.Errorf(
+			"invalid infra.deploymentName.truncate '%s', must be '%s' or '%s'",
+			o.Truncate, DeploymentNameTruncateStart, DeploymentNameTruncatePrefix)
+	}
+
+	return nil
+}
+
+// DeploymentName returns the name of a deployment of the environment. The git commit sha is left out when it isn't known.
+func (o *DeploymentNameOptions) DeploymentName(envName string, gitSha string, deployedAt time.Time) string {
+	prefix := o.Prefix
+	if prefix == "" {
+		prefix = envName
+	}
+	prefix = deploymentNameInvalidChars.ReplaceAllString(prefix, "-")
+
+	suffix := ""
+	if o.Timestamp == nil || *o.Timestamp {
+		suffix += "-" + strconv.FormatInt(deployedAt.Unix(), 10)
+	}
+	if o.GitSha && gitSha != "" {
+		if len(gitSha) > gitShaLength {
+			gitSha = gitSha[:gitShaLength]
+		}
+		suffix += "-" + gitSha
+	}
+
+	maxLength := o.MaxLength
+	if maxLength == 0 {
+		maxLength = deploymentNameMaxLength
+	}
+
+	name := prefix + suffix
+	if len(name) <= maxLength {
+		return name
+	}
+
+	if o.Truncate == DeploymentNameTruncatePrefix && len(suffix) < maxLength {
+		return prefix[:maxLength-len(suffix)] + suffix
+	}
+
+	return name[len(name)-maxLength:]
+}
+
+// DeploymentsToPrune returns the deployments to remove from a deployment history near the ARM limit, so the next
+// deployments don't fail. Only the completed deployments of azd are pruned, the oldest first, keeping the latest deployment
+// of each environment which azd reads the outputs and resources from. Returns nil when the history isn't near the limit.
+func DeploymentsToPrune(deployments []*azapi.ResourceDeployment) []*azapi.ResourceDeployment {
+	if len(deployments) < deploymentHistoryPruneAt {
+		return nil
+	}
+
+	candidates := []*azapi.ResourceDeployment{}
+	latest := map[string]*azapi.ResourceDeployment{}
+	for _, deployment := range deployments {
+		envName, has := deployment.Tags[azure.TagKeyAzdEnvName]
+		if !has || envName == nil {
+			continue
+		}
+
+		switch deployment.ProvisioningState {
+		case azapi.DeploymentProvisioningStateSucceeded,
+			azapi.DeploymentProvisioningStateFailed,
+			azapi.DeploymentProvisioningStateCanceled:
+		default:
+			continue
+		}
+
+		candidates = append(candidates, deployment)
+		if current, has := latest[*envName]; !has || deployment.Timestamp.After(current.Timestamp) {
+			latest[*envName] = deployment
+		}
+	}
+
+	candidates = slices.DeleteFunc(candidates, func(deployment *azapi.ResourceDeployment) bool {
+		return latest[*deployment.Tags[azure.TagKeyAzdEnvName]] == deployment
+	})
+	slices.SortFunc(candidates, func(a, b *azapi.ResourceDeployment) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	count := len(deployments) - deploymentHistoryPruneTo
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	return candidates[:count]
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DeploymentName(t *testing.T) {
+	deployedAt := time.Unix(1683303710, 0)
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	longPrefix := strings.Repeat("p", 60)
+
+	tests := []struct {
+		name     string
+		options  DeploymentNameOptions
+		expected string
+	}{
+		{"Default", DeploymentNameOptions{}, "dev-1683303710"},
+		{"Prefix", DeploymentNameOptions{Prefix: "my app"}, "my-app-1683303710"},
+		{"GitSha", DeploymentNameOptions{GitSha: true}, "dev-1683303710-0123456789ab"},
+		{"NoTimestamp", DeploymentNameOptions{Timestamp: to.Ptr(false)}, "dev"},
+		{"NoTimestampGitSha", DeploymentNameOptions{Timestamp: to.Ptr(false), GitSha: true}, "dev-0123456789ab"},
+		{"TruncateStart", DeploymentNameOptions{Prefix: longPrefix}, longPrefix[7:] + "-1683303710"},
+		{
+			"TruncatePrefix",
+			DeploymentNameOptions{Prefix: "abcdefghij", MaxLength: 30, GitSha: true, Truncate: DeploymentNameTruncatePrefix},
+			"abcdef-1683303710-0123456789ab",
+		},
+		{
+			"TruncatePrefixSuffixTooLong",
+			DeploymentNameOptions{MaxLength: 10, Truncate: DeploymentNameTruncatePrefix},
+			"1683303710",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.options.Validate())
+
+			name := tt.options.DeploymentName("dev", sha, deployedAt)
+			require.Equal(t, tt.expected, name)
+			require.LessOrEqual(t, len(name), deploymentNameMaxLength)
+		})
+	}
+
+	t.Run("GitShaUnknown", func(t *testing.T) {
+		options := DeploymentNameOptions{GitSha: true}
+		require.Equal(t, "dev-1683303710", options.DeploymentName("dev", "", deployedAt))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		require.ErrorContains(t, (&DeploymentNameOptions{MaxLength: 65}).Validate(), "maxLength")
+		require.ErrorContains(t, (&DeploymentNameOptions{Truncate: "end"}).Validate(), "truncate 'end'")
+	})
+}
+
+func Test_DeploymentsToPrune(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newDeployments := func(count int) []*azapi.ResourceDeployment {
+		deployments := []*azapi.ResourceDeployment{}
+		for i := range count {
+			env := "dev"
+			if i%2 == 1 {
+				env = "prod"
+			}
+
+			deployments = append(deployments, &azapi.ResourceDeployment{
+				Name:              fmt.Sprintf("%s-%d", env, i),
+				Tags:              map[string]*string{azure.TagKeyAzdEnvName: to.Ptr(env)},
+				ProvisioningState: azapi.DeploymentProvisioningStateSucceeded,
+				Timestamp:         start.Add(time.Duration(i) * time.Hour),
+			})
+		}
+
+		return deployments
+	}
+
+	t.Run("NotNearLimit", func(t *testing.T) {
+		require.Empty(t, DeploymentsToPrune(newDeployments(deploymentHistoryPruneAt-1)))
+	})
+
+	t.Run("OldestFirst", func(t *testing.T) {
+		deployments := newDeployments(DeploymentHistoryLimit)
+		// Deployments not created by azd, or still running, are kept
+		deployments[0].Tags = nil
+		deployments[1].ProvisioningState = azapi.DeploymentProvisioningStateRunning
+
+		prune := DeploymentsToPrune(deployments)
+		require.Len(t, prune, DeploymentHistoryLimit-deploymentHistoryPruneTo)
+		require.Equal(t, "dev-2", prune[0].Name)
+		require.Equal(t, "prod-3", prune[1].Name)
+	})
+
+	t.Run("KeepsLatestOfEachEnvironment", func(t *testing.T) {
+		deployments := newDeployments(DeploymentHistoryLimit)
+		for _, deployment := range deployments[:len(deployments)-2] {
+			deployment.Tags = nil
+		}
+
+		require.Empty(t, DeploymentsToPrune(deployments))
+	})
+}
@@ -0,0 +1,343 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package existing contains the implementation of the provisioning provider used by projects which infrastructure is
+// owned and deployed outside of azd. The provider doesn't deploy anything: it validates the existing resources mapped to
+// the services of the project and records them in the environment, so that the services can be deployed with azd.
+package existing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+)
+
+// The properties, set for each service as SERVICE_<NAME>_<PROPERTY>, describing the existing resource of the service.
+const (
+	resourceIdProperty    = "RESOURCE_ID"
+	resourceNameProperty  = "NAME"
+	resourceGroupProperty = "RESOURCE_GROUP"
+)
+
+type ExistingProvider struct {
+	envManager      environment.Manager
+	env             *environment.Environment
+	resourceService *azapi.ResourceService
+	console         input.Console
+	prompters       prompt.Prompter
+	options         provisioning.Options
+}
+
+// Name gets the name of the infra provider
+func (p *ExistingProvider) Name() string {
+	return "Existing"
+}
+
+func (p *ExistingProvider) Initialize(ctx context.Context, projectPath string, options provisioning.Options) error {
+	p.options = options
+
+	if len(options.Resources) == 0 {
+		return errors.New(
+			"the 'existing' provider requires the resources of the services to be set in the 'infra.resources' section " +
+				"of azure.yaml")
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(options.Resources)) {
+		resource := options.Resources[name]
+		if resource.Id == "" && len(resource.Tags) == 0 {
+			return fmt.Errorf("the resource of service '%s' must set either an 'id' or 'tags'", name)
+		}
+
+		if resource.Id != "" {
+			if _, err := arm.ParseResourceID(resource.Id); err != nil {
+				return fmt.Errorf("invalid resource id '%s' for service '%s': %w", resource.Id, name, err)
+			}
+		}
+	}
+
+	return p.EnsureEnv(ctx)
+}
+
+// EnsureEnv ensures that the environment has the subscription of the existing resources, prompting the user when it
+// isn't set. Nothing is deployed by the provider, so the location isn't required.
+func (p *ExistingProvider) EnsureEnv(ctx context.Context) error {
+	return provisioning.EnsureSubscription(ctx, p.envManager, p.env, p.prompters)
+}
+
+// State resolves the existing resources, which make up the current state of the infrastructure.
+func (p *ExistingProvider) State(
+	ctx context.Context,
+	options *provisioning.StateOptions,
+) (*provisioning.StateResult, error) {
+	resources, err := p.resolveResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := provisioning.State{
+		Outputs:   outputs(resources),
+		Resources: make([]provisioning.Resource, 0, len(resources)),
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(resources)) {
+		state.Resources = append(state.Resources, provisioning.Resource{Id: resources[name].Id})
+	}
+
+	return &provisioning.StateResult{
+		State: &state,
+	}, nil
+}
+
+// Deploy validates the existing resources and records them as outputs, without deploying anything.
+func (p *ExistingProvider) Deploy(ctx context.Context) (*provisioning.DeployResult, error) {
+	p.console.ShowSpinner(ctx, "Validating existing resources", input.Step)
+
+	resources, err := p.resolveResources(ctx)
+	if err != nil {
+		p.console.StopSpinner(ctx, "Validating existing resources", input.StepFailed)
+		return nil, err
+	}
+
+	p.console.StopSpinner(ctx, "Validating existing resources", input.StepDone)
+
+	return &provisioning.DeployResult{
+		Deployment: &provisioning.Deployment{
+			Parameters: map[string]provisioning.InputParameter{},
+			Outputs:    outputs(resources),
+		},
+	}, nil
+}
+
+// Preview reports the existing resources as unchanged, since the provider never changes them.
+func (p *ExistingProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
+	resources, err := p.resolveResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*provisioning.DeploymentPreviewChange, 0, len(resources))
+	for _, name := range slices.Sorted(maps.Keys(resources)) {
+		resource := resources[name]
+		changes = append(changes, &provisioning.DeploymentPreviewChange{
+			ChangeType:   provisioning.ChangeTypeNoChange,
+			ResourceId:   provisioning.Resource{Id: resource.Id},
+			ResourceType: resource.Type,
+			Name:         resource.Name,
+		})
+	}
+
+	return &provisioning.DeployPreviewResult{
+		Preview: &provisioning.DeploymentPreview{
+			Status: "Completed",
+			Properties: &provisioning.DeploymentPreviewProperties{
+				Changes: changes,
+			},
+		},
+	}, nil
+}
+
+// Destroy never deletes the existing resources, which are owned outside of azd. Only the values recorded in the
+// environment are invalidated.
+func (p *ExistingProvider) Destroy(
+	ctx context.Context,
+	options provisioning.DestroyOptions,
+) (*provisioning.DestroyResult, error) {
+	p.console.MessageUxItem(
+		ctx,
+		&ux.WarningMessage{
+			Description: "Resources adopted with the 'existing' provider are owned outside of azd and aren't deleted.",
+		},
+	)
+
+	invalidatedEnvKeys := []string{environment.ResourceGroupEnvVarName}
+
+	for _, name := range slices.Sorted(maps.Keys(p.options.Resources)) {
+		for _, property := range []string{resourceIdProperty, resourceNameProperty, resourceGroupProperty} {
+			invalidatedEnvKeys = append(invalidatedEnvKeys, serviceEnvKey(name, property))
+		}
+	}
+
+	return &provisioning.DestroyResult{
+		InvalidatedEnvKeys: invalidatedEnvKeys,
+	}, nil
+}
+
+func (p *ExistingProvider) Parameters(ctx context.Context) ([]provisioning.Parameter, error) {
+	// not supported (no-op)
+	return nil, nil
+}
+
+// resolveResources resolves the existing resource of each service, failing when a resource can't be found.
+func (p *ExistingProvider) resolveResources(ctx context.Context) (map[string]*azapi.ResourceExtended, error) {
+	subscriptionId := p.env.GetSubscriptionId()
+	resources := map[string]*azapi.ResourceExtended{}
+
+	for _, name := range slices.Sorted(maps.Keys(p.options.Resources)) {
+		existing := p.options.Resources[name]
+
+		var resource *azapi.ResourceExtended
+		var err error
+		if existing.Id != "" {
+			resource, err = p.resourceById(ctx, subscriptionId, existing.Id)
+		} else {
+			resource, err = p.resourceByTags(ctx, subscriptionId, existing.Tags)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolving the existing resource of service '%s': %w", name, err)
+		}
+
+		resources[name] = resource
+	}
+
+	return resources, nil
+}
+
+func (p *ExistingProvider) resourceById(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+) (*azapi.ResourceExtended, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource id '%s': %w", resourceId, err)
+	}
+
+	if !strings.EqualFold(id.SubscriptionID, subscriptionId) {
+		return nil, fmt.Errorf(
+			"resource '%s' isn't in the subscription of the environment '%s'", resourceId, subscriptionId)
+	}
+
+	filter := fmt.Sprintf("name eq '%s'", id.Name)
+	resources, err := p.resourceService.ListResourceGroupResources(
+		ctx,
+		id.SubscriptionID,
+		id.ResourceGroupName,
+		&azapi.ListResourceGroupResourcesOptions{
+			Filter: &filter,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		if strings.EqualFold(resource.Id, id.String()) {
+			return resource, nil
+		}
+	}
+
+	return nil, fmt.Errorf("resource '%s' doesn't exist", resourceId)
+}
+
+func (p *ExistingProvider) resourceByTags(
+	ctx context.Context,
+	subscriptionId string,
+	tags map[string]string,
+) (*azapi.ResourceExtended, error) {
+	tagNames := slices.Sorted(maps.Keys(tags))
+
+	// Resources can only be filtered by a single tag, the other tags are matched on the results
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", tagNames[0], tags[tagNames[0]])
+	resources, err := p.resourceService.ListSubscriptionResources(
+		ctx,
+		subscriptionId,
+		&armresources.ClientListOptions{
+			Filter: &filter,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := slices.DeleteFunc(resources, func(resource *azapi.ResourceExtended) bool {
+		for _, tagName := range tagNames {
+			value, has := resource.Tags[tagName]
+			if !has || value == nil || *value != tags[tagName] {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	tagPairs := make([]string, 0, len(tagNames))
+	for _, tagName := range tagNames {
+		tagPairs = append(tagPairs, fmt.Sprintf("'%s: %s'", tagName, tags[tagName]))
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unable to find a resource tagged with %s", strings.Join(tagPairs, ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf(
+			"expecting only '1' resource tagged with %s, but found '%d'", strings.Join(tagPairs, ", "), len(matches))
+	}
+}
+
+// outputs returns the outputs recording the resources. AZURE_RESOURCE_GROUP is only set when all the resources are in
+// the same resource group.
+func outputs(resources map[string]*azapi.ResourceExtended) map[string]provisioning.OutputParameter {
+	outputs := map[string]provisioning.OutputParameter{}
+	resourceGroups := map[string]string{}
+	for name, resource := range resources {
+		resourceGroup := ""
+		if id, err := arm.ParseResourceID(resource.Id); err == nil {
+			resourceGroup = id.ResourceGroupName
+			resourceGroups[strings.ToLower(resourceGroup)] = resourceGroup
+		}
+
+		outputs[serviceEnvKey(name, resourceIdProperty)] = stringOutput(resource.Id)
+		outputs[serviceEnvKey(name, resourceNameProperty)] = stringOutput(resource.Name)
+		outputs[serviceEnvKey(name, resourceGroupProperty)] = stringOutput(resourceGroup)
+	}
+
+	if len(resourceGroups) == 1 {
+		for _, resourceGroup := range resourceGroups {
+			outputs[environment.ResourceGroupEnvVarName] = stringOutput(resourceGroup)
+		}
+	}
+
+	return outputs
+}
+
+func serviceEnvKey(serviceName string, property string) string {
+	return fmt.Sprintf("SERVICE_%s_%s", environment.Key(serviceName), property)
+}
+
+func stringOutput(value string) provisioning.OutputParameter {
+	return provisioning.OutputParameter{
+		Type:  provisioning.ParameterTypeString,
+		Value: value,
+	}
+}
+
+// NewExistingProvider creates a new instance of the provider adopting existing resources.
+func NewExistingProvider(
+	envManager environment.Manager,
+	env *environment.Environment,
+	resourceService *azapi.ResourceService,
+	console input.Console,
+	prompters prompt.Prompter,
+) provisioning.Provider {
+	return &ExistingProvider{
+		envManager:      envManager,
+		env:             env,
+		resourceService: resourceService,
+		console:         console,
+		prompters:       prompters,
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package existing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	apiResourceId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RG/providers/Microsoft.App/containerApps/api"
+	webResourceId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RG/providers/Microsoft.Web/sites/web"
+)
+
+func TestExistingProvider_Deploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	provider := newTestProvider(t, mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/resourceGroups/RG/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"id": apiResourceId, "name": "api", "type": "Microsoft.App/containerApps", "location": "eastus2"},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/subscriptions/SUBSCRIPTION_ID/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"id": webResourceId, "name": "web", "type": "Microsoft.Web/sites", "location": "eastus2",
+					"tags": map[string]string{"team": "web", "env": "prod"}},
+				{"id": webResourceId + "-dev", "name": "web-dev", "type": "Microsoft.Web/sites", "location": "eastus2",
+					"tags": map[string]string{"team": "web", "env": "dev"}},
+			},
+		})
+	})
+
+	err := provider.Initialize(*mockContext.Context, "", provisioning.Options{
+		Provider: provisioning.Existing,
+		Resources: map[string]provisioning.ExistingResource{
+			"api": {Id: apiResourceId},
+			"web": {Tags: map[string]string{"team": "web", "env": "prod"}},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := provider.Deploy(*mockContext.Context)
+	require.NoError(t, err)
+
+	outputs := map[string]any{}
+	for key, output := range result.Deployment.Outputs {
+		outputs[key] = output.Value
+	}
+
+	require.Equal(t, map[string]any{
+		"AZURE_RESOURCE_GROUP":       "RG",
+		"SERVICE_API_RESOURCE_ID":    apiResourceId,
+		"SERVICE_API_NAME":           "api",
+		"SERVICE_API_RESOURCE_GROUP": "RG",
+		"SERVICE_WEB_RESOURCE_ID":    webResourceId,
+		"SERVICE_WEB_NAME":           "web",
+		"SERVICE_WEB_RESOURCE_GROUP": "RG",
+	}, outputs)
+
+	destroyResult, err := provider.Destroy(*mockContext.Context, provisioning.NewDestroyOptions(true, true))
+	require.NoError(t, err)
+	require.Contains(t, destroyResult.InvalidatedEnvKeys, "SERVICE_WEB_RESOURCE_ID")
+}
+
+func TestExistingProvider_Errors(t *testing.T) {
+	t.Run("NoResources", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := newTestProvider(t, mockContext)
+
+		err := provider.Initialize(*mockContext.Context, "", provisioning.Options{Provider: provisioning.Existing})
+		require.ErrorContains(t, err, "infra.resources")
+	})
+
+	t.Run("InvalidResourceId", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := newTestProvider(t, mockContext)
+
+		err := provider.Initialize(*mockContext.Context, "", provisioning.Options{
+			Provider:  provisioning.Existing,
+			Resources: map[string]provisioning.ExistingResource{"api": {Id: "not-an-id"}},
+		})
+		require.ErrorContains(t, err, "invalid resource id")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := newTestProvider(t, mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasSuffix(request.URL.Path, "/resourceGroups/RG/resources")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value": []map[string]any{},
+			})
+		})
+
+		err := provider.Initialize(*mockContext.Context, "", provisioning.Options{
+			Provider:  provisioning.Existing,
+			Resources: map[string]provisioning.ExistingResource{"api": {Id: apiResourceId}},
+		})
+		require.NoError(t, err)
+
+		_, err = provider.Deploy(*mockContext.Context)
+		require.ErrorContains(t, err, "doesn't exist")
+	})
+
+	t.Run("OtherSubscription", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := newTestProvider(t, mockContext)
+
+		err := provider.Initialize(*mockContext.Context, "", provisioning.Options{
+			Provider: provisioning.Existing,
+			Resources: map[string]provisioning.ExistingResource{
+				"api": {Id: strings.Replace(apiResourceId, "SUBSCRIPTION_ID", "OTHER_SUBSCRIPTION_ID", 1)},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = provider.Deploy(*mockContext.Context)
+		require.ErrorContains(t, err, "isn't in the subscription of the environment")
+	})
+}
+
+func newTestProvider(t *testing.T, mockContext *mocks.MockContext) provisioning.Provider {
+	env := environment.NewWithValues("test-env", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	return NewExistingProvider(
+		envManager,
+		env,
+		azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		mockContext.Console,
+		nil,
+	)
+}
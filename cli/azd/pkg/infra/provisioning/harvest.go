@@ -0,0 +1,119 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/tidwall/gjson"
+)
+
+// HarvestOptions reads a property of an existing resource, ex) the endpoint of a shared App Configuration store, into an
+// output of the environment after provisioning.
+type HarvestOptions struct {
+	// The name of the environment output the value is written to
+	Name string `yaml:"name"`
+	// The resource id of the resource, which supports environment variable substitution
+	ResourceId osutil.ExpandableString `yaml:"resourceId"`
+	// The path of the property in the resource, ex) properties.endpoint
+	Property string `yaml:"property"`
+	// The API version used to read the resource. (Default: the latest stable API version of the resource type)
+	ApiVersion string `yaml:"apiVersion,omitempty"`
+}
+
+func (h HarvestOptions) validate() error {
+	if h.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if h.ResourceId.Empty() {
+		return fmt.Errorf("resourceId is required for '%s'", h.Name)
+	}
+
+	if h.Property == "" {
+		return fmt.Errorf("property is required for '%s'", h.Name)
+	}
+
+	return nil
+}
+
+// harvest reads the properties of the existing resources configured in 'infra.harvest' into outputs of the environment.
+func (m *Manager) harvest(ctx context.Context) error {
+	if len(m.options.Harvest) == 0 {
+		return nil
+	}
+
+	var resourceService *azapi.ResourceService
+	if err := m.serviceLocator.Resolve(&resourceService); err != nil {
+		return fmt.Errorf("resolving resource service: %w", err)
+	}
+
+	outputs := map[string]OutputParameter{}
+	for _, options := range m.options.Harvest {
+		output, err := harvestOutput(ctx, resourceService, options, m.env.Getenv)
+		if err != nil {
+			return fmt.Errorf("harvesting infra output: %w", err)
+		}
+
+		outputs[options.Name] = output
+	}
+
+	return m.UpdateEnvironment(ctx, outputs)
+}
+
+// harvestOutput reads the property of the resource as an output parameter. Objects and arrays are kept as is, other
+// values are read as strings.
+func harvestOutput(
+	ctx context.Context,
+	resourceService *azapi.ResourceService,
+	options HarvestOptions,
+	getenv func(string) string,
+) (OutputParameter, error) {
+	if err := options.validate(); err != nil {
+		return OutputParameter{}, err
+	}
+
+	resourceId, err := options.ResourceId.Envsubst(getenv)
+	if err != nil {
+		return OutputParameter{}, fmt.Errorf("evaluating resourceId of '%s': %w", options.Name, err)
+	}
+
+	parsedId, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return OutputParameter{}, fmt.Errorf("parsing resourceId '%s' of '%s': %w", resourceId, options.Name, err)
+	}
+
+	apiVersion := options.ApiVersion
+	if apiVersion == "" {
+		apiVersion, err = resourceService.ResolveApiVersion(ctx, parsedId.SubscriptionID, parsedId.ResourceType)
+		if err != nil {
+			return OutputParameter{}, err
+		}
+	}
+
+	log.Printf("harvesting '%s' of resource '%s' into '%s'", options.Property, resourceId, options.Name)
+	armResource, err := resourceService.GetRawResource(ctx, *parsedId, apiVersion)
+	if err != nil {
+		return OutputParameter{}, fmt.Errorf("getting resource %s: %w", resourceId, err)
+	}
+
+	result := gjson.Get(armResource, options.Property)
+	switch {
+	case !result.Exists():
+		return OutputParameter{}, fmt.Errorf(
+			"property '%s' of '%s' not found in resource %s", options.Property, options.Name, resourceId)
+	case result.IsObject():
+		return OutputParameter{Type: ParameterTypeObject, Value: result.Value()}, nil
+	case result.IsArray():
+		return OutputParameter{Type: ParameterTypeArray, Value: result.Value()}, nil
+	default:
+		return OutputParameter{Type: ParameterTypeString, Value: result.String()}, nil
+	}
+}
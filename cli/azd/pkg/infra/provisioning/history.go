@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrHistoryNotSupported = errors.New("deployment history is not supported by the provisioning provider")
+
+// DeploymentHistoryEntry describes a single previous deployment of an environment.
+type DeploymentHistoryEntry struct {
+	// The provider specific ID of the deployment (ex: the ARM deployment name or the ADE operation ID)
+	Id string `json:"id"`
+	// The time of the deployment as reported by the provider
+	Timestamp time.Time `json:"timestamp"`
+	// The provider reported status of the deployment (ex: Succeeded, Failed)
+	Status string `json:"status"`
+	// The duration of the deployment when known (ex: 1m30s)
+	Duration string `json:"duration,omitempty"`
+	// The correlation ID of the deployment when known
+	CorrelationId string `json:"correlationId,omitempty"`
+	// The principal which started the deployment when known
+	Initiator string `json:"initiator,omitempty"`
+	// The source control commit which was deployed when known
+	GitSha string `json:"gitSha,omitempty"`
+	// The tags associated with the deployment
+	Tags map[string]string `json:"tags,omitempty"`
+	// The outputs of the deployment at that point in time. Not every provider reports historical outputs.
+	Outputs map[string]OutputParameter `json:"outputs,omitempty"`
+	// A link to the deployment in the Azure portal when available
+	PortalUrl string `json:"portalUrl,omitempty"`
+}
+
+// HistoryProvider is implemented by providers able to list the previous deployments of an environment.
+type HistoryProvider interface {
+	// History returns the deployments of the current environment, most recent first.
+	History(ctx context.Context) ([]*DeploymentHistoryEntry, error)
+}
+
+// History returns the deployments of the current environment, most recent first.
+func (m *Manager) History(ctx context.Context) ([]*DeploymentHistoryEntry, error) {
+	historyProvider, ok := m.provider.(HistoryProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", m.provider.Name(), ErrHistoryNotSupported)
+	}
+
+	history, err := historyProvider.History(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deployment history: %w", err)
+	}
+
+	return history, nil
+}
+
+// sourceVersionEnvVars are the variables set by CI systems to the commit being built
+var sourceVersionEnvVars = []string{
+	// GitHub Actions
+	"GITHUB_SHA",
+	// Azure Pipelines
+	"BUILD_SOURCEVERSION",
+	// GitLab CI
+	"CI_COMMIT_SHA",
+	// Circle CI
+	"CIRCLE_SHA1",
+	// BitBucket
+	"BITBUCKET_COMMIT",
+	// Jenkins
+	"GIT_COMMIT",
+}
+
+// SourceVersion returns the source control commit being deployed when running in a known CI system,
+// or an empty string otherwise.
+func SourceVersion() string {
+	for _, envVar := range sourceVersionEnvVars {
+		if value := strings.TrimSpace(os.Getenv(envVar)); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// ParseIsoDuration parses the subset of ISO 8601 durations (ex: PT1M30.5S) reported by Azure deployments.
+func ParseIsoDuration(value string) (time.Duration, error) {
+	remaining, found := strings.CutPrefix(strings.ToUpper(value), "PT")
+	if !found {
+		return 0, fmt.Errorf("unsupported duration '%s'", value)
+	}
+
+	var duration time.Duration
+	for remaining != "" {
+		i := strings.IndexAny(remaining, "HMS")
+		if i <= 0 {
+			return 0, fmt.Errorf("unsupported duration '%s'", value)
+		}
+
+		unit := map[byte]string{'H': "h", 'M': "m", 'S': "s"}[remaining[i]]
+		part, err := time.ParseDuration(remaining[:i] + unit)
+		if err != nil {
+			return 0, fmt.Errorf("unsupported duration '%s': %w", value, err)
+		}
+
+		duration += part
+		remaining = remaining[i+1:]
+	}
+
+	return duration, nil
+}
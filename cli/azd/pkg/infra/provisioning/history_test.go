@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIsoDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"PT30S", 30 * time.Second},
+		{"PT1M30.5S", 90*time.Second + 500*time.Millisecond},
+		{"PT2H5M", 2*time.Hour + 5*time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			duration, err := ParseIsoDuration(tt.value)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, duration)
+		})
+	}
+
+	_, err := ParseIsoDuration("P1D")
+	require.Error(t, err)
+}
+
+func TestSourceVersion(t *testing.T) {
+	for _, envVar := range sourceVersionEnvVars {
+		t.Setenv(envVar, "")
+	}
+	require.Empty(t, SourceVersion())
+
+	t.Setenv("BUILD_SOURCEVERSION", "abc123")
+	require.Equal(t, "abc123", SourceVersion())
+}
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -107,6 +109,16 @@ func (m *Manager) Deploy(ctx context.Context) (*DeployResult, error) {
 		return nil, fmt.Errorf("updating environment with deployment outputs: %w", err)
 	}
 
+	if deployResult.Snapshot != nil {
+		if err := m.saveSnapshot(ctx, deployResult.Snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.harvest(ctx); err != nil {
+		return nil, err
+	}
+
 	infraRoot := m.options.Path
 	if !filepath.IsAbs(infraRoot) {
 		infraRoot = filepath.Join(m.projectPath, m.options.Path)
@@ -314,22 +326,47 @@ func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*Destroy
 	return destroyResult, nil
 }
 
+var (
+	// provisionValueOrigin is the origin of the environment values set from provisioning outputs
+	provisionValueOrigin = environment.ValueOrigin{Source: environment.ValueSourceProvision}
+	// initValueOrigin is the origin of the subscription and location selected for the environment
+	initValueOrigin = environment.ValueOrigin{Source: environment.ValueSourceInit}
+)
+
 func (m *Manager) UpdateEnvironment(
 	ctx context.Context,
 	outputs map[string]OutputParameter,
 ) error {
 	if len(outputs) > 0 {
-		for key, param := range outputs {
+		for _, key := range slices.Sorted(maps.Keys(outputs)) {
+			param := outputs[key]
 			// Complex types marshalled as JSON strings, simple types marshalled as simple strings
+			value := fmt.Sprintf("%v", param.Value)
 			if param.Type == ParameterTypeArray || param.Type == ParameterTypeObject {
 				bytes, err := json.Marshal(param.Value)
 				if err != nil {
 					return fmt.Errorf("invalid value for output parameter '%s' (%s): %w", key, string(param.Type), err)
 				}
-				m.env.DotenvSet(key, string(bytes))
-			} else {
-				m.env.DotenvSet(key, fmt.Sprintf("%v", param.Value))
+				value = string(bytes)
+			}
+
+			// Values set by the user aren't silently overwritten by outputs
+			if userValue, has := userSetValue(m.env, key); has && userValue != value {
+				overwrite, err := m.resolveOutputConflict(ctx, key, userValue)
+				if err != nil {
+					return err
+				}
+
+				if !overwrite {
+					m.console.MessageUxItem(ctx, &ux.WarningMessage{
+						Description: fmt.Sprintf("Keeping the value of '%s' set by the user instead of the "+
+							"provisioning output. Use --force-outputs to use the provisioning output.", key),
+					})
+					continue
+				}
 			}
+
+			m.env.DotenvSetFrom(key, value, provisionValueOrigin)
 		}
 
 		if err := m.envManager.Save(ctx, m.env); err != nil {
@@ -370,7 +407,7 @@ func EnsureSubscriptionAndLocation(
 	// By doing this, we ensure that any command depending on .env values does not need to read system env.
 	// For example, on CI, when running `azd provision`, we want the .env to have the subscription id and location
 	// so that `azd deploy` can just use the values from .env w/o checking os-env again.
-	env.SetSubscriptionId(subId)
+	env.DotenvSetFrom(environment.SubscriptionIdEnvVarName, subId, initValueOrigin)
 	if err := envManager.Save(ctx, env); err != nil {
 		return err
 	}
@@ -391,7 +428,7 @@ func EnsureSubscriptionAndLocation(
 	}
 
 	// Same as before, this make sure the location is persisted in the .env file.
-	env.SetLocation(location)
+	env.DotenvSetFrom(environment.LocationEnvVarName, location, initValueOrigin)
 	return envManager.Save(ctx, env)
 }
 
@@ -414,7 +451,7 @@ func EnsureSubscription(
 	// By doing this, we ensure that any command depending on .env values does not need to read system env.
 	// For example, on CI, when running `azd provision`, we want the .env to have the subscription id and location
 	// so that `azd deploy` can just use the values from .env w/o checking os-env again.
-	env.SetSubscriptionId(subId)
+	env.DotenvSetFrom(environment.SubscriptionIdEnvVarName, subId, initValueOrigin)
 	if err := envManager.Save(ctx, env); err != nil {
 		return err
 	}
@@ -5,6 +5,8 @@ package provisioning_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/test"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
@@ -149,6 +152,89 @@ func TestManagerDeploy(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestManagerUpdateEnvironmentUserSetConflicts(t *testing.T) {
+	userOrigin := environment.ValueOrigin{Source: environment.ValueSourceUser}
+	outputs := map[string]provisioning.OutputParameter{
+		"CONNECTION_STRING": {Type: provisioning.ParameterTypeString, Value: "output-connection"},
+		"ENDPOINT":          {Type: provisioning.ParameterTypeString, Value: "output-endpoint"},
+	}
+
+	newManager := func(mockContext *mocks.MockContext, env *environment.Environment) *provisioning.Manager {
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+		return provisioning.NewManager(
+			mockContext.Container,
+			defaultProvider,
+			envManager,
+			env,
+			mockContext.Console,
+			mockContext.AlphaFeaturesManager,
+			nil,
+			cloud.AzurePublic(),
+		)
+	}
+
+	t.Run("PromptAndRecord", func(t *testing.T) {
+		env := environment.New("test-env")
+		env.DotenvSetFrom("CONNECTION_STRING", "user-connection", userOrigin)
+		env.DotenvSetFrom("ENDPOINT", "user-endpoint", userOrigin)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		prompts := 0
+		mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "conflicts with the value set by user")
+		}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+			prompts++
+			// Keep the connection string, use the output endpoint
+			if strings.Contains(options.Message, "CONNECTION_STRING") {
+				return 0, nil
+			}
+
+			return 1, nil
+		})
+
+		mgr := newManager(mockContext, env)
+		require.NoError(t, mgr.UpdateEnvironment(*mockContext.Context, outputs))
+		require.Equal(t, "user-connection", env.Getenv("CONNECTION_STRING"))
+		require.Equal(t, "output-endpoint", env.Getenv("ENDPOINT"))
+		require.Equal(t, 2, prompts)
+
+		// The decision to keep the value is recorded, the overwritten value isn't a conflict anymore
+		require.NoError(t, mgr.UpdateEnvironment(*mockContext.Context, outputs))
+		require.Equal(t, "user-connection", env.Getenv("CONNECTION_STRING"))
+		require.Equal(t, 2, prompts)
+
+		// The decision doesn't record the value set by the user
+		raw, err := json.Marshal(env.Config.Raw())
+		require.NoError(t, err)
+		require.NotContains(t, string(raw), "user-connection")
+
+		// Changing the value again prompts again
+		env.DotenvSetFrom("CONNECTION_STRING", "new-user-connection", userOrigin)
+		require.NoError(t, mgr.UpdateEnvironment(*mockContext.Context, outputs))
+		require.Equal(t, 3, prompts)
+	})
+
+	t.Run("ForceOutputs", func(t *testing.T) {
+		env := environment.NewWithValues("test-env", map[string]string{
+			"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+			"AZURE_LOCATION":        "eastus2",
+		})
+		env.DotenvSetFrom("CONNECTION_STRING", "user-connection", userOrigin)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		registerContainerDependencies(mockContext, env)
+
+		mgr := newManager(mockContext, env)
+		err := mgr.Initialize(*mockContext.Context, "", provisioning.Options{Provider: "test", ForceOutputs: true})
+		require.NoError(t, err)
+
+		require.NoError(t, mgr.UpdateEnvironment(*mockContext.Context, outputs))
+		require.Equal(t, "output-connection", env.Getenv("CONNECTION_STRING"))
+	})
+}
+
 func TestManagerDestroyWithPositiveConfirmation(t *testing.T) {
 	env := environment.NewWithValues("test-env", map[string]string{
 		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
@@ -277,3 +363,151 @@ func registerContainerDependencies(mockContext *mocks.MockContext, env *environm
 func defaultProvider() (provisioning.ProviderKind, error) {
 	return provisioning.Bicep, nil
 }
+
+func TestManagerDeployHarvest(t *testing.T) {
+	appConfigId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-platform/providers/" +
+		"Microsoft.AppConfiguration/configurationStores/appcs-shared"
+	vnetId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-hub/providers/Microsoft.Network/virtualNetworks/vnet-hub"
+
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+		"AZURE_LOCATION":        "eastus2",
+		"SHARED_APPCONFIG_ID":   appConfigId,
+	})
+
+	mockContext := mocks.NewMockContext(context.Background())
+	registerContainerDependencies(mockContext, env)
+
+	apiVersions := map[string]string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.EqualFold(request.URL.Path, appConfigId)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		apiVersions["appConfig"] = request.URL.Query().Get("api-version")
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"id":       appConfigId,
+			"name":     "appcs-shared",
+			"type":     "Microsoft.AppConfiguration/configurationStores",
+			"location": "eastus2",
+			"properties": map[string]any{
+				"endpoint": "https://appcs-shared.azconfig.io",
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Network")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"namespace": "Microsoft.Network",
+			"resourceTypes": []map[string]any{
+				{
+					"resourceType": "virtualNetworks",
+					"apiVersions":  []string{"2024-07-01-preview", "2024-05-01"},
+				},
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.EqualFold(request.URL.Path, vnetId)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		apiVersions["vnet"] = request.URL.Query().Get("api-version")
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"id":       vnetId,
+			"name":     "vnet-hub",
+			"type":     "Microsoft.Network/virtualNetworks",
+			"location": "eastus2",
+			"properties": map[string]any{
+				"addressSpace": map[string]any{
+					"addressPrefixes": []string{"10.0.0.0/16"},
+				},
+			},
+		})
+	})
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	newManager := func() *provisioning.Manager {
+		return provisioning.NewManager(
+			mockContext.Container,
+			defaultProvider,
+			envManager,
+			env,
+			mockContext.Console,
+			mockContext.AlphaFeaturesManager,
+			nil,
+			cloud.AzurePublic(),
+		)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mgr := newManager()
+		err := mgr.Initialize(*mockContext.Context, "", provisioning.Options{
+			Provider: "test",
+			Harvest: []provisioning.HarvestOptions{
+				{
+					Name:       "APP_CONFIG_ENDPOINT",
+					ResourceId: osutil.NewExpandableString("${SHARED_APPCONFIG_ID}"),
+					Property:   "properties.endpoint",
+					ApiVersion: "2023-03-01",
+				},
+				{
+					Name:       "HUB_VNET_ID",
+					ResourceId: osutil.NewExpandableString(vnetId),
+					Property:   "id",
+				},
+				{
+					Name:       "HUB_VNET_ADDRESS_PREFIXES",
+					ResourceId: osutil.NewExpandableString(vnetId),
+					Property:   "properties.addressSpace.addressPrefixes",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		deployResult, err := mgr.Deploy(*mockContext.Context)
+		require.NoError(t, err)
+		require.NotNil(t, deployResult)
+
+		require.Equal(t, "https://appcs-shared.azconfig.io", env.Getenv("APP_CONFIG_ENDPOINT"))
+		require.Equal(t, vnetId, env.Getenv("HUB_VNET_ID"))
+		require.Equal(t, `["10.0.0.0/16"]`, env.Getenv("HUB_VNET_ADDRESS_PREFIXES"))
+		require.Equal(t, "2023-03-01", apiVersions["appConfig"])
+		require.Equal(t, "2024-05-01", apiVersions["vnet"])
+	})
+
+	t.Run("PropertyNotFound", func(t *testing.T) {
+		mgr := newManager()
+		err := mgr.Initialize(*mockContext.Context, "", provisioning.Options{
+			Provider: "test",
+			Harvest: []provisioning.HarvestOptions{
+				{
+					Name:       "APP_CONFIG_SKU",
+					ResourceId: osutil.NewExpandableString("${SHARED_APPCONFIG_ID}"),
+					Property:   "sku.name",
+					ApiVersion: "2023-03-01",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = mgr.Deploy(*mockContext.Context)
+		require.ErrorContains(t, err, "property 'sku.name' of 'APP_CONFIG_SKU' not found")
+	})
+
+	t.Run("PropertyRequired", func(t *testing.T) {
+		mgr := newManager()
+		err := mgr.Initialize(*mockContext.Context, "", provisioning.Options{
+			Provider: "test",
+			Harvest: []provisioning.HarvestOptions{
+				{
+					Name:       "APP_CONFIG_ENDPOINT",
+					ResourceId: osutil.NewExpandableString("${SHARED_APPCONFIG_ID}"),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = mgr.Deploy(*mockContext.Context)
+		require.ErrorContains(t, err, "property is required for 'APP_CONFIG_ENDPOINT'")
+	})
+}
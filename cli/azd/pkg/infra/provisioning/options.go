@@ -34,6 +34,8 @@ type DestroyOptions struct {
 	force bool
 	// Whether or not to purge any key vaults associated with the deployment
 	purge bool
+	// The ids of the existing resources referenced by the project, which are never deleted
+	existingResourceIds []string
 }
 
 type StateOptions struct {
@@ -59,6 +61,18 @@ func (o *DestroyOptions) Force() bool {
 	return o.force
 }
 
+// ExistingResourceIds returns the ids of the existing resources referenced by the project. The resource groups holding
+// them are kept when the resources of the deployment are deleted.
+func (o *DestroyOptions) ExistingResourceIds() []string {
+	return o.existingResourceIds
+}
+
+// WithExistingResourceIds returns a copy of the options which keeps the given existing resources.
+func (o DestroyOptions) WithExistingResourceIds(resourceIds []string) DestroyOptions {
+	o.existingResourceIds = resourceIds
+	return o
+}
+
 func NewDestroyOptions(force bool, purge bool) DestroyOptions {
 	return DestroyOptions{
 		force: force,
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+var outputConflictPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id: "OUTPUT_CONFLICT",
+	Description: "Which value to use when a provisioning output conflicts with a value you set: " +
+		"'Keep my value' or 'Use the provisioning output'.",
+	Commands: []string{"provision", "up"},
+})
+
+// outputConflictsConfigPath is the environment config section where the decisions taken for outputs conflicting with
+// values set by the user are recorded.
+const outputConflictsConfigPath = "outputConflicts"
+
+// OutputConflictDecision is the decision taken when a provisioning output conflicts with a value set by the user.
+type OutputConflictDecision string
+
+const (
+	// OutputConflictKeep keeps the value set by the user.
+	OutputConflictKeep OutputConflictDecision = "keep"
+	// OutputConflictOverwrite overwrites the value set by the user with the provisioning output.
+	OutputConflictOverwrite OutputConflictDecision = "overwrite"
+)
+
+// outputConflict records the decision taken for the value set by the user. The decision only applies while the user
+// doesn't change the value again. The value, which may be a secret, is recorded as its fingerprint.
+type outputConflict struct {
+	Decision    OutputConflictDecision `json:"decision"`
+	Fingerprint string                 `json:"fingerprint"`
+}
+
+// userSetValue returns the value of key when it was last set by the user, either with 'azd env set' or from a hook.
+func userSetValue(env *environment.Environment, key string) (string, bool) {
+	current, has := env.Dotenv()[key]
+	if !has {
+		return "", false
+	}
+
+	history := env.Provenance(key)
+	if len(history) == 0 {
+		return "", false
+	}
+
+	latest := history[len(history)-1]
+	if !latest.Matches(current) ||
+		(latest.Source != environment.ValueSourceUser && latest.Source != environment.ValueSourceHook) {
+		return "", false
+	}
+
+	return current, true
+}
+
+// resolveOutputConflict decides whether the output for key overwrites userValue, the value set by the user. Decisions
+// recorded for the same user value are reused, otherwise the user is prompted and the decision is recorded. When
+// prompting isn't possible, the value set by the user is kept.
+func (m *Manager) resolveOutputConflict(ctx context.Context, key string, userValue string) (bool, error) {
+	if m.options != nil && m.options.ForceOutputs {
+		return true, nil
+	}
+
+	configPath := outputConflictsConfigPath + "." + key
+	var recorded outputConflict
+	if has, err := m.env.Config.GetSection(configPath, &recorded); err != nil {
+		log.Printf("failed reading output conflict decision for '%s': %v", key, err)
+	} else if has && recorded.Fingerprint == environment.ValueFingerprint(userValue) {
+		return recorded.Decision == OutputConflictOverwrite, nil
+	}
+
+	origin := string(environment.ValueSourceUser)
+	if history := m.env.Provenance(key); len(history) > 0 {
+		origin = history[len(history)-1].ValueOrigin.String()
+	}
+
+	keepOption := "Keep my value"
+	choices := []string{keepOption, "Use the provisioning output"}
+	selection, err := m.console.Select(ctx, input.ConsoleOptions{
+		Id: outputConflictPromptId,
+		Message: fmt.Sprintf("The provisioning output '%s' conflicts with the value set by %s. Which value should be used?",
+			key, origin),
+		Help: "The value you set is kept until you change it again. " +
+			"Use --force-outputs to always use the provisioning outputs.",
+		Options:      choices,
+		DefaultValue: keepOption,
+	})
+	if err != nil {
+		return false, fmt.Errorf("prompting for the value of '%s': %w", key, err)
+	}
+
+	decision := OutputConflictKeep
+	if selection == 1 {
+		decision = OutputConflictOverwrite
+	}
+
+	if err := m.env.Config.Set(configPath, outputConflict{
+		Decision:    decision,
+		Fingerprint: environment.ValueFingerprint(userValue),
+	}); err != nil {
+		return false, fmt.Errorf("recording the decision for '%s': %w", key, err)
+	}
+
+	return decision == OutputConflictOverwrite, nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/sethvargo/go-retry"
+)
+
+// ParametersKeyVaultConfigPath is the environment config path of the resource id of the Key Vault storing the values of
+// the secure parameters prompted for. azd creates the Key Vault when it isn't configured and creating it is enabled.
+const ParametersKeyVaultConfigPath = "infra.parametersKeyVault"
+
+// ErrParametersKeyVaultUnavailable is returned when no Key Vault is configured for the environment, and one isn't
+// created since creating it isn't enabled or the subscription or the location of the environment isn't known.
+var ErrParametersKeyVaultUnavailable = errors.New("no Key Vault is configured to store the secure parameters")
+
+var invalidSecretNameChars = regexp.MustCompile(`[^a-zA-Z0-9-]`)
+
+// ParameterSecrets stores the values of secure parameters, like the @secure() parameters of Bicep templates or the secret
+// parameters of Azure Deployment Environments, in a Key Vault of the environment. Only an akvs:// reference to the secret
+// is persisted in the environment config.
+type ParameterSecrets struct {
+	keyVaultService    keyvault.KeyVaultService
+	resourceService    *azapi.ResourceService
+	entraIdService     entraid.EntraIdService
+	userProfileService *azapi.UserProfileService
+	subResolver        account.SubscriptionTenantResolver
+	// How long to retry writing a secret while the role assignment on a new Key Vault propagates
+	rbacPropagationTimeout time.Duration
+}
+
+// NewParameterSecrets creates a new ParameterSecrets
+func NewParameterSecrets(
+	keyVaultService keyvault.KeyVaultService,
+	resourceService *azapi.ResourceService,
+	entraIdService entraid.EntraIdService,
+	userProfileService *azapi.UserProfileService,
+	subResolver account.SubscriptionTenantResolver,
+) *ParameterSecrets {
+	return &ParameterSecrets{
+		keyVaultService:        keyVaultService,
+		resourceService:        resourceService,
+		entraIdService:         entraIdService,
+		userProfileService:     userProfileService,
+		subResolver:            subResolver,
+		rbacPropagationTimeout: 5 * time.Minute,
+	}
+}
+
+// Store stores the value of the secure parameter in the Key Vault of the environment, and returns the akvs:// reference
+// to persist in the environment config instead of the value. With createVault, the Key Vault is created in the
+// subscription and location of the environment when none is configured, ex) when the project enables
+// infra.parametersKeyVault. The caller is responsible for saving the environment.
+func (s *ParameterSecrets) Store(
+	ctx context.Context,
+	env *environment.Environment,
+	name string,
+	value string,
+	createVault bool,
+) (string, error) {
+	vaultId, err := s.ensureKeyVault(ctx, env, createVault)
+	if err != nil {
+		return "", err
+	}
+
+	secretName := parameterSecretName(name)
+	err = retry.Do(
+		ctx,
+		retry.WithMaxDuration(s.rbacPropagationTimeout, retry.NewConstant(10*time.Second)),
+		func(ctx context.Context) error {
+			err := s.keyVaultService.CreateKeyVaultSecret(ctx, vaultId.SubscriptionID, vaultId.Name, secretName, value)
+
+			// Role assignments on a new Key Vault take a few minutes to be effective
+			var responseErr *azcore.ResponseError
+			if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+				return retry.RetryableError(err)
+			}
+
+			return err
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("storing parameter '%s' in Key Vault '%s': %w", name, vaultId.Name, err)
+	}
+
+	output.AddSecret(value)
+	return keyvault.NewAzureKeyVaultSecret(vaultId.SubscriptionID, vaultId.Name, secretName), nil
+}
+
+// Resolve returns the value of the secret referenced by an akvs:// reference, or the value itself otherwise.
+func (s *ParameterSecrets) Resolve(ctx context.Context, value any) (any, error) {
+	reference, isString := value.(string)
+	if !isString || !keyvault.IsAzureKeyVaultSecret(reference) {
+		return value, nil
+	}
+
+	secret, err := s.keyVaultService.SecretFromAkvs(ctx, reference)
+	if err != nil {
+		return nil, fmt.Errorf("resolving secure parameter from '%s': %w", reference, err)
+	}
+
+	output.AddSecret(secret)
+	return secret, nil
+}
+
+// ensureKeyVault returns the Key Vault configured for the environment, creating it when needed and createVault is set.
+func (s *ParameterSecrets) ensureKeyVault(
+	ctx context.Context,
+	env *environment.Environment,
+	createVault bool,
+) (*arm.ResourceID, error) {
+	if configured, has := env.Config.GetString(ParametersKeyVaultConfigPath); has && configured != "" {
+		vaultId, err := arm.ParseResourceID(configured)
+		if err != nil {
+			return nil, fmt.Errorf("parsing '%s': %w", ParametersKeyVaultConfigPath, err)
+		}
+
+		return vaultId, nil
+	}
+
+	subscriptionId := env.GetSubscriptionId()
+	location := env.GetLocation()
+	if !createVault || subscriptionId == "" || location == "" {
+		return nil, ErrParametersKeyVaultUnavailable
+	}
+
+	tenantId, err := s.subResolver.LookupTenant(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("looking up tenant for subscription: %w", err)
+	}
+
+	// The Key Vault is kept in its own resource group so that it isn't deleted with the resources of the environment,
+	// and the values of the parameters are still available when the environment is provisioned again after 'azd down'
+	resourceGroupName := fmt.Sprintf("rg-%s-secrets", env.Name())
+	vaultName, err := parametersKeyVaultName()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("creating Key Vault '%s' for the secure parameters of environment '%s'", vaultName, env.Name())
+
+	if _, err := s.resourceService.CreateOrUpdateResourceGroup(
+		ctx, subscriptionId, resourceGroupName, location, nil); err != nil {
+		return nil, fmt.Errorf("creating resource group '%s': %w", resourceGroupName, err)
+	}
+
+	vault, err := s.createKeyVault(ctx, tenantId, subscriptionId, resourceGroupName, location, vaultName)
+	if err != nil {
+		// The resource group isn't left behind without the Key Vault, ex) when the user can't assign roles
+		if deleteErr := s.resourceService.DeleteResourceGroup(ctx, subscriptionId, resourceGroupName); deleteErr != nil {
+			log.Printf("deleting resource group '%s': %v", resourceGroupName, deleteErr)
+		}
+
+		return nil, err
+	}
+
+	if err := env.Config.Set(ParametersKeyVaultConfigPath, vault.Id); err != nil {
+		return nil, err
+	}
+
+	return arm.ParseResourceID(vault.Id)
+}
+
+// createKeyVault creates the Key Vault in the resource group and grants the current principal the Key Vault
+// Administrator role on it.
+func (s *ParameterSecrets) createKeyVault(
+	ctx context.Context,
+	tenantId string,
+	subscriptionId string,
+	resourceGroupName string,
+	location string,
+	vaultName string,
+) (*keyvault.Vault, error) {
+	vault, err := s.keyVaultService.CreateVault(ctx, tenantId, subscriptionId, resourceGroupName, location, vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	principalId, err := azureutil.GetCurrentPrincipalId(ctx, s.userProfileService, tenantId)
+	if err != nil {
+		return nil, fmt.Errorf("getting current principal ID: %w", err)
+	}
+
+	err = s.entraIdService.CreateRbac(
+		ctx, subscriptionId, vault.Id, keyvault.RoleIdKeyVaultAdministrator, principalId)
+	if err != nil {
+		return nil, fmt.Errorf("adding Administrator Role on Key Vault '%s': %w", vaultName, err)
+	}
+
+	return &vault, nil
+}
+
+// parameterSecretName returns the name of the Key Vault secret of a parameter, ex) param-adminPassword
+func parameterSecretName(name string) string {
+	return "param-" + invalidSecretNameChars.ReplaceAllString(name, "-")
+}
+
+// parametersKeyVaultName returns a name for the Key Vault created for an environment. Key Vault names are globally
+// unique, including the soft deleted vaults, and limited to 24 characters, so the name is random. The resource id of the
+// vault is persisted in the environment config.
+func parametersKeyVaultName() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating Key Vault name: %w", err)
+	}
+
+	return fmt.Sprintf("kv-azd-%x", suffix), nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeyVaultService struct {
+	keyvault.KeyVaultService
+	secrets map[string]string
+}
+
+func (f *fakeKeyVaultService) CreateKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	secretValue string,
+) error {
+	f.secrets[keyvault.NewAzureKeyVaultSecret(subscriptionId, vaultName, secretName)] = secretValue
+	return nil
+}
+
+func (f *fakeKeyVaultService) SecretFromAkvs(ctx context.Context, akvs string) (string, error) {
+	secret, has := f.secrets[akvs]
+	if !has {
+		return "", fmt.Errorf("secret '%s' not found", akvs)
+	}
+
+	return secret, nil
+}
+
+func Test_ParameterSecrets(t *testing.T) {
+	vaultId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-secrets/providers/Microsoft.KeyVault/vaults/VAULT"
+
+	t.Run("StoreAndResolve", func(t *testing.T) {
+		keyVaultService := &fakeKeyVaultService{secrets: map[string]string{}}
+		parameterSecrets := NewParameterSecrets(keyVaultService, nil, nil, nil, nil)
+
+		env := environment.New("test")
+		require.NoError(t, env.Config.Set(ParametersKeyVaultConfigPath, vaultId))
+
+		reference, err := parameterSecrets.Store(context.Background(), env, "admin_password", "P@ssw0rd", false)
+		require.NoError(t, err)
+		require.Equal(t, "akvs://SUBSCRIPTION_ID/VAULT/param-admin-password", reference)
+
+		value, err := parameterSecrets.Resolve(context.Background(), reference)
+		require.NoError(t, err)
+		require.Equal(t, "P@ssw0rd", value)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		parameterSecrets := NewParameterSecrets(&fakeKeyVaultService{}, nil, nil, nil, nil)
+
+		_, err := parameterSecrets.Store(context.Background(), environment.New("test"), "password", "P@ssw0rd", true)
+		require.ErrorIs(t, err, ErrParametersKeyVaultUnavailable)
+	})
+
+	t.Run("CreateNotEnabled", func(t *testing.T) {
+		// The services creating the Key Vault aren't set, creating it would panic
+		parameterSecrets := NewParameterSecrets(&fakeKeyVaultService{}, nil, nil, nil, nil)
+
+		env := environment.NewWithValues("test", map[string]string{
+			environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			environment.LocationEnvVarName:       "eastus2",
+		})
+		_, err := parameterSecrets.Store(context.Background(), env, "password", "P@ssw0rd", false)
+		require.ErrorIs(t, err, ErrParametersKeyVaultUnavailable)
+	})
+
+	t.Run("ResolveNonReference", func(t *testing.T) {
+		parameterSecrets := NewParameterSecrets(&fakeKeyVaultService{}, nil, nil, nil, nil)
+
+		value, err := parameterSecrets.Resolve(context.Background(), "plain")
+		require.NoError(t, err)
+		require.Equal(t, "plain", value)
+
+		value, err = parameterSecrets.Resolve(context.Background(), 42)
+		require.NoError(t, err)
+		require.Equal(t, 42, value)
+	})
+}
+
+func Test_ParametersKeyVaultName(t *testing.T) {
+	name, err := parametersKeyVaultName()
+	require.NoError(t, err)
+	require.Regexp(t, `^kv-azd-[0-9a-f]{16}$`, name)
+
+	// A new name is used each time, ex) when a soft deleted vault has the name of the previous one
+	other, err := parametersKeyVaultName()
+	require.NoError(t, err)
+	require.NotEqual(t, name, other)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+var ErrParametersExportNotSupported = errors.New("exporting parameters is not supported by the provisioning provider")
+
+// ParametersFile is the parameters of an environment in the native format of the IaC tool of a provider, ex) a
+// .bicepparam file, so the tool can be run directly with the same values as azd.
+type ParametersFile struct {
+	// The default name of the file, ex) main.dev.bicepparam
+	Name string
+	// The contents of the file. The values of secure parameters are never inlined, the file references them instead.
+	Contents []byte
+}
+
+// ParametersExporter is implemented by providers able to render the parameters of an environment as a parameters file
+// of their IaC tool.
+type ParametersExporter interface {
+	// ExportParameters renders the effective parameters of the current environment, as a file to write in dir. Paths in
+	// the file, ex) the template of a .bicepparam file, are relative to dir.
+	ExportParameters(ctx context.Context, dir string) (*ParametersFile, error)
+}
+
+// ExportParameters renders the effective parameters of the current environment, as a file to write in dir.
+func (m *Manager) ExportParameters(ctx context.Context, dir string) (*ParametersFile, error) {
+	exporter, ok := m.provider.(ParametersExporter)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", m.provider.Name(), ErrParametersExportNotSupported)
+	}
+
+	file, err := exporter.ExportParameters(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("exporting parameters: %w", err)
+	}
+
+	return file, nil
+}
+
+// InfraPath returns the absolute path of the infrastructure folder of the project.
+func (m *Manager) InfraPath() string {
+	if filepath.IsAbs(m.options.Path) {
+		return m.options.Path
+	}
+
+	return filepath.Join(m.projectPath, m.options.Path)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+)
+
+// PolicyViolation is a resource of a deployment which creation or update would be denied by an Azure Policy.
+type PolicyViolation struct {
+	ResourceId   string
+	ResourceType string
+	ResourceName string
+	Policy       azapi.PolicyEvaluation
+}
+
+// DenyingPolicies returns the policy evaluations which would deny the creation or update of a resource.
+func DenyingPolicies(evaluations []azapi.PolicyEvaluation) []azapi.PolicyEvaluation {
+	denying := []azapi.PolicyEvaluation{}
+	for _, evaluation := range evaluations {
+		if strings.EqualFold(evaluation.EvaluationResult, "NonCompliant") &&
+			strings.EqualFold(evaluation.Effect, "Deny") {
+			denying = append(denying, evaluation)
+		}
+	}
+
+	return denying
+}
+
+// PolicyViolationsError is the error of a deployment which resources would be denied by Azure Policies.
+type PolicyViolationsError struct {
+	Violations []PolicyViolation
+}
+
+func (e *PolicyViolationsError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf(
+		"the deployment would be denied by Azure Policy, %d resource(s) aren't compliant:", len(e.Violations)))
+
+	for _, violation := range e.Violations {
+		policy := fmt.Sprintf("'%s'", path.Base(violation.Policy.PolicyDefinitionId))
+		if violation.Policy.PolicySetDefinitionId != "" {
+			policy += fmt.Sprintf(" (initiative '%s')", path.Base(violation.Policy.PolicySetDefinitionId))
+		}
+
+		builder.WriteString(fmt.Sprintf(
+			"\n- %s '%s': denied by policy %s, assigned by '%s'",
+			violation.ResourceType,
+			violation.ResourceName,
+			policy,
+			violation.Policy.PolicyAssignmentId,
+		))
+	}
+
+	return builder.String()
+}
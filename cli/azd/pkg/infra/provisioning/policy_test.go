@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DenyingPolicies(t *testing.T) {
+	deny := azapi.PolicyEvaluation{PolicyDefinitionId: "deny", EvaluationResult: "NonCompliant", Effect: "Deny"}
+
+	require.Equal(t, []azapi.PolicyEvaluation{deny}, DenyingPolicies([]azapi.PolicyEvaluation{
+		deny,
+		{PolicyDefinitionId: "audit", EvaluationResult: "NonCompliant", Effect: "Audit"},
+		{PolicyDefinitionId: "compliant", EvaluationResult: "Compliant", Effect: "Deny"},
+	}))
+}
+
+func Test_PolicyViolationsError(t *testing.T) {
+	err := &PolicyViolationsError{
+		Violations: []PolicyViolation{
+			{
+				ResourceType: "Microsoft.Storage/storageAccounts",
+				ResourceName: "stdev",
+				Policy: azapi.PolicyEvaluation{
+					PolicyDefinitionId:    "/providers/Microsoft.Authorization/policyDefinitions/allowed-locations",
+					PolicySetDefinitionId: "/providers/Microsoft.Authorization/policySetDefinitions/baseline",
+					PolicyAssignmentId:    "/subscriptions/SUB/providers/Microsoft.Authorization/policyAssignments/baseline",
+				},
+			},
+		},
+	}
+
+	require.Equal(t,
+		"the deployment would be denied by Azure Policy, 1 resource(s) aren't compliant:\n"+
+			"- Microsoft.Storage/storageAccounts 'stdev': denied by policy 'allowed-locations' (initiative 'baseline'), "+
+			"assigned by '/subscriptions/SUB/providers/Microsoft.Authorization/policyAssignments/baseline'",
+		err.Error())
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The folder, in the infra folder of the project, where presets are stored
+const presetsDirName = "presets"
+
+// ErrPresetNotFound is returned when a preset doesn't exist
+var ErrPresetNotFound = errors.New("preset not found")
+
+// presetParametersConfigPaths are the paths, in the config of environments, where providers store the values of the
+// parameters set by the user. Presets are only supported by these providers.
+var presetParametersConfigPaths = map[ProviderKind]string{
+	Bicep: "infra.parameters",
+	// The devcenter provider, which kind is defined by the devcenter package
+	ProviderKind("devcenter"): "provision.parameters",
+}
+
+var presetNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-_]*$`)
+
+// Preset is a named set of parameter values, stored at the project level so that teams can share standard configurations
+// of their infrastructure, like "small", "demo" or "load-test".
+type Preset struct {
+	// The provider the parameters are for
+	Provider ProviderKind `json:"provider"`
+	// The values of the parameters
+	Parameters map[string]any `json:"parameters"`
+}
+
+// ResolveProviderKind returns the kind of the provider used by options, which is the default provider when options
+// doesn't set one.
+func ResolveProviderKind(options Options, defaultProvider DefaultProviderResolver) (ProviderKind, error) {
+	kind := options.Provider
+	if kind == NotSpecified {
+		var err error
+		if kind, err = defaultProvider(); err != nil {
+			return NotSpecified, err
+		}
+	}
+
+	// Bicep is the provider used when none is specified
+	if kind == NotSpecified {
+		kind = Bicep
+	}
+
+	return kind, nil
+}
+
+// NewPresetFromEnvironment creates a preset from the parameter values stored in the config of env.
+func NewPresetFromEnvironment(env *environment.Environment, kind ProviderKind) (*Preset, error) {
+	configPath, err := presetParametersConfigPath(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, has := env.Config.GetMap(configPath)
+	if !has || len(parameters) == 0 {
+		return nil, fmt.Errorf("environment '%s' doesn't have any parameter value to export", env.Name())
+	}
+
+	return &Preset{
+		Provider:   kind,
+		Parameters: parameters,
+	}, nil
+}
+
+// Apply stores the parameter values of the preset in the config of env, replacing existing values.
+func (p *Preset) Apply(env *environment.Environment, kind ProviderKind) error {
+	if p.Provider != kind {
+		return fmt.Errorf("the preset is for the '%s' provider but the project uses the '%s' provider", p.Provider, kind)
+	}
+
+	configPath, err := presetParametersConfigPath(kind)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(p.Parameters)) {
+		if err := env.Config.Set(fmt.Sprintf("%s.%s", configPath, name), p.Parameters[name]); err != nil {
+			return fmt.Errorf("setting parameter '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// PresetPath returns the path of the preset with the given name, in the presets folder of the infra folder of the project.
+func PresetPath(projectPath string, options Options, name string) (string, error) {
+	if !presetNameRegex.MatchString(name) {
+		return "", fmt.Errorf(
+			"invalid preset name '%s', names can only contain letters, digits, '-' and '_'", name)
+	}
+
+	infraPath := options.Path
+	if infraPath == "" {
+		infraPath = defaultPath
+	}
+
+	if !filepath.IsAbs(infraPath) {
+		infraPath = filepath.Join(projectPath, infraPath)
+	}
+
+	return filepath.Join(infraPath, presetsDirName, name+".json"), nil
+}
+
+// LoadPreset loads the preset with the given name, returning ErrPresetNotFound when it doesn't exist.
+func LoadPreset(projectPath string, options Options, name string) (*Preset, error) {
+	presetPath, err := PresetPath(projectPath, options, name)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(presetPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("'%s': %w", name, ErrPresetNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading preset '%s': %w", name, err)
+	}
+
+	var preset Preset
+	if err := json.Unmarshal(contents, &preset); err != nil {
+		return nil, fmt.Errorf("parsing preset '%s': %w", name, err)
+	}
+
+	return &preset, nil
+}
+
+// SavePreset saves the preset with the given name, replacing the existing one.
+func SavePreset(projectPath string, options Options, name string, preset *Preset) (string, error) {
+	presetPath, err := PresetPath(projectPath, options, name)
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := json.MarshalIndent(preset, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshalling preset: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(presetPath), osutil.PermissionDirectory); err != nil {
+		return "", fmt.Errorf("creating presets folder: %w", err)
+	}
+
+	if err := os.WriteFile(presetPath, append(contents, '\n'), osutil.PermissionFile); err != nil {
+		return "", fmt.Errorf("writing preset '%s': %w", name, err)
+	}
+
+	return presetPath, nil
+}
+
+func presetParametersConfigPath(kind ProviderKind) (string, error) {
+	configPath, has := presetParametersConfigPaths[kind]
+	if !has {
+		supported := []string{}
+		for supportedKind := range presetParametersConfigPaths {
+			supported = append(supported, string(supportedKind))
+		}
+		slices.Sort(supported)
+
+		return "", fmt.Errorf(
+			"presets aren't supported by the '%s' provider, supported providers are: %s",
+			kind,
+			strings.Join(supported, ", "),
+		)
+	}
+
+	return configPath, nil
+}
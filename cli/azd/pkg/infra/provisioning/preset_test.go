@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset_ExportAndApply(t *testing.T) {
+	projectPath := t.TempDir()
+	options := Options{}
+
+	source := environment.New("source")
+	require.NoError(t, source.Config.Set("infra.parameters.skuName", "B1"))
+	require.NoError(t, source.Config.Set("infra.parameters.replicas", 2))
+
+	preset, err := NewPresetFromEnvironment(source, Bicep)
+	require.NoError(t, err)
+
+	presetPath, err := SavePreset(projectPath, options, "small", preset)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(projectPath, "infra", "presets", "small.json"), presetPath)
+
+	loaded, err := LoadPreset(projectPath, options, "small")
+	require.NoError(t, err)
+	require.Equal(t, Bicep, loaded.Provider)
+
+	target := environment.New("target")
+	require.NoError(t, target.Config.Set("infra.parameters.skuName", "P1v3"))
+	require.NoError(t, loaded.Apply(target, Bicep))
+
+	skuName, _ := target.Config.GetString("infra.parameters.skuName")
+	require.Equal(t, "B1", skuName)
+	replicas, has := target.Config.Get("infra.parameters.replicas")
+	require.True(t, has)
+	require.EqualValues(t, 2, replicas)
+
+	// Presets only apply to the provider they were exported from
+	require.Error(t, loaded.Apply(target, ProviderKind("devcenter")))
+}
+
+func TestPreset_Errors(t *testing.T) {
+	projectPath := t.TempDir()
+
+	_, err := LoadPreset(projectPath, Options{}, "missing")
+	require.ErrorIs(t, err, ErrPresetNotFound)
+
+	_, err = PresetPath(projectPath, Options{}, "../escape")
+	require.Error(t, err)
+
+	_, err = NewPresetFromEnvironment(environment.New("empty"), Bicep)
+	require.Error(t, err)
+
+	_, err = NewPresetFromEnvironment(environment.New("empty"), Terraform)
+	require.ErrorContains(t, err, "presets aren't supported by the 'terraform' provider")
+}
+
+func TestResolveProviderKind(t *testing.T) {
+	devCenter := func() (ProviderKind, error) { return ProviderKind("devcenter"), nil }
+	notSpecified := func() (ProviderKind, error) { return NotSpecified, nil }
+
+	kind, err := ResolveProviderKind(Options{Provider: Terraform}, devCenter)
+	require.NoError(t, err)
+	require.Equal(t, Terraform, kind)
+
+	kind, err = ResolveProviderKind(Options{}, devCenter)
+	require.NoError(t, err)
+	require.Equal(t, ProviderKind("devcenter"), kind)
+
+	kind, err = ResolveProviderKind(Options{}, notSpecified)
+	require.NoError(t, err)
+	require.Equal(t, Bicep, kind)
+}
@@ -5,6 +5,7 @@ package provisioning
 
 import (
 	"context"
+	"time"
 )
 
 type ProviderKind string
@@ -16,6 +17,7 @@ const (
 	Terraform    ProviderKind = "terraform"
 	Pulumi       ProviderKind = "pulumi"
 	Test         ProviderKind = "test"
+	Existing     ProviderKind = "existing"
 )
 
 type Options struct {
@@ -23,8 +25,59 @@ type Options struct {
 	Path             string         `yaml:"path,omitempty"`
 	Module           string         `yaml:"module,omitempty"`
 	DeploymentStacks map[string]any `yaml:"deploymentStacks,omitempty"`
+	// Resources maps the services of the project to the existing resources they're deployed to.
+	// Only used by the existing provider.
+	Resources map[string]ExistingResource `yaml:"resources,omitempty"`
+	// Budget enables checking the spend of the budgets of the subscription before provisioning.
+	Budget *BudgetOptions `yaml:"budget,omitempty"`
+	// Quota lists the quotas the infrastructure needs in the location of the environment, ex) the vCPUs of a GPU VM family,
+	// checked before provisioning to fail early instead of during the deployment.
+	Quota []QuotaRequirement `yaml:"quota,omitempty"`
+	// TemplateSpec enables publishing the compiled template of each successful provision as a template spec version.
+	TemplateSpec *TemplateSpecOptions `yaml:"templateSpec,omitempty"`
+	// ParametersKeyVault enables storing the values of the secure parameters prompted for in a Key Vault of the
+	// environment, created when the environment doesn't configure one. Otherwise, they are stored in the user vault.
+	ParametersKeyVault bool `yaml:"parametersKeyVault,omitempty"`
+	// PolicyCheck enables evaluating the resources to deploy against the assigned Azure Policies before provisioning.
+	PolicyCheck bool `yaml:"policyCheck,omitempty"`
+	// AuxiliaryTenants are the ids of the tenants, other than the tenant of the subscription, the Terraform providers and
+	// the Bicep deployments authenticate to, ex) to reference resources of another tenant.
+	AuxiliaryTenants []string `yaml:"auxiliaryTenants,omitempty"`
+	// Harvest reads properties of existing resources, ex) shared platform resources, into outputs of the environment after
+	// provisioning.
+	Harvest []HarvestOptions `yaml:"harvest,omitempty"`
+	// DeploymentName configures the names of the deployments created by the bicep provider. Not used with deployment
+	// stacks, which are updated in place.
+	DeploymentName *DeploymentNameOptions `yaml:"deploymentName,omitempty"`
+	// BicepVersion pins the version of the Bicep CLI compiling the templates, ex) 0.30.3. Only used by the bicep provider.
+	BicepVersion string `yaml:"bicepVersion,omitempty"`
+	// ResourceGroup configures how the resource group of the environment is chosen, ex) reusing a resource group
+	// pre-created with tags instead of prompting.
+	ResourceGroup *ResourceGroupOptions `yaml:"resourceGroup,omitempty"`
 	// Not expected to be defined at azure.yaml
 	IgnoreDeploymentState bool `yaml:"-"`
+	// Not expected to be defined at azure.yaml
+	ForceOutputs bool `yaml:"-"`
+	// Timeout limits how long the provider waits for the deployment or deletion to finish. Set from flags, only used by
+	// the devcenter provider.
+	Timeout time.Duration `yaml:"-"`
+	// PollInterval is the time between the checks of the status of the deployment or deletion. Set from flags, only used
+	// by the devcenter provider.
+	PollInterval time.Duration `yaml:"-"`
+	// Resume waits for the deployment that is still running, instead of starting a new one. Set from flags, only used by
+	// the devcenter provider.
+	Resume bool `yaml:"-"`
+	// User is the object id of the user owning the environment, for admins operating on the environment of another user.
+	// Set from flags, only used by the devcenter provider.
+	User string `yaml:"-"`
+}
+
+// ExistingResource identifies a pre-existing Azure resource, either by its resource id or by the tags set on it.
+type ExistingResource struct {
+	// The resource id of the resource
+	Id string `yaml:"id,omitempty"`
+	// The tags used to discover the resource in the subscription, when the resource id isn't set
+	Tags map[string]string `yaml:"tags,omitempty"`
 }
 
 type SkippedReasonType string
@@ -34,6 +87,9 @@ const DeploymentStateSkipped SkippedReasonType = "deployment State"
 type DeployResult struct {
 	Deployment    *Deployment
 	SkippedReason SkippedReasonType
+	// Snapshot is the snapshot of the template and parameters of the provision, stored in the environment to compare the
+	// next provisions with. Nil when the provider doesn't support snapshots.
+	Snapshot *Snapshot
 }
 
 // DeployPreviewResult defines one deployment in preview mode, displaying what changes would it be performed, without
@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+)
+
+// defaultQuotaProvider is the resource provider of the quotas which don't set it, the one of the vCPUs of VM families
+const defaultQuotaProvider = "Microsoft.Compute"
+
+// QuotaRequirement is a quota the infrastructure needs in the location of the environment, ex) the vCPUs of the GPU VM
+// family of an AI template, checked before provisioning.
+type QuotaRequirement struct {
+	// The resource provider of the quota. (Default: Microsoft.Compute)
+	Provider string `yaml:"provider,omitempty"`
+	// The name of the quota, ex) standardNCADSH100v5Family
+	Name string `yaml:"name"`
+	// The capacity the infrastructure needs, ex) 40 vCPUs
+	Capacity float64 `yaml:"capacity"`
+}
+
+// ResourceProvider returns the resource provider of the quota
+func (q QuotaRequirement) ResourceProvider() string {
+	if q.Provider == "" {
+		return defaultQuotaProvider
+	}
+
+	return q.Provider
+}
+
+// QuotaStatus is a quota requirement which capacity isn't available in the location of the environment
+type QuotaStatus struct {
+	Requirement QuotaRequirement
+	// The quota of the location, nil when the resource provider doesn't have the quota in the location, ex) a VM family
+	// which isn't offered in the location
+	Usage *azapi.QuotaUsage
+}
+
+// Error describes why the capacity of the requirement isn't available
+func (s QuotaStatus) Error() string {
+	if s.Usage == nil {
+		return fmt.Sprintf("the quota '%s' of %s isn't available in the location",
+			s.Requirement.Name, s.Requirement.ResourceProvider())
+	}
+
+	name := s.Usage.DisplayName
+	if name == "" {
+		name = s.Usage.Name
+	}
+
+	return fmt.Sprintf("%s: %.0f needed, %.0f available (%.0f of %.0f used)",
+		name, s.Requirement.Capacity, max(s.Usage.Available(), 0), s.Usage.Usage, s.Usage.Limit)
+}
+
+// CheckQuotas returns the requirements which capacity isn't available in the quotas of the location, by resource
+// provider, in the order of the requirements.
+func CheckQuotas(requirements []QuotaRequirement, quotas map[string][]azapi.QuotaUsage) []QuotaStatus {
+	statuses := []QuotaStatus{}
+	for _, requirement := range requirements {
+		providerQuotas := quotas[strings.ToLower(requirement.ResourceProvider())]
+		index := slices.IndexFunc(providerQuotas, func(quota azapi.QuotaUsage) bool {
+			return strings.EqualFold(quota.Name, requirement.Name)
+		})
+
+		if index < 0 {
+			statuses = append(statuses, QuotaStatus{Requirement: requirement})
+			continue
+		}
+
+		if usage := providerQuotas[index]; usage.Available() < requirement.Capacity {
+			statuses = append(statuses, QuotaStatus{Requirement: requirement, Usage: &usage})
+		}
+	}
+
+	return statuses
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckQuotas(t *testing.T) {
+	quotas := map[string][]azapi.QuotaUsage{
+		"microsoft.compute": {
+			{Name: "cores", DisplayName: "Total Regional vCPUs", Limit: 100, Usage: 20},
+			{
+				Name:        "standardNCADSH100v5Family",
+				DisplayName: "Standard NCADS_H100_v5 Family vCPUs",
+				Limit:       80,
+				Usage:       60,
+			},
+		},
+		"microsoft.machinelearningservices": {
+			{Name: "standardDSv2Family", Limit: 24, Usage: 0},
+		},
+	}
+
+	t.Run("Available", func(t *testing.T) {
+		statuses := CheckQuotas([]QuotaRequirement{
+			{Name: "cores", Capacity: 80},
+			{Provider: "Microsoft.MachineLearningServices", Name: "StandardDSv2Family", Capacity: 24},
+		}, quotas)
+		require.Empty(t, statuses)
+	})
+
+	t.Run("Insufficient", func(t *testing.T) {
+		statuses := CheckQuotas([]QuotaRequirement{
+			{Name: "cores", Capacity: 40},
+			{Name: "standardNCADSH100v5Family", Capacity: 40},
+			{Name: "standardNDamsrA100V4Family", Capacity: 96},
+		}, quotas)
+
+		require.Len(t, statuses, 2)
+		require.Equal(t, "standardNCADSH100v5Family", statuses[0].Requirement.Name)
+		require.Equal(t, "Standard NCADS_H100_v5 Family vCPUs: 40 needed, 20 available (60 of 80 used)", statuses[0].Error())
+		require.Nil(t, statuses[1].Usage)
+		require.Equal(t,
+			"the quota 'standardNDamsrA100V4Family' of Microsoft.Compute isn't available in the location",
+			statuses[1].Error())
+	})
+}
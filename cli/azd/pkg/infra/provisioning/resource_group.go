@@ -0,0 +1,278 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+)
+
+// ResourceGroupStrategy is how the resource group of an environment is chosen
+type ResourceGroupStrategy string
+
+const (
+	// ResourceGroupStrategyPrompt prompts for an existing resource group, or for creating rg-<env>. The default.
+	ResourceGroupStrategyPrompt ResourceGroupStrategy = "prompt"
+	// ResourceGroupStrategyCreate always uses the resource group named by the options, rg-<env> by default, and creates
+	// it when it doesn't exist.
+	ResourceGroupStrategyCreate ResourceGroupStrategy = "create"
+	// ResourceGroupStrategyTag reuses the existing resource group with the tags of the options, azd-env-name: <env> by
+	// default, ex) a resource group pre-created by the platform team.
+	ResourceGroupStrategyTag ResourceGroupStrategy = "tag"
+	// ResourceGroupStrategyPerService deploys each service, which doesn't set its resource group, to the resource group
+	// rg-<env>-<service> created by the infrastructure. The resource group of the environment is chosen like with
+	// ResourceGroupStrategyCreate.
+	ResourceGroupStrategyPerService ResourceGroupStrategy = "perService"
+)
+
+// ResourceGroupOptions configures how the resource group of an environment, AZURE_RESOURCE_GROUP, is chosen when it isn't
+// set yet, ex) when a resource group scoped template is provisioned.
+type ResourceGroupOptions struct {
+	// The strategy choosing the resource group. (Default: prompt)
+	Strategy ResourceGroupStrategy `yaml:"strategy,omitempty"`
+	// The name of the resource group used by the create and perService strategies. (Default: rg-${AZURE_ENV_NAME})
+	Name osutil.ExpandableString `yaml:"name,omitempty"`
+	// The tags of the resource group reused by the tag strategy. (Default: azd-env-name: ${AZURE_ENV_NAME})
+	Tags map[string]osutil.ExpandableString `yaml:"tags,omitempty"`
+}
+
+// Validate checks the strategy of the options is known
+func (o *ResourceGroupOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	switch o.Strategy {
+	case "", ResourceGroupStrategyPrompt, ResourceGroupStrategyCreate, ResourceGroupStrategyTag,
+		ResourceGroupStrategyPerService:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unknown resource group strategy '%s', expected one of: prompt, create, tag, perService", o.Strategy)
+	}
+}
+
+// ServiceResourceGroupName returns the resource group of a service which doesn't set its resource group,
+// rg-${AZURE_ENV_NAME}-<service> with the perService strategy, empty otherwise.
+func (o *ResourceGroupOptions) ServiceResourceGroupName(serviceName string) osutil.ExpandableString {
+	if o == nil || o.Strategy != ResourceGroupStrategyPerService {
+		return osutil.NewExpandableString("")
+	}
+
+	return osutil.NewExpandableString(fmt.Sprintf("rg-${%s}-%s", environment.EnvNameEnvVarName, serviceName))
+}
+
+// resourceGroupName returns the name of the resource group used by the create and perService strategies
+func (o *ResourceGroupOptions) resourceGroupName(env *environment.Environment) (string, error) {
+	name, err := o.Name.Envsubst(env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("expanding the name of the resource group: %w", err)
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = fmt.Sprintf("rg-%s", env.Name())
+	}
+
+	return name, nil
+}
+
+// resourceGroupTags returns the tags of the resource group reused by the tag strategy
+func (o *ResourceGroupOptions) resourceGroupTags(env *environment.Environment) (map[string]string, error) {
+	if len(o.Tags) == 0 {
+		return map[string]string{azure.TagKeyAzdEnvName: env.Name()}, nil
+	}
+
+	tags := map[string]string{}
+	for name, value := range o.Tags {
+		expanded, err := value.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding the tag '%s' of the resource group: %w", name, err)
+		}
+
+		tags[name] = expanded
+	}
+
+	return tags, nil
+}
+
+// WarnIgnoredResourceGroup warns when the options choose the resource group of the environment with the create or the tag
+// strategy, but the provisioning doesn't deploy to a resource group chosen by azd, ex) a subscription scoped template.
+// The resource groups of the services of the perService strategy don't depend on the provisioning.
+func WarnIgnoredResourceGroup(ctx context.Context, console input.Console, options *ResourceGroupOptions, reason string) {
+	if options == nil || (options.Strategy != ResourceGroupStrategyCreate && options.Strategy != ResourceGroupStrategyTag) {
+		return
+	}
+
+	console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf(
+			"The resource group strategy '%s' of 'infra.resourceGroup' in azure.yaml is ignored, %s. It only applies "+
+				"to Bicep templates deployed to a resource group.", options.Strategy, reason),
+	})
+}
+
+// EnsureResourceGroup ensures that the resource group (AZURE_RESOURCE_GROUP) is set in the environment, choosing it with
+// the strategy of the options when it doesn't exist.
+func EnsureResourceGroup(
+	ctx context.Context,
+	envManager environment.Manager,
+	env *environment.Environment,
+	prompter prompt.Prompter,
+	resourceService *azapi.ResourceService,
+	options *ResourceGroupOptions,
+) error {
+	if env.Getenv(environment.ResourceGroupEnvVarName) != "" {
+		return nil
+	}
+
+	if options == nil {
+		options = &ResourceGroupOptions{}
+	}
+
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	var rgName string
+	var err error
+	switch options.Strategy {
+	case ResourceGroupStrategyCreate, ResourceGroupStrategyPerService:
+		rgName, err = createResourceGroup(ctx, env, resourceService, options)
+	case ResourceGroupStrategyTag:
+		rgName, err = findResourceGroupByTags(ctx, env, resourceService, options)
+	default:
+		// Prompt Resource Group supports creating a new resource group
+		// And prompts for a location as part of creating a new resource group
+		rgName, err = prompter.PromptResourceGroup(ctx, prompt.PromptResourceOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	env.DotenvSet(environment.ResourceGroupEnvVarName, rgName)
+	if err := envManager.Save(ctx, env); err != nil {
+		return fmt.Errorf("saving resource group name: %w", err)
+	}
+
+	return nil
+}
+
+// createResourceGroup creates the resource group of the environment in its location, unless it already exists.
+func createResourceGroup(
+	ctx context.Context,
+	env *environment.Environment,
+	resourceService *azapi.ResourceService,
+	options *ResourceGroupOptions,
+) (string, error) {
+	rgName, err := options.resourceGroupName(env)
+	if err != nil {
+		return "", err
+	}
+
+	group, err := resourceService.GetResourceGroup(ctx, env.GetSubscriptionId(), rgName)
+	if err == nil {
+		return group.Name, nil
+	}
+
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusNotFound {
+		return "", err
+	}
+
+	if env.GetLocation() == "" {
+		return "", fmt.Errorf("creating resource group '%s': the location of the environment isn't set", rgName)
+	}
+
+	_, err = resourceService.CreateOrUpdateResourceGroup(
+		ctx,
+		env.GetSubscriptionId(),
+		rgName,
+		env.GetLocation(),
+		map[string]*string{
+			azure.TagKeyAzdEnvName: to.Ptr(env.Name()),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return rgName, nil
+}
+
+// findResourceGroupByTags returns the one resource group with all the tags of the options.
+func findResourceGroupByTags(
+	ctx context.Context,
+	env *environment.Environment,
+	resourceService *azapi.ResourceService,
+	options *ResourceGroupOptions,
+) (string, error) {
+	tags, err := options.resourceGroupTags(env)
+	if err != nil {
+		return "", err
+	}
+
+	// The resource groups can only be filtered by one tag, the resource groups matching all of them are kept
+	var matches []string
+	for _, name := range slices.Sorted(maps.Keys(tags)) {
+		groups, err := resourceService.ListResourceGroup(
+			ctx, env.GetSubscriptionId(), &azapi.ListResourceGroupOptions{
+				TagFilter: &azapi.Filter{Key: name, Value: tags[name]},
+			})
+		if err != nil {
+			return "", fmt.Errorf("listing resource groups: %w", err)
+		}
+
+		names := []string{}
+		for _, group := range groups {
+			// Managed resource groups, ex) the node resource group of AKS, carry the tags of their parent
+			if group.ManagedBy == nil && (matches == nil || slices.Contains(matches, group.Name)) {
+				names = append(names, group.Name)
+			}
+		}
+		matches = names
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("no resource group has the tags %s", formatTags(tags)),
+			Suggestion: "Suggestion: tag the resource group of the environment, or set 'infra.resourceGroup.tags' " +
+				"in azure.yaml.",
+		}
+	default:
+		slices.Sort(matches)
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"more than one resource group has the tags %s: %s", formatTags(tags), strings.Join(matches, ", ")),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: set the resource group with 'azd env set %s <name>'.", environment.ResourceGroupEnvVarName),
+		}
+	}
+}
+
+func formatTags(tags map[string]string) string {
+	formatted := []string{}
+	for _, name := range slices.Sorted(maps.Keys(tags)) {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", name, tags[name]))
+	}
+
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnsureResourceGroup(t *testing.T) {
+	groups := []*armresources.ResourceGroup{
+		testResourceGroup("rg-shared", map[string]string{"owner": "platform"}, nil),
+		testResourceGroup("rg-team-dev", map[string]string{"owner": "platform", "azd-env-name": "dev"}, nil),
+		testResourceGroup("rg-aks-nodes", map[string]string{"azd-env-name": "dev"}, to.Ptr("AKS_ID")),
+	}
+
+	ensure := func(t *testing.T, options *ResourceGroupOptions) (*environment.Environment, []string, error) {
+		mockContext := mocks.NewMockContext(context.Background())
+		created := registerResourceGroupMocks(mockContext, groups)
+
+		env := environment.NewWithValues("dev", map[string]string{
+			environment.EnvNameEnvVarName:        "dev",
+			environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			environment.LocationEnvVarName:       "eastus2",
+		})
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", mock.Anything, env).Return(nil)
+
+		resourceService := azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+		err := EnsureResourceGroup(*mockContext.Context, envManager, env, nil, resourceService, options)
+		return env, *created, err
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		env, created, err := ensure(t, &ResourceGroupOptions{Strategy: ResourceGroupStrategyCreate})
+		require.NoError(t, err)
+		require.Equal(t, "rg-dev", env.Getenv(environment.ResourceGroupEnvVarName))
+		require.Equal(t, []string{"rg-dev"}, created)
+	})
+
+	t.Run("CreateExisting", func(t *testing.T) {
+		env, created, err := ensure(t, &ResourceGroupOptions{
+			Strategy: ResourceGroupStrategyPerService,
+			Name:     osutil.NewExpandableString("RG-TEAM-${AZURE_ENV_NAME}"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "rg-team-dev", env.Getenv(environment.ResourceGroupEnvVarName))
+		require.Empty(t, created)
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		env, _, err := ensure(t, &ResourceGroupOptions{Strategy: ResourceGroupStrategyTag})
+		require.NoError(t, err)
+		require.Equal(t, "rg-team-dev", env.Getenv(environment.ResourceGroupEnvVarName))
+	})
+
+	t.Run("TagMultipleMatches", func(t *testing.T) {
+		_, _, err := ensure(t, &ResourceGroupOptions{
+			Strategy: ResourceGroupStrategyTag,
+			Tags:     map[string]osutil.ExpandableString{"owner": osutil.NewExpandableString("platform")},
+		})
+		require.ErrorContains(t, err, "more than one resource group has the tags owner=platform: rg-shared, rg-team-dev")
+	})
+
+	t.Run("TagNoMatch", func(t *testing.T) {
+		_, _, err := ensure(t, &ResourceGroupOptions{
+			Strategy: ResourceGroupStrategyTag,
+			Tags: map[string]osutil.ExpandableString{
+				"owner":        osutil.NewExpandableString("platform"),
+				"azd-env-name": osutil.NewExpandableString("prod"),
+			},
+		})
+		require.ErrorContains(t, err, "no resource group has the tags azd-env-name=prod, owner=platform")
+	})
+
+	t.Run("UnknownStrategy", func(t *testing.T) {
+		_, _, err := ensure(t, &ResourceGroupOptions{Strategy: "reuse"})
+		require.ErrorContains(t, err, "unknown resource group strategy 'reuse'")
+	})
+}
+
+func Test_WarnIgnoredResourceGroup(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	WarnIgnoredResourceGroup(*mockContext.Context, mockContext.Console, nil, "the template is deployed to the subscription")
+	WarnIgnoredResourceGroup(*mockContext.Context, mockContext.Console,
+		&ResourceGroupOptions{Strategy: ResourceGroupStrategyPerService}, "the template is deployed to the subscription")
+	require.Empty(t, mockContext.Console.Output())
+
+	WarnIgnoredResourceGroup(*mockContext.Context, mockContext.Console,
+		&ResourceGroupOptions{Strategy: ResourceGroupStrategyTag}, "the template is deployed to the subscription")
+	require.Len(t, mockContext.Console.Output(), 1)
+	require.Contains(t, mockContext.Console.Output()[0], "The resource group strategy 'tag'")
+}
+
+func Test_ResourceGroupOptions_ServiceResourceGroupName(t *testing.T) {
+	var options *ResourceGroupOptions
+	require.True(t, options.ServiceResourceGroupName("api").Empty())
+
+	options = &ResourceGroupOptions{Strategy: ResourceGroupStrategyPerService}
+	require.Equal(t, "rg-dev-api", options.ServiceResourceGroupName("api").MustEnvsubst(func(name string) string {
+		return map[string]string{environment.EnvNameEnvVarName: "dev"}[name]
+	}))
+}
+
+func testResourceGroup(name string, tags map[string]string, managedBy *string) *armresources.ResourceGroup {
+	groupTags := map[string]*string{}
+	for key, value := range tags {
+		groupTags[key] = to.Ptr(value)
+	}
+
+	return &armresources.ResourceGroup{
+		ID:        to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/" + name),
+		Name:      to.Ptr(name),
+		Type:      to.Ptr("Microsoft.Resources/resourceGroups"),
+		Location:  to.Ptr("eastus2"),
+		Tags:      groupTags,
+		ManagedBy: managedBy,
+	}
+}
+
+// registerResourceGroupMocks serves the resource groups, filtered by the tag of the request, and returns the names of
+// the resource groups created.
+func registerResourceGroupMocks(mockContext *mocks.MockContext, groups []*armresources.ResourceGroup) *[]string {
+	created := []string{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		filtered := []*armresources.ResourceGroup{}
+		for _, group := range groups {
+			matches := true
+			if filter := request.URL.Query().Get("$filter"); filter != "" {
+				matches = false
+				for key, value := range group.Tags {
+					if filter == "tagName eq '"+key+"' and tagValue eq '"+*value+"'" {
+						matches = true
+					}
+				}
+			}
+
+			if matches {
+				filtered = append(filtered, group)
+			}
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+			Value: filtered,
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/resourcegroups/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		name := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+		for _, group := range groups {
+			if strings.EqualFold(*group.Name, name) {
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, group)
+			}
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "/resourcegroups/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		name := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+		created = append(created, name)
+
+		var group armresources.ResourceGroup
+		if err := json.NewDecoder(request.Body).Decode(&group); err != nil {
+			return nil, err
+		}
+
+		group.ID = to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/" + name)
+		group.Name = to.Ptr(name)
+		return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, group)
+	})
+
+	return &created
+}
@@ -0,0 +1,227 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+var ErrSnapshotNotSupported = errors.New("comparing with the last provision is not supported by the provisioning provider")
+
+// SnapshotConfigPath is the environment config path of the snapshot of the last successful provision
+const SnapshotConfigPath = "provision.snapshot"
+
+// snapshotKeyConfigPath is the environment config path of the random key the values of secure parameters are hashed with
+const snapshotKeyConfigPath = "provision.snapshotKey"
+
+// Snapshot is a normalized record of the template and the parameter values of a provision, stored in the config of the
+// environment after each successful provision, so the next one can be compared with it without calling Azure.
+type Snapshot struct {
+	// The provider which provisioned the environment
+	Provider ProviderKind `json:"provider"`
+	// The hash of the normalized template
+	TemplateHash string `json:"templateHash"`
+	// The normalized JSON value of each parameter by name. Secure values are replaced by their HMAC, see SnapshotKey.
+	Parameters map[string]string `json:"parameters"`
+}
+
+// SnapshotParameter is the value of a parameter of a provision, see NewSnapshot.
+type SnapshotParameter struct {
+	Value  any
+	Secure bool
+}
+
+// NewSnapshot creates the snapshot of a provision from its template, ex) a compiled ARM template, and its parameters.
+// Generator metadata of the template, like the version of the tool which compiled it, is ignored. The values of secure
+// parameters are hashed with the key of the environment, see SnapshotKey.
+func NewSnapshot(
+	provider ProviderKind,
+	template json.RawMessage,
+	parameters map[string]SnapshotParameter,
+	key []byte,
+) (*Snapshot, error) {
+	var normalized any
+	if err := json.Unmarshal(template, &normalized); err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	if root, ok := normalized.(map[string]any); ok {
+		if metadata, ok := root["metadata"].(map[string]any); ok {
+			delete(metadata, "_generator")
+		}
+	}
+
+	// Maps are marshalled with sorted keys, so the same template always has the same hash
+	templateJson, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("normalizing template: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		Provider:     provider,
+		TemplateHash: fmt.Sprintf("%x", sha256.Sum256(templateJson)),
+		Parameters:   make(map[string]string, len(parameters)),
+	}
+
+	for name, parameter := range parameters {
+		value, err := json.Marshal(parameter.Value)
+		if err != nil {
+			return nil, fmt.Errorf("normalizing parameter '%s': %w", name, err)
+		}
+
+		if parameter.Secure {
+			mac := hmac.New(sha256.New, key)
+			mac.Write(value)
+			snapshot.Parameters[name] = fmt.Sprintf("hmac-sha256:%x", mac.Sum(nil))
+		} else {
+			snapshot.Parameters[name] = string(value)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// SnapshotKey returns the random key of the environment which the values of secure parameters are hashed with in its
+// snapshots, so a value can't be found from its hash by hashing common values. The key is created with the first snapshot
+// and stored in the config of the environment, where it's saved with the snapshot.
+func SnapshotKey(env *environment.Environment) ([]byte, error) {
+	if value, has := env.Config.GetString(snapshotKeyConfigPath); has {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("reading the snapshot key of the environment: %w", err)
+		}
+
+		return key, nil
+	}
+
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("creating the snapshot key of the environment: %w", err)
+	}
+
+	if err := env.Config.Set(snapshotKeyConfigPath, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("setting the snapshot key of the environment: %w", err)
+	}
+
+	return key, nil
+}
+
+// SnapshotDiff is the difference between the current template and parameters and the last successful provision.
+type SnapshotDiff struct {
+	// Whether a provision is needed to apply the current template and parameters
+	Changed bool `json:"changed"`
+	// Whether the environment has no recorded provision, ex) it was never provisioned or it was provisioned by an older
+	// version of azd
+	NoSnapshot bool `json:"noSnapshot,omitempty"`
+	// Whether the template changed
+	TemplateChanged bool `json:"templateChanged,omitempty"`
+	// The names of the parameters which were added, removed and whose value changed, sorted
+	AddedParameters   []string `json:"addedParameters,omitempty"`
+	RemovedParameters []string `json:"removedParameters,omitempty"`
+	ChangedParameters []string `json:"changedParameters,omitempty"`
+}
+
+// Diff compares the snapshot with the snapshot of the last provision, which is nil when there is none.
+func (s *Snapshot) Diff(last *Snapshot) *SnapshotDiff {
+	if last == nil {
+		return &SnapshotDiff{Changed: true, NoSnapshot: true}
+	}
+
+	diff := &SnapshotDiff{
+		TemplateChanged: s.Provider != last.Provider || s.TemplateHash != last.TemplateHash,
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.Parameters)) {
+		lastValue, has := last.Parameters[name]
+		switch {
+		case !has:
+			diff.AddedParameters = append(diff.AddedParameters, name)
+		case lastValue != s.Parameters[name]:
+			diff.ChangedParameters = append(diff.ChangedParameters, name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(last.Parameters)) {
+		if _, has := s.Parameters[name]; !has {
+			diff.RemovedParameters = append(diff.RemovedParameters, name)
+		}
+	}
+
+	diff.Changed = diff.TemplateChanged ||
+		len(diff.AddedParameters) > 0 || len(diff.RemovedParameters) > 0 || len(diff.ChangedParameters) > 0
+	return diff
+}
+
+// SnapshotProvider is implemented by providers able to create the snapshot of the current template and parameters of the
+// environment, without calling Azure.
+type SnapshotProvider interface {
+	// Snapshot creates the snapshot of the template and parameters a provision would deploy now.
+	Snapshot(ctx context.Context) (*Snapshot, error)
+}
+
+// WhatChanged compares the current template and parameters of the environment with the snapshot of its last successful
+// provision, to tell whether a provision is needed.
+func (m *Manager) WhatChanged(ctx context.Context) (*SnapshotDiff, error) {
+	snapshotProvider, ok := m.provider.(SnapshotProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", m.provider.Name(), ErrSnapshotNotSupported)
+	}
+
+	current, err := snapshotProvider.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating snapshot: %w", err)
+	}
+
+	last, err := lastSnapshot(m.env)
+	if err != nil {
+		return nil, err
+	}
+
+	return current.Diff(last), nil
+}
+
+// lastSnapshot returns the snapshot of the last successful provision of the environment, or nil when there is none.
+func lastSnapshot(env *environment.Environment) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	has, err := env.Config.GetSection(SnapshotConfigPath, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("reading the snapshot of the last provision: %w", err)
+	}
+
+	if !has {
+		return nil, nil
+	}
+
+	return snapshot, nil
+}
+
+// saveSnapshot stores the snapshot of a successful provision in the config of the environment.
+func (m *Manager) saveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	value, err := convert.ToMap(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err := m.env.Config.Set(SnapshotConfigPath, value); err != nil {
+		return fmt.Errorf("setting the snapshot of the provision: %w", err)
+	}
+
+	if err := m.envManager.Save(ctx, m.env); err != nil {
+		return fmt.Errorf("saving the snapshot of the provision: %w", err)
+	}
+
+	return nil
+}
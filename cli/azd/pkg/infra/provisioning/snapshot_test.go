@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	template := `{
+		"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+		"metadata": { "_generator": { "name": "bicep", "version": "0.30.3.12046" } },
+		"resources": {}
+	}`
+
+	parameters := map[string]SnapshotParameter{
+		"location": {Value: "eastus2"},
+		"tags":     {Value: map[string]any{"b": "2", "a": "1"}},
+		"password": {Value: "P@ssw0rd", Secure: true},
+	}
+	snapshot, err := NewSnapshot(Bicep, []byte(template), parameters, []byte("key"))
+	require.NoError(t, err)
+
+	require.Equal(t, Bicep, snapshot.Provider)
+	require.Equal(t, `"eastus2"`, snapshot.Parameters["location"])
+	require.Equal(t, `{"a":"1","b":"2"}`, snapshot.Parameters["tags"])
+	require.True(t, strings.HasPrefix(snapshot.Parameters["password"], "hmac-sha256:"))
+	require.NotContains(t, snapshot.Parameters["password"], "P@ssw0rd")
+
+	t.Run("SecureValuesHashedWithKey", func(t *testing.T) {
+		other, err := NewSnapshot(Bicep, []byte(template), parameters, []byte("other key"))
+		require.NoError(t, err)
+		require.NotEqual(t, snapshot.Parameters["password"], other.Parameters["password"])
+		require.Equal(t, snapshot.Parameters["location"], other.Parameters["location"])
+	})
+
+	t.Run("FormattingAndGeneratorIgnored", func(t *testing.T) {
+		other, err := NewSnapshot(Bicep, []byte(`{"resources":{},"metadata":{"_generator":{"version":"0.31.0"}},`+
+			`"$schema":"https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#"}`),
+			nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, snapshot.TemplateHash, other.TemplateHash)
+	})
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		_, err := NewSnapshot(Bicep, []byte("not json"), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	last := &Snapshot{
+		Provider:     Bicep,
+		TemplateHash: "hash",
+		Parameters: map[string]string{
+			"location": `"eastus2"`,
+			"sku":      `"B1"`,
+			"old":      `true`,
+		},
+	}
+
+	t.Run("NoSnapshot", func(t *testing.T) {
+		diff := last.Diff(nil)
+		require.Equal(t, &SnapshotDiff{Changed: true, NoSnapshot: true}, diff)
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		current := *last
+		require.Equal(t, &SnapshotDiff{}, current.Diff(last))
+	})
+
+	t.Run("TemplateChanged", func(t *testing.T) {
+		current := *last
+		current.TemplateHash = "other"
+		require.Equal(t, &SnapshotDiff{Changed: true, TemplateChanged: true}, current.Diff(last))
+	})
+
+	t.Run("ParametersChanged", func(t *testing.T) {
+		current := &Snapshot{
+			Provider:     Bicep,
+			TemplateHash: "hash",
+			Parameters: map[string]string{
+				"location": `"westus"`,
+				"sku":      `"B1"`,
+				"new":      `1`,
+			},
+		}
+
+		require.Equal(t, &SnapshotDiff{
+			Changed:           true,
+			AddedParameters:   []string{"new"},
+			RemovedParameters: []string{"old"},
+			ChangedParameters: []string{"location"},
+		}, current.Diff(last))
+	})
+}
+
+func TestSnapshotKey(t *testing.T) {
+	env := environment.New("dev")
+
+	key, err := SnapshotKey(env)
+	require.NoError(t, err)
+	require.Len(t, key, 32)
+
+	// the key is created once per environment
+	again, err := SnapshotKey(env)
+	require.NoError(t, err)
+	require.Equal(t, key, again)
+
+	other, err := SnapshotKey(environment.New("other"))
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"regexp"
+	"time"
+)
+
+// templateSpecVersionInvalidChars matches the characters not allowed in the name of a template spec version
+var templateSpecVersionInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._()-]`)
+
+// templateSpecVersionMaxLength is the max length of the name of a template spec version
+const templateSpecVersionMaxLength = 90
+
+// gitShaLength is the length of the git commit sha kept in the name of a template spec version
+const gitShaLength = 12
+
+// TemplateSpecOptions enables publishing the compiled template of each successful provision as a version of an Azure
+// Template Spec, which keeps a catalog of what was deployed.
+type TemplateSpecOptions struct {
+	// The resource group of the template spec. (Default: the AZURE_RESOURCE_GROUP of the environment)
+	ResourceGroup string `yaml:"resourceGroup,omitempty"`
+	// The name of the template spec. (Default: the name of the module, ex) main)
+	Name string `yaml:"name,omitempty"`
+}
+
+// TemplateSpecVersionName returns the name of the template spec version published for a provision of the environment, ex)
+// dev-0123456789ab. The time of the provision is used when the git commit sha isn't known.
+func TemplateSpecVersionName(envName string, gitSha string, provisionedAt time.Time) string {
+	suffix := provisionedAt.UTC().Format("20060102T150405Z")
+	if gitSha != "" {
+		suffix = gitSha
+		if len(suffix) > gitShaLength {
+			suffix = suffix[:gitShaLength]
+		}
+	}
+
+	name := templateSpecVersionInvalidChars.ReplaceAllString(envName, "-")
+	if maxLength := templateSpecVersionMaxLength - len(suffix) - 1; len(name) > maxLength {
+		name = name[:maxLength]
+	}
+
+	return name + "-" + suffix
+}
@@ -0,0 +1,25 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TemplateSpecVersionName(t *testing.T) {
+	provisionedAt := time.Date(2026, 10, 15, 11, 6, 42, 0, time.UTC)
+
+	require.Equal(t, "dev-0123456789ab",
+		TemplateSpecVersionName("dev", "0123456789abcdef0123456789abcdef01234567", provisionedAt))
+	require.Equal(t, "dev-20261015T110642Z", TemplateSpecVersionName("dev", "", provisionedAt))
+	require.Equal(t, "my-env-abc123", TemplateSpecVersionName("my env", "abc123", provisionedAt))
+
+	name := TemplateSpecVersionName(strings.Repeat("e", 100), "0123456789abcdef", provisionedAt)
+	require.Len(t, name, templateSpecVersionMaxLength)
+	require.True(t, strings.HasSuffix(name, "-0123456789ab"))
+}
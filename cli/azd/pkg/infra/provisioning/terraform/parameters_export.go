@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// ExportParameters renders the effective variables of the environment as a .tfvars file, from the .tfvars.json template
+// of the project. The values of sensitive variables aren't inlined, they're read by Terraform from TF_VAR_<name>
+// environment variables instead.
+func (t *TerraformProvider) ExportParameters(ctx context.Context, dir string) (*provisioning.ParametersFile, error) {
+	replaced, err := t.substituteParametersTemplate(ctx, t.parametersTemplateFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]any{}
+	if err := json.Unmarshal([]byte(replaced), &variables); err != nil {
+		return nil, fmt.Errorf("parsing parameter file: %w", err)
+	}
+
+	sensitive, err := sensitiveVariables(t.modulePath())
+	if err != nil {
+		return nil, err
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf(
+		"# Generated by 'azd infra params export' for environment '%s'\n", t.env.Name()))
+
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		builder.WriteString("\n")
+		if slices.Contains(sensitive, name) {
+			builder.WriteString(fmt.Sprintf(
+				"# Sensitive variable, set the TF_VAR_%s environment variable to its value\n", name))
+			continue
+		}
+
+		value, err := hclValue(variables[name], "")
+		if err != nil {
+			return nil, fmt.Errorf("rendering variable '%s': %w", name, err)
+		}
+
+		builder.WriteString(fmt.Sprintf("%s = %s\n", hclKey(name), value))
+	}
+
+	return &provisioning.ParametersFile{
+		Name:     fmt.Sprintf("%s.%s.tfvars", t.options.Module, t.env.Name()),
+		Contents: []byte(builder.String()),
+	}, nil
+}
+
+var variableBlockRegex = regexp.MustCompile(`(?m)^\s*variable\s+"([^"]+)"\s*\{`)
+var sensitiveRegex = regexp.MustCompile(`(?m)^\s*sensitive\s*=\s*true\s*$`)
+
+// sensitiveVariables returns the names of the variables declared with sensitive = true in the .tf files of a module
+func sensitiveVariables(modulePath string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(modulePath, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+
+	sensitive := []string{}
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading '%s': %w", file, err)
+		}
+
+		text := string(contents)
+		for _, match := range variableBlockRegex.FindAllStringSubmatchIndex(text, -1) {
+			name := text[match[2]:match[3]]
+
+			// The body of the block ends at its matching closing brace
+			depth := 1
+			end := match[1]
+			for ; end < len(text) && depth > 0; end++ {
+				switch text[end] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				}
+			}
+
+			if sensitiveRegex.MatchString(text[match[1]:end]) {
+				sensitive = append(sensitive, name)
+			}
+		}
+	}
+
+	return sensitive, nil
+}
+
+var hclIdentifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// hclKey renders the name of a variable or an attribute, quoting it when it isn't a valid identifier
+func hclKey(key string) string {
+	if hclIdentifierRegex.MatchString(key) {
+		return key
+	}
+
+	return hclString(key)
+}
+
+// hclValue renders a JSON value as an HCL literal, ex) "eastus", ["a", "b"] or { name = "value" }
+func hclValue(value any, indent string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return hclString(v), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case []any:
+		if len(v) == 0 {
+			return "[]", nil
+		}
+
+		builder := strings.Builder{}
+		builder.WriteString("[\n")
+		for _, item := range v {
+			rendered, err := hclValue(item, indent+"  ")
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(fmt.Sprintf("%s  %s,\n", indent, rendered))
+		}
+		builder.WriteString(indent + "]")
+		return builder.String(), nil
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}", nil
+		}
+
+		builder := strings.Builder{}
+		builder.WriteString("{\n")
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			rendered, err := hclValue(v[key], indent+"  ")
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(fmt.Sprintf("%s  %s = %s\n", indent, hclKey(key), rendered))
+		}
+		builder.WriteString(indent + "}")
+		return builder.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
+
+// hclString renders a string as an HCL string literal, escaping the template sequences so they're kept verbatim
+func hclString(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformExportParameters(t *testing.T) {
+	projectDir := t.TempDir()
+	infraDir := filepath.Join(projectDir, "infra")
+	require.NoError(t, os.MkdirAll(infraDir, osutil.PermissionDirectory))
+
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "main.tfvars.json"), []byte(`{
+		"location": "${AZURE_LOCATION}",
+		"principal_id": "${AZURE_PRINCIPAL_ID}",
+		"db_password": "${DB_PASSWORD}",
+		"tags": { "env": "${AZURE_ENV_NAME}", "cost-center": 42 },
+		"zones": ["1", "2"]
+	}`), osutil.PermissionFile))
+
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "variables.tf"), []byte(`
+variable "location" {
+  type = string
+}
+
+variable "db_password" {
+  type      = string
+  validation {
+    condition     = length(var.db_password) > 8
+    error_message = "too short"
+  }
+  sensitive = true
+}
+`), osutil.PermissionFile))
+
+	provider := &TerraformProvider{
+		env: environment.NewWithValues("dev", map[string]string{
+			"AZURE_ENV_NAME": "dev",
+			"AZURE_LOCATION": "westus2",
+			"DB_PASSWORD":    "secret-value",
+		}),
+		curPrincipal: &mockCurrentPrincipal{},
+		projectPath:  projectDir,
+		options:      provisioning.Options{Path: "infra", Module: "main"},
+	}
+
+	file, err := provider.ExportParameters(context.Background(), infraDir)
+	require.NoError(t, err)
+	require.Equal(t, "main.dev.tfvars", file.Name)
+
+	expected := `# Generated by 'azd infra params export' for environment 'dev'
+
+# Sensitive variable, set the TF_VAR_db_password environment variable to its value
+
+location = "westus2"
+
+principal_id = "11111111-1111-1111-1111-111111111111"
+
+tags = {
+  cost-center = 42
+  env = "dev"
+}
+
+zones = [
+  "1",
+  "2",
+]
+`
+	require.Equal(t, expected, string(file.Contents))
+	require.NotContains(t, string(file.Contents), "secret-value")
+}
+
+func TestHclString(t *testing.T) {
+	require.Equal(t, `"say \"hi\"\n$${name} %%{if}"`, hclString("say \"hi\"\n${name} %{if}"))
+}
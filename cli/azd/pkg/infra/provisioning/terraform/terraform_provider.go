@@ -15,6 +15,8 @@ import (
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -39,10 +41,21 @@ type TerraformProvider struct {
 	console      input.Console
 	cli          *terraform.Cli
 	curPrincipal provisioning.CurrentPrincipalIdProvider
+	authManager  AuthManager
 	projectPath  string
 	options      provisioning.Options
 }
 
+// AuthManager provides the ARM_* environment variables which let the Terraform providers authenticate as the current user
+// of azd.
+type AuthManager interface {
+	ArmEnvForCurrentUser(ctx context.Context, options *auth.ArmEnvOptions) ([]string, error)
+}
+
+// armCredentialEnvVars are the environment variables which, when set by the user, configure how the Terraform providers
+// authenticate. The credential of azd isn't passed through when any of them is set.
+var armCredentialEnvVars = []string{"ARM_CLIENT_ID", "ARM_USE_CLI", "ARM_USE_MSI", "ARM_USE_OIDC"}
+
 type terraformDeploymentDetails struct {
 	ParameterFilePath  string
 	PlanFilePath       string
@@ -66,6 +79,7 @@ func NewTerraformProvider(
 	console input.Console,
 	curPrincipal provisioning.CurrentPrincipalIdProvider,
 	prompters prompt.Prompter,
+	authManager AuthManager,
 ) provisioning.Provider {
 	provider := &TerraformProvider{
 		envManager:   envManager,
@@ -74,6 +88,7 @@ func NewTerraformProvider(
 		cli:          cli,
 		curPrincipal: curPrincipal,
 		prompters:    prompters,
+		authManager:  authManager,
 	}
 
 	return provider
@@ -101,15 +116,17 @@ func (t *TerraformProvider) Initialize(ctx context.Context, projectPath string,
 	envVars := []string{
 		// Sets the terraform data directory env var that will get set on all terraform CLI commands
 		fmt.Sprintf("TF_DATA_DIR=%s", t.dataDirPath()),
-		// Required when using service principal login
-		fmt.Sprintf("ARM_TENANT_ID=%s", os.Getenv("ARM_TENANT_ID")),
 		fmt.Sprintf("ARM_SUBSCRIPTION_ID=%s", t.env.GetSubscriptionId()),
-		fmt.Sprintf("ARM_CLIENT_ID=%s", os.Getenv("ARM_CLIENT_ID")),
-		fmt.Sprintf("ARM_CLIENT_SECRET=%s", os.Getenv("ARM_CLIENT_SECRET")),
 		// Include azd in user agent
 		fmt.Sprintf("TF_APPEND_USER_AGENT=%s", internal.UserAgent()),
 	}
 
+	credentialEnvVars, err := t.credentialEnvVars(ctx)
+	if err != nil {
+		return err
+	}
+	envVars = append(envVars, credentialEnvVars...)
+
 	spanCtx := trace.SpanContextFromContext(ctx)
 	if spanCtx.HasTraceID() {
 		envVars = append(envVars, fmt.Sprintf("ARM_CORRELATION_REQUEST_ID=%s", spanCtx.TraceID().String()))
@@ -119,12 +136,54 @@ func (t *TerraformProvider) Initialize(ctx context.Context, projectPath string,
 	return nil
 }
 
+// credentialEnvVars returns the environment variables the Terraform providers authenticate with. The service principal or
+// managed identity azd is logged in as is passed through, unless the user configured the credential of the providers
+// through ARM_* environment variables.
+func (t *TerraformProvider) credentialEnvVars(ctx context.Context) ([]string, error) {
+	userConfigured := slices.ContainsFunc(armCredentialEnvVars, func(name string) bool {
+		return os.Getenv(name) != ""
+	})
+
+	if !userConfigured && t.authManager != nil {
+		envVars, err := t.authManager.ArmEnvForCurrentUser(ctx, &auth.ArmEnvOptions{
+			TokenDir:           filepath.Join(t.dataDirPath(), "auth"),
+			AuxiliaryTenantIDs: t.options.AuxiliaryTenants,
+			TenantID:           account.TenantFromContext(ctx),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("passing the azd credential to terraform: %w", err)
+		}
+
+		if len(envVars) > 0 {
+			log.Printf("passing the azd credential to terraform")
+			return envVars, nil
+		}
+	}
+
+	envVars := []string{
+		// Required when using service principal login
+		fmt.Sprintf("ARM_TENANT_ID=%s", os.Getenv("ARM_TENANT_ID")),
+		fmt.Sprintf("ARM_CLIENT_ID=%s", os.Getenv("ARM_CLIENT_ID")),
+		fmt.Sprintf("ARM_CLIENT_SECRET=%s", os.Getenv("ARM_CLIENT_SECRET")),
+	}
+
+	if len(t.options.AuxiliaryTenants) > 0 {
+		envVars = append(envVars,
+			fmt.Sprintf("ARM_AUXILIARY_TENANT_IDS=%s", strings.Join(t.options.AuxiliaryTenants, ";")))
+	}
+
+	return envVars, nil
+}
+
 // EnsureEnv ensures that the environment is in a provision-ready state with required values set, prompting the user if
 // values are unset.
 //
 // An environment is considered to be in a provision-ready state if it contains both an AZURE_SUBSCRIPTION_ID and
 // AZURE_LOCATION value.
 func (t *TerraformProvider) EnsureEnv(ctx context.Context) error {
+	provisioning.WarnIgnoredResourceGroup(
+		ctx, t.console, t.options.ResourceGroup, "the resource groups of Terraform are declared by its modules")
+
 	return provisioning.EnsureSubscriptionAndLocation(
 		ctx,
 		t.envManager,
@@ -143,11 +202,25 @@ func (t *TerraformProvider) plan(ctx context.Context) (*provisioning.Deployment,
 
 	modulePath := t.modulePath()
 
+	// The workspace is mapped before 'terraform init' creates the data directory, which tells the environments
+	// initialized before the mapping existed apart from the new ones
+	if isRemoteBackendConfig {
+		if _, err := t.workspaceName(ctx); err != nil {
+			return nil, nil, fmt.Errorf("mapping terraform workspace: %w", err)
+		}
+	}
+
 	initRes, err := t.init(ctx, isRemoteBackendConfig)
 	if err != nil {
 		return nil, nil, fmt.Errorf("terraform init failed: %s , err: %w", initRes, err)
 	}
 
+	if isRemoteBackendConfig {
+		if err := t.ensureWorkspace(ctx); err != nil {
+			return nil, nil, fmt.Errorf("selecting terraform workspace: %w", err)
+		}
+	}
+
 	err = t.createInputParametersFile(ctx, t.parametersTemplateFilePath(), t.parametersFilePath())
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating parameters file: %w", err)
@@ -201,6 +274,12 @@ func (t *TerraformProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 		return nil, err
 	}
 
+	if isRemoteBackendConfig {
+		if err := t.verifyWorkspace(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	runResult, err := t.cli.Apply(ctx, modulePath, applyArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("template Deploy failed: %s , err:%w", runResult, err)
@@ -252,6 +331,12 @@ func (t *TerraformProvider) Destroy(
 
 	modulePath := t.modulePath()
 
+	if isRemoteBackendConfig {
+		if err := t.ensureWorkspace(ctx); err != nil {
+			return nil, fmt.Errorf("selecting terraform workspace: %w", err)
+		}
+	}
+
 	//load the deployment result
 	outputs, err := t.createOutputParameters(ctx, modulePath, isRemoteBackendConfig)
 	if err != nil {
@@ -263,11 +348,24 @@ func (t *TerraformProvider) Destroy(
 	// as it could be an interactive operation if it needs confirmation
 	t.console.StopSpinner(ctx, "", input.Step)
 	destroyArgs := t.createDestroyArgs(isRemoteBackendConfig, options.Force())
+	if isRemoteBackendConfig {
+		if err := t.verifyWorkspace(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	runResult, err := t.cli.Destroy(ctx, modulePath, destroyArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("template Deploy failed: %s, err: %w", runResult, err)
 	}
 
+	// The workspace state is empty once destroyed, so it is safe to remove it when purging
+	if isRemoteBackendConfig && options.Purge() {
+		if err := t.deleteWorkspace(ctx); err != nil {
+			return nil, fmt.Errorf("deleting terraform workspace: %w", err)
+		}
+	}
+
 	return &provisioning.DestroyResult{
 		InvalidatedEnvKeys: slices.Collect(maps.Keys(outputs)),
 	}, nil
@@ -285,6 +383,12 @@ func (t *TerraformProvider) State(
 	t.console.Message(ctx, "Retrieving terraform state...")
 	modulePath := t.modulePath()
 
+	if isRemoteBackendConfig {
+		if err := t.ensureWorkspace(ctx); err != nil {
+			return nil, fmt.Errorf("selecting terraform workspace: %w", err)
+		}
+	}
+
 	terraformState, err := t.showCurrentState(ctx, modulePath, isRemoteBackendConfig)
 	if err != nil {
 		return nil, fmt.Errorf("fetching terraform state failed: %w", err)
@@ -677,18 +781,39 @@ func (t *TerraformProvider) createInputParametersFile(
 	templateFilePath string,
 	inputFilePath string,
 ) error {
+	// Copy the parameter template file to the environment working directory and do substitutions.
+	replaced, err := t.substituteParametersTemplate(ctx, templateFilePath)
+	if err != nil {
+		return err
+	}
+
+	writeDir := filepath.Dir(inputFilePath)
+	if err := os.MkdirAll(writeDir, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating directory structure: %w", err)
+	}
+
+	log.Printf("Writing parameters file to: %s", inputFilePath)
+	err = os.WriteFile(inputFilePath, []byte(replaced), 0600)
+	if err != nil {
+		return fmt.Errorf("writing parameter file: %w", err)
+	}
+
+	return nil
+}
 
+// substituteParametersTemplate reads the parameter template file and substitutes the environment variables in it.
+func (t *TerraformProvider) substituteParametersTemplate(ctx context.Context, templateFilePath string) (string, error) {
 	principalId, err := t.curPrincipal.CurrentPrincipalId(ctx)
 	if err != nil {
-		return fmt.Errorf("fetching current principal id: %w", err)
+		return "", fmt.Errorf("fetching current principal id: %w", err)
 	}
 
-	// Copy the parameter template file to the environment working directory and do substitutions.
 	log.Printf("Reading parameters template file from: %s", templateFilePath)
 	parametersBytes, err := os.ReadFile(templateFilePath)
 	if err != nil {
-		return fmt.Errorf("reading parameter file template: %w", err)
+		return "", fmt.Errorf("reading parameter file template: %w", err)
 	}
+
 	replaced, err := envsubst.Eval(string(parametersBytes), func(name string) string {
 		if name == environment.PrincipalIdEnvVarName {
 			return principalId
@@ -696,23 +821,11 @@ func (t *TerraformProvider) createInputParametersFile(
 
 		return t.env.Getenv(name)
 	})
-
 	if err != nil {
-		return fmt.Errorf("substituting parameter file: %w", err)
+		return "", fmt.Errorf("substituting parameter file: %w", err)
 	}
 
-	writeDir := filepath.Dir(inputFilePath)
-	if err := os.MkdirAll(writeDir, osutil.PermissionDirectory); err != nil {
-		return fmt.Errorf("creating directory structure: %w", err)
-	}
-
-	log.Printf("Writing parameters file to: %s", inputFilePath)
-	err = os.WriteFile(inputFilePath, []byte(replaced), 0600)
-	if err != nil {
-		return fmt.Errorf("writing parameter file: %w", err)
-	}
-
-	return nil
+	return replaced, nil
 }
 
 // terraformShowOutput is a model type for the output of `terraform show` for a tfstate file.
@@ -6,17 +6,23 @@ package terraform
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	terraformTools "github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -59,6 +65,50 @@ func TestTerraformPlan(t *testing.T) {
 	require.NotEmpty(t, deploymentPlan.localStateFilePath)
 }
 
+func TestTerraformPlanRemoteBackend(t *testing.T) {
+	run := func(t *testing.T, initialized bool) (*TerraformProvider, *[]string) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareGenericMocks(mockContext.CommandRunner)
+		preparePlanningMocks(mockContext.CommandRunner)
+		workspaceCommands := prepareWorkspaceMocks(mockContext.CommandRunner, "default", []string{"default"})
+
+		infraProvider := createTerraformProvider(t, mockContext)
+		infraProvider.projectPath = createRemoteBackendProject(t)
+		if initialized {
+			require.NoError(t, os.MkdirAll(infraProvider.dataDirPath(), osutil.PermissionDirectory))
+		}
+
+		// As terraform, init creates the data directory
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "terraform" && strings.Contains(command, "init")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			err := os.MkdirAll(infraProvider.dataDirPath(), osutil.PermissionDirectory)
+			return exec.NewRunResult(0, "Terraform has been successfully initialized!", ""), err
+		})
+
+		_, _, err := infraProvider.plan(*mockContext.Context)
+		require.NoError(t, err)
+
+		return infraProvider, workspaceCommands
+	}
+
+	t.Run("NewEnvironment", func(t *testing.T) {
+		infraProvider, workspaceCommands := run(t, false)
+
+		workspace, _ := infraProvider.env.Config.GetString(workspaceConfigPath)
+		require.Equal(t, "test-env", workspace)
+		require.Equal(t, []string{"new test-env"}, *workspaceCommands)
+	})
+
+	t.Run("InitializedBeforeMapping", func(t *testing.T) {
+		infraProvider, workspaceCommands := run(t, true)
+
+		workspace, _ := infraProvider.env.Config.GetString(workspaceConfigPath)
+		require.Equal(t, "default", workspace)
+		require.Empty(t, *workspaceCommands)
+	})
+}
+
 func TestTerraformDestroy(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	prepareGenericMocks(mockContext.CommandRunner)
@@ -97,6 +147,151 @@ func TestTerraformState(t *testing.T) {
 	)
 }
 
+func TestTerraformEnsureWorkspace(t *testing.T) {
+	t.Run("CreatesWorkspaceForNewEnvironment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareGenericMocks(mockContext.CommandRunner)
+		workspaceCommands := prepareWorkspaceMocks(mockContext.CommandRunner, "default", []string{"default"})
+
+		infraProvider := createTerraformProvider(t, mockContext)
+		infraProvider.projectPath = t.TempDir()
+
+		err := infraProvider.ensureWorkspace(*mockContext.Context)
+		require.NoError(t, err)
+
+		workspace, has := infraProvider.env.Config.GetString(workspaceConfigPath)
+		require.True(t, has)
+		require.Equal(t, "test-env", workspace)
+		require.Contains(t, *workspaceCommands, "new test-env")
+	})
+
+	t.Run("SelectsExistingWorkspace", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareGenericMocks(mockContext.CommandRunner)
+		workspaceCommands := prepareWorkspaceMocks(mockContext.CommandRunner, "default", []string{"default", "test-env"})
+
+		infraProvider := createTerraformProvider(t, mockContext)
+		infraProvider.projectPath = t.TempDir()
+
+		err := infraProvider.ensureWorkspace(*mockContext.Context)
+		require.NoError(t, err)
+		require.Contains(t, *workspaceCommands, "select test-env")
+		require.NotContains(t, *workspaceCommands, "new test-env")
+	})
+
+	t.Run("KeepsDefaultWorkspaceForExistingState", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareGenericMocks(mockContext.CommandRunner)
+		workspaceCommands := prepareWorkspaceMocks(mockContext.CommandRunner, "default", []string{"default"})
+
+		infraProvider := createTerraformProvider(t, mockContext)
+		infraProvider.projectPath = t.TempDir()
+		require.NoError(t, os.MkdirAll(infraProvider.dataDirPath(), osutil.PermissionDirectory))
+
+		err := infraProvider.ensureWorkspace(*mockContext.Context)
+		require.NoError(t, err)
+
+		workspace, _ := infraProvider.env.Config.GetString(workspaceConfigPath)
+		require.Equal(t, "default", workspace)
+		require.Empty(t, *workspaceCommands)
+	})
+
+	t.Run("ConflictingWorkspaceOverride", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		prepareGenericMocks(mockContext.CommandRunner)
+		prepareWorkspaceMocks(mockContext.CommandRunner, "default", []string{"default"})
+		t.Setenv("TF_WORKSPACE", "other-env")
+
+		infraProvider := createTerraformProvider(t, mockContext)
+		infraProvider.projectPath = t.TempDir()
+
+		err := infraProvider.ensureWorkspace(*mockContext.Context)
+		require.Error(t, err)
+	})
+}
+
+func TestTerraformVerifyWorkspace(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	prepareWorkspaceMocks(mockContext.CommandRunner, "other-env", []string{"default", "other-env", "test-env"})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	require.NoError(t, infraProvider.env.Config.Set(workspaceConfigPath, "test-env"))
+
+	err := infraProvider.verifyWorkspace(*mockContext.Context)
+	require.ErrorContains(t, err, "terraform workspace 'other-env' is selected")
+}
+
+func TestTerraformDeleteWorkspace(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	workspaceCommands := prepareWorkspaceMocks(mockContext.CommandRunner, "test-env", []string{"default", "test-env"})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	require.NoError(t, infraProvider.env.Config.Set(workspaceConfigPath, "test-env"))
+
+	err := infraProvider.deleteWorkspace(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, []string{"select default", "delete test-env"}, *workspaceCommands)
+
+	_, has := infraProvider.env.Config.Get(workspaceConfigPath)
+	require.False(t, has)
+}
+
+func TestTerraformCredentialEnvVars(t *testing.T) {
+	for _, name := range armCredentialEnvVars {
+		t.Setenv(name, "")
+	}
+
+	newProvider := func(authManager *mockAuthManager) *TerraformProvider {
+		return &TerraformProvider{
+			env:         environment.NewWithValues("test-env", nil),
+			authManager: authManager,
+			projectPath: t.TempDir(),
+			options: provisioning.Options{
+				Path:             "infra",
+				AuxiliaryTenants: []string{"tenant-2", "tenant-3"},
+			},
+		}
+	}
+
+	t.Run("PassesAzdCredential", func(t *testing.T) {
+		authManager := &mockAuthManager{
+			env: []string{"ARM_TENANT_ID=tenant", "ARM_CLIENT_ID=client", "ARM_USE_OIDC=true"},
+		}
+		provider := newProvider(authManager)
+
+		envVars, err := provider.credentialEnvVars(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, authManager.env, envVars)
+		require.Equal(t, []string{"tenant-2", "tenant-3"}, authManager.options.AuxiliaryTenantIDs)
+		require.Equal(t, filepath.Join(provider.dataDirPath(), "auth"), authManager.options.TokenDir)
+	})
+
+	t.Run("UserConfigured", func(t *testing.T) {
+		t.Setenv("ARM_CLIENT_ID", "user-client")
+		t.Setenv("ARM_TENANT_ID", "user-tenant")
+		t.Setenv("ARM_CLIENT_SECRET", "user-secret")
+
+		authManager := &mockAuthManager{env: []string{"ARM_CLIENT_ID=client"}}
+		envVars, err := newProvider(authManager).credentialEnvVars(context.Background())
+		require.NoError(t, err)
+		require.Nil(t, authManager.options)
+		require.Equal(t, []string{
+			"ARM_TENANT_ID=user-tenant",
+			"ARM_CLIENT_ID=user-client",
+			"ARM_CLIENT_SECRET=user-secret",
+			"ARM_AUXILIARY_TENANT_IDS=tenant-2;tenant-3",
+		}, envVars)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		authManager := &mockAuthManager{err: errors.New("no system access token")}
+		_, err := newProvider(authManager).credentialEnvVars(context.Background())
+		require.ErrorContains(t, err, "no system access token")
+	})
+}
+
 func createTerraformProvider(t *testing.T, mockContext *mocks.MockContext) *TerraformProvider {
 	projectDir := "../../../../test/functional/testdata/samples/resourcegroupterraform"
 	options := provisioning.Options{
@@ -135,6 +330,7 @@ func createTerraformProvider(t *testing.T, mockContext *mocks.MockContext) *Terr
 		mockContext.Console,
 		&mockCurrentPrincipal{},
 		prompt.NewDefaultPrompter(env, mockContext.Console, accountManager, resourceService, cloud.AzurePublic()),
+		&mockAuthManager{},
 	)
 
 	err := provider.Initialize(*mockContext.Context, projectDir, options)
@@ -143,6 +339,28 @@ func createTerraformProvider(t *testing.T, mockContext *mocks.MockContext) *Terr
 	return provider.(*TerraformProvider)
 }
 
+// createRemoteBackendProject copies the terraform sample to a temporary project using an azurerm backend
+func createRemoteBackendProject(t *testing.T) string {
+	sampleDir := "../../../../test/functional/testdata/samples/resourcegroupterraform/infra"
+	infraDir := filepath.Join(t.TempDir(), "infra")
+	require.NoError(t, os.MkdirAll(infraDir, osutil.PermissionDirectory))
+
+	entries, err := os.ReadDir(sampleDir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		contents, err := os.ReadFile(filepath.Join(sampleDir, entry.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(infraDir, entry.Name()), contents, osutil.PermissionFile))
+	}
+
+	backend := "terraform {\n  backend \"azurerm\" {}\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "backend.tf"), []byte(backend), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(infraDir, "provider.conf.json"), []byte(`{"key": "${AZURE_ENV_NAME}"}`), osutil.PermissionFile))
+
+	return filepath.Dir(infraDir)
+}
+
 func prepareGenericMocks(commandRunner *mockexec.MockCommandRunner) {
 	commandRunner.When(func(args exec.RunArgs, command string) bool {
 		return strings.Contains(command, "terraform version")
@@ -213,6 +431,52 @@ func prepareDestroyMocks(commandRunner *mockexec.MockCommandRunner) {
 	})
 }
 
+// prepareWorkspaceMocks mocks the terraform workspace commands and returns the workspace commands which modify the
+// selected workspace (ex: "new test-env")
+func prepareWorkspaceMocks(
+	commandRunner *mockexec.MockCommandRunner,
+	selected string,
+	workspaces []string,
+) *[]string {
+	workspaceCommands := []string{}
+
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && slices.Contains(args.Args, "workspace")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		subCommand := args.Args[len(args.Args)-1]
+		switch subCommand {
+		case "show":
+			return exec.NewRunResult(0, selected+"\n", ""), nil
+		case "list":
+			lines := []string{}
+			for _, workspace := range workspaces {
+				prefix := "  "
+				if workspace == selected {
+					prefix = "* "
+				}
+				lines = append(lines, prefix+workspace)
+			}
+			return exec.NewRunResult(0, strings.Join(lines, "\n"), ""), nil
+		default:
+			workspaceCommands = append(workspaceCommands, strings.Join(args.Args[len(args.Args)-2:], " "))
+			return exec.NewRunResult(0, "", ""), nil
+		}
+	})
+
+	return &workspaceCommands
+}
+
+type mockAuthManager struct {
+	env     []string
+	err     error
+	options *auth.ArmEnvOptions
+}
+
+func (m *mockAuthManager) ArmEnvForCurrentUser(_ context.Context, options *auth.ArmEnvOptions) ([]string, error) {
+	m.options = options
+	return m.env, m.err
+}
+
 type mockCurrentPrincipal struct{}
 
 func (m *mockCurrentPrincipal) CurrentPrincipalId(_ context.Context) (string, error) {
@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+)
+
+const (
+	// workspaceConfigPath is the environment config path storing the Terraform workspace mapped to the environment
+	workspaceConfigPath = "infra.terraform.workspace"
+	// defaultWorkspace is the workspace Terraform creates and selects when no workspace is configured
+	defaultWorkspace = "default"
+)
+
+// workspaceName returns the Terraform workspace mapped to the current environment, creating the mapping when missing.
+//
+// New environments are mapped to a workspace with the same name as the environment. Environments which were already
+// initialized against the default workspace stay mapped to it so their existing state is not orphaned. The mapping must
+// be created before 'terraform init' runs, since init creates the data directory of new environments too.
+func (t *TerraformProvider) workspaceName(ctx context.Context) (string, error) {
+	if name, has := t.env.Config.GetString(workspaceConfigPath); has && name != "" {
+		return name, nil
+	}
+
+	name := t.env.Name()
+
+	// Terraform records the selected workspace in the 'environment' file of the data directory. A data directory without
+	// this file was initialized before the environment was mapped and has always been using the default workspace.
+	if _, err := os.Stat(t.dataDirPath()); err == nil {
+		if _, err := os.Stat(filepath.Join(t.dataDirPath(), "environment")); errors.Is(err, os.ErrNotExist) {
+			name = defaultWorkspace
+		}
+	}
+
+	if err := t.env.Config.Set(workspaceConfigPath, name); err != nil {
+		return "", fmt.Errorf("setting terraform workspace: %w", err)
+	}
+
+	if err := t.envManager.Save(ctx, t.env); err != nil {
+		return "", fmt.Errorf("saving terraform workspace: %w", err)
+	}
+
+	return name, nil
+}
+
+// ensureWorkspace creates or selects the Terraform workspace mapped to the current environment.
+// The backend must have been initialized.
+func (t *TerraformProvider) ensureWorkspace(ctx context.Context) error {
+	name, err := t.workspaceName(ctx)
+	if err != nil {
+		return err
+	}
+
+	// TF_WORKSPACE overrides the selected workspace on every terraform command
+	if override := os.Getenv("TF_WORKSPACE"); override != "" && override != name {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"the TF_WORKSPACE environment variable is set to '%s' but environment '%s' is mapped to workspace '%s'",
+				override, t.env.Name(), name),
+			Suggestion: "Unset the TF_WORKSPACE environment variable, or update the mapping with " +
+				fmt.Sprintf("'azd env config set %s <workspace>'.", workspaceConfigPath),
+		}
+	}
+
+	modulePath := t.modulePath()
+	current, err := t.cli.WorkspaceShow(ctx, modulePath)
+	if err != nil {
+		return err
+	}
+
+	if current == name {
+		return nil
+	}
+
+	workspaces, err := t.cli.WorkspaceList(ctx, modulePath)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(workspaces, name) {
+		log.Printf("selecting terraform workspace '%s'", name)
+		return t.cli.WorkspaceSelect(ctx, modulePath, name)
+	}
+
+	log.Printf("creating terraform workspace '%s'", name)
+	return t.cli.WorkspaceNew(ctx, modulePath, name)
+}
+
+// verifyWorkspace ensures the selected Terraform workspace is the one mapped to the current environment, preventing
+// changes from being applied to the state of another environment.
+func (t *TerraformProvider) verifyWorkspace(ctx context.Context) error {
+	name, err := t.workspaceName(ctx)
+	if err != nil {
+		return err
+	}
+
+	current, err := t.cli.WorkspaceShow(ctx, t.modulePath())
+	if err != nil {
+		return err
+	}
+
+	if current != name {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"terraform workspace '%s' is selected but environment '%s' is mapped to workspace '%s'",
+				current, t.env.Name(), name),
+			Suggestion: fmt.Sprintf(
+				"Run 'terraform workspace select %s' or re-run the command to select the mapped workspace.", name),
+		}
+	}
+
+	return nil
+}
+
+// deleteWorkspace deletes the Terraform workspace mapped to the current environment and removes the mapping.
+// The default workspace cannot be deleted and is left in place.
+func (t *TerraformProvider) deleteWorkspace(ctx context.Context) error {
+	name, err := t.workspaceName(ctx)
+	if err != nil {
+		return err
+	}
+
+	if name != defaultWorkspace {
+		modulePath := t.modulePath()
+
+		// The selected workspace cannot be deleted
+		if err := t.cli.WorkspaceSelect(ctx, modulePath, defaultWorkspace); err != nil {
+			return err
+		}
+
+		if err := t.cli.WorkspaceDelete(ctx, modulePath, name); err != nil {
+			return err
+		}
+	}
+
+	if err := t.env.Config.Unset(workspaceConfigPath); err != nil {
+		return fmt.Errorf("removing terraform workspace: %w", err)
+	}
+
+	return t.envManager.Save(ctx, t.env)
+}
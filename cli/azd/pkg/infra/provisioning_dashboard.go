@@ -0,0 +1,233 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/nathan-fiscaletti/consolesize-go"
+)
+
+const (
+	// Switches to the alternate screen of the terminal and hides the cursor, so the dashboard doesn't scroll the output
+	// of the command, which is restored when the dashboard is closed
+	dashboardEnterScreen = "\x1b[?1049h\x1b[?25l"
+	dashboardLeaveScreen = "\x1b[?25h\x1b[?1049l"
+	// Moves the cursor to the top left of the screen and clears it, before each frame
+	dashboardClearScreen = "\x1b[H\x1b[2J"
+
+	// The number of recent errors shown by the dashboard
+	dashboardMaxErrors = 5
+	// The size of the screen when it can't be found
+	dashboardDefaultWidth  = 80
+	dashboardDefaultHeight = 24
+)
+
+// dashboardResource is a resource of a deployment shown by the dashboard.
+type dashboardResource struct {
+	ResourceGroup string
+	Type          string
+	Name          string
+	State         string
+	Duration      time.Duration
+}
+
+// provisioningDashboard is the full-screen view of the progress of a provisioning, with a live tree of the resources of
+// the deployment by resource group, see the ux.provisionView user config.
+type provisioningDashboard struct {
+	writer         io.Writer
+	deploymentName string
+	portalUrl      string
+	started        time.Time
+	// Guards active and closed, since the dashboard is also closed by the interrupt handler of the console
+	mu sync.Mutex
+	// Whether the terminal shows the dashboard
+	active bool
+	// Whether the dashboard was closed, it isn't drawn again then
+	closed bool
+	// The resources of the deployment by resource id
+	resources map[string]dashboardResource
+	// The most recent errors of the failed resources, oldest first
+	errors []string
+}
+
+func newProvisioningDashboard(writer io.Writer, deploymentName string, started time.Time) *provisioningDashboard {
+	return &provisioningDashboard{
+		writer:         writer,
+		deploymentName: deploymentName,
+		started:        started,
+		resources:      map[string]dashboardResource{},
+	}
+}
+
+// update sets the state of a resource of the deployment. The error of a resource which failed is added to the recent
+// errors the first time it's reported.
+func (d *provisioningDashboard) update(resourceId string, resource dashboardResource, errorMessage string) {
+	previous, has := d.resources[resourceId]
+	d.resources[resourceId] = resource
+
+	if resource.State == "Failed" && (!has || previous.State != "Failed") && errorMessage != "" {
+		d.errors = append(d.errors, fmt.Sprintf("%s: %s", resource.Name, errorMessage))
+		if len(d.errors) > dashboardMaxErrors {
+			d.errors = d.errors[len(d.errors)-dashboardMaxErrors:]
+		}
+	}
+}
+
+// draw renders the dashboard on the whole screen, switching to the alternate screen of the terminal the first time.
+func (d *provisioningDashboard) draw(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return
+	}
+
+	width, height := consolesize.GetConsoleSize()
+	if width <= 0 || height <= 0 {
+		width, height = dashboardDefaultWidth, dashboardDefaultHeight
+	}
+
+	frame := dashboardClearScreen + strings.Join(d.render(width, height, now), "\n")
+	if !d.active {
+		frame = dashboardEnterScreen + frame
+		d.active = true
+	}
+
+	fmt.Fprint(d.writer, frame)
+}
+
+// close restores the screen of the terminal as it was before the dashboard was drawn.
+func (d *provisioningDashboard) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.active {
+		fmt.Fprint(d.writer, dashboardLeaveScreen)
+		d.active = false
+	}
+	d.closed = true
+}
+
+// isActive returns whether the terminal shows the dashboard
+func (d *provisioningDashboard) isActive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.active
+}
+
+// render returns the lines of the dashboard for a screen of the given size. When the resources don't fit on the screen,
+// the last ones are replaced by their count.
+func (d *provisioningDashboard) render(width int, height int, now time.Time) []string {
+	header := []string{
+		fmt.Sprintf("%s %s",
+			output.WithBold("Provisioning %s", d.deploymentName),
+			output.WithGrayFormat("(%s)", now.Sub(d.started).Truncate(time.Second))),
+	}
+	if d.portalUrl != "" {
+		header = append(header, output.WithLinkFormat(truncateText(d.portalUrl, width)))
+	}
+	header = append(header, output.HorizontalLine(width))
+
+	succeeded, running, failed := 0, 0, 0
+	for _, resource := range d.resources {
+		switch resource.State {
+		case "Succeeded":
+			succeeded++
+		case "Failed":
+			failed++
+		default:
+			running++
+		}
+	}
+
+	footer := []string{
+		output.HorizontalLine(width),
+		fmt.Sprintf("%d succeeded, %d in progress, %d failed", succeeded, running, failed),
+	}
+	if len(d.errors) > 0 {
+		footer = append(footer, "", output.WithErrorFormat("Recent errors:"))
+		for _, message := range d.errors {
+			footer = append(footer, "  "+truncateText(message, width-2))
+		}
+	}
+
+	tree := d.renderTree(width)
+	available := max(height-len(header)-len(footer), 1)
+	if len(tree) > available {
+		hidden := len(tree) - available + 1
+		tree = append(tree[:available-1], output.WithGrayFormat("  ... %d more", hidden))
+	}
+
+	return slices.Concat(header, tree, footer)
+}
+
+// renderTree returns the lines of the resources grouped by resource group, sorted by name.
+func (d *provisioningDashboard) renderTree(width int) []string {
+	groups := map[string][]dashboardResource{}
+	for _, resource := range d.resources {
+		groups[resource.ResourceGroup] = append(groups[resource.ResourceGroup], resource)
+	}
+
+	lines := []string{}
+	for _, group := range slices.Sorted(maps.Keys(groups)) {
+		resources := groups[group]
+		slices.SortFunc(resources, func(a, b dashboardResource) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		if group == "" {
+			group = "Subscription"
+		}
+		lines = append(lines, output.WithHighLightFormat(truncateText(group, width)))
+
+		for i, resource := range resources {
+			connector := output.Symbol("├─", "|-")
+			if i == len(resources)-1 {
+				connector = output.Symbol("└─", "`-")
+			}
+
+			text := fmt.Sprintf("%s: %s", resource.Type, resource.Name)
+			if resource.Duration > 0 {
+				text += fmt.Sprintf(" (%s)", resource.Duration.Truncate(time.Second))
+			}
+
+			// the connector, the state and their spaces take 7 columns
+			lines = append(lines, fmt.Sprintf("  %s %s %s",
+				connector, dashboardStateSymbol(resource.State), truncateText(text, width-7)))
+		}
+	}
+
+	return lines
+}
+
+// dashboardStateSymbol returns the symbol of the provisioning state of a resource.
+func dashboardStateSymbol(state string) string {
+	switch state {
+	case "Succeeded":
+		return output.WithSuccessFormat(output.CheckMark())
+	case "Failed":
+		return output.WithErrorFormat("x")
+	default:
+		return output.WithHighLightFormat(output.Symbol("…", "~"))
+	}
+}
+
+// truncateText shortens the text to the given number of characters, ending it with an ellipsis when it's shortened.
+func truncateText(text string, length int) string {
+	runes := []rune(text)
+	if length <= 0 || len(runes) <= length {
+		return text
+	}
+
+	return string(runes[:length-1]) + output.Symbol("…", "~")
+}
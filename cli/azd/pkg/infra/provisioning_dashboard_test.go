@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProvisioningDashboard_Render(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("TreeByResourceGroup", func(t *testing.T) {
+		dashboard := newProvisioningDashboard(&bytes.Buffer{}, "dev-1234", started)
+		dashboard.portalUrl = "https://portal.azure.com/deployment"
+		dashboard.update("/rg/app", dashboardResource{
+			ResourceGroup: "rg-dev", Type: "Container App", Name: "app", State: "Running",
+		}, "")
+		dashboard.update("/rg/kv", dashboardResource{
+			ResourceGroup: "rg-dev", Type: "Key Vault", Name: "kv", State: "Succeeded", Duration: 12 * time.Second,
+		}, "")
+		dashboard.update("/rg", dashboardResource{
+			Type: "Resource group", Name: "rg-dev", State: "Succeeded", Duration: 2 * time.Second,
+		}, "")
+
+		lines := dashboard.render(80, 24, started.Add(90*time.Second))
+		text := strings.Join(lines, "\n")
+
+		require.Contains(t, lines[0], "Provisioning dev-1234")
+		require.Contains(t, lines[0], "(1m30s)")
+		require.Contains(t, text, "https://portal.azure.com/deployment")
+		require.Contains(t, text, "2 succeeded, 1 in progress, 0 failed")
+		require.NotContains(t, text, "Recent errors:")
+
+		subscription := indexOf(lines, "Subscription")
+		require.Contains(t, lines[subscription+2], "rg-dev")
+		group := subscription + 2
+		require.Contains(t, lines[subscription+1], "Resource group: rg-dev (2s)")
+		require.Contains(t, lines[group+1], "Container App: app")
+		require.Contains(t, lines[group+2], "Key Vault: kv (12s)")
+	})
+
+	t.Run("RecentErrors", func(t *testing.T) {
+		dashboard := newProvisioningDashboard(&bytes.Buffer{}, "dev-1234", started)
+		for i := range dashboardMaxErrors + 2 {
+			id := fmt.Sprintf("/rg/res%d", i)
+			resource := dashboardResource{ResourceGroup: "rg-dev", Type: "Storage", Name: fmt.Sprintf("res%d", i)}
+
+			resource.State = "Running"
+			dashboard.update(id, resource, "")
+			resource.State = "Failed"
+			dashboard.update(id, resource, fmt.Sprintf("error %d", i))
+			// reporting the same failure again doesn't add it twice
+			dashboard.update(id, resource, fmt.Sprintf("error %d", i))
+		}
+
+		require.Len(t, dashboard.errors, dashboardMaxErrors)
+		require.Equal(t, "res2: error 2", dashboard.errors[0])
+		require.Equal(t, "res6: error 6", dashboard.errors[dashboardMaxErrors-1])
+
+		text := strings.Join(dashboard.render(80, 40, started), "\n")
+		require.Contains(t, text, "0 succeeded, 0 in progress, 7 failed")
+		require.Contains(t, text, "Recent errors:")
+		require.Contains(t, text, "res6: error 6")
+		require.NotContains(t, text, "res1: error 1")
+	})
+
+	t.Run("TruncatedToScreen", func(t *testing.T) {
+		dashboard := newProvisioningDashboard(&bytes.Buffer{}, "dev-1234", started)
+		for i := range 30 {
+			dashboard.update(fmt.Sprintf("/rg/res%02d", i), dashboardResource{
+				ResourceGroup: "rg-dev", Type: "Storage", Name: fmt.Sprintf("res%02d", i), State: "Succeeded",
+			}, "")
+		}
+
+		lines := dashboard.render(80, 20, started)
+		require.Len(t, lines, 20)
+		// 2 header lines and 2 footer lines leave 16 lines, of which the last counts the hidden resources
+		require.Contains(t, lines[17], "... 16 more")
+	})
+}
+
+func Test_ProvisioningDashboard_DrawAndClose(t *testing.T) {
+	writer := &bytes.Buffer{}
+	dashboard := newProvisioningDashboard(writer, "dev-1234", time.Now())
+
+	// closing a dashboard which was never drawn leaves the screen as it is
+	newProvisioningDashboard(writer, "dev-1234", time.Now()).close()
+	require.Empty(t, writer.String())
+
+	dashboard.draw(time.Now())
+	dashboard.draw(time.Now())
+	require.Equal(t, 1, strings.Count(writer.String(), dashboardEnterScreen))
+	require.Equal(t, 2, strings.Count(writer.String(), dashboardClearScreen))
+
+	dashboard.close()
+	dashboard.close()
+	require.Equal(t, 1, strings.Count(writer.String(), dashboardLeaveScreen))
+	require.True(t, strings.HasSuffix(writer.String(), dashboardLeaveScreen))
+
+	// the dashboard closed by an interrupt isn't drawn again by a late progress report
+	dashboard.draw(time.Now())
+	require.True(t, strings.HasSuffix(writer.String(), dashboardLeaveScreen))
+}
+
+func Test_TruncateText(t *testing.T) {
+	require.Equal(t, "storage", truncateText("storage", 7))
+	require.Equal(t, "storage", truncateText("storage", 0))
+	require.Equal(t, 5, len([]rune(truncateText("storage", 5))))
+	require.True(t, strings.HasPrefix(truncateText("storage", 5), "stor"))
+}
+
+func indexOf(lines []string, text string) int {
+	for i, line := range lines {
+		if strings.Contains(line, text) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
@@ -21,6 +23,31 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 )
 
+const (
+	// The delay before the first progress report, which is short to be responsive in displaying initial progress
+	ProgressInitialPollDelay = 3 * time.Second
+
+	// The shortest delay between progress reports, used while the deployment is changing
+	progressMinPollDelay = 3 * time.Second
+	// The longest delay between progress reports, reached by backing off while the deployment isn't changing
+	progressMaxPollDelay = 30 * time.Second
+	// The number of deployment operations which add a second to the shortest delay. Each report lists all the
+	// operations of the deployment, so large deployments are polled less often to avoid being throttled.
+	progressOperationsPerDelaySecond = 25
+)
+
+type deploymentOperationObserverContextKey struct{}
+
+// DeploymentOperationObserver is notified of the deployment operations which state changed since the last progress
+// report, ex) an operation which started running or completed.
+type DeploymentOperationObserver func(ctx context.Context, operation *armresources.DeploymentOperation)
+
+// WithDeploymentOperationObserver returns a context which progress reports notify the observer of the updates of the
+// deployment operations, as they are found, in addition to displaying them on the console.
+func WithDeploymentOperationObserver(ctx context.Context, observer DeploymentOperationObserver) context.Context {
+	return context.WithValue(ctx, deploymentOperationObserverContextKey{}, observer)
+}
+
 // ProvisioningProgressDisplay displays interactive progress for an ongoing Azure provisioning operation.
 type ProvisioningProgressDisplay struct {
 	// Whether the deployment has started
@@ -30,6 +57,24 @@ type ProvisioningProgressDisplay struct {
 	resourceManager    ResourceManager
 	console            input.Console
 	deployment         Deployment
+	// The number of operations in the last progress report
+	operationCount int
+	// The resources which were in progress in the last progress report
+	inProgressResources string
+	// Whether the last progress report found changes in the deployment
+	changed bool
+	// The delay returned by the last call to NextPollDelay
+	pollDelay time.Duration
+	// The full-screen dashboard showing the progress instead of the spinner, when the tui provisioning view is used
+	dashboard *provisioningDashboard
+	// The resource type display names shown by the dashboard, by resource id
+	dashboardTypes map[string]string
+	// The operations of the last progress report, logged when the dashboard is closed
+	lastOperations []*armresources.DeploymentOperation
+	// Removes the interrupt handler restoring the terminal while the dashboard is shown
+	popInterruptHandler func()
+	// The provisioning state of the operations sent to the operation observer, by target resource id
+	observedStates map[string]string
 }
 
 func NewProvisioningProgressDisplay(
@@ -37,18 +82,29 @@ func NewProvisioningProgressDisplay(
 	console input.Console,
 	deployment Deployment,
 ) *ProvisioningProgressDisplay {
-	return &ProvisioningProgressDisplay{
+	display := &ProvisioningProgressDisplay{
 		displayedResources: map[string]bool{},
+		observedStates:     map[string]string{},
 		deployment:         deployment,
 		resourceManager:    rm,
 		console:            console,
 	}
+
+	// The dashboard takes the whole screen, which non-interactive consoles don't have
+	if output.CurrentProvisionView() == output.ProvisionViewTui && console.IsSpinnerInteractive() {
+		display.dashboard = newProvisioningDashboard(console.GetWriter(), deployment.Name(), time.Now())
+		display.dashboardTypes = map[string]string{}
+	}
+
+	return display
 }
 
 // ReportProgress reports the current deployment progress, setting the currently executing operation title and logging
 // progress.
 func (display *ProvisioningProgressDisplay) ReportProgress(
 	ctx context.Context, queryStart *time.Time) error {
+	display.changed = false
+
 	if !display.deploymentStarted {
 		_, err := display.deployment.Get(ctx)
 		if err != nil {
@@ -63,6 +119,10 @@ func (display *ProvisioningProgressDisplay) ReportProgress(
 			return err
 		}
 
+		if display.dashboard != nil {
+			display.dashboard.portalUrl = deploymentUrl
+		}
+
 		deploymentLink := fmt.Sprintf(output.WithLinkFormat("%s\n"), deploymentUrl)
 
 		display.console.EnsureBlankLine(ctx)
@@ -122,10 +182,186 @@ func (display *ProvisioningProgressDisplay) ReportProgress(
 	})
 
 	displayedResources := append(newlyDeployedResources, newlyFailedResources...)
+
+	runningResourceNames := make([]string, 0, len(runningDeployments))
+	for _, operation := range runningDeployments {
+		runningResourceNames = append(runningResourceNames, *operation.Properties.TargetResource.ResourceName)
+	}
+	slices.Sort(runningResourceNames)
+	inProgressResources := strings.Join(runningResourceNames, ",")
+
+	display.changed = len(displayedResources) > 0 || inProgressResources != display.inProgressResources
+	display.inProgressResources = inProgressResources
+	display.operationCount = len(operations)
+
+	if observer, has := ctx.Value(deploymentOperationObserverContextKey{}).(DeploymentOperationObserver); has {
+		display.notifyOperationUpdates(ctx, observer, operations)
+	}
+
+	if display.dashboard != nil {
+		display.lastOperations = operations
+		display.updateDashboard(ctx, operations)
+		return nil
+	}
+
 	display.logNewlyCreatedResources(ctx, displayedResources, runningDeployments)
 	return nil
 }
 
+// notifyOperationUpdates notifies the observer of the operations which state changed since they were last notified, in
+// the order they changed.
+func (display *ProvisioningProgressDisplay) notifyOperationUpdates(
+	ctx context.Context,
+	observer DeploymentOperationObserver,
+	operations []*armresources.DeploymentOperation,
+) {
+	updated := []*armresources.DeploymentOperation{}
+	for _, operation := range operations {
+		target := operation.Properties.TargetResource
+		if target == nil || target.ID == nil || operation.Properties.ProvisioningState == nil {
+			continue
+		}
+
+		if display.observedStates[*target.ID] == *operation.Properties.ProvisioningState {
+			continue
+		}
+
+		display.observedStates[*target.ID] = *operation.Properties.ProvisioningState
+		updated = append(updated, operation)
+	}
+
+	slices.SortStableFunc(updated, func(a, b *armresources.DeploymentOperation) int {
+		return convert.ToValueWithDefault(a.Properties.Timestamp, time.Time{}).Compare(
+			convert.ToValueWithDefault(b.Properties.Timestamp, time.Time{}))
+	})
+
+	for _, operation := range updated {
+		observer(ctx, operation)
+	}
+}
+
+// updateDashboard shows the state of the resources of the deployment on the full-screen dashboard.
+func (display *ProvisioningProgressDisplay) updateDashboard(
+	ctx context.Context,
+	operations []*armresources.DeploymentOperation,
+) {
+	if display.popInterruptHandler == nil {
+		// The dashboard replaces the spinner. Azd may exit on Ctrl+C while it's shown, which must restore the screen
+		// and the cursor of the terminal first
+		display.console.StopSpinner(ctx, "", input.Step)
+		display.popInterruptHandler = display.console.PushInterruptHandler(display.dashboard.close)
+	}
+
+	for _, operation := range operations {
+		target := operation.Properties.TargetResource
+		if target == nil || target.ID == nil || target.ResourceType == nil || target.ResourceName == nil {
+			continue
+		}
+
+		// Nested deployments and resource groups are shown by the tree itself
+		resourceType := azapi.AzureResourceType(*target.ResourceType)
+		if resourceType == azapi.AzureResourceTypeDeployment || resourceType == azapi.AzureResourceTypeResourceGroup {
+			continue
+		}
+
+		typeName, has := display.dashboardTypes[*target.ID]
+		if !has {
+			var err error
+			typeName, err = display.resourceManager.GetResourceTypeDisplayName(
+				ctx, display.deployment.SubscriptionId(), *target.ID, resourceType)
+			if err != nil || typeName == "" {
+				// Unlike the default view, the resources without a translation are shown with their resource type
+				typeName = azapi.GetResourceTypeDisplayName(resourceType)
+			}
+			if typeName == "" {
+				typeName = string(resourceType)
+			}
+			display.dashboardTypes[*target.ID] = typeName
+		}
+
+		resourceGroup := ""
+		if resourceId, err := arm.ParseResourceID(*target.ID); err == nil {
+			resourceGroup = resourceId.ResourceGroupName
+		}
+
+		duration := time.Duration(0)
+		if operation.Properties.Duration != nil {
+			duration, _ = convert.ParseDuration(*operation.Properties.Duration)
+		}
+
+		errorMessage := ""
+		if status := operation.Properties.StatusMessage; status != nil && status.Error != nil &&
+			status.Error.Message != nil {
+			errorMessage = *status.Error.Message
+		}
+
+		display.dashboard.update(*target.ID, dashboardResource{
+			ResourceGroup: resourceGroup,
+			Type:          typeName,
+			Name:          *target.ResourceName,
+			State:         convert.ToValueWithDefault(operation.Properties.ProvisioningState, ""),
+			Duration:      duration,
+		}, errorMessage)
+	}
+
+	display.dashboard.draw(time.Now())
+}
+
+// Close closes the full-screen dashboard, when the tui provisioning view is used, and logs the resources which completed
+// like the default view, so they remain in the output of the command.
+func (display *ProvisioningProgressDisplay) Close(ctx context.Context) {
+	if display.popInterruptHandler != nil {
+		display.popInterruptHandler()
+	}
+
+	if display.dashboard == nil || !display.dashboard.isActive() {
+		return
+	}
+
+	display.dashboard.close()
+
+	completed := []*armresources.DeploymentOperation{}
+	for _, operation := range display.lastOperations {
+		if operation.Properties.TargetResource == nil || operation.Properties.ProvisioningState == nil {
+			continue
+		}
+
+		switch *operation.Properties.ProvisioningState {
+		case string(armresources.ProvisioningStateSucceeded), string(armresources.ProvisioningStateFailed):
+			completed = append(completed, operation)
+		}
+	}
+
+	slices.SortFunc(completed, func(a, b *armresources.DeploymentOperation) int {
+		return convert.ToValueWithDefault(a.Properties.Timestamp, time.Time{}).Compare(
+			convert.ToValueWithDefault(b.Properties.Timestamp, time.Time{}))
+	})
+
+	display.logNewlyCreatedResources(ctx, completed, nil)
+}
+
+// NextPollDelay gets the delay before the next progress report.
+//
+// The deployment is polled often while it's changing, so that changes are shown within seconds, and the delay backs off
+// exponentially while it isn't changing or while reporting progress fails. The shortest delay grows with the number of
+// operations of the deployment, which reduces throttling on large deployments.
+func (display *ProvisioningProgressDisplay) NextPollDelay() time.Duration {
+	minDelay := progressMinPollDelay +
+		time.Duration(display.operationCount/progressOperationsPerDelaySecond)*time.Second
+	if minDelay > progressMaxPollDelay {
+		minDelay = progressMaxPollDelay
+	}
+
+	switch {
+	case display.changed || display.pollDelay < minDelay:
+		display.pollDelay = minDelay
+	default:
+		display.pollDelay = min(display.pollDelay*2, progressMaxPollDelay)
+	}
+
+	return display.pollDelay
+}
+
 func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 	ctx context.Context,
 	resources []*armresources.DeploymentOperation,
@@ -93,6 +93,7 @@ func (mock *mockResourceManager) AddInProgressOperation() {
 func (mock *mockResourceManager) MarkComplete(i int) {
 	mock.operations[i].Properties.ProvisioningState = to.Ptr(string(armresources.ProvisioningStateSucceeded))
 	mock.operations[i].Properties.Timestamp = to.Ptr(time.Now().UTC())
+	mock.operations[i].Properties.Duration = to.Ptr("PT1S")
 }
 
 func mockAzDeploymentShow(t *testing.T, m mocks.MockContext) {
@@ -152,3 +153,88 @@ func TestReportProgress(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, mockContext.Console.Output(), outputLength)
 }
+
+func TestNextPollDelay(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	deploymentService := mockazapi.NewDeploymentsServiceFromMockContext(mockContext)
+
+	scope := newSubscriptionScope(deploymentService, "SUBSCRIPTION_ID", "eastus2")
+	deployment := NewSubscriptionDeployment(
+		scope,
+		"DEPLOYMENT_NAME",
+	)
+	mockAzDeploymentShow(t, *mockContext)
+
+	startTime := time.Now()
+	mockResourceManager := mockResourceManager{}
+	progressDisplay := NewProvisioningProgressDisplay(&mockResourceManager, mockContext.Console, deployment)
+
+	// Backs off while the deployment isn't changing
+	require.NoError(t, progressDisplay.ReportProgress(*mockContext.Context, &startTime))
+	require.Equal(t, progressMinPollDelay, progressDisplay.NextPollDelay())
+	require.NoError(t, progressDisplay.ReportProgress(*mockContext.Context, &startTime))
+	require.Equal(t, 2*progressMinPollDelay, progressDisplay.NextPollDelay())
+	require.NoError(t, progressDisplay.ReportProgress(*mockContext.Context, &startTime))
+	require.Equal(t, 4*progressMinPollDelay, progressDisplay.NextPollDelay())
+
+	// Polls often again once the deployment changes
+	mockResourceManager.AddInProgressOperation()
+	mockResourceManager.MarkComplete(0)
+	require.NoError(t, progressDisplay.ReportProgress(*mockContext.Context, &startTime))
+	require.Equal(t, progressMinPollDelay, progressDisplay.NextPollDelay())
+
+	// Never exceeds the longest delay
+	for i := 0; i < 10; i++ {
+		require.NoError(t, progressDisplay.ReportProgress(*mockContext.Context, &startTime))
+		require.LessOrEqual(t, progressDisplay.NextPollDelay(), progressMaxPollDelay)
+	}
+	require.Equal(t, progressMaxPollDelay, progressDisplay.NextPollDelay())
+
+	// Large deployments are polled less often
+	for i := 0; i < 4*progressOperationsPerDelaySecond; i++ {
+		mockResourceManager.AddInProgressOperation()
+	}
+	mockResourceManager.MarkComplete(1)
+	require.NoError(t, progressDisplay.ReportProgress(*mockContext.Context, &startTime))
+	require.Equal(t, progressMinPollDelay+4*time.Second, progressDisplay.NextPollDelay())
+}
+
+func TestReportProgress_OperationObserver(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	deploymentService := mockazapi.NewDeploymentsServiceFromMockContext(mockContext)
+
+	scope := newSubscriptionScope(deploymentService, "SUBSCRIPTION_ID", "eastus2")
+	deployment := NewSubscriptionDeployment(
+		scope,
+		"DEPLOYMENT_NAME",
+	)
+	mockAzDeploymentShow(t, *mockContext)
+
+	updates := []string{}
+	ctx := WithDeploymentOperationObserver(*mockContext.Context,
+		func(ctx context.Context, operation *armresources.DeploymentOperation) {
+			updates = append(updates, fmt.Sprintf("%s %s",
+				*operation.Properties.TargetResource.ResourceName, *operation.Properties.ProvisioningState))
+		})
+
+	startTime := time.Now()
+	mockResourceManager := mockResourceManager{}
+	progressDisplay := NewProvisioningProgressDisplay(&mockResourceManager, mockContext.Console, deployment)
+
+	mockResourceManager.AddInProgressOperation()
+	require.NoError(t, progressDisplay.ReportProgress(ctx, &startTime))
+	require.Equal(t, []string{"website-resource-name-0 In Progress"}, updates)
+
+	// Only the operations which state changed are notified again
+	mockResourceManager.AddInProgressOperation()
+	mockResourceManager.MarkComplete(0)
+	require.NoError(t, progressDisplay.ReportProgress(ctx, &startTime))
+	require.Equal(t, []string{
+		"website-resource-name-0 In Progress",
+		"website-resource-name-1 In Progress",
+		"website-resource-name-0 Succeeded",
+	}, updates)
+
+	require.NoError(t, progressDisplay.ReportProgress(ctx, &startTime))
+	require.Len(t, updates, 3)
+}
@@ -17,6 +17,9 @@ type Scope interface {
 	SubscriptionId() string
 	// ListDeployments returns all the deployments at this scope.
 	ListDeployments(ctx context.Context) ([]*azapi.ResourceDeployment, error)
+	// DeleteDeploymentRecord removes the deployment from the deployment history of this scope, keeping the resources it
+	// deployed. Returns azapi.ErrDeploymentHistoryNotSupported when the deployments aren't kept in a history.
+	DeleteDeploymentRecord(ctx context.Context, deploymentName string) error
 	Deployment(deploymentName string) Deployment
 }
 
@@ -218,6 +221,16 @@ func (s *ResourceGroupScope) ListDeployments(ctx context.Context) ([]*azapi.Reso
 	return s.deploymentService.ListResourceGroupDeployments(ctx, s.subscriptionId, s.resourceGroupName)
 }
 
+// DeleteDeploymentRecord removes the deployment from the deployment history of this resource group.
+func (s *ResourceGroupScope) DeleteDeploymentRecord(ctx context.Context, deploymentName string) error {
+	history, ok := s.deploymentService.(azapi.DeploymentHistory)
+	if !ok {
+		return azapi.ErrDeploymentHistoryNotSupported
+	}
+
+	return history.DeleteResourceGroupDeploymentRecord(ctx, s.subscriptionId, s.resourceGroupName, deploymentName)
+}
+
 // Deployment gets the deployment with the specified name.
 func (s *ResourceGroupScope) Deployment(deploymentName string) Deployment {
 	return NewResourceGroupDeployment(s, deploymentName)
@@ -371,6 +384,16 @@ func (s *SubscriptionScope) ListDeployments(ctx context.Context) ([]*azapi.Resou
 	return s.deploymentService.ListSubscriptionDeployments(ctx, s.subscriptionId)
 }
 
+// DeleteDeploymentRecord removes the deployment from the deployment history of the subscription.
+func (s *SubscriptionScope) DeleteDeploymentRecord(ctx context.Context, deploymentName string) error {
+	history, ok := s.deploymentService.(azapi.DeploymentHistory)
+	if !ok {
+		return azapi.ErrDeploymentHistoryNotSupported
+	}
+
+	return history.DeleteSubscriptionDeploymentRecord(ctx, s.subscriptionId, deploymentName)
+}
+
 func newSubscriptionScope(
 	deploymentsService azapi.DeploymentService,
 	subscriptionId string,
@@ -12,7 +12,7 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/fatih/color"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 )
 
 type Asker func(p survey.Prompt, response interface{}) error
@@ -122,7 +122,7 @@ func askOnePrompt(p survey.Prompt, response interface{}, isTerminal bool, stdout
 			icons.Help.Format = "black+h"
 			icons.Help.Text = "Hint:"
 
-			icons.MarkedOption.Text = "[" + color.GreenString("✓") + "]"
+			icons.MarkedOption.Text = "[" + output.WithSuccessFormat(output.CheckMark()) + "]"
 			icons.MarkedOption.Format = ""
 		}))
 
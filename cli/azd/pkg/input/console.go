@@ -29,6 +29,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/status"
 	tm "github.com/buger/goterm"
 	"github.com/mattn/go-isatty"
 	"github.com/nathan-fiscaletti/consolesize-go"
@@ -131,6 +132,13 @@ type Console interface {
 	GetWriter() io.Writer
 	// Gets the standard input, output and error stream
 	Handles() ConsoleHandles
+	// Sets the handler called on the next interrupt (Ctrl+C) of the terminal, instead of exiting azd. The handler is
+	// called once, a following interrupt exits azd. Set the handler to nil to restore the default behavior.
+	SetInterruptHandler(handler func())
+	// Pushes a handler called on the next interrupt of the terminal, before the handler set with SetInterruptHandler is
+	// called or azd exits, ex) to restore the terminal. Pushed handlers are called once, the most recent first. The
+	// returned function removes the handler.
+	PushInterruptHandler(handler func()) (pop func())
 	ConsoleShim
 }
 
@@ -170,9 +178,15 @@ type AskerConsole struct {
 	// holds the last 2 bytes written by message or messageUX. This is used to detect when there is already an empty
 	// line (\n\n)
 	last2Byte [2]byte
+
+	interruptHandlerMu sync.Mutex // secures interruptHandler and pushedHandlers
+	interruptHandler   func()
+	pushedHandlers     []*func()
 }
 
 type ConsoleOptions struct {
+	// Id identifies the prompt so that it can be answered with the AZD_ANSWER_<ID> environment variable. Optional.
+	Id      PromptId
 	Message string
 	Help    string
 	Options []string
@@ -211,6 +225,8 @@ func (c *AskerConsole) IsUnformatted() bool {
 
 // Prints out a message to the underlying console write
 func (c *AskerConsole) Message(ctx context.Context, message string) {
+	status.FromContext(ctx).Log(message)
+
 	// Disable output when formatting is enabled
 	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
 		// we call json.Marshal directly, because the formatter marshalls using indentation, and we would prefer
@@ -265,6 +281,16 @@ func shouldWarn() bool {
 }
 
 func (c *AskerConsole) MessageUxItem(ctx context.Context, item ux.UxItem) {
+	switch item := item.(type) {
+	case *ux.DisplayedResource:
+		status.FromContext(ctx).SetResource(item.Type, item.Name, string(item.State))
+	case *ux.WarningMessage:
+		status.FromContext(ctx).Warn(item.Description)
+		status.FromContext(ctx).Log(item.ToString(""))
+	default:
+		status.FromContext(ctx).Log(item.ToString(""))
+	}
+
 	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
 		// no need to check the spinner for json format, as the spinner won't start when using json format
 		// instead, there would be a message about starting spinner
@@ -354,28 +380,48 @@ func (c *AskerConsole) spinnerLine(title string, indent string) spinnerLine {
 	switch {
 	case width <= 3: // show number of dots up to 3
 		return spinnerLine{
-			CharSet: spinnerShortCharSet[:width],
+			CharSet: staticCharSet(spinnerShortCharSet[:width]),
 		}
 	case width <= spinnerLen+len(truncationDots): // show number of dots
 		return spinnerLine{
-			CharSet: spinnerShortCharSet,
+			CharSet: staticCharSet(spinnerShortCharSet),
 		}
 	case width <= spinnerLen+len(title): // truncate title
 		return spinnerLine{
 			Prefix:  indent,
-			CharSet: spinnerCharSet,
+			CharSet: terminalSpinnerCharSet(),
 			Message: title[:width-spinnerLen-len(truncationDots)] + truncationDots,
 		}
 	default:
 		return spinnerLine{
 			Prefix:  indent,
-			CharSet: spinnerCharSet,
+			CharSet: terminalSpinnerCharSet(),
 			Message: title,
 		}
 	}
 }
 
+// terminalSpinnerCharSet returns the char set of the spinner on terminals, a single frame when motion is reduced
+func terminalSpinnerCharSet() []string {
+	if output.ReducedMotion() {
+		return spinnerReducedMotionCharSet
+	}
+
+	return spinnerCharSet
+}
+
+// staticCharSet returns the last frame of the char set when motion is reduced, which stops the animation
+func staticCharSet(charSet []string) []string {
+	if output.ReducedMotion() && len(charSet) > 0 {
+		return charSet[len(charSet)-1:]
+	}
+
+	return charSet
+}
+
 func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format SpinnerUxType) {
+	status.FromContext(ctx).SetStep(title)
+
 	c.showProgressMu.Lock()
 	defer c.showProgressMu.Unlock()
 
@@ -432,6 +478,9 @@ var spinnerCharSet []string = []string{
 
 var spinnerShortCharSet []string = []string{".", "..", "..."}
 
+// The spinner doesn't animate when motion is reduced, see the ux.reducedMotion user config
+var spinnerReducedMotionCharSet []string = []string{"|  ...  |"}
+
 var spinnerNoTerminalCharSet []string = []string{""}
 
 func setIndentation(spaces int) string {
@@ -451,6 +500,8 @@ func (c *AskerConsole) getIndent() string {
 }
 
 func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, format SpinnerUxType) {
+	status.FromContext(ctx).Log(lastMessage)
+
 	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
 		// Spinner is disabled when using json format.
 		return
@@ -485,13 +536,11 @@ func (c *AskerConsole) IsSpinnerInteractive() bool {
 	return c.spinnerTerminalMode&yacspin.ForceTTYMode > 0
 }
 
-var donePrefix string = output.WithSuccessFormat("(✓) Done:")
-
 func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
 	var stopChar string
 	switch format {
 	case StepDone:
-		stopChar = donePrefix
+		stopChar = output.WithSuccessFormat("(%s) Done:", output.CheckMark())
 	case StepFailed:
 		stopChar = output.WithErrorFormat("(x) Failed:")
 	case StepWarning:
@@ -577,6 +626,10 @@ func (c *AskerConsole) PromptDialog(ctx context.Context, dialog PromptDialog) (m
 
 // Prompts the user for a single value
 func (c *AskerConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	if answer, has := promptAnswer(options.Id); has {
+		return answer, nil
+	}
+
 	var response string
 
 	if c.promptClient != nil {
@@ -637,6 +690,10 @@ func choicesFromOptions(options ConsoleOptions) []promptChoice {
 
 // Prompts the user to select from a set of values
 func (c *AskerConsole) Select(ctx context.Context, options ConsoleOptions) (int, error) {
+	if answer, has := promptAnswer(options.Id); has {
+		return selectAnswer(options.Id, options.Options, answer)
+	}
+
 	if c.promptClient != nil {
 		opts := promptOptions{
 			Type: "select",
@@ -715,6 +772,10 @@ func (c *AskerConsole) Select(ctx context.Context, options ConsoleOptions) (int,
 }
 
 func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	if answer, has := promptAnswer(options.Id); has {
+		return multiSelectAnswer(options.Id, options.Options, answer)
+	}
+
 	var response []string
 
 	if c.promptClient != nil {
@@ -785,6 +846,10 @@ func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions)
 
 // Prompts the user to confirm an operation
 func (c *AskerConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
+	if answer, has := promptAnswer(options.Id); has {
+		return confirmAnswer(options.Id, answer)
+	}
+
 	if c.promptClient != nil {
 		opts := promptOptions{
 			Type: "confirm",
@@ -938,16 +1003,57 @@ func watchTerminalResize(c *AskerConsole) {
 	}
 }
 
+// SetInterruptHandler sets the handler called on the next interrupt of the terminal, instead of exiting azd.
+func (c *AskerConsole) SetInterruptHandler(handler func()) {
+	c.interruptHandlerMu.Lock()
+	defer c.interruptHandlerMu.Unlock()
+
+	c.interruptHandler = handler
+}
+
+// PushInterruptHandler pushes a handler called on the next interrupt of the terminal, before the handler set with
+// SetInterruptHandler is called or azd exits.
+func (c *AskerConsole) PushInterruptHandler(handler func()) func() {
+	c.interruptHandlerMu.Lock()
+	defer c.interruptHandlerMu.Unlock()
+
+	pushed := &handler
+	c.pushedHandlers = append(c.pushedHandlers, pushed)
+
+	return func() {
+		c.interruptHandlerMu.Lock()
+		defer c.interruptHandlerMu.Unlock()
+
+		c.pushedHandlers = slices.DeleteFunc(c.pushedHandlers, func(h *func()) bool { return h == pushed })
+	}
+}
+
 func watchTerminalInterrupt(c *AskerConsole) {
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt)
 	go func() {
-		<-signalChan
+		for range signalChan {
+			// the handler is only called once, so a second interrupt exits
+			c.interruptHandlerMu.Lock()
+			handler := c.interruptHandler
+			c.interruptHandler = nil
+			pushedHandlers := c.pushedHandlers
+			c.pushedHandlers = nil
+			c.interruptHandlerMu.Unlock()
+
+			for _, pushed := range slices.Backward(pushedHandlers) {
+				(*pushed)()
+			}
+
+			// unhide the cursor if applicable
+			_ = c.spinner.Stop()
 
-		// unhide the cursor if applicable
-		_ = c.spinner.Stop()
+			if handler == nil {
+				os.Exit(1)
+			}
 
-		os.Exit(1)
+			handler()
+		}
 	}()
 }
 
@@ -1005,7 +1111,7 @@ func NewConsole(
 		TerminalMode: spinnerTerminalMode(isTerminal),
 	}
 	if isTerminal {
-		spinnerConfig.CharSet = spinnerCharSet
+		spinnerConfig.CharSet = terminalSpinnerCharSet()
 	} else {
 		spinnerConfig.CharSet = spinnerNoTerminalCharSet
 	}
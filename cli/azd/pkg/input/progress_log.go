@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/adam-lavrik/go-imath/ix"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	tm "github.com/buger/goterm"
 )
 
@@ -292,7 +293,7 @@ func (p *progressLog) buildTopBottom() {
 	}
 
 	// end line is all space after the prefix
-	p.footerLine = p.prefix + strings.Repeat("─", consoleLen-len(p.prefix))
+	p.footerLine = p.prefix + output.HorizontalLine(consoleLen-len(p.prefix))
 
 	if titleLen >= consoleLen {
 		// can't add lines as title is longer than what's available
@@ -306,7 +307,7 @@ func (p *progressLog) buildTopBottom() {
 
 	if p.title == "" {
 		// using single line for remaining space as title is empty
-		p.displayTitle = p.prefix + strings.Repeat("─", remainingSpace)
+		p.displayTitle = p.prefix + output.HorizontalLine(remainingSpace)
 		return
 	}
 
@@ -327,5 +328,5 @@ func (p *progressLog) buildTopBottom() {
 	left := remainingSpace / 10
 	right := remainingSpace - left
 
-	p.displayTitle = p.prefix + strings.Repeat("─", left) + " " + p.title + " " + strings.Repeat("─", right)
+	p.displayTitle = p.prefix + output.HorizontalLine(left) + " " + p.title + " " + output.HorizontalLine(right)
 }
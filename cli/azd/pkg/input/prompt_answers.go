@@ -0,0 +1,190 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The prefix of the environment variables answering prompts, followed by the id of the prompt
+const promptAnswerEnvVarPrefix = "AZD_ANSWER_"
+
+// PromptId identifies a prompt, so that it can be answered ahead of time, ex) on CI, by setting the
+// AZD_ANSWER_<ID> environment variable.
+type PromptId string
+
+// EnvVarName returns the name of the environment variable answering the prompt, ex) AZD_ANSWER_SUBSCRIPTION
+func (id PromptId) EnvVarName() string {
+	return promptAnswerEnvVarPrefix + string(id)
+}
+
+// HasAnswer returns true when the prompt is answered by its environment variable. Callers prompting again on invalid
+// answers use it to fail instead, as the same answer would be returned forever.
+func (id PromptId) HasAnswer() bool {
+	if id == "" {
+		return false
+	}
+
+	_, has := os.LookupEnv(id.EnvVarName())
+	return has
+}
+
+// PromptInfo describes a prompt which can be answered with an environment variable.
+type PromptInfo struct {
+	Id PromptId
+	// What the answer of the prompt is, and the format expected
+	Description string
+	// The commands showing the prompt, ex) provision or env new
+	Commands []string
+}
+
+var (
+	promptIdRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+	promptsMu sync.Mutex
+	prompts   = map[PromptId]PromptInfo{}
+)
+
+// MustRegisterPrompt adds the prompt to the prompts listed by '--list-prompts' and returns its id, set as the Id of the
+// ConsoleOptions of the prompt. Panics when the id isn't in upper snake case or is already registered.
+func MustRegisterPrompt(info PromptInfo) PromptId {
+	if !promptIdRegex.MatchString(string(info.Id)) {
+		panic(fmt.Sprintf("prompt id '%s' must be in upper snake case", info.Id))
+	}
+
+	promptsMu.Lock()
+	defer promptsMu.Unlock()
+
+	if _, has := prompts[info.Id]; has {
+		panic(fmt.Sprintf("prompt id '%s' is already registered", info.Id))
+	}
+
+	prompts[info.Id] = info
+	return info.Id
+}
+
+// Prompts returns the registered prompts shown by the command, ex) provision, sorted by id. All the prompts are returned
+// when the command is empty.
+func Prompts(command string) []PromptInfo {
+	promptsMu.Lock()
+	defer promptsMu.Unlock()
+
+	result := []PromptInfo{}
+	for _, info := range prompts {
+		if command == "" || slices.Contains(info.Commands, command) {
+			result = append(result, info)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b PromptInfo) int {
+		return strings.Compare(string(a.Id), string(b.Id))
+	})
+
+	return result
+}
+
+// promptAnswer returns the answer of the prompt set in its environment variable, if any
+func promptAnswer(id PromptId) (string, bool) {
+	if id == "" {
+		return "", false
+	}
+
+	answer, has := os.LookupEnv(id.EnvVarName())
+	if has {
+		log.Printf("answering prompt '%s' from %s", id, id.EnvVarName())
+	}
+
+	return answer, has
+}
+
+// selectAnswer returns the index of the option matching the answer. An option matches when it's equal to the answer,
+// ignoring case, any '1. ' numbering and any trailing '(...)' details, or when one of its words is, ex) the 'eastus' of
+// '(US) East US (eastus)'.
+func selectAnswer(id PromptId, options []string, answer string) (int, error) {
+	answer = strings.TrimSpace(answer)
+	matches := []int{}
+	for i, option := range options {
+		if optionMatches(option, answer) {
+			matches = append(matches, i)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return -1, fmt.Errorf(
+			"%s value '%s' doesn't match any of the options: %s",
+			id.EnvVarName(), answer, strings.Join(options, ", "))
+	default:
+		return -1, fmt.Errorf("%s value '%s' matches more than one option", id.EnvVarName(), answer)
+	}
+}
+
+// multiSelectAnswer returns the options matching the comma separated values of the answer
+func multiSelectAnswer(id PromptId, options []string, answer string) ([]string, error) {
+	selected := []string{}
+	for _, value := range strings.Split(answer, ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		index, err := selectAnswer(id, options, value)
+		if err != nil {
+			return nil, err
+		}
+
+		selected = append(selected, options[index])
+	}
+
+	return selected, nil
+}
+
+// confirmAnswer parses the answer of a confirmation, ex) true, y or no
+func confirmAnswer(id PromptId, answer string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+
+	confirm, err := strconv.ParseBool(strings.TrimSpace(answer))
+	if err != nil {
+		return false, fmt.Errorf("%s value '%s' is not valid, use 'true' or 'false'", id.EnvVarName(), answer)
+	}
+
+	return confirm, nil
+}
+
+var (
+	// optionNumbering matches the numbering of options, ex) ' 1. '
+	optionNumbering = regexp.MustCompile(`^\s*\d+\.\s+`)
+	// optionDetails matches the details trailing options, ex) the ' (eastus)' of 'East US (eastus)'
+	optionDetails = regexp.MustCompile(`\s+\([^()]*\)$`)
+)
+
+func optionMatches(option string, answer string) bool {
+	unnumbered := optionNumbering.ReplaceAllString(option, "")
+	if strings.EqualFold(option, answer) ||
+		strings.EqualFold(unnumbered, answer) ||
+		strings.EqualFold(optionDetails.ReplaceAllString(unnumbered, ""), answer) {
+		return true
+	}
+
+	words := strings.FieldsFunc(option, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')' || r == '[' || r == ']' || r == ','
+	})
+
+	return slices.ContainsFunc(words, func(word string) bool {
+		return strings.EqualFold(word, answer)
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMustRegisterPrompt(t *testing.T) {
+	id := MustRegisterPrompt(PromptInfo{
+		Id:          "TEST_REGISTER",
+		Description: "A test prompt.",
+		Commands:    []string{"test command"},
+	})
+	require.Equal(t, "AZD_ANSWER_TEST_REGISTER", id.EnvVarName())
+
+	prompts := Prompts("test command")
+	require.Len(t, prompts, 1)
+	require.Equal(t, id, prompts[0].Id)
+	require.Empty(t, Prompts("test"))
+
+	require.Panics(t, func() {
+		MustRegisterPrompt(PromptInfo{Id: "TEST_REGISTER"})
+	})
+	require.Panics(t, func() {
+		MustRegisterPrompt(PromptInfo{Id: "test-register"})
+	})
+}
+
+func Test_selectAnswer(t *testing.T) {
+	locations := []string{
+		" 1. (US) East US (eastus)",
+		" 2. (US) East US 2 (eastus2)",
+		" 3. (Europe) West Europe (westeurope)",
+	}
+
+	tests := []struct {
+		name     string
+		options  []string
+		answer   string
+		expected int
+	}{
+		{name: "Word", options: locations, answer: "eastus2", expected: 1},
+		{name: "IgnoresCase", options: locations, answer: "WestEurope", expected: 2},
+		{name: "WithoutNumbering", options: locations, answer: "(US) East US (eastus)", expected: 0},
+		{name: "WithoutDetails", options: []string{" 1. Dev (0000)", " 2. Prod (1111)"}, answer: "prod", expected: 1},
+		{name: "Exact", options: []string{"Create a new environment", "dev"}, answer: "dev", expected: 1},
+		{name: "TrimsSpaces", options: []string{"dev", "prod"}, answer: " prod\n", expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index, err := selectAnswer("LOCATION", test.options, test.answer)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, index)
+		})
+	}
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, err := selectAnswer("LOCATION", locations, "westus")
+		require.ErrorContains(t, err, "AZD_ANSWER_LOCATION value 'westus' doesn't match any of the options")
+	})
+
+	t.Run("Ambiguous", func(t *testing.T) {
+		_, err := selectAnswer("LOCATION", locations, "US")
+		require.ErrorContains(t, err, "AZD_ANSWER_LOCATION value 'US' matches more than one option")
+	})
+}
+
+func Test_multiSelectAnswer(t *testing.T) {
+	selected, err := multiSelectAnswer("SERVICES", []string{"api", "web", "worker"}, "web, api,")
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "api"}, selected)
+
+	_, err = multiSelectAnswer("SERVICES", []string{"api", "web"}, "api,jobs")
+	require.Error(t, err)
+}
+
+func Test_confirmAnswer(t *testing.T) {
+	for answer, expected := range map[string]bool{
+		"true": true, "1": true, "Y": true, "yes": true,
+		"false": false, "0": false, "n": false, "No": false,
+	} {
+		confirm, err := confirmAnswer("CONFIRM", answer)
+		require.NoError(t, err)
+		require.Equal(t, expected, confirm, answer)
+	}
+
+	_, err := confirmAnswer("CONFIRM", "maybe")
+	require.ErrorContains(t, err, "AZD_ANSWER_CONFIRM value 'maybe' is not valid")
+}
+
+func TestAskerConsole_PromptAnswers(t *testing.T) {
+	formatter, err := output.NewFormatter(string(output.NoneFormat))
+	require.NoError(t, err)
+
+	c := NewConsole(
+		false,
+		false,
+		Writers{Output: os.Stdout},
+		ConsoleHandles{
+			Stderr: os.Stderr,
+			Stdin:  os.Stdin,
+			Stdout: os.Stdout,
+		},
+		formatter,
+		nil,
+	)
+
+	ctx := context.Background()
+	t.Setenv("AZD_ANSWER_TEST_NAME", "dev")
+	t.Setenv("AZD_ANSWER_TEST_LOCATION", "westus2")
+	t.Setenv("AZD_ANSWER_TEST_CONFIRM", "yes")
+
+	name, err := c.Prompt(ctx, ConsoleOptions{Id: "TEST_NAME", Message: "Enter a name:"})
+	require.NoError(t, err)
+	require.Equal(t, "dev", name)
+
+	selected, err := c.Select(ctx, ConsoleOptions{
+		Id:      "TEST_LOCATION",
+		Message: "Select a location:",
+		Options: []string{"1. (US) East US (eastus)", "2. (US) West US 2 (westus2)"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, selected)
+
+	confirm, err := c.Confirm(ctx, ConsoleOptions{Id: "TEST_CONFIRM", Message: "Continue?"})
+	require.NoError(t, err)
+	require.True(t, confirm)
+
+	require.True(t, PromptId("TEST_NAME").HasAnswer())
+	require.False(t, PromptId("TEST_MISSING").HasAnswer())
+	require.False(t, PromptId("").HasAnswer())
+}
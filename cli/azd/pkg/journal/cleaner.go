@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcentersdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+)
+
+// Cleaner deletes the resources recorded in a journal.
+type Cleaner struct {
+	resourceService          *azapi.ResourceService
+	containerRegistryService azapi.ContainerRegistryService
+	serviceLocator           ioc.ServiceLocator
+}
+
+// NewCleaner creates a new Cleaner
+func NewCleaner(
+	resourceService *azapi.ResourceService,
+	containerRegistryService azapi.ContainerRegistryService,
+	serviceLocator ioc.ServiceLocator,
+) *Cleaner {
+	return &Cleaner{
+		resourceService:          resourceService,
+		containerRegistryService: containerRegistryService,
+		serviceLocator:           serviceLocator,
+	}
+}
+
+// Clean deletes the resources of each entry of the journal. The images are deleted first, since they are usually
+// pushed to a registry of the resource groups, and the resource groups last. The resources of all the entries are
+// deleted even when deleting some of them fails, the errors are joined.
+func (c *Cleaner) Clean(ctx context.Context, j *Journal) error {
+	order := []EntryKind{EntryContainerImage, EntryDevCenterEnvironment, EntryResourceGroups}
+	entries := slices.Clone(j.Entries)
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return slices.Index(order, a.Kind) - slices.Index(order, b.Kind)
+	})
+
+	var errs []error
+	for _, entry := range entries {
+		if err := c.cleanEntry(ctx, entry); err != nil {
+			errs = append(errs, fmt.Errorf("deleting %s: %w", entry, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Cleaner) cleanEntry(ctx context.Context, entry Entry) error {
+	switch entry.Kind {
+	case EntryContainerImage:
+		return c.containerRegistryService.DeleteImageTag(
+			ctx, entry.SubscriptionId, entry.Registry, entry.Repository, entry.Tag)
+	case EntryDevCenterEnvironment:
+		var client devcentersdk.DevCenterClient
+		if err := c.serviceLocator.Resolve(&client); err != nil {
+			return fmt.Errorf("the devcenter platform is not enabled: %w", err)
+		}
+
+		return client.
+			DevCenterByName(entry.DevCenter).
+			ProjectByName(entry.Project).
+			EnvironmentsByUser(entry.User).
+			EnvironmentByName(entry.Name).
+			Delete(ctx)
+	case EntryResourceGroups:
+		return c.deleteResourceGroups(ctx, entry)
+	default:
+		return fmt.Errorf("unknown journal entry kind '%s'", entry.Kind)
+	}
+}
+
+// deleteResourceGroups deletes the resource groups tagged with the environment name, except the ones which already
+// existed when the entry was recorded.
+func (c *Cleaner) deleteResourceGroups(ctx context.Context, entry Entry) error {
+	resourceGroups, err := c.resourceService.ListResourceGroup(ctx, entry.SubscriptionId, &azapi.ListResourceGroupOptions{
+		TagFilter: &azapi.Filter{Key: azure.TagKeyAzdEnvName, Value: entry.EnvironmentName},
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, resourceGroup := range resourceGroups {
+		if slices.ContainsFunc(entry.ExistingResourceGroups, func(existing string) bool {
+			return strings.EqualFold(existing, resourceGroup.Name)
+		}) {
+			continue
+		}
+
+		if err := c.resourceService.DeleteResourceGroup(ctx, entry.SubscriptionId, resourceGroup.Name); err != nil {
+			errs = append(errs, fmt.Errorf("resource group '%s': %w", resourceGroup.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
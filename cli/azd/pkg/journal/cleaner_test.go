@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package journal
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Cleaner_Clean(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	deleted := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/resourcegroups")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Contains(t, request.URL.Query().Get("$filter"), "azd-env-name")
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+			Value: []*armresources.ResourceGroup{
+				resourceGroup("rg-shared"),
+				resourceGroup("rg-dev"),
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && strings.Contains(request.URL.Path, "/resourcegroups/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = append(deleted, "resourceGroup:"+request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:])
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	registryService := &mockRegistryService{deleted: &deleted}
+	cleaner := NewCleaner(
+		azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		registryService,
+		mockContext.Container,
+	)
+
+	journal := &Journal{
+		Entries: []Entry{
+			{
+				Kind:                   EntryResourceGroups,
+				SubscriptionId:         "SUBSCRIPTION_ID",
+				EnvironmentName:        "dev",
+				ExistingResourceGroups: []string{"RG-SHARED"},
+			},
+			{
+				Kind:      EntryDevCenterEnvironment,
+				DevCenter: "DEV_CENTER",
+				Project:   "Project1",
+				Name:      "dev",
+			},
+			{
+				Kind:           EntryContainerImage,
+				SubscriptionId: "SUBSCRIPTION_ID",
+				Registry:       "myregistry.azurecr.io",
+				Repository:     "app/web-dev",
+				Tag:            "azd-deploy-1",
+			},
+		},
+	}
+
+	err := cleaner.Clean(*mockContext.Context, journal)
+
+	// The devcenter platform isn't enabled, the other resources are still deleted
+	require.ErrorContains(t, err, "the devcenter platform is not enabled")
+	require.Equal(t, []string{
+		"image:myregistry.azurecr.io/app/web-dev:azd-deploy-1",
+		"resourceGroup:rg-dev",
+	}, deleted)
+}
+
+func resourceGroup(name string) *armresources.ResourceGroup {
+	return &armresources.ResourceGroup{
+		ID:       to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/" + name),
+		Name:     to.Ptr(name),
+		Type:     to.Ptr("Microsoft.Resources/resourceGroups"),
+		Location: to.Ptr("eastus2"),
+	}
+}
+
+type mockRegistryService struct {
+	azapi.ContainerRegistryService
+	deleted *[]string
+}
+
+func (m *mockRegistryService) DeleteImageTag(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	tag string,
+) error {
+	*m.deleted = append(*m.deleted, "image:"+loginServer+"/"+repository+":"+tag)
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package journal records the resources created while running a command like 'azd up', so that they can be removed
+// when the command is interrupted instead of being left behind silently.
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// FileName is the name of the journal file, saved in the directory of the environment.
+const FileName = "up-journal.json"
+
+// EntryKind is the kind of resource recorded in a journal.
+type EntryKind string
+
+const (
+	// The resource groups tagged with the azd-env-name of the environment, created by a subscription deployment
+	EntryResourceGroups EntryKind = "resourceGroups"
+	// A container image pushed to a registry
+	EntryContainerImage EntryKind = "containerImage"
+	// An Azure Deployment Environment created in a devcenter project
+	EntryDevCenterEnvironment EntryKind = "devCenterEnvironment"
+)
+
+// Entry is a resource, or a set of resources, created while running a command.
+type Entry struct {
+	Kind           EntryKind `json:"kind"`
+	SubscriptionId string    `json:"subscriptionId,omitempty"`
+
+	// The azd-env-name tag of the resource groups, for EntryResourceGroups
+	EnvironmentName string `json:"environmentName,omitempty"`
+	// The resource groups which already existed before the deployment, for EntryResourceGroups
+	ExistingResourceGroups []string `json:"existingResourceGroups,omitempty"`
+
+	// The login server, repository and tag of the image, for EntryContainerImage
+	Registry   string `json:"registry,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+
+	// The dev center, project, user and name of the environment, for EntryDevCenterEnvironment
+	DevCenter string `json:"devCenter,omitempty"`
+	Project   string `json:"project,omitempty"`
+	User      string `json:"user,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// String describes the resources of the entry.
+func (e Entry) String() string {
+	switch e.Kind {
+	case EntryResourceGroups:
+		return fmt.Sprintf(
+			"Resource groups tagged with azd-env-name '%s' in subscription %s", e.EnvironmentName, e.SubscriptionId)
+	case EntryContainerImage:
+		return fmt.Sprintf("Container image %s/%s:%s", e.Registry, e.Repository, e.Tag)
+	case EntryDevCenterEnvironment:
+		return fmt.Sprintf("Dev center environment '%s' of project '%s'", e.Name, e.Project)
+	default:
+		return string(e.Kind)
+	}
+}
+
+// Journal is the list of the resources created while running a command. It's saved after each new entry, so it
+// remains when azd exits before the command completes.
+type Journal struct {
+	Command   string    `json:"command"`
+	StartedAt time.Time `json:"startedAt"`
+	Entries   []Entry   `json:"entries"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// Path returns the path of the journal of the environment with the given name.
+func Path(azdCtx *azdcontext.AzdContext, envName string) string {
+	return filepath.Join(azdCtx.EnvironmentRoot(envName), FileName)
+}
+
+// Start creates a new journal for the command, replacing any journal at path.
+func Start(path string, command string) (*Journal, error) {
+	journal := &Journal{
+		Command:   command,
+		StartedAt: time.Now(),
+		Entries:   []Entry{},
+		path:      path,
+	}
+
+	if err := journal.save(); err != nil {
+		return nil, err
+	}
+
+	return journal, nil
+}
+
+// Load loads the journal at path. When there is no journal, the error wraps os.ErrNotExist.
+func Load(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("reading journal %s: %w", path, err)
+	}
+	journal.path = path
+
+	return &journal, nil
+}
+
+// Add records a new entry, unless the same entry is already recorded.
+func (j *Journal) Add(entry Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, existing := range j.Entries {
+		if existing.String() == entry.String() {
+			return nil
+		}
+	}
+
+	j.Entries = append(j.Entries, entry)
+	return j.save()
+}
+
+// Remove deletes the journal, once the command completed or its resources were handled.
+func (j *Journal) Remove() error {
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	return os.WriteFile(j.path, data, osutil.PermissionFile)
+}
+
+type journalContextKey struct{}
+
+// WithJournal returns a context recording the resources created by the commands run with it to the journal.
+func WithJournal(ctx context.Context, journal *Journal) context.Context {
+	return context.WithValue(ctx, journalContextKey{}, journal)
+}
+
+// Record records the entry to the journal of the context, if any. Failing to record an entry doesn't fail the
+// command creating the resource.
+func Record(ctx context.Context, entry Entry) {
+	journal, ok := ctx.Value(journalContextKey{}).(*Journal)
+	if !ok {
+		return
+	}
+
+	if err := journal.Add(entry); err != nil {
+		log.Printf("failed recording '%s' to the journal: %v", entry, err)
+	}
+}
+
+// IsRecording returns true when the resources created with the context are recorded to a journal.
+func IsRecording(ctx context.Context) bool {
+	_, ok := ctx.Value(journalContextKey{}).(*Journal)
+	return ok
+}
+
+// Describe returns the description of each entry of the journal, one per line.
+func (j *Journal) Describe() string {
+	lines := make([]string, 0, len(j.Entries))
+	for _, entry := range j.Entries {
+		lines = append(lines, "  - "+entry.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package journal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Journal_Lifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "env", FileName)
+
+	journal, err := Start(path, "up")
+	require.NoError(t, err)
+	require.FileExists(t, path)
+
+	image := Entry{
+		Kind:           EntryContainerImage,
+		SubscriptionId: "SUBSCRIPTION_ID",
+		Registry:       "myregistry.azurecr.io",
+		Repository:     "app/web-dev",
+		Tag:            "azd-deploy-1",
+	}
+	require.NoError(t, journal.Add(image))
+	// the same entry is only recorded once
+	require.NoError(t, journal.Add(image))
+	require.NoError(t, journal.Add(Entry{
+		Kind:                   EntryResourceGroups,
+		SubscriptionId:         "SUBSCRIPTION_ID",
+		EnvironmentName:        "dev",
+		ExistingResourceGroups: []string{"rg-shared"},
+	}))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "up", loaded.Command)
+	require.Len(t, loaded.Entries, 2)
+	require.Equal(t, image, loaded.Entries[0])
+	require.Equal(t, []string{"rg-shared"}, loaded.Entries[1].ExistingResourceGroups)
+	require.Equal(t,
+		"  - Container image myregistry.azurecr.io/app/web-dev:azd-deploy-1\n"+
+			"  - Resource groups tagged with azd-env-name 'dev' in subscription SUBSCRIPTION_ID",
+		loaded.Describe())
+
+	require.NoError(t, loaded.Remove())
+	_, err = Load(path)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	// removing a journal which doesn't exist is a no-op
+	require.NoError(t, loaded.Remove())
+}
+
+func Test_Journal_Record(t *testing.T) {
+	entry := Entry{
+		Kind:      EntryDevCenterEnvironment,
+		DevCenter: "DEV_CENTER",
+		Project:   "Project1",
+		Name:      "dev",
+	}
+
+	t.Run("WithoutJournal", func(t *testing.T) {
+		ctx := context.Background()
+		require.False(t, IsRecording(ctx))
+
+		// recording without a journal is a no-op
+		Record(ctx, entry)
+	})
+
+	t.Run("WithJournal", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), FileName)
+		journal, err := Start(path, "up")
+		require.NoError(t, err)
+
+		ctx := WithJournal(context.Background(), journal)
+		require.True(t, IsRecording(ctx))
+
+		Record(ctx, entry)
+
+		loaded, err := Load(path)
+		require.NoError(t, err)
+		require.Equal(t, []Entry{entry}, loaded.Entries)
+	})
+}
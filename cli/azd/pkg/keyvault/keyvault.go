@@ -19,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 )
 
 var ErrAzCliSecretNotFound = errors.New("secret not found")
@@ -75,6 +76,16 @@ type KeyVaultService interface {
 		secretValue string,
 	) error
 	SecretFromAkvs(ctx context.Context, akvs string) (string, error)
+	// Adds an access policy granting a principal read access to the secrets of a vault, when the vault uses access
+	// policies. Returns false, without changing the vault, when the vault uses Azure RBAC instead, in which case the Key
+	// Vault Secrets User role must be assigned to the principal.
+	EnsureSecretsAccessPolicy(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		vaultName string,
+		principalId string,
+	) (bool, error)
 }
 
 type keyVaultService struct {
@@ -332,6 +343,77 @@ func (kvs *keyVaultService) CreateVault(
 	}, nil
 }
 
+func (kvs *keyVaultService) EnsureSecretsAccessPolicy(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	vaultName string,
+	principalId string,
+) (bool, error) {
+	client, err := kvs.createKeyVaultClient(ctx, subscriptionId)
+	if err != nil {
+		return false, err
+	}
+
+	vault, err := client.Get(ctx, resourceGroupName, vaultName, nil)
+	if err != nil {
+		return false, fmt.Errorf("getting key vault: %w", err)
+	}
+
+	if vault.Properties == nil || convert.ToValueWithDefault(vault.Properties.EnableRbacAuthorization, false) {
+		return false, nil
+	}
+
+	readPermissions := []armkeyvault.SecretPermissions{armkeyvault.SecretPermissionsGet, armkeyvault.SecretPermissionsList}
+	for _, policy := range vault.Properties.AccessPolicies {
+		if policy.ObjectID == nil || !strings.EqualFold(*policy.ObjectID, principalId) || policy.Permissions == nil {
+			continue
+		}
+
+		granted := map[armkeyvault.SecretPermissions]bool{}
+		for _, permission := range policy.Permissions.Secrets {
+			if permission != nil {
+				granted[armkeyvault.SecretPermissions(strings.ToLower(string(*permission)))] = true
+			}
+		}
+
+		if granted[armkeyvault.SecretPermissionsGet] && granted[armkeyvault.SecretPermissionsList] {
+			return true, nil
+		}
+	}
+
+	secretPermissions := make([]*armkeyvault.SecretPermissions, 0, len(readPermissions))
+	for _, permission := range readPermissions {
+		secretPermissions = append(secretPermissions, to.Ptr(permission))
+	}
+
+	_, err = client.UpdateAccessPolicy(
+		ctx,
+		resourceGroupName,
+		vaultName,
+		armkeyvault.AccessPolicyUpdateKindAdd,
+		armkeyvault.VaultAccessPolicyParameters{
+			Properties: &armkeyvault.VaultAccessPolicyProperties{
+				AccessPolicies: []*armkeyvault.AccessPolicyEntry{
+					{
+						TenantID: vault.Properties.TenantID,
+						ObjectID: to.Ptr(principalId),
+						Permissions: &armkeyvault.Permissions{
+							Secrets: secretPermissions,
+						},
+					},
+				},
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return false, fmt.Errorf("adding access policy to key vault '%s': %w", vaultName, err)
+	}
+
+	return true, nil
+}
+
 // Built-in roles for Key Vault RBAC
 // https://learn.microsoft.com/azure/role-based-access-control/built-in-roles
 const (
@@ -370,6 +452,8 @@ func (kvs *keyVaultService) SecretFromAkvs(ctx context.Context, akvs string) (st
 	if err != nil {
 		return "", fmt.Errorf("fetching secret value from key vault: %w", err)
 	}
+
+	output.AddSecret(secretValue.Value)
 	return secretValue.Value, nil
 }
 
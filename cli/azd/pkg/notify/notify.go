@@ -0,0 +1,412 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package notify posts a notification to webhooks, Teams or Slack channels and Event Grid topics when an azd lifecycle
+// command, ex) 'azd provision', completes or fails.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Kind is the kind of endpoint a notification is posted to
+type Kind string
+
+const (
+	// A webhook receiving the JSON payload, signed with the secret of the target
+	KindWebhook Kind = "webhook"
+	// A Teams channel, through a workflow webhook receiving adaptive cards
+	KindTeams Kind = "teams"
+	// A Slack channel, through an incoming webhook
+	KindSlack Kind = "slack"
+	// An Event Grid topic receiving the payload as a CloudEvent, authenticated with the access key of the topic
+	KindEventGrid Kind = "eventgrid"
+)
+
+// The header holding the HMAC-SHA256 signature of the payload posted to webhooks, ex) sha256=<hex>
+const SignatureHeader = "X-Azd-Signature"
+
+// Target is an endpoint notified when lifecycle commands complete, declared in the 'notifications' of azure.yaml or of
+// the user configuration.
+type Target struct {
+	// The kind of the endpoint. Defaults to 'webhook'
+	Kind Kind `yaml:"kind,omitempty" json:"kind,omitempty"`
+	// The URL of the endpoint. Supports environment variable substitution, ex) ${TEAMS_WEBHOOK_URL}
+	Url string `yaml:"url" json:"url"`
+	// The secret signing the payload posted to a webhook, or the access key of an Event Grid topic. Supports environment
+	// variable substitution.
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+	// The commands notified, ex) provision, deploy, down, up. Defaults to all of them
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"`
+	// Only notify the failures of the commands
+	FailuresOnly bool `yaml:"failuresOnly,omitempty" json:"failuresOnly,omitempty"`
+}
+
+// Notifies returns whether the target is notified of the payload
+func (t *Target) Notifies(payload *Payload) bool {
+	if t.FailuresOnly && payload.Succeeded() {
+		return false
+	}
+
+	return len(t.Events) == 0 || slices.Contains(t.Events, payload.Operation)
+}
+
+// Validate checks the target can be notified
+func (t *Target) Validate() error {
+	switch t.Kind {
+	case "", KindWebhook, KindTeams, KindSlack, KindEventGrid:
+	default:
+		return fmt.Errorf(
+			"invalid notification kind '%s', supported kinds are: %s, %s, %s, %s",
+			t.Kind, KindWebhook, KindTeams, KindSlack, KindEventGrid)
+	}
+
+	if t.Url == "" {
+		return errors.New("notifications must set a 'url'")
+	}
+
+	return nil
+}
+
+// Endpoint is the endpoint a target posts to, identified by a fingerprint of its url, since the urls of Teams and Slack
+// webhooks are secrets.
+type Endpoint struct {
+	Kind Kind
+	// The host of the url, displayed instead of the url
+	Host string
+	// The hex SHA-256 hash of the kind and the url
+	Fingerprint string
+}
+
+// Endpoint returns the endpoint of the target, once its url is expanded with getenv
+func (t *Target) Endpoint(getenv func(string) string) (*Endpoint, error) {
+	url, err := t.expandUrl(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the url of the %s notification: %w", t.kind(), err)
+	}
+
+	hash := sha256.Sum256([]byte(string(t.kind()) + " " + url))
+	return &Endpoint{
+		Kind:        t.kind(),
+		Host:        parsed.Host,
+		Fingerprint: hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+// Status is the outcome of the command
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Link is a link to more details about the command, ex) the resource group in the Azure Portal
+type Link struct {
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
+
+// Payload is the JSON document posted when a command completes
+type Payload struct {
+	// The type of the event, ex) azd.deploy.succeeded
+	Event           string    `json:"event"`
+	Operation       string    `json:"operation"`
+	Status          Status    `json:"status"`
+	Project         string    `json:"project"`
+	Environment     string    `json:"environment"`
+	StartedAt       time.Time `json:"startedAt"`
+	EndedAt         time.Time `json:"endedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Error           string    `json:"error,omitempty"`
+	Links           []Link    `json:"links,omitempty"`
+}
+
+// NewPayload creates the payload of a command, ex) deploy, which started at startedAt and just ended with err
+func NewPayload(operation string, project string, environment string, startedAt time.Time, err error) *Payload {
+	endedAt := time.Now()
+	payload := &Payload{
+		Event:           fmt.Sprintf("azd.%s.%s", operation, StatusSucceeded),
+		Operation:       operation,
+		Status:          StatusSucceeded,
+		Project:         project,
+		Environment:     environment,
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: endedAt.Sub(startedAt).Round(time.Millisecond).Seconds(),
+	}
+
+	if err != nil {
+		payload.Event = fmt.Sprintf("azd.%s.%s", operation, StatusFailed)
+		payload.Status = StatusFailed
+		payload.Error = err.Error()
+	}
+
+	return payload
+}
+
+// Succeeded returns whether the command succeeded
+func (p *Payload) Succeeded() bool {
+	return p.Status == StatusSucceeded
+}
+
+// Summary describes the payload in a line, ex) azd deploy succeeded for environment dev of project todo in 1m30s
+func (p *Payload) Summary() string {
+	duration := time.Duration(p.DurationSeconds * float64(time.Second)).Round(time.Second)
+	return fmt.Sprintf(
+		"azd %s %s for environment %s of project %s in %s", p.Operation, p.Status, p.Environment, p.Project, duration)
+}
+
+// Notifier posts the notifications of the commands
+type Notifier struct {
+	transporter policy.Transporter
+}
+
+// NewNotifier creates a new Notifier
+func NewNotifier(transporter policy.Transporter) *Notifier {
+	return &Notifier{
+		transporter: transporter,
+	}
+}
+
+// Notify posts the payload to the targets notified of it. The values of the targets are expanded with getenv. Every
+// target is notified, and the errors of the targets which failed are returned together.
+func (n *Notifier) Notify(
+	ctx context.Context,
+	targets []*Target,
+	payload *Payload,
+	getenv func(string) string,
+) error {
+	pipeline := runtime.NewPipeline("azd-notify", internal.Version, runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: n.transporter,
+	})
+
+	var errs []error
+	for _, target := range targets {
+		if !target.Notifies(payload) {
+			continue
+		}
+
+		if err := n.notify(ctx, pipeline, target, payload, getenv); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) notify(
+	ctx context.Context,
+	pipeline runtime.Pipeline,
+	target *Target,
+	payload *Payload,
+	getenv func(string) string,
+) error {
+	if err := target.Validate(); err != nil {
+		return err
+	}
+
+	url, err := target.expandUrl(getenv)
+	if err != nil {
+		return err
+	}
+
+	secret, err := osutil.NewExpandableString(target.Secret).Envsubst(getenv)
+	if err != nil {
+		return fmt.Errorf("expanding the secret of the %s notification: %w", target.kind(), err)
+	}
+
+	body, contentType, err := target.body(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, url)
+	if err != nil {
+		return fmt.Errorf("creating the %s notification: %w", target.kind(), err)
+	}
+
+	switch target.kind() {
+	case KindWebhook:
+		if secret != "" {
+			req.Raw().Header.Set(SignatureHeader, Sign(body, secret))
+		}
+	case KindEventGrid:
+		req.Raw().Header.Set("aeg-sas-key", secret)
+	}
+
+	if err := req.SetBody(streaming.NopCloser(bytes.NewReader(body)), contentType); err != nil {
+		return err
+	}
+
+	// The errors don't include the url, since the urls of Teams and Slack webhooks are secrets
+	res, err := pipeline.Do(req)
+	if urlErr, ok := err.(*neturl.Error); ok {
+		err = urlErr.Err
+	}
+	if err != nil {
+		return fmt.Errorf("posting the %s notification: %w", target.kind(), err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("posting the %s notification: unexpected status code %d", target.kind(), res.StatusCode)
+	}
+
+	return nil
+}
+
+func (t *Target) expandUrl(getenv func(string) string) (string, error) {
+	url, err := osutil.NewExpandableString(t.Url).Envsubst(getenv)
+	if err != nil {
+		return "", fmt.Errorf("expanding the url of the %s notification: %w", t.kind(), err)
+	}
+
+	return url, nil
+}
+
+func (t *Target) kind() Kind {
+	if t.Kind == "" {
+		return KindWebhook
+	}
+
+	return t.Kind
+}
+
+// body returns the body posted to the target, and its content type
+func (t *Target) body(payload *Payload) ([]byte, string, error) {
+	var document any = payload
+	contentType := "application/json"
+
+	switch t.kind() {
+	case KindTeams:
+		document = teamsMessage(payload)
+	case KindSlack:
+		document = slackMessage(payload)
+	case KindEventGrid:
+		document = cloudEvent(payload)
+		contentType = "application/cloudevents+json; charset=utf-8"
+	}
+
+	// The links of Slack messages, ex) <url|title>, aren't escaped
+	body := bytes.Buffer{}
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return nil, "", fmt.Errorf("marshaling the %s notification: %w", t.kind(), err)
+	}
+
+	return body.Bytes(), contentType, nil
+}
+
+// Sign returns the HMAC-SHA256 signature of the body with the secret, ex) sha256=<hex>, set in the X-Azd-Signature
+// header so webhooks can verify the payload was sent by azd.
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// teamsMessage is an adaptive card, as received by the workflows of Teams channels
+func teamsMessage(payload *Payload) map[string]any {
+	duration := time.Duration(payload.DurationSeconds * float64(time.Second)).Round(time.Second)
+	facts := []map[string]string{
+		{"title": "Environment", "value": payload.Environment},
+		{"title": "Duration", "value": duration.String()},
+	}
+	if payload.Error != "" {
+		facts = append(facts, map[string]string{"title": "Error", "value": payload.Error})
+	}
+
+	actions := []map[string]string{}
+	for _, link := range payload.Links {
+		actions = append(actions, map[string]string{"type": "Action.OpenUrl", "title": link.Title, "url": link.Url})
+	}
+
+	color := "Good"
+	if !payload.Succeeded() {
+		color = "Attention"
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []map[string]any{
+						{
+							"type":   "TextBlock",
+							"text":   fmt.Sprintf("azd %s %s: %s", payload.Operation, payload.Status, payload.Project),
+							"weight": "Bolder",
+							"size":   "Medium",
+							"color":  color,
+							"wrap":   true,
+						},
+						{"type": "FactSet", "facts": facts},
+					},
+					"actions": actions,
+				},
+			},
+		},
+	}
+}
+
+// slackMessage is a message, as received by the incoming webhooks of Slack channels
+func slackMessage(payload *Payload) map[string]any {
+	icon := ":white_check_mark:"
+	if !payload.Succeeded() {
+		icon = ":x:"
+	}
+
+	lines := []string{fmt.Sprintf("%s %s", icon, payload.Summary())}
+	if payload.Error != "" {
+		lines = append(lines, fmt.Sprintf("> %s", strings.ReplaceAll(payload.Error, "\n", " ")))
+	}
+	for _, link := range payload.Links {
+		lines = append(lines, fmt.Sprintf("<%s|%s>", link.Url, link.Title))
+	}
+
+	return map[string]any{
+		"text": strings.Join(lines, "\n"),
+	}
+}
+
+// cloudEvent is the payload as a CloudEvent, as received by Event Grid topics
+func cloudEvent(payload *Payload) map[string]any {
+	return map[string]any{
+		"specversion":     "1.0",
+		"id":              fmt.Sprintf("%s-%s-%d", payload.Operation, payload.Environment, payload.EndedAt.UnixNano()),
+		"source":          fmt.Sprintf("azd/%s/%s", payload.Project, payload.Environment),
+		"type":            payload.Event,
+		"time":            payload.EndedAt.UTC().Format(time.RFC3339),
+		"datacontenttype": "application/json",
+		"data":            payload,
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPayload(t *testing.T) {
+	startedAt := time.Now().Add(-90 * time.Second)
+
+	payload := NewPayload("deploy", "todo", "dev", startedAt, nil)
+	require.Equal(t, "azd.deploy.succeeded", payload.Event)
+	require.True(t, payload.Succeeded())
+	require.InDelta(t, 90, payload.DurationSeconds, 1)
+	require.Equal(t, "azd deploy succeeded for environment dev of project todo in 1m30s", payload.Summary())
+
+	payload = NewPayload("provision", "todo", "dev", startedAt, errors.New("deployment failed"))
+	require.Equal(t, "azd.provision.failed", payload.Event)
+	require.Equal(t, StatusFailed, payload.Status)
+	require.Equal(t, "deployment failed", payload.Error)
+}
+
+func TestTarget_Notifies(t *testing.T) {
+	succeeded := &Payload{Operation: "deploy", Status: StatusSucceeded}
+	failed := &Payload{Operation: "deploy", Status: StatusFailed}
+
+	require.True(t, (&Target{}).Notifies(succeeded))
+	require.True(t, (&Target{Events: []string{"provision", "deploy"}}).Notifies(succeeded))
+	require.False(t, (&Target{Events: []string{"down"}}).Notifies(succeeded))
+	require.False(t, (&Target{FailuresOnly: true}).Notifies(succeeded))
+	require.True(t, (&Target{FailuresOnly: true}).Notifies(failed))
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	requests := map[string]*http.Request{}
+	bodies := map[string][]byte{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Host == "hooks.contoso.com"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		requests[request.URL.Path] = request
+		bodies[request.URL.Path] = body
+
+		if request.URL.Path == "/broken" {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusBadRequest)
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	targets := []*Target{
+		{Url: "https://hooks.contoso.com/${HOOK_PATH}", Secret: "${HOOK_SECRET}"},
+		{Kind: KindTeams, Url: "https://hooks.contoso.com/teams"},
+		{Kind: KindSlack, Url: "https://hooks.contoso.com/slack"},
+		{Kind: KindEventGrid, Url: "https://hooks.contoso.com/eventgrid", Secret: "topic-key"},
+		{Kind: KindSlack, Url: "https://hooks.contoso.com/down", Events: []string{"down"}},
+	}
+	env := map[string]string{"HOOK_PATH": "webhook", "HOOK_SECRET": "s3cr3t"}
+	payload := NewPayload("deploy", "todo", "dev", time.Now(), nil)
+	payload.Links = []Link{{Title: "Open in Azure Portal", Url: "https://portal.azure.com/#@/resource/rg"}}
+
+	notifier := NewNotifier(mockContext.HttpClient)
+	err := notifier.Notify(*mockContext.Context, targets, payload, func(name string) string { return env[name] })
+	require.NoError(t, err)
+	require.Len(t, requests, 4)
+
+	// The webhook receives the payload, signed with its secret
+	var received Payload
+	require.NoError(t, json.Unmarshal(bodies["/webhook"], &received))
+	require.Equal(t, "azd.deploy.succeeded", received.Event)
+	require.Equal(t, Sign(bodies["/webhook"], "s3cr3t"), requests["/webhook"].Header.Get(SignatureHeader))
+
+	require.Contains(t, string(bodies["/teams"]), "application/vnd.microsoft.card.adaptive")
+	require.Contains(t, string(bodies["/teams"]), "Action.OpenUrl")
+	require.Contains(t, string(bodies["/slack"]), "<https://portal.azure.com/#@/resource/rg|Open in Azure Portal>")
+
+	require.Equal(t, "topic-key", requests["/eventgrid"].Header.Get("aeg-sas-key"))
+	require.Contains(t, requests["/eventgrid"].Header.Get("Content-Type"), "application/cloudevents+json")
+	require.Contains(t, string(bodies["/eventgrid"]), `"type":"azd.deploy.succeeded"`)
+
+	// A target failing doesn't stop the others from being notified
+	targets = []*Target{
+		{Url: "https://hooks.contoso.com/broken"},
+		{Kind: "email", Url: "https://hooks.contoso.com/email"},
+		{Url: "https://hooks.contoso.com/webhook"},
+	}
+	delete(requests, "/webhook")
+	err = notifier.Notify(*mockContext.Context, targets, payload, func(string) string { return "" })
+	require.ErrorContains(t, err, "unexpected status code 400")
+	require.ErrorContains(t, err, "invalid notification kind 'email'")
+	require.NotContains(t, err.Error(), "hooks.contoso.com")
+	require.Contains(t, requests, "/webhook")
+}
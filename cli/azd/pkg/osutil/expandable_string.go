@@ -25,15 +25,21 @@ func (e ExpandableString) Empty() bool {
 	return e.template == ""
 }
 
-// Envsubst evaluates the template, substituting values as [envsubst.Eval] would.
+// Envsubst evaluates the template, substituting values as [envsubst.Eval] would. References may also call functions,
+// for example ${lower(substr(AZURE_ENV_NAME, 0, 8))} or ${hash(AZURE_SUBSCRIPTION_ID, AZURE_ENV_NAME)}.
 func (e ExpandableString) Envsubst(mapping func(string) string) (string, error) {
-	return envsubst.Eval(e.template, mapping)
+	template, err := expandFuncs(e.template, mapping)
+	if err != nil {
+		return "", err
+	}
+
+	return envsubst.Eval(template, mapping)
 }
 
-// MustEnvsubst evaluates the template, substituting values as [envsubst.Eval] would and panics if there
+// MustEnvsubst evaluates the template, substituting values as [ExpandableString.Envsubst] would and panics if there
 // is an error (for example, the string is malformed).
 func (e ExpandableString) MustEnvsubst(mapping func(string) string) string {
-	if v, err := envsubst.Eval(e.template, mapping); err != nil {
+	if v, err := e.Envsubst(mapping); err != nil {
 		panic(fmt.Sprintf("MustEnvsubst: %v", err))
 	} else {
 		return v
@@ -0,0 +1,249 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package osutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expandableStringFunc is a function which can be called in the ${...} references of an ExpandableString.
+type expandableStringFunc struct {
+	minArgs int
+	// The maximum number of arguments, -1 when the function takes any number of arguments.
+	maxArgs int
+	eval    func(args []string) (string, error)
+}
+
+// expandableStringFuncs are the functions which can be called in the ${...} references of an ExpandableString, in
+// addition to the bash string functions supported by envsubst, ex) ${hash(AZURE_ENV_NAME, AZURE_SUBSCRIPTION_ID)}.
+//
+// The arguments of a function are names of variables, quoted strings, numbers, or calls of other functions.
+var expandableStringFuncs = map[string]expandableStringFunc{
+	// lower(value) returns the value in lower case.
+	"lower": {minArgs: 1, maxArgs: 1, eval: func(args []string) (string, error) {
+		return strings.ToLower(args[0]), nil
+	}},
+	// upper(value) returns the value in upper case.
+	"upper": {minArgs: 1, maxArgs: 1, eval: func(args []string) (string, error) {
+		return strings.ToUpper(args[0]), nil
+	}},
+	// substr(value, start, [length]) returns the part of the value starting at start, up to length characters.
+	"substr": {minArgs: 2, maxArgs: 3, eval: evalSubstr},
+	// alnum(value) returns the value without the characters which aren't letters or digits.
+	"alnum": {minArgs: 1, maxArgs: 1, eval: func(args []string) (string, error) {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+
+			return -1
+		}, args[0]), nil
+	}},
+	// default(value, fallback) returns the fallback when the value is empty.
+	"default": {minArgs: 2, maxArgs: 2, eval: func(args []string) (string, error) {
+		if args[0] == "" {
+			return args[1], nil
+		}
+
+		return args[0], nil
+	}},
+	// hash(values...) returns the SHA-256 hash of the values, in lower case hexadecimal.
+	"hash": {minArgs: 1, maxArgs: -1, eval: func(args []string) (string, error) {
+		hash := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+		return hex.EncodeToString(hash[:]), nil
+	}},
+}
+
+func evalSubstr(args []string) (string, error) {
+	runes := []rune(args[0])
+
+	start, err := strconv.Atoi(args[1])
+	if err != nil || start < 0 {
+		return "", fmt.Errorf("substr: start '%s' is not a positive number", args[1])
+	}
+
+	end := len(runes)
+	if len(args) == 3 {
+		length, err := strconv.Atoi(args[2])
+		if err != nil || length < 0 {
+			return "", fmt.Errorf("substr: length '%s' is not a positive number", args[2])
+		}
+
+		end = min(start+length, end)
+	}
+
+	if start >= end {
+		return "", nil
+	}
+
+	return string(runes[start:end]), nil
+}
+
+// expandFuncs evaluates the ${...} references of the template calling functions, and returns the template with the
+// results in place of the references. The other references are left for envsubst, and the results are escaped so
+// envsubst doesn't evaluate them again.
+func expandFuncs(template string, mapping func(string) string) (string, error) {
+	if !strings.Contains(template, "(") {
+		return template, nil
+	}
+
+	var result strings.Builder
+	for pos := 0; pos < len(template); {
+		// $$ is an escaped $, the reference that follows is not evaluated
+		if strings.HasPrefix(template[pos:], "$$") {
+			result.WriteString("$$")
+			pos += 2
+			continue
+		}
+
+		if strings.HasPrefix(template[pos:], "${") {
+			p := &funcParser{input: template, pos: pos + 2, mapping: mapping}
+			if name := p.ident(); name != "" && p.peek() == '(' {
+				p.pos = pos + 2
+				value, err := p.call()
+				if err != nil {
+					return "", fmt.Errorf("evaluating '%s': %w", template, err)
+				}
+
+				if p.skipSpaces(); p.peek() != '}' {
+					return "", fmt.Errorf("evaluating '%s': missing '}' at position %d", template, p.pos)
+				}
+
+				result.WriteString(strings.ReplaceAll(value, "$", "$$"))
+				pos = p.pos + 1
+				continue
+			}
+		}
+
+		result.WriteByte(template[pos])
+		pos++
+	}
+
+	return result.String(), nil
+}
+
+// funcParser parses and evaluates the function calls of a ${...} reference.
+type funcParser struct {
+	input   string
+	pos     int
+	mapping func(string) string
+}
+
+// call parses and evaluates a function call, ex) lower(AZURE_ENV_NAME).
+func (p *funcParser) call() (string, error) {
+	name := p.ident()
+	function, has := expandableStringFuncs[name]
+	if !has {
+		return "", fmt.Errorf("unknown function '%s'", name)
+	}
+
+	// skip the opening parenthesis
+	p.pos++
+
+	args := []string{}
+	for {
+		if p.skipSpaces(); p.peek() == ')' && len(args) == 0 {
+			p.pos++
+			break
+		}
+
+		arg, err := p.expr()
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+			continue
+		case ')':
+			p.pos++
+		default:
+			return "", fmt.Errorf("expected ',' or ')' at position %d", p.pos)
+		}
+
+		break
+	}
+
+	if len(args) < function.minArgs || (function.maxArgs >= 0 && len(args) > function.maxArgs) {
+		return "", fmt.Errorf("wrong number of arguments for function '%s': %d", name, len(args))
+	}
+
+	return function.eval(args)
+}
+
+// expr parses and evaluates an argument of a function call.
+func (p *funcParser) expr() (string, error) {
+	p.skipSpaces()
+
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		end := strings.IndexByte(p.input[p.pos+1:], c)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string at position %d", p.pos)
+		}
+
+		value := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+
+		return p.input[start:p.pos], nil
+	}
+
+	start := p.pos
+	name := p.ident()
+	if name == "" {
+		return "", fmt.Errorf("unexpected character at position %d", p.pos)
+	}
+
+	if p.skipSpaces(); p.peek() == '(' {
+		p.pos = start
+		return p.call()
+	}
+
+	return p.mapping(name), nil
+}
+
+// ident parses the name of a variable or a function.
+func (p *funcParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := rune(p.input[p.pos])
+		if c != '_' && !unicode.IsLetter(c) && (p.pos == start || !unicode.IsDigit(c)) {
+			break
+		}
+
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func (p *funcParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// peek returns the next character, or 0 at the end of the input.
+func (p *funcParser) peek() byte {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
@@ -35,3 +35,68 @@ func TestExpandableString_Empty(t *testing.T) {
 		assert.False(t, e.Empty())
 	})
 }
+
+func TestExpandableString_Envsubst(t *testing.T) {
+	values := map[string]string{
+		"AZURE_ENV_NAME":        "My-Env_Name",
+		"AZURE_SUBSCRIPTION_ID": "00000000-0000-0000-0000-000000000000",
+		"EMPTY":                 "",
+		"PRICE":                 "$5",
+	}
+	mapping := func(name string) string { return values[name] }
+
+	tests := map[string]struct {
+		template string
+		expected string
+	}{
+		"Variable":         {template: "${AZURE_ENV_NAME}", expected: "My-Env_Name"},
+		"Default":          {template: "${EMPTY:-fallback}", expected: "fallback"},
+		"Lowercase":        {template: "${AZURE_ENV_NAME,,}", expected: "my-env_name"},
+		"Substring":        {template: "${AZURE_ENV_NAME:0:6}", expected: "My-Env"},
+		"Lower":            {template: "${lower(AZURE_ENV_NAME)}", expected: "my-env_name"},
+		"Upper":            {template: "${upper(AZURE_ENV_NAME)}", expected: "MY-ENV_NAME"},
+		"Substr":           {template: "${substr(AZURE_ENV_NAME, 3)}", expected: "Env_Name"},
+		"SubstrLength":     {template: "${substr(AZURE_ENV_NAME, 3, 3)}", expected: "Env"},
+		"SubstrOutOfRange": {template: "${substr(AZURE_ENV_NAME, 3, 100)}|${substr(EMPTY, 2)}", expected: "Env_Name|"},
+		"Alnum":            {template: "${alnum(AZURE_ENV_NAME)}", expected: "MyEnvName"},
+		"DefaultFunc": {
+			template: "${default(EMPTY, 'fallback')}-${default(AZURE_ENV_NAME, \"x\")}",
+			expected: "fallback-My-Env_Name",
+		},
+		"Hash": {template: "${substr(hash('a'), 0, 8)}", expected: "ca978112"},
+		"Nested": {
+			template: "st${lower(alnum(AZURE_ENV_NAME))}${substr(hash('a'), 0, 6)}",
+			expected: "stmyenvnameca9781",
+		},
+		"Escaped":        {template: "$${lower(AZURE_ENV_NAME)}", expected: "${lower(AZURE_ENV_NAME)}"},
+		"DollarInResult": {template: "${lower(PRICE)} ${PRICE}", expected: "$5 $5"},
+		"Unchanged":      {template: "(${AZURE_ENV_NAME})", expected: "(My-Env_Name)"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, err := NewExpandableString(test.template).Envsubst(mapping)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+
+	errorTests := map[string]struct {
+		template string
+		expected string
+	}{
+		"UnknownFunction":  {template: "${trim(AZURE_ENV_NAME)}", expected: "unknown function 'trim'"},
+		"WrongArgs":        {template: "${lower(AZURE_ENV_NAME, EMPTY)}", expected: "wrong number of arguments"},
+		"InvalidStart":     {template: "${substr(AZURE_ENV_NAME, 'x')}", expected: "start 'x' is not a positive number"},
+		"MissingBrace":     {template: "${lower(AZURE_ENV_NAME)", expected: "missing '}'"},
+		"MissingParen":     {template: "${lower(AZURE_ENV_NAME}", expected: "expected ',' or ')'"},
+		"UnterminatedText": {template: "${lower('abc)}", expected: "unterminated string"},
+	}
+
+	for name, test := range errorTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewExpandableString(test.template).Envsubst(mapping)
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
@@ -9,39 +9,40 @@ import (
 	"github.com/fatih/color"
 )
 
+// The colors of the formats depend on the theme of the console output, see SetTheme.
+
 // withLinkFormat creates string with hyperlink-looking color
 func WithLinkFormat(link string, a ...interface{}) string {
-	return color.HiCyanString(link, a...)
+	return currentPalette().link.sprintf(link, a...)
 }
 
 // withHighLightFormat creates string with highlight-looking color
 func WithHighLightFormat(text string, a ...interface{}) string {
-	return color.HiBlueString(text, a...)
+	return currentPalette().highlight.sprintf(text, a...)
 }
 
 func WithErrorFormat(text string, a ...interface{}) string {
-	return color.RedString(text, a...)
+	return currentPalette().error.sprintf(text, a...)
 }
 
 func WithWarningFormat(text string, a ...interface{}) string {
-	return color.YellowString(text, a...)
+	return currentPalette().warning.sprintf(text, a...)
 }
 
 func WithSuccessFormat(text string, a ...interface{}) string {
-	return color.GreenString(text, a...)
+	return currentPalette().success.sprintf(text, a...)
 }
 
 func WithGrayFormat(text string, a ...interface{}) string {
-	return color.HiBlackString(text, a...)
+	return currentPalette().gray.sprintf(text, a...)
 }
 
 func WithHintFormat(text string, a ...interface{}) string {
-	return color.MagentaString(text, a...)
+	return currentPalette().hint.sprintf(text, a...)
 }
 
 func WithBold(text string, a ...interface{}) string {
-	format := color.New(color.FgHiWhite, color.Bold)
-	return format.Sprintf(text, a...)
+	return currentPalette().bold.sprintf(text, a...)
 }
 
 func WithUnderline(text string, a ...interface{}) string {
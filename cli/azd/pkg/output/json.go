@@ -46,19 +46,34 @@ var _ Formatter = (*JsonFormatter)(nil)
 // jsonObjectForMessage creates a json object representing a message. Any ANSI control sequences from the message are
 // removed. A trailing newline is added to the message.
 func EventForMessage(message string) contracts.EventEnvelope {
-	// Strip any ANSI colors for the message.
+	// Add the newline that would have been added by fmt.Println when we wrote the message directly to the console.
+	return newConsoleMessageEvent(StripAnsi(message) + "\n")
+}
+
+// StripAnsi removes any ANSI colors from the message.
+func StripAnsi(message string) string {
 	var buf bytes.Buffer
 
 	// We do not expect the io.Copy to fail since none of these sub-calls will ever return an error (other than
 	// EOF when we hit the end of the string)
 	if _, err := io.Copy(colorable.NewNonColorable(&buf), strings.NewReader(message)); err != nil {
-		panic(fmt.Sprintf("consoleMessageForMessage: did not expect error from io.Copy but got: %v", err))
+		panic(fmt.Sprintf("StripAnsi: did not expect error from io.Copy but got: %v", err))
 	}
 
-	// Add the newline that would have been added by fmt.Println when we wrote the message directly to the console.
-	buf.WriteByte('\n')
+	return buf.String()
+}
 
-	return newConsoleMessageEvent(buf.String())
+// EventForError creates an event describing the error of a failed command. Any ANSI control sequences from the message
+// and the suggestion are removed.
+func EventForError(consoleError contracts.ConsoleError) contracts.EventEnvelope {
+	consoleError.Message = StripAnsi(consoleError.Message)
+	consoleError.Suggestion = StripAnsi(consoleError.Suggestion)
+
+	return contracts.EventEnvelope{
+		Type:      contracts.ConsoleErrorEventDataType,
+		Timestamp: time.Now(),
+		Data:      consoleError,
+	}
 }
 
 func newConsoleMessageEvent(msg string) contracts.EventEnvelope {
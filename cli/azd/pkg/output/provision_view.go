@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// ProvisionView is how the progress of a provisioning is displayed, configured with the ux.provisionView key of the
+// user config.
+type ProvisionView string
+
+const (
+	// The default view, a spinner with the resources in progress and a line per resource when it's completed
+	ProvisionViewDefault ProvisionView = "default"
+	// A full-screen dashboard with a live tree of the resources of the deployment, with their states, durations and
+	// the recent errors. The default view is used when the console isn't interactive.
+	ProvisionViewTui ProvisionView = "tui"
+)
+
+// ProvisionViewConfigPath is the user config path of the view of the progress of a provisioning
+const ProvisionViewConfigPath = "ux.provisionView"
+
+// ProvisionViews are the supported provisioning views
+var ProvisionViews = []ProvisionView{ProvisionViewDefault, ProvisionViewTui}
+
+var currentProvisionView atomic.Value
+
+// ParseProvisionView returns the provisioning view of the given name, or an error listing the supported views.
+func ParseProvisionView(name string) (ProvisionView, error) {
+	view := ProvisionView(strings.ToLower(strings.TrimSpace(name)))
+	if !slices.Contains(ProvisionViews, view) {
+		names := make([]string, len(ProvisionViews))
+		for i, view := range ProvisionViews {
+			names[i] = string(view)
+		}
+
+		return "", fmt.Errorf(
+			"invalid provisioning view '%s', supported views are: %s", name, strings.Join(names, ", "))
+	}
+
+	return view, nil
+}
+
+// SetProvisionView sets the view of the progress of a provisioning
+func SetProvisionView(view ProvisionView) {
+	currentProvisionView.Store(view)
+}
+
+// CurrentProvisionView returns the view of the progress of a provisioning, ProvisionViewDefault by default
+func CurrentProvisionView() ProvisionView {
+	if view, ok := currentProvisionView.Load().(ProvisionView); ok {
+		return view
+	}
+
+	return ProvisionViewDefault
+}
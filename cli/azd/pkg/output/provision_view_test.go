@@ -0,0 +1,19 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseProvisionView(t *testing.T) {
+	view, err := ParseProvisionView(" TUI ")
+	require.NoError(t, err)
+	require.Equal(t, ProvisionViewTui, view)
+
+	_, err = ParseProvisionView("fullscreen")
+	require.ErrorContains(t, err, "supported views are: default, tui")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RedactedMask replaces the secrets in the output
+const RedactedMask = "******"
+
+// minSecretLength is the length under which values aren't redacted, since masking them would mask unrelated output, ex)
+// a secret 'true' would mask every boolean value.
+const minSecretLength = 4
+
+// redactor masks the secrets known to azd, and the values matching the redaction patterns, in the output.
+type redactor struct {
+	mu       sync.RWMutex
+	secrets  []string
+	patterns []*regexp.Regexp
+}
+
+var defaultRedactor = &redactor{}
+
+// AddSecret registers a secret value, ex) a secure parameter or a secret fetched from Key Vault, to mask in the console
+// output, the debug logs and the formatted output of the commands.
+func AddSecret(value string) {
+	if len(strings.TrimSpace(value)) < minSecretLength {
+		return
+	}
+
+	defaultRedactor.mu.Lock()
+	defer defaultRedactor.mu.Unlock()
+
+	forms := []string{value}
+	// Secrets in the JSON output are escaped, ex) a password including a quote
+	if encoded, err := json.Marshal(value); err == nil {
+		if escaped := string(encoded[1 : len(encoded)-1]); escaped != value {
+			forms = append(forms, escaped)
+		}
+	}
+
+	for _, form := range forms {
+		if !slices.Contains(defaultRedactor.secrets, form) {
+			defaultRedactor.secrets = append(defaultRedactor.secrets, form)
+		}
+	}
+
+	// The longest secrets are masked first, so a secret containing another one is fully masked
+	slices.SortFunc(defaultRedactor.secrets, func(a, b string) int { return len(b) - len(a) })
+}
+
+// AddRedactionPattern registers a pattern which matches are masked in the output.
+func AddRedactionPattern(pattern *regexp.Regexp) {
+	defaultRedactor.mu.Lock()
+	defer defaultRedactor.mu.Unlock()
+
+	defaultRedactor.patterns = append(defaultRedactor.patterns, pattern)
+}
+
+// Redact masks the registered secrets and the matches of the redaction patterns in the value.
+func Redact(value string) string {
+	defaultRedactor.mu.RLock()
+	defer defaultRedactor.mu.RUnlock()
+
+	for _, secret := range defaultRedactor.secrets {
+		value = strings.ReplaceAll(value, secret, RedactedMask)
+	}
+
+	for _, pattern := range defaultRedactor.patterns {
+		value = pattern.ReplaceAllString(value, RedactedMask)
+	}
+
+	return value
+}
+
+// hasRedactions returns true when there are secrets or patterns to mask.
+func hasRedactions() bool {
+	defaultRedactor.mu.RLock()
+	defer defaultRedactor.mu.RUnlock()
+
+	return len(defaultRedactor.secrets) > 0 || len(defaultRedactor.patterns) > 0
+}
+
+// resetRedactions removes the registered secrets and patterns.
+func resetRedactions() {
+	defaultRedactor.mu.Lock()
+	defer defaultRedactor.mu.Unlock()
+
+	defaultRedactor.secrets = nil
+	defaultRedactor.patterns = nil
+}
+
+// RedactingWriter masks the secrets in what is written to the underlying writer. A secret split across writes isn't
+// masked, which is fine for the line oriented output of azd.
+type RedactingWriter struct {
+	writer io.Writer
+}
+
+// NewRedactingWriter creates a writer masking the secrets written to the given writer.
+func NewRedactingWriter(writer io.Writer) *RedactingWriter {
+	return &RedactingWriter{writer: writer}
+}
+
+// Write writes the data to the underlying writer, with the secrets masked. The length of the data is returned, not the
+// length of the masked data, since callers expect their whole data to be written.
+func (w *RedactingWriter) Write(p []byte) (int, error) {
+	if !hasRedactions() {
+		return w.writer.Write(p)
+	}
+
+	if _, err := io.WriteString(w.writer, Redact(string(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Redact(t *testing.T) {
+	t.Cleanup(resetRedactions)
+
+	// nothing is masked until secrets are registered
+	require.Equal(t, "password: P@ss\"word1", Redact("password: P@ss\"word1"))
+
+	AddSecret("P@ss\"word1")
+	AddSecret("P@ss\"word1-suffix")
+	// short values aren't masked
+	AddSecret("yes")
+
+	require.Equal(t, "password: ******, confirmed: yes", Redact("password: P@ss\"word1, confirmed: yes"))
+	require.Equal(t, "connection: ******", Redact("connection: P@ss\"word1-suffix"))
+	// the JSON escaped form of the secret is masked too
+	require.Equal(t, `{"password": "******"}`, Redact(`{"password": "P@ss\"word1"}`))
+
+	AddRedactionPattern(regexp.MustCompile(`AccountKey=[^;]+`))
+	require.Equal(t, "Endpoint=https://st;******", Redact("Endpoint=https://st;AccountKey=abc123=="))
+}
+
+func Test_RedactingWriter(t *testing.T) {
+	t.Cleanup(resetRedactions)
+
+	buf := &bytes.Buffer{}
+	writer := NewRedactingWriter(buf)
+
+	n, err := writer.Write([]byte("token: abcdef\n"))
+	require.NoError(t, err)
+	require.Equal(t, 14, n)
+
+	AddSecret("abcdef")
+	n, err = writer.Write([]byte("token: abcdef\n"))
+	require.NoError(t, err)
+	require.Equal(t, 14, n)
+
+	require.Equal(t, "token: abcdef\ntoken: ******\n", buf.String())
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// Theme is the color palette and the set of characters used in the console output, configured with the ux.theme key of
+// the user config.
+type Theme string
+
+const (
+	// The default theme, for terminals with a dark background
+	ThemeDark Theme = "dark"
+	// Darker colors, readable on terminals with a light background
+	ThemeLight Theme = "light"
+	// Bold, bright colors and the default foreground color instead of gray, for low vision users
+	ThemeHighContrast Theme = "high-contrast"
+	// The colors of the dark theme, and ASCII characters instead of unicode symbols, for screen readers and terminals
+	// without unicode fonts
+	ThemeNoUnicode Theme = "no-unicode"
+)
+
+// ThemeConfigPath is the user config path of the theme of the console output
+const ThemeConfigPath = "ux.theme"
+
+// ReducedMotionConfigPath is the user config path which disables the animation of spinners when true
+const ReducedMotionConfigPath = "ux.reducedMotion"
+
+// Themes are the supported themes
+var Themes = []Theme{ThemeDark, ThemeLight, ThemeHighContrast, ThemeNoUnicode}
+
+// style is the color attributes of a kind of text
+type style []color.Attribute
+
+// sprintf formats the text like the color functions of the color package, which don't format text without arguments
+func (s style) sprintf(text string, a ...interface{}) string {
+	c := color.New(s...)
+	if len(a) == 0 {
+		return c.SprintFunc()(text)
+	}
+
+	return c.SprintfFunc()(text, a...)
+}
+
+// palette is the style of each kind of text of a theme
+type palette struct {
+	link      style
+	highlight style
+	error     style
+	warning   style
+	success   style
+	gray      style
+	hint      style
+	bold      style
+}
+
+var darkPalette = palette{
+	link:      style{color.FgHiCyan},
+	highlight: style{color.FgHiBlue},
+	error:     style{color.FgRed},
+	warning:   style{color.FgYellow},
+	success:   style{color.FgGreen},
+	gray:      style{color.FgHiBlack},
+	hint:      style{color.FgMagenta},
+	bold:      style{color.FgHiWhite, color.Bold},
+}
+
+var palettes = map[Theme]palette{
+	ThemeDark: darkPalette,
+	ThemeLight: {
+		link:      style{color.FgBlue},
+		highlight: style{color.FgBlue},
+		error:     style{color.FgRed},
+		warning:   style{color.FgMagenta},
+		success:   style{color.FgGreen},
+		gray:      style{color.FgHiBlack},
+		hint:      style{color.FgBlue},
+		bold:      style{color.Bold},
+	},
+	ThemeHighContrast: {
+		link:      style{color.FgHiCyan, color.Underline},
+		highlight: style{color.Bold},
+		error:     style{color.FgHiRed, color.Bold},
+		warning:   style{color.FgHiYellow, color.Bold},
+		success:   style{color.FgHiGreen, color.Bold},
+		gray:      style{color.Reset},
+		hint:      style{color.Bold},
+		bold:      style{color.Bold},
+	},
+	ThemeNoUnicode: darkPalette,
+}
+
+var currentTheme atomic.Value
+var reducedMotion atomic.Bool
+
+// ParseTheme returns the theme of the given name, or an error listing the supported themes.
+func ParseTheme(name string) (Theme, error) {
+	theme := Theme(strings.ToLower(strings.TrimSpace(name)))
+	if !slices.Contains(Themes, theme) {
+		names := make([]string, len(Themes))
+		for i, theme := range Themes {
+			names[i] = string(theme)
+		}
+
+		return "", fmt.Errorf("invalid theme '%s', supported themes are: %s", name, strings.Join(names, ", "))
+	}
+
+	return theme, nil
+}
+
+// SetTheme sets the theme of the console output
+func SetTheme(theme Theme) {
+	currentTheme.Store(theme)
+}
+
+// CurrentTheme returns the theme of the console output, ThemeDark by default
+func CurrentTheme() Theme {
+	if theme, ok := currentTheme.Load().(Theme); ok {
+		return theme
+	}
+
+	return ThemeDark
+}
+
+// SetReducedMotion disables the animation of spinners and progress indicators when true
+func SetReducedMotion(reduced bool) {
+	reducedMotion.Store(reduced)
+}
+
+// ReducedMotion returns true when spinners and progress indicators shouldn't be animated
+func ReducedMotion() bool {
+	return reducedMotion.Load()
+}
+
+// currentPalette returns the palette of the current theme
+func currentPalette() palette {
+	return palettes[CurrentTheme()]
+}
+
+// Symbol returns the unicode symbol, or its ASCII alternative with the no-unicode theme.
+func Symbol(unicode string, ascii string) string {
+	if CurrentTheme() == ThemeNoUnicode {
+		return ascii
+	}
+
+	return unicode
+}
+
+// CheckMark returns the check mark used for completed and successful steps
+func CheckMark() string {
+	return Symbol("✓", "v")
+}
+
+// Bullet returns the bullet used for list items
+func Bullet() string {
+	return Symbol("•", "*")
+}
+
+// HorizontalLine returns a horizontal line of the given length
+func HorizontalLine(length int) string {
+	if length <= 0 {
+		return ""
+	}
+
+	return strings.Repeat(Symbol("─", "-"), length)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseTheme(t *testing.T) {
+	theme, err := ParseTheme("High-Contrast")
+	require.NoError(t, err)
+	require.Equal(t, ThemeHighContrast, theme)
+
+	_, err = ParseTheme("solarized")
+	require.ErrorContains(t, err, "supported themes are: dark, light, high-contrast, no-unicode")
+}
+
+func Test_Theme(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		SetTheme(ThemeDark)
+	})
+
+	t.Run("Dark", func(t *testing.T) {
+		SetTheme(ThemeDark)
+		require.Equal(t, color.HiCyanString("https://%s", "aka.ms"), WithLinkFormat("https://%s", "aka.ms"))
+		require.Equal(t, color.YellowString("100%"), WithWarningFormat("100%"))
+		require.Equal(t, "✓", CheckMark())
+	})
+
+	t.Run("Light", func(t *testing.T) {
+		SetTheme(ThemeLight)
+		require.Equal(t, color.BlueString("link"), WithLinkFormat("link"))
+		require.Equal(t, color.New(color.Bold).Sprint("bold"), WithBold("bold"))
+	})
+
+	t.Run("NoUnicode", func(t *testing.T) {
+		SetTheme(ThemeNoUnicode)
+		require.Equal(t, color.HiCyanString("link"), WithLinkFormat("link"))
+		require.Equal(t, "v", CheckMark())
+		require.Equal(t, "*", Bullet())
+		require.Equal(t, "---", HorizontalLine(3))
+		require.Equal(t, "", HorizontalLine(-1))
+	})
+}
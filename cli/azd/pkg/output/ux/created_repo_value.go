@@ -28,11 +28,11 @@ func (cr *CreatedRepoValue) ToString(currentIndentation string) string {
 	if action == "" {
 		action = "Setting"
 	}
-	return fmt.Sprintf("%s%s %s %s repo %s", currentIndentation, donePrefix, action, cr.Name, cr.Kind)
+	return fmt.Sprintf("%s%s %s %s repo %s", currentIndentation, donePrefix(), action, cr.Name, cr.Kind)
 }
 
 func (cr *CreatedRepoValue) MarshalJSON() ([]byte, error) {
 	// reusing the same envelope from console messages
 	return json.Marshal(output.EventForMessage(
-		fmt.Sprintf("%s Setting %s repo %s", donePrefix, cr.Name, cr.Kind)))
+		fmt.Sprintf("%s Setting %s repo %s", donePrefix(), cr.Name, cr.Kind)))
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/fatih/color"
@@ -56,11 +57,18 @@ type ShowEnvironment struct {
 	IsRemote  bool
 }
 
+// ShowScheduledAction is an action scheduled on the current environment, ex) its deletion when it expires
+type ShowScheduledAction struct {
+	ActionType    string
+	ScheduledTime time.Time
+}
+
 type Show struct {
-	AppName         string
-	Services        []*ShowService
-	Environments    []*ShowEnvironment
-	AzurePortalLink string
+	AppName          string
+	Services         []*ShowService
+	Environments     []*ShowEnvironment
+	ScheduledActions []*ShowScheduledAction
+	AzurePortalLink  string
 }
 
 func (s *Show) ToString(currentIndentation string) string {
@@ -69,7 +77,7 @@ func (s *Show) ToString(currentIndentation string) string {
 		pickHeader = "\nShowing services and environments for apps in this directory.\n"
 	}
 	return fmt.Sprintf(
-		"%s%s%s%s%s%s%s%s%s    %s\n",
+		"%s%s%s%s%s%s%s%s%s%s    %s\n",
 		pickHeader,
 		"To view a different environment, run ",
 		output.WithHighLightFormat("%s\n\n", "azd show -e <environment name>"),
@@ -78,11 +86,33 @@ func (s *Show) ToString(currentIndentation string) string {
 		services(s.Services),
 		"\n  Environments:\n",
 		environments(s.Environments),
+		scheduledActions(s.ScheduledActions),
 		"\n  View in Azure Portal:\n",
 		azurePortalLink(s.AzurePortalLink),
 	)
 }
 
+// scheduledActions lists the actions scheduled on the current environment, nothing when there are none
+func scheduledActions(actions []*ShowScheduledAction) string {
+	if len(actions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n  Scheduled actions:\n")
+	for _, action := range actions {
+		sb.WriteString(fmt.Sprintf(
+			"    %s  %s\n",
+			output.WithHighLightFormat(action.ActionType),
+			action.ScheduledTime.UTC().Format(time.RFC1123),
+		))
+	}
+
+	sb.WriteString(output.WithGrayFormat(
+		"    To push out the deletion of the environment, run %s\n", "azd env extend --hours <hours>"))
+	return sb.String()
+}
+
 func azurePortalLink(link string) string {
 	if link == "" {
 		return fmt.Sprintf(
@@ -163,3 +193,75 @@ func (s *ShowResource) ToString(currentIndentation string) string {
 func (s *ShowResource) MarshalJSON() ([]byte, error) {
 	return nil, fmt.Errorf("not implemented")
 }
+
+type ShowServiceDeployment struct {
+	Id      string
+	Status  string
+	Time    *time.Time
+	Version string
+}
+
+// ShowServiceDetails displays the details of a service of the project.
+type ShowServiceDetails struct {
+	Name              string
+	Host              string
+	ResourceIds       []string
+	Endpoints         []string
+	Identities        []string
+	DeployedVersion   string
+	RecentDeployments []*ShowServiceDeployment
+}
+
+func (s *ShowServiceDetails) ToString(currentIndentation string) string {
+	var sb strings.Builder
+	sb.WriteString(color.HiMagentaString("%s (%s)", s.Name, s.Host))
+	sb.WriteString("\n")
+
+	writeList := func(title string, items []string, format func(string) string) {
+		sb.WriteString(fmt.Sprintf("  %s:\n", title))
+		if len(items) == 0 {
+			sb.WriteString(output.WithGrayFormat("    None\n"))
+			return
+		}
+
+		for _, item := range items {
+			sb.WriteString(fmt.Sprintf("    %s\n", format(item)))
+		}
+	}
+
+	plain := func(value string) string { return value }
+	link := func(value string) string { return output.WithLinkFormat(value) }
+
+	writeList("Resources", s.ResourceIds, plain)
+	writeList("Endpoints", s.Endpoints, link)
+	writeList("Managed identities", s.Identities, plain)
+
+	sb.WriteString("  Deployed version:\n")
+	if s.DeployedVersion == "" {
+		sb.WriteString(output.WithGrayFormat("    Unknown\n"))
+	} else {
+		sb.WriteString(fmt.Sprintf("    %s\n", output.WithHighLightFormat(s.DeployedVersion)))
+	}
+
+	deployments := make([]string, len(s.RecentDeployments))
+	for i, deployment := range s.RecentDeployments {
+		line := deployment.Id
+		if deployment.Time != nil {
+			line = fmt.Sprintf("%s  %s", deployment.Time.Format("2006-01-02 15:04:05"), line)
+		}
+		if deployment.Status != "" {
+			line = fmt.Sprintf("%s  %s", line, deployment.Status)
+		}
+		if deployment.Version != "" {
+			line = fmt.Sprintf("%s  %s", line, output.WithGrayFormat(deployment.Version))
+		}
+		deployments[i] = line
+	}
+	writeList("Recent deployments", deployments, plain)
+
+	return sb.String()
+}
+
+func (s *ShowServiceDetails) MarshalJSON() ([]byte, error) {
+	return nil, fmt.Errorf("not implemented")
+}
@@ -5,6 +5,7 @@ package ux
 
 import (
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/test/snapshot"
 )
@@ -104,3 +105,63 @@ func TestShowRemoteAndCurrent(t *testing.T) {
 	output := pp.ToString("")
 	snapshot.SnapshotT(t, output)
 }
+
+func TestShowScheduledActions(t *testing.T) {
+	pp := &Show{
+		AppName: "Foo",
+		Environments: []*ShowEnvironment{
+			{
+				Name:      "dev",
+				IsCurrent: true,
+			},
+		},
+		ScheduledActions: []*ShowScheduledAction{
+			{
+				ActionType:    "Delete",
+				ScheduledTime: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		AzurePortalLink: "foo.com",
+	}
+
+	output := pp.ToString("")
+	snapshot.SnapshotT(t, output)
+}
+
+func TestShowServiceDetails(t *testing.T) {
+	deployedOn := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	pp := &ShowServiceDetails{
+		Name:        "api",
+		Host:        "containerapp",
+		ResourceIds: []string{"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps/api"},
+		Endpoints:   []string{"https://api.example.com/"},
+		Identities: []string{
+			"SystemAssigned (principal ID: 00000000-0000-0000-0000-000000000000)",
+		},
+		DeployedVersion: "registry.azurecr.io/api:azd-deploy-2",
+		RecentDeployments: []*ShowServiceDeployment{
+			{
+				Id:      "api--azd-2",
+				Status:  "Running",
+				Time:    &deployedOn,
+				Version: "registry.azurecr.io/api:azd-deploy-2",
+			},
+			{
+				Id: "api--azd-1",
+			},
+		},
+	}
+
+	output := pp.ToString("")
+	snapshot.SnapshotT(t, output)
+}
+
+func TestShowServiceDetailsEmpty(t *testing.T) {
+	pp := &ShowServiceDetails{
+		Name: "api",
+		Host: "appservice",
+	}
+
+	output := pp.ToString("")
+	snapshot.SnapshotT(t, output)
+}
@@ -16,5 +16,9 @@ type UxItem interface {
 	json.Marshaler
 }
 
-var donePrefix string = output.WithSuccessFormat("(✓) Done:")
+// donePrefix returns the prefix of completed steps, formatted with the theme of the console output
+func donePrefix() string {
+	return output.WithSuccessFormat("(%s) Done:", output.CheckMark())
+}
+
 var failedPrefix string = output.WithErrorFormat("(x) Failed:")
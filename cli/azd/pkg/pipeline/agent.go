@@ -0,0 +1,582 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/resources"
+)
+
+// The kinds of self-hosted agents bootstrapped by 'azd pipeline agent bootstrap'
+const (
+	// A Managed DevOps Pool, for Azure Pipelines
+	AgentKindManagedPool = "managed-pool"
+	// Ephemeral GitHub Actions runners started by an Azure Container Apps job, for GitHub Actions
+	AgentKindContainer = "container"
+)
+
+// AgentInfraDirectory is the directory of the infrastructure of the agent, relative to the project directory. The files
+// are generated on the first bootstrap, and deployed again, with the changes of the user, by the next ones.
+var AgentInfraDirectory = filepath.Join("infra", "pipeline-agent")
+
+// AgentBootstrapArgs are the arguments of 'azd pipeline agent bootstrap'
+type AgentBootstrapArgs struct {
+	// The pipeline provider, github or azdo. Defaults to the provider of the project.
+	Provider string
+	// The kind of agent. Defaults to a Managed DevOps Pool for Azure Pipelines, and container runners for GitHub Actions.
+	Kind string
+	// The name of the agent pool, or the label of the runners. Defaults to azd-<environment name>.
+	Name string
+	// The resource group of the agent. Defaults to rg-<environment name>-agent.
+	ResourceGroup string
+}
+
+// AgentBootstrapResult is the agent bootstrapped, saved in azure.yaml for the pipelines generated by
+// 'azd pipeline config'
+type AgentBootstrapResult struct {
+	Agent         project.PipelineAgent
+	Provider      ciProviderType
+	ResourceGroup string
+	// The pipeline definition already generated, which doesn't run on the agent until it is updated
+	PipelineFile string
+}
+
+// AgentBootstrapper provisions the self-hosted agent running the pipeline generated by 'azd pipeline config', for
+// enterprises that can't use the hosted agents of the providers.
+type AgentBootstrapper struct {
+	azdCtx             *azdcontext.AzdContext
+	env                *environment.Environment
+	envManager         environment.Manager
+	console            input.Console
+	gitCli             *git.Cli
+	bicepCli           *bicep.Cli
+	resourceService    *azapi.ResourceService
+	deploymentService  azapi.DeploymentService
+	remoteBuildManager *containerregistry.RemoteBuildManager
+}
+
+// NewAgentBootstrapper creates a new AgentBootstrapper
+func NewAgentBootstrapper(
+	azdCtx *azdcontext.AzdContext,
+	env *environment.Environment,
+	envManager environment.Manager,
+	console input.Console,
+	gitCli *git.Cli,
+	bicepCli *bicep.Cli,
+	resourceService *azapi.ResourceService,
+	deploymentService azapi.DeploymentService,
+	remoteBuildManager *containerregistry.RemoteBuildManager,
+) *AgentBootstrapper {
+	return &AgentBootstrapper{
+		azdCtx:             azdCtx,
+		env:                env,
+		envManager:         envManager,
+		console:            console,
+		gitCli:             gitCli,
+		bicepCli:           bicepCli,
+		resourceService:    resourceService,
+		deploymentService:  deploymentService,
+		remoteBuildManager: remoteBuildManager,
+	}
+}
+
+// Bootstrap provisions the agent and saves it in azure.yaml, so the pipelines generated by 'azd pipeline config' run on
+// it.
+func (b *AgentBootstrapper) Bootstrap(
+	ctx context.Context,
+	prjConfig *project.ProjectConfig,
+	args AgentBootstrapArgs,
+) (*AgentBootstrapResult, error) {
+	provider, err := b.resolveProvider(prjConfig, args.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, err := resolveAgentKind(provider, args.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	name := args.Name
+	if name == "" {
+		name = fmt.Sprintf("azd-%s", b.env.Name())
+	}
+
+	resourceGroup := args.ResourceGroup
+	if resourceGroup == "" {
+		resourceGroup = fmt.Sprintf("rg-%s-agent", b.env.Name())
+	}
+
+	subscriptionId := b.env.GetSubscriptionId()
+	location := b.env.GetLocation()
+	if subscriptionId == "" || location == "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: errors.New("the subscription and the location of the agent are not set"),
+			Suggestion: fmt.Sprintf("Suggestion: run '%s' first, or set them with '%s' and '%s'.",
+				output.WithHighLightFormat("azd provision"),
+				output.WithHighLightFormat("azd env set %s", environment.SubscriptionIdEnvVarName),
+				output.WithHighLightFormat("azd env set %s", environment.LocationEnvVarName)),
+		}
+	}
+
+	infraDir := filepath.Join(b.azdCtx.ProjectDirectory(), AgentInfraDirectory)
+	if err := writeAgentInfra(infraDir, kind, prjConfig); err != nil {
+		return nil, err
+	}
+
+	b.console.ShowSpinner(ctx, fmt.Sprintf("Creating resource group %s", resourceGroup), input.Step)
+	_, err = b.resourceService.CreateOrUpdateResourceGroup(ctx, subscriptionId, resourceGroup, location,
+		map[string]*string{azure.TagKeyAzdEnvName: to.Ptr(b.env.Name())})
+	b.console.StopSpinner(ctx, fmt.Sprintf("Creating resource group %s", resourceGroup), input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	target := agentDeploymentTarget{
+		subscriptionId: subscriptionId,
+		resourceGroup:  resourceGroup,
+		template:       filepath.Join(infraDir, "main.bicep"),
+		tags:           map[string]any{azure.TagKeyAzdEnvName: b.env.Name()},
+	}
+
+	switch kind {
+	case AgentKindManagedPool:
+		err = b.bootstrapManagedPool(ctx, target, name)
+	default:
+		err = b.bootstrapContainer(ctx, target, infraDir, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	agent := project.PipelineAgent{Kind: kind, Name: name}
+	prjConfig.Pipeline.Agent = &agent
+
+	// The project may include the overrides of the environment, which mustn't be saved in azure.yaml
+	savedConfig, err := project.Load(ctx, b.azdCtx.ProjectPath())
+	if err != nil {
+		return nil, fmt.Errorf("loading azure.yaml: %w", err)
+	}
+
+	savedConfig.Pipeline.Agent = &agent
+	if err := project.Save(ctx, savedConfig, b.azdCtx.ProjectPath()); err != nil {
+		return nil, fmt.Errorf("saving the agent in azure.yaml: %w", err)
+	}
+
+	result := &AgentBootstrapResult{Agent: agent, Provider: provider, ResourceGroup: resourceGroup}
+	repoRoot, err := b.gitCli.GetRepoRoot(ctx, b.azdCtx.ProjectDirectory())
+	if err != nil {
+		repoRoot = b.azdCtx.ProjectDirectory()
+	}
+
+	for _, path := range pipelineProviderFiles[provider].Files {
+		if osutil.FileExists(filepath.Join(repoRoot, path)) {
+			result.PipelineFile = path
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// resolveProvider returns the provider of the args, or of the project, or the one last used by 'azd pipeline config'
+func (b *AgentBootstrapper) resolveProvider(prjConfig *project.ProjectConfig, provider string) (ciProviderType, error) {
+	if provider == "" {
+		provider = prjConfig.Pipeline.Provider
+	}
+
+	if provider == "" {
+		provider, _ = b.env.LookupEnv(envPersistedKey)
+	}
+
+	if provider == "" {
+		return ciProviderGitHubActions, nil
+	}
+
+	return toCiProviderType(strings.ToLower(provider))
+}
+
+// resolveAgentKind returns the kind of agent of the provider. Managed DevOps Pools only run Azure Pipelines, and the
+// container runners are GitHub Actions runners.
+func resolveAgentKind(provider ciProviderType, kind string) (string, error) {
+	supported := AgentKindContainer
+	if provider == ciProviderAzureDevOps {
+		supported = AgentKindManagedPool
+	}
+
+	if kind != "" && kind != supported {
+		if kind != AgentKindContainer && kind != AgentKindManagedPool {
+			return "", fmt.Errorf("invalid agent kind '%s', supported values are: %s, %s",
+				kind, AgentKindManagedPool, AgentKindContainer)
+		}
+
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("the '%s' agent isn't supported for the %s provider", kind, provider),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: Managed DevOps Pools run Azure Pipelines (--provider azdo), and container runners run "+
+					"GitHub Actions (--provider github). Use '--kind %s' for the %s provider.", supported, provider),
+		}
+	}
+
+	return supported, nil
+}
+
+// agentDeploymentTarget is where the infrastructure of the agent is deployed
+type agentDeploymentTarget struct {
+	subscriptionId string
+	resourceGroup  string
+	template       string
+	tags           map[string]any
+}
+
+// deploy compiles and deploys the template of the agent with the parameters, and returns the outputs of the deployment
+func (b *AgentBootstrapper) deploy(
+	ctx context.Context,
+	target agentDeploymentTarget,
+	title string,
+	parameters map[string]any,
+) (map[string]any, error) {
+	b.console.ShowSpinner(ctx, title, input.Step)
+	outputs, err := func() (map[string]any, error) {
+		build, err := b.bicepCli.Build(ctx, target.template)
+		if err != nil {
+			return nil, err
+		}
+
+		armParameters := azure.ArmParameters{"tags": {Value: target.tags}}
+		for name, value := range parameters {
+			armParameters[name] = azure.ArmParameter{Value: value}
+		}
+
+		deploymentName := fmt.Sprintf("azd-pipeline-agent-%d", time.Now().Unix())
+		deployment, err := b.deploymentService.DeployToResourceGroup(
+			ctx,
+			target.subscriptionId,
+			target.resourceGroup,
+			deploymentName,
+			azure.RawArmTemplate(build.Compiled),
+			armParameters,
+			nil,
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return deploymentOutputs(deployment.Outputs), nil
+	}()
+	b.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, fmt.Errorf("deploying the pipeline agent: %w", err)
+	}
+
+	return outputs, nil
+}
+
+// bootstrapManagedPool deploys a Managed DevOps Pool, which creates the agent pool of the same name in the Azure
+// DevOps organization.
+func (b *AgentBootstrapper) bootstrapManagedPool(ctx context.Context, target agentDeploymentTarget, name string) error {
+	orgName, _, err := azdo.EnsureOrgNameExists(ctx, b.envManager, b.env, b.console)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.deploy(ctx, target, fmt.Sprintf("Deploying Managed DevOps Pool %s", name), map[string]any{
+		"poolName":        name,
+		"organizationUrl": fmt.Sprintf("https://%s/%s", azdo.AzDoHostName, orgName),
+	})
+	return err
+}
+
+// bootstrapContainer deploys the registry of the runner image, builds the image in the registry, then deploys the
+// Container Apps job starting the runners.
+func (b *AgentBootstrapper) bootstrapContainer(
+	ctx context.Context,
+	target agentDeploymentTarget,
+	infraDir string,
+	name string,
+) error {
+	owner, repo, err := b.gitHubRepository(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := b.gitHubToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	parameters := map[string]any{"name": name}
+	outputs, err := b.deploy(ctx, target, "Deploying the registry of the runner image", parameters)
+	if err != nil {
+		return err
+	}
+
+	registryName, _ := outputs["AZURE_CONTAINER_REGISTRY_NAME"].(string)
+	loginServer, _ := outputs["AZURE_CONTAINER_REGISTRY_ENDPOINT"].(string)
+	if registryName == "" || loginServer == "" {
+		return errors.New("the deployment of the pipeline agent didn't output the container registry")
+	}
+
+	image := fmt.Sprintf("%s/azd-pipeline-agent/%s:%d", loginServer, name, time.Now().Unix())
+	if err := b.buildRunnerImage(ctx, target, infraDir, registryName, image); err != nil {
+		return err
+	}
+
+	parameters["deployJob"] = true
+	parameters["image"] = image
+	parameters["owner"] = owner
+	parameters["repo"] = repo
+	parameters["githubToken"] = token
+	_, err = b.deploy(ctx, target, fmt.Sprintf("Deploying the runners of %s/%s", owner, repo), parameters)
+	return err
+}
+
+// buildRunnerImage builds the image of the runner from the Dockerfile of the agent infrastructure, in the registry
+func (b *AgentBootstrapper) buildRunnerImage(
+	ctx context.Context,
+	target agentDeploymentTarget,
+	infraDir string,
+	registryName string,
+	image string,
+) error {
+	contextPath, dockerPath, err := containerregistry.PackRemoteBuildSource(
+		ctx, infraDir, filepath.Join(infraDir, "Dockerfile"), nil)
+	if contextPath != "" {
+		defer os.Remove(contextPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	source, err := b.remoteBuildManager.UploadBuildSource(
+		ctx, target.subscriptionId, target.resourceGroup, registryName, contextPath)
+	if err != nil {
+		return fmt.Errorf("uploading the runner image build context: %w", err)
+	}
+
+	previewer := b.console.ShowPreviewer(ctx, &input.ShowPreviewerOptions{
+		Prefix:       "  ",
+		MaxLineCount: 8,
+		Title:        "Building the runner image",
+	})
+	err = b.remoteBuildManager.RunDockerBuildRequestWithLogs(
+		ctx, target.subscriptionId, target.resourceGroup, registryName, &armcontainerregistry.DockerBuildRequest{
+			SourceLocation: source.RelativePath,
+			DockerFilePath: to.Ptr(dockerPath),
+			IsPushEnabled:  to.Ptr(true),
+			ImageNames:     []*string{to.Ptr(image)},
+			Platform: &armcontainerregistry.PlatformProperties{
+				OS:           to.Ptr(armcontainerregistry.OSLinux),
+				Architecture: to.Ptr(armcontainerregistry.ArchitectureAmd64),
+			},
+		}, previewer)
+	b.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return fmt.Errorf("building the runner image: %w", err)
+	}
+
+	return nil
+}
+
+// gitHubRepository returns the owner and the name of the GitHub repository of the project, from its origin remote
+func (b *AgentBootstrapper) gitHubRepository(ctx context.Context) (string, string, error) {
+	remoteUrl, err := b.gitCli.GetRemoteUrl(ctx, b.azdCtx.ProjectDirectory(), "origin")
+	if err != nil {
+		return "", "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("getting the GitHub repository of the runners: %w", err),
+			Suggestion: fmt.Sprintf("Suggestion: run '%s' first to push the project to a GitHub repository.",
+				output.WithHighLightFormat("azd pipeline config")),
+		}
+	}
+
+	details, err := (&GitHubScmProvider{}).gitRepoDetails(ctx, remoteUrl)
+	if err != nil {
+		return "", "", err
+	}
+
+	return details.owner, details.repoName, nil
+}
+
+// gitHubToken returns the token registering the runners, from GITHUB_TOKEN or GH_TOKEN, or prompted
+func (b *AgentBootstrapper) gitHubToken(ctx context.Context) (string, error) {
+	for _, name := range github.TokenEnvVars {
+		if token, has := b.env.LookupEnv(name); has && token != "" {
+			return token, nil
+		}
+	}
+
+	b.console.Message(ctx, fmt.Sprintf(
+		"The runners are registered with a %s allowed to administer the repository (%s).",
+		output.WithWarningFormat("GitHub token"),
+		output.WithHighLightFormat("Administration: Read and write")))
+	b.console.Message(ctx, fmt.Sprintf("(%s this prompt by setting the token to env var: %s)",
+		output.WithWarningFormat("skip"),
+		output.WithHighLightFormat(github.TokenEnvVars[0])))
+
+	token, err := b.console.Prompt(ctx, input.ConsoleOptions{
+		Message:    "GitHub token:",
+		IsPassword: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("prompting for the GitHub token: %w", err)
+	}
+
+	return token, nil
+}
+
+// deploymentOutputs returns the values of the outputs of a deployment by name
+func deploymentOutputs(outputs any) map[string]any {
+	values := map[string]any{}
+	raw, err := json.Marshal(outputs)
+	if err != nil {
+		return values
+	}
+
+	parsed := map[string]struct {
+		Value any `json:"value"`
+	}{}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		log.Printf("failed parsing the outputs of the deployment: %v", err)
+		return values
+	}
+
+	for name, output := range parsed {
+		values[name] = output.Value
+	}
+
+	return values
+}
+
+// agentTools are the tools installed in the image of the container runners
+type agentTools struct {
+	Node      bool
+	Python    bool
+	DotNet    bool
+	Java      bool
+	Terraform bool
+}
+
+// requiredAgentTools returns the tools needed to build and deploy the services of the project
+func requiredAgentTools(prjConfig *project.ProjectConfig) agentTools {
+	tools := agentTools{Terraform: prjConfig.Infra.Provider == provisioning.Terraform}
+	for _, svc := range prjConfig.Services {
+		switch {
+		case svc.Language.IsDotNet():
+			tools.DotNet = true
+		case svc.Language == project.ServiceLanguageJavaScript ||
+			svc.Language == project.ServiceLanguageTypeScript ||
+			svc.Language == project.ServiceLanguageSwa:
+			tools.Node = true
+		case svc.Language == project.ServiceLanguagePython:
+			tools.Python = true
+		case svc.Language == project.ServiceLanguageJava:
+			tools.Java = true
+		}
+	}
+
+	return tools
+}
+
+// writeAgentInfra writes the infrastructure of the agent to the directory, keeping the files which already exist
+func writeAgentInfra(infraDir string, kind string, prjConfig *project.ProjectConfig) error {
+	files := map[string]func() ([]byte, error){}
+	if kind == AgentKindManagedPool {
+		files["main.bicep"] = embeddedAgentFile("managed-pool.bicep")
+	} else {
+		files["main.bicep"] = embeddedAgentFile("container.bicep")
+		files["entrypoint.sh"] = embeddedAgentFile("entrypoint.sh")
+		files["Dockerfile"] = func() ([]byte, error) {
+			return renderAgentDockerfile(requiredAgentTools(prjConfig))
+		}
+	}
+
+	if err := os.MkdirAll(infraDir, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(infraDir, name)
+		if _, err := os.Stat(path); err == nil {
+			log.Printf("keeping the existing pipeline agent file %s", path)
+			continue
+		}
+
+		data, err := contents()
+		if err != nil {
+			return err
+		}
+
+		perm := osutil.PermissionFile
+		if filepath.Ext(name) == ".sh" {
+			perm = osutil.PermissionExecutableFile
+		}
+
+		if err := os.WriteFile(path, data, perm); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func embeddedAgentFile(name string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return fs.ReadFile(resources.PipelineFiles, "pipeline/agent/"+name)
+	}
+}
+
+// renderAgentDockerfile returns the Dockerfile of the image of the container runners, with the tools installed
+func renderAgentDockerfile(tools agentTools) ([]byte, error) {
+	tmpl, err := template.New("agent").
+		Option("missingkey=error").
+		ParseFS(resources.PipelineFiles, "pipeline/agent/Dockerfile.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parsing the Dockerfile template: %w", err)
+	}
+
+	builder := strings.Builder{}
+	if err := tmpl.ExecuteTemplate(&builder, "Dockerfile", tools); err != nil {
+		return nil, fmt.Errorf("executing the Dockerfile template: %w", err)
+	}
+
+	return []byte(builder.String()), nil
+}
+
+// pipelineAgentName returns the name of the agent running the pipeline, empty for the hosted agents
+func pipelineAgentName(agent *project.PipelineAgent) string {
+	if agent == nil {
+		return ""
+	}
+
+	return agent.Name
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resolveAgentKind(t *testing.T) {
+	kind, err := resolveAgentKind(ciProviderAzureDevOps, "")
+	require.NoError(t, err)
+	require.Equal(t, AgentKindManagedPool, kind)
+
+	kind, err = resolveAgentKind(ciProviderGitHubActions, AgentKindContainer)
+	require.NoError(t, err)
+	require.Equal(t, AgentKindContainer, kind)
+
+	_, err = resolveAgentKind(ciProviderGitHubActions, AgentKindManagedPool)
+	var errWithSuggestion *internal.ErrorWithSuggestion
+	require.ErrorAs(t, err, &errWithSuggestion)
+	require.Contains(t, errWithSuggestion.Suggestion, "--kind container")
+
+	_, err = resolveAgentKind(ciProviderGitHubActions, "vm")
+	require.ErrorContains(t, err, "invalid agent kind 'vm'")
+}
+
+func Test_requiredAgentTools(t *testing.T) {
+	prjConfig := &project.ProjectConfig{
+		Infra: provisioning.Options{Provider: provisioning.Terraform},
+		Services: map[string]*project.ServiceConfig{
+			"web": {Language: project.ServiceLanguageTypeScript},
+			"api": {Language: project.ServiceLanguageCsharp},
+		},
+	}
+
+	require.Equal(t, agentTools{Node: true, DotNet: true, Terraform: true}, requiredAgentTools(prjConfig))
+}
+
+func Test_renderAgentDockerfile(t *testing.T) {
+	dockerfile, err := renderAgentDockerfile(agentTools{})
+	require.NoError(t, err)
+	require.Contains(t, string(dockerfile), "FROM ghcr.io/actions/actions-runner:latest")
+	require.Contains(t, string(dockerfile), "install-azd.sh")
+	require.NotContains(t, string(dockerfile), "python3")
+	require.NotContains(t, string(dockerfile), "nodejs")
+
+	dockerfile, err = renderAgentDockerfile(agentTools{Python: true, Node: true, Java: true})
+	require.NoError(t, err)
+	require.Contains(t, string(dockerfile), "python3-venv")
+	require.Contains(t, string(dockerfile), "nodejs")
+	require.Contains(t, string(dockerfile), "openjdk-17-jdk maven")
+	require.NotContains(t, string(dockerfile), "dotnet-install.sh")
+}
+
+func Test_writeAgentInfra(t *testing.T) {
+	t.Run("ManagedPool", func(t *testing.T) {
+		infraDir := filepath.Join(t.TempDir(), AgentInfraDirectory)
+		require.NoError(t, writeAgentInfra(infraDir, AgentKindManagedPool, &project.ProjectConfig{}))
+
+		entries, err := os.ReadDir(infraDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		bicep, err := os.ReadFile(filepath.Join(infraDir, "main.bicep"))
+		require.NoError(t, err)
+		require.Contains(t, string(bicep), "Microsoft.DevOpsInfrastructure/pools")
+	})
+
+	t.Run("ContainerKeepsExistingFiles", func(t *testing.T) {
+		infraDir := filepath.Join(t.TempDir(), AgentInfraDirectory)
+		require.NoError(t, os.MkdirAll(infraDir, osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(filepath.Join(infraDir, "Dockerfile"), []byte("FROM custom"), osutil.PermissionFile))
+
+		require.NoError(t, writeAgentInfra(infraDir, AgentKindContainer, &project.ProjectConfig{}))
+
+		dockerfile, err := os.ReadFile(filepath.Join(infraDir, "Dockerfile"))
+		require.NoError(t, err)
+		require.Equal(t, "FROM custom", string(dockerfile))
+		require.FileExists(t, filepath.Join(infraDir, "entrypoint.sh"))
+
+		bicep, err := os.ReadFile(filepath.Join(infraDir, "main.bicep"))
+		require.NoError(t, err)
+		require.Contains(t, string(bicep), "Microsoft.App/jobs")
+	})
+}
+
+func Test_deploymentOutputs(t *testing.T) {
+	outputs := deploymentOutputs(map[string]any{
+		"AZURE_CONTAINER_REGISTRY_NAME": map[string]any{"type": "String", "value": "cr123"},
+	})
+	require.Equal(t, map[string]any{"AZURE_CONTAINER_REGISTRY_NAME": "cr123"}, outputs)
+
+	require.Empty(t, deploymentOutputs(nil))
+}
+
+func Test_generatePipelineDefinition_Agent(t *testing.T) {
+	tests := map[ciProviderType]string{
+		ciProviderGitHubActions: "runs-on: [self-hosted, azd-dev]",
+		ciProviderAzureDevOps:   "name: azd-dev",
+	}
+
+	for provider, expected := range tests {
+		t.Run(string(provider), func(t *testing.T) {
+			tempDir := t.TempDir()
+			path := filepath.Join(tempDir, pipelineProviderFiles[provider].PipelineDirectories[0])
+			require.NoError(t, os.MkdirAll(path, osutil.PermissionDirectory))
+			expectedPath := filepath.Join(tempDir, pipelineProviderFiles[provider].Files[0])
+			err := generatePipelineDefinition(expectedPath, projectProperties{
+				CiProvider:    provider,
+				InfraProvider: infraProviderBicep,
+				RepoRoot:      tempDir,
+				BranchName:    "main",
+				AuthType:      AuthTypeFederated,
+				Agent:         pipelineAgentName(&project.PipelineAgent{Kind: AgentKindContainer, Name: "azd-dev"}),
+			})
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(expectedPath)
+			require.NoError(t, err)
+			require.Contains(t, string(content), expected)
+			require.NotContains(t, string(content), "ubuntu-latest")
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
@@ -27,6 +28,26 @@ import (
 	azdoGit "github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
 )
 
+var (
+	azdoProjectPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "AZDO_PROJECT",
+		Description: "How to configure the git remote: 'Select an existing Azure DevOps project' or " +
+			"'Create a new Azure DevOps Project'.",
+		Commands: []string{"pipeline config"},
+	})
+	azdoRemotePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "AZDO_REMOTE",
+		Description: "How to configure the repository of the remote: 'Select an existing Azure DevOps Repository' or " +
+			"'Create a new private Azure DevOps Repository'.",
+		Commands: []string{"pipeline config"},
+	})
+	azdoRepositoryNamePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "AZDO_REPOSITORY_NAME",
+		Description: "The name of the Azure DevOps repository to create.",
+		Commands:    []string{"pipeline config"},
+	})
+)
+
 // AzdoScmProvider implements ScmProvider using Azure DevOps as the provider
 // for source control manager.
 type AzdoScmProvider struct {
@@ -151,6 +172,7 @@ func (p *AzdoScmProvider) createNewGitRepositoryFromInput(ctx context.Context, c
 	var repo *azdoGit.GitRepository
 	for {
 		name, err := console.Prompt(ctx, input.ConsoleOptions{
+			Id:           azdoRepositoryNamePromptId,
 			Message:      "Enter the name for your new Azure DevOps Repository OR Hit enter to use this name:",
 			DefaultValue: p.repoDetails.projectName,
 		})
@@ -254,6 +276,7 @@ func (p *AzdoScmProvider) ensureProjectExists(ctx context.Context, console input
 		return p.repoDetails.projectName, p.repoDetails.projectId, false, nil
 	}
 	idx, err := console.Select(ctx, input.ConsoleOptions{
+		Id:      azdoProjectPromptId,
 		Message: "How would you like to configure your git remote to Azure DevOps?",
 		Options: []string{
 			"Select an existing Azure DevOps project",
@@ -381,6 +404,7 @@ func (p *AzdoScmProvider) promptForAzdoRepository(ctx context.Context, console i
 	var remoteUrl string
 	// There are a few ways to configure the remote so offer a choice to the user.
 	idx, err := console.Select(ctx, input.ConsoleOptions{
+		Id:      azdoRemotePromptId,
 		Message: fmt.Sprintf("How would you like to configure your remote? (Organization: %s)", p.repoDetails.projectName),
 		Options: []string{
 			"Select an existing Azure DevOps Repository",
@@ -711,6 +735,7 @@ func (p *AzdoCiProvider) credentialOptions(
 	infraOptions provisioning.Options,
 	authType PipelineAuthType,
 	credentials *entraid.AzureCredentials,
+	stages []*pipelineStage,
 ) (*CredentialOptions, error) {
 	if authType == AuthTypeClientCredentials {
 		return &CredentialOptions{
@@ -792,6 +817,56 @@ func (p *AzdoCiProvider) configureConnection(
 	return err
 }
 
+// configureEnvironments creates or updates the Azure DevOps environment of each stage, where the approvals of the stage
+// are checked, and the variable group holding the variables and secrets of the stage.
+func (p *AzdoCiProvider) configureEnvironments(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	stages []*pipelineStage,
+) error {
+	details := repoDetails.details.(*AzdoRepositoryDetails)
+	org, _, err := azdo.EnsureOrgNameExists(ctx, p.envManager, p.Env, p.console)
+	if err != nil {
+		return err
+	}
+	pat, _, err := azdo.EnsurePatExists(ctx, p.Env, p.console)
+	if err != nil {
+		return err
+	}
+	connection, err := azdo.GetConnection(ctx, org, pat)
+	if err != nil {
+		return err
+	}
+
+	for _, stage := range stages {
+		environment, err := azdo.EnsureEnvironment(ctx, connection, details.projectId, stage.name)
+		if err != nil {
+			return err
+		}
+
+		if len(stage.approvers) > 0 {
+			err := azdo.EnsureApprovalCheck(ctx, connection, details.projectId, environment, stage.approvers)
+			if err != nil {
+				return err
+			}
+		}
+
+		groupName := azdo.VariableGroupName(stage.name)
+		_, err = azdo.EnsureVariableGroup(
+			ctx, connection, details.projectId, groupName, stage.variables, stage.secrets)
+		if err != nil {
+			return err
+		}
+
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "Azure DevOps environment",
+			Name: fmt.Sprintf("%s (%d approvers, variable group %s)", stage.name, len(stage.approvers), groupName),
+		})
+	}
+
+	return nil
+}
+
 // configurePipeline create Azdo pipeline
 func (p *AzdoCiProvider) configurePipeline(
 	ctx context.Context,
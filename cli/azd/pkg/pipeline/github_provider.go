@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/fs"
 	"maps"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"regexp"
@@ -31,6 +32,65 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 )
 
+var (
+	gitHubRemotePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "GITHUB_REMOTE",
+		Description: "How to configure the git remote: 'Select an existing GitHub project', " +
+			"'Create a new private GitHub repository' or 'Enter a remote URL directly'.",
+		Commands: []string{"pipeline config"},
+	})
+	gitHubRepositoryPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "GITHUB_REPOSITORY",
+		Description: "The existing GitHub repository to use, ex) owner/repo.",
+		Commands:    []string{"pipeline config"},
+	})
+	gitHubRepositoryNamePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "GITHUB_REPOSITORY_NAME",
+		Description: "The name of the GitHub repository to create.",
+		Commands:    []string{"pipeline config"},
+	})
+	gitHubRemoteUrlPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "GITHUB_REMOTE_URL",
+		Description: "The url of the git remote.",
+		Commands:    []string{"pipeline config"},
+	})
+	gitHubActionsDisabledPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "GITHUB_ACTIONS_DISABLED",
+		Description: "What to do when GitHub Actions are disabled for the repository, the text of the option, ex) " +
+			"'Exit without pushing my changes. I don't need to run GitHub actions right now.'.",
+		Commands: []string{"pipeline config"},
+	})
+	gitHubExistingSecretPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "GITHUB_EXISTING_SECRET",
+		Description: "What to do with the secrets which already exist in the pipeline, the text of the option, ex) " +
+			"'Keep ALL existing secrets from pipeline (ignore this and any other existing secret).'.",
+		Commands: []string{"pipeline config"},
+	})
+	gitHubUnusedSecretPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "GITHUB_UNUSED_SECRET",
+		Description: "What to do with the secrets of the pipeline which are no longer required, the text of the " +
+			"option, ex) 'Ignore and keep ALL unused secrets from the pipeline.'.",
+		Commands: []string{"pipeline config"},
+	})
+	gitHubExistingVariablePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "GITHUB_EXISTING_VARIABLE",
+		Description: "What to do with the variables which already exist in the pipeline, the text of the option, ex) " +
+			"'Keep ALL existing variables from pipeline (ignore this and any other existing variable).'.",
+		Commands: []string{"pipeline config"},
+	})
+	gitHubUnusedVariablePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "GITHUB_UNUSED_VARIABLE",
+		Description: "What to do with the variables of the pipeline which are no longer required, the text of the " +
+			"option, ex) 'Ignore and keep ALL unused variables from the pipeline.'.",
+		Commands: []string{"pipeline config"},
+	})
+	gitHubLoginPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "GITHUB_LOGIN",
+		Description: "Whether to log in to GitHub with the GitHub CLI: true or false.",
+		Commands:    []string{"pipeline config", "init"},
+	})
+)
+
 // GitHubScmProvider implements ScmProvider using GitHub as the provider
 // for source control manager.
 type GitHubScmProvider struct {
@@ -90,6 +150,7 @@ func (p *GitHubScmProvider) configureGitRemote(
 
 	// There are a few ways to configure the remote so offer a choice to the user.
 	idx, err := p.console.Select(ctx, input.ConsoleOptions{
+		Id:      gitHubRemotePromptId,
 		Message: "How would you like to configure your git remote to GitHub?",
 		Options: []string{
 			"Select an existing GitHub project",
@@ -277,6 +338,7 @@ func (p *GitHubScmProvider) notifyWhenGitHubActionsAreDisabled(
 		p.console.Message(ctx, message)
 
 		rawSelection, err := p.console.Select(ctx, input.ConsoleOptions{
+			Id:      gitHubActionsDisabledPromptId,
 			Message: "What would you like to do now?",
 			Options: []string{
 				manualChoice.String(),
@@ -369,6 +431,7 @@ func (p *GitHubCiProvider) credentialOptions(
 	infraOptions provisioning.Options,
 	authType PipelineAuthType,
 	credentials *entraid.AzureCredentials,
+	stages []*pipelineStage,
 ) (*CredentialOptions, error) {
 	if authType == AuthTypeClientCredentials {
 		return &CredentialOptions{
@@ -409,6 +472,17 @@ func (p *GitHubCiProvider) credentialOptions(
 			federatedCredentials = append(federatedCredentials, branchCredentials)
 		}
 
+		// Jobs deploying to a GitHub environment get a token for the environment, instead of the branch
+		for _, stage := range stages {
+			federatedCredentials = append(federatedCredentials, &graphsdk.FederatedIdentityCredential{
+				Name:        url.PathEscape(fmt.Sprintf("%s-env-%s", credentialSafeName, stage.name)),
+				Issuer:      federatedIdentityIssuer,
+				Subject:     fmt.Sprintf("repo:%s:environment:%s", repoSlug, stage.name),
+				Description: to.Ptr("Created by Azure Developer CLI"),
+				Audiences:   []string{federatedIdentityAudience},
+			})
+		}
+
 		return &CredentialOptions{
 			EnableFederatedCredentials: true,
 			FederatedCredentialOptions: federatedCredentials,
@@ -423,6 +497,97 @@ func (p *GitHubCiProvider) credentialOptions(
 
 // ***  ciProvider implementation ******
 
+// configureEnvironments creates or updates the GitHub environment of each stage. The reviewers of the environment must
+// approve the jobs deploying to it, and the variables and secrets of the stage are set on the environment, overriding the
+// values set on the repository.
+func (p *GitHubCiProvider) configureEnvironments(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	stages []*pipelineStage,
+) error {
+	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+	for _, stage := range stages {
+		reviewers, err := p.resolveReviewers(ctx, stage.approvers)
+		if err != nil {
+			return fmt.Errorf("resolving reviewers of environment %s: %w", stage.name, err)
+		}
+
+		body, err := json.Marshal(map[string]any{"reviewers": reviewers})
+		if err != nil {
+			return err
+		}
+
+		_, err = p.ghCli.ApiCall(
+			ctx,
+			github.GitHubHostName,
+			fmt.Sprintf("/repos/%s/environments/%s", repoSlug, url.PathEscape(stage.name)),
+			github.ApiCallOptions{
+				Method: http.MethodPut,
+				Body:   string(body),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("configuring environment %s: %w", stage.name, err)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(stage.variables)) {
+			if err := p.ghCli.SetEnvironmentVariable(ctx, repoSlug, stage.name, name, stage.variables[name]); err != nil {
+				return fmt.Errorf("failed setting %s variable of environment %s: %w", name, stage.name, err)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(stage.secrets)) {
+			if err := p.ghCli.SetEnvironmentSecret(ctx, repoSlug, stage.name, name, stage.secrets[name]); err != nil {
+				return fmt.Errorf("failed setting %s secret of environment %s: %w", name, stage.name, err)
+			}
+		}
+
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "GitHub environment",
+			Name: fmt.Sprintf("%s (%d reviewers, %d variables, %d secrets)",
+				stage.name, len(reviewers), len(stage.variables), len(stage.secrets)),
+		})
+	}
+
+	return nil
+}
+
+// gitHubReviewer is a reviewer of the deployments to a GitHub environment.
+type gitHubReviewer struct {
+	Type string `json:"type"`
+	Id   int64  `json:"id"`
+}
+
+// resolveReviewers resolves the GitHub id of each approver, which is either a user login or an 'org/team' slug.
+func (p *GitHubCiProvider) resolveReviewers(ctx context.Context, approvers []string) ([]gitHubReviewer, error) {
+	reviewers := make([]gitHubReviewer, 0, len(approvers))
+	for _, approver := range approvers {
+		reviewer := gitHubReviewer{Type: "User"}
+		apiPath := fmt.Sprintf("/users/%s", url.PathEscape(approver))
+		if org, team, isTeam := strings.Cut(approver, "/"); isTeam {
+			reviewer.Type = "Team"
+			apiPath = fmt.Sprintf("/orgs/%s/teams/%s", url.PathEscape(org), url.PathEscape(team))
+		}
+
+		response, err := p.ghCli.ApiCall(ctx, github.GitHubHostName, apiPath, github.ApiCallOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("finding reviewer %s: %w", approver, err)
+		}
+
+		var account struct {
+			Id int64 `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(response), &account); err != nil {
+			return nil, fmt.Errorf("parsing reviewer %s: %w", approver, err)
+		}
+
+		reviewer.Id = account.Id
+		reviewers = append(reviewers, reviewer)
+	}
+
+	return reviewers, nil
+}
+
 // configureConnection set up GitHub account with Azure Credentials for
 // GitHub actions to use a service principal account to log in to Azure
 // and make changes on behalf of a user.
@@ -678,6 +843,7 @@ func (p *GitHubCiProvider) configurePipeline(
 				selectionUpdateAll,
 			}
 			selectionIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+				Id: gitHubExistingSecretPromptId,
 				Message: fmt.Sprintf(
 					"The secret %s already exists in the pipeline. What would you like AZD to do?", existingSecret),
 				Options:      options,
@@ -750,6 +916,7 @@ func (p *GitHubCiProvider) configurePipeline(
 				selectionDeleteAll,
 			}
 			selectionIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+				Id: gitHubUnusedSecretPromptId,
 				Message: fmt.Sprintf(
 					"The secret %s exists in the pipeline but is no longer required. What would you like AZD to do?",
 					existingSecret),
@@ -855,6 +1022,7 @@ func (p *GitHubCiProvider) configurePipeline(
 				selectionUpdateAllVars,
 			}
 			selectionIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+				Id: gitHubExistingVariablePromptId,
 				Message: fmt.Sprintf(
 					"The variable %s already exists in the pipeline. What would you like AZD to do?", existingVariable),
 				Options:      options,
@@ -927,6 +1095,7 @@ func (p *GitHubCiProvider) configurePipeline(
 				selectionDeleteAllVars,
 			}
 			selectionIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+				Id: gitHubUnusedVariablePromptId,
 				Message: fmt.Sprintf(
 					"The variable %s exists in the pipeline but is no longer required. What would you like AZD to do?",
 					existingVariable),
@@ -1031,6 +1200,7 @@ func ensureGitHubLogin(
 	for {
 		var accept bool
 		accept, err := console.Confirm(ctx, input.ConsoleOptions{
+			Id:           gitHubLoginPromptId,
 			Message:      "This command requires you to be logged into GitHub. Log in using the GitHub CLI?",
 			DefaultValue: true,
 		})
@@ -1060,6 +1230,11 @@ func ensureGitHubLogin(
 			return true, nil
 		}
 
+		// The same answer would be returned forever
+		if gitHubLoginPromptId.HasAnswer() {
+			return false, errors.New("logging into GitHub failed; use `gh auth login` to log into GitHub")
+		}
+
 		fmt.Fprintln(console.Handles().Stdout, "There was an issue logging into GitHub.")
 	}
 }
@@ -1082,6 +1257,7 @@ func getRemoteUrlFromExisting(ctx context.Context, ghCli *github.Cli, console in
 	}
 
 	repoIdx, err := console.Select(ctx, input.ConsoleOptions{
+		Id:      gitHubRepositoryPromptId,
 		Message: "Choose an existing GitHub repository",
 		Options: options,
 	})
@@ -1123,6 +1299,7 @@ func getRemoteUrlFromNewRepository(
 
 	for {
 		name, err := console.Prompt(ctx, input.ConsoleOptions{
+			Id:           gitHubRepositoryNamePromptId,
 			Message:      "Enter the name for your new repository OR Hit enter to use this name:",
 			DefaultValue: currentDirectoryName,
 		})
@@ -1157,6 +1334,7 @@ func getRemoteUrlFromPrompt(ctx context.Context, remoteName string, console inpu
 
 	for remoteUrl == "" {
 		promptValue, err := console.Prompt(ctx, input.ConsoleOptions{
+			Id:      gitHubRemoteUrlPromptId,
 			Message: fmt.Sprintf("Enter the url to use for remote %s:", remoteName),
 		})
 
@@ -137,7 +137,15 @@ type CiProvider interface {
 		infraOptions provisioning.Options,
 		authType PipelineAuthType,
 		credentials *entraid.AzureCredentials,
+		stages []*pipelineStage,
 	) (*CredentialOptions, error)
+	// configureEnvironments creates the deployment environment of each stage of a multi-stage pipeline, with its
+	// approvals, variables and secrets.
+	configureEnvironments(
+		ctx context.Context,
+		repoDetails *gitRepositoryDetails,
+		stages []*pipelineStage,
+	) error
 }
 
 // mergeProjectVariablesAndSecrets returns the list of variables and secrets to be used in the pipeline
@@ -329,7 +337,12 @@ type projectProperties struct {
 	Variables             []string
 	Secrets               []string
 	RequiredAlphaFeatures []string
-	providerParameters    []provisioning.Parameter
+	// Stages are the stages of a multi-stage pipeline, empty for a single-stage pipeline
+	Stages []pipelineStageTemplate
+	// Agent is the agent pool, or the label of the runners, of the self-hosted agent running the pipeline. Empty for
+	// the hosted agents.
+	Agent              string
+	providerParameters []provisioning.Parameter
 }
 
 type authConfiguration struct {
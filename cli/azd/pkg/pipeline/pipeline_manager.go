@@ -41,6 +41,46 @@ import (
 	"github.com/sethvargo/go-retry"
 )
 
+var (
+	pipelineProviderPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "PIPELINE_PROVIDER",
+		Description: "The CI/CD provider of the pipeline: 'GitHub' or 'Azure DevOps'.",
+		Commands:    []string{"pipeline config"},
+	})
+	pipelineAuthTypePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "PIPELINE_AUTH_TYPE",
+		Description: "How the pipeline authenticates to Azure: 'Federated User Managed Identity', " +
+			"'Federated Service Principal', 'Client Credentials' or 'Skip authentication setup'.",
+		Commands: []string{"pipeline config"},
+	})
+	pipelineMsiPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id: "PIPELINE_MSI",
+		Description: "Whether to create a User Managed Identity for the pipeline or use an existing one: " +
+			"'Create new User Managed Identity' or 'Use existing User Managed Identity'.",
+		Commands: []string{"pipeline config"},
+	})
+	pipelineExistingMsiPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "PIPELINE_EXISTING_MSI",
+		Description: "The name of the existing User Managed Identity used by the pipeline.",
+		Commands:    []string{"pipeline config"},
+	})
+	pipelineGitInitPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "PIPELINE_GIT_INIT",
+		Description: "Whether to initialize a git repository in the project directory: true or false.",
+		Commands:    []string{"pipeline config"},
+	})
+	pipelineAddWorkflowPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "PIPELINE_ADD_WORKFLOW",
+		Description: "Whether to add the default pipeline definition when it's missing: true or false.",
+		Commands:    []string{"pipeline config"},
+	})
+	pipelinePushPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "PIPELINE_PUSH",
+		Description: "Whether to commit and push the local changes to start the pipeline: true or false.",
+		Commands:    []string{"pipeline config"},
+	})
+)
+
 type PipelineAuthType string
 
 // servicePrincipalLookupKind is the type of lookup to use when resolving the service principal.
@@ -70,6 +110,9 @@ type PipelineManagerArgs struct {
 	PipelineProvider             string
 	PipelineAuthTypeName         string
 	ServiceManagementReference   string
+	// PrintVariables is set when only the variables of the pipeline are printed, see Variables. The provider is then
+	// neither prompted for nor saved to the environment.
+	PrintVariables bool
 }
 
 // CredentialOptions represents the options for configuring credentials for a pipeline.
@@ -288,6 +331,12 @@ func (pm *PipelineManager) Configure(
 		return result, fmt.Errorf("ensuring git remote: %w", err)
 	}
 
+	// stages of a multi-stage pipeline, deploying the environments defined in azure.yaml
+	stages, err := pm.resolveStages(ctx)
+	if err != nil {
+		return result, fmt.Errorf("resolving pipeline environments: %w", err)
+	}
+
 	if pm.args.PipelineServicePrincipalName != "" && pm.args.PipelineServicePrincipalId != "" {
 		//nolint:lll
 		return result, fmt.Errorf(
@@ -330,6 +379,7 @@ func (pm *PipelineManager) Configure(
 			optionSkip,
 		}
 		selectedOption, err := pm.console.Select(ctx, input.ConsoleOptions{
+			Id:           pipelineAuthTypePromptId,
 			Message:      "Select how to authenticate the pipeline to Azure",
 			Options:      options,
 			DefaultValue: optionMsi,
@@ -415,6 +465,7 @@ func (pm *PipelineManager) Configure(
 				optionUseExisting,
 			}
 			selectedOption, err := pm.console.Select(ctx, input.ConsoleOptions{
+				Id:           pipelineMsiPromptId,
 				Message:      "Do you want to create a new User Managed Identity (MSI) or use an existing one?",
 				Options:      options,
 				DefaultValue: optionCreate,
@@ -466,6 +517,7 @@ func (pm *PipelineManager) Configure(
 					msiOptions[i] = fmt.Sprintf("%2d. %s (%s)", i+1, *msi.Name, msiData.ResourceGroupName)
 				}
 				selectedOption, err := pm.console.Select(ctx, input.ConsoleOptions{
+					Id:           pipelineExistingMsiPromptId,
 					Message:      "Select an existing User Managed Identity (MSI) to use:",
 					Options:      msiOptions,
 					DefaultValue: msiOptions[0],
@@ -522,6 +574,13 @@ func (pm *PipelineManager) Configure(
 		}
 	}
 
+	if !skipAuth {
+		// stages can deploy to other subscriptions than the one of the current environment
+		if err := pm.ensureStageRoleAssignments(ctx, subscriptionId, stages, authConfig); err != nil {
+			return result, err
+		}
+	}
+
 	if !skipAuth {
 		repoSlug := gitRepoInfo.owner + "/" + gitRepoInfo.repoName
 		displayMsg := fmt.Sprintf("Configuring repository %s to use credentials for %s", repoSlug, spConfig.applicationName)
@@ -534,6 +593,7 @@ func (pm *PipelineManager) Configure(
 			infra.Options,
 			PipelineAuthType(pm.args.PipelineAuthTypeName),
 			authConfig.AzureCredentials,
+			stages,
 		)
 		if err != nil {
 			return result, fmt.Errorf("failed to get credential options: %w", err)
@@ -728,9 +788,16 @@ func (pm *PipelineManager) Configure(
 		return result, err
 	}
 
+	if len(stages) > 0 {
+		if err := pm.configureStages(ctx, gitRepoInfo, stages); err != nil {
+			return result, err
+		}
+	}
+
 	// The CI pipeline should be set-up and ready at this point.
 	// azd offers to push changes to the scm to start a new pipeline run
 	doPush, err := pm.console.Confirm(ctx, input.ConsoleOptions{
+		Id:           pipelinePushPromptId,
 		Message:      "Would you like to commit and push your local changes to start the configured CI pipeline?",
 		DefaultValue: true,
 	})
@@ -897,6 +964,7 @@ func (pm *PipelineManager) getGitRepoDetails(ctx context.Context) (*gitRepositor
 
 			// Offer the user a chance to init a new repository if one does not exist.
 			initRepo, err := pm.console.Confirm(ctx, input.ConsoleOptions{
+				Id:           pipelineGitInitPromptId,
 				Message:      "Do you want to initialize a new Git repository in this directory?",
 				DefaultValue: true,
 			})
@@ -1052,8 +1120,10 @@ func (pm *PipelineManager) initialize(ctx context.Context, override string) erro
 	pm.prjConfig = prjConfig
 
 	// Save the provider to the environment
-	if err := pm.savePipelineProviderToEnv(ctx, pipelineProvider, pm.env); err != nil {
-		return err
+	if !pm.args.PrintVariables {
+		if err := pm.savePipelineProviderToEnv(ctx, pipelineProvider, pm.env); err != nil {
+			return err
+		}
 	}
 
 	var scmProviderName, ciProviderName, displayName string
@@ -1184,6 +1254,7 @@ func (pm *PipelineManager) promptForCiFiles(ctx context.Context, props projectPr
 
 	// Prompt the user for confirmation
 	confirm, err := pm.console.Confirm(ctx, input.ConsoleOptions{
+		Id:           pipelineAddWorkflowPromptId,
 		Message:      "Would you like to add it now?",
 		DefaultValue: true,
 	})
@@ -1239,6 +1310,9 @@ func (pm *PipelineManager) promptForCiFiles(ctx context.Context, props projectPr
 
 func generatePipelineDefinition(path string, props projectProperties) error {
 	embedFilePath := fmt.Sprintf("pipeline/.%s/azure-dev.ymlt", props.CiProvider)
+	if len(props.Stages) > 0 {
+		embedFilePath = fmt.Sprintf("pipeline/.%s/azure-dev-stages.ymlt", props.CiProvider)
+	}
 	tmpl, err := template.
 		New("azure-dev.yml").
 		Option("missingkey=error").
@@ -1255,6 +1329,8 @@ func generatePipelineDefinition(path string, props projectProperties) error {
 		Secrets                []string
 		AlphaFeatures          []string
 		IsTerraform            bool
+		Stages                 []pipelineStageTemplate
+		Agent                  string
 	}{
 		BranchName:             props.BranchName,
 		FedCredLogIn:           props.AuthType == AuthTypeFederated,
@@ -1263,6 +1339,8 @@ func generatePipelineDefinition(path string, props projectProperties) error {
 		Secrets:                props.Secrets,
 		AlphaFeatures:          props.RequiredAlphaFeatures,
 		IsTerraform:            props.InfraProvider == infraProviderTerraform,
+		Stages:                 props.Stages,
+		Agent:                  props.Agent,
 	}
 
 	// Apply provider parameters
@@ -1286,6 +1364,15 @@ func generatePipelineDefinition(path string, props projectProperties) error {
 		}
 	}
 
+	if len(props.Stages) > 0 {
+		// each stage deploys its own environment, which name and location are set as variables of the stage
+		for _, variable := range []string{"AZURE_ENV_NAME", "AZURE_LOCATION"} {
+			if !slices.Contains(tmplContext.Variables, variable) {
+				tmplContext.Variables = append(tmplContext.Variables, variable)
+			}
+		}
+	}
+
 	err = tmpl.Execute(&builder, tmplContext)
 	if err != nil {
 		return fmt.Errorf("executing template: %w", err)
@@ -1323,6 +1410,11 @@ func (pm *PipelineManager) determineProvider(ctx context.Context, repoRoot strin
 	switch {
 	case (!hasGitHubYml && !hasAzDevOpsYml) || (hasGitHubYml && hasAzDevOpsYml):
 		// No official YAML files found for either provider or both are found
+		if pm.args.PrintVariables {
+			log.Printf("Neither or both YAML files found. Using GitHub Actions to print the variables.")
+			return ciProviderGitHubActions, nil
+		}
+
 		log.Printf("Neither or both YAML files found. Prompting user for provider selection.")
 		return pm.promptForProvider(ctx)
 
@@ -1348,6 +1440,7 @@ func (pm *PipelineManager) promptForProvider(ctx context.Context) (ciProviderTyp
 	log.Printf("Prompting user to select a CI/CD provider.")
 	pm.console.Message(ctx, "")
 	choice, err := pm.console.Select(ctx, input.ConsoleOptions{
+		Id:      pipelineProviderPromptId,
 		Message: "Select a provider:",
 		Options: []string{gitHubDisplayName, azdoDisplayName},
 	})
@@ -1413,6 +1506,10 @@ func (pm *PipelineManager) ensurePipelineDefinition(ctx context.Context) error {
 		return err
 	}
 
+	if err := validatePipelineEnvironments(pm.prjConfig.Pipeline.Environments); err != nil {
+		return err
+	}
+
 	var requiredAlphaFeatures []string
 	if pm.infra.IsCompose {
 		requiredAlphaFeatures = append(requiredAlphaFeatures, "compose")
@@ -1448,6 +1545,8 @@ func (pm *PipelineManager) ensurePipelineDefinition(ctx context.Context) error {
 			Variables:             pm.prjConfig.Pipeline.Variables,
 			Secrets:               pm.prjConfig.Pipeline.Secrets,
 			RequiredAlphaFeatures: requiredAlphaFeatures,
+			Stages:                newPipelineStageTemplates(pm.prjConfig.Pipeline.Environments),
+			Agent:                 pipelineAgentName(pm.prjConfig.Pipeline.Agent),
 			providerParameters:    pm.configOptions.providerParameters,
 		})
 	if err != nil {
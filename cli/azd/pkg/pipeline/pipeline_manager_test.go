@@ -516,6 +516,23 @@ func Test_PipelineManager_Initialize(t *testing.T) {
 
 		deleteYamlFiles(t, tempDir)
 	})
+	t.Run("print variables - no prompt and no persisted selection", func(t *testing.T) {
+
+		mockContext = resetContext(tempDir, ctx)
+
+		// the mock console fails the prompt for the provider
+		env := environment.New("test")
+		args := &PipelineManagerArgs{
+			PrintVariables: true,
+		}
+
+		manager, err := createPipelineManager(mockContext, azdContext, env, args)
+
+		verifyProvider(t, manager, ciProviderGitHubActions, err)
+
+		_, found := env.Dotenv()[envPersistedKey]
+		assert.False(t, found)
+	})
 	t.Run("persist selection on environment and override with yaml", func(t *testing.T) {
 
 		mockContext = resetContext(tempDir, ctx)
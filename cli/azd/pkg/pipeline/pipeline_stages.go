@@ -0,0 +1,284 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// pipelineStage is an azd environment deployed by a stage of a multi-stage pipeline. The name of the azd environment is
+// also the name of the deployment environment created in the CI provider, where approvals are configured.
+type pipelineStage struct {
+	name           string
+	subscriptionId string
+	location       string
+	approvers      []string
+	// variables are the key-value pairs set as variables of the stage in the CI provider
+	variables map[string]string
+	// secrets are the key-value pairs set as secrets of the stage in the CI provider
+	secrets map[string]string
+}
+
+// pipelineStageTemplate is the data of a stage used to generate the definition of a multi-stage pipeline.
+type pipelineStageTemplate struct {
+	// The name of the azd environment deployed by the stage
+	Name string
+	// The identifier of the stage (or job) in the pipeline definition
+	Id string
+	// The identifier of the stage which must succeed before the stage runs, if any
+	DependsOn string
+}
+
+// newPipelineStageTemplates returns the stages of the pipeline definition deploying the given environments, in order.
+func newPipelineStageTemplates(environments []project.PipelineEnvironment) []pipelineStageTemplate {
+	stages := make([]pipelineStageTemplate, 0, len(environments))
+	for i, env := range environments {
+		stage := pipelineStageTemplate{
+			Name: env.Name,
+			Id:   stageId(env.Name),
+		}
+		if i > 0 {
+			stage.DependsOn = stages[i-1].Id
+		}
+
+		stages = append(stages, stage)
+	}
+
+	return stages
+}
+
+// stageId returns an identifier, valid for both GitHub jobs and Azure DevOps stages, for the stage deploying envName.
+func stageId(envName string) string {
+	return "deploy_" + strings.NewReplacer("-", "_", ".", "_").Replace(envName)
+}
+
+// validatePipelineEnvironments validates the environments of a multi-stage pipeline defined in azure.yaml.
+func validatePipelineEnvironments(environments []project.PipelineEnvironment) error {
+	names := []string{}
+	for _, env := range environments {
+		if !environment.IsValidEnvironmentName(env.Name) {
+			return fmt.Errorf(
+				"pipeline environment name '%s' is invalid (it should contain only alphanumeric characters and hyphens)",
+				env.Name)
+		}
+
+		if slices.Contains(names, env.Name) {
+			return fmt.Errorf("the pipeline environment '%s' is defined more than once", env.Name)
+		}
+
+		names = append(names, env.Name)
+	}
+
+	return nil
+}
+
+// resolveStages resolves the stages of the multi-stage pipeline defined in azure.yaml. The subscription, location,
+// variables and secrets of each stage are read from the azd environment with the same name, when it exists. Otherwise,
+// the subscription and location of the current environment are used.
+func (pm *PipelineManager) resolveStages(ctx context.Context) ([]*pipelineStage, error) {
+	environments := pm.prjConfig.Pipeline.Environments
+	if err := validatePipelineEnvironments(environments); err != nil {
+		return nil, err
+	}
+
+	stages := make([]*pipelineStage, 0, len(environments))
+	for _, pipelineEnv := range environments {
+		stageEnv := pm.env
+		if pipelineEnv.Name != pm.env.Name() {
+			env, err := pm.envManager.Get(ctx, pipelineEnv.Name)
+			if errors.Is(err, environment.ErrNotFound) {
+				log.Printf("azd environment '%s' not found, using the values of '%s'", pipelineEnv.Name, pm.env.Name())
+				env = nil
+			} else if err != nil {
+				return nil, fmt.Errorf("loading environment '%s': %w", pipelineEnv.Name, err)
+			}
+
+			stageEnv = env
+		}
+
+		stage := &pipelineStage{
+			name:           pipelineEnv.Name,
+			subscriptionId: pipelineEnv.Subscription,
+			location:       pipelineEnv.Location,
+			approvers:      pipelineEnv.Approvers,
+			variables:      map[string]string{},
+			secrets:        map[string]string{},
+		}
+
+		dotenv := map[string]string{}
+		if stageEnv != nil {
+			dotenv = stageEnv.Dotenv()
+		} else {
+			pm.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf(
+					"The azd environment '%s' doesn't exist, its pipeline variables and secrets aren't set. "+
+						"Run 'azd env new %s' to create it.", pipelineEnv.Name, pipelineEnv.Name),
+			})
+		}
+
+		if stage.subscriptionId == "" {
+			stage.subscriptionId = dotenv[environment.SubscriptionIdEnvVarName]
+		}
+		if stage.subscriptionId == "" {
+			stage.subscriptionId = pm.env.GetSubscriptionId()
+		}
+		if stage.location == "" {
+			stage.location = dotenv[environment.LocationEnvVarName]
+		}
+		if stage.location == "" {
+			stage.location = pm.env.GetLocation()
+		}
+
+		initialVariables := map[string]string{
+			environment.EnvNameEnvVarName:        stage.name,
+			environment.SubscriptionIdEnvVarName: stage.subscriptionId,
+			environment.LocationEnvVarName:       stage.location,
+		}
+		if rgName, has := dotenv[environment.ResourceGroupEnvVarName]; has {
+			initialVariables[environment.ResourceGroupEnvVarName] = rgName
+		}
+
+		// The provider parameters are resolved from the current environment, the values of the stage are read from
+		// its own environment
+		var parameters []provisioning.Parameter
+		if stageEnv == pm.env {
+			parameters = pm.configOptions.providerParameters
+		} else if stageEnv != nil {
+			parameters = stageParameters(pm.configOptions.providerParameters, stageEnv)
+		}
+
+		variables, secrets, err := mergeProjectVariablesAndSecrets(
+			pm.prjConfig.Pipeline.Variables, pm.prjConfig.Pipeline.Secrets,
+			initialVariables, map[string]string{}, parameters, dotenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving variables and secrets of environment '%s': %w", stage.name, err)
+		}
+
+		stage.variables = variables
+		stage.secrets = secrets
+		stages = append(stages, stage)
+	}
+
+	return stages, nil
+}
+
+// configInfraParametersKey is the environment config path of the values of the parameters prompted for
+const configInfraParametersKey = "infra.parameters."
+
+// stageParameters returns the provider parameters with the values of the environment of a stage. The parameters mapped
+// to an environment variable take the value of the variable in the stage environment, the ones prompted for take the
+// value prompted for in the stage environment. The parameters without a value in the stage environment are left out,
+// rather than using the value of the current environment.
+func stageParameters(
+	parameters []provisioning.Parameter,
+	stageEnv *environment.Environment,
+) []provisioning.Parameter {
+	dotenv := stageEnv.Dotenv()
+	result := make([]provisioning.Parameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		// The parameters mapped to several environment variables are read from the dotenv of the stage when merged
+		if len(parameter.EnvVarMapping) != 1 {
+			result = append(result, parameter)
+			continue
+		}
+
+		stageParameter := parameter
+		if value := dotenv[parameter.EnvVarMapping[0]]; value != "" {
+			stageParameter.Value = value
+			stageParameter.UsingEnvVarMapping = true
+			stageParameter.LocalPrompt = false
+		} else if value, has := stageEnv.Config.Get(configInfraParametersKey + parameter.Name); has {
+			stageParameter.Value = value
+			stageParameter.UsingEnvVarMapping = false
+			stageParameter.LocalPrompt = true
+		} else {
+			log.Printf("parameter '%s' has no value in environment '%s'", parameter.Name, stageEnv.Name())
+			continue
+		}
+
+		result = append(result, stageParameter)
+	}
+
+	return result
+}
+
+// ensureStageRoleAssignments assigns the pipeline roles to the identity of the pipeline on the subscription of each
+// stage. The roles on subscriptionId, the subscription of the current environment, are already assigned.
+func (pm *PipelineManager) ensureStageRoleAssignments(
+	ctx context.Context,
+	subscriptionId string,
+	stages []*pipelineStage,
+	authConfig *authConfiguration,
+) error {
+	principal := authConfig.sp
+	if authConfig.msi != nil {
+		// Adapting the MSI to work with the same method as a regular Service Principal, like for the main subscription.
+		principal = &graphsdk.ServicePrincipal{
+			Id:          authConfig.msi.Properties.PrincipalID,
+			DisplayName: *authConfig.msi.Name,
+		}
+	}
+
+	assigned := []string{subscriptionId}
+	for _, stage := range stages {
+		if slices.Contains(assigned, stage.subscriptionId) {
+			continue
+		}
+
+		displayMsg := fmt.Sprintf("Assigning roles for environment %s on subscription %s", stage.name, stage.subscriptionId)
+		pm.console.ShowSpinner(ctx, displayMsg, input.Step)
+		err := pm.entraIdService.EnsureRoleAssignments(ctx, stage.subscriptionId, pm.args.PipelineRoleNames, principal)
+		pm.console.StopSpinner(ctx, displayMsg, input.GetStepResultFormat(err))
+		if err != nil {
+			return fmt.Errorf("failed to assign roles for environment %s: %w", stage.name, err)
+		}
+
+		assigned = append(assigned, stage.subscriptionId)
+	}
+
+	return nil
+}
+
+// configureStages configures the deployment environment of each stage in the CI provider. Secrets referencing Azure Key
+// Vault secrets (akvs://) are resolved, like the secrets of a single-stage pipeline.
+func (pm *PipelineManager) configureStages(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	stages []*pipelineStage,
+) error {
+	for _, stage := range stages {
+		for key, value := range stage.secrets {
+			if !strings.HasPrefix(value, "akvs://") {
+				continue
+			}
+			kvSecret, err := pm.keyVaultService.SecretFromAkvs(ctx, value)
+			if err != nil {
+				return fmt.Errorf("failed to resolve akvs '%s' of environment '%s': %w", key, stage.name, err)
+			}
+			stage.secrets[key] = kvSecret
+		}
+	}
+
+	displayMsg := fmt.Sprintf("Configuring %s environments", pm.ciProvider.Name())
+	pm.console.ShowSpinner(ctx, displayMsg, input.Step)
+	err := pm.ciProvider.configureEnvironments(ctx, repoDetails, stages)
+	pm.console.StopSpinner(ctx, displayMsg, input.GetStepResultFormat(err))
+	if err != nil {
+		return fmt.Errorf("configuring pipeline environments: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testPipelineEnvironments = []project.PipelineEnvironment{
+	{Name: "dev"},
+	{Name: "staging", Approvers: []string{"alice"}},
+	{Name: "prod", Subscription: "PROD_SUBSCRIPTION_ID", Approvers: []string{"contoso/release-managers"}},
+}
+
+func Test_generatePipelineDefinition_Stages(t *testing.T) {
+	for _, provider := range []ciProviderType{ciProviderGitHubActions, ciProviderAzureDevOps} {
+		t.Run(string(provider), func(t *testing.T) {
+			tempDir := t.TempDir()
+			path := filepath.Join(tempDir, pipelineProviderFiles[provider].PipelineDirectories[0])
+			err := os.MkdirAll(path, osutil.PermissionDirectory)
+			require.NoError(t, err)
+			expectedPath := filepath.Join(tempDir, pipelineProviderFiles[provider].Files[0])
+			err = generatePipelineDefinition(expectedPath, projectProperties{
+				CiProvider:    provider,
+				InfraProvider: infraProviderBicep,
+				RepoRoot:      tempDir,
+				BranchName:    "main",
+				AuthType:      AuthTypeFederated,
+				Variables:     []string{"VAR_1"},
+				Secrets:       []string{"SECRET_1"},
+				Stages:        newPipelineStageTemplates(testPipelineEnvironments),
+			})
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(expectedPath)
+			require.NoError(t, err)
+			snapshot.SnapshotT(t, normalizeEOL(content))
+		})
+	}
+}
+
+func Test_newPipelineStageTemplates(t *testing.T) {
+	stages := newPipelineStageTemplates([]project.PipelineEnvironment{{Name: "dev"}, {Name: "prod-eu"}})
+	require.Equal(t, []pipelineStageTemplate{
+		{Name: "dev", Id: "deploy_dev"},
+		{Name: "prod-eu", Id: "deploy_prod_eu", DependsOn: "deploy_dev"},
+	}, stages)
+}
+
+func Test_validatePipelineEnvironments(t *testing.T) {
+	require.NoError(t, validatePipelineEnvironments(testPipelineEnvironments))
+
+	err := validatePipelineEnvironments([]project.PipelineEnvironment{{Name: "dev"}, {Name: "dev"}})
+	require.ErrorContains(t, err, "defined more than once")
+
+	err = validatePipelineEnvironments([]project.PipelineEnvironment{{Name: "dev env"}})
+	require.ErrorContains(t, err, "is invalid")
+}
+
+func Test_resolveStages(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.SubscriptionIdEnvVarName: "DEV_SUBSCRIPTION_ID",
+		environment.LocationEnvVarName:       "eastus2",
+		"VAR_1":                              "dev-value",
+	})
+	stagingEnv := environment.NewWithValues("staging", map[string]string{
+		environment.SubscriptionIdEnvVarName: "STAGING_SUBSCRIPTION_ID",
+		environment.LocationEnvVarName:       "westus3",
+		environment.ResourceGroupEnvVarName:  "rg-staging",
+		"VAR_1":                              "staging-value",
+		"SECRET_1":                           "staging-secret",
+		"DB_PASSWORD":                        "staging-password",
+	})
+	require.NoError(t, stagingEnv.Config.Set("infra.parameters.adminName", "staging-admin"))
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Get", mock.Anything, "staging").Return(stagingEnv, nil)
+	envManager.On("Get", mock.Anything, "prod").Return((*environment.Environment)(nil), environment.ErrNotFound)
+
+	pm := &PipelineManager{
+		env:        env,
+		envManager: envManager,
+		console:    mockContext.Console,
+		configOptions: &configurePipelineOptions{
+			// The provider parameters are resolved from the dev environment
+			providerParameters: []provisioning.Parameter{
+				{
+					Name:               "dbPassword",
+					Secret:             true,
+					Value:              "dev-password",
+					EnvVarMapping:      []string{"DB_PASSWORD"},
+					UsingEnvVarMapping: true,
+				},
+				{
+					Name:          "adminName",
+					Value:         "dev-admin",
+					EnvVarMapping: []string{"ADMIN_NAME"},
+					LocalPrompt:   true,
+				},
+			},
+		},
+		prjConfig: &project.ProjectConfig{
+			Pipeline: project.PipelineOptions{
+				Variables:    []string{"VAR_1"},
+				Secrets:      []string{"SECRET_1"},
+				Environments: testPipelineEnvironments,
+			},
+		},
+	}
+
+	stages, err := pm.resolveStages(*mockContext.Context)
+	require.NoError(t, err)
+	require.Len(t, stages, 3)
+
+	require.Equal(t, "DEV_SUBSCRIPTION_ID", stages[0].subscriptionId)
+	require.Equal(t, "dev-value", stages[0].variables["VAR_1"])
+	require.Equal(t, "dev-admin", stages[0].variables["ADMIN_NAME"])
+	require.Equal(t, "dev-password", stages[0].secrets["DB_PASSWORD"])
+
+	require.Equal(t, "STAGING_SUBSCRIPTION_ID", stages[1].subscriptionId)
+	require.Equal(t, "westus3", stages[1].location)
+	require.Equal(t, []string{"alice"}, stages[1].approvers)
+	require.Equal(t, map[string]string{
+		environment.EnvNameEnvVarName:        "staging",
+		environment.SubscriptionIdEnvVarName: "STAGING_SUBSCRIPTION_ID",
+		environment.LocationEnvVarName:       "westus3",
+		environment.ResourceGroupEnvVarName:  "rg-staging",
+		"VAR_1":                              "staging-value",
+		"ADMIN_NAME":                         "staging-admin",
+	}, stages[1].variables)
+	require.Equal(t, map[string]string{
+		"SECRET_1":    "staging-secret",
+		"DB_PASSWORD": "staging-password",
+	}, stages[1].secrets)
+
+	// prod doesn't have an azd environment, the subscription comes from azure.yaml and the location from dev
+	require.Equal(t, "PROD_SUBSCRIPTION_ID", stages[2].subscriptionId)
+	require.Equal(t, "eastus2", stages[2].location)
+	require.Empty(t, stages[2].secrets)
+	require.NotContains(t, stages[2].variables, "ADMIN_NAME")
+}
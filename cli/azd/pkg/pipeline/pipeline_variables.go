@@ -0,0 +1,195 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// PipelineVariables are the variables and secrets 'azd pipeline config' sets for the pipeline of the project, so they can
+// be configured manually, ex) when the pipeline is wired by a platform team.
+type PipelineVariables struct {
+	// The name of the CI provider, ex) GitHub
+	Provider string
+	// The values of the variables, by name. The values which aren't known yet, ex) the client id of a pipeline identity
+	// which hasn't been created, are empty.
+	Variables map[string]string
+	// The names of the secrets, which values are read from the azd environment or Azure Key Vault
+	Secrets []string
+}
+
+// terraformRemoteStateKeys are the environment variables configuring the remote state of terraform
+var terraformRemoteStateKeys = []string{"RS_RESOURCE_GROUP", "RS_STORAGE_ACCOUNT", "RS_CONTAINER_NAME"}
+
+// Variables computes the variables and secrets of the pipeline of the project, without configuring the repository, the
+// pipeline or its identity.
+func (pm *PipelineManager) Variables(ctx context.Context, infra *project.Infra) (*PipelineVariables, error) {
+	pm.infra = infra
+	if pm.configOptions == nil {
+		pm.configOptions = &configurePipelineOptions{}
+	}
+
+	credentials, err := pm.pipelineCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The dev center configuration is only registered when the devcenter platform is enabled
+	var devCenterConfig *devcenter.Config
+	if err := pm.serviceLocator.Resolve(&devCenterConfig); err != nil && !errors.Is(err, ioc.ErrResolveInstance) {
+		return nil, fmt.Errorf("resolving dev center configuration: %w", err)
+	}
+
+	defaultAzdVariables := map[string]string{}
+	if rgGroup, exists := pm.env.LookupEnv(environment.ResourceGroupEnvVarName); exists {
+		defaultAzdVariables[environment.ResourceGroupEnvVarName] = rgGroup
+	}
+
+	variables, secrets, err := mergeProjectVariablesAndSecrets(
+		pm.prjConfig.Pipeline.Variables, pm.prjConfig.Pipeline.Secrets,
+		defaultAzdVariables, map[string]string{}, pm.configOptions.providerParameters, pm.env.Dotenv())
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge variables and secrets: %w", err)
+	}
+
+	result := pipelineVariables(
+		pm.ciProviderType,
+		pm.env,
+		infra.Options,
+		PipelineAuthType(pm.args.PipelineAuthTypeName),
+		credentials,
+		devCenterConfig,
+	)
+
+	maps.Copy(result.Variables, variables)
+	for name := range secrets {
+		if !slices.Contains(result.Secrets, name) {
+			result.Secrets = append(result.Secrets, name)
+		}
+	}
+	slices.Sort(result.Secrets)
+
+	return result, nil
+}
+
+// pipelineCredentials returns the client and tenant ids of the identity of the pipeline, when it has been configured by a
+// previous 'azd pipeline config' or is set with '--principal-id' or '--principal-name'.
+func (pm *PipelineManager) pipelineCredentials(ctx context.Context) (*entraid.AzureCredentials, error) {
+	credentials := &entraid.AzureCredentials{
+		SubscriptionId: pm.env.GetSubscriptionId(),
+		TenantId:       pm.env.Getenv(environment.TenantIdEnvVarName),
+	}
+
+	// The MSI takes precedence over the service principal, like when the pipeline is configured
+	if msiResourceId := pm.env.Getenv(AzurePipelineMsiResourceId); msiResourceId != "" {
+		identity, err := pm.msiService.GetUserIdentity(ctx, msiResourceId)
+		if err != nil {
+			return nil, fmt.Errorf("getting User Managed Identity (MSI) '%s': %w", msiResourceId, err)
+		}
+
+		credentials.ClientId = *identity.Properties.ClientID
+		credentials.TenantId = *identity.Properties.TenantID
+		return credentials, nil
+	}
+
+	if pm.args.PipelineServicePrincipalId == "" && pm.args.PipelineServicePrincipalName == "" &&
+		pm.env.Getenv(AzurePipelineClientIdEnvVarName) == "" {
+		return credentials, nil
+	}
+
+	spConfig, err := servicePrincipal(
+		ctx,
+		pm.env.Getenv(AzurePipelineClientIdEnvVarName),
+		credentials.SubscriptionId,
+		pm.args,
+		pm.entraIdService,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if spConfig.servicePrincipal != nil {
+		credentials.ClientId = spConfig.servicePrincipal.AppId
+		if spConfig.servicePrincipal.AppOwnerOrganizationId != nil {
+			credentials.TenantId = *spConfig.servicePrincipal.AppOwnerOrganizationId
+		}
+	}
+
+	return credentials, nil
+}
+
+// pipelineVariables returns the variables and secrets set by the CI provider for the connection to Azure, and the dev center
+// settings of the project when it's deployed to Azure Deployment Environments.
+func pipelineVariables(
+	providerType ciProviderType,
+	env *environment.Environment,
+	infraOptions provisioning.Options,
+	authType PipelineAuthType,
+	credentials *entraid.AzureCredentials,
+	devCenterConfig *devcenter.Config,
+) *PipelineVariables {
+	result := &PipelineVariables{
+		Provider: pipelineProviderFiles[providerType].DisplayName,
+		Variables: map[string]string{
+			environment.EnvNameEnvVarName:        env.Name(),
+			environment.LocationEnvVarName:       env.GetLocation(),
+			environment.SubscriptionIdEnvVarName: credentials.SubscriptionId,
+		},
+		Secrets: []string{},
+	}
+
+	if providerType == ciProviderAzureDevOps {
+		// The pipeline logs in with the service connection, which holds the credentials
+		result.Variables["AZURE_SERVICE_CONNECTION"] = azdo.ServiceConnectionName
+		if infraOptions.Provider == provisioning.Terraform {
+			result.Variables["ARM_TENANT_ID"] = credentials.TenantId
+			result.Secrets = append(result.Secrets, "ARM_CLIENT_ID", "ARM_CLIENT_SECRET")
+		}
+	} else {
+		result.Variables[environment.TenantIdEnvVarName] = credentials.TenantId
+		result.Variables["AZURE_CLIENT_ID"] = credentials.ClientId
+		if authType == AuthTypeClientCredentials {
+			result.Secrets = append(result.Secrets, "AZURE_CREDENTIALS")
+			if infraOptions.Provider == provisioning.Terraform {
+				result.Variables["ARM_TENANT_ID"] = credentials.TenantId
+				result.Variables["ARM_CLIENT_ID"] = credentials.ClientId
+				result.Secrets = append(result.Secrets, "ARM_CLIENT_SECRET")
+			}
+		}
+	}
+
+	if infraOptions.Provider == provisioning.Terraform {
+		for _, key := range terraformRemoteStateKeys {
+			result.Variables[key] = env.Getenv(key)
+		}
+	}
+
+	if devCenterConfig != nil {
+		for name, value := range map[string]string{
+			devcenter.DevCenterNameEnvName:          devCenterConfig.Name,
+			devcenter.DevCenterProjectEnvName:       devCenterConfig.Project,
+			devcenter.DevCenterCatalogEnvName:       devCenterConfig.Catalog,
+			devcenter.DevCenterEnvTypeEnvName:       devCenterConfig.EnvironmentType,
+			devcenter.DevCenterEnvDefinitionEnvName: devCenterConfig.EnvironmentDefinition,
+		} {
+			if value != "" {
+				result.Variables[name] = value
+			}
+		}
+	}
+
+	return result
+}
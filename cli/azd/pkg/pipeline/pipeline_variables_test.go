@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pipelineVariables(t *testing.T) {
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.LocationEnvVarName:       "eastus2",
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"RS_RESOURCE_GROUP":                  "rg-state",
+		"RS_STORAGE_ACCOUNT":                 "ststate",
+	})
+	credentials := &entraid.AzureCredentials{
+		ClientId:       "CLIENT_ID",
+		TenantId:       "TENANT_ID",
+		SubscriptionId: "SUBSCRIPTION_ID",
+	}
+
+	t.Run("GitHubFederated", func(t *testing.T) {
+		variables := pipelineVariables(
+			ciProviderGitHubActions,
+			env,
+			provisioning.Options{Provider: provisioning.Bicep},
+			AuthTypeFederated,
+			credentials,
+			nil,
+		)
+
+		require.Equal(t, "GitHub", variables.Provider)
+		require.Equal(t, map[string]string{
+			"AZURE_ENV_NAME":        "dev",
+			"AZURE_LOCATION":        "eastus2",
+			"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+			"AZURE_TENANT_ID":       "TENANT_ID",
+			"AZURE_CLIENT_ID":       "CLIENT_ID",
+		}, variables.Variables)
+		require.Empty(t, variables.Secrets)
+	})
+
+	t.Run("GitHubClientCredentialsTerraform", func(t *testing.T) {
+		variables := pipelineVariables(
+			ciProviderGitHubActions,
+			env,
+			provisioning.Options{Provider: provisioning.Terraform},
+			AuthTypeClientCredentials,
+			credentials,
+			nil,
+		)
+
+		require.Equal(t, "CLIENT_ID", variables.Variables["ARM_CLIENT_ID"])
+		require.Equal(t, "TENANT_ID", variables.Variables["ARM_TENANT_ID"])
+		require.Equal(t, "rg-state", variables.Variables["RS_RESOURCE_GROUP"])
+		// The remote state which isn't configured yet is listed without value
+		require.Contains(t, variables.Variables, "RS_CONTAINER_NAME")
+		require.Empty(t, variables.Variables["RS_CONTAINER_NAME"])
+		require.Equal(t, []string{"AZURE_CREDENTIALS", "ARM_CLIENT_SECRET"}, variables.Secrets)
+	})
+
+	t.Run("AzureDevOps", func(t *testing.T) {
+		variables := pipelineVariables(
+			ciProviderAzureDevOps,
+			env,
+			provisioning.Options{Provider: provisioning.Terraform},
+			AuthTypeFederated,
+			credentials,
+			nil,
+		)
+
+		require.Equal(t, "Azure DevOps", variables.Provider)
+		require.Equal(t, "azconnection", variables.Variables["AZURE_SERVICE_CONNECTION"])
+		require.NotContains(t, variables.Variables, "AZURE_CLIENT_ID")
+		require.Equal(t, []string{"ARM_CLIENT_ID", "ARM_CLIENT_SECRET"}, variables.Secrets)
+	})
+
+	t.Run("DevCenter", func(t *testing.T) {
+		variables := pipelineVariables(
+			ciProviderGitHubActions,
+			env,
+			provisioning.Options{Provider: "devcenter"},
+			AuthTypeFederated,
+			credentials,
+			&devcenter.Config{
+				Name:                  "DEV_CENTER",
+				Project:               "PROJECT",
+				EnvironmentType:       "Dev",
+				EnvironmentDefinition: "WebApp",
+			},
+		)
+
+		require.Equal(t, "DEV_CENTER", variables.Variables["AZURE_DEVCENTER_NAME"])
+		require.Equal(t, "PROJECT", variables.Variables["AZURE_DEVCENTER_PROJECT"])
+		require.Equal(t, "Dev", variables.Variables["AZURE_DEVCENTER_ENVIRONMENT_TYPE"])
+		require.Equal(t, "WebApp", variables.Variables["AZURE_DEVCENTER_ENVIRONMENT_DEFINITION"])
+		// The catalog isn't set
+		require.NotContains(t, variables.Variables, "AZURE_DEVCENTER_CATALOG")
+	})
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/journal"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
@@ -219,18 +221,133 @@ func (ch *ContainerHelper) Login(
 		return "", err
 	}
 
+	return registryName, ch.loginTo(ctx, registryName)
+}
+
+// loginTo logs into the specified server when it is an Azure Container Registry.
+func (ch *ContainerHelper) loginTo(ctx context.Context, loginServer string) error {
 	// Only perform automatic login for ACR
 	// Other registries require manual login via external 'docker login' command
-	hostParts := strings.Split(registryName, ".")
-	if len(hostParts) == 1 || strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix) {
-		return registryName, ch.containerRegistryService.Login(ctx, ch.env.GetSubscriptionId(), registryName)
+	if ch.isAzureContainerRegistry(loginServer) {
+		return ch.containerRegistryService.Login(ctx, ch.env.GetSubscriptionId(), loginServer)
 	}
 
-	return registryName, nil
+	return nil
+}
+
+// isAzureContainerRegistry returns true when the server is an Azure Container Registry login server.
+func (ch *ContainerHelper) isAzureContainerRegistry(server string) bool {
+	hostParts := strings.Split(server, ".")
+	return len(hostParts) == 1 || strings.HasSuffix(server, ch.cloud.ContainerRegistryEndpointSuffix)
+}
+
+// loginServer returns the server images are pushed to, which is 'docker.loginServer' when configured or the registry
+// otherwise.
+func (ch *ContainerHelper) loginServer(serviceConfig *ServiceConfig, registryName string) (string, error) {
+	loginServer, err := serviceConfig.Docker.LoginServer.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing 'loginServer' from docker configuration, %w", err)
+	}
+
+	if loginServer == "" {
+		return registryName, nil
+	}
+
+	return loginServer, nil
+}
+
+// registryNetworkError describes a failure reaching an Azure Container Registry, inspecting the network configuration of
+// the registry to provide actionable suggestions. Registries with public network access disabled otherwise fail with
+// opaque TLS or authorization errors.
+func (ch *ContainerHelper) registryNetworkError(
+	ctx context.Context,
+	loginServer string,
+	loginServerResult *containerregistry.ConnectivityResult,
+	err error,
+) error {
+	registry, findErr := ch.containerRegistryService.FindContainerRegistry(ctx, ch.env.GetSubscriptionId(), loginServer)
+	if findErr != nil || registry.Properties == nil {
+		log.Printf("failed inspecting container registry '%s' network configuration: %v", loginServer, findErr)
+		if loginServerResult == nil {
+			return err
+		}
+
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("container registry '%s' is not reachable: %w", loginServer, err),
+			Suggestion: "Ensure this machine can resolve and reach the container registry on port 443, " +
+				"or set 'docker.loginServer' to a reachable server for the registry.",
+		}
+	}
+
+	if loginServerResult == nil {
+		loginServerResult = checkRegistryConnectivity(ctx, loginServer)
+	}
+
+	results := []*containerregistry.ConnectivityResult{loginServerResult}
+	if registry.Properties.DataEndpointEnabled != nil && *registry.Properties.DataEndpointEnabled {
+		for _, host := range registry.Properties.DataEndpointHostNames {
+			if host != nil {
+				results = append(results, checkRegistryConnectivity(ctx, *host))
+			}
+		}
+	}
+
+	publicAccessDisabled := registry.Properties.PublicNetworkAccess != nil &&
+		*registry.Properties.PublicNetworkAccess == armcontainerregistry.PublicNetworkAccessDisabled
+
+	suggestions := []string{}
+	for _, result := range results {
+		log.Printf("container registry connectivity: %s", result)
+
+		if !result.Ok() {
+			suggestions = append(suggestions, fmt.Sprintf("Unable to reach %s.", result))
+		}
+
+		if publicAccessDisabled && result.HasPublicAddress() {
+			suggestions = append(suggestions, fmt.Sprintf(
+				"%s resolves to a public address (%s). Link the 'privatelink.%s' private DNS zone to the network "+
+					"of this machine so the registry resolves to its private endpoint.",
+				result.Host,
+				strings.Join(result.Addresses, ", "),
+				ch.cloud.ContainerRegistryEndpointSuffix,
+			))
+		}
+	}
+
+	if publicAccessDisabled {
+		suggestions = append(suggestions,
+			fmt.Sprintf("Container registry '%s' has public network access disabled. ", loginServer)+
+				"Run 'azd deploy' from a machine or pipeline agent with access to the virtual network of the "+
+				"registry private endpoint, or set 'docker.loginServer' to a server routed to it.")
+	}
+
+	if len(suggestions) == 0 {
+		return err
+	}
+
+	return &internal.ErrorWithSuggestion{
+		Err:        fmt.Errorf("failed pushing to container registry '%s': %w", loginServer, err),
+		Suggestion: strings.Join(suggestions, "\n"),
+	}
 }
 
 var defaultCredentialsRetryDelay = 20 * time.Second
 
+// Replaceable for testing
+var checkRegistryConnectivity = containerregistry.CheckConnectivity
+
+// usesProxy returns true when docker may reach registries through an HTTPS proxy, in which case the registry is not
+// expected to be resolvable or reachable directly.
+func usesProxy() bool {
+	for _, name := range []string{"HTTPS_PROXY", "https_proxy"} {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ch *ContainerHelper) Credentials(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -277,10 +394,19 @@ func (ch *ContainerHelper) Deploy(
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceDeployResult, error) {
 	var remoteImage string
+	var imageDigest string
 	var err error
 
-	if serviceConfig.Docker.RemoteBuild {
+	imagePackage, isImagePackage := packageOutput.Details.(*ImagePackageResult)
+	if isImagePackage {
+		// The image has already been pushed, ex) to a staging registry, and is deployed as is
+		log.Printf("deploying image %s without building it", imagePackage.Image)
+		remoteImage = imagePackage.Image
+		imageDigest = imagePackage.Digest()
+	} else if serviceConfig.Docker.RemoteBuild {
 		remoteImage, err = ch.runRemoteBuild(ctx, serviceConfig, targetResource, progress)
+	} else if isMultiPlatformBuild(serviceConfig) {
+		remoteImage, imageDigest, err = ch.runMultiPlatformBuild(ctx, serviceConfig, progress)
 	} else if useDotnetPublishForDockerBuild(serviceConfig) {
 		remoteImage, err = ch.runDotnetPublish(ctx, serviceConfig, targetResource, progress)
 	} else {
@@ -294,6 +420,11 @@ func (ch *ContainerHelper) Deploy(
 		// Save the name of the image we pushed into the environment with a well known key.
 		log.Printf("writing image name to environment")
 		ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteImage)
+		if imageDigest != "" || isImagePackage {
+			// The digest of the manifest list references the image of every platform. A deployed image replaces the
+			// digest of the image deployed before it, even when it's referenced by tag.
+			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST", imageDigest)
+		}
 
 		if err := ch.envManager.Save(ctx, ch.env); err != nil {
 			return nil, fmt.Errorf("saving image name to environment: %w", err)
@@ -364,37 +495,261 @@ func (ch *ContainerHelper) runLocalBuild(
 
 			remoteImage = remoteImageWithTag
 
-			progress.SetProgress(NewServiceProgress("Tagging container image"))
-			if err := ch.docker.Tag(ctx, serviceConfig.Path(), targetImage, remoteImage); err != nil {
+			// The image is pushed through the login server override when configured, while the deployed image
+			// keeps referencing the registry.
+			loginServer, err := ch.loginServer(serviceConfig, registryName)
+			if err != nil {
 				return "", err
 			}
 
-			log.Printf("logging into container registry '%s'\n", registryName)
-			progress.SetProgress(NewServiceProgress("Logging into container registry"))
-
-			_, err = ch.Login(ctx, serviceConfig)
+			pushImage, err := pushImageName(remoteImage, registryName, loginServer)
 			if err != nil {
 				return "", err
 			}
 
+			progress.SetProgress(NewServiceProgress("Tagging container image"))
+			if err := ch.docker.Tag(ctx, serviceConfig.Path(), targetImage, pushImage); err != nil {
+				return "", err
+			}
+
+			// Check the registry can be reached before attempting to push to get actionable diagnostics instead of
+			// the TLS or authorization errors reported by docker.
+			if strings.HasSuffix(loginServer, ch.cloud.ContainerRegistryEndpointSuffix) {
+				progress.SetProgress(NewServiceProgress("Checking container registry connectivity"))
+				if result := checkRegistryConnectivity(ctx, loginServer); !result.Ok() && !usesProxy() {
+					return "", ch.registryNetworkError(ctx, loginServer, result, errors.New(result.String()))
+				}
+			}
+
 			// Push image.
-			log.Printf("pushing %s to registry", remoteImage)
-			progress.SetProgress(NewServiceProgress("Pushing container image"))
-			if err := ch.docker.Push(ctx, serviceConfig.Path(), remoteImage); err != nil {
-				errSuggestion := &internal.ErrorWithSuggestion{
-					Err: err,
-					//nolint:lll
-					Suggestion: "When pushing to an external registry, ensure you have successfully authenticated by calling 'docker login' and run 'azd deploy' again",
+			if ch.isAzureContainerRegistry(loginServer) {
+				if err := ch.pushToAzureContainerRegistry(
+					ctx, serviceConfig, loginServer, pushImage, progress); err != nil {
+					return "", ch.registryNetworkError(ctx, loginServer, nil, err)
 				}
+			} else {
+				log.Printf("pushing %s to registry", pushImage)
+				progress.SetProgress(NewServiceProgress("Pushing container image"))
+				if err := ch.docker.Push(ctx, serviceConfig.Path(), pushImage); err != nil {
+					errSuggestion := &internal.ErrorWithSuggestion{
+						Err: err,
+						//nolint:lll
+						Suggestion: "When pushing to an external registry, ensure you have successfully authenticated by calling 'docker login' and run 'azd deploy' again",
+					}
 
-				return "", errSuggestion
+					return "", errSuggestion
+				}
 			}
+
+			ch.recordPushedImage(ctx, pushImage)
 		}
 	}
 
 	return remoteImage, nil
 }
 
+// pushToAzureContainerRegistry pushes the image with a token exchanged from the azd credential, or the admin user
+// credentials of the registry. The credentials are passed to docker through an ephemeral client configuration, so that
+// they're neither stored in ~/.docker/config.json nor dependent on the 'az acr login' credential helpers.
+func (ch *ContainerHelper) pushToAzureContainerRegistry(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	loginServer string,
+	pushImage string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	progress.SetProgress(NewServiceProgress("Logging into container registry"))
+	dockerConfig, err := ch.ephemeralDockerConfig(ctx, loginServer)
+	if err != nil {
+		return err
+	}
+	defer closeDockerConfig(dockerConfig)
+
+	log.Printf("pushing %s to registry", pushImage)
+	progress.SetProgress(NewServiceProgress("Pushing container image"))
+	return ch.docker.PushWithConfig(ctx, serviceConfig.Path(), pushImage, dockerConfig.Dir)
+}
+
+// ephemeralDockerConfig creates a docker client configuration authenticating with the Azure Container Registry, with a
+// token exchanged from the azd credential or the admin user credentials of the registry.
+func (ch *ContainerHelper) ephemeralDockerConfig(
+	ctx context.Context,
+	loginServer string,
+) (*containerregistry.EphemeralDockerConfig, error) {
+	log.Printf("getting credentials for container registry '%s'\n", loginServer)
+	credentials, err := ch.containerRegistryService.Credentials(ctx, ch.env.GetSubscriptionId(), loginServer)
+	if err != nil {
+		return nil, err
+	}
+
+	return containerregistry.NewEphemeralDockerConfig(loginServer, credentials.Username, credentials.Password)
+}
+
+// closeDockerConfig removes an ephemeral docker client configuration, logging the failures
+func closeDockerConfig(dockerConfig *containerregistry.EphemeralDockerConfig) {
+	if err := dockerConfig.Close(); err != nil {
+		log.Printf("failed removing docker config '%s': %v", dockerConfig.Dir, err)
+	}
+}
+
+// runMultiPlatformBuild builds the image for each of the platforms of the service with buildx and pushes the manifest
+// list to the remote registry. It returns the full remote image name and the digest of the manifest list.
+func (ch *ContainerHelper) runMultiPlatformBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) (string, string, error) {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+
+	registryName, err := ch.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	if registryName == "" {
+		return "", "", fmt.Errorf(
+			"multi-platform images are pushed as they are built, set the '%s' environment variable or 'docker.registry'",
+			environment.ContainerRegistryEndpointEnvVarName,
+		)
+	}
+
+	dockerfilePath := dockerOptions.Path
+	if !filepath.IsAbs(dockerfilePath) {
+		dockerfilePath = filepath.Join(serviceConfig.Path(), dockerfilePath)
+	}
+
+	if _, err := os.Stat(dockerfilePath); err != nil {
+		return "", "", fmt.Errorf("multi-platform images are built from a Dockerfile: %w", err)
+	}
+
+	localImageTag, err := ch.LocalImageTag(ctx, serviceConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	remoteImage, err := ch.RemoteImageTag(ctx, serviceConfig, localImageTag)
+	if err != nil {
+		return "", "", fmt.Errorf("getting remote image tag: %w", err)
+	}
+
+	loginServer, err := ch.loginServer(serviceConfig, registryName)
+	if err != nil {
+		return "", "", err
+	}
+
+	pushImage, err := pushImageName(remoteImage, registryName, loginServer)
+	if err != nil {
+		return "", "", err
+	}
+
+	// buildx pushes to Azure Container Registry with ephemeral credentials, as single platform images are, while the
+	// other registries rely on an external 'docker login'
+	var dockerConfig *containerregistry.EphemeralDockerConfig
+	if ch.isAzureContainerRegistry(loginServer) {
+		progress.SetProgress(NewServiceProgress("Logging into container registry"))
+		dockerConfig, err = ch.ephemeralDockerConfig(ctx, loginServer)
+		if err != nil {
+			return "", "", ch.registryNetworkError(ctx, loginServer, nil, err)
+		}
+		defer closeDockerConfig(dockerConfig)
+	}
+
+	buildArgs := []string{}
+	for _, arg := range dockerOptions.BuildArgs {
+		buildArgValue, err := arg.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return "", "", fmt.Errorf("substituting environment variables in build args: %w", err)
+		}
+
+		buildArgs = append(buildArgs, buildArgValue)
+	}
+
+	dockerEnv := []string{}
+	dockerEnv = append(dockerEnv, os.Environ()...)
+	dockerEnv = append(dockerEnv, ch.env.Environ()...)
+	dockerEnv = append(dockerEnv, dockerOptions.BuildEnv...)
+	if dockerConfig != nil {
+		dockerEnv = append(dockerEnv, dockerConfig.Env())
+	}
+
+	buildContext, dockerfilePath, cleanup, err := packageBuildContext(ctx, serviceConfig, dockerOptions)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+
+	log.Printf("building %s for platforms %v", pushImage, dockerOptions.Platforms)
+	progress.SetProgress(NewServiceProgress("Building and pushing multi-platform container image"))
+	previewerWriter := ch.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        "Docker Output",
+		})
+	digest, err := ch.docker.BuildMultiPlatform(
+		ctx,
+		serviceConfig.Path(),
+		dockerfilePath,
+		dockerOptions.Platforms,
+		dockerOptions.Target,
+		buildContext,
+		pushImage,
+		buildArgs,
+		dockerOptions.BuildSecrets,
+		dockerEnv,
+		previewerWriter,
+	)
+	ch.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return "", "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("building container: %s at %s: %w", serviceConfig.Name, dockerOptions.Context, err),
+			//nolint:lll
+			Suggestion: "When the buildx builder doesn't support multi-platform builds, create one with 'docker buildx create --use --driver docker-container' and run 'azd deploy' again",
+		}
+	}
+
+	ch.recordPushedImage(ctx, pushImage)
+
+	return remoteImage, digest, nil
+}
+
+// recordPushedImage records the image pushed to an Azure Container Registry to the journal of the context, so it can
+// be deleted when the command is interrupted.
+func (ch *ContainerHelper) recordPushedImage(ctx context.Context, image string) {
+	if !journal.IsRecording(ctx) {
+		return
+	}
+
+	containerImage, err := docker.ParseContainerImage(image)
+	if err != nil || containerImage.Registry == "" || !ch.isAzureContainerRegistry(containerImage.Registry) {
+		return
+	}
+
+	journal.Record(ctx, journal.Entry{
+		Kind:           journal.EntryContainerImage,
+		SubscriptionId: ch.env.GetSubscriptionId(),
+		Registry:       containerImage.Registry,
+		Repository:     containerImage.Repository,
+		Tag:            containerImage.Tag,
+	})
+}
+
+// pushImageName returns the name the remote image is pushed with. The image is pushed through the login server
+// override when configured, while the deployed image keeps referencing the registry.
+func pushImageName(remoteImage string, registryName string, loginServer string) (string, error) {
+	if loginServer == registryName {
+		return remoteImage, nil
+	}
+
+	pushContainerImage, err := docker.ParseContainerImage(remoteImage)
+	if err != nil {
+		return "", err
+	}
+
+	pushContainerImage.Registry = loginServer
+	return pushContainerImage.Remote(), nil
+}
+
 // runRemoteBuild builds the image using a remote azure container registry and tags it.
 // It returns the full remote image name.
 func (ch *ContainerHelper) runRemoteBuild(
@@ -419,7 +774,18 @@ func (ch *ContainerHelper) runRemoteBuild(
 
 	progress.SetProgress(NewServiceProgress("Packing remote build context"))
 
-	contextPath, dockerPath, err := containerregistry.PackRemoteBuildSource(ctx, dockerOptions.Context, dockerOptions.Path)
+	rules, err := newPackageRules(serviceConfig.Package)
+	if err != nil {
+		return "", err
+	}
+
+	var filter containerregistry.ContextFilter
+	if rules != nil {
+		filter = rules.contextFilter()
+	}
+
+	contextPath, dockerPath, err := containerregistry.PackRemoteBuildSource(
+		ctx, dockerOptions.Context, dockerOptions.Path, filter)
 	if contextPath != "" {
 		defer os.Remove(contextPath)
 	}
@@ -484,6 +850,8 @@ func (ch *ContainerHelper) runRemoteBuild(
 		return "", err
 	}
 
+	ch.recordPushedImage(ctx, imageName)
+
 	return imageName, nil
 }
 
@@ -525,3 +893,58 @@ func (ch *ContainerHelper) runDotnetPublish(
 type dockerDeployResult struct {
 	RemoteImageTag string
 }
+
+// ImagePackageResult is the package of a service deployed from a container image pushed earlier, ex) with
+// 'azd deploy <service> --image', which is deployed without being built, tagged or pushed.
+type ImagePackageResult struct {
+	// The image, by tag or digest, ex) contoso.azurecr.io/web@sha256:<digest>
+	Image string `json:"image"`
+}
+
+// NewImagePackageResult returns the package of a service deployed from the image, which must include its registry and
+// either a tag or a digest.
+func NewImagePackageResult(image string) (*ImagePackageResult, error) {
+	name, digest, hasDigest := strings.Cut(image, "@")
+	if hasDigest && !strings.HasPrefix(digest, "sha256:") {
+		return nil, fmt.Errorf("invalid image '%s': expected a digest like sha256:<digest>", image)
+	}
+
+	containerImage, err := docker.ParseContainerImage(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image '%s': %w", image, err)
+	}
+
+	if containerImage.Registry == "" {
+		return nil, fmt.Errorf(
+			"invalid image '%s': expected the registry of the image, ex) <registry>/<repository>:<tag>", image)
+	}
+
+	if containerImage.Tag == "" && !hasDigest {
+		return nil, fmt.Errorf("invalid image '%s': expected the tag or the digest of the image", image)
+	}
+
+	return &ImagePackageResult{Image: image}, nil
+}
+
+// Digest returns the digest of the image, when it's referenced by digest.
+func (ipr *ImagePackageResult) Digest() string {
+	_, digest, _ := strings.Cut(ipr.Image, "@")
+	return digest
+}
+
+func (ipr *ImagePackageResult) ToString(currentIndentation string) string {
+	return fmt.Sprintf("%s- Image: %s", currentIndentation, output.WithLinkFormat(ipr.Image))
+}
+
+// SupportsImagePackage returns true when the service is deployed as a container image, so it can be deployed from an
+// image pushed earlier with an ImagePackageResult.
+func SupportsImagePackage(serviceConfig *ServiceConfig) bool {
+	switch serviceConfig.Host {
+	case ContainerAppTarget, AksTarget, AciTarget:
+		return true
+	case AppServiceTarget, DotNetContainerAppTarget:
+		return serviceConfig.Language == ServiceLanguageDocker
+	default:
+		return false
+	}
+}
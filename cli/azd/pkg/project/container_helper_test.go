@@ -8,15 +8,21 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -30,6 +36,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	// Avoid network calls when pushing to container registries
+	checkRegistryConnectivity = func(ctx context.Context, host string) *containerregistry.ConnectivityResult {
+		return &containerregistry.ConnectivityResult{Host: host, Addresses: []string{"10.0.0.4"}}
+	}
+
+	os.Exit(m.Run())
+}
+
 func Test_ContainerHelper_LocalImageTag(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	mockClock := clock.NewMock()
@@ -192,18 +207,20 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 
 func Test_ContainerHelper_Deploy(t *testing.T) {
 	tests := []struct {
-		name                    string
-		registry                osutil.ExpandableString
-		image                   string
-		project                 string
-		packagePath             string
-		dockerDetails           *dockerPackageResult
-		expectedRemoteImage     string
-		expectDockerLoginCalled bool
-		expectDockerPullCalled  bool
-		expectDockerTagCalled   bool
-		expectDockerPushCalled  bool
-		expectError             bool
+		name                     string
+		registry                 osutil.ExpandableString
+		loginServer              osutil.ExpandableString
+		image                    string
+		project                  string
+		packagePath              string
+		dockerDetails            *dockerPackageResult
+		expectedRemoteImage      string
+		expectedPushImage        string
+		expectRegistryAuthCalled bool
+		expectDockerPullCalled   bool
+		expectDockerTagCalled    bool
+		expectDockerPushCalled   bool
+		expectError              bool
 	}{
 		{
 			name:     "Source code and registry",
@@ -214,12 +231,30 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				SourceImage: "",
 				TargetImage: "my-project/my-service:azd-deploy-0",
 			},
-			expectDockerLoginCalled: true,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   true,
-			expectDockerPushCalled:  true,
-			expectedRemoteImage:     "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
-			expectError:             false,
+			expectRegistryAuthCalled: true,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    true,
+			expectDockerPushCalled:   true,
+			expectedRemoteImage:      "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+			expectError:              false,
+		},
+		{
+			name:        "Source code and login server override",
+			project:     "./src/api",
+			registry:    osutil.NewExpandableString("contoso.azurecr.io"),
+			loginServer: osutil.NewExpandableString("contoso-edge.local:8080"),
+			dockerDetails: &dockerPackageResult{
+				ImageHash:   "IMAGE_ID",
+				SourceImage: "",
+				TargetImage: "my-project/my-service:azd-deploy-0",
+			},
+			expectRegistryAuthCalled: false,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    true,
+			expectDockerPushCalled:   true,
+			expectedRemoteImage:      "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+			expectedPushImage:        "contoso-edge.local:8080/my-project/my-service:azd-deploy-0",
+			expectError:              false,
 		},
 		{
 			name:    "Source code and no registry",
@@ -229,23 +264,23 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				SourceImage: "",
 				TargetImage: "my-project/my-service:azd-deploy-0",
 			},
-			expectError:             true,
-			expectDockerLoginCalled: false,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   false,
-			expectDockerPushCalled:  false,
+			expectError:              true,
+			expectRegistryAuthCalled: false,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    false,
+			expectDockerPushCalled:   false,
 		},
 		{
-			name:                    "Source code with existing package path",
-			project:                 "./src/api",
-			registry:                osutil.NewExpandableString("contoso.azurecr.io"),
-			packagePath:             "my-project/my-service:azd-deploy-0",
-			expectedRemoteImage:     "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
-			expectDockerLoginCalled: true,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   true,
-			expectDockerPushCalled:  true,
-			expectError:             false,
+			name:                     "Source code with existing package path",
+			project:                  "./src/api",
+			registry:                 osutil.NewExpandableString("contoso.azurecr.io"),
+			packagePath:              "my-project/my-service:azd-deploy-0",
+			expectedRemoteImage:      "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+			expectRegistryAuthCalled: true,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    true,
+			expectDockerPushCalled:   true,
+			expectError:              false,
 		},
 		{
 			name:     "Source image and registry",
@@ -256,12 +291,12 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				SourceImage: "nginx",
 				TargetImage: "my-project/nginx:azd-deploy-0",
 			},
-			expectDockerLoginCalled: true,
-			expectDockerPullCalled:  true,
-			expectDockerTagCalled:   true,
-			expectDockerPushCalled:  true,
-			expectedRemoteImage:     "contoso.azurecr.io/my-project/nginx:azd-deploy-0",
-			expectError:             false,
+			expectRegistryAuthCalled: true,
+			expectDockerPullCalled:   true,
+			expectDockerTagCalled:    true,
+			expectDockerPushCalled:   true,
+			expectedRemoteImage:      "contoso.azurecr.io/my-project/nginx:azd-deploy-0",
+			expectError:              false,
 		},
 		{
 			name:     "Source image and external registry",
@@ -272,12 +307,12 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				SourceImage: "nginx",
 				TargetImage: "my-project/nginx:azd-deploy-0",
 			},
-			expectDockerLoginCalled: false,
-			expectDockerPullCalled:  true,
-			expectDockerTagCalled:   true,
-			expectDockerPushCalled:  true,
-			expectedRemoteImage:     "docker.io/custom/my-project/nginx:azd-deploy-0",
-			expectError:             false,
+			expectRegistryAuthCalled: false,
+			expectDockerPullCalled:   true,
+			expectDockerTagCalled:    true,
+			expectDockerPushCalled:   true,
+			expectedRemoteImage:      "docker.io/custom/my-project/nginx:azd-deploy-0",
+			expectError:              false,
 		},
 		{
 			name:  "Source image and no registry",
@@ -287,41 +322,41 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				SourceImage: "nginx",
 				TargetImage: "my-project/nginx:azd-deploy-0",
 			},
-			expectDockerLoginCalled: false,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   false,
-			expectDockerPushCalled:  false,
-			expectedRemoteImage:     "nginx",
-			expectError:             false,
+			expectRegistryAuthCalled: false,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    false,
+			expectDockerPushCalled:   false,
+			expectedRemoteImage:      "nginx",
+			expectError:              false,
 		},
 		{
-			name:                    "Source image with existing package path and registry",
-			registry:                osutil.NewExpandableString("contoso.azurecr.io"),
-			packagePath:             "my-project/my-service:azd-deploy-0",
-			expectedRemoteImage:     "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
-			expectDockerLoginCalled: true,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   true,
-			expectDockerPushCalled:  true,
-			expectError:             false,
+			name:                     "Source image with existing package path and registry",
+			registry:                 osutil.NewExpandableString("contoso.azurecr.io"),
+			packagePath:              "my-project/my-service:azd-deploy-0",
+			expectedRemoteImage:      "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+			expectRegistryAuthCalled: true,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    true,
+			expectDockerPushCalled:   true,
+			expectError:              false,
 		},
 		{
-			name:                    "Empty package details",
-			dockerDetails:           &dockerPackageResult{},
-			expectError:             true,
-			expectDockerLoginCalled: false,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   false,
-			expectDockerPushCalled:  false,
+			name:                     "Empty package details",
+			dockerDetails:            &dockerPackageResult{},
+			expectError:              true,
+			expectRegistryAuthCalled: false,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    false,
+			expectDockerPushCalled:   false,
 		},
 		{
-			name:                    "Nil package details",
-			dockerDetails:           nil,
-			expectError:             true,
-			expectDockerLoginCalled: false,
-			expectDockerPullCalled:  false,
-			expectDockerTagCalled:   false,
-			expectDockerPushCalled:  false,
+			name:                     "Nil package details",
+			dockerDetails:            nil,
+			expectError:              true,
+			expectRegistryAuthCalled: false,
+			expectDockerPullCalled:   false,
+			expectDockerTagCalled:    false,
+			expectDockerPushCalled:   false,
 		},
 	}
 
@@ -361,6 +396,7 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 			serviceConfig.Image = osutil.NewExpandableString(tt.image)
 			serviceConfig.RelativePath = tt.project
 			serviceConfig.Docker.Registry = tt.registry
+			serviceConfig.Docker.LoginServer = tt.loginServer
 
 			packageOutput := &ServicePackageResult{
 				Details:     tt.dockerDetails,
@@ -391,25 +427,302 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 			_, dockerTagCalled := mockResults["docker-tag"]
 			_, dockerPushCalled := mockResults["docker-push"]
 
-			if tt.expectDockerLoginCalled {
+			if tt.expectRegistryAuthCalled {
 				registryName, err := tt.registry.Envsubst(env.Getenv)
 				require.NoError(t, err)
 
+				// Images are pushed with ephemeral credentials instead of logging docker into the registry
 				mockContainerRegistryService.AssertCalled(
 					t,
-					"Login",
+					"Credentials",
 					*mockContext.Context,
 					env.GetSubscriptionId(),
 					registryName,
 				)
-			} else {
 				mockContainerRegistryService.AssertNotCalled(t, "Login")
+				require.Len(t, mockResults["docker-push"].Env, 1)
+				require.True(t, strings.HasPrefix(mockResults["docker-push"].Env[0], "DOCKER_CONFIG="))
+
+				configDir := strings.TrimPrefix(mockResults["docker-push"].Env[0], "DOCKER_CONFIG=")
+				require.NoDirExists(t, configDir)
+			} else {
+				mockContainerRegistryService.AssertNotCalled(t, "Credentials")
 			}
 
 			require.Equal(t, tt.expectDockerPullCalled, dockerPullCalled)
 			require.Equal(t, tt.expectDockerTagCalled, dockerTagCalled)
 			require.Equal(t, tt.expectDockerPushCalled, dockerPushCalled)
 			require.Equal(t, tt.expectedRemoteImage, env.GetServiceProperty("api", "IMAGE_NAME"))
+
+			if tt.expectedPushImage != "" {
+				require.Equal(t, tt.expectedPushImage, mockResults["docker-push"].Args[1])
+			}
+		})
+	}
+}
+
+func Test_ContainerHelper_Deploy_MultiPlatform(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "Dockerfile"), []byte("FROM scratch"), osutil.PermissionFile))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	var buildArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args
+
+		metadataFile := args.Args[len(args.Args)-1]
+		err := os.WriteFile(metadataFile, []byte(`{"containerimage.digest": "sha256:0123456789abcdef"}`), 0600)
+		return exec.NewRunResult(0, "", ""), err
+	})
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		nil,
+		docker.NewCli(mockContext.CommandRunner),
+		dotnet.NewCli(mockContext.CommandRunner),
+		mockContext.Console,
+		cloud.AzurePublic(),
+	)
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageDocker)
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.Platforms = []string{"linux/amd64", "linux/arm64"}
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP", "Microsoft.App/containerApps")
+
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return containerHelper.Deploy(
+				*mockContext.Context, serviceConfig, &ServicePackageResult{}, targetResource, true, progress)
+		},
+	)
+	require.NoError(t, err)
+
+	remoteImage := "contoso.azurecr.io/test-app/api-dev:azd-deploy-0"
+	require.Equal(t, remoteImage, deployResult.Details.(*dockerDeployResult).RemoteImageTag)
+	require.Contains(t, buildArgs.Args, "linux/amd64,linux/arm64")
+	require.Contains(t, buildArgs.Args, remoteImage)
+	require.Contains(t, buildArgs.Args, "--push")
+
+	// buildx pushes with ephemeral credentials instead of logging docker into the registry
+	mockContainerRegistryService.AssertCalled(
+		t, "Credentials", *mockContext.Context, env.GetSubscriptionId(), "contoso.azurecr.io")
+	mockContainerRegistryService.AssertNotCalled(t, "Login")
+	dockerConfigEnv := slices.IndexFunc(buildArgs.Env, func(env string) bool {
+		return strings.HasPrefix(env, "DOCKER_CONFIG=") && strings.Contains(env, "azd-docker-config")
+	})
+	require.GreaterOrEqual(t, dockerConfigEnv, 0)
+	require.NoDirExists(t, strings.TrimPrefix(buildArgs.Env[dockerConfigEnv], "DOCKER_CONFIG="))
+
+	require.Equal(t, remoteImage, env.GetServiceProperty("api", "IMAGE_NAME"))
+	require.Equal(t, "sha256:0123456789abcdef", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+}
+
+func Test_ContainerHelper_Deploy_ImagePackage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{})
+	env.SetServiceProperty("api", "IMAGE_DIGEST", "sha256:previous")
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	setupContainerRegistryMocks(mockContext, &mockContainerRegistryService.Mock)
+
+	dockerCalled := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "docker"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		dockerCalled = true
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		nil,
+		docker.NewCli(mockContext.CommandRunner),
+		dotnet.NewCli(mockContext.CommandRunner),
+		mockContext.Console,
+		cloud.AzurePublic(),
+	)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageDocker)
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP", "Microsoft.App/containerApps")
+
+	deploy := func(image string) *ServiceDeployResult {
+		imagePackage, err := NewImagePackageResult(image)
+		require.NoError(t, err)
+
+		deployResult, err := logProgress(
+			t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+				return containerHelper.Deploy(
+					*mockContext.Context,
+					serviceConfig,
+					&ServicePackageResult{PackagePath: image, Details: imagePackage},
+					targetResource,
+					true,
+					progress,
+				)
+			},
+		)
+		require.NoError(t, err)
+		return deployResult
+	}
+
+	t.Run("Digest", func(t *testing.T) {
+		image := "staging.azurecr.io/web@sha256:0123456789abcdef"
+		deployResult := deploy(image)
+
+		require.Equal(t, image, deployResult.Details.(*dockerDeployResult).RemoteImageTag)
+		require.Equal(t, image, env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Equal(t, "sha256:0123456789abcdef", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		image := "staging.azurecr.io/web:v1"
+		deployResult := deploy(image)
+
+		require.Equal(t, image, deployResult.Details.(*dockerDeployResult).RemoteImageTag)
+		require.Equal(t, image, env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Empty(t, env.GetServiceProperty("api", "IMAGE_DIGEST"))
+	})
+
+	// The image is neither built, tagged nor pushed
+	require.False(t, dockerCalled)
+	mockContainerRegistryService.AssertNotCalled(t, "Login", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_NewImagePackageResult(t *testing.T) {
+	tests := []struct {
+		image   string
+		wantErr string
+	}{
+		{image: "contoso.azurecr.io/web@sha256:0123456789abcdef"},
+		{image: "contoso.azurecr.io/apps/web:v1"},
+		{image: "web:v1", wantErr: "expected the registry of the image"},
+		{image: "contoso.azurecr.io/web", wantErr: "expected the tag or the digest of the image"},
+		{image: "contoso.azurecr.io/web@md5:0123", wantErr: "expected a digest like sha256:<digest>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			imagePackage, err := NewImagePackageResult(tt.image)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.image, imagePackage.Image)
+		})
+	}
+}
+
+func Test_ContainerHelper_Deploy_RegistryNetworkDiagnostics(t *testing.T) {
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		"Microsoft.App/containerApps",
+	)
+
+	registry := &armcontainerregistry.Registry{
+		Properties: &armcontainerregistry.RegistryProperties{
+			PublicNetworkAccess: to.Ptr(armcontainerregistry.PublicNetworkAccessDisabled),
+		},
+	}
+
+	tests := []struct {
+		name               string
+		connectivity       *containerregistry.ConnectivityResult
+		expectedSuggestion []string
+	}{
+		{
+			name: "Unresolvable registry",
+			connectivity: &containerregistry.ConnectivityResult{
+				Host:   "contoso.azurecr.io",
+				DnsErr: errors.New("no such host"),
+			},
+			expectedSuggestion: []string{"Unable to reach contoso.azurecr.io", "public network access disabled"},
+		},
+		{
+			name: "Registry resolving to public address",
+			connectivity: &containerregistry.ConnectivityResult{
+				Host:       "contoso.azurecr.io",
+				Addresses:  []string{"20.1.2.3"},
+				ConnectErr: errors.New("i/o timeout"),
+			},
+			expectedSuggestion: []string{"privatelink.azurecr.io", "public network access disabled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := checkRegistryConnectivity
+			t.Cleanup(func() { checkRegistryConnectivity = original })
+			checkRegistryConnectivity = func(ctx context.Context, host string) *containerregistry.ConnectivityResult {
+				return tt.connectivity
+			}
+
+			mockContext := mocks.NewMockContext(context.Background())
+			mockResults := setupDockerMocks(mockContext)
+			env := environment.NewWithValues("dev", map[string]string{})
+
+			mockContainerRegistryService := &mockContainerRegistryService{}
+			mockContainerRegistryService.
+				On("FindContainerRegistry", mock.Anything, mock.Anything, "contoso.azurecr.io").
+				Return(registry, nil)
+
+			containerHelper := NewContainerHelper(
+				env,
+				&mockenv.MockEnvManager{},
+				clock.NewMock(),
+				mockContainerRegistryService,
+				nil,
+				docker.NewCli(mockContext.CommandRunner),
+				dotnet.NewCli(mockContext.CommandRunner),
+				mockContext.Console,
+				cloud.AzurePublic(),
+			)
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+
+			packageOutput := &ServicePackageResult{
+				Details: &dockerPackageResult{
+					TargetImage: "my-project/my-service:azd-deploy-0",
+				},
+			}
+
+			_, err := logProgress(
+				t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+					return containerHelper.Deploy(
+						*mockContext.Context, serviceConfig, packageOutput, targetResource, false, progress)
+				},
+			)
+
+			var suggestionErr *internal.ErrorWithSuggestion
+			require.ErrorAs(t, err, &suggestionErr)
+			for _, expected := range tt.expectedSuggestion {
+				require.Contains(t, suggestionErr.Suggestion, expected)
+			}
+
+			_, dockerPushCalled := mockResults["docker-push"]
+			require.False(t, dockerPushCalled)
+			mockContainerRegistryService.AssertNotCalled(t, "Credentials")
 		})
 	}
 }
@@ -590,6 +903,36 @@ func (m *mockContainerRegistryServiceForRetry) GetContainerRegistries(
 	return args.Get(0).([]*armcontainerregistry.Registry), args.Error(1)
 }
 
+func (m *mockContainerRegistryServiceForRetry) FindContainerRegistry(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+) (*armcontainerregistry.Registry, error) {
+	args := m.Called(ctx, subscriptionId, loginServer)
+	return args.Get(0).(*armcontainerregistry.Registry), args.Error(1)
+}
+
+func (m *mockContainerRegistryServiceForRetry) DeleteImageTag(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	tag string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, repository, tag)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryServiceForRetry) AccessToken(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	scope string,
+) (string, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, scope)
+	return args.String(0), args.Error(1)
+}
+
 func Test_ContainerHelper_Credential_Retry(t *testing.T) {
 	t.Run("Retry on 404 on time", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
@@ -623,6 +966,13 @@ func setupContainerRegistryMocks(mockContext *mocks.MockContext, mockContainerRe
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType("string")).
 		Return(nil)
+
+	mockContainerRegistryService.On(
+		"Credentials",
+		*mockContext.Context,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string")).
+		Return(&azapi.DockerCredentials{Username: "username", Password: "password"}, nil)
 }
 
 func setupDockerMocks(mockContext *mocks.MockContext) map[string]exec.RunArgs {
@@ -699,3 +1049,33 @@ func (m *mockContainerRegistryService) GetContainerRegistries(
 	args := m.Called(ctx, subscriptionId)
 	return args.Get(0).([]*armcontainerregistry.Registry), args.Error(1)
 }
+
+func (m *mockContainerRegistryService) FindContainerRegistry(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+) (*armcontainerregistry.Registry, error) {
+	args := m.Called(ctx, subscriptionId, loginServer)
+	return args.Get(0).(*armcontainerregistry.Registry), args.Error(1)
+}
+
+func (m *mockContainerRegistryService) DeleteImageTag(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	tag string,
+) error {
+	args := m.Called(ctx, subscriptionId, loginServer, repository, tag)
+	return args.Error(0)
+}
+
+func (m *mockContainerRegistryService) AccessToken(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	scope string,
+) (string, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, scope)
+	return args.String(0), args.Error(1)
+}
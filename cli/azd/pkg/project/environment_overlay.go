@@ -0,0 +1,179 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/braydonk/yaml"
+)
+
+// environmentOverlaysKey is the key of azure.yaml holding the overrides of the project by environment name
+const environmentOverlaysKey = "env"
+
+// The keys of the project the overrides of an environment can set
+var environmentOverlayKeys = []string{"services", "hooks"}
+
+// EffectiveConfig is the configuration of the project used in an environment, with the overrides of the environment
+// merged over azure.yaml.
+type EffectiveConfig struct {
+	// The name of the environment, empty when the project isn't loaded for an environment
+	Environment string `json:"environment,omitempty"`
+	// The sources the configuration is merged from, by increasing precedence, ex) azure.yaml, azure.yaml#env.prod and
+	// azure.prod.yaml
+	Sources []string `json:"sources"`
+	// The configuration of the project, in the format of azure.yaml
+	Config map[string]any `json:"config"`
+}
+
+// EnvironmentOverlayPath returns the path of the file overriding the project in an environment, next to the project
+// file, ex) azure.prod.yaml for azure.yaml.
+func EnvironmentOverlayPath(projectFilePath string, envName string) string {
+	ext := filepath.Ext(projectFilePath)
+	return strings.TrimSuffix(projectFilePath, ext) + "." + envName + ext
+}
+
+// LoadEffectiveConfig loads the configuration of the project used in the environment. The overrides of the environment
+// are merged over the services and hooks of azure.yaml, by increasing precedence:
+//
+//  1. The entry of the environment under 'env' in azure.yaml
+//  2. The overlay file of the environment next to azure.yaml, see EnvironmentOverlayPath
+//
+// Maps are merged key by key, other values, including lists, are replaced, and null values remove the key, ex) to turn
+// off a hook in an environment.
+func LoadEffectiveConfig(ctx context.Context, projectFilePath string, envName string) (*EffectiveConfig, error) {
+	projectConfig, err := LoadConfig(ctx, projectFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := &EffectiveConfig{
+		Environment: envName,
+		Sources:     []string{filepath.Base(projectFilePath)},
+		Config:      projectConfig.Raw(),
+	}
+
+	overlays, _ := effective.Config[environmentOverlaysKey].(map[string]any)
+	delete(effective.Config, environmentOverlaysKey)
+
+	if envName == "" {
+		return effective, nil
+	}
+
+	if !environment.IsValidEnvironmentName(envName) {
+		return nil, fmt.Errorf("invalid environment name '%s'", envName)
+	}
+
+	if overlay, has := overlays[envName]; has {
+		source := fmt.Sprintf("%s#%s.%s", filepath.Base(projectFilePath), environmentOverlaysKey, envName)
+		if err := effective.merge(source, overlay); err != nil {
+			return nil, err
+		}
+	}
+
+	overlayPath := EnvironmentOverlayPath(projectFilePath, envName)
+	overlayFile, err := os.ReadFile(overlayPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", overlayPath, err)
+	} else if err == nil {
+		overlay := map[string]any{}
+		if err := yaml.Unmarshal(overlayFile, &overlay); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", overlayPath, err)
+		}
+
+		if err := effective.merge(filepath.Base(overlayPath), overlay); err != nil {
+			return nil, err
+		}
+	}
+
+	return effective, nil
+}
+
+// LoadForEnvironment loads the project with the overrides of the environment merged, see LoadEffectiveConfig. The
+// project can't be saved when it includes overrides, since they'd be written into azure.yaml.
+func LoadForEnvironment(ctx context.Context, projectFilePath string, envName string) (*ProjectConfig, error) {
+	effective, err := LoadEffectiveConfig(ctx, projectFilePath, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(effective.Sources) == 1 {
+		return Load(ctx, projectFilePath)
+	}
+
+	log.Printf("Merging the overrides of environment '%s' from %s", envName, strings.Join(effective.Sources[1:], ", "))
+	content, err := yaml.Marshal(effective.Config)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling the project of environment '%s': %w", envName, err)
+	}
+
+	projectConfig, err := load(ctx, projectFilePath, string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfig.overlayEnvironment = envName
+	return projectConfig, nil
+}
+
+// merge merges the overrides read from the source over the configuration.
+func (e *EffectiveConfig) merge(source string, overlay any) error {
+	if overlay == nil {
+		return nil
+	}
+
+	overlayMap, ok := overlay.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: the overrides of environment '%s' must be a map", source, e.Environment)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(overlayMap)) {
+		if !slices.Contains(environmentOverlayKeys, key) {
+			return fmt.Errorf("%s: '%s' can't be overridden by environment, only %s can",
+				source, key, strings.Join(environmentOverlayKeys, " and "))
+		}
+	}
+
+	if services, ok := overlayMap["services"].(map[string]any); ok {
+		baseServices, _ := e.Config["services"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(services)) {
+			if _, has := baseServices[name]; !has {
+				return fmt.Errorf("%s: service '%s' isn't defined in azure.yaml", source, name)
+			}
+		}
+	}
+
+	mergeOverlay(e.Config, overlayMap)
+	e.Sources = append(e.Sources, source)
+	return nil
+}
+
+// mergeOverlay merges the overlay into the base map. Maps are merged key by key, other values replace the value of the
+// base, and null values remove the key of the base.
+func mergeOverlay(base map[string]any, overlay map[string]any) {
+	for key, value := range overlay {
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+
+		if overlayMap, ok := value.(map[string]any); ok {
+			if baseMap, ok := base[key].(map[string]any); ok {
+				mergeOverlay(baseMap, overlayMap)
+				continue
+			}
+		}
+
+		base[key] = value
+	}
+}
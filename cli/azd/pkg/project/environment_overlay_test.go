@@ -0,0 +1,135 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+const overlayProject = `
+name: overlay
+hooks:
+  postprovision:
+    shell: sh
+    run: ./seed.sh
+services:
+  api:
+    project: src/api
+    language: js
+    host: containerapp
+    docker:
+      buildArgs:
+        - MODE=debug
+  web:
+    project: src/web
+    language: js
+    host: appservice
+env:
+  prod:
+    services:
+      api:
+        docker:
+          buildArgs:
+            - MODE=release
+    hooks:
+      postprovision: null
+  test:
+    services:
+      api:
+        docker:
+          buildArgs:
+            - MODE=test
+`
+
+func writeOverlayProject(t *testing.T, overlayFiles map[string]string) string {
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "azure.yaml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(overlayProject), 0600))
+
+	for envName, content := range overlayFiles {
+		require.NoError(t, os.WriteFile(EnvironmentOverlayPath(projectPath, envName), []byte(content), 0600))
+	}
+
+	return projectPath
+}
+
+func buildArgs(args ...string) []osutil.ExpandableString {
+	expandable := make([]osutil.ExpandableString, len(args))
+	for i, arg := range args {
+		expandable[i] = osutil.NewExpandableString(arg)
+	}
+
+	return expandable
+}
+
+func TestLoadForEnvironment(t *testing.T) {
+	projectPath := writeOverlayProject(t, map[string]string{
+		"test": "services:\n  web:\n    host: containerapp\n    docker:\n      buildArgs:\n        - MODE=file\n",
+	})
+
+	t.Run("NoEnvironment", func(t *testing.T) {
+		projectConfig, err := LoadForEnvironment(context.Background(), projectPath, "")
+		require.NoError(t, err)
+
+		require.Equal(t, buildArgs("MODE=debug"), projectConfig.Services["api"].Docker.BuildArgs)
+		require.Len(t, projectConfig.Hooks["postprovision"], 1)
+		require.Len(t, projectConfig.Env, 2)
+	})
+
+	t.Run("InlineOverrides", func(t *testing.T) {
+		projectConfig, err := LoadForEnvironment(context.Background(), projectPath, "prod")
+		require.NoError(t, err)
+
+		require.Equal(t, buildArgs("MODE=release"), projectConfig.Services["api"].Docker.BuildArgs)
+		require.Equal(t, "src/api", projectConfig.Services["api"].RelativePath)
+		require.Empty(t, projectConfig.Hooks["postprovision"])
+		require.Empty(t, projectConfig.Env)
+
+		require.ErrorContains(t, Save(context.Background(), projectConfig, projectPath), "includes the overrides")
+	})
+
+	t.Run("OverlayFileTakesPrecedence", func(t *testing.T) {
+		effective, err := LoadEffectiveConfig(context.Background(), projectPath, "test")
+		require.NoError(t, err)
+		require.Equal(t, []string{"azure.yaml", "azure.yaml#env.test", "azure.test.yaml"}, effective.Sources)
+
+		projectConfig, err := LoadForEnvironment(context.Background(), projectPath, "test")
+		require.NoError(t, err)
+
+		require.Equal(t, buildArgs("MODE=test"), projectConfig.Services["api"].Docker.BuildArgs)
+		require.Equal(t, ContainerAppTarget, projectConfig.Services["web"].Host)
+		require.Equal(t, buildArgs("MODE=file"), projectConfig.Services["web"].Docker.BuildArgs)
+	})
+
+	t.Run("NoOverrides", func(t *testing.T) {
+		projectConfig, err := LoadForEnvironment(context.Background(), projectPath, "dev")
+		require.NoError(t, err)
+
+		require.Equal(t, buildArgs("MODE=debug"), projectConfig.Services["api"].Docker.BuildArgs)
+		require.NoError(t, Save(context.Background(), projectConfig, projectPath))
+	})
+}
+
+func TestLoadForEnvironmentInvalidOverrides(t *testing.T) {
+	tests := map[string]string{
+		"UnknownService": "services:\n  worker:\n    host: containerapp\n",
+		"NotOverridable": "name: other\n",
+	}
+
+	for name, overlay := range tests {
+		t.Run(name, func(t *testing.T) {
+			projectPath := writeOverlayProject(t, map[string]string{"dev": overlay})
+
+			_, err := LoadForEnvironment(context.Background(), projectPath, "dev")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "azure.dev.yaml")
+		})
+	}
+}
@@ -63,6 +63,9 @@ type FrameworkPackageRequirements struct {
 // FrameworkService is an abstraction for a programming language or framework
 // that describe the required tools as well as implementations for
 // restore and build commands
+//
+// Framework services are registered in the container by language name. Languages which azd doesn't support
+// natively are declared as build plugins in azure.yaml, see [BuildPluginConfig].
 type FrameworkService interface {
 	// Gets a list of the required external tools for the framework service
 	RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool
@@ -39,8 +39,10 @@ type DockerProjectOptions struct {
 	Path        string                    `yaml:"path,omitempty"        json:"path,omitempty"`
 	Context     string                    `yaml:"context,omitempty"     json:"context,omitempty"`
 	Platform    string                    `yaml:"platform,omitempty"    json:"platform,omitempty"`
+	Platforms   []string                  `yaml:"platforms,omitempty"   json:"platforms,omitempty"`
 	Target      string                    `yaml:"target,omitempty"      json:"target,omitempty"`
 	Registry    osutil.ExpandableString   `yaml:"registry,omitempty"    json:"registry,omitempty"`
+	LoginServer osutil.ExpandableString   `yaml:"loginServer,omitempty" json:"loginServer,omitempty"`
 	Image       osutil.ExpandableString   `yaml:"image,omitempty"       json:"image,omitempty"`
 	Tag         osutil.ExpandableString   `yaml:"tag,omitempty"         json:"tag,omitempty"`
 	RemoteBuild bool                      `yaml:"remoteBuild,omitempty" json:"remoteBuild,omitempty"`
@@ -171,6 +173,18 @@ func (p *dockerProject) RequiredExternalTools(ctx context.Context, sc *ServiceCo
 
 // Initializes the docker project
 func (p *dockerProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if isMultiPlatformBuild(serviceConfig) {
+		switch {
+		case serviceConfig.Docker.Platform != "":
+			return fmt.Errorf("service '%s' can't set both 'docker.platform' and 'docker.platforms'", serviceConfig.Name)
+		case serviceConfig.Docker.RemoteBuild:
+			return fmt.Errorf("remote build of service '%s' doesn't support multiple platforms", serviceConfig.Name)
+		case serviceConfig.RelativePath == "":
+			return fmt.Errorf(
+				"'docker.platforms' of service '%s' requires building the image from the project", serviceConfig.Name)
+		}
+	}
+
 	return p.framework.Initialize(ctx, serviceConfig)
 }
 
@@ -197,7 +211,8 @@ func (p *dockerProject) Build(
 	restoreOutput *ServiceRestoreResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceBuildResult, error) {
-	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) {
+	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) ||
+		isMultiPlatformBuild(serviceConfig) {
 		return &ServiceBuildResult{Restore: restoreOutput}, nil
 	}
 
@@ -302,6 +317,12 @@ func (p *dockerProject) Build(
 	dockerEnv = append(dockerEnv, p.env.Environ()...)
 	dockerEnv = append(dockerEnv, dockerOptions.BuildEnv...)
 
+	buildContext, buildDockerfilePath, cleanup, err := packageBuildContext(ctx, serviceConfig, dockerOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	// Build the container
 	progress.SetProgress(NewServiceProgress("Building Docker image"))
 	previewerWriter := p.console.ShowPreviewer(ctx,
@@ -313,10 +334,10 @@ func (p *dockerProject) Build(
 	imageId, err := p.docker.Build(
 		ctx,
 		serviceConfig.Path(),
-		dockerOptions.Path,
+		buildDockerfilePath,
 		dockerOptions.Platform,
 		dockerOptions.Target,
-		dockerOptions.Context,
+		buildContext,
 		imageName,
 		resolvedBuildArgs,
 		dockerOptions.BuildSecrets,
@@ -369,13 +390,20 @@ func useDotnetPublishForDockerBuild(serviceConfig *ServiceConfig) bool {
 	return *serviceConfig.useDotNetPublishForDockerBuild
 }
 
+// isMultiPlatformBuild returns true when the image of the service is built for multiple platforms. Multi-platform
+// images are built and pushed in a single step on deploy.
+func isMultiPlatformBuild(serviceConfig *ServiceConfig) bool {
+	return len(serviceConfig.Docker.Platforms) > 0
+}
+
 func (p *dockerProject) Package(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	buildOutput *ServiceBuildResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServicePackageResult, error) {
-	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) {
+	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) ||
+		isMultiPlatformBuild(serviceConfig) {
 		return &ServicePackageResult{Build: buildOutput}, nil
 	}
 
@@ -646,3 +646,38 @@ func Test_DockerProject_Package(t *testing.T) {
 		})
 	}
 }
+
+func Test_DockerProject_Initialize_Platforms(t *testing.T) {
+	tests := map[string]struct {
+		relativePath string
+		docker       DockerProjectOptions
+		expectedErr  string
+	}{
+		"WithPlatform": {
+			relativePath: "./src/api",
+			docker:       DockerProjectOptions{Platform: "linux/amd64"},
+			expectedErr:  "can't set both 'docker.platform' and 'docker.platforms'",
+		},
+		"WithRemoteBuild": {
+			relativePath: "./src/api",
+			docker:       DockerProjectOptions{RemoteBuild: true},
+			expectedErr:  "remote build of service 'api' doesn't support multiple platforms",
+		},
+		"WithoutProject": {
+			expectedErr: "'docker.platforms' of service 'api' requires building the image from the project",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dockerProject := NewDockerProject(environment.New("test"), nil, nil, nil, nil, nil)
+			serviceConfig := createTestServiceConfig(tt.relativePath, ContainerAppTarget, ServiceLanguageDocker)
+			serviceConfig.RelativePath = tt.relativePath
+			serviceConfig.Docker = tt.docker
+			serviceConfig.Docker.Platforms = []string{"linux/amd64", "linux/arm64"}
+
+			err := dockerProject.Initialize(context.Background(), serviceConfig)
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
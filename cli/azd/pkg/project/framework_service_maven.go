@@ -11,9 +11,11 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
 	"github.com/otiai10/copy"
@@ -22,18 +24,40 @@ import (
 // The default, conventional App Service Java package name
 const AppServiceJavaPackageName = "app"
 
+// JavaOptions selects what is built and deployed for a service in a multi-module Maven or Gradle repository, where the
+// project path of the service is the root of the build.
+type JavaOptions struct {
+	// The path of the Maven module of the service, relative to the project path, ex) api. Only the module and the
+	// modules it depends on are built, with '-pl <module> -am'.
+	Module string `yaml:"module,omitempty"`
+	// The Gradle subproject of the service, ex) :api. The service is built with the tasks of the subproject using Gradle
+	// instead of Maven.
+	GradleProject string `yaml:"gradleProject,omitempty"`
+	// A glob matching the archive deployed, relative to the project path, ex) api/target/api-*.jar. Defaults to the
+	// single archive of the build directory of the module.
+	Artifact string `yaml:"artifact,omitempty"`
+}
+
 type mavenProject struct {
-	env      *environment.Environment
-	mavenCli *maven.Cli
-	javacCli *javac.Cli
+	env       *environment.Environment
+	mavenCli  *maven.Cli
+	javacCli  *javac.Cli
+	gradleCli *gradle.Cli
 }
 
-// NewMavenProject creates a new instance of a maven project
-func NewMavenProject(env *environment.Environment, mavenCli *maven.Cli, javaCli *javac.Cli) FrameworkService {
+// NewMavenProject creates a new instance of a maven project, or of a gradle project when the service sets a
+// java.gradleProject
+func NewMavenProject(
+	env *environment.Environment,
+	mavenCli *maven.Cli,
+	javaCli *javac.Cli,
+	gradleCli *gradle.Cli,
+) FrameworkService {
 	return &mavenProject{
-		env:      env,
-		mavenCli: mavenCli,
-		javacCli: javaCli,
+		env:       env,
+		mavenCli:  mavenCli,
+		javacCli:  javaCli,
+		gradleCli: gradleCli,
 	}
 }
 
@@ -48,7 +72,14 @@ func (m *mavenProject) Requirements() FrameworkRequirements {
 }
 
 // Gets the required external tools for the project
-func (m *mavenProject) RequiredExternalTools(_ context.Context, _ *ServiceConfig) []tools.ExternalTool {
+func (m *mavenProject) RequiredExternalTools(_ context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	if serviceConfig.Java.GradleProject != "" {
+		return []tools.ExternalTool{
+			m.gradleCli,
+			m.javacCli,
+		}
+	}
+
 	return []tools.ExternalTool{
 		m.mavenCli,
 		m.javacCli,
@@ -57,18 +88,50 @@ func (m *mavenProject) RequiredExternalTools(_ context.Context, _ *ServiceConfig
 
 // Initializes the maven project
 func (m *mavenProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if serviceConfig.Java.Module != "" && serviceConfig.Java.GradleProject != "" {
+		return fmt.Errorf(
+			"service '%s' sets both java.module and java.gradleProject, only one of them can be set", serviceConfig.Name)
+	}
+
 	m.mavenCli.SetPath(serviceConfig.Path(), serviceConfig.Project.Path)
+	m.gradleCli.SetPath(serviceConfig.Path(), serviceConfig.Project.Path)
 	return nil
 }
 
+// modules returns the maven modules built for the service, all the modules when empty
+func modules(serviceConfig *ServiceConfig) []string {
+	if serviceConfig.Java.Module == "" {
+		return nil
+	}
+
+	return []string{filepath.ToSlash(serviceConfig.Java.Module)}
+}
+
+// moduleDir returns the directory of the module, or of the gradle subproject, of the service
+func moduleDir(serviceConfig *ServiceConfig) string {
+	switch {
+	case serviceConfig.Java.GradleProject != "":
+		return gradle.ProjectDir(serviceConfig.Path(), serviceConfig.Java.GradleProject)
+	case serviceConfig.Java.Module != "":
+		return filepath.Join(serviceConfig.Path(), serviceConfig.Java.Module)
+	default:
+		return serviceConfig.Path()
+	}
+}
+
 // Restores dependencies using the Maven CLI
 func (m *mavenProject) Restore(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceRestoreResult, error) {
+	if serviceConfig.Java.GradleProject != "" {
+		// Gradle resolves the dependencies when building
+		return &ServiceRestoreResult{}, nil
+	}
+
 	progress.SetProgress(NewServiceProgress("Resolving maven dependencies"))
-	if err := m.mavenCli.ResolveDependencies(ctx, serviceConfig.Path()); err != nil {
+	if err := m.mavenCli.ResolveDependencies(ctx, serviceConfig.Path(), modules(serviceConfig)...); err != nil {
 		return nil, fmt.Errorf("resolving maven dependencies: %w", err)
 	}
 
@@ -82,9 +145,16 @@ func (m *mavenProject) Build(
 	restoreOutput *ServiceRestoreResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceBuildResult, error) {
-	progress.SetProgress(NewServiceProgress("Compiling maven project"))
-	if err := m.mavenCli.Compile(ctx, serviceConfig.Path()); err != nil {
-		return nil, err
+	if subproject := serviceConfig.Java.GradleProject; subproject != "" {
+		progress.SetProgress(NewServiceProgress("Compiling gradle project"))
+		if err := m.gradleCli.Run(ctx, serviceConfig.Path(), gradle.Task(subproject, "classes")); err != nil {
+			return nil, err
+		}
+	} else {
+		progress.SetProgress(NewServiceProgress("Compiling maven project"))
+		if err := m.mavenCli.Compile(ctx, serviceConfig.Path(), modules(serviceConfig)...); err != nil {
+			return nil, err
+		}
 	}
 
 	return &ServiceBuildResult{
@@ -99,9 +169,16 @@ func (m *mavenProject) Package(
 	buildOutput *ServiceBuildResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServicePackageResult, error) {
-	progress.SetProgress(NewServiceProgress("Packaging maven project"))
-	if err := m.mavenCli.Package(ctx, serviceConfig.Path()); err != nil {
-		return nil, err
+	if subproject := serviceConfig.Java.GradleProject; subproject != "" {
+		progress.SetProgress(NewServiceProgress("Packaging gradle project"))
+		if err := m.gradleCli.Run(ctx, serviceConfig.Path(), gradle.Task(subproject, "assemble")); err != nil {
+			return nil, err
+		}
+	} else {
+		progress.SetProgress(NewServiceProgress("Packaging maven project"))
+		if err := m.mavenCli.Package(ctx, serviceConfig.Path(), modules(serviceConfig)...); err != nil {
+			return nil, err
+		}
 	}
 
 	if serviceConfig.Host == AzureFunctionTarget {
@@ -134,23 +211,29 @@ func (m *mavenProject) Package(
 		packageSrcPath = serviceConfig.Path()
 	}
 
-	if serviceConfig.OutputPath != "" {
+	switch {
+	case serviceConfig.OutputPath != "":
 		packageSrcPath = filepath.Join(packageSrcPath, serviceConfig.OutputPath)
-	} else {
-		packageSrcPath = filepath.Join(packageSrcPath, "target")
+	case serviceConfig.Java.GradleProject != "":
+		subprojectDir := gradle.ProjectDir(packageSrcPath, serviceConfig.Java.GradleProject)
+		packageSrcPath = filepath.Join(subprojectDir, "build", "libs")
+	default:
+		packageSrcPath = filepath.Join(packageSrcPath, serviceConfig.Java.Module, "target")
 	}
 
-	packageSrcFileInfo, err := os.Stat(packageSrcPath)
-	if err != nil {
+	archive := ""
+	if serviceConfig.Java.Artifact != "" {
+		archive, err = findArtifact(serviceConfig)
+		if err != nil {
+			return nil, err
+		}
+	} else if packageSrcFileInfo, err := os.Stat(packageSrcPath); err != nil {
 		if serviceConfig.OutputPath == "" {
 			return nil, fmt.Errorf("reading default maven target path %s: %w", packageSrcPath, err)
 		} else {
 			return nil, fmt.Errorf("reading dist path %s: %w", packageSrcPath, err)
 		}
-	}
-
-	archive := ""
-	if packageSrcFileInfo.IsDir() {
+	} else if packageSrcFileInfo.IsDir() {
 		archive, err = m.discoverArchive(packageSrcPath)
 		if err != nil {
 			return nil, err
@@ -181,7 +264,7 @@ func (m *mavenProject) Package(
 //
 // The app is typically packaged under target/azure-functions.
 func (m *mavenProject) funcAppDir(ctx context.Context, svc *ServiceConfig) (string, error) {
-	svcPath := svc.Path()
+	svcPath := moduleDir(svc)
 	// The staging directory for azure-functions-maven-plugin is target/azure-functions.
 	// It isn't configurable, but this may change in the future: https://github.com/microsoft/azure-maven-plugins/issues/1968
 	functionsStagingRel := filepath.Join("target", "azure-functions")
@@ -189,7 +272,7 @@ func (m *mavenProject) funcAppDir(ctx context.Context, svc *ServiceConfig) (stri
 
 	// A conventional azure-functions-maven-plugin project will have the property 'functionAppName' in pom.xml,
 	// with its property value is passed to azure-functions-maven-plugin as 'appName'.
-	appName, err := m.mavenCli.GetProperty(ctx, "functionAppName", svcPath)
+	appName, err := m.mavenCli.GetProperty(ctx, "functionAppName", moduleDir(svc))
 	if err != nil && !errors.Is(err, maven.ErrPropertyNotFound) {
 		return "", fmt.Errorf("getting 'functionAppName' maven property: %w", err)
 	}
@@ -229,6 +312,49 @@ func (m *mavenProject) funcAppDir(ctx context.Context, svc *ServiceConfig) (stri
 		strings.Join(dirs, ", "))
 }
 
+// findArtifact returns the archive matching the java.artifact glob of the service, which must match a single archive
+// so that a missing or ambiguous artifact fails the packaging instead of the deployment.
+func findArtifact(serviceConfig *ServiceConfig) (string, error) {
+	pattern := serviceConfig.Java.Artifact
+	matches, err := filepath.Glob(filepath.Join(serviceConfig.Path(), pattern))
+	if err != nil {
+		return "", fmt.Errorf("invalid java.artifact pattern '%s': %w", pattern, err)
+	}
+
+	archives := []string{}
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() && isSupportedJavaArchive(match) {
+			archives = append(archives, match)
+		}
+	}
+
+	switch len(archives) {
+	case 0:
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"no java archive (.jar, .war, .ear) matching java.artifact '%s' was built for service '%s'",
+				pattern, serviceConfig.Name),
+			Suggestion: "Suggestion: check that the build produces the archive, and that java.artifact is relative " +
+				"to the project path of the service in azure.yaml.",
+		}
+	case 1:
+		return archives[0], nil
+	default:
+		for i := range archives {
+			if rel, err := filepath.Rel(serviceConfig.Path(), archives[i]); err == nil {
+				archives[i] = rel
+			}
+		}
+
+		return "", &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"multiple java archives match java.artifact '%s' for service '%s': %s",
+				pattern, serviceConfig.Name, strings.Join(archives, ", ")),
+			Suggestion: "Suggestion: make java.artifact in azure.yaml match a single archive, ex) api/target/api-*-exec.jar",
+		}
+	}
+}
+
 func isSupportedJavaArchive(archiveFile string) bool {
 	ext := strings.ToLower(filepath.Ext(archiveFile))
 	return ext == ".jar" || ext == ".war" || ext == ".ear"
@@ -261,7 +387,7 @@ func (m *mavenProject) discoverArchive(dir string) (string, error) {
 		names := strings.Join(archiveFiles, ", ")
 		return "", fmt.Errorf(
 			//nolint:lll
-			"multiple java archive files (.jar, .ear, .war) found in %s: %s. To pick a specific archive to be used, specify the relative path to the archive file using the 'dist' property in azure.yaml",
+			"multiple java archive files (.jar, .ear, .war) found in %s: %s. To pick a specific archive to be used, specify the relative path to the archive file using the 'dist' property, or a glob matching it using the 'java.artifact' property in azure.yaml",
 			dir,
 			names,
 		)
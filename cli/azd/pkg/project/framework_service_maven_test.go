@@ -17,6 +17,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -49,7 +50,7 @@ func Test_MavenProject(t *testing.T) {
 		mavenCli := maven.NewCli(mockContext.CommandRunner)
 		javaCli := javac.NewCli(mockContext.CommandRunner)
 
-		mavenProject := NewMavenProject(env, mavenCli, javaCli)
+		mavenProject := NewMavenProject(env, mavenCli, javaCli, gradle.NewCli(mockContext.CommandRunner))
 		err = mavenProject.Initialize(*mockContext.Context, serviceConfig)
 		require.NoError(t, err)
 
@@ -85,7 +86,7 @@ func Test_MavenProject(t *testing.T) {
 		mavenCli := maven.NewCli(mockContext.CommandRunner)
 		javaCli := javac.NewCli(mockContext.CommandRunner)
 
-		mavenProject := NewMavenProject(env, mavenCli, javaCli)
+		mavenProject := NewMavenProject(env, mavenCli, javaCli, gradle.NewCli(mockContext.CommandRunner))
 		err = mavenProject.Initialize(*mockContext.Context, serviceConfig)
 		require.NoError(t, err)
 
@@ -129,7 +130,7 @@ func Test_MavenProject(t *testing.T) {
 		err = os.WriteFile(filepath.Join(buildOutputDir, "test.jar"), []byte("test"), osutil.PermissionFile)
 		require.NoError(t, err)
 
-		mavenProject := NewMavenProject(env, mavenCli, javaCli)
+		mavenProject := NewMavenProject(env, mavenCli, javaCli, gradle.NewCli(mockContext.CommandRunner))
 		err = mavenProject.Initialize(*mockContext.Context, serviceConfig)
 		require.NoError(t, err)
 
@@ -291,7 +292,7 @@ func Test_MavenProject_AppService_Package(t *testing.T) {
 			env := environment.New("test")
 			mavenCli := maven.NewCli(mockContext.CommandRunner)
 			javaCli := javac.NewCli(mockContext.CommandRunner)
-			mavenProject := NewMavenProject(env, mavenCli, javaCli)
+			mavenProject := NewMavenProject(env, mavenCli, javaCli, gradle.NewCli(mockContext.CommandRunner))
 			err = mavenProject.Initialize(*mockContext.Context, tt.args.svc)
 			require.NoError(t, err)
 
@@ -361,7 +362,7 @@ func Test_MavenProject_FuncApp_Package(t *testing.T) {
 	err = os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory)
 	require.NoError(t, err)
 
-	mavenProject := NewMavenProject(env, mavenCli, javaCli)
+	mavenProject := NewMavenProject(env, mavenCli, javaCli, gradle.NewCli(mockContext.CommandRunner))
 
 	t.Run("uses maven property functionAppName", func(t *testing.T) {
 		mvnFuncAppNameProperty = "my-function-app"
@@ -461,6 +462,115 @@ func Test_MavenProject_FuncApp_Package(t *testing.T) {
 	})
 }
 
+func Test_MavenProject_MultiModule_Package(t *testing.T) {
+	// newProject creates a multi-module project with the maven and gradle wrappers in its root, and returns the runner
+	// capturing the arguments of the last build
+	newProject := func(t *testing.T, java JavaOptions) (*ServiceConfig, *mocks.MockContext, *exec.RunArgs) {
+		temp := t.TempDir()
+		svc := &ServiceConfig{
+			Project:         &ProjectConfig{Path: temp},
+			Name:            "api",
+			RelativePath:    ".",
+			Host:            AppServiceTarget,
+			Language:        ServiceLanguageJava,
+			Java:            java,
+			EventDispatcher: ext.NewEventDispatcher[ServiceLifecycleEventArgs](),
+		}
+
+		gradlew := "gradlew"
+		if runtime.GOOS == "windows" {
+			gradlew = "gradlew.bat"
+		}
+		for _, wrapper := range []string{getMvnwCmd(), gradlew} {
+			require.NoError(t, os.WriteFile(filepath.Join(temp, wrapper), nil, osutil.PermissionExecutableFile))
+		}
+
+		var runArgs exec.RunArgs
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, " package") || strings.Contains(command, ":assemble")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		return svc, mockContext, &runArgs
+	}
+
+	writeArchives := func(t *testing.T, dir string, names ...string) {
+		require.NoError(t, os.MkdirAll(dir, osutil.PermissionDirectory))
+		for _, name := range names {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("test"), osutil.PermissionFile))
+		}
+	}
+
+	packageService := func(
+		t *testing.T, svc *ServiceConfig, mockContext *mocks.MockContext) (*ServicePackageResult, error) {
+		mavenProject := NewMavenProject(
+			environment.New("test"),
+			maven.NewCli(mockContext.CommandRunner),
+			javac.NewCli(mockContext.CommandRunner),
+			gradle.NewCli(mockContext.CommandRunner),
+		)
+		if err := mavenProject.Initialize(*mockContext.Context, svc); err != nil {
+			return nil, err
+		}
+
+		return logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return mavenProject.Package(*mockContext.Context, svc, &ServiceBuildResult{}, progress)
+		})
+	}
+
+	t.Run("MavenModule", func(t *testing.T) {
+		svc, mockContext, runArgs := newProject(t, JavaOptions{Module: "api"})
+		writeArchives(t, filepath.Join(svc.Path(), "api", "target"), "api-1.0.jar")
+		writeArchives(t, filepath.Join(svc.Path(), "worker", "target"), "worker-1.0.jar")
+
+		result, err := packageService(t, svc, mockContext)
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(result.PackagePath, "app.jar"))
+		require.Equal(t, []string{"package", "-DskipTests", "-pl", "api", "-am"}, runArgs.Args)
+	})
+
+	t.Run("GradleProject", func(t *testing.T) {
+		svc, mockContext, runArgs := newProject(t, JavaOptions{
+			GradleProject: ":services:api",
+			Artifact:      "services/api/build/libs/*-boot.jar",
+		})
+		writeArchives(t, filepath.Join(svc.Path(), "services", "api", "build", "libs"), "api-plain.jar", "api-boot.jar")
+
+		result, err := packageService(t, svc, mockContext)
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(result.PackagePath, "app.jar"))
+		require.Equal(t, []string{":services:api:assemble", "-x", "test", "--console=plain"}, runArgs.Args)
+	})
+
+	t.Run("ErrArtifactNotFound", func(t *testing.T) {
+		svc, mockContext, _ := newProject(t, JavaOptions{Module: "api", Artifact: "api/target/api-*.war"})
+		writeArchives(t, filepath.Join(svc.Path(), "api", "target"), "api-1.0.jar")
+
+		_, err := packageService(t, svc, mockContext)
+		require.ErrorContains(t, err, "no java archive (.jar, .war, .ear) matching java.artifact 'api/target/api-*.war'")
+	})
+
+	t.Run("ErrMultipleArtifacts", func(t *testing.T) {
+		svc, mockContext, _ := newProject(t, JavaOptions{GradleProject: "api", Artifact: "api/build/libs/*.jar"})
+		writeArchives(t, filepath.Join(svc.Path(), "api", "build", "libs"), "api.jar", "api-plain.jar")
+
+		_, err := packageService(t, svc, mockContext)
+		require.ErrorContains(t, err, "multiple java archives match java.artifact 'api/build/libs/*.jar'")
+	})
+
+	t.Run("ErrModuleAndGradleProject", func(t *testing.T) {
+		svc, mockContext, _ := newProject(t, JavaOptions{Module: "api", GradleProject: ":api"})
+
+		_, err := packageService(t, svc, mockContext)
+		require.ErrorContains(t, err, "sets both java.module and java.gradleProject")
+	})
+}
+
 func getMvnwCmd() string {
 	if runtime.GOOS == "windows" {
 		return "mvnw.cmd"
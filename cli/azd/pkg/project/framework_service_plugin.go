@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// Environment variables set for the commands of build plugins
+const (
+	// The name of the service being built
+	BuildPluginServiceNameEnvVarName = "AZD_SERVICE_NAME"
+	// The absolute path of the service being built
+	BuildPluginServicePathEnvVarName = "AZD_SERVICE_PATH"
+	// The absolute path of the directory where the package command must write the deployment artifacts
+	BuildPluginOutputEnvVarName = "AZD_BUILD_OUTPUT"
+)
+
+// BuildPluginConfig declares a custom build system in azure.yaml, which allows services to use languages that azd doesn't
+// support natively.
+//
+// The commands run with a shell in the service directory, with the azd environment values and the AZD_SERVICE_NAME,
+// AZD_SERVICE_PATH and AZD_BUILD_OUTPUT environment variables set. The package command must write the deployment
+// artifacts to the AZD_BUILD_OUTPUT directory, which is then deployed as is.
+type BuildPluginConfig struct {
+	// The tools which must be installed to run the commands
+	Tools []BuildPluginTool `yaml:"tools,omitempty"`
+	// The command that restores the dependencies of the service
+	Restore string `yaml:"restore,omitempty"`
+	// The command that builds the service
+	Build string `yaml:"build,omitempty"`
+	// The command that writes the deployment artifacts of the service. Defaults to the build command
+	Package string `yaml:"package,omitempty"`
+	// The directory, relative to the service, the deployment artifacts are written to. Defaults to 'dist'.
+	// The `dist` of a service takes precedence over this value.
+	Output string `yaml:"output,omitempty"`
+}
+
+// BuildPluginTool is an executable required by a build plugin
+type BuildPluginTool struct {
+	// The name of the executable, which must be found on the PATH
+	Name string `yaml:"name"`
+	// The URL with install instructions for the tool
+	InstallUrl string `yaml:"installUrl,omitempty"`
+}
+
+// buildPluginTool adapts the tools declared by build plugins to external tools
+type buildPluginTool struct {
+	tool BuildPluginTool
+}
+
+func (t *buildPluginTool) CheckInstalled(_ context.Context) error {
+	return tools.ToolInPath(t.tool.Name)
+}
+
+func (t *buildPluginTool) InstallUrl() string {
+	return t.tool.InstallUrl
+}
+
+func (t *buildPluginTool) Name() string {
+	return t.tool.Name
+}
+
+type buildPluginProject struct {
+	name          string
+	config        *BuildPluginConfig
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+}
+
+// NewBuildPluginProject creates a framework service which restores, builds and packages services with the commands of
+// a build plugin declared in azure.yaml.
+func NewBuildPluginProject(
+	name string,
+	config *BuildPluginConfig,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+) FrameworkService {
+	return &buildPluginProject{
+		name:          name,
+		config:        config,
+		env:           env,
+		commandRunner: commandRunner,
+	}
+}
+
+func (bp *buildPluginProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		Package: FrameworkPackageRequirements{
+			RequireRestore: bp.config.Restore != "",
+			RequireBuild:   false,
+		},
+	}
+}
+
+// Gets the required external tools declared by the build plugin
+func (bp *buildPluginProject) RequiredExternalTools(_ context.Context, _ *ServiceConfig) []tools.ExternalTool {
+	externalTools := make([]tools.ExternalTool, 0, len(bp.config.Tools))
+	for _, tool := range bp.config.Tools {
+		externalTools = append(externalTools, &buildPluginTool{tool: tool})
+	}
+
+	return externalTools
+}
+
+// Initializes the build plugin project
+func (bp *buildPluginProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Restores dependencies with the restore command of the build plugin
+func (bp *buildPluginProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceRestoreResult, error) {
+	if bp.config.Restore != "" {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Restoring %s dependencies", bp.name)))
+		if err := bp.run(ctx, serviceConfig, "restore", bp.config.Restore); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ServiceRestoreResult{}, nil
+}
+
+// Builds the service with the build command of the build plugin
+func (bp *buildPluginProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceBuildResult, error) {
+	if bp.config.Build != "" {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Building %s project", bp.name)))
+		if err := bp.run(ctx, serviceConfig, "build", bp.config.Build); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ServiceBuildResult{
+		Restore:         restoreOutput,
+		BuildOutputPath: bp.outputPath(serviceConfig),
+	}, nil
+}
+
+// Packages the service with the package command of the build plugin, which defaults to the build command
+func (bp *buildPluginProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	packageCommand := bp.config.Package
+	if packageCommand == "" {
+		packageCommand = bp.config.Build
+	}
+
+	if packageCommand != "" {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Packaging %s project", bp.name)))
+		if err := bp.run(ctx, serviceConfig, "package", packageCommand); err != nil {
+			return nil, err
+		}
+	}
+
+	packagePath := bp.outputPath(serviceConfig)
+	if err := validatePackageOutput(packagePath); err != nil {
+		return nil, fmt.Errorf(
+			"the '%s' build plugin must write the deployment artifacts to the %s directory: %w",
+			bp.name,
+			BuildPluginOutputEnvVarName,
+			err,
+		)
+	}
+
+	return &ServicePackageResult{
+		Build:       buildOutput,
+		PackagePath: packagePath,
+	}, nil
+}
+
+// outputPath gets the absolute path of the directory containing the deployment artifacts of the service
+func (bp *buildPluginProject) outputPath(serviceConfig *ServiceConfig) string {
+	output := serviceConfig.OutputPath
+	if output == "" {
+		output = filepath.FromSlash(bp.config.Output)
+	}
+
+	if output == "" {
+		output = "dist"
+	}
+
+	if filepath.IsAbs(output) {
+		return output
+	}
+
+	return filepath.Join(serviceConfig.Path(), output)
+}
+
+func (bp *buildPluginProject) run(ctx context.Context, serviceConfig *ServiceConfig, step string, command string) error {
+	env := append(
+		bp.env.Environ(),
+		fmt.Sprintf("%s=%s", BuildPluginServiceNameEnvVarName, serviceConfig.Name),
+		fmt.Sprintf("%s=%s", BuildPluginServicePathEnvVarName, serviceConfig.Path()),
+		fmt.Sprintf("%s=%s", BuildPluginOutputEnvVarName, bp.outputPath(serviceConfig)),
+	)
+
+	runArgs := exec.NewRunArgs(command).
+		WithCwd(serviceConfig.Path()).
+		WithEnv(env).
+		WithShell(true)
+
+	if _, err := bp.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("running the '%s' build plugin %s command '%s': %w", bp.name, step, command, err)
+	}
+
+	return nil
+}
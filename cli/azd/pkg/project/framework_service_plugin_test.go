@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildPluginProject_Restore_Build_Package(t *testing.T) {
+	temp := t.TempDir()
+	ran := map[string]exec.RunArgs{}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "cargo")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran[args.Cmd] = args
+			if args.Cmd == "cargo build --release" {
+				outputDir := filepath.Join(args.Cwd, "target")
+				err := os.MkdirAll(outputDir, osutil.PermissionDirectory)
+				require.NoError(t, err)
+				err = os.WriteFile(filepath.Join(outputDir, "app"), []byte("app"), osutil.PermissionFile)
+				require.NoError(t, err)
+			}
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	env := environment.NewWithValues("test", map[string]string{"AZURE_LOCATION": "eastus2"})
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageKind("rust"))
+	serviceConfig.Project.Path = temp
+
+	pluginProject := NewBuildPluginProject("rust", &BuildPluginConfig{
+		Restore: "cargo fetch",
+		Build:   "cargo build --release",
+		Output:  "target",
+	}, env, mockContext.CommandRunner)
+
+	require.True(t, pluginProject.Requirements().Package.RequireRestore)
+
+	restoreResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceRestoreResult, error) {
+		return pluginProject.Restore(*mockContext.Context, serviceConfig, progress)
+	})
+	require.NoError(t, err)
+	require.Contains(t, ran, "cargo fetch")
+
+	buildResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+		return pluginProject.Build(*mockContext.Context, serviceConfig, restoreResult, progress)
+	})
+	require.NoError(t, err)
+
+	outputPath := filepath.Join(serviceConfig.Path(), "target")
+	require.Equal(t, outputPath, buildResult.BuildOutputPath)
+
+	buildArgs := ran["cargo build --release"]
+	require.True(t, buildArgs.UseShell)
+	require.Equal(t, serviceConfig.Path(), buildArgs.Cwd)
+	require.Contains(t, buildArgs.Env, "AZURE_LOCATION=eastus2")
+	require.Contains(t, buildArgs.Env, BuildPluginServiceNameEnvVarName+"=api")
+	require.Contains(t, buildArgs.Env, BuildPluginServicePathEnvVarName+"="+serviceConfig.Path())
+	require.Contains(t, buildArgs.Env, BuildPluginOutputEnvVarName+"="+outputPath)
+
+	// Without a package command, the build command is used to package the service
+	delete(ran, "cargo build --release")
+	packageResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return pluginProject.Package(*mockContext.Context, serviceConfig, buildResult, progress)
+	})
+	require.NoError(t, err)
+	require.Contains(t, ran, "cargo build --release")
+	require.Equal(t, outputPath, packageResult.PackagePath)
+}
+
+func Test_BuildPluginProject_Package_EmptyOutput(t *testing.T) {
+	temp := t.TempDir()
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "deno")
+		}).
+		Respond(exec.NewRunResult(0, "", ""))
+
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageKind("deno"))
+	serviceConfig.Project.Path = temp
+
+	pluginProject := NewBuildPluginProject("deno", &BuildPluginConfig{
+		Package: "deno compile --output $AZD_BUILD_OUTPUT/app main.ts",
+	}, environment.New("test"), mockContext.CommandRunner)
+
+	_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return pluginProject.Package(*mockContext.Context, serviceConfig, &ServiceBuildResult{}, progress)
+	})
+	require.Error(t, err)
+	require.ErrorContains(t, err, BuildPluginOutputEnvVarName)
+}
+
+func Test_BuildPluginProject_RequiredExternalTools(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageKind("bun"))
+
+	pluginProject := NewBuildPluginProject("bun", &BuildPluginConfig{
+		Tools: []BuildPluginTool{
+			{Name: "bun", InstallUrl: "https://bun.sh"},
+		},
+		Build: "bun build",
+	}, environment.New("test"), mockContext.CommandRunner)
+
+	require.False(t, pluginProject.Requirements().Package.RequireRestore)
+
+	externalTools := pluginProject.RequiredExternalTools(*mockContext.Context, serviceConfig)
+	require.Len(t, externalTools, 1)
+	require.Equal(t, "bun", externalTools[0].Name())
+	require.Equal(t, "https://bun.sh", externalTools[0].InstallUrl())
+}
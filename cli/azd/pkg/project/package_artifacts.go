@@ -0,0 +1,385 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+)
+
+const (
+	// The artifact type of the service packages published by azd
+	PackageArtifactType = "application/vnd.azure.azd.package.v1"
+	// The artifact type of the infrastructure as code of a project published by azd
+	InfraArtifactType = "application/vnd.azure.azd.infra.v1"
+
+	// The annotation of the service of a package
+	PackageAnnotationService = "com.azure.azd.service"
+	// The annotation of the kind of a package, either 'file' or 'directory'
+	PackageAnnotationKind = "com.azure.azd.package.kind"
+)
+
+const (
+	packageKindFile      = "file"
+	packageKindDirectory = "directory"
+)
+
+// PackageArtifacts publishes service packages to an OCI registry, ex) an Azure Container Registry, so that the packages
+// built once can be deployed to any environment with 'azd deploy --from-package oci://...'.
+//
+// Container images are pushed as images, packages of files and directories are pushed as OCI artifacts, annotated with
+// the service, the tag and the commit of the project they're built from.
+type PackageArtifacts struct {
+	env                      *environment.Environment
+	containerHelper          *ContainerHelper
+	containerRegistryService azapi.ContainerRegistryService
+	docker                   *docker.Cli
+	gitCli                   *git.Cli
+	coreClientOptions        *azcore.ClientOptions
+}
+
+func NewPackageArtifacts(
+	env *environment.Environment,
+	containerHelper *ContainerHelper,
+	containerRegistryService azapi.ContainerRegistryService,
+	docker *docker.Cli,
+	gitCli *git.Cli,
+	coreClientOptions *azcore.ClientOptions,
+) *PackageArtifacts {
+	return &PackageArtifacts{
+		env:                      env,
+		containerHelper:          containerHelper,
+		containerRegistryService: containerRegistryService,
+		docker:                   docker,
+		gitCli:                   gitCli,
+		coreClientOptions:        coreClientOptions,
+	}
+}
+
+// DefaultTag returns the default tag of published packages, generated from the current time. It's computed once per
+// run, so that all the packages published together share the same tag.
+func (pa *PackageArtifacts) DefaultTag() string {
+	return fmt.Sprintf("azd-package-%d", pa.containerHelper.clock.Now().Unix())
+}
+
+// Publish pushes the package of the service to the '<repository>/<service>' repository of the registry of the
+// reference, and returns the package result referencing the published package with its oci:// reference.
+func (pa *PackageArtifacts) Publish(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+	reference *containerregistry.ArtifactReference,
+) (*ServicePackageResult, error) {
+	if reference.Reference == "" {
+		return nil, fmt.Errorf("packages must be published to a tag, '%s' doesn't have one", reference)
+	}
+
+	target := &containerregistry.ArtifactReference{
+		Registry:   reference.Registry,
+		Repository: fmt.Sprintf("%s/%s", reference.Repository, strings.ToLower(serviceConfig.Name)),
+		Reference:  reference.Reference,
+	}
+
+	if details, ok := packageResult.Details.(*dockerPackageResult); ok && details != nil {
+		if err := pa.publishImage(ctx, serviceConfig, details, target); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := pa.publishFiles(ctx, serviceConfig, packageResult.PackagePath, target); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ServicePackageResult{
+		Build:       packageResult.Build,
+		PackagePath: target.String(),
+	}, nil
+}
+
+// PublishInfra pushes the infrastructure as code of the project to the repository of the reference itself, next to the
+// repositories of the service packages, and returns its oci:// reference. Projects without an infra directory, ex) when
+// the infrastructure is generated in memory, don't have anything to publish and an empty reference is returned.
+func (pa *PackageArtifacts) PublishInfra(
+	ctx context.Context,
+	projectConfig *ProjectConfig,
+	reference *containerregistry.ArtifactReference,
+) (string, error) {
+	if reference.Reference == "" {
+		return "", fmt.Errorf("packages must be published to a tag, '%s' doesn't have one", reference)
+	}
+
+	infraPath := projectConfig.Infra.Path
+	if !filepath.IsAbs(infraPath) {
+		infraPath = filepath.Join(projectConfig.Path, infraPath)
+	}
+
+	if _, err := os.Stat(infraPath); errors.Is(err, os.ErrNotExist) {
+		log.Printf("project doesn't have an infra directory at '%s', skipping publishing it", infraPath)
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("reading infra directory: %w", err)
+	}
+
+	annotations := map[string]string{
+		PackageAnnotationKind:               packageKindDirectory,
+		containerregistry.AnnotationVersion: reference.Reference,
+		containerregistry.AnnotationCreated: pa.containerHelper.clock.Now().UTC().Format(time.RFC3339),
+	}
+	if revision := pa.revision(ctx, projectConfig.Path); revision != "" {
+		annotations[containerregistry.AnnotationRevision] = revision
+	}
+
+	if err := pa.pushDirectory(ctx, infraPath, InfraArtifactType, annotations, reference); err != nil {
+		return "", fmt.Errorf("publishing infra: %w", err)
+	}
+
+	return reference.String(), nil
+}
+
+// Fetch gets the package published at the reference, pulling container images with docker and downloading other
+// packages to the directory, which is owned by the caller.
+func (pa *PackageArtifacts) Fetch(
+	ctx context.Context,
+	reference *containerregistry.ArtifactReference,
+	directory string,
+) (*ServicePackageResult, error) {
+	if reference.Reference == "" {
+		return nil, fmt.Errorf("package '%s' must reference a tag or a digest", reference)
+	}
+
+	client, err := pa.artifactClient(ctx, reference, "pull")
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := client.Manifest(ctx, reference.Repository, reference.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.ArtifactType != PackageArtifactType {
+		log.Printf("package '%s' is not an azd package artifact, pulling it as a container image", reference)
+		if err := pa.docker.Pull(ctx, reference.Image()); err != nil {
+			return nil, fmt.Errorf("pulling image: %w", err)
+		}
+
+		return &ServicePackageResult{PackagePath: reference.Image()}, nil
+	}
+
+	if len(manifest.Layers) != 1 {
+		return nil, fmt.Errorf("package '%s': %w", reference, containerregistry.ErrNotArtifactFile)
+	}
+
+	layer := manifest.Layers[0]
+	name := filepath.Base(layer.Annotations[containerregistry.AnnotationTitle])
+	if name == "." || name == string(filepath.Separator) {
+		name = "package"
+	}
+
+	if err := os.MkdirAll(directory, osutil.PermissionDirectory); err != nil {
+		return nil, err
+	}
+
+	packagePath := filepath.Join(directory, name)
+	if err := client.PullBlob(ctx, reference.Repository, layer, packagePath); err != nil {
+		return nil, fmt.Errorf("downloading package '%s': %w", reference, err)
+	}
+
+	if manifest.Annotations[PackageAnnotationKind] == packageKindDirectory {
+		extracted := filepath.Join(directory, strings.TrimSuffix(name, filepath.Ext(name)))
+		if err := rzip.ExtractToDirectory(packagePath, extracted); err != nil {
+			return nil, fmt.Errorf("extracting package '%s': %w", reference, err)
+		}
+
+		packagePath = extracted
+	}
+
+	return &ServicePackageResult{PackagePath: packagePath}, nil
+}
+
+// publishImage tags and pushes the container image of the package to the target.
+func (pa *PackageArtifacts) publishImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	details *dockerPackageResult,
+	target *containerregistry.ArtifactReference,
+) error {
+	image := details.TargetImage
+	if image == "" {
+		image = details.SourceImage
+	}
+
+	if image == "" {
+		return fmt.Errorf("service '%s' doesn't have a container image to publish", serviceConfig.Name)
+	}
+
+	if err := pa.docker.Tag(ctx, serviceConfig.Path(), image, target.Image()); err != nil {
+		return err
+	}
+
+	// Images are pushed to Azure Container Registry with ephemeral credentials, the other registries rely on an
+	// external 'docker login'
+	if !pa.containerHelper.isAzureContainerRegistry(target.Registry) {
+		if err := pa.docker.Push(ctx, serviceConfig.Path(), target.Image()); err != nil {
+			return fmt.Errorf("pushing image %s: %w", target.Image(), err)
+		}
+
+		return nil
+	}
+
+	dockerConfig, err := pa.containerHelper.ephemeralDockerConfig(ctx, target.Registry)
+	if err != nil {
+		return err
+	}
+	defer closeDockerConfig(dockerConfig)
+
+	if err := pa.docker.PushWithConfig(ctx, serviceConfig.Path(), target.Image(), dockerConfig.Dir); err != nil {
+		return fmt.Errorf("pushing image %s: %w", target.Image(), err)
+	}
+
+	return nil
+}
+
+// publishFiles pushes the package file, or a zip of the package directory, as an OCI artifact.
+func (pa *PackageArtifacts) publishFiles(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packagePath string,
+	target *containerregistry.ArtifactReference,
+) error {
+	if packagePath == "" {
+		return fmt.Errorf("service '%s' doesn't have a package to publish", serviceConfig.Name)
+	}
+
+	info, err := os.Stat(packagePath)
+	if err != nil {
+		return fmt.Errorf("reading package of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	annotations := map[string]string{
+		PackageAnnotationService:            serviceConfig.Name,
+		PackageAnnotationKind:               packageKindFile,
+		containerregistry.AnnotationVersion: target.Reference,
+		containerregistry.AnnotationCreated: pa.containerHelper.clock.Now().UTC().Format(time.RFC3339),
+	}
+	if revision := pa.revision(ctx, serviceConfig.Project.Path); revision != "" {
+		annotations[containerregistry.AnnotationRevision] = revision
+	}
+
+	if info.IsDir() {
+		annotations[PackageAnnotationKind] = packageKindDirectory
+		if err := pa.pushDirectory(ctx, packagePath, PackageArtifactType, annotations, target); err != nil {
+			return fmt.Errorf("publishing package of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		return nil
+	}
+
+	return pa.pushFile(ctx, packagePath, PackageArtifactType, annotations, target)
+}
+
+// pushDirectory pushes a zip of the directory as an OCI artifact.
+func (pa *PackageArtifacts) pushDirectory(
+	ctx context.Context,
+	directory string,
+	artifactType string,
+	annotations map[string]string,
+	target *containerregistry.ArtifactReference,
+) error {
+	zipFile, err := os.CreateTemp("", fmt.Sprintf("%s-*.zip", filepath.Base(directory)))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(zipFile.Name())
+	defer zipFile.Close()
+
+	if err := rzip.CreateFromDirectory(directory, zipFile, nil); err != nil {
+		return fmt.Errorf("zipping '%s': %w", directory, err)
+	}
+
+	return pa.pushFile(ctx, zipFile.Name(), artifactType, annotations, target)
+}
+
+// pushFile pushes the file as an OCI artifact.
+func (pa *PackageArtifacts) pushFile(
+	ctx context.Context,
+	path string,
+	artifactType string,
+	annotations map[string]string,
+	target *containerregistry.ArtifactReference,
+) error {
+	mediaType := "application/octet-stream"
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		mediaType = "application/zip"
+	}
+
+	client, err := pa.artifactClient(ctx, target, "pull,push")
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PushFile(ctx, target.Repository, target.Reference, containerregistry.ArtifactFile{
+		Path:         path,
+		MediaType:    mediaType,
+		ArtifactType: artifactType,
+		Annotations:  annotations,
+	})
+
+	return err
+}
+
+// revision returns the commit the project is built from, either set by the CI/CD pipeline or the head commit of its
+// repository. It's empty when the project isn't a git repository.
+func (pa *PackageArtifacts) revision(ctx context.Context, projectPath string) string {
+	if sourceVersion := provisioning.SourceVersion(); sourceVersion != "" {
+		return sourceVersion
+	}
+
+	headCommit, err := pa.gitCli.GetHeadCommit(ctx, projectPath)
+	if err != nil {
+		log.Printf("failed getting the head commit of the project for the package annotations: %v", err)
+		return ""
+	}
+
+	return headCommit
+}
+
+// artifactClient creates a client of the registry of the reference, authorized for the given actions on its
+// repository.
+func (pa *PackageArtifacts) artifactClient(
+	ctx context.Context,
+	reference *containerregistry.ArtifactReference,
+	actions string,
+) (*containerregistry.ArtifactClient, error) {
+	accessToken, err := pa.containerRegistryService.AccessToken(
+		ctx,
+		pa.env.GetSubscriptionId(),
+		reference.Registry,
+		fmt.Sprintf("repository:%s:%s", reference.Repository, actions),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, pa.coreClientOptions)
+	return containerregistry.NewArtifactClient(pipeline, reference.Registry, accessToken), nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PackageArtifacts(t *testing.T) {
+	// The revision comes from the repository of the project outside of CI
+	for _, envVar := range []string{
+		"GITHUB_SHA", "BUILD_SOURCEVERSION", "CI_COMMIT_SHA", "CIRCLE_SHA1", "BITBUCKET_COMMIT", "GIT_COMMIT",
+	} {
+		t.Setenv(envVar, "")
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "rev-parse HEAD")
+	}).Respond(exec.NewRunResult(0, "0123abcd\n", ""))
+
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "contoso.azurecr.io"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		repository, _, _ := strings.Cut(strings.TrimPrefix(request.URL.Path, "/v2/"), "/blobs/")
+		repository, _, _ = strings.Cut(repository, "/manifests/")
+
+		path := request.URL.Path
+		switch {
+		case request.Method == http.MethodHead:
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		case request.Method == http.MethodPost && strings.HasSuffix(path, "/blobs/uploads/"):
+			response, err := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+			if err != nil {
+				return nil, err
+			}
+			response.Header.Set("Location", "/v2/"+repository+"/blobs/uploads/UPLOAD")
+			return response, nil
+		case request.Method == http.MethodPut && strings.HasSuffix(path, "/blobs/uploads/UPLOAD"):
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			blobs[request.URL.Query().Get("digest")] = body
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		case request.Method == http.MethodPut && strings.Contains(path, "/manifests/"):
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			manifests[strings.TrimPrefix(path, "/v2/")] = body
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		case request.Method == http.MethodGet && strings.Contains(path, "/manifests/"):
+			manifest, has := manifests[strings.TrimPrefix(path, "/v2/")]
+			if !has {
+				return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+			}
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, json.RawMessage(manifest))
+		case request.Method == http.MethodGet && strings.Contains(path, "/blobs/"):
+			response, err := mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+			if err != nil {
+				return nil, err
+			}
+			response.Body = io.NopCloser(strings.NewReader(string(blobs[path[strings.LastIndex(path, "/")+1:]])))
+			return response, nil
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusBadRequest)
+	})
+
+	containerRegistryService := &mockContainerRegistryService{}
+	containerRegistryService.
+		On("AccessToken", mock.Anything, mock.Anything, "contoso.azurecr.io", mock.Anything).
+		Return("ACCESS_TOKEN", nil)
+
+	packageArtifacts := NewPackageArtifacts(
+		environment.New("test"),
+		&ContainerHelper{clock: clock.NewMock()},
+		containerRegistryService,
+		nil,
+		git.NewCli(mockContext.CommandRunner),
+		&azcore.ClientOptions{Transport: mockContext.HttpClient},
+	)
+
+	projectPath := t.TempDir()
+	projectConfig := &ProjectConfig{Name: "todo", Path: projectPath}
+	projectConfig.Infra.Path = "infra"
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, "infra"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(projectPath, "infra", "main.bicep"), []byte("targetScope = 'subscription'"), osutil.PermissionFile))
+
+	apiPath := filepath.Join(projectPath, "src", "api")
+	require.NoError(t, os.MkdirAll(apiPath, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(apiPath, "app.js"), []byte("console.log()"), osutil.PermissionFile))
+	serviceConfig := &ServiceConfig{Name: "api", Project: projectConfig}
+
+	reference := &containerregistry.ArtifactReference{Registry: "contoso.azurecr.io", Repository: "todo"}
+	_, err := packageArtifacts.Publish(
+		*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: apiPath}, reference)
+	require.ErrorContains(t, err, "must be published to a tag")
+
+	reference.Reference = packageArtifacts.DefaultTag()
+	published, err := packageArtifacts.Publish(
+		*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: apiPath}, reference)
+	require.NoError(t, err)
+	require.Equal(t, "oci://contoso.azurecr.io/todo/api:"+reference.Reference, published.PackagePath)
+
+	infraReference, err := packageArtifacts.PublishInfra(*mockContext.Context, projectConfig, reference)
+	require.NoError(t, err)
+	require.Equal(t, "oci://contoso.azurecr.io/todo:"+reference.Reference, infraReference)
+
+	var infraManifest containerregistry.ArtifactManifest
+	require.NoError(t, json.Unmarshal(manifests["todo/manifests/"+reference.Reference], &infraManifest))
+	require.Equal(t, InfraArtifactType, infraManifest.ArtifactType)
+	require.Equal(t, "0123abcd", infraManifest.Annotations[containerregistry.AnnotationRevision])
+
+	var apiManifest containerregistry.ArtifactManifest
+	require.NoError(t, json.Unmarshal(manifests["todo/api/manifests/"+reference.Reference], &apiManifest))
+	require.Equal(t, PackageArtifactType, apiManifest.ArtifactType)
+	require.Equal(t, "api", apiManifest.Annotations[PackageAnnotationService])
+	require.Equal(t, "0123abcd", apiManifest.Annotations[containerregistry.AnnotationRevision])
+
+	// The package is downloaded to the directory given by the caller
+	directory := filepath.Join(t.TempDir(), "api")
+	fetched, err := packageArtifacts.Fetch(*mockContext.Context, &containerregistry.ArtifactReference{
+		Registry:   "contoso.azurecr.io",
+		Repository: "todo/api",
+		Reference:  reference.Reference,
+	}, directory)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(fetched.PackagePath, directory))
+	require.FileExists(t, filepath.Join(fetched.PackagePath, "app.js"))
+
+	t.Run("NoInfra", func(t *testing.T) {
+		projectConfig := &ProjectConfig{Name: "todo", Path: t.TempDir()}
+		projectConfig.Infra.Path = "infra"
+
+		infraReference, err := packageArtifacts.PublishInfra(*mockContext.Context, projectConfig, reference)
+		require.NoError(t, err)
+		require.Empty(t, infraReference)
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/denormal/go-gitignore"
+)
+
+// PackageFilesOptions selects the files of a service shipped in its zip package, or its docker build context
+type PackageFilesOptions struct {
+	// The patterns of the files left out of the package, in .gitignore syntax relative to the folder of the service, or
+	// to the docker build context, ex) tests/, *.test.js, .env*
+	Exclude []string `yaml:"exclude,omitempty"`
+	// The patterns of the files shipped even when they're excluded, by the exclude patterns, the ignore files
+	// (.webappignore, .funcignore, .dockerignore) or the default exclusions of azd, ex) node_modules/
+	Include []string `yaml:"include,omitempty"`
+}
+
+// packageRules are the parsed exclude and include patterns of the package of a service
+type packageRules struct {
+	exclude  gitignore.GitIgnore
+	include  gitignore.GitIgnore
+	includes bool
+}
+
+// newPackageRules parses the patterns of the options, returns nil when there are no patterns
+func newPackageRules(options PackageFilesOptions) (*packageRules, error) {
+	if len(options.Exclude) == 0 && len(options.Include) == 0 {
+		return nil, nil
+	}
+
+	exclude, err := parsePackagePatterns("exclude", options.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	include, err := parsePackagePatterns("include", options.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	return &packageRules{
+		exclude:  exclude,
+		include:  include,
+		includes: len(options.Include) > 0,
+	}, nil
+}
+
+func parsePackagePatterns(name string, patterns []string) (gitignore.GitIgnore, error) {
+	for _, pattern := range patterns {
+		// Patterns matching the root, ex) /, aren't supported by the parser
+		if strings.Trim(strings.TrimPrefix(strings.TrimSpace(pattern), "!"), "/") == "" {
+			return nil, fmt.Errorf("invalid package.%s pattern '%s'", name, pattern)
+		}
+	}
+
+	var parseErr error
+	ignore := gitignore.New(strings.NewReader(strings.Join(patterns, "\n")), "", func(err gitignore.Error) bool {
+		parseErr = fmt.Errorf("invalid package.%s pattern '%s': %w", name, patterns[err.Position().Line-1], err)
+		return false
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return ignore, nil
+}
+
+// packaged returns whether the file or folder at the path, relative to the root of the package, is shipped. excluded
+// is whether it's excluded by an ignore file or the default exclusions of azd.
+func (r *packageRules) packaged(relativePath string, isDir bool, excluded bool) bool {
+	if r == nil {
+		return !excluded
+	}
+
+	if matchesOrParentMatches(r.include, relativePath, isDir) {
+		return true
+	}
+
+	return !excluded && !matchesOrParentMatches(r.exclude, relativePath, isDir)
+}
+
+// hasIncludes returns whether files can be shipped from excluded folders
+func (r *packageRules) hasIncludes() bool {
+	return r != nil && r.includes
+}
+
+// contextFilter returns the filter of the files of a docker build context
+func (r *packageRules) contextFilter() containerregistry.ContextFilter {
+	return func(contextPath string, ignored bool) bool {
+		return r.packaged(contextPath, false, ignored)
+	}
+}
+
+// matchesOrParentMatches returns whether the path, or one of its parent folders, is matched by the patterns
+func matchesOrParentMatches(patterns gitignore.GitIgnore, relativePath string, isDir bool) bool {
+	relativePath = filepath.ToSlash(relativePath)
+	for current := relativePath; current != "." && current != "/" && current != ""; current = path.Dir(current) {
+		if match := patterns.Relative(current, isDir || current != relativePath); match != nil {
+			return match.Ignore()
+		}
+	}
+
+	return false
+}
+
+// packageBuildContext returns the docker build context and Dockerfile of the service. When the service has package rules,
+// they're a filtered copy of the context, which is removed by the returned function.
+func packageBuildContext(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	dockerOptions DockerProjectOptions,
+) (string, string, func(), error) {
+	rules, err := newPackageRules(serviceConfig.Package)
+	if err != nil || rules == nil {
+		return dockerOptions.Context, dockerOptions.Path, func() {}, err
+	}
+
+	contextPath := dockerOptions.Context
+	if !filepath.IsAbs(contextPath) {
+		contextPath = filepath.Join(serviceConfig.Path(), contextPath)
+	}
+
+	dockerfilePath := dockerOptions.Path
+	if !filepath.IsAbs(dockerfilePath) {
+		dockerfilePath = filepath.Join(serviceConfig.Path(), dockerfilePath)
+	}
+
+	dir, dockerfileCopyPath, err := containerregistry.CopyBuildSource(
+		ctx, contextPath, dockerfilePath, rules.contextFilter())
+	cleanup := func() {
+		if dir != "" {
+			_ = os.RemoveAll(dir)
+		}
+	}
+	if err != nil {
+		cleanup()
+		return "", "", func() {}, fmt.Errorf("copying the build context of the package: %w", err)
+	}
+
+	return dir, dockerfileCopyPath, cleanup, nil
+}
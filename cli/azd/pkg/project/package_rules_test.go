@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PackageRules(t *testing.T) {
+	rules, err := newPackageRules(PackageFilesOptions{
+		Exclude: []string{"tests/", "*.test.js", ".env*", "!.env.example"},
+		Include: []string{"node_modules/left-pad/"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		excluded bool
+		expected bool
+	}{
+		{"index.js", false, false, true},
+		{"tests", true, false, false},
+		{"tests/fixtures/data.json", false, false, false},
+		{"src/app.test.js", false, false, false},
+		{".env.local", false, false, false},
+		{".env.example", false, false, true},
+		{"node_modules", true, true, false},
+		{"node_modules/left-pad", true, true, true},
+		{"node_modules/left-pad/index.js", false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.expected, rules.packaged(tt.path, tt.isDir, tt.excluded))
+		})
+	}
+
+	t.Run("InvalidPatterns", func(t *testing.T) {
+		_, err := newPackageRules(PackageFilesOptions{Exclude: []string{"tests/", "***"}})
+		require.ErrorContains(t, err, "invalid package.exclude pattern '***'")
+
+		_, err = newPackageRules(PackageFilesOptions{Include: []string{"/"}})
+		require.ErrorContains(t, err, "invalid package.include pattern '/'")
+
+		_, err = newPackageRules(PackageFilesOptions{Exclude: []string{""}})
+		require.ErrorContains(t, err, "invalid package.exclude pattern ''")
+	})
+
+	t.Run("NoRules", func(t *testing.T) {
+		rules, err := newPackageRules(PackageFilesOptions{})
+		require.NoError(t, err)
+		require.Nil(t, rules)
+		require.True(t, rules.packaged("index.js", false, false))
+		require.False(t, rules.packaged("node_modules", true, true))
+		require.False(t, rules.hasIncludes())
+	})
+}
+
+func Test_CreateDeployableZip_PackageRules(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{
+		"index.js",
+		"index.test.js",
+		".env",
+		"tests/fixture.json",
+		"node_modules/express/index.js",
+		"node_modules/typescript/index.js",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(file), osutil.PermissionFile))
+	}
+
+	svc := &ServiceConfig{
+		Name:     "api",
+		Project:  &ProjectConfig{Name: "app"},
+		Host:     AppServiceTarget,
+		Language: ServiceLanguageJavaScript,
+		Package: PackageFilesOptions{
+			Exclude: []string{"*.test.js", ".env", "tests/"},
+			Include: []string{"node_modules/express/"},
+		},
+	}
+
+	zipPath, err := createDeployableZip(svc, root)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Remove(zipPath) })
+
+	reader, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	files := []string{}
+	for _, file := range reader.File {
+		files = append(files, filepath.ToSlash(file.Name))
+	}
+	slices.Sort(files)
+
+	require.Equal(t, []string{"index.js", "node_modules/express/index.js"}, files)
+}
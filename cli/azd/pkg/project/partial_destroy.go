@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// PartialDestroyFilter selects a subset of the environment resources to delete instead of the whole environment.
+type PartialDestroyFilter struct {
+	// Names of the services whose resources (found via the azd-service-name tag) should be deleted
+	Services []string
+	// Names of individual resources that should be deleted
+	Resources []string
+	// Ids of the existing resources of the project, which are never deleted
+	Existing []string
+}
+
+// IsEmpty returns true when the filter does not select any service or resource.
+func (f PartialDestroyFilter) IsEmpty() bool {
+	return len(f.Services) == 0 && len(f.Resources) == 0
+}
+
+// PartialDestroyPlan describes the resources selected for deletion by a [PartialDestroyFilter], together with
+// any warnings produced by the dependency analysis.
+type PartialDestroyPlan struct {
+	Resources []*azapi.ResourceExtended
+	Warnings  []string
+}
+
+// PlanPartialDestroy selects the resources matching the filter from the full list of resources of the environment.
+//
+// An error is returned when a requested service or resource does not match any resource. Resources that are not
+// owned by the selected services (untagged, or tagged with another service) and resources nested under a selected
+// resource but owned by other services are reported as warnings, since deleting them may break those services.
+func PlanPartialDestroy(
+	filter PartialDestroyFilter,
+	allResources []*azapi.ResourceExtended,
+	serviceNames []string,
+) (*PartialDestroyPlan, error) {
+	plan := &PartialDestroyPlan{}
+	selected := map[string]struct{}{}
+
+	add := func(resource *azapi.ResourceExtended) {
+		key := strings.ToLower(resource.Id)
+		if _, has := selected[key]; has {
+			return
+		}
+
+		// Existing resources and their child resources are kept
+		if slices.ContainsFunc(filter.Existing, func(existing string) bool {
+			existing = strings.ToLower(existing)
+			return key == existing || strings.HasPrefix(key, existing+"/")
+		}) {
+			selected[key] = struct{}{}
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"'%s' is an existing resource of the project and won't be deleted", resource.Name))
+			return
+		}
+
+		selected[key] = struct{}{}
+		plan.Resources = append(plan.Resources, resource)
+	}
+
+	for _, serviceName := range filter.Services {
+		found := false
+		for _, resource := range allResources {
+			if resourceServiceName(resource) == serviceName {
+				add(resource)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf(
+				"unable to find any resource tagged with '%s: %s'", azure.TagKeyAzdServiceName, serviceName)
+		}
+	}
+
+	for _, resourceName := range filter.Resources {
+		found := false
+		for _, resource := range allResources {
+			if strings.EqualFold(resource.Name, resourceName) {
+				add(resource)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unable to find a resource named '%s'", resourceName)
+		}
+	}
+
+	// Services which remain deployed after the partial destroy.
+	remaining := []string{}
+	for _, serviceName := range serviceNames {
+		if !slices.Contains(filter.Services, serviceName) {
+			remaining = append(remaining, serviceName)
+		}
+	}
+
+	for _, resource := range plan.Resources {
+		owner := resourceServiceName(resource)
+		switch {
+		case owner == "" && len(remaining) > 0:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"'%s' is not owned by a service and may be shared by: %s",
+				resource.Name,
+				strings.Join(remaining, ", "),
+			))
+		case owner != "" && slices.Contains(remaining, owner):
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"'%s' hosts service '%s', which will stop working", resource.Name, owner,
+			))
+		}
+
+		childPrefix := strings.ToLower(resource.Id) + "/"
+		for _, other := range allResources {
+			if !strings.HasPrefix(strings.ToLower(other.Id), childPrefix) {
+				continue
+			}
+
+			if otherOwner := resourceServiceName(other); otherOwner != "" && slices.Contains(remaining, otherOwner) {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+					"'%s' contains '%s', used by service '%s'", resource.Name, other.Name, otherOwner,
+				))
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// resourceServiceName returns the value of the azd-service-name tag of the resource, or an empty string.
+func resourceServiceName(resource *azapi.ResourceExtended) string {
+	if value, has := resource.Tags[azure.TagKeyAzdServiceName]; has && value != nil {
+		return *value
+	}
+
+	return ""
+}
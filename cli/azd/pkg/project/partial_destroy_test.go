@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PlanPartialDestroy(t *testing.T) {
+	const rg = "/subscriptions/SUB/resourceGroups/RG/providers/"
+
+	newResource := func(id string, name string, service string) *azapi.ResourceExtended {
+		resource := &azapi.ResourceExtended{
+			Resource: azapi.Resource{Id: rg + id, Name: name},
+		}
+		if service != "" {
+			resource.Tags = map[string]*string{azure.TagKeyAzdServiceName: to.Ptr(service)}
+		}
+		return resource
+	}
+
+	plan := newResource("Microsoft.Web/serverfarms/plan", "plan", "")
+	web := newResource("Microsoft.Web/sites/web", "web", "web")
+	api := newResource("Microsoft.Web/sites/api", "api", "api")
+	apiSlot := newResource("Microsoft.Web/sites/web/slots/staging", "staging", "api")
+	all := []*azapi.ResourceExtended{plan, web, api, apiSlot}
+	services := []string{"web", "api"}
+
+	t.Run("Service", func(t *testing.T) {
+		result, err := PlanPartialDestroy(PartialDestroyFilter{Services: []string{"web"}}, all, services)
+		require.NoError(t, err)
+		require.Equal(t, []*azapi.ResourceExtended{web}, result.Resources)
+		require.Len(t, result.Warnings, 1)
+		require.Contains(t, result.Warnings[0], "'staging', used by service 'api'")
+	})
+
+	t.Run("SharedResource", func(t *testing.T) {
+		result, err := PlanPartialDestroy(PartialDestroyFilter{Resources: []string{"plan"}}, all, services)
+		require.NoError(t, err)
+		require.Equal(t, []*azapi.ResourceExtended{plan}, result.Resources)
+		require.Equal(t, []string{"'plan' is not owned by a service and may be shared by: web, api"}, result.Warnings)
+	})
+
+	t.Run("ServiceAndOwnedResource", func(t *testing.T) {
+		result, err := PlanPartialDestroy(
+			PartialDestroyFilter{Services: []string{"api"}, Resources: []string{"API"}}, all, services)
+		require.NoError(t, err)
+		require.Equal(t, []*azapi.ResourceExtended{api, apiSlot}, result.Resources)
+		require.Empty(t, result.Warnings)
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		filter := PartialDestroyFilter{Resources: []string{"web", "plan"}, Existing: []string{rg + "Microsoft.Web/sites/web"}}
+		result, err := PlanPartialDestroy(filter, all, services)
+		require.NoError(t, err)
+		require.Equal(t, []*azapi.ResourceExtended{plan}, result.Resources)
+		require.Contains(t, result.Warnings, "'web' is an existing resource of the project and won't be deleted")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := PlanPartialDestroy(PartialDestroyFilter{Services: []string{"worker"}}, all, services)
+		require.ErrorContains(t, err, "azd-service-name: worker")
+
+		_, err = PlanPartialDestroy(PartialDestroyFilter{Resources: []string{"missing"}}, all, services)
+		require.ErrorContains(t, err, "named 'missing'")
+	})
+}
+
+func Test_ExistingResourceIds(t *testing.T) {
+	resources := map[string]*ResourceConfig{
+		"cache":  {Type: ResourceTypeDbRedis, Existing: true},
+		"db":     {Type: ResourceTypeDbPostgres},
+		"vault":  {Type: ResourceTypeKeyVault, Existing: true},
+		"stored": {Type: ResourceTypeStorage, Existing: true},
+	}
+	env := map[string]string{
+		"AZURE_RESOURCE_CACHE_ID": "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Cache/redis/cache",
+		"AZURE_RESOURCE_DB_ID":    "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.DBforPostgreSQL/flexibleServers/db",
+		"AZURE_RESOURCE_VAULT_ID": "/subscriptions/SUB/resourceGroups/SHARED/providers/Microsoft.KeyVault/vaults/vault",
+	}
+
+	require.Equal(t, []string{
+		"/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Cache/redis/cache",
+		"/subscriptions/SUB/resourceGroups/SHARED/providers/Microsoft.KeyVault/vaults/vault",
+	}, ExistingResourceIds(resources, func(name string) string { return env[name] }))
+}
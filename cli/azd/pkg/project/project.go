@@ -90,15 +90,35 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 
 	projectConfig.Infra.Path = filepath.FromSlash(projectConfig.Infra.Path)
 
+	for name, plugin := range projectConfig.BuildPlugins {
+		if _, err := parseServiceLanguage(ServiceLanguageKind(name)); err == nil {
+			return nil, fmt.Errorf("parsing project %s: build plugin '%s' conflicts with a built-in language",
+				projectConfig.Name, name)
+		}
+
+		if plugin == nil || (plugin.Build == "" && plugin.Package == "") {
+			return nil, fmt.Errorf("parsing project %s: build plugin '%s' must specify a build or package command",
+				projectConfig.Name, name)
+		}
+	}
+
+	if projectConfig.Protection != nil {
+		if err := projectConfig.Protection.Validate(); err != nil {
+			return nil, fmt.Errorf("parsing project %s: %w", projectConfig.Name, err)
+		}
+	}
+
 	for key, svc := range projectConfig.Services {
 		svc.Name = key
 		svc.Project = &projectConfig
 		svc.EventDispatcher = ext.NewEventDispatcher[ServiceLifecycleEventArgs]()
 
 		var err error
-		svc.Language, err = parseServiceLanguage(svc.Language)
-		if err != nil {
-			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+		if _, isPlugin := projectConfig.BuildPlugins[string(svc.Language)]; !isPlugin {
+			svc.Language, err = parseServiceLanguage(svc.Language)
+			if err != nil {
+				return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+			}
 		}
 
 		svc.Host, err = parseServiceHost(svc.Host)
@@ -154,8 +174,11 @@ func Load(ctx context.Context, projectFilePath string) (*ProjectConfig, error) {
 		return nil, fmt.Errorf("reading project file: %w", err)
 	}
 
-	yaml := string(bytes)
+	return load(ctx, projectFilePath, string(bytes))
+}
 
+// load parses the content of the project file and complements it with the configuration defined next to it.
+func load(ctx context.Context, projectFilePath string, yaml string) (*ProjectConfig, error) {
 	projectConfig, err := Parse(ctx, yaml)
 	if err != nil {
 		return nil, fmt.Errorf("parsing project file: %w", err)
@@ -260,6 +283,11 @@ func SaveConfig(ctx context.Context, config config.Config, projectFilePath strin
 
 // Saves the current instance back to the azure.yaml file
 func Save(ctx context.Context, projectConfig *ProjectConfig, projectFilePath string) error {
+	if projectConfig.overlayEnvironment != "" {
+		return fmt.Errorf(
+			"the project can't be saved, it includes the overrides of environment '%s'", projectConfig.overlayEnvironment)
+	}
+
 	// We store paths at runtime with os native separators, but want to normalize paths to use forward slashes
 	// before saving so `azure.yaml` is consistent across platforms. To avoid mutating the original projectConfig,
 	// we make a copy.
@@ -10,6 +10,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/notify"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
@@ -40,6 +41,18 @@ type ProjectConfig struct {
 	Workflows         workflow.WorkflowMap       `yaml:"workflows,omitempty"`
 	Cloud             *cloud.Config              `yaml:"cloud,omitempty"`
 	Resources         map[string]*ResourceConfig `yaml:"resources,omitempty"`
+	// Custom build systems for languages azd doesn't support natively, keyed by the language name used by services
+	BuildPlugins map[string]*BuildPluginConfig `yaml:"buildPlugins,omitempty"`
+	// The webhooks, Teams or Slack channels and Event Grid topics notified when provision, deploy, down or up complete
+	Notifications []*notify.Target `yaml:"notifications,omitempty"`
+	// The environments which can't be deleted by 'azd down' or 'azd env delete' without confirmation, ex) prod*
+	Protection *ProtectionConfig `yaml:"protection,omitempty"`
+	// The overrides of the services and hooks by environment name, merged over them when the project is loaded for the
+	// environment, see LoadForEnvironment
+	Env map[string]map[string]any `yaml:"env,omitempty"`
+
+	// The name of the environment whose overrides were merged into the project, which is then never saved
+	overlayEnvironment string
 
 	*ext.EventDispatcher[ProjectLifecycleEventArgs] `yaml:"-"`
 }
@@ -57,6 +70,32 @@ type PipelineOptions struct {
 	Provider  string   `yaml:"provider"`
 	Variables []string `yaml:"variables"`
 	Secrets   []string `yaml:"secrets"`
+	// Environments are the azd environments deployed, in order, by the stages of a multi-stage pipeline.
+	// When empty, the pipeline deploys the environment used to configure it.
+	Environments []PipelineEnvironment `yaml:"environments,omitempty"`
+	// Agent is the self-hosted agent running the pipeline, set by 'azd pipeline agent bootstrap'. When empty, the
+	// pipeline runs on the hosted agents of the provider.
+	Agent *PipelineAgent `yaml:"agent,omitempty"`
+}
+
+// PipelineAgent is a self-hosted agent running the pipeline generated by 'azd pipeline config'.
+type PipelineAgent struct {
+	// The kind of agent, ex) managed-pool for a Managed DevOps Pool or container for container-based runners
+	Kind string `yaml:"kind"`
+	// The name of the agent pool for Azure Pipelines, or the label of the runners for GitHub Actions
+	Name string `yaml:"name"`
+}
+
+// PipelineEnvironment is an azd environment deployed by a stage of a multi-stage pipeline.
+type PipelineEnvironment struct {
+	// The name of the azd environment
+	Name string `yaml:"name"`
+	// The subscription the environment is deployed to. Defaults to the subscription of the azd environment.
+	Subscription string `yaml:"subscription,omitempty"`
+	// The location the environment is deployed to. Defaults to the location of the azd environment.
+	Location string `yaml:"location,omitempty"`
+	// The users who must approve the deployment of the environment before the stage runs.
+	Approvers []string `yaml:"approvers,omitempty"`
 }
 
 // Project lifecycle event arguments
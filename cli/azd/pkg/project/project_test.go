@@ -474,3 +474,79 @@ postbuild:
 		require.Equal(t, expectedHooks, project.Hooks)
 	})
 }
+
+func Test_BuildPlugins(t *testing.T) {
+	t.Run("CustomLanguage", func(t *testing.T) {
+		const testProj = `
+name: test-proj
+buildPlugins:
+  rust:
+    tools:
+      - name: cargo
+        installUrl: https://rustup.rs
+    build: cargo build --release
+    output: target/release
+services:
+  api:
+    project: src/api
+    language: rust
+    host: containerapp
+`
+		projectConfig, err := Parse(context.Background(), testProj)
+		require.NoError(t, err)
+
+		service := projectConfig.Services["api"]
+		require.Equal(t, ServiceLanguageKind("rust"), service.Language)
+
+		plugin := service.buildPlugin()
+		require.NotNil(t, plugin)
+		require.Equal(t, "cargo build --release", plugin.Build)
+		require.Equal(t, "target/release", plugin.Output)
+		require.Equal(t, []BuildPluginTool{{Name: "cargo", InstallUrl: "https://rustup.rs"}}, plugin.Tools)
+	})
+
+	t.Run("BuiltInLanguageConflict", func(t *testing.T) {
+		const testProj = `
+name: test-proj
+buildPlugins:
+  python:
+    build: make
+services:
+  api:
+    project: src/api
+    language: python
+    host: appservice
+`
+		_, err := Parse(context.Background(), testProj)
+		require.ErrorContains(t, err, "conflicts with a built-in language")
+	})
+
+	t.Run("MissingCommands", func(t *testing.T) {
+		const testProj = `
+name: test-proj
+buildPlugins:
+  deno:
+    restore: deno cache main.ts
+services:
+  api:
+    project: src/api
+    language: deno
+    host: appservice
+`
+		_, err := Parse(context.Background(), testProj)
+		require.ErrorContains(t, err, "must specify a build or package command")
+	})
+
+	t.Run("UndeclaredLanguage", func(t *testing.T) {
+		const testProj = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: deno
+    host: appservice
+`
+		_, err := Parse(context.Background(), testProj)
+		require.Error(t, err)
+	})
+}
@@ -38,58 +38,92 @@ func createDeployableZip(svc *ServiceConfig, root string) (string, error) {
 		ignorer = ig
 	}
 
-	// apply exclusions for zip deployment
-	onZip := func(src string, info os.FileInfo) (bool, error) {
-		name := info.Name()
-		isDir := info.IsDir()
-
-		// resolve symlink if needed
-		if info.Mode()&os.ModeSymlink != 0 {
-			target, err := os.Stat(src)
-			if err != nil {
-				return false, err
-			}
-			isDir = target.IsDir()
-		}
+	rules, err := newPackageRules(svc.Package)
+	if err != nil {
+		return "", err
+	}
 
+	// default exclusions and exclusions from the ignore file
+	excludedByDefault := func(src string, name string, isDir bool) bool {
 		if name == ".azure" && isDir {
-			return false, nil
+			return true
 		}
 
 		if name == ignoreFile && !isDir {
-			return false, nil
+			return true
 		}
 
 		// host specific exclusions
 		if svc.Host == AzureFunctionTarget {
 			if name == "local.settings.json" && !isDir {
-				return false, nil
+				return true
+			}
+		}
+
+		// the local settings and the design time files of the Logic Apps extension of VS Code
+		if svc.Host == LogicAppTarget {
+			if (name == "local.settings.json" && !isDir) || (name == "workflow-designtime" && isDir) {
+				return true
 			}
 		}
 
 		// apply exclusions from ignore file
 		if ignorer != nil && ignorer.Absolute(src, isDir) != nil {
-			return false, nil
+			return true
 		} else if ignorer == nil { // default exclusions without ignorefile control
 			if svc.Language == ServiceLanguagePython {
 				if isDir {
 					// check for .venv containing pyvenv.cfg
 					if _, err := os.Stat(filepath.Join(src, "pyvenv.cfg")); err == nil {
-						return false, nil
+						return true
 					}
 
 					if strings.ToLower(name) == "__pycache__" {
-						return false, nil
+						return true
 					}
 				}
 			} else if svc.Language == ServiceLanguageJavaScript || svc.Language == ServiceLanguageTypeScript {
 				if name == "node_modules" && isDir {
-					return false, nil
+					return true
 				}
 			}
 		}
 
-		return true, nil
+		return false
+	}
+
+	// excluded folders walked to find the files shipped by the include patterns of the package
+	excludedDirs := map[string]bool{}
+
+	// apply exclusions for zip deployment
+	onZip := func(src string, info os.FileInfo) (bool, error) {
+		isDir := info.IsDir()
+
+		// resolve symlink if needed
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Stat(src)
+			if err != nil {
+				return false, err
+			}
+			isDir = target.IsDir()
+		}
+
+		relativePath, err := filepath.Rel(root, src)
+		if err != nil {
+			return false, err
+		}
+
+		excluded := excludedDirs[filepath.Dir(src)] || excludedByDefault(src, info.Name(), isDir)
+		if rules.packaged(relativePath, isDir, excluded) {
+			return true, nil
+		}
+
+		if isDir && rules.hasIncludes() {
+			excludedDirs[src] = true
+			return true, nil
+		}
+
+		return false, nil
 	}
 
 	if err := rzip.CreateFromDirectory(root, zipFile, onZip); err != nil {
@@ -0,0 +1,258 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/yamlschema"
+	"github.com/azure/azure-dev/schemas"
+	"github.com/braydonk/yaml"
+)
+
+type DiagnosticSeverity string
+
+const (
+	DiagnosticError   DiagnosticSeverity = "error"
+	DiagnosticWarning DiagnosticSeverity = "warning"
+)
+
+// The kinds of the issues found in azure.yaml
+const (
+	// The file isn't valid YAML, or azd can't load it
+	DiagnosticCodeParse = "parse"
+	// A value doesn't match the schema of azure.yaml
+	DiagnosticCodeSchema = "schema"
+	// A referenced path doesn't exist
+	DiagnosticCodePath = "path"
+	// A referenced Dockerfile doesn't exist
+	DiagnosticCodeDockerfile = "dockerfile"
+	// The language of a service isn't supported by its host
+	DiagnosticCodeHostLanguage = "host-language"
+)
+
+// ProjectDiagnostic is an issue found in azure.yaml by [ValidateProject]
+type ProjectDiagnostic struct {
+	Severity DiagnosticSeverity `json:"severity"`
+	Code     string             `json:"code"`
+	Message  string             `json:"message"`
+	// The path of the value the issue is about, ex) services.api.project
+	Path string `json:"path,omitempty"`
+	// The position of the value in azure.yaml, starting at 1
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+var yamlErrorLineRegex = regexp.MustCompile(`line (\d+)`)
+
+// ValidateProject validates azure.yaml against its schema, and checks that the paths it references exist and that the
+// languages of its services are supported by their hosts. The alpha schema is used when the file references it in its
+// yaml-language-server comment.
+//
+// The diagnostics are sorted by their position in the file. An error is returned only when the file can't be read.
+func ValidateProject(ctx context.Context, projectFilePath string) ([]ProjectDiagnostic, error) {
+	content, err := os.ReadFile(projectFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		diagnostic := ProjectDiagnostic{
+			Severity: DiagnosticError,
+			Code:     DiagnosticCodeParse,
+			Message:  err.Error(),
+		}
+		if match := yamlErrorLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			diagnostic.Line, _ = strconv.Atoi(match[1])
+		}
+
+		return []ProjectDiagnostic{diagnostic}, nil
+	}
+
+	schemaContent := schemas.AzureYamlV1
+	if strings.Contains(string(content), "schemas/alpha/azure.yaml.json") {
+		schemaContent = schemas.AzureYamlAlpha
+	}
+
+	schema, err := yamlschema.Compile(schemaContent)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaErrors, err := schema.Validate(&root)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := []ProjectDiagnostic{}
+	for _, schemaErr := range schemaErrors {
+		diagnostics = append(diagnostics, ProjectDiagnostic{
+			Severity: DiagnosticError,
+			Code:     DiagnosticCodeSchema,
+			Message:  schemaErr.Message,
+			Path:     schemaErr.Path,
+			Line:     schemaErr.Line,
+			Column:   schemaErr.Column,
+		})
+	}
+
+	projectConfig, err := Parse(ctx, string(content))
+	if err != nil {
+		// The schema errors usually explain why the project can't be loaded
+		if len(diagnostics) == 0 {
+			diagnostics = append(diagnostics, ProjectDiagnostic{
+				Severity: DiagnosticError,
+				Code:     DiagnosticCodeParse,
+				Message:  err.Error(),
+			})
+		}
+
+		return diagnostics, nil
+	}
+
+	projectConfig.Path = filepath.Dir(projectFilePath)
+	diagnostics = append(diagnostics, validateProjectReferences(projectConfig, &root)...)
+
+	slices.SortStableFunc(diagnostics, func(a, b ProjectDiagnostic) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+
+	return diagnostics, nil
+}
+
+// validateProjectReferences checks the paths referenced by the project, and the host and language of its services
+func validateProjectReferences(projectConfig *ProjectConfig, root *yaml.Node) []ProjectDiagnostic {
+	diagnostics := []ProjectDiagnostic{}
+
+	if node := lookupNode(root, "infra", "path"); node != nil {
+		infraPath := projectConfig.Infra.Path
+		if !filepath.IsAbs(infraPath) {
+			infraPath = filepath.Join(projectConfig.Path, infraPath)
+		}
+
+		// The infrastructure of projects with resources is generated in memory when the path doesn't exist
+		if !pathExists(infraPath) {
+			diagnostic := newNodeDiagnostic(node, "infra.path", DiagnosticCodePath,
+				fmt.Sprintf("infra path '%s' doesn't exist", projectConfig.Infra.Path))
+			diagnostic.Severity = DiagnosticWarning
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(projectConfig.Services)) {
+		svc := projectConfig.Services[name]
+		path := "services." + name
+
+		if node := lookupNode(root, "services", name, "project"); node != nil && !pathExists(svc.Path()) {
+			diagnostics = append(diagnostics, newNodeDiagnostic(node, path+".project", DiagnosticCodePath,
+				fmt.Sprintf("project path '%s' of service '%s' doesn't exist", svc.RelativePath, name)))
+		}
+
+		if node := lookupNode(root, "services", name, "docker", "path"); node != nil && svc.Docker.Path != "" {
+			dockerfilePath := svc.Docker.Path
+			if !filepath.IsAbs(dockerfilePath) {
+				dockerfilePath = filepath.Join(svc.Path(), dockerfilePath)
+			}
+
+			if !pathExists(dockerfilePath) {
+				diagnostics = append(diagnostics, newNodeDiagnostic(node, path+".docker.path", DiagnosticCodeDockerfile,
+					fmt.Sprintf("Dockerfile '%s' of service '%s' doesn't exist", svc.Docker.Path, name)))
+			}
+		}
+
+		if node := lookupNode(root, "services", name, "docker", "context"); node != nil && svc.Docker.Context != "" {
+			contextPath := svc.Docker.Context
+			if !filepath.IsAbs(contextPath) {
+				contextPath = filepath.Join(svc.Path(), contextPath)
+			}
+
+			if !pathExists(contextPath) {
+				diagnostics = append(diagnostics, newNodeDiagnostic(node, path+".docker.context", DiagnosticCodePath,
+					fmt.Sprintf("Docker build context '%s' of service '%s' doesn't exist", svc.Docker.Context, name)))
+			}
+		}
+
+		if message := hostLanguageMismatch(svc); message != "" {
+			node := lookupNode(root, "services", name, "language")
+			if node == nil {
+				node = lookupNode(root, "services", name)
+			}
+
+			diagnostics = append(diagnostics, newNodeDiagnostic(node, path, DiagnosticCodeHostLanguage, message))
+		}
+	}
+
+	return diagnostics
+}
+
+// hostLanguageMismatch returns why the language of the service isn't supported by its host, if it isn't
+func hostLanguageMismatch(svc *ServiceConfig) string {
+	switch {
+	case svc.Language == ServiceLanguageDocker && !svc.Host.RequiresContainer() && svc.Host != AppServiceTarget:
+		return fmt.Sprintf("service '%s' is built with Docker, which isn't supported by host '%s'", svc.Name, svc.Host)
+	case svc.Host == SpringAppTarget && svc.Language != ServiceLanguageJava:
+		return fmt.Sprintf("host '%s' of service '%s' only supports language '%s'",
+			svc.Host, svc.Name, ServiceLanguageJava)
+	case !svc.Image.Empty() && !svc.Host.RequiresContainer():
+		return fmt.Sprintf("service '%s' uses a container image, which isn't supported by host '%s'", svc.Name, svc.Host)
+	}
+
+	return ""
+}
+
+// lookupNode returns the value of the nested keys of the mapping, or nil when a key isn't found
+func lookupNode(node *yaml.Node, keys ...string) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var value *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				value = node.Content[i+1]
+				break
+			}
+		}
+		node = value
+	}
+
+	return node
+}
+
+func newNodeDiagnostic(node *yaml.Node, path string, code string, message string) ProjectDiagnostic {
+	diagnostic := ProjectDiagnostic{
+		Severity: DiagnosticError,
+		Code:     code,
+		Message:  message,
+		Path:     path,
+	}
+	if node != nil {
+		diagnostic.Line = node.Line
+		diagnostic.Column = node.Column
+	}
+
+	return diagnostic
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeProjectFile(t *testing.T, content string) string {
+	projectDir := t.TempDir()
+	projectFilePath := filepath.Join(projectDir, "azure.yaml")
+	require.NoError(t, os.WriteFile(projectFilePath, []byte(content), 0600))
+
+	return projectFilePath
+}
+
+func Test_ValidateProject(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		projectFilePath := writeProjectFile(t, `name: app
+services:
+  api:
+    project: .
+    host: containerapp
+    language: python
+    docker:
+      path: Dockerfile
+`)
+		require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(projectFilePath), "Dockerfile"), nil, 0600))
+
+		diagnostics, err := ValidateProject(context.Background(), projectFilePath)
+		require.NoError(t, err)
+		require.Empty(t, diagnostics)
+	})
+
+	t.Run("SchemaErrors", func(t *testing.T) {
+		projectFilePath := writeProjectFile(t, `name: app
+services:
+  api:
+    project: .
+    host: containerapps
+    language: python
+    replicas: 2
+`)
+
+		diagnostics, err := ValidateProject(context.Background(), projectFilePath)
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 2)
+
+		require.Equal(t, DiagnosticCodeSchema, diagnostics[0].Code)
+		require.Equal(t, "services.api.host", diagnostics[0].Path)
+		require.Equal(t, 5, diagnostics[0].Line)
+		require.Equal(t, 11, diagnostics[0].Column)
+
+		require.Equal(t, ProjectDiagnostic{
+			Severity: DiagnosticError,
+			Code:     DiagnosticCodeSchema,
+			Message:  "property 'replicas' is not allowed",
+			Path:     "services.api.replicas",
+			Line:     7,
+			Column:   5,
+		}, diagnostics[1])
+	})
+
+	t.Run("References", func(t *testing.T) {
+		projectFilePath := writeProjectFile(t, `name: app
+infra:
+  provider: bicep
+  path: missing-infra
+services:
+  api:
+    project: ./missing
+    host: containerapp
+    language: python
+  web:
+    project: .
+    host: containerapp
+    language: docker
+    docker:
+      path: web.Dockerfile
+  spring:
+    project: .
+    host: springapp
+    language: js
+`)
+
+		diagnostics, err := ValidateProject(context.Background(), projectFilePath)
+		require.NoError(t, err)
+		require.Equal(t, []ProjectDiagnostic{
+			{
+				Severity: DiagnosticWarning,
+				Code:     DiagnosticCodePath,
+				Message:  "infra path 'missing-infra' doesn't exist",
+				Path:     "infra.path",
+				Line:     4,
+				Column:   9,
+			},
+			{
+				Severity: DiagnosticError,
+				Code:     DiagnosticCodePath,
+				Message:  "project path './missing' of service 'api' doesn't exist",
+				Path:     "services.api.project",
+				Line:     7,
+				Column:   14,
+			},
+			{
+				Severity: DiagnosticError,
+				Code:     DiagnosticCodeDockerfile,
+				Message:  "Dockerfile 'web.Dockerfile' of service 'web' doesn't exist",
+				Path:     "services.web.docker.path",
+				Line:     15,
+				Column:   13,
+			},
+			{
+				Severity: DiagnosticError,
+				Code:     DiagnosticCodeHostLanguage,
+				Message:  "host 'springapp' of service 'spring' only supports language 'java'",
+				Path:     "services.spring",
+				Line:     19,
+				Column:   15,
+			},
+		}, diagnostics)
+	})
+
+	t.Run("InvalidYaml", func(t *testing.T) {
+		projectFilePath := writeProjectFile(t, "name: app\nservices:\n  api: [\n")
+
+		diagnostics, err := ValidateProject(context.Background(), projectFilePath)
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 1)
+		require.Equal(t, DiagnosticCodeParse, diagnostics[0].Code)
+		require.Equal(t, 3, diagnostics[0].Line)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		_, err := ValidateProject(context.Background(), filepath.Join(t.TempDir(), "azure.yaml"))
+		require.Error(t, err)
+	})
+}
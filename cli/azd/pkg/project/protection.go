@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// ProtectionConfig is the protection of shared environments against accidental deletion.
+type ProtectionConfig struct {
+	// The names or patterns of the names of the protected environments, ex) prod*
+	Environments []string `yaml:"environments,omitempty"`
+}
+
+// Validate checks the patterns of the protected environments are valid.
+func (p *ProtectionConfig) Validate() error {
+	for _, pattern := range p.Environments {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid protected environment pattern '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// EnvironmentProtection returns why the environment is protected against deletion, or an empty string when it isn't.
+// An environment is protected when it's locked, or when its name matches a pattern of the protection of the project.
+func EnvironmentProtection(projectConfig *ProjectConfig, env *environment.Environment) string {
+	if env.IsLocked() {
+		return fmt.Sprintf("environment '%s' is locked", env.Name())
+	}
+
+	if projectConfig == nil || projectConfig.Protection == nil {
+		return ""
+	}
+
+	for _, pattern := range projectConfig.Protection.Environments {
+		// Environment names are case insensitive, like the names of the resource groups they're deployed to
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(env.Name())); matched {
+			return fmt.Sprintf("environment '%s' matches the protected environments '%s' of the project", env.Name(), pattern)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentProtection(t *testing.T) {
+	projectConfig := &ProjectConfig{
+		Protection: &ProtectionConfig{
+			Environments: []string{"prod*", "shared"},
+		},
+	}
+
+	require.Empty(t, EnvironmentProtection(projectConfig, environment.New("dev")))
+	require.Empty(t, EnvironmentProtection(nil, environment.New("prod")))
+	require.Contains(t, EnvironmentProtection(projectConfig, environment.New("prod-eastus")), "'prod*'")
+	require.Contains(t, EnvironmentProtection(projectConfig, environment.New("Shared")), "'shared'")
+
+	env := environment.New("dev")
+	require.NoError(t, env.SetLocked(true))
+	require.True(t, env.IsLocked())
+	require.Equal(t, "environment 'dev' is locked", EnvironmentProtection(projectConfig, env))
+
+	require.NoError(t, env.SetLocked(false))
+	require.False(t, env.IsLocked())
+	require.Empty(t, EnvironmentProtection(projectConfig, env))
+}
+
+func TestParseProtection(t *testing.T) {
+	_, err := Parse(context.Background(), `
+name: test
+protection:
+  environments:
+    - "prod["
+`)
+	require.ErrorContains(t, err, "invalid protected environment pattern 'prod['")
+
+	projectConfig, err := Parse(context.Background(), `
+name: test
+protection:
+  environments:
+    - prod*
+`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod*"}, projectConfig.Protection.Environments)
+}
@@ -207,12 +207,7 @@ func (rm *resourceManager) GetTargetResource(
 	subscriptionId string,
 	serviceConfig *ServiceConfig,
 ) (*environment.TargetResource, error) {
-	resourceGroupTemplate := serviceConfig.ResourceGroupName
-	if resourceGroupTemplate.Empty() {
-		resourceGroupTemplate = serviceConfig.Project.ResourceGroupName
-	}
-
-	resourceGroupName, err := rm.GetResourceGroupName(ctx, subscriptionId, resourceGroupTemplate)
+	resourceGroupName, err := rm.GetResourceGroupName(ctx, subscriptionId, serviceConfig.ResourceGroupTemplate())
 	if err != nil {
 		return nil, err
 	}
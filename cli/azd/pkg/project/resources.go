@@ -5,7 +5,10 @@ package project
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/braydonk/yaml"
 )
 
@@ -145,6 +148,24 @@ type ResourceConfig struct {
 	IncludeName bool `yaml:"-"`
 }
 
+// ExistingResourceIds returns the ids of the existing resources of the project, found from their
+// AZURE_RESOURCE_<NAME>_ID environment variables. These resources are referenced by the project but not owned by it, so
+// they're never deleted.
+func ExistingResourceIds(resources map[string]*ResourceConfig, getenv func(string) string) []string {
+	resourceIds := []string{}
+	for _, name := range slices.Sorted(maps.Keys(resources)) {
+		if !resources[name].Existing {
+			continue
+		}
+
+		if resourceId := getenv(infra.ResourceIdName(name)); resourceId != "" {
+			resourceIds = append(resourceIds, resourceId)
+		}
+	}
+
+	return resourceIds
+}
+
 func (r *ResourceConfig) MarshalYAML() (interface{}, error) {
 	type rawResourceConfig ResourceConfig
 	raw := rawResourceConfig(*r)
@@ -306,6 +327,7 @@ type ServiceEnvVar struct {
 
 type AIModelProps struct {
 	Model AIModelPropsModel `yaml:"model,omitempty"`
+	Sku   AIModelPropsSku   `yaml:"sku,omitempty"`
 }
 
 type AIModelPropsModel struct {
@@ -313,6 +335,12 @@ type AIModelPropsModel struct {
 	Version string `yaml:"version,omitempty"`
 }
 
+// AIModelPropsSku is the deployment SKU of the model. When not set, a 'Standard' deployment with a capacity of 20 is used.
+type AIModelPropsSku struct {
+	Name     string `yaml:"name,omitempty"`
+	Capacity int32  `yaml:"capacity,omitempty"`
+}
+
 type CosmosDBProps struct {
 	Containers []CosmosDBContainerProps `yaml:"containers,omitempty"`
 }
@@ -274,12 +274,24 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				return nil, fmt.Errorf("resources.%s.version is required", res.Name)
 			}
 
+			sku := scaffold.AIModelSku{
+				Name:     props.Sku.Name,
+				Capacity: props.Sku.Capacity,
+			}
+			if sku.Name == "" {
+				sku.Name = "Standard"
+			}
+			if sku.Capacity == 0 {
+				sku.Capacity = 20
+			}
+
 			infraSpec.AIModels = append(infraSpec.AIModels, scaffold.AIModel{
 				Name: res.Name,
 				Model: scaffold.AIModelModel{
 					Name:    props.Model.Name,
 					Version: props.Model.Version,
 				},
+				Sku: sku,
 			})
 		case ResourceTypeMessagingEventHubs:
 			if infraSpec.EventHubs != nil {
@@ -496,7 +508,12 @@ func mapHostUses(
 				return emitVariable(emitEnv, val, results)
 			}
 
-			results, err := scaffold.EmitBicep(resourceMeta.Variables, emitter)
+			variables, err := scaffold.ExistingVariables(resourceMeta.Variables)
+			if err != nil {
+				return fmt.Errorf("emitting bicep bindings for '%s': %w", useRes.Name, err)
+			}
+
+			results, err := scaffold.EmitBicep(variables, emitter)
 			if err != nil {
 				return fmt.Errorf("emitting bicep bindings for '%s': %w", useRes.Name, err)
 			}
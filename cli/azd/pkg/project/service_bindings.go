@@ -0,0 +1,488 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/yamlnode"
+	"github.com/tidwall/gjson"
+)
+
+// ServiceBinding is the connection of a service to another service or resource of the project, listed in the 'uses'
+// of the service.
+type ServiceBinding struct {
+	// The name of the service or resource used
+	Name string
+	// The connection environment variables, ex) STORAGE_BLOB_ENDPOINT
+	Env map[string]string
+	// The names of the environment variables which values are secrets, ex) REDIS_PASSWORD
+	Secrets []string
+	// The resource used, or its parent the variables are evaluated on, nil for a service
+	ResourceId *arm.ResourceID
+	// The roles assigned to the managed identities of the service on the resource used
+	Roles []scaffold.RoleAssignment
+}
+
+// ServiceBindings resolves the 'uses' of a service into connection environment variables, and assigns roles on the
+// resources used to the managed identities of the service when it's deployed.
+type ServiceBindings struct {
+	env             *environment.Environment
+	resourceManager ResourceManager
+	resourceService *azapi.ResourceService
+	kvService       keyvault.KeyVaultService
+	entraIdService  entraid.EntraIdService
+	// The service manager is resolved lazily since it depends on the service targets, which depend on the bindings
+	serviceLocator ioc.ServiceLocator
+}
+
+// NewServiceBindings creates a new ServiceBindings
+func NewServiceBindings(
+	env *environment.Environment,
+	resourceManager ResourceManager,
+	resourceService *azapi.ResourceService,
+	kvService keyvault.KeyVaultService,
+	entraIdService entraid.EntraIdService,
+	serviceLocator ioc.ServiceLocator,
+) *ServiceBindings {
+	return &ServiceBindings{
+		env:             env,
+		resourceManager: resourceManager,
+		resourceService: resourceService,
+		kvService:       kvService,
+		entraIdService:  entraIdService,
+		serviceLocator:  serviceLocator,
+	}
+}
+
+// Bind resolves the bindings of the service, and assigns the roles of the resources used to the managed identities of
+// the resource the service is deployed to. The apiVersion is the version used to get the resource.
+func (sb *ServiceBindings) Bind(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResourceId string,
+	apiVersion string,
+) ([]*ServiceBinding, error) {
+	if len(serviceConfig.Uses) == 0 {
+		return nil, nil
+	}
+
+	bindings, err := sb.Resolve(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(bindings, func(binding *ServiceBinding) bool { return len(binding.Roles) > 0 }) {
+		return bindings, nil
+	}
+
+	principalIds, err := sb.identityPrincipals(ctx, targetResourceId, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(principalIds) == 0 {
+		return nil, fmt.Errorf(
+			"service %s uses resources requiring a role assignment, but the resource it's deployed to doesn't have "+
+				"a managed identity",
+			serviceConfig.Name,
+		)
+	}
+
+	for _, binding := range bindings {
+		for _, role := range binding.Roles {
+			scope := binding.ResourceId.String()
+			if role.Scope == scaffold.RoleAssignmentScopeGroup {
+				scope = azure.ResourceGroupRID(binding.ResourceId.SubscriptionID, binding.ResourceId.ResourceGroupName)
+			}
+
+			for _, principalId := range principalIds {
+				err := sb.entraIdService.CreateRbac(
+					ctx,
+					binding.ResourceId.SubscriptionID,
+					scope,
+					"/providers/Microsoft.Authorization/roleDefinitions/"+role.RoleDefinitionId,
+					principalId,
+				)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"assigning role '%s' on '%s' to service '%s': %w",
+						role.RoleDefinitionName, binding.Name, serviceConfig.Name, err)
+				}
+			}
+		}
+	}
+
+	return bindings, nil
+}
+
+// Resolve resolves the bindings of the service to the services and resources it uses.
+func (sb *ServiceBindings) Resolve(ctx context.Context, serviceConfig *ServiceConfig) ([]*ServiceBinding, error) {
+	bindings := []*ServiceBinding{}
+	names := map[string]string{}
+
+	for _, use := range serviceConfig.Uses {
+		var binding *ServiceBinding
+		var err error
+
+		if usedService, has := serviceConfig.Project.Services[use]; has {
+			if use == serviceConfig.Name {
+				return nil, fmt.Errorf("service %s uses itself", serviceConfig.Name)
+			}
+
+			binding, err = sb.resolveService(ctx, usedService)
+		} else if usedResource, has := serviceConfig.Project.Resources[use]; has {
+			binding, err = sb.resolveResource(ctx, usedResource)
+		} else {
+			return nil, fmt.Errorf("service %s uses %s, which does not exist", serviceConfig.Name, use)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("resolving '%s' used by service %s: %w", use, serviceConfig.Name, err)
+		}
+
+		for name := range binding.Env {
+			if other, has := names[name]; has {
+				return nil, fmt.Errorf(
+					"service %s uses %s and %s, which both set the environment variable %s",
+					serviceConfig.Name, other, use, name)
+			}
+
+			names[name] = use
+		}
+
+		bindings = append(bindings, binding)
+	}
+
+	return bindings, nil
+}
+
+// resolveService binds to the first endpoint of a service, as <NAME>_BASE_URL
+func (sb *ServiceBindings) resolveService(ctx context.Context, serviceConfig *ServiceConfig) (*ServiceBinding, error) {
+	var serviceManager ServiceManager
+	if err := sb.serviceLocator.Resolve(&serviceManager); err != nil {
+		return nil, err
+	}
+
+	serviceTarget, err := serviceManager.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	targetResource, err := sb.resourceManager.GetTargetResource(ctx, sb.env.GetSubscriptionId(), serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := serviceTarget.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("service %s doesn't have any endpoint", serviceConfig.Name)
+	}
+
+	// Some endpoints are followed by a description, ex) http://10.0.0.1/ (Service, Type: LoadBalancer)
+	baseUrl, _, _ := strings.Cut(endpoints[0], " ")
+
+	return &ServiceBinding{
+		Name: serviceConfig.Name,
+		Env: map[string]string{
+			scaffold.EnvVarName(environment.Key(serviceConfig.Name), "baseUrl"): strings.TrimSuffix(baseUrl, "/"),
+		},
+	}, nil
+}
+
+// resolveResource binds to a resource of the project, with the variables and role assignments of its resource type.
+// The resource is found from its AZURE_RESOURCE_<NAME>_ID environment variable.
+func (sb *ServiceBindings) resolveResource(ctx context.Context, resource *ResourceConfig) (*ServiceBinding, error) {
+	resourceMeta, has := scaffold.ResourceMetaFromType(resource.Type.AzureResourceType())
+	if !has {
+		return nil, fmt.Errorf("resource type '%s' is not supported", resource.Type)
+	}
+
+	resourceId, err := infra.ResourceId(resource.Name, sb.env)
+	if err != nil {
+		return nil, fmt.Errorf("finding the resource, run 'azd provision' first: %w", err)
+	}
+
+	// The variables of some resources are evaluated on their parent, ex) the account of an AI model deployment
+	evalId := resourceId
+	for resourceMeta.ParentForEval != "" && evalId.ResourceType.String() != resourceMeta.ParentForEval {
+		if evalId.Parent == nil {
+			return nil, fmt.Errorf("'%s' was not found as a parent of '%s'", resourceMeta.ParentForEval, resourceId)
+		}
+
+		evalId = evalId.Parent
+	}
+
+	armResource, err := sb.resourceService.GetRawResource(ctx, *evalId, resourceMeta.ApiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource %s: %w", resourceId, err)
+	}
+
+	specResource := *resource
+	specResource.IncludeName = true
+	resourceSpec, err := yamlnode.Encode(&specResource)
+	if err != nil {
+		return nil, fmt.Errorf("encoding resource spec: %w", err)
+	}
+
+	variables := resourceMeta.Variables
+	if resource.Existing {
+		variables, err = scaffold.ExistingVariables(variables)
+		if err != nil {
+			return nil, fmt.Errorf("expanding variables: %w", err)
+		}
+	}
+
+	secretValues := []string{}
+	values, err := scaffold.Eval(variables, scaffold.EvalEnv{
+		ResourceSpec: resourceSpec,
+		ArmResource:  armResource,
+		VaultSecret: func(name string) (string, error) {
+			secret, err := sb.kvService.GetKeyVaultSecret(
+				ctx, resourceId.SubscriptionID, infra.KeyVaultName(sb.env), name)
+			if err != nil {
+				return "", err
+			}
+
+			secretValues = append(secretValues, secret.Value)
+			output.AddSecret(secret.Value)
+			return secret.Value, nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expanding variables: %w", err)
+	}
+
+	// Same as the environment variables of the resource in the generated infrastructure
+	prefix := resourceMeta.StandardVarPrefix
+	if strings.Contains(prefix, "${") {
+		// The variables of hosts are prefixed by their name
+		prefix = environment.Key(resource.Name)
+	} else if resource.Existing {
+		prefix += "_" + environment.Key(resource.Name)
+	}
+	env := scaffold.EnvVars(prefix, values)
+
+	// Variables composed from a secret, ex) a connection url including a password, are secrets too
+	secrets := []string{}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if slices.ContainsFunc(secretValues, func(secret string) bool {
+			return secret != "" && strings.Contains(env[name], secret)
+		}) {
+			secrets = append(secrets, name)
+		}
+	}
+
+	return &ServiceBinding{
+		Name:       resource.Name,
+		Env:        env,
+		Secrets:    secrets,
+		ResourceId: evalId,
+		Roles:      resourceMeta.RoleAssignments.Write,
+	}, nil
+}
+
+// identityPrincipals returns the principal ids of the managed identities of a resource: its system assigned identity,
+// its user assigned identities, and the kubelet identity of an AKS cluster.
+func (sb *ServiceBindings) identityPrincipals(ctx context.Context, resourceId string, apiVersion string) ([]string, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	armResource, err := sb.resourceService.GetRawResource(ctx, *id, apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource %s: %w", resourceId, err)
+	}
+
+	principalIds := []string{}
+	for _, path := range []string{
+		"identity.principalId",
+		"identity.userAssignedIdentities.*.principalId",
+		"properties.identityProfile.kubeletidentity.objectId",
+	} {
+		result := gjson.Get(armResource, path)
+		if result.IsArray() {
+			for _, item := range result.Array() {
+				principalIds = append(principalIds, item.String())
+			}
+		} else if result.String() != "" {
+			principalIds = append(principalIds, result.String())
+		}
+	}
+
+	return principalIds, nil
+}
+
+// AuthorizeKeyVaultSecrets grants the managed identities referencing Key Vault secrets read access to the secrets of the
+// vaults, on the resource the service is deployed to. Vaults using access policies get an access policy for the identity,
+// and the Key Vault Secrets User role is assigned to the identity on vaults using Azure RBAC. The apiVersion is the
+// version used to get the resource.
+func (sb *ServiceBindings) AuthorizeKeyVaultSecrets(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResourceId string,
+	apiVersion string,
+	references []containerapps.KeyVaultSecretReference,
+) error {
+	targetId, err := arm.ParseResourceID(targetResourceId)
+	if err != nil {
+		return err
+	}
+
+	var vaults []keyvault.Vault
+	authorized := map[string]bool{}
+	for _, reference := range references {
+		vaultName, err := keyVaultNameFromSecretUrl(reference.Url)
+		if err != nil {
+			return err
+		}
+
+		key := strings.ToLower(vaultName + "|" + reference.Identity)
+		if authorized[key] {
+			continue
+		}
+		authorized[key] = true
+
+		if vaults == nil {
+			vaults, err = sb.kvService.ListSubscriptionVaults(ctx, targetId.SubscriptionID)
+			if err != nil {
+				return err
+			}
+		}
+
+		index := slices.IndexFunc(vaults, func(vault keyvault.Vault) bool {
+			return strings.EqualFold(vault.Name, vaultName)
+		})
+		if index < 0 {
+			log.Printf(
+				"key vault '%s' was not found in subscription '%s', access to its secrets must be granted manually",
+				vaultName, targetId.SubscriptionID)
+			continue
+		}
+
+		vaultId, err := arm.ParseResourceID(vaults[index].Id)
+		if err != nil {
+			return err
+		}
+
+		principalId, err := sb.identityPrincipal(ctx, targetResourceId, apiVersion, reference.Identity)
+		if err != nil {
+			return err
+		}
+
+		hasAccessPolicies, err := sb.kvService.EnsureSecretsAccessPolicy(
+			ctx, vaultId.SubscriptionID, vaultId.ResourceGroupName, vaultId.Name, principalId)
+		if err != nil {
+			return fmt.Errorf("granting service '%s' access to key vault '%s': %w", serviceConfig.Name, vaultName, err)
+		}
+
+		if hasAccessPolicies {
+			continue
+		}
+
+		err = sb.entraIdService.CreateRbac(
+			ctx, vaultId.SubscriptionID, vaultId.String(), keyvault.RoleIdKeyVaultSecretsUser, principalId)
+		if err != nil {
+			return fmt.Errorf(
+				"assigning role 'Key Vault Secrets User' on key vault '%s' to service '%s': %w",
+				vaultName, serviceConfig.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// identityPrincipal returns the principal id of a managed identity of a resource: its system assigned identity for
+// 'system', or else the user assigned identity with the resource id.
+func (sb *ServiceBindings) identityPrincipal(
+	ctx context.Context,
+	resourceId string,
+	apiVersion string,
+	identity string,
+) (string, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return "", err
+	}
+
+	armResource, err := sb.resourceService.GetRawResource(ctx, *id, apiVersion)
+	if err != nil {
+		return "", fmt.Errorf("getting resource %s: %w", resourceId, err)
+	}
+
+	if identity == "" || strings.EqualFold(identity, "system") {
+		principalId := gjson.Get(armResource, "identity.principalId").String()
+		if principalId == "" {
+			return "", fmt.Errorf("resource '%s' doesn't have a system assigned identity", id.Name)
+		}
+
+		return principalId, nil
+	}
+
+	principalId := ""
+	gjson.Get(armResource, "identity.userAssignedIdentities").ForEach(func(key, value gjson.Result) bool {
+		if strings.EqualFold(key.String(), identity) {
+			principalId = value.Get("principalId").String()
+			return false
+		}
+
+		return true
+	})
+
+	if principalId == "" {
+		return "", fmt.Errorf("resource '%s' doesn't have the user assigned identity '%s'", id.Name, identity)
+	}
+
+	return principalId, nil
+}
+
+// keyVaultNameFromSecretUrl returns the name of the vault of a Key Vault secret url, ex) https://vault.vault.azure.net/...
+func keyVaultNameFromSecretUrl(secretUrl string) (string, error) {
+	u, err := url.Parse(secretUrl)
+	if err != nil || u.Host == "" || !strings.HasPrefix(u.Path, "/secrets/") {
+		return "", fmt.Errorf("'%s' is not the url of a Key Vault secret", secretUrl)
+	}
+
+	name, _, _ := strings.Cut(u.Hostname(), ".")
+	return name, nil
+}
+
+// bindingsEnv returns the environment variables of the bindings, split between the ones which are secrets and the others.
+func bindingsEnv(bindings []*ServiceBinding) (env map[string]string, secretEnv map[string]string) {
+	env = map[string]string{}
+	secretEnv = map[string]string{}
+	for _, binding := range bindings {
+		for name, value := range binding.Env {
+			if slices.Contains(binding.Secrets, name) {
+				secretEnv[name] = value
+			} else {
+				env[name] = value
+			}
+		}
+	}
+
+	return env, secretEnv
+}
@@ -0,0 +1,290 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceBindings_Bind(t *testing.T) {
+	storageId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.Storage/storageAccounts/stdev"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/storageAccounts/stdev")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"id":   storageId,
+			"name": "stdev",
+			"properties": map[string]any{
+				"primaryEndpoints": map[string]any{
+					"blob": "https://stdev.blob.core.windows.net/",
+				},
+			},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/containerApps/ca-api")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"name": "ca-api",
+			"identity": map[string]any{
+				"type":        "SystemAssigned",
+				"principalId": "PRINCIPAL_ID",
+			},
+		})
+	})
+
+	roleAssignments := []map[string]any{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "/roleAssignments/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		var roleAssignment map[string]any
+		require.NoError(t, json.Unmarshal(body, &roleAssignment))
+		roleAssignment["scope"] = request.URL.Path[:strings.Index(request.URL.Path, "/providers/Microsoft.Authorization")]
+		roleAssignments = append(roleAssignments, roleAssignment)
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, map[string]any{})
+	})
+
+	env := environment.NewWithValues("dev", map[string]string{
+		"AZURE_SUBSCRIPTION_ID":      "SUBSCRIPTION_ID",
+		"AZURE_RESOURCE_STORAGE_ID":  storageId,
+		"AZURE_RESOURCE_GROUP":       "RESOURCE_GROUP",
+		"SERVICE_API_RESOURCE_NAME":  "ca-api",
+		"SERVICE_API_RESOURCE_GROUP": "RESOURCE_GROUP",
+	})
+
+	resourceService := azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	bindings := NewServiceBindings(
+		env,
+		nil,
+		resourceService,
+		nil,
+		entraid.NewEntraIdService(
+			mockContext.SubscriptionCredentialProvider,
+			mockContext.ArmClientOptions,
+			mockContext.CoreClientOptions,
+		),
+		mockContext.Container,
+	)
+
+	projectConfig := &ProjectConfig{
+		Name: "app",
+		Resources: map[string]*ResourceConfig{
+			"storage": {Name: "storage", Type: ResourceTypeStorage},
+		},
+	}
+	serviceConfig := &ServiceConfig{
+		Project: projectConfig,
+		Name:    "api",
+		Host:    ContainerAppTarget,
+		Uses:    []string{"storage"},
+	}
+	projectConfig.Services = map[string]*ServiceConfig{"api": serviceConfig}
+
+	result, err := bindings.Bind(
+		*mockContext.Context,
+		serviceConfig,
+		azure.ContainerAppRID("SUBSCRIPTION_ID", "RESOURCE_GROUP", "ca-api"),
+		containerAppApiVersion,
+	)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, map[string]string{
+		"AZURE_STORAGE_ACCOUNT_NAME":  "stdev",
+		"AZURE_STORAGE_BLOB_ENDPOINT": "https://stdev.blob.core.windows.net/",
+	}, result[0].Env)
+	require.Empty(t, result[0].Secrets)
+
+	// The Storage Blob Data Contributor role is assigned to the identity of the container app on the storage account
+	require.Len(t, roleAssignments, 1)
+	properties := roleAssignments[0]["properties"].(map[string]any)
+	require.Equal(t, "PRINCIPAL_ID", properties["principalId"])
+	require.Equal(t,
+		"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/roleDefinitions/"+
+			"ba92f5b4-2d11-453d-a403-e96b0029c9fe",
+		properties["roleDefinitionId"])
+	require.Equal(t, storageId, roleAssignments[0]["scope"])
+}
+
+type fakeBindingsKeyVaultService struct {
+	keyvault.KeyVaultService
+	vaults            []keyvault.Vault
+	accessPolicyVault string
+	accessPolicies    []string
+}
+
+func (f *fakeBindingsKeyVaultService) ListSubscriptionVaults(
+	ctx context.Context,
+	subscriptionId string,
+) ([]keyvault.Vault, error) {
+	return f.vaults, nil
+}
+
+func (f *fakeBindingsKeyVaultService) EnsureSecretsAccessPolicy(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	vaultName string,
+	principalId string,
+) (bool, error) {
+	if vaultName != f.accessPolicyVault {
+		return false, nil
+	}
+
+	f.accessPolicies = append(f.accessPolicies, vaultName+"/"+principalId)
+	return true, nil
+}
+
+func Test_ServiceBindings_AuthorizeKeyVaultSecrets(t *testing.T) {
+	identityId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.ManagedIdentity/userAssignedIdentities/id-api"
+	rbacVaultId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.KeyVault/vaults/kv-rbac"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/containerApps/ca-api")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"name": "ca-api",
+			"identity": map[string]any{
+				"type":        "SystemAssigned,UserAssigned",
+				"principalId": "SYSTEM_PRINCIPAL_ID",
+				"userAssignedIdentities": map[string]any{
+					strings.ToLower(identityId): map[string]any{"principalId": "USER_PRINCIPAL_ID"},
+				},
+			},
+		})
+	})
+
+	roleAssignments := []map[string]any{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "/roleAssignments/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		var roleAssignment map[string]any
+		require.NoError(t, json.Unmarshal(body, &roleAssignment))
+		roleAssignment["scope"] = request.URL.Path[:strings.Index(request.URL.Path, "/providers/Microsoft.Authorization")]
+		roleAssignments = append(roleAssignments, roleAssignment)
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, map[string]any{})
+	})
+
+	kvService := &fakeBindingsKeyVaultService{
+		vaults: []keyvault.Vault{
+			{Id: rbacVaultId, Name: "kv-rbac"},
+			{
+				Id: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+					"Microsoft.KeyVault/vaults/kv-policies",
+				Name: "kv-policies",
+			},
+		},
+		accessPolicyVault: "kv-policies",
+	}
+
+	bindings := NewServiceBindings(
+		environment.New("dev"),
+		nil,
+		azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		kvService,
+		entraid.NewEntraIdService(
+			mockContext.SubscriptionCredentialProvider,
+			mockContext.ArmClientOptions,
+			mockContext.CoreClientOptions,
+		),
+		mockContext.Container,
+	)
+
+	err := bindings.AuthorizeKeyVaultSecrets(
+		*mockContext.Context,
+		&ServiceConfig{Name: "api"},
+		azure.ContainerAppRID("SUBSCRIPTION_ID", "RESOURCE_GROUP", "ca-api"),
+		containerAppApiVersion,
+		[]containerapps.KeyVaultSecretReference{
+			{Url: "https://kv-rbac.vault.azure.net/secrets/db-password", Identity: identityId},
+			{Url: "https://kv-rbac.vault.azure.net/secrets/api-key", Identity: identityId},
+			{Url: "https://kv-policies.vault.azure.net/secrets/token", Identity: "system"},
+			{Url: "https://kv-other.vault.azure.net/secrets/token", Identity: "system"},
+		},
+	)
+	require.NoError(t, err)
+
+	// Vaults using access policies get an access policy for the identity
+	require.Equal(t, []string{"kv-policies/SYSTEM_PRINCIPAL_ID"}, kvService.accessPolicies)
+
+	// The Key Vault Secrets User role is assigned once on vaults using Azure RBAC
+	require.Len(t, roleAssignments, 1)
+	properties := roleAssignments[0]["properties"].(map[string]any)
+	require.Equal(t, "USER_PRINCIPAL_ID", properties["principalId"])
+	require.Equal(t,
+		"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/roleDefinitions/"+
+			"4633458b-17de-408a-b874-0445c86b69e6",
+		properties["roleDefinitionId"])
+	require.Equal(t, rbacVaultId, roleAssignments[0]["scope"])
+}
+
+func Test_ServiceBindings_Resolve_Errors(t *testing.T) {
+	projectConfig := &ProjectConfig{Name: "app"}
+	api := &ServiceConfig{Project: projectConfig, Name: "api"}
+	projectConfig.Services = map[string]*ServiceConfig{"api": api}
+
+	bindings := NewServiceBindings(environment.New("dev"), nil, nil, nil, nil, nil)
+
+	t.Run("NotFound", func(t *testing.T) {
+		api.Uses = []string{"db"}
+		_, err := bindings.Resolve(context.Background(), api)
+		require.ErrorContains(t, err, "service api uses db, which does not exist")
+	})
+
+	t.Run("Itself", func(t *testing.T) {
+		api.Uses = []string{"api"}
+		_, err := bindings.Resolve(context.Background(), api)
+		require.ErrorContains(t, err, "service api uses itself")
+	})
+}
+
+func Test_BindingsEnv(t *testing.T) {
+	env, secretEnv := bindingsEnv([]*ServiceBinding{
+		{
+			Name: "redis",
+			Env: map[string]string{
+				"REDIS_HOST":     "redis.example.com",
+				"REDIS_PASSWORD": "password",
+			},
+			Secrets: []string{"REDIS_PASSWORD"},
+		},
+		{
+			Name: "web",
+			Env:  map[string]string{"WEB_BASE_URL": "https://web.example.com"},
+		},
+	})
+
+	require.Equal(t, map[string]string{
+		"REDIS_HOST":   "redis.example.com",
+		"WEB_BASE_URL": "https://web.example.com",
+	}, env)
+	require.Equal(t, map[string]string{"REDIS_PASSWORD": "password"}, secretEnv)
+}
@@ -35,10 +35,31 @@ type ServiceConfig struct {
 	Image osutil.ExpandableString `yaml:"image,omitempty"`
 	// The optional docker options for configuring the output image
 	Docker DockerProjectOptions `yaml:"docker,omitempty"`
+	// The optional rules selecting the files shipped in the zip package or the docker build context of the service
+	Package PackageFilesOptions `yaml:"package,omitempty"`
 	// The optional K8S / AKS options
 	K8s AksOptions `yaml:"k8s,omitempty"`
 	// The optional Azure Spring Apps options
 	Spring SpringOptions `yaml:"spring,omitempty"`
+	// The optional Azure Container Apps options
+	ContainerApp ContainerAppOptions `yaml:"containerApp,omitempty"`
+	// The optional Azure App Service options
+	AppService AppServiceOptions `yaml:"appService,omitempty"`
+	// The optional Azure Container Instances options
+	Aci AciOptions `yaml:"aci,omitempty"`
+	// The optional Azure Storage static website options
+	StaticSite StorageStaticSiteOptions `yaml:"staticSite,omitempty"`
+	// The optional Azure Front Door / CDN options
+	Cdn CdnOptions `yaml:"cdn,omitempty"`
+	// The optional options selecting the module and the archive of a Java service in a multi-module repository
+	Java JavaOptions `yaml:"java,omitempty"`
+	// The custom domains bound to the service after it's deployed
+	Domains []DomainConfig `yaml:"domains,omitempty"`
+	// The optional options of a realtime backend of an Azure Web PubSub hub or an Azure SignalR Service
+	Realtime RealtimeOptions `yaml:"realtime,omitempty"`
+	// The other services and resources of the project the service connects to. The connection environment variables
+	// are set on the service, and roles on the resources are assigned to its managed identity, when it's deployed.
+	Uses []string `yaml:"uses,omitempty"`
 	// The infrastructure provisioning configuration
 	Infra provisioning.Options `yaml:"infra,omitempty"`
 	// Hook configuration for service
@@ -55,6 +76,55 @@ type ServiceConfig struct {
 	*ext.EventDispatcher[ServiceLifecycleEventArgs] `yaml:"-"`
 }
 
+// CdnOptions configures the purge of the Azure Front Door or CDN endpoints of a service after it's deployed.
+// The endpoints are the one set in the SERVICE_<NAME>_CDN_ENDPOINT_ID environment variable, usually from an output, or
+// the endpoints tagged with the 'azd-service-name' of the service.
+type CdnOptions struct {
+	// The paths purged after a deployment. Defaults to '/*'
+	PurgePaths []string `yaml:"purgePaths,omitempty"`
+}
+
+// RealtimeOptions makes the service the backend of an Azure Web PubSub hub or an Azure SignalR Service. After the service
+// is deployed, the event handler of the hub, or the upstream of the SignalR Service, is pointed at the endpoint of the
+// service, authenticated with the managed identity of the Web PubSub or SignalR resource.
+type RealtimeOptions struct {
+	// The Web PubSub or SignalR Service resource, as the name of a resource of the project or a resource ID, ex)
+	// ${AZURE_WEB_PUBSUB_ID}. Setting it makes the service a realtime backend
+	Resource osutil.ExpandableString `yaml:"resource,omitempty"`
+	// The Web PubSub hub, or the pattern of the SignalR hubs, ex) chat. Required for Web PubSub, defaults to all the hubs
+	// of SignalR
+	Hub string `yaml:"hub,omitempty"`
+	// The url path of the event handler of the service, ex) /api/{event}. Defaults to '/eventhandler'
+	Path string `yaml:"path,omitempty"`
+	// The pattern of the user events sent to the service, ex) message,chat. Defaults to all the user events
+	UserEvents string `yaml:"userEvents,omitempty"`
+	// The system events of Web PubSub sent to the service, ex) connect, connected, disconnected
+	SystemEvents []string `yaml:"systemEvents,omitempty"`
+	// The audience of the token of the managed identity sent to the service, ex) the application ID URI of the service.
+	// Defaults to the default audience of the managed identity
+	Audience string `yaml:"audience,omitempty"`
+}
+
+// DomainCertificateKind is the kind of certificate securing a custom domain
+type DomainCertificateKind string
+
+const (
+	// A free certificate managed by App Service, Container Apps or Static Web Apps
+	DomainCertificateManaged DomainCertificateKind = "managed"
+	// The domain isn't secured by a certificate
+	DomainCertificateNone DomainCertificateKind = "none"
+)
+
+// DomainConfig is a custom domain of a web service, supported by App Service, Container Apps and Static Web Apps.
+// After the service is deployed, the DNS records validating the domain are created when its Azure DNS zone is in the
+// subscription of the service, then the domain is bound to the service and secured with a managed certificate.
+type DomainConfig struct {
+	// The host name of the domain, ex) www.contoso.com
+	Name osutil.ExpandableString `yaml:"name"`
+	// The certificate securing the domain. Defaults to 'managed'
+	Certificate DomainCertificateKind `yaml:"certificate,omitempty"`
+}
+
 type DotNetContainerAppOptions struct {
 	Manifest    *apphost.Manifest
 	AppHostPath string
@@ -63,6 +133,15 @@ type DotNetContainerAppOptions struct {
 	ContainerImage string
 }
 
+// buildPlugin returns the build plugin declared in the project for the language of the service, if any
+func (sc *ServiceConfig) buildPlugin() *BuildPluginConfig {
+	if sc.Project == nil {
+		return nil
+	}
+
+	return sc.Project.BuildPlugins[string(sc.Language)]
+}
+
 // Path returns the fully qualified path to the project
 func (sc *ServiceConfig) Path() string {
 	if filepath.IsAbs(sc.RelativePath) {
@@ -70,3 +149,18 @@ func (sc *ServiceConfig) Path() string {
 	}
 	return filepath.Join(sc.Project.Path, sc.RelativePath)
 }
+
+// ResourceGroupTemplate returns the resource group the service is deployed to: the resource group set on the service,
+// or else on the project, or else the resource group of the service chosen by the resource group strategy of the project,
+// see provisioning.ResourceGroupStrategyPerService. Empty when the service uses the resource group of the environment.
+func (sc *ServiceConfig) ResourceGroupTemplate() osutil.ExpandableString {
+	if !sc.ResourceGroupName.Empty() || sc.Project == nil {
+		return sc.ResourceGroupName
+	}
+
+	if !sc.Project.ResourceGroupName.Empty() {
+		return sc.Project.ResourceGroupName
+	}
+
+	return sc.Project.Infra.ResourceGroup.ServiceResourceGroupName(sc.Name)
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -227,3 +229,20 @@ func createTestServiceConfig(path string, host ServiceTargetKind, language Servi
 		EventDispatcher: ext.NewEventDispatcher[ServiceLifecycleEventArgs](),
 	}
 }
+
+func TestServiceConfigResourceGroupTemplate(t *testing.T) {
+	project := &ProjectConfig{}
+	service := &ServiceConfig{Name: "api", Project: project}
+	require.True(t, service.ResourceGroupTemplate().Empty())
+
+	project.Infra.ResourceGroup = &provisioning.ResourceGroupOptions{
+		Strategy: provisioning.ResourceGroupStrategyPerService,
+	}
+	require.Equal(t, osutil.NewExpandableString("rg-${AZURE_ENV_NAME}-api"), service.ResourceGroupTemplate())
+
+	project.ResourceGroupName = osutil.NewExpandableString("rg-project")
+	require.Equal(t, project.ResourceGroupName, service.ResourceGroupTemplate())
+
+	service.ResourceGroupName = osutil.NewExpandableString("rg-service")
+	require.Equal(t, service.ResourceGroupName, service.ResourceGroupTemplate())
+}
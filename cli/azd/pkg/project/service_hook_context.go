@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+)
+
+// The keys of the service lifecycle event args set by the service manager for the hooks of the service
+const (
+	// The *ServicePackageResult of the package or deploy operation, set for post package, pre and post deploy events
+	packageEventArg = "package"
+	// The *environment.TargetResource of the deploy operation, set for pre and post deploy events
+	targetResourceEventArg = "targetResource"
+	// The *ServiceDeployResult of the deploy operation, set for post deploy events
+	deployResultEventArg = "deployResult"
+)
+
+// ServiceHookContext is the context of a service package or deployment, exposed to the hooks of the service as the JSON
+// file referenced by AZD_HOOK_CONTEXT and as AZD_SERVICE_* environment variables.
+type ServiceHookContext struct {
+	// The name of the service
+	Service string `json:"service"`
+	// The package of the service, a file path or a local container image
+	PackagePath string `json:"packagePath,omitempty"`
+	// The container image pushed by the deployment, ex) myregistry.azurecr.io/app/web-dev:azd-deploy-1700000000
+	Image string `json:"image,omitempty"`
+	// The digest of the container image pushed by the deployment, when known
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// The resource ID of the Azure resource hosting the service
+	TargetResourceId string `json:"targetResourceId,omitempty"`
+	// The endpoints of the service once deployed
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// NewServiceHookContext returns the context of the service operation raising the event, nil when the event args don't
+// carry any.
+func NewServiceHookContext(eventArgs ServiceLifecycleEventArgs, env *environment.Environment) *ext.HookContext {
+	if len(eventArgs.Args) == 0 {
+		return nil
+	}
+
+	hookContext := ServiceHookContext{
+		Service: eventArgs.Service.Name,
+	}
+
+	if packageResult, ok := eventArgs.Args[packageEventArg].(*ServicePackageResult); ok && packageResult != nil {
+		hookContext.PackagePath = packageResult.PackagePath
+	}
+
+	if targetResource, ok := eventArgs.Args[targetResourceEventArg].(*environment.TargetResource); ok &&
+		targetResource != nil && targetResource.ResourceType() != "" && targetResource.ResourceName() != "" {
+		hookContext.TargetResourceId = fmt.Sprintf(
+			"%s/providers/%s/%s",
+			azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()),
+			targetResource.ResourceType(),
+			targetResource.ResourceName(),
+		)
+	}
+
+	if deployResult, ok := eventArgs.Args[deployResultEventArg].(*ServiceDeployResult); ok && deployResult != nil {
+		if deployResult.TargetResourceId != "" {
+			hookContext.TargetResourceId = deployResult.TargetResourceId
+		}
+		hookContext.Endpoints = deployResult.Endpoints
+
+		// Container service targets record the pushed image in the environment
+		if env != nil {
+			hookContext.Image = env.GetServiceProperty(eventArgs.Service.Name, "IMAGE_NAME")
+			hookContext.ImageDigest = env.GetServiceProperty(eventArgs.Service.Name, "IMAGE_DIGEST")
+		}
+	}
+
+	return &ext.HookContext{
+		Env: map[string]string{
+			"AZD_SERVICE_NAME":               hookContext.Service,
+			"AZD_SERVICE_PACKAGE_PATH":       hookContext.PackagePath,
+			"AZD_SERVICE_IMAGE":              hookContext.Image,
+			"AZD_SERVICE_IMAGE_DIGEST":       hookContext.ImageDigest,
+			"AZD_SERVICE_TARGET_RESOURCE_ID": hookContext.TargetResourceId,
+			"AZD_SERVICE_ENDPOINTS":          strings.Join(hookContext.Endpoints, ","),
+		},
+		Data: hookContext,
+	}
+}
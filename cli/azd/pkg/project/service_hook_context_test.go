@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewServiceHookContext(t *testing.T) {
+	serviceConfig := &ServiceConfig{Name: "api"}
+	env := environment.New("test")
+	env.SetServiceProperty("api", "IMAGE_NAME", "myregistry.azurecr.io/app/api:azd-deploy-1")
+	env.SetServiceProperty("api", "IMAGE_DIGEST", "sha256:abc")
+
+	packageResult := &ServicePackageResult{PackagePath: "app/api:azd-deploy-1"}
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "API", "Microsoft.App/containerApps")
+
+	t.Run("NoArgs", func(t *testing.T) {
+		hookContext := NewServiceHookContext(ServiceLifecycleEventArgs{Service: serviceConfig}, env)
+		require.Nil(t, hookContext)
+	})
+
+	t.Run("PreDeploy", func(t *testing.T) {
+		hookContext := NewServiceHookContext(ServiceLifecycleEventArgs{
+			Service: serviceConfig,
+			Args: map[string]any{
+				packageEventArg:        packageResult,
+				targetResourceEventArg: targetResource,
+			},
+		}, env)
+
+		require.NotNil(t, hookContext)
+		require.Equal(t, ServiceHookContext{
+			Service:     "api",
+			PackagePath: "app/api:azd-deploy-1",
+			TargetResourceId: "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP" +
+				"/providers/Microsoft.App/containerApps/API",
+		}, hookContext.Data)
+		require.Equal(t, "", hookContext.Env["AZD_SERVICE_IMAGE"])
+	})
+
+	t.Run("PostDeploy", func(t *testing.T) {
+		hookContext := NewServiceHookContext(ServiceLifecycleEventArgs{
+			Service: serviceConfig,
+			Args: map[string]any{
+				packageEventArg:        packageResult,
+				targetResourceEventArg: targetResource,
+				deployResultEventArg: &ServiceDeployResult{
+					TargetResourceId: "TARGET_RESOURCE_ID",
+					Endpoints:        []string{"https://api.example.com", "https://api.internal"},
+				},
+			},
+		}, env)
+
+		require.NotNil(t, hookContext)
+		require.Equal(t, "TARGET_RESOURCE_ID", hookContext.Env["AZD_SERVICE_TARGET_RESOURCE_ID"])
+		require.Equal(t, "myregistry.azurecr.io/app/api:azd-deploy-1", hookContext.Env["AZD_SERVICE_IMAGE"])
+		require.Equal(t, "sha256:abc", hookContext.Env["AZD_SERVICE_IMAGE_DIGEST"])
+		require.Equal(t, "https://api.example.com,https://api.internal", hookContext.Env["AZD_SERVICE_ENDPOINTS"])
+		require.Equal(t, "app/api:azd-deploy-1", hookContext.Env["AZD_SERVICE_PACKAGE_PATH"])
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// ServiceLogStreamer streams the runtime logs of deployed services. Logs are supported for services hosted on
+// Azure App Service, Azure Functions and Azure Container Apps.
+type ServiceLogStreamer struct {
+	azCli               *azapi.AzureClient
+	containerAppService containerapps.ContainerAppService
+}
+
+// NewServiceLogStreamer creates a new instance of the ServiceLogStreamer
+func NewServiceLogStreamer(
+	azCli *azapi.AzureClient,
+	containerAppService containerapps.ContainerAppService,
+) *ServiceLogStreamer {
+	return &ServiceLogStreamer{
+		azCli:               azCli,
+		containerAppService: containerAppService,
+	}
+}
+
+// Stream streams the runtime logs of the service deployed to the target resource, following new logs until ctx is
+// canceled. The caller must close the returned reader.
+func (s *ServiceLogStreamer) Stream(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (io.ReadCloser, error) {
+	resourceType := targetResource.ResourceType()
+	resourceName := targetResource.ResourceName()
+
+	// The resource of .NET Aspire services is resolved on deploy, the container app is named after the service
+	if serviceConfig.Host == DotNetContainerAppTarget && resourceName == "" {
+		resourceType = string(azapi.AzureResourceTypeContainerApp)
+		resourceName = serviceConfig.Name
+	}
+
+	switch {
+	case strings.EqualFold(resourceType, string(azapi.AzureResourceTypeWebSite)):
+		return s.azCli.StreamAppServiceLogs(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), resourceName)
+	case strings.EqualFold(resourceType, string(azapi.AzureResourceTypeContainerApp)):
+		return s.containerAppService.StreamLogs(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), resourceName)
+	default:
+		return nil, fmt.Errorf("streaming logs is not supported for service '%s' hosted on '%s'",
+			serviceConfig.Name, serviceConfig.Host)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceLogStreamer_Stream_Unsupported(t *testing.T) {
+	streamer := NewServiceLogStreamer(nil, nil)
+	serviceConfig := &ServiceConfig{Name: "api", Host: AksTarget}
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "CLUSTER", "Microsoft.ContainerService/managedClusters")
+
+	stream, err := streamer.Stream(context.Background(), serviceConfig, targetResource)
+	require.ErrorContains(t, err, "streaming logs is not supported for service 'api' hosted on 'aks'")
+	require.Nil(t, stream)
+}
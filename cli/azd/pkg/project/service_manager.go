@@ -18,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -303,6 +304,7 @@ func (sm *serviceManager) Package(
 	eventArgs := ServiceLifecycleEventArgs{
 		Project: serviceConfig.Project,
 		Service: serviceConfig,
+		Args:    map[string]any{},
 	}
 
 	hasBuildOutput := buildOutput != nil
@@ -355,6 +357,7 @@ func (sm *serviceManager) Package(
 
 		packageResult = serviceTargetPackageResult
 		sm.setOperationResult(serviceConfig, string(ServiceEventPackage), packageResult)
+		eventArgs.Args[packageEventArg] = packageResult
 
 		return nil
 	})
@@ -451,15 +454,11 @@ func (sm *serviceManager) Deploy(
 		// Get any explicitly configured resource group name
 		// 1. Service level override
 		// 2. Project level override
-		resourceGroupNameTemplate := serviceConfig.ResourceGroupName
-		if resourceGroupNameTemplate.Empty() {
-			resourceGroupNameTemplate = serviceConfig.Project.ResourceGroupName
-		}
-
+		// 3. Per service resource group strategy
 		resourceGroupName, err := sm.resourceManager.GetResourceGroupName(
 			ctx,
 			sm.env.GetSubscriptionId(),
-			resourceGroupNameTemplate,
+			serviceConfig.ResourceGroupTemplate(),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("getting resource group name: %w", err)
@@ -478,26 +477,39 @@ func (sm *serviceManager) Deploy(
 		}
 	}
 
-	deployResult, err := runCommand(
-		ctx,
-		ServiceEventDeploy,
-		serviceConfig,
-		func() (*ServiceDeployResult, error) {
-			return serviceTarget.Deploy(ctx, serviceConfig, packageResult, targetResource, progress)
+	// The hooks of the service get the package and the target resource of the deployment, and its result once deployed
+	eventArgs := ServiceLifecycleEventArgs{
+		Project: serviceConfig.Project,
+		Service: serviceConfig,
+		Args: map[string]any{
+			packageEventArg:        packageResult,
+			targetResourceEventArg: targetResource,
 		},
-	)
+	}
+
+	var deployResult *ServiceDeployResult
+	err = serviceConfig.Invoke(ctx, ServiceEventDeploy, eventArgs, func() error {
+		result, err := serviceTarget.Deploy(ctx, serviceConfig, packageResult, targetResource, progress)
+		if err != nil {
+			return err
+		}
+
+		// Allow users to specify their own endpoints, in cases where they've configured their own front-end load
+		// balancers, reverse proxies or DNS host names outside of the service target (and prefer that to be used instead).
+		overriddenEndpoints := OverriddenEndpoints(ctx, serviceConfig, sm.env)
+		if len(overriddenEndpoints) > 0 {
+			result.Endpoints = overriddenEndpoints
+		}
+
+		deployResult = result
+		eventArgs.Args[deployResultEventArg] = deployResult
+		return nil
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed deploying service '%s': %w", serviceConfig.Name, err)
 	}
 
-	// Allow users to specify their own endpoints, in cases where they've configured their own front-end load balancers,
-	// reverse proxies or DNS host names outside of the service target (and prefer that to be used instead).
-	overriddenEndpoints := OverriddenEndpoints(ctx, serviceConfig, sm.env)
-	if len(overriddenEndpoints) > 0 {
-		deployResult.Endpoints = overriddenEndpoints
-	}
-
 	sm.setOperationResult(serviceConfig, string(ServiceEventDeploy), deployResult)
 	return deployResult, nil
 }
@@ -539,7 +551,14 @@ func (sm *serviceManager) GetFrameworkService(ctx context.Context, serviceConfig
 		serviceConfig.Language = ServiceLanguageDocker
 	}
 
-	if err := sm.serviceLocator.ResolveNamed(string(serviceConfig.Language), &frameworkService); err != nil {
+	if plugin := serviceConfig.buildPlugin(); plugin != nil {
+		var commandRunner exec.CommandRunner
+		if err := sm.serviceLocator.Resolve(&commandRunner); err != nil {
+			return nil, fmt.Errorf("resolving command runner for build plugin '%s': %w", serviceConfig.Language, err)
+		}
+
+		frameworkService = NewBuildPluginProject(string(serviceConfig.Language), plugin, sm.env, commandRunner)
+	} else if err := sm.serviceLocator.ResolveNamed(string(serviceConfig.Language), &frameworkService); err != nil {
 		return nil, fmt.Errorf(
 			"failed to resolve language '%s' for service '%s', %w",
 			serviceConfig.Language,
@@ -202,11 +202,14 @@ func Test_ServiceManager_Deploy(t *testing.T) {
 
 	_ = serviceConfig.AddHandler("predeploy", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
 		raisedPreDeployEvent = true
+		require.Contains(t, args.Args, targetResourceEventArg)
+		require.NotContains(t, args.Args, deployResultEventArg)
 		return nil
 	})
 
 	_ = serviceConfig.AddHandler("postdeploy", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
 		raisedPostDeployEvent = true
+		require.IsType(t, &ServiceDeployResult{}, args.Args[deployResultEventArg])
 		return nil
 	})
 
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 )
@@ -104,6 +106,10 @@ type ServiceDeployResult struct {
 	Kind             ServiceTargetKind `json:"kind"`
 	Endpoints        []string          `json:"endpoints"`
 	Details          interface{}       `json:"details"`
+	// The app settings changed by the deployment of an App Service or Function App service
+	AppSettings []azapi.AppSettingChange `json:"appSettings,omitempty"`
+	// The secrets of the environment variables changed by the deployment of a Container Apps service
+	Secrets []containerapps.SecretChange `json:"secrets,omitempty"`
 }
 
 // Supports rendering messages for UX items
@@ -133,9 +139,41 @@ func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
 		}
 	}
 
+	if len(spr.AppSettings) > 0 {
+		builder.WriteString(fmt.Sprintf("%s- App settings: %s\n", currentIndentation, FormatAppSettingChanges(spr.AppSettings)))
+	}
+
+	if len(spr.Secrets) > 0 {
+		builder.WriteString(fmt.Sprintf("%s- Secrets: %s\n", currentIndentation, FormatSecretChanges(spr.Secrets)))
+	}
+
 	return builder.String()
 }
 
+// FormatAppSettingChanges describes the changes of app settings, ex) API_URL (added), LOG_LEVEL (updated)
+func FormatAppSettingChanges(changes []azapi.AppSettingChange) string {
+	if len(changes) == 0 {
+		return "no changes"
+	}
+
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		names = append(names, fmt.Sprintf("%s (%s)", change.Name, change.Kind))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// FormatSecretChanges describes the changes of the secrets of a container app, ex) db-password (added)
+func FormatSecretChanges(changes []containerapps.SecretChange) string {
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		names = append(names, fmt.Sprintf("%s (%s)", change.Name, change.Kind))
+	}
+
+	return strings.Join(names, ", ")
+}
+
 func (spr *ServiceDeployResult) MarshalJSON() ([]byte, error) {
 	return json.Marshal(*spr)
 }
@@ -27,13 +27,17 @@ const (
 	AksTarget                ServiceTargetKind = "aks"
 	DotNetContainerAppTarget ServiceTargetKind = "containerapp-dotnet"
 	AiEndpointTarget         ServiceTargetKind = "ai.endpoint"
+	AciTarget                ServiceTargetKind = "aci"
+	StorageStaticSiteTarget  ServiceTargetKind = "storage-static-site"
+	LogicAppTarget           ServiceTargetKind = "logicapp"
 )
 
 // RequiresContainer returns true if the service target runs a container image.
 func (stk ServiceTargetKind) RequiresContainer() bool {
 	switch stk {
 	case ContainerAppTarget,
-		AksTarget:
+		AksTarget,
+		AciTarget:
 		return true
 	}
 
@@ -52,7 +56,10 @@ func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
 		StaticWebAppTarget,
 		SpringAppTarget,
 		AksTarget,
-		AiEndpointTarget:
+		AiEndpointTarget,
+		AciTarget,
+		StorageStaticSiteTarget,
+		LogicAppTarget:
 
 		return kind, nil
 	}
@@ -139,7 +146,7 @@ func (st ServiceTargetKind) IgnoreFile() string {
 	switch st {
 	case AppServiceTarget:
 		return ".webappignore"
-	case AzureFunctionTarget:
+	case AzureFunctionTarget, LogicAppTarget:
 		return ".funcignore"
 	default:
 		return ""
@@ -149,10 +156,10 @@ func (st ServiceTargetKind) IgnoreFile() string {
 // SupportsDelayedProvisioning returns true if the service target kind
 // supports delayed provisioning resources at deployment time, otherwise false.
 //
-// As an example, ContainerAppTarget is able to provision the container app as part of deployment,
+// As an example, AciTarget creates the container group as part of deployment,
 // and thus returns true.
 func (st ServiceTargetKind) SupportsDelayedProvisioning() bool {
-	return st == AksTarget
+	return st == AksTarget || st == AciTarget
 }
 
 func checkResourceType(resource *environment.TargetResource, expectedResourceType azapi.AzureResourceType) error {
@@ -0,0 +1,635 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/armmsi"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/sethvargo/go-retry"
+)
+
+// The Azure Container Instances options
+type AciOptions struct {
+	// The restart policy of the container, 'Always', 'OnFailure' or 'Never'. With 'Never', the service runs as a job:
+	// deployments wait for the container to exit, stream its logs and fail when its exit code isn't 0. Defaults to 'Always'
+	RestartPolicy azapi.ContainerGroupRestartPolicy `yaml:"restartPolicy,omitempty"`
+	// The CPU cores of the container. Defaults to 1
+	Cpu float64 `yaml:"cpu,omitempty"`
+	// The memory of the container in GB. Defaults to 1.5
+	Memory float64 `yaml:"memory,omitempty"`
+	// The port exposed on the public IP address of the container group, if any
+	Port int `yaml:"port,omitempty"`
+	// The command run by the container instead of the entrypoint of the image
+	Command []string `yaml:"command,omitempty"`
+	// The environment variables of the container
+	Env map[string]osutil.ExpandableString `yaml:"env,omitempty"`
+	// The environment variables of the container holding secrets. Their values are never returned by Azure
+	Secrets map[string]osutil.ExpandableString `yaml:"secrets,omitempty"`
+}
+
+const (
+	aciDefaultCpu    = 1.0
+	aciDefaultMemory = 1.5
+)
+
+// aciPollInterval is the interval between the checks of the state of a container running as a job.
+var aciPollInterval = 5 * time.Second
+
+// aciRoleAssignmentPropagation is how long deploying a container group is retried when its image can't be pulled right
+// after the AcrPull role was assigned to its identity, since role assignments take a few minutes to propagate.
+var aciRoleAssignmentPropagation = 5 * time.Minute
+
+// aciPullIdentity is the user assigned identity a container group pulls its image from the registry with.
+type aciPullIdentity struct {
+	// The login server of the registry
+	server string
+	// The resource id of the identity
+	resourceId string
+	// Whether the AcrPull role was just assigned to the identity
+	roleAssigned bool
+}
+
+// aciDeployResult is the result of deploying a container group.
+type aciDeployResult struct {
+	Image         string                            `json:"image"`
+	RestartPolicy azapi.ContainerGroupRestartPolicy `json:"restartPolicy"`
+	// The exit code of the container, when the service runs as a job
+	ExitCode *int32 `json:"exitCode,omitempty"`
+}
+
+type aciTarget struct {
+	env             *environment.Environment
+	console         input.Console
+	cli             *azapi.AzureClient
+	resourceService *azapi.ResourceService
+	entraIdService  entraid.EntraIdService
+	msiService      armmsi.ArmMsiService
+	containerHelper *ContainerHelper
+}
+
+// NewAciTarget creates the Azure Container Instances service target.
+//
+// The target resource can be partially filled with only ResourceGroupName, since container groups are created during
+// deployment.
+func NewAciTarget(
+	env *environment.Environment,
+	console input.Console,
+	azCli *azapi.AzureClient,
+	resourceService *azapi.ResourceService,
+	entraIdService entraid.EntraIdService,
+	msiService armmsi.ArmMsiService,
+	containerHelper *ContainerHelper,
+) ServiceTarget {
+	return &aciTarget{
+		env:             env,
+		console:         console,
+		cli:             azCli,
+		resourceService: resourceService,
+		entraIdService:  entraIdService,
+		msiService:      msiService,
+		containerHelper: containerHelper,
+	}
+}
+
+// Gets the required external tools
+func (at *aciTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	return at.containerHelper.RequiredExternalTools(ctx, serviceConfig)
+}
+
+// Initializes the Container Instances target
+func (at *aciTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	config := serviceConfig.Aci
+
+	switch config.RestartPolicy {
+	case "", azapi.ContainerGroupRestartAlways, azapi.ContainerGroupRestartOnFailure, azapi.ContainerGroupRestartNever:
+	default:
+		return fmt.Errorf(
+			"restart policy '%s' of service '%s' is not valid. Supported values are 'Always', 'OnFailure' and 'Never'",
+			config.RestartPolicy,
+			serviceConfig.Name,
+		)
+	}
+
+	for name := range config.Secrets {
+		if _, has := config.Env[name]; has {
+			return fmt.Errorf("'%s' is set in both the env and the secrets of service '%s'", name, serviceConfig.Name)
+		}
+	}
+
+	return nil
+}
+
+// Prepares and tags the container image from the build output based on the specified service configuration
+func (at *aciTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	return packageOutput, nil
+}
+
+// Deploys the service container image to ACR and runs it in a container group. When the restart policy is 'Never',
+// waits for the container to exit while streaming its logs.
+//
+// Images of the registry of the project are pulled with the user assigned identity 'id-<container group name>',
+// which is created and assigned the AcrPull role on the registry when needed.
+func (at *aciTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := at.validateTargetResource(targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	// Login, tag & push container image to ACR
+	_, err := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	imageName := at.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+
+	containerGroupName, err := at.containerGroupName(serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	targetResource = environment.NewTargetResource(
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		containerGroupName,
+		string(azapi.AzureResourceTypeContainerGroup),
+	)
+
+	location, err := at.containerGroupLocation(ctx, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	registryName, err := at.containerHelper.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullIdentity *aciPullIdentity
+	if registryName != "" && strings.EqualFold(registryHost(imageName), registryName) {
+		pullIdentity, err = at.ensurePullIdentity(ctx, targetResource, registryName, location, progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := at.deployContainerGroup(
+		ctx, serviceConfig, targetResource, location, imageName, pullIdentity, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for container group"))
+	endpoints, err := at.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: azure.ContainerGroupRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:      AciTarget,
+		Details:   result,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// Gets the endpoint of the public IP address of the container group, if any
+func (at *aciTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	containerGroupName, err := at.containerGroupName(serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	containerGroup, err := at.cli.GetContainerGroup(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), containerGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	ipAddress := containerGroup.Properties.IpAddress
+	if ipAddress == nil || len(ipAddress.Ports) == 0 {
+		return []string{}, nil
+	}
+
+	host := ipAddress.Fqdn
+	if host == "" {
+		host = ipAddress.Ip
+	}
+
+	// The IP address of a container group is released when its containers are terminated
+	if host == "" {
+		return []string{}, nil
+	}
+
+	return []string{fmt.Sprintf("http://%s:%d/", host, ipAddress.Ports[0].Port)}, nil
+}
+
+// containerGroupLocation returns the location of the existing container group, or the location of the environment
+// when the container group doesn't exist yet.
+func (at *aciTarget) containerGroupLocation(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+) (string, error) {
+	containerGroupName := targetResource.ResourceName()
+
+	existing, err := at.cli.GetContainerGroup(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), containerGroupName)
+	var responseErr *azcore.ResponseError
+	if err == nil {
+		return existing.Location, nil
+	} else if !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusNotFound {
+		return "", err
+	}
+
+	location := at.env.GetLocation()
+	if location == "" {
+		return "", fmt.Errorf(
+			"the location of container group '%s' is not set, set the '%s' environment variable",
+			containerGroupName,
+			environment.LocationEnvVarName,
+		)
+	}
+
+	return location, nil
+}
+
+// ensurePullIdentity ensures the user assigned identity of the container group exists and is assigned the AcrPull role
+// on the registry, so the container group doesn't depend on the short-lived registry token of the user to pull its
+// image when it's restarted.
+func (at *aciTarget) ensurePullIdentity(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	loginServer string,
+	location string,
+	progress *async.Progress[ServiceProgress],
+) (*aciPullIdentity, error) {
+	subscriptionId := targetResource.SubscriptionId()
+	identityName := "id-" + targetResource.ResourceName()
+
+	progress.SetProgress(NewServiceProgress("Configuring the access of the container group to the registry"))
+	identity, err := at.msiService.CreateUserIdentity(
+		ctx, subscriptionId, targetResource.ResourceGroupName(), location, identityName)
+	if err != nil {
+		return nil, fmt.Errorf("creating managed identity '%s': %w", identityName, err)
+	}
+
+	if identity.ID == nil || identity.Properties == nil || identity.Properties.PrincipalID == nil {
+		return nil, fmt.Errorf("managed identity '%s' doesn't have a principal id", identityName)
+	}
+
+	pullIdentity := &aciPullIdentity{
+		server:     loginServer,
+		resourceId: *identity.ID,
+	}
+
+	registry, err := at.containerHelper.containerRegistryService.FindContainerRegistry(
+		ctx, subscriptionId, loginServer)
+	if err != nil {
+		return nil, fmt.Errorf("finding container registry '%s': %w", loginServer, err)
+	}
+
+	roleAssignments, err := at.resourceService.ListPrincipalRoleAssignments(
+		ctx, subscriptionId, *registry.ID, *identity.Properties.PrincipalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if canPullFromRegistry(roleAssignments) {
+		return pullIdentity, nil
+	}
+
+	err = at.entraIdService.CreateRbac(
+		ctx,
+		subscriptionId,
+		*registry.ID,
+		"/providers/Microsoft.Authorization/roleDefinitions/"+acrPullRoleDefinitionId,
+		*identity.Properties.PrincipalID,
+	)
+	if err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"assigning the AcrPull role on registry '%s' to managed identity '%s': %w", loginServer, identityName, err),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: assigning roles requires the Owner or User Access Administrator role on the registry. "+
+					"Otherwise assign the AcrPull role on the registry to the managed identity '%s' in your infrastructure.",
+				identityName),
+		}
+	}
+
+	pullIdentity.roleAssigned = true
+	return pullIdentity, nil
+}
+
+// deployContainerGroup creates or updates the container group running the image, then waits for the container to exit
+// when the service runs as a job.
+func (at *aciTarget) deployContainerGroup(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	location string,
+	imageName string,
+	pullIdentity *aciPullIdentity,
+	progress *async.Progress[ServiceProgress],
+) (*aciDeployResult, error) {
+	subscriptionId := targetResource.SubscriptionId()
+	resourceGroup := targetResource.ResourceGroupName()
+	containerGroupName := targetResource.ResourceName()
+
+	containerGroup, err := at.containerGroup(serviceConfig, location, imageName, pullIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Deploying container group"))
+	err = retry.Do(
+		ctx,
+		retry.WithMaxDuration(aciRoleAssignmentPropagation, retry.NewConstant(10*time.Second)),
+		func(ctx context.Context) error {
+			_, err := at.cli.CreateOrUpdateContainerGroup(
+				ctx, subscriptionId, resourceGroup, containerGroupName, *containerGroup)
+
+			var responseErr *azcore.ResponseError
+			if pullIdentity != nil && pullIdentity.roleAssigned &&
+				errors.As(err, &responseErr) && responseErr.ErrorCode == "InaccessibleImage" {
+				log.Printf("image of container group '%s' isn't accessible yet, retrying: %v", containerGroupName, err)
+				return retry.RetryableError(err)
+			}
+
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &aciDeployResult{
+		Image:         imageName,
+		RestartPolicy: containerGroup.Properties.RestartPolicy,
+	}
+
+	if result.RestartPolicy != azapi.ContainerGroupRestartNever {
+		return result, nil
+	}
+
+	progress.SetProgress(NewServiceProgress("Waiting for the container to exit"))
+	exitCode, err := at.waitForExit(ctx, targetResource, serviceConfig.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExitCode = &exitCode
+	if exitCode != 0 {
+		return nil, fmt.Errorf("the container of service '%s' exited with code %d", serviceConfig.Name, exitCode)
+	}
+
+	return result, nil
+}
+
+// containerGroup returns the container group running the image with the container instances options of the service.
+func (at *aciTarget) containerGroup(
+	serviceConfig *ServiceConfig,
+	location string,
+	imageName string,
+	pullIdentity *aciPullIdentity,
+) (*azapi.ContainerGroup, error) {
+	config := serviceConfig.Aci
+
+	restartPolicy := config.RestartPolicy
+	if restartPolicy == "" {
+		restartPolicy = azapi.ContainerGroupRestartAlways
+	}
+
+	cpu := config.Cpu
+	if cpu == 0 {
+		cpu = aciDefaultCpu
+	}
+
+	memory := config.Memory
+	if memory == 0 {
+		memory = aciDefaultMemory
+	}
+
+	container := azapi.ContainerGroupContainer{
+		Name: serviceConfig.Name,
+		Properties: azapi.ContainerGroupContainerProperties{
+			Image:   imageName,
+			Command: config.Command,
+			Resources: azapi.ContainerResources{
+				Requests: azapi.ContainerResourceRequests{Cpu: cpu, MemoryInGB: memory},
+			},
+		},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Env)) {
+		value, err := config.Env[name].Envsubst(at.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding environment variable '%s': %w", name, err)
+		}
+
+		container.Properties.EnvironmentVariables = append(
+			container.Properties.EnvironmentVariables, azapi.ContainerEnvironmentVariable{Name: name, Value: value})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Secrets)) {
+		value, err := config.Secrets[name].Envsubst(at.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding secret '%s': %w", name, err)
+		}
+
+		container.Properties.EnvironmentVariables = append(
+			container.Properties.EnvironmentVariables, azapi.ContainerEnvironmentVariable{Name: name, SecureValue: value})
+	}
+
+	containerGroup := &azapi.ContainerGroup{
+		Location: location,
+		Tags: map[string]string{
+			azure.TagKeyAzdEnvName:     at.env.Name(),
+			azure.TagKeyAzdServiceName: serviceConfig.Name,
+		},
+		Properties: azapi.ContainerGroupProperties{
+			OsType:        "Linux",
+			RestartPolicy: restartPolicy,
+		},
+	}
+
+	if config.Port > 0 {
+		ports := []azapi.ContainerGroupPort{{Port: config.Port, Protocol: "TCP"}}
+		container.Properties.Ports = ports
+		containerGroup.Properties.IpAddress = &azapi.ContainerGroupIpAddress{
+			Type:  "Public",
+			Ports: ports,
+		}
+	}
+
+	if pullIdentity != nil {
+		containerGroup.Identity = &azapi.ContainerGroupIdentity{
+			Type:                   "UserAssigned",
+			UserAssignedIdentities: map[string]any{pullIdentity.resourceId: map[string]any{}},
+		}
+		containerGroup.Properties.ImageRegistryCredentials = []azapi.ContainerGroupRegistryCredential{
+			{
+				Server:   pullIdentity.server,
+				Identity: pullIdentity.resourceId,
+			},
+		}
+	}
+
+	containerGroup.Properties.Containers = []azapi.ContainerGroupContainer{container}
+	return containerGroup, nil
+}
+
+// waitForExit waits for the container to exit, displaying its logs as they are written, and returns its exit code.
+func (at *aciTarget) waitForExit(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	containerName string,
+) (int32, error) {
+	subscriptionId := targetResource.SubscriptionId()
+	resourceGroup := targetResource.ResourceGroupName()
+	containerGroupName := targetResource.ResourceName()
+
+	// The logs API returns all the logs of the container, only the new lines are displayed
+	displayedLines := 0
+	displayLogs := func(exited bool) {
+		content, err := at.cli.GetContainerLogs(ctx, subscriptionId, resourceGroup, containerGroupName, containerName)
+		if err != nil {
+			log.Printf("failed getting logs of container '%s': %v", containerName, err)
+			return
+		}
+
+		// Until the container exits, the last line may not be complete yet
+		lines := strings.Split(content, "\n")
+		if exited && !strings.HasSuffix(content, "\n") {
+			lines = append(lines, "")
+		}
+		lines = lines[:len(lines)-1]
+
+		if len(lines) <= displayedLines {
+			return
+		}
+
+		for _, line := range lines[displayedLines:] {
+			at.console.Message(ctx, output.WithGrayFormat("%s", line))
+		}
+		displayedLines = len(lines)
+	}
+
+	for {
+		containerGroup, err := at.cli.GetContainerGroup(ctx, subscriptionId, resourceGroup, containerGroupName)
+		if err != nil {
+			return 0, err
+		}
+
+		if containerGroup.Properties.ProvisioningState == "Failed" {
+			return 0, fmt.Errorf("provisioning container group '%s' failed", containerGroupName)
+		}
+
+		state := containerState(containerGroup, containerName)
+		if state != nil && state.State == azapi.ContainerStateTerminated {
+			displayLogs(true)
+
+			exitCode := int32(0)
+			if state.ExitCode != nil {
+				exitCode = *state.ExitCode
+			}
+
+			at.console.Message(ctx, fmt.Sprintf("Container %s exited with code %d", containerName, exitCode))
+			return exitCode, nil
+		}
+
+		displayLogs(false)
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(aciPollInterval):
+		}
+	}
+}
+
+// containerGroupName returns the name of the container group of the service. Container groups are created on deploy,
+// when the target resource isn't found, it's named after the resource name of the service or the service itself.
+func (at *aciTarget) containerGroupName(
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (string, error) {
+	if targetResource.ResourceName() != "" {
+		return targetResource.ResourceName(), nil
+	}
+
+	resourceName, err := serviceConfig.ResourceName.Envsubst(at.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("expanding resource name: %w", err)
+	}
+
+	if resourceName != "" {
+		return resourceName, nil
+	}
+
+	return serviceConfig.Name, nil
+}
+
+func (at *aciTarget) validateTargetResource(targetResource *environment.TargetResource) error {
+	if targetResource.ResourceGroupName() == "" {
+		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
+	}
+
+	if targetResource.ResourceType() != "" {
+		if err := checkResourceType(targetResource, azapi.AzureResourceTypeContainerGroup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// containerState returns the current state of the container with the given name, if known.
+func containerState(containerGroup *azapi.ContainerGroup, containerName string) *azapi.ContainerState {
+	for _, container := range containerGroup.Properties.Containers {
+		if container.Name == containerName && container.Properties.InstanceView != nil {
+			return container.Properties.InstanceView.CurrentState
+		}
+	}
+
+	return nil
+}
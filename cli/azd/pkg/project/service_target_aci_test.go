@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_aciTarget_Initialize(t *testing.T) {
+	serviceTarget := &aciTarget{}
+
+	serviceConfig := createTestServiceConfig("./src/job", AciTarget, ServiceLanguageDocker)
+	serviceConfig.Aci.RestartPolicy = "Sometimes"
+	err := serviceTarget.Initialize(context.Background(), serviceConfig)
+	require.ErrorContains(t, err, "restart policy 'Sometimes' of service 'api' is not valid")
+
+	serviceConfig = createTestServiceConfig("./src/job", AciTarget, ServiceLanguageDocker)
+	serviceConfig.Aci.Env = map[string]osutil.ExpandableString{"DB_PASSWORD": osutil.NewExpandableString("value")}
+	serviceConfig.Aci.Secrets = map[string]osutil.ExpandableString{"DB_PASSWORD": osutil.NewExpandableString("secret")}
+	err = serviceTarget.Initialize(context.Background(), serviceConfig)
+	require.ErrorContains(t, err, "'DB_PASSWORD' is set in both the env and the secrets of service 'api'")
+}
+
+func Test_aciTarget_deployContainerGroup(t *testing.T) {
+	pollInterval := aciPollInterval
+	aciPollInterval = 0
+	t.Cleanup(func() { aciPollInterval = pollInterval })
+
+	tests := map[string]struct {
+		exitCode    int32
+		expectError bool
+	}{
+		"Succeeded": {exitCode: 0},
+		"Failed":    {exitCode: 3, expectError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			env := environment.NewWithValues("dev", map[string]string{
+				environment.LocationEnvVarName: "westus3",
+				"DATABASE_HOST":                "db.contoso.com",
+				"DATABASE_PASSWORD":            "password",
+			})
+
+			var deployed azapi.ContainerGroup
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/containerGroups/job")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				if err := mocks.ReadHttpBody(request.Body, &deployed); err != nil {
+					return nil, err
+				}
+
+				deployed.Properties.ProvisioningState = "Succeeded"
+				return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, deployed)
+			})
+
+			// The container runs for one check before exiting
+			gets := 0
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/containerGroups/job")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				gets++
+				containerGroup := deployed
+				containerGroup.Properties.Containers = []azapi.ContainerGroupContainer{deployed.Properties.Containers[0]}
+				state := &azapi.ContainerState{State: "Running"}
+				if gets > 1 {
+					state = &azapi.ContainerState{State: azapi.ContainerStateTerminated, ExitCode: to.Ptr(test.exitCode)}
+				}
+				containerGroup.Properties.Containers[0].Properties.InstanceView = &azapi.ContainerInstanceView{
+					CurrentState: state,
+				}
+
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, containerGroup)
+			})
+
+			mockContext.HttpClient.When(func(request *http.Request) bool {
+				return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/containers/api/logs")
+			}).RespondFn(func(request *http.Request) (*http.Response, error) {
+				content := "Migrating database\nApplying migra"
+				if gets > 1 {
+					content = "Migrating database\nApplying migrations\nDone"
+				}
+
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"content": content})
+			})
+
+			serviceTarget := &aciTarget{
+				env:     env,
+				console: mockContext.Console,
+				cli:     azapi.NewAzureClient(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+			}
+
+			serviceConfig := createTestServiceConfig("./src/job", AciTarget, ServiceLanguageDocker)
+			serviceConfig.Aci = AciOptions{
+				RestartPolicy: azapi.ContainerGroupRestartNever,
+				Command:       []string{"./migrate.sh"},
+				Env: map[string]osutil.ExpandableString{
+					"DATABASE_HOST": osutil.NewExpandableString("${DATABASE_HOST}"),
+				},
+				Secrets: map[string]osutil.ExpandableString{
+					"DATABASE_PASSWORD": osutil.NewExpandableString("${DATABASE_PASSWORD}"),
+				},
+			}
+			targetResource := environment.NewTargetResource(
+				"SUBSCRIPTION_ID", "RESOURCE_GROUP", "job", string(azapi.AzureResourceTypeContainerGroup))
+			identityId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+				"Microsoft.ManagedIdentity/userAssignedIdentities/id-job"
+			pullIdentity := &aciPullIdentity{server: "contoso.azurecr.io", resourceId: identityId}
+
+			result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*aciDeployResult, error) {
+				return serviceTarget.deployContainerGroup(
+					*mockContext.Context,
+					serviceConfig,
+					targetResource,
+					"westus3",
+					"contoso.azurecr.io/app/job:azd-1",
+					pullIdentity,
+					progress,
+				)
+			})
+			if test.expectError {
+				require.ErrorContains(t, err, "the container of service 'api' exited with code 3")
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, to.Ptr(int32(0)), result.ExitCode)
+			}
+
+			require.Equal(t, "westus3", deployed.Location)
+			require.Equal(t, "api", deployed.Tags["azd-service-name"])
+			require.Equal(t, azapi.ContainerGroupRestartNever, deployed.Properties.RestartPolicy)
+			require.Equal(t, "UserAssigned", deployed.Identity.Type)
+			require.Contains(t, deployed.Identity.UserAssignedIdentities, identityId)
+			require.Equal(t, []azapi.ContainerGroupRegistryCredential{
+				{Server: "contoso.azurecr.io", Identity: identityId},
+			}, deployed.Properties.ImageRegistryCredentials)
+
+			container := deployed.Properties.Containers[0].Properties
+			require.Equal(t, "contoso.azurecr.io/app/job:azd-1", container.Image)
+			require.Equal(t, []string{"./migrate.sh"}, container.Command)
+			require.Equal(t, azapi.ContainerResourceRequests{Cpu: 1, MemoryInGB: 1.5}, container.Resources.Requests)
+			require.Equal(t, []azapi.ContainerEnvironmentVariable{
+				{Name: "DATABASE_HOST", Value: "db.contoso.com"},
+				{Name: "DATABASE_PASSWORD", SecureValue: "password"},
+			}, container.EnvironmentVariables)
+
+			// Each line is displayed once, the incomplete line once the container exited
+			output := strings.Join(mockContext.Console.Output(), "\n")
+			require.Equal(t, 1, strings.Count(output, "Migrating database"))
+			require.Equal(t, 1, strings.Count(output, "Applying migra"))
+			require.Contains(t, output, "Applying migrations")
+			require.Contains(t, output, "Done")
+			require.Contains(t, output, "exited with code")
+		})
+	}
+}
+
+func Test_aciTarget_containerGroupName(t *testing.T) {
+	serviceTarget := &aciTarget{
+		env: environment.NewWithValues("dev", map[string]string{"JOB_NAME": "migrations"}),
+	}
+	serviceConfig := createTestServiceConfig("./src/job", AciTarget, ServiceLanguageDocker)
+
+	name, err := serviceTarget.containerGroupName(
+		serviceConfig, environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "existing", ""))
+	require.NoError(t, err)
+	require.Equal(t, "existing", name)
+
+	// The container group is created on deploy when it doesn't exist yet
+	targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+	name, err = serviceTarget.containerGroupName(serviceConfig, targetResource)
+	require.NoError(t, err)
+	require.Equal(t, "api", name)
+
+	serviceConfig.ResourceName = osutil.NewExpandableString("${JOB_NAME}")
+	name, err = serviceTarget.containerGroupName(serviceConfig, targetResource)
+	require.NoError(t, err)
+	require.Equal(t, "migrations", name)
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -20,6 +21,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/helm"
@@ -29,7 +31,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/braydonk/yaml"
 	"github.com/sethvargo/go-retry"
 )
 
@@ -64,6 +68,30 @@ type AksOptions struct {
 	Helm *helm.Config `yaml:"helm"`
 	// The kustomize configuration options
 	Kustomize *kustomize.Config `yaml:"kustomize"`
+	// The GitOps configuration options. When set, the manifests are committed to a Git repository which Flux syncs to
+	// the cluster instead of being applied directly
+	GitOps *AksGitOpsOptions `yaml:"gitops,omitempty"`
+	// The clusters the service is deployed to, instead of the cluster of the service
+	Clusters []AksClusterOptions `yaml:"clusters,omitempty"`
+	// The Azure Kubernetes Fleet Manager which member clusters the service is deployed to, instead of the cluster of
+	// the service
+	Fleet *AksFleetOptions `yaml:"fleet,omitempty"`
+	// How the access of the cluster to the container registry of the service is ensured. Defaults to 'prompt'
+	AcrAccess AksAcrAccess `yaml:"acrAccess,omitempty"`
+}
+
+// The AKS GitOps options
+type AksGitOpsOptions struct {
+	// The URL of the Git repository the rendered manifests are pushed to and Flux syncs from
+	Repository string `yaml:"repository"`
+	// The branch of the Git repository. Defaults to 'main'
+	Branch string `yaml:"branch,omitempty"`
+	// The path of the rendered manifests in the Git repository. Defaults to '<environment name>/<service name>'
+	Path string `yaml:"path,omitempty"`
+	// The name of the Flux configuration created on the cluster. Defaults to 'azd-<service name>'
+	Name string `yaml:"name,omitempty"`
+	// The namespace the Flux configuration is created in
+	Namespace string `yaml:"namespace,omitempty"`
 }
 
 // The AKS ingress options
@@ -88,14 +116,21 @@ type aksTarget struct {
 	console                input.Console
 	managedClustersService azapi.ManagedClustersService
 	resourceManager        ResourceManager
+	resourceService        *azapi.ResourceService
+	entraIdService         entraid.EntraIdService
 	kubectl                *kubectl.Cli
 	kubeLoginCli           *kubelogin.Cli
 	helmCli                *helm.Cli
 	kustomizeCli           *kustomize.Cli
+	gitCli                 *git.Cli
 	containerHelper        *ContainerHelper
 	featureManager         *alpha.FeatureManager
+	bindings               *ServiceBindings
 }
 
+// The API version used to get the kubelet identity of a cluster bound to other services and resources
+const managedClusterApiVersion = "2023-08-01"
+
 // Creates a new instance of the AKS service target
 func NewAksTarget(
 	env *environment.Environment,
@@ -103,12 +138,16 @@ func NewAksTarget(
 	console input.Console,
 	managedClustersService azapi.ManagedClustersService,
 	resourceManager ResourceManager,
+	resourceService *azapi.ResourceService,
+	entraIdService entraid.EntraIdService,
 	kubectlCli *kubectl.Cli,
 	kubeLoginCli *kubelogin.Cli,
 	helmCli *helm.Cli,
 	kustomizeCli *kustomize.Cli,
+	gitCli *git.Cli,
 	containerHelper *ContainerHelper,
 	featureManager *alpha.FeatureManager,
+	bindings *ServiceBindings,
 ) ServiceTarget {
 	return &aksTarget{
 		env:                    env,
@@ -116,12 +155,16 @@ func NewAksTarget(
 		console:                console,
 		managedClustersService: managedClustersService,
 		resourceManager:        resourceManager,
+		resourceService:        resourceService,
+		entraIdService:         entraIdService,
 		kubectl:                kubectlCli,
 		kubeLoginCli:           kubeLoginCli,
 		helmCli:                helmCli,
 		kustomizeCli:           kustomizeCli,
+		gitCli:                 gitCli,
 		containerHelper:        containerHelper,
 		featureManager:         featureManager,
+		bindings:               bindings,
 	}
 }
 
@@ -139,6 +182,10 @@ func (t *aksTarget) RequiredExternalTools(ctx context.Context, serviceConfig *Se
 		allTools = append(allTools, t.kustomizeCli)
 	}
 
+	if serviceConfig.K8s.GitOps != nil {
+		allTools = append(allTools, t.gitCli)
+	}
+
 	return allTools
 }
 
@@ -205,7 +252,8 @@ func (t *aksTarget) Deploy(
 	// Only deploy the container image if a package output has been defined
 	// Empty package details is a valid scenario for any AKS deployment that does not build any containers
 	// Ex) Helm charts, or other manifests that reference external images
-	if serviceConfig.Docker.RemoteBuild || packageOutput.Details != nil || packageOutput.PackagePath != "" {
+	pushesImage := serviceConfig.Docker.RemoteBuild || packageOutput.Details != nil || packageOutput.PackagePath != ""
+	if pushesImage {
 		// Login, tag & push container image to ACR
 		serviceDeployResult, err := t.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
 		if err != nil {
@@ -217,6 +265,79 @@ func (t *aksTarget) Deploy(
 	// Sync environment
 	t.kubectl.SetEnv(t.env.Dotenv())
 
+	// The access of each cluster to the registry is ensured when the service is deployed to it
+	if pushesImage && !serviceConfig.K8s.isMultiCluster() {
+		clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+		if err != nil {
+			return nil, err
+		}
+
+		err = t.ensureAcrAccess(
+			ctx, serviceConfig, targetResource, clusterName, t.getK8sNamespace(serviceConfig), progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// In GitOps mode the manifests are handed off to Flux instead of being applied directly
+	if serviceConfig.K8s.GitOps != nil {
+		if serviceConfig.K8s.isMultiCluster() {
+			return nil, errors.New("GitOps deployments don't support deploying to multiple clusters")
+		}
+
+		return t.deployGitOps(ctx, serviceConfig, packageOutput, targetResource, progress)
+	}
+
+	if serviceConfig.K8s.isMultiCluster() {
+		return t.deployClusters(ctx, serviceConfig, packageOutput, targetResource, pushesImage, progress)
+	}
+
+	deployment, endpoints, err := t.deployResources(ctx, serviceConfig, targetResource, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) > 0 {
+		// The AKS endpoints contain some additional identifying information
+		// Regex is used to pull the URL ignoring the additional metadata
+		// The last endpoint in the array will be the most publicly exposed
+		matches := endpointRegex.FindStringSubmatch(endpoints[len(endpoints)-1])
+		if len(matches) > 1 {
+			t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", matches[1])
+			if err := t.envManager.Save(ctx, t.env); err != nil {
+				return nil, fmt.Errorf("failed updating environment with endpoint url, %w", err)
+			}
+		}
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: azure.KubernetesServiceRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:      AksTarget,
+		Details:   deployment,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// deployResources deploys the k8s resources of the service to the cluster of the current kube context, and returns the
+// deployment of the service, when there is one, and the endpoints of the service.
+func (t *aksTarget) deployResources(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*kubectl.Deployment, []string, error) {
+	if len(serviceConfig.Uses) > 0 {
+		progress.SetProgress(NewServiceProgress("Binding services and resources"))
+		if err := t.bindServices(ctx, serviceConfig, targetResource); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Deploy k8s resources in the following order:
 	// 1. Helm
 	// 2. Kustomize
@@ -231,7 +352,7 @@ func (t *aksTarget) Deploy(
 	// Helm Support
 	helmDeployed, err := t.deployHelmCharts(ctx, serviceConfig, progress)
 	if err != nil {
-		return nil, fmt.Errorf("helm deployment failed: %w", err)
+		return nil, nil, fmt.Errorf("helm deployment failed: %w", err)
 	}
 
 	deployed = deployed || helmDeployed
@@ -239,7 +360,7 @@ func (t *aksTarget) Deploy(
 	// Kustomize Support
 	kustomizeDeployed, err := t.deployKustomize(ctx, serviceConfig, progress)
 	if err != nil {
-		return nil, fmt.Errorf("kustomize deployment failed: %w", err)
+		return nil, nil, fmt.Errorf("kustomize deployment failed: %w", err)
 	}
 
 	deployed = deployed || kustomizeDeployed
@@ -247,45 +368,22 @@ func (t *aksTarget) Deploy(
 	// Vanilla k8s manifests with minimal templating support
 	manifestsDeployed, deployment, err := t.deployManifests(ctx, serviceConfig, progress)
 	if err != nil && !os.IsNotExist(err) {
-		return nil, err
+		return nil, nil, err
 	}
 
 	deployed = deployed || manifestsDeployed
 
 	if !deployed {
-		return nil, errors.New("no deployment manifests found")
+		return nil, nil, errors.New("no deployment manifests found")
 	}
 
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
 	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if len(endpoints) > 0 {
-		// The AKS endpoints contain some additional identifying information
-		// Regex is used to pull the URL ignoring the additional metadata
-		// The last endpoint in the array will be the most publicly exposed
-		matches := endpointRegex.FindStringSubmatch(endpoints[len(endpoints)-1])
-		if len(matches) > 1 {
-			t.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", matches[1])
-			if err := t.envManager.Save(ctx, t.env); err != nil {
-				return nil, fmt.Errorf("failed updating environment with endpoint url, %w", err)
-			}
-		}
-	}
-
-	return &ServiceDeployResult{
-		Package: packageOutput,
-		TargetResourceId: azure.KubernetesServiceRID(
-			targetResource.SubscriptionId(),
-			targetResource.ResourceGroupName(),
-			targetResource.ResourceName(),
-		),
-		Kind:      AksTarget,
-		Details:   deployment,
-		Endpoints: endpoints,
-	}, nil
+	return deployment, endpoints, nil
 }
 
 // deployManifests deploys raw or templated yaml manifests to the k8s cluster
@@ -539,28 +637,20 @@ func (t *aksTarget) validateTargetResource(
 	return nil
 }
 
+// ensureClusterContext gets the credentials of the AKS cluster, merges them into the default kube config and sets the
+// cluster as the current kube context, with the given default namespace.
 func (t *aksTarget) ensureClusterContext(
 	ctx context.Context,
-	serviceConfig *ServiceConfig,
-	targetResource *environment.TargetResource,
+	subscriptionId string,
+	resourceGroupName string,
+	clusterName string,
 	defaultNamespace string,
 ) (string, error) {
-	kubeConfigPath := t.env.Getenv(kubectl.KubeConfigEnvVarName)
-	if kubeConfigPath != "" {
-		return kubeConfigPath, nil
-	}
-
-	// Login to AKS cluster
-	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
-	if err != nil {
-		return "", err
-	}
-
 	log.Printf("getting AKS credentials for cluster '%s'\n", clusterName)
 	clusterCreds, err := t.managedClustersService.GetUserCredentials(
 		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
+		subscriptionId,
+		resourceGroupName,
 		clusterName,
 	)
 	if err != nil {
@@ -597,7 +687,7 @@ func (t *aksTarget) ensureClusterContext(
 	}
 
 	// Create or update the kube config/context for the AKS cluster
-	kubeConfigPath, err = kubeConfigManager.AddOrUpdateContext(ctx, clusterName, kubeConfig)
+	kubeConfigPath, err := kubeConfigManager.AddOrUpdateContext(ctx, clusterName, kubeConfig)
 	if err != nil {
 		return "", fmt.Errorf("failed adding/updating kube context, %w", err)
 	}
@@ -605,8 +695,8 @@ func (t *aksTarget) ensureClusterContext(
 	// Get the provisioned cluster properties to inspect configuration
 	managedCluster, err := t.managedClustersService.Get(
 		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
+		subscriptionId,
+		resourceGroupName,
 		clusterName,
 	)
 	if err != nil {
@@ -652,6 +742,65 @@ func (t *aksTarget) ensureClusterContext(
 	return kubeConfigPath, nil
 }
 
+// bindServices resolves the services and resources used by the service. The connection environment variables which
+// aren't secrets are available to the templated manifests, and all of them are set in the '<service>-bindings' secret
+// of the namespace, which deployments reference with 'envFrom'.
+func (t *aksTarget) bindServices(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	bindings, err := t.bindings.Bind(
+		ctx,
+		serviceConfig,
+		azure.KubernetesServiceRID(
+			targetResource.SubscriptionId(), targetResource.ResourceGroupName(), targetResource.ResourceName()),
+		managedClusterApiVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	env, secretEnv := bindingsEnv(bindings)
+
+	templateEnv := t.env.Dotenv()
+	maps.Copy(templateEnv, env)
+	t.kubectl.SetEnv(templateEnv)
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	if err := t.ensureNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	secretData := maps.Clone(env)
+	maps.Copy(secretData, secretEnv)
+
+	secret, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"metadata": map[string]any{
+			"name":      bindingsSecretName(serviceConfig.Name),
+			"namespace": namespace,
+		},
+		"stringData": secretData,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.kubectl.ApplyWithStdIn(ctx, string(secret), &kubectl.KubeCliFlags{Namespace: namespace}); err != nil {
+		return fmt.Errorf("failed applying the bindings secret: %w", err)
+	}
+
+	return nil
+}
+
+// bindingsSecretName returns the name of the k8s secret holding the connection environment variables of a service
+func bindingsSecretName(serviceName string) string {
+	return strings.ReplaceAll(strings.ToLower(serviceName), "_", "-") + "-bindings"
+}
+
 // Ensures the k8s namespace exists otherwise creates it
 func (t *aksTarget) ensureNamespace(ctx context.Context, namespace string) error {
 	namespaceResult, err := t.kubectl.CreateNamespace(
@@ -846,6 +995,11 @@ func (t *aksTarget) getK8sNamespace(serviceConfig *ServiceConfig) string {
 
 func (t *aksTarget) setK8sContext(ctx context.Context, serviceConfig *ServiceConfig, eventName ext.Event) error {
 	t.kubectl.SetEnv(t.env.Dotenv())
+
+	// The context of each cluster is acquired when the service is deployed to it
+	if serviceConfig.K8s.isMultiCluster() {
+		return nil
+	}
 	hasCustomKubeConfig := false
 
 	// If a KUBECONFIG env var is set, use it.
@@ -861,9 +1015,17 @@ func (t *aksTarget) setK8sContext(ctx context.Context, serviceConfig *ServiceCon
 	}
 
 	defaultNamespace := t.getK8sNamespace(serviceConfig)
-	_, err = t.ensureClusterContext(ctx, serviceConfig, targetResource, defaultNamespace)
-	if err != nil {
-		return err
+	if !hasCustomKubeConfig {
+		clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+		if err != nil {
+			return err
+		}
+
+		_, err = t.ensureClusterContext(
+			ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), clusterName, defaultNamespace)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = t.ensureNamespace(ctx, defaultNamespace)
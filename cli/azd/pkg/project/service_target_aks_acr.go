@@ -0,0 +1,201 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// AksAcrAccess controls how azd ensures an AKS cluster can pull the images of a service from its Azure Container
+// Registry.
+type AksAcrAccess string
+
+const (
+	// Asks for confirmation before attaching the registry to the cluster when the cluster can't pull from the registry.
+	// Without confirmation, e.g. with --no-prompt, the registry isn't attached. This is the default.
+	AksAcrAccessPrompt AksAcrAccess = "prompt"
+	// Attaches the registry to the cluster, by assigning the AcrPull role on the registry to the kubelet identity of the
+	// cluster, when the cluster can't pull from the registry
+	AksAcrAccessAttach AksAcrAccess = "attach"
+	// Doesn't check nor configure the access of the cluster to the registry
+	AksAcrAccessNone AksAcrAccess = "none"
+)
+
+// The role definitions which allow pulling images from a registry: AcrPull, AcrPush, Contributor and Owner
+var acrPullRoleDefinitionIds = []string{
+	"7f951dda-4ed3-4680-a7ca-43fe172d538d",
+	"8311e382-0749-4cb8-b61a-304f252e45ec",
+	"b24988ac-6180-42a0-ab88-20f7382dd24c",
+	"8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+}
+
+// The AcrPull role definition, assigned to the kubelet identity when attaching a registry to a cluster
+const acrPullRoleDefinitionId = "7f951dda-4ed3-4680-a7ca-43fe172d538d"
+
+var acrAccessPromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id: "AKS_ACR_ACCESS",
+	Description: "Whether to attach the container registry to an AKS cluster which can't pull its images, " +
+		"by assigning the AcrPull role to the kubelet identity of the cluster.",
+	Commands: []string{"deploy", "up"},
+})
+
+// ensureAcrAccess ensures the cluster can pull the images pushed to the Azure Container Registry of the service, as
+// configured by 'k8s.acrAccess'. Otherwise pods fail with ImagePullBackOff once the deployment is over.
+//
+// Access is only granted through the kubelet identity of the cluster, like 'az aks update --attach-acr', since the
+// registry token of the user expires after a few hours. The role assignment is only created when 'k8s.acrAccess' is
+// 'attach' or the user confirms it.
+func (t *aksTarget) ensureAcrAccess(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	clusterName string,
+	namespace string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	mode := serviceConfig.K8s.AcrAccess
+	if mode == "" {
+		mode = AksAcrAccessPrompt
+	}
+
+	switch mode {
+	case AksAcrAccessNone:
+		return nil
+	case AksAcrAccessPrompt, AksAcrAccessAttach:
+	default:
+		return fmt.Errorf(
+			"invalid 'k8s.acrAccess' value '%s', expected one of 'prompt', 'attach' or 'none'", mode)
+	}
+
+	loginServer, err := t.containerHelper.RegistryName(ctx, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	// Clusters are expected to already be configured to pull from other registries
+	if !t.containerHelper.isAzureContainerRegistry(loginServer) {
+		return nil
+	}
+
+	progress.SetProgress(NewServiceProgress("Checking cluster access to container registry"))
+	cluster, err := t.managedClustersService.Get(
+		ctx, targetResource.SubscriptionId(), targetResource.ResourceGroupName(), clusterName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving managed cluster, %w", err)
+	}
+
+	kubeletObjectId := kubeletIdentityObjectId(cluster)
+	if kubeletObjectId == "" {
+		if mode == AksAcrAccessAttach {
+			return &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf(
+					"registry '%s' can't be attached to cluster '%s', which doesn't have a kubelet identity",
+					loginServer, clusterName),
+				Suggestion: "Suggestion: grant the cluster access to the registry, then set 'k8s.acrAccess' to " +
+					"'none' in azure.yaml.",
+			}
+		}
+
+		log.Printf("cluster '%s' doesn't have a kubelet identity, skipping the registry access check\n", clusterName)
+		return nil
+	}
+
+	registry, err := t.containerHelper.containerRegistryService.FindContainerRegistry(
+		ctx, targetResource.SubscriptionId(), loginServer)
+	if err != nil {
+		return fmt.Errorf("finding container registry '%s': %w", loginServer, err)
+	}
+
+	roleAssignments, err := t.resourceService.ListPrincipalRoleAssignments(
+		ctx, targetResource.SubscriptionId(), *registry.ID, kubeletObjectId)
+	if err != nil {
+		return err
+	}
+
+	if canPullFromRegistry(roleAssignments) {
+		return nil
+	}
+
+	if mode == AksAcrAccessPrompt {
+		attach, err := t.console.Confirm(ctx, input.ConsoleOptions{
+			Id: acrAccessPromptId,
+			Message: fmt.Sprintf(
+				"Cluster '%s' can't pull images from registry '%s'. "+
+					"Attach the registry (assign the AcrPull role to the kubelet identity of the cluster)?",
+				clusterName, loginServer),
+			Help: "Pods fail with ImagePullBackOff when the cluster can't pull their images. " +
+				"Set 'k8s.acrAccess' to 'attach' in azure.yaml to attach the registry without prompting.",
+			DefaultValue: false,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !attach {
+			t.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: Cluster '%s' can't pull images from registry '%s'. Set 'k8s.acrAccess' to 'attach' in "+
+					"azure.yaml or run 'az aks update --attach-acr' to grant access.", clusterName, loginServer))
+			return nil
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Attaching container registry to cluster"))
+	err = t.entraIdService.CreateRbac(
+		ctx,
+		targetResource.SubscriptionId(),
+		*registry.ID,
+		"/providers/Microsoft.Authorization/roleDefinitions/"+acrPullRoleDefinitionId,
+		kubeletObjectId,
+	)
+	if err != nil {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("attaching registry '%s' to cluster '%s': %w", loginServer, clusterName, err),
+			Suggestion: "Suggestion: assigning roles requires the Owner or User Access Administrator role on the " +
+				"registry. Otherwise ask an administrator to run 'az aks update --attach-acr'.",
+		}
+	}
+
+	return nil
+}
+
+// kubeletIdentityObjectId returns the object id of the kubelet identity of the cluster, empty when the cluster uses a
+// service principal instead.
+func kubeletIdentityObjectId(cluster *armcontainerservice.ManagedCluster) string {
+	if cluster.Properties == nil {
+		return ""
+	}
+
+	identity, has := cluster.Properties.IdentityProfile["kubeletidentity"]
+	if !has || identity == nil || identity.ObjectID == nil {
+		return ""
+	}
+
+	return *identity.ObjectID
+}
+
+// canPullFromRegistry returns true when one of the role assignments allows pulling images from the registry
+func canPullFromRegistry(roleAssignments []*armauthorization.RoleAssignment) bool {
+	return slices.ContainsFunc(roleAssignments, func(roleAssignment *armauthorization.RoleAssignment) bool {
+		if roleAssignment == nil || roleAssignment.Properties == nil ||
+			roleAssignment.Properties.RoleDefinitionID == nil {
+			return false
+		}
+
+		roleDefinitionId := strings.ToLower(path.Base(*roleAssignment.Properties.RoleDefinitionID))
+		return slices.Contains(acrPullRoleDefinitionIds, roleDefinitionId)
+	})
+}
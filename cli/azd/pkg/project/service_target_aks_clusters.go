@@ -0,0 +1,352 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+)
+
+// The options of an AKS cluster a service is deployed to
+type AksClusterOptions struct {
+	// The name of the AKS cluster
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the AKS cluster. Defaults to the resource group of the service
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup,omitempty"`
+	// The rollout stage of the cluster. The clusters of a stage are deployed once all the clusters of the previous
+	// stages are deployed successfully. Defaults to 0
+	Stage int `yaml:"stage,omitempty"`
+}
+
+// The options of an Azure Kubernetes Fleet Manager which member clusters a service is deployed to
+type AksFleetOptions struct {
+	// The name of the fleet
+	Name osutil.ExpandableString `yaml:"name"`
+	// The resource group of the fleet. Defaults to the resource group of the service
+	ResourceGroup osutil.ExpandableString `yaml:"resourceGroup,omitempty"`
+	// The update groups of the members, in their rollout order. The members of the groups which aren't listed are
+	// deployed last
+	Stages []string `yaml:"stages,omitempty"`
+}
+
+// isMultiCluster returns true when the service is deployed to a list of clusters or to the members of a fleet
+func (o AksOptions) isMultiCluster() bool {
+	return len(o.Clusters) > 0 || o.Fleet != nil
+}
+
+// aksCluster is a cluster a service is deployed to, with its rollout stage
+type aksCluster struct {
+	subscriptionId string
+	resourceGroup  string
+	name           string
+	// The order of the stage, the lower stages are deployed first
+	stage int
+	// The name of the stage displayed, ex) the update group of a fleet member
+	stageName string
+}
+
+// The status of the deployment of a service to a cluster
+type AksClusterDeployStatus string
+
+const (
+	AksClusterDeploySucceeded AksClusterDeployStatus = "Succeeded"
+	AksClusterDeployFailed    AksClusterDeployStatus = "Failed"
+	// The cluster wasn't deployed since the deployment of a previous stage failed
+	AksClusterDeploySkipped AksClusterDeployStatus = "Skipped"
+)
+
+// AksClusterDeployResult is the result of the deployment of a service to one of its clusters
+type AksClusterDeployResult struct {
+	Cluster       string                 `json:"cluster"`
+	ResourceGroup string                 `json:"resourceGroup"`
+	Stage         string                 `json:"stage"`
+	Status        AksClusterDeployStatus `json:"status"`
+	Endpoints     []string               `json:"endpoints,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+}
+
+// AksClustersDeployResult is the aggregated result of the deployment of a service to multiple clusters
+type AksClustersDeployResult struct {
+	Clusters []*AksClusterDeployResult `json:"clusters"`
+}
+
+func (r *AksClustersDeployResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+	for _, cluster := range r.Clusters {
+		status := string(cluster.Status)
+		switch cluster.Status {
+		case AksClusterDeploySucceeded:
+			status = output.WithSuccessFormat(status)
+		case AksClusterDeployFailed:
+			status = output.WithErrorFormat(status)
+		default:
+			status = output.WithGrayFormat(status)
+		}
+
+		builder.WriteString(fmt.Sprintf(
+			"%s- Cluster %s (stage %s): %s\n", currentIndentation, cluster.Cluster, cluster.Stage, status))
+
+		for _, endpoint := range cluster.Endpoints {
+			builder.WriteString(fmt.Sprintf("%s  - Endpoint: %s\n", currentIndentation, output.WithLinkFormat(endpoint)))
+		}
+
+		if cluster.Error != "" {
+			builder.WriteString(fmt.Sprintf("%s  - Error: %s\n", currentIndentation, cluster.Error))
+		}
+	}
+
+	return builder.String()
+}
+
+func (r *AksClustersDeployResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*r)
+}
+
+// deployClusters deploys the service to each of its clusters, stage by stage. The clusters of a stage are deployed
+// one after the other, and the clusters of the next stages aren't deployed when a cluster of a stage fails. The access
+// of each cluster to the registry is ensured when the service pushed an image.
+func (t *aksTarget) deployClusters(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	pushesImage bool,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	// The context of each cluster is merged in the default kube config
+	if t.env.Getenv(kubectl.KubeConfigEnvVarName) != "" {
+		return nil, fmt.Errorf(
+			"deploying to multiple clusters isn't supported when '%s' is set", kubectl.KubeConfigEnvVarName)
+	}
+
+	clusters, err := t.resolveClusters(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clusters) == 0 {
+		return nil, errors.New("no clusters were found to deploy the service to")
+	}
+
+	result := &AksClustersDeployResult{}
+	for _, cluster := range clusters {
+		result.Clusters = append(result.Clusters, &AksClusterDeployResult{
+			Cluster:       cluster.name,
+			ResourceGroup: cluster.resourceGroup,
+			Stage:         cluster.stageName,
+			Status:        AksClusterDeploySkipped,
+		})
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	endpoints := []string{}
+	var deployErr error
+	var failedStage int
+
+	for i, cluster := range clusters {
+		if deployErr != nil && cluster.stage > failedStage {
+			break
+		}
+
+		progress.SetProgress(NewServiceProgress(
+			fmt.Sprintf("Deploying to cluster %s (stage %s)", cluster.name, cluster.stageName)))
+
+		clusterEndpoints, err := t.deployCluster(ctx, serviceConfig, cluster, namespace, pushesImage, progress)
+		if err != nil {
+			result.Clusters[i].Status = AksClusterDeployFailed
+			result.Clusters[i].Error = err.Error()
+
+			// The other clusters of the stage are still deployed
+			if deployErr == nil {
+				deployErr = fmt.Errorf("deploying to cluster '%s' of stage %s: %w", cluster.name, cluster.stageName, err)
+				failedStage = cluster.stage
+			}
+			continue
+		}
+
+		result.Clusters[i].Status = AksClusterDeploySucceeded
+		result.Clusters[i].Endpoints = clusterEndpoints
+		endpoints = append(endpoints, clusterEndpoints...)
+	}
+
+	if deployErr != nil {
+		return nil, fmt.Errorf("%w\n\n%s", deployErr, result.ToString(""))
+	}
+
+	return &ServiceDeployResult{
+		Package:   packageOutput,
+		Kind:      AksTarget,
+		Details:   result,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// deployCluster sets the cluster as the current kube context and deploys the k8s resources of the service to it
+func (t *aksTarget) deployCluster(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	cluster aksCluster,
+	namespace string,
+	pushesImage bool,
+	progress *async.Progress[ServiceProgress],
+) ([]string, error) {
+	_, err := t.ensureClusterContext(ctx, cluster.subscriptionId, cluster.resourceGroup, cluster.name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.ensureNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
+
+	targetResource := environment.NewTargetResource(
+		cluster.subscriptionId,
+		cluster.resourceGroup,
+		cluster.name,
+		string(azapi.AzureResourceTypeManagedCluster),
+	)
+
+	if pushesImage {
+		err := t.ensureAcrAccess(ctx, serviceConfig, targetResource, cluster.name, namespace, progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, endpoints, err := t.deployResources(ctx, serviceConfig, targetResource, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// resolveClusters returns the clusters the service is deployed to, either listed in azure.yaml or the members of a
+// fleet, sorted by their rollout stage.
+func (t *aksTarget) resolveClusters(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]aksCluster, error) {
+	options := serviceConfig.K8s
+	if len(options.Clusters) > 0 && options.Fleet != nil {
+		return nil, errors.New("'clusters' and 'fleet' can't both be set")
+	}
+
+	clusters := []aksCluster{}
+	if options.Fleet != nil {
+		fleetClusters, err := t.resolveFleetClusters(ctx, *options.Fleet, targetResource)
+		if err != nil {
+			return nil, err
+		}
+
+		clusters = fleetClusters
+	}
+
+	for _, clusterOptions := range options.Clusters {
+		name, err := clusterOptions.Name.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving the name of the cluster: %w", err)
+		}
+
+		if name == "" {
+			return nil, errors.New("the name of each cluster must be set")
+		}
+
+		resourceGroup, err := clusterOptions.ResourceGroup.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving the resource group of cluster '%s': %w", name, err)
+		}
+
+		if resourceGroup == "" {
+			resourceGroup = targetResource.ResourceGroupName()
+		}
+
+		clusters = append(clusters, aksCluster{
+			subscriptionId: targetResource.SubscriptionId(),
+			resourceGroup:  resourceGroup,
+			name:           name,
+			stage:          clusterOptions.Stage,
+			stageName:      fmt.Sprint(clusterOptions.Stage),
+		})
+	}
+
+	slices.SortStableFunc(clusters, func(a, b aksCluster) int {
+		return a.stage - b.stage
+	})
+
+	return clusters, nil
+}
+
+// resolveFleetClusters returns the member clusters of the fleet, staged by the order of their update group.
+func (t *aksTarget) resolveFleetClusters(
+	ctx context.Context,
+	fleet AksFleetOptions,
+	targetResource *environment.TargetResource,
+) ([]aksCluster, error) {
+	fleetName, err := fleet.Name.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the name of the fleet: %w", err)
+	}
+
+	if fleetName == "" {
+		return nil, errors.New("the name of the fleet must be set")
+	}
+
+	resourceGroup, err := fleet.ResourceGroup.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the resource group of fleet '%s': %w", fleetName, err)
+	}
+
+	if resourceGroup == "" {
+		resourceGroup = targetResource.ResourceGroupName()
+	}
+
+	members, err := t.managedClustersService.ListFleetMembers(
+		ctx, targetResource.SubscriptionId(), resourceGroup, fleetName)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := []aksCluster{}
+	for _, member := range members {
+		clusterId, err := arm.ParseResourceID(member.ClusterResourceId)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the cluster of fleet member '%s': %w", member.Name, err)
+		}
+
+		// The members of the groups which aren't listed are deployed last
+		stage := slices.Index(fleet.Stages, member.Group)
+		if stage == -1 {
+			stage = len(fleet.Stages)
+		}
+
+		stageName := member.Group
+		if stageName == "" {
+			stageName = fmt.Sprint(stage)
+		}
+
+		clusters = append(clusters, aksCluster{
+			subscriptionId: clusterId.SubscriptionID,
+			resourceGroup:  clusterId.ResourceGroupName,
+			name:           clusterId.Name,
+			stage:          stage,
+			stageName:      stageName,
+		})
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+const defaultGitOpsBranch = "main"
+
+// deployGitOps hands the deployment of a service off to Flux: the manifests of the service are rendered with the values
+// of the environment and pushed to the GitOps repository, then a Flux configuration which syncs them to the cluster is
+// created or updated.
+//
+// Since the manifests aren't applied by azd, templates should reference the image of the service with
+// {{.Env.SERVICE_<NAME>_IMAGE_NAME}}.
+func (t *aksTarget) deployGitOps(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	gitOps := serviceConfig.K8s.GitOps
+	if gitOps.Repository == "" {
+		return nil, errors.New("the GitOps repository must be set to deploy with GitOps")
+	}
+
+	if serviceConfig.K8s.Helm != nil || serviceConfig.K8s.Kustomize != nil {
+		return nil, errors.New("GitOps deployments only support k8s manifests, helm and kustomize can't be used with GitOps")
+	}
+
+	deploymentPath := serviceConfig.K8s.DeploymentPath
+	if deploymentPath == "" {
+		deploymentPath = defaultDeploymentPath
+	}
+
+	deploymentPath = filepath.Join(serviceConfig.Path(), deploymentPath)
+	if _, err := os.Stat(deploymentPath); err != nil {
+		return nil, fmt.Errorf("no deployment manifests found: %w", err)
+	}
+
+	branch := gitOps.Branch
+	if branch == "" {
+		branch = defaultGitOpsBranch
+	}
+
+	repositoryPath := strings.Trim(gitOps.Path, "/")
+	if repositoryPath == "" {
+		repositoryPath = path.Join(t.env.Name(), serviceConfig.Name)
+	}
+
+	clusterName, err := t.resolveClusterName(serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	configName := gitOps.Name
+	if configName == "" {
+		configName = strings.ToLower(fmt.Sprintf("azd-%s", serviceConfig.Name))
+	}
+
+	cloneDir, err := os.MkdirTemp("", "azd-gitops")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer os.RemoveAll(cloneDir)
+
+	progress.SetProgress(NewServiceProgress("Cloning GitOps repository"))
+	if err := t.gitCli.ShallowClone(ctx, gitOps.Repository, branch, cloneDir); err != nil {
+		return nil, fmt.Errorf("cloning GitOps repository '%s': %w", gitOps.Repository, err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Rendering k8s manifests"))
+	// Manifests removed from the service are pruned from the cluster by Flux
+	manifestsPath := filepath.Join(cloneDir, filepath.FromSlash(repositoryPath))
+	if err := os.RemoveAll(manifestsPath); err != nil {
+		return nil, fmt.Errorf("removing previous manifests: %w", err)
+	}
+
+	if err := t.kubectl.RenderManifests(deploymentPath, manifestsPath); err != nil {
+		return nil, fmt.Errorf("rendering k8s manifests: %w", err)
+	}
+
+	if err := t.gitCli.AddFile(ctx, cloneDir, "."); err != nil {
+		return nil, err
+	}
+
+	hasChanges, err := t.gitCli.HasStagedChanges(ctx, cloneDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasChanges {
+		progress.SetProgress(NewServiceProgress("Pushing k8s manifests to GitOps repository"))
+		message := fmt.Sprintf("Deploy %s to %s", serviceConfig.Name, t.env.Name())
+		if err := t.gitCli.Commit(ctx, cloneDir, message); err != nil {
+			return nil, err
+		}
+
+		if err := t.gitCli.PushUpstream(ctx, cloneDir, "origin", branch); err != nil {
+			return nil, err
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Updating Flux configuration"))
+	err = t.managedClustersService.CreateOrUpdateFluxConfiguration(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+		&azapi.FluxConfiguration{
+			Name:          configName,
+			Namespace:     gitOps.Namespace,
+			RepositoryUrl: gitOps.Repository,
+			Branch:        branch,
+			Path:          "./" + repositoryPath,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("updating Flux configuration '%s': %w", configName, err)
+	}
+
+	// The cluster converges asynchronously, so the endpoints aren't known yet
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: azure.KubernetesServiceRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			clusterName,
+		),
+		Kind:      AksTarget,
+		Endpoints: []string{},
+	}, nil
+}
@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -23,14 +24,17 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/entraid"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/kubelogin"
 	"github.com/azure/azure-dev/cli/azd/pkg/kustomize"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
@@ -381,6 +385,357 @@ func Test_Deploy_Kustomize(t *testing.T) {
 	require.Equal(t, []string{"apply", "-k", filepath.FromSlash("kustomize/overlays/dev")}, kubectlApplyKustomize.Args)
 }
 
+func Test_Deploy_GitOps(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	var renderedManifests []string
+	mockResults := map[string]exec.RunArgs{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "git clone")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["git-clone"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "git -C") && strings.Contains(command, " add ")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		entries, err := os.ReadDir(filepath.Join(args.Args[1], "test", "api"))
+		if err != nil {
+			return exec.NewRunResult(1, "", ""), err
+		}
+
+		for _, entry := range entries {
+			renderedManifests = append(renderedManifests, entry.Name())
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "diff --cached")
+	}).Respond(exec.NewRunResult(0, "test/api/deployment.yaml", ""))
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, " commit ")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["git-commit"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, " push ")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["git-push"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	var fluxConfiguration map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(
+			request.URL.Path,
+			"managedClusters/AKS_CLUSTER/providers/Microsoft.KubernetesConfiguration/fluxConfigurations/azd-api",
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(request.Body).Decode(&fluxConfiguration); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"id":         request.URL.Path,
+			"properties": map[string]any{"provisioningState": "Succeeded"},
+		})
+	})
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.GitOps = &AksGitOpsOptions{
+		Repository: "https://github.com/contoso/gitops",
+	}
+
+	err = setupK8sManifests(t, &serviceConfig)
+	require.NoError(t, err)
+
+	env := createEnv()
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "",
+	}
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(azapi.AzureResourceTypeManagedCluster))
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, &serviceConfig, packageResult, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+	require.Empty(t, deployResult.Endpoints)
+
+	require.Contains(t, strings.Join(mockResults["git-clone"].Args, " "), "https://github.com/contoso/gitops")
+	require.Contains(t, mockResults["git-commit"].Args, "Deploy api to test")
+	require.Equal(t, "main", mockResults["git-push"].Args[len(mockResults["git-push"].Args)-1])
+	require.ElementsMatch(t, []string{"deployment.yaml", "service.yaml", "ingress.yaml"}, renderedManifests)
+
+	require.NotNil(t, fluxConfiguration)
+	properties := fluxConfiguration["properties"].(map[string]any)
+	require.Equal(t, "GitRepository", properties["sourceKind"])
+	kustomizations := properties["kustomizations"].(map[string]any)
+	require.Equal(t, "./test/api", kustomizations["azd-api"].(map[string]any)["path"])
+}
+
+func Test_Deploy_Fleet(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+	usedContexts := setupMocksForMultiCluster(mockContext, "")
+
+	clusterId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s"
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path, "/resourceGroups/FLEET_RG/providers/Microsoft.ContainerService/fleets/FLEET/members")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"name": "east",
+					"properties": map[string]any{
+						"clusterResourceId": fmt.Sprintf(clusterId, "RG_EAST", "AKS_EAST"), "group": "prod"},
+				},
+				{
+					"name":       "dev",
+					"properties": map[string]any{"clusterResourceId": fmt.Sprintf(clusterId, "RG_DEV", "AKS_DEV")},
+				},
+				{
+					"name": "west",
+					"properties": map[string]any{
+						"clusterResourceId": fmt.Sprintf(clusterId, "RG_WEST", "AKS_WEST"), "group": "canary"},
+				},
+			},
+		})
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Fleet = &AksFleetOptions{
+		Name:          osutil.NewExpandableString("FLEET"),
+		ResourceGroup: osutil.NewExpandableString("FLEET_RG"),
+		Stages:        []string{"canary", "prod"},
+	}
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	env := createEnv()
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+	deployResult, err := logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"AKS_WEST", "AKS_EAST", "AKS_DEV"}, *usedContexts)
+	require.Len(t, deployResult.Endpoints, 6)
+
+	details, ok := deployResult.Details.(*AksClustersDeployResult)
+	require.True(t, ok)
+	require.Len(t, details.Clusters, 3)
+	require.Equal(t, "canary", details.Clusters[0].Stage)
+	require.Equal(t, "prod", details.Clusters[1].Stage)
+	require.Equal(t, "RG_DEV", details.Clusters[2].ResourceGroup)
+	require.Equal(t, "2", details.Clusters[2].Stage)
+	for _, cluster := range details.Clusters {
+		require.Equal(t, AksClusterDeploySucceeded, cluster.Status)
+	}
+}
+
+func Test_Deploy_Clusters_StageFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+	usedContexts := setupMocksForMultiCluster(mockContext, "AKS_B")
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Clusters = []AksClusterOptions{
+		{Name: osutil.NewExpandableString("AKS_C"), Stage: 2},
+		{Name: osutil.NewExpandableString("AKS_A"), Stage: 1},
+		{Name: osutil.NewExpandableString("AKS_B"), Stage: 1},
+		{Name: osutil.NewExpandableString("AKS_D"), Stage: 1, ResourceGroup: osutil.NewExpandableString("${OTHER_RG}")},
+	}
+
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	env := createEnv()
+	env.DotenvSet("OTHER_RG", "RG_OTHER")
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env, nil)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+	_, err = logProgress(
+		t, func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{}, scope, progress)
+		},
+	)
+
+	// The other clusters of the failed stage are deployed, the clusters of the next stages aren't
+	require.ErrorContains(t, err, "deploying to cluster 'AKS_B' of stage 1")
+	require.ErrorContains(t, err, "Cluster AKS_C (stage 2): Skipped")
+	require.Equal(t, []string{"AKS_A", "AKS_D"}, *usedContexts)
+}
+
+func Test_Deploy_AcrAccess(t *testing.T) {
+	setup := func(t *testing.T, acrAccess AksAcrAccess) (*mocks.MockContext, *aksTarget, *ServiceConfig) {
+		tempDir := t.TempDir()
+		ostest.Chdir(t, tempDir)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		err := setupMocksForAksTarget(mockContext)
+		require.NoError(t, err)
+
+		serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+		serviceConfig.K8s.AcrAccess = acrAccess
+		serviceTarget := createAksServiceTarget(mockContext, serviceConfig, createEnv(), nil)
+
+		return mockContext, serviceTarget.(*aksTarget), serviceConfig
+	}
+
+	ensureAcrAccess := func(
+		t *testing.T, mockContext *mocks.MockContext, target *aksTarget, serviceConfig *ServiceConfig) error {
+		targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "AKS_CLUSTER", "")
+		_, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (any, error) {
+			return nil, target.ensureAcrAccess(
+				*mockContext.Context, serviceConfig, targetResource, "AKS_CLUSTER", "NAMESPACE", progress)
+		})
+		return err
+	}
+
+	mockRoleAssignmentCreate := func(mockContext *mocks.MockContext) *string {
+		var principalId string
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.Contains(
+				request.URL.Path, "/registries/REGISTRY/providers/Microsoft.Authorization/roleAssignments/")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			var body map[string]map[string]string
+			if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+
+			principalId = body["properties"]["principalId"]
+			return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, body)
+		})
+
+		return &principalId
+	}
+
+	t.Run("Attached", func(t *testing.T) {
+		mockContext, target, serviceConfig := setup(t, "")
+		principalId := mockRoleAssignmentCreate(mockContext)
+
+		require.NoError(t, ensureAcrAccess(t, mockContext, target, serviceConfig))
+		require.Empty(t, *principalId)
+	})
+
+	t.Run("Attach", func(t *testing.T) {
+		mockContext, target, serviceConfig := setup(t, AksAcrAccessAttach)
+		setupRoleAssignmentsMock(mockContext, "acdd72a7-3385-48ef-bd42-f606fba81ae7")
+		principalId := mockRoleAssignmentCreate(mockContext)
+
+		require.NoError(t, ensureAcrAccess(t, mockContext, target, serviceConfig))
+		require.Equal(t, "KUBELET_OBJECT_ID", *principalId)
+	})
+
+	t.Run("PromptConfirmed", func(t *testing.T) {
+		mockContext, target, serviceConfig := setup(t, "")
+		setupRoleAssignmentsMock(mockContext)
+		principalId := mockRoleAssignmentCreate(mockContext)
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "can't pull images from registry 'REGISTRY.azurecr.io'")
+		}).Respond(true)
+
+		require.NoError(t, ensureAcrAccess(t, mockContext, target, serviceConfig))
+		require.Equal(t, "KUBELET_OBJECT_ID", *principalId)
+	})
+
+	// Like --no-prompt, the role assignment isn't created without confirmation
+	t.Run("PromptDefault", func(t *testing.T) {
+		mockContext, target, serviceConfig := setup(t, "")
+		setupRoleAssignmentsMock(mockContext)
+		principalId := mockRoleAssignmentCreate(mockContext)
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "can't pull images from registry 'REGISTRY.azurecr.io'")
+		}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+			return options.DefaultValue, nil
+		})
+
+		require.NoError(t, ensureAcrAccess(t, mockContext, target, serviceConfig))
+		require.Empty(t, *principalId)
+		require.Contains(t, strings.Join(mockContext.Console.Output(), "\n"), "WARNING: Cluster 'AKS_CLUSTER'")
+	})
+
+	t.Run("None", func(t *testing.T) {
+		mockContext, target, serviceConfig := setup(t, AksAcrAccessNone)
+		setupRoleAssignmentsMock(mockContext)
+		principalId := mockRoleAssignmentCreate(mockContext)
+
+		require.NoError(t, ensureAcrAccess(t, mockContext, target, serviceConfig))
+		require.Empty(t, *principalId)
+	})
+}
+
+// setupMocksForMultiCluster mocks the clusters of any name, and returns the kube contexts used. Setting the kube
+// context of the failing cluster fails.
+func setupMocksForMultiCluster(mockContext *mocks.MockContext, failingCluster string) *[]string {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(
+			request.URL.Path, "Microsoft.ContainerService/managedClusters/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerservice.ManagedCluster{
+			ID:         to.Ptr(request.URL.Path),
+			Properties: &armcontainerservice.ManagedClusterProperties{},
+		})
+	})
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl set image")
+	}).Respond(exec.NewRunResult(0, "", ""))
+
+	usedContexts := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl config use-context")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		context := args.Args[len(args.Args)-1]
+		if context == failingCluster {
+			return exec.NewRunResult(1, "", "context not found"), errors.New("context not found")
+		}
+
+		usedContexts = append(usedContexts, context)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return &usedContexts
+}
+
 func setupK8sManifests(t *testing.T, serviceConfig *ServiceConfig) error {
 	manifestsDir := filepath.Join(serviceConfig.RelativePath, defaultDeploymentPath)
 	err := os.MkdirAll(manifestsDir, osutil.PermissionDirectory)
@@ -468,12 +823,34 @@ func setupMocksForAksTarget(mockContext *mocks.MockContext) error {
 
 	setupGetClusterMock(mockContext, http.StatusOK)
 	setupMocksForAcr(mockContext)
+	setupRoleAssignmentsMock(mockContext, acrPullRoleDefinitionId)
 	setupMocksForKubectl(mockContext)
 	setupMocksForDocker(mockContext)
 
 	return nil
 }
 
+// setupRoleAssignmentsMock mocks the role assignments of the kubelet identity on the registry
+func setupRoleAssignmentsMock(mockContext *mocks.MockContext, roleDefinitionIds ...string) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path, "/registries/REGISTRY/providers/Microsoft.Authorization/roleAssignments")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		roleAssignments := []map[string]any{}
+		for _, roleDefinitionId := range roleDefinitionIds {
+			roleAssignments = append(roleAssignments, map[string]any{
+				"properties": map[string]any{
+					"principalId": "KUBELET_OBJECT_ID",
+					"roleDefinitionId": "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/" +
+						"roleDefinitions/" + roleDefinitionId,
+				},
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"value": roleAssignments})
+	})
+}
+
 func setupGetClusterMock(mockContext *mocks.MockContext, statusCode int) {
 	// Get cluster configuration
 	mockContext.HttpClient.When(func(request *http.Request) bool {
@@ -490,6 +867,9 @@ func setupGetClusterMock(mockContext *mocks.MockContext, statusCode int) {
 				Properties: &armcontainerservice.ManagedClusterProperties{
 					EnableRBAC:           to.Ptr(true),
 					DisableLocalAccounts: to.Ptr(false),
+					IdentityProfile: map[string]*armcontainerservice.UserAssignedIdentity{
+						"kubeletidentity": {ObjectID: to.Ptr("KUBELET_OBJECT_ID")},
+					},
 				},
 			},
 		}
@@ -871,12 +1251,16 @@ func createAksServiceTarget(
 		mockContext.Console,
 		managedClustersService,
 		resourceManager,
+		azapi.NewResourceService(credentialProvider, mockContext.ArmClientOptions),
+		entraid.NewEntraIdService(credentialProvider, mockContext.ArmClientOptions, mockContext.CoreClientOptions),
 		kubeCtl,
 		kubeLoginCli,
 		helmCli,
 		kustomizeCli,
+		git.NewCli(mockContext.CommandRunner),
 		containerHelper,
 		alpha.NewFeaturesManagerWithConfig(userConfig),
+		nil,
 	)
 }
 
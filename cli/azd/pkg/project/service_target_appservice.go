@@ -6,42 +6,113 @@ package project
 import (
 	"context"
 	"fmt"
+	"log"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
+// The Azure App Service options
+type AppServiceOptions struct {
+	// The sidecar containers running next to the main container of a Linux App Service
+	Sidecars []AppServiceSidecar `yaml:"sidecars,omitempty"`
+	// The app settings of the App Service or Function App, applied on deploy, ex) API_URL: ${API_URL}. Only the settings
+	// that changed are written, and the other settings of the app are kept.
+	AppSettings map[string]osutil.ExpandableString `yaml:"appSettings,omitempty"`
+	// If true, the package of the App Service or Function App is deployed with Kudu zipdeploy, rather than with
+	// OneDeploy on Linux apps which support it.
+	ZipDeploy bool `yaml:"zipDeploy,omitempty"`
+}
+
+// zipDeployOptions returns the options of the deployment of the package of the service
+func (o AppServiceOptions) zipDeployOptions() *azapi.ZipDeployOptions {
+	return &azapi.ZipDeployOptions{DisableOneDeploy: o.ZipDeploy}
+}
+
+// AppServiceSidecar is a sidecar container of a Linux App Service, deployed with the sitecontainers API.
+type AppServiceSidecar struct {
+	// The name of the container
+	Name string `yaml:"name"`
+	// The image of the container, ex) mcr.microsoft.com/oss/redis:latest
+	Image osutil.ExpandableString `yaml:"image"`
+	// The port the container listens on, if any
+	Port int `yaml:"port,omitempty"`
+	// The environment variables of the container. The values are stored in app settings of the App Service
+	Env map[string]osutil.ExpandableString `yaml:"env,omitempty"`
+}
+
+// appServiceContainersDeployResult is the result of deploying the containers of a Linux App Service.
+type appServiceContainersDeployResult struct {
+	// The images of the containers, by container name
+	Images map[string]string `json:"images"`
+	// The URL listing the logs of the containers
+	LogsUrl string `json:"logsUrl,omitempty"`
+}
+
+// The name of the main container created by azd, when the App Service doesn't have one yet
+const appServiceMainContainerName = "main"
+
 type appServiceTarget struct {
-	env *environment.Environment
-	cli *azapi.AzureClient
+	env             *environment.Environment
+	cli             *azapi.AzureClient
+	containerHelper *ContainerHelper
 }
 
 // NewAppServiceTarget creates a new instance of the AppServiceTarget
 func NewAppServiceTarget(
 	env *environment.Environment,
 	azCli *azapi.AzureClient,
+	containerHelper *ContainerHelper,
 ) ServiceTarget {
 	return &appServiceTarget{
-		env: env,
-		cli: azCli,
+		env:             env,
+		cli:             azCli,
+		containerHelper: containerHelper,
 	}
 }
 
 // Gets the required external tools
 func (st *appServiceTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	if isAppServiceContainer(serviceConfig) {
+		return st.containerHelper.RequiredExternalTools(ctx, serviceConfig)
+	}
+
 	return []tools.ExternalTool{}
 }
 
 // Initializes the AppService target
 func (st *appServiceTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	names := []string{}
+	for _, sidecar := range serviceConfig.AppService.Sidecars {
+		if sidecar.Name == "" || sidecar.Image.Empty() {
+			return fmt.Errorf("the sidecars of service '%s' must set a 'name' and an 'image'", serviceConfig.Name)
+		}
+
+		if slices.Contains(names, sidecar.Name) {
+			return fmt.Errorf("the sidecar '%s' of service '%s' is defined more than once", sidecar.Name, serviceConfig.Name)
+		}
+
+		names = append(names, sidecar.Name)
+	}
+
 	return nil
 }
 
+// isAppServiceContainer returns true when the service runs as the main container of a Linux App Service, instead of
+// being deployed as a zip package.
+func isAppServiceContainer(serviceConfig *ServiceConfig) bool {
+	return serviceConfig.Language == ServiceLanguageDocker
+}
+
 // Prepares a zip archive from the specified build output
 func (st *appServiceTarget) Package(
 	ctx context.Context,
@@ -49,6 +120,11 @@ func (st *appServiceTarget) Package(
 	packageOutput *ServicePackageResult,
 	progress *async.Progress[ServiceProgress],
 ) (*ServicePackageResult, error) {
+	// The container image is pushed to the container registry on deploy
+	if isAppServiceContainer(serviceConfig) {
+		return packageOutput, nil
+	}
+
 	progress.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
 	zipFilePath, err := createDeployableZip(
 		serviceConfig,
@@ -76,6 +152,22 @@ func (st *appServiceTarget) Deploy(
 		return nil, fmt.Errorf("validating target resource: %w", err)
 	}
 
+	// The settings are applied before the code, so the new code starts with them
+	appSettings, err := updateAppSettingsWithProgress(ctx, st.cli, st.env, serviceConfig, targetResource, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAppServiceContainer(serviceConfig) {
+		sdr, err := st.deployContainers(ctx, serviceConfig, packageOutput, targetResource, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		sdr.AppSettings = appSettings
+		return sdr, nil
+	}
+
 	zipFile, err := os.Open(packageOutput.PackagePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed reading deployment zip file: %w", err)
@@ -84,19 +176,31 @@ func (st *appServiceTarget) Deploy(
 	defer os.Remove(packageOutput.PackagePath)
 	defer zipFile.Close()
 
-	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
+	uploadReader, err := newUploadProgressReader(zipFile, progress)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := st.cli.DeployAppServiceZip(
 		ctx,
 		targetResource.SubscriptionId(),
 		targetResource.ResourceGroupName(),
 		targetResource.ResourceName(),
-		zipFile,
+		uploadReader,
 		func(logProgress string) { progress.SetProgress(NewServiceProgress(logProgress)) },
+		serviceConfig.AppService.zipDeployOptions(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err)
 	}
 
+	// Sidecars can also run next to an app deployed from code
+	if len(serviceConfig.AppService.Sidecars) > 0 {
+		if _, err := st.updateSiteContainers(ctx, serviceConfig, targetResource, "", progress); err != nil {
+			return nil, err
+		}
+	}
+
 	progress.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
 	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
 	if err != nil {
@@ -114,10 +218,267 @@ func (st *appServiceTarget) Deploy(
 		endpoints,
 	)
 	sdr.Package = packageOutput
+	sdr.AppSettings = appSettings
 
 	return sdr, nil
 }
 
+// deployContainers pushes the image of the service and deploys it as the main container of the App Service, next to
+// its sidecars.
+func (st *appServiceTarget) deployContainers(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	// Login, tag & push container image to ACR
+	_, err := st.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	imageName := st.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+	result, err := st.updateSiteContainers(ctx, serviceConfig, targetResource, imageName, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
+	endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployResult{
+		Package: packageOutput,
+		TargetResourceId: azure.WebsiteRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:      AppServiceTarget,
+		Details:   result,
+		Endpoints: endpoints,
+	}, nil
+}
+
+// updateSiteContainers updates the image of the main container, when mainImage is set, and the sidecars of the App
+// Service, then restarts it to run the new containers.
+func (st *appServiceTarget) updateSiteContainers(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	mainImage string,
+	progress *async.Progress[ServiceProgress],
+) (*appServiceContainersDeployResult, error) {
+	subscriptionId := targetResource.SubscriptionId()
+	resourceGroup := targetResource.ResourceGroupName()
+	appName := targetResource.ResourceName()
+
+	existing, err := st.cli.GetAppServiceSiteContainers(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	main := azapi.SiteContainer{
+		Name:       appServiceMainContainerName,
+		IsMain:     true,
+		TargetPort: "80",
+		AuthType:   azapi.SiteContainerAuthSystemIdentity,
+	}
+	existingByName := map[string]azapi.SiteContainer{}
+	for _, container := range existing {
+		existingByName[container.Name] = container
+		if container.IsMain {
+			main = container
+		}
+	}
+
+	result := &appServiceContainersDeployResult{
+		Images: map[string]string{},
+	}
+
+	if mainImage != "" {
+		main.Image = mainImage
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Updating container %s", main.Name)))
+		if err := st.cli.UpdateAppServiceSiteContainer(ctx, subscriptionId, resourceGroup, appName, main); err != nil {
+			return nil, err
+		}
+		result.Images[main.Name] = main.Image
+	}
+
+	sidecars := make([]azapi.SiteContainer, 0, len(serviceConfig.AppService.Sidecars))
+	appSettings := map[string]string{}
+	for _, sidecar := range serviceConfig.AppService.Sidecars {
+		image, err := sidecar.Image.Envsubst(st.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding image of sidecar '%s': %w", sidecar.Name, err)
+		}
+
+		container := azapi.SiteContainer{
+			Name:     sidecar.Name,
+			Image:    image,
+			AuthType: azapi.SiteContainerAuthAnonymous,
+		}
+		if current, has := existingByName[sidecar.Name]; has {
+			// keep how the sidecar authenticates to its registry
+			container.AuthType = current.AuthType
+			container.UserName = current.UserName
+			container.UserManagedIdentityClientId = current.UserManagedIdentityClientId
+		} else if registryHost(image) != "" && registryHost(image) == registryHost(main.Image) {
+			// images from the registry of the main container are pulled the same way
+			container.AuthType = main.AuthType
+			container.UserName = main.UserName
+			container.UserManagedIdentityClientId = main.UserManagedIdentityClientId
+		}
+		if sidecar.Port > 0 {
+			container.TargetPort = strconv.Itoa(sidecar.Port)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(sidecar.Env)) {
+			value, err := sidecar.Env[name].Envsubst(st.env.Getenv)
+			if err != nil {
+				return nil, fmt.Errorf("expanding environment variable '%s' of sidecar '%s': %w", name, sidecar.Name, err)
+			}
+
+			settingName := sidecarAppSettingName(sidecar.Name, name)
+			appSettings[settingName] = value
+			container.EnvironmentVariables = append(container.EnvironmentVariables, azapi.SiteContainerEnvironmentVariable{
+				Name:  name,
+				Value: settingName,
+			})
+		}
+
+		sidecars = append(sidecars, container)
+	}
+
+	if len(appSettings) > 0 {
+		progress.SetProgress(NewServiceProgress("Updating app settings of sidecars"))
+		if _, err := st.cli.UpdateAppServiceAppSettings(ctx, subscriptionId, resourceGroup, appName, appSettings); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, sidecar := range sidecars {
+		progress.SetProgress(NewServiceProgress(fmt.Sprintf("Updating sidecar container %s", sidecar.Name)))
+		if err := st.cli.UpdateAppServiceSiteContainer(ctx, subscriptionId, resourceGroup, appName, sidecar); err != nil {
+			return nil, err
+		}
+		result.Images[sidecar.Name] = sidecar.Image
+	}
+
+	progress.SetProgress(NewServiceProgress("Restarting app service"))
+	if err := st.cli.RestartAppService(ctx, subscriptionId, resourceGroup, appName); err != nil {
+		return nil, err
+	}
+
+	logsUrl, err := st.cli.AppServiceContainerLogsUrl(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		log.Printf("failed getting the container logs url of app service '%s': %v", appName, err)
+	}
+	result.LogsUrl = logsUrl
+
+	return result, nil
+}
+
+// UpdateAppSettings applies the app settings of an App Service or Function App service, expanded from the environment,
+// and returns the settings that changed. It returns nil when the service doesn't declare app settings.
+func UpdateAppSettings(
+	ctx context.Context,
+	cli *azapi.AzureClient,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]azapi.AppSettingChange, error) {
+	if len(serviceConfig.AppService.AppSettings) == 0 {
+		return nil, nil
+	}
+
+	settings, err := expandAppSettings(env, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.UpdateAppServiceAppSettings(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		settings,
+	)
+}
+
+// AppSettingChanges returns the changes UpdateAppSettings would make to the app settings of the service, without
+// applying them.
+func AppSettingChanges(
+	ctx context.Context,
+	cli *azapi.AzureClient,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]azapi.AppSettingChange, error) {
+	settings, err := expandAppSettings(env, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.AppServiceAppSettingChanges(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		settings,
+	)
+}
+
+// expandAppSettings returns the app settings of the service, with the environment variables they reference expanded
+func expandAppSettings(env *environment.Environment, serviceConfig *ServiceConfig) (map[string]string, error) {
+	settings := map[string]string{}
+	for name, value := range serviceConfig.AppService.AppSettings {
+		expanded, err := value.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding app setting '%s' of service '%s': %w", name, serviceConfig.Name, err)
+		}
+
+		settings[name] = expanded
+	}
+
+	return settings, nil
+}
+
+// updateAppSettingsWithProgress applies the app settings of the service, reporting the progress when it declares any
+func updateAppSettingsWithProgress(
+	ctx context.Context,
+	cli *azapi.AzureClient,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) ([]azapi.AppSettingChange, error) {
+	if len(serviceConfig.AppService.AppSettings) == 0 {
+		return nil, nil
+	}
+
+	progress.SetProgress(NewServiceProgress("Updating app settings"))
+	return UpdateAppSettings(ctx, cli, env, serviceConfig, targetResource)
+}
+
+// sidecarAppSettingName returns the name of the app setting holding the value of an environment variable of a sidecar.
+func sidecarAppSettingName(sidecarName string, envName string) string {
+	return environment.Key(fmt.Sprintf("SIDECAR_%s_%s", sidecarName, envName))
+}
+
+// registryHost returns the host of the registry of a container image, or an empty string for images of Docker Hub.
+func registryHost(image string) string {
+	host, _, found := strings.Cut(image, "/")
+	if !found || !strings.ContainsAny(host, ".:") {
+		return ""
+	}
+
+	return strings.ToLower(host)
+}
+
 // Gets the exposed endpoints for the App Service
 func (st *appServiceTarget) Endpoints(
 	ctx context.Context,
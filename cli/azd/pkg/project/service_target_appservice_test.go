@@ -4,11 +4,16 @@
 package project
 
 import (
+	"context"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +58,221 @@ func TestNewAppServiceTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_appServiceTarget_Initialize_Sidecars(t *testing.T) {
+	serviceTarget := &appServiceTarget{}
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+
+	serviceConfig.AppService.Sidecars = []AppServiceSidecar{
+		{Name: "redis", Image: osutil.NewExpandableString("redis:7")},
+	}
+	require.NoError(t, serviceTarget.Initialize(context.Background(), serviceConfig))
+
+	serviceConfig.AppService.Sidecars = []AppServiceSidecar{{Name: "redis"}}
+	err := serviceTarget.Initialize(context.Background(), serviceConfig)
+	require.ErrorContains(t, err, "must set a 'name' and an 'image'")
+
+	serviceConfig.AppService.Sidecars = []AppServiceSidecar{
+		{Name: "redis", Image: osutil.NewExpandableString("redis:7")},
+		{Name: "redis", Image: osutil.NewExpandableString("redis:6")},
+	}
+	err = serviceTarget.Initialize(context.Background(), serviceConfig)
+	require.ErrorContains(t, err, "defined more than once")
+}
+
+func Test_appServiceTarget_updateSiteContainers(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("test", map[string]string{
+		"AZURE_CONTAINER_REGISTRY_ENDPOINT": "contoso.azurecr.io",
+		"OTEL_ENDPOINT":                     "http://localhost:4317",
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/sites/WEB_APP/sitecontainers")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"name": "main",
+					"properties": map[string]any{
+						"image":      "contoso.azurecr.io/app/web:azd-1",
+						"isMain":     true,
+						"targetPort": "8080",
+						"authType":   "SystemIdentity",
+					},
+				},
+			},
+		})
+	})
+
+	appSettings := map[string]*string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/config/appsettings/list")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"properties": map[string]any{"EXISTING": "value"},
+		})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/config/appsettings")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var body struct {
+			Properties map[string]*string `json:"properties"`
+		}
+		if err := mocks.ReadHttpBody(request.Body, &body); err != nil {
+			return nil, err
+		}
+		appSettings = body.Properties
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, body)
+	})
+
+	updated := map[string]azapi.SiteContainer{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "/sites/WEB_APP/sitecontainers/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var body struct {
+			Properties azapi.SiteContainer `json:"properties"`
+		}
+		if err := mocks.ReadHttpBody(request.Body, &body); err != nil {
+			return nil, err
+		}
+		updated[request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]] = body.Properties
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{})
+	})
+
+	restarted := false
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/sites/WEB_APP/restart")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		restarted = true
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/sites/WEB_APP")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"properties": map[string]any{
+				"hostNameSslStates": []map[string]any{
+					{"name": "web-app.scm.azurewebsites.net", "hostType": "Repository"},
+				},
+			},
+		})
+	})
+
+	serviceTarget := &appServiceTarget{
+		env: env,
+		cli: azapi.NewAzureClient(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+	}
+
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+	serviceConfig.AppService.Sidecars = []AppServiceSidecar{
+		{
+			Name:  "otel",
+			Image: osutil.NewExpandableString("${AZURE_CONTAINER_REGISTRY_ENDPOINT}/otel-collector:latest"),
+			Port:  4317,
+			Env: map[string]osutil.ExpandableString{
+				"OTEL_ENDPOINT": osutil.NewExpandableString("${OTEL_ENDPOINT}"),
+			},
+		},
+		{Name: "redis", Image: osutil.NewExpandableString("redis:7")},
+	}
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "WEB_APP", string(azapi.AzureResourceTypeWebSite))
+
+	result, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (
+		*appServiceContainersDeployResult, error) {
+		return serviceTarget.updateSiteContainers(
+			*mockContext.Context, serviceConfig, targetResource, "contoso.azurecr.io/app/web:azd-2", progress)
+	})
+	require.NoError(t, err)
+	require.True(t, restarted)
+
+	require.Equal(t, map[string]string{
+		"main":  "contoso.azurecr.io/app/web:azd-2",
+		"otel":  "contoso.azurecr.io/otel-collector:latest",
+		"redis": "redis:7",
+	}, result.Images)
+	require.Equal(t, "https://web-app.scm.azurewebsites.net/api/logs/docker", result.LogsUrl)
+
+	// the main container keeps its settings
+	require.True(t, updated["main"].IsMain)
+	require.Equal(t, "8080", updated["main"].TargetPort)
+
+	// the sidecar from the registry of the main container pulls its image the same way
+	require.Equal(t, azapi.SiteContainerAuthSystemIdentity, updated["otel"].AuthType)
+	require.Equal(t, "4317", updated["otel"].TargetPort)
+	require.Equal(t, []azapi.SiteContainerEnvironmentVariable{
+		{Name: "OTEL_ENDPOINT", Value: "SIDECAR_OTEL_OTEL_ENDPOINT"},
+	}, updated["otel"].EnvironmentVariables)
+	require.Equal(t, azapi.SiteContainerAuthAnonymous, updated["redis"].AuthType)
+
+	require.Equal(t, "value", *appSettings["EXISTING"])
+	require.Equal(t, "http://localhost:4317", *appSettings["SIDECAR_OTEL_OTEL_ENDPOINT"])
+}
+
+func Test_registryHost(t *testing.T) {
+	require.Equal(t, "contoso.azurecr.io", registryHost("contoso.azurecr.io/app/web:azd-1"))
+	require.Equal(t, "localhost:5000", registryHost("localhost:5000/web"))
+	require.Equal(t, "", registryHost("redis:7"))
+	require.Equal(t, "", registryHost("library/redis:7"))
+}
+
+func Test_UpdateAppSettings(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("test", map[string]string{
+		"API_URL": "https://api.contoso.com",
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/config/appsettings/list")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"properties": map[string]any{"EXISTING": "value", "API_URL": "https://api.contoso.com"},
+		})
+	})
+
+	var written map[string]*string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, "/config/appsettings")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var body struct {
+			Properties map[string]*string `json:"properties"`
+		}
+		if err := mocks.ReadHttpBody(request.Body, &body); err != nil {
+			return nil, err
+		}
+		written = body.Properties
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, body)
+	})
+
+	cli := azapi.NewAzureClient(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "WEB_APP", string(azapi.AzureResourceTypeWebSite))
+
+	// Without app settings, nothing is applied
+	changes, err := UpdateAppSettings(*mockContext.Context, cli, env, serviceConfig, targetResource)
+	require.NoError(t, err)
+	require.Nil(t, changes)
+
+	// Unchanged settings aren't written, so the app isn't restarted
+	serviceConfig.AppService.AppSettings = map[string]osutil.ExpandableString{
+		"API_URL": osutil.NewExpandableString("${API_URL}"),
+	}
+	changes, err = UpdateAppSettings(*mockContext.Context, cli, env, serviceConfig, targetResource)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	require.Nil(t, written)
+
+	serviceConfig.AppService.AppSettings["LOG_LEVEL"] = osutil.NewExpandableString("debug")
+	changes, err = UpdateAppSettings(*mockContext.Context, cli, env, serviceConfig, targetResource)
+	require.NoError(t, err)
+	require.Equal(t, []azapi.AppSettingChange{{Name: "LOG_LEVEL", Kind: azapi.AppSettingAdded}}, changes)
+	require.Equal(t, "value", *written["EXISTING"])
+	require.Equal(t, "debug", *written["LOG_LEVEL"])
+	require.Equal(t, "LOG_LEVEL (added)", FormatAppSettingChanges(changes))
+}
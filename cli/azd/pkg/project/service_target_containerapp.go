@@ -5,7 +5,10 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -13,17 +16,60 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
+// The Azure Container Apps options
+type ContainerAppOptions struct {
+	// The suffix of the revisions created by deployments. Defaults to a timestamp based suffix
+	RevisionSuffix osutil.ExpandableString `yaml:"revisionSuffix,omitempty"`
+	// The revisions mode of the container app, 'single' or 'multiple'. Deploying with 'multiple' activates the
+	// multiple revision mode on the container app
+	RevisionsMode ContainerAppRevisionsMode `yaml:"revisionsMode,omitempty"`
+	// The percentage of traffic sent to the latest revision after a deployment in multiple revision mode. The remaining
+	// traffic is sent to the previous revision. Defaults to 100
+	LatestRevisionWeight *int32 `yaml:"latestRevisionWeight,omitempty"`
+	// The environment variables set on the container of the container app on deploy. Secret variables are stored in
+	// secrets of the container app, or reference Key Vault secrets
+	Env []ContainerAppEnv `yaml:"env,omitempty"`
+}
+
+// ContainerAppEnv is an environment variable of the container of a container app
+type ContainerAppEnv struct {
+	// The name of the variable, ex) DB_PASSWORD
+	Name string `yaml:"name"`
+	// The value of the variable, ex) ${DB_PASSWORD}
+	Value osutil.ExpandableString `yaml:"value,omitempty"`
+	// When set the value is stored in a secret of the container app, which the variable references
+	Secret bool `yaml:"secret,omitempty"`
+	// The url of a Key Vault secret the variable references instead of a value, ex)
+	// https://vault.vault.azure.net/secrets/db-password. Access to the secret is granted to the identity on deploy
+	KeyVaultUrl osutil.ExpandableString `yaml:"keyVaultUrl,omitempty"`
+	// The managed identity reading the Key Vault secret, 'system' or the resource id of a user assigned identity.
+	// Defaults to 'system'
+	Identity osutil.ExpandableString `yaml:"identity,omitempty"`
+}
+
+type ContainerAppRevisionsMode string
+
+const (
+	ContainerAppRevisionsModeSingle   ContainerAppRevisionsMode = "single"
+	ContainerAppRevisionsModeMultiple ContainerAppRevisionsMode = "multiple"
+)
+
 type containerAppTarget struct {
 	env                 *environment.Environment
 	envManager          environment.Manager
 	containerHelper     *ContainerHelper
 	containerAppService containerapps.ContainerAppService
 	resourceManager     ResourceManager
+	bindings            *ServiceBindings
 }
 
+// The API version used to get the managed identities of a container app bound to other services and resources
+const containerAppApiVersion = "2023-05-01"
+
 // NewContainerAppTarget creates the container app service target.
 //
 // The target resource can be partially filled with only ResourceGroupName, since container apps
@@ -34,6 +80,7 @@ func NewContainerAppTarget(
 	containerHelper *ContainerHelper,
 	containerAppService containerapps.ContainerAppService,
 	resourceManager ResourceManager,
+	bindings *ServiceBindings,
 ) ServiceTarget {
 	return &containerAppTarget{
 		env:                 env,
@@ -41,6 +88,7 @@ func NewContainerAppTarget(
 		containerHelper:     containerHelper,
 		containerAppService: containerAppService,
 		resourceManager:     resourceManager,
+		bindings:            bindings,
 	}
 }
 
@@ -86,19 +134,54 @@ func (at *containerAppTarget) Deploy(
 		return nil, err
 	}
 
-	containerAppOptions := containerapps.ContainerAppOptions{
-		ApiVersion: serviceConfig.ApiVersion,
+	containerAppOptions, err := at.revisionOptions(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	containerAppId := azure.ContainerAppRID(
+		targetResource.SubscriptionId(), targetResource.ResourceGroupName(), targetResource.ResourceName())
+
+	if len(serviceConfig.Uses) > 0 {
+		progress.SetProgress(NewServiceProgress("Binding services and resources"))
+		bindings, err := at.bindings.Bind(ctx, serviceConfig, containerAppId, containerAppApiVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		// The variables of the service take precedence over the connection variables of its bindings
+		env, secretEnv := bindingsEnv(bindings)
+		for name, value := range env {
+			setBindingEnv(containerAppOptions, name, value, false)
+		}
+		for name, value := range secretEnv {
+			setBindingEnv(containerAppOptions, name, value, true)
+		}
+	}
+
+	if len(containerAppOptions.KeyVaultSecretEnv) > 0 {
+		progress.SetProgress(NewServiceProgress("Granting access to Key Vault secrets"))
+		err := at.bindings.AuthorizeKeyVaultSecrets(
+			ctx,
+			serviceConfig,
+			containerAppId,
+			containerAppApiVersion,
+			slices.Collect(maps.Values(containerAppOptions.KeyVaultSecretEnv)),
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	imageName := at.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
 	progress.SetProgress(NewServiceProgress("Updating container app revision"))
-	err = at.containerAppService.AddRevision(
+	secretChanges, err := at.containerAppService.AddRevision(
 		ctx,
 		targetResource.SubscriptionId(),
 		targetResource.ResourceGroupName(),
 		targetResource.ResourceName(),
 		imageName,
-		&containerAppOptions,
+		containerAppOptions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("updating container app service: %w", err)
@@ -119,6 +202,7 @@ func (at *containerAppTarget) Deploy(
 		),
 		Kind:      ContainerAppTarget,
 		Endpoints: endpoints,
+		Secrets:   secretChanges,
 	}, nil
 }
 
@@ -150,6 +234,148 @@ func (at *containerAppTarget) Endpoints(
 	}
 }
 
+// revisionOptions gets the options used to add a revision based on the container app configuration of the service.
+func (at *containerAppTarget) revisionOptions(serviceConfig *ServiceConfig) (*containerapps.ContainerAppOptions, error) {
+	config := serviceConfig.ContainerApp
+
+	revisionSuffix, err := config.RevisionSuffix.Envsubst(at.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("expanding revision suffix: %w", err)
+	}
+
+	switch config.RevisionsMode {
+	case "", ContainerAppRevisionsModeSingle, ContainerAppRevisionsModeMultiple:
+	default:
+		return nil, fmt.Errorf(
+			"revisions mode '%s' is not valid. Supported values are 'single' and 'multiple'", config.RevisionsMode)
+	}
+
+	if weight := config.LatestRevisionWeight; weight != nil {
+		if *weight < 0 || *weight > 100 {
+			return nil, fmt.Errorf("latest revision weight must be between 0 and 100, got %d", *weight)
+		}
+
+		if config.RevisionsMode != ContainerAppRevisionsModeMultiple {
+			return nil, errors.New("latest revision weight requires the 'multiple' revisions mode")
+		}
+	}
+
+	options := &containerapps.ContainerAppOptions{
+		ApiVersion:           serviceConfig.ApiVersion,
+		RevisionSuffix:       revisionSuffix,
+		MultipleRevisions:    config.RevisionsMode == ContainerAppRevisionsModeMultiple,
+		LatestRevisionWeight: config.LatestRevisionWeight,
+	}
+
+	for _, envVar := range config.Env {
+		if err := at.setEnv(options, envVar); err != nil {
+			return nil, err
+		}
+	}
+
+	return options, nil
+}
+
+// setEnv sets an environment variable of the service on the options of the revision, as a value, a secret of the
+// container app, or a reference to a Key Vault secret.
+func (at *containerAppTarget) setEnv(options *containerapps.ContainerAppOptions, envVar ContainerAppEnv) error {
+	if envVar.Name == "" {
+		return errors.New("the name of the environment variables of the container app must be set")
+	}
+
+	if _, has := options.Env[envVar.Name]; has {
+		return fmt.Errorf("environment variable '%s' is set more than once", envVar.Name)
+	}
+	if _, has := options.SecretEnv[envVar.Name]; has {
+		return fmt.Errorf("environment variable '%s' is set more than once", envVar.Name)
+	}
+	if _, has := options.KeyVaultSecretEnv[envVar.Name]; has {
+		return fmt.Errorf("environment variable '%s' is set more than once", envVar.Name)
+	}
+
+	value, err := envVar.Value.Envsubst(at.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("expanding environment variable '%s': %w", envVar.Name, err)
+	}
+
+	keyVaultUrl, err := envVar.KeyVaultUrl.Envsubst(at.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("expanding the key vault url of environment variable '%s': %w", envVar.Name, err)
+	}
+
+	identity, err := envVar.Identity.Envsubst(at.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("expanding the identity of environment variable '%s': %w", envVar.Name, err)
+	}
+
+	initEnv(options)
+	if keyVaultUrl == "" {
+		if identity != "" {
+			return fmt.Errorf("the identity of environment variable '%s' requires a key vault url", envVar.Name)
+		}
+
+		if envVar.Secret {
+			options.SecretEnv[envVar.Name] = value
+		} else {
+			options.Env[envVar.Name] = value
+		}
+
+		return nil
+	}
+
+	if !envVar.Value.Empty() {
+		return fmt.Errorf("environment variable '%s' can't set both a value and a key vault url", envVar.Name)
+	}
+
+	if _, err := keyVaultNameFromSecretUrl(keyVaultUrl); err != nil {
+		return fmt.Errorf("environment variable '%s': %w", envVar.Name, err)
+	}
+
+	if identity == "" {
+		identity = "system"
+	}
+
+	options.KeyVaultSecretEnv[envVar.Name] = containerapps.KeyVaultSecretReference{
+		Url:      keyVaultUrl,
+		Identity: identity,
+	}
+
+	return nil
+}
+
+// setBindingEnv sets a connection environment variable of a binding, unless the service sets the variable itself.
+func setBindingEnv(options *containerapps.ContainerAppOptions, name string, value string, secret bool) {
+	if _, has := options.Env[name]; has {
+		return
+	}
+	if _, has := options.SecretEnv[name]; has {
+		return
+	}
+	if _, has := options.KeyVaultSecretEnv[name]; has {
+		return
+	}
+
+	initEnv(options)
+	if secret {
+		options.SecretEnv[name] = value
+	} else {
+		options.Env[name] = value
+	}
+}
+
+// initEnv creates the environment variables of the options which are nil
+func initEnv(options *containerapps.ContainerAppOptions) {
+	if options.Env == nil {
+		options.Env = map[string]string{}
+	}
+	if options.SecretEnv == nil {
+		options.SecretEnv = map[string]string{}
+	}
+	if options.KeyVaultSecretEnv == nil {
+		options.KeyVaultSecretEnv = map[string]containerapps.KeyVaultSecretReference{}
+	}
+}
+
 func (at *containerAppTarget) validateTargetResource(
 	targetResource *environment.TargetResource,
 ) error {
@@ -19,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -179,6 +180,7 @@ func createContainerAppServiceTarget(
 		containerHelper,
 		containerAppService,
 		resourceManager,
+		nil,
 	)
 }
 
@@ -254,3 +256,120 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 	mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
 	mockazsdk.MockContainerRegistryTokenExchange(mockContext, subscriptionId, subscriptionId, "REFRESH_TOKEN")
 }
+
+func Test_ContainerApp_RevisionOptions(t *testing.T) {
+	env := environment.NewWithValues("test", map[string]string{
+		"GIT_SHA": "abc123",
+	})
+	target := &containerAppTarget{env: env}
+
+	t.Run("Defaults", func(t *testing.T) {
+		options, err := target.revisionOptions(&ServiceConfig{ApiVersion: "2024-03-01"})
+		require.NoError(t, err)
+		require.Equal(t, &containerapps.ContainerAppOptions{ApiVersion: "2024-03-01"}, options)
+	})
+
+	t.Run("MultipleRevisions", func(t *testing.T) {
+		options, err := target.revisionOptions(&ServiceConfig{
+			ContainerApp: ContainerAppOptions{
+				RevisionSuffix:       osutil.NewExpandableString("sha-${GIT_SHA}"),
+				RevisionsMode:        ContainerAppRevisionsModeMultiple,
+				LatestRevisionWeight: to.Ptr[int32](10),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &containerapps.ContainerAppOptions{
+			RevisionSuffix:       "sha-abc123",
+			MultipleRevisions:    true,
+			LatestRevisionWeight: to.Ptr[int32](10),
+		}, options)
+	})
+
+	t.Run("WeightRequiresMultipleRevisions", func(t *testing.T) {
+		_, err := target.revisionOptions(&ServiceConfig{
+			ContainerApp: ContainerAppOptions{
+				LatestRevisionWeight: to.Ptr[int32](10),
+			},
+		})
+		require.ErrorContains(t, err, "requires the 'multiple' revisions mode")
+	})
+
+	t.Run("InvalidWeight", func(t *testing.T) {
+		_, err := target.revisionOptions(&ServiceConfig{
+			ContainerApp: ContainerAppOptions{
+				RevisionsMode:        ContainerAppRevisionsModeMultiple,
+				LatestRevisionWeight: to.Ptr[int32](120),
+			},
+		})
+		require.ErrorContains(t, err, "must be between 0 and 100")
+	})
+
+	t.Run("InvalidRevisionsMode", func(t *testing.T) {
+		_, err := target.revisionOptions(&ServiceConfig{
+			ContainerApp: ContainerAppOptions{
+				RevisionsMode: "many",
+			},
+		})
+		require.ErrorContains(t, err, "revisions mode 'many' is not valid")
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		options, err := target.revisionOptions(&ServiceConfig{
+			ContainerApp: ContainerAppOptions{
+				Env: []ContainerAppEnv{
+					{Name: "GIT_SHA", Value: osutil.NewExpandableString("${GIT_SHA}")},
+					{Name: "API_KEY", Value: osutil.NewExpandableString("key"), Secret: true},
+					{
+						Name:        "DB_PASSWORD",
+						KeyVaultUrl: osutil.NewExpandableString("https://vault.vault.azure.net/secrets/db-password"),
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"GIT_SHA": "abc123"}, options.Env)
+		require.Equal(t, map[string]string{"API_KEY": "key"}, options.SecretEnv)
+		require.Equal(t, map[string]containerapps.KeyVaultSecretReference{
+			"DB_PASSWORD": {Url: "https://vault.vault.azure.net/secrets/db-password", Identity: "system"},
+		}, options.KeyVaultSecretEnv)
+
+		// The variables of the service take precedence over the ones of its bindings
+		setBindingEnv(options, "API_KEY", "binding", false)
+		setBindingEnv(options, "REDIS_PASSWORD", "password", true)
+		require.Equal(t, map[string]string{"GIT_SHA": "abc123"}, options.Env)
+		require.Equal(t, map[string]string{"API_KEY": "key", "REDIS_PASSWORD": "password"}, options.SecretEnv)
+	})
+
+	t.Run("InvalidEnv", func(t *testing.T) {
+		tests := map[string]ContainerAppEnv{
+			"can't set both a value and a key vault url": {
+				Name:        "DB_PASSWORD",
+				Value:       osutil.NewExpandableString("value"),
+				KeyVaultUrl: osutil.NewExpandableString("https://vault.vault.azure.net/secrets/db-password"),
+			},
+			"is not the url of a Key Vault secret": {
+				Name:        "DB_PASSWORD",
+				KeyVaultUrl: osutil.NewExpandableString("https://vault.vault.azure.net/keys/db-password"),
+			},
+			"requires a key vault url": {
+				Name:     "DB_PASSWORD",
+				Identity: osutil.NewExpandableString("system"),
+			},
+		}
+
+		for expected, envVar := range tests {
+			_, err := target.revisionOptions(&ServiceConfig{
+				ContainerApp: ContainerAppOptions{Env: []ContainerAppEnv{envVar}},
+			})
+			require.ErrorContains(t, err, expected)
+		}
+
+		_, err := target.revisionOptions(&ServiceConfig{
+			ContainerApp: ContainerAppOptions{Env: []ContainerAppEnv{
+				{Name: "API_KEY", Value: osutil.NewExpandableString("a")},
+				{Name: "API_KEY", Value: osutil.NewExpandableString("b"), Secret: true},
+			}},
+		})
+		require.ErrorContains(t, err, "is set more than once")
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/cosmosdb"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/sqldb"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
@@ -645,5 +646,7 @@ func (fns *containerAppTemplateManifestFuncs) kvSecret(kvHost, secretName string
 	if err != nil {
 		return "", fmt.Errorf("fetching secret %s from %s: %w", secretName, hostName, err)
 	}
+
+	output.AddSecret(secret.Value)
 	return secret.Value, nil
 }
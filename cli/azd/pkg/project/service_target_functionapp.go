@@ -78,6 +78,12 @@ func (f *functionAppTarget) Deploy(
 		return nil, fmt.Errorf("validating target resource: %w", err)
 	}
 
+	// The settings are applied before the code, so the new code starts with them
+	appSettings, err := updateAppSettingsWithProgress(ctx, f.cli, f.env, serviceConfig, targetResource, progress)
+	if err != nil {
+		return nil, err
+	}
+
 	zipFile, err := os.Open(packageOutput.PackagePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed reading deployment zip file: %w", err)
@@ -86,7 +92,11 @@ func (f *functionAppTarget) Deploy(
 	defer os.Remove(packageOutput.PackagePath)
 	defer zipFile.Close()
 
-	progress.SetProgress(NewServiceProgress("Uploading deployment package"))
+	uploadReader, err := newUploadProgressReader(zipFile, progress)
+	if err != nil {
+		return nil, err
+	}
+
 	remoteBuild := serviceConfig.Language == ServiceLanguageJavaScript ||
 		serviceConfig.Language == ServiceLanguageTypeScript ||
 		serviceConfig.Language == ServiceLanguagePython
@@ -95,8 +105,9 @@ func (f *functionAppTarget) Deploy(
 		targetResource.SubscriptionId(),
 		targetResource.ResourceGroupName(),
 		targetResource.ResourceName(),
-		zipFile,
+		uploadReader,
 		remoteBuild,
+		serviceConfig.AppService.zipDeployOptions(),
 	)
 	if err != nil {
 		return nil, err
@@ -119,6 +130,7 @@ func (f *functionAppTarget) Deploy(
 		endpoints,
 	)
 	sdr.Package = packageOutput
+	sdr.AppSettings = appSettings
 
 	return sdr, nil
 }
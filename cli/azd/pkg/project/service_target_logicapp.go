@@ -0,0 +1,339 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/otiai10/copy"
+)
+
+// logicAppConnectionFiles are the files of a workflow project in which the ${VAR} placeholders are replaced by the values
+// of the environment on package, ex) the ids of the connections and the endpoints of the services they connect to.
+var logicAppConnectionFiles = []string{"connections.json", "parameters.json"}
+
+// logicAppPlaceholder matches a ${VAR} placeholder of a connection file. $VAR isn't matched, since it's the syntax of
+// the parameters of workflows, ex) $connections.
+var logicAppPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// logicAppSettings are the app settings the Functions runtime needs to run the workflows of a Logic App Standard. They're
+// only added when neither the app settings of the service nor the app, ex) from its infrastructure, have them.
+var logicAppSettings = map[string]string{
+	"APP_KIND": "workflowApp",
+	"AzureFunctionsJobHost__extensionBundle__id":      "Microsoft.Azure.Functions.ExtensionBundle.Workflows",
+	"AzureFunctionsJobHost__extensionBundle__version": "[1.*, 2.0.0)",
+	"FUNCTIONS_EXTENSION_VERSION":                     "~4",
+}
+
+// callbackSignature matches the SAS signature parameter of a workflow callback URL
+var callbackSignature = regexp.MustCompile(`([?&]sig=)[^&#]*`)
+
+// logicAppTarget specifies an Azure Logic App Standard to deploy a workflow project to.
+// Implements `project.ServiceTarget`
+type logicAppTarget struct {
+	env *environment.Environment
+	cli *azapi.AzureClient
+}
+
+// NewLogicAppTarget creates a new instance of the Logic App Standard target
+func NewLogicAppTarget(
+	env *environment.Environment,
+	azCli *azapi.AzureClient,
+) ServiceTarget {
+	return &logicAppTarget{
+		env: env,
+		cli: azCli,
+	}
+}
+
+// Gets the required external tools for the Logic App
+func (t *logicAppTarget) RequiredExternalTools(ctx context.Context, serviceConfig *ServiceConfig) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the Logic App target
+func (t *logicAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Package replaces the placeholders of the connection files of the workflow project by the values of the environment and
+// prepares a zip archive of the project
+func (t *logicAppTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	packagePath := packageOutput.PackagePath
+	if packagePath == "" {
+		packagePath = serviceConfig.Path()
+	}
+
+	if _, err := os.Stat(filepath.Join(packagePath, "host.json")); err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("the workflow project of service '%s' has no host.json in '%s'", serviceConfig.Name, packagePath),
+			Suggestion: "Suggestion: set the 'project' of the service to the folder of the Logic Apps Standard " +
+				"workflow project, which contains host.json and a folder per workflow.",
+		}
+	}
+
+	progress.SetProgress(NewServiceProgress("Updating workflow connections"))
+	stagedPath, err := stageLogicAppConnections(packagePath, t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("updating the connections of service '%s': %w", serviceConfig.Name, err)
+	}
+	if stagedPath != packagePath {
+		defer os.RemoveAll(stagedPath)
+	}
+
+	progress.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
+	zipFilePath, err := createDeployableZip(serviceConfig, stagedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServicePackageResult{
+		Build:       packageOutput.Build,
+		PackagePath: zipFilePath,
+	}, nil
+}
+
+// stageLogicAppConnections returns the path of the workflow project to package. When the connection files of the project
+// have placeholders, it's a temporary copy of the project with the placeholders replaced, which the caller removes.
+func stageLogicAppConnections(projectPath string, getenv func(string) string) (string, error) {
+	updated := map[string][]byte{}
+	for _, name := range logicAppConnectionFiles {
+		content, err := os.ReadFile(filepath.Join(projectPath, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		replaced, err := replaceLogicAppPlaceholders(content, getenv)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+
+		if string(replaced) != string(content) {
+			updated[name] = replaced
+		}
+	}
+
+	if len(updated) == 0 {
+		return projectPath, nil
+	}
+
+	stagedPath, err := os.MkdirTemp("", "azd-logicapp")
+	if err != nil {
+		return "", err
+	}
+
+	if err := copy.Copy(projectPath, stagedPath, copy.Options{
+		Skip: func(info os.FileInfo, src string, dest string) (bool, error) {
+			return info.IsDir() && (info.Name() == ".git" || info.Name() == ".azure"), nil
+		},
+	}); err != nil {
+		os.RemoveAll(stagedPath)
+		return "", fmt.Errorf("copying the workflow project: %w", err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(updated)) {
+		if err := os.WriteFile(filepath.Join(stagedPath, name), updated[name], osutil.PermissionFile); err != nil {
+			os.RemoveAll(stagedPath)
+			return "", err
+		}
+	}
+
+	return stagedPath, nil
+}
+
+// replaceLogicAppPlaceholders replaces the ${VAR} placeholders of a connection file by the values of the environment.
+// Placeholders of variables which aren't set are an error, so the workflows don't run with broken connections.
+func replaceLogicAppPlaceholders(content []byte, getenv func(string) string) ([]byte, error) {
+	missing := []string{}
+	replaced := logicAppPlaceholder.ReplaceAllFunc(content, func(match []byte) []byte {
+		name := string(logicAppPlaceholder.FindSubmatch(match)[1])
+		value := getenv(name)
+		if value == "" {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+
+			return match
+		}
+
+		// The values are written inside JSON strings
+		quoted, _ := json.Marshal(value)
+		return quoted[1 : len(quoted)-1]
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("the environment variables %s aren't set", strings.Join(missing, ", "))
+	}
+
+	return replaced, nil
+}
+
+// Deploys the prepared zip archive using Zip deploy to the Logic App, after applying the app settings the workflows need
+func (t *logicAppTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := checkResourceType(targetResource, azapi.AzureResourceTypeWebSite); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	props, err := t.cli.GetLogicAppProperties(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	if !props.IsWorkflowApp() {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"resource '%s' of kind '%s' is not a Logic App Standard", targetResource.ResourceName(), props.Kind),
+			Suggestion: "Suggestion: set the kind of the site to 'functionapp,workflowapp' in the infrastructure of " +
+				"the service, or use the 'function' host for Function Apps.",
+		}
+	}
+
+	// The settings are applied before the code, so the new workflows start with them
+	progress.SetProgress(NewServiceProgress("Updating app settings"))
+	settings, err := expandAppSettings(t.env, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	appSettings, err := t.cli.UpdateAppServiceAppSettingsWithDefaults(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		settings,
+		logicAppSettings,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	zipFile, err := os.Open(packageOutput.PackagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading deployment zip file: %w", err)
+	}
+
+	defer os.Remove(packageOutput.PackagePath)
+	defer zipFile.Close()
+
+	uploadReader, err := newUploadProgressReader(zipFile, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.cli.DeployFunctionAppUsingZipFile(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		uploadReader,
+		false,
+		serviceConfig.AppService.zipDeployOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching workflow callback URLs"))
+	endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	sdr := NewServiceDeployResult(
+		azure.WebsiteRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		LogicAppTarget,
+		*res,
+		endpoints,
+	)
+	sdr.Package = packageOutput
+	sdr.AppSettings = appSettings
+
+	return sdr, nil
+}
+
+// Gets the endpoint of the Logic App and the callback URLs of the request triggers of its workflows. The SAS signature of
+// the callback URLs is redacted, since anyone with it can run the workflows; it's displayed in the Azure Portal or
+// returned by the listCallbackUrl API of the trigger.
+func (t *logicAppTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	props, err := t.cli.GetLogicAppProperties(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	endpoints := []string{}
+	for _, hostName := range props.HostNames {
+		endpoints = append(endpoints, fmt.Sprintf("https://%s/", hostName))
+	}
+
+	triggerUrls, err := t.cli.ListLogicAppTriggerUrls(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching workflow callback URLs: %w", err)
+	}
+
+	for _, triggerUrl := range triggerUrls {
+		endpoints = append(endpoints,
+			fmt.Sprintf("Workflow %s, trigger %s: %s",
+				triggerUrl.Workflow, triggerUrl.Trigger, redactCallbackSignature(triggerUrl.Url)))
+	}
+
+	return endpoints, nil
+}
+
+// redactCallbackSignature replaces the SAS signature of a workflow callback URL, ex) ...&sig=REDACTED
+func redactCallbackSignature(callbackUrl string) string {
+	return callbackSignature.ReplaceAllString(callbackUrl, "${1}REDACTED")
+}
@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazapi"
+	"github.com/stretchr/testify/require"
+)
+
+func createWorkflowProject(t *testing.T, connections string) string {
+	root := t.TempDir()
+	for file, contents := range map[string]string{
+		"host.json":                 `{"version": "2.0"}`,
+		"connections.json":          connections,
+		"local.settings.json":       `{"Values": {}}`,
+		"orders/workflow.json":      `{"definition": {}}`,
+		"workflow-designtime/x.txt": "design time",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+	}
+
+	return root
+}
+
+func packageLogicApp(t *testing.T, env *environment.Environment, projectPath string) (map[string]string, error) {
+	target := NewLogicAppTarget(env, nil)
+	serviceConfig := &ServiceConfig{
+		Name:         "workflows",
+		Project:      &ProjectConfig{Name: "app", Path: projectPath},
+		RelativePath: ".",
+		Host:         LogicAppTarget,
+	}
+
+	result, err := async.RunWithProgress(
+		func(progress ServiceProgress) {},
+		func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+			return target.Package(context.Background(), serviceConfig, &ServicePackageResult{}, progress)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { _ = os.Remove(result.PackagePath) })
+
+	reader, err := zip.OpenReader(result.PackagePath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	files := map[string]string{}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		f.Close()
+
+		files[filepath.ToSlash(file.Name)] = string(content)
+	}
+
+	return files, nil
+}
+
+func Test_LogicAppTarget_Package(t *testing.T) {
+	connections := `{"serviceProviderConnections": {"sb": {"parameterValues": ` +
+		`{"fullyQualifiedNamespace": "${SERVICEBUS_NAMESPACE}"}}}, "managedApiConnections": {"$connections": {}}}`
+
+	t.Run("ReplacesPlaceholders", func(t *testing.T) {
+		projectPath := createWorkflowProject(t, connections)
+		env := environment.NewWithValues("dev", map[string]string{
+			"SERVICEBUS_NAMESPACE": `sb-dev.servicebus.windows.net`,
+		})
+
+		files, err := packageLogicApp(t, env, projectPath)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"connections.json", "host.json", "orders/workflow.json"}, slices.Sorted(maps.Keys(files)))
+		require.Contains(t, files["connections.json"], `"fullyQualifiedNamespace": "sb-dev.servicebus.windows.net"`)
+		require.Contains(t, files["connections.json"], `"$connections"`)
+
+		// The project itself isn't changed
+		original, err := os.ReadFile(filepath.Join(projectPath, "connections.json"))
+		require.NoError(t, err)
+		require.Equal(t, connections, string(original))
+	})
+
+	t.Run("MissingVariable", func(t *testing.T) {
+		projectPath := createWorkflowProject(t, connections)
+
+		_, err := packageLogicApp(t, environment.NewWithValues("dev", nil), projectPath)
+		require.ErrorContains(t, err, "connections.json: the environment variables SERVICEBUS_NAMESPACE aren't set")
+	})
+
+	t.Run("NotWorkflowProject", func(t *testing.T) {
+		projectPath := createWorkflowProject(t, connections)
+		require.NoError(t, os.Remove(filepath.Join(projectPath, "host.json")))
+
+		_, err := packageLogicApp(t, environment.NewWithValues("dev", nil), projectPath)
+		var suggestionErr *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.ErrorContains(t, err, "has no host.json")
+	})
+}
+
+func Test_ReplaceLogicAppPlaceholders(t *testing.T) {
+	getenv := func(name string) string {
+		return map[string]string{"URL": `https://api/"v1"`}[name]
+	}
+
+	replaced, err := replaceLogicAppPlaceholders([]byte(`{"url": "${URL}", "p": "$URL"}`), getenv)
+	require.NoError(t, err)
+	require.Equal(t, `{"url": "https://api/\"v1\"", "p": "$URL"}`, string(replaced))
+}
+
+func Test_LogicAppTarget_Deploy_NotWorkflowApp(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/Microsoft.Web/sites/FUNC_APP")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.WebAppsClientGetResponse{
+			Site: armappservice.Site{
+				Kind: to.Ptr("functionapp,linux"),
+				Properties: &armappservice.SiteProperties{
+					DefaultHostName: to.Ptr("FUNC_APP.azurewebsites.net"),
+				},
+			},
+		})
+	})
+
+	target := NewLogicAppTarget(environment.NewWithValues("dev", nil), mockazapi.NewAzureClientFromMockContext(mockContext))
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID", "RESOURCE_GROUP", "FUNC_APP", string(azapi.AzureResourceTypeWebSite))
+
+	_, err := async.RunWithProgress(
+		func(progress ServiceProgress) {},
+		func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return target.Deploy(*mockContext.Context, &ServiceConfig{Name: "workflows"}, &ServicePackageResult{},
+				targetResource, progress)
+		},
+	)
+	require.ErrorContains(t, err, "resource 'FUNC_APP' of kind 'functionapp,linux' is not a Logic App Standard")
+}
+
+func Test_RedactCallbackSignature(t *testing.T) {
+	require.Equal(t,
+		"https://app.azurewebsites.net:443/api/orders/triggers/manual/invoke?api-version=2022-05-01&sp=%2Ftriggers"+
+			"%2Fmanual%2Frun&sv=1.0&sig=REDACTED",
+		redactCallbackSignature("https://app.azurewebsites.net:443/api/orders/triggers/manual/invoke?"+
+			"api-version=2022-05-01&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0&sig=SIGNATURE"))
+	require.Equal(t,
+		"https://app.azurewebsites.net/invoke?sig=REDACTED&sv=1.0",
+		redactCallbackSignature("https://app.azurewebsites.net/invoke?sig=SIGNATURE&sv=1.0"))
+}
@@ -0,0 +1,423 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// The container of a storage account the files of its static website are served from
+	staticWebsiteContainer = "$web"
+	// The API version used to read the endpoints of storage accounts
+	storageAccountApiVersion = "2023-05-01"
+	// The default index document of static websites
+	defaultStaticSiteIndexDocument = "index.html"
+	// The number of files uploaded at the same time
+	staticSiteUploadConcurrency = 16
+)
+
+// StorageStaticSiteOptions configures the static website of the storage account a service is deployed to.
+type StorageStaticSiteOptions struct {
+	// The document served for requests to the root of the site or a directory. Defaults to 'index.html'
+	IndexDocument string `yaml:"indexDocument,omitempty"`
+	// The document served, with a 404 status, for requests to files that don't exist, ex) 404.html
+	ErrorDocument string `yaml:"errorDocument,omitempty"`
+	// The Cache-Control header values of the uploaded files. The first rule matching a file applies.
+	CacheControl []CacheControlRule `yaml:"cacheControl,omitempty"`
+}
+
+// CacheControlRule sets the Cache-Control header of the files of a static site matching a pattern.
+type CacheControlRule struct {
+	// The glob pattern matched against the path of a file relative to the root of the site, ex) assets/*, or its name,
+	// ex) *.html
+	Pattern string `yaml:"pattern"`
+	// The value of the Cache-Control header, ex) public, max-age=31536000, immutable
+	Value string `yaml:"value"`
+}
+
+// cacheControl returns the Cache-Control header value of the file at the given path, relative to the root of the site,
+// or an empty string when no rule matches the file.
+func (o StorageStaticSiteOptions) cacheControl(filePath string) string {
+	for _, rule := range o.CacheControl {
+		if matched, _ := path.Match(rule.Pattern, filePath); matched {
+			return rule.Value
+		}
+
+		if matched, _ := path.Match(rule.Pattern, path.Base(filePath)); matched {
+			return rule.Value
+		}
+	}
+
+	return ""
+}
+
+type storageStaticSiteTarget struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	resourceService    *azapi.ResourceService
+	armClientOptions   *arm.ClientOptions
+}
+
+// NewStorageStaticSiteTarget creates a new instance of the Azure Storage static website target
+func NewStorageStaticSiteTarget(
+	credentialProvider account.SubscriptionCredentialProvider,
+	resourceService *azapi.ResourceService,
+	armClientOptions *arm.ClientOptions,
+) ServiceTarget {
+	return &storageStaticSiteTarget{
+		credentialProvider: credentialProvider,
+		resourceService:    resourceService,
+		armClientOptions:   armClientOptions,
+	}
+}
+
+// Gets the required external tools for the static website target
+func (st *storageStaticSiteTarget) RequiredExternalTools(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the static website target
+func (st *storageStaticSiteTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	for _, rule := range serviceConfig.StaticSite.CacheControl {
+		if _, err := path.Match(rule.Pattern, ""); err != nil || rule.Pattern == "" {
+			return fmt.Errorf(
+				"cache control pattern '%s' of service '%s' is not a valid glob pattern", rule.Pattern, serviceConfig.Name)
+		}
+	}
+
+	return nil
+}
+
+// Package sets the directory of the built site, which is the 'dist' of the service when set, as the package
+func (st *storageStaticSiteTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	progress *async.Progress[ServiceProgress],
+) (*ServicePackageResult, error) {
+	packagePath := serviceConfig.OutputPath
+	if strings.TrimSpace(packagePath) == "" {
+		packagePath = packageOutput.PackagePath
+	}
+
+	if !filepath.IsAbs(packagePath) {
+		packagePath = filepath.Join(serviceConfig.Path(), packagePath)
+	}
+
+	return &ServicePackageResult{
+		Build:       packageOutput.Build,
+		PackagePath: packagePath,
+	}, nil
+}
+
+// Deploy enables the static website of the storage account, uploads the files of the site to its '$web' container and
+// removes the files of previous deployments which aren't part of the site anymore.
+func (st *storageStaticSiteTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+	progress *async.Progress[ServiceProgress],
+) (*ServiceDeployResult, error) {
+	if err := checkResourceType(targetResource, azapi.AzureResourceTypeStorageAccount); err != nil {
+		return nil, err
+	}
+
+	files, err := staticSiteFiles(packageOutput.PackagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Fetching storage account endpoints"))
+	blobEndpoint, webEndpoint, err := st.accountEndpoints(ctx, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := st.credentialProvider.CredentialForSubscription(ctx, targetResource.SubscriptionId())
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azblob.NewClient(blobEndpoint, credential, &azblob.ClientOptions{
+		ClientOptions: st.armClientOptions.ClientOptions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating blob client: %w", err)
+	}
+
+	progress.SetProgress(NewServiceProgress("Enabling static website"))
+	indexDocument := serviceConfig.StaticSite.IndexDocument
+	if indexDocument == "" {
+		indexDocument = defaultStaticSiteIndexDocument
+	}
+
+	staticWebsite := &service.StaticWebsite{
+		Enabled:       to.Ptr(true),
+		IndexDocument: to.Ptr(indexDocument),
+	}
+	if serviceConfig.StaticSite.ErrorDocument != "" {
+		staticWebsite.ErrorDocument404Path = to.Ptr(serviceConfig.StaticSite.ErrorDocument)
+	}
+
+	if _, err := client.ServiceClient().SetProperties(ctx, &service.SetPropertiesOptions{
+		StaticWebsite: staticWebsite,
+	}); err != nil {
+		return nil, fmt.Errorf("enabling static website: %w", err)
+	}
+
+	if err := st.uploadFiles(ctx, client, serviceConfig, packageOutput.PackagePath, files, progress); err != nil {
+		return nil, err
+	}
+
+	progress.SetProgress(NewServiceProgress("Removing stale files"))
+	if err := st.removeStaleFiles(ctx, client, files); err != nil {
+		return nil, err
+	}
+
+	sdr := NewServiceDeployResult(
+		azure.StorageAccountRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		StorageStaticSiteTarget,
+		fmt.Sprintf("Uploaded %d files", len(files)),
+		[]string{webEndpoint},
+	)
+	sdr.Package = packageOutput
+
+	return sdr, nil
+}
+
+// Gets the endpoint of the static website
+func (st *storageStaticSiteTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	_, webEndpoint, err := st.accountEndpoints(ctx, targetResource)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{webEndpoint}, nil
+}
+
+// accountEndpoints returns the blob and static website endpoints of the storage account
+func (st *storageStaticSiteTarget) accountEndpoints(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+) (blobEndpoint string, webEndpoint string, err error) {
+	resourceId, err := arm.ParseResourceID(azure.StorageAccountRID(
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	))
+	if err != nil {
+		return "", "", err
+	}
+
+	account, err := st.resourceService.GetRawResource(ctx, *resourceId, storageAccountApiVersion)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching storage account endpoints: %w", err)
+	}
+
+	blobEndpoint = gjson.Get(account, "properties.primaryEndpoints.blob").String()
+	webEndpoint = gjson.Get(account, "properties.primaryEndpoints.web").String()
+	if blobEndpoint == "" || webEndpoint == "" {
+		return "", "", fmt.Errorf(
+			"storage account '%s' has no blob or static website endpoint. Static websites require a StorageV2 account",
+			targetResource.ResourceName(),
+		)
+	}
+
+	return blobEndpoint, webEndpoint, nil
+}
+
+// uploadFiles uploads the files of the site in parallel, setting their content type from their extension and their
+// cache control from the rules of the service.
+func (st *storageStaticSiteTarget) uploadFiles(
+	ctx context.Context,
+	client *azblob.Client,
+	serviceConfig *ServiceConfig,
+	root string,
+	files []string,
+	progress *async.Progress[ServiceProgress],
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		uploaded atomic.Int32
+	)
+
+	slots := make(chan struct{}, staticSiteUploadConcurrency)
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Uploading files (0/%d)", len(files))))
+
+	for _, file := range files {
+		slots <- struct{}{}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(file string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if err := st.uploadFile(ctx, client, serviceConfig, root, file); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+
+			progress.SetProgress(NewServiceProgress(
+				fmt.Sprintf("Uploading files (%d/%d)", uploaded.Add(1), len(files))))
+		}(file)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+func (st *storageStaticSiteTarget) uploadFile(
+	ctx context.Context,
+	client *azblob.Client,
+	serviceConfig *ServiceConfig,
+	root string,
+	file string,
+) error {
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(file)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(file))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	headers := &blob.HTTPHeaders{
+		BlobContentType: to.Ptr(contentType),
+	}
+	if cacheControl := serviceConfig.StaticSite.cacheControl(file); cacheControl != "" {
+		headers.BlobCacheControl = to.Ptr(cacheControl)
+	}
+
+	if _, err := client.UploadFile(ctx, staticWebsiteContainer, file, f, &azblob.UploadFileOptions{
+		HTTPHeaders: headers,
+	}); err != nil {
+		return fmt.Errorf("uploading file '%s': %w", file, err)
+	}
+
+	return nil
+}
+
+// removeStaleFiles deletes the files of the '$web' container which aren't part of the uploaded site, once the new
+// files are uploaded.
+func (st *storageStaticSiteTarget) removeStaleFiles(ctx context.Context, client *azblob.Client, files []string) error {
+	uploaded := map[string]struct{}{}
+	for _, file := range files {
+		uploaded[file] = struct{}{}
+	}
+
+	stale := []string{}
+	pager := client.NewListBlobsFlatPager(staticWebsiteContainer, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("listing static website files: %w", err)
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+
+			if _, has := uploaded[*item.Name]; !has {
+				stale = append(stale, *item.Name)
+			}
+		}
+	}
+
+	for _, name := range stale {
+		log.Printf("removing stale static website file '%s'", name)
+		if _, err := client.DeleteBlob(ctx, staticWebsiteContainer, name, nil); err != nil {
+			return fmt.Errorf("removing stale file '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// staticSiteFiles returns the paths, relative to the root and separated by slashes, of the files of a static site.
+func staticSiteFiles(root string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, filepath.ToSlash(relativePath))
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("static site directory '%s' does not exist, set the 'dist' of the service", root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading static site files: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("static site directory '%s' is empty", root)
+	}
+
+	return files, nil
+}
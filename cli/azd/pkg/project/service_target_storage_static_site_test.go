@@ -0,0 +1,173 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_storageStaticSiteTarget_Deploy(t *testing.T) {
+	siteDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(siteDir, "assets"), osutil.PermissionDirectory))
+	for name, content := range map[string]string{
+		"index.html":       "<html></html>",
+		"404.html":         "<html>Not found</html>",
+		"assets/app.js":    "console.log('app')",
+		"assets/app.wasm":  "\x00asm",
+		"assets/logo.webp": "webp",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(siteDir, filepath.FromSlash(name)), []byte(content), 0600))
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/storageAccounts/stsite")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"id":   "/subscriptions/SUB/resourceGroups/RG/providers/Microsoft.Storage/storageAccounts/stsite",
+			"name": "stsite",
+			"properties": map[string]any{
+				"primaryEndpoints": map[string]any{
+					"blob": "https://stsite.blob.core.windows.net/",
+					"web":  "https://stsite.z13.web.core.windows.net/",
+				},
+			},
+		})
+	})
+
+	var serviceProperties string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && request.URL.Query().Get("comp") == "properties"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		serviceProperties = string(body)
+		return mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+	})
+
+	// The SDK sets the blob headers without canonicalizing their names
+	type blobHeaders struct {
+		contentType  string
+		cacheControl string
+	}
+
+	var mu sync.Mutex
+	uploaded := map[string]blobHeaders{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasPrefix(request.URL.Path, "/$web/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		uploaded[strings.TrimPrefix(request.URL.Path, "/$web/")] = blobHeaders{
+			contentType:  strings.Join(request.Header["x-ms-blob-content-type"], ","),
+			cacheControl: strings.Join(request.Header["x-ms-blob-cache-control"], ","),
+		}
+		return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Query().Get("comp") == "list"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response, err := mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		response.Header.Set("Content-Type", "application/xml")
+		response.Body = io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="utf-8"?>
+<EnumerationResults ServiceEndpoint="https://stsite.blob.core.windows.net/" ContainerName="$web">
+  <Blobs>
+    <Blob><Name>index.html</Name><Properties></Properties></Blob>
+    <Blob><Name>assets/old.js</Name><Properties></Properties></Blob>
+  </Blobs>
+  <NextMarker />
+</EnumerationResults>`))
+		return response, nil
+	})
+
+	deleted := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && strings.HasPrefix(request.URL.Path, "/$web/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = append(deleted, strings.TrimPrefix(request.URL.Path, "/$web/"))
+		return mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+	})
+
+	serviceTarget := NewStorageStaticSiteTarget(
+		mockContext.SubscriptionCredentialProvider,
+		azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions),
+		mockContext.ArmClientOptions,
+	)
+
+	serviceConfig := createTestServiceConfig("./src/web", StorageStaticSiteTarget, ServiceLanguageJavaScript)
+	serviceConfig.StaticSite = StorageStaticSiteOptions{
+		ErrorDocument: "404.html",
+		CacheControl: []CacheControlRule{
+			{Pattern: "assets/*", Value: "public, max-age=31536000, immutable"},
+			{Pattern: "*.html", Value: "no-cache"},
+		},
+	}
+	require.NoError(t, serviceTarget.Initialize(*mockContext.Context, serviceConfig))
+
+	targetResource := environment.NewTargetResource(
+		"SUB", "RG", "stsite", string(azapi.AzureResourceTypeStorageAccount))
+
+	deployResult, err := async.RunWithProgress(
+		func(progress ServiceProgress) {},
+		func(progress *async.Progress[ServiceProgress]) (*ServiceDeployResult, error) {
+			return serviceTarget.Deploy(
+				*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: siteDir}, targetResource, progress)
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://stsite.z13.web.core.windows.net/"}, deployResult.Endpoints)
+
+	require.Contains(t, serviceProperties, "<Enabled>true</Enabled>")
+	require.Contains(t, serviceProperties, "<IndexDocument>index.html</IndexDocument>")
+	require.Contains(t, serviceProperties, "<ErrorDocument404Path>404.html</ErrorDocument404Path>")
+
+	require.Len(t, uploaded, 5)
+	require.Equal(t, blobHeaders{"text/html; charset=utf-8", "no-cache"}, uploaded["index.html"])
+	require.Equal(t, "application/wasm", uploaded["assets/app.wasm"].contentType)
+	require.Equal(t, "public, max-age=31536000, immutable", uploaded["assets/app.js"].cacheControl)
+
+	// Only the files which aren't part of the new site are removed
+	require.Equal(t, []string{"assets/old.js"}, deleted)
+}
+
+func Test_storageStaticSiteTarget_Initialize(t *testing.T) {
+	serviceTarget := &storageStaticSiteTarget{}
+
+	serviceConfig := createTestServiceConfig("./src/web", StorageStaticSiteTarget, ServiceLanguageJavaScript)
+	serviceConfig.StaticSite.CacheControl = []CacheControlRule{{Pattern: "assets/[", Value: "no-cache"}}
+	err := serviceTarget.Initialize(context.Background(), serviceConfig)
+	require.ErrorContains(t, err, "cache control pattern 'assets/[' of service 'api' is not a valid glob pattern")
+}
+
+func Test_StorageStaticSiteOptions_cacheControl(t *testing.T) {
+	options := StorageStaticSiteOptions{
+		CacheControl: []CacheControlRule{
+			{Pattern: "assets/*", Value: "immutable"},
+			{Pattern: "*.html", Value: "no-cache"},
+		},
+	}
+
+	require.Equal(t, "immutable", options.cacheControl("assets/app.js"))
+	require.Equal(t, "no-cache", options.cacheControl("docs/index.html"))
+	require.Equal(t, "", options.cacheControl("favicon.ico"))
+}
@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// The folders which changes never require a service to be rebuilt, like dependencies and build outputs
+var watchIgnoredFolders = map[string]struct{}{
+	".git":         {},
+	".azure":       {},
+	".venv":        {},
+	"venv":         {},
+	"__pycache__":  {},
+	"node_modules": {},
+	"bin":          {},
+	"obj":          {},
+	"build":        {},
+	"dist":         {},
+	".next":        {},
+	"target":       {},
+}
+
+// ServiceWatcher watches the source directories of services, and reports the services which files changed once the
+// changes settle.
+type ServiceWatcher struct {
+	watcher  *fsnotify.Watcher
+	services []*ServiceConfig
+	debounce time.Duration
+}
+
+// NewServiceWatcher creates a watcher of the source directories of the services. The changes are reported once no
+// other change happened during the debounce duration.
+func NewServiceWatcher(services []*ServiceConfig, debounce time.Duration) (*ServiceWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+
+	w := &ServiceWatcher{
+		watcher:  watcher,
+		services: services,
+		debounce: debounce,
+	}
+
+	for _, svc := range services {
+		if err := w.watchRecursive(svc.Path()); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("watching service '%s': %w", svc.Name, err)
+		}
+	}
+
+	return w, nil
+}
+
+// Watch reports the changed services to onChange until the context is cancelled. onChange is called on the goroutine
+// of Watch, the changes happening while it runs are reported on its next call.
+func (w *ServiceWatcher) Watch(ctx context.Context, onChange func(ctx context.Context, services []*ServiceConfig)) error {
+	defer w.watcher.Close()
+
+	debounce := time.NewTimer(w.debounce)
+	debounce.Stop()
+
+	changed := map[string]*ServiceConfig{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watching for changes: %w", err)
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Has(fsnotify.Chmod) || w.ignored(event.Name) {
+				continue
+			}
+
+			// New directories aren't watched by fsnotify, they have to be added
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := w.watchRecursive(event.Name); err != nil {
+						log.Printf("failed to watch '%s': %v", event.Name, err)
+					}
+				}
+			}
+
+			svc := w.serviceForPath(event.Name)
+			if svc == nil {
+				continue
+			}
+
+			log.Printf("change detected for service '%s': %s", svc.Name, event)
+			changed[svc.Name] = svc
+			debounce.Reset(w.debounce)
+		case <-debounce.C:
+			if len(changed) == 0 {
+				continue
+			}
+
+			services := make([]*ServiceConfig, 0, len(changed))
+			for _, svc := range w.services {
+				if _, has := changed[svc.Name]; has {
+					services = append(services, svc)
+				}
+			}
+			clear(changed)
+
+			onChange(ctx, services)
+		}
+	}
+}
+
+func (w *ServiceWatcher) watchRecursive(root string) error {
+	return filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		if path != root && w.ignored(path) {
+			return filepath.SkipDir
+		}
+
+		return w.watcher.Add(path)
+	})
+}
+
+// ignored returns true when the path is in an ignored folder, or in the output folder of its service
+func (w *ServiceWatcher) ignored(path string) bool {
+	svc := w.serviceForPath(path)
+	if svc == nil {
+		return true
+	}
+
+	relativePath, err := filepath.Rel(svc.Path(), path)
+	if err != nil {
+		return true
+	}
+
+	segments := strings.Split(relativePath, string(filepath.Separator))
+	if slices.ContainsFunc(segments, func(segment string) bool {
+		_, has := watchIgnoredFolders[segment]
+		return has
+	}) {
+		return true
+	}
+
+	if svc.OutputPath != "" {
+		outputPath := filepath.Clean(svc.OutputPath)
+		if relativePath == outputPath || strings.HasPrefix(relativePath, outputPath+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serviceForPath returns the service which directory contains the path. When the directories of services are nested,
+// the service with the innermost directory is returned.
+func (w *ServiceWatcher) serviceForPath(path string) *ServiceConfig {
+	var match *ServiceConfig
+	for _, svc := range w.services {
+		servicePath := svc.Path()
+		if path != servicePath && !strings.HasPrefix(path, servicePath+string(filepath.Separator)) {
+			continue
+		}
+
+		if match == nil || len(servicePath) > len(match.Path()) {
+			match = svc
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceWatcher_ServiceForPath(t *testing.T) {
+	projectPath := t.TempDir()
+	project := &ProjectConfig{Path: projectPath}
+	web := &ServiceConfig{Name: "web", RelativePath: ".", Project: project, OutputPath: "public"}
+	api := &ServiceConfig{Name: "api", RelativePath: "api", Project: project}
+
+	w := &ServiceWatcher{services: []*ServiceConfig{web, api}}
+
+	tests := []struct {
+		path    string
+		service *ServiceConfig
+		ignored bool
+	}{
+		{path: filepath.Join(projectPath, "index.html"), service: web},
+		{path: filepath.Join(projectPath, "api", "main.py"), service: api},
+		{path: filepath.Join(projectPath, "apis", "main.py"), service: web},
+		{path: filepath.Join(projectPath, "public", "index.html"), service: web, ignored: true},
+		{path: filepath.Join(projectPath, "api", "public", "main.py"), service: api},
+		{path: filepath.Join(projectPath, "api", "node_modules", "lib.js"), service: api, ignored: true},
+		{path: filepath.Join(projectPath, "api", "src", "__pycache__", "main.pyc"), service: api, ignored: true},
+		{path: filepath.Join(filepath.Dir(projectPath), "other.txt"), ignored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.service, w.serviceForPath(tt.path))
+			require.Equal(t, tt.ignored, w.ignored(tt.path))
+		})
+	}
+}
+
+func Test_ServiceWatcher_Watch(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, "web", "node_modules"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, "api"), 0755))
+
+	project := &ProjectConfig{Path: projectPath}
+	web := &ServiceConfig{Name: "web", RelativePath: "web", Project: project}
+	api := &ServiceConfig{Name: "api", RelativePath: "api", Project: project}
+
+	w, err := NewServiceWatcher([]*ServiceConfig{web, api}, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	changes := make(chan []*ServiceConfig, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Watch(ctx, func(ctx context.Context, services []*ServiceConfig) {
+			changes <- services
+		})
+	}()
+
+	// Changes to the dependencies are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "web", "node_modules", "lib.js"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "api", "main.py"), nil, 0600))
+
+	select {
+	case services := <-changes:
+		require.Equal(t, []*ServiceConfig{api}, services)
+	case <-ctx.Done():
+		require.Fail(t, "no changes were reported")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+)
+
+// uploadProgressInterval is the minimum time between two progress updates of an upload
+const uploadProgressInterval = 500 * time.Millisecond
+
+// uploadProgressReader reports the progress of the upload of a deployment package, with the estimated time left, as the
+// package is read by the HTTP client. Seeking back, as done when the request is retried, restarts the progress.
+type uploadProgressReader struct {
+	file       *os.File
+	size       int64
+	offset     int64
+	start      time.Time
+	lastReport time.Time
+	progress   *async.Progress[ServiceProgress]
+	now        func() time.Time
+}
+
+// newUploadProgressReader wraps the deployment package so that reading it reports the progress of its upload
+func newUploadProgressReader(
+	file *os.File,
+	progress *async.Progress[ServiceProgress],
+) (*uploadProgressReader, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading deployment package size: %w", err)
+	}
+
+	return &uploadProgressReader{
+		file:     file,
+		size:     info.Size(),
+		progress: progress,
+		now:      time.Now,
+	}, nil
+}
+
+func (r *uploadProgressReader) Read(p []byte) (int, error) {
+	if r.start.IsZero() {
+		r.start = r.now()
+		r.report(r.start)
+	}
+
+	n, err := r.file.Read(p)
+	r.offset += int64(n)
+
+	if n > 0 {
+		if now := r.now(); now.Sub(r.lastReport) >= uploadProgressInterval || r.offset == r.size {
+			r.report(now)
+		}
+	}
+
+	return n, err
+}
+
+func (r *uploadProgressReader) Seek(offset int64, whence int) (int64, error) {
+	position, err := r.file.Seek(offset, whence)
+	if err != nil {
+		return position, err
+	}
+
+	r.offset = position
+	if position == 0 {
+		r.start = time.Time{}
+	}
+
+	return position, nil
+}
+
+func (r *uploadProgressReader) report(now time.Time) {
+	r.lastReport = now
+	r.progress.SetProgress(NewServiceProgress(r.message(now)))
+}
+
+// message describes the progress of the upload, ex) Uploading deployment package (12.5 MB of 310.2 MB, 1m5s left)
+func (r *uploadProgressReader) message(now time.Time) string {
+	message := fmt.Sprintf("Uploading deployment package (%s of %s", formatPackageSize(r.offset), formatPackageSize(r.size))
+
+	elapsed := now.Sub(r.start)
+	if r.offset > 0 && r.offset < r.size && elapsed >= time.Second {
+		left := time.Duration(float64(elapsed) * float64(r.size-r.offset) / float64(r.offset))
+		message += fmt.Sprintf(", %s left", left.Round(time.Second))
+	}
+
+	return message + ")"
+}
+
+func formatPackageSize(size int64) string {
+	const unit = 1024
+	if size < unit*unit {
+		return fmt.Sprintf("%.1f KB", float64(size)/unit)
+	}
+
+	return fmt.Sprintf("%.1f MB", float64(size)/(unit*unit))
+}
+
+var _ io.ReadSeeker = (*uploadProgressReader)(nil)
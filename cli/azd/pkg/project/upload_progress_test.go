@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_uploadProgressReader(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "package.zip")
+	require.NoError(t, os.WriteFile(packagePath, make([]byte, 4*1024*1024), 0600))
+
+	file, err := os.Open(packagePath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	messages := []string{}
+	err = async.RunWithProgressE(func(progress ServiceProgress) {
+		messages = append(messages, progress.Message)
+	}, func(progress *async.Progress[ServiceProgress]) error {
+		reader, err := newUploadProgressReader(file, progress)
+		if err != nil {
+			return err
+		}
+
+		// Each read of 1 MB takes a second
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		reader.now = func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}
+
+		buf := make([]byte, 1024*1024)
+		if _, err := reader.Read(buf); err != nil {
+			return err
+		}
+
+		// A retry rewinds the package, restarting the progress
+		if _, err := reader.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		for {
+			if _, err := reader.Read(buf); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"Uploading deployment package (0.0 KB of 4.0 MB)",
+		"Uploading deployment package (1.0 MB of 4.0 MB, 3s left)",
+		"Uploading deployment package (0.0 KB of 4.0 MB)",
+		"Uploading deployment package (1.0 MB of 4.0 MB, 3s left)",
+		"Uploading deployment package (2.0 MB of 4.0 MB, 2s left)",
+		"Uploading deployment package (3.0 MB of 4.0 MB, 1s left)",
+		"Uploading deployment package (4.0 MB of 4.0 MB)",
+	}, messages)
+}
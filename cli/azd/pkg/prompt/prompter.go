@@ -24,6 +24,24 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/stringutil"
 )
 
+var (
+	subscriptionPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "SUBSCRIPTION",
+		Description: "The id or name of the Azure subscription to use.",
+		Commands:    []string{"provision", "up", "deploy", "env new", "env refresh", "down", "init"},
+	})
+	resourceGroupPromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "RESOURCE_GROUP",
+		Description: "The name of the resource group to use, or 'Create' to create a new one.",
+		Commands:    []string{"provision", "up", "deploy"},
+	})
+	resourceGroupNamePromptId = input.MustRegisterPrompt(input.PromptInfo{
+		Id:          "RESOURCE_GROUP_NAME",
+		Description: "The name of the resource group to create.",
+		Commands:    []string{"provision", "up", "deploy"},
+	})
+)
+
 type LocationFilterPredicate func(loc account.Location) bool
 
 type Prompter interface {
@@ -80,6 +98,7 @@ func (p *DefaultPrompter) PromptSubscription(ctx context.Context, msg string) (s
 
 	for subscriptionId == "" {
 		subscriptionSelectionIndex, err := p.console.Select(ctx, input.ConsoleOptions{
+			Id:           subscriptionPromptId,
 			Message:      msg,
 			Options:      subscriptionOptions,
 			DefaultValue: defaultSubscription,
@@ -174,6 +193,7 @@ func (p *DefaultPrompter) PromptResourceGroupFrom(
 	}
 
 	choice, err := p.console.Select(ctx, input.ConsoleOptions{
+		Id:      resourceGroupPromptId,
 		Message: "Pick a resource group to use:",
 		Options: choices,
 		Help:    options.PickResourceGroupHelp,
@@ -199,6 +219,7 @@ func (p *DefaultPrompter) PromptResourceGroupFrom(
 	}
 
 	name, err := p.console.Prompt(ctx, input.ConsoleOptions{
+		Id:           resourceGroupNamePromptId,
 		Message:      "Enter a name for the new resource group:",
 		DefaultValue: options.DefaultName,
 		Help:         options.NewResourceGroupHelp,
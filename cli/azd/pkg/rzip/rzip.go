@@ -5,11 +5,17 @@ package rzip
 
 import (
 	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"hash/crc32"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"unicode/utf8"
 )
 
 // OnZipFn is a function that is invoked on each file or directory,
@@ -19,30 +25,192 @@ type OnZipFn func(src string, info os.FileInfo) (bool, error)
 // CreateFromDirectory creates a zip archive from the given directory recursively,
 // that is suitable for transporting across machines.
 //
-// It resolves any symlinks it encounters.
+// It resolves any symlinks it encounters. The files are compressed in parallel, and written to the archive in the
+// order they're found in.
 //
 // An optional function callback onZip can be passed to observe files being included,
 // or simply to exclude files.
 func CreateFromDirectory(source string, buf *os.File, onZip OnZipFn) error {
-	w := zip.NewWriter(buf)
+	var entries []zipEntry
+	if err := addDir(&entries, "", source, onZip, 0); err != nil {
+		return err
+	}
 
-	err := addDirRoot(w, source, onZip)
-	if err != nil {
+	w := zip.NewWriter(buf)
+	if err := writeEntries(w, entries); err != nil {
 		return err
 	}
 
 	return w.Close()
 }
 
-func addDirRoot(
-	w *zip.Writer,
-	src string,
-	onZip OnZipFn) error {
-	return addDir(w, "", src, onZip, 0)
+// maxParallelFileSize is the size above which files are compressed while they're written to the archive, instead of
+// in parallel, to bound the memory used for the compressed files waiting to be written.
+const maxParallelFileSize = 8 * 1024 * 1024
+
+// compressionLevel matches the level archive/zip compresses with.
+const compressionLevel = 5
+
+// zipEntry is a file to add to the archive
+type zipEntry struct {
+	// The path of the file to read
+	src string
+	// The path of the file in the archive
+	dest string
+	info os.FileInfo
+}
+
+// compressedEntry is the result of compressing a zipEntry. data is nil when the file is too large to be compressed in
+// parallel.
+type compressedEntry struct {
+	header *zip.FileHeader
+	data   []byte
+	err    error
+}
+
+// writeEntries compresses the entries with a worker per CPU and writes them to the archive, preserving their order.
+func writeEntries(w *zip.Writer, entries []zipEntry) error {
+	results := make([]chan compressedEntry, len(entries))
+	for i := range results {
+		results[i] = make(chan compressedEntry, 1)
+	}
+
+	// Bounds the entries being compressed, or waiting to be written
+	workers := make(chan struct{}, runtime.NumCPU())
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for i, entry := range entries {
+			select {
+			case workers <- struct{}{}:
+			case <-done:
+				return
+			}
+
+			go func() {
+				results[i] <- compressEntry(entry)
+			}()
+		}
+	}()
+
+	for i, entry := range entries {
+		result := <-results[i]
+		<-workers
+
+		if result.err != nil {
+			return result.err
+		}
+
+		if result.data == nil {
+			if err := addFile(w, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		f, err := w.CreateRaw(result.header)
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(result.data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func entryHeader(entry zipEntry) *zip.FileHeader {
+	return &zip.FileHeader{
+		Name:     strings.ReplaceAll(entry.dest, "\\", "/"),
+		Modified: entry.info.ModTime(),
+		Method:   zip.Deflate,
+	}
+}
+
+// rawEntryHeader returns the header of an entry compressed ahead of time. It sets the fields that CreateHeader derives
+// from the name and modification time of the file, since CreateRaw writes the header as is.
+func rawEntryHeader(entry zipEntry) *zip.FileHeader {
+	header := entryHeader(entry)
+	header.CreatorVersion = zipVersion20
+	header.ReaderVersion = zipVersion20
+
+	modified := header.Modified
+	header.ModifiedDate = uint16(modified.Day() + int(modified.Month())<<5 + (modified.Year()-1980)<<9)
+	header.ModifiedTime = uint16(modified.Second()/2 + modified.Minute()<<5 + modified.Hour()<<11)
+
+	// The extended timestamp keeps the time zone of the modification time
+	extra := make([]byte, 9)
+	binary.LittleEndian.PutUint16(extra[0:], zipExtTimeExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], 5)
+	extra[4] = 1
+	binary.LittleEndian.PutUint32(extra[5:], uint32(modified.Unix()))
+	header.Extra = extra
+
+	if !isASCII(header.Name) && utf8.ValidString(header.Name) {
+		header.Flags |= zipFlagUTF8
+	}
+
+	return header
+}
+
+// The zip version and flag set by archive/zip on the headers it creates
+const (
+	zipVersion20      = 20
+	zipFlagUTF8       = 0x800
+	zipExtTimeExtraID = 0x5455
+)
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compressEntry compresses the file in memory, unless it is larger than maxParallelFileSize.
+func compressEntry(entry zipEntry) compressedEntry {
+	if entry.info.Size() > maxParallelFileSize {
+		return compressedEntry{}
+	}
+
+	in, err := os.Open(entry.src)
+	if err != nil {
+		return compressedEntry{err: err}
+	}
+	defer in.Close()
+
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, compressionLevel)
+	if err != nil {
+		return compressedEntry{err: err}
+	}
+
+	checksum := crc32.NewIEEE()
+	size, err := io.Copy(io.MultiWriter(fw, checksum), in)
+	if err != nil {
+		return compressedEntry{err: err}
+	}
+
+	if err := fw.Close(); err != nil {
+		return compressedEntry{err: err}
+	}
+
+	header := rawEntryHeader(entry)
+	header.CRC32 = checksum.Sum32()
+	header.UncompressedSize64 = uint64(size)
+	header.CompressedSize64 = uint64(compressed.Len())
+
+	return compressedEntry{header: header, data: compressed.Bytes()}
 }
 
 func addDir(
-	w *zip.Writer,
+	entries *[]zipEntry,
 	destRoot,
 	src string,
 	onZip OnZipFn,
@@ -53,12 +221,11 @@ func addDir(
 		return nil
 	}
 
-	entries, err := os.ReadDir(src)
+	dirEntries, err := os.ReadDir(src)
 	if err != nil {
 		return err
 	}
-
-	for _, entry := range entries {
+	for _, entry := range dirEntries {
 		s := filepath.Join(src, entry.Name())
 		info, err := os.Lstat(s)
 		if err != nil {
@@ -77,12 +244,12 @@ func addDir(
 
 		switch {
 		case info.Mode()&os.ModeSymlink != 0:
-			err = onSymlink(w, destRoot, s, info, onZip, symlinkDepth)
+			err = onSymlink(entries, destRoot, s, info, onZip, symlinkDepth)
 		case info.IsDir():
 			root := filepath.Join(destRoot, info.Name())
-			err = addDir(w, root, s, onZip, symlinkDepth)
+			err = addDir(entries, root, s, onZip, symlinkDepth)
 		default:
-			err = addFile(w, destRoot, s, info.Name(), info)
+			*entries = append(*entries, zipEntry{src: s, dest: filepath.Join(destRoot, info.Name()), info: info})
 		}
 
 		if err != nil {
@@ -92,25 +259,14 @@ func addDir(
 	return err
 }
 
-func addFile(
-	w *zip.Writer,
-	destRoot string,
-	src string,
-	name string,
-	info os.FileInfo) error {
-	dest := filepath.Join(destRoot, name)
-	header := &zip.FileHeader{
-		Name:     strings.ReplaceAll(dest, "\\", "/"),
-		Modified: info.ModTime(),
-		Method:   zip.Deflate,
-	}
-
-	f, err := w.CreateHeader(header)
+// addFile compresses the file while it's written to the archive
+func addFile(w *zip.Writer, entry zipEntry) error {
+	f, err := w.CreateHeader(entryHeader(entry))
 	if err != nil {
 		return err
 	}
 
-	in, err := os.Open(src)
+	in, err := os.Open(entry.src)
 	if err != nil {
 		return err
 	}
@@ -125,7 +281,7 @@ func addFile(
 }
 
 func onSymlink(
-	w *zip.Writer,
+	entries *[]zipEntry,
 	destRoot,
 	src string,
 	link os.FileInfo,
@@ -145,9 +301,10 @@ func onSymlink(
 	case info.IsDir():
 		symlinkDepth++
 		root := filepath.Join(destRoot, link.Name())
-		return addDir(w, root, target, onZip, symlinkDepth)
+		return addDir(entries, root, target, onZip, symlinkDepth)
 	default:
-		return addFile(w, destRoot, target, link.Name(), info)
+		*entries = append(*entries, zipEntry{src: target, dest: filepath.Join(destRoot, link.Name()), info: info})
+		return nil
 	}
 }
 
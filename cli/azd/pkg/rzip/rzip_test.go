@@ -13,6 +13,7 @@ import (
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
 	"github.com/stretchr/testify/require"
@@ -203,6 +204,50 @@ func TestCreateFromDirectory_SymlinkRecursive(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCreateFromDirectory_ManyFiles(t *testing.T) {
+	tmp := t.TempDir()
+	modTime := time.Date(2024, 5, 17, 10, 30, 42, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	// Small files are compressed in parallel, while the large file is compressed as it's written
+	expectedFiles := map[string]string{
+		"large.bin":   strings.Repeat("large file content ", 600_000),
+		"ünïcode.txt": "Content of unicode",
+	}
+	for i := range 100 {
+		expectedFiles[fmt.Sprintf("files/file%03d.txt", i)] = strings.Repeat(fmt.Sprintf("content %d ", i), i*100)
+	}
+
+	for path, content := range expectedFiles {
+		fullPath := filepath.Join(tmp, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+		require.NoError(t, os.Chtimes(fullPath, modTime, modTime))
+	}
+
+	zipFile, err := os.CreateTemp("", "test_archive_*.zip")
+	require.NoError(t, err, "failed to create temp zip file")
+	defer os.Remove(zipFile.Name())
+	defer zipFile.Close()
+
+	err = rzip.CreateFromDirectory(tmp, zipFile, nil)
+	require.NoError(t, err)
+
+	zipInfo, err := zipFile.Stat()
+	require.NoError(t, err)
+	zipReader, err := zip.NewReader(zipFile, zipInfo.Size())
+	require.NoError(t, err)
+
+	// The files are written in the order they're found in
+	names := []string{}
+	for _, file := range zipReader.File {
+		names = append(names, file.Name)
+		require.True(t, file.Modified.Equal(modTime), "modification time of %s", file.Name)
+	}
+	require.True(t, slices.IsSorted(names))
+
+	checkFiles(t, expectedFiles, zipFile)
+}
+
 func formatFiles(files map[string]string) string {
 	var sb strings.Builder
 	keys := slices.Collect(maps.Keys(files))
@@ -0,0 +1,283 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package secretguard prevents the credentials set in the environments from leaking to git. It verifies that the
+// environment directory is git-ignored, and finds the values which look like credentials when the .env file of an
+// environment is tracked by git anyway, ex) committed before the directory was ignored or added with 'git add -f'.
+package secretguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+)
+
+// ModeConfigPath is the user config path of what azd does when credentials are set in a .env file tracked by git
+const ModeConfigPath = "environment.secretGuard"
+
+// Mode is what azd does when credentials are set in a .env file tracked by git
+type Mode string
+
+const (
+	// Warns and offers to store the credentials in Key Vault instead, the default
+	ModeWarn Mode = "warn"
+	// Fails the command
+	ModeBlock Mode = "block"
+	// Disables the checks
+	ModeOff Mode = "off"
+)
+
+// ParseMode returns the mode of the given name, ModeWarn when empty.
+func ParseMode(name string) (Mode, error) {
+	mode := Mode(strings.ToLower(strings.TrimSpace(name)))
+	switch mode {
+	case "":
+		return ModeWarn, nil
+	case ModeWarn, ModeBlock, ModeOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid value '%s' for %s, supported values are: warn, block, off", name, ModeConfigPath)
+	}
+}
+
+// Finding is a value which looks like a credential
+type Finding struct {
+	// The key of the value in the environment
+	Key string
+	// The kind of credential, ex) GitHub token
+	Kind string
+}
+
+type credentialPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+// credentialPatterns match the credentials most commonly set in environments. They are specific enough to not match
+// regular values like resource names or URLs.
+var credentialPatterns = []credentialPattern{
+	{"storage account key", regexp.MustCompile(`(?i)AccountKey=[A-Za-z0-9+/]{40,}={0,2}`)},
+	{"shared access key", regexp.MustCompile(`(?i)SharedAccessKey=[A-Za-z0-9+/]{30,}={0,2}`)},
+	{"shared access signature", regexp.MustCompile(`(?i)[?&]sig=[A-Za-z0-9%+/]{30,}`)},
+	{"Microsoft Entra client secret", regexp.MustCompile(`\b[A-Za-z0-9_~.-]{3}\dQ~[A-Za-z0-9_~.-]{31,34}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"password in URL", regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:[^/\s:@]+@`)},
+}
+
+// Scan returns the values which look like credentials, sorted by key. Key Vault references aren't credentials.
+func Scan(values map[string]string) []Finding {
+	findings := []Finding{}
+	for key, value := range values {
+		if keyvault.IsAzureKeyVaultSecret(value) {
+			continue
+		}
+
+		for _, credential := range credentialPatterns {
+			if credential.pattern.MatchString(value) {
+				findings = append(findings, Finding{Key: key, Kind: credential.kind})
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(findings, func(a, b Finding) int { return strings.Compare(a.Key, b.Key) })
+	return findings
+}
+
+// Status is the status of the .env file of an environment in the git repository of the project
+type Status struct {
+	// False when the project isn't in a git repository, or git isn't installed. The other fields are false then.
+	InRepository bool
+	// The environment directory is ignored
+	Ignored bool
+	// The .env file of the environment is tracked, its changes are committed with the other changes
+	Tracked bool
+}
+
+var storePromptId = input.MustRegisterPrompt(input.PromptInfo{
+	Id:          "SECRET_GUARD_STORE",
+	Description: "Whether to store the credentials set in a .env file tracked by git in Key Vault instead.",
+	Commands:    []string{"env set"},
+})
+
+// secretStore stores secrets and returns references to them, implemented by provisioning.ParameterSecrets
+type secretStore interface {
+	Store(ctx context.Context, env *environment.Environment, name string, value string, createVault bool) (string, error)
+}
+
+// Guard checks the status of the environments in the git repository of the project, and protects the credentials set
+// in the environments.
+type Guard struct {
+	gitCli            *git.Cli
+	console           input.Console
+	userConfigManager config.UserConfigManager
+	secrets           secretStore
+}
+
+// NewGuard creates a new Guard
+func NewGuard(
+	gitCli *git.Cli,
+	console input.Console,
+	userConfigManager config.UserConfigManager,
+	parameterSecrets *provisioning.ParameterSecrets,
+) *Guard {
+	return &Guard{
+		gitCli:            gitCli,
+		console:           console,
+		userConfigManager: userConfigManager,
+		secrets:           parameterSecrets,
+	}
+}
+
+// Protect checks the values about to be set in the environment. When the .env file of the environment is tracked by
+// git and values look like credentials, it warns and offers to store them in the Key Vault of the environment, replacing
+// them in values by references to the secrets, or fails with the block mode.
+func (g *Guard) Protect(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	env *environment.Environment,
+	values map[string]string,
+) error {
+	userConfig, err := g.userConfigManager.Load()
+	if err != nil {
+		return fmt.Errorf("loading user config: %w", err)
+	}
+
+	modeName, _ := userConfig.GetString(ModeConfigPath)
+	mode, err := ParseMode(modeName)
+	if err != nil {
+		return err
+	}
+
+	if mode == ModeOff {
+		return nil
+	}
+
+	status, err := g.Check(ctx, azdCtx, env.Name())
+	if err != nil {
+		// The checks don't prevent setting values when git fails
+		log.Printf("failed checking the git status of the environment: %v", err)
+		return nil
+	}
+
+	findings := Scan(values)
+	if !status.Tracked || len(findings) == 0 {
+		return nil
+	}
+
+	dotEnvPath := filepath.Join(azdCtx.EnvironmentRoot(env.Name()), azdcontext.DotEnvFileName)
+	if relativePath, err := filepath.Rel(azdCtx.ProjectDirectory(), dotEnvPath); err == nil {
+		dotEnvPath = relativePath
+	}
+
+	lines := []string{fmt.Sprintf("%s is tracked by git, and these values look like credentials:", dotEnvPath)}
+	for _, finding := range findings {
+		lines = append(lines, fmt.Sprintf("  %s %s (%s)", output.Bullet(), finding.Key, finding.Kind))
+	}
+	g.console.MessageUxItem(ctx, &ux.WarningMessage{Description: strings.Join(lines, "\n")})
+
+	if mode == ModeBlock {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("refusing to set credentials in %s, which is tracked by git", dotEnvPath),
+			Suggestion: fmt.Sprintf(
+				"Suggestion: stop tracking the file with '%s', or set the values with '%s'.",
+				output.WithHighLightFormat("git rm --cached %s", filepath.ToSlash(dotEnvPath)),
+				output.WithHighLightFormat("azd env set-secret"),
+			),
+		}
+	}
+
+	store, err := g.console.Confirm(ctx, input.ConsoleOptions{
+		Id:           storePromptId,
+		Message:      "Store them in the Key Vault of the environment instead, and set references to the secrets?",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return fmt.Errorf("prompting to store the credentials in Key Vault: %w", err)
+	}
+
+	if !store {
+		return nil
+	}
+
+	for _, finding := range findings {
+		// The user chose to store the values in Key Vault, it's created when the environment doesn't have one
+		reference, err := g.secrets.Store(ctx, env, finding.Key, values[finding.Key], true)
+		if errors.Is(err, provisioning.ErrParametersKeyVaultUnavailable) {
+			return &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: fmt.Sprintf(
+					"Suggestion: set the subscription and the location of the environment first, or use '%s'.",
+					output.WithHighLightFormat("azd env set-secret")),
+			}
+		} else if err != nil {
+			return err
+		}
+
+		values[finding.Key] = reference
+	}
+
+	return nil
+}
+
+// Check returns the status of the .env file of the environment. The .gitignore file of the environment directory is
+// restored when the directory isn't ignored, ex) after the file was deleted.
+func (g *Guard) Check(ctx context.Context, azdCtx *azdcontext.AzdContext, envName string) (Status, error) {
+	if err := g.gitCli.CheckInstalled(ctx); err != nil {
+		log.Printf("skipping the git checks of the environment: %v", err)
+		return Status{}, nil
+	}
+
+	projectDir := azdCtx.ProjectDirectory()
+	dotEnvPath := filepath.Join(azdCtx.EnvironmentRoot(envName), azdcontext.DotEnvFileName)
+	tracked, err := g.gitCli.IsTrackedFile(ctx, projectDir, dotEnvPath)
+	if errors.Is(err, git.ErrNotRepository) {
+		return Status{}, nil
+	} else if err != nil {
+		return Status{}, err
+	}
+
+	// The files of the environment directory are ignored by its own .gitignore, which git doesn't apply to tracked files
+	ignored := false
+	if !tracked {
+		if ignored, err = g.gitCli.IsIgnored(ctx, projectDir, dotEnvPath); err != nil {
+			return Status{}, err
+		}
+
+		if !ignored {
+			log.Printf("the environment directory of '%s' isn't git-ignored, restoring its .gitignore", envName)
+			if err := azdCtx.WriteEnvironmentGitIgnore(); err != nil {
+				return Status{}, fmt.Errorf("restoring the .gitignore of the environment directory: %w", err)
+			}
+
+			if ignored, err = g.gitCli.IsIgnored(ctx, projectDir, dotEnvPath); err != nil {
+				return Status{}, err
+			}
+		}
+	}
+
+	return Status{
+		InRepository: true,
+		Ignored:      ignored,
+		Tracked:      tracked,
+	}, nil
+}
@@ -0,0 +1,172 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// The files of the report of the last run, in the directory of the environment
+const (
+	ReportJsonFileName     = "last-run.json"
+	ReportMarkdownFileName = "last-run.md"
+)
+
+// PhaseReport is a phase of the operation, with its duration
+type PhaseReport struct {
+	Phase
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// Report is the summary of a completed operation, ex) 'azd up', written to the directory of the environment as JSON
+// and markdown so CI pipelines can keep it as an artifact of the run.
+type Report struct {
+	Operation       string        `json:"operation"`
+	Environment     string        `json:"environment"`
+	State           State         `json:"state"`
+	StartedAt       time.Time     `json:"startedAt"`
+	EndedAt         time.Time     `json:"endedAt"`
+	DurationSeconds float64       `json:"durationSeconds"`
+	Error           string        `json:"error,omitempty"`
+	Phases          []PhaseReport `json:"phases"`
+	Resources       []Resource    `json:"resources"`
+	Services        []Service     `json:"services"`
+	Warnings        []string      `json:"warnings"`
+}
+
+// NewReport creates the report of the operation of the snapshot, ended now when it is still running
+func NewReport(environment string, snapshot Snapshot) *Report {
+	endedAt := time.Now()
+	if snapshot.EndedAt != nil {
+		endedAt = *snapshot.EndedAt
+	}
+
+	report := &Report{
+		Operation:       snapshot.Operation,
+		Environment:     environment,
+		State:           snapshot.State,
+		StartedAt:       snapshot.StartedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: seconds(endedAt.Sub(snapshot.StartedAt)),
+		Error:           snapshot.Error,
+		Phases:          make([]PhaseReport, 0, len(snapshot.Phases)),
+		Resources:       snapshot.Resources,
+		Services:        snapshot.Services,
+		Warnings:        snapshot.Warnings,
+	}
+
+	for _, phase := range snapshot.Phases {
+		phaseEnd := endedAt
+		if phase.EndedAt != nil {
+			phaseEnd = *phase.EndedAt
+		}
+
+		report.Phases = append(report.Phases, PhaseReport{
+			Phase:           phase,
+			DurationSeconds: seconds(phaseEnd.Sub(phase.StartedAt)),
+		})
+	}
+
+	return report
+}
+
+func seconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
+
+// Markdown returns the report as markdown, ex) for the summary of a GitHub Actions job
+func (r *Report) Markdown() string {
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "# azd %s: %s\n\n", r.Operation, r.State)
+	fmt.Fprintf(&builder, "- Environment: %s\n", r.Environment)
+	fmt.Fprintf(&builder, "- Started: %s\n", r.StartedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&builder, "- Duration: %s\n", formatSeconds(r.DurationSeconds))
+	if r.Error != "" {
+		fmt.Fprintf(&builder, "- Error: %s\n", r.Error)
+	}
+
+	if len(r.Phases) > 0 {
+		builder.WriteString("\n## Phases\n\n| Phase | State | Duration |\n| --- | --- | --- |\n")
+		for _, phase := range r.Phases {
+			fmt.Fprintf(&builder, "| %s | %s | %s |\n", phase.Name, phase.State, formatSeconds(phase.DurationSeconds))
+		}
+	}
+
+	if len(r.Resources) > 0 {
+		builder.WriteString("\n## Resources\n\n| Type | Name | State |\n| --- | --- | --- |\n")
+		for _, resource := range r.Resources {
+			fmt.Fprintf(&builder, "| %s | %s | %s |\n", resource.Type, resource.Name, resource.State)
+		}
+	}
+
+	if len(r.Services) > 0 {
+		builder.WriteString("\n## Services\n\n| Service | Endpoints | Image |\n| --- | --- | --- |\n")
+		for _, service := range r.Services {
+			image := service.Image
+			if service.ImageDigest != "" {
+				image = fmt.Sprintf("%s@%s", image, service.ImageDigest)
+			}
+
+			fmt.Fprintf(&builder, "| %s | %s | %s |\n", service.Name, strings.Join(service.Endpoints, "<br>"), image)
+		}
+	}
+
+	if len(r.Warnings) > 0 {
+		builder.WriteString("\n## Warnings\n\n")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(&builder, "- %s\n", strings.ReplaceAll(warning, "\n", " "))
+		}
+	}
+
+	return builder.String()
+}
+
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
+
+// WriteReport writes the report to the directory, as JSON and as markdown
+func WriteReport(dir string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling the report: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ReportJsonFileName), data, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing the report: %w", err)
+	}
+
+	markdown := []byte(report.Markdown())
+	if err := os.WriteFile(filepath.Join(dir, ReportMarkdownFileName), markdown, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing the report: %w", err)
+	}
+
+	return nil
+}
+
+// ReadReport reads the report written to the directory. The error wraps os.ErrNotExist when there is no report.
+func ReadReport(dir string) (*Report, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ReportJsonFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("reading the report: %w", err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewReport(t *testing.T) {
+	startedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	provisioned := startedAt.Add(90 * time.Second)
+	endedAt := startedAt.Add(2 * time.Minute)
+
+	report := NewReport("dev", Snapshot{
+		Operation: "up",
+		State:     StateFailed,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Error:     "deploying service api: failed",
+		Phases: []Phase{
+			{Name: "provision", State: StateSucceeded, StartedAt: startedAt, EndedAt: &provisioned},
+			{Name: "deploy --all", State: StateFailed, StartedAt: provisioned, EndedAt: &endedAt},
+		},
+		Resources: []Resource{{Type: "Container App", Name: "ca-api", State: "Succeeded"}},
+		Services: []Service{{
+			Name:        "api",
+			Endpoints:   []string{"https://api.example.com/"},
+			Image:       "cr.azurecr.io/api:azd-1",
+			ImageDigest: "sha256:abc",
+		}},
+		Warnings: []string{"The location is deprecated"},
+	})
+
+	require.Equal(t, "dev", report.Environment)
+	require.Equal(t, 120.0, report.DurationSeconds)
+	require.Equal(t, 90.0, report.Phases[0].DurationSeconds)
+	require.Equal(t, 30.0, report.Phases[1].DurationSeconds)
+
+	markdown := report.Markdown()
+	require.Contains(t, markdown, "# azd up: failed")
+	require.Contains(t, markdown, "- Duration: 2m0s")
+	require.Contains(t, markdown, "| provision | succeeded | 1m30s |")
+	require.Contains(t, markdown, "| Container App | ca-api | Succeeded |")
+	require.Contains(t, markdown, "| api | https://api.example.com/ | cr.azurecr.io/api:azd-1@sha256:abc |")
+	require.Contains(t, markdown, "- The location is deprecated")
+}
+
+func Test_WriteReport(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ReadReport(dir)
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	tracker := NewTracker("up")
+	tracker.StartPhase("provision")
+	tracker.EndPhase("provision", nil)
+	tracker.Complete(nil)
+
+	require.NoError(t, WriteReport(dir, NewReport("dev", tracker.Snapshot())))
+	require.FileExists(t, filepath.Join(dir, ReportMarkdownFileName))
+
+	report, err := ReadReport(dir)
+	require.NoError(t, err)
+	require.Equal(t, StateSucceeded, report.State)
+	require.Equal(t, "provision", report.Phases[0].Name)
+	require.Empty(t, report.Services)
+}
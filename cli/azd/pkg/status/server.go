@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Server serves the state of a tracked operation:
+//
+//	GET /status returns the snapshot of the operation as JSON
+//	GET /events streams the changes of the operation as server-sent events, starting with the snapshot
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// Serve starts serving the state of the operation on the address, ex) :7070. The server only listens on localhost
+// when the address has no host.
+func Serve(address string, tracker *Tracker) (*Server, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status endpoint address '%s', expected [host]:port: %w", address, err)
+	}
+
+	if host == "" {
+		host = "localhost"
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", statusHandler(tracker))
+	mux.HandleFunc("GET /events", eventsHandler(tracker))
+
+	server := &Server{
+		listener: listener,
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+	}
+
+	go func() {
+		if err := server.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("status endpoint stopped: %v", err)
+		}
+	}()
+
+	return server, nil
+}
+
+// Url returns the base URL of the server, ex) http://127.0.0.1:7070
+func (s *Server) Url() string {
+	return fmt.Sprintf("http://%s", s.listener.Addr().String())
+}
+
+// Close stops the server, after giving the clients of the event stream a moment to receive the last events.
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
+
+func statusHandler(tracker *Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(tracker.Snapshot()); err != nil {
+			log.Printf("failed writing the status: %v", err)
+		}
+	}
+}
+
+func eventsHandler(tracker *Tracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		events, unsubscribe := tracker.Subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					log.Printf("failed marshaling the status event: %v", err)
+					continue
+				}
+
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
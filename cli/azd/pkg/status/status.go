@@ -0,0 +1,376 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package status tracks the live state of a long operation like 'azd up', ex) its phases, the resources being
+// provisioned and its logs, and serves it on a local HTTP endpoint so dashboards, IDEs and test harnesses can observe
+// the operation without parsing the console output.
+package status
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// State is the state of an operation, or of one of its phases
+type State string
+
+const (
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// Phase is a phase of an operation, ex) the provision step of 'azd up'
+type Phase struct {
+	Name      string     `json:"name"`
+	State     State      `json:"state"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Resource is a resource created by an operation, as displayed in the console while provisioning
+type Resource struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// Service is a service deployed by an operation
+type Service struct {
+	Name      string   `json:"name"`
+	Endpoints []string `json:"endpoints"`
+	// The container image deployed, ex) the image pushed to the registry for Container Apps
+	Image       string `json:"image,omitempty"`
+	ImageDigest string `json:"imageDigest,omitempty"`
+}
+
+// Snapshot is the state of an operation at a point in time
+type Snapshot struct {
+	Operation string     `json:"operation"`
+	State     State      `json:"state"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	// The title of the step in progress, ex) the message of the spinner of the console
+	CurrentStep string     `json:"currentStep,omitempty"`
+	Phases      []Phase    `json:"phases"`
+	Resources   []Resource `json:"resources"`
+	Services    []Service  `json:"services"`
+	Warnings    []string   `json:"warnings"`
+	// The last lines written to the console, at most maxLogs
+	Logs []string `json:"logs"`
+}
+
+// EventKind is the kind of change of an event
+type EventKind string
+
+const (
+	// The full snapshot, sent first to each subscriber
+	EventSnapshot  EventKind = "snapshot"
+	EventPhase     EventKind = "phase"
+	EventStep      EventKind = "step"
+	EventResource  EventKind = "resource"
+	EventService   EventKind = "service"
+	EventWarning   EventKind = "warning"
+	EventLog       EventKind = "log"
+	EventCompleted EventKind = "completed"
+)
+
+// Event is a change of the state of an operation. Only the field matching the kind is set.
+type Event struct {
+	Kind     EventKind `json:"kind"`
+	Snapshot *Snapshot `json:"snapshot,omitempty"`
+	Phase    *Phase    `json:"phase,omitempty"`
+	Step     string    `json:"step,omitempty"`
+	Resource *Resource `json:"resource,omitempty"`
+	Service  *Service  `json:"service,omitempty"`
+	Warning  string    `json:"warning,omitempty"`
+	Log      string    `json:"log,omitempty"`
+}
+
+// The maximum number of log lines kept in the snapshot
+const maxLogs = 500
+
+// The number of events buffered for each subscriber, events are dropped for subscribers which don't keep up
+const subscriberBuffer = 256
+
+// Tracker tracks the state of an operation and publishes its changes to the subscribers. The methods of a nil Tracker
+// do nothing, so the commands can report their progress whether the operation is observed or not.
+type Tracker struct {
+	mu          sync.Mutex
+	snapshot    Snapshot
+	subscribers map[chan Event]struct{}
+}
+
+// NewTracker creates a tracker of a running operation, ex) up
+func NewTracker(operation string) *Tracker {
+	return &Tracker{
+		snapshot: Snapshot{
+			Operation: operation,
+			State:     StateRunning,
+			StartedAt: time.Now(),
+			Phases:    []Phase{},
+			Resources: []Resource{},
+			Services:  []Service{},
+			Warnings:  []string{},
+			Logs:      []string{},
+		},
+		subscribers: map[chan Event]struct{}{},
+	}
+}
+
+// Snapshot returns a copy of the current state of the operation
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.copySnapshot()
+}
+
+func (t *Tracker) copySnapshot() Snapshot {
+	snapshot := t.snapshot
+	snapshot.Phases = append([]Phase{}, t.snapshot.Phases...)
+	snapshot.Resources = append([]Resource{}, t.snapshot.Resources...)
+	snapshot.Services = append([]Service{}, t.snapshot.Services...)
+	snapshot.Warnings = append([]string{}, t.snapshot.Warnings...)
+	snapshot.Logs = append([]string{}, t.snapshot.Logs...)
+	return snapshot
+}
+
+// StartPhase starts a phase of the operation, ex) provision
+func (t *Tracker) StartPhase(name string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	phase := Phase{Name: name, State: StateRunning, StartedAt: time.Now()}
+	t.snapshot.Phases = append(t.snapshot.Phases, phase)
+	t.publish(Event{Kind: EventPhase, Phase: &phase})
+}
+
+// EndPhase ends the last phase of the given name, failed when err isn't nil
+func (t *Tracker) EndPhase(name string, err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := len(t.snapshot.Phases) - 1; i >= 0; i-- {
+		phase := &t.snapshot.Phases[i]
+		if phase.Name != name || phase.EndedAt != nil {
+			continue
+		}
+
+		now := time.Now()
+		phase.EndedAt = &now
+		phase.State, phase.Error = result(err)
+
+		ended := *phase
+		t.publish(Event{Kind: EventPhase, Phase: &ended})
+		return
+	}
+}
+
+// SetStep sets the title of the step in progress
+func (t *Tracker) SetStep(title string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.snapshot.CurrentStep = title
+	t.publish(Event{Kind: EventStep, Step: title})
+}
+
+// SetResource adds the resource, or updates its state when it is already tracked
+func (t *Tracker) SetResource(resourceType string, name string, state string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resource := Resource{Type: resourceType, Name: name, State: state}
+	updated := false
+	for i, existing := range t.snapshot.Resources {
+		if existing.Type == resourceType && existing.Name == name {
+			t.snapshot.Resources[i] = resource
+			updated = true
+			break
+		}
+	}
+
+	if !updated {
+		t.snapshot.Resources = append(t.snapshot.Resources, resource)
+	}
+
+	t.publish(Event{Kind: EventResource, Resource: &resource})
+}
+
+// SetService adds the deployed service, or replaces it when it is already tracked
+func (t *Tracker) SetService(service Service) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	updated := false
+	for i, existing := range t.snapshot.Services {
+		if existing.Name == service.Name {
+			t.snapshot.Services[i] = service
+			updated = true
+			break
+		}
+	}
+
+	if !updated {
+		t.snapshot.Services = append(t.snapshot.Services, service)
+	}
+
+	t.publish(Event{Kind: EventService, Service: &service})
+}
+
+// Warn adds a warning written to the console, without its colors
+func (t *Tracker) Warn(message string) {
+	if t == nil {
+		return
+	}
+
+	message = strings.TrimSpace(output.StripAnsi(message))
+	if message == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.snapshot.Warnings = append(t.snapshot.Warnings, message)
+	t.publish(Event{Kind: EventWarning, Warning: message})
+}
+
+// Log adds a message written to the console, without its colors. Empty messages are ignored.
+func (t *Tracker) Log(message string) {
+	if t == nil {
+		return
+	}
+
+	message = strings.TrimRight(output.StripAnsi(message), "\n")
+	if strings.TrimSpace(message) == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.snapshot.Logs = append(t.snapshot.Logs, message)
+	if len(t.snapshot.Logs) > maxLogs {
+		t.snapshot.Logs = t.snapshot.Logs[len(t.snapshot.Logs)-maxLogs:]
+	}
+
+	t.publish(Event{Kind: EventLog, Log: message})
+}
+
+// Complete ends the operation, failed when err isn't nil, and closes the subscriptions
+func (t *Tracker) Complete(err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.snapshot.EndedAt != nil {
+		return
+	}
+
+	now := time.Now()
+	t.snapshot.EndedAt = &now
+	t.snapshot.CurrentStep = ""
+	t.snapshot.State, t.snapshot.Error = result(err)
+
+	snapshot := t.copySnapshot()
+	t.publish(Event{Kind: EventCompleted, Snapshot: &snapshot})
+	t.closeSubscribers()
+}
+
+// Subscribe returns the channel receiving the changes of the operation, starting with the current snapshot, and the
+// function ending the subscription. The channel is closed when the operation completes.
+func (t *Tracker) Subscribe() (<-chan Event, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	events := make(chan Event, subscriberBuffer)
+	snapshot := t.copySnapshot()
+	events <- Event{Kind: EventSnapshot, Snapshot: &snapshot}
+
+	if t.snapshot.EndedAt != nil {
+		close(events)
+		return events, func() {}
+	}
+
+	t.subscribers[events] = struct{}{}
+	return events, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if _, has := t.subscribers[events]; has {
+			delete(t.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// publish sends the event to the subscribers, t.mu must be held
+func (t *Tracker) publish(event Event) {
+	for events := range t.subscribers {
+		select {
+		case events <- event:
+		default:
+			// The subscriber doesn't keep up, it can get the full state from the snapshot
+		}
+	}
+}
+
+// closeSubscribers closes the channels of the subscribers, t.mu must be held
+func (t *Tracker) closeSubscribers() {
+	for events := range t.subscribers {
+		delete(t.subscribers, events)
+		close(events)
+	}
+}
+
+func result(err error) (State, string) {
+	if err != nil {
+		return StateFailed, err.Error()
+	}
+
+	return StateSucceeded, ""
+}
+
+type trackerContextKey struct{}
+
+// WithTracker returns a context reporting the progress of the commands run with it to the tracker.
+func WithTracker(ctx context.Context, tracker *Tracker) context.Context {
+	return context.WithValue(ctx, trackerContextKey{}, tracker)
+}
+
+// FromContext returns the tracker of the context, nil when the operation isn't tracked.
+func FromContext(ctx context.Context) *Tracker {
+	tracker, _ := ctx.Value(trackerContextKey{}).(*Tracker)
+	return tracker
+}
@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package status
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tracker(t *testing.T) {
+	tracker := NewTracker("up")
+	events, unsubscribe := tracker.Subscribe()
+	defer unsubscribe()
+
+	tracker.StartPhase("package --all")
+	tracker.EndPhase("package --all", nil)
+	tracker.StartPhase("provision")
+	tracker.SetStep("Creating/Updating resources")
+	tracker.SetResource("Resource group", "rg-dev", "Succeeded")
+	tracker.SetResource("App Service", "app-api", "Running")
+	tracker.SetResource("App Service", "app-api", "Failed")
+	tracker.Log("\x1b[32mSUCCESS\x1b[0m: resource group\n")
+	tracker.Log("")
+	tracker.EndPhase("provision", errors.New("deployment failed"))
+	tracker.Complete(errors.New("deployment failed"))
+
+	snapshot := tracker.Snapshot()
+	require.Equal(t, "up", snapshot.Operation)
+	require.Equal(t, StateFailed, snapshot.State)
+	require.Equal(t, "deployment failed", snapshot.Error)
+	require.NotNil(t, snapshot.EndedAt)
+	require.Empty(t, snapshot.CurrentStep)
+	require.Len(t, snapshot.Phases, 2)
+	require.Equal(t, StateSucceeded, snapshot.Phases[0].State)
+	require.Equal(t, StateFailed, snapshot.Phases[1].State)
+	require.Equal(t, []Resource{
+		{Type: "Resource group", Name: "rg-dev", State: "Succeeded"},
+		{Type: "App Service", Name: "app-api", State: "Failed"},
+	}, snapshot.Resources)
+	require.Equal(t, []string{"SUCCESS: resource group"}, snapshot.Logs)
+
+	kinds := []EventKind{}
+	for event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	require.Equal(t, []EventKind{
+		EventSnapshot,
+		EventPhase, EventPhase,
+		EventPhase,
+		EventStep,
+		EventResource, EventResource, EventResource,
+		EventLog,
+		EventPhase,
+		EventCompleted,
+	}, kinds)
+}
+
+func Test_Tracker_Nil(t *testing.T) {
+	var tracker *Tracker
+	require.Nil(t, FromContext(context.Background()))
+
+	// Reporting progress without a tracker does nothing
+	tracker.StartPhase("provision")
+	tracker.SetStep("step")
+	tracker.Log("message")
+	tracker.Complete(nil)
+
+	tracker = NewTracker("up")
+	require.Same(t, tracker, FromContext(WithTracker(context.Background(), tracker)))
+}
+
+func Test_Tracker_Logs(t *testing.T) {
+	tracker := NewTracker("up")
+	for i := 0; i < maxLogs+10; i++ {
+		tracker.Log("line")
+	}
+
+	require.Len(t, tracker.Snapshot().Logs, maxLogs)
+}
+
+func Test_Handlers(t *testing.T) {
+	tracker := NewTracker("up")
+	tracker.StartPhase("provision")
+
+	t.Run("Status", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		statusHandler(tracker)(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+		var snapshot Snapshot
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &snapshot))
+		require.Equal(t, StateRunning, snapshot.State)
+		require.Equal(t, "provision", snapshot.Phases[0].Name)
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		server := httptest.NewServer(eventsHandler(tracker))
+		defer server.Close()
+
+		res, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		tracker.Log("Provisioning resources")
+		tracker.Complete(nil)
+
+		lines := []string{}
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "event: ") {
+				lines = append(lines, scanner.Text())
+			}
+		}
+
+		require.Equal(t, []string{"event: snapshot", "event: log", "event: completed"}, lines)
+	})
+}
+
+func Test_Serve(t *testing.T) {
+	_, err := Serve("7070", NewTracker("up"))
+	require.ErrorContains(t, err, "invalid status endpoint address '7070'")
+
+	server, err := Serve(":0", NewTracker("up"))
+	require.NoError(t, err)
+	defer server.Close()
+
+	require.True(t, strings.HasPrefix(server.Url(), "http://127.0.0.1:") || strings.HasPrefix(server.Url(), "http://[::1]:"))
+
+	res, err := http.Get(server.Url() + "/status")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func Test_Tracker_ServicesWarnings(t *testing.T) {
+	tracker := NewTracker("up")
+	tracker.SetService(Service{Name: "api", Endpoints: []string{"https://api.example.com/"}})
+	tracker.SetService(Service{Name: "api", Endpoints: []string{"https://api.example.com/"}, Image: "cr.io/api:1"})
+	tracker.Warn("\x1b[33mThe location is deprecated\x1b[0m\n")
+	tracker.Warn(" ")
+
+	snapshot := tracker.Snapshot()
+	require.Equal(t, []Service{
+		{Name: "api", Endpoints: []string{"https://api.example.com/"}, Image: "cr.io/api:1"},
+	}, snapshot.Services)
+	require.Equal(t, []string{"The location is deprecated"}, snapshot.Warnings)
+}
@@ -35,7 +35,7 @@ func NewCli(
 	console input.Console,
 	commandRunner exec.CommandRunner,
 ) (*Cli, error) {
-	return newCliWithTransporter(ctx, console, commandRunner, http.DefaultClient)
+	return newCliWithTransporter(ctx, console, commandRunner, tools.NewDownloadTransporter(http.DefaultClient))
 }
 
 // newCliWithTransporter is like NewBicepCli but allows providing a custom transport to use when downloading the
@@ -50,8 +50,9 @@ func newCliWithTransporter(
 		log.Printf("using external bicep tool: %s", override)
 
 		return &Cli{
-			path:   override,
-			runner: commandRunner,
+			path:     override,
+			runner:   commandRunner,
+			external: true,
 		}, nil
 	}
 
@@ -78,8 +79,10 @@ func newCliWithTransporter(
 	}
 
 	cli := &Cli{
-		path:   bicepPath,
-		runner: commandRunner,
+		path:        bicepPath,
+		runner:      commandRunner,
+		console:     console,
+		transporter: transporter,
 	}
 
 	ver, err := cli.version(ctx)
@@ -123,6 +126,12 @@ func runStep(ctx context.Context, console input.Console, title string, action fu
 type Cli struct {
 	path   string
 	runner exec.CommandRunner
+	// external is set when the bicep CLI is provided by the user with AZD_BICEP_TOOL_PATH, and never replaced.
+	external bool
+
+	// Used to download the version pinned by the project
+	console     input.Console
+	transporter policy.Transporter
 }
 
 // azdBicepPath returns the path where we store our local copy of bicep ($AZD_CONFIG_DIR/bin).
@@ -141,34 +150,9 @@ func azdBicepPath() (string, error) {
 
 // downloadBicep downloads a given version of bicep from the release site, writing the output to name.
 func downloadBicep(ctx context.Context, transporter policy.Transporter, bicepVersion semver.Version, name string) error {
-	var arch string
-	switch runtime.GOARCH {
-	case "amd64":
-		arch = "x64"
-	case "arm64":
-		arch = "arm64"
-	default:
-		return fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
-	}
-
-	var releaseName string
-	switch runtime.GOOS {
-	case "windows":
-		releaseName = fmt.Sprintf("bicep-win-%s.exe", arch)
-	case "darwin":
-		releaseName = fmt.Sprintf("bicep-osx-%s", arch)
-	case "linux":
-		if preferMuslBicep(os.Stat) {
-			if runtime.GOARCH != "arm64" {
-				return fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
-			}
-
-			releaseName = "bicep-linux-musl-x64"
-		} else {
-			releaseName = fmt.Sprintf("bicep-linux-%s", arch)
-		}
-	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	releaseName, err := bicepReleaseName()
+	if err != nil {
+		return err
 	}
 
 	bicepReleaseUrl := fmt.Sprintf("https://downloads.bicep.azure.com/v%s/%s", bicepVersion, releaseName)
@@ -217,6 +201,41 @@ func downloadBicep(ctx context.Context, transporter policy.Transporter, bicepVer
 	return nil
 }
 
+// bicepReleaseName returns the name of the release of bicep for the current platform, ex) bicep-linux-x64
+func bicepReleaseName() (string, error) {
+	var arch string
+	switch runtime.GOARCH {
+	case "amd64":
+		arch = "x64"
+	case "arm64":
+		arch = "arm64"
+	default:
+		return "", fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+	}
+
+	var releaseName string
+	switch runtime.GOOS {
+	case "windows":
+		releaseName = fmt.Sprintf("bicep-win-%s.exe", arch)
+	case "darwin":
+		releaseName = fmt.Sprintf("bicep-osx-%s", arch)
+	case "linux":
+		if preferMuslBicep(os.Stat) {
+			if runtime.GOARCH != "arm64" {
+				return "", fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+			}
+
+			releaseName = "bicep-linux-musl-x64"
+		} else {
+			releaseName = fmt.Sprintf("bicep-linux-%s", arch)
+		}
+	default:
+		return "", fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+
+	return releaseName, nil
+}
+
 type stater func(name string) (os.FileInfo, error)
 
 // preferMuslBicep determines if we should install the version of bicep that used musl instead of glibc. We prefer
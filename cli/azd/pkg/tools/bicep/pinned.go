@@ -0,0 +1,278 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/blang/semver/v4"
+)
+
+// WithVersion returns a CLI running the version of bicep pinned by the project, leaving the CLI unchanged. Each pinned
+// version is downloaded once to its own directory, side-by-side with the version managed by azd. The download is
+// verified against the digest GitHub publishes for the release of bicep, and the checksum recorded then is verified
+// every time the version is used.
+//
+// The bicep CLI provided with AZD_BICEP_TOOL_PATH is always used, regardless of the pinned version.
+func (cli *Cli) WithVersion(ctx context.Context, version semver.Version) (*Cli, error) {
+	if cli.external {
+		log.Printf("ignoring pinned bicep version %s, using external bicep tool: %s", version, cli.path)
+		return cli, nil
+	}
+
+	bicepPath, err := pinnedBicepPath(version)
+	if err != nil {
+		return nil, fmt.Errorf("finding bicep %s: %w", version, err)
+	}
+
+	checksumPath := bicepPath + ".sha256"
+	if _, err := os.Stat(bicepPath); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(bicepPath), osutil.PermissionDirectory); err != nil {
+			return nil, fmt.Errorf("downloading bicep %s: %w", version, err)
+		}
+
+		if err := runStep(
+			ctx, cli.console, fmt.Sprintf("Downloading Bicep %s", version), func() error {
+				return downloadPinnedBicep(ctx, cli.transporter, version, bicepPath, checksumPath)
+			},
+		); err != nil {
+			return nil, fmt.Errorf("downloading bicep %s: %w", version, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("finding bicep %s: %w", version, err)
+	}
+
+	if err := verifyChecksum(bicepPath, checksumPath); err != nil {
+		return nil, err
+	}
+
+	log.Printf("using pinned bicep %s: %s", version, bicepPath)
+	return &Cli{
+		path:        bicepPath,
+		runner:      cli.runner,
+		console:     cli.console,
+		transporter: cli.transporter,
+	}, nil
+}
+
+// downloadPinnedBicep downloads a version of bicep, verifies it against the digest published for its release and
+// records its checksum. The binary is removed when it doesn't match the published digest.
+func downloadPinnedBicep(
+	ctx context.Context,
+	transporter policy.Transporter,
+	version semver.Version,
+	bicepPath string,
+	checksumPath string,
+) error {
+	releaseName, err := bicepReleaseName()
+	if err != nil {
+		return err
+	}
+
+	published, err := publishedDigest(ctx, transporter, version, releaseName)
+	if err != nil {
+		return err
+	}
+
+	if err := downloadBicep(ctx, transporter, version, bicepPath); err != nil {
+		return err
+	}
+
+	checksum, err := fileSha256(bicepPath)
+	if err != nil {
+		return fmt.Errorf("computing the checksum of %s: %w", bicepPath, err)
+	}
+
+	if !strings.EqualFold(checksum, published) {
+		_ = os.Remove(bicepPath)
+		return fmt.Errorf(
+			"the checksum of %s doesn't match the digest published with the release v%s of bicep", releaseName, version)
+	}
+
+	if err := os.WriteFile(checksumPath, []byte(checksum), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("saving the checksum of %s: %w", bicepPath, err)
+	}
+
+	return nil
+}
+
+// bicepReleasesUrl is the GitHub API of the releases of bicep, which publishes the SHA-256 digest of their assets
+const bicepReleasesUrl = "https://api.github.com/repos/Azure/bicep/releases/tags"
+
+// publishedDigest returns the SHA-256 digest GitHub publishes for a release of bicep, which doesn't come from the site
+// the release is downloaded from.
+func publishedDigest(
+	ctx context.Context,
+	transporter policy.Transporter,
+	version semver.Version,
+	releaseName string,
+) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v%s", bicepReleasesUrl, version), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := transporter.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("getting the release v%s of bicep: %w", version, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("getting the release v%s of bicep: http error %d", version, resp.StatusCode)
+	}
+
+	var release struct {
+		Assets []struct {
+			Name   string `json:"name"`
+			Digest string `json:"digest"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("reading the release v%s of bicep: %w", version, err)
+	}
+
+	for _, asset := range release.Assets {
+		if asset.Name != releaseName {
+			continue
+		}
+
+		digest, has := strings.CutPrefix(asset.Digest, "sha256:")
+		if !has {
+			return "", fmt.Errorf(
+				"no digest is published for %s of the release v%s of bicep, it can't be verified. Pin a newer "+
+					"version, or set AZD_BICEP_TOOL_PATH to a bicep CLI you installed", releaseName, version)
+		}
+
+		return digest, nil
+	}
+
+	return "", fmt.Errorf("the release v%s of bicep has no asset %s", version, releaseName)
+}
+
+// pinnedBicepPath returns the path of a pinned version of bicep ($AZD_CONFIG_DIR/bin/bicep-versions/<version>)
+func pinnedBicepPath(version semver.Version) (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := "bicep"
+	if runtime.GOOS == "windows" {
+		name = "bicep.exe"
+	}
+
+	return filepath.Join(configDir, "bin", "bicep-versions", version.String(), name), nil
+}
+
+// verifyChecksum verifies the bicep binary hasn't changed since it was downloaded
+func verifyChecksum(bicepPath string, checksumPath string) error {
+	expected, err := os.ReadFile(checksumPath)
+	if err != nil {
+		return fmt.Errorf("reading the checksum of %s: %w", bicepPath, err)
+	}
+
+	actual, err := fileSha256(bicepPath)
+	if err != nil {
+		return fmt.Errorf("computing the checksum of %s: %w", bicepPath, err)
+	}
+
+	if actual != strings.TrimSpace(string(expected)) {
+		return fmt.Errorf(
+			"the checksum of %s doesn't match the one recorded when it was downloaded. Delete the directory %s to "+
+				"download it again", bicepPath, filepath.Dir(bicepPath))
+	}
+
+	return nil
+}
+
+func fileSha256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// RequiredVersion is the minimum version of bicep declared by a template
+type RequiredVersion struct {
+	Version semver.Version
+	// The file declaring the version
+	File string
+}
+
+// requiresRegexp matches the minimum version of bicep required by a template, ex) // #requires bicep >= 0.30.3. The
+// statement is written in a comment, since bicep doesn't support it.
+var requiresRegexp = regexp.MustCompile(`^\s*(?://\s*)?#requires\s+bicep\s*(?:>=)?\s*v?(\d+\.\d+\.\d+)\s*$`)
+
+// FindRequiredVersion returns the highest version of bicep required by the '#requires bicep >= <version>' statements
+// of the templates in the directory, or nil when no template declares one.
+func FindRequiredVersion(dir string) (*RequiredVersion, error) {
+	var required *RequiredVersion
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || (filepath.Ext(path) != ".bicep" && filepath.Ext(path) != ".bicepparam") {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			matches := requiresRegexp.FindStringSubmatch(scanner.Text())
+			if matches == nil {
+				continue
+			}
+
+			version, err := semver.Parse(matches[1])
+			if err != nil {
+				return fmt.Errorf("parsing the bicep version required by %s: %w", path, err)
+			}
+
+			if required == nil || version.GT(required.Version) {
+				required = &RequiredVersion{Version: version, File: path}
+			}
+		}
+
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return required, nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithVersion(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	releaseName, err := bicepReleaseName()
+	require.NoError(t, err)
+
+	pinnedBicep := []byte("this is pinned bicep")
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(pinnedBicep))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	downloads := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "downloads.bicep.azure.com"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		downloads = append(downloads, request.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(pinnedBicep)),
+		}, nil
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Host == "api.github.com"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		release := map[string]any{
+			"tag_name": path.Base(request.URL.Path),
+			"assets": []map[string]any{
+				{"name": "bicep.nupkg", "digest": "sha256:0000"},
+				{"name": releaseName, "digest": digest},
+			},
+		}
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, release)
+	})
+
+	cli := &Cli{
+		path:        "bicep",
+		runner:      mockContext.CommandRunner,
+		console:     mockContext.Console,
+		transporter: mockContext.HttpClient,
+	}
+
+	version := semver.MustParse("0.30.3")
+	pinnedCli, err := cli.WithVersion(*mockContext.Context, version)
+	require.NoError(t, err)
+
+	bicepPath, err := pinnedBicepPath(version)
+	require.NoError(t, err)
+	require.Equal(t, bicepPath, pinnedCli.path)
+	require.Equal(t, "bicep", cli.path)
+	require.Contains(t, bicepPath, filepath.Join("bicep-versions", "0.30.3"))
+	require.Len(t, downloads, 1)
+	require.Contains(t, downloads[0], "/v0.30.3/")
+	require.FileExists(t, bicepPath+".sha256")
+
+	// The version already downloaded is reused
+	_, err = cli.WithVersion(*mockContext.Context, version)
+	require.NoError(t, err)
+	require.Len(t, downloads, 1)
+
+	// A binary changed since its download is rejected
+	require.NoError(t, os.WriteFile(bicepPath, []byte("this is changed bicep"), osutil.PermissionExecutableFile))
+	_, err = cli.WithVersion(*mockContext.Context, version)
+	require.ErrorContains(t, err, "doesn't match the one recorded when it was downloaded")
+
+	t.Run("DigestMismatch", func(t *testing.T) {
+		digest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("this is published bicep")))
+
+		version := semver.MustParse("0.31.92")
+		_, err := cli.WithVersion(*mockContext.Context, version)
+		require.ErrorContains(t, err, "doesn't match the digest published with the release v0.31.92 of bicep")
+
+		bicepPath, err := pinnedBicepPath(version)
+		require.NoError(t, err)
+		require.NoFileExists(t, bicepPath)
+		require.NoFileExists(t, bicepPath+".sha256")
+	})
+
+	t.Run("NoDigest", func(t *testing.T) {
+		digest = ""
+
+		_, err := cli.WithVersion(*mockContext.Context, semver.MustParse("0.28.1"))
+		require.ErrorContains(t, err, "it can't be verified")
+	})
+}
+
+func TestWithVersion_External(t *testing.T) {
+	cli := &Cli{path: "/opt/bicep", external: true}
+	pinnedCli, err := cli.WithVersion(context.Background(), semver.MustParse("0.30.3"))
+	require.NoError(t, err)
+	require.Equal(t, "/opt/bicep", pinnedCli.path)
+}
+
+func TestFindRequiredVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "main.bicep"),
+		[]byte("// #requires bicep >= 0.28.1\ntargetScope = 'subscription'\n"),
+		osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "modules", "app.bicep"),
+		[]byte("// #requires bicep >=0.31.92\nparam name string\n"),
+		osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "main.json"),
+		[]byte("// #requires bicep >= 9.0.0"),
+		osutil.PermissionFile))
+
+	required, err := FindRequiredVersion(dir)
+	require.NoError(t, err)
+	require.Equal(t, semver.MustParse("0.31.92"), required.Version)
+	require.Equal(t, filepath.Join(dir, "modules", "app.bicep"), required.File)
+
+	required, err = FindRequiredVersion(t.TempDir())
+	require.NoError(t, err)
+	require.Nil(t, required)
+}
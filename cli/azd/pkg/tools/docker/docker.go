@@ -5,6 +5,7 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -128,6 +129,85 @@ func (d *Cli) Build(
 	return strings.TrimSpace(string(imgId)), nil
 }
 
+// Runs a Docker buildx build of a given Dockerfile for each of the platforms, and pushes the resulting manifest list
+// to the registry of [tagName], writing the output of docker buildx to [buildProgress] when it is not nil. The platforms
+// are built concurrently by buildx. If the build is successful, the function returns the digest of the manifest list.
+//
+// Multi-platform images can't be loaded in the default image store of docker, the registry must be logged in to.
+func (d *Cli) BuildMultiPlatform(
+	ctx context.Context,
+	cwd string,
+	dockerFilePath string,
+	platforms []string,
+	target string,
+	buildContext string,
+	tagName string,
+	buildArgs []string,
+	buildSecrets []string,
+	buildEnv []string,
+	buildProgress io.Writer,
+) (string, error) {
+	tmpFolder, err := os.MkdirTemp(os.TempDir(), "azd-docker-buildx")
+	defer func() {
+		_ = os.RemoveAll(tmpFolder)
+	}()
+
+	if err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+	metadataFile := filepath.Join(tmpFolder, "metadata.json")
+
+	args := []string{
+		"buildx", "build",
+		"-f", dockerFilePath,
+		"--platform", strings.Join(platforms, ","),
+	}
+
+	if target != "" {
+		args = append(args, "--target", target)
+	}
+
+	args = append(args, "-t", tagName)
+
+	for _, arg := range buildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+
+	for _, arg := range buildSecrets {
+		args = append(args, "--secret", arg)
+	}
+
+	args = append(args, "--push", buildContext)
+
+	// the metadata file holds the digest of the pushed manifest list
+	args = append(args, "--metadata-file", metadataFile)
+
+	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd).WithEnv(buildEnv)
+
+	if buildProgress != nil {
+		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
+	}
+
+	_, err = d.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+
+	metadata, err := os.ReadFile(metadataFile)
+	if err != nil {
+		return "", fmt.Errorf("building multi-platform image: %w", err)
+	}
+
+	var buildMetadata struct {
+		Digest string `json:"containerimage.digest"`
+	}
+	if err := json.Unmarshal(metadata, &buildMetadata); err != nil {
+		return "", fmt.Errorf("reading build metadata: %w", err)
+	}
+
+	return buildMetadata.Digest, nil
+}
+
 func (d *Cli) Tag(ctx context.Context, cwd string, imageName string, tag string) error {
 	_, err := d.executeCommand(ctx, cwd, "tag", imageName, tag)
 	if err != nil {
@@ -146,6 +226,21 @@ func (d *Cli) Push(ctx context.Context, cwd string, tag string) error {
 	return nil
 }
 
+// PushWithConfig pushes the image using the docker client configuration in configDir, instead of ~/.docker, to
+// authenticate with the registry.
+func (d *Cli) PushWithConfig(ctx context.Context, cwd string, tag string, configDir string) error {
+	runArgs := exec.NewRunArgs("docker", "push", tag).
+		WithCwd(cwd).
+		WithEnv([]string{"DOCKER_CONFIG=" + configDir})
+
+	_, err := d.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("pushing image: %w", err)
+	}
+
+	return nil
+}
+
 func (d *Cli) Pull(ctx context.Context, imageName string) error {
 	_, err := d.executeCommand(ctx, "", "pull", imageName)
 	if err != nil {
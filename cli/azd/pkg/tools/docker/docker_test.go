@@ -295,6 +295,53 @@ func Test_DockerBuildArgsMultiple(t *testing.T) {
 	require.Equal(t, mockedDockerImgId, result)
 }
 
+func Test_DockerBuildMultiPlatform(t *testing.T) {
+	ran := false
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewCli(mockContext.CommandRunner)
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+
+		// "--metadata-file" and path args are expected always at the end
+		argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+
+		require.Equal(t, []string{
+			"buildx", "build",
+			"-f", "./Dockerfile",
+			"--platform", "linux/amd64,linux/arm64",
+			"-t", "contoso.azurecr.io/app/api:azd-deploy-0",
+			"--build-arg", "foo=bar",
+			"--push",
+			".",
+		}, argsNoFile)
+
+		err := os.WriteFile(value, []byte(`{"containerimage.digest": "sha256:0123456789abcdef"}`), 0600)
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	digest, err := docker.BuildMultiPlatform(
+		context.Background(),
+		".",
+		"./Dockerfile",
+		[]string{"linux/amd64", "linux/arm64"},
+		"",
+		".",
+		"contoso.azurecr.io/app/api:azd-deploy-0",
+		[]string{"foo=bar"},
+		nil,
+		nil,
+		nil,
+	)
+
+	require.True(t, ran)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:0123456789abcdef", digest)
+}
+
 func Test_DockerTag(t *testing.T) {
 	cwd := "."
 	imageName := "image-name"
@@ -407,6 +454,29 @@ func Test_DockerPush(t *testing.T) {
 		require.Nil(t, err)
 	})
 
+	t.Run("WithConfig", func(t *testing.T) {
+		ran := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewCli(mockContext.CommandRunner)
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker push")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+
+			require.Equal(t, []string{"push", tag}, args.Args)
+			require.Equal(t, []string{"DOCKER_CONFIG=/tmp/docker-config"}, args.Env)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		err := docker.PushWithConfig(context.Background(), cwd, tag, "/tmp/docker-config")
+
+		require.True(t, ran)
+		require.NoError(t, err)
+	})
+
 	t.Run("WithError", func(t *testing.T) {
 		ran := false
 		stdErr := "Error pushing DockerFile"
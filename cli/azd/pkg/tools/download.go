@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tools
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// MirrorConfigPath is the user config path of the base URL of an internal mirror of the releases of the tools azd
+	// downloads, ex) https://artifacts.contoso.com/azd-tools
+	MirrorConfigPath = "tools.mirror"
+	// BundleConfigPath is the user config path of the directory of an offline bundle of the releases of the tools azd
+	// downloads
+	BundleConfigPath = "tools.bundle"
+
+	// The extension of the file next to each release of a mirror or a bundle, holding the SHA-256 checksum of the
+	// release in the format of sha256sum. The checksum comes from the same source as the release, so it only detects
+	// a corrupted or incomplete copy, not a release replaced with its checksum.
+	checksumExtension = ".sha256"
+)
+
+// downloadSources are where the releases of the tools are downloaded from instead of their release sites. A release is
+// found at the same path under the mirror or the bundle as its original URL with the host, ex)
+// <mirror>/downloads.bicep.azure.com/v0.36.1/bicep-linux-x64.
+type downloadSources struct {
+	mirror string
+	bundle string
+}
+
+var currentDownloadSources atomic.Pointer[downloadSources]
+
+// SetDownloadSources sets the mirror URL and the offline bundle directory the releases of the tools are downloaded from.
+// Either can be empty. The bundle is used first, and the release sites are never used when either is set.
+func SetDownloadSources(mirror string, bundle string) error {
+	if mirror != "" {
+		if err := ValidateMirror(mirror); err != nil {
+			return err
+		}
+	}
+
+	if bundle != "" {
+		if err := ValidateBundle(bundle); err != nil {
+			return err
+		}
+	}
+
+	currentDownloadSources.Store(&downloadSources{
+		mirror: strings.TrimSuffix(mirror, "/"),
+		bundle: bundle,
+	})
+	return nil
+}
+
+// ValidateMirror returns an error when the mirror isn't an http or https URL.
+func ValidateMirror(mirror string) error {
+	mirrorUrl, err := url.Parse(mirror)
+	if err != nil || (mirrorUrl.Scheme != "https" && mirrorUrl.Scheme != "http") || mirrorUrl.Host == "" {
+		return fmt.Errorf("invalid mirror '%s', expected an http or https URL", mirror)
+	}
+
+	return nil
+}
+
+// ValidateBundle returns an error when the bundle isn't an absolute path.
+func ValidateBundle(bundle string) error {
+	if !filepath.IsAbs(bundle) {
+		return fmt.Errorf("invalid bundle '%s', expected an absolute path", bundle)
+	}
+
+	return nil
+}
+
+// NewDownloadTransporter returns the transporter used to download the releases of the tools, which downloads them from
+// the mirror or the bundle set with SetDownloadSources, checking their integrity with the checksum next to them, and
+// otherwise uses the given transporter to download them from their release sites.
+func NewDownloadTransporter(transporter policy.Transporter) policy.Transporter {
+	return &downloadTransporter{transporter: transporter}
+}
+
+type downloadTransporter struct {
+	transporter policy.Transporter
+}
+
+func (t *downloadTransporter) Do(req *http.Request) (*http.Response, error) {
+	sources := currentDownloadSources.Load()
+	if sources == nil || (sources.mirror == "" && sources.bundle == "") {
+		return t.transporter.Do(req)
+	}
+
+	releasePath := path.Join(req.URL.Host, req.URL.Path)
+
+	if sources.bundle != "" {
+		bundlePath := filepath.Join(sources.bundle, filepath.FromSlash(releasePath))
+		contents, err := os.ReadFile(bundlePath)
+		if err == nil {
+			log.Printf("using the release %s of the offline bundle", bundlePath)
+			checksum, err := os.ReadFile(bundlePath + checksumExtension)
+			if err != nil {
+				return nil, fmt.Errorf("reading the checksum of %s: %w", bundlePath, err)
+			}
+
+			return releaseResponse(req, bundlePath, contents, checksum)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading %s: %w", bundlePath, err)
+		}
+
+		if sources.mirror == "" {
+			return nil, fmt.Errorf(
+				"%s isn't in the offline bundle %s, add it with its checksum in %s%s",
+				req.URL, sources.bundle, bundlePath, checksumExtension)
+		}
+	}
+
+	mirrorUrl := sources.mirror + "/" + releasePath
+	log.Printf("downloading %s from the mirror %s", req.URL, mirrorUrl)
+
+	contents, err := t.get(req, mirrorUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := t.get(req, mirrorUrl+checksumExtension)
+	if err != nil {
+		return nil, fmt.Errorf("downloading the checksum of %s: %w", mirrorUrl, err)
+	}
+
+	return releaseResponse(req, mirrorUrl, contents, checksum)
+}
+
+// get downloads the URL with the context and the headers of the original request.
+func (t *downloadTransporter) get(req *http.Request, rawUrl string) ([]byte, error) {
+	mirrorReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	mirrorReq.Header = req.Header.Clone()
+
+	resp, err := t.transporter.Do(mirrorReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: http error %d", rawUrl, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// releaseResponse checks the integrity of the release with its checksum and returns it as the response of the original
+// request.
+func releaseResponse(req *http.Request, source string, contents []byte, checksumFile []byte) (*http.Response, error) {
+	fields := strings.Fields(string(checksumFile))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the checksum file of %s is empty", source)
+	}
+
+	sum := sha256.Sum256(contents)
+	if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+		return nil, fmt.Errorf(
+			"the checksum of %s doesn't match the checksum %s%s, the copy of the release may be corrupted or incomplete",
+			source, source, checksumExtension)
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(contents)),
+		ContentLength: int64(len(contents)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/require"
+)
+
+type transporterFunc func(req *http.Request) (*http.Response, error)
+
+func (f transporterFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func checksumOf(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:]) + "  bicep-linux-x64\n"
+}
+
+func TestDownloadTransporter(t *testing.T) {
+	release := []byte("bicep release")
+	releaseUrl := "https://downloads.bicep.azure.com/v0.36.1/bicep-linux-x64"
+
+	download := func(t *testing.T, transporter policy.Transporter) ([]byte, error) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, releaseUrl, nil)
+		require.NoError(t, err)
+
+		resp, err := NewDownloadTransporter(transporter).Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return io.ReadAll(resp.Body)
+	}
+
+	requested := []string{}
+	server := transporterFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+
+		body := release
+		switch req.URL.String() {
+		case releaseUrl, "https://mirror.contoso.com/azd/downloads.bicep.azure.com/v0.36.1/bicep-linux-x64":
+		case "https://mirror.contoso.com/azd/downloads.bicep.azure.com/v0.36.1/bicep-linux-x64.sha256":
+			body = []byte(checksumOf(release))
+		default:
+			return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody}, nil
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}, nil
+	})
+
+	t.Cleanup(func() { currentDownloadSources.Store(nil) })
+
+	t.Run("ReleaseSite", func(t *testing.T) {
+		currentDownloadSources.Store(nil)
+		requested = nil
+
+		_, err := download(t, server)
+		require.NoError(t, err)
+		require.Equal(t, []string{releaseUrl}, requested)
+	})
+
+	t.Run("Mirror", func(t *testing.T) {
+		require.NoError(t, SetDownloadSources("https://mirror.contoso.com/azd/", ""))
+		requested = nil
+
+		contents, err := download(t, server)
+		require.NoError(t, err)
+		require.Equal(t, release, contents)
+		require.Len(t, requested, 2)
+	})
+
+	t.Run("MirrorChecksumMismatch", func(t *testing.T) {
+		require.NoError(t, SetDownloadSources("https://mirror.contoso.com/azd", ""))
+		tampered := transporterFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := server.Do(req)
+			if err == nil && filepath.Ext(req.URL.Path) != ".sha256" {
+				resp.Body = io.NopCloser(bytes.NewReader([]byte("tampered release")))
+			}
+			return resp, err
+		})
+
+		_, err := download(t, tampered)
+		require.ErrorContains(t, err, "doesn't match the checksum")
+	})
+
+	t.Run("Bundle", func(t *testing.T) {
+		bundle := t.TempDir()
+		releaseDir := filepath.Join(bundle, "downloads.bicep.azure.com", "v0.36.1")
+		require.NoError(t, os.MkdirAll(releaseDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(releaseDir, "bicep-linux-x64"), release, 0600))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(releaseDir, "bicep-linux-x64.sha256"), []byte(checksumOf(release)), 0600))
+
+		require.NoError(t, SetDownloadSources("", bundle))
+		requested = nil
+
+		contents, err := download(t, server)
+		require.NoError(t, err)
+		require.Equal(t, release, contents)
+		require.Empty(t, requested)
+	})
+
+	t.Run("NotInBundle", func(t *testing.T) {
+		require.NoError(t, SetDownloadSources("", t.TempDir()))
+
+		_, err := download(t, server)
+		require.ErrorContains(t, err, "isn't in the offline bundle")
+	})
+
+	t.Run("NotInBundleFromMirror", func(t *testing.T) {
+		require.NoError(t, SetDownloadSources("https://mirror.contoso.com/azd", t.TempDir()))
+
+		contents, err := download(t, server)
+		require.NoError(t, err)
+		require.Equal(t, release, contents)
+	})
+}
+
+func TestSetDownloadSources(t *testing.T) {
+	t.Cleanup(func() { currentDownloadSources.Store(nil) })
+
+	require.Error(t, SetDownloadSources("mirror.contoso.com", ""))
+	require.Error(t, SetDownloadSources("ftp://mirror.contoso.com", ""))
+	require.Error(t, SetDownloadSources("", filepath.Join("relative", "bundle")))
+	require.NoError(t, SetDownloadSources("https://mirror.contoso.com", ""))
+}
@@ -33,6 +33,25 @@ func (m *missingToolErrors) Error() string {
 	return buf.String()
 }
 
+// Unwrap returns the errors of the missing tools
+func (m *missingToolErrors) Unwrap() []error {
+	return m.errs
+}
+
+// toolNotInstalledError is the error of a tool which isn't installed, wrapping exec.ErrNotFound
+type toolNotInstalledError struct {
+	name       string
+	installUrl string
+}
+
+func (e *toolNotInstalledError) Error() string {
+	return fmt.Sprintf("%s is not installed, see %s to install", e.name, e.installUrl)
+}
+
+func (e *toolNotInstalledError) Unwrap() error {
+	return osexec.ErrNotFound
+}
+
 // EnsureInstalled checks that all tools are installed, returning an
 // error if one or more tools are not.
 func EnsureInstalled(ctx context.Context, tools ...ExternalTool) error {
@@ -60,8 +79,7 @@ func EnsureInstalled(ctx context.Context, tools ...ExternalTool) error {
 				errorsEncountered[errorMsg] = struct{}{}
 			}
 		} else if errors.Is(err, osexec.ErrNotFound) {
-			allErrors = append(
-				allErrors, fmt.Errorf("%s is not installed, see %s to install", tool.Name(), tool.InstallUrl()))
+			allErrors = append(allErrors, &toolNotInstalledError{name: tool.Name(), installUrl: tool.InstallUrl()})
 
 		} else if err != nil {
 			errorMsg := err.Error()
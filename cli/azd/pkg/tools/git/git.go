@@ -123,6 +123,19 @@ func (cli *Cli) GetCurrentBranch(ctx context.Context, repositoryPath string) (st
 	return strings.TrimSpace(res.Stdout), nil
 }
 
+// GetHeadCommit returns the sha of the commit checked out in the repository
+func (cli *Cli) GetHeadCommit(ctx context.Context, repositoryPath string) (string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "HEAD")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return "", ErrNotRepository
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
 func (cli *Cli) GetRepoRoot(ctx context.Context, repositoryPath string) (string, error) {
 	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "--show-toplevel")
 	res, err := cli.commandRunner.Run(ctx, runArgs)
@@ -225,6 +238,17 @@ func (cli *Cli) ListStagedFiles(ctx context.Context, repositoryPath string) (str
 	return res.Stdout, nil
 }
 
+// HasStagedChanges checks whether the index of the repository contains changes that haven't been committed
+func (cli *Cli) HasStagedChanges(ctx context.Context, repositoryPath string) (bool, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "diff", "--cached", "--name-only")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return false, fmt.Errorf("failed to list staged changes: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout) != "", nil
+}
+
 func (cli *Cli) AddFileExecPermission(ctx context.Context, repositoryPath string, file string) error {
 	runArgs := newRunArgs("-C", repositoryPath, "update-index", "--add", "--chmod=+x", file)
 	_, err := cli.commandRunner.Run(ctx, runArgs)
@@ -249,6 +273,36 @@ func (cli *Cli) IsUntrackedFile(ctx context.Context, repositoryPath string, file
 	return false, nil
 }
 
+// IsIgnored returns true when the path is ignored by the .gitignore files of the repository. Paths of tracked files are
+// not ignored, even when they match a pattern.
+func (cli *Cli) IsIgnored(ctx context.Context, repositoryPath string, path string) (bool, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "check-ignore", "-q", path)
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return false, ErrNotRepository
+	} else if err != nil && res.ExitCode == 1 {
+		// check-ignore exits with 1 when the path isn't ignored
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to check ignored path: %w", err)
+	}
+
+	return true, nil
+}
+
+// IsTrackedFile returns true when the file is tracked by the repository, ex) committed before being ignored
+func (cli *Cli) IsTrackedFile(ctx context.Context, repositoryPath string, filePath string) (bool, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "ls-files", "--", filePath)
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return false, ErrNotRepository
+	} else if err != nil {
+		return false, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout) != "", nil
+}
+
 // SetGitHubAuthForRepo creates git config for the repositoryPath like
 //
 // [credential "https://github.com"]  (when credential is equal to "https://github.com")
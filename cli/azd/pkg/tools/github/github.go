@@ -32,7 +32,8 @@ import (
 var _ tools.ExternalTool = (*Cli)(nil)
 
 func NewGitHubCli(ctx context.Context, console input.Console, commandRunner exec.CommandRunner) (*Cli, error) {
-	return newGitHubCliImplementation(ctx, console, commandRunner, http.DefaultClient, downloadGh, extractGhCli)
+	return newGitHubCliImplementation(
+		ctx, console, commandRunner, tools.NewDownloadTransporter(http.DefaultClient), downloadGh, extractGhCli)
 }
 
 // Version is the minimum version of GitHub cli that we require (and the one we fetch when we fetch gh on
@@ -200,6 +201,10 @@ func (cli *Cli) Login(ctx context.Context, hostname string) error {
 // ApiCallOptions represent the options for the ApiCall method.
 type ApiCallOptions struct {
 	Headers []string
+	// The HTTP method of the request. Defaults to GET.
+	Method string
+	// The JSON body of the request, if any.
+	Body string
 }
 
 // ApiCall uses gh cli to call https://api.<hostname>/<path>.
@@ -209,8 +214,17 @@ func (cli *Cli) ApiCall(ctx context.Context, hostname, path string, options ApiC
 	for _, header := range options.Headers {
 		args = append(args, "-H", header)
 	}
+	if options.Method != "" {
+		args = append(args, "--method", options.Method)
+	}
+	if options.Body != "" {
+		args = append(args, "--input", "-")
+	}
 	// application/vnd.github.raw makes the API return the raw content of the file
 	runArgs := cli.newRunArgs(args...)
+	if options.Body != "" {
+		runArgs = runArgs.WithStdIn(strings.NewReader(options.Body))
+	}
 	result, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return "", fmt.Errorf("failed running gh api: %s: %w", url, err)
@@ -284,6 +298,30 @@ func (cli *Cli) SetVariable(ctx context.Context, repoSlug string, name string, v
 	return nil
 }
 
+// SetEnvironmentSecret sets a secret of the deployment environment [envName] of the repository.
+func (cli *Cli) SetEnvironmentSecret(
+	ctx context.Context, repoSlug string, envName string, name string, value string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "set", name, "--env", envName).
+		WithStdIn(strings.NewReader(value))
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh secret set: %w", err)
+	}
+	return nil
+}
+
+// SetEnvironmentVariable sets a variable of the deployment environment [envName] of the repository.
+func (cli *Cli) SetEnvironmentVariable(
+	ctx context.Context, repoSlug string, envName string, name string, value string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "variable", "set", name, "--env", envName).
+		WithStdIn(strings.NewReader(value))
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh variable set: %w", err)
+	}
+	return nil
+}
+
 func (cli *Cli) DeleteSecret(ctx context.Context, repoSlug string, name string) error {
 	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "delete", name)
 	_, err := cli.run(ctx, runArgs)
@@ -0,0 +1,175 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gradle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	osexec "os/exec"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+type Cli struct {
+	commandRunner   exec.CommandRunner
+	projectPath     string
+	rootProjectPath string
+
+	// Lazily initialized. Access through gradleCmd.
+	gradleCmdStr  string
+	gradleCmdOnce sync.Once
+	gradleCmdErr  error
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *Cli) Name() string {
+	return "Gradle"
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://gradle.org/install"
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	gradleCmd, err := cli.gradleCmd()
+	if err != nil {
+		return err
+	}
+
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs(gradleCmd, "--version"))
+	if err == nil {
+		if parts := gradleVersionRegexp.FindStringSubmatch(res.Stdout); len(parts) == 2 {
+			log.Printf("gradle version: %s", parts[1])
+		}
+	}
+
+	return nil
+}
+
+// SetPath sets the paths searched for the Gradle wrapper, from the project path up to the root project path.
+func (cli *Cli) SetPath(projectPath string, rootProjectPath string) {
+	cli.projectPath = projectPath
+	cli.rootProjectPath = rootProjectPath
+}
+
+// gradleVersionRegexp captures the version number of gradle from the output of "gradle --version", ex) Gradle 8.5
+var gradleVersionRegexp = regexp.MustCompile(`Gradle (\S+)`)
+
+// Run runs the tasks, ex) :api:assemble, in the project directory. Tests are excluded with '-x test'.
+func (cli *Cli) Run(ctx context.Context, projectPath string, tasks ...string) error {
+	gradleCmd, err := cli.gradleCmd()
+	if err != nil {
+		return err
+	}
+
+	args := append(slices.Clone(tasks), "-x", "test", "--console=plain")
+	_, err = cli.commandRunner.Run(ctx, exec.NewRunArgs(gradleCmd, args...).WithCwd(projectPath))
+	if err != nil {
+		return fmt.Errorf("gradle %s on project '%s' failed: %w", strings.Join(tasks, " "), projectPath, err)
+	}
+
+	return nil
+}
+
+// ProjectDir returns the directory of the subproject, ex) services/api for :services:api, assuming the default
+// layout where the directory of a subproject matches its path.
+func ProjectDir(rootDir string, subproject string) string {
+	parts := strings.Split(strings.Trim(subproject, ":"), ":")
+	return filepath.Join(append([]string{rootDir}, parts...)...)
+}
+
+// Task returns the task of the subproject, ex) :api:assemble
+func Task(subproject string, task string) string {
+	subproject = strings.Trim(subproject, ":")
+	if subproject == "" {
+		return task
+	}
+
+	return fmt.Sprintf(":%s:%s", subproject, task)
+}
+
+func (cli *Cli) gradleCmd() (string, error) {
+	cli.gradleCmdOnce.Do(func() {
+		cli.gradleCmdStr, cli.gradleCmdErr = getGradlePath(cli.projectPath, cli.rootProjectPath)
+	})
+
+	return cli.gradleCmdStr, cli.gradleCmdErr
+}
+
+func getGradlePath(projectPath string, rootProjectPath string) (string, error) {
+	gradlew, err := getGradleWrapperPath(projectPath, rootProjectPath)
+	if gradlew != "" {
+		return gradlew, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed finding gradlew in repository path: %w", err)
+	}
+
+	gradle, err := osexec.LookPath("gradle")
+	if err == nil {
+		return gradle, nil
+	}
+
+	if !errors.Is(err, osexec.ErrNotFound) {
+		return "", fmt.Errorf("failed looking up gradle in PATH: %w", err)
+	}
+
+	return "", errors.New(
+		"gradle could not be found. Install either Gradle or the Gradle Wrapper by " +
+			"visiting https://gradle.org/install or https://docs.gradle.org/current/userguide/gradle_wrapper.html",
+	)
+}
+
+// getGradleWrapperPath finds the path to gradlew in the project directory, up to the root project directory.
+//
+// An error is returned if an unexpected error occurred while finding. If gradlew is not found, an empty string is
+// returned with no error.
+func getGradleWrapperPath(projectPath string, rootProjectPath string) (string, error) {
+	searchDir, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.Abs(rootProjectPath)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		gradlew, err := osexec.LookPath(filepath.Join(searchDir, "gradlew"))
+		if err == nil {
+			log.Printf("found gradlew as: %s\n", gradlew)
+			return gradlew, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, osexec.ErrNotFound) {
+			return "", err
+		}
+
+		searchDir = filepath.Dir(searchDir)
+
+		// Past root, terminate search and return not found
+		if len(searchDir) < len(root) {
+			return "", nil
+		}
+	}
+}
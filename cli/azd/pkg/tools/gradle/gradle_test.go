@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package gradle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run(t *testing.T) {
+	rootPath := t.TempDir()
+	projectPath := filepath.Join(rootPath, "src", "api")
+	require.NoError(t, os.MkdirAll(projectPath, 0755))
+
+	gradlew := "gradlew"
+	if runtime.GOOS == "windows" {
+		gradlew = "gradlew.bat"
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(rootPath, gradlew), nil, 0755))
+	ostest.Unsetenv(t, "PATH")
+
+	var runArgs exec.RunArgs
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "assemble")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetPath(projectPath, rootPath)
+	require.NoError(t, cli.Run(*mockContext.Context, projectPath, Task("api", "assemble")))
+
+	require.Equal(t, filepath.Join(rootPath, gradlew), runArgs.Cmd)
+	require.Equal(t, projectPath, runArgs.Cwd)
+	require.Equal(t, []string{":api:assemble", "-x", "test", "--console=plain"}, runArgs.Args)
+}
+
+func Test_Run_NotFound(t *testing.T) {
+	rootPath := t.TempDir()
+	ostest.Unsetenv(t, "PATH")
+
+	cli := NewCli(mocks.NewMockContext(context.Background()).CommandRunner)
+	cli.SetPath(rootPath, rootPath)
+	require.ErrorContains(t, cli.Run(context.Background(), rootPath, "assemble"), "gradle could not be found")
+}
+
+func Test_ProjectDir(t *testing.T) {
+	require.Equal(t, filepath.Join("root", "services", "api"), ProjectDir("root", ":services:api"))
+	require.Equal(t, filepath.Join("root", "api"), ProjectDir("root", "api"))
+	require.Equal(t, ":services:api:assemble", Task(":services:api", "assemble"))
+	require.Equal(t, "assemble", Task("", "assemble"))
+}
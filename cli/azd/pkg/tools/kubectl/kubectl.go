@@ -14,6 +14,7 @@ import (
 	"text/template"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
@@ -328,6 +329,67 @@ func (cli *Cli) applyTemplates(ctx context.Context, directoryPath string, flags
 	return nil
 }
 
+// RenderManifests recursively copies the k8s manifests of the specified directory to the output directory, rendering
+// *.tmpl files as templates to support environment injection, the same way as Apply. The rendered templates are written
+// without the .tmpl suffix.
+func (cli *Cli) RenderManifests(directoryPath string, outputPath string) error {
+	entries, err := os.ReadDir(directoryPath)
+	if err != nil {
+		return fmt.Errorf("failed reading files in path, '%s', %w", directoryPath, err)
+	}
+
+	if err := os.MkdirAll(outputPath, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("failed creating directory '%s', %w", outputPath, err)
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(directoryPath, entry.Name())
+
+		if entry.IsDir() {
+			if err := cli.RenderManifests(entryPath, filepath.Join(outputPath, entry.Name())); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" { // Only include yaml files
+			continue
+		}
+
+		fileNameWithoutExtension := strings.TrimSuffix(entry.Name(), ext)
+		var contents []byte
+
+		if templateName, isTemplateFile := strings.CutSuffix(fileNameWithoutExtension, ".tmpl"); isTemplateFile {
+			k8sTemplate, err := template.ParseFiles(entryPath)
+			if err != nil {
+				return fmt.Errorf("failed parsing template file '%s', %w", entryPath, err)
+			}
+
+			builder := strings.Builder{}
+			if err := k8sTemplate.Execute(&builder, templateRoot{Env: cli.env}); err != nil {
+				return fmt.Errorf("failed executing template file '%s', %w", entryPath, err)
+			}
+
+			contents = []byte(builder.String())
+			fileNameWithoutExtension = templateName
+		} else {
+			contents, err = os.ReadFile(entryPath)
+			if err != nil {
+				return fmt.Errorf("failed reading file '%s', %w", entryPath, err)
+			}
+		}
+
+		renderedPath := filepath.Join(outputPath, fileNameWithoutExtension+ext)
+		if err := os.WriteFile(renderedPath, contents, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("failed writing file '%s', %w", renderedPath, err)
+		}
+	}
+
+	return nil
+}
+
 func (cli *Cli) executeCommandWithArgs(
 	ctx context.Context,
 	args exec.RunArgs,
@@ -256,3 +256,29 @@ func Test_Apply_Template(t *testing.T) {
 		require.Contains(t, yaml, "EXAMPLE_CLIENT_ID")
 	})
 }
+
+func Test_RenderManifests(t *testing.T) {
+	cli := NewCli(mocks.NewMockContext(context.Background()).CommandRunner)
+	cli.SetEnv(map[string]string{
+		"SERVICE_API_IMAGE_NAME":       "test.azureacr.io/repo/service:latest",
+		"AZURE_AKS_IDENTITY_CLIENT_ID": "EXAMPLE_CLIENT_ID",
+	})
+
+	outputPath := filepath.Join(t.TempDir(), "manifests")
+	err := cli.RenderManifests("../../../test/testdata/k8s/apply", outputPath)
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(filepath.Join(outputPath, "raw", "config-map.yaml"))
+	require.NoError(t, err)
+	expected, err := os.ReadFile("../../../test/testdata/k8s/apply/raw/config-map.yaml")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(raw))
+
+	rendered, err := os.ReadFile(filepath.Join(outputPath, "templates", "deployment.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(rendered), "test.azureacr.io/repo/service:latest")
+	require.Contains(t, string(rendered), "EXAMPLE_CLIENT_ID")
+
+	_, err = os.Stat(filepath.Join(outputPath, "templates", "deployment.tmpl.yaml"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -11,10 +11,11 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeDeployment ResourceType = "deployment"
-	ResourceTypeIngress    ResourceType = "ing"
-	ResourceTypeService    ResourceType = "svc"
-	KubeConfigEnvVarName   string       = "KUBECONFIG"
+	ResourceTypeDeployment     ResourceType = "deployment"
+	ResourceTypeIngress        ResourceType = "ing"
+	ResourceTypeService        ResourceType = "svc"
+	ResourceTypeServiceAccount ResourceType = "serviceaccount"
+	KubeConfigEnvVarName       string       = "KUBECONFIG"
 )
 
 type Resource struct {
@@ -40,6 +41,16 @@ type ResourceMetadata struct {
 	Annotations map[string]any
 }
 
+type ServiceAccount struct {
+	Resource
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets" yaml:"imagePullSecrets"`
+}
+
+// LocalObjectReference references a resource by name in the same namespace, ex) an image pull secret
+type LocalObjectReference struct {
+	Name string `json:"name" yaml:"name"`
+}
+
 type Deployment ResourceWithSpec[DeploymentSpec, DeploymentStatus]
 
 type DeploymentSpec struct {
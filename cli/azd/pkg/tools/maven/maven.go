@@ -172,13 +172,14 @@ func (cli *Cli) extractVersion(ctx context.Context) (string, error) {
 	return parts[1], nil
 }
 
-func (cli *Cli) Compile(ctx context.Context, projectPath string) error {
+// Compile compiles the project. When modules are given, only the modules and the modules they depend on are compiled.
+func (cli *Cli) Compile(ctx context.Context, projectPath string, modules ...string) error {
 	mvnCmd, err := cli.mvnCmd()
 	if err != nil {
 		return err
 	}
 
-	runArgs := exec.NewRunArgs(mvnCmd, "compile").WithCwd(projectPath)
+	runArgs := exec.NewRunArgs(mvnCmd, withModules([]string{"compile"}, modules)...).WithCwd(projectPath)
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn compile on project '%s' failed: %w", projectPath, err)
@@ -187,14 +188,15 @@ func (cli *Cli) Compile(ctx context.Context, projectPath string) error {
 	return nil
 }
 
-func (cli *Cli) Package(ctx context.Context, projectPath string) error {
+// Package packages the project. When modules are given, only the modules and the modules they depend on are packaged.
+func (cli *Cli) Package(ctx context.Context, projectPath string, modules ...string) error {
 	mvnCmd, err := cli.mvnCmd()
 	if err != nil {
 		return err
 	}
 
 	// Maven's package phase includes tests by default. Skip it explicitly.
-	runArgs := exec.NewRunArgs(mvnCmd, "package", "-DskipTests").WithCwd(projectPath)
+	runArgs := exec.NewRunArgs(mvnCmd, withModules([]string{"package", "-DskipTests"}, modules)...).WithCwd(projectPath)
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn package on project '%s' failed: %w", projectPath, err)
@@ -203,12 +205,13 @@ func (cli *Cli) Package(ctx context.Context, projectPath string) error {
 	return nil
 }
 
-func (cli *Cli) ResolveDependencies(ctx context.Context, projectPath string) error {
+// ResolveDependencies resolves the dependencies of the project, or of the given modules and the modules they depend on.
+func (cli *Cli) ResolveDependencies(ctx context.Context, projectPath string, modules ...string) error {
 	mvnCmd, err := cli.mvnCmd()
 	if err != nil {
 		return err
 	}
-	runArgs := exec.NewRunArgs(mvnCmd, "dependency:resolve").WithCwd(projectPath)
+	runArgs := exec.NewRunArgs(mvnCmd, withModules([]string{"dependency:resolve"}, modules)...).WithCwd(projectPath)
 	_, err = cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn dependency:resolve on project '%s' failed: %w", projectPath, err)
@@ -217,6 +220,16 @@ func (cli *Cli) ResolveDependencies(ctx context.Context, projectPath string) err
 	return nil
 }
 
+// withModules restricts the build to the modules of a multi-module project, and the modules they depend on.
+// Link to "-pl" and "-am" related doc: https://maven.apache.org/ref/3.1.0/maven-embedder/cli.html
+func withModules(args []string, modules []string) []string {
+	if len(modules) == 0 {
+		return args
+	}
+
+	return append(args, "-pl", strings.Join(modules, ","), "-am")
+}
+
 var ErrPropertyNotFound = errors.New("property not found")
 
 func (cli *Cli) GetProperty(ctx context.Context, propertyPath string, projectPath string) (string, error) {
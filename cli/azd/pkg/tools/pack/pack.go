@@ -67,7 +67,7 @@ func NewCli(
 		ctx,
 		console,
 		commandRunner,
-		http.DefaultClient,
+		tools.NewDownloadTransporter(http.DefaultClient),
 		extractCli)
 }
 
@@ -102,6 +102,30 @@ func (cli *Cli) Deploy(
 	return res.Stdout + res.Stderr, nil
 }
 
+type StartOptions struct {
+	// The folder of the static content, or the url of the dev server of the app
+	OutputRelativeFolderPath string
+	// The port the emulator listens on. Defaults to 4280
+	Port int
+}
+
+// Start runs the SWA emulator in the given directory, until the context is cancelled or the emulator exits.
+func (cli *Cli) Start(ctx context.Context, cwd string, options StartOptions, startProgress io.Writer) error {
+	args := []string{"start"}
+	if options.OutputRelativeFolderPath != "" {
+		args = append(args, options.OutputRelativeFolderPath)
+	}
+	if options.Port != 0 {
+		args = append(args, "--port", fmt.Sprint(options.Port))
+	}
+
+	if _, err := cli.run(ctx, cwd, startProgress, args...); err != nil {
+		return fmt.Errorf("swa start: %w", err)
+	}
+
+	return nil
+}
+
 func (cli *Cli) CheckInstalled(_ context.Context) error {
 
 	return tools.ToolInPath("npx")
@@ -232,3 +232,31 @@ func Test_SwaDeploy(t *testing.T) {
 		)
 	})
 }
+
+func Test_SwaStart(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	swacli := NewCli(mockContext.CommandRunner)
+
+	ran := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "npx")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+
+		require.Equal(t, testPath, args.Cwd)
+		require.Equal(t, []string{
+			"-y", swaCliPackage,
+			"start", "dist",
+			"--port", "4281",
+		}, args.Args)
+
+		return exec.RunResult{}, nil
+	})
+
+	err := swacli.Start(context.Background(), testPath, StartOptions{
+		OutputRelativeFolderPath: "dist",
+		Port:                     4281,
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, ran)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -234,3 +235,73 @@ func (cli *Cli) Destroy(ctx context.Context, modulePath string, additionalArgs .
 	}
 	return cmdRes.Stdout, nil
 }
+
+// WorkspaceShow returns the name of the currently selected workspace.
+func (cli *Cli) WorkspaceShow(ctx context.Context, modulePath string) (string, error) {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "workspace", "show"}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform workspace show: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return strings.TrimSpace(cmdRes.Stdout), nil
+}
+
+// WorkspaceList returns the names of all the workspaces of the configured backend.
+func (cli *Cli) WorkspaceList(ctx context.Context, modulePath string) ([]string, error) {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "workspace", "list"}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed running terraform workspace list: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+
+	workspaces := []string{}
+	for _, line := range strings.Split(cmdRes.Stdout, "\n") {
+		// The selected workspace is prefixed with '*'
+		name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if name != "" {
+			workspaces = append(workspaces, name)
+		}
+	}
+
+	return workspaces, nil
+}
+
+// WorkspaceSelect selects the specified workspace.
+func (cli *Cli) WorkspaceSelect(ctx context.Context, modulePath string, name string) error {
+	return cli.runWorkspaceCommand(ctx, modulePath, "select", name)
+}
+
+// WorkspaceNew creates and selects a new workspace.
+func (cli *Cli) WorkspaceNew(ctx context.Context, modulePath string, name string) error {
+	return cli.runWorkspaceCommand(ctx, modulePath, "new", name)
+}
+
+// WorkspaceDelete deletes the specified workspace. The workspace must not be selected and its state must be empty.
+func (cli *Cli) WorkspaceDelete(ctx context.Context, modulePath string, name string) error {
+	return cli.runWorkspaceCommand(ctx, modulePath, "delete", name)
+}
+
+func (cli *Cli) runWorkspaceCommand(ctx context.Context, modulePath string, command string, name string) error {
+	args := []string{fmt.Sprintf("-chdir=%s", modulePath), "workspace", command, name}
+
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running terraform workspace %s: %s (%w)",
+			command,
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
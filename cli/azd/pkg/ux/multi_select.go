@@ -329,7 +329,7 @@ func (p *MultiSelect) renderOptions(printer Printer, indent string) {
 		// Show checkbox
 		checkbox := " "
 		if option.Selected {
-			checkbox = output.WithSuccessFormat("✔")
+			checkbox = output.WithSuccessFormat(output.Symbol("✔", "v"))
 		}
 
 		// Show item digit prefixes
@@ -475,7 +475,7 @@ func (p *MultiSelect) renderFooter(printer Printer) {
 	}
 
 	printer.Fprintln()
-	printer.Fprintln(output.WithGrayFormat("───────────────────────────────────────"))
+	printer.Fprintln(output.WithGrayFormat(output.HorizontalLine(39)))
 	printer.Fprintln(output.WithGrayFormat("Use arrows to move, use space to select"))
 	printer.Fprintln(output.WithGrayFormat("Use left/right to select none/all"))
 	printer.Fprintln(output.WithGrayFormat("Use enter to submit, type ? for help"))
@@ -399,6 +399,6 @@ func (p *Select) renderFooter(printer Printer) {
 	}
 
 	printer.Fprintln()
-	printer.Fprintln(output.WithGrayFormat("───────────────────────────────────"))
+	printer.Fprintln(output.WithGrayFormat(output.HorizontalLine(35)))
 	printer.Fprintln(output.WithGrayFormat("Use arrows to move, type ? for hint"))
 }
@@ -53,6 +53,11 @@ func NewSpinner(options *SpinnerOptions) *Spinner {
 		panic(err)
 	}
 
+	// A single frame doesn't animate the spinner, see the ux.reducedMotion user config
+	if output.ReducedMotion() && len(mergedConfig.Animation) > 1 {
+		mergedConfig.Animation = mergedConfig.Animation[len(mergedConfig.Animation)-1:]
+	}
+
 	return &Spinner{
 		options: &mergedConfig,
 		text:    mergedConfig.Text,
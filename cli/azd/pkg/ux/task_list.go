@@ -38,12 +38,13 @@ var DefaultTaskListOptions TaskListOptions = TaskListOptions{
 	Writer:             os.Stdout,
 	MaxConcurrentAsync: 5,
 
-	SuccessStyle: output.WithSuccessFormat("(✔) Done "),
-	ErrorStyle:   output.WithErrorFormat("(x) Error "),
-	WarningStyle: output.WithWarningFormat("(!) Warning "),
-	RunningStyle: output.WithHighLightFormat("(-) Running "),
-	SkippedStyle: output.WithGrayFormat("(-) Skipped "),
-	PendingStyle: output.WithGrayFormat("(o) Pending "),
+	// The styles are formatted with the colors of the theme when rendered. SuccessStyle defaults to a check mark of the
+	// theme, see NewTaskList.
+	ErrorStyle:   "(x) Error ",
+	WarningStyle: "(!) Warning ",
+	RunningStyle: "(-) Running ",
+	SkippedStyle: "(-) Skipped ",
+	PendingStyle: "(o) Pending ",
 }
 
 // TaskList is a component for managing a list of tasks.
@@ -110,6 +111,10 @@ func NewTaskList(options *TaskListOptions) *TaskList {
 		panic(err)
 	}
 
+	if mergedOptions.SuccessStyle == "" {
+		mergedOptions.SuccessStyle = fmt.Sprintf("(%s) Done ", output.Symbol("✔", "v"))
+	}
+
 	return &TaskList{
 		options:        &mergedOptions,
 		waitGroup:      sync.WaitGroup{},
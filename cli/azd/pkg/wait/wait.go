@@ -0,0 +1,240 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package wait blocks until conditions on the environment or the deployed resources are met, ex) an environment value is
+// set, a DNS name resolves or an endpoint is up, instead of the sleep loops usually wrapped around azd in pipelines.
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ErrTimeout is returned when the conditions aren't all met before the timeout.
+var ErrTimeout = errors.New("timed out waiting for the conditions")
+
+// The kinds of conditions
+const (
+	KindEnv  = "env"
+	KindDNS  = "dns"
+	KindHTTP = "http"
+)
+
+// Condition is a condition to wait for.
+type Condition interface {
+	// Kind returns the kind of the condition, ex) dns
+	Kind() string
+	// Target returns what the condition checks, ex) the DNS name
+	Target() string
+	// Check returns true, with details like the resolved addresses, when the condition is met. Errors returned by
+	// Check are permanent and stop the wait, transient failures should return false instead.
+	Check(ctx context.Context) (bool, string, error)
+}
+
+// Result is the result of waiting for a condition.
+type Result struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	Met    bool   `json:"met"`
+	// Details of the last check, ex) the resolved addresses or the status code of the endpoint
+	Details string `json:"details,omitempty"`
+	// The number of times the condition was checked
+	Attempts int `json:"attempts"`
+	// The time it took for the condition to be met, in seconds
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// Options configures the wait.
+type Options struct {
+	// The maximum time to wait for the conditions, 10 minutes by default
+	Timeout time.Duration
+	// The time between the checks of the conditions which aren't met, 5 seconds by default
+	Interval time.Duration
+	// Called when a condition is met
+	OnMet func(result Result)
+}
+
+// For waits until all the conditions are met, and returns the results of the conditions. ErrTimeout is returned, with
+// the results, when some of the conditions aren't met before the timeout.
+func For(ctx context.Context, conditions []Condition, options Options) ([]Result, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Minute
+	}
+
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	results := make([]Result, len(conditions))
+	for i, condition := range conditions {
+		results[i] = Result{Kind: condition.Kind(), Target: condition.Target()}
+	}
+
+	for {
+		pending := 0
+		for i, condition := range conditions {
+			if results[i].Met {
+				continue
+			}
+
+			met, details, err := condition.Check(ctx)
+			if err != nil && ctx.Err() == nil {
+				return results, fmt.Errorf("checking %s '%s': %w", condition.Kind(), condition.Target(), err)
+			}
+
+			results[i].Attempts++
+			results[i].Details = details
+			if met {
+				results[i].Met = true
+				results[i].ElapsedSeconds = time.Since(start).Round(time.Millisecond).Seconds()
+				if options.OnMet != nil {
+					options.OnMet(results[i])
+				}
+				continue
+			}
+
+			pending++
+		}
+
+		if pending == 0 {
+			return results, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return results, fmt.Errorf("%w after %s", ErrTimeout, options.Timeout)
+			}
+
+			return results, ctx.Err()
+		case <-time.After(options.Interval):
+		}
+	}
+}
+
+// LookupFn returns the value of a key, ex) from the environment reloaded on each call.
+type LookupFn func(ctx context.Context, key string) (string, bool, error)
+
+// EnvValue is met when the key is set to a non empty value, ex) by a provisioning running in another job.
+func EnvValue(key string, lookup LookupFn) Condition {
+	return &envCondition{key: key, lookup: lookup}
+}
+
+type envCondition struct {
+	key    string
+	lookup LookupFn
+}
+
+func (c *envCondition) Kind() string {
+	return KindEnv
+}
+
+func (c *envCondition) Target() string {
+	return c.key
+}
+
+func (c *envCondition) Check(ctx context.Context) (bool, string, error) {
+	value, has, err := c.lookup(ctx, c.key)
+	if err != nil {
+		return false, "", err
+	}
+
+	if !has || value == "" {
+		return false, "not set", nil
+	}
+
+	return true, "set", nil
+}
+
+// Resolver resolves DNS names, implemented by net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DNS is met when the DNS name resolves. The default resolver is used when resolver is nil.
+func DNS(host string, resolver Resolver) Condition {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return &dnsCondition{host: host, resolver: resolver}
+}
+
+type dnsCondition struct {
+	host     string
+	resolver Resolver
+}
+
+func (c *dnsCondition) Kind() string {
+	return KindDNS
+}
+
+func (c *dnsCondition) Target() string {
+	return c.host
+}
+
+func (c *dnsCondition) Check(ctx context.Context) (bool, string, error) {
+	addresses, err := c.resolver.LookupHost(ctx, c.host)
+	if err != nil || len(addresses) == 0 {
+		// The name doesn't resolve yet, or its records haven't propagated to the resolver
+		return false, fmt.Sprintf("does not resolve: %v", err), nil
+	}
+
+	return true, fmt.Sprintf("resolves to %v", addresses), nil
+}
+
+// HttpClient sends HTTP requests, implemented by http.Client.
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// The maximum time to wait for a response of an endpoint, for each check
+const httpRequestTimeout = 30 * time.Second
+
+// HTTP is met when a GET request to the URL returns 200 OK. The default client is used when client is nil.
+func HTTP(url string, client HttpClient) Condition {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &httpCondition{url: url, client: client}
+}
+
+type httpCondition struct {
+	url    string
+	client HttpClient
+}
+
+func (c *httpCondition) Kind() string {
+	return KindHTTP
+}
+
+func (c *httpCondition) Target() string {
+	return c.url
+}
+
+func (c *httpCondition) Check(ctx context.Context) (bool, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, httpRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err.Error(), nil
+	}
+	defer res.Body.Close()
+
+	return res.StatusCode == http.StatusOK, res.Status, nil
+}
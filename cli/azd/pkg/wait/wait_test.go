@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package wait
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	resolvesAfter int
+	lookups       int
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.lookups++
+	if r.lookups < r.resolvesAfter {
+		return nil, errors.New("no such host")
+	}
+
+	return []string{"10.0.0.1"}, nil
+}
+
+type fakeHttpClient struct {
+	statusCodes []int
+}
+
+func (c *fakeHttpClient) Do(req *http.Request) (*http.Response, error) {
+	statusCode := c.statusCodes[0]
+	if len(c.statusCodes) > 1 {
+		c.statusCodes = c.statusCodes[1:]
+	}
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func Test_For(t *testing.T) {
+	t.Run("AllMet", func(t *testing.T) {
+		env := map[string]string{}
+		lookups := 0
+		lookup := func(ctx context.Context, key string) (string, bool, error) {
+			lookups++
+			if lookups == 2 {
+				env["SERVICE_API_URI"] = "https://api.example.com"
+			}
+
+			value, has := env[key]
+			return value, has, nil
+		}
+
+		met := []string{}
+		results, err := For(context.Background(), []Condition{
+			EnvValue("SERVICE_API_URI", lookup),
+			DNS("api.example.com", &fakeResolver{resolvesAfter: 3}),
+			HTTP("https://api.example.com", &fakeHttpClient{statusCodes: []int{503, 200}}),
+		}, Options{
+			Interval: time.Millisecond,
+			OnMet: func(result Result) {
+				met = append(met, result.Kind)
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{KindEnv, KindHTTP, KindDNS}, met)
+		require.Len(t, results, 3)
+		require.True(t, results[0].Met)
+		require.Equal(t, 2, results[0].Attempts)
+		require.Equal(t, 3, results[1].Attempts)
+		require.Equal(t, "resolves to [10.0.0.1]", results[1].Details)
+		require.Equal(t, KindHTTP, results[2].Kind)
+		require.Equal(t, "https://api.example.com", results[2].Target)
+		require.Equal(t, "OK", results[2].Details)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		results, err := For(context.Background(), []Condition{
+			DNS("api.example.com", &fakeResolver{resolvesAfter: 1}),
+			HTTP("https://api.example.com", &fakeHttpClient{statusCodes: []int{404}}),
+		}, Options{
+			Timeout:  20 * time.Millisecond,
+			Interval: time.Millisecond,
+		})
+
+		require.ErrorIs(t, err, ErrTimeout)
+		require.True(t, results[0].Met)
+		require.False(t, results[1].Met)
+		require.Equal(t, "Not Found", results[1].Details)
+	})
+
+	t.Run("LookupError", func(t *testing.T) {
+		_, err := For(context.Background(), []Condition{
+			EnvValue("KEY", func(ctx context.Context, key string) (string, bool, error) {
+				return "", false, errors.New("environment not found")
+			}),
+		}, Options{Interval: time.Millisecond})
+
+		require.ErrorContains(t, err, "checking env 'KEY': environment not found")
+	})
+}
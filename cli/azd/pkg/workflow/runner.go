@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/status"
 )
 
 // AzdCommandRunner abstracts the execution of an azd command given an set of arguments and context.
@@ -38,7 +39,14 @@ func (r *Runner) Run(ctx context.Context, workflow *Workflow) error {
 			r.azdRunner.SetArgs(step.AzdCommand.Args)
 		}
 
-		if err := r.azdRunner.ExecuteContext(ctx); err != nil {
+		// The phases of the operation observed on the status endpoint are the steps of the workflow
+		phase := strings.Join(step.AzdCommand.Args, " ")
+		tracker := status.FromContext(ctx)
+		tracker.StartPhase(phase)
+
+		err := r.azdRunner.ExecuteContext(ctx)
+		tracker.EndPhase(phase, err)
+		if err != nil {
 			return fmt.Errorf("error executing step command '%s': %w", strings.Join(step.AzdCommand.Args, " "), err)
 		}
 	}
@@ -0,0 +1,501 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package yamlschema validates YAML documents against JSON schemas, reporting the line and column of each error.
+//
+// The keywords of draft 2019-09 used by the azure.yaml schemas are supported: type, enum, const, properties,
+// additionalProperties, required, minProperties, items, minItems, maxItems, uniqueItems, minLength, maxLength, pattern,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, allOf, anyOf, oneOf, not, if/then/else and local $ref.
+package yamlschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/braydonk/yaml"
+)
+
+// Error is a violation of the schema by a value of the document
+type Error struct {
+	// The path of the value, ex) services.api.host
+	Path string `json:"path"`
+	// The position of the value in the document, starting at 1
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+
+	// The type of the value isn't the expected type
+	typeMismatch bool
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Schema is a compiled JSON schema
+type Schema struct {
+	root any
+}
+
+// Compile parses a JSON schema
+func Compile(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+
+	return &Schema{root: root}, nil
+}
+
+// Validate validates the YAML document, returning the errors sorted by their position in the document.
+func (s *Schema) Validate(node *yaml.Node) ([]Error, error) {
+	v := &validator{root: s.root}
+	errs := v.validate(node, s.root, "")
+	if v.err != nil {
+		return nil, v.err
+	}
+
+	slices.SortStableFunc(errs, func(a, b Error) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+
+	return errs, nil
+}
+
+type validator struct {
+	root any
+	// An error of the schema itself, ex) an unresolvable $ref
+	err error
+}
+
+func (v *validator) validate(node *yaml.Node, schema any, path string) []Error {
+	node = resolveNode(node)
+	if node == nil {
+		return nil
+	}
+
+	switch schema := schema.(type) {
+	case bool:
+		if !schema {
+			return []Error{newError(node, path, "value is not allowed")}
+		}
+		return nil
+	case map[string]any:
+		return v.validateObject(node, schema, path)
+	}
+
+	return nil
+}
+
+func (v *validator) validateObject(node *yaml.Node, schema map[string]any, path string) []Error {
+	errs := []Error{}
+
+	if ref, has := schema["$ref"].(string); has {
+		resolved, err := v.resolveRef(ref)
+		if err != nil {
+			v.err = err
+			return nil
+		}
+		errs = append(errs, v.validate(node, resolved, path)...)
+	}
+
+	if expected, has := schema["type"]; has {
+		if !matchesType(node, expected) {
+			typeErr := newError(node, path, fmt.Sprintf("expected %s, got %s", typeNames(expected), nodeType(node)))
+			typeErr.typeMismatch = true
+			return append(errs, typeErr)
+		}
+	}
+
+	if enum, has := schema["enum"].([]any); has {
+		value := nodeValue(node)
+		if !slices.ContainsFunc(enum, func(allowed any) bool { return reflect.DeepEqual(value, allowed) }) {
+			allowed := make([]string, 0, len(enum))
+			for _, value := range enum {
+				allowed = append(allowed, fmt.Sprintf("'%v'", value))
+			}
+			errs = append(errs, newError(node, path, "value must be one of "+strings.Join(allowed, ", ")))
+		}
+	}
+
+	if constant, has := schema["const"]; has {
+		if !reflect.DeepEqual(nodeValue(node), constant) {
+			errs = append(errs, newError(node, path, fmt.Sprintf("value must be '%v'", constant)))
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		errs = append(errs, v.validateMapping(node, schema, path)...)
+	case yaml.SequenceNode:
+		errs = append(errs, v.validateSequence(node, schema, path)...)
+	case yaml.ScalarNode:
+		errs = append(errs, validateScalar(node, schema, path)...)
+	}
+
+	if allOf, has := schema["allOf"].([]any); has {
+		for _, subSchema := range allOf {
+			errs = append(errs, v.validate(node, subSchema, path)...)
+		}
+	}
+
+	if anyOf, has := schema["anyOf"].([]any); has {
+		errs = append(errs, v.validateAnyOf(node, anyOf, path)...)
+	}
+
+	if oneOf, has := schema["oneOf"].([]any); has {
+		errs = append(errs, v.validateOneOf(node, oneOf, path)...)
+	}
+
+	if not, has := schema["not"]; has {
+		if len(v.validate(node, not, path)) == 0 {
+			errs = append(errs, newError(node, path, "value is not allowed"))
+		}
+	}
+
+	if ifSchema, has := schema["if"]; has {
+		if len(v.validate(node, ifSchema, path)) == 0 {
+			if then, has := schema["then"]; has {
+				errs = append(errs, v.validate(node, then, path)...)
+			}
+		} else if elseSchema, has := schema["else"]; has {
+			errs = append(errs, v.validate(node, elseSchema, path)...)
+		}
+	}
+
+	return errs
+}
+
+func (v *validator) validateMapping(node *yaml.Node, schema map[string]any, path string) []Error {
+	errs := []Error{}
+	properties, _ := schema["properties"].(map[string]any)
+
+	keys := map[string]struct{}{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keys[key.Value] = struct{}{}
+		propertyPath := joinPath(path, key.Value)
+
+		if propertySchema, has := properties[key.Value]; has {
+			errs = append(errs, v.validate(value, propertySchema, propertyPath)...)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, newError(key, propertyPath, fmt.Sprintf("property '%s' is not allowed", key.Value)))
+			}
+		case map[string]any:
+			errs = append(errs, v.validate(value, additional, propertyPath)...)
+		}
+	}
+
+	if required, has := schema["required"].([]any); has {
+		for _, name := range required {
+			if _, has := keys[fmt.Sprint(name)]; !has {
+				errs = append(errs, newError(node, path, fmt.Sprintf("missing required property '%v'", name)))
+			}
+		}
+	}
+
+	if minProperties, has := schema["minProperties"].(float64); has && float64(len(keys)) < minProperties {
+		errs = append(errs, newError(node, path, fmt.Sprintf("expected at least %v properties", minProperties)))
+	}
+
+	return errs
+}
+
+func (v *validator) validateSequence(node *yaml.Node, schema map[string]any, path string) []Error {
+	errs := []Error{}
+
+	if items, has := schema["items"]; has {
+		for i, item := range node.Content {
+			errs = append(errs, v.validate(item, items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	if minItems, has := schema["minItems"].(float64); has && float64(len(node.Content)) < minItems {
+		errs = append(errs, newError(node, path, fmt.Sprintf("expected at least %v items", minItems)))
+	}
+
+	if maxItems, has := schema["maxItems"].(float64); has && float64(len(node.Content)) > maxItems {
+		errs = append(errs, newError(node, path, fmt.Sprintf("expected at most %v items", maxItems)))
+	}
+
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		values := []any{}
+		for i, item := range node.Content {
+			value := nodeValue(resolveNode(item))
+			if slices.ContainsFunc(values, func(other any) bool { return reflect.DeepEqual(value, other) }) {
+				errs = append(errs, newError(item, fmt.Sprintf("%s[%d]", path, i), "items must be unique"))
+			}
+			values = append(values, value)
+		}
+	}
+
+	return errs
+}
+
+func validateScalar(node *yaml.Node, schema map[string]any, path string) []Error {
+	errs := []Error{}
+
+	switch nodeType(node) {
+	case "string":
+		length := float64(utf8.RuneCountInString(node.Value))
+		if minLength, has := schema["minLength"].(float64); has && length < minLength {
+			errs = append(errs, newError(node, path, fmt.Sprintf("expected at least %v characters", minLength)))
+		}
+		if maxLength, has := schema["maxLength"].(float64); has && length > maxLength {
+			errs = append(errs, newError(node, path, fmt.Sprintf("expected at most %v characters", maxLength)))
+		}
+		if pattern, has := schema["pattern"].(string); has {
+			if matched, err := regexp.MatchString(pattern, node.Value); err == nil && !matched {
+				errs = append(errs, newError(node, path, fmt.Sprintf("value must match the pattern '%s'", pattern)))
+			}
+		}
+	case "integer", "number":
+		value, ok := nodeValue(node).(float64)
+		if !ok {
+			return errs
+		}
+		if minimum, has := schema["minimum"].(float64); has && value < minimum {
+			errs = append(errs, newError(node, path, fmt.Sprintf("value must be at least %v", minimum)))
+		}
+		if maximum, has := schema["maximum"].(float64); has && value > maximum {
+			errs = append(errs, newError(node, path, fmt.Sprintf("value must be at most %v", maximum)))
+		}
+		if minimum, has := schema["exclusiveMinimum"].(float64); has && value <= minimum {
+			errs = append(errs, newError(node, path, fmt.Sprintf("value must be greater than %v", minimum)))
+		}
+		if maximum, has := schema["exclusiveMaximum"].(float64); has && value >= maximum {
+			errs = append(errs, newError(node, path, fmt.Sprintf("value must be less than %v", maximum)))
+		}
+	}
+
+	return errs
+}
+
+// validateAnyOf reports the errors of the best matching schema, so the errors point at the nested values rather than
+// at the whole value.
+func (v *validator) validateAnyOf(node *yaml.Node, schemas []any, path string) []Error {
+	var best []Error
+	for _, schema := range schemas {
+		errs := v.validate(node, schema, path)
+		if len(errs) == 0 {
+			return nil
+		}
+
+		if best == nil || closerMatch(errs, best, path) {
+			best = errs
+		}
+	}
+
+	if matchRank(best, path) == 0 {
+		return []Error{newError(node, path, "value doesn't match any of the allowed schemas")}
+	}
+
+	return best
+}
+
+func (v *validator) validateOneOf(node *yaml.Node, schemas []any, path string) []Error {
+	matches := 0
+	for _, schema := range schemas {
+		if len(v.validate(node, schema, path)) == 0 {
+			matches++
+		}
+	}
+
+	switch matches {
+	case 0:
+		return v.validateAnyOf(node, schemas, path)
+	case 1:
+		return nil
+	default:
+		return []Error{newError(node, path, "value matches more than one of the allowed schemas")}
+	}
+}
+
+// closerMatch returns true when the errors are a closer match than the other errors, see [matchRank]. For the same
+// rank, the fewer errors the closer.
+func closerMatch(errs []Error, other []Error, path string) bool {
+	rank, otherRank := matchRank(errs, path), matchRank(other, path)
+	if rank != otherRank {
+		return rank > otherRank
+	}
+
+	return len(errs) < len(other)
+}
+
+// matchRank ranks how close the value at the path matches a schema, given the errors of its validation: 2 when the
+// errors are all about nested values, 1 when the value has the expected type, and 0 otherwise.
+func matchRank(errs []Error, path string) int {
+	if slices.ContainsFunc(errs, func(err Error) bool { return err.Path == path && err.typeMismatch }) {
+		return 0
+	}
+
+	if slices.ContainsFunc(errs, func(err Error) bool { return err.Path == path }) {
+		return 1
+	}
+
+	return 2
+}
+
+// resolveRef resolves a reference to a definition of the schema, ex) #/definitions/serviceConfig
+func (v *validator) resolveRef(ref string) (any, error) {
+	pointer, found := strings.CutPrefix(ref, "#")
+	if !found {
+		return nil, fmt.Errorf("unsupported schema reference '%s'", ref)
+	}
+
+	current := v.root
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("schema reference '%s' not found", ref)
+		}
+
+		current, ok = object[segment]
+		if !ok {
+			return nil, fmt.Errorf("schema reference '%s' not found", ref)
+		}
+	}
+
+	return current, nil
+}
+
+func resolveNode(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+			node = node.Content[0]
+		case yaml.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+
+	return nil
+}
+
+// nodeType returns the JSON type of the node
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.ShortTag() {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	default:
+		return "string"
+	}
+}
+
+func matchesType(node *yaml.Node, expected any) bool {
+	actual := nodeType(node)
+	matches := func(name any) bool {
+		return name == actual || (name == "number" && actual == "integer")
+	}
+
+	if types, ok := expected.([]any); ok {
+		return slices.ContainsFunc(types, matches)
+	}
+
+	return matches(expected)
+}
+
+func typeNames(expected any) string {
+	if types, ok := expected.([]any); ok {
+		names := make([]string, 0, len(types))
+		for _, name := range types {
+			names = append(names, fmt.Sprint(name))
+		}
+		return strings.Join(names, " or ")
+	}
+
+	return fmt.Sprint(expected)
+}
+
+// nodeValue returns the value of the node, with the types of JSON values: numbers are float64 and objects are
+// map[string]any.
+func nodeValue(node *yaml.Node) any {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+
+	return normalize(value)
+}
+
+func normalize(value any) any {
+	switch value := value.(type) {
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	case []any:
+		for i := range value {
+			value[i] = normalize(value[i])
+		}
+		return value
+	case map[string]any:
+		for key := range value {
+			value[key] = normalize(value[key])
+		}
+		return value
+	case map[any]any:
+		object := make(map[string]any, len(value))
+		for key, item := range value {
+			object[fmt.Sprint(key)] = normalize(item)
+		}
+		return object
+	}
+
+	return value
+}
+
+func joinPath(path string, property string) string {
+	if path == "" {
+		return property
+	}
+
+	return path + "." + property
+}
+
+func newError(node *yaml.Node, path string, message string) Error {
+	return Error{
+		Path:    path,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: message,
+	}
+}
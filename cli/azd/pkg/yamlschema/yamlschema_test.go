@@ -0,0 +1,156 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package yamlschema
+
+import (
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name"],
+	"additionalProperties": false,
+	"properties": {
+		"name": { "type": "string", "minLength": 2 },
+		"replicas": { "type": "integer", "minimum": 1 },
+		"tags": { "type": "array", "items": { "type": "string" }, "uniqueItems": true },
+		"services": {
+			"type": "object",
+			"additionalProperties": { "$ref": "#/definitions/service" }
+		},
+		"hook": {
+			"anyOf": [
+				{ "type": "array", "items": { "$ref": "#/definitions/hook" } },
+				{ "$ref": "#/definitions/hook" }
+			]
+		}
+	},
+	"definitions": {
+		"service": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"host": { "type": "string", "enum": ["appservice", "containerapp"] },
+				"image": { "type": "string" },
+				"project": { "type": "string" }
+			},
+			"if": { "properties": { "host": { "const": "appservice" } } },
+			"then": { "properties": { "image": false } }
+		},
+		"hook": {
+			"type": "object",
+			"required": ["run"],
+			"properties": {
+				"run": { "type": "string" }
+			}
+		}
+	}
+}`
+
+func validate(t *testing.T, document string) []Error {
+	schema, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(document), &node))
+
+	errs, err := schema.Validate(&node)
+	require.NoError(t, err)
+
+	return errs
+}
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected []Error
+	}{
+		{
+			name: "Valid",
+			document: `name: app
+replicas: 2
+tags: [a, b]
+services:
+  api:
+    host: containerapp
+    image: nginx
+hook:
+  run: echo`,
+			expected: []Error{},
+		},
+		{
+			name:     "MissingRequired",
+			document: `replicas: 1`,
+			expected: []Error{
+				{Path: "", Line: 1, Column: 1, Message: "missing required property 'name'"},
+			},
+		},
+		{
+			name: "TypesAndConstraints",
+			document: `name: a
+replicas: many
+tags: [a, a]
+extra: true`,
+			expected: []Error{
+				{Path: "name", Line: 1, Column: 7, Message: "expected at least 2 characters"},
+				{Path: "replicas", Line: 2, Column: 11, Message: "expected integer, got string", typeMismatch: true},
+				{Path: "tags[1]", Line: 3, Column: 11, Message: "items must be unique"},
+				{Path: "extra", Line: 4, Column: 1, Message: "property 'extra' is not allowed"},
+			},
+		},
+		{
+			name: "References",
+			document: `name: app
+services:
+  api:
+    host: functions
+  web:
+    host: appservice
+    image: nginx`,
+			expected: []Error{
+				{Path: "services.api.host", Line: 4, Column: 11, Message: "value must be one of 'appservice', 'containerapp'"},
+				{Path: "services.web.image", Line: 7, Column: 12, Message: "value is not allowed"},
+			},
+		},
+		{
+			name: "AnyOfReportsClosestMatch",
+			document: `name: app
+hook:
+  - run: echo
+  - shell: sh`,
+			expected: []Error{
+				{Path: "hook[1]", Line: 4, Column: 5, Message: "missing required property 'run'"},
+			},
+		},
+		{
+			name: "AnyOfNoMatch",
+			document: `name: app
+hook: echo`,
+			expected: []Error{
+				{Path: "hook", Line: 2, Column: 7, Message: "value doesn't match any of the allowed schemas"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, validate(t, tt.document))
+		})
+	}
+}
+
+func Test_Validate_InvalidReference(t *testing.T) {
+	schema, err := Compile([]byte(`{ "$ref": "#/definitions/missing" }`))
+	require.NoError(t, err)
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`name: app`), &node))
+
+	_, err = schema.Validate(&node)
+	require.EqualError(t, err, "schema reference '#/definitions/missing' not found")
+}
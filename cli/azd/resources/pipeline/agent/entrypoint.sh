@@ -0,0 +1,12 @@
+#!/bin/bash
+# Registers an ephemeral runner, which runs a single workflow job then exits.
+set -euo pipefail
+
+REGISTRATION_TOKEN=$(curl -fsSL -X POST \
+  -H "Authorization: Bearer ${GITHUB_PAT}" \
+  -H "Accept: application/vnd.github+json" \
+  "${REGISTRATION_TOKEN_API_URL}" | jq -r .token)
+
+./config.sh --url "${REPO_URL}" --token "${REGISTRATION_TOKEN}" --labels "${RUNNER_LABELS}" \
+  --unattended --ephemeral --disableupdate
+./run.sh
@@ -145,6 +145,7 @@ public interface IEnvironmentService
     ValueTask<bool> SetCurrentEnvironmentAsync(Context c, string envName, IObserver<ProgressMessage> outputObserver, CancellationToken cancellationToken);
     ValueTask<Environment> DeployAsync(Context c, string envName, IObserver<ProgressMessage> outputObserver, CancellationToken cancellationToken);
     ValueTask<Environment> DeployServiceAsync(Context c, string envName, string serviceName, IObserver<ProgressMessage> outputObserver, CancellationToken cancellationToken);
+    ValueTask<bool> StreamServiceLogsAsync(Context c, string envName, string serviceName, IObserver<ProgressMessage> outputObserver, CancellationToken cancellationToken);
 }
 
 public interface IAspireService {
@@ -334,3 +334,25 @@ func MockListEnvironmentTypes(
 
 	return mockRequest
 }
+
+// MockListPermissions mocks the permissions of the signed-in user on the project, ex) the data actions of the
+// DevCenter Project Admin role.
+func MockListPermissions(
+	mockContext *mocks.MockContext,
+	projectName string,
+	dataActions []string,
+) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(
+			request.URL.Path,
+			fmt.Sprintf("/providers/Microsoft.DevCenter/projects/%s/providers/Microsoft.Authorization/permissions",
+				projectName),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"dataActions": dataActions},
+			},
+		})
+	})
+}
@@ -67,3 +67,12 @@ func (m *MockEnvManager) Delete(ctx context.Context, name string) error {
 	args := m.Called(name)
 	return args.Error(0)
 }
+
+func (m *MockEnvManager) DeleteWithOptions(
+	ctx context.Context,
+	name string,
+	options *environment.DeleteOptions,
+) error {
+	args := m.Called(name, options)
+	return args.Error(0)
+}
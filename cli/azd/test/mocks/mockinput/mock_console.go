@@ -69,6 +69,12 @@ func (c *MockConsole) SpinnerOps() []SpinnerOp {
 	return c.spinnerOps
 }
 
+func (c *MockConsole) SetInterruptHandler(handler func()) {}
+
+func (c *MockConsole) PushInterruptHandler(handler func()) func() {
+	return func() {}
+}
+
 func (c *MockConsole) Handles() input.ConsoleHandles {
 	return input.ConsoleHandles{
 		Stdout: io.Discard,
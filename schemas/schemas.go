@@ -0,0 +1,15 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package schemas embeds the JSON schemas of azure.yaml, so azd validates projects against the schemas editors use.
+package schemas
+
+import (
+	_ "embed"
+)
+
+//go:embed v1.0/azure.yaml.json
+var AzureYamlV1 []byte
+
+//go:embed alpha/azure.yaml.json
+var AzureYamlAlpha []byte